@@ -0,0 +1,85 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/skyhook-io/radar/internal/config"
+)
+
+// runConfigCommand handles `radar config <subcommand>` and returns the exit code
+func runConfigCommand(args []string) int {
+	if len(args) == 0 || args[0] != "validate" {
+		fmt.Fprintln(os.Stderr, "Usage: radar config validate [--profile name] <radar.yaml>")
+		return 2
+	}
+
+	fs := flag.NewFlagSet("config validate", flag.ContinueOnError)
+	profile := fs.String("profile", "", "Also apply this profile before validating")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	path := fs.Arg(0)
+	if path == "" {
+		path = os.Getenv("RADAR_CONFIG")
+	}
+	if path == "" {
+		fmt.Fprintln(os.Stderr, "Usage: radar config validate [--profile name] <radar.yaml>")
+		return 2
+	}
+
+	cfg, err := config.Load(path, *profile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "✗ %v\n", err)
+		return 1
+	}
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "✗ %s: %v\n", path, err)
+		return 1
+	}
+
+	fmt.Printf("✓ %s is valid\n", path)
+	if names := cfg.ProfileNames(); len(names) > 0 {
+		fmt.Printf("  profiles: %s\n", strings.Join(names, ", "))
+	}
+	flags := cfg.Flags()
+	names := make([]string, 0, len(flags))
+	for name := range flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Printf("  --%s=%s\n", name, flags[name])
+	}
+	return 0
+}
+
+// applyConfig loads the config file (and RADAR_* env overrides) and applies it to
+// every flag that wasn't passed explicitly on the command line
+func applyConfig(path, profile string) (*config.Config, error) {
+	cfg, err := config.LoadOptional(path, profile)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	explicit := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = true
+	})
+	for name, value := range cfg.Flags() {
+		if explicit[name] {
+			continue
+		}
+		if err := flag.Set(name, value); err != nil {
+			return nil, fmt.Errorf("config value for --%s: %w", name, err)
+		}
+	}
+	return cfg, nil
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const imageRolloutUsage = "Usage: radar image-rollout [--server URL] [--namespace NS] [--watch] preview <from> <to> | start <from> <to> | status [id]"
+
+// imageRollout mirrors the server's image rollout response
+type imageRollout struct {
+	ID        string `json:"id"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+	State     string `json:"state"`
+	Workloads []struct {
+		Kind       string   `json:"kind"`
+		Namespace  string   `json:"namespace"`
+		Name       string   `json:"name"`
+		Containers []string `json:"containers"`
+		ManagedBy  string   `json:"managedBy"`
+		State      string   `json:"state"`
+		Message    string   `json:"message"`
+	} `json:"workloads"`
+	Warnings []string `json:"warnings"`
+}
+
+// runImageRolloutCommand previews, starts and tracks cluster-wide image rollouts on a
+// running Radar server and returns the exit code
+func runImageRolloutCommand(args []string) int {
+	fs := flag.NewFlagSet("image-rollout", flag.ContinueOnError)
+	server := fs.String("server", "http://localhost:9280", "Radar server URL")
+	namespace := fs.String("namespace", "", "Only update workloads in this namespace")
+	watch := fs.Bool("watch", false, "Follow the rollout until it finishes (start and status)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	base := strings.TrimRight(*server, "/") + "/api/image-rollouts"
+	client := &http.Client{Timeout: 60 * time.Second}
+
+	switch action := fs.Arg(0); action {
+	case "preview", "start":
+		if fs.NArg() != 3 {
+			fmt.Fprintln(os.Stderr, imageRolloutUsage)
+			return 2
+		}
+		body, _ := json.Marshal(map[string]any{
+			"from":      fs.Arg(1),
+			"to":        fs.Arg(2),
+			"namespace": *namespace,
+			"dryRun":    action == "preview",
+		})
+		var rollout imageRollout
+		if err := doImageRolloutRequest(client, http.MethodPost, base, body, &rollout); err != nil {
+			fmt.Fprintf(os.Stderr, "✗ %v\n", err)
+			return 1
+		}
+		if action == "preview" {
+			if len(rollout.Workloads) == 0 {
+				fmt.Printf("No workloads use %s\n", rollout.From)
+				return 0
+			}
+			fmt.Printf("%d workload(s) would move %s → %s:\n", len(rollout.Workloads), rollout.From, rollout.To)
+			printImageRollout(rollout)
+			return 0
+		}
+		fmt.Printf("✓ Started image rollout %s (%s → %s)\n", rollout.ID, rollout.From, rollout.To)
+		if *watch {
+			return watchImageRollout(client, base, rollout)
+		}
+		printImageRollout(rollout)
+		return imageRolloutExitCode(rollout)
+
+	case "status":
+		if fs.NArg() == 1 {
+			var rollouts []imageRollout
+			if err := doImageRolloutRequest(client, http.MethodGet, base, nil, &rollouts); err != nil {
+				fmt.Fprintf(os.Stderr, "✗ %v\n", err)
+				return 1
+			}
+			if len(rollouts) == 0 {
+				fmt.Println("No image rollouts")
+				return 0
+			}
+			for _, r := range rollouts {
+				fmt.Printf("%s  %-9s  %s → %s (%d workloads)\n", r.ID, r.State, r.From, r.To, len(r.Workloads))
+			}
+			return 0
+		}
+		var rollout imageRollout
+		if err := doImageRolloutRequest(client, http.MethodGet, base+"/"+url.PathEscape(fs.Arg(1)), nil, &rollout); err != nil {
+			fmt.Fprintf(os.Stderr, "✗ %v\n", err)
+			return 1
+		}
+		if *watch {
+			return watchImageRollout(client, base, rollout)
+		}
+		fmt.Printf("Image rollout %s (%s → %s): %s\n", rollout.ID, rollout.From, rollout.To, rollout.State)
+		printImageRollout(rollout)
+		return imageRolloutExitCode(rollout)
+
+	default:
+		fmt.Fprintln(os.Stderr, imageRolloutUsage)
+		return 2
+	}
+}
+
+// watchImageRollout polls a rollout until it finishes, printing workloads as they change state
+func watchImageRollout(client *http.Client, base string, rollout imageRollout) int {
+	reported := make(map[string]string)
+	for {
+		for _, wl := range rollout.Workloads {
+			key := wl.Kind + "/" + wl.Namespace + "/" + wl.Name
+			if reported[key] == wl.State {
+				continue
+			}
+			reported[key] = wl.State
+			mark := "…"
+			switch wl.State {
+			case "done":
+				mark = "✓"
+			case "failed":
+				mark = "✗"
+			}
+			line := fmt.Sprintf("%s %s %s/%s: %s", mark, wl.Kind, wl.Namespace, wl.Name, wl.State)
+			if wl.Message != "" {
+				line += " - " + wl.Message
+			}
+			fmt.Println(line)
+		}
+		if rollout.State != "running" {
+			fmt.Printf("Image rollout %s %s\n", rollout.ID, rollout.State)
+			return imageRolloutExitCode(rollout)
+		}
+		time.Sleep(2 * time.Second)
+		if err := doImageRolloutRequest(client, http.MethodGet, base+"/"+url.PathEscape(rollout.ID), nil, &rollout); err != nil {
+			fmt.Fprintf(os.Stderr, "✗ %v\n", err)
+			return 1
+		}
+	}
+}
+
+func printImageRollout(rollout imageRollout) {
+	for _, wl := range rollout.Workloads {
+		line := fmt.Sprintf("  %s %s/%s [%s]", wl.Kind, wl.Namespace, wl.Name, strings.Join(wl.Containers, ", "))
+		if wl.ManagedBy != "" {
+			line += " (managed by " + wl.ManagedBy + ")"
+		}
+		if rollout.State != "preview" {
+			line += ": " + wl.State
+			if wl.Message != "" {
+				line += " - " + wl.Message
+			}
+		}
+		fmt.Println(line)
+	}
+	for _, w := range rollout.Warnings {
+		fmt.Fprintf(os.Stderr, "! %s\n", w)
+	}
+}
+
+func imageRolloutExitCode(rollout imageRollout) int {
+	if rollout.State == "failed" {
+		return 1
+	}
+	return 0
+}
+
+func doImageRolloutRequest(client *http.Client, method, endpoint string, body []byte, out any) error {
+	req, err := http.NewRequest(method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot reach Radar server (is it running?): %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 400 {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Error != "" {
+			return fmt.Errorf("%s", apiErr.Error)
+		}
+		return fmt.Errorf("server returned %s", resp.Status)
+	}
+	return json.Unmarshal(data, out)
+}
@@ -14,13 +14,26 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/skyhook-io/radar/internal/auth"
+	"github.com/skyhook-io/radar/internal/cost"
+	"github.com/skyhook-io/radar/internal/execaudit"
+	"github.com/skyhook-io/radar/internal/health"
 	"github.com/skyhook-io/radar/internal/helm"
+	"github.com/skyhook-io/radar/internal/hygiene"
+	"github.com/skyhook-io/radar/internal/images"
 	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/notifications"
+	"github.com/skyhook-io/radar/internal/objectstore"
+	"github.com/skyhook-io/radar/internal/policy"
+	"github.com/skyhook-io/radar/internal/replay"
 	"github.com/skyhook-io/radar/internal/server"
+	"github.com/skyhook-io/radar/internal/settings"
 	"github.com/skyhook-io/radar/internal/static"
 	"github.com/skyhook-io/radar/internal/timeline"
+	"github.com/skyhook-io/radar/internal/topology"
 	"github.com/skyhook-io/radar/internal/traffic"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/klog/v2"
 )
 
@@ -29,23 +42,122 @@ var (
 )
 
 func main() {
+	// Subcommands
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		os.Exit(runConfigCommand(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "port-forward" {
+		os.Exit(runPortForwardCommand(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "image-rollout" {
+		os.Exit(runImageRolloutCommand(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrateCommand(os.Args[2:]))
+	}
+
 	// Parse flags
-	kubeconfig := flag.String("kubeconfig", "", "Path to kubeconfig file (default: ~/.kube/config)")
+	configPath := flag.String("config", "", "Path to radar.yaml config file (env: RADAR_CONFIG); explicit flags take precedence")
+	profile := flag.String("profile", "", "Config file profile to apply (env: RADAR_PROFILE)")
+	kubeconfig := flag.String("kubeconfig", "", "Path to kubeconfig file, directory or path list like KUBECONFIG (default: $KUBECONFIG, then ~/.kube/config)")
 	kubeconfigDir := flag.String("kubeconfig-dir", "", "Comma-separated directories containing kubeconfig files (mutually exclusive with --kubeconfig)")
 	namespace := flag.String("namespace", "", "Initial namespace filter (empty = all namespaces)")
+	watchNamespaces := flag.String("namespaces", "", "Comma-separated namespaces to watch instead of the whole cluster (reduces memory on large clusters)")
 	port := flag.Int("port", 9280, "Server port")
 	noBrowser := flag.Bool("no-browser", false, "Don't auto-open browser")
 	devMode := flag.Bool("dev", false, "Development mode (serve frontend from filesystem)")
+	requireAPIToken := flag.Bool("require-api-token", false, "Require an API token (Authorization: Bearer) for API requests from non-loopback clients")
+	authProxyUserHeader := flag.String("auth-proxy-user-header", "", "Trust this header (e.g. X-Forwarded-User) from an authenticating reverse proxy as the request's user")
+	authProxyGroupsHeader := flag.String("auth-proxy-groups-header", auth.DefaultProxyGroupsHeader, "Header carrying the proxy user's comma-separated groups")
+	authProxySecretHeader := flag.String("auth-proxy-secret-header", auth.DefaultProxySecretHeader, "Header carrying the secret shared with the proxy")
+	authProxySecret := flag.String("auth-proxy-secret", "", "Secret the proxy must send in --auth-proxy-secret-header (prefer env: RADAR_AUTH_PROXY_SECRET)")
+	tlsCert := flag.String("tls-cert", "", "Serve HTTPS with this certificate file (requires --tls-key)")
+	tlsKey := flag.String("tls-key", "", "Private key file for --tls-cert")
+	tlsClientCA := flag.String("tls-client-ca", "", "CA bundle verifying client certificates; with proxy auth, the proxy must present one (mTLS)")
+	publicSnapshot := flag.Bool("public-snapshot", false, "Serve a sanitized read-only health snapshot at /public/snapshot.json (no token required)")
+	publicSnapshotFile := flag.String("public-snapshot-file", "", "Write the sanitized health snapshot to this JSON file on every refresh")
+	publicSnapshotInterval := flag.Duration("public-snapshot-interval", 30*time.Second, "How often the public snapshot is rebuilt (minimum 5s)")
+	publicSnapshotNamespaces := flag.String("public-snapshot-namespaces", "", "Comma-separated namespaces to include in the public snapshot (empty = all)")
+	publicSnapshotHideNames := flag.Bool("public-snapshot-hide-names", false, "Omit cluster, namespace and resource names from the public snapshot")
+	shutdownTimeout := flag.Duration("shutdown-timeout", 15*time.Second, "How long shutdown waits for in-flight requests and timeline writes before exiting")
 	showVersion := flag.Bool("version", false, "Show version and exit")
 	historyLimit := flag.Int("history-limit", 10000, "Maximum number of events to retain in timeline")
 	debugEvents := flag.Bool("debug-events", false, "Enable verbose event debugging (logs all event drops)")
 	// Timeline storage options
-	timelineStorage := flag.String("timeline-storage", "memory", "Timeline storage backend: memory or sqlite")
+	timelineStorage := flag.String("timeline-storage", "memory", "Timeline storage backend: memory, sqlite or postgres")
 	timelineDBPath := flag.String("timeline-db", "", "Path to timeline database file (default: ~/.radar/timeline.db)")
+	timelineDSN := flag.String("timeline-dsn", "", "PostgreSQL connection string for postgres timeline storage (default: PG* environment variables)")
+	timelineMaxConns := flag.Int("timeline-max-conns", 10, "PostgreSQL connection pool size for postgres timeline storage")
+	timelineRetention := flag.Duration("timeline-retention", 0, "Delete timeline events older than this with sqlite or postgres storage (0 = no age limit)")
+	timelineMaxRowsPerKind := flag.Int("timeline-max-rows-per-kind", 0, "Keep at most this many timeline events per kind with sqlite storage (0 = unlimited)")
+	timelineMaxDBSizeMB := flag.Int("timeline-max-db-size-mb", 0, "Delete the oldest timeline events to keep the sqlite database under this size in MB (0 = unlimited)")
+	timelineDownsampleAfter := flag.Duration("timeline-downsample-after", 0, "Collapse each resource's updates within an hour into one aggregated event once older than this, with sqlite storage (0 = off)")
+	// Metrics history storage options
+	metricsStorage := flag.String("metrics-storage", "memory", "Pod and node metrics history storage: memory (last hour) or sqlite (survives restarts, with 5m and 1h rollups)")
+	metricsDBPath := flag.String("metrics-db", "", "Path to metrics history database file (default: ~/.radar/metrics.db)")
+	metricsRetentionRaw := flag.Duration("metrics-retention-raw", timeline.DefaultMetricsRetention.Raw, "How long raw 30s metrics samples are kept with sqlite storage")
+	metricsRetention5m := flag.Duration("metrics-retention-5m", timeline.DefaultMetricsRetention.FiveMinute, "How long 5 minute metrics rollups are kept with sqlite storage")
+	metricsRetention1h := flag.Duration("metrics-retention-1h", timeline.DefaultMetricsRetention.Hour, "How long 1 hour metrics rollups are kept with sqlite storage")
+	notificationsConfig := flag.String("notifications-config", "", "Path to notification channels config file (YAML or JSON)")
+	hygieneInterval := flag.Duration("hygiene-interval", time.Hour, "How often to record the cluster hygiene score (history kept in ~/.radar/hygiene-history.json)")
+	costPricing := flag.String("cost-pricing", "", "Pricing table for cost estimates: a YAML/JSON file or http(s) URL (default: built-in per-CPU and per-GiB rates)")
+	enableNodeShell := flag.Bool("enable-node-shell", false, "Allow opening host shells on nodes via privileged debug pods (audited)")
+	nodeShellImage := flag.String("node-shell-image", "busybox:1.36", "Image for node shell debug pods (must provide nsenter)")
+	nodeShellNamespace := flag.String("node-shell-namespace", "default", "Namespace to create node shell debug pods in")
+	debugImages := flag.String("debug-images", "busybox:1.36,nicolaka/netshoot:latest", "Comma-separated images offered for ephemeral debug containers; the first is the default")
+	fileTransferMaxMB := flag.Int("file-transfer-max-mb", 1024, "Largest pod file download or upload in MB (0 = unlimited)")
+	scaleHPAPolicy := flag.String("scale-hpa-policy", k8s.ScaleHPAPolicyWarn, "Scaling a workload an HPA manages: warn (scale, and warn the HPA will revert it) or refuse (only its HPA range can be changed)")
+	execAudit := flag.String("exec-audit", "", "Comma-separated sinks to record exec and node shell sessions to: file:<dir>, sqlite:<path>, webhook:<url>, or an s3://, gs:// or azblob:// URL")
+	execAuditInput := flag.Bool("exec-audit-input", false, "Also record keystrokes in exec session recordings (may capture typed secrets)")
+	trafficMetrics := flag.Bool("traffic-metrics", false, "Annotate traffic view edges with request rate, error rate and p99 latency from Prometheus")
+	prometheusURL := flag.String("prometheus-url", "", "Prometheus URL for --traffic-metrics (default: discover a Prometheus service in the cluster)")
+	imageInspection := flag.Bool("image-inspection", false, "Look up running images' registry digests and build times, and flag pods running an outdated digest")
+	imagePullSecrets := flag.String("image-pull-secrets", "", "Comma-separated pull secrets (namespace/name) for --image-inspection, besides each pod's own imagePullSecrets")
+	imageVulnerabilities := flag.String("image-vulnerabilities", "", "Vulnerability counts for --image-inspection: harbor (registry scan results) or trivy (Trivy Operator VulnerabilityReports)")
+	imageInspectionInterval := flag.Duration("image-inspection-interval", images.DefaultInterval, "How often --image-inspection looks up each running image again")
+	portForwardProfiles := flag.String("port-forward-profiles", "", "Comma-separated saved port-forward profiles to start at launch")
+	impersonate := flag.Bool("impersonate", false, "Run changes made for a token's Kubernetes user (edits, deletes, exec, Helm) as that user via impersonation headers (needs the impersonate verb)")
+	secretsMode := flag.String("secrets", k8s.SecretsModeAuto, "How to watch secrets: auto (full if RBAC allows), full, metadata (names/types/ages only, values never loaded) or off")
+	watchList := flag.String("watch-list", k8s.WatchListModeAuto, "How informers load their initial state: auto (streaming lists when the API server supports them), on or off (paged LIST)")
+	artifactStore := flag.String("artifact-store", "", "Where POSTs to the timeline and replay export endpoints write: s3://bucket/prefix, gs://bucket/prefix, azblob://account/container/prefix or file:///dir (credentials from the provider's environment variables or workload identity)")
+	replayBundle := flag.String("replay", "", "Serve a recorded bundle (from /api/replay/export) instead of a live cluster")
+	replaySpeed := flag.Float64("replay-speed", 1, "Replay timeline playback speed multiplier (0 = load the whole timeline at once)")
 	flag.Parse()
 
+	// Layer config file, profile and RADAR_* env overrides under explicit flags
+	fileCfg, err := applyConfig(*configPath, *profile)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
 	// Set debug mode for event tracking
 	k8s.DebugEvents = *debugEvents
+	if err := k8s.ValidateSecretsMode(*secretsMode); err != nil {
+		log.Fatalf("%v", err)
+	}
+	k8s.SecretsMode = *secretsMode
+	if err := k8s.ValidateWatchListMode(*watchList); err != nil {
+		log.Fatalf("%v", err)
+	}
+	k8s.WatchListMode = *watchList
+	if err := k8s.ValidateScaleHPAPolicy(*scaleHPAPolicy); err != nil {
+		log.Fatalf("%v", err)
+	}
+	k8s.SetImpersonation(*impersonate)
+	if err := k8s.SetDiffRules(fileCfg.Timeline.DiffRules); err != nil {
+		log.Fatalf("Invalid timeline.diffRules: %v", err)
+	}
+	if err := health.SetRules(fileCfg.Health.Rules); err != nil {
+		log.Fatalf("Invalid health.rules: %v", err)
+	}
+	for _, ns := range strings.Split(*watchNamespaces, ",") {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			if errs := validation.IsDNS1123Label(ns); len(errs) > 0 {
+				log.Fatalf("--namespaces: invalid namespace %q: %s", ns, errs[0])
+			}
+			k8s.WatchNamespaces = append(k8s.WatchNamespaces, ns)
+		}
+	}
 
 	if *showVersion {
 		fmt.Printf("radar %s\n", version)
@@ -66,6 +178,29 @@ func main() {
 		log.Fatalf("--kubeconfig and --kubeconfig-dir are mutually exclusive")
 	}
 
+	// Replay mode: serve the bundle from a local read-only API server and connect to it
+	// like any other cluster. History stays in memory so the real timeline DB is untouched.
+	var bundle *replay.Bundle
+	var clientContentType string
+	if *replayBundle != "" {
+		if *kubeconfig != "" || *kubeconfigDir != "" {
+			log.Fatalf("--replay cannot be combined with --kubeconfig or --kubeconfig-dir")
+		}
+		bundle, err = replay.LoadBundle(*replayBundle)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		replayKubeconfig, err := replay.StartAPIServer(bundle)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		*kubeconfig = replayKubeconfig
+		*timelineStorage = "memory"
+		*metricsStorage = "memory"
+		clientContentType = "application/json" // The replay server doesn't decode protobuf
+		log.Printf("Replaying %s captured %s", *replayBundle, bundle.CapturedAt.Format(time.RFC3339))
+	}
+
 	// Parse kubeconfig directories if provided
 	var kubeconfigDirs []string
 	if *kubeconfigDir != "" {
@@ -78,16 +213,22 @@ func main() {
 	}
 
 	// Initialize K8s client
-	err := k8s.Initialize(k8s.InitOptions{
+	donePhase := k8s.StartPhase("kubeconfig")
+	err = k8s.Initialize(k8s.InitOptions{
 		KubeconfigPath: *kubeconfig,
 		KubeconfigDirs: kubeconfigDirs,
+		SkipInCluster:  bundle != nil,
+		ContentType:    clientContentType,
 	})
+	donePhase(err)
 	if err != nil {
 		log.Fatalf("Failed to initialize K8s client: %v", err)
 	}
 
 	if len(kubeconfigDirs) > 0 {
 		log.Printf("Using kubeconfigs from directories: %v", kubeconfigDirs)
+	} else if kubepaths := k8s.GetKubeconfigPaths(); len(kubepaths) > 1 {
+		log.Printf("Using %d kubeconfigs: %s", len(kubepaths), strings.Join(kubepaths, ", "))
 	} else if kubepath := k8s.GetKubeconfigPath(); kubepath != "" {
 		log.Printf("Using kubeconfig: %s", kubepath)
 	} else {
@@ -95,15 +236,21 @@ func main() {
 	}
 
 	// Preflight check: verify cluster connectivity before starting informers
-	if err := checkClusterAccess(); err != nil {
+	donePhase = k8s.StartPhase("cluster-access")
+	err = checkClusterAccess()
+	donePhase(err)
+	if err != nil {
 		// Error already printed with helpful message
 		os.Exit(1)
 	}
 
-	// Initialize timeline event store (unified storage for all events)
+	// Initialize timeline event store (unified storage for all events). Seen resources
+	// are tracked per cluster and context.
+	seenScope := func() string { return k8s.GetClusterName() + "/" + k8s.GetContextName() }
 	timelineStoreCfg := timeline.StoreConfig{
 		Type:    timeline.StoreTypeMemory,
 		MaxSize: *historyLimit,
+		Scope:   seenScope(),
 	}
 	if *timelineStorage == "sqlite" {
 		timelineStoreCfg.Type = timeline.StoreTypeSQLite
@@ -113,25 +260,53 @@ func main() {
 			dbPath = filepath.Join(homeDir, ".radar", "timeline.db")
 		}
 		timelineStoreCfg.Path = dbPath
+		timelineStoreCfg.MaxAge = *timelineRetention
+		timelineStoreCfg.MaxRowsPerKind = *timelineMaxRowsPerKind
+		timelineStoreCfg.MaxDBSize = int64(*timelineMaxDBSizeMB) << 20
+		timelineStoreCfg.DownsampleAfter = *timelineDownsampleAfter
+	}
+	if *timelineStorage == "postgres" {
+		timelineStoreCfg.Type = timeline.StoreTypePostgres
+		timelineStoreCfg.DSN = *timelineDSN
+		timelineStoreCfg.MaxConns = *timelineMaxConns
+		timelineStoreCfg.MaxAge = *timelineRetention
 	}
-	if err := timeline.InitStore(timelineStoreCfg); err != nil {
+	donePhase = k8s.StartPhase("timeline-store")
+	err = timeline.InitStore(timelineStoreCfg)
+	donePhase(err)
+	if err != nil {
 		log.Fatalf("Failed to initialize timeline store: %v", err)
 	}
 
+	// Detect server version and served API groups (gates which informers are started)
+	donePhase = k8s.StartPhase("feature-detection")
+	err = k8s.InitFeatureDetection()
+	donePhase(err)
+	if err != nil {
+		log.Printf("Warning: Failed to detect cluster features: %v", err)
+	}
+
 	// Initialize resource cache (typed informers for core resources)
-	if err := k8s.InitResourceCache(); err != nil {
+	donePhase = k8s.StartPhase("informer-sync")
+	err = k8s.InitResourceCache()
+	donePhase(err)
+	if err != nil {
 		log.Fatalf("Failed to initialize resource cache: %v", err)
 	}
 
 	log.Printf("Resource cache initialized with %d resources", k8s.GetResourceCache().GetResourceCount())
 
 	// Initialize resource discovery (for CRD support)
-	if err := k8s.InitResourceDiscovery(); err != nil {
+	donePhase = k8s.StartPhase("crd-discovery")
+	err = k8s.InitResourceDiscovery()
+	donePhase(err)
+	if err != nil {
 		log.Printf("Warning: Failed to initialize resource discovery: %v", err)
 	}
 
 	// Initialize dynamic resource cache (for CRDs)
 	// Share the change channel with the typed cache so all changes go to SSE
+	donePhase = k8s.StartPhase("crd-warmup")
 	changeCh := k8s.GetResourceCache().ChangesRaw()
 	if err := k8s.InitDynamicResourceCache(changeCh); err != nil {
 		log.Printf("Warning: Failed to initialize dynamic resource cache: %v", err)
@@ -139,25 +314,90 @@ func main() {
 
 	// Warm up dynamic cache for common CRDs so they appear in initial timeline
 	k8s.WarmupCommonCRDs()
+	donePhase(nil)
 
 	// Initialize metrics history collection (polls metrics-server every 30s)
-	k8s.InitMetricsHistory()
+	donePhase = k8s.StartPhase("metrics-init")
+	var metricsStore *timeline.MetricsStore
+	if *metricsStorage == "sqlite" {
+		dbPath := *metricsDBPath
+		if dbPath == "" {
+			homeDir, _ := os.UserHomeDir()
+			dbPath = filepath.Join(homeDir, ".radar", "metrics.db")
+		}
+		retention := timeline.MetricsRetention{Raw: *metricsRetentionRaw, FiveMinute: *metricsRetention5m, Hour: *metricsRetention1h}
+		if metricsStore, err = timeline.NewMetricsStore(dbPath, retention); err != nil {
+			log.Printf("Warning: metrics history will not survive restarts: %v", err)
+		} else {
+			log.Printf("Persisting metrics history to %s (retention: %s)", dbPath, metricsStore.Retention())
+		}
+	}
+	k8s.InitMetricsHistory(metricsStore)
+	donePhase(nil)
+
+	// Start cross-resource consistency checks (published via the problems API)
+	k8s.InitConsistencyChecker()
+
+	// Sample quota and PVC usage for exhaustion forecasts (insights API, dashboard)
+	k8s.InitUsageForecaster()
+
+	// Start recurring hygiene scoring with persisted trend history
+	hygienePath := ""
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		hygienePath = filepath.Join(homeDir, ".radar", "hygiene-history.json")
+	}
+	if err := hygiene.InitRecorder(hygienePath, *hygieneInterval); err != nil {
+		log.Printf("Warning: Failed to initialize hygiene scoring: %v", err)
+	}
+
+	// Load the pricing table for cost estimates
+	if err := cost.Init(*costPricing); err != nil {
+		log.Printf("Warning: Failed to load cost pricing, using default rates: %v", err)
+	}
+
+	// Load persisted server settings (port-forward profiles, ...)
+	settingsPath := ""
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		settingsPath = filepath.Join(homeDir, ".radar", "settings.json")
+	}
+	if err := settings.Init(settingsPath); err != nil {
+		log.Printf("Warning: Failed to load settings, using defaults: %v", err)
+	}
+
+	// Evaluate workloads against the resource policy (rules stored in settings)
+	policy.InitChecker()
+	k8s.OnContextSwitch(func(string) {
+		policy.GetChecker().Reset()
+	})
 
 	// Initialize Helm client
-	if err := helm.Initialize(k8s.GetKubeconfigPath()); err != nil {
+	donePhase = k8s.StartPhase("helm-init")
+	err = helm.Initialize(k8s.GetKubeconfigPath())
+	donePhase(err)
+	if err != nil {
 		log.Printf("Warning: Failed to initialize Helm client: %v", err)
 	}
 
+	// Enforce the resource policy on Helm installs/upgrades made through Radar
+	helm.SetManifestGate(func(namespace, manifest string) error {
+		return policy.CheckManifest(manifest, namespace, policy.NamespaceLabels)
+	})
+
 	// Register Helm reset/reinit functions for context switching
 	k8s.RegisterHelmFuncs(helm.ResetClient, helm.ReinitClient)
 
 	// Register timeline store reset/reinit functions for context switching
 	k8s.RegisterTimelineFuncs(timeline.ResetStore, func() error {
-		return timeline.ReinitStore(timelineStoreCfg)
+		cfg := timelineStoreCfg
+		cfg.Scope = seenScope()
+		return timeline.ReinitStore(cfg)
 	})
 
 	// Initialize traffic source manager with full config for port-forward support
-	if err := traffic.InitializeWithConfig(k8s.GetClient(), k8s.GetConfig(), k8s.GetContextName()); err != nil {
+	donePhase = k8s.StartPhase("traffic-init")
+	err = traffic.InitializeWithConfig(k8s.GetClient(), k8s.GetConfig(), k8s.GetContextName())
+	donePhase(err)
+	if err != nil {
 		log.Printf("Warning: Failed to initialize traffic manager: %v", err)
 	}
 
@@ -166,33 +406,171 @@ func main() {
 		return traffic.ReinitializeWithConfig(k8s.GetClient(), k8s.GetConfig(), k8s.GetContextName())
 	})
 
+	// Annotate traffic topology edges with Prometheus request metrics (optional)
+	if *trafficMetrics {
+		rates := traffic.NewRateCollector(*prometheusURL, traffic.DefaultRateInterval)
+		topology.SetTrafficLoadSource(rates)
+		rates.Start(context.Background())
+	}
+
+	// Inspect running images in their registries (optional; a replay has no registry credentials)
+	if *imageInspection && bundle == nil {
+		opts := images.Options{Vulnerabilities: *imageVulnerabilities, Interval: *imageInspectionInterval}
+		for _, name := range strings.Split(*imagePullSecrets, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				opts.PullSecrets = append(opts.PullSecrets, name)
+			}
+		}
+		if err := images.Init(opts); err != nil {
+			log.Fatalf("%v", err)
+		}
+		k8s.OnContextSwitch(func(string) {
+			images.Reset()
+		})
+	}
+
+	// Initialize notification channels, lifecycle triggers and health alert rules (optional)
+	if *notificationsConfig != "" || len(fileCfg.Notifications.Channels) > 0 || len(fileCfg.Notifications.Triggers) > 0 || len(fileCfg.Notifications.Rules) > 0 {
+		notifCfg := notifications.Config{Channels: fileCfg.Notifications.Channels, Triggers: fileCfg.Notifications.Triggers, Rules: fileCfg.Notifications.Rules}
+		var loadErr error
+		if *notificationsConfig != "" {
+			notifCfg, loadErr = notifications.LoadConfig(*notificationsConfig)
+		}
+		if loadErr != nil {
+			log.Printf("Warning: Failed to load notifications config: %v", loadErr)
+		} else if err := notifications.Initialize(notifCfg, k8s.GetClusterName()); err != nil {
+			log.Printf("Warning: Invalid notifications config: %v", err)
+		}
+		notifications.StartLifecycleWatcher()
+		// Keep the cluster name on outgoing alerts in sync with the active context
+		k8s.OnContextSwitch(func(newContext string) {
+			if m := notifications.GetManager(); m != nil {
+				m.SetCluster(k8s.GetClusterName())
+			}
+			notifications.ResetLifecycleState()
+		})
+	}
+
 	// Create and start server
 	cfg := server.Config{
 		Port:       *port,
 		DevMode:    *devMode,
 		StaticFS:   static.FS,
 		StaticRoot: "dist",
+		NodeShell: server.NodeShellConfig{
+			Enabled:   *enableNodeShell,
+			Image:     *nodeShellImage,
+			Namespace: *nodeShellNamespace,
+		},
+		RequireAPIToken: *requireAPIToken,
+		TLS: server.TLSConfig{
+			CertFile:     *tlsCert,
+			KeyFile:      *tlsKey,
+			ClientCAFile: *tlsClientCA,
+		},
+		FileTransferMaxBytes: int64(*fileTransferMaxMB) << 20,
+		ScaleHPAPolicy:       *scaleHPAPolicy,
+		PublicSnapshot: server.PublicSnapshotConfig{
+			Serve:     *publicSnapshot,
+			File:      *publicSnapshotFile,
+			Interval:  *publicSnapshotInterval,
+			HideNames: *publicSnapshotHideNames,
+		},
+	}
+	proxyAuth, err := auth.NewProxyAuthenticator(auth.ProxyConfig{
+		UserHeader:        *authProxyUserHeader,
+		GroupsHeader:      *authProxyGroupsHeader,
+		SecretHeader:      *authProxySecretHeader,
+		Secret:            *authProxySecret,
+		RequireClientCert: *tlsClientCA != "",
+	})
+	if err != nil {
+		log.Fatalf("Invalid proxy auth settings: %v", err)
+	}
+	if proxyAuth != nil {
+		cfg.Authenticators = append(cfg.Authenticators, proxyAuth)
+		log.Printf("Trusting users from %s set by the authenticating proxy", *authProxyUserHeader)
+	}
+	for _, image := range strings.Split(*debugImages, ",") {
+		if image = strings.TrimSpace(image); image != "" {
+			cfg.Debug.Images = append(cfg.Debug.Images, image)
+		}
+	}
+	for _, ns := range strings.Split(*publicSnapshotNamespaces, ",") {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			cfg.PublicSnapshot.Namespaces = append(cfg.PublicSnapshot.Namespaces, ns)
+		}
+	}
+	if *enableNodeShell {
+		log.Printf("Node shell enabled (image=%s, namespace=%s) - sessions are audit logged", *nodeShellImage, *nodeShellNamespace)
+	}
+
+	if *execAudit != "" {
+		var sinks []string
+		for _, spec := range strings.Split(*execAudit, ",") {
+			if spec = strings.TrimSpace(spec); spec != "" {
+				sinks = append(sinks, spec)
+			}
+		}
+		if err := execaudit.Configure(execaudit.Config{Sinks: sinks, RecordInput: *execAuditInput}); err != nil {
+			log.Fatalf("Invalid --exec-audit: %v", err)
+		}
+	}
+
+	if err := objectstore.Configure(*artifactStore); err != nil {
+		log.Fatalf("Invalid --artifact-store: %v", err)
 	}
 
 	srv := server.New(cfg)
 
-	// Handle shutdown signals
+	if *portForwardProfiles != "" {
+		var names []string
+		for _, name := range strings.Split(*portForwardProfiles, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+		go server.AutoStartPortForwardProfiles(names)
+	}
+
+	// Handle shutdown signals: stop taking requests, tell connected clients, let in-flight
+	// work and timeline writes finish within --shutdown-timeout, then close stores
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	shutdownDone := make(chan struct{})
 
 	go func() {
 		<-sigCh
-		log.Println("Shutting down...")
-		srv.Stop()
+		log.Printf("Shutting down (waiting up to %s; signal again to exit now)...", *shutdownTimeout)
+		go func() {
+			<-sigCh
+			log.Println("Forced exit")
+			os.Exit(1)
+		}()
+		ctx, cancel := context.WithTimeout(context.Background(), *shutdownTimeout)
+		defer cancel()
+
+		if err := srv.Shutdown(ctx); err != nil {
+			log.Printf("Warning: server shutdown: %v", err)
+		}
+		execaudit.Flush(ctx)
+		server.StopAllPortForwards()
+		notifications.StopLifecycleWatcher()
+		images.Stop()
+		replay.StopPlayer()
+		// Stop informers so no new timeline writes start, then wait for running ones
 		if cache := k8s.GetResourceCache(); cache != nil {
 			cache.Stop()
 		}
 		if dynCache := k8s.GetDynamicResourceCache(); dynCache != nil {
 			dynCache.Stop()
 		}
-		// Close timeline store
+		if err := timeline.Drain(ctx); err != nil {
+			log.Printf("Warning: %v", err)
+		}
 		timeline.ResetStore()
-		os.Exit(0)
+		k8s.StopMetricsHistory()
+		close(shutdownDone)
 	}()
 
 	// Open browser unless disabled
@@ -204,10 +582,19 @@ func main() {
 		go openBrowser(url)
 	}
 
-	// Start server (blocks)
+	// Log where startup time went (also served at /api/debug/startup)
+	k8s.MarkStartupComplete()
+
+	if bundle != nil {
+		replay.StartPlayer(bundle, *replaySpeed)
+	}
+
+	// Start server (blocks until shutdown begins)
 	if err := srv.Start(); err != nil {
 		log.Fatalf("Server error: %v", err)
 	}
+	<-shutdownDone
+	log.Println("Shutdown complete")
 }
 
 func openBrowser(url string) {
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/skyhook-io/radar/internal/timeline"
+)
+
+const migrateUsage = "Usage: radar migrate [--timeline-storage sqlite|postgres] [--timeline-db PATH] [--timeline-dsn DSN] status | down <version>"
+
+// runMigrateCommand inspects or reverts the timeline database schema and returns the exit
+// code. Radar applies pending migrations itself at startup; down is for downgrades.
+func runMigrateCommand(args []string) int {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	storage := fs.String("timeline-storage", "sqlite", "Timeline storage backend: sqlite or postgres")
+	dbPath := fs.String("timeline-db", "", "Path to timeline database file (default: ~/.radar/timeline.db)")
+	dsn := fs.String("timeline-dsn", "", "PostgreSQL connection string (default: PG* environment variables)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg := timeline.StoreConfig{Type: timeline.StoreType(*storage), Path: *dbPath, DSN: *dsn}
+	if cfg.Type == timeline.StoreTypeSQLite && cfg.Path == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "✗ %v\n", err)
+			return 1
+		}
+		cfg.Path = filepath.Join(homeDir, ".radar", "timeline.db")
+	}
+	ctx := context.Background()
+
+	switch fs.Arg(0) {
+	case "status":
+		st, err := timeline.GetSchemaStatus(ctx, cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "✗ %v\n", err)
+			return 1
+		}
+		fmt.Printf("Schema version %d (this build supports %d)\n", st.Version, st.Latest)
+		for _, m := range st.Applied {
+			fmt.Printf("  ✓ %d %s  %s\n", m.Version, m.Name, m.AppliedAt)
+		}
+		for i, name := range st.Pending {
+			fmt.Printf("  … %d %s (applied at next start)\n", st.Version+i+1, name)
+		}
+		return 0
+
+	case "down":
+		if fs.NArg() != 2 {
+			fmt.Fprintln(os.Stderr, migrateUsage)
+			return 2
+		}
+		target, err := strconv.Atoi(fs.Arg(1))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, migrateUsage)
+			return 2
+		}
+		from, backup, err := timeline.MigrateDown(ctx, cfg, target)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "✗ %v\n", err)
+			return 1
+		}
+		if backup != "" {
+			fmt.Printf("Backed up to %s\n", backup)
+		}
+		fmt.Printf("✓ Reverted schema from version %d to %d\n", from, target)
+		return 0
+
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+}
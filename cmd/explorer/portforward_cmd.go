@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const portForwardUsage = "Usage: radar port-forward [--server URL] list | start <profile> | stop <profile>"
+
+// runPortForwardCommand controls saved port-forward profiles on a running Radar
+// server and returns the exit code
+func runPortForwardCommand(args []string) int {
+	fs := flag.NewFlagSet("port-forward", flag.ContinueOnError)
+	server := fs.String("server", "http://localhost:9280", "Radar server URL")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	action, name := fs.Arg(0), fs.Arg(1)
+	base := strings.TrimRight(*server, "/") + "/api/portforward-profiles"
+	client := &http.Client{Timeout: 60 * time.Second}
+
+	switch action {
+	case "list":
+		var profiles []struct {
+			Name        string `json:"name"`
+			Description string `json:"description"`
+			Active      bool   `json:"active"`
+			Forwards    []struct {
+				Namespace   string `json:"namespace"`
+				PodName     string `json:"podName"`
+				ServiceName string `json:"serviceName"`
+				PodPort     int    `json:"podPort"`
+				LocalPort   int    `json:"localPort"`
+			} `json:"forwards"`
+		}
+		if err := doPortForwardRequest(client, http.MethodGet, base, &profiles); err != nil {
+			fmt.Fprintf(os.Stderr, "✗ %v\n", err)
+			return 1
+		}
+		if len(profiles) == 0 {
+			fmt.Println("No port-forward profiles saved")
+			return 0
+		}
+		for _, p := range profiles {
+			state := "stopped"
+			if p.Active {
+				state = "running"
+			}
+			fmt.Printf("%s (%s)", p.Name, state)
+			if p.Description != "" {
+				fmt.Printf(" - %s", p.Description)
+			}
+			fmt.Println()
+			for _, f := range p.Forwards {
+				target := "svc/" + f.ServiceName
+				if f.ServiceName == "" {
+					target = "pod/" + f.PodName
+				}
+				local := "auto"
+				if f.LocalPort != 0 {
+					local = fmt.Sprintf("%d", f.LocalPort)
+				}
+				fmt.Printf("  %s/%s:%d -> localhost:%s\n", f.Namespace, target, f.PodPort, local)
+			}
+		}
+		return 0
+
+	case "start", "stop":
+		if name == "" {
+			fmt.Fprintln(os.Stderr, portForwardUsage)
+			return 2
+		}
+		endpoint := base + "/" + url.PathEscape(name) + "/" + action
+		if action == "stop" {
+			var result struct {
+				Stopped int `json:"stopped"`
+			}
+			if err := doPortForwardRequest(client, http.MethodPost, endpoint, &result); err != nil {
+				fmt.Fprintf(os.Stderr, "✗ %v\n", err)
+				return 1
+			}
+			fmt.Printf("✓ Stopped %d forward(s) for %s\n", result.Stopped, name)
+			return 0
+		}
+
+		var result struct {
+			Sessions []struct {
+				Namespace string `json:"namespace"`
+				PodName   string `json:"podName"`
+				PodPort   int    `json:"podPort"`
+				LocalPort int    `json:"localPort"`
+			} `json:"sessions"`
+			Errors []string `json:"errors"`
+		}
+		if err := doPortForwardRequest(client, http.MethodPost, endpoint, &result); err != nil {
+			fmt.Fprintf(os.Stderr, "✗ %v\n", err)
+			return 1
+		}
+		for _, s := range result.Sessions {
+			fmt.Printf("✓ localhost:%d -> %s/%s:%d\n", s.LocalPort, s.Namespace, s.PodName, s.PodPort)
+		}
+		for _, msg := range result.Errors {
+			fmt.Fprintf(os.Stderr, "✗ %s\n", msg)
+		}
+		if len(result.Errors) > 0 {
+			return 1
+		}
+		return 0
+
+	default:
+		fmt.Fprintln(os.Stderr, portForwardUsage)
+		return 2
+	}
+}
+
+func doPortForwardRequest(client *http.Client, method, endpoint string, out any) error {
+	req, err := http.NewRequest(method, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot reach Radar server (is it running?): %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 400 {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(body, &apiErr) == nil && apiErr.Error != "" {
+			return fmt.Errorf("%s", apiErr.Error)
+		}
+		return fmt.Errorf("server returned %s", resp.Status)
+	}
+	return json.Unmarshal(body, out)
+}
@@ -0,0 +1,124 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+	"github.com/skyhook-io/radar/internal/timeline"
+)
+
+// Handlers provides HTTP handlers for API token management
+type Handlers struct{}
+
+// NewHandlers creates a new Handlers instance
+func NewHandlers() *Handlers {
+	return &Handlers{}
+}
+
+// RegisterRoutes registers token management routes on the given router
+func (h *Handlers) RegisterRoutes(r chi.Router) {
+	r.Route("/tokens", func(r chi.Router) {
+		r.Get("/", h.handleListTokens)
+		r.Post("/", h.handleCreateToken)
+		r.Delete("/{id}", h.handleRevokeToken)
+	})
+}
+
+// CreateTokenRequest is the body of POST /api/tokens
+type CreateTokenRequest struct {
+	Name  string `json:"name"`
+	Scope Scope  `json:"scope"`
+	TTL   string `json:"ttl,omitempty"` // Go duration, e.g. "720h" (empty = never expires)
+}
+
+// CreateTokenResponse returns the new token and its secret, which is not shown again
+type CreateTokenResponse struct {
+	Token
+	Secret string `json:"secret"`
+}
+
+// ownTokens returns the tokens the request may manage: all of them, or for a proxy user
+// only those bound to that user
+func ownTokens(r *http.Request) []Token {
+	tokens := List()
+	u, ok := sessionUser(r.Context())
+	if !ok {
+		return tokens
+	}
+	own := make([]Token, 0, len(tokens))
+	for _, t := range tokens {
+		if t.Scope.User != nil && t.Scope.User.Name == u.Name {
+			own = append(own, t)
+		}
+	}
+	return own
+}
+
+// handleListTokens lists tokens without their secrets
+func (h *Handlers) handleListTokens(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, ownTokens(r))
+}
+
+// handleCreateToken issues a token and records it in the audit log. A proxy user's
+// tokens are bound to that user, so they can't act with more than the user's RBAC.
+func (h *Handlers) handleCreateToken(w http.ResponseWriter, r *http.Request) {
+	var req CreateTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	var ttl time.Duration
+	if req.TTL != "" {
+		var err error
+		if ttl, err = time.ParseDuration(req.TTL); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid ttl: "+err.Error())
+			return
+		}
+	}
+
+	if u, ok := sessionUser(r.Context()); ok {
+		req.Scope.User = &u
+	}
+
+	token, secret, err := Create(req.Name, req.Scope, ttl)
+	if err != nil {
+		explorerErrors.Write(w, explorerErrors.ValidationError(err.Error()))
+		return
+	}
+	timeline.RecordAction(r.Context(), "create-token", "APIToken", "", token.Name, Actor(r))
+	writeJSON(w, CreateTokenResponse{Token: token, Secret: secret})
+}
+
+// handleRevokeToken deletes a token so it stops working immediately
+func (h *Handlers) handleRevokeToken(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if !slices.ContainsFunc(ownTokens(r), func(t Token) bool { return t.ID == id }) {
+		writeError(w, http.StatusNotFound, "Token not found")
+		return
+	}
+	token, ok, err := Revoke(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !ok {
+		writeError(w, http.StatusNotFound, "Token not found")
+		return
+	}
+	timeline.RecordAction(r.Context(), "revoke-token", "APIToken", "", token.Name, Actor(r))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeJSON(w http.ResponseWriter, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(data)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	explorerErrors.WriteHTTP(w, status, message)
+}
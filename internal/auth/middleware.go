@@ -0,0 +1,278 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+)
+
+type tokenKey struct{}
+
+// sessionKey marks requests whose user was established by an Authenticator
+type sessionKey struct{}
+
+// interactiveRoutes open a shell or exec session over GET, so read-only tokens can't use them
+var interactiveRoutes = map[string]bool{
+	"/api/pods/{namespace}/{name}/exec":           true,
+	"/api/pods/{namespace}/{name}/debug":          true,
+	"/api/pods/{namespace}/{name}/files":          true,
+	"/api/pods/{namespace}/{name}/files/download": true,
+	"/api/nodes/{name}/shell":                     true,
+	"/api/exec/shared/{token}":                    true,
+	"/api/exec/sessions/{id}/attach":              true,
+}
+
+// clusterRoutes may be called by namespace-limited tokens without naming a namespace.
+// Exec session routes act on sessions the caller already opened or was invited to.
+var clusterRoutes = map[string]bool{
+	"/api/health":                                        true,
+	"/api/capabilities":                                  true,
+	"/api/exec/sessions/{id}/share":                      true,
+	"/api/exec/sessions/{id}/share/{token}":              true,
+	"/api/exec/sessions/{id}/participants/{participant}": true,
+}
+
+// clusterScopedRoutes act on cluster-scoped objects whatever namespace the request
+// names, so namespace-limited tokens can't call them
+var clusterScopedRoutes = map[string]bool{
+	"/api/nodes":                 true,
+	"/api/nodes/{name}":          true,
+	"/api/nodes/{name}/cordon":   true,
+	"/api/nodes/{name}/uncordon": true,
+	"/api/nodes/{name}/drain":    true,
+	"/api/nodes/{name}/shell":    true,
+	"/api/watch-namespaces":      true,
+	"/api/contexts/{name}":       true,
+}
+
+// bodyNamespaceRoutes take their target namespaces from the request body. Their handlers
+// check each against the token's scope with CheckNamespace.
+var bodyNamespaceRoutes = map[string]bool{
+	"/api/portforwards":                 true,
+	"/api/helm/releases":                true,
+	"/api/helm/releases/install-stream": true,
+	"/api/permissions/check":            true,
+	"/api/image-rollouts":               true,
+	"/api/pods/bulk":                    true,
+	"/api/workloads/restart":            true,
+}
+
+// queryNamespaceRoutes change state without a {namespace} in the path, scoped by
+// ?namespace= alone
+var queryNamespaceRoutes = map[string]bool{
+	"/api/changes/annotations": true,
+}
+
+// managementPrefix is the token management API, which tokens can never call
+const managementPrefix = "/api/tokens"
+
+// Middleware authenticates API requests that carry "Authorization: Bearer <token>" and
+// enforces the token's scope. routes resolves the route pattern and URL parameters, which
+// aren't known yet when the middleware runs. Requests without a token are passed to the
+// authenticators in turn; the first that recognizes the request sets its user. Requests
+// neither recognizes are let through (the browser UI) unless requireToken is set or an
+// authenticator is configured, in which case only loopback clients may omit credentials:
+// a client reaching Radar around the proxy mustn't act as Radar's service account.
+func Middleware(routes chi.Routes, requireToken bool, authenticators ...Authenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			secret, ok := bearerToken(r)
+			if !ok {
+				for _, a := range authenticators {
+					u, ok, err := a.Authenticate(r)
+					if err != nil {
+						explorerErrors.Write(w, explorerErrors.New(explorerErrors.ErrUnauthorized, err.Error()))
+						return
+					}
+					if ok {
+						ctx := context.WithValue(WithUser(r.Context(), u), sessionKey{}, true)
+						next.ServeHTTP(w, r.WithContext(ctx))
+						return
+					}
+				}
+				if (requireToken || len(authenticators) > 0) && !isLoopback(r.RemoteAddr) {
+					explorerErrors.Write(w, explorerErrors.New(explorerErrors.ErrUnauthorized,
+						"an API token is required (Authorization: Bearer <token>)"))
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			token, err := Authenticate(secret)
+			if err != nil {
+				explorerErrors.Write(w, explorerErrors.New(explorerErrors.ErrUnauthorized, err.Error()))
+				return
+			}
+			if err := authorize(routes, r, &token.Scope); err != nil {
+				explorerErrors.Write(w, explorerErrors.New(explorerErrors.ErrForbidden,
+					fmt.Sprintf("API token %q: %v", token.Name, err)))
+				return
+			}
+			ctx := context.WithValue(r.Context(), tokenKey{}, token)
+			if token.Scope.User != nil {
+				ctx = WithUser(ctx, *token.Scope.User)
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// FromContext returns the API token that authenticated the request, if any
+func FromContext(ctx context.Context) (Token, bool) {
+	t, ok := ctx.Value(tokenKey{}).(Token)
+	return t, ok
+}
+
+// Actor identifies who made a request, for the audit log: the token name for token
+// requests, the user for requests authenticated by a proxy, otherwise the remote address
+func Actor(r *http.Request) string {
+	if t, ok := FromContext(r.Context()); ok {
+		return "token:" + t.Name
+	}
+	if u, ok := sessionUser(r.Context()); ok {
+		return "user:" + u.Name
+	}
+	return r.RemoteAddr
+}
+
+// sessionUser returns the user an Authenticator established for the request, if any
+func sessionUser(ctx context.Context) (User, bool) {
+	if session, _ := ctx.Value(sessionKey{}).(bool); !session {
+		return User{}, false
+	}
+	return UserFromContext(ctx)
+}
+
+// authorize checks a request against a token scope
+func authorize(routes chi.Routes, r *http.Request, s *Scope) error {
+	p := r.URL.Path
+	if p == managementPrefix || strings.HasPrefix(p, managementPrefix+"/") {
+		return fmt.Errorf("tokens can't manage API tokens")
+	}
+
+	rctx := chi.NewRouteContext()
+	pattern := routes.Find(rctx, r.Method, p)
+
+	if s.ReadOnly {
+		if !isRead(r) {
+			return fmt.Errorf("read-only token can't %s %s", r.Method, p)
+		}
+		if interactiveRoutes[pattern] {
+			return fmt.Errorf("read-only token can't open interactive sessions")
+		}
+	}
+
+	if len(s.Endpoints) > 0 && !endpointAllowed(s.Endpoints, p) {
+		return fmt.Errorf("endpoint %s is not in the token's scope", p)
+	}
+
+	if len(s.Namespaces) > 0 && !clusterRoutes[pattern] {
+		if clusterScopedRoutes[pattern] {
+			return fmt.Errorf("token is limited to namespaces %s; %s acts cluster-wide", strings.Join(s.Namespaces, ","), p)
+		}
+		namespaces := requestNamespaces(rctx, r)
+		for _, ns := range namespaces {
+			if !contains(s.Namespaces, ns) {
+				return fmt.Errorf("namespace %q is not in the token's scope", ns)
+			}
+		}
+		switch {
+		case bodyNamespaceRoutes[pattern]:
+			// The handler checks the namespaces in the body
+		case rctx.URLParam("namespace") == "" && !isRead(r) && !queryNamespaceRoutes[pattern]:
+			// A namespace in the query doesn't scope a change the route makes elsewhere
+			return fmt.Errorf("token is limited to namespaces %s; %s %s isn't namespaced", strings.Join(s.Namespaces, ","), r.Method, p)
+		case len(namespaces) == 0:
+			return fmt.Errorf("token is limited to namespaces %s; add ?namespace=", strings.Join(s.Namespaces, ","))
+		}
+	}
+	return nil
+}
+
+// CheckNamespace returns an error unless the request's token may act in namespace ns.
+// Handlers call it for namespaces named in a request body, which the middleware can't
+// see; an empty ns means every namespace, which namespace-limited tokens can't target.
+func CheckNamespace(ctx context.Context, ns string) error {
+	t, ok := FromContext(ctx)
+	if !ok || len(t.Scope.Namespaces) == 0 {
+		return nil
+	}
+	if ns == "" {
+		return fmt.Errorf("API token %q is limited to namespaces %s; name one", t.Name, strings.Join(t.Scope.Namespaces, ","))
+	}
+	if !contains(t.Scope.Namespaces, ns) {
+		return fmt.Errorf("API token %q: namespace %q is not in the token's scope", t.Name, ns)
+	}
+	return nil
+}
+
+func isRead(r *http.Request) bool {
+	return r.Method == http.MethodGet || r.Method == http.MethodHead
+}
+
+// requestNamespaces returns the namespaces a request targets, from the {namespace} URL
+// parameter and the namespace query parameter (comma-separated)
+func requestNamespaces(rctx *chi.Context, r *http.Request) []string {
+	var namespaces []string
+	if ns := rctx.URLParam("namespace"); ns != "" {
+		namespaces = append(namespaces, ns)
+	}
+	for _, ns := range strings.Split(r.URL.Query().Get("namespace"), ",") {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces
+}
+
+func endpointAllowed(patterns []string, p string) bool {
+	p = strings.TrimSuffix(p, "/")
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok && !strings.ContainsAny(prefix, "*?[") {
+			if strings.HasPrefix(p, prefix) {
+				return true
+			}
+			continue
+		}
+		if ok, _ := path.Match(strings.TrimSuffix(pattern, "/"), p); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	h := r.Header.Get("Authorization")
+	scheme, token, ok := strings.Cut(h, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+func isLoopback(remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
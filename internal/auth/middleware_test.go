@@ -0,0 +1,192 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func testRouter(requireToken bool) *chi.Mux {
+	r := chi.NewRouter()
+	ok := func(w http.ResponseWriter, r *http.Request) { w.Write([]byte(Actor(r))) }
+	r.Route("/api", func(api chi.Router) {
+		api.Use(Middleware(r, requireToken))
+		api.Get("/health", ok)
+		api.Get("/resources/{kind}", ok)
+		api.Get("/resources/{kind}/{namespace}/{name}", ok)
+		api.Put("/resources/{kind}/{namespace}/{name}", ok)
+		api.Get("/pods/{namespace}/{name}/exec", ok)
+		api.Get("/changes", ok)
+		api.Get("/tokens", ok)
+		api.Post("/nodes/{name}/drain", ok)
+		api.Put("/watch-namespaces", ok)
+		api.Post("/portforwards", ok)
+		api.Post("/traffic/connect", ok)
+		api.Post("/changes/annotations", ok)
+	})
+	return r
+}
+
+func do(t *testing.T, h http.Handler, method, target, secret, remote string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, target, nil)
+	if secret != "" {
+		req.Header.Set("Authorization", "Bearer "+secret)
+	}
+	if remote != "" {
+		req.RemoteAddr = remote
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMiddlewareScopes(t *testing.T) {
+	router := testRouter(false)
+
+	_, readOnly, err := Create("ci-read", Scope{ReadOnly: true, Namespaces: []string{"payments"}}, time.Hour)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	_, changesOnly, err := Create("ci-changes", Scope{Endpoints: []string{"/api/changes*"}}, 0)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	_, deployer, err := Create("ci-deploy", Scope{Namespaces: []string{"payments"}}, 0)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, _, err := Create("ci-read", Scope{}, 0); err == nil {
+		t.Error("expected duplicate name to be rejected")
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		secret string
+		want   int
+	}{
+		{"no token is the browser session", "GET", "/api/resources/pods", "", 200},
+		{"unknown token", "GET", "/api/health", "radar_nope", 401},
+		{"namespace in path", "GET", "/api/resources/pods/payments/api-0", readOnly, 200},
+		{"namespace in query", "GET", "/api/resources/pods?namespace=payments", readOnly, 200},
+		{"other namespace", "GET", "/api/resources/pods/kube-system/etcd", readOnly, 403},
+		{"one namespace outside scope", "GET", "/api/resources/pods?namespace=payments,kube-system", readOnly, 403},
+		{"cluster-wide listing", "GET", "/api/resources/pods", readOnly, 403},
+		{"health needs no namespace", "GET", "/api/health", readOnly, 200},
+		{"read-only write", "PUT", "/api/resources/pods/payments/api-0", readOnly, 403},
+		{"read-only exec", "GET", "/api/pods/payments/api-0/exec", readOnly, 403},
+		{"endpoint prefix", "GET", "/api/changes?namespace=x", changesOnly, 200},
+		{"endpoint outside scope", "GET", "/api/resources/pods", changesOnly, 403},
+		{"tokens can't manage tokens", "GET", "/api/tokens", changesOnly, 403},
+		{"cluster-scoped route with a namespace", "POST", "/api/nodes/node-1/drain?namespace=payments", deployer, 403},
+		{"cluster-wide setting with a namespace", "PUT", "/api/watch-namespaces?namespace=payments", deployer, 403},
+		{"unscoped change with a namespace", "POST", "/api/traffic/connect?namespace=payments", deployer, 403},
+		{"body namespace checked by the handler", "POST", "/api/portforwards", deployer, 200},
+		{"change scoped by query", "POST", "/api/changes/annotations?namespace=payments", deployer, 200},
+		{"change scoped by query outside scope", "POST", "/api/changes/annotations?namespace=kube-system", deployer, 403},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := do(t, router, tt.method, tt.target, tt.secret, ""); rec.Code != tt.want {
+				t.Errorf("status = %d, want %d (%s)", rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+
+	if rec := do(t, router, "GET", "/api/changes", changesOnly, ""); rec.Body.String() != "token:ci-changes" {
+		t.Errorf("actor = %q, want token:ci-changes", rec.Body.String())
+	}
+}
+
+func TestMiddlewareRequireToken(t *testing.T) {
+	router := testRouter(true)
+	if rec := do(t, router, "GET", "/api/health", "", "127.0.0.1:51000"); rec.Code != 200 {
+		t.Errorf("loopback without token: status = %d, want 200", rec.Code)
+	}
+	if rec := do(t, router, "GET", "/api/health", "", "10.0.0.8:51000"); rec.Code != 401 {
+		t.Errorf("remote without token: status = %d, want 401", rec.Code)
+	}
+}
+
+func TestExpiredAndRevoked(t *testing.T) {
+	router := testRouter(false)
+	tok, secret, err := Create("short-lived", Scope{}, time.Hour)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	tokensMu.Lock()
+	for i := range tokens {
+		if tokens[i].ID == tok.ID {
+			past := time.Now().Add(-time.Minute)
+			tokens[i].ExpiresAt = &past
+		}
+	}
+	tokensMu.Unlock()
+	if rec := do(t, router, "GET", "/api/health", secret, ""); rec.Code != 401 {
+		t.Errorf("expired token: status = %d, want 401", rec.Code)
+	}
+
+	if _, ok, err := Revoke(tok.ID); err != nil || !ok {
+		t.Fatalf("Revoke = %v, %v", ok, err)
+	}
+	if _, err := Authenticate(secret); err == nil {
+		t.Error("revoked token still authenticates")
+	}
+	for _, listed := range List() {
+		if listed.ID == tok.ID {
+			t.Error("revoked token still listed")
+		}
+	}
+}
+
+func TestTokenUser(t *testing.T) {
+	if _, _, err := Create("no-user-name", Scope{User: &User{Groups: []string{"dev"}}}, 0); err == nil {
+		t.Error("expected a user without a name to be rejected")
+	}
+	_, bound, err := Create("bound", Scope{User: &User{Name: "alice@example.com", Groups: []string{"dev"}}}, 0)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	_, unbound, err := Create("unbound", Scope{}, 0)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	r := chi.NewRouter()
+	r.Route("/api", func(api chi.Router) {
+		api.Use(Middleware(r, false))
+		api.Get("/health", func(w http.ResponseWriter, r *http.Request) {
+			if u, ok := UserFromContext(r.Context()); ok {
+				w.Write([]byte(u.Name))
+			}
+		})
+	})
+	if rec := do(t, r, "GET", "/api/health", bound, ""); rec.Body.String() != "alice@example.com" {
+		t.Errorf("bound token user = %q, want alice@example.com", rec.Body.String())
+	}
+	if rec := do(t, r, "GET", "/api/health", unbound, ""); rec.Body.String() != "" {
+		t.Errorf("unbound token user = %q, want none", rec.Body.String())
+	}
+}
+
+func TestCheckNamespace(t *testing.T) {
+	if err := CheckNamespace(context.Background(), ""); err != nil {
+		t.Errorf("no token: %v", err)
+	}
+	ctx := context.WithValue(context.Background(), tokenKey{}, Token{Name: "ci", Scope: Scope{Namespaces: []string{"payments"}}})
+	if err := CheckNamespace(ctx, "payments"); err != nil {
+		t.Errorf("namespace in scope: %v", err)
+	}
+	for _, ns := range []string{"", "kube-system"} {
+		if err := CheckNamespace(ctx, ns); err == nil {
+			t.Errorf("CheckNamespace(%q) = nil, want an error", ns)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Authenticator establishes the user of requests that don't carry an API token, e.g.
+// from headers set by an authenticating reverse proxy
+type Authenticator interface {
+	// Authenticate returns the request's user. ok is false when the request carries none
+	// of the authenticator's credentials; an error means they were present but invalid.
+	Authenticate(r *http.Request) (u User, ok bool, err error)
+}
+
+// Default headers, as set by oauth2-proxy
+const (
+	DefaultProxyGroupsHeader = "X-Forwarded-Groups"
+	DefaultProxySecretHeader = "X-Radar-Proxy-Secret"
+)
+
+// ProxyConfig configures trusting user headers from an authenticating reverse proxy
+// (oauth2-proxy, Pomerium, ...). The proxy must prove itself with a shared secret, a
+// verified TLS client certificate, or both; otherwise anyone reaching Radar directly
+// could claim any user.
+type ProxyConfig struct {
+	UserHeader   string // e.g. X-Forwarded-User; empty disables proxy auth
+	GroupsHeader string // Comma-separated groups (default X-Forwarded-Groups)
+	SecretHeader string // Header carrying Secret (default X-Radar-Proxy-Secret)
+	Secret       string
+	// RequireClientCert only trusts requests whose TLS client certificate verified against
+	// the server's client CA (mTLS between the proxy and Radar)
+	RequireClientCert bool
+}
+
+// ProxyAuthenticator authenticates requests from user headers set by a trusted proxy
+type ProxyAuthenticator struct {
+	cfg ProxyConfig
+}
+
+// NewProxyAuthenticator returns an authenticator for cfg, or nil when proxy auth is off
+func NewProxyAuthenticator(cfg ProxyConfig) (*ProxyAuthenticator, error) {
+	if cfg.UserHeader == "" {
+		return nil, nil
+	}
+	if cfg.Secret == "" && !cfg.RequireClientCert {
+		return nil, fmt.Errorf("proxy auth needs a shared secret or a TLS client CA to verify the proxy")
+	}
+	if cfg.GroupsHeader == "" {
+		cfg.GroupsHeader = DefaultProxyGroupsHeader
+	}
+	if cfg.SecretHeader == "" {
+		cfg.SecretHeader = DefaultProxySecretHeader
+	}
+	return &ProxyAuthenticator{cfg: cfg}, nil
+}
+
+// Authenticate returns the user named by the proxy's headers once the request is shown
+// to come from the proxy
+func (p *ProxyAuthenticator) Authenticate(r *http.Request) (User, bool, error) {
+	name := strings.TrimSpace(r.Header.Get(p.cfg.UserHeader))
+	if name == "" {
+		return User{}, false, nil
+	}
+	if p.cfg.Secret != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get(p.cfg.SecretHeader)), []byte(p.cfg.Secret)) != 1 {
+		return User{}, false, fmt.Errorf("%s header from an untrusted proxy (%s is missing or wrong)", p.cfg.UserHeader, p.cfg.SecretHeader)
+	}
+	if p.cfg.RequireClientCert && (r.TLS == nil || len(r.TLS.VerifiedChains) == 0) {
+		return User{}, false, fmt.Errorf("%s header from an untrusted proxy (no verified client certificate)", p.cfg.UserHeader)
+	}
+
+	u := User{Name: name}
+	for _, header := range r.Header.Values(p.cfg.GroupsHeader) {
+		for _, g := range strings.Split(header, ",") {
+			if g = strings.TrimSpace(g); g != "" {
+				u.Groups = append(u.Groups, g)
+			}
+		}
+	}
+	return u, true, nil
+}
@@ -0,0 +1,177 @@
+package auth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func proxyRouter(t *testing.T, requireToken bool, cfg ProxyConfig) *chi.Mux {
+	t.Helper()
+	proxy, err := NewProxyAuthenticator(cfg)
+	if err != nil {
+		t.Fatalf("NewProxyAuthenticator: %v", err)
+	}
+	r := chi.NewRouter()
+	r.Route("/api", func(api chi.Router) {
+		api.Use(Middleware(r, requireToken, proxy))
+		api.Get("/health", func(w http.ResponseWriter, r *http.Request) {
+			u, _ := UserFromContext(r.Context())
+			w.Header().Set("X-Groups", strings.Join(u.Groups, "|"))
+			w.Write([]byte(Actor(r)))
+		})
+	})
+	return r
+}
+
+func proxyRequest(headers map[string]string) *http.Request {
+	req := httptest.NewRequest("GET", "/api/health", nil)
+	req.RemoteAddr = "10.0.0.8:51000"
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	return req
+}
+
+func TestNewProxyAuthenticator(t *testing.T) {
+	if p, err := NewProxyAuthenticator(ProxyConfig{}); p != nil || err != nil {
+		t.Errorf("no user header = %v, %v, want disabled", p, err)
+	}
+	if _, err := NewProxyAuthenticator(ProxyConfig{UserHeader: "X-Forwarded-User"}); err == nil {
+		t.Error("expected a proxy without a secret or client CA to be rejected")
+	}
+}
+
+func TestProxyAuthSecret(t *testing.T) {
+	router := proxyRouter(t, true, ProxyConfig{UserHeader: "X-Forwarded-User", Secret: "s3cret"})
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    int
+		actor   string
+		groups  string
+	}{
+		{"trusted proxy", map[string]string{"X-Forwarded-User": "alice", "X-Forwarded-Groups": "dev, ops,", DefaultProxySecretHeader: "s3cret"}, 200, "user:alice", "dev|ops"},
+		{"wrong secret", map[string]string{"X-Forwarded-User": "alice", DefaultProxySecretHeader: "guess"}, 401, "", ""},
+		{"missing secret", map[string]string{"X-Forwarded-User": "alice"}, 401, "", ""},
+		{"no user header falls back to require-token", map[string]string{DefaultProxySecretHeader: "s3cret"}, 401, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, proxyRequest(tt.headers))
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d (%s)", rec.Code, tt.want, rec.Body.String())
+			}
+			if tt.want != 200 {
+				return
+			}
+			if rec.Body.String() != tt.actor || rec.Header().Get("X-Groups") != tt.groups {
+				t.Errorf("actor = %q groups = %q, want %q %q", rec.Body.String(), rec.Header().Get("X-Groups"), tt.actor, tt.groups)
+			}
+		})
+	}
+}
+
+func TestProxyAuthWithoutRequireToken(t *testing.T) {
+	router := proxyRouter(t, false, ProxyConfig{UserHeader: "X-Forwarded-User", Secret: "s3cret"})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, proxyRequest(nil))
+	if rec.Code != 401 {
+		t.Errorf("direct request without a user: status = %d, want 401", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, proxyRequest(map[string]string{"X-Forwarded-User": "alice", DefaultProxySecretHeader: "s3cret"}))
+	if rec.Code != 200 || rec.Body.String() != "user:alice" {
+		t.Errorf("proxied request: status = %d actor = %q, want user:alice", rec.Code, rec.Body.String())
+	}
+
+	req := proxyRequest(nil)
+	req.RemoteAddr = "127.0.0.1:51000"
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != 200 {
+		t.Errorf("loopback request: status = %d, want 200", rec.Code)
+	}
+}
+
+func TestProxyAuthTokenTakesPrecedence(t *testing.T) {
+	router := proxyRouter(t, false, ProxyConfig{UserHeader: "X-Forwarded-User", Secret: "s3cret"})
+	_, secret, err := Create("proxy-precedence", Scope{}, 0)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	req := proxyRequest(map[string]string{"X-Forwarded-User": "mallory", "Authorization": "Bearer " + secret})
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != 200 || rec.Body.String() != "token:proxy-precedence" {
+		t.Errorf("status = %d actor = %q, want the token's", rec.Code, rec.Body.String())
+	}
+}
+
+func TestProxyAuthClientCert(t *testing.T) {
+	p, err := NewProxyAuthenticator(ProxyConfig{UserHeader: "X-Forwarded-User", RequireClientCert: true})
+	if err != nil {
+		t.Fatalf("NewProxyAuthenticator: %v", err)
+	}
+
+	req := proxyRequest(map[string]string{"X-Forwarded-User": "alice"})
+	if _, _, err := p.Authenticate(req); err == nil {
+		t.Error("expected a plain HTTP request to be rejected")
+	}
+
+	req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{}}}}
+	u, ok, err := p.Authenticate(req)
+	if err != nil || !ok || !reflect.DeepEqual(u, User{Name: "alice"}) {
+		t.Errorf("Authenticate = %+v, %v, %v, want alice", u, ok, err)
+	}
+}
+
+func TestProxyUserTokensAreBound(t *testing.T) {
+	proxy, err := NewProxyAuthenticator(ProxyConfig{UserHeader: "X-Forwarded-User", Secret: "s3cret"})
+	if err != nil {
+		t.Fatalf("NewProxyAuthenticator: %v", err)
+	}
+	r := chi.NewRouter()
+	r.Route("/api", func(api chi.Router) {
+		api.Use(Middleware(r, false, proxy))
+		NewHandlers().RegisterRoutes(api)
+	})
+	other, _, err := Create("proxy-other", Scope{}, 0)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	req := proxyRequest(map[string]string{"X-Forwarded-User": "alice", DefaultProxySecretHeader: "s3cret"})
+	req.Method, req.URL.Path = "POST", "/api/tokens"
+	req.Body = io.NopCloser(strings.NewReader(`{"name":"proxy-alice","scope":{"readOnly":true}}`))
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	var created CreateTokenResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil || rec.Code != 200 {
+		t.Fatalf("create: status = %d body = %s", rec.Code, rec.Body.String())
+	}
+	if u := created.Scope.User; u == nil || u.Name != "alice" {
+		t.Errorf("token user = %+v, want alice", u)
+	}
+
+	req = proxyRequest(map[string]string{"X-Forwarded-User": "alice", DefaultProxySecretHeader: "s3cret"})
+	req.Method, req.URL.Path = "DELETE", "/api/tokens/"+other.ID
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != 404 {
+		t.Errorf("revoking another user's token: status = %d, want 404", rec.Code)
+	}
+}
@@ -0,0 +1,233 @@
+// Package auth manages scoped API tokens, so scripts and CI jobs can call Radar's API
+// without borrowing a person's browser session. Tokens are stored hashed in the settings
+// file; the secret is only returned once, when the token is created.
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/skyhook-io/radar/internal/settings"
+)
+
+const (
+	settingsSection = "apiTokens"
+	// secretPrefix marks Radar tokens so they are easy to spot in logs and secret scanners
+	secretPrefix = "radar_"
+	// displayPrefixLen is how much of the secret is kept to identify a token in listings
+	displayPrefixLen = len(secretPrefix) + 6
+	// maxTokenTTL caps how long a token can live
+	maxTokenTTL = 366 * 24 * time.Hour
+)
+
+// Scope limits what a token can do. The zero value allows every endpoint except token
+// management.
+type Scope struct {
+	ReadOnly bool `json:"readOnly,omitempty"` // GET only, and no exec or shell sessions
+	// Namespaces the token may read or act in (empty = all). Requests must name a namespace,
+	// by path or ?namespace=, so cluster-wide listings are rejected.
+	Namespaces []string `json:"namespaces,omitempty"`
+	// Endpoints are API paths the token may call (empty = all). A trailing * matches any
+	// suffix ("/api/changes*"); other entries are path.Match globs ("/api/resources/*").
+	Endpoints []string `json:"endpoints,omitempty"`
+	// User binds the token to a Kubernetes identity, whose RBAC then limits what the token
+	// can see and do on top of the rest of the scope
+	User *User `json:"user,omitempty"`
+}
+
+// Token is an API token as shown to users (never includes the secret)
+type Token struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"` // First characters of the secret, to tell tokens apart
+	Scope      Scope      `json:"scope"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"` // Not persisted; resets on restart
+}
+
+// Expired reports whether the token can no longer be used
+func (t *Token) Expired(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Before(*t.ExpiresAt)
+}
+
+// storedToken is a token as persisted in settings
+type storedToken struct {
+	Token
+	Hash string `json:"hash"` // SHA-256 of the secret, hex encoded
+}
+
+var (
+	tokens   []storedToken
+	tokensMu sync.Mutex
+	loadOnce sync.Once
+)
+
+// load reads tokens from the settings store on first use
+func load() {
+	loadOnce.Do(func() {
+		var stored []storedToken
+		if _, err := settings.Get().Load(settingsSection, &stored); err == nil {
+			tokensMu.Lock()
+			tokens = stored
+			tokensMu.Unlock()
+		}
+	})
+}
+
+// saveLocked persists tokens; the caller holds tokensMu
+func saveLocked() error {
+	stored := make([]storedToken, len(tokens))
+	for i, t := range tokens {
+		stored[i] = t
+		stored[i].LastUsedAt = nil
+	}
+	if err := settings.Get().Save(settingsSection, stored); err != nil {
+		return fmt.Errorf("failed to save API tokens: %w", err)
+	}
+	return nil
+}
+
+// List returns all tokens, including expired ones, oldest first
+func List() []Token {
+	load()
+	tokensMu.Lock()
+	defer tokensMu.Unlock()
+	list := make([]Token, len(tokens))
+	for i, t := range tokens {
+		list[i] = t.Token
+	}
+	return list
+}
+
+// Create issues a new token and returns it with its secret. ttl 0 means no expiry.
+func Create(name string, scope Scope, ttl time.Duration) (Token, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return Token{}, "", fmt.Errorf("name is required")
+	}
+	if ttl < 0 || ttl > maxTokenTTL {
+		return Token{}, "", fmt.Errorf("ttl must be between 0 and %s", maxTokenTTL)
+	}
+	if err := ValidateScope(scope); err != nil {
+		return Token{}, "", err
+	}
+
+	secret := secretPrefix + randomHex(24)
+	now := time.Now().UTC()
+	t := storedToken{
+		Token: Token{
+			ID:        randomHex(8),
+			Name:      name,
+			Prefix:    secret[:displayPrefixLen],
+			Scope:     scope,
+			CreatedAt: now,
+		},
+		Hash: hashSecret(secret),
+	}
+	if ttl > 0 {
+		expires := now.Add(ttl)
+		t.ExpiresAt = &expires
+	}
+
+	load()
+	tokensMu.Lock()
+	defer tokensMu.Unlock()
+	for _, existing := range tokens {
+		if existing.Name == name {
+			return Token{}, "", fmt.Errorf("a token named %q already exists", name)
+		}
+	}
+	tokens = append(tokens, t)
+	if err := saveLocked(); err != nil {
+		tokens = tokens[:len(tokens)-1]
+		return Token{}, "", err
+	}
+	return t.Token, secret, nil
+}
+
+// Revoke deletes a token. Returns the revoked token, or false if it didn't exist.
+func Revoke(id string) (Token, bool, error) {
+	load()
+	tokensMu.Lock()
+	defer tokensMu.Unlock()
+	for i, t := range tokens {
+		if t.ID != id {
+			continue
+		}
+		prev := tokens
+		tokens = append(append([]storedToken{}, tokens[:i]...), tokens[i+1:]...)
+		if err := saveLocked(); err != nil {
+			tokens = prev
+			return Token{}, false, err
+		}
+		return t.Token, true, nil
+	}
+	return Token{}, false, nil
+}
+
+// Authenticate resolves a secret to its token and records the use
+func Authenticate(secret string) (Token, error) {
+	if !strings.HasPrefix(secret, secretPrefix) {
+		return Token{}, fmt.Errorf("malformed API token")
+	}
+	hash := hashSecret(secret)
+	now := time.Now().UTC()
+
+	load()
+	tokensMu.Lock()
+	defer tokensMu.Unlock()
+	for i := range tokens {
+		t := &tokens[i]
+		if subtle.ConstantTimeCompare([]byte(t.Hash), []byte(hash)) != 1 {
+			continue
+		}
+		if t.Expired(now) {
+			return Token{}, fmt.Errorf("API token %q expired at %s", t.Name, t.ExpiresAt.Format(time.RFC3339))
+		}
+		t.LastUsedAt = &now
+		return t.Token, nil
+	}
+	return Token{}, fmt.Errorf("unknown API token")
+}
+
+// ValidateScope checks namespace names, endpoint patterns and the bound user
+func ValidateScope(s Scope) error {
+	for _, ns := range s.Namespaces {
+		if strings.TrimSpace(ns) == "" {
+			return fmt.Errorf("scope.namespaces: empty namespace")
+		}
+	}
+	for _, e := range s.Endpoints {
+		if !strings.HasPrefix(e, "/api/") {
+			return fmt.Errorf("scope.endpoints: %q must start with /api/", e)
+		}
+		if _, err := path.Match(strings.TrimSuffix(e, "*"), "/api/"); err != nil {
+			return fmt.Errorf("scope.endpoints: invalid pattern %q: %w", e, err)
+		}
+	}
+	if s.User != nil && strings.TrimSpace(s.User.Name) == "" {
+		return fmt.Errorf("scope.user.name is required")
+	}
+	return nil
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("crypto/rand failed: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
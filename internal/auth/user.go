@@ -0,0 +1,25 @@
+package auth
+
+import "context"
+
+// User is the Kubernetes identity a request acts for. When a request carries one, Radar
+// evaluates that user's RBAC (via SubjectAccessReview) to decide what the request may see
+// and do, instead of assuming its own service account's permissions. A user can only
+// narrow access: requests still run with Radar's credentials.
+type User struct {
+	Name   string   `json:"name"`
+	Groups []string `json:"groups,omitempty"`
+}
+
+type userKey struct{}
+
+// WithUser returns a context carrying the user a request acts for
+func WithUser(ctx context.Context, u User) context.Context {
+	return context.WithValue(ctx, userKey{}, u)
+}
+
+// UserFromContext returns the user a request acts for, if one was established
+func UserFromContext(ctx context.Context) (User, bool) {
+	u, ok := ctx.Value(userKey{}).(User)
+	return u, ok && u.Name != ""
+}
@@ -0,0 +1,371 @@
+// Package config loads the optional radar.yaml server configuration file.
+//
+// Precedence (lowest to highest): built-in flag defaults, config file, selected profile,
+// RADAR_* environment variables, explicitly passed command-line flags.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/skyhook-io/radar/internal/health"
+	"github.com/skyhook-io/radar/internal/notifications"
+)
+
+// Config is the root of the server configuration file
+type Config struct {
+	Server        ServerConfig        `json:"server"`
+	Kubernetes    KubernetesConfig    `json:"kubernetes"`
+	Timeline      TimelineConfig      `json:"timeline"`
+	Metrics       MetricsConfig       `json:"metrics"`
+	Features      FeaturesConfig      `json:"features"`
+	Notifications NotificationsConfig `json:"notifications"`
+	Health        HealthConfig        `json:"health"`
+	Artifacts     ArtifactsConfig     `json:"artifacts"`
+
+	// Profiles are named partial configs layered on top of the base config
+	// (selected with --profile or RADAR_PROFILE)
+	Profiles map[string]json.RawMessage `json:"profiles,omitempty"`
+}
+
+// ServerConfig holds HTTP server settings
+type ServerConfig struct {
+	Port      *int  `json:"port,omitempty"`
+	NoBrowser *bool `json:"noBrowser,omitempty"`
+	Dev       *bool `json:"dev,omitempty"`
+	// RequireAPIToken rejects API requests without a token unless they come from loopback
+	RequireAPIToken *bool                `json:"requireApiToken,omitempty"`
+	ProxyAuth       ProxyAuthConfig      `json:"proxyAuth"`
+	TLS             TLSConfig            `json:"tls"`
+	PublicSnapshot  PublicSnapshotConfig `json:"publicSnapshot"`
+	// ShutdownTimeout bounds draining requests and flushing history on exit (Go duration)
+	ShutdownTimeout string `json:"shutdownTimeout,omitempty"`
+}
+
+// ProxyAuthConfig trusts user headers from an authenticating reverse proxy
+type ProxyAuthConfig struct {
+	UserHeader   string `json:"userHeader,omitempty"` // e.g. X-Forwarded-User; empty disables
+	GroupsHeader string `json:"groupsHeader,omitempty"`
+	SecretHeader string `json:"secretHeader,omitempty"`
+	Secret       string `json:"secret,omitempty"` // Prefer RADAR_AUTH_PROXY_SECRET
+}
+
+// TLSConfig serves HTTPS; a client CA enables mTLS for the proxy
+type TLSConfig struct {
+	Cert     string `json:"cert,omitempty"`
+	Key      string `json:"key,omitempty"`
+	ClientCA string `json:"clientCA,omitempty"`
+}
+
+// PublicSnapshotConfig holds settings for the sanitized wallboard snapshot
+type PublicSnapshotConfig struct {
+	Serve      *bool    `json:"serve,omitempty"`    // Serve at /public/snapshot.json
+	File       string   `json:"file,omitempty"`     // Write to this file
+	Interval   string   `json:"interval,omitempty"` // Go duration
+	Namespaces []string `json:"namespaces,omitempty"`
+	HideNames  *bool    `json:"hideNames,omitempty"`
+}
+
+// KubernetesConfig holds cluster connection and scope settings
+type KubernetesConfig struct {
+	Kubeconfig     string   `json:"kubeconfig,omitempty"` // File, directory or path list like KUBECONFIG
+	KubeconfigDirs []string `json:"kubeconfigDirs,omitempty"`
+	Namespace      string   `json:"namespace,omitempty"` // Initial namespace filter (empty = all)
+	Secrets        string   `json:"secrets,omitempty"`   // auto, full, metadata or off
+	WatchList      string   `json:"watchList,omitempty"` // auto, on or off
+	// WatchNamespaces restricts the informers to these namespaces (empty = whole cluster)
+	WatchNamespaces []string `json:"watchNamespaces,omitempty"`
+	// Impersonate runs changes made for a request's user (edits, deletes, exec, Helm) as
+	// that user instead of Radar's service account
+	Impersonate *bool `json:"impersonate,omitempty"`
+}
+
+// TimelineConfig holds timeline storage settings
+type TimelineConfig struct {
+	Storage      string `json:"storage,omitempty"` // memory, sqlite or postgres
+	DBPath       string `json:"dbPath,omitempty"`
+	HistoryLimit *int   `json:"historyLimit,omitempty"`
+	Retention    string `json:"retention,omitempty"` // Go duration; sqlite and postgres
+	// Postgres only
+	DSN      string `json:"dsn,omitempty"`
+	MaxConns *int   `json:"maxConns,omitempty"`
+	// SQLite only
+	MaxRowsPerKind  *int   `json:"maxRowsPerKind,omitempty"`
+	MaxDBSizeMB     *int   `json:"maxDBSizeMB,omitempty"`
+	DownsampleAfter string `json:"downsampleAfter,omitempty"` // Go duration
+	// DiffRules are the field paths summarized for custom resource updates, by kind
+	// (e.g. Certificate: [".spec.dnsNames", ".status.conditions[Ready]"])
+	DiffRules map[string][]string `json:"diffRules,omitempty"`
+}
+
+// MetricsConfig holds pod and node metrics history storage settings
+type MetricsConfig struct {
+	Storage string `json:"storage,omitempty"` // memory or sqlite
+	DBPath  string `json:"dbPath,omitempty"`
+	// How long each resolution is kept with sqlite storage (Go durations)
+	RetentionRaw string `json:"retentionRaw,omitempty"`
+	Retention5m  string `json:"retention5m,omitempty"`
+	Retention1h  string `json:"retention1h,omitempty"`
+}
+
+// FeaturesConfig holds feature gates and background job settings
+type FeaturesConfig struct {
+	DebugEvents     *bool           `json:"debugEvents,omitempty"`
+	HygieneInterval string          `json:"hygieneInterval,omitempty"` // Go duration
+	CostPricing     string          `json:"costPricing,omitempty"`     // Pricing table file or http(s) URL
+	NodeShell       NodeShellConfig `json:"nodeShell"`
+	// TrafficMetrics annotates traffic view edges with rates from Prometheus
+	TrafficMetrics TrafficMetricsConfig `json:"trafficMetrics"`
+	// PortForwardProfiles are saved port-forward profiles started at launch
+	PortForwardProfiles []string `json:"portForwardProfiles,omitempty"`
+	// ExecAudit records terminal sessions for security review
+	ExecAudit ExecAuditConfig `json:"execAudit"`
+	// DebugImages are offered for ephemeral debug containers; the first is the default
+	DebugImages []string `json:"debugImages,omitempty"`
+	// FileTransferMaxMB caps pod file downloads and uploads (0 = unlimited)
+	FileTransferMaxMB *int `json:"fileTransferMaxMB,omitempty"`
+	// ScaleHPAPolicy is warn or refuse: what scaling a workload an HPA manages does
+	ScaleHPAPolicy string `json:"scaleHpaPolicy,omitempty"`
+	// ImageInspection looks up running images' digests, build times and vulnerabilities
+	ImageInspection ImageInspectionConfig `json:"imageInspection"`
+}
+
+// ExecAuditConfig holds terminal session recording settings
+type ExecAuditConfig struct {
+	Sinks       []string `json:"sinks,omitempty"`       // file:<dir>, sqlite:<path>, webhook:<url> or an object store URL
+	RecordInput *bool    `json:"recordInput,omitempty"` // Also record keystrokes
+}
+
+// NodeShellConfig holds node shell settings
+type NodeShellConfig struct {
+	Enabled   *bool  `json:"enabled,omitempty"`
+	Image     string `json:"image,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// ImageInspectionConfig holds registry image inspection settings
+type ImageInspectionConfig struct {
+	Enabled *bool `json:"enabled,omitempty"`
+	// PullSecrets (namespace/name) log in to registries, besides the pods' own imagePullSecrets
+	PullSecrets     []string `json:"pullSecrets,omitempty"`
+	Vulnerabilities string   `json:"vulnerabilities,omitempty"` // harbor or trivy (empty = off)
+	Interval        string   `json:"interval,omitempty"`        // Go duration
+}
+
+// TrafficMetricsConfig holds Prometheus traffic metrics settings
+type TrafficMetricsConfig struct {
+	Enabled       *bool  `json:"enabled,omitempty"`
+	PrometheusURL string `json:"prometheusUrl,omitempty"` // Empty = discover in the cluster
+}
+
+// NotificationsConfig holds notification channels, lifecycle triggers and health alert rules, inline or from a separate file
+type NotificationsConfig struct {
+	ConfigFile string                        `json:"configFile,omitempty"`
+	Channels   []notifications.ChannelConfig `json:"channels,omitempty"`
+	Triggers   []notifications.TriggerConfig `json:"triggers,omitempty"`
+	Rules      []notifications.RuleConfig    `json:"rules,omitempty"`
+}
+
+// HealthConfig holds custom resource health rules
+type HealthConfig struct {
+	// Rules assess custom resources' health by GroupKind, replacing the built-in rule
+	// for the same GroupKind
+	Rules []health.Rule `json:"rules,omitempty"`
+}
+
+// ArtifactsConfig holds where exported artifacts (timeline exports, replay bundles) are written
+type ArtifactsConfig struct {
+	// Store is an s3://, gs://, azblob:// or file:// URL; credentials come from the environment
+	Store string `json:"store,omitempty"`
+}
+
+// Load reads a config file, applies the profile (if non-empty) and environment overrides
+func Load(path, profile string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	cfg, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if profile == "" {
+		profile = os.Getenv("RADAR_PROFILE")
+	}
+	if err := cfg.ApplyProfile(profile); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := cfg.ApplyEnv(os.LookupEnv); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// LoadOptional is like Load but returns an env-only config when path is empty
+func LoadOptional(path, profile string) (*Config, error) {
+	if path == "" {
+		path = os.Getenv("RADAR_CONFIG")
+	}
+	if path != "" {
+		return Load(expandHome(path), profile)
+	}
+	cfg := &Config{}
+	if profile != "" || os.Getenv("RADAR_PROFILE") != "" {
+		return nil, fmt.Errorf("--profile requires a config file (--config or RADAR_CONFIG)")
+	}
+	if err := cfg.ApplyEnv(os.LookupEnv); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Parse decodes YAML (or JSON) config, rejecting unknown fields so typos surface early
+func Parse(data []byte) (*Config, error) {
+	var cfg Config
+	if err := yaml.UnmarshalStrict(data, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// ApplyProfile overlays the named profile on the config. Fields set in the profile
+// replace base values; maps and nested sections merge.
+func (c *Config) ApplyProfile(name string) error {
+	if name == "" {
+		return nil
+	}
+	raw, ok := c.Profiles[name]
+	if !ok {
+		return fmt.Errorf("profile %q not found (available: %s)", name, strings.Join(c.ProfileNames(), ", "))
+	}
+	var overlay Config
+	if err := yaml.UnmarshalStrict(raw, &overlay); err != nil {
+		return fmt.Errorf("profile %q: %w", name, err)
+	}
+	if len(overlay.Profiles) > 0 {
+		return fmt.Errorf("profile %q: profiles cannot be nested", name)
+	}
+	// Decoding onto the existing struct only touches fields present in the profile
+	if err := json.Unmarshal(raw, c); err != nil {
+		return fmt.Errorf("profile %q: %w", name, err)
+	}
+	return nil
+}
+
+// clone returns a deep copy so profiles can be applied without touching the original
+func (c *Config) clone() (*Config, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	var out Config
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ProfileNames returns the defined profile names, sorted
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Flags returns config values as command-line flag values, keyed by flag name.
+// Only values present in the config are included.
+func (c *Config) Flags() map[string]string {
+	flags := make(map[string]string)
+	setInt := func(name string, v *int) {
+		if v != nil {
+			flags[name] = fmt.Sprintf("%d", *v)
+		}
+	}
+	setBool := func(name string, v *bool) {
+		if v != nil {
+			flags[name] = fmt.Sprintf("%t", *v)
+		}
+	}
+	setString := func(name, v string) {
+		if v != "" {
+			flags[name] = v
+		}
+	}
+
+	setInt("port", c.Server.Port)
+	setBool("no-browser", c.Server.NoBrowser)
+	setBool("dev", c.Server.Dev)
+	setBool("require-api-token", c.Server.RequireAPIToken)
+	setString("auth-proxy-user-header", c.Server.ProxyAuth.UserHeader)
+	setString("auth-proxy-groups-header", c.Server.ProxyAuth.GroupsHeader)
+	setString("auth-proxy-secret-header", c.Server.ProxyAuth.SecretHeader)
+	setString("auth-proxy-secret", c.Server.ProxyAuth.Secret)
+	setString("tls-cert", expandHome(c.Server.TLS.Cert))
+	setString("tls-key", expandHome(c.Server.TLS.Key))
+	setString("tls-client-ca", expandHome(c.Server.TLS.ClientCA))
+	setBool("public-snapshot", c.Server.PublicSnapshot.Serve)
+	setString("public-snapshot-file", expandHome(c.Server.PublicSnapshot.File))
+	setString("public-snapshot-interval", c.Server.PublicSnapshot.Interval)
+	setString("public-snapshot-namespaces", strings.Join(c.Server.PublicSnapshot.Namespaces, ","))
+	setBool("public-snapshot-hide-names", c.Server.PublicSnapshot.HideNames)
+	setString("shutdown-timeout", c.Server.ShutdownTimeout)
+
+	setString("kubeconfig", expandPathList(c.Kubernetes.Kubeconfig))
+	dirs := make([]string, len(c.Kubernetes.KubeconfigDirs))
+	for i, dir := range c.Kubernetes.KubeconfigDirs {
+		dirs[i] = expandHome(dir)
+	}
+	setString("kubeconfig-dir", strings.Join(dirs, ","))
+	setString("namespace", c.Kubernetes.Namespace)
+	setString("secrets", c.Kubernetes.Secrets)
+	setString("watch-list", c.Kubernetes.WatchList)
+	setString("namespaces", strings.Join(c.Kubernetes.WatchNamespaces, ","))
+	setBool("impersonate", c.Kubernetes.Impersonate)
+
+	setString("timeline-storage", c.Timeline.Storage)
+	setString("timeline-db", expandHome(c.Timeline.DBPath))
+	setInt("history-limit", c.Timeline.HistoryLimit)
+	setString("timeline-dsn", c.Timeline.DSN)
+	setInt("timeline-max-conns", c.Timeline.MaxConns)
+	setString("timeline-retention", c.Timeline.Retention)
+	setInt("timeline-max-rows-per-kind", c.Timeline.MaxRowsPerKind)
+	setInt("timeline-max-db-size-mb", c.Timeline.MaxDBSizeMB)
+	setString("timeline-downsample-after", c.Timeline.DownsampleAfter)
+
+	setString("metrics-storage", c.Metrics.Storage)
+	setString("metrics-db", expandHome(c.Metrics.DBPath))
+	setString("metrics-retention-raw", c.Metrics.RetentionRaw)
+	setString("metrics-retention-5m", c.Metrics.Retention5m)
+	setString("metrics-retention-1h", c.Metrics.Retention1h)
+
+	setBool("debug-events", c.Features.DebugEvents)
+	setString("hygiene-interval", c.Features.HygieneInterval)
+	setString("cost-pricing", expandHome(c.Features.CostPricing))
+	setBool("enable-node-shell", c.Features.NodeShell.Enabled)
+	setString("node-shell-image", c.Features.NodeShell.Image)
+	setString("node-shell-namespace", c.Features.NodeShell.Namespace)
+	setString("port-forward-profiles", strings.Join(c.Features.PortForwardProfiles, ","))
+	setBool("traffic-metrics", c.Features.TrafficMetrics.Enabled)
+	setString("prometheus-url", c.Features.TrafficMetrics.PrometheusURL)
+	setString("exec-audit", strings.Join(c.Features.ExecAudit.Sinks, ","))
+	setBool("exec-audit-input", c.Features.ExecAudit.RecordInput)
+	setInt("file-transfer-max-mb", c.Features.FileTransferMaxMB)
+	setString("scale-hpa-policy", c.Features.ScaleHPAPolicy)
+	setString("debug-images", strings.Join(c.Features.DebugImages, ","))
+	setBool("image-inspection", c.Features.ImageInspection.Enabled)
+	setString("image-pull-secrets", strings.Join(c.Features.ImageInspection.PullSecrets, ","))
+	setString("image-vulnerabilities", c.Features.ImageInspection.Vulnerabilities)
+	setString("image-inspection-interval", c.Features.ImageInspection.Interval)
+
+	setString("notifications-config", expandHome(c.Notifications.ConfigFile))
+	setString("artifact-store", c.Artifacts.Store)
+	return flags
+}
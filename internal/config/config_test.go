@@ -0,0 +1,138 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const testConfig = `
+server:
+  port: 9300
+timeline:
+  storage: sqlite
+  historyLimit: 5000
+profiles:
+  staging:
+    server:
+      port: 9400
+    kubernetes:
+      namespace: staging
+`
+
+func TestApplyProfile_OverlaysOnlySetFields(t *testing.T) {
+	cfg, err := Parse([]byte(testConfig))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if err := cfg.ApplyProfile("staging"); err != nil {
+		t.Fatalf("ApplyProfile: %v", err)
+	}
+
+	flags := cfg.Flags()
+	if flags["port"] != "9400" {
+		t.Errorf("port = %q, want 9400", flags["port"])
+	}
+	if flags["namespace"] != "staging" {
+		t.Errorf("namespace = %q, want staging", flags["namespace"])
+	}
+	if flags["timeline-storage"] != "sqlite" || flags["history-limit"] != "5000" {
+		t.Errorf("base timeline settings lost: %v", flags)
+	}
+}
+
+func TestApplyProfile_Unknown(t *testing.T) {
+	cfg, _ := Parse([]byte(testConfig))
+	err := cfg.ApplyProfile("prod")
+	if err == nil || !strings.Contains(err.Error(), "staging") {
+		t.Errorf("expected error listing available profiles, got %v", err)
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	cfg, _ := Parse([]byte(testConfig))
+	env := map[string]string{"RADAR_PORT": "9500", "RADAR_DEBUG_EVENTS": "true"}
+	if err := cfg.ApplyEnv(func(k string) (string, bool) { v, ok := env[k]; return v, ok }); err != nil {
+		t.Fatalf("ApplyEnv: %v", err)
+	}
+	flags := cfg.Flags()
+	if flags["port"] != "9500" || flags["debug-events"] != "true" {
+		t.Errorf("env overrides not applied: %v", flags)
+	}
+
+	bad := map[string]string{"RADAR_PORT": "high"}
+	if err := cfg.ApplyEnv(func(k string) (string, bool) { v, ok := bad[k]; return v, ok }); err == nil {
+		t.Error("expected error for non-numeric RADAR_PORT")
+	}
+}
+
+func TestParse_RejectsUnknownFields(t *testing.T) {
+	if _, err := Parse([]byte("server:\n  prot: 1\n")); err == nil {
+		t.Error("expected unknown field error")
+	}
+}
+
+func TestValidate_CollectsAllProblems(t *testing.T) {
+	cfg, err := Parse([]byte(`
+server:
+  port: 0
+timeline:
+  storage: mysql
+profiles:
+  broken:
+    features:
+      hygieneInterval: soon
+`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	var verr *ValidationError
+	if err := cfg.Validate(); !errors.As(err, &verr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+	if len(verr.Problems) != 3 {
+		t.Errorf("expected 3 problems (port, storage, profile), got %v", verr.Problems)
+	}
+}
+
+func TestValidate_DiffRules(t *testing.T) {
+	cfg, err := Parse([]byte(`
+timeline:
+  diffRules:
+    Certificate: [".spec.dnsNames", ".status.conditions[Ready]"]
+    VirtualService: [".spec.http[0"]
+`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	var verr *ValidationError
+	if err := cfg.Validate(); !errors.As(err, &verr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+	if len(verr.Problems) != 1 || !strings.Contains(verr.Problems[0], "VirtualService") {
+		t.Errorf("expected one VirtualService problem, got %v", verr.Problems)
+	}
+}
+
+func TestValidate_HealthRules(t *testing.T) {
+	cfg, err := Parse([]byte(`
+health:
+  rules:
+    - group: example.com
+      kind: Widget
+      conditions:
+        - {type: Synced, status: "True", health: healthy}
+    - kind: Gadget
+      cel: object.status.ready +
+`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	var verr *ValidationError
+	if err := cfg.Validate(); !errors.As(err, &verr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+	if len(verr.Problems) != 1 || !strings.Contains(verr.Problems[0], "Gadget") {
+		t.Errorf("expected one Gadget problem, got %v", verr.Problems)
+	}
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// envOverride maps a RADAR_* environment variable onto a config field
+type envOverride struct {
+	name  string
+	apply func(c *Config, value string) error
+}
+
+// envOverrides lists supported environment variables
+var envOverrides = []envOverride{
+	{"RADAR_PORT", func(c *Config, v string) error { return parseIntInto(&c.Server.Port, v) }},
+	{"RADAR_NO_BROWSER", func(c *Config, v string) error { return parseBoolInto(&c.Server.NoBrowser, v) }},
+	{"RADAR_REQUIRE_API_TOKEN", func(c *Config, v string) error { return parseBoolInto(&c.Server.RequireAPIToken, v) }},
+	{"RADAR_AUTH_PROXY_USER_HEADER", func(c *Config, v string) error { c.Server.ProxyAuth.UserHeader = v; return nil }},
+	{"RADAR_AUTH_PROXY_GROUPS_HEADER", func(c *Config, v string) error { c.Server.ProxyAuth.GroupsHeader = v; return nil }},
+	{"RADAR_AUTH_PROXY_SECRET", func(c *Config, v string) error { c.Server.ProxyAuth.Secret = v; return nil }},
+	{"RADAR_TLS_CERT", func(c *Config, v string) error { c.Server.TLS.Cert = v; return nil }},
+	{"RADAR_TLS_KEY", func(c *Config, v string) error { c.Server.TLS.Key = v; return nil }},
+	{"RADAR_TLS_CLIENT_CA", func(c *Config, v string) error { c.Server.TLS.ClientCA = v; return nil }},
+	{"RADAR_PUBLIC_SNAPSHOT", func(c *Config, v string) error { return parseBoolInto(&c.Server.PublicSnapshot.Serve, v) }},
+	{"RADAR_PUBLIC_SNAPSHOT_FILE", func(c *Config, v string) error { c.Server.PublicSnapshot.File = v; return nil }},
+	{"RADAR_SHUTDOWN_TIMEOUT", func(c *Config, v string) error { c.Server.ShutdownTimeout = v; return nil }},
+	{"RADAR_KUBECONFIG", func(c *Config, v string) error { c.Kubernetes.Kubeconfig = v; return nil }},
+	{"RADAR_KUBECONFIG_DIRS", func(c *Config, v string) error {
+		c.Kubernetes.KubeconfigDirs = splitList(v)
+		return nil
+	}},
+	{"RADAR_NAMESPACE", func(c *Config, v string) error { c.Kubernetes.Namespace = v; return nil }},
+	{"RADAR_SECRETS", func(c *Config, v string) error { c.Kubernetes.Secrets = v; return nil }},
+	{"RADAR_WATCH_LIST", func(c *Config, v string) error { c.Kubernetes.WatchList = v; return nil }},
+	{"RADAR_WATCH_NAMESPACES", func(c *Config, v string) error {
+		c.Kubernetes.WatchNamespaces = splitList(v)
+		return nil
+	}},
+	{"RADAR_IMPERSONATE", func(c *Config, v string) error { return parseBoolInto(&c.Kubernetes.Impersonate, v) }},
+	{"RADAR_TIMELINE_STORAGE", func(c *Config, v string) error { c.Timeline.Storage = v; return nil }},
+	{"RADAR_TIMELINE_DB", func(c *Config, v string) error { c.Timeline.DBPath = v; return nil }},
+	{"RADAR_HISTORY_LIMIT", func(c *Config, v string) error { return parseIntInto(&c.Timeline.HistoryLimit, v) }},
+	{"RADAR_TIMELINE_DSN", func(c *Config, v string) error { c.Timeline.DSN = v; return nil }},
+	{"RADAR_TIMELINE_MAX_CONNS", func(c *Config, v string) error { return parseIntInto(&c.Timeline.MaxConns, v) }},
+	{"RADAR_TIMELINE_RETENTION", func(c *Config, v string) error { c.Timeline.Retention = v; return nil }},
+	{"RADAR_TIMELINE_MAX_ROWS_PER_KIND", func(c *Config, v string) error { return parseIntInto(&c.Timeline.MaxRowsPerKind, v) }},
+	{"RADAR_TIMELINE_MAX_DB_SIZE_MB", func(c *Config, v string) error { return parseIntInto(&c.Timeline.MaxDBSizeMB, v) }},
+	{"RADAR_TIMELINE_DOWNSAMPLE_AFTER", func(c *Config, v string) error { c.Timeline.DownsampleAfter = v; return nil }},
+	{"RADAR_METRICS_STORAGE", func(c *Config, v string) error { c.Metrics.Storage = v; return nil }},
+	{"RADAR_METRICS_DB", func(c *Config, v string) error { c.Metrics.DBPath = v; return nil }},
+	{"RADAR_METRICS_RETENTION_RAW", func(c *Config, v string) error { c.Metrics.RetentionRaw = v; return nil }},
+	{"RADAR_METRICS_RETENTION_5M", func(c *Config, v string) error { c.Metrics.Retention5m = v; return nil }},
+	{"RADAR_METRICS_RETENTION_1H", func(c *Config, v string) error { c.Metrics.Retention1h = v; return nil }},
+	{"RADAR_DEBUG_EVENTS", func(c *Config, v string) error { return parseBoolInto(&c.Features.DebugEvents, v) }},
+	{"RADAR_HYGIENE_INTERVAL", func(c *Config, v string) error { c.Features.HygieneInterval = v; return nil }},
+	{"RADAR_COST_PRICING", func(c *Config, v string) error { c.Features.CostPricing = v; return nil }},
+	{"RADAR_NODE_SHELL_ENABLED", func(c *Config, v string) error { return parseBoolInto(&c.Features.NodeShell.Enabled, v) }},
+	{"RADAR_NODE_SHELL_IMAGE", func(c *Config, v string) error { c.Features.NodeShell.Image = v; return nil }},
+	{"RADAR_NODE_SHELL_NAMESPACE", func(c *Config, v string) error { c.Features.NodeShell.Namespace = v; return nil }},
+	{"RADAR_PORT_FORWARD_PROFILES", func(c *Config, v string) error {
+		c.Features.PortForwardProfiles = splitList(v)
+		return nil
+	}},
+	{"RADAR_TRAFFIC_METRICS", func(c *Config, v string) error { return parseBoolInto(&c.Features.TrafficMetrics.Enabled, v) }},
+	{"RADAR_PROMETHEUS_URL", func(c *Config, v string) error { c.Features.TrafficMetrics.PrometheusURL = v; return nil }},
+	{"RADAR_EXEC_AUDIT", func(c *Config, v string) error {
+		c.Features.ExecAudit.Sinks = splitList(v)
+		return nil
+	}},
+	{"RADAR_IMAGE_INSPECTION", func(c *Config, v string) error { return parseBoolInto(&c.Features.ImageInspection.Enabled, v) }},
+	{"RADAR_IMAGE_PULL_SECRETS", func(c *Config, v string) error {
+		c.Features.ImageInspection.PullSecrets = splitList(v)
+		return nil
+	}},
+	{"RADAR_IMAGE_VULNERABILITIES", func(c *Config, v string) error { c.Features.ImageInspection.Vulnerabilities = v; return nil }},
+	{"RADAR_IMAGE_INSPECTION_INTERVAL", func(c *Config, v string) error { c.Features.ImageInspection.Interval = v; return nil }},
+	{"RADAR_SCALE_HPA_POLICY", func(c *Config, v string) error { c.Features.ScaleHPAPolicy = v; return nil }},
+	{"RADAR_EXEC_AUDIT_INPUT", func(c *Config, v string) error { return parseBoolInto(&c.Features.ExecAudit.RecordInput, v) }},
+	{"RADAR_NOTIFICATIONS_CONFIG", func(c *Config, v string) error { c.Notifications.ConfigFile = v; return nil }},
+	{"RADAR_ARTIFACT_STORE", func(c *Config, v string) error { c.Artifacts.Store = v; return nil }},
+}
+
+// ApplyEnv applies RADAR_* environment overrides using the given lookup (os.LookupEnv)
+func (c *Config) ApplyEnv(lookup func(string) (string, bool)) error {
+	for _, o := range envOverrides {
+		v, ok := lookup(o.name)
+		if !ok {
+			continue
+		}
+		if err := o.apply(c, strings.TrimSpace(v)); err != nil {
+			return fmt.Errorf("%s: %w", o.name, err)
+		}
+	}
+	return nil
+}
+
+// EnvVarNames returns the supported environment variable names
+func EnvVarNames() []string {
+	names := make([]string, len(envOverrides))
+	for i, o := range envOverrides {
+		names[i] = o.name
+	}
+	return names
+}
+
+func parseIntInto(dst **int, v string) error {
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return fmt.Errorf("expected an integer, got %q", v)
+	}
+	*dst = &n
+	return nil
+}
+
+func parseBoolInto(dst **bool, v string) error {
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return fmt.Errorf("expected true or false, got %q", v)
+	}
+	*dst = &b
+	return nil
+}
+
+func splitList(v string) []string {
+	var out []string
+	for _, part := range strings.Split(v, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
@@ -0,0 +1,310 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/skyhook-io/radar/internal/cost"
+	"github.com/skyhook-io/radar/internal/execaudit"
+	"github.com/skyhook-io/radar/internal/health"
+	"github.com/skyhook-io/radar/internal/images"
+	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/notifications"
+	"github.com/skyhook-io/radar/internal/objectstore"
+)
+
+// ValidationError collects all problems found in a config
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%d config problem(s):\n  - %s", len(e.Problems), strings.Join(e.Problems, "\n  - "))
+}
+
+// Validate checks the config for invalid values, returning a *ValidationError listing every problem
+func (c *Config) Validate() error {
+	var problems []string
+	add := func(field, format string, args ...any) {
+		problems = append(problems, field+": "+fmt.Sprintf(format, args...))
+	}
+
+	if p := c.Server.Port; p != nil && (*p < 1 || *p > 65535) {
+		add("server.port", "must be between 1 and 65535, got %d", *p)
+	}
+
+	if pa := c.Server.ProxyAuth; pa.UserHeader != "" && pa.Secret == "" && c.Server.TLS.ClientCA == "" {
+		add("server.proxyAuth", "userHeader needs a secret or tls.clientCA to verify the proxy")
+	} else if pa.UserHeader == "" && (pa.Secret != "" || pa.GroupsHeader != "" || pa.SecretHeader != "") {
+		add("server.proxyAuth", "secret/groupsHeader/secretHeader are set but userHeader is not")
+	}
+	if tls := c.Server.TLS; (tls.Cert == "") != (tls.Key == "") {
+		add("server.tls", "cert and key must be set together")
+	} else if tls.ClientCA != "" && tls.Cert == "" {
+		add("server.tls.clientCA", "requires cert and key")
+	}
+
+	if v := c.Server.PublicSnapshot.Interval; v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			add("server.publicSnapshot.interval", "invalid duration %q (examples: 30s, 1m)", v)
+		} else if d < 5*time.Second {
+			add("server.publicSnapshot.interval", "must be at least 5s, got %s", d)
+		}
+	}
+	for i, ns := range c.Server.PublicSnapshot.Namespaces {
+		if errs := validation.IsDNS1123Label(ns); len(errs) > 0 {
+			add(fmt.Sprintf("server.publicSnapshot.namespaces[%d]", i), "invalid namespace %q: %s", ns, errs[0])
+		}
+	}
+
+	if v := c.Server.ShutdownTimeout; v != "" {
+		if d, err := time.ParseDuration(v); err != nil || d <= 0 {
+			add("server.shutdownTimeout", "invalid duration %q (examples: 10s, 1m)", v)
+		}
+	}
+
+	if c.Kubernetes.Kubeconfig != "" && len(c.Kubernetes.KubeconfigDirs) > 0 {
+		add("kubernetes", "kubeconfig and kubeconfigDirs are mutually exclusive")
+	}
+	for _, path := range filepath.SplitList(c.Kubernetes.Kubeconfig) {
+		if _, err := os.Stat(expandHome(path)); err != nil {
+			add("kubernetes.kubeconfig", "file %s is not readable (%v)", path, errors.Unwrap(err))
+		}
+	}
+	for i, dir := range c.Kubernetes.KubeconfigDirs {
+		if info, err := os.Stat(expandHome(dir)); err != nil || !info.IsDir() {
+			add(fmt.Sprintf("kubernetes.kubeconfigDirs[%d]", i), "%s is not a directory", dir)
+		}
+	}
+
+	switch c.Kubernetes.Secrets {
+	case "", "auto", "full", "metadata", "off":
+	default:
+		add("kubernetes.secrets", "must be one of auto, full, metadata or off, got %q", c.Kubernetes.Secrets)
+	}
+	switch c.Kubernetes.WatchList {
+	case "", "auto", "on", "off":
+	default:
+		add("kubernetes.watchList", "must be one of auto, on or off, got %q", c.Kubernetes.WatchList)
+	}
+	for i, ns := range c.Kubernetes.WatchNamespaces {
+		if errs := validation.IsDNS1123Label(ns); len(errs) > 0 {
+			add(fmt.Sprintf("kubernetes.watchNamespaces[%d]", i), "invalid namespace %q: %s", ns, errs[0])
+		}
+	}
+
+	switch c.Timeline.Storage {
+	case "", "memory", "sqlite", "postgres":
+	default:
+		add("timeline.storage", "must be \"memory\", \"sqlite\" or \"postgres\", got %q", c.Timeline.Storage)
+	}
+	if c.Timeline.DBPath != "" && c.Timeline.Storage != "sqlite" {
+		add("timeline.dbPath", "only used with storage: sqlite")
+	}
+	if l := c.Timeline.HistoryLimit; l != nil && *l <= 0 {
+		add("timeline.historyLimit", "must be positive, got %d", *l)
+	}
+	if n := c.Timeline.MaxConns; n != nil && *n <= 0 {
+		add("timeline.maxConns", "must be positive, got %d", *n)
+	}
+	if v := c.Timeline.Retention; v != "" {
+		if d, err := time.ParseDuration(v); err != nil || d < 0 {
+			add("timeline.retention", "invalid duration %q (examples: 72h, 720h)", v)
+		}
+	}
+	sqliteOnly := func(field string) {
+		if c.Timeline.Storage != "sqlite" {
+			add(field, "only used with storage: sqlite")
+		}
+	}
+	if n := c.Timeline.MaxRowsPerKind; n != nil {
+		sqliteOnly("timeline.maxRowsPerKind")
+		if *n < 0 {
+			add("timeline.maxRowsPerKind", "must not be negative, got %d", *n)
+		}
+	}
+	if n := c.Timeline.MaxDBSizeMB; n != nil {
+		sqliteOnly("timeline.maxDBSizeMB")
+		if *n < 0 {
+			add("timeline.maxDBSizeMB", "must not be negative, got %d", *n)
+		}
+	}
+	if v := c.Timeline.DownsampleAfter; v != "" {
+		sqliteOnly("timeline.downsampleAfter")
+		if d, err := time.ParseDuration(v); err != nil || d < 0 {
+			add("timeline.downsampleAfter", "invalid duration %q (examples: 24h, 168h)", v)
+		}
+	}
+	if err := k8s.ValidateDiffRules(c.Timeline.DiffRules); err != nil {
+		add("timeline.diffRules", "%v", err)
+	}
+	if err := health.ValidateRules(c.Health.Rules); err != nil {
+		add("health.rules", "%v", err)
+	}
+
+	switch c.Metrics.Storage {
+	case "", "memory", "sqlite":
+	default:
+		add("metrics.storage", "must be \"memory\" or \"sqlite\", got %q", c.Metrics.Storage)
+	}
+	if c.Metrics.DBPath != "" && c.Metrics.Storage != "sqlite" {
+		add("metrics.dbPath", "only used with storage: sqlite")
+	}
+	for _, r := range []struct{ field, value string }{
+		{"metrics.retentionRaw", c.Metrics.RetentionRaw},
+		{"metrics.retention5m", c.Metrics.Retention5m},
+		{"metrics.retention1h", c.Metrics.Retention1h},
+	} {
+		if r.value == "" {
+			continue
+		}
+		if c.Metrics.Storage != "sqlite" {
+			add(r.field, "only used with storage: sqlite")
+		}
+		if d, err := time.ParseDuration(r.value); err != nil || d <= 0 {
+			add(r.field, "invalid duration %q (examples: 24h, 168h, 2160h)", r.value)
+		}
+	}
+
+	if n := c.Features.FileTransferMaxMB; n != nil && *n < 0 {
+		add("features.fileTransferMaxMB", "must not be negative, got %d", *n)
+	}
+	switch c.Features.ScaleHPAPolicy {
+	case "", "warn", "refuse":
+	default:
+		add("features.scaleHpaPolicy", "must be warn or refuse, got %q", c.Features.ScaleHPAPolicy)
+	}
+	if v := c.Features.HygieneInterval; v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			add("features.hygieneInterval", "invalid duration %q (examples: 30m, 1h, 6h)", v)
+		} else if d < time.Minute {
+			add("features.hygieneInterval", "must be at least 1m, got %s", d)
+		}
+	}
+	if v := c.Features.CostPricing; v != "" {
+		if err := cost.ValidateSource(expandHome(v)); err != nil {
+			add("features.costPricing", "%v", err)
+		}
+	}
+	ns := c.Features.NodeShell
+	if (ns.Image != "" || ns.Namespace != "") && (ns.Enabled == nil || !*ns.Enabled) {
+		add("features.nodeShell", "image/namespace are set but enabled is not true")
+	}
+	for _, spec := range c.Features.ExecAudit.Sinks {
+		if err := execaudit.ValidateSink(spec); err != nil {
+			add("features.execAudit.sinks", "%v", err)
+		}
+	}
+	if v := c.Artifacts.Store; v != "" {
+		if err := objectstore.Validate(v); err != nil {
+			add("artifacts.store", "%v", err)
+		}
+	}
+	if tm := c.Features.TrafficMetrics; tm.PrometheusURL != "" {
+		if u, err := url.Parse(tm.PrometheusURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			add("features.trafficMetrics.prometheusUrl", "must be an http(s) URL, got %q", tm.PrometheusURL)
+		}
+		if tm.Enabled == nil || !*tm.Enabled {
+			add("features.trafficMetrics", "prometheusUrl is set but enabled is not true")
+		}
+	}
+
+	if ii := c.Features.ImageInspection; ii.Enabled == nil || !*ii.Enabled {
+		if len(ii.PullSecrets) > 0 || ii.Vulnerabilities != "" || ii.Interval != "" {
+			add("features.imageInspection", "pullSecrets/vulnerabilities/interval are set but enabled is not true")
+		}
+	}
+	for _, name := range c.Features.ImageInspection.PullSecrets {
+		if ns, secret, ok := strings.Cut(name, "/"); !ok || ns == "" || secret == "" {
+			add("features.imageInspection.pullSecrets", "%q must be namespace/name", name)
+		}
+	}
+	if err := images.ValidateVulnSource(c.Features.ImageInspection.Vulnerabilities); err != nil {
+		add("features.imageInspection.vulnerabilities", "%v", err)
+	}
+	if v := c.Features.ImageInspection.Interval; v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			add("features.imageInspection.interval", "invalid duration %q (examples: 1h, 6h, 24h)", v)
+		} else if d < 10*time.Minute {
+			add("features.imageInspection.interval", "must be at least 10m, got %s", d)
+		}
+	}
+
+	if c.Notifications.ConfigFile != "" && (len(c.Notifications.Channels) > 0 || len(c.Notifications.Triggers) > 0 || len(c.Notifications.Rules) > 0) {
+		add("notifications", "configFile and inline channels/triggers/rules are mutually exclusive")
+	}
+	if c.Notifications.ConfigFile != "" {
+		if nc, err := notifications.LoadConfig(expandHome(c.Notifications.ConfigFile)); err != nil {
+			add("notifications.configFile", "%v", err)
+		} else {
+			for _, err := range notifications.ValidateConfig(nc) {
+				add("notifications.configFile", "%v", err)
+			}
+		}
+	}
+	for _, err := range notifications.ValidateConfig(notifications.Config{Channels: c.Notifications.Channels, Triggers: c.Notifications.Triggers, Rules: c.Notifications.Rules}) {
+		add("notifications", "%v", err)
+	}
+
+	// Every profile must be valid on its own; problems inherited from the base are reported once
+	baseProblems := make(map[string]bool, len(problems))
+	for _, p := range problems {
+		baseProblems[p] = true
+	}
+	for _, name := range c.ProfileNames() {
+		profiled, err := c.clone()
+		if err != nil {
+			add("profiles."+name, "%v", err)
+			continue
+		}
+		if err := profiled.ApplyProfile(name); err != nil {
+			add("profiles."+name, "%v", err)
+			continue
+		}
+		profiled.Profiles = nil
+		if err := profiled.Validate(); err != nil {
+			var verr *ValidationError
+			if errors.As(err, &verr) {
+				for _, p := range verr.Problems {
+					if !baseProblems[p] {
+						add("profiles."+name, "%s", p)
+					}
+				}
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// expandHome expands a leading ~/ to the user's home directory
+// expandPathList expands ~ in each entry of a path list like KUBECONFIG
+func expandPathList(list string) string {
+	paths := filepath.SplitList(list)
+	for i, path := range paths {
+		paths[i] = expandHome(path)
+	}
+	return strings.Join(paths, string(filepath.ListSeparator))
+}
+
+func expandHome(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return home + path[1:]
+		}
+	}
+	return path
+}
@@ -0,0 +1,340 @@
+package cost
+
+import (
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Basis is what a pod is charged for
+type Basis string
+
+const (
+	BasisRequests Basis = "requests" // CPU and memory requests (what the pod reserves)
+	BasisUsage    Basis = "usage"    // Average measured usage, falling back to requests without metrics
+	BasisMax      Basis = "max"      // The larger of requests and usage
+)
+
+// ParseBasis validates a basis name (empty = requests)
+func ParseBasis(s string) (Basis, bool) {
+	switch Basis(s) {
+	case "", BasisRequests:
+		return BasisRequests, true
+	case BasisUsage, BasisMax:
+		return Basis(s), true
+	}
+	return "", false
+}
+
+// Well-known node labels
+const (
+	labelInstanceType     = "node.kubernetes.io/instance-type"
+	labelInstanceTypeBeta = "beta.kubernetes.io/instance-type"
+	labelRegion           = "topology.kubernetes.io/region"
+	labelRegionBeta       = "failure-domain.beta.kubernetes.io/region"
+)
+
+// spotLabels mark spot and preemptible nodes, by label and the value that means spot
+var spotLabels = map[string]string{
+	"karpenter.sh/capacity-type":            "spot",
+	"eks.amazonaws.com/capacityType":        "SPOT",
+	"cloud.google.com/gke-spot":             "true",
+	"cloud.google.com/gke-preemptible":      "true",
+	"kubernetes.azure.com/scalesetpriority": "spot",
+	"node.kubernetes.io/lifecycle":          "spot",
+}
+
+// Cost is an amount at hourly, daily and monthly rates
+type Cost struct {
+	Hourly  float64 `json:"hourly"`
+	Daily   float64 `json:"daily"`
+	Monthly float64 `json:"monthly"`
+}
+
+func hourly(h float64) Cost {
+	return Cost{Hourly: h, Daily: h * 24, Monthly: h * HoursPerMonth}
+}
+
+// NodeCost is the estimated cost of one node
+type NodeCost struct {
+	Name         string  `json:"name"`
+	InstanceType string  `json:"instanceType,omitempty"`
+	Region       string  `json:"region,omitempty"`
+	Spot         bool    `json:"spot,omitempty"`
+	PricedBy     string  `json:"pricedBy"` // "instance" (listed price) or "resources" (CPU and memory rates)
+	CPUCores     float64 `json:"cpuCores"` // Allocatable
+	MemoryBytes  int64   `json:"memoryBytes"`
+	Cost         Cost    `json:"cost"`
+	Idle         Cost    `json:"idle"` // Not attributed to any pod
+}
+
+// Allocation is the cost attributed to a namespace or workload
+type Allocation struct {
+	Namespace   string  `json:"namespace"`
+	Kind        string  `json:"kind,omitempty"` // Workload allocations only
+	Name        string  `json:"name,omitempty"`
+	Pods        int     `json:"pods"`
+	CPUCores    float64 `json:"cpuCores"`
+	MemoryBytes int64   `json:"memoryBytes"`
+	Cost        Cost    `json:"cost"`
+}
+
+// Report is a cluster cost estimate
+type Report struct {
+	Currency      string       `json:"currency"`
+	Basis         Basis        `json:"basis"`
+	PricingSource string       `json:"pricingSource"`
+	GeneratedAt   time.Time    `json:"generatedAt"`
+	Total         Cost         `json:"total"`
+	Idle          Cost         `json:"idle"` // Node capacity no pod is charged for
+	Nodes         []NodeCost   `json:"nodes"`
+	Namespaces    []Allocation `json:"namespaces"`
+	Workloads     []Allocation `json:"workloads,omitempty"`
+}
+
+// Usage is a pod's average measured usage
+type Usage struct {
+	CPUCores    float64
+	MemoryBytes int64
+}
+
+// Input is what a cost estimate is computed from
+type Input struct {
+	Pricing       *Pricing
+	PricingSource string
+	Basis         Basis
+	Nodes         []*corev1.Node
+	Pods          []*corev1.Pod
+	// Usage returns a pod's measured usage (nil = no metrics). Needed for the usage and max bases.
+	Usage func(namespace, name string) *Usage
+	// Owner resolves a pod to its top-level workload (nil = the pod's controller)
+	Owner func(pod *corev1.Pod) (kind, name string)
+	// Workloads limits the workload breakdown to these namespaces; nil = none, empty = all
+	Workloads map[string]bool
+	Now       time.Time
+}
+
+// nodeRates is what a core and a GiB cost per hour on one node
+type nodeRates struct {
+	cpu, memGB float64
+}
+
+// Compute estimates node costs and attributes them to the pods scheduled on them
+func Compute(in Input) *Report {
+	p := in.Pricing
+	if p == nil {
+		p = DefaultPricing()
+	}
+	report := &Report{
+		Currency:      p.Currency,
+		Basis:         in.Basis,
+		PricingSource: in.PricingSource,
+		GeneratedAt:   in.Now,
+		Nodes:         []NodeCost{},
+		Namespaces:    []Allocation{},
+	}
+
+	rates := make(map[string]*nodeRates, len(in.Nodes))
+	nodes := make([]NodeCost, 0, len(in.Nodes))
+	for _, node := range in.Nodes {
+		nodes = append(nodes, priceNode(node, p))
+	}
+	var total float64
+	for i := range nodes {
+		nc := &nodes[i]
+		total += nc.Cost.Hourly
+		// Split the node's price between CPU and memory in proportion to the unit rates,
+		// then spread each part over what's allocatable
+		memGB := float64(nc.MemoryBytes) / (1 << 30)
+		weighted := nc.CPUCores*p.CPUHourly + memGB*p.MemoryGBHourly
+		r := &nodeRates{}
+		if weighted > 0 {
+			if nc.CPUCores > 0 {
+				r.cpu = nc.Cost.Hourly * (nc.CPUCores * p.CPUHourly / weighted) / nc.CPUCores
+			}
+			if memGB > 0 {
+				r.memGB = nc.Cost.Hourly * (memGB * p.MemoryGBHourly / weighted) / memGB
+			}
+		}
+		rates[nc.Name] = r
+	}
+
+	attributed := make(map[string]float64, len(rates))
+	namespaces := map[string]*Allocation{}
+	workloads := map[string]*Allocation{}
+	for _, pod := range in.Pods {
+		if pod.Spec.NodeName == "" || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		r, ok := rates[pod.Spec.NodeName]
+		if !ok {
+			continue
+		}
+		cpu, mem := chargedResources(pod, in.Basis, in.Usage)
+		h := cpu*r.cpu + float64(mem)/(1<<30)*r.memGB
+		attributed[pod.Spec.NodeName] += h
+
+		ns := namespaces[pod.Namespace]
+		if ns == nil {
+			ns = &Allocation{Namespace: pod.Namespace}
+			namespaces[pod.Namespace] = ns
+		}
+		ns.add(cpu, mem, h)
+
+		if in.Workloads == nil || (len(in.Workloads) > 0 && !in.Workloads[pod.Namespace]) {
+			continue
+		}
+		kind, name := podOwner(pod, in.Owner)
+		key := pod.Namespace + "/" + kind + "/" + name
+		wl := workloads[key]
+		if wl == nil {
+			wl = &Allocation{Namespace: pod.Namespace, Kind: kind, Name: name}
+			workloads[key] = wl
+		}
+		wl.add(cpu, mem, h)
+	}
+
+	var idle float64
+	for i := range nodes {
+		nc := &nodes[i]
+		// Usage above requests can exceed what's allocatable; that node has no idle cost
+		if v := nc.Cost.Hourly - attributed[nc.Name]; v > 0 {
+			nc.Idle = hourly(v)
+			idle += v
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+	report.Nodes = nodes
+	report.Total = hourly(total)
+	report.Idle = hourly(idle)
+	report.Namespaces = sortedAllocations(namespaces)
+	if in.Workloads != nil {
+		report.Workloads = sortedAllocations(workloads)
+	}
+	return report
+}
+
+func (a *Allocation) add(cpu float64, mem int64, h float64) {
+	a.Pods++
+	a.CPUCores += cpu
+	a.MemoryBytes += mem
+	a.Cost = hourly(a.Cost.Hourly + h)
+}
+
+// sortedAllocations orders allocations by cost, most expensive first
+func sortedAllocations(m map[string]*Allocation) []Allocation {
+	out := make([]Allocation, 0, len(m))
+	for _, a := range m {
+		out = append(out, *a)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Cost.Hourly != out[j].Cost.Hourly {
+			return out[i].Cost.Hourly > out[j].Cost.Hourly
+		}
+		if out[i].Namespace != out[j].Namespace {
+			return out[i].Namespace < out[j].Namespace
+		}
+		return out[i].Kind+"/"+out[i].Name < out[j].Kind+"/"+out[j].Name
+	})
+	return out
+}
+
+// priceNode prices a node from its instance type, or from its capacity at the unit rates
+func priceNode(node *corev1.Node, p *Pricing) NodeCost {
+	nc := NodeCost{
+		Name:         node.Name,
+		InstanceType: firstLabel(node.Labels, labelInstanceType, labelInstanceTypeBeta),
+		Region:       firstLabel(node.Labels, labelRegion, labelRegionBeta),
+		Spot:         isSpot(node.Labels),
+		CPUCores:     quantityCores(node.Status.Allocatable[corev1.ResourceCPU]),
+		MemoryBytes:  node.Status.Allocatable.Memory().Value(),
+	}
+	price, ok := p.instancePrice(nc.Region, nc.InstanceType)
+	if ok {
+		nc.PricedBy = "instance"
+	} else {
+		nc.PricedBy = "resources"
+		cpu := quantityCores(node.Status.Capacity[corev1.ResourceCPU])
+		mem := float64(node.Status.Capacity.Memory().Value()) / (1 << 30)
+		price = cpu*p.CPUHourly + mem*p.MemoryGBHourly
+	}
+	if nc.Spot {
+		price *= 1 - p.SpotDiscount
+	}
+	nc.Cost = hourly(price)
+	return nc
+}
+
+func firstLabel(labels map[string]string, keys ...string) string {
+	for _, k := range keys {
+		if v := labels[k]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func isSpot(labels map[string]string) bool {
+	for k, v := range spotLabels {
+		if labels[k] == v {
+			return true
+		}
+	}
+	return false
+}
+
+func quantityCores(q resource.Quantity) float64 {
+	return float64(q.MilliValue()) / 1000
+}
+
+// chargedResources returns the CPU cores and memory bytes a pod is charged for
+func chargedResources(pod *corev1.Pod, basis Basis, usage func(namespace, name string) *Usage) (float64, int64) {
+	cpu, mem := podRequests(pod)
+	if basis == BasisRequests || usage == nil {
+		return cpu, mem
+	}
+	u := usage(pod.Namespace, pod.Name)
+	if u == nil {
+		return cpu, mem
+	}
+	if basis == BasisUsage {
+		return u.CPUCores, u.MemoryBytes
+	}
+	return max(cpu, u.CPUCores), max(mem, u.MemoryBytes)
+}
+
+// podRequests returns a pod's effective requests, as the scheduler counts them: the sum
+// of its containers or its largest init container, whichever is larger, plus overhead
+func podRequests(pod *corev1.Pod) (float64, int64) {
+	var cpu, initCPU float64
+	var mem, initMem int64
+	for _, c := range pod.Spec.Containers {
+		cpu += quantityCores(c.Resources.Requests[corev1.ResourceCPU])
+		mem += c.Resources.Requests.Memory().Value()
+	}
+	for _, c := range pod.Spec.InitContainers {
+		initCPU = max(initCPU, quantityCores(c.Resources.Requests[corev1.ResourceCPU]))
+		initMem = max(initMem, c.Resources.Requests.Memory().Value())
+	}
+	cpu, mem = max(cpu, initCPU), max(mem, initMem)
+	cpu += quantityCores(pod.Spec.Overhead[corev1.ResourceCPU])
+	mem += pod.Spec.Overhead.Memory().Value()
+	return cpu, mem
+}
+
+// podOwner returns the workload a pod's cost is attributed to
+func podOwner(pod *corev1.Pod, owner func(*corev1.Pod) (string, string)) (string, string) {
+	if owner != nil {
+		if kind, name := owner(pod); kind != "" {
+			return kind, name
+		}
+	}
+	for _, ref := range pod.OwnerReferences {
+		if ref.Controller != nil && *ref.Controller {
+			return ref.Kind, ref.Name
+		}
+	}
+	return "Pod", pod.Name
+}
@@ -0,0 +1,141 @@
+package cost
+
+import (
+	"math"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func node(name string, labels map[string]string, cpu, mem string) *corev1.Node {
+	res := corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu), corev1.ResourceMemory: resource.MustParse(mem)}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Status:     corev1.NodeStatus{Capacity: res, Allocatable: res},
+	}
+}
+
+func pod(ns, name, nodeName, owner, cpu, mem string) *corev1.Pod {
+	p := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name},
+		Spec: corev1.PodSpec{
+			NodeName: nodeName,
+			Containers: []corev1.Container{{Name: "app", Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse(cpu), corev1.ResourceMemory: resource.MustParse(mem),
+			}}}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	if owner != "" {
+		isController := true
+		p.OwnerReferences = []metav1.OwnerReference{{Kind: "ReplicaSet", Name: owner, Controller: &isController}}
+	}
+	return p
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestComputeAttributesNodeCost(t *testing.T) {
+	pricing := &Pricing{Currency: "USD", CPUHourly: 0.04, MemoryGBHourly: 0.005, SpotDiscount: 0.5,
+		Instances: map[string]float64{"m5.xlarge": 0.192, "eu-west-1/m5.xlarge": 0.214}}
+	nodes := []*corev1.Node{
+		node("a", map[string]string{labelInstanceType: "m5.xlarge", labelRegion: "us-east-1"}, "4", "16Gi"),
+		node("b", map[string]string{labelInstanceType: "m5.xlarge", labelRegion: "eu-west-1", "karpenter.sh/capacity-type": "spot"}, "4", "16Gi"),
+		node("c", nil, "2", "8Gi"),
+	}
+	pods := []*corev1.Pod{
+		pod("shop", "web-1", "a", "web-abc", "2", "8Gi"),
+		pod("shop", "web-2", "a", "web-abc", "2", "8Gi"),
+		pod("batch", "job-1", "c", "", "1", "4Gi"),
+		pod("shop", "pending", "", "web-abc", "1", "1Gi"),
+	}
+	done := pod("batch", "done", "c", "", "1", "1Gi")
+	done.Status.Phase = corev1.PodSucceeded
+	pods = append(pods, done)
+
+	r := Compute(Input{Pricing: pricing, Basis: BasisRequests, Nodes: nodes, Pods: pods, Workloads: map[string]bool{}})
+
+	want := map[string]struct {
+		hourly   float64
+		pricedBy string
+	}{
+		"a": {0.192, "instance"},
+		"b": {0.214 * 0.5, "instance"},
+		"c": {2*0.04 + 8*0.005, "resources"},
+	}
+	for _, n := range r.Nodes {
+		if w := want[n.Name]; !near(n.Cost.Hourly, w.hourly) || n.PricedBy != w.pricedBy {
+			t.Errorf("node %s = %v by %s, want %v by %s", n.Name, n.Cost.Hourly, n.PricedBy, w.hourly, w.pricedBy)
+		}
+	}
+	if total := 0.192 + 0.107 + 0.12; !near(r.Total.Hourly, total) || !near(r.Total.Monthly, total*HoursPerMonth) {
+		t.Errorf("total = %+v, want %v/h", r.Total, total)
+	}
+
+	// The web pods request all of node a, and the batch pod half of node c
+	if len(r.Namespaces) != 2 || r.Namespaces[0].Namespace != "shop" || r.Namespaces[1].Namespace != "batch" {
+		t.Fatalf("namespaces = %+v", r.Namespaces)
+	}
+	if shop := r.Namespaces[0]; !near(shop.Cost.Hourly, 0.192) || shop.Pods != 2 || shop.CPUCores != 4 {
+		t.Errorf("shop = %+v", shop)
+	}
+	if batch := r.Namespaces[1]; !near(batch.Cost.Hourly, 0.06) || batch.Pods != 1 {
+		t.Errorf("batch = %+v", batch)
+	}
+	if !near(r.Idle.Hourly, 0.107+0.06) {
+		t.Errorf("idle = %v, want all of b and half of c", r.Idle.Hourly)
+	}
+
+	if len(r.Workloads) != 2 || r.Workloads[0].Kind != "ReplicaSet" || r.Workloads[0].Name != "web-abc" || r.Workloads[1].Kind != "Pod" {
+		t.Errorf("workloads = %+v", r.Workloads)
+	}
+}
+
+func TestComputeBasis(t *testing.T) {
+	nodes := []*corev1.Node{node("a", nil, "4", "16Gi")}
+	pods := []*corev1.Pod{pod("shop", "web", "a", "", "1", "1Gi"), pod("shop", "idle", "a", "", "1", "1Gi")}
+	usage := func(ns, name string) *Usage {
+		if name == "web" {
+			return &Usage{CPUCores: 2, MemoryBytes: 512 << 20}
+		}
+		return nil // No metrics: charged for requests
+	}
+
+	tests := []struct {
+		basis Basis
+		cpu   float64
+		mem   int64
+	}{
+		{BasisRequests, 2, 2 << 30},
+		{BasisUsage, 3, 1<<30 + 512<<20},
+		{BasisMax, 3, 2 << 30},
+	}
+	for _, tt := range tests {
+		r := Compute(Input{Basis: tt.basis, Nodes: nodes, Pods: pods, Usage: usage})
+		if ns := r.Namespaces[0]; ns.CPUCores != tt.cpu || ns.MemoryBytes != tt.mem {
+			t.Errorf("%s: charged %v cores, %d bytes; want %v, %d", tt.basis, ns.CPUCores, ns.MemoryBytes, tt.cpu, tt.mem)
+		}
+		if r.Workloads != nil {
+			t.Errorf("%s: workloads without a namespace filter", tt.basis)
+		}
+	}
+}
+
+func TestParsePricing(t *testing.T) {
+	p, err := ParsePricing([]byte("instances:\n  m5.large: 0.096\nspotDiscount: 0.7\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Currency != "USD" || p.CPUHourly != DefaultPricing().CPUHourly || p.Instances["m5.large"] != 0.096 {
+		t.Errorf("pricing = %+v, want defaults plus instances", p)
+	}
+	for _, bad := range []string{`{"spotDiscount": 1.5}`, `{"cpuHourly": 0}`, `{"instances": {"x": -1}}`, `{"cpuHourlyy": 1}`} {
+		if _, err := ParsePricing([]byte(bad)); err == nil {
+			t.Errorf("ParsePricing(%s) = nil error", bad)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package cost
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"k8s.io/apimachinery/pkg/labels"
+
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// Handlers provides HTTP handlers for cost estimation endpoints
+type Handlers struct{}
+
+// NewHandlers creates a new Handlers instance
+func NewHandlers() *Handlers {
+	return &Handlers{}
+}
+
+// RegisterRoutes registers cost routes on the given router
+func (h *Handlers) RegisterRoutes(r chi.Router) {
+	r.Route("/costs", func(r chi.Router) {
+		r.Get("/", h.handleGetCosts)
+		r.Get("/pricing", h.handleGetPricing)
+	})
+}
+
+// handleGetCosts returns the cluster cost estimate, by node and namespace.
+// ?namespace= (comma-separated) limits namespaces and adds their per-workload breakdown;
+// ?basis=requests|usage|max picks what pods are charged for (default requests).
+func (h *Handlers) handleGetCosts(w http.ResponseWriter, r *http.Request) {
+	basis, ok := ParseBasis(r.URL.Query().Get("basis"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "basis must be requests, usage or max")
+		return
+	}
+	cache := k8s.GetResourceCache()
+	if cache == nil {
+		explorerErrors.Write(w, explorerErrors.CacheNotInitialized())
+		return
+	}
+	nodes, err := cache.Nodes().List(labels.Everything())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	pods, err := cache.Pods().List(labels.Everything())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	pricing, source := CurrentPricing()
+	in := Input{
+		Pricing:       pricing,
+		PricingSource: source,
+		Basis:         basis,
+		Nodes:         nodes,
+		Pods:          pods,
+		Usage:         metricsUsage(k8s.GetMetricsHistory()),
+		Owner:         cache.PodWorkload,
+		Now:           time.Now(),
+	}
+	var namespaces []string
+	for _, ns := range strings.Split(r.URL.Query().Get("namespace"), ",") {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	if len(namespaces) > 0 {
+		in.Workloads = make(map[string]bool, len(namespaces))
+		for _, ns := range namespaces {
+			in.Workloads[ns] = true
+		}
+	}
+
+	report := Compute(in)
+	if in.Workloads != nil {
+		// Node totals stay cluster-wide; only the namespace breakdown is scoped
+		scoped := make([]Allocation, 0, len(namespaces))
+		for _, a := range report.Namespaces {
+			if in.Workloads[a.Namespace] {
+				scoped = append(scoped, a)
+			}
+		}
+		report.Namespaces = scoped
+	}
+	writeJSON(w, report)
+}
+
+// handleGetPricing returns the active pricing table
+func (h *Handlers) handleGetPricing(w http.ResponseWriter, r *http.Request) {
+	pricing, source := CurrentPricing()
+	writeJSON(w, map[string]any{"source": source, "pricing": pricing})
+}
+
+// metricsUsage averages a pod's samples in the metrics history
+func metricsUsage(store *k8s.MetricsHistoryStore) func(namespace, name string) *Usage {
+	if store == nil {
+		return nil
+	}
+	return func(namespace, name string) *Usage {
+		history := store.GetPodMetricsHistory(namespace, name)
+		if history == nil {
+			return nil
+		}
+		var u Usage
+		var found bool
+		for _, c := range history.Containers {
+			if len(c.DataPoints) == 0 {
+				continue
+			}
+			var cpu, mem int64
+			for _, dp := range c.DataPoints {
+				cpu += dp.CPU
+				mem += dp.Memory
+			}
+			n := int64(len(c.DataPoints))
+			u.CPUCores += float64(cpu/n) / 1e9 // Nanocores
+			u.MemoryBytes += mem / n
+			found = true
+		}
+		if !found {
+			return nil
+		}
+		return &u
+	}
+}
+
+func writeJSON(w http.ResponseWriter, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(data)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	explorerErrors.WriteHTTP(w, status, message)
+}
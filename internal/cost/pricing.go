@@ -0,0 +1,206 @@
+// Package cost estimates what the cluster costs to run and attributes it to namespaces and
+// workloads. Nodes are priced by instance type (from the well-known node labels) using a
+// pricing table, and each node's cost is split between the pods on it by their CPU and
+// memory requests, measured usage from the metrics history, or the larger of the two.
+// Estimates are on-demand list prices: discounts, storage and network aren't included.
+package cost
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// HoursPerMonth is the average month used for monthly estimates
+const HoursPerMonth = 730
+
+// pricingRefreshInterval is how often a pricing table served over HTTP is re-fetched
+const pricingRefreshInterval = 24 * time.Hour
+
+// Pricing is a pricing table. Nodes whose instance type is listed cost that hourly price;
+// others are priced by their CPU and memory at the per-unit rates.
+type Pricing struct {
+	Currency       string  `json:"currency"`
+	CPUHourly      float64 `json:"cpuHourly"`      // Per core
+	MemoryGBHourly float64 `json:"memoryGBHourly"` // Per GiB
+	// SpotDiscount is the fraction taken off spot and preemptible nodes (0.7 = 70% off)
+	SpotDiscount float64 `json:"spotDiscount,omitempty"`
+	// Instances maps instance types to hourly prices. A "region/type" key (us-east-1/m5.large)
+	// takes precedence over the plain type for nodes in that region.
+	Instances map[string]float64 `json:"instances,omitempty"`
+}
+
+// DefaultPricing is used when no pricing table is configured: per-unit rates in line with
+// on-demand general-purpose instances on the major clouds
+func DefaultPricing() *Pricing {
+	return &Pricing{
+		Currency:       "USD",
+		CPUHourly:      0.031611,
+		MemoryGBHourly: 0.004237,
+	}
+}
+
+// Validate checks that the rates are usable
+func (p *Pricing) Validate() error {
+	if p.CPUHourly <= 0 || p.MemoryGBHourly <= 0 {
+		return fmt.Errorf("cpuHourly and memoryGBHourly must be positive")
+	}
+	if p.SpotDiscount < 0 || p.SpotDiscount >= 1 {
+		return fmt.Errorf("spotDiscount must be between 0 and 1, got %v", p.SpotDiscount)
+	}
+	for k, v := range p.Instances {
+		if v < 0 {
+			return fmt.Errorf("instance %s: price must not be negative", k)
+		}
+	}
+	return nil
+}
+
+// instancePrice returns the listed hourly price of an instance type
+func (p *Pricing) instancePrice(region, instanceType string) (float64, bool) {
+	if instanceType == "" {
+		return 0, false
+	}
+	if region != "" {
+		if v, ok := p.Instances[region+"/"+instanceType]; ok {
+			return v, true
+		}
+	}
+	v, ok := p.Instances[instanceType]
+	return v, ok
+}
+
+// ParsePricing decodes a pricing table (YAML or JSON). Unset rates and currency fall back
+// to the defaults, so a table may list only instance prices.
+func ParsePricing(data []byte) (*Pricing, error) {
+	p := DefaultPricing()
+	if err := yaml.UnmarshalStrict(data, p); err != nil {
+		return nil, fmt.Errorf("invalid pricing table: %w", err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid pricing table: %w", err)
+	}
+	return p, nil
+}
+
+// LoadPricing reads a pricing table from a file or an http(s) URL
+func LoadPricing(ctx context.Context, source string) (*Pricing, error) {
+	if !isURL(source) {
+		data, err := os.ReadFile(source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read pricing table: %w", err)
+		}
+		return ParsePricing(data)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch pricing table: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch pricing table: %s returned %s", source, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 16<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch pricing table: %w", err)
+	}
+	return ParsePricing(data)
+}
+
+// ValidateSource checks a pricing source without loading remote tables
+func ValidateSource(source string) error {
+	if isURL(source) {
+		if u, err := url.Parse(source); err != nil || u.Host == "" {
+			return fmt.Errorf("invalid pricing URL %q", source)
+		}
+		return nil
+	}
+	data, err := os.ReadFile(source)
+	if err != nil {
+		return err
+	}
+	_, err = ParsePricing(data)
+	return err
+}
+
+func isURL(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+// pricingState is the active pricing table and where it came from
+type pricingState struct {
+	mu         sync.Mutex
+	source     string // Empty = defaults
+	pricing    *Pricing
+	fetchedAt  time.Time
+	refreshing bool
+}
+
+var active = &pricingState{pricing: DefaultPricing()}
+
+// Init loads the pricing table from source (a file or an http(s) URL; empty = defaults).
+// Tables served over HTTP are re-fetched daily in the background.
+func Init(source string) error {
+	active.mu.Lock()
+	defer active.mu.Unlock()
+	active.source, active.pricing, active.fetchedAt = source, DefaultPricing(), time.Time{}
+	if source == "" {
+		return nil
+	}
+	p, err := LoadPricing(context.Background(), source)
+	if err != nil {
+		return err
+	}
+	active.pricing, active.fetchedAt = p, time.Now()
+	log.Printf("Cost pricing loaded from %s (%d instance types)", source, len(p.Instances))
+	return nil
+}
+
+// CurrentPricing returns the active pricing table and its source ("default" when none
+// is configured)
+func CurrentPricing() (*Pricing, string) {
+	active.mu.Lock()
+	defer active.mu.Unlock()
+	if isURL(active.source) && !active.refreshing && time.Since(active.fetchedAt) > pricingRefreshInterval {
+		active.refreshing = true
+		go refreshPricing(active.source)
+	}
+	if active.source == "" {
+		return active.pricing, "default"
+	}
+	return active.pricing, active.source
+}
+
+// refreshPricing re-fetches a remote pricing table, keeping the previous one on failure
+func refreshPricing(source string) {
+	p, err := LoadPricing(context.Background(), source)
+
+	active.mu.Lock()
+	defer active.mu.Unlock()
+	active.refreshing = false
+	if active.source != source {
+		return
+	}
+	if err != nil {
+		log.Printf("Warning: failed to refresh cost pricing: %v", err)
+		active.fetchedAt = time.Now() // Retry on the next interval rather than every request
+		return
+	}
+	active.pricing, active.fetchedAt = p, time.Now()
+}
@@ -0,0 +1,187 @@
+package drain
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func testPod(name, ownerKind string, mutate ...func(*corev1.Pod)) *corev1.Pod {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop", UID: types.UID("uid-" + name)}}
+	if ownerKind != "" {
+		controller := true
+		pod.OwnerReferences = []metav1.OwnerReference{{Kind: ownerKind, Name: name + "-owner", Controller: &controller}}
+	}
+	for _, m := range mutate {
+		m(pod)
+	}
+	return pod
+}
+
+func TestNewPlan(t *testing.T) {
+	pods := []*corev1.Pod{
+		testPod("web", "ReplicaSet"),
+		testPod("agent", "DaemonSet"),
+		testPod("etcd", "Node", func(p *corev1.Pod) {
+			p.Annotations = map[string]string{mirrorPodAnnotation: "abc"}
+		}),
+		testPod("scratch", "ReplicaSet", func(p *corev1.Pod) {
+			p.Spec.Volumes = []corev1.Volume{{Name: "tmp", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}}
+		}),
+		testPod("bare", ""),
+		testPod("job-done", "", func(p *corev1.Pod) { p.Status.Phase = corev1.PodSucceeded }),
+	}
+
+	plan := NewPlan("node-1", pods, PlanOptions{})
+	var evict []string
+	for _, p := range plan.Evict {
+		evict = append(evict, p.Name)
+	}
+	if want := []string{"web", "scratch", "bare", "job-done"}; !reflect.DeepEqual(evict, want) {
+		t.Errorf("Evict = %v, want %v", evict, want)
+	}
+	if len(plan.Skipped) != 2 || plan.Skipped[0].Name != "agent" || plan.Skipped[1].Name != "etcd" {
+		t.Errorf("Skipped = %+v, want the DaemonSet and mirror pods", plan.Skipped)
+	}
+	if len(plan.Problems) != 2 || !strings.Contains(plan.Problems[0], "emptyDir") || !strings.Contains(plan.Problems[1], "no controller") {
+		t.Errorf("Problems = %v, want emptyDir and bare pod", plan.Problems)
+	}
+
+	if forced := NewPlan("node-1", pods, PlanOptions{Force: true, DeleteEmptyDirData: true}); len(forced.Problems) != 0 {
+		t.Errorf("Problems with force = %v, want none", forced.Problems)
+	}
+}
+
+// fakeCluster blocks evictions a set number of times and removes pods after some polls
+type fakeCluster struct {
+	blocks    map[string]int // Evictions refused before one is accepted
+	lingers   map[string]int // Exists checks that still find the pod after eviction
+	fail      string         // Pod whose eviction fails outright
+	cordoned  bool
+	evictions []string
+}
+
+func (f *fakeCluster) Cordon(ctx context.Context, node string) error {
+	f.cordoned = true
+	return nil
+}
+
+func (f *fakeCluster) Evict(ctx context.Context, p Pod) error {
+	if p.Name == f.fail {
+		return errors.New("forbidden")
+	}
+	if f.blocks[p.Name] > 0 {
+		f.blocks[p.Name]--
+		return ErrBlocked
+	}
+	f.evictions = append(f.evictions, p.Name)
+	return nil
+}
+
+func (f *fakeCluster) Exists(p Pod) (bool, error) {
+	if f.lingers[p.Name] > 0 {
+		f.lingers[p.Name]--
+		return true, nil
+	}
+	return false, nil
+}
+
+func runEvents(t *testing.T, c Cluster, plan *Plan, opts Options) ([]Event, error) {
+	t.Helper()
+	var events []Event
+	err := Run(context.Background(), c, plan, opts, func(e Event) error {
+		events = append(events, e)
+		return nil
+	})
+	return events, err
+}
+
+func eventTypes(events []Event) string {
+	var types []string
+	for _, e := range events {
+		name := e.Type
+		if e.Pod != nil {
+			name += ":" + e.Pod.Name
+		}
+		types = append(types, name)
+	}
+	return strings.Join(types, " ")
+}
+
+func TestRunWaitsForBlockedPods(t *testing.T) {
+	plan := &Plan{Node: "node-1", Evict: []Pod{{Namespace: "shop", Name: "a"}, {Namespace: "shop", Name: "b"}}}
+	cluster := &fakeCluster{blocks: map[string]int{"b": 2}, lingers: map[string]int{"a": 1}}
+
+	events, err := runEvents(t, cluster, plan, Options{PollInterval: time.Millisecond, StuckAfter: time.Hour})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !cluster.cordoned {
+		t.Error("node wasn't cordoned")
+	}
+	want := "plan cordoned evicted:a blocked:b deleted:a evicted:b deleted:b done"
+	if got := eventTypes(events); got != want {
+		t.Errorf("events:\n got %s\nwant %s", got, want)
+	}
+	if last := events[len(events)-1]; !last.Completed {
+		t.Errorf("done event = %+v, want completed", last)
+	}
+}
+
+func TestRunReportsStuckAndTimesOut(t *testing.T) {
+	plan := &Plan{Node: "node-1", Evict: []Pod{{Namespace: "shop", Name: "a"}}}
+	cluster := &fakeCluster{blocks: map[string]int{"a": 1 << 30}}
+
+	events, err := runEvents(t, cluster, plan, Options{PollInterval: time.Millisecond, StuckAfter: time.Nanosecond, Timeout: 20 * time.Millisecond})
+	if err == nil {
+		t.Fatal("expected a timeout")
+	}
+	if got := eventTypes(events); !strings.HasPrefix(got, "plan cordoned blocked:a stuck:a done") {
+		t.Errorf("events = %s, want blocked, stuck once, then done", got)
+	}
+	if last := events[len(events)-1]; last.Completed || last.Remaining != 1 {
+		t.Errorf("done event = %+v, want 1 remaining", last)
+	}
+}
+
+func TestRunHaltsOnFailure(t *testing.T) {
+	plan := &Plan{Node: "node-1", Evict: []Pod{{Namespace: "shop", Name: "a"}, {Namespace: "shop", Name: "b"}}}
+	cluster := &fakeCluster{fail: "a"}
+
+	events, err := runEvents(t, cluster, plan, Options{PollInterval: time.Millisecond})
+	if err == nil {
+		t.Fatal("expected the failed eviction to halt the drain")
+	}
+	if got := eventTypes(events); got != "plan cordoned failed:a done" {
+		t.Errorf("events = %s", got)
+	}
+	if len(cluster.evictions) != 0 {
+		t.Errorf("evicted %v after the failure", cluster.evictions)
+	}
+}
+
+func TestRunCallerDeadlineIsNotATimeout(t *testing.T) {
+	plan := &Plan{Node: "node-1", Evict: []Pod{{Namespace: "shop", Name: "a"}}}
+	cluster := &fakeCluster{blocks: map[string]int{"a": 1 << 30}}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	var last Event
+	err := Run(ctx, cluster, plan, Options{PollInterval: time.Millisecond, StuckAfter: time.Hour}, func(e Event) error {
+		last = e
+		return nil
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Run = %v, want the caller's deadline", err)
+	}
+	if strings.Contains(last.Message, "timed out") {
+		t.Errorf("last event = %+v, the drain's own timeout didn't pass", last)
+	}
+}
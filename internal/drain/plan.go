@@ -0,0 +1,82 @@
+package drain
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// mirrorPodAnnotation marks static pods the kubelet mirrors into the API
+const mirrorPodAnnotation = "kubernetes.io/config.mirror"
+
+// Pod identifies a pod on the node being drained
+type Pod struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Owner     string `json:"owner,omitempty"` // Kind/name of the controller
+	UID       string `json:"-"`               // Tells the evicted pod apart from a replacement with its name
+}
+
+func (p Pod) String() string { return p.Namespace + "/" + p.Name }
+
+// SkippedPod is a pod the drain leaves in place
+type SkippedPod struct {
+	Pod
+	Reason string `json:"reason"`
+}
+
+// PlanOptions loosen which pods a drain may evict, like kubectl drain's flags
+type PlanOptions struct {
+	Force              bool // Evict pods without a controller, which won't be recreated
+	DeleteEmptyDirData bool // Evict pods with emptyDir volumes, losing their data
+}
+
+// Plan is what a drain would do. Problems are pods that block it unless the matching
+// option is set.
+type Plan struct {
+	Node     string       `json:"node"`
+	Evict    []Pod        `json:"evict"`
+	Skipped  []SkippedPod `json:"skipped,omitempty"`
+	Problems []string     `json:"problems,omitempty"`
+}
+
+// NewPlan sorts the node's pods into those to evict and those to skip: mirror pods are
+// owned by the kubelet and DaemonSet pods would be recreated on the cordoned node anyway.
+// Finished pods are always evicted, since nothing is lost.
+func NewPlan(node string, pods []*corev1.Pod, opts PlanOptions) *Plan {
+	plan := &Plan{Node: node, Evict: make([]Pod, 0, len(pods))}
+	for _, pod := range pods {
+		p := Pod{Namespace: pod.Namespace, Name: pod.Name, UID: string(pod.UID)}
+		owner := metav1.GetControllerOf(pod)
+		if owner != nil {
+			p.Owner = owner.Kind + "/" + owner.Name
+		}
+		finished := pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+
+		switch {
+		case pod.Annotations[mirrorPodAnnotation] != "":
+			plan.Skipped = append(plan.Skipped, SkippedPod{Pod: p, Reason: "mirror pod managed by the kubelet"})
+			continue
+		case owner != nil && owner.Kind == "DaemonSet":
+			plan.Skipped = append(plan.Skipped, SkippedPod{Pod: p, Reason: "DaemonSet pod"})
+			continue
+		case finished:
+		case owner == nil && !opts.Force:
+			plan.Problems = append(plan.Problems, fmt.Sprintf("%s has no controller and won't be recreated (set force)", p))
+		case hasEmptyDir(pod) && !opts.DeleteEmptyDirData:
+			plan.Problems = append(plan.Problems, fmt.Sprintf("%s has emptyDir data that would be lost (set deleteEmptyDirData)", p))
+		}
+		plan.Evict = append(plan.Evict, p)
+	}
+	return plan
+}
+
+func hasEmptyDir(pod *corev1.Pod) bool {
+	for _, v := range pod.Spec.Volumes {
+		if v.EmptyDir != nil {
+			return true
+		}
+	}
+	return false
+}
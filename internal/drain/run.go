@@ -0,0 +1,187 @@
+package drain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultTimeout is how long a drain may take before it gives up on the remaining pods
+	DefaultTimeout = 30 * time.Minute
+	// DefaultStuckAfter is how long a pod may stay blocked or terminating before it's reported stuck
+	DefaultStuckAfter = 2 * time.Minute
+	// defaultPollInterval is how often blocked evictions are retried and terminations checked
+	defaultPollInterval = 5 * time.Second
+)
+
+// Event types streamed while a drain progresses
+const (
+	EventPlan     = "plan"     // Plan: the pods to evict and skip
+	EventCordoned = "cordoned" // The node is unschedulable
+	EventEvicted  = "evicted"  // Pod: eviction accepted, waiting for it to terminate
+	EventBlocked  = "blocked"  // Pod: a PodDisruptionBudget refused the eviction; retried until it allows it
+	EventDeleted  = "deleted"  // Pod: gone from the node
+	EventStuck    = "stuck"    // Pod: still blocked or terminating after StuckAfter
+	EventFailed   = "failed"   // Pod: eviction failed (the drain halts)
+	EventDone     = "done"     // Completed, halted or timed out
+)
+
+// Event reports drain progress
+type Event struct {
+	Type      string `json:"type"`
+	Pod       *Pod   `json:"pod,omitempty"`
+	Plan      *Plan  `json:"plan,omitempty"`
+	Message   string `json:"message,omitempty"`
+	Remaining int    `json:"remaining,omitempty"` // Pods not yet gone
+	Completed bool   `json:"completed,omitempty"` // Done: every pod was evicted
+}
+
+// ErrBlocked is wrapped by Cluster.Evict errors when a PodDisruptionBudget refuses the eviction
+var ErrBlocked = errors.New("eviction would violate a PodDisruptionBudget")
+
+// errTimedOut is the cause of a drain's context ending at Options.Timeout
+var errTimedOut = errors.New("drain timed out")
+
+// Cluster carries out a drain
+type Cluster interface {
+	// Cordon marks the node unschedulable
+	Cordon(ctx context.Context, node string) error
+	// Evict requests the pod's eviction. A pod that's already gone isn't an error.
+	Evict(ctx context.Context, p Pod) error
+	// Exists reports whether the pod (not a replacement with its name) still exists
+	Exists(p Pod) (bool, error)
+}
+
+// Options tune a drain
+type Options struct {
+	Timeout      time.Duration // Default DefaultTimeout
+	StuckAfter   time.Duration // Default DefaultStuckAfter
+	PollInterval time.Duration // Default 5s
+}
+
+// podState tracks one pod through the drain
+type podState struct {
+	pod          Pod
+	evictedAt    time.Time // Zero until the eviction is accepted
+	blockedSince time.Time // Zero unless the last eviction attempt was blocked
+	stuck        bool      // Stuck was reported
+}
+
+// Run cordons the node, then evicts the plan's pods and waits for them to terminate.
+// Evictions a PodDisruptionBudget blocks are retried every PollInterval, so pods leave as
+// their budgets allow. The drain halts at the first eviction that fails for another reason
+// and gives up after Timeout; the node stays cordoned either way. emit receives progress;
+// an emit error (the client went away) stops the drain.
+func Run(ctx context.Context, c Cluster, plan *Plan, opts Options, emit func(Event) error) error {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.StuckAfter <= 0 {
+		opts.StuckAfter = DefaultStuckAfter
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaultPollInterval
+	}
+	if err := emit(Event{Type: EventPlan, Plan: plan}); err != nil {
+		return err
+	}
+
+	if err := c.Cordon(ctx, plan.Node); err != nil {
+		emit(Event{Type: EventDone, Message: fmt.Sprintf("cordoning %s: %v", plan.Node, err)})
+		return fmt.Errorf("cordoning %s: %w", plan.Node, err)
+	}
+	if err := emit(Event{Type: EventCordoned, Message: plan.Node + " is unschedulable"}); err != nil {
+		return err
+	}
+
+	// The cause tells the drain's own timeout from the caller's context ending
+	ctx, cancel := context.WithTimeoutCause(ctx, opts.Timeout, errTimedOut)
+	defer cancel()
+	ticker := time.NewTicker(opts.PollInterval)
+	defer ticker.Stop()
+
+	pending := make([]*podState, len(plan.Evict))
+	for i, p := range plan.Evict {
+		pending[i] = &podState{pod: p}
+	}
+	for {
+		var still []*podState
+		for _, s := range pending {
+			gone, err := step(ctx, c, s, opts, len(pending), emit)
+			if err != nil {
+				emit(Event{Type: EventDone, Remaining: len(pending), Message: fmt.Sprintf("halted: %v", err)})
+				return err
+			}
+			if !gone {
+				still = append(still, s)
+			}
+		}
+		pending = still
+		if len(pending) == 0 {
+			return emit(Event{Type: EventDone, Completed: true, Message: fmt.Sprintf("%s drained: %d pods evicted", plan.Node, len(plan.Evict))})
+		}
+
+		select {
+		case <-ctx.Done():
+			if context.Cause(ctx) == errTimedOut {
+				emit(Event{Type: EventDone, Remaining: len(pending), Message: fmt.Sprintf("timed out after %s with %d pods remaining", opts.Timeout, len(pending))})
+				return fmt.Errorf("drain of %s timed out with %d pods remaining", plan.Node, len(pending))
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// step advances one pod: requests its eviction until one is accepted, then waits for it
+// to go. It returns whether the pod is gone.
+func step(ctx context.Context, c Cluster, s *podState, opts Options, remaining int, emit func(Event) error) (bool, error) {
+	p := s.pod
+	if s.evictedAt.IsZero() {
+		err := c.Evict(ctx, p)
+		switch {
+		case err == nil:
+			s.evictedAt, s.blockedSince, s.stuck = time.Now(), time.Time{}, false
+			if err := emit(Event{Type: EventEvicted, Pod: &p, Remaining: remaining}); err != nil {
+				return false, err
+			}
+		case errors.Is(err, ErrBlocked):
+			if s.blockedSince.IsZero() {
+				s.blockedSince = time.Now()
+				if err := emit(Event{Type: EventBlocked, Pod: &p, Remaining: remaining, Message: err.Error()}); err != nil {
+					return false, err
+				}
+			}
+			if !s.stuck && time.Since(s.blockedSince) >= opts.StuckAfter {
+				s.stuck = true
+				msg := fmt.Sprintf("blocked by a PodDisruptionBudget for %s", time.Since(s.blockedSince).Round(time.Second))
+				if err := emit(Event{Type: EventStuck, Pod: &p, Remaining: remaining, Message: msg}); err != nil {
+					return false, err
+				}
+			}
+			return false, nil
+		default:
+			emit(Event{Type: EventFailed, Pod: &p, Remaining: remaining, Message: err.Error()})
+			return false, fmt.Errorf("evicting %s: %w", p, err)
+		}
+	}
+
+	exists, err := c.Exists(p)
+	if err != nil {
+		emit(Event{Type: EventFailed, Pod: &p, Remaining: remaining, Message: err.Error()})
+		return false, fmt.Errorf("%s: %w", p, err)
+	}
+	if !exists {
+		return true, emit(Event{Type: EventDeleted, Pod: &p, Remaining: remaining - 1})
+	}
+	if !s.stuck && time.Since(s.evictedAt) >= opts.StuckAfter {
+		s.stuck = true
+		msg := fmt.Sprintf("still terminating after %s", time.Since(s.evictedAt).Round(time.Second))
+		if err := emit(Event{Type: EventStuck, Pod: &p, Remaining: remaining, Message: msg}); err != nil {
+			return false, err
+		}
+	}
+	return false, nil
+}
@@ -5,6 +5,7 @@ package errors
 import (
 	"errors"
 	"fmt"
+	"net/http"
 )
 
 // ErrorCode represents a unique identifier for error types.
@@ -23,6 +24,13 @@ const (
 	ErrK8sResourceNotFound     ErrorCode = 1003
 	ErrK8sAPIError             ErrorCode = 1004
 	ErrK8sClusterUnreachable   ErrorCode = 1005
+	ErrK8sForbidden            ErrorCode = 1006 // RBAC denied the request
+	ErrK8sUnauthorized         ErrorCode = 1007 // Credentials rejected or expired
+	ErrK8sConflict             ErrorCode = 1008 // Object changed since it was read
+	ErrK8sAlreadyExists        ErrorCode = 1009
+	ErrK8sInvalid              ErrorCode = 1010 // API server rejected the object
+	ErrK8sTimeout              ErrorCode = 1011
+	ErrK8sThrottled            ErrorCode = 1012
 
 	// Server/HTTP errors (2xxx)
 	ErrBadRequest         ErrorCode = 2001
@@ -31,12 +39,18 @@ const (
 	ErrValidation         ErrorCode = 2004
 	ErrServiceUnavailable ErrorCode = 2005
 	ErrMarshalFailed      ErrorCode = 2006
+	ErrForbidden          ErrorCode = 2007 // Disabled feature, insufficient share/owner token or API token scope
+	ErrConflict           ErrorCode = 2008
+	ErrUnprocessable      ErrorCode = 2009 // Well-formed but rejected (e.g. policy violation)
+	ErrUnauthorized       ErrorCode = 2010
+	ErrPolicyViolation    ErrorCode = 2011 // Rejected by an enforced resource policy
 
 	// Cache errors (3xxx)
 	ErrCacheNotInitialized  ErrorCode = 3001
 	ErrCacheSyncFailed      ErrorCode = 3002
 	ErrCacheHandlerFailed   ErrorCode = 3003
 	ErrCacheDynamicNotFound ErrorCode = 3004
+	ErrCacheNotSynced       ErrorCode = 3005 // Informer still doing its initial list
 
 	// Timeline/storage errors (4xxx)
 	ErrTimelineStoreNotInit ErrorCode = 4001
@@ -64,6 +78,20 @@ func (c ErrorCode) String() string {
 		return "K8S_API_ERROR"
 	case ErrK8sClusterUnreachable:
 		return "K8S_CLUSTER_UNREACHABLE"
+	case ErrK8sForbidden:
+		return "K8S_FORBIDDEN"
+	case ErrK8sUnauthorized:
+		return "K8S_UNAUTHORIZED"
+	case ErrK8sConflict:
+		return "K8S_CONFLICT"
+	case ErrK8sAlreadyExists:
+		return "K8S_ALREADY_EXISTS"
+	case ErrK8sInvalid:
+		return "K8S_INVALID"
+	case ErrK8sTimeout:
+		return "K8S_TIMEOUT"
+	case ErrK8sThrottled:
+		return "K8S_THROTTLED"
 	// Server errors
 	case ErrBadRequest:
 		return "BAD_REQUEST"
@@ -77,6 +105,16 @@ func (c ErrorCode) String() string {
 		return "SERVICE_UNAVAILABLE"
 	case ErrMarshalFailed:
 		return "MARSHAL_FAILED"
+	case ErrForbidden:
+		return "FORBIDDEN"
+	case ErrConflict:
+		return "CONFLICT"
+	case ErrUnprocessable:
+		return "UNPROCESSABLE"
+	case ErrUnauthorized:
+		return "UNAUTHORIZED"
+	case ErrPolicyViolation:
+		return "POLICY_VIOLATION"
 	// Cache errors
 	case ErrCacheNotInitialized:
 		return "CACHE_NOT_INITIALIZED"
@@ -86,6 +124,8 @@ func (c ErrorCode) String() string {
 		return "CACHE_HANDLER_FAILED"
 	case ErrCacheDynamicNotFound:
 		return "CACHE_DYNAMIC_NOT_FOUND"
+	case ErrCacheNotSynced:
+		return "CACHE_NOT_SYNCED"
 	// Timeline errors
 	case ErrTimelineStoreNotInit:
 		return "TIMELINE_STORE_NOT_INITIALIZED"
@@ -107,6 +147,71 @@ func (c ErrorCode) String() string {
 	}
 }
 
+// HTTPStatus returns the HTTP status code API responses use for this error code.
+func (c ErrorCode) HTTPStatus() int {
+	switch c {
+	case ErrBadRequest, ErrValidation, ErrK8sInvalid:
+		return http.StatusBadRequest
+	case ErrUnauthorized, ErrK8sUnauthorized:
+		return http.StatusUnauthorized
+	case ErrForbidden, ErrK8sForbidden:
+		return http.StatusForbidden
+	case ErrNotFound, ErrK8sResourceNotFound, ErrHelmReleaseNotFound, ErrCacheDynamicNotFound:
+		return http.StatusNotFound
+	case ErrConflict, ErrK8sConflict, ErrK8sAlreadyExists:
+		return http.StatusConflict
+	case ErrUnprocessable, ErrPolicyViolation:
+		return http.StatusUnprocessableEntity
+	case ErrK8sThrottled:
+		return http.StatusTooManyRequests
+	case ErrK8sTimeout:
+		return http.StatusGatewayTimeout
+	case ErrK8sClusterUnreachable:
+		return http.StatusBadGateway
+	case ErrServiceUnavailable, ErrCacheNotInitialized, ErrCacheNotSynced,
+		ErrK8sClientNotInitialized, ErrTimelineStoreNotInit, ErrHelmClientNotInit:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Hint returns a remediation suggestion for the error code, or "" if there is none.
+func (c ErrorCode) Hint() string {
+	switch c {
+	case ErrK8sForbidden:
+		return "Your Kubernetes identity lacks RBAC permission for this action. Check with `kubectl auth can-i` and ask a cluster admin for access."
+	case ErrK8sUnauthorized:
+		return "The cluster rejected your credentials. Re-authenticate (e.g. refresh your cloud CLI login) and retry."
+	case ErrK8sConflict:
+		return "The resource changed since it was loaded. Reload it and reapply your change."
+	case ErrK8sAlreadyExists:
+		return "A resource with this name already exists. Choose another name or edit the existing resource."
+	case ErrK8sInvalid:
+		return "The API server rejected the object. Check the field errors in the message."
+	case ErrK8sTimeout, ErrK8sClusterUnreachable:
+		return "The API server did not respond. Check cluster connectivity (VPN, kubeconfig endpoint) and retry."
+	case ErrK8sThrottled:
+		return "The API server is throttling requests. Wait a moment and retry."
+	case ErrK8sClientNotInitialized:
+		return "Radar is not connected to a cluster yet. Check the kubeconfig and current context."
+	case ErrCacheNotInitialized:
+		return "The resource cache is starting or being rebuilt after a context switch. Retry in a few seconds."
+	case ErrCacheNotSynced:
+		return "Radar is still loading this resource type from the cluster. Retry in a few seconds."
+	case ErrTimelineStoreNotInit:
+		return "The timeline store failed to start. Check the server logs and --timeline-storage settings."
+	case ErrHelmClientNotInit:
+		return "The Helm client failed to initialize. Check the server logs and kubeconfig."
+	case ErrHelmReleaseNotFound:
+		return "The release may have been uninstalled or lives in another namespace."
+	case ErrPolicyViolation:
+		return "Fix the listed violations or ask an admin to adjust the resource policy (GET /api/policy)."
+	default:
+		return ""
+	}
+}
+
 // ExplorerError is a structured error type with error codes.
 type ExplorerError struct {
 	Code    ErrorCode
@@ -195,6 +300,17 @@ func CacheNotInitialized() *ExplorerError {
 	return New(ErrCacheNotInitialized, "resource cache not initialized")
 }
 
+// CacheNotSynced returns an error when a resource type's informer hasn't finished its initial list.
+func CacheNotSynced(resource string) *ExplorerError {
+	return New(ErrCacheNotSynced, fmt.Sprintf("%s cache is still syncing", resource)).
+		WithDetail("resource", resource)
+}
+
+// HelmClientNotInitialized returns an error when the Helm client isn't ready.
+func HelmClientNotInitialized() *ExplorerError {
+	return New(ErrHelmClientNotInit, "Helm client not initialized")
+}
+
 // ValidationError returns an error for invalid input.
 func ValidationError(message string) *ExplorerError {
 	return New(ErrValidation, message)
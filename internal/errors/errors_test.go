@@ -0,0 +1,53 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestClassify(t *testing.T) {
+	gr := schema.GroupResource{Group: "apps", Resource: "deployments"}
+	cases := []struct {
+		err  error
+		want ErrorCode
+	}{
+		{apierrors.NewForbidden(gr, "api", fmt.Errorf("no RBAC")), ErrK8sForbidden},
+		{fmt.Errorf("failed to delete resource: %w", apierrors.NewNotFound(gr, "api")), ErrK8sResourceNotFound},
+		{apierrors.NewConflict(gr, "api", fmt.Errorf("modified")), ErrK8sConflict},
+		{CacheNotSynced("widgets"), ErrCacheNotSynced},
+		{fmt.Errorf("resource not found: default/api"), ErrNotFound},
+		{fmt.Errorf("boom"), ErrInternalServer},
+	}
+	for _, tc := range cases {
+		if got := Classify(tc.err).Code; got != tc.want {
+			t.Errorf("Classify(%v) = %s, want %s", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestWriteIncludesCorrelationID(t *testing.T) {
+	handler := CorrelationMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Write(w, apierrors.NewForbidden(schema.GroupResource{Resource: "secrets"}, "db", fmt.Errorf("denied")))
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(CorrelationHeader, "req-123")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want 403", rec.Code)
+	}
+	var body APIError
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body.Code != "K8S_FORBIDDEN" || body.CorrelationID != "req-123" || body.Hint == "" {
+		t.Errorf("unexpected body: %+v", body)
+	}
+}
@@ -0,0 +1,154 @@
+package errors
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// CorrelationHeader carries the per-request correlation ID. It is accepted from the
+// client (so callers can tag requests) and always echoed on the response.
+const CorrelationHeader = "X-Request-ID"
+
+// APIError is the JSON body of every API error response. Clients should branch on
+// Code, which is stable; Error is for display and may change.
+type APIError struct {
+	Error         string         `json:"error"`
+	Code          string         `json:"code"`
+	Status        int            `json:"status"`
+	Hint          string         `json:"hint,omitempty"`
+	CorrelationID string         `json:"correlationId,omitempty"`
+	Details       map[string]any `json:"details,omitempty"`
+}
+
+// CorrelationMiddleware assigns each request a correlation ID, echoed in the
+// X-Request-ID response header and in error bodies, and logged with every error.
+func CorrelationMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(CorrelationHeader)
+		if id == "" || len(id) > 64 || strings.ContainsAny(id, " \t\r\n") {
+			id = newCorrelationID()
+		}
+		w.Header().Set(CorrelationHeader, id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), correlationKey{}, id)))
+	})
+}
+
+type correlationKey struct{}
+
+// CorrelationID returns the request's correlation ID ("" outside CorrelationMiddleware).
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationKey{}).(string)
+	return id
+}
+
+func newCorrelationID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Classify converts any error into an ExplorerError, recognizing Kubernetes API
+// status errors (RBAC denied, not found, conflicts, ...) and network failures.
+func Classify(err error) *ExplorerError {
+	var explorerErr *ExplorerError
+	if errors.As(err, &explorerErr) {
+		return explorerErr
+	}
+	msg := err.Error()
+	switch {
+	case apierrors.IsForbidden(err):
+		return Wrap(ErrK8sForbidden, msg, err)
+	case apierrors.IsUnauthorized(err):
+		return Wrap(ErrK8sUnauthorized, msg, err)
+	case apierrors.IsNotFound(err):
+		return Wrap(ErrK8sResourceNotFound, msg, err)
+	case apierrors.IsAlreadyExists(err):
+		return Wrap(ErrK8sAlreadyExists, msg, err)
+	case apierrors.IsConflict(err):
+		return Wrap(ErrK8sConflict, msg, err)
+	case apierrors.IsInvalid(err), apierrors.IsBadRequest(err):
+		return Wrap(ErrK8sInvalid, msg, err)
+	case apierrors.IsTooManyRequests(err):
+		return Wrap(ErrK8sThrottled, msg, err)
+	case apierrors.IsTimeout(err), apierrors.IsServerTimeout(err), errors.Is(err, context.DeadlineExceeded):
+		return Wrap(ErrK8sTimeout, msg, err)
+	case apierrors.IsServiceUnavailable(err):
+		return Wrap(ErrK8sClusterUnreachable, msg, err)
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return Wrap(ErrK8sClusterUnreachable, msg, err)
+	}
+	// Plain errors from cache lookups and discovery ("resource not found: ns/name")
+	if strings.Contains(msg, "not found") {
+		return Wrap(ErrNotFound, msg, err)
+	}
+	return Wrap(ErrInternalServer, msg, err)
+}
+
+// codeForStatus is the generic code for handlers that only report an HTTP status
+func codeForStatus(status int) ErrorCode {
+	switch status {
+	case http.StatusBadRequest:
+		return ErrBadRequest
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	case http.StatusForbidden:
+		return ErrForbidden
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusConflict:
+		return ErrConflict
+	case http.StatusUnprocessableEntity:
+		return ErrUnprocessable
+	case http.StatusServiceUnavailable:
+		return ErrServiceUnavailable
+	default:
+		return ErrInternalServer
+	}
+}
+
+// Write classifies err and writes it as an APIError response.
+func Write(w http.ResponseWriter, err error) {
+	e := Classify(err)
+	// Show the cause for classified errors; the message alone for constructed ones
+	message := e.Message
+	if e.Cause != nil && !strings.Contains(message, e.Cause.Error()) {
+		message = e.Message + ": " + e.Cause.Error()
+	}
+	writeAPIError(w, e.Code.HTTPStatus(), APIError{
+		Error:   message,
+		Code:    e.Code.String(),
+		Hint:    e.Code.Hint(),
+		Details: e.Details,
+	})
+}
+
+// WriteHTTP writes an APIError for handlers that choose the status themselves.
+func WriteHTTP(w http.ResponseWriter, status int, message string) {
+	code := codeForStatus(status)
+	writeAPIError(w, status, APIError{Error: message, Code: code.String(), Hint: code.Hint()})
+}
+
+func writeAPIError(w http.ResponseWriter, status int, body APIError) {
+	body.Status = status
+	body.CorrelationID = w.Header().Get(CorrelationHeader)
+	if body.CorrelationID != "" {
+		log.Printf("API error [%s] %d %s: %s", body.CorrelationID, status, body.Code, body.Error)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("Failed to encode error response: %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package execaudit
+
+import (
+	"context"
+	"log"
+	"sync"
+)
+
+// Config selects where sessions are recorded
+type Config struct {
+	Sinks       []string // Sink specs, see ParseSink
+	RecordInput bool     // Also record keystrokes, which can include secrets typed at prompts
+}
+
+type state struct {
+	sinks       []Sink
+	recordInput bool
+}
+
+var (
+	mu      sync.RWMutex
+	active  state
+	pending sync.WaitGroup // Recordings being delivered to sinks
+)
+
+func current() state {
+	mu.RLock()
+	defer mu.RUnlock()
+	return active
+}
+
+// Configure opens the configured sinks, replacing any previous ones
+func Configure(cfg Config) error {
+	var sinks []Sink
+	for _, spec := range cfg.Sinks {
+		sink, err := ParseSink(spec)
+		if err != nil {
+			for _, s := range sinks {
+				s.Close()
+			}
+			return err
+		}
+		sinks = append(sinks, sink)
+	}
+	mu.Lock()
+	old := active
+	active = state{sinks: sinks, recordInput: cfg.RecordInput}
+	mu.Unlock()
+	for _, s := range old.sinks {
+		s.Close()
+	}
+	for _, s := range sinks {
+		log.Printf("Recording terminal sessions to %s", s.Name())
+	}
+	return nil
+}
+
+// Enabled reports whether terminal sessions are being recorded
+func Enabled() bool {
+	return len(current().sinks) > 0
+}
+
+// Flush waits for finished recordings to reach their sinks, then closes the sinks
+func Flush(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		pending.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		log.Printf("Warning: exec recordings still being delivered at shutdown: %v", ctx.Err())
+	}
+	mu.Lock()
+	old := active
+	active = state{}
+	mu.Unlock()
+	for _, s := range old.sinks {
+		s.Close()
+	}
+}
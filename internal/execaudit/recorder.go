@@ -0,0 +1,173 @@
+// Package execaudit records terminal sessions (pod exec and node shells): who opened them,
+// against which pod, when, and an asciicast v2 transcript, delivered to pluggable sinks
+// when each session ends.
+package execaudit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// maxCastBytes caps a transcript held in memory; later output is dropped and the
+// recording marked truncated
+const maxCastBytes = 16 << 20
+
+// Session describes a recorded terminal session
+type Session struct {
+	ID           string    `json:"id"`
+	Type         string    `json:"type"` // "exec", "debug" or "node-shell"
+	User         string    `json:"user"` // Radar actor: API token, Kubernetes user or "local"
+	Remote       string    `json:"remote"`
+	Context      string    `json:"context"`
+	Namespace    string    `json:"namespace"`
+	Pod          string    `json:"pod"`
+	Container    string    `json:"container,omitempty"`
+	Node         string    `json:"node,omitempty"` // Node shells only
+	Command      []string  `json:"command"`
+	StartedAt    time.Time `json:"startedAt"`
+	EndedAt      time.Time `json:"endedAt"`
+	Participants []string  `json:"participants,omitempty"` // Share-link participants who joined
+	Truncated    bool      `json:"truncated,omitempty"`
+}
+
+// Recording is a finished session with its asciicast v2 transcript
+type Recording struct {
+	Session
+	Cast []byte `json:"-"`
+}
+
+// Recorder captures one session. A nil Recorder (recording disabled) ignores every call.
+type Recorder struct {
+	mu          sync.Mutex
+	session     Session
+	recordInput bool
+	cast        []byte
+	lastInputBy string
+	done        bool
+}
+
+// Start begins recording a session, or returns nil when no sinks are configured
+func Start(s Session) *Recorder {
+	cfg := current()
+	if len(cfg.sinks) == 0 {
+		return nil
+	}
+	s.ID = newSessionID()
+	s.StartedAt = time.Now()
+	r := &Recorder{session: s, recordInput: cfg.recordInput}
+
+	header, _ := json.Marshal(map[string]any{
+		"version":   2,
+		"width":     80,
+		"height":    24,
+		"timestamp": s.StartedAt.Unix(),
+		"title":     fmt.Sprintf("%s %s/%s by %s", s.Type, s.Namespace, s.Pod, s.User),
+		"env":       map[string]string{"TERM": "xterm-256color"},
+	})
+	r.cast = append(header, '\n')
+	return r
+}
+
+// event appends an asciicast event line; callers hold r.mu
+func (r *Recorder) event(code, data string) {
+	if r.done || r.session.Truncated {
+		return
+	}
+	line, _ := json.Marshal([]any{time.Since(r.session.StartedAt).Seconds(), code, data})
+	if len(r.cast)+len(line) > maxCastBytes {
+		r.session.Truncated = true
+		return
+	}
+	r.cast = append(append(r.cast, line...), '\n')
+}
+
+// Output records terminal output
+func (r *Recorder) Output(p []byte) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.event("o", string(p))
+}
+
+// Input records keystrokes from the owner ("" for by) or a share participant, when input
+// recording is enabled. A marker names whoever starts typing.
+func (r *Recorder) Input(data, by string) {
+	if r == nil || !r.recordInput {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if by == "" {
+		by = r.session.User
+	}
+	if by != r.lastInputBy {
+		r.event("m", "input: "+by)
+		r.lastInputBy = by
+	}
+	r.event("i", data)
+}
+
+// Resize records a terminal size change
+func (r *Recorder) Resize(cols, rows uint16) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.event("r", fmt.Sprintf("%dx%d", cols, rows))
+}
+
+// Joined records a share-link participant joining
+func (r *Recorder) Joined(name string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.session.Participants = append(r.session.Participants, name)
+	r.event("m", "joined: "+name)
+}
+
+// Finish ends the recording and delivers it to every sink in the background (see Flush)
+func (r *Recorder) Finish() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	if r.done {
+		r.mu.Unlock()
+		return
+	}
+	r.done = true
+	r.session.EndedAt = time.Now()
+	rec := &Recording{Session: r.session, Cast: r.cast}
+	r.mu.Unlock()
+
+	cfg := current()
+	pending.Add(1)
+	go func() {
+		defer pending.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		for _, sink := range cfg.sinks {
+			if err := sink.Store(ctx, rec); err != nil {
+				log.Printf("[audit] Failed to store exec recording %s in %s: %v", rec.ID, sink.Name(), err)
+			}
+		}
+	}()
+}
+
+// newSessionID returns an ID that stays unique across restarts and sorts by start time
+func newSessionID() string {
+	b := make([]byte, 4)
+	rand.Read(b)
+	return time.Now().UTC().Format("20060102T150405Z") + "-" + hex.EncodeToString(b)
+}
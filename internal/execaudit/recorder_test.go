@@ -0,0 +1,215 @@
+package execaudit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func configure(t *testing.T, cfg Config) {
+	t.Helper()
+	if err := Configure(cfg); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+	t.Cleanup(func() { Flush(context.Background()) })
+}
+
+func castEvents(t *testing.T, cast []byte) (map[string]any, [][]any) {
+	t.Helper()
+	sc := bufio.NewScanner(bytes.NewReader(cast))
+	if !sc.Scan() {
+		t.Fatal("empty cast")
+	}
+	var header map[string]any
+	if err := json.Unmarshal(sc.Bytes(), &header); err != nil {
+		t.Fatalf("header: %v", err)
+	}
+	var events [][]any
+	for sc.Scan() {
+		var ev []any
+		if err := json.Unmarshal(sc.Bytes(), &ev); err != nil {
+			t.Fatalf("event %q: %v", sc.Text(), err)
+		}
+		events = append(events, ev)
+	}
+	return header, events
+}
+
+func TestStartDisabled(t *testing.T) {
+	configure(t, Config{})
+	r := Start(Session{Type: "exec", User: "alice"})
+	if r != nil {
+		t.Fatal("expected nil recorder without sinks")
+	}
+	// Nil recorders ignore calls
+	r.Output([]byte("x"))
+	r.Input("x", "")
+	r.Resize(80, 24)
+	r.Joined("bob")
+	r.Finish()
+}
+
+func TestFileSink(t *testing.T) {
+	dir := t.TempDir()
+	configure(t, Config{Sinks: []string{"file:" + dir}})
+
+	r := Start(Session{Type: "exec", User: "alice", Namespace: "default", Pod: "web-1", Command: []string{"/bin/sh"}})
+	r.Output([]byte("$ "))
+	r.Input("ls\r", "")
+	r.Resize(120, 40)
+	r.Joined("bob")
+	r.Finish()
+	r.Finish() // idempotent
+	Flush(context.Background())
+
+	cast, err := os.ReadFile(filepath.Join(dir, r.session.ID+".cast"))
+	if err != nil {
+		t.Fatalf("read cast: %v", err)
+	}
+	header, events := castEvents(t, cast)
+	if header["version"] != float64(2) {
+		t.Errorf("version = %v, want 2", header["version"])
+	}
+	var codes []string
+	for _, ev := range events {
+		codes = append(codes, ev[1].(string))
+	}
+	// Input is not recorded by default
+	if got := strings.Join(codes, ","); got != "o,r,m" {
+		t.Errorf("event codes = %s, want o,r,m", got)
+	}
+
+	meta, err := os.ReadFile(filepath.Join(dir, "sessions.jsonl"))
+	if err != nil {
+		t.Fatalf("read sessions.jsonl: %v", err)
+	}
+	var s Session
+	if err := json.Unmarshal(bytes.TrimSpace(meta), &s); err != nil {
+		t.Fatalf("session: %v", err)
+	}
+	if s.User != "alice" || s.Pod != "web-1" || len(s.Participants) != 1 || s.EndedAt.IsZero() {
+		t.Errorf("unexpected session metadata: %+v", s)
+	}
+}
+
+func TestRecordInput(t *testing.T) {
+	configure(t, Config{Sinks: []string{"file:" + t.TempDir()}, RecordInput: true})
+
+	r := Start(Session{Type: "exec", User: "alice"})
+	r.Input("a", "")
+	r.Input("b", "")
+	r.Input("c", "bob")
+	_, events := castEvents(t, r.cast)
+
+	want := []string{"m:input: alice", "i:a", "i:b", "m:input: bob", "i:c"}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d", len(events), len(want))
+	}
+	for i, ev := range events {
+		if got := ev[1].(string) + ":" + ev[2].(string); got != want[i] {
+			t.Errorf("event %d = %s, want %s", i, got, want[i])
+		}
+	}
+}
+
+func TestTruncation(t *testing.T) {
+	configure(t, Config{Sinks: []string{"file:" + t.TempDir()}})
+
+	r := Start(Session{Type: "exec", User: "alice"})
+	chunk := bytes.Repeat([]byte("x"), 1<<20)
+	for i := 0; i < 20; i++ {
+		r.Output(chunk)
+	}
+	if !r.session.Truncated {
+		t.Error("expected recording to be marked truncated")
+	}
+	if len(r.cast) > maxCastBytes {
+		t.Errorf("cast is %d bytes, cap is %d", len(r.cast), maxCastBytes)
+	}
+}
+
+func TestSQLiteSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.db")
+	configure(t, Config{Sinks: []string{"sqlite:" + path}})
+
+	r := Start(Session{Type: "node-shell", User: "alice", Namespace: "default", Pod: "node-shell-abc", Node: "node-1"})
+	r.Output([]byte("# "))
+	r.Finish()
+	Flush(context.Background())
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	var typ, node string
+	var cast []byte
+	if err := db.QueryRow(`SELECT type, node, transcript FROM exec_sessions WHERE id = ?`, r.session.ID).Scan(&typ, &node, &cast); err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if typ != "node-shell" || node != "node-1" || !bytes.Contains(cast, []byte(`"# "`)) {
+		t.Errorf("unexpected row: type=%s node=%s cast=%s", typ, node, cast)
+	}
+}
+
+func TestObjectSink(t *testing.T) {
+	var mu sync.Mutex
+	objects := map[string]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		objects[r.URL.Path] = string(body)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	configure(t, Config{Sinks: []string{"s3://audit/exec?endpoint=" + srv.URL}})
+
+	r := Start(Session{Type: "exec", User: "alice", Namespace: "default", Pod: "web-1"})
+	r.Output([]byte("$ "))
+	r.Finish()
+	Flush(context.Background())
+
+	mu.Lock()
+	defer mu.Unlock()
+	if cast := objects["/audit/exec/"+r.session.ID+".cast"]; !strings.Contains(cast, `"o","$ "`) {
+		t.Errorf("cast = %q", cast)
+	}
+	var s Session
+	if err := json.Unmarshal([]byte(objects["/audit/exec/"+r.session.ID+".json"]), &s); err != nil || s.Pod != "web-1" {
+		t.Errorf("session = %+v, %v", s, err)
+	}
+}
+
+func TestValidateSink(t *testing.T) {
+	for _, tc := range []struct {
+		spec  string
+		valid bool
+	}{
+		{"file:/var/log/radar", true},
+		{"sqlite:/data/audit.db", true},
+		{"webhook:https://audit.example.com/radar", true},
+		{"webhook:ftp://audit.example.com", false},
+		{"s3:bucket", false},
+		{"s3://audit/radar?region=eu-west-1", true},
+		{"gs://audit", true},
+		{"azblob://account", false},
+		{"file:", false},
+		{"/var/log/radar", false},
+	} {
+		if err := ValidateSink(tc.spec); (err == nil) != tc.valid {
+			t.Errorf("ValidateSink(%q) = %v, want valid=%v", tc.spec, err, tc.valid)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package execaudit
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	_ "modernc.org/sqlite" // Pure Go SQLite driver
+
+	"github.com/skyhook-io/radar/internal/objectstore"
+)
+
+// Sink stores finished recordings
+type Sink interface {
+	Name() string
+	Store(ctx context.Context, rec *Recording) error
+	Close() error
+}
+
+// ValidateSink checks a sink spec without opening it
+func ValidateSink(spec string) error {
+	kind, target, ok := strings.Cut(spec, ":")
+	switch {
+	case !ok || target == "":
+		return fmt.Errorf("expected file:<dir>, sqlite:<path>, webhook:<url> or an s3://, gs:// or azblob:// URL, got %q", spec)
+	case kind == "s3" || kind == "gs" || kind == "azblob":
+		return objectstore.Validate(spec)
+	case kind == "webhook":
+		if u, err := url.ParseRequestURI(target); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("invalid webhook URL %q", target)
+		}
+	case kind != "file" && kind != "sqlite":
+		return fmt.Errorf("unknown sink type %q (expected file, sqlite, webhook, s3, gs or azblob)", kind)
+	}
+	return nil
+}
+
+// ParseSink builds a sink from a spec: file:<dir>, sqlite:<path>, webhook:<url>, or an
+// s3://, gs:// or azblob:// object store URL
+func ParseSink(spec string) (Sink, error) {
+	if err := ValidateSink(spec); err != nil {
+		return nil, fmt.Errorf("exec audit sink: %w", err)
+	}
+	kind, target, _ := strings.Cut(spec, ":")
+	switch kind {
+	case "file":
+		return newFileSink(target)
+	case "sqlite":
+		return newSQLiteSink(target)
+	case "s3", "gs", "azblob":
+		store, err := objectstore.Open(spec)
+		if err != nil {
+			return nil, fmt.Errorf("exec audit sink: %w", err)
+		}
+		return &objectSink{store: store}, nil
+	default:
+		return &webhookSink{url: target, token: os.Getenv("RADAR_EXEC_AUDIT_WEBHOOK_TOKEN")}, nil
+	}
+}
+
+// fileSink writes each transcript to <dir>/<id>.cast and appends session metadata to
+// <dir>/sessions.jsonl
+type fileSink struct {
+	dir string
+	mu  sync.Mutex
+}
+
+func newFileSink(dir string) (*fileSink, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create exec audit directory: %w", err)
+	}
+	return &fileSink{dir: dir}, nil
+}
+
+func (s *fileSink) Name() string { return "file:" + s.dir }
+
+func (s *fileSink) Store(_ context.Context, rec *Recording) error {
+	if err := os.WriteFile(filepath.Join(s.dir, rec.ID+".cast"), rec.Cast, 0600); err != nil {
+		return err
+	}
+	line, err := json.Marshal(rec.Session)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := os.OpenFile(filepath.Join(s.dir, "sessions.jsonl"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (s *fileSink) Close() error { return nil }
+
+// sqliteSink stores sessions and transcripts in an exec_sessions table
+type sqliteSink struct {
+	path string
+	db   *sql.DB
+}
+
+func newSQLiteSink(path string) (*sqliteSink, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create exec audit directory: %w", err)
+	}
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(`PRAGMA busy_timeout=10000;
+	CREATE TABLE IF NOT EXISTS exec_sessions (
+		id TEXT PRIMARY KEY,
+		type TEXT NOT NULL,
+		user TEXT NOT NULL,
+		remote TEXT,
+		context TEXT,
+		namespace TEXT NOT NULL,
+		pod TEXT NOT NULL,
+		container TEXT,
+		node TEXT,
+		command TEXT,
+		started_at TEXT NOT NULL,
+		ended_at TEXT NOT NULL,
+		participants TEXT,
+		truncated INTEGER NOT NULL DEFAULT 0,
+		transcript BLOB
+	);
+	CREATE INDEX IF NOT EXISTS idx_exec_sessions_started ON exec_sessions(started_at DESC);
+	CREATE INDEX IF NOT EXISTS idx_exec_sessions_target ON exec_sessions(namespace, pod);`); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize exec audit database: %w", err)
+	}
+	return &sqliteSink{path: path, db: db}, nil
+}
+
+func (s *sqliteSink) Name() string { return "sqlite:" + s.path }
+
+func (s *sqliteSink) Store(ctx context.Context, rec *Recording) error {
+	command, _ := json.Marshal(rec.Command)
+	participants, _ := json.Marshal(rec.Participants)
+	_, err := s.db.ExecContext(ctx, `INSERT OR REPLACE INTO exec_sessions
+		(id, type, user, remote, context, namespace, pod, container, node, command, started_at, ended_at, participants, truncated, transcript)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		rec.ID, rec.Type, rec.User, rec.Remote, rec.Context, rec.Namespace, rec.Pod, rec.Container, rec.Node,
+		string(command), rec.StartedAt.UTC().Format(time.RFC3339Nano), rec.EndedAt.UTC().Format(time.RFC3339Nano),
+		string(participants), rec.Truncated, rec.Cast)
+	return err
+}
+
+func (s *sqliteSink) Close() error { return s.db.Close() }
+
+// webhookSink posts each recording as JSON, with the transcript in "cast"
+type webhookSink struct {
+	url   string
+	token string // Bearer token from RADAR_EXEC_AUDIT_WEBHOOK_TOKEN
+}
+
+var httpClient = &http.Client{Timeout: 15 * time.Second}
+
+func (s *webhookSink) Name() string { return "webhook:" + s.url }
+
+func (s *webhookSink) Store(ctx context.Context, rec *Recording) error {
+	body, err := json.Marshal(struct {
+		Session
+		Cast string `json:"cast"`
+	}{rec.Session, string(rec.Cast)})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+func (s *webhookSink) Close() error { return nil }
+
+// objectSink writes each transcript to <prefix>/<id>.cast and its session metadata to
+// <prefix>/<id>.json in object storage
+type objectSink struct {
+	store objectstore.Store
+}
+
+func (s *objectSink) Name() string { return s.store.Name() }
+
+func (s *objectSink) Store(ctx context.Context, rec *Recording) error {
+	if _, err := s.store.Put(ctx, rec.ID+".cast", rec.Cast, "application/x-asciicast"); err != nil {
+		return err
+	}
+	session, err := json.Marshal(rec.Session)
+	if err != nil {
+		return err
+	}
+	_, err = s.store.Put(ctx, rec.ID+".json", session, "application/json")
+	return err
+}
+
+func (s *objectSink) Close() error { return nil }
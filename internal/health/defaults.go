@@ -0,0 +1,111 @@
+package health
+
+// readyCondition is the common convention: Ready=True is healthy, Ready=False unhealthy,
+// and Ready=Unknown means the operator is still reconciling
+var readyCondition = []ConditionRule{
+	{Type: "Ready", Status: "True", Health: Healthy},
+	{Type: "Ready", Status: "False", Health: Unhealthy},
+	{Type: "Ready", Status: "Unknown", Health: Degraded},
+}
+
+// istioValidation grades Istio config by the analyzer messages it writes to the status:
+// errors are unhealthy, warnings degraded, anything else healthy
+const istioValidation = `
+!has(object.status) || !has(object.status.validationMessages) ? "healthy" :
+object.status.validationMessages.exists(m, has(m.level) && m.level == "ERROR") ? "unhealthy" :
+object.status.validationMessages.exists(m, has(m.level) && m.level == "WARNING") ? "degraded" :
+"healthy"`
+
+// prometheusBindings grades Prometheus Operator config objects by whether every
+// Prometheus selecting them accepted them (status.bindings, operator 0.79+)
+const prometheusBindings = `
+!has(object.status) || !has(object.status.bindings) ? "healthy" :
+object.status.bindings.exists(b, has(b.conditions) &&
+  b.conditions.exists(c, c.type == "Accepted" && c.status == "False")) ? "unhealthy" :
+"healthy"`
+
+// DefaultRules cover popular operators' CRDs. User rules for the same GroupKind replace
+// them.
+var DefaultRules = []Rule{
+	// cert-manager
+	{Group: "cert-manager.io", Kind: "Certificate", Conditions: []ConditionRule{
+		{Type: "Ready", Status: "True", Health: Healthy},
+		{Type: "Issuing", Status: "True", Health: Degraded},
+		{Type: "Ready", Status: "False", Health: Unhealthy},
+		{Type: "Ready", Status: "Unknown", Health: Degraded},
+	}},
+	{Group: "cert-manager.io", Kind: "CertificateRequest", Conditions: []ConditionRule{
+		{Type: "Ready", Status: "True", Health: Healthy},
+		{Type: "Denied", Status: "True", Health: Unhealthy},
+		{Type: "Ready", Status: "False", Reason: "Pending", Health: Degraded},
+		{Type: "Ready", Status: "False", Health: Unhealthy},
+		{Type: "Ready", Status: "Unknown", Health: Degraded},
+	}},
+	{Group: "cert-manager.io", Kind: "Issuer", Conditions: readyCondition},
+	{Group: "cert-manager.io", Kind: "ClusterIssuer", Conditions: readyCondition},
+	{Group: "acme.cert-manager.io", Kind: "Order", CEL: `
+!has(object.status) || !has(object.status.state) ? "degraded" :
+object.status.state == "valid" ? "healthy" :
+object.status.state in ["invalid", "errored", "expired"] ? "unhealthy" :
+"degraded"`},
+
+	// Argo Rollouts: Paused and Progressing are mid-rollout
+	{Group: "argoproj.io", Kind: "Rollout", CEL: `
+!has(object.status) || !has(object.status.phase) ? "unknown" :
+object.status.phase == "Healthy" ? "healthy" :
+object.status.phase == "Degraded" ? "unhealthy" :
+"degraded"`},
+
+	// Argo CD Applications report the aggregated health of their resources; Missing means
+	// they haven't been created. Suspended is usually deliberate, so it isn't graded.
+	{Group: "argoproj.io", Kind: "Application", CEL: `
+!has(object.status) || !has(object.status.health) || !has(object.status.health.status) ? "unknown" :
+object.status.health.status == "Healthy" ? "healthy" :
+object.status.health.status in ["Degraded", "Missing"] ? "unhealthy" :
+object.status.health.status == "Progressing" ? "degraded" :
+"unknown"`},
+
+	// Istio
+	{Group: "networking.istio.io", Kind: "VirtualService", CEL: istioValidation},
+	{Group: "networking.istio.io", Kind: "DestinationRule", CEL: istioValidation},
+	{Group: "networking.istio.io", Kind: "Gateway", CEL: istioValidation},
+	{Group: "networking.istio.io", Kind: "ServiceEntry", CEL: istioValidation},
+	{Group: "networking.istio.io", Kind: "Sidecar", CEL: istioValidation},
+	{Group: "security.istio.io", Kind: "AuthorizationPolicy", CEL: istioValidation},
+	{Group: "security.istio.io", Kind: "PeerAuthentication", CEL: istioValidation},
+
+	// Strimzi: NotReady=True carries the failure reason; Creating is still in progress
+	{Group: "kafka.strimzi.io", Kind: "Kafka", Conditions: strimziConditions},
+	{Group: "kafka.strimzi.io", Kind: "KafkaTopic", Conditions: strimziConditions},
+	{Group: "kafka.strimzi.io", Kind: "KafkaUser", Conditions: strimziConditions},
+	{Group: "kafka.strimzi.io", Kind: "KafkaConnect", Conditions: strimziConditions},
+	{Group: "kafka.strimzi.io", Kind: "KafkaConnector", Conditions: strimziConditions},
+	{Group: "kafka.strimzi.io", Kind: "KafkaMirrorMaker2", Conditions: strimziConditions},
+	{Group: "kafka.strimzi.io", Kind: "KafkaBridge", Conditions: strimziConditions},
+
+	// Prometheus Operator: workloads report Available (True, Degraded or False) and
+	// Reconciled; config objects report per-Prometheus bindings
+	{Group: "monitoring.coreos.com", Kind: "Prometheus", Conditions: prometheusConditions},
+	{Group: "monitoring.coreos.com", Kind: "PrometheusAgent", Conditions: prometheusConditions},
+	{Group: "monitoring.coreos.com", Kind: "Alertmanager", Conditions: prometheusConditions},
+	{Group: "monitoring.coreos.com", Kind: "ThanosRuler", Conditions: prometheusConditions},
+	{Group: "monitoring.coreos.com", Kind: "ServiceMonitor", CEL: prometheusBindings},
+	{Group: "monitoring.coreos.com", Kind: "PodMonitor", CEL: prometheusBindings},
+	{Group: "monitoring.coreos.com", Kind: "Probe", CEL: prometheusBindings},
+	{Group: "monitoring.coreos.com", Kind: "PrometheusRule", CEL: prometheusBindings},
+}
+
+var strimziConditions = []ConditionRule{
+	{Type: "Ready", Status: "True", Health: Healthy},
+	{Type: "NotReady", Status: "True", Reason: "Creating", Health: Degraded},
+	{Type: "NotReady", Status: "True", Health: Unhealthy},
+	{Type: "Ready", Status: "False", Health: Unhealthy},
+	{Type: "Ready", Status: "Unknown", Health: Degraded},
+}
+
+var prometheusConditions = []ConditionRule{
+	{Type: "Available", Status: "False", Health: Unhealthy},
+	{Type: "Available", Status: "Degraded", Health: Degraded},
+	{Type: "Reconciled", Status: "False", Health: Degraded},
+	{Type: "Available", Status: "True", Health: Healthy},
+}
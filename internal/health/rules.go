@@ -0,0 +1,236 @@
+// Package health assesses custom resources' health from rules keyed by GroupKind, in the
+// spirit of Argo CD's resource health checks. A rule maps status conditions to a health
+// state, or computes one with a CEL expression. Defaults cover popular operators and
+// user-supplied rules replace them per GroupKind.
+package health
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// State is a resource's assessed health; the values match timeline.HealthState
+type State string
+
+const (
+	Healthy   State = "healthy"
+	Degraded  State = "degraded"
+	Unhealthy State = "unhealthy"
+	Unknown   State = "unknown"
+)
+
+// celCost caps the work one expression may do on one object
+const celCost = 100000
+
+// Rule assesses the health of one kind of custom resource. Conditions are tried first,
+// in order; the first whose type and status (and reason, if set) match decides. When
+// none match, CEL is evaluated if set, otherwise the state is unknown.
+type Rule struct {
+	Group      string          `json:"group,omitempty"` // Empty matches the kind in any group
+	Kind       string          `json:"kind"`
+	Conditions []ConditionRule `json:"conditions,omitempty"`
+	// CEL is evaluated with the resource as `object` and must return "healthy",
+	// "degraded", "unhealthy" or "unknown", or a bool (true healthy, false unhealthy)
+	CEL string `json:"cel,omitempty"`
+}
+
+// ConditionRule maps a status condition to a health state
+type ConditionRule struct {
+	Type   string `json:"type"`
+	Status string `json:"status"`           // True, False or Unknown (the Prometheus Operator also uses Degraded)
+	Reason string `json:"reason,omitempty"` // Empty matches any reason
+	Health State  `json:"health"`
+}
+
+type compiledRule struct {
+	Rule
+	program cel.Program
+}
+
+// Engine assesses resources against a fixed set of rules
+type Engine struct {
+	byGroupKind map[schema.GroupKind]*compiledRule
+	byKind      map[string]*compiledRule // Rules without a group
+}
+
+// NewEngine compiles rules; later rules for the same GroupKind replace earlier ones
+func NewEngine(rules []Rule) (*Engine, error) {
+	env, err := cel.NewEnv(cel.Variable("object", cel.DynType))
+	if err != nil {
+		return nil, err
+	}
+	e := &Engine{byGroupKind: map[schema.GroupKind]*compiledRule{}, byKind: map[string]*compiledRule{}}
+	for i, r := range rules {
+		c, err := compileRule(env, r)
+		if err != nil {
+			name := r.Kind
+			if r.Group != "" {
+				name += "." + r.Group
+			}
+			return nil, fmt.Errorf("rule %d (%s): %w", i, name, err)
+		}
+		if r.Group == "" {
+			e.byKind[r.Kind] = c
+			// Also overrides earlier rules for the kind in a specific group
+			for gk := range e.byGroupKind {
+				if gk.Kind == r.Kind {
+					delete(e.byGroupKind, gk)
+				}
+			}
+		} else {
+			e.byGroupKind[schema.GroupKind{Group: r.Group, Kind: r.Kind}] = c
+		}
+	}
+	return e, nil
+}
+
+func compileRule(env *cel.Env, r Rule) (*compiledRule, error) {
+	if r.Kind == "" {
+		return nil, fmt.Errorf("kind is required")
+	}
+	if len(r.Conditions) == 0 && r.CEL == "" {
+		return nil, fmt.Errorf("needs conditions or cel")
+	}
+	for _, cond := range r.Conditions {
+		if cond.Type == "" || cond.Status == "" {
+			return nil, fmt.Errorf("conditions need a type and status")
+		}
+		switch cond.Health {
+		case Healthy, Degraded, Unhealthy, Unknown:
+		default:
+			return nil, fmt.Errorf("condition %s=%s: invalid health %q", cond.Type, cond.Status, cond.Health)
+		}
+	}
+	c := &compiledRule{Rule: r}
+	if r.CEL == "" {
+		return c, nil
+	}
+	ast, issues := env.Compile(r.CEL)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("cel: %w", issues.Err())
+	}
+	if t := ast.OutputType(); !t.IsAssignableType(cel.StringType) && !t.IsAssignableType(cel.BoolType) {
+		return nil, fmt.Errorf("cel must return a string or bool, got %s", t)
+	}
+	program, err := env.Program(ast, cel.CostLimit(celCost))
+	if err != nil {
+		return nil, fmt.Errorf("cel: %w", err)
+	}
+	c.program = program
+	return c, nil
+}
+
+// rule returns the rule for a GroupKind, preferring one written for its group
+func (e *Engine) rule(gk schema.GroupKind) *compiledRule {
+	if r, ok := e.byGroupKind[gk]; ok {
+		return r
+	}
+	return e.byKind[gk.Kind]
+}
+
+// Assess returns a resource's health, or Unknown when no rule covers its kind or the
+// rule can't tell
+func (e *Engine) Assess(obj *unstructured.Unstructured) State {
+	if obj == nil {
+		return Unknown
+	}
+	r := e.rule(obj.GroupVersionKind().GroupKind())
+	if r == nil {
+		return Unknown
+	}
+	if state, ok := matchConditions(r.Conditions, obj); ok {
+		return state
+	}
+	if r.program == nil {
+		return Unknown
+	}
+	out, _, err := r.program.Eval(map[string]any{"object": obj.Object})
+	if err != nil {
+		return Unknown
+	}
+	return stateOf(out)
+}
+
+// matchConditions returns the health of the first condition rule the object matches
+func matchConditions(rules []ConditionRule, obj *unstructured.Unstructured) (State, bool) {
+	if len(rules) == 0 {
+		return "", false
+	}
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, rule := range rules {
+		for _, c := range conditions {
+			cond, ok := c.(map[string]any)
+			if !ok || cond["type"] != rule.Type || cond["status"] != rule.Status {
+				continue
+			}
+			if rule.Reason != "" && cond["reason"] != rule.Reason {
+				continue
+			}
+			return rule.Health, true
+		}
+	}
+	return "", false
+}
+
+func stateOf(v ref.Val) State {
+	switch v.Type() {
+	case types.BoolType:
+		if v == types.True {
+			return Healthy
+		}
+		return Unhealthy
+	case types.StringType:
+		switch s := State(strings.ToLower(v.Value().(string))); s {
+		case Healthy, Degraded, Unhealthy:
+			return s
+		}
+	}
+	return Unknown
+}
+
+var (
+	engineMu sync.RWMutex
+	engine   = mustEngine(DefaultRules)
+)
+
+func mustEngine(rules []Rule) *Engine {
+	e, err := NewEngine(rules)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// SetRules replaces the rules in use with the defaults plus rules, which override the
+// defaults for the same GroupKind
+func SetRules(rules []Rule) error {
+	e, err := NewEngine(append(append([]Rule{}, DefaultRules...), rules...))
+	if err != nil {
+		return err
+	}
+	engineMu.Lock()
+	engine = e
+	engineMu.Unlock()
+	return nil
+}
+
+// ValidateRules checks rules without applying them
+func ValidateRules(rules []Rule) error {
+	_, err := NewEngine(rules)
+	return err
+}
+
+// Assess returns a resource's health under the rules in use
+func Assess(obj *unstructured.Unstructured) State {
+	engineMu.RLock()
+	e := engine
+	engineMu.RUnlock()
+	return e.Assess(obj)
+}
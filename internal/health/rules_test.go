@@ -0,0 +1,118 @@
+package health
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func testObject(apiVersion, kind string, status map[string]any) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata":   map[string]any{"name": "x", "namespace": "shop"},
+	}}
+	if status != nil {
+		obj.Object["status"] = status
+	}
+	return obj
+}
+
+func conditions(pairs ...string) map[string]any {
+	var list []any
+	for i := 0; i+2 < len(pairs); i += 3 {
+		list = append(list, map[string]any{"type": pairs[i], "status": pairs[i+1], "reason": pairs[i+2]})
+	}
+	return map[string]any{"conditions": list}
+}
+
+func TestDefaultRules(t *testing.T) {
+	e, err := NewEngine(DefaultRules)
+	if err != nil {
+		t.Fatalf("NewEngine(DefaultRules): %v", err)
+	}
+	tests := []struct {
+		name string
+		obj  *unstructured.Unstructured
+		want State
+	}{
+		{"certificate ready", testObject("cert-manager.io/v1", "Certificate", conditions("Ready", "True", "Ready")), Healthy},
+		{"certificate renewing", testObject("cert-manager.io/v1", "Certificate", conditions("Ready", "False", "DoesNotExist", "Issuing", "True", "Renewing")), Degraded},
+		{"certificate failed", testObject("cert-manager.io/v1", "Certificate", conditions("Ready", "False", "Failed")), Unhealthy},
+		{"certificate request pending", testObject("cert-manager.io/v1", "CertificateRequest", conditions("Ready", "False", "Pending")), Degraded},
+		{"certificate without status", testObject("cert-manager.io/v1", "Certificate", nil), Unknown},
+		{"strimzi creating", testObject("kafka.strimzi.io/v1beta2", "Kafka", conditions("NotReady", "True", "Creating")), Degraded},
+		{"strimzi failing", testObject("kafka.strimzi.io/v1beta2", "KafkaTopic", conditions("NotReady", "True", "InvalidResourceException")), Unhealthy},
+		{"prometheus degraded", testObject("monitoring.coreos.com/v1", "Prometheus", conditions("Available", "Degraded", "SomePodsNotReady", "Reconciled", "True", "")), Degraded},
+		{"prometheus available", testObject("monitoring.coreos.com/v1", "Prometheus", conditions("Available", "True", "", "Reconciled", "True", "")), Healthy},
+		{"argocd app healthy", testObject("argoproj.io/v1alpha1", "Application", map[string]any{"health": map[string]any{"status": "Healthy"}}), Healthy},
+		{"argocd app degraded", testObject("argoproj.io/v1alpha1", "Application", map[string]any{"health": map[string]any{"status": "Degraded"}}), Unhealthy},
+		{"argocd app progressing", testObject("argoproj.io/v1alpha1", "Application", map[string]any{"health": map[string]any{"status": "Progressing"}}), Degraded},
+		{"argocd app without health", testObject("argoproj.io/v1alpha1", "Application", map[string]any{}), Unknown},
+		{"virtualservice without messages", testObject("networking.istio.io/v1", "VirtualService", nil), Healthy},
+		{"virtualservice with error", testObject("networking.istio.io/v1", "VirtualService", map[string]any{
+			"validationMessages": []any{
+				map[string]any{"level": "WARNING", "type": map[string]any{"code": "IST0173"}},
+				map[string]any{"level": "ERROR", "type": map[string]any{"code": "IST0101"}},
+			},
+		}), Unhealthy},
+		{"servicemonitor rejected", testObject("monitoring.coreos.com/v1", "ServiceMonitor", map[string]any{
+			"bindings": []any{map[string]any{
+				"name":       "k8s",
+				"conditions": []any{map[string]any{"type": "Accepted", "status": "False"}},
+			}},
+		}), Unhealthy},
+		{"kind in another group", testObject("example.com/v1", "Certificate", conditions("Ready", "False", "")), Unknown},
+		{"kind without rules", testObject("example.com/v1", "Widget", conditions("Ready", "True", "")), Unknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := e.Assess(tt.obj); got != tt.want {
+				t.Errorf("Assess = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserRules(t *testing.T) {
+	e, err := NewEngine(append(append([]Rule{}, DefaultRules...),
+		Rule{Kind: "Certificate", CEL: `has(object.status) && object.status.notAfter > "2026"`},
+		Rule{Group: "example.com", Kind: "Widget", Conditions: []ConditionRule{{Type: "Synced", Status: "True", Health: Healthy}}, CEL: `"degraded"`},
+	))
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+
+	// A rule without a group replaces the default in cert-manager.io
+	cert := testObject("cert-manager.io/v1", "Certificate", map[string]any{"notAfter": "2025-01-01T00:00:00Z"})
+	if got := e.Assess(cert); got != Unhealthy {
+		t.Errorf("expired certificate = %q, want unhealthy", got)
+	}
+	// Conditions decide first, CEL when none match
+	if got := e.Assess(testObject("example.com/v1", "Widget", conditions("Synced", "True", ""))); got != Healthy {
+		t.Errorf("synced widget = %q, want healthy", got)
+	}
+	if got := e.Assess(testObject("example.com/v1", "Widget", nil)); got != Degraded {
+		t.Errorf("widget without conditions = %q, want degraded", got)
+	}
+}
+
+func TestInvalidRules(t *testing.T) {
+	tests := []struct {
+		rule Rule
+		want string
+	}{
+		{Rule{Group: "example.com"}, "kind is required"},
+		{Rule{Kind: "Widget"}, "needs conditions or cel"},
+		{Rule{Kind: "Widget", Conditions: []ConditionRule{{Type: "Ready", Status: "True", Health: "fine"}}}, "invalid health"},
+		{Rule{Kind: "Widget", CEL: `object.status.`}, "cel:"},
+		{Rule{Kind: "Widget", CEL: `1 + 2`}, "string or bool"},
+	}
+	for _, tt := range tests {
+		err := ValidateRules([]Rule{tt.rule})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ValidateRules(%+v) = %v, want error containing %q", tt.rule, err, tt.want)
+		}
+	}
+}
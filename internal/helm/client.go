@@ -2,6 +2,7 @@ package helm
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -14,14 +15,27 @@ import (
 	"time"
 
 	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/yamldiff"
 
 	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chart/loader"
 	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/registry"
 	"helm.sh/helm/v3/pkg/release"
 	"helm.sh/helm/v3/pkg/releaseutil"
 	"helm.sh/helm/v3/pkg/repo"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/yaml"
 )
 
 // HTTP client for ArtifactHub requests
@@ -31,9 +45,10 @@ var httpClient = &http.Client{
 
 // Client provides access to Helm releases
 type Client struct {
-	mu         sync.RWMutex
-	settings   *cli.EnvSettings
-	kubeconfig string
+	mu             sync.RWMutex
+	settings       *cli.EnvSettings
+	kubeconfig     string
+	registryClient *registry.Client // For oci:// charts; nil if it couldn't be created
 }
 
 var (
@@ -50,9 +65,14 @@ func Initialize(kubeconfig string) error {
 		if kubeconfig != "" {
 			settings.KubeConfig = kubeconfig
 		}
+		registryClient, err := newRegistryClient(settings)
+		if err != nil {
+			log.Printf("Warning: Helm OCI registry client unavailable: %v", err)
+		}
 		globalClient = &Client{
-			settings:   settings,
-			kubeconfig: kubeconfig,
+			settings:       settings,
+			kubeconfig:     kubeconfig,
+			registryClient: registryClient,
 		}
 		log.Printf("Helm client initialized")
 	})
@@ -82,11 +102,41 @@ func ReinitClient(kubeconfig string) error {
 
 // getActionConfig creates a new action configuration for the given namespace
 func (c *Client) getActionConfig(namespace string) (*action.Configuration, error) {
+	return c.actionConfigAs(namespace, nil)
+}
+
+// getActionConfigFor creates an action configuration for changes made by a request,
+// impersonating the request's user when impersonation is enabled
+func (c *Client) getActionConfigFor(ctx context.Context, namespace string) (*action.Configuration, error) {
+	return c.actionConfigAs(namespace, k8s.ImpersonationFrom(ctx))
+}
+
+// actionConfigAs creates an action configuration acting as subject (nil = Radar itself)
+func (c *Client) actionConfigAs(namespace string, subject *k8s.ImpersonationSubject) (*action.Configuration, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
 	actionConfig := new(action.Configuration)
 
+	// Use Explorer's current context (in-memory) instead of kubeconfig's current-context
+	// This ensures Helm uses the same context as the rest of Explorer after context switches
+	currentContext := k8s.GetContextName()
+
+	// Several kubeconfig files are merged by Radar (duplicate contexts get file-qualified
+	// names), so Helm reads the same merged config rather than a single path
+	overrides := &clientcmd.ConfigOverrides{Context: clientcmdapi.Context{Namespace: namespace}, CurrentContext: currentContext}
+	if subject != nil {
+		overrides.AuthInfo.Impersonate = subject.User
+		overrides.AuthInfo.ImpersonateGroups = subject.Groups
+	}
+	if loader := k8s.KubeconfigLoader(overrides); loader != nil {
+		if err := actionConfig.Init(&kubeconfigGetter{loader: loader}, namespace, "secrets", log.Printf); err != nil {
+			return nil, fmt.Errorf("failed to initialize helm action config: %w", err)
+		}
+		actionConfig.RegistryClient = c.registryClient
+		return actionConfig, nil
+	}
+
 	// Use RESTClientGetter for kubeconfig
 	// NOTE: Use false for usePersistentConfig to avoid caching issues during context switches
 	configFlags := genericclioptions.NewConfigFlags(false)
@@ -97,20 +147,56 @@ func (c *Client) getActionConfig(namespace string) (*action.Configuration, error
 		configFlags.Namespace = &namespace
 	}
 
-	// Use Explorer's current context (in-memory) instead of kubeconfig's current-context
-	// This ensures Helm uses the same context as the rest of Explorer after context switches
-	currentContext := k8s.GetContextName()
 	if currentContext != "" && currentContext != "in-cluster" {
 		configFlags.Context = &currentContext
 	}
+	if subject != nil {
+		configFlags.Impersonate = &subject.User
+		configFlags.ImpersonateGroup = &subject.Groups
+	}
 
 	if err := actionConfig.Init(configFlags, namespace, "secrets", log.Printf); err != nil {
 		return nil, fmt.Errorf("failed to initialize helm action config: %w", err)
 	}
+	actionConfig.RegistryClient = c.registryClient
 
 	return actionConfig, nil
 }
 
+// kubeconfigGetter is a RESTClientGetter over a client config Radar built itself
+type kubeconfigGetter struct {
+	loader clientcmd.ClientConfig
+}
+
+func (g *kubeconfigGetter) ToRawKubeConfigLoader() clientcmd.ClientConfig { return g.loader }
+
+func (g *kubeconfigGetter) ToRESTConfig() (*rest.Config, error) {
+	return g.loader.ClientConfig()
+}
+
+func (g *kubeconfigGetter) ToDiscoveryClient() (discovery.CachedDiscoveryInterface, error) {
+	config, err := g.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	// Discovery fans out a request per group, so allow bursts like kubectl does
+	config.Burst = 300
+	dc, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return memory.NewMemCacheClient(dc), nil
+}
+
+func (g *kubeconfigGetter) ToRESTMapper() (meta.RESTMapper, error) {
+	dc, err := g.ToDiscoveryClient()
+	if err != nil {
+		return nil, err
+	}
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(dc)
+	return restmapper.NewShortcutExpander(mapper, dc, nil), nil
+}
+
 // ListReleases returns all Helm releases, optionally filtered by namespace
 func (c *Client) ListReleases(namespace string) ([]HelmRelease, error) {
 	actionConfig, err := c.getActionConfig(namespace)
@@ -181,7 +267,7 @@ func (c *Client) GetRelease(namespace, name string) (*HelmReleaseDetail, error)
 	resources := parseManifestResources(rel.Manifest, namespace)
 
 	// Enrich resources with live status from k8s cache
-	enrichResourcesWithStatus(resources)
+	enrichResourcesWithStatus(resources, true)
 
 	// Extract hooks
 	hooks := extractHooks(rel)
@@ -192,6 +278,12 @@ func (c *Client) GetRelease(namespace, name string) (*HelmReleaseDetail, error)
 	// Extract dependencies
 	dependencies := extractDependencies(rel)
 
+	// Compare the manifest with live objects: orphans left by upgrades, deleted entries
+	var drift *ReleaseDrift
+	if k8s.GetResourceCache() != nil {
+		drift = computeDrift(rel, history, listLiveObjects)
+	}
+
 	detail := &HelmReleaseDetail{
 		Name:         rel.Name,
 		Namespace:    rel.Namespace,
@@ -208,6 +300,7 @@ func (c *Client) GetRelease(namespace, name string) (*HelmReleaseDetail, error)
 		Hooks:        hooks,
 		Readme:       readme,
 		Dependencies: dependencies,
+		Drift:        drift,
 	}
 
 	return detail, nil
@@ -233,6 +326,16 @@ func (c *Client) GetManifest(namespace, name string, revision int) (string, erro
 	return rel.Manifest, nil
 }
 
+// GetReleaseResources returns the resources in a release's current manifest, without
+// their live status
+func (c *Client) GetReleaseResources(namespace, name string) ([]OwnedResource, error) {
+	manifest, err := c.GetManifest(namespace, name, 0)
+	if err != nil {
+		return nil, err
+	}
+	return parseManifestResources(manifest, namespace), nil
+}
+
 // GetValues returns the values for a release
 func (c *Client) GetValues(namespace, name string, allValues bool) (*HelmValues, error) {
 	actionConfig, err := c.getActionConfig(namespace)
@@ -265,8 +368,9 @@ func (c *Client) GetValues(namespace, name string, allValues bool) (*HelmValues,
 	return result, nil
 }
 
-// GetManifestDiff returns the diff between two revisions
-func (c *Client) GetManifestDiff(namespace, name string, revision1, revision2 int) (*ManifestDiff, error) {
+// GetManifestDiff returns the diff between two revisions. Diff holds the text rendering
+// for unified and side-by-side formats; Changes is always the structured diff.
+func (c *Client) GetManifestDiff(namespace, name string, revision1, revision2 int, format yamldiff.Format) (*ManifestDiff, error) {
 	manifest1, err := c.GetManifest(namespace, name, revision1)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get manifest for revision %d: %w", revision1, err)
@@ -277,14 +381,19 @@ func (c *Client) GetManifestDiff(namespace, name string, revision1, revision2 in
 		return nil, fmt.Errorf("failed to get manifest for revision %d: %w", revision2, err)
 	}
 
-	// Compute unified diff
-	diff := computeDiff(manifest1, manifest2, revision1, revision2)
-
-	return &ManifestDiff{
+	changes := yamldiff.Compare(manifest1, manifest2)
+	result := &ManifestDiff{
 		Revision1: revision1,
 		Revision2: revision2,
-		Diff:      diff,
-	}, nil
+		Format:    format,
+		Changes:   changes,
+	}
+	if format != yamldiff.FormatJSON {
+		if result.Diff, err = yamldiff.RenderString(format, changes); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
 }
 
 // toHelmRelease converts a helm release to our API type
@@ -302,7 +411,7 @@ func toHelmRelease(rel *release.Release) HelmRelease {
 
 	// Compute health from owned resources
 	resources := parseManifestResources(rel.Manifest, rel.Namespace)
-	enrichResourcesWithStatus(resources)
+	enrichResourcesWithStatus(resources, false)
 	health, issue, summary := computeResourceHealth(resources)
 	hr.ResourceHealth = health
 	hr.HealthIssue = issue
@@ -412,43 +521,18 @@ func toHelmRevision(rel *release.Release) HelmRevision {
 	}
 }
 
-// parseManifestResources extracts K8s resources from a rendered manifest
+// parseManifestResources decodes a rendered manifest into the resources it creates.
+// Documents that don't decode or lack a kind or name are skipped, List kinds are
+// flattened into their items, and hook documents are left out since Helm tracks hooks
+// separately (see extractHooks). Namespaced resources without a namespace get
+// defaultNamespace; cluster-scoped ones keep none.
 func parseManifestResources(manifest, defaultNamespace string) []OwnedResource {
 	var resources []OwnedResource
-
-	// Split manifest into individual documents
-	manifests := releaseutil.SplitManifests(manifest)
-
-	for _, m := range manifests {
-		// Simple parsing - look for kind, name, and namespace
-		lines := strings.Split(m, "\n")
-		var kind, name, namespace string
-
-		for _, line := range lines {
-			line = strings.TrimSpace(line)
-			if strings.HasPrefix(line, "kind:") {
-				kind = strings.TrimSpace(strings.TrimPrefix(line, "kind:"))
-			} else if strings.HasPrefix(line, "name:") && name == "" {
-				// Only take first name (metadata.name, not container names etc)
-				name = strings.TrimSpace(strings.TrimPrefix(line, "name:"))
-				// Remove quotes if present
-				name = strings.Trim(name, `"'`)
-			} else if strings.HasPrefix(line, "namespace:") && namespace == "" {
-				namespace = strings.TrimSpace(strings.TrimPrefix(line, "namespace:"))
-				namespace = strings.Trim(namespace, `"'`)
-			}
-		}
-
-		if kind != "" && name != "" {
-			if namespace == "" {
-				namespace = defaultNamespace
-			}
-			resources = append(resources, OwnedResource{
-				Kind:      kind,
-				Name:      name,
-				Namespace: namespace,
-			})
+	for _, obj := range decodeManifest(manifest) {
+		if _, isHook := obj.GetAnnotations()[release.HookAnnotation]; isHook {
+			continue
 		}
+		resources = append(resources, toOwnedResource(obj, defaultNamespace))
 	}
 
 	// Sort by kind, then name
@@ -462,15 +546,70 @@ func parseManifestResources(manifest, defaultNamespace string) []OwnedResource {
 	return resources
 }
 
-// enrichResourcesWithStatus adds live status from k8s cache to resources
-func enrichResourcesWithStatus(resources []OwnedResource) {
+// decodeManifest splits a multi-document manifest and decodes each document, expanding
+// List kinds (v1 List, ConfigMapList, ...) into their items
+func decodeManifest(manifest string) []*unstructured.Unstructured {
+	var objs []*unstructured.Unstructured
+	for _, doc := range releaseutil.SplitManifests(manifest) {
+		var content map[string]any
+		if err := yaml.Unmarshal([]byte(doc), &content); err != nil || content == nil {
+			continue
+		}
+		obj := &unstructured.Unstructured{Object: content}
+		if obj.IsList() {
+			_ = obj.EachListItem(func(item runtime.Object) error {
+				if u, ok := item.(*unstructured.Unstructured); ok && u.GetKind() != "" && u.GetName() != "" {
+					objs = append(objs, u)
+				}
+				return nil
+			})
+			continue
+		}
+		if obj.GetKind() != "" && obj.GetName() != "" {
+			objs = append(objs, obj)
+		}
+	}
+	return objs
+}
+
+func toOwnedResource(obj *unstructured.Unstructured, defaultNamespace string) OwnedResource {
+	gvk := obj.GroupVersionKind()
+	namespace := obj.GetNamespace()
+	if namespace == "" && !isClusterScoped(gvk.Group, gvk.Kind) {
+		namespace = defaultNamespace
+	}
+	return OwnedResource{
+		Kind:       gvk.Kind,
+		Group:      k8s.CustomGroup(gvk.Group),
+		APIVersion: obj.GetAPIVersion(),
+		Name:       obj.GetName(),
+		Namespace:  namespace,
+	}
+}
+
+// isClusterScoped reports whether discovery knows the kind as cluster-scoped. Unknown
+// kinds (e.g. CRDs the release is about to install) are treated as namespaced.
+func isClusterScoped(group, kind string) bool {
+	res, ok := k8s.GetResourceDiscovery().GetResource(k8s.QualifiedKind(kind, group))
+	return ok && !res.Namespaced
+}
+
+// enrichResourcesWithStatus adds live status from k8s cache to resources. Custom resources
+// are looked up in the dynamic cache only when includeCustom is set, since that starts
+// watching their kinds.
+func enrichResourcesWithStatus(resources []OwnedResource, includeCustom bool) {
 	cache := k8s.GetResourceCache()
 	if cache == nil {
 		return
 	}
 
 	for i := range resources {
-		status := cache.GetResourceStatus(resources[i].Kind, resources[i].Namespace, resources[i].Name)
+		var status *k8s.ResourceStatus
+		if resources[i].Group == "" {
+			status = cache.GetResourceStatus(resources[i].Kind, resources[i].Namespace, resources[i].Name)
+		} else if includeCustom {
+			status = k8s.GetCustomResourceStatus(resources[i].Group, resources[i].Kind, resources[i].Namespace, resources[i].Name)
+		}
 		if status != nil {
 			resources[i].Status = status.Status
 			resources[i].Ready = status.Ready
@@ -481,156 +620,6 @@ func enrichResourcesWithStatus(resources []OwnedResource) {
 	}
 }
 
-// computeDiff generates a unified diff between two manifests using LCS algorithm
-func computeDiff(manifest1, manifest2 string, rev1, rev2 int) string {
-	var result bytes.Buffer
-	result.WriteString(fmt.Sprintf("--- Revision %d\n", rev1))
-	result.WriteString(fmt.Sprintf("+++ Revision %d\n", rev2))
-
-	lines1 := strings.Split(manifest1, "\n")
-	lines2 := strings.Split(manifest2, "\n")
-
-	result.WriteString(computeUnifiedDiff(lines1, lines2))
-
-	return result.String()
-}
-
-// computeUnifiedDiff creates a unified diff from two sets of lines
-func computeUnifiedDiff(lines1, lines2 []string) string {
-	var result bytes.Buffer
-
-	// Use LCS-based diff algorithm
-	lcs := computeLCS(lines1, lines2)
-
-	i, j := 0, 0
-	lcsIdx := 0
-
-	// Track hunks for unified diff format
-	var hunkLines []string
-	hunkStart1, hunkStart2 := 1, 1
-	hunkLen1, hunkLen2 := 0, 0
-	contextLines := 3
-	pendingContext := []string{}
-
-	flushHunk := func() {
-		if len(hunkLines) > 0 {
-			result.WriteString(fmt.Sprintf("@@ -%d,%d +%d,%d @@\n",
-				hunkStart1, hunkLen1, hunkStart2, hunkLen2))
-			for _, line := range hunkLines {
-				result.WriteString(line)
-				result.WriteString("\n")
-			}
-			hunkLines = nil
-			hunkLen1, hunkLen2 = 0, 0
-		}
-	}
-
-	for i < len(lines1) || j < len(lines2) {
-		if lcsIdx < len(lcs) && i < len(lines1) && j < len(lines2) &&
-			lines1[i] == lcs[lcsIdx] && lines2[j] == lcs[lcsIdx] {
-			// Common line
-			if len(hunkLines) > 0 {
-				// Add context to current hunk
-				hunkLines = append(hunkLines, " "+lines1[i])
-				hunkLen1++
-				hunkLen2++
-				pendingContext = append(pendingContext, " "+lines1[i])
-				if len(pendingContext) > contextLines {
-					// Too much context, might need to end hunk
-					flushHunk()
-					pendingContext = nil
-					hunkStart1 = i + 2
-					hunkStart2 = j + 2
-				}
-			}
-			i++
-			j++
-			lcsIdx++
-		} else if i < len(lines1) && (lcsIdx >= len(lcs) || lines1[i] != lcs[lcsIdx]) {
-			// Line removed
-			if len(hunkLines) == 0 {
-				// Start new hunk with context
-				hunkStart1 = max(1, i-contextLines+1)
-				hunkStart2 = max(1, j-contextLines+1)
-				// Add leading context
-				for k := max(0, i-contextLines); k < i; k++ {
-					if k < len(lines1) {
-						hunkLines = append(hunkLines, " "+lines1[k])
-						hunkLen1++
-						hunkLen2++
-					}
-				}
-			}
-			pendingContext = nil
-			hunkLines = append(hunkLines, "-"+lines1[i])
-			hunkLen1++
-			i++
-		} else if j < len(lines2) {
-			// Line added
-			if len(hunkLines) == 0 {
-				hunkStart1 = max(1, i-contextLines+1)
-				hunkStart2 = max(1, j-contextLines+1)
-				// Add leading context
-				for k := max(0, i-contextLines); k < i; k++ {
-					if k < len(lines1) {
-						hunkLines = append(hunkLines, " "+lines1[k])
-						hunkLen1++
-						hunkLen2++
-					}
-				}
-			}
-			pendingContext = nil
-			hunkLines = append(hunkLines, "+"+lines2[j])
-			hunkLen2++
-			j++
-		}
-	}
-
-	flushHunk()
-	return result.String()
-}
-
-// computeLCS computes the Longest Common Subsequence of two string slices
-func computeLCS(a, b []string) []string {
-	m, n := len(a), len(b)
-	if m == 0 || n == 0 {
-		return nil
-	}
-
-	// Build LCS table
-	dp := make([][]int, m+1)
-	for i := range dp {
-		dp[i] = make([]int, n+1)
-	}
-
-	for i := 1; i <= m; i++ {
-		for j := 1; j <= n; j++ {
-			if a[i-1] == b[j-1] {
-				dp[i][j] = dp[i-1][j-1] + 1
-			} else {
-				dp[i][j] = max(dp[i-1][j], dp[i][j-1])
-			}
-		}
-	}
-
-	// Backtrack to find LCS
-	lcs := make([]string, 0, dp[m][n])
-	i, j := m, n
-	for i > 0 && j > 0 {
-		if a[i-1] == b[j-1] {
-			lcs = append([]string{a[i-1]}, lcs...)
-			i--
-			j--
-		} else if dp[i-1][j] > dp[i][j-1] {
-			i--
-		} else {
-			j--
-		}
-	}
-
-	return lcs
-}
-
 // extractHooks extracts hook information from a release
 func extractHooks(rel *release.Release) []HelmHook {
 	if rel.Hooks == nil {
@@ -651,6 +640,13 @@ func extractHooks(rel *release.Release) []HelmHook {
 			Weight: h.Weight,
 		}
 
+		// The hook's own manifest says where it runs, so its resource can be looked up
+		if objs := decodeManifest(h.Manifest); len(objs) > 0 {
+			res := toOwnedResource(objs[0], rel.Namespace)
+			hook.Namespace = res.Namespace
+			hook.Group = res.Group
+		}
+
 		// Add status if available
 		if h.LastRun.Phase != "" {
 			hook.Status = string(h.LastRun.Phase)
@@ -700,8 +696,9 @@ func extractDependencies(rel *release.Release) []ChartDependency {
 	return deps
 }
 
-// CheckForUpgrade checks if a newer version of the chart is available in configured repos
-func (c *Client) CheckForUpgrade(namespace, name string) (*UpgradeInfo, error) {
+// CheckForUpgrade checks if a newer version of the chart is available in configured repos,
+// or among the tags of an oci:// repository when one is given
+func (c *Client) CheckForUpgrade(namespace, name, repository string) (*UpgradeInfo, error) {
 	actionConfig, err := c.getActionConfig(namespace)
 	if err != nil {
 		return nil, err
@@ -721,6 +718,18 @@ func (c *Client) CheckForUpgrade(namespace, name string) (*UpgradeInfo, error) {
 		CurrentVersion: currentVersion,
 	}
 
+	if registry.IsOCI(repository) {
+		latestVersion, err := c.latestOCIVersion(ociChartRef(repository, chartName))
+		if err != nil {
+			info.Error = err.Error()
+			return info, nil
+		}
+		info.LatestVersion = latestVersion
+		info.RepositoryName = repository
+		info.UpdateAvailable = compareVersions(latestVersion, currentVersion) > 0
+		return info, nil
+	}
+
 	// Load repository file
 	repoFile := c.settings.RepositoryConfig
 	f, err := repo.LoadFile(repoFile)
@@ -814,8 +823,8 @@ func compareVersions(v1, v2 string) int {
 }
 
 // Rollback rolls back a release to a previous revision
-func (c *Client) Rollback(namespace, name string, revision int) error {
-	actionConfig, err := c.getActionConfig(namespace)
+func (c *Client) Rollback(ctx context.Context, namespace, name string, revision int) error {
+	actionConfig, err := c.getActionConfigFor(ctx, namespace)
 	if err != nil {
 		return err
 	}
@@ -833,8 +842,8 @@ func (c *Client) Rollback(namespace, name string, revision int) error {
 }
 
 // Uninstall removes a release
-func (c *Client) Uninstall(namespace, name string) error {
-	actionConfig, err := c.getActionConfig(namespace)
+func (c *Client) Uninstall(ctx context.Context, namespace, name string) error {
+	actionConfig, err := c.getActionConfigFor(ctx, namespace)
 	if err != nil {
 		return err
 	}
@@ -851,9 +860,10 @@ func (c *Client) Uninstall(namespace, name string) error {
 	return nil
 }
 
-// Upgrade upgrades a release to a new version
-func (c *Client) Upgrade(namespace, name, targetVersion string) error {
-	actionConfig, err := c.getActionConfig(namespace)
+// Upgrade upgrades a release to a new version. repository is an oci:// repository for OCI
+// charts; otherwise the configured repositories are searched.
+func (c *Client) Upgrade(ctx context.Context, namespace, name, repository, targetVersion string) error {
+	actionConfig, err := c.getActionConfigFor(ctx, namespace)
 	if err != nil {
 		return err
 	}
@@ -865,8 +875,128 @@ func (c *Client) Upgrade(namespace, name, targetVersion string) error {
 		return fmt.Errorf("failed to get current release: %w", err)
 	}
 
-	chartName := rel.Chart.Metadata.Name
+	// Create upgrade action
+	upgradeAction := action.NewUpgrade(actionConfig)
+	upgradeAction.Namespace = namespace
+	upgradeAction.Wait = true
+	upgradeAction.Timeout = 300 * time.Second
+	upgradeAction.ReuseValues = true // Keep existing values
+	upgradeAction.PostRenderer = gatePostRenderer(namespace)
+
+	newChart, err := c.loadRepoChart(actionConfig, repository, rel.Chart.Metadata.Name, targetVersion)
+	if err != nil {
+		return err
+	}
+
+	// The reused values must still satisfy the new version's schema
+	if err := validateValues(newChart, rel.Config); err != nil {
+		return err
+	}
+
+	// Run the upgrade
+	_, err = upgradeAction.Run(name, newChart, rel.Config)
+	if err != nil {
+		return fmt.Errorf("upgrade failed: %w", err)
+	}
+
+	return nil
+}
+
+// PreviewUpgrade renders targetVersion of the release's chart with its current values
+// (client-side dry-run, as Upgrade would run it) and diffs the result against the
+// installed manifest. An empty targetVersion previews the latest version in the
+// configured repositories, or in repository when it's an oci:// reference.
+func (c *Client) PreviewUpgrade(namespace, name, repository, targetVersion string, format yamldiff.Format) (*UpgradePreview, error) {
+	actionConfig, err := c.getActionConfig(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	getAction := action.NewGet(actionConfig)
+	rel, err := getAction.Run(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current release: %w", err)
+	}
+
+	if targetVersion == "" {
+		info, err := c.CheckForUpgrade(namespace, name, repository)
+		if err != nil {
+			return nil, err
+		}
+		if info.LatestVersion == "" {
+			return nil, fmt.Errorf("no target version: %s", info.Error)
+		}
+		targetVersion = info.LatestVersion
+	}
+
+	newChart, err := c.loadRepoChart(actionConfig, repository, rel.Chart.Metadata.Name, targetVersion)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateValues(newChart, rel.Config); err != nil {
+		return nil, err
+	}
+
+	upgradeAction := action.NewUpgrade(actionConfig)
+	upgradeAction.Namespace = namespace
+	upgradeAction.DryRun = true
+	upgradeAction.DryRunOption = "client"
+	upgradeAction.ReuseValues = true // Same values Upgrade would use
+
+	newRel, err := upgradeAction.Run(name, newChart, rel.Config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render version %s: %w", targetVersion, err)
+	}
+
+	changes := yamldiff.Compare(rel.Manifest, newRel.Manifest)
+	preview := &UpgradePreview{
+		CurrentVersion: rel.Chart.Metadata.Version,
+		TargetVersion:  targetVersion,
+		Format:         format,
+		Changes:        changes,
+	}
+	if format != yamldiff.FormatJSON {
+		if preview.Diff, err = yamldiff.RenderString(format, changes); err != nil {
+			return nil, err
+		}
+	}
+	return preview, nil
+}
+
+// loadRepoChart downloads and loads a chart version. An oci:// repository is pulled from
+// directly; otherwise the chart is looked up in the configured repositories (only the
+// named one, if repository is set).
+func (c *Client) loadRepoChart(actionConfig *action.Configuration, repository, chartName, version string) (*chart.Chart, error) {
+	chartPath := ociChartRef(repository, chartName)
+	if !registry.IsOCI(repository) {
+		var err error
+		if chartPath, err = c.findRepoChartURL(repository, chartName, version); err != nil {
+			return nil, err
+		}
+	}
+
+	// Download and load the chart
+	// Use ChartPathOptions to locate/download the chart
+	client := action.NewInstall(actionConfig)
+	client.Version = version
+
+	// Get chart path (will download if needed)
+	cp, err := client.ChartPathOptions.LocateChart(chartPath, c.settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate chart: %w", err)
+	}
+
+	// Load the chart from the path
+	loaded, err := loader.Load(cp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chart: %w", err)
+	}
 
+	return loaded, nil
+}
+
+// findRepoChartURL returns the download URL of a chart version from the configured repositories
+func (c *Client) findRepoChartURL(repository, chartName, version string) (string, error) {
 	// Find the chart in local repos
 	repoFile := c.settings.RepositoryConfig
 	repoCache := c.settings.RepositoryCache
@@ -874,12 +1004,15 @@ func (c *Client) Upgrade(namespace, name, targetVersion string) error {
 	// Load repo file
 	repos, err := repo.LoadFile(repoFile)
 	if err != nil {
-		return fmt.Errorf("failed to load repo file: %w", err)
+		return "", fmt.Errorf("failed to load repo file: %w", err)
 	}
 
 	// Find the chart in repos
 	var chartPath string
 	for _, r := range repos.Repositories {
+		if repository != "" && r.Name != repository {
+			continue
+		}
 		indexPath := filepath.Join(repoCache, r.Name+"-index.yaml")
 		idx, err := repo.LoadIndexFile(indexPath)
 		if err != nil {
@@ -888,7 +1021,7 @@ func (c *Client) Upgrade(namespace, name, targetVersion string) error {
 
 		if entries, ok := idx.Entries[chartName]; ok {
 			for _, entry := range entries {
-				if entry.Version == targetVersion {
+				if entry.Version == version {
 					// Found the chart - we need to download it
 					if len(entry.URLs) > 0 {
 						// Use helm's chart downloader
@@ -908,40 +1041,9 @@ func (c *Client) Upgrade(namespace, name, targetVersion string) error {
 	}
 
 	if chartPath == "" {
-		return fmt.Errorf("chart %s version %s not found in configured repositories", chartName, targetVersion)
-	}
-
-	// Create upgrade action
-	upgradeAction := action.NewUpgrade(actionConfig)
-	upgradeAction.Namespace = namespace
-	upgradeAction.Wait = true
-	upgradeAction.Timeout = 300 * time.Second
-	upgradeAction.ReuseValues = true // Keep existing values
-
-	// Download and load the chart
-	// Use ChartPathOptions to locate/download the chart
-	client := action.NewInstall(actionConfig)
-	client.Version = targetVersion
-
-	// Get chart path (will download if needed)
-	cp, err := client.ChartPathOptions.LocateChart(chartPath, c.settings)
-	if err != nil {
-		return fmt.Errorf("failed to locate chart: %w", err)
-	}
-
-	// Load the chart from the path
-	chart, err := loader.Load(cp)
-	if err != nil {
-		return fmt.Errorf("failed to load chart: %w", err)
+		return "", fmt.Errorf("chart %s version %s not found in configured repositories", chartName, version)
 	}
-
-	// Run the upgrade
-	_, err = upgradeAction.Run(name, chart, rel.Config)
-	if err != nil {
-		return fmt.Errorf("upgrade failed: %w", err)
-	}
-
-	return nil
+	return chartPath, nil
 }
 
 // BatchCheckUpgrades checks for upgrades for all releases at once (more efficient)
@@ -1063,25 +1165,34 @@ func (c *Client) PreviewValuesChange(namespace, name string, newValues map[strin
 	upgradeAction.DryRunOption = "client"
 	upgradeAction.ResetValues = true // Use only the provided values, don't merge
 
+	if err := validateValues(rel.Chart, newValues); err != nil {
+		return nil, err
+	}
+
 	// Run the dry-run upgrade
 	newRel, err := upgradeAction.Run(name, rel.Chart, newValues)
 	if err != nil {
 		return nil, fmt.Errorf("failed to preview values change: %w", err)
 	}
 
-	// Compute the manifest diff
-	diff := computeDiff(currentManifest, newRel.Manifest, rel.Version, rel.Version)
+	manifestChanges := yamldiff.Compare(currentManifest, newRel.Manifest)
+	manifestDiff, err := yamldiff.RenderString(yamldiff.FormatUnified, manifestChanges)
+	if err != nil {
+		return nil, err
+	}
 
 	return &ValuesPreviewResponse{
-		CurrentValues: currentValues,
-		NewValues:     newValues,
-		ManifestDiff:  diff,
+		CurrentValues:   currentValues,
+		NewValues:       newValues,
+		ValuesChanges:   yamldiff.CompareObjects("values.yaml", currentValues, newValues),
+		ManifestDiff:    manifestDiff,
+		ManifestChanges: manifestChanges,
 	}, nil
 }
 
 // ApplyValues upgrades a release with new values (same chart version)
-func (c *Client) ApplyValues(namespace, name string, newValues map[string]any) error {
-	actionConfig, err := c.getActionConfig(namespace)
+func (c *Client) ApplyValues(ctx context.Context, namespace, name string, newValues map[string]any) error {
+	actionConfig, err := c.getActionConfigFor(ctx, namespace)
 	if err != nil {
 		return err
 	}
@@ -1099,6 +1210,11 @@ func (c *Client) ApplyValues(namespace, name string, newValues map[string]any) e
 	upgradeAction.Wait = true
 	upgradeAction.Timeout = 300 * time.Second
 	upgradeAction.ResetValues = true // Use only the provided values, don't merge
+	upgradeAction.PostRenderer = gatePostRenderer(namespace)
+
+	if err := validateValues(rel.Chart, newValues); err != nil {
+		return err
+	}
 
 	// Run the upgrade with the existing chart and new values
 	_, err = upgradeAction.Run(name, rel.Chart, newValues)
@@ -1181,8 +1297,13 @@ func (c *Client) UpdateRepository(repoName string) error {
 	return nil
 }
 
-// SearchCharts searches for charts across all repositories
+// SearchCharts searches for charts across all repositories. A query that is an oci://
+// chart reference lists that chart's versions from its registry instead.
 func (c *Client) SearchCharts(query string, allVersions bool) (*ChartSearchResult, error) {
+	if registry.IsOCI(query) {
+		return c.searchOCIChart(query, allVersions)
+	}
+
 	repoFile := c.settings.RepositoryConfig
 	f, err := repo.LoadFile(repoFile)
 	if err != nil {
@@ -1256,8 +1377,13 @@ func (c *Client) SearchCharts(query string, allVersions bool) (*ChartSearchResul
 	}, nil
 }
 
-// GetChartDetail returns detailed information about a specific chart version
+// GetChartDetail returns detailed information about a specific chart version. repoName
+// may be an oci:// repository.
 func (c *Client) GetChartDetail(repoName, chartName, version string) (*ChartDetail, error) {
+	if registry.IsOCI(repoName) {
+		return c.getOCIChartDetail(repoName, chartName, version)
+	}
+
 	repoFile := c.settings.RepositoryConfig
 	f, err := repo.LoadFile(repoFile)
 	if err != nil {
@@ -1337,9 +1463,13 @@ func (c *Client) GetChartDetail(repoName, chartName, version string) (*ChartDeta
 		}, nil
 	}
 
-	// Build detail response
+	return buildChartDetail(chartVersionToInfo(chartVersion, repoName), chart), nil
+}
+
+// buildChartDetail adds a loaded chart's README, values and metadata to its ChartInfo
+func buildChartDetail(info ChartInfo, chart *chart.Chart) *ChartDetail {
 	detail := &ChartDetail{
-		ChartInfo: chartVersionToInfo(chartVersion, repoName),
+		ChartInfo: info,
 	}
 
 	// Extract README
@@ -1376,12 +1506,12 @@ func (c *Client) GetChartDetail(repoName, chartName, version string) (*ChartDeta
 	detail.Sources = chart.Metadata.Sources
 	detail.Keywords = chart.Metadata.Keywords
 
-	return detail, nil
+	return detail
 }
 
 // Install installs a new Helm release
-func (c *Client) Install(req *InstallRequest) (*HelmRelease, error) {
-	actionConfig, err := c.getActionConfig(req.Namespace)
+func (c *Client) Install(ctx context.Context, req *InstallRequest) (*HelmRelease, error) {
+	actionConfig, err := c.getActionConfigFor(ctx, req.Namespace)
 	if err != nil {
 		return nil, err
 	}
@@ -1391,7 +1521,11 @@ func (c *Client) Install(req *InstallRequest) (*HelmRelease, error) {
 	// Check if the repository is a URL (for ArtifactHub installs) or a local repo name
 	isRepoURL := strings.HasPrefix(req.Repository, "http://") || strings.HasPrefix(req.Repository, "https://")
 
-	if isRepoURL {
+	switch {
+	case registry.IsOCI(req.Repository):
+		// OCI registry - the chart is pulled by reference, versions are tags
+		chartURL = ociChartRef(req.Repository, req.ChartName)
+	case isRepoURL:
 		// Direct URL - fetch the repository index to find the chart
 		repoURL := strings.TrimSuffix(req.Repository, "/")
 
@@ -1454,7 +1588,7 @@ func (c *Client) Install(req *InstallRequest) (*HelmRelease, error) {
 		if !strings.HasPrefix(chartURL, "http://") && !strings.HasPrefix(chartURL, "https://") {
 			chartURL = repoURL + "/" + chartURL
 		}
-	} else {
+	default:
 		// Local repository name - use existing logic
 		repoFile := c.settings.RepositoryConfig
 		f, err := repo.LoadFile(repoFile)
@@ -1518,7 +1652,8 @@ func (c *Client) Install(req *InstallRequest) (*HelmRelease, error) {
 	installAction.CreateNamespace = req.CreateNamespace
 	installAction.Wait = true
 	installAction.Timeout = 300 * time.Second
-	installAction.Version = req.Version
+	installAction.Version = installVersion(req.Version)
+	installAction.PostRenderer = gatePostRenderer(req.Namespace)
 
 	// Locate/download chart
 	cp, err := installAction.ChartPathOptions.LocateChart(chartURL, c.settings)
@@ -1532,6 +1667,10 @@ func (c *Client) Install(req *InstallRequest) (*HelmRelease, error) {
 		return nil, fmt.Errorf("failed to load chart: %w", err)
 	}
 
+	if err := validateValues(chart, req.Values); err != nil {
+		return nil, err
+	}
+
 	// Run install
 	rel, err := installAction.Run(chart, req.Values)
 	if err != nil {
@@ -1551,7 +1690,7 @@ func (c *Client) Install(req *InstallRequest) (*HelmRelease, error) {
 }
 
 // InstallWithProgress installs a new Helm release and streams progress updates
-func (c *Client) InstallWithProgress(req *InstallRequest, progressCh chan<- InstallProgress) (*HelmRelease, error) {
+func (c *Client) InstallWithProgress(ctx context.Context, req *InstallRequest, progressCh chan<- InstallProgress) (*HelmRelease, error) {
 	sendProgress := func(phase, message, detail string) {
 		select {
 		case progressCh <- InstallProgress{Phase: phase, Message: message, Detail: detail}:
@@ -1560,7 +1699,7 @@ func (c *Client) InstallWithProgress(req *InstallRequest, progressCh chan<- Inst
 		}
 	}
 
-	actionConfig, err := c.getActionConfig(req.Namespace)
+	actionConfig, err := c.getActionConfigFor(ctx, req.Namespace)
 	if err != nil {
 		return nil, err
 	}
@@ -1570,7 +1709,10 @@ func (c *Client) InstallWithProgress(req *InstallRequest, progressCh chan<- Inst
 	// Check if the repository is a URL (for ArtifactHub installs) or a local repo name
 	isRepoURL := strings.HasPrefix(req.Repository, "http://") || strings.HasPrefix(req.Repository, "https://")
 
-	if isRepoURL {
+	switch {
+	case registry.IsOCI(req.Repository):
+		chartURL = ociChartRef(req.Repository, req.ChartName)
+	case isRepoURL:
 		sendProgress("fetching", "Fetching repository index...", req.Repository)
 
 		repoURL := strings.TrimSuffix(req.Repository, "/")
@@ -1631,7 +1773,7 @@ func (c *Client) InstallWithProgress(req *InstallRequest, progressCh chan<- Inst
 		if !strings.HasPrefix(chartURL, "http://") && !strings.HasPrefix(chartURL, "https://") {
 			chartURL = repoURL + "/" + chartURL
 		}
-	} else {
+	default:
 		sendProgress("resolving", "Resolving chart from local repository...", req.Repository)
 
 		repoFile := c.settings.RepositoryConfig
@@ -1694,7 +1836,8 @@ func (c *Client) InstallWithProgress(req *InstallRequest, progressCh chan<- Inst
 	installAction.CreateNamespace = req.CreateNamespace
 	installAction.Wait = true
 	installAction.Timeout = 300 * time.Second
-	installAction.Version = req.Version
+	installAction.Version = installVersion(req.Version)
+	installAction.PostRenderer = gatePostRenderer(req.Namespace)
 
 	cp, err := installAction.ChartPathOptions.LocateChart(chartURL, c.settings)
 	if err != nil {
@@ -1708,6 +1851,11 @@ func (c *Client) InstallWithProgress(req *InstallRequest, progressCh chan<- Inst
 		return nil, fmt.Errorf("failed to load chart: %w", err)
 	}
 
+	sendProgress("validating", "Validating values against chart schema...", "")
+	if err := validateValues(chart, req.Values); err != nil {
+		return nil, err
+	}
+
 	sendProgress("installing", fmt.Sprintf("Installing %s to namespace %s...", req.ReleaseName, req.Namespace), "")
 
 	if req.CreateNamespace {
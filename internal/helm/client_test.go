@@ -0,0 +1,107 @@
+package helm
+
+import (
+	"reflect"
+	"testing"
+)
+
+const testManifest = `---
+# Source: web/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+metadata:
+  name: "web"
+---
+# Source: web/templates/empty.yaml
+---
+# Source: web/templates/configmaps.yaml
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: web-a
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: web-b
+    namespace: other
+---
+# Source: web/templates/certificate.yaml
+apiVersion: cert-manager.io/v1
+kind: Certificate
+metadata:
+  name: web-tls
+---
+# Source: web/templates/migrate.yaml
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: web-migrate
+  annotations:
+    helm.sh/hook: pre-upgrade
+`
+
+func TestParseManifestResources(t *testing.T) {
+	got := parseManifestResources(testManifest, "prod")
+	want := []OwnedResource{
+		{Kind: "Certificate", Group: "cert-manager.io", APIVersion: "cert-manager.io/v1", Name: "web-tls", Namespace: "prod"},
+		{Kind: "ConfigMap", APIVersion: "v1", Name: "web-a", Namespace: "prod"},
+		{Kind: "ConfigMap", APIVersion: "v1", Name: "web-b", Namespace: "other"},
+		{Kind: "Deployment", APIVersion: "apps/v1", Name: "web", Namespace: "prod"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseManifestResources() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+// Documents whose nested fields look like top-level ones, a first document without a
+// separator, and separators followed by comments
+const trickyManifest = `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  names:
+    kind: Widget
+    plural: widgets
+  versions:
+  - name: v1
+metadata:
+  name: widgets.example.com
+--- # Source: web/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  labels:
+    name: not-the-name
+  name: web
+  namespace: edge
+spec:
+  ports:
+  - name: http
+    port: 80
+---
+# A document with only comments
+---
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: "web: primary"
+`
+
+func TestParseManifestResourcesNestedFields(t *testing.T) {
+	got := parseManifestResources(trickyManifest, "prod")
+	want := []OwnedResource{
+		{Kind: "CustomResourceDefinition", APIVersion: "apiextensions.k8s.io/v1", Name: "widgets.example.com", Namespace: "prod"},
+		{Kind: "Service", APIVersion: "v1", Name: "web", Namespace: "edge"},
+		{Kind: "Widget", Group: "example.com", APIVersion: "example.com/v1", Name: "web: primary", Namespace: "prod"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseManifestResources() =\n%+v\nwant\n%+v", got, want)
+	}
+}
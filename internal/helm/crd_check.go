@@ -0,0 +1,328 @@
+package helm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+var crdGVR = schema.GroupVersionResource{
+	Group:    "apiextensions.k8s.io",
+	Version:  "v1",
+	Resource: "customresourcedefinitions",
+}
+
+// CheckCRDCompatibility compares the CRDs of a target chart version against the CRDs
+// installed in the cluster. It flags storage version changes and served versions that are
+// dropped while existing objects may still be stored in them.
+//
+// Both the chart's crds/ directory and CRDs rendered from its templates (with the
+// release's values) are inspected. Helm applies only the latter on upgrade.
+func (c *Client) CheckCRDCompatibility(ctx context.Context, namespace, name, repository, targetVersion string) (*CRDCompatibilityReport, error) {
+	actionConfig, err := c.getActionConfig(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	getAction := action.NewGet(actionConfig)
+	rel, err := getAction.Run(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current release: %w", err)
+	}
+
+	newChart, err := c.loadRepoChart(actionConfig, repository, rel.Chart.Metadata.Name, targetVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	// Render the templates the way the upgrade would, to find the CRDs they create
+	upgradeAction := action.NewUpgrade(actionConfig)
+	upgradeAction.Namespace = namespace
+	upgradeAction.DryRun = true
+	upgradeAction.DryRunOption = "client"
+	upgradeAction.ReuseValues = true
+	newRel, err := upgradeAction.Run(name, newChart, rel.Config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render version %s: %w", targetVersion, err)
+	}
+
+	dynamicClient := k8s.GetDynamicClient()
+	if dynamicClient == nil {
+		return nil, fmt.Errorf("dynamic client not initialized")
+	}
+
+	report := &CRDCompatibilityReport{
+		Release:       name,
+		Namespace:     namespace,
+		Chart:         rel.Chart.Metadata.Name,
+		TargetVersion: targetVersion,
+		CRDs:          []CRDCompatibility{},
+	}
+
+	for _, chartCRD := range chartCRDs(newChart.CRDObjects(), newRel.Manifest) {
+		crd := chartCRD.crd
+		result := CRDCompatibility{
+			Name:         crd.Name,
+			File:         chartCRD.file,
+			FromTemplate: chartCRD.fromTemplate,
+			NewStorage:   storageVersion(crd),
+			NewServed:    servedVersions(crd),
+			Findings:     []CRDCheckFinding{},
+		}
+
+		reqCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		obj, err := dynamicClient.Resource(crdGVR).Get(reqCtx, crd.Name, metav1.GetOptions{})
+		cancel()
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, fmt.Errorf("failed to get installed CRD %s: %w", crd.Name, err)
+			}
+			result.Findings = append(result.Findings, CRDCheckFinding{
+				Severity: CRDCheckInfo,
+				Message:  "CRD is not installed yet and will be created",
+			})
+			report.CRDs = append(report.CRDs, result)
+			continue
+		}
+
+		var installed apiextensionsv1.CustomResourceDefinition
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &installed); err != nil {
+			return nil, fmt.Errorf("failed to decode installed CRD %s: %w", crd.Name, err)
+		}
+
+		result.Installed = true
+		result.InstalledStorage = storageVersion(&installed)
+		result.InstalledServed = servedVersions(&installed)
+		result.StoredVersions = installed.Status.StoredVersions
+		result.Findings = compareCRDs(&installed, crd)
+		if len(result.Findings) > 0 && !chartCRD.fromTemplate {
+			// Helm never upgrades CRDs from crds/, so call it out alongside the findings
+			result.Findings = append(result.Findings, CRDCheckFinding{
+				Severity: CRDCheckInfo,
+				Message:  "Helm does not update CRDs from the crds/ directory on upgrade; these changes must be applied separately",
+			})
+		}
+
+		report.CRDs = append(report.CRDs, result)
+	}
+
+	sort.Slice(report.CRDs, func(i, j int) bool {
+		return report.CRDs[i].Name < report.CRDs[j].Name
+	})
+
+	for _, crd := range report.CRDs {
+		for _, f := range crd.Findings {
+			switch f.Severity {
+			case CRDCheckWarning:
+				report.Warnings++
+			case CRDCheckDanger:
+				report.Dangers++
+			}
+		}
+	}
+	report.Safe = report.Warnings == 0 && report.Dangers == 0
+
+	return report, nil
+}
+
+// compareCRDs returns findings for moving from the installed CRD to the new one
+func compareCRDs(installed, updated *apiextensionsv1.CustomResourceDefinition) []CRDCheckFinding {
+	findings := []CRDCheckFinding{}
+
+	newVersions := make(map[string]apiextensionsv1.CustomResourceDefinitionVersion)
+	for _, v := range updated.Spec.Versions {
+		newVersions[v.Name] = v
+	}
+
+	// Storage version changes require existing objects to be migrated
+	oldStorage, newStorage := storageVersion(installed), storageVersion(updated)
+	if oldStorage != "" && newStorage != "" && oldStorage != newStorage {
+		findings = append(findings, CRDCheckFinding{
+			Severity: CRDCheckWarning,
+			Version:  newStorage,
+			Message:  fmt.Sprintf("storage version changes from %s to %s; existing objects stay stored as %s until rewritten", oldStorage, newStorage, oldStorage),
+		})
+	}
+
+	// Versions that existing objects are stored in must remain in the CRD
+	for _, stored := range installed.Status.StoredVersions {
+		v, ok := newVersions[stored]
+		if !ok {
+			findings = append(findings, CRDCheckFinding{
+				Severity: CRDCheckDanger,
+				Version:  stored,
+				Message:  fmt.Sprintf("version %s is removed but is still listed in status.storedVersions; the API server will reject the update until objects are migrated", stored),
+			})
+		} else if !v.Served {
+			findings = append(findings, CRDCheckFinding{
+				Severity: CRDCheckWarning,
+				Version:  stored,
+				Message:  fmt.Sprintf("version %s stops being served while objects are still stored in it", stored),
+			})
+		}
+	}
+
+	// Served versions that disappear break clients still using them
+	for _, v := range installed.Spec.Versions {
+		if !v.Served || containsString(installed.Status.StoredVersions, v.Name) {
+			continue
+		}
+		nv, ok := newVersions[v.Name]
+		if !ok || !nv.Served {
+			findings = append(findings, CRDCheckFinding{
+				Severity: CRDCheckWarning,
+				Version:  v.Name,
+				Message:  fmt.Sprintf("served version %s is dropped; clients and manifests using it will fail", v.Name),
+			})
+		}
+	}
+
+	for _, v := range updated.Spec.Versions {
+		if _, ok := findVersion(installed, v.Name); !ok {
+			findings = append(findings, CRDCheckFinding{
+				Severity: CRDCheckInfo,
+				Version:  v.Name,
+				Message:  fmt.Sprintf("new version %s is added", v.Name),
+			})
+		}
+	}
+
+	// Removed schema properties may silently prune fields from existing objects
+	for _, v := range installed.Spec.Versions {
+		nv, ok := newVersions[v.Name]
+		if !ok || v.Schema == nil || nv.Schema == nil || v.Schema.OpenAPIV3Schema == nil || nv.Schema.OpenAPIV3Schema == nil {
+			continue
+		}
+		removed := removedProperties(v.Schema.OpenAPIV3Schema, nv.Schema.OpenAPIV3Schema, "")
+		if len(removed) > 0 {
+			sort.Strings(removed)
+			if len(removed) > 10 {
+				removed = append(removed[:10], fmt.Sprintf("... and %d more", len(removed)-10))
+			}
+			findings = append(findings, CRDCheckFinding{
+				Severity: CRDCheckWarning,
+				Version:  v.Name,
+				Message:  fmt.Sprintf("schema removes fields: %v", removed),
+			})
+		}
+	}
+
+	return findings
+}
+
+// chartCRD is a CRD a chart ships and the file it comes from
+type chartCRD struct {
+	crd          *apiextensionsv1.CustomResourceDefinition
+	file         string
+	fromTemplate bool
+}
+
+// chartCRDs returns the CRDs in a chart's crds/ files and in its rendered manifest. A CRD
+// in both is reported once, from the templates, since that's the copy Helm applies.
+func chartCRDs(crdFiles []chart.CRD, manifest string) []chartCRD {
+	var crds []chartCRD
+	seen := make(map[string]int)
+	add := func(doc, file string, fromTemplate bool) {
+		var crd apiextensionsv1.CustomResourceDefinition
+		if err := yaml.Unmarshal([]byte(doc), &crd); err != nil || crd.Kind != "CustomResourceDefinition" || crd.Name == "" {
+			return
+		}
+		entry := chartCRD{crd: &crd, file: file, fromTemplate: fromTemplate}
+		if i, ok := seen[crd.Name]; ok {
+			crds[i] = entry
+			return
+		}
+		seen[crd.Name] = len(crds)
+		crds = append(crds, entry)
+	}
+
+	for _, crdFile := range crdFiles {
+		for _, doc := range releaseutil.SplitManifests(string(crdFile.File.Data)) {
+			add(doc, crdFile.Name, false)
+		}
+	}
+	for _, doc := range releaseutil.SplitManifests(manifest) {
+		add(doc, manifestSource(doc), true)
+	}
+	return crds
+}
+
+// manifestSource returns the template a rendered document came from ("# Source: ...")
+func manifestSource(doc string) string {
+	for _, line := range strings.Split(doc, "\n") {
+		if source, ok := strings.CutPrefix(strings.TrimSpace(line), "# Source: "); ok {
+			return source
+		}
+	}
+	return ""
+}
+
+// removedProperties lists property paths present in old but missing from new, descending
+// into array items ("ports[].name") and map values ("labels.*.name")
+func removedProperties(old, updated *apiextensionsv1.JSONSchemaProps, prefix string) []string {
+	var removed []string
+	for name, oldProp := range old.Properties {
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+		newProp, ok := updated.Properties[name]
+		if !ok {
+			removed = append(removed, path)
+			continue
+		}
+		removed = append(removed, removedProperties(&oldProp, &newProp, path)...)
+	}
+	if old.Items != nil && old.Items.Schema != nil && updated.Items != nil && updated.Items.Schema != nil {
+		removed = append(removed, removedProperties(old.Items.Schema, updated.Items.Schema, prefix+"[]")...)
+	}
+	if old.AdditionalProperties != nil && old.AdditionalProperties.Schema != nil &&
+		updated.AdditionalProperties != nil && updated.AdditionalProperties.Schema != nil {
+		removed = append(removed, removedProperties(old.AdditionalProperties.Schema, updated.AdditionalProperties.Schema, prefix+".*")...)
+	}
+	return removed
+}
+
+// storageVersion returns the version marked as storage
+func storageVersion(crd *apiextensionsv1.CustomResourceDefinition) string {
+	for _, v := range crd.Spec.Versions {
+		if v.Storage {
+			return v.Name
+		}
+	}
+	return ""
+}
+
+// servedVersions returns the names of all served versions
+func servedVersions(crd *apiextensionsv1.CustomResourceDefinition) []string {
+	var served []string
+	for _, v := range crd.Spec.Versions {
+		if v.Served {
+			served = append(served, v.Name)
+		}
+	}
+	return served
+}
+
+func findVersion(crd *apiextensionsv1.CustomResourceDefinition, name string) (apiextensionsv1.CustomResourceDefinitionVersion, bool) {
+	for _, v := range crd.Spec.Versions {
+		if v.Name == name {
+			return v, true
+		}
+	}
+	return apiextensionsv1.CustomResourceDefinitionVersion{}, false
+}
@@ -0,0 +1,163 @@
+package helm
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/chart"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func testCRD(storedVersions []string, versions ...apiextensionsv1.CustomResourceDefinitionVersion) *apiextensionsv1.CustomResourceDefinition {
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	crd.Name = "widgets.example.com"
+	crd.Spec.Versions = versions
+	crd.Status.StoredVersions = storedVersions
+	return crd
+}
+
+func crdVersion(name string, served, storage bool) apiextensionsv1.CustomResourceDefinitionVersion {
+	return apiextensionsv1.CustomResourceDefinitionVersion{Name: name, Served: served, Storage: storage}
+}
+
+func countSeverity(findings []CRDCheckFinding, sev CRDCheckSeverity) int {
+	n := 0
+	for _, f := range findings {
+		if f.Severity == sev {
+			n++
+		}
+	}
+	return n
+}
+
+func TestCompareCRDs_NoChanges(t *testing.T) {
+	installed := testCRD([]string{"v1"}, crdVersion("v1", true, true))
+	updated := testCRD(nil, crdVersion("v1", true, true))
+
+	findings := compareCRDs(installed, updated)
+	if len(findings) != 0 {
+		t.Errorf("expected no findings, got %+v", findings)
+	}
+}
+
+func TestCompareCRDs_StorageVersionChange(t *testing.T) {
+	installed := testCRD([]string{"v1alpha1"}, crdVersion("v1alpha1", true, true))
+	updated := testCRD(nil, crdVersion("v1alpha1", true, false), crdVersion("v1", true, true))
+
+	findings := compareCRDs(installed, updated)
+	if countSeverity(findings, CRDCheckWarning) != 1 {
+		t.Errorf("expected 1 warning for storage change, got %+v", findings)
+	}
+	if countSeverity(findings, CRDCheckDanger) != 0 {
+		t.Errorf("expected no danger findings, got %+v", findings)
+	}
+}
+
+func TestCompareCRDs_StoredVersionRemoved(t *testing.T) {
+	installed := testCRD([]string{"v1alpha1", "v1"}, crdVersion("v1alpha1", true, false), crdVersion("v1", true, true))
+	updated := testCRD(nil, crdVersion("v1", true, true))
+
+	findings := compareCRDs(installed, updated)
+	if countSeverity(findings, CRDCheckDanger) != 1 {
+		t.Fatalf("expected 1 danger finding, got %+v", findings)
+	}
+	for _, f := range findings {
+		if f.Severity == CRDCheckDanger && f.Version != "v1alpha1" {
+			t.Errorf("expected danger for v1alpha1, got %s", f.Version)
+		}
+	}
+}
+
+func TestCompareCRDs_ServedVersionDropped(t *testing.T) {
+	installed := testCRD([]string{"v1"}, crdVersion("v1beta1", true, false), crdVersion("v1", true, true))
+	updated := testCRD(nil, crdVersion("v1beta1", false, false), crdVersion("v1", true, true))
+
+	findings := compareCRDs(installed, updated)
+	if countSeverity(findings, CRDCheckWarning) != 1 {
+		t.Errorf("expected 1 warning for dropped served version, got %+v", findings)
+	}
+}
+
+func TestRemovedProperties(t *testing.T) {
+	old := &apiextensionsv1.JSONSchemaProps{
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{
+			"spec": {Properties: map[string]apiextensionsv1.JSONSchemaProps{
+				"replicas": {Type: "integer"},
+				"legacy":   {Type: "string"},
+			}},
+		},
+	}
+	updated := &apiextensionsv1.JSONSchemaProps{
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{
+			"spec": {Properties: map[string]apiextensionsv1.JSONSchemaProps{
+				"replicas": {Type: "integer"},
+			}},
+		},
+	}
+
+	removed := removedProperties(old, updated, "")
+	if len(removed) != 1 || !strings.HasSuffix(removed[0], "spec.legacy") {
+		t.Errorf("expected [spec.legacy], got %v", removed)
+	}
+}
+
+func TestRemovedProperties_ItemsAndMaps(t *testing.T) {
+	old := &apiextensionsv1.JSONSchemaProps{
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{
+			"ports": {Items: &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &apiextensionsv1.JSONSchemaProps{
+				Properties: map[string]apiextensionsv1.JSONSchemaProps{"name": {Type: "string"}, "port": {Type: "integer"}},
+			}}},
+			"routes": {AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{Schema: &apiextensionsv1.JSONSchemaProps{
+				Properties: map[string]apiextensionsv1.JSONSchemaProps{"host": {Type: "string"}, "weight": {Type: "integer"}},
+			}}},
+		},
+	}
+	updated := &apiextensionsv1.JSONSchemaProps{
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{
+			"ports": {Items: &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &apiextensionsv1.JSONSchemaProps{
+				Properties: map[string]apiextensionsv1.JSONSchemaProps{"port": {Type: "integer"}},
+			}}},
+			"routes": {AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{Schema: &apiextensionsv1.JSONSchemaProps{
+				Properties: map[string]apiextensionsv1.JSONSchemaProps{"host": {Type: "string"}},
+			}}},
+		},
+	}
+
+	removed := removedProperties(old, updated, "")
+	sort.Strings(removed)
+	if want := []string{"ports[].name", "routes.*.weight"}; !reflect.DeepEqual(removed, want) {
+		t.Errorf("removed = %v, want %v", removed, want)
+	}
+}
+
+func TestChartCRDs(t *testing.T) {
+	crdDoc := func(name string) string {
+		return "apiVersion: apiextensions.k8s.io/v1\nkind: CustomResourceDefinition\nmetadata:\n  name: " + name + "\n"
+	}
+	crdFiles := []chart.CRD{{
+		Name: "crds/widgets.yaml",
+		File: &chart.File{Name: "crds/widgets.yaml", Data: []byte(crdDoc("widgets.example.com") + "---\n" + crdDoc("gadgets.example.com"))},
+	}}
+	manifest := "---\n# Source: app/templates/crds.yaml\n" + crdDoc("gadgets.example.com") +
+		"---\n# Source: app/templates/crds.yaml\n" + crdDoc("gizmos.example.com") +
+		"---\n# Source: app/templates/service.yaml\napiVersion: v1\nkind: Service\nmetadata:\n  name: app\n"
+
+	got := map[string]chartCRD{}
+	for _, c := range chartCRDs(crdFiles, manifest) {
+		got[c.crd.Name] = c
+	}
+	if len(got) != 3 {
+		t.Fatalf("CRDs = %+v, want widgets, gadgets and gizmos", got)
+	}
+	if c := got["widgets.example.com"]; c.fromTemplate || c.file != "crds/widgets.yaml" {
+		t.Errorf("widgets = %+v, want from crds/", c)
+	}
+	// In both: the templated copy is the one Helm upgrades
+	for _, name := range []string{"gadgets.example.com", "gizmos.example.com"} {
+		if c := got[name]; !c.fromTemplate || c.file != "app/templates/crds.yaml" {
+			t.Errorf("%s = %+v, want from app/templates/crds.yaml", name, c)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package helm
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+
+	"helm.sh/helm/v3/pkg/release"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Labels and annotations that tie live objects to a release. Helm 3 annotates everything
+// it manages; charts conventionally also set the instance label.
+const (
+	releaseNameAnnotation      = "meta.helm.sh/release-name"
+	releaseNamespaceAnnotation = "meta.helm.sh/release-namespace"
+	instanceLabel              = "app.kubernetes.io/instance"
+)
+
+// driftSyncTimeout bounds the wait for a custom resource kind's first list
+const driftSyncTimeout = 3 * time.Second
+
+// ReleaseDrift compares a release's manifest with what's in the cluster
+type ReleaseDrift struct {
+	// Orphaned objects belong to the release but aren't in its manifest, typically left
+	// behind by an upgrade (resource-policy: keep, or a failed upgrade)
+	Orphaned []DriftResource `json:"orphaned"`
+	// Missing manifest entries aren't in the cluster (deleted by hand or never created)
+	Missing []DriftResource `json:"missing"`
+	// Unchecked kinds couldn't be listed (unknown to discovery, outside the watched
+	// namespaces, or still syncing), so drift in them isn't reported
+	Unchecked []string `json:"unchecked,omitempty"`
+}
+
+// DriftResource is an object on one side of the manifest/cluster comparison
+type DriftResource struct {
+	Kind      string `json:"kind"`
+	Group     string `json:"group,omitempty"` // API group, for custom resources only
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	// LastRevision is the newest revision whose manifest had an orphaned object (0 if the
+	// release never rendered it, e.g. only its instance label matches)
+	LastRevision int `json:"lastRevision,omitempty"`
+}
+
+// driftKind is a kind and the namespaces to look for it in ("" = cluster-scoped)
+type driftKind struct {
+	kind, group string
+	namespaces  map[string]bool
+}
+
+// liveLister lists the live objects of a kind in a namespace ("" = all namespaces); false
+// means the kind couldn't be listed
+type liveLister func(kind, group, namespace string) ([]metav1.Object, bool)
+
+// resourceKey identifies a resource across manifests and the cluster
+func resourceKey(kind, group, namespace, name string) string {
+	return group + "/" + kind + "/" + namespace + "/" + name
+}
+
+// computeDrift diffs the release's current manifest against the cluster. Only kinds the
+// release has ever rendered (in any revision in history) are searched for orphans.
+func computeDrift(rel *release.Release, history []*release.Release, list liveLister) *ReleaseDrift {
+	drift := &ReleaseDrift{Orphaned: []DriftResource{}, Missing: []DriftResource{}}
+
+	kinds := make(map[string]*driftKind)
+	lastRevision := make(map[string]int)
+	addKinds := func(resources []OwnedResource, revision int) {
+		for _, r := range resources {
+			gk := r.Group + "/" + r.Kind
+			if kinds[gk] == nil {
+				kinds[gk] = &driftKind{kind: r.Kind, group: r.Group, namespaces: make(map[string]bool)}
+			}
+			kinds[gk].namespaces[r.Namespace] = true
+			key := resourceKey(r.Kind, r.Group, r.Namespace, r.Name)
+			lastRevision[key] = max(lastRevision[key], revision)
+		}
+	}
+	current := parseManifestResources(rel.Manifest, rel.Namespace)
+	addKinds(current, rel.Version)
+	for _, h := range history {
+		if h.Version != rel.Version {
+			addKinds(parseManifestResources(h.Manifest, rel.Namespace), h.Version)
+		}
+	}
+	// Label-only orphans live in the release namespace, so always look there
+	for _, k := range kinds {
+		if !k.namespaces[""] {
+			k.namespaces[rel.Namespace] = true
+		}
+	}
+
+	inManifest := make(map[string]bool, len(current))
+	for _, r := range current {
+		inManifest[resourceKey(r.Kind, r.Group, r.Namespace, r.Name)] = true
+	}
+	live := make(map[string]bool)
+	checked := make(map[string]bool)
+
+	for gk, k := range kinds {
+		for ns := range k.namespaces {
+			objs, ok := list(k.kind, k.group, ns)
+			if !ok {
+				drift.Unchecked = append(drift.Unchecked, k.qualified())
+				break
+			}
+			for _, obj := range objs {
+				key := resourceKey(k.kind, k.group, obj.GetNamespace(), obj.GetName())
+				live[key] = true
+				if inManifest[key] || !belongsToRelease(obj, rel.Name, rel.Namespace) {
+					continue
+				}
+				drift.Orphaned = append(drift.Orphaned, DriftResource{
+					Kind:         k.kind,
+					Group:        k.group,
+					Name:         obj.GetName(),
+					Namespace:    obj.GetNamespace(),
+					LastRevision: lastRevision[key],
+				})
+			}
+		}
+		if !slices.Contains(drift.Unchecked, k.qualified()) {
+			checked[gk] = true
+		}
+	}
+
+	for _, r := range current {
+		key := resourceKey(r.Kind, r.Group, r.Namespace, r.Name)
+		if checked[r.Group+"/"+r.Kind] && !live[key] {
+			drift.Missing = append(drift.Missing, DriftResource{Kind: r.Kind, Group: r.Group, Name: r.Name, Namespace: r.Namespace})
+		}
+	}
+
+	sortDriftResources(drift.Orphaned)
+	sortDriftResources(drift.Missing)
+	sort.Strings(drift.Unchecked)
+	return drift
+}
+
+func (k *driftKind) qualified() string {
+	if k.group == "" {
+		return k.kind
+	}
+	return k.kind + "." + k.group
+}
+
+// belongsToRelease reports whether a live object was created for the release: Helm's
+// ownership annotations name it, or (without them) its instance label does and it's in
+// the release namespace. Objects created by controllers (pods, replica sets) inherit the
+// instance label from their templates and are skipped, as are hooks.
+func belongsToRelease(obj metav1.Object, name, namespace string) bool {
+	if metav1.GetControllerOf(obj) != nil {
+		return false
+	}
+	annotations := obj.GetAnnotations()
+	if _, isHook := annotations[release.HookAnnotation]; isHook {
+		return false
+	}
+	if owner, ok := annotations[releaseNameAnnotation]; ok {
+		return owner == name && annotations[releaseNamespaceAnnotation] == namespace
+	}
+	return obj.GetLabels()[instanceLabel] == name && obj.GetNamespace() == namespace
+}
+
+func sortDriftResources(resources []DriftResource) {
+	sort.Slice(resources, func(i, j int) bool {
+		a, b := resources[i], resources[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+}
+
+// listLiveObjects lists objects from the typed cache when it watches the kind, and from
+// the dynamic cache otherwise (waiting briefly for kinds it hasn't watched yet)
+func listLiveObjects(kind, group, namespace string) ([]metav1.Object, bool) {
+	cache := k8s.GetResourceCache()
+	if cache == nil {
+		return nil, false
+	}
+	if group == "" {
+		if objs, ok := cache.TypedObjects(kind, namespace); ok {
+			if namespace != "" && cache.IsNamespaceScoped() && !slices.Contains(cache.WatchedNamespaces(), namespace) {
+				return nil, false
+			}
+			return objs, true
+		}
+	}
+
+	discovery := k8s.GetResourceDiscovery()
+	dynamicCache := k8s.GetDynamicResourceCache()
+	if discovery == nil || dynamicCache == nil {
+		return nil, false
+	}
+	gvr, ok := discovery.GetGVRWithGroup(kind, group)
+	if group == "" {
+		gvr, ok = discovery.GetGVR(kind)
+	}
+	if !ok {
+		return nil, false
+	}
+	items, err := dynamicCache.ListBlocking(gvr, namespace, driftSyncTimeout)
+	if err != nil || !dynamicCache.IsSynced(gvr) {
+		return nil, false
+	}
+	objs := make([]metav1.Object, len(items))
+	for i, item := range items {
+		objs[i] = item
+	}
+	return objs, true
+}
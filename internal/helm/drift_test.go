@@ -0,0 +1,88 @@
+package helm
+
+import (
+	"reflect"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/release"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const driftManifestV1 = `---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web-legacy
+`
+
+const driftManifestV2 = `---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+---
+apiVersion: cert-manager.io/v1
+kind: Certificate
+metadata:
+  name: web-tls
+`
+
+func helmOwned(name string, extra map[string]string) *metav1.ObjectMeta {
+	annotations := map[string]string{releaseNameAnnotation: "web", releaseNamespaceAnnotation: "prod"}
+	for k, v := range extra {
+		annotations[k] = v
+	}
+	return &metav1.ObjectMeta{Name: name, Namespace: "prod", Annotations: annotations}
+}
+
+func TestComputeDrift(t *testing.T) {
+	rel := &release.Release{Name: "web", Namespace: "prod", Version: 2, Manifest: driftManifestV2}
+	history := []*release.Release{
+		{Name: "web", Namespace: "prod", Version: 1, Manifest: driftManifestV1},
+		rel,
+	}
+	isController := true
+	live := map[string][]metav1.Object{
+		"Deployment": {helmOwned("web", nil)},
+		"ConfigMap": {
+			helmOwned("web-legacy", map[string]string{"helm.sh/resource-policy": "keep"}),
+			// Another release's object, and one only labeled for this release
+			&metav1.ObjectMeta{Name: "api-config", Namespace: "prod", Annotations: map[string]string{releaseNameAnnotation: "api", releaseNamespaceAnnotation: "prod"}},
+			&metav1.ObjectMeta{Name: "web-extra", Namespace: "prod", Labels: map[string]string{instanceLabel: "web"}},
+			// Created by a controller, so not the release's even with the label
+			&metav1.ObjectMeta{Name: "web-generated", Namespace: "prod", Labels: map[string]string{instanceLabel: "web"},
+				OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "web", Controller: &isController}}},
+			helmOwned("web-hook", map[string]string{release.HookAnnotation: "pre-install"}),
+		},
+		"Service": {},
+	}
+	list := func(kind, group, namespace string) ([]metav1.Object, bool) {
+		if group != "" {
+			return nil, false // Custom resources unavailable
+		}
+		return live[kind], true
+	}
+
+	got := computeDrift(rel, history, list)
+	want := &ReleaseDrift{
+		Orphaned: []DriftResource{
+			{Kind: "ConfigMap", Name: "web-extra", Namespace: "prod"},
+			{Kind: "ConfigMap", Name: "web-legacy", Namespace: "prod", LastRevision: 1},
+		},
+		Missing:   []DriftResource{{Kind: "Service", Name: "web", Namespace: "prod"}},
+		Unchecked: []string{"Certificate.cert-manager.io"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("computeDrift() =\n%+v\nwant\n%+v", got, want)
+	}
+}
@@ -2,10 +2,18 @@ package helm
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
+
+	"github.com/skyhook-io/radar/internal/auth"
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+	"github.com/skyhook-io/radar/internal/timeline"
+	"github.com/skyhook-io/radar/internal/yamldiff"
 )
 
 // Handlers provides HTTP handlers for Helm endpoints
@@ -21,6 +29,7 @@ func (h *Handlers) RegisterRoutes(r chi.Router) {
 	r.Route("/helm", func(r chi.Router) {
 		// Release management
 		r.Get("/releases", h.handleListReleases)
+		r.Get("/summary", h.handleGetSummary)
 		r.Post("/releases", h.handleInstall)
 		r.Post("/releases/install-stream", h.handleInstallStream)
 		r.Get("/releases/{namespace}/{name}", h.handleGetRelease)
@@ -28,6 +37,9 @@ func (h *Handlers) RegisterRoutes(r chi.Router) {
 		r.Get("/releases/{namespace}/{name}/values", h.handleGetValues)
 		r.Get("/releases/{namespace}/{name}/diff", h.handleGetDiff)
 		r.Get("/releases/{namespace}/{name}/upgrade-info", h.handleCheckUpgrade)
+		r.Get("/releases/{namespace}/{name}/upgrade-preview", h.handlePreviewUpgrade)
+		r.Get("/releases/{namespace}/{name}/failure", h.handleGetFailureDetail)
+		r.Get("/releases/{namespace}/{name}/crd-check", h.handleCRDCheck)
 		r.Get("/upgrade-check", h.handleBatchUpgradeCheck)
 		// Actions (write operations)
 		r.Post("/releases/{namespace}/{name}/rollback", h.handleRollback)
@@ -42,6 +54,8 @@ func (h *Handlers) RegisterRoutes(r chi.Router) {
 		r.Get("/charts", h.handleSearchCharts)
 		r.Get("/charts/{repo}/{chart}", h.handleGetChartDetail)
 		r.Get("/charts/{repo}/{chart}/{version}", h.handleGetChartDetailVersion)
+		r.Get("/oci/chart", h.handleGetOCIChartDetail)
+		r.Post("/registries/{namespace}/{name}/login", h.handleRegistryLogin)
 
 		// ArtifactHub integration
 		r.Get("/artifacthub/search", h.handleArtifactHubSearch)
@@ -54,7 +68,7 @@ func (h *Handlers) RegisterRoutes(r chi.Router) {
 func (h *Handlers) handleListReleases(w http.ResponseWriter, r *http.Request) {
 	client := GetClient()
 	if client == nil {
-		writeError(w, http.StatusServiceUnavailable, "Helm client not initialized")
+		explorerErrors.Write(w, explorerErrors.HelmClientNotInitialized())
 		return
 	}
 
@@ -69,11 +83,54 @@ func (h *Handlers) handleListReleases(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, releases)
 }
 
+// handleGetSummary returns releases aggregated by chart and namespace
+func (h *Handlers) handleGetSummary(w http.ResponseWriter, r *http.Request) {
+	client := GetClient()
+	if client == nil {
+		explorerErrors.Write(w, explorerErrors.HelmClientNotInitialized())
+		return
+	}
+
+	namespace := r.URL.Query().Get("namespace")
+
+	summary, err := client.GetSummary(namespace)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, summary)
+}
+
+// handleGetFailureDetail returns the last failed revision with failing hooks and resources
+func (h *Handlers) handleGetFailureDetail(w http.ResponseWriter, r *http.Request) {
+	client := GetClient()
+	if client == nil {
+		explorerErrors.Write(w, explorerErrors.HelmClientNotInitialized())
+		return
+	}
+
+	namespace := chi.URLParam(r, "namespace")
+	name := chi.URLParam(r, "name")
+
+	detail, err := client.GetFailureDetail(namespace, name)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "no failed revision") {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, detail)
+}
+
 // handleGetRelease returns details for a specific release
 func (h *Handlers) handleGetRelease(w http.ResponseWriter, r *http.Request) {
 	client := GetClient()
 	if client == nil {
-		writeError(w, http.StatusServiceUnavailable, "Helm client not initialized")
+		explorerErrors.Write(w, explorerErrors.HelmClientNotInitialized())
 		return
 	}
 
@@ -93,7 +150,7 @@ func (h *Handlers) handleGetRelease(w http.ResponseWriter, r *http.Request) {
 func (h *Handlers) handleGetManifest(w http.ResponseWriter, r *http.Request) {
 	client := GetClient()
 	if client == nil {
-		writeError(w, http.StatusServiceUnavailable, "Helm client not initialized")
+		explorerErrors.Write(w, explorerErrors.HelmClientNotInitialized())
 		return
 	}
 
@@ -123,7 +180,7 @@ func (h *Handlers) handleGetManifest(w http.ResponseWriter, r *http.Request) {
 func (h *Handlers) handleGetValues(w http.ResponseWriter, r *http.Request) {
 	client := GetClient()
 	if client == nil {
-		writeError(w, http.StatusServiceUnavailable, "Helm client not initialized")
+		explorerErrors.Write(w, explorerErrors.HelmClientNotInitialized())
 		return
 	}
 
@@ -144,7 +201,7 @@ func (h *Handlers) handleGetValues(w http.ResponseWriter, r *http.Request) {
 func (h *Handlers) handleGetDiff(w http.ResponseWriter, r *http.Request) {
 	client := GetClient()
 	if client == nil {
-		writeError(w, http.StatusServiceUnavailable, "Helm client not initialized")
+		explorerErrors.Write(w, explorerErrors.HelmClientNotInitialized())
 		return
 	}
 
@@ -171,7 +228,13 @@ func (h *Handlers) handleGetDiff(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	diff, err := client.GetManifestDiff(namespace, name, rev1, rev2)
+	format, err := yamldiff.ParseFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	diff, err := client.GetManifestDiff(namespace, name, rev1, rev2, format)
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -184,14 +247,14 @@ func (h *Handlers) handleGetDiff(w http.ResponseWriter, r *http.Request) {
 func (h *Handlers) handleCheckUpgrade(w http.ResponseWriter, r *http.Request) {
 	client := GetClient()
 	if client == nil {
-		writeError(w, http.StatusServiceUnavailable, "Helm client not initialized")
+		explorerErrors.Write(w, explorerErrors.HelmClientNotInitialized())
 		return
 	}
 
 	namespace := chi.URLParam(r, "namespace")
 	name := chi.URLParam(r, "name")
 
-	info, err := client.CheckForUpgrade(namespace, name)
+	info, err := client.CheckForUpgrade(namespace, name, r.URL.Query().Get("repository"))
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -200,11 +263,37 @@ func (h *Handlers) handleCheckUpgrade(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, info)
 }
 
+// handlePreviewUpgrade diffs the release against a chart version it could be upgraded to
+func (h *Handlers) handlePreviewUpgrade(w http.ResponseWriter, r *http.Request) {
+	client := GetClient()
+	if client == nil {
+		explorerErrors.Write(w, explorerErrors.HelmClientNotInitialized())
+		return
+	}
+
+	namespace := chi.URLParam(r, "namespace")
+	name := chi.URLParam(r, "name")
+
+	format, err := yamldiff.ParseFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	preview, err := client.PreviewUpgrade(namespace, name, r.URL.Query().Get("repository"), r.URL.Query().Get("version"), format)
+	if err != nil {
+		writeActionError(w, err)
+		return
+	}
+
+	writeJSON(w, preview)
+}
+
 // handleBatchUpgradeCheck checks all releases for upgrades at once
 func (h *Handlers) handleBatchUpgradeCheck(w http.ResponseWriter, r *http.Request) {
 	client := GetClient()
 	if client == nil {
-		writeError(w, http.StatusServiceUnavailable, "Helm client not initialized")
+		explorerErrors.Write(w, explorerErrors.HelmClientNotInitialized())
 		return
 	}
 
@@ -219,11 +308,37 @@ func (h *Handlers) handleBatchUpgradeCheck(w http.ResponseWriter, r *http.Reques
 	writeJSON(w, info)
 }
 
+// handleCRDCheck compares CRDs in a target chart version with the installed CRDs
+func (h *Handlers) handleCRDCheck(w http.ResponseWriter, r *http.Request) {
+	client := GetClient()
+	if client == nil {
+		explorerErrors.Write(w, explorerErrors.HelmClientNotInitialized())
+		return
+	}
+
+	namespace := chi.URLParam(r, "namespace")
+	name := chi.URLParam(r, "name")
+
+	version := r.URL.Query().Get("version")
+	if version == "" {
+		writeError(w, http.StatusBadRequest, "version parameter is required")
+		return
+	}
+
+	report, err := client.CheckCRDCompatibility(r.Context(), namespace, name, r.URL.Query().Get("repository"), version)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, report)
+}
+
 // handleRollback rolls back a release to a previous revision
 func (h *Handlers) handleRollback(w http.ResponseWriter, r *http.Request) {
 	client := GetClient()
 	if client == nil {
-		writeError(w, http.StatusServiceUnavailable, "Helm client not initialized")
+		explorerErrors.Write(w, explorerErrors.HelmClientNotInitialized())
 		return
 	}
 
@@ -242,11 +357,12 @@ func (h *Handlers) handleRollback(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := client.Rollback(namespace, name, revision); err != nil {
+	if err := client.Rollback(r.Context(), namespace, name, revision); err != nil {
 		writeError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
+	timeline.RecordAction(r.Context(), "helm-rollback", "HelmRelease", namespace, name, auth.Actor(r))
 	writeJSON(w, map[string]string{"status": "success", "message": "Rollback completed"})
 }
 
@@ -254,18 +370,19 @@ func (h *Handlers) handleRollback(w http.ResponseWriter, r *http.Request) {
 func (h *Handlers) handleUninstall(w http.ResponseWriter, r *http.Request) {
 	client := GetClient()
 	if client == nil {
-		writeError(w, http.StatusServiceUnavailable, "Helm client not initialized")
+		explorerErrors.Write(w, explorerErrors.HelmClientNotInitialized())
 		return
 	}
 
 	namespace := chi.URLParam(r, "namespace")
 	name := chi.URLParam(r, "name")
 
-	if err := client.Uninstall(namespace, name); err != nil {
+	if err := client.Uninstall(r.Context(), namespace, name); err != nil {
 		writeError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
+	timeline.RecordAction(r.Context(), "helm-uninstall", "HelmRelease", namespace, name, auth.Actor(r))
 	writeJSON(w, map[string]string{"status": "success", "message": "Release uninstalled"})
 }
 
@@ -273,7 +390,7 @@ func (h *Handlers) handleUninstall(w http.ResponseWriter, r *http.Request) {
 func (h *Handlers) handleUpgrade(w http.ResponseWriter, r *http.Request) {
 	client := GetClient()
 	if client == nil {
-		writeError(w, http.StatusServiceUnavailable, "Helm client not initialized")
+		explorerErrors.Write(w, explorerErrors.HelmClientNotInitialized())
 		return
 	}
 
@@ -286,11 +403,12 @@ func (h *Handlers) handleUpgrade(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := client.Upgrade(namespace, name, version); err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+	if err := client.Upgrade(r.Context(), namespace, name, r.URL.Query().Get("repository"), version); err != nil {
+		writeActionError(w, err)
 		return
 	}
 
+	timeline.RecordAction(r.Context(), "helm-upgrade", "HelmRelease", namespace, name, auth.Actor(r))
 	writeJSON(w, map[string]string{"status": "success", "message": "Upgrade completed"})
 }
 
@@ -298,7 +416,7 @@ func (h *Handlers) handleUpgrade(w http.ResponseWriter, r *http.Request) {
 func (h *Handlers) handlePreviewValues(w http.ResponseWriter, r *http.Request) {
 	client := GetClient()
 	if client == nil {
-		writeError(w, http.StatusServiceUnavailable, "Helm client not initialized")
+		explorerErrors.Write(w, explorerErrors.HelmClientNotInitialized())
 		return
 	}
 
@@ -313,7 +431,7 @@ func (h *Handlers) handlePreviewValues(w http.ResponseWriter, r *http.Request) {
 
 	preview, err := client.PreviewValuesChange(namespace, name, req.Values)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+		writeActionError(w, err)
 		return
 	}
 
@@ -324,7 +442,7 @@ func (h *Handlers) handlePreviewValues(w http.ResponseWriter, r *http.Request) {
 func (h *Handlers) handleApplyValues(w http.ResponseWriter, r *http.Request) {
 	client := GetClient()
 	if client == nil {
-		writeError(w, http.StatusServiceUnavailable, "Helm client not initialized")
+		explorerErrors.Write(w, explorerErrors.HelmClientNotInitialized())
 		return
 	}
 
@@ -337,11 +455,12 @@ func (h *Handlers) handleApplyValues(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := client.ApplyValues(namespace, name, req.Values); err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+	if err := client.ApplyValues(r.Context(), namespace, name, req.Values); err != nil {
+		writeActionError(w, err)
 		return
 	}
 
+	timeline.RecordAction(r.Context(), "helm-values", "HelmRelease", namespace, name, auth.Actor(r))
 	writeJSON(w, map[string]string{"status": "success", "message": "Values applied successfully"})
 }
 
@@ -353,7 +472,7 @@ func (h *Handlers) handleApplyValues(w http.ResponseWriter, r *http.Request) {
 func (h *Handlers) handleListRepositories(w http.ResponseWriter, r *http.Request) {
 	client := GetClient()
 	if client == nil {
-		writeError(w, http.StatusServiceUnavailable, "Helm client not initialized")
+		explorerErrors.Write(w, explorerErrors.HelmClientNotInitialized())
 		return
 	}
 
@@ -370,7 +489,7 @@ func (h *Handlers) handleListRepositories(w http.ResponseWriter, r *http.Request
 func (h *Handlers) handleUpdateRepository(w http.ResponseWriter, r *http.Request) {
 	client := GetClient()
 	if client == nil {
-		writeError(w, http.StatusServiceUnavailable, "Helm client not initialized")
+		explorerErrors.Write(w, explorerErrors.HelmClientNotInitialized())
 		return
 	}
 
@@ -392,7 +511,7 @@ func (h *Handlers) handleUpdateRepository(w http.ResponseWriter, r *http.Request
 func (h *Handlers) handleSearchCharts(w http.ResponseWriter, r *http.Request) {
 	client := GetClient()
 	if client == nil {
-		writeError(w, http.StatusServiceUnavailable, "Helm client not initialized")
+		explorerErrors.Write(w, explorerErrors.HelmClientNotInitialized())
 		return
 	}
 
@@ -412,7 +531,7 @@ func (h *Handlers) handleSearchCharts(w http.ResponseWriter, r *http.Request) {
 func (h *Handlers) handleGetChartDetail(w http.ResponseWriter, r *http.Request) {
 	client := GetClient()
 	if client == nil {
-		writeError(w, http.StatusServiceUnavailable, "Helm client not initialized")
+		explorerErrors.Write(w, explorerErrors.HelmClientNotInitialized())
 		return
 	}
 
@@ -432,7 +551,7 @@ func (h *Handlers) handleGetChartDetail(w http.ResponseWriter, r *http.Request)
 func (h *Handlers) handleGetChartDetailVersion(w http.ResponseWriter, r *http.Request) {
 	client := GetClient()
 	if client == nil {
-		writeError(w, http.StatusServiceUnavailable, "Helm client not initialized")
+		explorerErrors.Write(w, explorerErrors.HelmClientNotInitialized())
 		return
 	}
 
@@ -449,11 +568,67 @@ func (h *Handlers) handleGetChartDetailVersion(w http.ResponseWriter, r *http.Re
 	writeJSON(w, detail)
 }
 
+// handleGetOCIChartDetail returns detailed info about a chart in an OCI registry. The
+// reference (?ref=oci://host/path/chart) can't be a path parameter; ?version= picks a tag.
+func (h *Handlers) handleGetOCIChartDetail(w http.ResponseWriter, r *http.Request) {
+	client := GetClient()
+	if client == nil {
+		explorerErrors.Write(w, explorerErrors.HelmClientNotInitialized())
+		return
+	}
+
+	ref := r.URL.Query().Get("ref")
+	repository, chartName, tag := splitOCIChartRef(ref)
+	if !strings.HasPrefix(ref, "oci://") || repository == "" {
+		writeError(w, http.StatusBadRequest, "ref must be an oci:// chart reference")
+		return
+	}
+	version := r.URL.Query().Get("version")
+	if version == "" {
+		version = tag
+	}
+
+	detail, err := client.GetChartDetail(repository, chartName, version)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, detail)
+}
+
+// handleRegistryLogin logs in to OCI registries with the credentials in a Secret
+func (h *Handlers) handleRegistryLogin(w http.ResponseWriter, r *http.Request) {
+	client := GetClient()
+	if client == nil {
+		explorerErrors.Write(w, explorerErrors.HelmClientNotInitialized())
+		return
+	}
+
+	namespace := chi.URLParam(r, "namespace")
+	secretName := chi.URLParam(r, "name")
+
+	// The body is optional: without it every registry in the Secret is logged in to
+	var req RegistryLoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	result, err := client.RegistryLogin(r.Context(), namespace, secretName, req.Registry, req.Insecure)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, result)
+}
+
 // handleInstall installs a new Helm release (non-streaming version)
 func (h *Handlers) handleInstall(w http.ResponseWriter, r *http.Request) {
 	client := GetClient()
 	if client == nil {
-		writeError(w, http.StatusServiceUnavailable, "Helm client not initialized")
+		explorerErrors.Write(w, explorerErrors.HelmClientNotInitialized())
 		return
 	}
 
@@ -472,6 +647,10 @@ func (h *Handlers) handleInstall(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "namespace is required")
 		return
 	}
+	if err := auth.CheckNamespace(r.Context(), req.Namespace); err != nil {
+		explorerErrors.Write(w, explorerErrors.New(explorerErrors.ErrForbidden, err.Error()))
+		return
+	}
 	if req.ChartName == "" {
 		writeError(w, http.StatusBadRequest, "chartName is required")
 		return
@@ -481,9 +660,9 @@ func (h *Handlers) handleInstall(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	release, err := client.Install(&req)
+	release, err := client.Install(r.Context(), &req)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+		writeActionError(w, err)
 		return
 	}
 
@@ -494,7 +673,7 @@ func (h *Handlers) handleInstall(w http.ResponseWriter, r *http.Request) {
 func (h *Handlers) handleInstallStream(w http.ResponseWriter, r *http.Request) {
 	client := GetClient()
 	if client == nil {
-		writeError(w, http.StatusServiceUnavailable, "Helm client not initialized")
+		explorerErrors.Write(w, explorerErrors.HelmClientNotInitialized())
 		return
 	}
 
@@ -513,6 +692,10 @@ func (h *Handlers) handleInstallStream(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "namespace is required")
 		return
 	}
+	if err := auth.CheckNamespace(r.Context(), req.Namespace); err != nil {
+		explorerErrors.Write(w, explorerErrors.New(explorerErrors.ErrForbidden, err.Error()))
+		return
+	}
 	if req.ChartName == "" {
 		writeError(w, http.StatusBadRequest, "chartName is required")
 		return
@@ -541,7 +724,7 @@ func (h *Handlers) handleInstallStream(w http.ResponseWriter, r *http.Request) {
 	// Start install in goroutine
 	resultCh := make(chan installResult, 1)
 	go func() {
-		release, err := client.InstallWithProgress(&req, progressCh)
+		release, err := client.InstallWithProgress(r.Context(), &req, progressCh)
 		resultCh <- installResult{release: release, err: err}
 	}()
 
@@ -570,6 +753,9 @@ func (h *Handlers) handleInstallStream(w http.ResponseWriter, r *http.Request) {
 					"type":    "error",
 					"message": result.err.Error(),
 				}
+				if fields := valueErrors(result.err); fields != nil {
+					event["fields"] = fields
+				}
 				data, _ := json.Marshal(event)
 				w.Write([]byte("data: " + string(data) + "\n\n"))
 			} else {
@@ -602,9 +788,27 @@ func writeJSON(w http.ResponseWriter, data any) {
 }
 
 func writeError(w http.ResponseWriter, status int, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(map[string]string{"error": message})
+	explorerErrors.WriteHTTP(w, status, message)
+}
+
+// writeActionError reports values schema violations as a 400 with details.fields;
+// anything else failed while running the Helm action
+func writeActionError(w http.ResponseWriter, err error) {
+	if explorerErrors.IsCode(err, explorerErrors.ErrValidation) {
+		explorerErrors.Write(w, err)
+		return
+	}
+	writeError(w, http.StatusInternalServerError, err.Error())
+}
+
+// valueErrors returns the field-level schema violations carried by err, if any
+func valueErrors(err error) []ValueError {
+	var explorerErr *explorerErrors.ExplorerError
+	if !errors.As(err, &explorerErr) {
+		return nil
+	}
+	fields, _ := explorerErr.Details["fields"].([]ValueError)
+	return fields
 }
 
 // ============================================================================
@@ -0,0 +1,48 @@
+package helm
+
+import (
+	"bytes"
+	"sync"
+
+	"helm.sh/helm/v3/pkg/postrender"
+)
+
+// ManifestGate inspects rendered manifests before Helm applies them and returns an
+// error to abort the install/upgrade (e.g. resource policy enforcement)
+type ManifestGate func(namespace, manifest string) error
+
+var (
+	manifestGate   ManifestGate
+	manifestGateMu sync.RWMutex
+)
+
+// SetManifestGate registers the gate run on every install and upgrade made through Radar
+func SetManifestGate(gate ManifestGate) {
+	manifestGateMu.Lock()
+	defer manifestGateMu.Unlock()
+	manifestGate = gate
+}
+
+// gatePostRenderer returns a post-renderer that runs the registered gate, or nil if none is set
+func gatePostRenderer(namespace string) postrender.PostRenderer {
+	manifestGateMu.RLock()
+	gate := manifestGate
+	manifestGateMu.RUnlock()
+	if gate == nil {
+		return nil
+	}
+	return &gateRenderer{namespace: namespace, gate: gate}
+}
+
+type gateRenderer struct {
+	namespace string
+	gate      ManifestGate
+}
+
+// Run passes manifests through unchanged unless the gate rejects them
+func (g *gateRenderer) Run(rendered *bytes.Buffer) (*bytes.Buffer, error) {
+	if err := g.gate(g.namespace, rendered.String()); err != nil {
+		return nil, err
+	}
+	return rendered, nil
+}
@@ -0,0 +1,297 @@
+package helm
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/registry"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OCI registries have no index.yaml: a "repository" is an oci:// reference such as
+// oci://ghcr.io/org/charts, and a chart is the last path segment below it. Versions are
+// the registry's semver tags.
+
+// newRegistryClient creates the client Helm uses for oci:// charts. Logins are stored in
+// Helm's registry config, so they survive restarts the same way `helm registry login` does.
+func newRegistryClient(settings *cli.EnvSettings) (*registry.Client, error) {
+	return registry.NewClient(
+		registry.ClientOptCredentialsFile(settings.RegistryConfig),
+		registry.ClientOptEnableCache(true),
+		registry.ClientOptWriter(io.Discard),
+	)
+}
+
+// ociChartRef joins an oci:// repository and a chart name into a chart reference
+func ociChartRef(repository, chartName string) string {
+	return strings.TrimSuffix(repository, "/") + "/" + chartName
+}
+
+// splitOCIChartRef splits oci://host/path/chart into its repository and chart name.
+// A tag (oci://host/path/chart:1.2.3) is returned as the version.
+func splitOCIChartRef(ref string) (repository, chartName, version string) {
+	rest := strings.TrimPrefix(ref, registry.OCIScheme+"://")
+	if i := strings.LastIndex(rest, ":"); i > strings.LastIndex(rest, "/") {
+		rest, version = rest[:i], rest[i+1:]
+	}
+	i := strings.LastIndex(rest, "/")
+	if i < 0 {
+		return "", rest, version
+	}
+	return registry.OCIScheme + "://" + rest[:i], rest[i+1:], version
+}
+
+func (c *Client) getRegistryClient() (*registry.Client, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.registryClient == nil {
+		return nil, fmt.Errorf("OCI registry client is not available")
+	}
+	return c.registryClient, nil
+}
+
+// ociTags lists the semver tags of a chart, newest first
+func (c *Client) ociTags(chartRef string) ([]string, error) {
+	rc, err := c.getRegistryClient()
+	if err != nil {
+		return nil, err
+	}
+	tags, err := rc.Tags(strings.TrimPrefix(chartRef, registry.OCIScheme+"://"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags for %s: %w", chartRef, err)
+	}
+	return tags, nil
+}
+
+// latestOCIVersion returns the newest tag of an OCI chart
+func (c *Client) latestOCIVersion(chartRef string) (string, error) {
+	tags, err := c.ociTags(chartRef)
+	if err != nil {
+		return "", err
+	}
+	if len(tags) == 0 {
+		return "", fmt.Errorf("no versions found for %s", chartRef)
+	}
+	return tags[0], nil
+}
+
+// searchOCIChart lists the versions of the chart at an oci:// reference. Only the latest
+// version is pulled for its metadata; older versions carry just name and version.
+func (c *Client) searchOCIChart(ref string, allVersions bool) (*ChartSearchResult, error) {
+	repository, chartName, _ := splitOCIChartRef(ref)
+	chartRef := ociChartRef(repository, chartName)
+
+	tags, err := c.ociTags(chartRef)
+	if err != nil {
+		return nil, err
+	}
+	charts := []ChartInfo{}
+	for i, tag := range tags {
+		if i > 0 && !allVersions {
+			break
+		}
+		info := ChartInfo{Name: chartName, Version: tag, Repository: repository}
+		if i == 0 {
+			if meta, err := c.ociChartMetadata(chartRef, tag); err == nil {
+				info = chartMetadataToInfo(meta, repository)
+			}
+		}
+		charts = append(charts, info)
+	}
+	return &ChartSearchResult{Charts: charts, Total: len(charts)}, nil
+}
+
+// ociChartMetadata pulls one chart version and returns its Chart.yaml
+func (c *Client) ociChartMetadata(chartRef, version string) (*chart.Metadata, error) {
+	rc, err := c.getRegistryClient()
+	if err != nil {
+		return nil, err
+	}
+	result, err := rc.Pull(chartRef+":"+version, registry.PullOptWithChart(true))
+	if err != nil {
+		return nil, err
+	}
+	return result.Chart.Meta, nil
+}
+
+// chartMetadataToInfo converts a chart's own metadata to ChartInfo, for charts that
+// don't come from a repository index
+func chartMetadataToInfo(m *chart.Metadata, repository string) ChartInfo {
+	return ChartInfo{
+		Name:        m.Name,
+		Version:     m.Version,
+		AppVersion:  m.AppVersion,
+		Description: m.Description,
+		Icon:        m.Icon,
+		Repository:  repository,
+		Home:        m.Home,
+		Deprecated:  m.Deprecated,
+	}
+}
+
+// getOCIChartDetail pulls a chart version from an oci:// repository and returns its
+// README, values and metadata. An empty or "latest" version is the newest tag.
+func (c *Client) getOCIChartDetail(repository, chartName, version string) (*ChartDetail, error) {
+	actionConfig, err := c.getActionConfig("")
+	if err != nil {
+		return nil, err
+	}
+
+	client := action.NewInstall(actionConfig)
+	client.Version = installVersion(version)
+	cp, err := client.ChartPathOptions.LocateChart(ociChartRef(repository, chartName), c.settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pull chart: %w", err)
+	}
+	loaded, err := loader.Load(cp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chart: %w", err)
+	}
+	return buildChartDetail(chartMetadataToInfo(loaded.Metadata, repository), loaded), nil
+}
+
+// installVersion maps the API's version to the one Helm resolves: "latest" means no
+// constraint, which for OCI charts picks the newest tag
+func installVersion(version string) string {
+	if version == "latest" {
+		return ""
+	}
+	return version
+}
+
+// RegistryLogin logs in to OCI registries with credentials from a Secret. Secrets of type
+// kubernetes.io/dockerconfigjson (or dockercfg) log in to each registry they list, or only
+// to registryHost when it's given. Any other Secret must have username and password keys,
+// plus a registry key unless registryHost is given.
+func (c *Client) RegistryLogin(ctx context.Context, namespace, secretName, registryHost string, insecure bool) (*RegistryLoginResult, error) {
+	rc, err := c.getRegistryClient()
+	if err != nil {
+		return nil, err
+	}
+	client := k8s.GetClient()
+	if client == nil {
+		return nil, fmt.Errorf("kubernetes client not initialized")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	secret, err := client.CoreV1().Secrets(namespace).Get(ctx, secretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret %s/%s: %w", namespace, secretName, err)
+	}
+
+	creds, err := registryCredentials(secret, registryHost)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RegistryLoginResult{Registries: []string{}}
+	for _, cred := range creds {
+		if err := rc.Login(cred.host, registry.LoginOptBasicAuth(cred.username, cred.password), registry.LoginOptInsecure(insecure)); err != nil {
+			return nil, fmt.Errorf("login to %s failed: %w", cred.host, err)
+		}
+		result.Registries = append(result.Registries, cred.host)
+	}
+	return result, nil
+}
+
+type registryCredential struct {
+	host, username, password string
+}
+
+// dockerAuth is one registry entry of a docker config
+type dockerAuth struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Auth     string `json:"auth"`
+}
+
+// registryCredentials reads registry logins from a Secret, sorted by host. When host is
+// set, only that registry is returned.
+func registryCredentials(secret *corev1.Secret, host string) ([]registryCredential, error) {
+	host = registryHostname(host)
+
+	var auths map[string]dockerAuth
+	switch secret.Type {
+	case corev1.SecretTypeDockerConfigJson:
+		var cfg struct {
+			Auths map[string]dockerAuth `json:"auths"`
+		}
+		if err := json.Unmarshal(secret.Data[corev1.DockerConfigJsonKey], &cfg); err != nil {
+			return nil, fmt.Errorf("invalid %s in secret %s: %w", corev1.DockerConfigJsonKey, secret.Name, err)
+		}
+		auths = cfg.Auths
+	case corev1.SecretTypeDockercfg:
+		if err := json.Unmarshal(secret.Data[corev1.DockerConfigKey], &auths); err != nil {
+			return nil, fmt.Errorf("invalid %s in secret %s: %w", corev1.DockerConfigKey, secret.Name, err)
+		}
+	default:
+		if h := registryHostname(string(secret.Data["registry"])); host == "" {
+			host = h
+		}
+		if host == "" {
+			return nil, fmt.Errorf("secret %s has no registry key; specify the registry", secret.Name)
+		}
+		username, password := string(secret.Data["username"]), string(secret.Data["password"])
+		if username == "" || password == "" {
+			return nil, fmt.Errorf("secret %s must have username and password keys", secret.Name)
+		}
+		return []registryCredential{{host: host, username: username, password: password}}, nil
+	}
+
+	var creds []registryCredential
+	for server, a := range auths {
+		h := registryHostname(server)
+		if host != "" && h != host {
+			continue
+		}
+		username, password := a.Username, a.Password
+		if username == "" && a.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(a.Auth)
+			if err != nil {
+				return nil, fmt.Errorf("invalid auth for %s in secret %s: %w", server, secret.Name, err)
+			}
+			username, password, _ = strings.Cut(string(decoded), ":")
+		}
+		creds = append(creds, registryCredential{host: h, username: username, password: password})
+	}
+	if len(creds) == 0 {
+		if host != "" {
+			return nil, fmt.Errorf("secret %s has no credentials for %s", secret.Name, host)
+		}
+		return nil, fmt.Errorf("secret %s has no registry credentials", secret.Name)
+	}
+	sort.Slice(creds, func(i, j int) bool { return creds[i].host < creds[j].host })
+	return creds, nil
+}
+
+// registryHostname reduces a registry address (oci://ghcr.io/org, https://index.docker.io/v1/,
+// ghcr.io) to its host
+func registryHostname(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return ""
+	}
+	if !strings.Contains(s, "://") {
+		s = "//" + s
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return s
+	}
+	return u.Host
+}
@@ -0,0 +1,76 @@
+package helm
+
+import (
+	"encoding/base64"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSplitOCIChartRef(t *testing.T) {
+	tests := []struct {
+		ref, repository, chart, version string
+	}{
+		{"oci://ghcr.io/org/charts/web", "oci://ghcr.io/org/charts", "web", ""},
+		{"oci://ghcr.io/org/charts/web:1.2.3", "oci://ghcr.io/org/charts", "web", "1.2.3"},
+		{"oci://localhost:5000/web", "oci://localhost:5000", "web", ""},
+		{"oci://localhost:5000/web:0.1.0", "oci://localhost:5000", "web", "0.1.0"},
+	}
+	for _, tt := range tests {
+		repository, chart, version := splitOCIChartRef(tt.ref)
+		if repository != tt.repository || chart != tt.chart || version != tt.version {
+			t.Errorf("splitOCIChartRef(%q) = %q, %q, %q", tt.ref, repository, chart, version)
+		}
+		if tt.version == "" && ociChartRef(repository, chart) != tt.ref {
+			t.Errorf("ociChartRef(%q, %q) = %q, want %q", repository, chart, ociChartRef(repository, chart), tt.ref)
+		}
+	}
+}
+
+func TestRegistryCredentials(t *testing.T) {
+	auth := base64.StdEncoding.EncodeToString([]byte("bot:s3cr:et"))
+	dockerConfig := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "pull"},
+		Type:       corev1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{corev1.DockerConfigJsonKey: []byte(`{"auths": {
+			"https://ghcr.io": {"username": "octo", "password": "ghp_x"},
+			"123.dkr.ecr.us-east-1.amazonaws.com": {"auth": "` + auth + `"}
+		}}`)},
+	}
+
+	got, err := registryCredentials(dockerConfig, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []registryCredential{
+		{host: "123.dkr.ecr.us-east-1.amazonaws.com", username: "bot", password: "s3cr:et"},
+		{host: "ghcr.io", username: "octo", password: "ghp_x"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("registryCredentials() = %+v, want %+v", got, want)
+	}
+
+	got, err = registryCredentials(dockerConfig, "oci://ghcr.io/org/charts")
+	if err != nil || len(got) != 1 || got[0].host != "ghcr.io" {
+		t.Errorf("filtered by registry = %+v, %v", got, err)
+	}
+	if _, err := registryCredentials(dockerConfig, "quay.io"); err == nil {
+		t.Error("expected an error for a registry the secret doesn't list")
+	}
+
+	basic := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "basic"},
+		Type:       corev1.SecretTypeOpaque,
+		Data:       map[string][]byte{"username": []byte("u"), "password": []byte("p"), "registry": []byte("ghcr.io")},
+	}
+	got, err = registryCredentials(basic, "")
+	if err != nil || !reflect.DeepEqual(got, []registryCredential{{host: "ghcr.io", username: "u", password: "p"}}) {
+		t.Errorf("basic secret = %+v, %v", got, err)
+	}
+	delete(basic.Data, "registry")
+	if _, err := registryCredentials(basic, ""); err == nil {
+		t.Error("expected an error when the registry is unknown")
+	}
+}
@@ -0,0 +1,202 @@
+package helm
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+)
+
+// ValueError is one field of user-supplied values that violates a chart's values.schema.json
+type ValueError struct {
+	Path    string `json:"path"`            // Dotted path, e.g. "image.tag" or "ingress.hosts[0].host"; "" for the root
+	Message string `json:"message"`         // What the schema requires
+	Chart   string `json:"chart,omitempty"` // Chart whose schema was violated (parent or subchart)
+}
+
+var schemaPrinter = message.NewPrinter(language.English)
+
+// validateValues checks values against the chart's and its subcharts' schemas the way Helm
+// does at render time (after merging chart defaults), but returns every violation by field.
+// The error is a validation ExplorerError with the violations in details.fields, so handlers
+// can return them to the UI before anything is installed.
+func validateValues(chrt *chart.Chart, values map[string]any) error {
+	merged, err := chartutil.CoalesceValues(chrt, values)
+	if err != nil {
+		return explorerErrors.ValidationError(fmt.Sprintf("invalid values: %v", err))
+	}
+	fields := schemaViolations(chrt, merged.AsMap(), "")
+	if len(fields) == 0 {
+		return nil
+	}
+	sort.SliceStable(fields, func(i, j int) bool { return fields[i].Path < fields[j].Path })
+
+	summary := make([]string, 0, len(fields))
+	for _, f := range fields {
+		path := f.Path
+		if path == "" {
+			path = "(root)"
+		}
+		summary = append(summary, path+": "+f.Message)
+	}
+	msg := fmt.Sprintf("values do not match the chart's values.schema.json: %s", strings.Join(summary, "; "))
+	return explorerErrors.ValidationError(msg).WithDetail("fields", fields)
+}
+
+// schemaViolations validates one chart's values, then recurses into subcharts with the
+// values nested under their name
+func schemaViolations(chrt *chart.Chart, values map[string]any, prefix string) []ValueError {
+	var fields []ValueError
+	if len(chrt.Schema) > 0 {
+		fields = append(fields, validateAgainstSchema(chrt.Name(), chrt.Schema, values, prefix)...)
+	}
+	for _, sub := range chrt.Dependencies() {
+		raw, ok := values[sub.Name()]
+		if !ok || raw == nil {
+			continue
+		}
+		subValues, ok := raw.(map[string]any)
+		if !ok {
+			fields = append(fields, ValueError{Path: joinPath(prefix, sub.Name()), Message: fmt.Sprintf("expected object, got %T", raw), Chart: sub.Name()})
+			continue
+		}
+		fields = append(fields, schemaViolations(sub, subValues, joinPath(prefix, sub.Name()))...)
+	}
+	return fields
+}
+
+func validateAgainstSchema(chartName string, schemaJSON []byte, values map[string]any, prefix string) (fields []ValueError) {
+	defer func() {
+		if r := recover(); r != nil {
+			fields = []ValueError{{Path: prefix, Message: fmt.Sprintf("unable to validate schema: %v", r), Chart: chartName}}
+		}
+	}()
+
+	validator, err := compileSchema(schemaJSON)
+	if err != nil {
+		// A schema we can't compile (e.g. unreachable remote $ref) falls back to Helm's own check
+		if err := chartutil.ValidateAgainstSingleSchema(values, schemaJSON); err != nil {
+			return []ValueError{{Path: prefix, Message: strings.TrimSpace(err.Error()), Chart: chartName}}
+		}
+		return nil
+	}
+	err = validator.Validate(values)
+	if err == nil {
+		return nil
+	}
+	verr, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return []ValueError{{Path: prefix, Message: err.Error(), Chart: chartName}}
+	}
+	for _, leaf := range leafErrors(verr) {
+		fields = append(fields, ValueError{
+			Path:    joinPath(prefix, formatInstancePath(leaf.InstanceLocation)),
+			Message: leaf.ErrorKind.LocalizedString(schemaPrinter),
+			Chart:   chartName,
+		})
+	}
+	return fields
+}
+
+// compileSchema compiles a values.schema.json with the same loaders Helm uses
+func compileSchema(schemaJSON []byte) (*jsonschema.Schema, error) {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(schemaJSON))
+	if err != nil {
+		return nil, err
+	}
+	compiler := jsonschema.NewCompiler()
+	compiler.UseLoader(jsonschema.SchemeURLLoader{
+		"file":  jsonschema.FileLoader{},
+		"http":  schemaHTTPLoader{},
+		"https": schemaHTTPLoader{},
+		"urn":   permissiveLoader{},
+	})
+	if err := compiler.AddResource("file:///values.schema.json", doc); err != nil {
+		return nil, err
+	}
+	return compiler.Compile("file:///values.schema.json")
+}
+
+// leafErrors returns the most specific violations; parents only say "validation failed"
+func leafErrors(e *jsonschema.ValidationError) []*jsonschema.ValidationError {
+	if len(e.Causes) == 0 {
+		return []*jsonschema.ValidationError{e}
+	}
+	var leaves []*jsonschema.ValidationError
+	for _, c := range e.Causes {
+		leaves = append(leaves, leafErrors(c)...)
+	}
+	return leaves
+}
+
+// formatInstancePath renders a JSON pointer's segments as a values path, using [n] for
+// array indexes
+func formatInstancePath(segments []string) string {
+	var sb strings.Builder
+	for _, s := range segments {
+		if isIndex(s) {
+			sb.WriteString("[" + s + "]")
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteByte('.')
+		}
+		sb.WriteString(s)
+	}
+	return sb.String()
+}
+
+func isIndex(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func joinPath(prefix, path string) string {
+	switch {
+	case prefix == "":
+		return path
+	case path == "":
+		return prefix
+	case strings.HasPrefix(path, "["):
+		return prefix + path
+	}
+	return prefix + "." + path
+}
+
+// schemaHTTPLoader fetches remote $refs
+type schemaHTTPLoader struct{}
+
+func (schemaHTTPLoader) Load(url string) (any, error) {
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned status %d", url, resp.StatusCode)
+	}
+	return jsonschema.UnmarshalJSON(resp.Body)
+}
+
+// permissiveLoader resolves urn: $refs to an always-valid schema, as Helm does by default
+type permissiveLoader struct{}
+
+func (permissiveLoader) Load(string) (any, error) {
+	return true, nil
+}
@@ -0,0 +1,60 @@
+package helm
+
+import (
+	"testing"
+
+	"helm.sh/helm/v3/pkg/chart"
+
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+)
+
+func TestValidateValues(t *testing.T) {
+	schema := []byte(`{
+		"type": "object",
+		"properties": {
+			"replicas": {"type": "integer", "minimum": 1},
+			"image": {
+				"type": "object",
+				"properties": {"tag": {"type": "string"}},
+				"required": ["tag"]
+			},
+			"hosts": {"type": "array", "items": {"type": "string", "pattern": "^[a-z.]+$"}}
+		}
+	}`)
+	sub := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "redis", Version: "1.0.0"},
+		Schema:   []byte(`{"type": "object", "properties": {"port": {"type": "integer"}}}`),
+	}
+	parent := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "app", Version: "1.0.0"},
+		Schema:   schema,
+		Values:   map[string]any{"replicas": 1, "image": map[string]any{"tag": "v1"}},
+	}
+	parent.SetDependencies(sub)
+
+	if err := validateValues(parent, map[string]any{"replicas": 3}); err != nil {
+		t.Fatalf("valid values rejected: %v", err)
+	}
+
+	err := validateValues(parent, map[string]any{
+		"replicas": 0,
+		"hosts":    []any{"ok.example", "Bad_Host"},
+		"redis":    map[string]any{"port": "6379"},
+	})
+	if !explorerErrors.IsCode(err, explorerErrors.ErrValidation) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	fields := valueErrors(err)
+	got := make(map[string]string)
+	for _, f := range fields {
+		got[f.Path] = f.Chart
+	}
+	for path, chartName := range map[string]string{"replicas": "app", "hosts[1]": "app", "redis.port": "redis"} {
+		if got[path] != chartName {
+			t.Errorf("missing violation at %s (chart %s), got %+v", path, chartName, fields)
+		}
+	}
+	if len(fields) != 3 {
+		t.Errorf("got %d violations, want 3: %+v", len(fields), fields)
+	}
+}
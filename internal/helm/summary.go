@@ -0,0 +1,264 @@
+package helm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+// recentFailureWindow bounds how far back failed revisions are reported in the summary
+const recentFailureWindow = 7 * 24 * time.Hour
+
+// maxRecentFailures caps the number of failures returned in the summary
+const maxRecentFailures = 20
+
+// GetSummary returns releases grouped by chart and namespace with health rollups,
+// pending/upgrade counts, and the most recent failed revisions
+func (c *Client) GetSummary(namespace string) (*HelmSummary, error) {
+	releases, err := c.ListReleases(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &HelmSummary{
+		Total:          len(releases),
+		StatusCounts:   make(map[string]int),
+		Groups:         []HelmReleaseGroup{},
+		RecentFailures: []HelmReleaseFailure{},
+	}
+
+	// Upgrade info comes from local repo indexes; failures here are non-fatal
+	var upgrades *BatchUpgradeInfo
+	if len(releases) > 0 {
+		upgrades, _ = c.BatchCheckUpgrades(namespace)
+	}
+
+	namespaces := make(map[string]bool)
+	groups := make(map[string]*HelmReleaseGroup)
+	var order []string
+
+	for _, rel := range releases {
+		namespaces[rel.Namespace] = true
+		summary.StatusCounts[rel.Status]++
+
+		key := rel.Namespace + "/" + rel.Chart
+		g, ok := groups[key]
+		if !ok {
+			g = &HelmReleaseGroup{Chart: rel.Chart, Namespace: rel.Namespace}
+			groups[key] = g
+			order = append(order, key)
+		}
+		g.Releases = append(g.Releases, rel)
+		if !containsString(g.ChartVersions, rel.ChartVersion) {
+			g.ChartVersions = append(g.ChartVersions, rel.ChartVersion)
+		}
+
+		addHealth(&g.Health, rel.ResourceHealth)
+		addHealth(&summary.Health, rel.ResourceHealth)
+
+		if rel.Status == release.StatusFailed.String() {
+			g.Failed++
+		}
+		if isPendingStatus(rel.Status) {
+			g.Pending++
+			summary.Pending++
+		}
+		if upgrades != nil {
+			if info := upgrades.Releases[rel.Namespace+"/"+rel.Name]; info != nil && info.UpdateAvailable {
+				g.UpgradeAvailable++
+				summary.UpgradesAvailable++
+			}
+		}
+	}
+
+	summary.Namespaces = len(namespaces)
+
+	for _, key := range order {
+		summary.Groups = append(summary.Groups, *groups[key])
+	}
+	// Groups with problems first, then by namespace/chart
+	sort.SliceStable(summary.Groups, func(i, j int) bool {
+		gi, gj := summary.Groups[i], summary.Groups[j]
+		pi := gi.Failed + gi.Health.Unhealthy
+		pj := gj.Failed + gj.Health.Unhealthy
+		if pi != pj {
+			return pi > pj
+		}
+		if gi.Namespace != gj.Namespace {
+			return gi.Namespace < gj.Namespace
+		}
+		return gi.Chart < gj.Chart
+	})
+
+	failures, err := c.recentFailures(namespace, releases)
+	if err == nil {
+		summary.RecentFailures = failures
+	}
+
+	return summary, nil
+}
+
+// recentFailures finds the latest failed revision per release within the failure window
+func (c *Client) recentFailures(namespace string, current []HelmRelease) ([]HelmReleaseFailure, error) {
+	actionConfig, err := c.getActionConfig(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := time.Now().Add(-recentFailureWindow)
+	failed, err := actionConfig.Releases.List(func(rel *release.Release) bool {
+		if rel.Info == nil || rel.Info.Status != release.StatusFailed {
+			return false
+		}
+		if namespace != "" && rel.Namespace != namespace {
+			return false
+		}
+		return rel.Info.LastDeployed.Time.After(cutoff)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list failed releases: %w", err)
+	}
+
+	latestRevision := make(map[string]int, len(current))
+	for _, rel := range current {
+		latestRevision[rel.Namespace+"/"+rel.Name] = rel.Revision
+	}
+
+	// Keep only the newest failed revision per release
+	byRelease := make(map[string]*release.Release)
+	for _, rel := range failed {
+		key := rel.Namespace + "/" + rel.Name
+		if existing, ok := byRelease[key]; !ok || rel.Version > existing.Version {
+			byRelease[key] = rel
+		}
+	}
+
+	result := make([]HelmReleaseFailure, 0, len(byRelease))
+	for key, rel := range byRelease {
+		result = append(result, toHelmReleaseFailure(rel, latestRevision[key] == rel.Version))
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].FailedAt.After(result[j].FailedAt)
+	})
+	if len(result) > maxRecentFailures {
+		result = result[:maxRecentFailures]
+	}
+
+	return result, nil
+}
+
+// GetFailureDetail returns the last failed revision of a release together with
+// failed hooks and unhealthy resources, so a failure can be diagnosed in one call
+func (c *Client) GetFailureDetail(namespace, name string) (*HelmFailureDetail, error) {
+	actionConfig, err := c.getActionConfig(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	historyAction := action.NewHistory(actionConfig)
+	historyAction.Max = 256
+	history, err := historyAction.Run(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get helm release history: %w", err)
+	}
+
+	var latest, lastFailed *release.Release
+	for _, rel := range history {
+		if latest == nil || rel.Version > latest.Version {
+			latest = rel
+		}
+		if rel.Info != nil && rel.Info.Status == release.StatusFailed {
+			if lastFailed == nil || rel.Version > lastFailed.Version {
+				lastFailed = rel
+			}
+		}
+	}
+	if lastFailed == nil {
+		return nil, fmt.Errorf("no failed revision found for release %s/%s", namespace, name)
+	}
+
+	detail := &HelmFailureDetail{
+		Release:            toHelmReleaseFailure(lastFailed, lastFailed.Version == latest.Version),
+		FailedHooks:        []HelmHook{},
+		UnhealthyResources: []OwnedResource{},
+		History:            make([]HelmRevision, 0, len(history)),
+	}
+
+	for _, hook := range extractHooks(lastFailed) {
+		if strings.EqualFold(hook.Status, string(release.HookPhaseFailed)) {
+			detail.FailedHooks = append(detail.FailedHooks, hook)
+		}
+	}
+
+	resources := parseManifestResources(lastFailed.Manifest, lastFailed.Namespace)
+	enrichResourcesWithStatus(resources, true)
+	for _, r := range resources {
+		if r.Issue != "" || r.Status == "Failed" || r.Status == "Error" {
+			detail.UnhealthyResources = append(detail.UnhealthyResources, r)
+		}
+	}
+
+	for _, rel := range history {
+		detail.History = append(detail.History, toHelmRevision(rel))
+	}
+	sort.Slice(detail.History, func(i, j int) bool {
+		return detail.History[i].Revision > detail.History[j].Revision
+	})
+
+	return detail, nil
+}
+
+// toHelmReleaseFailure converts a failed helm release revision to our API type
+func toHelmReleaseFailure(rel *release.Release, current bool) HelmReleaseFailure {
+	f := HelmReleaseFailure{
+		Name:      rel.Name,
+		Namespace: rel.Namespace,
+		Revision:  rel.Version,
+		Current:   current,
+	}
+	if rel.Chart != nil && rel.Chart.Metadata != nil {
+		f.Chart = rel.Chart.Metadata.Name + "-" + rel.Chart.Metadata.Version
+	}
+	if rel.Info != nil {
+		f.Error = rel.Info.Description
+		f.FailedAt = rel.Info.LastDeployed.Time
+	}
+	return f
+}
+
+// isPendingStatus reports whether a release is mid-operation
+func isPendingStatus(status string) bool {
+	switch status {
+	case release.StatusPendingInstall.String(), release.StatusPendingUpgrade.String(), release.StatusPendingRollback.String():
+		return true
+	}
+	return false
+}
+
+// addHealth increments the rollup bucket for a resource health value
+func addHealth(h *HelmHealthRollup, health string) {
+	switch health {
+	case "healthy":
+		h.Healthy++
+	case "degraded":
+		h.Degraded++
+	case "unhealthy":
+		h.Unhealthy++
+	default:
+		h.Unknown++
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
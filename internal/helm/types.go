@@ -2,6 +2,8 @@ package helm
 
 import (
 	"time"
+
+	"github.com/skyhook-io/radar/internal/yamldiff"
 )
 
 // HelmRelease represents a Helm release in the list view
@@ -47,15 +49,18 @@ type HelmReleaseDetail struct {
 	Hooks        []HelmHook        `json:"hooks,omitempty"`
 	Readme       string            `json:"readme,omitempty"`
 	Dependencies []ChartDependency `json:"dependencies,omitempty"`
+	Drift        *ReleaseDrift     `json:"drift,omitempty"` // Nil when the resource cache isn't ready
 }
 
 // HelmHook represents a Helm hook (pre/post install, upgrade, etc.)
 type HelmHook struct {
-	Name   string   `json:"name"`
-	Kind   string   `json:"kind"`
-	Events []string `json:"events"`
-	Weight int      `json:"weight"`
-	Status string   `json:"status,omitempty"`
+	Name      string   `json:"name"`
+	Kind      string   `json:"kind"`
+	Group     string   `json:"group,omitempty"` // API group, for custom resources only
+	Namespace string   `json:"namespace,omitempty"`
+	Events    []string `json:"events"`
+	Weight    int      `json:"weight"`
+	Status    string   `json:"status,omitempty"`
 }
 
 // ChartDependency represents a chart dependency
@@ -69,14 +74,16 @@ type ChartDependency struct {
 
 // OwnedResource represents a K8s resource created by a Helm release
 type OwnedResource struct {
-	Kind      string `json:"kind"`
-	Name      string `json:"name"`
-	Namespace string `json:"namespace"`
-	Status    string `json:"status,omitempty"`  // Running, Pending, Failed, etc.
-	Ready     string `json:"ready,omitempty"`   // e.g., "3/3" for deployments
-	Message   string `json:"message,omitempty"` // Status message or reason
-	Summary   string `json:"summary,omitempty"` // Brief status like "0/3 OOMKilled"
-	Issue     string `json:"issue,omitempty"`   // Primary issue if unhealthy
+	Kind       string `json:"kind"`
+	Group      string `json:"group,omitempty"` // API group, for custom resources only
+	APIVersion string `json:"apiVersion,omitempty"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace"`         // Empty for cluster-scoped resources
+	Status     string `json:"status,omitempty"`  // Running, Pending, Failed, etc.
+	Ready      string `json:"ready,omitempty"`   // e.g., "3/3" for deployments
+	Message    string `json:"message,omitempty"` // Status message or reason
+	Summary    string `json:"summary,omitempty"` // Brief status like "0/3 OOMKilled"
+	Issue      string `json:"issue,omitempty"`   // Primary issue if unhealthy
 }
 
 // HelmValues represents the values for a release
@@ -87,9 +94,11 @@ type HelmValues struct {
 
 // ManifestDiff represents a diff between two revisions
 type ManifestDiff struct {
-	Revision1 int    `json:"revision1"`
-	Revision2 int    `json:"revision2"`
-	Diff      string `json:"diff"`
+	Revision1 int              `json:"revision1"`
+	Revision2 int              `json:"revision2"`
+	Format    yamldiff.Format  `json:"format"`
+	Diff      string           `json:"diff"`    // Rendered in Format; empty for json
+	Changes   *yamldiff.Result `json:"changes"` // Per-document, per-field changes
 }
 
 // UpgradeInfo contains information about available upgrades
@@ -101,6 +110,16 @@ type UpgradeInfo struct {
 	Error           string `json:"error,omitempty"`
 }
 
+// UpgradePreview is what upgrading a release to another chart version would change in
+// its rendered manifest, with the release's current values
+type UpgradePreview struct {
+	CurrentVersion string           `json:"currentVersion"`
+	TargetVersion  string           `json:"targetVersion"`
+	Format         yamldiff.Format  `json:"format"`
+	Diff           string           `json:"diff"`    // Rendered in Format; empty for json
+	Changes        *yamldiff.Result `json:"changes"` // Per-document, per-field changes
+}
+
 // BatchUpgradeInfo contains upgrade info for multiple releases
 type BatchUpgradeInfo struct {
 	// Map of "namespace/name" to UpgradeInfo
@@ -114,9 +133,11 @@ type ApplyValuesRequest struct {
 
 // ValuesPreviewResponse contains the preview of a values change
 type ValuesPreviewResponse struct {
-	CurrentValues map[string]any `json:"currentValues"`
-	NewValues     map[string]any `json:"newValues"`
-	ManifestDiff  string         `json:"manifestDiff"`
+	CurrentValues   map[string]any   `json:"currentValues"`
+	NewValues       map[string]any   `json:"newValues"`
+	ValuesChanges   *yamldiff.Result `json:"valuesChanges"`
+	ManifestDiff    string           `json:"manifestDiff"` // Unified diff, empty when nothing changes
+	ManifestChanges *yamldiff.Result `json:"manifestChanges"`
 }
 
 // HelmRepository represents a configured Helm repository
@@ -162,11 +183,23 @@ type InstallRequest struct {
 	Namespace       string         `json:"namespace"`
 	ChartName       string         `json:"chartName"`
 	Version         string         `json:"version"`
-	Repository      string         `json:"repository"`
+	Repository      string         `json:"repository"` // Repository name, index URL, or oci:// reference
 	Values          map[string]any `json:"values,omitempty"`
 	CreateNamespace bool           `json:"createNamespace,omitempty"`
 }
 
+// RegistryLoginRequest is the request body for logging in to an OCI registry with a
+// Secret's credentials. Registry limits a docker config Secret to one of its registries.
+type RegistryLoginRequest struct {
+	Registry string `json:"registry,omitempty"`
+	Insecure bool   `json:"insecure,omitempty"`
+}
+
+// RegistryLoginResult lists the registries that were logged in to
+type RegistryLoginResult struct {
+	Registries []string `json:"registries"`
+}
+
 // ChartSearchResult contains search results for charts
 type ChartSearchResult struct {
 	Charts []ChartInfo `json:"charts"`
@@ -273,3 +306,104 @@ type InstallProgress struct {
 	Message string `json:"message"`          // Human-readable status message
 	Detail  string `json:"detail,omitempty"` // Additional detail (e.g., command output)
 }
+
+// ============================================================================
+// Aggregated Summary Types
+// ============================================================================
+
+// HelmHealthRollup counts releases by resource health
+type HelmHealthRollup struct {
+	Healthy   int `json:"healthy"`
+	Degraded  int `json:"degraded"`
+	Unhealthy int `json:"unhealthy"`
+	Unknown   int `json:"unknown"`
+}
+
+// HelmReleaseGroup aggregates releases sharing a chart within a namespace
+type HelmReleaseGroup struct {
+	Chart            string           `json:"chart"`
+	Namespace        string           `json:"namespace"`
+	Releases         []HelmRelease    `json:"releases"`
+	ChartVersions    []string         `json:"chartVersions"`
+	Health           HelmHealthRollup `json:"health"`
+	Failed           int              `json:"failed"`
+	Pending          int              `json:"pending"`          // pending-install/upgrade/rollback
+	UpgradeAvailable int              `json:"upgradeAvailable"` // releases with a newer chart in local repos
+}
+
+// HelmReleaseFailure describes the most recent failed revision of a release
+type HelmReleaseFailure struct {
+	Name      string    `json:"name"`
+	Namespace string    `json:"namespace"`
+	Chart     string    `json:"chart"`
+	Revision  int       `json:"revision"`
+	Error     string    `json:"error"` // Helm's release description, which carries the failure reason
+	FailedAt  time.Time `json:"failedAt"`
+	Current   bool      `json:"current"` // True if the failed revision is still the latest
+}
+
+// HelmSummary is the aggregated multi-namespace view of Helm releases
+type HelmSummary struct {
+	Total             int                  `json:"total"`
+	Namespaces        int                  `json:"namespaces"`
+	Health            HelmHealthRollup     `json:"health"`
+	StatusCounts      map[string]int       `json:"statusCounts"`
+	Pending           int                  `json:"pending"`
+	UpgradesAvailable int                  `json:"upgradesAvailable"`
+	Groups            []HelmReleaseGroup   `json:"groups"`
+	RecentFailures    []HelmReleaseFailure `json:"recentFailures"`
+}
+
+// HelmFailureDetail is the drill-down view for a failing release
+type HelmFailureDetail struct {
+	Release            HelmReleaseFailure `json:"release"`
+	FailedHooks        []HelmHook         `json:"failedHooks"`
+	UnhealthyResources []OwnedResource    `json:"unhealthyResources"`
+	History            []HelmRevision     `json:"history"`
+}
+
+// ============================================================================
+// CRD Compatibility Types
+// ============================================================================
+
+// CRDCheckSeverity indicates how serious a CRD compatibility finding is
+type CRDCheckSeverity string
+
+const (
+	CRDCheckInfo    CRDCheckSeverity = "info"
+	CRDCheckWarning CRDCheckSeverity = "warning"
+	CRDCheckDanger  CRDCheckSeverity = "danger"
+)
+
+// CRDCheckFinding is a single compatibility issue for one CRD
+type CRDCheckFinding struct {
+	Severity CRDCheckSeverity `json:"severity"`
+	Version  string           `json:"version,omitempty"`
+	Message  string           `json:"message"`
+}
+
+// CRDCompatibility is the comparison result for a single CRD in the target chart
+type CRDCompatibility struct {
+	Name             string            `json:"name"`
+	File             string            `json:"file"`
+	FromTemplate     bool              `json:"fromTemplate,omitempty"` // Rendered from templates/, which Helm applies on upgrade
+	Installed        bool              `json:"installed"`
+	InstalledStorage string            `json:"installedStorageVersion,omitempty"`
+	NewStorage       string            `json:"newStorageVersion,omitempty"`
+	StoredVersions   []string          `json:"storedVersions,omitempty"` // Versions existing objects may still be persisted in
+	InstalledServed  []string          `json:"installedServedVersions,omitempty"`
+	NewServed        []string          `json:"newServedVersions,omitempty"`
+	Findings         []CRDCheckFinding `json:"findings"`
+}
+
+// CRDCompatibilityReport summarizes CRD changes for a pending Helm upgrade
+type CRDCompatibilityReport struct {
+	Release       string             `json:"release"`
+	Namespace     string             `json:"namespace"`
+	Chart         string             `json:"chart"`
+	TargetVersion string             `json:"targetVersion"`
+	CRDs          []CRDCompatibility `json:"crds"`
+	Warnings      int                `json:"warnings"`
+	Dangers       int                `json:"dangers"`
+	Safe          bool               `json:"safe"` // No warning or danger findings
+}
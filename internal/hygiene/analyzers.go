@@ -0,0 +1,256 @@
+package hygiene
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/metadata"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// tally counts checked resources and violations per namespace and check
+type tally map[string]map[CheckID]*CheckResult
+
+func (t tally) add(namespace string, check CheckID, violated bool) {
+	byCheck, ok := t[namespace]
+	if !ok {
+		byCheck = make(map[CheckID]*CheckResult)
+		t[namespace] = byCheck
+	}
+	r, ok := byCheck[check]
+	if !ok {
+		r = &CheckResult{Check: check}
+		byCheck[check] = r
+	}
+	r.Checked++
+	if violated {
+		r.Violations++
+	}
+}
+
+// analyzer runs all checks against the resource cache
+type analyzer struct {
+	cache    *k8s.ResourceCache
+	tally    tally
+	findings []Finding
+	prodNS   map[string]bool
+}
+
+func (a *analyzer) record(check CheckID, kind, namespace, name string, violated bool, message string, suggestions ...Suggestion) {
+	a.tally.add(namespace, check, violated)
+	if violated {
+		a.findings = append(a.findings, Finding{
+			Check:     check,
+			Kind:      kind,
+			Namespace: namespace,
+			Name:      name,
+			Message:   message,
+
+			Suggestions: suggestions,
+		})
+	}
+}
+
+// workload is the common shape of pod-template-owning workloads
+type workload struct {
+	kind      string
+	namespace string
+	name      string
+	replicas  *int32 // nil for DaemonSets
+	template  *corev1.PodTemplateSpec
+}
+
+func (a *analyzer) workloads() []workload {
+	var result []workload
+	if deps, err := a.cache.Deployments().List(labels.Everything()); err == nil {
+		for _, d := range deps {
+			result = append(result, workload{"Deployment", d.Namespace, d.Name, d.Spec.Replicas, &d.Spec.Template})
+		}
+	}
+	if stss, err := a.cache.StatefulSets().List(labels.Everything()); err == nil {
+		for _, s := range stss {
+			result = append(result, workload{"StatefulSet", s.Namespace, s.Name, s.Spec.Replicas, &s.Spec.Template})
+		}
+	}
+	if dss, err := a.cache.DaemonSets().List(labels.Everything()); err == nil {
+		for _, d := range dss {
+			result = append(result, workload{"DaemonSet", d.Namespace, d.Name, nil, &d.Spec.Template})
+		}
+	}
+	return result
+}
+
+// checkWorkloads evaluates limits, probes, image tags and replica counts
+func (a *analyzer) checkWorkloads() {
+	for _, w := range a.workloads() {
+		containers := w.template.Spec.Containers
+
+		var noLimits, noProbes, latest []string
+		for _, c := range containers {
+			if c.Resources.Limits.Cpu().IsZero() && c.Resources.Limits.Memory().IsZero() {
+				noLimits = append(noLimits, c.Name)
+			}
+			if c.ReadinessProbe == nil && c.LivenessProbe == nil {
+				noProbes = append(noProbes, c.Name)
+			}
+			if usesLatestTag(c.Image) {
+				latest = append(latest, c.Image)
+			}
+		}
+		for _, c := range w.template.Spec.InitContainers {
+			if usesLatestTag(c.Image) {
+				latest = append(latest, c.Image)
+			}
+		}
+
+		a.record(CheckMissingLimits, w.kind, w.namespace, w.name, len(noLimits) > 0,
+			fmt.Sprintf("containers without resource limits: %s", strings.Join(noLimits, ", ")))
+		a.record(CheckMissingProbes, w.kind, w.namespace, w.name, len(noProbes) > 0,
+			fmt.Sprintf("containers without readiness or liveness probes: %s", strings.Join(noProbes, ", ")))
+		a.record(CheckLatestTag, w.kind, w.namespace, w.name, len(latest) > 0,
+			fmt.Sprintf("mutable image tags: %s", strings.Join(latest, ", ")))
+
+		if w.replicas != nil && a.prodNS[w.namespace] {
+			single := *w.replicas == 1
+			a.record(CheckSingleReplica, w.kind, w.namespace, w.name, single,
+				"production workload runs a single replica")
+		}
+	}
+}
+
+// checkUnowned flags bare pods and ReplicaSets that no controller manages
+func (a *analyzer) checkUnowned() {
+	if pods, err := a.cache.Pods().List(labels.Everything()); err == nil {
+		for _, p := range pods {
+			// Static pods are mirrored by the kubelet and legitimately unowned by controllers
+			if _, mirror := p.Annotations[corev1.MirrorPodAnnotationKey]; mirror {
+				continue
+			}
+			if p.Status.Phase == corev1.PodSucceeded || p.Status.Phase == corev1.PodFailed {
+				continue
+			}
+			a.record(CheckUnowned, "Pod", p.Namespace, p.Name, len(p.OwnerReferences) == 0,
+				"pod is not managed by a controller and won't be rescheduled")
+		}
+	}
+	if rss, err := a.cache.ReplicaSets().List(labels.Everything()); err == nil {
+		for _, rs := range rss {
+			a.record(CheckUnowned, "ReplicaSet", rs.Namespace, rs.Name, metav1.GetControllerOfNoCopy(rs) == nil,
+				"ReplicaSet is not managed by a Deployment")
+		}
+	}
+}
+
+// deprecatedAPIs maps group/versions that are deprecated or removed to their replacement
+var deprecatedAPIs = map[string]string{
+	"extensions/v1beta1":                   "apps/v1 or networking.k8s.io/v1",
+	"apps/v1beta1":                         "apps/v1",
+	"apps/v1beta2":                         "apps/v1",
+	"networking.k8s.io/v1beta1":            "networking.k8s.io/v1",
+	"batch/v1beta1":                        "batch/v1",
+	"autoscaling/v2beta1":                  "autoscaling/v2",
+	"autoscaling/v2beta2":                  "autoscaling/v2",
+	"policy/v1beta1":                       "policy/v1",
+	"flowcontrol.apiserver.k8s.io/v1beta1": "flowcontrol.apiserver.k8s.io/v1",
+	"flowcontrol.apiserver.k8s.io/v1beta2": "flowcontrol.apiserver.k8s.io/v1",
+}
+
+// deprecatedAPIResources are the resources whose write history is inspected
+var deprecatedAPIResources = []struct {
+	kind string
+	gvr  schema.GroupVersionResource
+}{
+	{"Deployment", appsv1.SchemeGroupVersion.WithResource("deployments")},
+	{"StatefulSet", appsv1.SchemeGroupVersion.WithResource("statefulsets")},
+	{"DaemonSet", appsv1.SchemeGroupVersion.WithResource("daemonsets")},
+	{"Ingress", schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"}},
+	{"CronJob", schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "cronjobs"}},
+	{"HorizontalPodAutoscaler", schema.GroupVersionResource{Group: "autoscaling", Version: "v2", Resource: "horizontalpodautoscalers"}},
+	{"PodDisruptionBudget", schema.GroupVersionResource{Group: "policy", Version: "v1", Resource: "poddisruptionbudgets"}},
+}
+
+// checkDeprecatedAPIs flags objects whose managed fields show writes through deprecated API versions.
+// The informer cache strips managedFields, so this lists object metadata directly.
+func (a *analyzer) checkDeprecatedAPIs(ctx context.Context) {
+	config := k8s.GetConfig()
+	if config == nil {
+		return
+	}
+	client, err := metadata.NewForConfig(config)
+	if err != nil {
+		return
+	}
+	features := k8s.GetFeatures()
+
+	for _, res := range deprecatedAPIResources {
+		if features != nil && !features.ServesGroupVersion(res.gvr.Group, res.gvr.Version) {
+			continue
+		}
+		list, err := client.Resource(res.gvr).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			continue
+		}
+		for _, item := range list.Items {
+			var used []string
+			for _, mf := range item.ManagedFields {
+				if replacement, ok := deprecatedAPIs[mf.APIVersion]; ok {
+					used = append(used, fmt.Sprintf("%s via %s (use %s)", mf.Manager, mf.APIVersion, replacement))
+				}
+			}
+			a.record(CheckDeprecatedAPI, res.kind, item.Namespace, item.Name, len(used) > 0,
+				"written through deprecated API: "+strings.Join(used, "; "))
+		}
+	}
+}
+
+// productionNamespaces returns namespaces that look like production by name or label
+func productionNamespaces(cache *k8s.ResourceCache) map[string]bool {
+	result := make(map[string]bool)
+	namespaces, err := cache.Namespaces().List(labels.Everything())
+	if err != nil {
+		return result
+	}
+	for _, ns := range namespaces {
+		if isProductionNamespace(ns.Name, ns.Labels) {
+			result[ns.Name] = true
+		}
+	}
+	return result
+}
+
+func isProductionNamespace(name string, nsLabels map[string]string) bool {
+	for _, key := range []string{"environment", "env", "tier"} {
+		switch strings.ToLower(nsLabels[key]) {
+		case "prod", "production":
+			return true
+		}
+	}
+	name = strings.ToLower(name)
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return r == '-' || r == '.' }) {
+		if part == "prod" || part == "production" {
+			return true
+		}
+	}
+	return false
+}
+
+// usesLatestTag reports whether an image reference is mutable (":latest" or untagged, no digest)
+func usesLatestTag(image string) bool {
+	if strings.Contains(image, "@") {
+		return false
+	}
+	// Only look at the last path segment so registry ports aren't mistaken for tags
+	last := image[strings.LastIndex(image, "/")+1:]
+	idx := strings.LastIndex(last, ":")
+	if idx < 0 {
+		return true
+	}
+	return last[idx+1:] == "latest"
+}
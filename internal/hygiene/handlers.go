@@ -0,0 +1,126 @@
+package hygiene
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// Handlers provides HTTP handlers for hygiene scoring endpoints
+type Handlers struct{}
+
+// NewHandlers creates a new Handlers instance
+func NewHandlers() *Handlers {
+	return &Handlers{}
+}
+
+// RegisterRoutes registers hygiene routes on the given router
+func (h *Handlers) RegisterRoutes(r chi.Router) {
+	r.Route("/hygiene", func(r chi.Router) {
+		r.Get("/", h.handleGetScore)
+		r.Get("/history", h.handleGetHistory)
+	})
+}
+
+// handleGetScore returns the hygiene report. The last recorded report is reused unless
+// ?refresh=true is passed or none exists for the current context.
+// ?namespace= limits namespaces and findings to a single namespace.
+func (h *Handlers) handleGetScore(w http.ResponseWriter, r *http.Request) {
+	var report *Report
+	rec := GetRecorder()
+	if rec != nil && r.URL.Query().Get("refresh") != "true" {
+		report = rec.Latest()
+	}
+	if report == nil {
+		cache := k8s.GetResourceCache()
+		if cache == nil {
+			explorerErrors.Write(w, explorerErrors.CacheNotInitialized())
+			return
+		}
+		var err error
+		report, err = Evaluate(r.Context(), cache)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	if ns := r.URL.Query().Get("namespace"); ns != "" {
+		scoped, ok := filterNamespace(report, ns)
+		if !ok {
+			writeError(w, http.StatusNotFound, "no scored resources in namespace "+ns)
+			return
+		}
+		report = scoped
+	}
+	if r.URL.Query().Get("findings") == "false" {
+		trimmed := *report
+		trimmed.Findings = nil
+		report = &trimmed
+	}
+
+	writeJSON(w, report)
+}
+
+// handleGetHistory returns score history for the current context (or ?context=)
+func (h *Handlers) handleGetHistory(w http.ResponseWriter, r *http.Request) {
+	rec := GetRecorder()
+	if rec == nil {
+		writeError(w, http.StatusServiceUnavailable, "Hygiene recorder not running")
+		return
+	}
+
+	contextName := r.URL.Query().Get("context")
+	if contextName == "" {
+		contextName = k8s.GetContextName()
+	}
+
+	var since time.Time
+	if v := r.URL.Query().Get("since"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			writeError(w, http.StatusBadRequest, "since must be a positive Go duration (e.g. 24h, 168h)")
+			return
+		}
+		since = time.Now().Add(-d)
+	}
+
+	writeJSON(w, rec.History(contextName, r.URL.Query().Get("namespace"), since))
+}
+
+// filterNamespace returns a copy of the report scoped to one namespace
+func filterNamespace(report *Report, namespace string) (*Report, bool) {
+	found := false
+	scoped := *report
+	scoped.Namespaces = nil
+	scoped.Findings = nil
+	for _, ns := range report.Namespaces {
+		if ns.Namespace == namespace {
+			scoped.Namespaces = append(scoped.Namespaces, ns)
+			scoped.Score = ns.Score
+			scoped.Grade = ns.Grade
+			scoped.Checks = ns.Checks
+			found = true
+		}
+	}
+	for _, f := range report.Findings {
+		if f.Namespace == namespace {
+			scoped.Findings = append(scoped.Findings, f)
+		}
+	}
+	return &scoped, found
+}
+
+func writeJSON(w http.ResponseWriter, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(data)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	explorerErrors.WriteHTTP(w, status, message)
+}
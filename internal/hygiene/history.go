@@ -0,0 +1,218 @@
+package hygiene
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+const (
+	// DefaultInterval is how often a hygiene snapshot is recorded
+	DefaultInterval = time.Hour
+	// maxSnapshotsPerContext bounds history (30 days at the default interval)
+	maxSnapshotsPerContext = 30 * 24
+)
+
+// Recorder periodically evaluates hygiene and keeps a per-context score history,
+// persisted as JSON so trends survive restarts
+type Recorder struct {
+	path     string
+	interval time.Duration
+
+	mu      sync.RWMutex
+	history map[string][]Snapshot // context name -> snapshots (oldest first)
+	latest  *Report
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+var (
+	recorder     *Recorder
+	recorderOnce sync.Once
+)
+
+// InitRecorder loads existing history from path (empty = in-memory only) and starts recording
+func InitRecorder(path string, interval time.Duration) error {
+	var initErr error
+	recorderOnce.Do(func() {
+		if interval <= 0 {
+			interval = DefaultInterval
+		}
+		r := &Recorder{
+			path:     path,
+			interval: interval,
+			history:  make(map[string][]Snapshot),
+			stopCh:   make(chan struct{}),
+		}
+		if err := r.load(); err != nil {
+			initErr = err
+			return
+		}
+		recorder = r
+		r.wg.Add(1)
+		go r.loop()
+		log.Printf("Hygiene scoring started (interval=%s)", interval)
+	})
+	return initErr
+}
+
+// GetRecorder returns the hygiene recorder (nil if not started)
+func GetRecorder() *Recorder {
+	return recorder
+}
+
+// StopRecorder stops periodic recording
+func StopRecorder() {
+	if recorder != nil {
+		close(recorder.stopCh)
+		recorder.wg.Wait()
+	}
+}
+
+func (r *Recorder) loop() {
+	defer r.wg.Done()
+
+	// Give informers a moment to settle before the first snapshot
+	select {
+	case <-r.stopCh:
+		return
+	case <-time.After(time.Minute):
+	}
+	r.RecordNow()
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.stopCh:
+			return
+		case <-ticker.C:
+			r.RecordNow()
+		}
+	}
+}
+
+// RecordNow evaluates hygiene and appends a snapshot for the current context
+func (r *Recorder) RecordNow() {
+	cache := k8s.GetResourceCache()
+	if cache == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	report, err := Evaluate(ctx, cache)
+	if err != nil {
+		log.Printf("Warning: hygiene evaluation failed: %v", err)
+		return
+	}
+
+	r.mu.Lock()
+	r.latest = report
+	snaps := append(r.history[report.Context], snapshotOf(report))
+	if len(snaps) > maxSnapshotsPerContext {
+		snaps = snaps[len(snaps)-maxSnapshotsPerContext:]
+	}
+	r.history[report.Context] = snaps
+	r.mu.Unlock()
+
+	if err := r.save(); err != nil {
+		log.Printf("Warning: failed to persist hygiene history: %v", err)
+	}
+}
+
+// Latest returns the most recent report if it belongs to the current context
+func (r *Recorder) Latest() *Report {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.latest == nil || r.latest.Context != k8s.GetContextName() {
+		return nil
+	}
+	return r.latest
+}
+
+// History returns score points for a context, optionally for one namespace, since a time
+func (r *Recorder) History(contextName, namespace string, since time.Time) HistoryResponse {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	resp := HistoryResponse{Context: contextName, Namespace: namespace, Points: []TrendPoint{}}
+	for _, s := range r.history[contextName] {
+		if s.Timestamp.Before(since) {
+			continue
+		}
+		score := s.Score
+		if namespace != "" {
+			nsScore, ok := s.Namespaces[namespace]
+			if !ok {
+				continue
+			}
+			score = nsScore
+		}
+		resp.Points = append(resp.Points, TrendPoint{Timestamp: s.Timestamp, Score: score})
+	}
+	resp.Trend = trendOf(resp.Points)
+	return resp
+}
+
+// trendOf summarizes the direction of a score series
+func trendOf(points []TrendPoint) *Trend {
+	if len(points) < 2 {
+		return nil
+	}
+	first, last := points[0].Score, points[len(points)-1].Score
+	t := &Trend{First: first, Last: last, Change: round1(last - first), Direction: "stable"}
+	// Ignore sub-point jitter
+	if t.Change >= 1 {
+		t.Direction = "improving"
+	} else if t.Change <= -1 {
+		t.Direction = "declining"
+	}
+	return t
+}
+
+func (r *Recorder) load() error {
+	if r.path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(r.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read hygiene history: %w", err)
+	}
+	if err := json.Unmarshal(data, &r.history); err != nil {
+		return fmt.Errorf("failed to parse hygiene history %s: %w", r.path, err)
+	}
+	return nil
+}
+
+func (r *Recorder) save() error {
+	if r.path == "" {
+		return nil
+	}
+	r.mu.RLock()
+	data, err := json.Marshal(r.history)
+	r.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	// Write atomically so a crash never leaves truncated history
+	tmp := r.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, r.path)
+}
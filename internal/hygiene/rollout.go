@@ -0,0 +1,297 @@
+package hygiene
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// defaultGracePeriod is the pod terminationGracePeriodSeconds when unset
+const defaultGracePeriod = 30
+
+// rolloutTarget is a replicated workload whose update strategy is reviewed
+type rolloutTarget struct {
+	kind      string
+	namespace string
+	name      string
+	replicas  int32
+	template  *corev1.PodTemplateSpec
+
+	// Deployments: Recreate or RollingUpdate with surge/unavailable budgets
+	recreate       bool
+	maxSurge       *intstr.IntOrString
+	maxUnavailable *intstr.IntOrString
+
+	fronted bool                           // Selected by a Service, so it receives traffic during rollouts
+	pdbs    []policyv1.PodDisruptionBudget // PDBs selecting the workload's pods
+}
+
+// checkRolloutSafety reviews update strategies, PDBs, probes and shutdown handling of
+// Deployments and StatefulSets for combinations that cause downtime during rollouts or
+// node drains. Findings carry concrete suggested values.
+func (a *analyzer) checkRolloutSafety(ctx context.Context) {
+	pdbs := a.cache.PodDisruptionBudgets(ctx, "")
+	services, _ := a.cache.Services().List(labels.Everything())
+
+	fronted := func(namespace string, podLabels map[string]string) bool {
+		for _, svc := range services {
+			if svc.Namespace == namespace && len(svc.Spec.Selector) > 0 &&
+				labels.SelectorFromSet(svc.Spec.Selector).Matches(labels.Set(podLabels)) {
+				return true
+			}
+		}
+		return false
+	}
+	matchingPDBs := func(namespace string, podLabels map[string]string) []policyv1.PodDisruptionBudget {
+		var out []policyv1.PodDisruptionBudget
+		for _, pdb := range pdbs {
+			if pdb.Namespace != namespace || pdb.Spec.Selector == nil {
+				continue
+			}
+			selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+			if err == nil && !selector.Empty() && selector.Matches(labels.Set(podLabels)) {
+				out = append(out, pdb)
+			}
+		}
+		return out
+	}
+
+	var targets []rolloutTarget
+	if deps, err := a.cache.Deployments().List(labels.Everything()); err == nil {
+		for _, d := range deps {
+			t := rolloutTarget{
+				kind: "Deployment", namespace: d.Namespace, name: d.Name, replicas: replicasOf(d.Spec.Replicas),
+				template: &d.Spec.Template, recreate: d.Spec.Strategy.Type == appsv1.RecreateDeploymentStrategyType,
+			}
+			if ru := d.Spec.Strategy.RollingUpdate; ru != nil {
+				t.maxSurge, t.maxUnavailable = ru.MaxSurge, ru.MaxUnavailable
+			}
+			targets = append(targets, t)
+		}
+	}
+	if stss, err := a.cache.StatefulSets().List(labels.Everything()); err == nil {
+		for _, s := range stss {
+			targets = append(targets, rolloutTarget{
+				kind: "StatefulSet", namespace: s.Namespace, name: s.Name, replicas: replicasOf(s.Spec.Replicas),
+				template: &s.Spec.Template,
+			})
+		}
+	}
+
+	for _, t := range targets {
+		if t.replicas == 0 {
+			continue // Scaled to zero, nothing to disrupt
+		}
+		t.fronted = fronted(t.namespace, t.template.Labels)
+		t.pdbs = matchingPDBs(t.namespace, t.template.Labels)
+		issues, suggestions := rolloutAdvice(t)
+		a.record(CheckRolloutSafety, t.kind, t.namespace, t.name, len(issues) > 0,
+			strings.Join(issues, "; "), suggestions...)
+	}
+}
+
+// rolloutAdvice returns the downtime risks of a workload and the changes that fix them
+func rolloutAdvice(t rolloutTarget) (issues []string, suggestions []Suggestion) {
+	spec := t.template.Spec
+
+	// Update strategy
+	if t.recreate && !mountsClaims(spec) {
+		issues = append(issues, "Recreate strategy stops every pod before starting new ones")
+		suggestions = append(suggestions,
+			Suggestion{Field: "spec.strategy.type", Current: "Recreate", Suggested: "RollingUpdate",
+				Reason: "replace pods gradually instead of all at once"},
+			Suggestion{Field: "spec.strategy.rollingUpdate.maxUnavailable", Suggested: "0",
+				Reason: "keep full capacity while new pods start"})
+	}
+	if t.kind == "Deployment" && !t.recreate {
+		surge, unavailable := rollingBudgets(t.maxSurge, t.maxUnavailable, t.replicas)
+		if unavailable >= t.replicas {
+			issues = append(issues, fmt.Sprintf("maxUnavailable allows all %d replicas to be down during a rollout", t.replicas))
+			suggestions = append(suggestions, Suggestion{
+				Field: "spec.strategy.rollingUpdate.maxUnavailable", Current: intOrStringValue(t.maxUnavailable, "25%"), Suggested: "0",
+				Reason: "never take serving pods down before replacements are ready"})
+			if surge == 0 {
+				suggestions = append(suggestions, Suggestion{
+					Field: "spec.strategy.rollingUpdate.maxSurge", Current: intOrStringValue(t.maxSurge, "25%"), Suggested: "1",
+					Reason: "a rollout with maxUnavailable 0 needs room to surge"})
+			}
+		}
+	}
+
+	// Readiness gates traffic and rollout progress on new pods actually being able to serve
+	if t.fronted && !anyContainer(spec.Containers, func(c corev1.Container) bool { return c.ReadinessProbe != nil }) {
+		issues = append(issues, "no readiness probe, so new pods receive traffic and old ones are removed before they can serve")
+		suggestions = append(suggestions, Suggestion{
+			Field:     fmt.Sprintf("spec.template.spec.containers[%s].readinessProbe", spec.Containers[0].Name),
+			Suggested: suggestedReadinessProbe(spec.Containers[0]),
+			Reason:    "only route traffic to pods that are ready"})
+	}
+
+	// Graceful shutdown: endpoints are removed asynchronously, so a pod that exits on SIGTERM
+	// right away still gets requests for a few seconds
+	grace := int64(defaultGracePeriod)
+	if spec.TerminationGracePeriodSeconds != nil {
+		grace = *spec.TerminationGracePeriodSeconds
+	}
+	longestPreStop := int64(0)
+	hasPreStop := false
+	for _, c := range spec.Containers {
+		if c.Lifecycle != nil && c.Lifecycle.PreStop != nil {
+			hasPreStop = true
+			longestPreStop = max(longestPreStop, preStopSeconds(c.Lifecycle.PreStop))
+		}
+	}
+	if t.fronted && !hasPreStop {
+		issues = append(issues, "no preStop hook, so pods stop before load balancers drain them")
+		suggestions = append(suggestions, Suggestion{
+			Field:     fmt.Sprintf("spec.template.spec.containers[%s].lifecycle.preStop", spec.Containers[0].Name),
+			Suggested: `{"sleep": {"seconds": 10}}`,
+			Reason:    "keep serving while endpoints are removed (use exec: [\"sleep\", \"10\"] before Kubernetes 1.30)"})
+		longestPreStop = 10
+	}
+	if longestPreStop > 0 && grace <= longestPreStop+5 {
+		issues = append(issues, fmt.Sprintf("terminationGracePeriodSeconds (%d) leaves no time to shut down after a %ds preStop hook", grace, longestPreStop))
+		suggestions = append(suggestions, Suggestion{
+			Field: "spec.template.spec.terminationGracePeriodSeconds", Current: strconv.FormatInt(grace, 10),
+			Suggested: strconv.FormatInt(longestPreStop+30, 10),
+			Reason:    "allow the preStop hook plus time to finish in-flight requests"})
+	}
+
+	// Node drains
+	if t.replicas > 1 && len(t.pdbs) == 0 {
+		issues = append(issues, fmt.Sprintf("no PodDisruptionBudget, so a node drain can evict all %d replicas at once", t.replicas))
+		suggestions = append(suggestions, Suggestion{
+			Field: "PodDisruptionBudget.spec.maxUnavailable", Suggested: "1",
+			Reason: "create a PDB selecting these pods so drains evict one at a time"})
+	}
+	for _, pdb := range t.pdbs {
+		if allowed, ok := pdbAllowsDisruption(pdb, t.replicas); ok && !allowed {
+			issues = append(issues, fmt.Sprintf("PodDisruptionBudget %s allows no disruptions, so node drains will hang", pdb.Name))
+			suggestions = append(suggestions, Suggestion{
+				Field: fmt.Sprintf("PodDisruptionBudget/%s.spec", pdb.Name), Current: pdbBudget(pdb), Suggested: "maxUnavailable: 1",
+				Reason: "let drains make progress one pod at a time"})
+		}
+	}
+	return issues, suggestions
+}
+
+// rollingBudgets resolves maxSurge/maxUnavailable (defaults 25%) to pod counts the way the
+// Deployment controller does: surge rounds up, unavailable rounds down
+func rollingBudgets(maxSurge, maxUnavailable *intstr.IntOrString, replicas int32) (surge, unavailable int32) {
+	def := intstr.FromString("25%")
+	if maxSurge == nil {
+		maxSurge = &def
+	}
+	if maxUnavailable == nil {
+		maxUnavailable = &def
+	}
+	s, err := intstr.GetScaledValueFromIntOrPercent(maxSurge, int(replicas), true)
+	if err != nil {
+		s = 0
+	}
+	u, err := intstr.GetScaledValueFromIntOrPercent(maxUnavailable, int(replicas), false)
+	if err != nil {
+		u = 0
+	}
+	// The controller bumps unavailable to 1 when both resolve to 0
+	if s == 0 && u == 0 {
+		u = 1
+	}
+	return int32(s), int32(u)
+}
+
+// pdbAllowsDisruption reports whether a PDB permits at least one eviction at full strength.
+// ok is false when the budget can't be evaluated (e.g. an invalid percentage).
+func pdbAllowsDisruption(pdb policyv1.PodDisruptionBudget, replicas int32) (allowed, ok bool) {
+	switch {
+	case pdb.Spec.MaxUnavailable != nil:
+		v, err := intstr.GetScaledValueFromIntOrPercent(pdb.Spec.MaxUnavailable, int(replicas), true)
+		return v > 0, err == nil
+	case pdb.Spec.MinAvailable != nil:
+		v, err := intstr.GetScaledValueFromIntOrPercent(pdb.Spec.MinAvailable, int(replicas), true)
+		return int32(v) < replicas, err == nil
+	}
+	return true, true
+}
+
+func pdbBudget(pdb policyv1.PodDisruptionBudget) string {
+	if pdb.Spec.MaxUnavailable != nil {
+		return "maxUnavailable: " + pdb.Spec.MaxUnavailable.String()
+	}
+	if pdb.Spec.MinAvailable != nil {
+		return "minAvailable: " + pdb.Spec.MinAvailable.String()
+	}
+	return ""
+}
+
+// preStopSeconds estimates how long a preStop hook delays shutdown (sleep actions and
+// exec sleep commands; other hooks count as 0)
+func preStopSeconds(h *corev1.LifecycleHandler) int64 {
+	if h.Sleep != nil {
+		return h.Sleep.Seconds
+	}
+	if h.Exec != nil {
+		cmd := h.Exec.Command
+		// ["sleep", "10"] or ["/bin/sh", "-c", "sleep 10"]
+		if len(cmd) > 0 {
+			fields := strings.Fields(cmd[len(cmd)-1])
+			if len(cmd) >= 2 && strings.HasSuffix(cmd[0], "sleep") {
+				fields = []string{"sleep", cmd[1]}
+			}
+			if len(fields) == 2 && fields[0] == "sleep" {
+				if n, err := strconv.ParseFloat(fields[1], 64); err == nil {
+					return int64(n)
+				}
+			}
+		}
+	}
+	return 0
+}
+
+// suggestedReadinessProbe proposes a probe against the container's first port
+func suggestedReadinessProbe(c corev1.Container) string {
+	if len(c.Ports) == 0 {
+		return `{"exec": {"command": ["<health check>"]}, "periodSeconds": 5, "failureThreshold": 3}`
+	}
+	return fmt.Sprintf(`{"tcpSocket": {"port": %d}, "periodSeconds": 5, "failureThreshold": 3}`, c.Ports[0].ContainerPort)
+}
+
+func mountsClaims(spec corev1.PodSpec) bool {
+	for _, v := range spec.Volumes {
+		if v.PersistentVolumeClaim != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func anyContainer(containers []corev1.Container, pred func(corev1.Container) bool) bool {
+	for _, c := range containers {
+		if pred(c) {
+			return true
+		}
+	}
+	return false
+}
+
+func intOrStringValue(v *intstr.IntOrString, def string) string {
+	if v == nil {
+		return def
+	}
+	return v.String()
+}
+
+func replicasOf(r *int32) int32 {
+	if r == nil {
+		return 1
+	}
+	return *r
+}
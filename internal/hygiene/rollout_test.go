@@ -0,0 +1,52 @@
+package hygiene
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestRolloutAdvice(t *testing.T) {
+	safePod := func() *corev1.PodTemplateSpec {
+		return &corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name:           "app",
+			ReadinessProbe: &corev1.Probe{},
+			Lifecycle:      &corev1.Lifecycle{PreStop: &corev1.LifecycleHandler{Sleep: &corev1.SleepAction{Seconds: 5}}},
+		}}}}
+	}
+	one, zero := intstr.FromInt32(1), intstr.FromInt32(0)
+	pdb := policyv1.PodDisruptionBudget{Spec: policyv1.PodDisruptionBudgetSpec{MaxUnavailable: &one}}
+	blocking := policyv1.PodDisruptionBudget{Spec: policyv1.PodDisruptionBudgetSpec{MaxUnavailable: &zero}}
+	allDown := intstr.FromString("100%")
+
+	noProbe := safePod()
+	noProbe.Spec.Containers[0].ReadinessProbe = nil
+	shortGrace := safePod()
+	grace := int64(8)
+	shortGrace.Spec.TerminationGracePeriodSeconds = &grace
+
+	tests := []struct {
+		name       string
+		target     rolloutTarget
+		wantIssues int
+	}{
+		{"safe", rolloutTarget{kind: "Deployment", replicas: 3, template: safePod(), fronted: true, pdbs: []policyv1.PodDisruptionBudget{pdb}}, 0},
+		{"recreate", rolloutTarget{kind: "Deployment", replicas: 1, template: safePod(), recreate: true}, 1},
+		{"all unavailable", rolloutTarget{kind: "Deployment", replicas: 2, template: safePod(), maxUnavailable: &allDown, pdbs: []policyv1.PodDisruptionBudget{pdb}}, 1},
+		{"no PDB", rolloutTarget{kind: "StatefulSet", replicas: 3, template: safePod()}, 1},
+		{"blocking PDB", rolloutTarget{kind: "StatefulSet", replicas: 3, template: safePod(), pdbs: []policyv1.PodDisruptionBudget{blocking}}, 1},
+		{"fronted without probe", rolloutTarget{kind: "Deployment", replicas: 1, template: noProbe, fronted: true}, 1},
+		{"preStop exceeds grace", rolloutTarget{kind: "Deployment", replicas: 1, template: shortGrace}, 1},
+	}
+	for _, tt := range tests {
+		issues, suggestions := rolloutAdvice(tt.target)
+		if len(issues) != tt.wantIssues {
+			t.Errorf("%s: got issues %q, want %d", tt.name, issues, tt.wantIssues)
+		}
+		if len(issues) > 0 && len(suggestions) == 0 {
+			t.Errorf("%s: issues without suggestions", tt.name)
+		}
+	}
+}
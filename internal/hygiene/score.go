@@ -0,0 +1,147 @@
+package hygiene
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// Evaluate runs all hygiene checks and scores the cluster and each namespace
+func Evaluate(ctx context.Context, cache *k8s.ResourceCache) (*Report, error) {
+	if cache == nil {
+		return nil, fmt.Errorf("resource cache not available")
+	}
+
+	a := &analyzer{
+		cache:  cache,
+		tally:  make(tally),
+		prodNS: productionNamespaces(cache),
+	}
+	a.checkWorkloads()
+	a.checkUnowned()
+	a.checkDeprecatedAPIs(ctx)
+	a.checkRolloutSafety(ctx)
+
+	report := &Report{
+		Context:   k8s.GetContextName(),
+		Timestamp: time.Now(),
+		Findings:  a.findings,
+	}
+
+	// Cluster-wide totals per check
+	cluster := make(map[CheckID]*CheckResult)
+	for ns, byCheck := range a.tally {
+		for id, r := range byCheck {
+			total, ok := cluster[id]
+			if !ok {
+				total = &CheckResult{Check: id}
+				cluster[id] = total
+			}
+			total.Checked += r.Checked
+			total.Violations += r.Violations
+		}
+
+		checks := orderedResults(byCheck)
+		score := weightedScore(checks)
+		report.Namespaces = append(report.Namespaces, NamespaceScore{
+			Namespace: ns,
+			Score:     score,
+			Grade:     Grade(score),
+			Checks:    checks,
+		})
+	}
+
+	report.Checks = orderedResults(cluster)
+	report.Score = weightedScore(report.Checks)
+	report.Grade = Grade(report.Score)
+
+	sort.Slice(report.Namespaces, func(i, j int) bool {
+		if report.Namespaces[i].Score != report.Namespaces[j].Score {
+			return report.Namespaces[i].Score < report.Namespaces[j].Score
+		}
+		return report.Namespaces[i].Namespace < report.Namespaces[j].Namespace
+	})
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		fi, fj := report.Findings[i], report.Findings[j]
+		if fi.Namespace != fj.Namespace {
+			return fi.Namespace < fj.Namespace
+		}
+		return fi.Check < fj.Check
+	})
+
+	return report, nil
+}
+
+// orderedResults returns check results in AllChecks order with per-check scores filled in
+func orderedResults(byCheck map[CheckID]*CheckResult) []CheckResult {
+	results := make([]CheckResult, 0, len(byCheck))
+	for _, id := range AllChecks {
+		r, ok := byCheck[id]
+		if !ok {
+			continue
+		}
+		res := *r
+		res.Score = round1(100 * (1 - float64(res.Violations)/float64(res.Checked)))
+		results = append(results, res)
+	}
+	return results
+}
+
+// weightedScore combines check scores using checkWeights. Checks that didn't apply are ignored;
+// a scope where nothing applied scores 100.
+func weightedScore(checks []CheckResult) float64 {
+	var sum, weights float64
+	for _, c := range checks {
+		if c.Checked == 0 {
+			continue
+		}
+		w := checkWeights[c.Check]
+		sum += w * c.Score
+		weights += w
+	}
+	if weights == 0 {
+		return 100
+	}
+	return round1(sum / weights)
+}
+
+// Grade maps a score to a letter grade
+func Grade(score float64) string {
+	switch {
+	case score >= 90:
+		return "A"
+	case score >= 80:
+		return "B"
+	case score >= 70:
+		return "C"
+	case score >= 60:
+		return "D"
+	default:
+		return "F"
+	}
+}
+
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
+
+// snapshotOf reduces a report to the values kept in history
+func snapshotOf(r *Report) Snapshot {
+	s := Snapshot{
+		Timestamp:  r.Timestamp,
+		Score:      r.Score,
+		Checks:     make(map[CheckID]int, len(r.Checks)),
+		Namespaces: make(map[string]float64, len(r.Namespaces)),
+	}
+	for _, c := range r.Checks {
+		s.Checks[c.Check] = c.Violations
+	}
+	for _, ns := range r.Namespaces {
+		s.Namespaces[ns.Namespace] = ns.Score
+	}
+	return s
+}
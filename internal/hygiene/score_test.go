@@ -0,0 +1,65 @@
+package hygiene
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUsesLatestTag(t *testing.T) {
+	cases := map[string]bool{
+		"nginx":                              true,
+		"nginx:latest":                       true,
+		"nginx:1.27":                         false,
+		"registry.local:5000/app":            true,
+		"registry.local:5000/app:v1":         false,
+		"ghcr.io/org/app@sha256:abcd":        false,
+		"ghcr.io/org/app:latest@sha256:abcd": false,
+	}
+	for image, want := range cases {
+		if got := usesLatestTag(image); got != want {
+			t.Errorf("usesLatestTag(%q) = %v, want %v", image, got, want)
+		}
+	}
+}
+
+func TestIsProductionNamespace(t *testing.T) {
+	if !isProductionNamespace("payments-prod", nil) {
+		t.Error("expected payments-prod to be production")
+	}
+	if !isProductionNamespace("payments", map[string]string{"environment": "Production"}) {
+		t.Error("expected environment=Production label to mark production")
+	}
+	if isProductionNamespace("product-catalog", nil) {
+		t.Error("product-catalog should not be treated as production")
+	}
+}
+
+func TestWeightedScore(t *testing.T) {
+	checks := orderedResults(map[CheckID]*CheckResult{
+		CheckDeprecatedAPI: {Check: CheckDeprecatedAPI, Checked: 10, Violations: 0},
+		CheckUnowned:       {Check: CheckUnowned, Checked: 4, Violations: 4},
+	})
+	// (3*100 + 1*0) / 4
+	if got := weightedScore(checks); got != 75 {
+		t.Errorf("weightedScore = %v, want 75", got)
+	}
+	if got := weightedScore(nil); got != 100 {
+		t.Errorf("weightedScore(nil) = %v, want 100", got)
+	}
+}
+
+func TestTrendOf(t *testing.T) {
+	now := time.Now()
+	points := []TrendPoint{
+		{Timestamp: now.Add(-2 * time.Hour), Score: 70},
+		{Timestamp: now.Add(-time.Hour), Score: 72},
+		{Timestamp: now, Score: 80.5},
+	}
+	trend := trendOf(points)
+	if trend == nil || trend.Direction != "improving" || trend.Change != 10.5 {
+		t.Errorf("unexpected trend %+v", trend)
+	}
+	if trendOf(points[:1]) != nil {
+		t.Error("expected nil trend for a single point")
+	}
+}
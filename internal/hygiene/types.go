@@ -0,0 +1,114 @@
+package hygiene
+
+import "time"
+
+// CheckID identifies a hygiene check
+type CheckID string
+
+const (
+	CheckMissingLimits CheckID = "missing-limits" // Containers without CPU/memory limits
+	CheckMissingProbes CheckID = "missing-probes" // Containers without a readiness probe
+	CheckDeprecatedAPI CheckID = "deprecated-api" // Objects last written through a deprecated/removed API version
+	CheckUnowned       CheckID = "unowned"        // Bare pods/ReplicaSets with no controller
+	CheckSingleReplica CheckID = "single-replica" // Production workloads running a single replica
+	CheckLatestTag     CheckID = "latest-tag"     // Images using :latest or no tag
+	CheckRolloutSafety CheckID = "rollout-safety" // Update strategy, PDB and shutdown settings that cause downtime during rollouts or drains
+)
+
+// checkWeights controls how much each check contributes to a score
+var checkWeights = map[CheckID]float64{
+	CheckMissingLimits: 2,
+	CheckMissingProbes: 2,
+	CheckDeprecatedAPI: 3,
+	CheckUnowned:       1,
+	CheckSingleReplica: 2,
+	CheckLatestTag:     2,
+	CheckRolloutSafety: 2,
+}
+
+// AllChecks lists checks in display order
+var AllChecks = []CheckID{
+	CheckDeprecatedAPI,
+	CheckMissingLimits,
+	CheckMissingProbes,
+	CheckSingleReplica,
+	CheckRolloutSafety,
+	CheckLatestTag,
+	CheckUnowned,
+}
+
+// Finding is a single resource failing a check
+type Finding struct {
+	Check     CheckID `json:"check"`
+	Kind      string  `json:"kind"`
+	Namespace string  `json:"namespace"`
+	Name      string  `json:"name"`
+	Message   string  `json:"message"`
+
+	Suggestions []Suggestion `json:"suggestions,omitempty"` // Concrete changes that resolve the finding
+}
+
+// Suggestion is a proposed value for a field of the resource (or a related one, like its PDB)
+type Suggestion struct {
+	Field     string `json:"field"`
+	Current   string `json:"current,omitempty"`
+	Suggested string `json:"suggested"`
+	Reason    string `json:"reason"`
+}
+
+// CheckResult summarizes one check within a scope (namespace or cluster)
+type CheckResult struct {
+	Check      CheckID `json:"check"`
+	Checked    int     `json:"checked"`    // Resources the check applied to
+	Violations int     `json:"violations"` // Resources failing the check
+	Score      float64 `json:"score"`      // 0-100, share of passing resources
+}
+
+// NamespaceScore is the hygiene score for a single namespace
+type NamespaceScore struct {
+	Namespace string        `json:"namespace"`
+	Score     float64       `json:"score"`
+	Grade     string        `json:"grade"`
+	Checks    []CheckResult `json:"checks"`
+}
+
+// Report is a full hygiene evaluation of the cluster
+type Report struct {
+	Context    string           `json:"context"`
+	Timestamp  time.Time        `json:"timestamp"`
+	Score      float64          `json:"score"`
+	Grade      string           `json:"grade"`
+	Checks     []CheckResult    `json:"checks"`
+	Namespaces []NamespaceScore `json:"namespaces"` // Lowest score first
+	Findings   []Finding        `json:"findings,omitempty"`
+}
+
+// Snapshot is a point-in-time score recorded for trend history
+type Snapshot struct {
+	Timestamp  time.Time          `json:"timestamp"`
+	Score      float64            `json:"score"`
+	Checks     map[CheckID]int    `json:"checks"`     // Violations per check
+	Namespaces map[string]float64 `json:"namespaces"` // Score per namespace
+}
+
+// Trend describes score movement across the history window
+type Trend struct {
+	First     float64 `json:"first"`
+	Last      float64 `json:"last"`
+	Change    float64 `json:"change"`    // Last - First
+	Direction string  `json:"direction"` // improving, declining, stable
+}
+
+// HistoryResponse is the recorded score history for a context
+type HistoryResponse struct {
+	Context   string       `json:"context"`
+	Namespace string       `json:"namespace,omitempty"`
+	Points    []TrendPoint `json:"points"`
+	Trend     *Trend       `json:"trend,omitempty"`
+}
+
+// TrendPoint is a single score in a history series
+type TrendPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Score     float64   `json:"score"`
+}
@@ -0,0 +1,66 @@
+package images
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+)
+
+// Handlers provides HTTP handlers for image inspection endpoints
+type Handlers struct{}
+
+// NewHandlers creates a new Handlers instance
+func NewHandlers() *Handlers {
+	return &Handlers{}
+}
+
+// RegisterRoutes registers image routes on the given router
+func (h *Handlers) RegisterRoutes(r chi.Router) {
+	r.Route("/images", func(r chi.Router) {
+		r.Get("/", h.handleListImages)
+		r.Get("/workloads", h.handleListWorkloads)
+	})
+}
+
+// handleListImages returns the running images with their registry metadata, oldest
+// build first. ?namespace= limits them to the images pods in that namespace run.
+func (h *Handlers) handleListImages(w http.ResponseWriter, r *http.Request) {
+	in := Get()
+	if in == nil {
+		writeError(w, http.StatusServiceUnavailable, "image inspection is not enabled (--image-inspection)")
+		return
+	}
+	images, err := in.Images(r.Context(), r.URL.Query().Get("namespace"))
+	if err != nil {
+		explorerErrors.Write(w, err)
+		return
+	}
+	writeJSON(w, map[string]any{"images": images, "vulnerabilities": in.opts.Vulnerabilities})
+}
+
+// handleListWorkloads returns per-workload image freshness and vulnerability badges
+func (h *Handlers) handleListWorkloads(w http.ResponseWriter, r *http.Request) {
+	in := Get()
+	if in == nil {
+		writeError(w, http.StatusServiceUnavailable, "image inspection is not enabled (--image-inspection)")
+		return
+	}
+	workloads, err := in.Workloads(r.Context(), r.URL.Query().Get("namespace"))
+	if err != nil {
+		explorerErrors.Write(w, err)
+		return
+	}
+	writeJSON(w, map[string]any{"workloads": workloads})
+}
+
+func writeJSON(w http.ResponseWriter, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(data)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	explorerErrors.WriteHTTP(w, status, message)
+}
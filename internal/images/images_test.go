@@ -0,0 +1,276 @@
+package images
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestParseReference(t *testing.T) {
+	for _, tc := range []struct {
+		image string
+		want  Reference
+	}{
+		{"nginx", Reference{Registry: "docker.io", Repository: "library/nginx", Tag: "latest"}},
+		{"nginx:1.27", Reference{Registry: "docker.io", Repository: "library/nginx", Tag: "1.27"}},
+		{"bitnami/redis:7.2", Reference{Registry: "docker.io", Repository: "bitnami/redis", Tag: "7.2"}},
+		{"index.docker.io/library/busybox", Reference{Registry: "docker.io", Repository: "library/busybox", Tag: "latest"}},
+		{"ghcr.io/skyhook-io/radar:v1.0.0", Reference{Registry: "ghcr.io", Repository: "skyhook-io/radar", Tag: "v1.0.0"}},
+		{"localhost:5000/app", Reference{Registry: "localhost:5000", Repository: "app", Tag: "latest"}},
+		{"registry.local:5000/team/app:2@sha256:abc", Reference{Registry: "registry.local:5000", Repository: "team/app", Tag: "2", Digest: "sha256:abc"}},
+		{"quay.io/prometheus/node-exporter@sha256:def", Reference{Registry: "quay.io", Repository: "prometheus/node-exporter", Digest: "sha256:def"}},
+	} {
+		got, err := ParseReference(tc.image)
+		if err != nil || got != tc.want {
+			t.Errorf("ParseReference(%q) = %+v, %v, want %+v", tc.image, got, err, tc.want)
+		}
+	}
+	for _, image := range []string{"", "nginx:", "Nginx", "nginx@abc"} {
+		if _, err := ParseReference(image); err == nil {
+			t.Errorf("ParseReference(%q) should fail", image)
+		}
+	}
+}
+
+func TestRunningDigest(t *testing.T) {
+	for imageID, want := range map[string]string{
+		"docker.io/library/nginx@sha256:abc": "sha256:abc",
+		"docker-pullable://nginx@sha256:def": "sha256:def",
+		"sha256:0123":                        "",
+		"":                                   "",
+	} {
+		if got := runningDigest(imageID); got != want {
+			t.Errorf("runningDigest(%q) = %q, want %q", imageID, got, want)
+		}
+	}
+}
+
+func TestParsePullSecret(t *testing.T) {
+	auth := base64.StdEncoding.EncodeToString([]byte("robot$ci:s3cr:et"))
+	secret := &corev1.Secret{
+		Type: corev1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{corev1.DockerConfigJsonKey: []byte(`{"auths": {
+			"https://index.docker.io/v1/": {"auth": "` + auth + `"},
+			"harbor.example.com": {"username": "dev", "password": "pw"}
+		}}`)},
+	}
+	creds, err := parsePullSecret(secret)
+	if err != nil {
+		t.Fatalf("parsePullSecret: %v", err)
+	}
+	if c := creds["docker.io"]; c.Username != "robot$ci" || c.Password != "s3cr:et" {
+		t.Errorf("docker.io credential = %+v", c)
+	}
+	// oras asks for Docker Hub by the host it actually talks to
+	c, _ := creds.credentialFunc()(context.Background(), "registry-1.docker.io")
+	if c.Username != "robot$ci" {
+		t.Errorf("credential for registry-1.docker.io = %+v", c)
+	}
+	if c := creds["harbor.example.com"]; c.Username != "dev" || c.Password != "pw" {
+		t.Errorf("harbor credential = %+v", c)
+	}
+
+	legacy := &corev1.Secret{
+		Type: corev1.SecretTypeDockercfg,
+		Data: map[string][]byte{corev1.DockerConfigKey: []byte(`{"quay.io": {"username": "q", "password": "p"}}`)},
+	}
+	if creds, err := parsePullSecret(legacy); err != nil || creds["quay.io"].Username != "q" {
+		t.Errorf("parsePullSecret(dockercfg) = %+v, %v", creds, err)
+	}
+	if _, err := parsePullSecret(&corev1.Secret{Type: corev1.SecretTypeOpaque}); err == nil {
+		t.Error("opaque secrets aren't pull secrets")
+	}
+}
+
+func TestFreshness(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	days := func(n int) *time.Time {
+		t := now.Add(-time.Duration(n) * 24 * time.Hour)
+		return &t
+	}
+	for _, tc := range []struct {
+		created *time.Time
+		want    string
+	}{
+		{nil, FreshnessUnknown},
+		{days(10), FreshnessFresh},
+		{days(200), FreshnessAging},
+		{days(400), FreshnessOld},
+	} {
+		if got := freshness(tc.created, now); got != tc.want {
+			t.Errorf("freshness(%v) = %s, want %s", tc.created, got, tc.want)
+		}
+	}
+}
+
+// fakeRegistry serves one multi-platform tag and records the Authorization headers it gets
+type fakeRegistry struct {
+	blobs     map[string][]byte // By digest
+	types     map[string]string
+	tags      map[string]string // Tag -> digest
+	authSeen  []string
+	scanCalls []string
+}
+
+func (f *fakeRegistry) add(mediaType string, body []byte) ocispec.Descriptor {
+	d := digest.FromBytes(body)
+	f.blobs[d.String()] = body
+	f.types[d.String()] = mediaType
+	return ocispec.Descriptor{MediaType: mediaType, Digest: d, Size: int64(len(body))}
+}
+
+func (f *fakeRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.authSeen = append(f.authSeen, r.Header.Get("Authorization"))
+	if strings.HasPrefix(r.URL.Path, "/api/v2.0/") {
+		f.scanCalls = append(f.scanCalls, r.URL.EscapedPath())
+		w.Write([]byte(`{"scan_overview": {"application/vnd.security.vulnerability.report; version=1.1": {
+			"scan_status": "Success", "end_time": "2026-02-01T00:00:00Z",
+			"summary": {"total": 6, "summary": {"Critical": 1, "High": 2, "Medium": 3}}}}}`))
+		return
+	}
+	parts := strings.Split(r.URL.Path, "/")
+	ref := parts[len(parts)-1]
+	if digest, ok := f.tags[ref]; ok {
+		ref = digest
+	}
+	body, ok := f.blobs[ref]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", f.types[ref])
+	w.Header().Set("Docker-Content-Digest", ref)
+	w.Write(body)
+}
+
+func TestRegistryResolve(t *testing.T) {
+	created := time.Date(2026, 1, 15, 8, 0, 0, 0, time.UTC)
+	reg := &fakeRegistry{blobs: map[string][]byte{}, types: map[string]string{}, tags: map[string]string{}}
+	config := reg.add(ocispec.MediaTypeImageConfig, []byte(`{"created": "`+created.Format(time.RFC3339)+`", "architecture": "amd64"}`))
+	armConfig := reg.add(ocispec.MediaTypeImageConfig, []byte(`{"created": "2020-01-01T00:00:00Z"}`))
+	manifest := func(cfg ocispec.Descriptor) []byte {
+		b, _ := json.Marshal(ocispec.Manifest{MediaType: ocispec.MediaTypeImageManifest, Config: cfg})
+		return b
+	}
+	amd := reg.add(ocispec.MediaTypeImageManifest, manifest(config))
+	amd.Platform = &ocispec.Platform{OS: "linux", Architecture: "amd64"}
+	arm := reg.add(ocispec.MediaTypeImageManifest, manifest(armConfig))
+	arm.Platform = &ocispec.Platform{OS: "linux", Architecture: "arm64"}
+	indexBody, _ := json.Marshal(ocispec.Index{MediaType: ocispec.MediaTypeImageIndex, Manifests: []ocispec.Descriptor{arm, amd}})
+	index := reg.add(ocispec.MediaTypeImageIndex, indexBody)
+	reg.tags["1.0"] = index.Digest.String()
+
+	srv := httptest.NewServer(reg)
+	defer srv.Close()
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	client := newRegistryClient()
+	client.plainHTTP = true
+	in := &Inspector{opts: Options{Vulnerabilities: VulnSourceHarbor}, registry: client}
+	creds := credentials{host: {Username: "dev", Password: "pw"}}
+
+	result := in.inspect(context.Background(), host+"/team/web/api:1.0", creds)
+	if result.err != "" {
+		t.Fatalf("inspect: %s", result.err)
+	}
+	if result.digest != index.Digest.String() {
+		t.Errorf("digest = %s, want the index digest %s", result.digest, index.Digest)
+	}
+	if result.created == nil || !result.created.Equal(created) {
+		t.Errorf("created = %v, want linux/amd64's %s", result.created, created)
+	}
+	if v := result.vulns; v == nil || v.Critical != 1 || v.High != 2 || v.Medium != 3 || v.Source != VulnSourceHarbor {
+		t.Errorf("vulnerabilities = %+v (%s)", v, result.vulnErr)
+	}
+	want := "/api/v2.0/projects/team/repositories/web%252Fapi/artifacts/" + index.Digest.String()
+	if len(reg.scanCalls) != 1 || reg.scanCalls[0] != want {
+		t.Errorf("harbor calls = %v, want %s", reg.scanCalls, want)
+	}
+	if reg.authSeen[len(reg.authSeen)-1] == "" {
+		t.Error("harbor request had no credentials")
+	}
+
+	if result := in.inspect(context.Background(), host+"/team/web/api:missing", creds); result.err == "" {
+		t.Error("inspecting a missing tag should fail")
+	}
+}
+
+func TestTrivyReports(t *testing.T) {
+	report := func(server, repo, tag, digest, updated string, critical int64) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]any{
+			"report": map[string]any{
+				"registry":        map[string]any{"server": server},
+				"artifact":        map[string]any{"repository": repo, "tag": tag, "digest": digest},
+				"summary":         map[string]any{"criticalCount": critical, "highCount": int64(4)},
+				"updateTimestamp": updated,
+			},
+		}}
+	}
+	idx := newTrivyReports([]*unstructured.Unstructured{
+		report("index.docker.io", "library/nginx", "1.27", "", "2026-02-01T00:00:00Z", 1),
+		report("index.docker.io", "library/nginx", "1.27", "", "2026-02-03T00:00:00Z", 2), // Newer scan wins
+		report("ghcr.io", "org/app", "v2", "sha256:aaa", "2026-02-01T00:00:00Z", 5),
+	})
+
+	nginx, _ := ParseReference("nginx:1.27")
+	if s := idx.lookup(nginx); s == nil || s.Critical != 2 || s.High != 4 {
+		t.Errorf("nginx report = %+v, want the newest scan", s)
+	}
+	retagged, _ := ParseReference("ghcr.io/org/app:latest")
+	if s := idx.lookup(retagged, "", "sha256:aaa"); s == nil || s.Critical != 5 {
+		t.Errorf("lookup by running digest = %+v", s)
+	}
+	other, _ := ParseReference("ghcr.io/org/app:v3")
+	if s := idx.lookup(other); s != nil {
+		t.Errorf("unrelated tag matched %+v", s)
+	}
+}
+
+func TestCollectUses(t *testing.T) {
+	pod := func(name string, secret string, digest string) *corev1.Pod {
+		p := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: name},
+			Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "migrate", Image: "ghcr.io/org/migrate:1"}},
+				Containers:     []corev1.Container{{Name: "web", Image: "nginx:1.27"}},
+			},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "web", ImageID: "docker.io/library/nginx@" + digest},
+			}},
+		}
+		if secret != "" {
+			p.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: secret}}
+		}
+		return p
+	}
+	workload := func(p *corev1.Pod) WorkloadRef {
+		return WorkloadRef{Kind: "Deployment", Namespace: p.Namespace, Name: "web"}
+	}
+	uses := collectUses([]*corev1.Pod{pod("web-1", "regcred", "sha256:old"), pod("web-2", "", "sha256:new")}, workload)
+
+	nginx := uses["nginx:1.27"]
+	if nginx == nil || len(nginx.runningDigests) != 2 || len(nginx.workloads) != 1 || !nginx.pullSecrets["shop/regcred"] {
+		t.Fatalf("nginx use = %+v", nginx)
+	}
+	if _, ok := uses["ghcr.io/org/migrate:1"]; !ok {
+		t.Error("init container images should be collected")
+	}
+
+	st := &state{uses: uses, results: map[string]*inspection{
+		"nginx:1.27": {digest: "sha256:new", at: time.Now()},
+	}}
+	if info := st.info("nginx:1.27", time.Now()); !info.Outdated || len(info.RunningDigests) != 2 {
+		t.Errorf("info = %+v, want outdated (one pod runs a digest the tag moved away from)", info)
+	}
+}
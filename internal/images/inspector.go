@@ -0,0 +1,589 @@
+// Package images enriches the images running in the cluster with registry metadata:
+// the digest each tag currently points to, when the image was built, and optionally
+// vulnerability counts from Harbor or Trivy Operator.
+package images
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+const (
+	// DefaultInterval is how often each running image is inspected again
+	DefaultInterval = 6 * time.Hour
+	// refreshTick is how often new images are picked up
+	refreshTick = time.Minute
+	// errorRetryInterval is how long a failed inspection waits before it's retried
+	errorRetryInterval = 15 * time.Minute
+	// maxInspectionsPerTick bounds registry traffic, so a large cluster warms up over several ticks
+	maxInspectionsPerTick = 100
+	inspectWorkers        = 4
+	inspectTimeout        = time.Minute
+)
+
+// Image freshness, by the age of the image's build
+const (
+	FreshnessFresh   = "fresh" // Built within the last 90 days
+	FreshnessAging   = "aging" // Within the last year
+	FreshnessOld     = "old"
+	FreshnessUnknown = "unknown" // Not inspected yet, or the image has no build time
+)
+
+// Options configures image inspection
+type Options struct {
+	// PullSecrets (namespace/name) are tried for every image, after the pods' own imagePullSecrets
+	PullSecrets     []string
+	Vulnerabilities string // VulnSourceNone, VulnSourceHarbor or VulnSourceTrivy
+	Interval        time.Duration
+}
+
+// WorkloadRef names a workload running an image
+type WorkloadRef struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// ImageInfo is what's known about one image as written in pod specs
+type ImageInfo struct {
+	Image          string     `json:"image"`
+	Reference      Reference  `json:"reference"`
+	Digest         string     `json:"digest,omitempty"` // What the tag points to in the registry now
+	Created        *time.Time `json:"created,omitempty"`
+	AgeDays        *int       `json:"ageDays,omitempty"`
+	Freshness      string     `json:"freshness"`
+	RunningDigests []string   `json:"runningDigests,omitempty"` // Reported by the pods' container statuses
+	// Outdated is set when pods run a digest the tag no longer points to (the tag was pushed again)
+	Outdated           bool                  `json:"outdated,omitempty"`
+	Vulnerabilities    *VulnerabilitySummary `json:"vulnerabilities,omitempty"`
+	Workloads          []WorkloadRef         `json:"workloads"`
+	InspectedAt        *time.Time            `json:"inspectedAt,omitempty"`
+	Error              string                `json:"error,omitempty"`
+	VulnerabilityError string                `json:"vulnerabilityError,omitempty"`
+}
+
+// ContainerImage is one container of a workload and the image it runs
+type ContainerImage struct {
+	Container     string `json:"container"`
+	Image         string `json:"image"`
+	RunningDigest string `json:"runningDigest,omitempty"`
+	Outdated      bool   `json:"outdated,omitempty"`
+}
+
+// WorkloadImages summarizes a workload's images into badges
+type WorkloadImages struct {
+	WorkloadRef
+	Containers []ContainerImage `json:"containers"`
+	Freshness  string           `json:"freshness"` // Of its oldest image
+	Outdated   bool             `json:"outdated,omitempty"`
+	// Vulnerabilities are summed over the workload's distinct images
+	Vulnerabilities *VulnerabilitySummary `json:"vulnerabilities,omitempty"`
+}
+
+// inspection is the result of inspecting one image
+type inspection struct {
+	digest  string
+	created *time.Time
+	vulns   *VulnerabilitySummary
+	err     string
+	vulnErr string
+	at      time.Time
+}
+
+// Inspector periodically inspects the images running in the cluster
+type Inspector struct {
+	opts     Options
+	registry *registryClient
+
+	mu      sync.RWMutex
+	results map[string]*inspection // By image as written in pod specs
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+var (
+	inspector   *Inspector
+	inspectorMu sync.RWMutex
+)
+
+// Init starts inspecting the cluster's images
+func Init(opts Options) error {
+	if err := ValidateVulnSource(opts.Vulnerabilities); err != nil {
+		return err
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+	in := &Inspector{
+		opts:     opts,
+		registry: newRegistryClient(),
+		results:  make(map[string]*inspection),
+		stopCh:   make(chan struct{}),
+	}
+	inspectorMu.Lock()
+	inspector = in
+	inspectorMu.Unlock()
+
+	in.wg.Add(1)
+	go in.loop()
+	log.Printf("Image inspection started (every %s)", opts.Interval)
+	return nil
+}
+
+// Get returns the inspector (nil when image inspection is off)
+func Get() *Inspector {
+	inspectorMu.RLock()
+	defer inspectorMu.RUnlock()
+	return inspector
+}
+
+// Stop stops inspecting
+func Stop() {
+	if in := Get(); in != nil {
+		close(in.stopCh)
+		in.wg.Wait()
+	}
+}
+
+// Reset drops results from the previous cluster (on context switch)
+func Reset() {
+	if in := Get(); in != nil {
+		in.mu.Lock()
+		in.results = make(map[string]*inspection)
+		in.mu.Unlock()
+	}
+}
+
+func (in *Inspector) loop() {
+	defer in.wg.Done()
+
+	in.refresh()
+
+	ticker := time.NewTicker(refreshTick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-in.stopCh:
+			return
+		case <-ticker.C:
+			in.refresh()
+		}
+	}
+}
+
+// imageUse is how the cluster uses one image
+type imageUse struct {
+	pullSecrets    map[string]bool // namespace/name
+	runningDigests map[string]bool
+	workloads      map[WorkloadRef]bool
+}
+
+// podContainers returns a pod's containers (init containers included) with the digest
+// each one runs, if the runtime reported it
+func podContainers(pod *corev1.Pod) []ContainerImage {
+	digests := make(map[string]string)
+	for _, statuses := range [][]corev1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for _, cs := range statuses {
+			digests[cs.Name] = runningDigest(cs.ImageID)
+		}
+	}
+	var result []ContainerImage
+	for _, containers := range [][]corev1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for _, c := range containers {
+			result = append(result, ContainerImage{Container: c.Name, Image: c.Image, RunningDigest: digests[c.Name]})
+		}
+	}
+	return result
+}
+
+// collectUses groups the pods' containers by image
+func collectUses(pods []*corev1.Pod, workloadOf func(*corev1.Pod) WorkloadRef) map[string]*imageUse {
+	uses := make(map[string]*imageUse)
+	for _, pod := range pods {
+		workload := workloadOf(pod)
+		for _, c := range podContainers(pod) {
+			u, ok := uses[c.Image]
+			if !ok {
+				u = &imageUse{
+					pullSecrets:    make(map[string]bool),
+					runningDigests: make(map[string]bool),
+					workloads:      make(map[WorkloadRef]bool),
+				}
+				uses[c.Image] = u
+			}
+			for _, s := range pod.Spec.ImagePullSecrets {
+				u.pullSecrets[pod.Namespace+"/"+s.Name] = true
+			}
+			if c.RunningDigest != "" {
+				u.runningDigests[c.RunningDigest] = true
+			}
+			u.workloads[workload] = true
+		}
+	}
+	return uses
+}
+
+// workloadOf resolves a pod to its workload through the cache (the pod itself if unmanaged)
+func workloadOf(cache *k8s.ResourceCache) func(*corev1.Pod) WorkloadRef {
+	return func(pod *corev1.Pod) WorkloadRef {
+		kind, name := cache.PodWorkload(pod)
+		if kind == "" {
+			kind, name = "Pod", pod.Name
+		}
+		return WorkloadRef{Kind: kind, Namespace: pod.Namespace, Name: name}
+	}
+}
+
+// refresh inspects images that are new or due, and forgets images no longer running
+func (in *Inspector) refresh() {
+	cache := k8s.GetResourceCache()
+	if cache == nil {
+		return
+	}
+	pods, err := cache.Pods().List(labels.Everything())
+	if err != nil {
+		return
+	}
+	uses := collectUses(pods, workloadOf(cache))
+	now := time.Now()
+
+	in.mu.Lock()
+	for image := range in.results {
+		if _, ok := uses[image]; !ok {
+			delete(in.results, image)
+		}
+	}
+	var due []string
+	for image := range uses {
+		r, ok := in.results[image]
+		switch {
+		case !ok:
+			due = append(due, image)
+		case r.err != "" && now.Sub(r.at) >= errorRetryInterval:
+			due = append(due, image)
+		case now.Sub(r.at) >= in.opts.Interval:
+			due = append(due, image)
+		}
+	}
+	in.mu.Unlock()
+	if len(due) == 0 {
+		return
+	}
+	sort.Strings(due)
+	if len(due) > maxInspectionsPerTick {
+		due = due[:maxInspectionsPerTick]
+	}
+
+	secrets := newSecretLoader()
+	sem := make(chan struct{}, inspectWorkers)
+	var wg sync.WaitGroup
+	for _, image := range due {
+		creds := secrets.credentials(in.opts.PullSecrets, uses[image].pullSecrets)
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			ctx, cancel := context.WithTimeout(context.Background(), inspectTimeout)
+			defer cancel()
+			result := in.inspect(ctx, image, creds)
+			in.mu.Lock()
+			in.results[image] = result
+			in.mu.Unlock()
+		}()
+	}
+	wg.Wait()
+}
+
+// inspect resolves one image in its registry and, with Harbor, reads its scan results
+func (in *Inspector) inspect(ctx context.Context, image string, creds credentials) *inspection {
+	result := &inspection{at: time.Now()}
+	ref, err := ParseReference(image)
+	if err != nil {
+		result.err = err.Error()
+		return result
+	}
+	reg, err := in.registry.resolve(ctx, ref, creds)
+	if err != nil {
+		result.err = err.Error()
+		if k8s.DebugEvents {
+			log.Printf("[DEBUG] Image inspection: %s: %v", image, err)
+		}
+	}
+	result.digest, result.created = reg.digest, reg.created
+
+	if in.opts.Vulnerabilities == VulnSourceHarbor && result.digest != "" {
+		scheme := "https"
+		if in.registry.plainHTTP {
+			scheme = "http"
+		}
+		result.vulns, err = harborVulnerabilities(ctx, in.registry.http, scheme, ref, result.digest, creds)
+		if err != nil {
+			result.vulnErr = err.Error()
+		}
+	}
+	return result
+}
+
+// secretLoader reads pull secrets once per refresh
+type secretLoader struct {
+	parsed map[string]credentials
+}
+
+func newSecretLoader() *secretLoader {
+	return &secretLoader{parsed: make(map[string]credentials)}
+}
+
+// credentials merges the logins of the pods' pull secrets, then the configured ones
+func (l *secretLoader) credentials(configured []string, fromPods map[string]bool) credentials {
+	names := make([]string, 0, len(fromPods)+len(configured))
+	for name := range fromPods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	names = append(names, configured...)
+
+	creds := make(credentials)
+	for _, name := range names {
+		c, ok := l.parsed[name]
+		if !ok {
+			c = l.load(name)
+			l.parsed[name] = c
+		}
+		creds.merge(c)
+	}
+	return creds
+}
+
+func (l *secretLoader) load(name string) credentials {
+	client := k8s.GetClient()
+	namespace, secretName, ok := strings.Cut(name, "/")
+	if client == nil || !ok {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	secret, err := client.CoreV1().Secrets(namespace).Get(ctx, secretName, metav1.GetOptions{})
+	if err != nil {
+		if k8s.DebugEvents {
+			log.Printf("[DEBUG] Image inspection: pull secret %s: %v", name, err)
+		}
+		return nil
+	}
+	creds, err := parsePullSecret(secret)
+	if err != nil {
+		log.Printf("Image inspection: skipping pull secret %s: %v", name, err)
+		return nil
+	}
+	return creds
+}
+
+// freshness classifies an image by how long ago it was built
+func freshness(created *time.Time, now time.Time) string {
+	if created == nil {
+		return FreshnessUnknown
+	}
+	switch age := now.Sub(*created); {
+	case age < 90*24*time.Hour:
+		return FreshnessFresh
+	case age < 365*24*time.Hour:
+		return FreshnessAging
+	}
+	return FreshnessOld
+}
+
+// freshnessRank orders freshness levels from best to worst
+var freshnessRank = map[string]int{FreshnessFresh: 0, FreshnessAging: 1, FreshnessOld: 2, FreshnessUnknown: -1}
+
+// state is a consistent view of the cluster's images for one query
+type state struct {
+	pods    []*corev1.Pod
+	uses    map[string]*imageUse
+	results map[string]*inspection
+	trivy   *trivyReports
+}
+
+func (in *Inspector) state(ctx context.Context, namespace string) (*state, error) {
+	cache := k8s.GetResourceCache()
+	if cache == nil {
+		return nil, explorerErrors.CacheNotInitialized()
+	}
+	var pods []*corev1.Pod
+	var err error
+	if namespace != "" {
+		pods, err = cache.Pods().Pods(namespace).List(labels.Everything())
+	} else {
+		pods, err = cache.Pods().List(labels.Everything())
+	}
+	if err != nil {
+		return nil, err
+	}
+	st := &state{pods: pods, uses: collectUses(pods, workloadOf(cache))}
+
+	in.mu.RLock()
+	st.results = make(map[string]*inspection, len(st.uses))
+	for image := range st.uses {
+		if r, ok := in.results[image]; ok {
+			st.results[image] = r
+		}
+	}
+	in.mu.RUnlock()
+
+	if in.opts.Vulnerabilities == VulnSourceTrivy {
+		// Missing CRD (Trivy Operator not installed) just means no reports
+		reports, _ := cache.ListDynamic(ctx, "VulnerabilityReport", namespace)
+		idx := newTrivyReports(reports)
+		st.trivy = &idx
+	}
+	return st, nil
+}
+
+// info builds what's known about one image
+func (st *state) info(image string, now time.Time) ImageInfo {
+	use := st.uses[image]
+	info := ImageInfo{Image: image, Freshness: FreshnessUnknown, Workloads: make([]WorkloadRef, 0, len(use.workloads))}
+	info.Reference, _ = ParseReference(image)
+	for d := range use.runningDigests {
+		info.RunningDigests = append(info.RunningDigests, d)
+	}
+	sort.Strings(info.RunningDigests)
+	for w := range use.workloads {
+		info.Workloads = append(info.Workloads, w)
+	}
+	sort.Slice(info.Workloads, func(i, j int) bool {
+		a, b := info.Workloads[i], info.Workloads[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+
+	if r, ok := st.results[image]; ok {
+		at := r.at
+		info.InspectedAt = &at
+		info.Digest, info.Created, info.Error, info.VulnerabilityError = r.digest, r.created, r.err, r.vulnErr
+		info.Vulnerabilities = r.vulns
+		info.Freshness = freshness(r.created, now)
+		if r.created != nil {
+			days := int(now.Sub(*r.created).Hours() / 24)
+			info.AgeDays = &days
+		}
+		// Images pinned by digest can't drift from their tag
+		if info.Reference.Digest == "" && r.digest != "" {
+			for _, d := range info.RunningDigests {
+				if d != r.digest {
+					info.Outdated = true
+				}
+			}
+		}
+	}
+	if st.trivy != nil {
+		info.Vulnerabilities = st.trivy.lookup(info.Reference, append([]string{info.Digest}, info.RunningDigests...)...)
+	}
+	return info
+}
+
+// Images returns the images running in namespace (empty = all), oldest build first
+func (in *Inspector) Images(ctx context.Context, namespace string) ([]ImageInfo, error) {
+	st, err := in.state(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	result := make([]ImageInfo, 0, len(st.uses))
+	for image := range st.uses {
+		result = append(result, st.info(image, now))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i].Created, result[j].Created
+		if (a == nil) != (b == nil) {
+			return a != nil
+		}
+		if a != nil && !a.Equal(*b) {
+			return a.Before(*b)
+		}
+		return result[i].Image < result[j].Image
+	})
+	return result, nil
+}
+
+// Workloads returns image badges for the workloads in namespace (empty = all)
+func (in *Inspector) Workloads(ctx context.Context, namespace string) ([]WorkloadImages, error) {
+	st, err := in.state(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	infos := make(map[string]ImageInfo, len(st.uses))
+	for image := range st.uses {
+		infos[image] = st.info(image, now)
+	}
+
+	cache := k8s.GetResourceCache()
+	resolve := workloadOf(cache)
+	byWorkload := make(map[WorkloadRef]*WorkloadImages)
+	seen := make(map[WorkloadRef]map[string]bool) // Containers already listed (one pod per workload is enough)
+	for _, pod := range st.pods {
+		ref := resolve(pod)
+		w, ok := byWorkload[ref]
+		if !ok {
+			w = &WorkloadImages{WorkloadRef: ref, Freshness: FreshnessUnknown}
+			byWorkload[ref] = w
+			seen[ref] = make(map[string]bool)
+		}
+		for _, c := range podContainers(pod) {
+			info := infos[c.Image]
+			c.Outdated = info.Reference.Digest == "" && info.Digest != "" && c.RunningDigest != "" && c.RunningDigest != info.Digest
+			w.Outdated = w.Outdated || c.Outdated
+			if seen[ref][c.Container+"\x00"+c.Image] {
+				continue
+			}
+			seen[ref][c.Container+"\x00"+c.Image] = true
+			w.Containers = append(w.Containers, c)
+		}
+	}
+
+	result := make([]WorkloadImages, 0, len(byWorkload))
+	for _, w := range byWorkload {
+		counted := make(map[string]bool)
+		for _, c := range w.Containers {
+			info := infos[c.Image]
+			if freshnessRank[info.Freshness] > freshnessRank[w.Freshness] {
+				w.Freshness = info.Freshness
+			}
+			if info.Vulnerabilities != nil && !counted[c.Image] {
+				counted[c.Image] = true
+				if w.Vulnerabilities == nil {
+					w.Vulnerabilities = &VulnerabilitySummary{Source: info.Vulnerabilities.Source}
+				}
+				w.Vulnerabilities.add(info.Vulnerabilities)
+			}
+		}
+		result = append(result, *w)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+	return result, nil
+}
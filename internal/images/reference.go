@@ -0,0 +1,99 @@
+package images
+
+import (
+	"fmt"
+	"strings"
+)
+
+// dockerHub is the registry of images without a registry host, as Docker resolves them
+const dockerHub = "docker.io"
+
+// Reference is a parsed image reference
+type Reference struct {
+	Registry   string `json:"registry"`
+	Repository string `json:"repository"`
+	Tag        string `json:"tag,omitempty"`
+	Digest     string `json:"digest,omitempty"` // Set when the image is pinned by digest
+}
+
+// ParseReference parses an image as written in a pod spec, applying Docker's defaults:
+// no registry means Docker Hub, official images live under library/, and an image with
+// neither tag nor digest is :latest.
+func ParseReference(image string) (Reference, error) {
+	var ref Reference
+	name := strings.TrimSpace(image)
+	if name == "" {
+		return ref, fmt.Errorf("empty image reference")
+	}
+	if i := strings.Index(name, "@"); i >= 0 {
+		ref.Digest = name[i+1:]
+		name = name[:i]
+		if !strings.Contains(ref.Digest, ":") {
+			return ref, fmt.Errorf("invalid digest in %q", image)
+		}
+	}
+	// A colon after the last slash starts the tag; before it, it's a registry port
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		ref.Tag = name[i+1:]
+		name = name[:i]
+		if ref.Tag == "" {
+			return ref, fmt.Errorf("empty tag in %q", image)
+		}
+	}
+	if name == "" {
+		return ref, fmt.Errorf("invalid image reference %q", image)
+	}
+
+	first, rest, found := strings.Cut(name, "/")
+	if found && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		ref.Registry, ref.Repository = first, rest
+	} else {
+		ref.Registry, ref.Repository = dockerHub, name
+	}
+	ref.Registry = normalizeRegistry(ref.Registry)
+	if ref.Registry == dockerHub && !strings.Contains(ref.Repository, "/") {
+		ref.Repository = "library/" + ref.Repository
+	}
+	if ref.Repository == "" || ref.Repository != strings.ToLower(ref.Repository) {
+		return ref, fmt.Errorf("invalid repository in %q", image)
+	}
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = "latest"
+	}
+	return ref, nil
+}
+
+// Name is the registry and repository, e.g. docker.io/library/nginx
+func (r Reference) Name() string {
+	return r.Registry + "/" + r.Repository
+}
+
+// reference is what to resolve in the registry: the digest when pinned, otherwise the tag
+func (r Reference) reference() string {
+	if r.Digest != "" {
+		return r.Digest
+	}
+	return r.Tag
+}
+
+// normalizeRegistry maps Docker Hub's aliases (as found in pull secrets and scan
+// reports) to docker.io
+func normalizeRegistry(host string) string {
+	host = strings.TrimPrefix(strings.TrimPrefix(host, "https://"), "http://")
+	host, _, _ = strings.Cut(host, "/")
+	switch host {
+	case "index.docker.io", "registry-1.docker.io", "registry.hub.docker.com":
+		return dockerHub
+	}
+	return host
+}
+
+// runningDigest returns the repository digest from a container status imageID
+// (e.g. docker.io/library/nginx@sha256:..., or docker-pullable://nginx@sha256:...). Runtimes
+// that only report the local image ID give no digest.
+func runningDigest(imageID string) string {
+	if _, digest, ok := strings.Cut(imageID, "@"); ok {
+		return digest
+	}
+	return ""
+}
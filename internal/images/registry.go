@@ -0,0 +1,214 @@
+package images
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	corev1 "k8s.io/api/core/v1"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+// maxManifestSize caps manifest and image config reads
+const maxManifestSize = 4 << 20
+
+// dockerManifestList is Docker's multi-platform manifest type (OCI types come from image-spec)
+const dockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+
+// credentials are registry logins by registry host
+type credentials map[string]auth.Credential
+
+// dockerConfig is the format of .dockerconfigjson (and, without the auths wrapper, .dockercfg)
+type dockerConfig struct {
+	Auths map[string]dockerConfigEntry `json:"auths"`
+}
+
+type dockerConfigEntry struct {
+	Username      string `json:"username"`
+	Password      string `json:"password"`
+	Auth          string `json:"auth"` // base64 of username:password
+	IdentityToken string `json:"identitytoken"`
+}
+
+// parsePullSecret reads the registry logins in a kubernetes.io/dockerconfigjson or
+// kubernetes.io/dockercfg Secret
+func parsePullSecret(secret *corev1.Secret) (credentials, error) {
+	var entries map[string]dockerConfigEntry
+	switch secret.Type {
+	case corev1.SecretTypeDockerConfigJson:
+		var cfg dockerConfig
+		if err := json.Unmarshal(secret.Data[corev1.DockerConfigJsonKey], &cfg); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", corev1.DockerConfigJsonKey, err)
+		}
+		entries = cfg.Auths
+	case corev1.SecretTypeDockercfg:
+		if err := json.Unmarshal(secret.Data[corev1.DockerConfigKey], &entries); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", corev1.DockerConfigKey, err)
+		}
+	default:
+		return nil, fmt.Errorf("secret type %s is not a pull secret", secret.Type)
+	}
+
+	creds := make(credentials, len(entries))
+	for host, e := range entries {
+		cred := auth.Credential{Username: e.Username, Password: e.Password, RefreshToken: e.IdentityToken}
+		if e.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(e.Auth)
+			if err != nil {
+				return nil, fmt.Errorf("invalid auth for %s: %w", host, err)
+			}
+			cred.Username, cred.Password, _ = strings.Cut(string(decoded), ":")
+		}
+		creds[normalizeRegistry(host)] = cred
+	}
+	return creds, nil
+}
+
+// merge adds logins from other for registries that don't have one yet
+func (c credentials) merge(other credentials) {
+	for host, cred := range other {
+		if _, ok := c[host]; !ok {
+			c[host] = cred
+		}
+	}
+}
+
+// credentialFunc resolves logins for oras, which asks by the host it talks to
+// (registry-1.docker.io for Docker Hub)
+func (c credentials) credentialFunc() auth.CredentialFunc {
+	return func(_ context.Context, hostport string) (auth.Credential, error) {
+		if cred, ok := c[normalizeRegistry(hostport)]; ok {
+			return cred, nil
+		}
+		return auth.EmptyCredential, nil
+	}
+}
+
+// registryImage is what the registry says about a tag
+type registryImage struct {
+	digest  string // Of the manifest or index the tag points to
+	created *time.Time
+}
+
+// registryClient reads manifests and image configs from registries
+type registryClient struct {
+	http      *http.Client
+	cache     auth.Cache // Bearer tokens across requests
+	plainHTTP bool       // Tests only
+}
+
+func newRegistryClient() *registryClient {
+	return &registryClient{
+		http:  &http.Client{Timeout: 30 * time.Second},
+		cache: auth.NewCache(),
+	}
+}
+
+// resolve looks up the digest a reference points to and its image's creation time. For
+// multi-platform images the creation time is linux/amd64's, or the first platform's.
+func (c *registryClient) resolve(ctx context.Context, ref Reference, creds credentials) (registryImage, error) {
+	repo, err := remote.NewRepository(ref.Name())
+	if err != nil {
+		return registryImage{}, err
+	}
+	repo.PlainHTTP = c.plainHTTP
+	repo.Client = &auth.Client{Client: c.http, Cache: c.cache, Credential: creds.credentialFunc()}
+
+	desc, rc, err := repo.FetchReference(ctx, ref.reference())
+	if err != nil {
+		return registryImage{}, fmt.Errorf("failed to fetch manifest: %w", err)
+	}
+	body, err := readLimited(rc)
+	if err != nil {
+		return registryImage{}, err
+	}
+	result := registryImage{digest: desc.Digest.String()}
+
+	if desc.MediaType == ocispec.MediaTypeImageIndex || desc.MediaType == dockerManifestList {
+		var index ocispec.Index
+		if err := json.Unmarshal(body, &index); err != nil {
+			return result, fmt.Errorf("invalid image index: %w", err)
+		}
+		platform, ok := platformManifest(index)
+		if !ok {
+			return result, nil
+		}
+		rc, err := repo.Fetch(ctx, platform)
+		if err != nil {
+			return result, fmt.Errorf("failed to fetch platform manifest: %w", err)
+		}
+		if body, err = readLimited(rc); err != nil {
+			return result, err
+		}
+	}
+
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return result, fmt.Errorf("invalid image manifest: %w", err)
+	}
+	if manifest.Config.Digest == "" {
+		return result, nil // Not an image (e.g. a Helm chart or other artifact)
+	}
+	rc, err = repo.Fetch(ctx, manifest.Config)
+	if err != nil {
+		return result, fmt.Errorf("failed to fetch image config: %w", err)
+	}
+	body, err = readLimited(rc)
+	if err != nil {
+		return result, err
+	}
+	var config struct {
+		Created *time.Time `json:"created"`
+	}
+	if err := json.Unmarshal(body, &config); err != nil {
+		return result, fmt.Errorf("invalid image config: %w", err)
+	}
+	// Reproducible builds set the epoch; that's not a real creation time
+	if config.Created != nil && config.Created.Unix() > 0 {
+		result.created = config.Created
+	}
+	return result, nil
+}
+
+// platformManifest picks linux/amd64 from an index, or else the first real platform
+// (skipping attestation manifests, whose platform is unknown/unknown)
+func platformManifest(index ocispec.Index) (ocispec.Descriptor, bool) {
+	var first *ocispec.Descriptor
+	for i, m := range index.Manifests {
+		if m.Platform == nil {
+			if first == nil {
+				first = &index.Manifests[i]
+			}
+			continue
+		}
+		if m.Platform.OS == "linux" && m.Platform.Architecture == "amd64" {
+			return m, true
+		}
+		if first == nil && m.Platform.OS != "unknown" {
+			first = &index.Manifests[i]
+		}
+	}
+	if first == nil {
+		return ocispec.Descriptor{}, false
+	}
+	return *first, true
+}
+
+func readLimited(rc io.ReadCloser) ([]byte, error) {
+	defer rc.Close()
+	body, err := io.ReadAll(io.LimitReader(rc, maxManifestSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxManifestSize {
+		return nil, fmt.Errorf("manifest larger than %d bytes", maxManifestSize)
+	}
+	return body, nil
+}
@@ -0,0 +1,192 @@
+package images
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Vulnerability sources (set via --image-vulnerabilities)
+const (
+	VulnSourceNone   = ""
+	VulnSourceHarbor = "harbor" // The registry's own scan results (Harbor's scan overview API)
+	VulnSourceTrivy  = "trivy"  // VulnerabilityReports written to the cluster by Trivy Operator
+)
+
+// ValidateVulnSource returns an error for unknown vulnerability sources
+func ValidateVulnSource(source string) error {
+	switch source {
+	case VulnSourceNone, VulnSourceHarbor, VulnSourceTrivy:
+		return nil
+	}
+	return fmt.Errorf("invalid vulnerability source %q (expected harbor or trivy)", source)
+}
+
+// VulnerabilitySummary counts an image's known vulnerabilities by severity
+type VulnerabilitySummary struct {
+	Critical  int        `json:"critical"`
+	High      int        `json:"high"`
+	Medium    int        `json:"medium"`
+	Low       int        `json:"low"`
+	Unknown   int        `json:"unknown"`
+	Source    string     `json:"source"`
+	ScannedAt *time.Time `json:"scannedAt,omitempty"`
+}
+
+// add sums another image's counts into s (for workload badges)
+func (s *VulnerabilitySummary) add(other *VulnerabilitySummary) {
+	s.Critical += other.Critical
+	s.High += other.High
+	s.Medium += other.Medium
+	s.Low += other.Low
+	s.Unknown += other.Unknown
+}
+
+// harborScanMimeTypes are the report formats Radar reads from Harbor's scan overview
+const harborScanMimeTypes = "application/vnd.security.vulnerability.report; version=1.1, application/vnd.scanner.adapter.vuln.report.harbor+json; version=1.0"
+
+// harborArtifact is the subset of Harbor's artifact response we need
+type harborArtifact struct {
+	ScanOverview map[string]struct {
+		ScanStatus string     `json:"scan_status"`
+		EndTime    *time.Time `json:"end_time"`
+		Summary    *struct {
+			Summary map[string]int `json:"summary"` // By severity: Critical, High, Medium, Low, Unknown, None
+		} `json:"summary"`
+	} `json:"scan_overview"`
+}
+
+// harborVulnerabilities reads the scan summary of an artifact from Harbor, which serves
+// its API on the registry host. Images that haven't been scanned return nil.
+func harborVulnerabilities(ctx context.Context, client *http.Client, scheme string, ref Reference, digest string, creds credentials) (*VulnerabilitySummary, error) {
+	project, repo, ok := strings.Cut(ref.Repository, "/")
+	if !ok {
+		return nil, fmt.Errorf("%s isn't in a Harbor project", ref.Name())
+	}
+	// Harbor wants slashes in repository names encoded twice
+	u := fmt.Sprintf("%s://%s/api/v2.0/projects/%s/repositories/%s/artifacts/%s?with_scan_overview=true",
+		scheme, ref.Registry, url.PathEscape(project), url.PathEscape(url.PathEscape(repo)), digest)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Accept-Vulnerabilities", harborScanMimeTypes)
+	if cred, ok := creds[ref.Registry]; ok && cred.Username != "" {
+		req.SetBasicAuth(cred.Username, cred.Password)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("harbor returned %s", resp.Status)
+	}
+	var artifact harborArtifact
+	if err := json.NewDecoder(resp.Body).Decode(&artifact); err != nil {
+		return nil, fmt.Errorf("invalid harbor response: %w", err)
+	}
+	for _, report := range artifact.ScanOverview {
+		if report.ScanStatus != "Success" || report.Summary == nil {
+			continue
+		}
+		counts := report.Summary.Summary
+		return &VulnerabilitySummary{
+			Critical:  counts["Critical"],
+			High:      counts["High"],
+			Medium:    counts["Medium"],
+			Low:       counts["Low"],
+			Unknown:   counts["Unknown"],
+			Source:    VulnSourceHarbor,
+			ScannedAt: report.EndTime,
+		}, nil
+	}
+	return nil, nil
+}
+
+// trivyReports indexes Trivy Operator VulnerabilityReports by image digest and by
+// name:tag, so images match whether or not the report recorded a digest
+type trivyReports struct {
+	byDigest map[string]*VulnerabilitySummary
+	byTag    map[string]*VulnerabilitySummary
+}
+
+func newTrivyReports(reports []*unstructured.Unstructured) trivyReports {
+	idx := trivyReports{
+		byDigest: make(map[string]*VulnerabilitySummary),
+		byTag:    make(map[string]*VulnerabilitySummary),
+	}
+	for _, r := range reports {
+		report, ok := r.Object["report"].(map[string]any)
+		if !ok {
+			continue
+		}
+		registry, _, _ := unstructured.NestedString(report, "registry", "server")
+		repository, _, _ := unstructured.NestedString(report, "artifact", "repository")
+		tag, _, _ := unstructured.NestedString(report, "artifact", "tag")
+		digest, _, _ := unstructured.NestedString(report, "artifact", "digest")
+		if repository == "" {
+			continue
+		}
+
+		count := func(field string) int {
+			n, _, _ := unstructured.NestedInt64(report, "summary", field)
+			return int(n)
+		}
+		summary := &VulnerabilitySummary{
+			Critical: count("criticalCount"),
+			High:     count("highCount"),
+			Medium:   count("mediumCount"),
+			Low:      count("lowCount"),
+			Unknown:  count("unknownCount"),
+			Source:   VulnSourceTrivy,
+		}
+		if ts, _, _ := unstructured.NestedString(report, "updateTimestamp"); ts != "" {
+			if t, err := time.Parse(time.RFC3339, ts); err == nil {
+				summary.ScannedAt = &t
+			}
+		}
+
+		// Reports are per workload container, so the same image has several; keep the newest
+		keep := func(m map[string]*VulnerabilitySummary, key string) {
+			if prev, ok := m[key]; ok && prev.ScannedAt != nil && (summary.ScannedAt == nil || prev.ScannedAt.After(*summary.ScannedAt)) {
+				return
+			}
+			m[key] = summary
+		}
+		if digest != "" {
+			keep(idx.byDigest, digest)
+		}
+		if tag != "" {
+			ref, err := ParseReference(registry + "/" + repository + ":" + tag)
+			if registry == "" {
+				ref, err = ParseReference(repository + ":" + tag)
+			}
+			if err == nil {
+				keep(idx.byTag, ref.Name()+":"+ref.Tag)
+			}
+		}
+	}
+	return idx
+}
+
+// lookup finds the report for an image by any of its digests, then by tag
+func (idx trivyReports) lookup(ref Reference, digests ...string) *VulnerabilitySummary {
+	for _, d := range digests {
+		if s, ok := idx.byDigest[d]; ok && d != "" {
+			return s
+		}
+	}
+	if ref.Tag != "" {
+		return idx.byTag[ref.Name()+":"+ref.Tag]
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+// Package jsonpatch computes RFC 6902 JSON Patches between decoded JSON documents
+// (map[string]any, []any and scalars, as produced by encoding/json).
+package jsonpatch
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Operation is one RFC 6902 operation. Only add, remove and replace are generated.
+type Operation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value any    `json:"value"`
+}
+
+// MarshalJSON always includes the value of add and replace, even when it is null
+func (o Operation) MarshalJSON() ([]byte, error) {
+	if o.Op == "remove" {
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{o.Op, o.Path})
+	}
+	return json.Marshal(struct {
+		Op    string `json:"op"`
+		Path  string `json:"path"`
+		Value any    `json:"value"`
+	}{o.Op, o.Path, o.Value})
+}
+
+// Diff returns the operations that turn old into new. Objects are diffed key by key;
+// arrays of equal length element by element, otherwise they are replaced whole.
+func Diff(old, new any) []Operation {
+	return diff(nil, "", old, new)
+}
+
+// DiffList diffs two arrays whose elements are sorted by key, producing element-level
+// add and remove operations instead of replacing the array when items come and go.
+// Elements with the same key are diffed in place.
+func DiffList(old, new []any, key func(any) string) []Operation {
+	var ops []Operation
+	i, j, idx := 0, 0, 0
+	for i < len(old) || j < len(new) {
+		switch {
+		case j >= len(new) || (i < len(old) && key(old[i]) < key(new[j])):
+			ops = append(ops, Operation{Op: "remove", Path: "/" + strconv.Itoa(idx)})
+			i++
+		case i >= len(old) || key(new[j]) < key(old[i]):
+			ops = append(ops, Operation{Op: "add", Path: "/" + strconv.Itoa(idx), Value: new[j]})
+			j++
+			idx++
+		default:
+			ops = diff(ops, "/"+strconv.Itoa(idx), old[i], new[j])
+			i++
+			j++
+			idx++
+		}
+	}
+	return ops
+}
+
+func diff(ops []Operation, path string, old, new any) []Operation {
+	switch o := old.(type) {
+	case map[string]any:
+		n, ok := new.(map[string]any)
+		if !ok {
+			break
+		}
+		for _, k := range sortedKeys(o) {
+			if _, ok := n[k]; !ok {
+				ops = append(ops, Operation{Op: "remove", Path: path + "/" + escape(k)})
+			}
+		}
+		for _, k := range sortedKeys(n) {
+			ov, ok := o[k]
+			if !ok {
+				ops = append(ops, Operation{Op: "add", Path: path + "/" + escape(k), Value: n[k]})
+				continue
+			}
+			ops = diff(ops, path+"/"+escape(k), ov, n[k])
+		}
+		return ops
+	case []any:
+		n, ok := new.([]any)
+		if !ok || len(n) != len(o) {
+			break
+		}
+		for i := range o {
+			ops = diff(ops, path+"/"+strconv.Itoa(i), o[i], n[i])
+		}
+		return ops
+	}
+	if reflect.DeepEqual(old, new) {
+		return ops
+	}
+	return append(ops, Operation{Op: "replace", Path: path, Value: new})
+}
+
+// escape encodes a key as a JSON Pointer reference token
+func escape(key string) string {
+	if !strings.ContainsAny(key, "~/") {
+		return key
+	}
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,75 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	evanphx "github.com/evanphx/json-patch"
+)
+
+func decode(t *testing.T, s string) any {
+	t.Helper()
+	var v any
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatalf("decode %s: %v", s, err)
+	}
+	return v
+}
+
+// apply checks ops turn old into new using an independent RFC 6902 implementation
+func apply(t *testing.T, old any, ops []Operation, want any) {
+	t.Helper()
+	patchJSON, err := json.Marshal(ops)
+	if err != nil {
+		t.Fatalf("marshal ops: %v", err)
+	}
+	patch, err := evanphx.DecodePatch(patchJSON)
+	if err != nil {
+		t.Fatalf("decode patch %s: %v", patchJSON, err)
+	}
+	oldJSON, _ := json.Marshal(old)
+	gotJSON, err := patch.Apply(oldJSON)
+	if err != nil {
+		t.Fatalf("apply %s: %v", patchJSON, err)
+	}
+	var got any
+	json.Unmarshal(gotJSON, &got)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("patched = %s, want %v (patch %s)", gotJSON, want, patchJSON)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	old := decode(t, `{"metadata":{"name":"a","labels":{"app/name":"x","tier":"web"}},"spec":{"replicas":1,"ports":[80,443]},"status":{"ready":true}}`)
+	new := decode(t, `{"metadata":{"name":"a","labels":{"app/name":"y"}},"spec":{"replicas":2,"ports":[80]},"status":{"ready":null,"phase":"Running"}}`)
+
+	ops := Diff(old, new)
+	apply(t, old, ops, new)
+
+	for _, op := range ops {
+		if op.Path == "/metadata/labels/app~1name" {
+			return
+		}
+	}
+	t.Errorf("expected escaped path for app/name label, got %+v", ops)
+}
+
+func TestDiffList(t *testing.T) {
+	key := func(v any) string { return v.(map[string]any)["name"].(string) }
+	old := decode(t, `[{"name":"a","v":1},{"name":"b","v":1},{"name":"d","v":1}]`).([]any)
+	new := decode(t, `[{"name":"b","v":2},{"name":"c","v":1},{"name":"d","v":1},{"name":"e","v":1}]`).([]any)
+
+	ops := DiffList(old, new, key)
+	apply(t, old, ops, new)
+
+	// Unchanged items produce nothing; the list is never replaced whole
+	if len(ops) != 4 {
+		t.Errorf("got %d ops, want 4 (remove a, replace b.v, add c, add e): %+v", len(ops), ops)
+	}
+	if ops := DiffList(new, new, key); len(ops) != 0 {
+		t.Errorf("identical lists: got %+v", ops)
+	}
+	apply(t, []any{}, DiffList(nil, new, key), new)
+	apply(t, old, DiffList(old, []any{}, key), []any{})
+}
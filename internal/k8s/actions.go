@@ -0,0 +1,285 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+)
+
+// Propagation policies for DeleteResource
+const (
+	PropagationBackground = "background" // Delete the object now, dependents afterwards (default)
+	PropagationForeground = "foreground" // Delete dependents first, then the object
+	PropagationOrphan     = "orphan"     // Delete only the object, leaving dependents running
+)
+
+// DeleteOptions control how DeleteResource removes an object
+type DeleteOptions struct {
+	Propagation        string // See the Propagation constants (empty = background)
+	GracePeriodSeconds *int64 // Overrides the object's termination grace period (0 = immediately)
+}
+
+// meta converts the options to API delete options
+func (o DeleteOptions) meta() (metav1.DeleteOptions, error) {
+	opts := metav1.DeleteOptions{GracePeriodSeconds: o.GracePeriodSeconds}
+	var policy metav1.DeletionPropagation
+	switch strings.ToLower(o.Propagation) {
+	case "", PropagationBackground:
+		policy = metav1.DeletePropagationBackground
+	case PropagationForeground:
+		policy = metav1.DeletePropagationForeground
+	case PropagationOrphan:
+		policy = metav1.DeletePropagationOrphan
+	default:
+		return opts, explorerErrors.ValidationError(fmt.Sprintf(
+			"invalid propagation %q (use background, foreground or orphan)", o.Propagation))
+	}
+	if o.GracePeriodSeconds != nil && *o.GracePeriodSeconds < 0 {
+		return opts, explorerErrors.ValidationError("gracePeriodSeconds must not be negative")
+	}
+	opts.PropagationPolicy = &policy
+	return opts, nil
+}
+
+// CachedObject returns a resource from the informer caches, so actions can be validated
+// without a round trip to the API server. Kinds with a typed informer are read from it,
+// everything else from the dynamic cache.
+func (c *ResourceCache) CachedObject(ctx context.Context, kind, namespace, name string) (metav1.Object, error) {
+	if c == nil {
+		return nil, explorerErrors.CacheNotInitialized()
+	}
+	discovery := GetResourceDiscovery()
+	if discovery == nil {
+		return nil, fmt.Errorf("resource discovery not initialized")
+	}
+	res, ok := discovery.GetResource(kind)
+	if !ok {
+		return nil, explorerErrors.ValidationError(fmt.Sprintf("unknown resource kind: %s", kind))
+	}
+
+	var obj any
+	if IsKnownKind(kind) && c.HasTypedInformer(kind) {
+		key := name
+		if namespace != "" {
+			key = namespace + "/" + name
+		}
+		item, exists, err := c.indexer(res.Name).GetByKey(key)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, explorerErrors.K8sResourceNotFound(res.Kind, namespace, name)
+		}
+		obj = item
+	} else {
+		u, err := c.GetDynamic(ctx, kind, namespace, name)
+		if err != nil {
+			return nil, explorerErrors.K8sResourceNotFound(res.Kind, namespace, name)
+		}
+		obj = u
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, explorerErrors.InternalError("unexpected object in cache", err)
+	}
+	return accessor, nil
+}
+
+// Dependent is an object owned, directly or through other dependents, by a resource
+type Dependent struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+// DeletePreview describes what deleting a resource would do, for confirmation dialogs
+type DeletePreview struct {
+	Kind        string `json:"kind"`
+	Namespace   string `json:"namespace,omitempty"`
+	Name        string `json:"name"`
+	Propagation string `json:"propagation"`
+	// Dependents found in the cache: deleted along with the resource, or left running
+	// without an owner when propagation is orphan
+	Dependents []Dependent `json:"dependents"`
+	Orphaned   bool        `json:"orphaned,omitempty"`
+}
+
+// dependentResources are the cached kinds searched for dependents (the ones workload
+// controllers create)
+var dependentResources = []struct{ kind, resource string }{
+	{"ReplicaSet", "replicasets"},
+	{"Job", "jobs"},
+	{"Pod", "pods"},
+}
+
+// PreviewDelete validates a delete against the cache and lists the dependents it affects
+func PreviewDelete(ctx context.Context, kind, namespace, name string, opts DeleteOptions) (*DeletePreview, error) {
+	if _, err := opts.meta(); err != nil {
+		return nil, err
+	}
+	cache := GetResourceCache()
+	obj, err := cache.CachedObject(ctx, kind, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	propagation := strings.ToLower(opts.Propagation)
+	if propagation == "" {
+		propagation = PropagationBackground
+	}
+	preview := &DeletePreview{
+		Kind:        kindName(kind),
+		Namespace:   namespace,
+		Name:        name,
+		Propagation: propagation,
+		Dependents:  cache.dependents(obj.GetUID(), namespace),
+	}
+	preview.Orphaned = propagation == PropagationOrphan && len(preview.Dependents) > 0
+	return preview, nil
+}
+
+// dependents walks owner references down from an object, breadth first
+func (c *ResourceCache) dependents(root types.UID, namespace string) []Dependent {
+	type owned struct {
+		Dependent
+		uid types.UID
+	}
+	children := make(map[types.UID][]owned)
+	for _, dr := range dependentResources {
+		if !c.HasTypedInformer(dr.resource) {
+			continue
+		}
+		items := c.indexer(dr.resource).List()
+		for _, item := range items {
+			obj, err := meta.Accessor(item)
+			if err != nil || (namespace != "" && obj.GetNamespace() != namespace) {
+				continue
+			}
+			for _, ref := range obj.GetOwnerReferences() {
+				children[ref.UID] = append(children[ref.UID], owned{
+					Dependent: Dependent{Kind: dr.kind, Namespace: obj.GetNamespace(), Name: obj.GetName()},
+					uid:       obj.GetUID(),
+				})
+			}
+		}
+	}
+
+	deps := []Dependent{}
+	seen := map[types.UID]bool{root: true}
+	queue := []types.UID{root}
+	for len(queue) > 0 {
+		uid := queue[0]
+		queue = queue[1:]
+		level := children[uid]
+		sort.Slice(level, func(i, j int) bool { return level[i].Name < level[j].Name })
+		for _, child := range level {
+			if seen[child.uid] {
+				continue
+			}
+			seen[child.uid] = true
+			deps = append(deps, child.Dependent)
+			queue = append(queue, child.uid)
+		}
+	}
+	return deps
+}
+
+// ScaleWorkload sets the replica count of a Deployment, StatefulSet, ReplicaSet or Rollout
+// through its scale subresource and returns the previous count from the cache
+func ScaleWorkload(ctx context.Context, kind, namespace, name string, replicas int32) (int32, error) {
+	if replicas < 0 {
+		return 0, explorerErrors.ValidationError("replicas must not be negative")
+	}
+	switch strings.ToLower(kind) {
+	case "deployments", "deployment", "statefulsets", "statefulset", "replicasets", "replicaset", "rollouts", "rollout":
+	default:
+		return 0, explorerErrors.ValidationError("only Deployments, StatefulSets, ReplicaSets, and Rollouts can be scaled")
+	}
+	dynamicClient, err := DynamicClientFor(ctx)
+	if err != nil {
+		return 0, err
+	}
+	discovery := GetResourceDiscovery()
+	if discovery == nil {
+		return 0, fmt.Errorf("resource discovery not initialized")
+	}
+	gvr, ok := discovery.GetGVR(kind)
+	if !ok {
+		return 0, explorerErrors.ValidationError(fmt.Sprintf("unknown resource kind: %s", kind))
+	}
+
+	obj, err := GetResourceCache().CachedObject(ctx, kind, namespace, name)
+	if err != nil {
+		return 0, err
+	}
+	previous := specReplicas(obj)
+
+	patch := fmt.Sprintf(`{"spec":{"replicas":%d}}`, replicas)
+	_, err = dynamicClient.Resource(gvr).Namespace(namespace).Patch(
+		ctx,
+		name,
+		types.MergePatchType,
+		[]byte(patch),
+		metav1.PatchOptions{},
+		"scale",
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to scale workload: %w", err)
+	}
+	return previous, nil
+}
+
+// SetNodeUnschedulable cordons (true) or uncordons (false) a node
+func SetNodeUnschedulable(ctx context.Context, name string, unschedulable bool) error {
+	client, err := ClientFor(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := GetResourceCache().CachedObject(ctx, "nodes", "", name); err != nil {
+		return err
+	}
+	patch := fmt.Sprintf(`{"spec":{"unschedulable":%t}}`, unschedulable)
+	if _, err := client.CoreV1().Nodes().Patch(ctx, name, types.StrategicMergePatchType, []byte(patch), metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to update node %s: %w", name, err)
+	}
+	return nil
+}
+
+// specReplicas reads spec.replicas from a cached workload (1 when unset, as the API defaults)
+func specReplicas(obj metav1.Object) int32 {
+	var replicas *int32
+	switch w := obj.(type) {
+	case *appsv1.Deployment:
+		replicas = w.Spec.Replicas
+	case *appsv1.StatefulSet:
+		replicas = w.Spec.Replicas
+	case *appsv1.ReplicaSet:
+		replicas = w.Spec.Replicas
+	case *unstructured.Unstructured:
+		if n, found, _ := unstructured.NestedInt64(w.Object, "spec", "replicas"); found {
+			return int32(n)
+		}
+	}
+	if replicas != nil {
+		return *replicas
+	}
+	return 1
+}
+
+// kindName resolves a URL kind ("deployments") to its Kind name
+func kindName(kind string) string {
+	if discovery := GetResourceDiscovery(); discovery != nil {
+		if res, ok := discovery.GetResource(kind); ok {
+			return res.Kind
+		}
+	}
+	return kind
+}
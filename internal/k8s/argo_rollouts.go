@@ -0,0 +1,315 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+)
+
+// Rollout actions, named after the kubectl-argo-rollouts commands they mirror
+const (
+	RolloutActionPromote     = "promote"      // Leave the current pause, or skip the current canary step
+	RolloutActionPromoteFull = "promote-full" // Skip the remaining steps and analysis
+	RolloutActionAbort       = "abort"        // Scale the new version down and go back to the stable one
+	RolloutActionRetry       = "retry"        // Start an aborted update again
+	RolloutActionPause       = "pause"        // Pause the update until promoted
+)
+
+// inconclusivePauseReason is the pause condition the controller sets when an analysis run
+// ends inconclusive
+const inconclusivePauseReason = "InconclusiveAnalysisRun"
+
+// ArgoRolloutStatus is an Argo Rollout's update progress: its canary steps and the
+// analysis runs it started
+type ArgoRolloutStatus struct {
+	Strategy         string            `json:"strategy"` // canary or blueGreen
+	Phase            string            `json:"phase,omitempty"`
+	Message          string            `json:"message,omitempty"`
+	Paused           bool              `json:"paused"`                     // spec.paused, set by a user
+	PauseReasons     []string          `json:"pauseReasons,omitempty"`     // Pause conditions set by the controller
+	Aborted          bool              `json:"aborted"`                    // The update was aborted; retry starts it again
+	PromoteFull      bool              `json:"promoteFull"`                // A full promotion is in progress
+	CurrentStepIndex *int64            `json:"currentStepIndex,omitempty"` // Canary only; equals len(steps) when all are done
+	Steps            []ArgoRolloutStep `json:"steps,omitempty"`
+	AnalysisRuns     []ArgoAnalysisRun `json:"analysisRuns"`
+}
+
+// ArgoRolloutStep is one canary step
+type ArgoRolloutStep struct {
+	Index  int    `json:"index"`
+	Type   string `json:"type"`            // setWeight, pause, analysis, experiment, setCanaryScale...
+	Value  any    `json:"value,omitempty"` // The step's spec, e.g. 20 for setWeight or {"duration":"1h"} for pause
+	Status string `json:"status"`          // completed, current or pending
+}
+
+// ArgoAnalysisRun summarizes an AnalysisRun started by a Rollout
+type ArgoAnalysisRun struct {
+	Name      string               `json:"name"`
+	Type      string               `json:"type,omitempty"` // Step, Background, PrePromotion or PostPromotion
+	Revision  string               `json:"revision,omitempty"`
+	Phase     string               `json:"phase,omitempty"` // Pending, Running, Successful, Failed, Error or Inconclusive
+	Message   string               `json:"message,omitempty"`
+	CreatedAt time.Time            `json:"createdAt"`
+	Metrics   []ArgoAnalysisMetric `json:"metrics,omitempty"`
+}
+
+// ArgoAnalysisMetric is one metric's measurement counts in an AnalysisRun
+type ArgoAnalysisMetric struct {
+	Name         string `json:"name"`
+	Phase        string `json:"phase,omitempty"`
+	Message      string `json:"message,omitempty"`
+	Count        int64  `json:"count"`
+	Successful   int64  `json:"successful"`
+	Failed       int64  `json:"failed"`
+	Inconclusive int64  `json:"inconclusive"`
+	Error        int64  `json:"error"`
+}
+
+// ValidateRolloutAction returns an error for unknown Rollout actions
+func ValidateRolloutAction(action string) error {
+	switch action {
+	case RolloutActionPromote, RolloutActionPromoteFull, RolloutActionAbort, RolloutActionRetry, RolloutActionPause:
+		return nil
+	}
+	return explorerErrors.ValidationError(fmt.Sprintf("unknown rollout action %q (expected promote, promote-full, abort, retry or pause)", action))
+}
+
+// argoRolloutsGVR resolves an Argo Rollouts resource ("rollouts", "analysisruns")
+func argoRolloutsGVR(resource string) (schema.GroupVersionResource, error) {
+	discovery := GetResourceDiscovery()
+	if discovery == nil {
+		return schema.GroupVersionResource{}, fmt.Errorf("resource discovery not initialized")
+	}
+	gvr, ok := discovery.GetGVRWithGroup(resource, "argoproj.io")
+	if !ok {
+		return schema.GroupVersionResource{}, explorerErrors.New(explorerErrors.ErrValidation, "Argo Rollouts (argoproj.io) isn't installed in this cluster")
+	}
+	return gvr, nil
+}
+
+// RunRolloutAction promotes, aborts, retries or pauses a Rollout by patching its spec and
+// status the same way the kubectl-argo-rollouts plugin does; the controller acts on it
+func RunRolloutAction(ctx context.Context, namespace, name, action string) error {
+	if err := ValidateRolloutAction(action); err != nil {
+		return err
+	}
+	dynamicClient, err := DynamicClientFor(ctx)
+	if err != nil {
+		return err
+	}
+	gvr, err := argoRolloutsGVR("rollouts")
+	if err != nil {
+		return err
+	}
+	rollout, err := dynamicClient.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get rollout: %w", err)
+	}
+
+	specPatch, statusPatch, err := rolloutActionPatches(rollout, action)
+	if err != nil {
+		return err
+	}
+	// Status first, as the plugin does: unpausing before the pause conditions are cleared
+	// would let the controller act on a half-applied promotion
+	client := dynamicClient.Resource(gvr).Namespace(namespace)
+	if statusPatch != nil {
+		data, err := json.Marshal(map[string]any{"status": statusPatch})
+		if err != nil {
+			return err
+		}
+		if _, err := client.Patch(ctx, name, types.MergePatchType, data, metav1.PatchOptions{}, "status"); err != nil {
+			return fmt.Errorf("failed to patch rollout status: %w", err)
+		}
+	}
+	if specPatch != nil {
+		data, err := json.Marshal(map[string]any{"spec": specPatch})
+		if err != nil {
+			return err
+		}
+		if _, err := client.Patch(ctx, name, types.MergePatchType, data, metav1.PatchOptions{}); err != nil {
+			return fmt.Errorf("failed to patch rollout: %w", err)
+		}
+	}
+	return nil
+}
+
+// rolloutActionPatches returns the spec and status merge patches for an action; either
+// may be nil
+func rolloutActionPatches(rollout *unstructured.Unstructured, action string) (spec, status map[string]any, err error) {
+	paused, _, _ := unstructured.NestedBool(rollout.Object, "spec", "paused")
+	pauseConditions, _, _ := unstructured.NestedSlice(rollout.Object, "status", "pauseConditions")
+	steps, _, _ := unstructured.NestedSlice(rollout.Object, "spec", "strategy", "canary", "steps")
+
+	switch action {
+	case RolloutActionPause:
+		return map[string]any{"paused": true}, nil, nil
+	case RolloutActionAbort:
+		return nil, map[string]any{"abort": true}, nil
+	case RolloutActionRetry:
+		return nil, map[string]any{"abort": false}, nil
+	case RolloutActionPromoteFull:
+		if full, _, _ := unstructured.NestedBool(rollout.Object, "status", "promoteFull"); !full {
+			status = map[string]any{"promoteFull": true}
+		}
+		if paused {
+			spec = map[string]any{"paused": false}
+		}
+		return spec, status, nil
+	}
+
+	// Promote: leave a user pause, then either clear the controller's pause conditions or
+	// skip the canary step that's running (analysis or experiment), never both
+	if paused {
+		spec = map[string]any{"paused": false}
+	}
+	stepIndex := currentStepIndex(rollout, len(steps))
+	switch {
+	case len(pauseConditions) > 0 && hasPauseReason(pauseConditions, inconclusivePauseReason) && stepIndex != nil:
+		// An inconclusive analysis pauses the controller on the step; move past it, or the
+		// rollout stays stuck when the next step is a pause too
+		status = map[string]any{"pauseConditions": nil, "controllerPause": false, "currentStepIndex": min(*stepIndex+1, int64(len(steps)))}
+	case len(pauseConditions) > 0:
+		status = map[string]any{"pauseConditions": nil}
+	case stepIndex != nil && *stepIndex < int64(len(steps)):
+		status = map[string]any{"currentStepIndex": *stepIndex + 1}
+	}
+	if spec == nil && status == nil {
+		return nil, nil, explorerErrors.New(explorerErrors.ErrConflict, "nothing to promote: the rollout isn't paused and has no remaining steps")
+	}
+	return spec, status, nil
+}
+
+// currentStepIndex returns a canary Rollout's current step, 0 before the controller sets
+// it, or nil when the Rollout has no steps
+func currentStepIndex(rollout *unstructured.Unstructured, steps int) *int64 {
+	if steps == 0 {
+		return nil
+	}
+	index, found, _ := unstructured.NestedInt64(rollout.Object, "status", "currentStepIndex")
+	if !found {
+		index = 0
+	}
+	return &index
+}
+
+func hasPauseReason(conditions []any, reason string) bool {
+	for _, c := range conditions {
+		if m, ok := c.(map[string]any); ok && m["reason"] == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// GetArgoRolloutStatus returns a Rollout's steps and the analysis runs it owns, read from
+// the dynamic cache. Analysis runs are left out when withRuns is false (the caller may not
+// be allowed to see them).
+func GetArgoRolloutStatus(rollout *unstructured.Unstructured, withRuns bool) *ArgoRolloutStatus {
+	p := &ArgoRolloutStatus{Strategy: "canary", AnalysisRuns: []ArgoAnalysisRun{}}
+	if _, ok, _ := unstructured.NestedMap(rollout.Object, "spec", "strategy", "blueGreen"); ok {
+		p.Strategy = "blueGreen"
+	}
+	p.Phase, _, _ = unstructured.NestedString(rollout.Object, "status", "phase")
+	p.Message, _, _ = unstructured.NestedString(rollout.Object, "status", "message")
+	p.Paused, _, _ = unstructured.NestedBool(rollout.Object, "spec", "paused")
+	p.Aborted, _, _ = unstructured.NestedBool(rollout.Object, "status", "abort")
+	p.PromoteFull, _, _ = unstructured.NestedBool(rollout.Object, "status", "promoteFull")
+	conditions, _, _ := unstructured.NestedSlice(rollout.Object, "status", "pauseConditions")
+	for _, c := range conditions {
+		if m, ok := c.(map[string]any); ok {
+			if reason, ok := m["reason"].(string); ok {
+				p.PauseReasons = append(p.PauseReasons, reason)
+			}
+		}
+	}
+
+	steps, _, _ := unstructured.NestedSlice(rollout.Object, "spec", "strategy", "canary", "steps")
+	p.CurrentStepIndex = currentStepIndex(rollout, len(steps))
+	for i, s := range steps {
+		step := ArgoRolloutStep{Index: i, Status: "pending"}
+		if m, ok := s.(map[string]any); ok {
+			for k, v := range m { // A step has exactly one field
+				step.Type, step.Value = k, v
+			}
+		}
+		switch {
+		case int64(i) < *p.CurrentStepIndex:
+			step.Status = "completed"
+		case int64(i) == *p.CurrentStepIndex:
+			step.Status = "current"
+		}
+		p.Steps = append(p.Steps, step)
+	}
+
+	if withRuns {
+		for _, run := range listOwnedByRollout(rollout, "analysisruns") {
+			p.AnalysisRuns = append(p.AnalysisRuns, summarizeAnalysisRun(run))
+		}
+	}
+	return p
+}
+
+// listOwnedByRollout lists the resources of an Argo Rollouts kind in the Rollout's
+// namespace whose owner is the Rollout, newest first
+func listOwnedByRollout(rollout *unstructured.Unstructured, resource string) []*unstructured.Unstructured {
+	gvr, err := argoRolloutsGVR(resource)
+	if err != nil {
+		return nil
+	}
+	items, err := GetDynamicResourceCache().List(gvr, rollout.GetNamespace())
+	if err != nil {
+		return nil
+	}
+	var owned []*unstructured.Unstructured
+	for _, item := range items {
+		for _, ref := range item.GetOwnerReferences() {
+			if ref.UID == rollout.GetUID() {
+				owned = append(owned, item)
+				break
+			}
+		}
+	}
+	sort.Slice(owned, func(i, j int) bool {
+		return owned[i].GetCreationTimestamp().After(owned[j].GetCreationTimestamp().Time)
+	})
+	return owned
+}
+
+func summarizeAnalysisRun(run *unstructured.Unstructured) ArgoAnalysisRun {
+	s := ArgoAnalysisRun{
+		Name:      run.GetName(),
+		Type:      run.GetLabels()["rollout-type"],
+		Revision:  run.GetAnnotations()["rollout.argoproj.io/revision"],
+		CreatedAt: run.GetCreationTimestamp().Time,
+	}
+	s.Phase, _, _ = unstructured.NestedString(run.Object, "status", "phase")
+	s.Message, _, _ = unstructured.NestedString(run.Object, "status", "message")
+	results, _, _ := unstructured.NestedSlice(run.Object, "status", "metricResults")
+	for _, r := range results {
+		m, ok := r.(map[string]any)
+		if !ok {
+			continue
+		}
+		metric := ArgoAnalysisMetric{}
+		metric.Name, _, _ = unstructured.NestedString(m, "name")
+		metric.Phase, _, _ = unstructured.NestedString(m, "phase")
+		metric.Message, _, _ = unstructured.NestedString(m, "message")
+		metric.Count, _, _ = unstructured.NestedInt64(m, "count")
+		metric.Successful, _, _ = unstructured.NestedInt64(m, "successful")
+		metric.Failed, _, _ = unstructured.NestedInt64(m, "failed")
+		metric.Inconclusive, _, _ = unstructured.NestedInt64(m, "inconclusive")
+		metric.Error, _, _ = unstructured.NestedInt64(m, "error")
+		s.Metrics = append(s.Metrics, metric)
+	}
+	return s
+}
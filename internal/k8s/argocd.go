@@ -0,0 +1,131 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+)
+
+// argoRefreshAnnotation asks the Argo CD application controller to re-read the app's
+// source ("normal") or also regenerate manifests bypassing its cache ("hard")
+const argoRefreshAnnotation = "argocd.argoproj.io/refresh"
+
+// ArgoSyncOptions configures an Argo CD sync triggered from Radar
+type ArgoSyncOptions struct {
+	Revision    string `json:"revision,omitempty"` // Sync to this revision instead of spec's targetRevision
+	Prune       bool   `json:"prune,omitempty"`    // Delete resources no longer in the source
+	DryRun      bool   `json:"dryRun,omitempty"`   // Let Argo CD compute the sync without applying
+	InitiatedBy string `json:"-"`                  // Recorded on the operation, shown in the Argo CD UI
+}
+
+// ArgoAutoSync configures an Application's automated sync policy
+type ArgoAutoSync struct {
+	Enabled  bool `json:"enabled"`
+	Prune    bool `json:"prune,omitempty"`
+	SelfHeal bool `json:"selfHeal,omitempty"`
+}
+
+// argoApplicationGVR resolves Argo CD's Application resource, which other projects'
+// "applications" resources would otherwise shadow
+func argoApplicationGVR() (schema.GroupVersionResource, error) {
+	discovery := GetResourceDiscovery()
+	if discovery == nil {
+		return schema.GroupVersionResource{}, fmt.Errorf("resource discovery not initialized")
+	}
+	gvr, ok := discovery.GetGVRWithGroup("applications", "argoproj.io")
+	if !ok {
+		return schema.GroupVersionResource{}, explorerErrors.New(explorerErrors.ErrValidation, "Argo CD Applications (argoproj.io) aren't installed in this cluster")
+	}
+	return gvr, nil
+}
+
+// patchArgoApplication applies a merge patch to an Argo CD Application
+func patchArgoApplication(ctx context.Context, namespace, name string, patch map[string]any) (*unstructured.Unstructured, error) {
+	dynamicClient, err := DynamicClientFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	gvr, err := argoApplicationGVR()
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return nil, err
+	}
+	app, err := dynamicClient.Resource(gvr).Namespace(namespace).Patch(ctx, name, types.MergePatchType, data, metav1.PatchOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch application: %w", err)
+	}
+	return app, nil
+}
+
+// SyncArgoApplication starts an Argo CD sync by setting the Application's operation, the
+// same way the Argo CD CLI and UI do. It fails if an operation is already running.
+func SyncArgoApplication(ctx context.Context, namespace, name string, opts ArgoSyncOptions) error {
+	dynamicClient, err := DynamicClientFor(ctx)
+	if err != nil {
+		return err
+	}
+	gvr, err := argoApplicationGVR()
+	if err != nil {
+		return err
+	}
+	app, err := dynamicClient.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get application: %w", err)
+	}
+	if _, pending, _ := unstructured.NestedMap(app.Object, "operation"); pending {
+		return explorerErrors.New(explorerErrors.ErrConflict, "another operation is already pending on this application")
+	}
+	if phase, _, _ := unstructured.NestedString(app.Object, "status", "operationState", "phase"); phase == "Running" || phase == "Terminating" {
+		return explorerErrors.New(explorerErrors.ErrConflict, fmt.Sprintf("another operation is already %s on this application", phase))
+	}
+
+	sync := map[string]any{
+		"prune":  opts.Prune,
+		"dryRun": opts.DryRun,
+	}
+	if opts.Revision != "" {
+		sync["revision"] = opts.Revision
+	}
+	operation := map[string]any{
+		"sync":        sync,
+		"initiatedBy": map[string]any{"username": opts.InitiatedBy},
+		"info":        []any{map[string]any{"name": "Reason", "value": "Initiated from Radar"}},
+	}
+	_, err = patchArgoApplication(ctx, namespace, name, map[string]any{"operation": operation})
+	return err
+}
+
+// RefreshArgoApplication asks Argo CD to compare the Application with its source now
+// instead of at the next polling interval
+func RefreshArgoApplication(ctx context.Context, namespace, name string, hard bool) error {
+	mode := "normal"
+	if hard {
+		mode = "hard"
+	}
+	_, err := patchArgoApplication(ctx, namespace, name, map[string]any{
+		"metadata": map[string]any{"annotations": map[string]any{argoRefreshAnnotation: mode}},
+	})
+	return err
+}
+
+// SetArgoAutoSync enables or disables an Application's automated sync
+func SetArgoAutoSync(ctx context.Context, namespace, name string, policy ArgoAutoSync) error {
+	var automated any // null removes the field, disabling auto-sync
+	if policy.Enabled {
+		automated = map[string]any{"prune": policy.Prune, "selfHeal": policy.SelfHeal}
+	}
+	_, err := patchArgoApplication(ctx, namespace, name, map[string]any{
+		"spec": map[string]any{"syncPolicy": map[string]any{"automated": automated}},
+	})
+	return err
+}
@@ -6,6 +6,7 @@ import (
 	"log"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -17,12 +18,12 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/client-go/informers"
 	listersappsv1 "k8s.io/client-go/listers/apps/v1"
 	listersautoscalingv2 "k8s.io/client-go/listers/autoscaling/v2"
 	listersbatchv1 "k8s.io/client-go/listers/batch/v1"
 	listerscorev1 "k8s.io/client-go/listers/core/v1"
 	listersnetworkingv1 "k8s.io/client-go/listers/networking/v1"
+	"k8s.io/client-go/metadata"
 	"k8s.io/client-go/tools/cache"
 
 	explorerErrors "github.com/skyhook-io/radar/internal/errors"
@@ -32,29 +33,45 @@ import (
 // DebugEvents enables verbose event debugging when true (set via --debug-events flag)
 var DebugEvents bool
 
-// initialSyncComplete is set to true after the initial cache sync completes.
+// initialSyncComplete is set after the initial cache sync completes.
 // During initial sync, "add" events are skipped since they represent existing
 // resources, not new creations. Only adds after sync are recorded.
-var initialSyncComplete bool
+var initialSyncComplete atomic.Bool
 
 // ResourceCache provides fast, eventually-consistent access to K8s resources
 // using SharedInformers. Optimized for small-mid sized clusters.
 type ResourceCache struct {
-	factory        informers.SharedInformerFactory
 	changes        chan ResourceChange
 	stopCh         chan struct{}
 	stopOnce       sync.Once
 	secretsEnabled bool // Whether secrets informer is running (requires RBAC)
+	cronJobEnabled bool // Whether batch/v1 CronJob informer is running (1.21+)
+	hpaEnabled     bool // Whether autoscaling/v2 HPA informer is running (1.23+)
+
+	secretMetadataOnly bool               // Secrets are watched without their data
+	metadataClient     metadata.Interface // For metadata-only secrets
+
+	// Informer factories by namespace ("" = cluster-wide), see cache_scope.go
+	namespaceScoped bool
+	scopes          map[string]*informerScope
+	scopeMu         sync.RWMutex
+	scopeChangeMu   sync.Mutex // Serializes SetWatchedNamespaces
 }
 
 // ResourceChange represents a resource change event
 type ResourceChange struct {
 	Kind      string // "Service", "Deployment", "Pod", etc.
+	Group     string // API group, set for custom resources
 	Namespace string
 	Name      string
 	UID       string
 	Operation string    // "add", "update", "delete"
 	Diff      *DiffInfo // Diff details for updates (from history)
+
+	// For SSE subscription filters; unset for K8s Events
+	Labels     map[string]string
+	Health     timeline.HealthState // After the change
+	PrevHealth timeline.HealthState // Before an update
 }
 
 var (
@@ -114,12 +131,6 @@ func InitResourceCache() error {
 			return
 		}
 
-		factory := informers.NewSharedInformerFactoryWithOptions(
-			k8sClient,
-			0, // no resync - updates come via watch
-			informers.WithTransform(dropManagedFields),
-		)
-
 		stopCh := make(chan struct{})
 		changes := make(chan ResourceChange, 10000)
 
@@ -128,101 +139,75 @@ func InitResourceCache() error {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		caps, _ := CheckCapabilities(ctx)
 		cancel()
-		secretsEnabled := caps != nil && caps.Secrets
-
-		// Core resources
-		svcInf := factory.Core().V1().Services().Informer()
-		podInf := factory.Core().V1().Pods().Informer()
-		nodeInf := factory.Core().V1().Nodes().Informer()
-		nsInf := factory.Core().V1().Namespaces().Informer()
-		cmInf := factory.Core().V1().ConfigMaps().Informer()
-		var secretInf cache.SharedIndexInformer
-		if secretsEnabled {
-			secretInf = factory.Core().V1().Secrets().Informer()
-		}
-		eventInf := factory.Core().V1().Events().Informer()
-		pvcInf := factory.Core().V1().PersistentVolumeClaims().Informer()
-
-		// Apps resources
-		depInf := factory.Apps().V1().Deployments().Informer()
-		dsInf := factory.Apps().V1().DaemonSets().Informer()
-		stsInf := factory.Apps().V1().StatefulSets().Informer()
-		rsInf := factory.Apps().V1().ReplicaSets().Informer()
-
-		// Networking resources
-		ingInf := factory.Networking().V1().Ingresses().Informer()
-
-		// Batch resources
-		jobInf := factory.Batch().V1().Jobs().Informer()
-		cronJobInf := factory.Batch().V1().CronJobs().Informer()
-
-		// Autoscaling resources
-		hpaInf := factory.Autoscaling().V2().HorizontalPodAutoscalers().Informer()
-
-		// Add event handlers - collect errors to fail fast on registration issues
-		handlerErrors := []error{
-			addChangeHandlers(svcInf, "Service", changes),
-			addChangeHandlers(podInf, "Pod", changes),
-			addChangeHandlers(nodeInf, "Node", changes),
-			addChangeHandlers(nsInf, "Namespace", changes),
-			addChangeHandlers(cmInf, "ConfigMap", changes),
-			addK8sEventHandlers(eventInf, changes), // K8s Events get special handling
-			addChangeHandlers(pvcInf, "PersistentVolumeClaim", changes),
-			addChangeHandlers(depInf, "Deployment", changes),
-			addChangeHandlers(dsInf, "DaemonSet", changes),
-			addChangeHandlers(stsInf, "StatefulSet", changes),
-			addChangeHandlers(rsInf, "ReplicaSet", changes),
-			addChangeHandlers(ingInf, "Ingress", changes),
-			addChangeHandlers(jobInf, "Job", changes),
-			addChangeHandlers(cronJobInf, "CronJob", changes),
-			addChangeHandlers(hpaInf, "HorizontalPodAutoscaler", changes),
-		}
-		if secretsEnabled {
-			handlerErrors = append(handlerErrors, addChangeHandlers(secretInf, "Secret", changes))
-		}
-		for _, err := range handlerErrors {
+		secretsEnabled := caps != nil && caps.Secrets && SecretsMode != SecretsModeOff
+		secretMetadataOnly := secretsEnabled && SecretsMode == SecretsModeMetadata
+		if !secretsEnabled && (SecretsMode == SecretsModeFull || SecretsMode == SecretsModeMetadata) {
+			log.Printf("Warning: --secrets=%s requested but RBAC does not allow listing secrets; secrets disabled", SecretsMode)
+		}
+
+		// Skip typed informers for API versions this cluster doesn't serve -
+		// an informer for a missing API never syncs and would block startup.
+		// Those kinds are served through the dynamic cache instead.
+		features := GetFeatures()
+
+		c := &ResourceCache{
+			changes:            changes,
+			stopCh:             stopCh,
+			secretsEnabled:     secretsEnabled,
+			cronJobEnabled:     features.HasCronJobV1(),
+			hpaEnabled:         features.HasHPAV2(),
+			secretMetadataOnly: secretMetadataOnly,
+			namespaceScoped:    len(WatchNamespaces) > 0,
+			scopes:             make(map[string]*informerScope),
+		}
+		if secretMetadataOnly {
+			metaClient, err := metadata.NewForConfig(k8sConfig)
 			if err != nil {
-				initErr = explorerErrors.Wrap(explorerErrors.ErrCacheHandlerFailed,
-					"failed to register event handlers", err)
+				initErr = fmt.Errorf("failed to create metadata client for secrets: %w", err)
 				return
 			}
+			c.metadataClient = metaClient
 		}
 
-		// Start all informers
-		factory.Start(stopCh)
+		listMethod := configureWatchList()
 
-		resourceCount := 15 // Base resource types without secrets
-		if secretsEnabled {
-			resourceCount = 16
+		// One cluster-wide factory, or with --namespaces a cluster factory for
+		// cluster-scoped kinds plus a factory per namespace (informers.WithNamespace)
+		scopeKinds := map[string][]typedKind{"": append(c.enabledKinds(false), c.enabledKinds(true)...)}
+		if c.namespaceScoped {
+			scopeKinds[""] = c.enabledKinds(false)
+			for _, ns := range WatchNamespaces {
+				scopeKinds[ns] = c.enabledKinds(true)
+			}
+		}
+		var syncTargets []namedInformer
+		for ns, kinds := range scopeKinds {
+			scope, targets, err := c.startScope(ns, kinds)
+			if err != nil {
+				c.stopScopes()
+				initErr = err
+				return
+			}
+			c.scopes[ns] = scope
+			syncTargets = append(syncTargets, targets...)
+		}
+
+		secretsDesc := fmt.Sprintf("%v", secretsEnabled)
+		if secretMetadataOnly {
+			secretsDesc = "metadata-only"
+		}
+		if c.namespaceScoped {
+			log.Printf("Starting resource cache with SharedInformers for %d resource types in namespaces %v (secrets=%s, lists=%s)",
+				len(scopeKinds[""])+len(c.enabledKinds(true)), WatchNamespaces, secretsDesc, listMethod)
+		} else {
+			log.Printf("Starting resource cache with SharedInformers for %d resource types (secrets=%s, lists=%s)", len(scopeKinds[""]), secretsDesc, listMethod)
 		}
-		log.Printf("Starting resource cache with SharedInformers for %d resource types (secrets=%v)", resourceCount, secretsEnabled)
 		syncStart := time.Now()
 
-		// Build list of sync functions - secrets is optional
-		syncFuncs := []cache.InformerSynced{
-			svcInf.HasSynced,
-			podInf.HasSynced,
-			nodeInf.HasSynced,
-			nsInf.HasSynced,
-			cmInf.HasSynced,
-			eventInf.HasSynced,
-			pvcInf.HasSynced,
-			depInf.HasSynced,
-			dsInf.HasSynced,
-			stsInf.HasSynced,
-			rsInf.HasSynced,
-			ingInf.HasSynced,
-			jobInf.HasSynced,
-			cronJobInf.HasSynced,
-			hpaInf.HasSynced,
-		}
-		if secretsEnabled {
-			syncFuncs = append(syncFuncs, secretInf.HasSynced)
-		}
-
-		// Wait for caches to sync
-		if !cache.WaitForCacheSync(stopCh, syncFuncs...) {
+		// Wait for caches to sync, timing each informer for the startup report
+		if !waitForInformersTimed(stopCh, syncTargets) {
 			close(stopCh)
+			c.stopScopes()
 			initErr = explorerErrors.New(explorerErrors.ErrCacheSyncFailed,
 				"failed to sync resource caches")
 			return
@@ -231,18 +216,68 @@ func InitResourceCache() error {
 		log.Printf("Resource caches synced successfully in %v", time.Since(syncStart))
 
 		// Mark initial sync as complete - now we can start recording "add" events
-		initialSyncComplete = true
+		initialSyncComplete.Store(true)
 
-		resourceCache = &ResourceCache{
-			factory:        factory,
-			changes:        changes,
-			stopCh:         stopCh,
-			secretsEnabled: secretsEnabled,
-		}
+		resourceCache = c
+		timeline.SetOwnerResolver(c.resolveOwner)
 	})
 	return initErr
 }
 
+// resolveOwner returns the controller owner of a cached Pod, ReplicaSet or Job, or the
+// workload an HPA scales, so timeline incidents group their events under it
+func (c *ResourceCache) resolveOwner(kind, namespace, name string) *timeline.OwnerInfo {
+	var obj any
+	var err error
+	switch kind {
+	case "Pod":
+		obj, err = c.Pods().Pods(namespace).Get(name)
+	case "ReplicaSet":
+		obj, err = c.ReplicaSets().ReplicaSets(namespace).Get(name)
+	case "Job":
+		obj, err = c.Jobs().Jobs(namespace).Get(name)
+	case "HorizontalPodAutoscaler":
+		hpa, err := c.HorizontalPodAutoscalers().HorizontalPodAutoscalers(namespace).Get(name)
+		if err != nil || hpa.Spec.ScaleTargetRef.Name == "" {
+			return nil
+		}
+		return &timeline.OwnerInfo{Kind: hpa.Spec.ScaleTargetRef.Kind, Name: hpa.Spec.ScaleTargetRef.Name}
+	default:
+		return nil
+	}
+	if err != nil {
+		return nil
+	}
+	return timeline.ExtractOwner(obj)
+}
+
+// namedInformer pairs an informer with its kind for sync reporting
+type namedInformer struct {
+	kind     string
+	informer cache.SharedIndexInformer
+}
+
+// waitForInformersTimed waits for all informers to sync, recording how long each took
+// and how many objects it holds. Returns false if any failed to sync before stopCh closed.
+func waitForInformersTimed(stopCh <-chan struct{}, targets []namedInformer) bool {
+	start := time.Now()
+	var wg sync.WaitGroup
+	var failed atomic.Bool
+	for _, t := range targets {
+		wg.Add(1)
+		go func(t namedInformer) {
+			defer wg.Done()
+			ok := cache.WaitForCacheSync(stopCh, t.informer.HasSynced)
+			if !ok {
+				failed.Store(true)
+			}
+			recordInformerSync(t.kind, false, time.Since(start), len(t.informer.GetStore().ListKeys()), !ok)
+		}(t)
+	}
+	wg.Wait()
+	return !failed.Load()
+}
+
 // GetResourceCache returns the singleton cache instance
 func GetResourceCache() *ResourceCache {
 	return resourceCache
@@ -259,7 +294,7 @@ func ResetResourceCache() {
 		resourceCache = nil
 	}
 	cacheOnce = sync.Once{}
-	initialSyncComplete = false
+	initialSyncComplete.Store(false)
 }
 
 // ReinitResourceCache reinitializes the resource cache after a context switch
@@ -344,7 +379,13 @@ func addK8sEventHandlers(inf cache.SharedIndexInformer, ch chan<- ResourceChange
 				}
 			}
 
-			// Update K8s Event in timeline store (with new count)
+			// Update the K8s Event's aggregate in the timeline store when it recurred;
+			// resyncs and unrelated metadata updates leave it alone
+			if oldEvent, ok := oldObj.(*corev1.Event); ok {
+				if newEvent, ok := newObj.(*corev1.Event); ok && !k8sEventRecurred(oldEvent, newEvent) {
+					return
+				}
+			}
 			recordK8sEventToTimeline(newObj)
 		},
 		DeleteFunc: func(obj any) {
@@ -386,7 +427,19 @@ func addK8sEventHandlers(inf cache.SharedIndexInformer, ch chan<- ResourceChange
 	return nil
 }
 
-// recordK8sEventToTimeline records a K8s Event to the timeline store
+// k8sEventRecurred reports whether an Event update is a new occurrence
+func k8sEventRecurred(oldEvent, newEvent *corev1.Event) bool {
+	if oldEvent.Count != newEvent.Count || !oldEvent.LastTimestamp.Equal(&newEvent.LastTimestamp) || oldEvent.Message != newEvent.Message {
+		return true
+	}
+	if newEvent.Series == nil {
+		return false
+	}
+	return oldEvent.Series == nil || oldEvent.Series.Count != newEvent.Series.Count
+}
+
+// recordK8sEventToTimeline folds a K8s Event into its aggregated timeline entry, one per
+// involved object, reason and type, and records the updated entry
 func recordK8sEventToTimeline(obj any) {
 	event, ok := obj.(*corev1.Event)
 	if !ok {
@@ -428,8 +481,7 @@ func recordK8sEventToTimeline(obj any) {
 		}
 	}
 
-	// Create timeline event using the converter
-	timelineEvent := timeline.NewK8sEventTimelineEvent(event, owner)
+	timelineEvent := timeline.FoldK8sEvent(event, owner)
 
 	// Record to store with broadcast to SSE subscribers
 	ctx := context.Background()
@@ -482,6 +534,7 @@ func isNoisyResource(kind, name, op string) bool {
 // enqueueChange sends a change notification and records to both legacy history and timeline store
 func enqueueChange(ch chan<- ResourceChange, kind string, obj any, oldObj any, op string) {
 	meta, ok := obj.(metav1.Object)
+	isTombstone := false
 	if !ok {
 		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
 			meta, ok = tombstone.Obj.(metav1.Object)
@@ -489,6 +542,7 @@ func enqueueChange(ch chan<- ResourceChange, kind string, obj any, oldObj any, o
 				return
 			}
 			obj = tombstone.Obj
+			isTombstone = true
 		} else {
 			return
 		}
@@ -497,16 +551,21 @@ func enqueueChange(ch chan<- ResourceChange, kind string, obj any, oldObj any, o
 	// Track event received
 	timeline.IncrementReceived(kind)
 
+	// Raw event for the watch inspector (nil unless someone is inspecting)
+	raw := newRawWatchEvent("typed", kind, meta.GetNamespace(), meta.GetName(), string(meta.GetUID()), op, meta.GetResourceVersion(), isTombstone)
+	defer publishRawWatchEvent(raw)
+
 	// Debug: log adds for core workload resources
 	if DebugEvents && op == "add" && (kind == "Pod" || kind == "Deployment" || kind == "Service") {
 		log.Printf("[DEBUG] enqueueChange: %s add %s/%s", kind, meta.GetNamespace(), meta.GetName())
 	}
 
 	// Skip recording noisy resources to preserve history buffer for interesting events
-	skipHistory := isNoisyResource(kind, meta.GetName(), op)
+	skipHistory := isNoisyResource(kind, meta.GetName(), op) || isQuotaUsageUpdate(kind, oldObj, obj)
 	if skipHistory {
 		timeline.RecordDrop(kind, meta.GetNamespace(), meta.GetName(),
 			timeline.DropReasonNoisyFilter, op)
+		raw.addDrop(timeline.DropReasonNoisyFilter)
 		if DebugEvents {
 			log.Printf("[DEBUG] Filtered noisy resource: %s/%s/%s op=%s", kind, meta.GetNamespace(), meta.GetName(), op)
 		}
@@ -515,6 +574,9 @@ func enqueueChange(ch chan<- ResourceChange, kind string, obj any, oldObj any, o
 	// Record to timeline store
 	if !skipHistory {
 		recordToTimelineStore(kind, meta.GetNamespace(), meta.GetName(), string(meta.GetUID()), op, oldObj, obj)
+		if raw != nil {
+			raw.SentToTimeline = true
+		}
 	}
 
 	// Compute diff for updates
@@ -530,15 +592,24 @@ func enqueueChange(ch chan<- ResourceChange, kind string, obj any, oldObj any, o
 		UID:       string(meta.GetUID()),
 		Operation: op,
 		Diff:      diff,
+		Labels:    meta.GetLabels(),
+		Health:    timeline.DetermineHealthState(kind, obj),
+	}
+	if op == "update" && oldObj != nil {
+		change.PrevHealth = timeline.DetermineHealthState(kind, oldObj)
 	}
 
 	// Non-blocking send
 	select {
 	case ch <- change:
+		if raw != nil {
+			raw.SentToChannel = true
+		}
 	default:
 		// Channel full, drop event
 		timeline.RecordDrop(kind, meta.GetNamespace(), meta.GetName(),
 			timeline.DropReasonChannelFull, op)
+		raw.addDrop(timeline.DropReasonChannelFull)
 		if DebugEvents {
 			log.Printf("[DEBUG] Change channel full, dropped: %s/%s/%s op=%s", kind, meta.GetNamespace(), meta.GetName(), op)
 		}
@@ -552,11 +623,19 @@ func recordToTimelineStore(kind, namespace, name, uid, op string, oldObj, newObj
 		return
 	}
 
+	// Custom resources are tracked by Kind.group so same-named kinds from different
+	// API groups don't share seen state
+	group := GroupOf(newObj)
+	if newObj == nil {
+		group = GroupOf(oldObj)
+	}
+	seenKind := QualifiedKind(kind, group)
+
 	// Check if we've already seen this resource (for dedup on restart)
 	// For "add", we check if seen and skip if so. We mark as seen AFTER successful append
 	// to avoid the race where a failed append leaves the resource marked as seen.
 	if op == "add" {
-		if store.IsResourceSeen(kind, namespace, name) {
+		if store.IsResourceSeen(seenKind, namespace, name, uid) {
 			timeline.RecordDrop(kind, namespace, name, timeline.DropReasonAlreadySeen, op)
 			if DebugEvents {
 				log.Printf("[DEBUG] Already seen, skipping: %s/%s/%s", kind, namespace, name)
@@ -565,7 +644,7 @@ func recordToTimelineStore(kind, namespace, name, uid, op string, oldObj, newObj
 		}
 		// Don't mark as seen yet - do it after successful append
 	} else if op == "delete" {
-		store.ClearResourceSeen(kind, namespace, name)
+		store.ClearResourceSeen(seenKind, namespace, name)
 	}
 	// For "update", we don't need to track seen state - updates are always recorded
 
@@ -623,6 +702,18 @@ func recordToTimelineStore(kind, namespace, name, uid, op string, oldObj, newObj
 		labels,
 		createdAt,
 	)
+	event.Group = group
+
+	// Label pod replacements/restarts with their inferred cause, and remember
+	// workload template changes so later pod deletions can be attributed to them
+	switch kind {
+	case "Pod":
+		annotatePodEvent(&event, op, oldObj, newObj)
+	case "Deployment", "StatefulSet", "DaemonSet":
+		if op == "update" {
+			trackTemplateChange(kind, namespace, name, oldObj, newObj)
+		}
+	}
 
 	// For "add" operations, also extract historical events from resource status
 	// and record them to the timeline store
@@ -640,7 +731,7 @@ func recordToTimelineStore(kind, namespace, name, uid, op string, oldObj, newObj
 		isSyncEvent := false
 
 		// Method 1: Check initialSyncComplete flag (fast path during startup)
-		if !initialSyncComplete {
+		if !initialSyncComplete.Load() {
 			isSyncEvent = true
 		}
 
@@ -690,7 +781,7 @@ func recordToTimelineStore(kind, namespace, name, uid, op string, oldObj, newObj
 	// Mark resource as seen AFTER successful append to avoid race condition
 	// where a failed append leaves the resource marked as seen
 	if op == "add" {
-		store.MarkResourceSeen(kind, namespace, name)
+		store.MarkResourceSeen(seenKind, namespace, name, uid)
 	}
 }
 
@@ -787,112 +878,132 @@ func (c *ResourceCache) Services() listerscorev1.ServiceLister {
 	if c == nil {
 		return nil
 	}
-	return c.factory.Core().V1().Services().Lister()
+	return listerscorev1.NewServiceLister(c.indexer("services"))
 }
 
 func (c *ResourceCache) Pods() listerscorev1.PodLister {
 	if c == nil {
 		return nil
 	}
-	return c.factory.Core().V1().Pods().Lister()
+	return listerscorev1.NewPodLister(c.indexer("pods"))
 }
 
 func (c *ResourceCache) Nodes() listerscorev1.NodeLister {
 	if c == nil {
 		return nil
 	}
-	return c.factory.Core().V1().Nodes().Lister()
+	return listerscorev1.NewNodeLister(c.indexer("nodes"))
 }
 
 func (c *ResourceCache) Namespaces() listerscorev1.NamespaceLister {
 	if c == nil {
 		return nil
 	}
-	return c.factory.Core().V1().Namespaces().Lister()
+	return listerscorev1.NewNamespaceLister(c.indexer("namespaces"))
 }
 
 func (c *ResourceCache) ConfigMaps() listerscorev1.ConfigMapLister {
 	if c == nil {
 		return nil
 	}
-	return c.factory.Core().V1().ConfigMaps().Lister()
+	return listerscorev1.NewConfigMapLister(c.indexer("configmaps"))
 }
 
 func (c *ResourceCache) Secrets() listerscorev1.SecretLister {
 	if c == nil || !c.secretsEnabled {
 		return nil
 	}
-	return c.factory.Core().V1().Secrets().Lister()
+	return listerscorev1.NewSecretLister(c.indexer("secrets"))
+}
+
+// SecretsMetadataOnly reports whether secrets are cached without their data
+// (names, types and ages are available, values are not)
+func (c *ResourceCache) SecretsMetadataOnly() bool {
+	return c != nil && c.secretMetadataOnly
 }
 
 func (c *ResourceCache) Events() listerscorev1.EventLister {
 	if c == nil {
 		return nil
 	}
-	return c.factory.Core().V1().Events().Lister()
+	return listerscorev1.NewEventLister(c.indexer("events"))
 }
 
 func (c *ResourceCache) PersistentVolumeClaims() listerscorev1.PersistentVolumeClaimLister {
 	if c == nil {
 		return nil
 	}
-	return c.factory.Core().V1().PersistentVolumeClaims().Lister()
+	return listerscorev1.NewPersistentVolumeClaimLister(c.indexer("persistentvolumeclaims"))
+}
+
+func (c *ResourceCache) ResourceQuotas() listerscorev1.ResourceQuotaLister {
+	if c == nil {
+		return nil
+	}
+	return listerscorev1.NewResourceQuotaLister(c.indexer("resourcequotas"))
+}
+
+func (c *ResourceCache) LimitRanges() listerscorev1.LimitRangeLister {
+	if c == nil {
+		return nil
+	}
+	return listerscorev1.NewLimitRangeLister(c.indexer("limitranges"))
 }
 
 func (c *ResourceCache) Deployments() listersappsv1.DeploymentLister {
 	if c == nil {
 		return nil
 	}
-	return c.factory.Apps().V1().Deployments().Lister()
+	return listersappsv1.NewDeploymentLister(c.indexer("deployments"))
 }
 
 func (c *ResourceCache) DaemonSets() listersappsv1.DaemonSetLister {
 	if c == nil {
 		return nil
 	}
-	return c.factory.Apps().V1().DaemonSets().Lister()
+	return listersappsv1.NewDaemonSetLister(c.indexer("daemonsets"))
 }
 
 func (c *ResourceCache) StatefulSets() listersappsv1.StatefulSetLister {
 	if c == nil {
 		return nil
 	}
-	return c.factory.Apps().V1().StatefulSets().Lister()
+	return listersappsv1.NewStatefulSetLister(c.indexer("statefulsets"))
 }
 
 func (c *ResourceCache) ReplicaSets() listersappsv1.ReplicaSetLister {
 	if c == nil {
 		return nil
 	}
-	return c.factory.Apps().V1().ReplicaSets().Lister()
+	return listersappsv1.NewReplicaSetLister(c.indexer("replicasets"))
 }
 
 func (c *ResourceCache) Ingresses() listersnetworkingv1.IngressLister {
 	if c == nil {
 		return nil
 	}
-	return c.factory.Networking().V1().Ingresses().Lister()
+	return listersnetworkingv1.NewIngressLister(c.indexer("ingresses"))
 }
 
 func (c *ResourceCache) Jobs() listersbatchv1.JobLister {
 	if c == nil {
 		return nil
 	}
-	return c.factory.Batch().V1().Jobs().Lister()
+	return listersbatchv1.NewJobLister(c.indexer("jobs"))
 }
 
 func (c *ResourceCache) CronJobs() listersbatchv1.CronJobLister {
 	if c == nil {
 		return nil
 	}
-	return c.factory.Batch().V1().CronJobs().Lister()
+	return listersbatchv1.NewCronJobLister(c.indexer("cronjobs"))
 }
 
 func (c *ResourceCache) HorizontalPodAutoscalers() listersautoscalingv2.HorizontalPodAutoscalerLister {
 	if c == nil {
 		return nil
 	}
-	return c.factory.Autoscaling().V2().HorizontalPodAutoscalers().Lister()
+	return listersautoscalingv2.NewHorizontalPodAutoscalerLister(c.indexer("horizontalpodautoscalers"))
 }
 
 // Changes returns the channel for resource change notifications
@@ -920,11 +1031,21 @@ func (c *ResourceCache) Stop() {
 	c.stopOnce.Do(func() {
 		log.Println("Stopping resource cache")
 		close(c.stopCh)
-		c.factory.Shutdown()
+		c.stopScopes()
 		close(c.changes)
 	})
 }
 
+// stopScopes stops every informer factory
+func (c *ResourceCache) stopScopes() {
+	c.scopeMu.Lock()
+	defer c.scopeMu.Unlock()
+	for ns, s := range c.scopes {
+		s.stop()
+		delete(c.scopes, ns)
+	}
+}
+
 // GetResourceCount returns total cached resources
 func (c *ResourceCache) GetResourceCount() int {
 	if c == nil {
@@ -975,6 +1096,8 @@ var knownKinds = map[string]bool{
 	"secret": true, "secrets": true,
 	"event": true, "events": true,
 	"persistentvolumeclaim": true, "persistentvolumeclaims": true, "pvc": true, "pvcs": true,
+	"resourcequota": true, "resourcequotas": true, "quota": true, "quotas": true,
+	"limitrange": true, "limitranges": true,
 	"node": true, "nodes": true,
 	"namespace": true, "namespaces": true,
 	"job": true, "jobs": true,
@@ -987,6 +1110,24 @@ func IsKnownKind(kind string) bool {
 	return knownKinds[strings.ToLower(kind)]
 }
 
+// HasTypedInformer reports whether the typed cache is actively watching the kind.
+// Returns false for kinds whose API version isn't served by the cluster
+// (e.g. batch/v1 CronJob on pre-1.21 clusters); those must use the dynamic cache.
+func (c *ResourceCache) HasTypedInformer(kind string) bool {
+	if c == nil {
+		return false
+	}
+	switch strings.ToLower(kind) {
+	case "cronjob", "cronjobs":
+		return c.cronJobEnabled
+	case "horizontalpodautoscaler", "horizontalpodautoscalers", "hpa", "hpas":
+		return c.hpaEnabled
+	case "secret", "secrets":
+		return c.secretsEnabled
+	}
+	return IsKnownKind(kind)
+}
+
 // ListDynamic returns resources of any type using the dynamic cache
 // Falls back to typed cache for known resources
 func (c *ResourceCache) ListDynamic(ctx context.Context, kind string, namespace string) ([]*unstructured.Unstructured, error) {
@@ -1121,7 +1262,7 @@ func (c *ResourceCache) GetResourceStatus(kind, namespace, name string) *Resourc
 
 		// Check pod-level issues for unhealthy deployments
 		if dep.Status.ReadyReplicas < dep.Status.Replicas && dep.Status.Replicas > 0 {
-			pods := c.getPodsForWorkload(namespace, dep.Spec.Selector)
+			pods := c.PodsForSelector(namespace, dep.Spec.Selector)
 			if len(pods) > 0 {
 				issueSummary := getPodsIssueSummary(pods)
 				if issueSummary.TopIssue != "" {
@@ -1159,7 +1300,7 @@ func (c *ResourceCache) GetResourceStatus(kind, namespace, name string) *Resourc
 
 		// Check pod-level issues for unhealthy statefulsets
 		if sts.Status.ReadyReplicas < replicas && replicas > 0 {
-			pods := c.getPodsForWorkload(namespace, sts.Spec.Selector)
+			pods := c.PodsForSelector(namespace, sts.Spec.Selector)
 			if len(pods) > 0 {
 				issueSummary := getPodsIssueSummary(pods)
 				if issueSummary.TopIssue != "" {
@@ -1192,7 +1333,7 @@ func (c *ResourceCache) GetResourceStatus(kind, namespace, name string) *Resourc
 
 		// Get pods owned by this DaemonSet
 		if ds.Status.NumberReady < ds.Status.DesiredNumberScheduled {
-			pods := c.getPodsForWorkload(namespace, ds.Spec.Selector)
+			pods := c.PodsForSelector(namespace, ds.Spec.Selector)
 			if len(pods) > 0 {
 				issueSummary := getPodsIssueSummary(pods)
 				if issueSummary.TopIssue != "" {
@@ -1325,6 +1466,57 @@ func (c *ResourceCache) GetResourceStatus(kind, namespace, name string) *Resourc
 	}
 }
 
+// GetCustomResourceStatus looks up a custom resource in the dynamic cache (which starts
+// watching its kind) and reports its status from its Ready or Available condition,
+// falling back to status.phase. Returns nil when the kind is unknown, the resource isn't
+// found or it reports neither.
+func GetCustomResourceStatus(group, kind, namespace, name string) *ResourceStatus {
+	res, ok := GetResourceDiscovery().GetResource(QualifiedKind(kind, group))
+	if !ok {
+		return nil
+	}
+	if !res.Namespaced {
+		namespace = ""
+	}
+	gvr := schema.GroupVersionResource{Group: res.Group, Version: res.Version, Resource: res.Name}
+	obj, err := GetDynamicResourceCache().Get(gvr, namespace, name)
+	if err != nil {
+		return nil
+	}
+	return customResourceStatus(obj)
+}
+
+func customResourceStatus(obj *unstructured.Unstructured) *ResourceStatus {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, condType := range []string{"Ready", "Available"} {
+		for _, c := range conditions {
+			cond, ok := c.(map[string]any)
+			if !ok || cond["type"] != condType {
+				continue
+			}
+			status, _ := cond["status"].(string)
+			reason, _ := cond["reason"].(string)
+			message, _ := cond["message"].(string)
+			switch status {
+			case "True":
+				return &ResourceStatus{Status: condType, Summary: condType, Message: message}
+			case "False":
+				issue := reason
+				if issue == "" {
+					issue = "Not" + condType
+				}
+				return &ResourceStatus{Status: issue, Summary: issue, Message: message, Issue: issue}
+			default:
+				return &ResourceStatus{Status: "Progressing", Summary: "Progressing", Message: message}
+			}
+		}
+	}
+	if phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase"); phase != "" {
+		return &ResourceStatus{Status: phase, Summary: phase}
+	}
+	return nil
+}
+
 // getPodReadyCount returns the ready container count as "ready/total"
 func getPodReadyCount(pod *corev1.Pod) string {
 	ready := 0
@@ -1463,30 +1655,3 @@ func (s *PodIssueSummary) FormatStatusSummary() string {
 	}
 	return fmt.Sprintf("%d/%d ready", s.Ready, s.Total)
 }
-
-// getPodsForWorkload returns pods matching the given label selector in a namespace
-func (c *ResourceCache) getPodsForWorkload(namespace string, selector *metav1.LabelSelector) []*corev1.Pod {
-	if c == nil || selector == nil {
-		return nil
-	}
-
-	allPods, err := c.Pods().Pods(namespace).List(labels.Everything())
-	if err != nil {
-		return nil
-	}
-
-	// Convert LabelSelector to Selector
-	labelSelector, err := metav1.LabelSelectorAsSelector(selector)
-	if err != nil {
-		return nil
-	}
-
-	var matchingPods []*corev1.Pod
-	for _, pod := range allPods {
-		if labelSelector.Matches(labels.Set(pod.Labels)) {
-			matchingPods = append(matchingPods, pod)
-		}
-	}
-
-	return matchingPods
-}
@@ -0,0 +1,207 @@
+package k8s
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Informer index names. Lookups through them touch only the matching objects instead
+// of listing and filtering a whole kind.
+const (
+	indexPodNode     = "node"     // Pods by spec.nodeName
+	indexOwnerUID    = "owner"    // Owned objects by each owner reference UID
+	indexLabel       = "label"    // Pods by "namespace/key=value" for each label
+	indexSvcSelector = "selector" // Services by "namespace/" + canonical selector
+)
+
+// indexersFor returns the extra indexers a typed informer gets before it starts
+func indexersFor(kind string) cache.Indexers {
+	switch kind {
+	case "Pod":
+		return cache.Indexers{indexPodNode: podNodeIndex, indexOwnerUID: ownerUIDIndex, indexLabel: labelIndex}
+	case "ReplicaSet", "Job":
+		return cache.Indexers{indexOwnerUID: ownerUIDIndex}
+	case "Service":
+		return cache.Indexers{indexSvcSelector: serviceSelectorIndex}
+	}
+	return nil
+}
+
+func podNodeIndex(obj any) ([]string, error) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok || pod.Spec.NodeName == "" {
+		return nil, nil
+	}
+	return []string{pod.Spec.NodeName}, nil
+}
+
+func ownerUIDIndex(obj any) ([]string, error) {
+	meta, ok := obj.(metav1.Object)
+	if !ok {
+		return nil, nil
+	}
+	refs := meta.GetOwnerReferences()
+	keys := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		keys = append(keys, string(ref.UID))
+	}
+	return keys, nil
+}
+
+func labelKey(namespace, key, value string) string {
+	return namespace + "/" + key + "=" + value
+}
+
+func labelIndex(obj any) ([]string, error) {
+	meta, ok := obj.(metav1.Object)
+	if !ok {
+		return nil, nil
+	}
+	keys := make([]string, 0, len(meta.GetLabels()))
+	for k, v := range meta.GetLabels() {
+		keys = append(keys, labelKey(meta.GetNamespace(), k, v))
+	}
+	return keys, nil
+}
+
+// selectorKey canonicalizes a selector map, so services with the same selector share a key
+func selectorKey(namespace string, selector map[string]string) string {
+	return namespace + "/" + labels.SelectorFromSet(selector).String()
+}
+
+func serviceSelectorIndex(obj any) ([]string, error) {
+	svc, ok := obj.(*corev1.Service)
+	if !ok || len(svc.Spec.Selector) == 0 {
+		return nil, nil
+	}
+	return []string{selectorKey(svc.Namespace, svc.Spec.Selector)}, nil
+}
+
+// byIndex returns the objects of a resource under an index value, nil on error
+func (c *ResourceCache) byIndex(resource, index, value string) []any {
+	items, err := c.indexer(resource).ByIndex(index, value)
+	if err != nil {
+		return nil
+	}
+	return items
+}
+
+func toPods(items []any) []*corev1.Pod {
+	pods := make([]*corev1.Pod, 0, len(items))
+	for _, item := range items {
+		if pod, ok := item.(*corev1.Pod); ok {
+			pods = append(pods, pod)
+		}
+	}
+	return pods
+}
+
+// PodsOnNode returns the cached pods scheduled to a node
+func (c *ResourceCache) PodsOnNode(node string) []*corev1.Pod {
+	if c == nil || !c.HasTypedInformer("Pod") {
+		return nil
+	}
+	return toPods(c.byIndex("pods", indexPodNode, node))
+}
+
+// PodsOwnedBy returns the cached pods with an owner reference to uid
+func (c *ResourceCache) PodsOwnedBy(uid types.UID) []*corev1.Pod {
+	if c == nil || !c.HasTypedInformer("Pod") {
+		return nil
+	}
+	return toPods(c.byIndex("pods", indexOwnerUID, string(uid)))
+}
+
+// OwnedBy returns the cached Pods, ReplicaSets and Jobs with an owner reference to uid,
+// keyed by kind
+func (c *ResourceCache) OwnedBy(uid types.UID) map[string][]metav1.Object {
+	if c == nil {
+		return nil
+	}
+	owned := make(map[string][]metav1.Object)
+	for _, k := range []struct{ kind, resource string }{{"ReplicaSet", "replicasets"}, {"Job", "jobs"}, {"Pod", "pods"}} {
+		if !c.HasTypedInformer(k.kind) {
+			continue
+		}
+		for _, item := range c.byIndex(k.resource, indexOwnerUID, string(uid)) {
+			if obj, ok := item.(metav1.Object); ok {
+				owned[k.kind] = append(owned[k.kind], obj)
+			}
+		}
+	}
+	return owned
+}
+
+// PodsWithLabels returns the cached pods in a namespace carrying every label in set,
+// as a Service selector matches them. An empty set matches nothing.
+func (c *ResourceCache) PodsWithLabels(namespace string, set map[string]string) []*corev1.Pod {
+	if c == nil || len(set) == 0 || !c.HasTypedInformer("Pod") {
+		return nil
+	}
+	return c.podsNarrowedBy(namespace, set, labels.SelectorFromSet(set))
+}
+
+// PodsForSelector returns the cached pods in a namespace matching a workload's label
+// selector. Its matchLabels narrow the candidates through the label index; selectors
+// with only expressions fall back to the namespace's pods.
+func (c *ResourceCache) PodsForSelector(namespace string, selector *metav1.LabelSelector) []*corev1.Pod {
+	if c == nil || selector == nil || !c.HasTypedInformer("Pod") {
+		return nil
+	}
+	sel, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil
+	}
+	if len(selector.MatchLabels) == 0 {
+		pods, err := c.Pods().Pods(namespace).List(sel)
+		if err != nil {
+			return nil
+		}
+		return pods
+	}
+	return c.podsNarrowedBy(namespace, selector.MatchLabels, sel)
+}
+
+// podsNarrowedBy looks up the smallest label bucket among set and keeps the pods
+// matching sel
+func (c *ResourceCache) podsNarrowedBy(namespace string, set map[string]string, sel labels.Selector) []*corev1.Pod {
+	var candidates []any
+	first := true
+	for k, v := range set {
+		items := c.byIndex("pods", indexLabel, labelKey(namespace, k, v))
+		if first || len(items) < len(candidates) {
+			candidates, first = items, false
+		}
+		if len(candidates) == 0 {
+			return nil
+		}
+	}
+	var pods []*corev1.Pod
+	for _, pod := range toPods(candidates) {
+		if sel.Matches(labels.Set(pod.Labels)) {
+			pods = append(pods, pod)
+		}
+	}
+	return pods
+}
+
+// ServicesWithSelector returns the cached services in a namespace whose selector is
+// exactly set, sorted by name
+func (c *ResourceCache) ServicesWithSelector(namespace string, set map[string]string) []*corev1.Service {
+	if c == nil || len(set) == 0 || !c.HasTypedInformer("Service") {
+		return nil
+	}
+	var services []*corev1.Service
+	for _, item := range c.byIndex("services", indexSvcSelector, selectorKey(namespace, set)) {
+		if svc, ok := item.(*corev1.Service); ok {
+			services = append(services, svc)
+		}
+	}
+	sort.Slice(services, func(i, j int) bool { return services[i].Name < services[j].Name })
+	return services
+}
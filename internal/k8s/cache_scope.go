@@ -0,0 +1,424 @@
+package k8s
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+)
+
+// WatchNamespaces restricts the typed informers to these namespaces (set via --namespaces).
+// Empty watches the whole cluster. Nodes and Namespaces are always watched cluster-wide.
+var WatchNamespaces []string
+
+// scopeSyncTimeout bounds how long adding a namespace at runtime waits for its informers
+const scopeSyncTimeout = 30 * time.Second
+
+// typedKind is a resource served by the typed informer cache
+type typedKind struct {
+	kind       string
+	gvr        schema.GroupVersionResource
+	namespaced bool
+}
+
+// typedKinds lists the typed informers in creation order
+var typedKinds = []typedKind{
+	{"Service", corev1.SchemeGroupVersion.WithResource("services"), true},
+	{"Pod", corev1.SchemeGroupVersion.WithResource("pods"), true},
+	{"Node", corev1.SchemeGroupVersion.WithResource("nodes"), false},
+	{"Namespace", corev1.SchemeGroupVersion.WithResource("namespaces"), false},
+	{"ConfigMap", corev1.SchemeGroupVersion.WithResource("configmaps"), true},
+	{"Secret", corev1.SchemeGroupVersion.WithResource("secrets"), true},
+	{"Event", corev1.SchemeGroupVersion.WithResource("events"), true},
+	{"PersistentVolumeClaim", corev1.SchemeGroupVersion.WithResource("persistentvolumeclaims"), true},
+	{"ResourceQuota", corev1.SchemeGroupVersion.WithResource("resourcequotas"), true},
+	{"LimitRange", corev1.SchemeGroupVersion.WithResource("limitranges"), true},
+	{"Deployment", appsv1.SchemeGroupVersion.WithResource("deployments"), true},
+	{"DaemonSet", appsv1.SchemeGroupVersion.WithResource("daemonsets"), true},
+	{"StatefulSet", appsv1.SchemeGroupVersion.WithResource("statefulsets"), true},
+	{"ReplicaSet", appsv1.SchemeGroupVersion.WithResource("replicasets"), true},
+	{"Ingress", networkingv1.SchemeGroupVersion.WithResource("ingresses"), true},
+	{"Job", batchv1.SchemeGroupVersion.WithResource("jobs"), true},
+	{"CronJob", batchv1.SchemeGroupVersion.WithResource("cronjobs"), true},
+	{"HorizontalPodAutoscaler", autoscalingv2.SchemeGroupVersion.WithResource("horizontalpodautoscalers"), true},
+}
+
+// informerScope is one SharedInformerFactory and the informers started from it.
+// An unscoped cache has a single cluster-wide scope; a namespace-scoped cache has a
+// cluster scope for Nodes and Namespaces plus one scope per watched namespace.
+type informerScope struct {
+	namespace string // Empty for the cluster scope
+	factory   informers.SharedInformerFactory
+	informers map[string]cache.SharedIndexInformer // By resource, e.g. "pods"
+	stopCh    chan struct{}
+}
+
+func (s *informerScope) stop() {
+	close(s.stopCh)
+	s.factory.Shutdown()
+}
+
+// enabledKinds returns the typed kinds this cache runs, namespaced or cluster-scoped
+func (c *ResourceCache) enabledKinds(namespaced bool) []typedKind {
+	var kinds []typedKind
+	for _, k := range typedKinds {
+		if k.namespaced != namespaced {
+			continue
+		}
+		switch k.kind {
+		case "Secret":
+			if !c.secretsEnabled {
+				continue
+			}
+		case "CronJob":
+			if !c.cronJobEnabled {
+				continue
+			}
+		case "HorizontalPodAutoscaler":
+			if !c.hpaEnabled {
+				continue
+			}
+		}
+		kinds = append(kinds, k)
+	}
+	return kinds
+}
+
+// startScope creates informers for the given kinds in a namespace ("" = all), registers
+// change handlers and starts them. The returned targets are used to wait for sync.
+func (c *ResourceCache) startScope(namespace string, kinds []typedKind) (*informerScope, []namedInformer, error) {
+	opts := []informers.SharedInformerOption{informers.WithTransform(dropManagedFields)}
+	if namespace != "" {
+		opts = append(opts, informers.WithNamespace(namespace))
+	}
+	s := &informerScope{
+		namespace: namespace,
+		factory:   informers.NewSharedInformerFactoryWithOptions(k8sClient, 0, opts...),
+		informers: make(map[string]cache.SharedIndexInformer),
+		stopCh:    make(chan struct{}),
+	}
+
+	var targets []namedInformer
+	var standalone []cache.SharedIndexInformer // Not owned by the factory
+	for _, k := range kinds {
+		var inf cache.SharedIndexInformer
+		if k.kind == "Secret" && c.secretMetadataOnly {
+			inf = newSecretMetadataInformer(c.metadataClient, namespace)
+			standalone = append(standalone, inf)
+		} else {
+			generic, err := s.factory.ForResource(k.gvr)
+			if err != nil {
+				return nil, nil, fmt.Errorf("no informer for %s: %w", k.gvr.Resource, err)
+			}
+			inf = generic.Informer()
+		}
+		if indexers := indexersFor(k.kind); indexers != nil {
+			if err := inf.AddIndexers(indexers); err != nil {
+				return nil, nil, fmt.Errorf("failed to add %s indexers: %w", k.kind, err)
+			}
+		}
+
+		var err error
+		if k.kind == "Event" {
+			err = addK8sEventHandlers(inf, c.changes) // K8s Events get special handling
+		} else {
+			err = addChangeHandlers(inf, k.kind, c.changes)
+		}
+		if err != nil {
+			return nil, nil, explorerErrors.Wrap(explorerErrors.ErrCacheHandlerFailed,
+				"failed to register event handlers", err)
+		}
+
+		s.informers[k.gvr.Resource] = inf
+		name := k.kind
+		if namespace != "" {
+			name += " (" + namespace + ")"
+		}
+		targets = append(targets, namedInformer{name, inf})
+	}
+
+	s.factory.Start(s.stopCh)
+	for _, inf := range standalone {
+		go inf.Run(s.stopCh)
+	}
+	return s, targets, nil
+}
+
+// indexer returns the store for a resource, merged across scopes when namespace-scoped
+func (c *ResourceCache) indexer(resource string) cache.Indexer {
+	c.scopeMu.RLock()
+	defer c.scopeMu.RUnlock()
+	var indexers []cache.Indexer
+	for _, s := range c.scopes {
+		if inf, ok := s.informers[resource]; ok {
+			indexers = append(indexers, inf.GetIndexer())
+		}
+	}
+	if len(indexers) == 1 {
+		return indexers[0]
+	}
+	return multiIndexer(indexers)
+}
+
+// ForEachTypedObject calls fn with every object in the typed cache, kind by kind
+func (c *ResourceCache) ForEachTypedObject(fn func(kind string, gvr schema.GroupVersionResource, obj any)) {
+	if c == nil {
+		return
+	}
+	for _, k := range typedKinds {
+		if !c.HasTypedInformer(k.kind) {
+			continue
+		}
+		for _, obj := range c.indexer(k.gvr.Resource).List() {
+			fn(k.kind, k.gvr, obj)
+		}
+	}
+}
+
+// TypedObjects returns the cached objects of a typed kind (e.g. "Deployment"), in one
+// namespace or all of them. The bool is false when the typed cache doesn't watch the kind.
+func (c *ResourceCache) TypedObjects(kind, namespace string) ([]metav1.Object, bool) {
+	if c == nil || !c.HasTypedInformer(kind) {
+		return nil, false
+	}
+	for _, k := range typedKinds {
+		if !strings.EqualFold(k.kind, kind) {
+			continue
+		}
+		idx := c.indexer(k.gvr.Resource)
+		var items []any
+		if namespace != "" && k.namespaced {
+			items, _ = idx.ByIndex(cache.NamespaceIndex, namespace)
+		} else {
+			items = idx.List()
+		}
+		objs := make([]metav1.Object, 0, len(items))
+		for _, item := range items {
+			if obj, err := meta.Accessor(item); err == nil {
+				objs = append(objs, obj)
+			}
+		}
+		return objs, true
+	}
+	return nil, false
+}
+
+// IsNamespaceScoped reports whether the typed cache only watches a subset of namespaces
+func (c *ResourceCache) IsNamespaceScoped() bool {
+	return c != nil && c.namespaceScoped
+}
+
+// WatchedNamespaces returns the namespaces the typed cache watches (nil when cluster-wide)
+func (c *ResourceCache) WatchedNamespaces() []string {
+	if !c.IsNamespaceScoped() {
+		return nil
+	}
+	c.scopeMu.RLock()
+	defer c.scopeMu.RUnlock()
+	namespaces := make([]string, 0, len(c.scopes))
+	for ns := range c.scopes {
+		if ns != "" {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// SetWatchedNamespaces changes the namespaces a namespace-scoped cache watches, starting
+// informers for added namespaces (waiting for them to sync) and stopping removed ones.
+func (c *ResourceCache) SetWatchedNamespaces(namespaces []string) error {
+	if !c.IsNamespaceScoped() {
+		return explorerErrors.New(explorerErrors.ErrConflict,
+			"the cache watches all namespaces; start Radar with --namespaces to scope it")
+	}
+	want := make(map[string]bool)
+	for _, ns := range namespaces {
+		if errs := validation.IsDNS1123Label(ns); len(errs) > 0 {
+			return explorerErrors.New(explorerErrors.ErrBadRequest, fmt.Sprintf("invalid namespace %q: %s", ns, errs[0]))
+		}
+		want[ns] = true
+	}
+	if len(want) == 0 {
+		return explorerErrors.New(explorerErrors.ErrBadRequest, "at least one namespace is required")
+	}
+
+	c.scopeChangeMu.Lock()
+	defer c.scopeChangeMu.Unlock()
+
+	c.scopeMu.RLock()
+	var added []string
+	for ns := range want {
+		if _, ok := c.scopes[ns]; !ok {
+			added = append(added, ns)
+		}
+	}
+	var removed []*informerScope
+	for ns, s := range c.scopes {
+		if ns != "" && !want[ns] {
+			removed = append(removed, s)
+		}
+	}
+	c.scopeMu.RUnlock()
+	sort.Strings(added)
+
+	// Start and sync new scopes before exposing them, so listers never see a half-synced namespace
+	started := make(map[string]*informerScope)
+	for _, ns := range added {
+		s, targets, err := c.startScope(ns, c.enabledKinds(true))
+		if err == nil && !waitForScopeSync(targets) {
+			s.stop()
+			err = explorerErrors.New(explorerErrors.ErrCacheSyncFailed,
+				fmt.Sprintf("informers for namespace %q did not sync within %s (check RBAC)", ns, scopeSyncTimeout))
+		}
+		if err != nil {
+			for _, s := range started {
+				s.stop()
+			}
+			return err
+		}
+		started[ns] = s
+	}
+
+	c.scopeMu.Lock()
+	for ns, s := range started {
+		c.scopes[ns] = s
+	}
+	for _, s := range removed {
+		delete(c.scopes, s.namespace)
+	}
+	c.scopeMu.Unlock()
+
+	for _, s := range removed {
+		s.stop()
+	}
+	if len(added) > 0 || len(removed) > 0 {
+		log.Printf("Watched namespaces changed: +%d -%d, now %v", len(added), len(removed), c.WatchedNamespaces())
+	}
+	return nil
+}
+
+// waitForScopeSync waits for a runtime-added scope, giving up after scopeSyncTimeout
+func waitForScopeSync(targets []namedInformer) bool {
+	abort := make(chan struct{})
+	timer := time.AfterFunc(scopeSyncTimeout, func() { close(abort) })
+	defer timer.Stop()
+
+	synced := make([]cache.InformerSynced, len(targets))
+	for i, t := range targets {
+		synced[i] = t.informer.HasSynced
+	}
+	return cache.WaitForCacheSync(abort, synced...)
+}
+
+// multiIndexer is a read-only view over the stores of several namespace scopes.
+// Listers only read, so writes are unsupported.
+type multiIndexer []cache.Indexer
+
+func (m multiIndexer) List() []any {
+	var out []any
+	for _, idx := range m {
+		out = append(out, idx.List()...)
+	}
+	return out
+}
+
+func (m multiIndexer) ListKeys() []string {
+	var out []string
+	for _, idx := range m {
+		out = append(out, idx.ListKeys()...)
+	}
+	return out
+}
+
+func (m multiIndexer) Get(obj any) (any, bool, error) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		return nil, false, err
+	}
+	return m.GetByKey(key)
+}
+
+func (m multiIndexer) GetByKey(key string) (any, bool, error) {
+	for _, idx := range m {
+		if item, exists, err := idx.GetByKey(key); err != nil || exists {
+			return item, exists, err
+		}
+	}
+	return nil, false, nil
+}
+
+func (m multiIndexer) Index(indexName string, obj any) ([]any, error) {
+	var out []any
+	for _, idx := range m {
+		items, err := idx.Index(indexName, obj)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, items...)
+	}
+	return out, nil
+}
+
+func (m multiIndexer) IndexKeys(indexName, indexedValue string) ([]string, error) {
+	var out []string
+	for _, idx := range m {
+		keys, err := idx.IndexKeys(indexName, indexedValue)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, keys...)
+	}
+	return out, nil
+}
+
+func (m multiIndexer) ListIndexFuncValues(indexName string) []string {
+	var out []string
+	for _, idx := range m {
+		out = append(out, idx.ListIndexFuncValues(indexName)...)
+	}
+	return out
+}
+
+func (m multiIndexer) ByIndex(indexName, indexedValue string) ([]any, error) {
+	var out []any
+	for _, idx := range m {
+		items, err := idx.ByIndex(indexName, indexedValue)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, items...)
+	}
+	return out, nil
+}
+
+func (m multiIndexer) GetIndexers() cache.Indexers {
+	if len(m) == 0 {
+		return cache.Indexers{}
+	}
+	return m[0].GetIndexers()
+}
+
+var errReadOnlyIndexer = fmt.Errorf("namespace-scoped cache view is read-only")
+
+func (m multiIndexer) Add(any) error                    { return errReadOnlyIndexer }
+func (m multiIndexer) Update(any) error                 { return errReadOnlyIndexer }
+func (m multiIndexer) Delete(any) error                 { return errReadOnlyIndexer }
+func (m multiIndexer) Replace([]any, string) error      { return errReadOnlyIndexer }
+func (m multiIndexer) Resync() error                    { return errReadOnlyIndexer }
+func (m multiIndexer) AddIndexers(cache.Indexers) error { return errReadOnlyIndexer }
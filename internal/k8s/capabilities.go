@@ -16,6 +16,17 @@ type Capabilities struct {
 	Logs        bool `json:"logs"`        // Can get pods/log (log viewer)
 	PortForward bool `json:"portForward"` // Can create pods/portforward
 	Secrets     bool `json:"secrets"`     // Can list secrets
+	NodeShell   bool `json:"nodeShell"`   // Node shell enabled on the server (set by the server, not RBAC)
+	// DebugContainers can patch pods/ephemeralcontainers (debug terminal for shell-less images)
+	DebugContainers bool     `json:"debugContainers"`
+	DebugImages     []string `json:"debugImages,omitempty"` // Images offered for debug containers (set by the server)
+
+	SecretsMetadataOnly bool `json:"secretsMetadataOnly,omitempty"` // Secrets are cached without values (--secrets=metadata)
+	ExecRecorded        bool `json:"execRecorded,omitempty"`        // Terminal sessions are recorded (--exec-audit)
+
+	// Unavailable explains each unavailable feature, keyed like the fields above plus
+	// cluster-dependent features (metrics, gatewayApi, argoRollouts, debugContainers)
+	Unavailable map[string]CapabilityExplainer `json:"unavailable,omitempty"`
 }
 
 var (
@@ -54,9 +65,9 @@ func CheckCapabilities(ctx context.Context) (*Capabilities, error) {
 
 	// Check each capability in parallel using local variables to avoid data race
 	var wg sync.WaitGroup
-	var execAllowed, logsAllowed, portForwardAllowed, secretsAllowed bool
+	var execAllowed, logsAllowed, portForwardAllowed, secretsAllowed, debugAllowed bool
 
-	wg.Add(4)
+	wg.Add(5)
 
 	go func() {
 		defer wg.Done()
@@ -78,14 +89,20 @@ func CheckCapabilities(ctx context.Context) (*Capabilities, error) {
 		secretsAllowed = canI(ctx, "", "secrets", "list")
 	}()
 
+	go func() {
+		defer wg.Done()
+		debugAllowed = canI(ctx, "", "pods/ephemeralcontainers", "patch")
+	}()
+
 	wg.Wait()
 
 	// Build capabilities struct after all goroutines complete
 	caps := &Capabilities{
-		Exec:        execAllowed,
-		Logs:        logsAllowed,
-		PortForward: portForwardAllowed,
-		Secrets:     secretsAllowed,
+		Exec:            execAllowed,
+		Logs:            logsAllowed,
+		PortForward:     portForwardAllowed,
+		Secrets:         secretsAllowed,
+		DebugContainers: debugAllowed,
 	}
 
 	// Cache the result
@@ -95,6 +112,34 @@ func CheckCapabilities(ctx context.Context) (*Capabilities, error) {
 	return caps, nil
 }
 
+// CheckCapabilitiesFor checks the capabilities of another user via SubjectAccessReview,
+// so features can be gated on the caller's RBAC rather than Radar's service account.
+// Checks that can't be evaluated count as denied (fail closed).
+func CheckCapabilitiesFor(ctx context.Context, subject *ImpersonationSubject) (*Capabilities, error) {
+	results, err := CheckPermissions(ctx, []PermissionCheck{
+		{Verb: "create", Resource: "pods", Subresource: "exec"},
+		{Verb: "get", Resource: "pods", Subresource: "log"},
+		{Verb: "create", Resource: "pods", Subresource: "portforward"},
+		{Verb: "list", Resource: "secrets"},
+		{Verb: "patch", Resource: "pods", Subresource: "ephemeralcontainers"},
+	}, subject)
+	if err != nil {
+		return nil, err
+	}
+	for _, res := range results {
+		if res.Error != "" {
+			log.Printf("Warning: capability check for %s failed: %s", subject.User, res.Error)
+		}
+	}
+	return &Capabilities{
+		Exec:            results[0].Allowed,
+		Logs:            results[1].Allowed,
+		PortForward:     results[2].Allowed,
+		Secrets:         results[3].Allowed,
+		DebugContainers: results[4].Allowed,
+	}, nil
+}
+
 // canI checks if the current user/service account can perform an action
 func canI(ctx context.Context, namespace, resource, verb string) bool {
 	k8sClient := GetClient()
@@ -128,4 +173,5 @@ func InvalidateCapabilitiesCache() {
 	capabilitiesMu.Lock()
 	defer capabilitiesMu.Unlock()
 	cachedCapabilities = nil
+	invalidatePermissionCache()
 }
@@ -0,0 +1,141 @@
+package k8s
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CapabilityExplainer says why a feature is unavailable and how to enable it, so the UI
+// can show the fix instead of an empty panel
+type CapabilityExplainer struct {
+	Reason     string           `json:"reason"`
+	Permission *PermissionCheck `json:"permission,omitempty"` // Missing RBAC permission
+	Component  string           `json:"component,omitempty"`  // Missing cluster component or API group
+	Fix        string           `json:"fix,omitempty"`        // Command that enables the feature
+	Manifest   string           `json:"manifest,omitempty"`   // Manifest to apply instead of Fix
+	DocsURL    string           `json:"docsUrl,omitempty"`
+}
+
+// rbacFeature is a feature gated on one RBAC permission
+type rbacFeature struct {
+	id         string
+	name       string
+	permission PermissionCheck
+	helmValue  string // Radar chart value granting the permission
+}
+
+var rbacFeatures = []rbacFeature{
+	{"exec", "Terminal", PermissionCheck{Verb: "create", Resource: "pods", Subresource: "exec"}, "rbac.podExec"},
+	{"logs", "Log viewer", PermissionCheck{Verb: "get", Resource: "pods", Subresource: "log"}, "rbac.podLogs"},
+	{"portForward", "Port forwarding", PermissionCheck{Verb: "create", Resource: "pods", Subresource: "portforward"}, "rbac.portForward"},
+	{"secrets", "Secrets", PermissionCheck{Verb: "list", Resource: "secrets"}, "rbac.secrets"},
+	{"debugContainers", "Debug containers", PermissionCheck{Verb: "patch", Resource: "pods", Subresource: "ephemeralcontainers"}, "rbac.podDebug"},
+}
+
+// ExplainCapabilities returns an explainer for each unavailable feature, keyed like the
+// Capabilities fields. subject is the user whose RBAC gates features (nil for Radar's own
+// identity); features may be nil if detection hasn't run.
+func ExplainCapabilities(caps *Capabilities, features *ClusterFeatures, subject *ImpersonationSubject) map[string]CapabilityExplainer {
+	out := make(map[string]CapabilityExplainer)
+	allowed := map[string]bool{
+		"exec":            caps.Exec,
+		"logs":            caps.Logs,
+		"portForward":     caps.PortForward,
+		"secrets":         caps.Secrets,
+		"debugContainers": caps.DebugContainers,
+	}
+	for _, f := range rbacFeatures {
+		if !allowed[f.id] {
+			out[f.id] = explainMissingPermission(f, subject)
+		}
+	}
+
+	if features == nil {
+		return out
+	}
+	if !features.MetricsAPI {
+		out["metrics"] = CapabilityExplainer{
+			Reason:    "CPU and memory usage need the metrics.k8s.io API, which isn't served by this cluster",
+			Component: "metrics-server",
+			Fix:       "kubectl apply -f https://github.com/kubernetes-sigs/metrics-server/releases/latest/download/components.yaml",
+			DocsURL:   "https://github.com/kubernetes-sigs/metrics-server#installation",
+		}
+	}
+	if !features.GatewayAPI {
+		out["gatewayApi"] = CapabilityExplainer{
+			Reason:    "Gateway and HTTPRoute resources need the Gateway API CRDs (gateway.networking.k8s.io), which aren't installed",
+			Component: "gateway.networking.k8s.io",
+			Fix:       "kubectl apply -f https://github.com/kubernetes-sigs/gateway-api/releases/latest/download/standard-install.yaml",
+			DocsURL:   "https://gateway-api.sigs.k8s.io/guides/#installing-gateway-api",
+		}
+	}
+	if !features.ServesGroup("argoproj.io") {
+		out["argoRollouts"] = CapabilityExplainer{
+			Reason:    "Rollout resources need the Argo Rollouts CRDs (argoproj.io), which aren't installed",
+			Component: "argoproj.io",
+			Fix:       "kubectl create namespace argo-rollouts && kubectl apply -n argo-rollouts -f https://github.com/argoproj/argo-rollouts/releases/latest/download/install.yaml",
+			DocsURL:   "https://argo-rollouts.readthedocs.io/en/stable/installation/",
+		}
+	}
+	if !features.EphemeralContainers {
+		out["debugContainers"] = CapabilityExplainer{
+			Reason:    fmt.Sprintf("Debug containers need ephemeral containers, GA in Kubernetes 1.25 (this cluster runs %s)", features.GitVersion),
+			Component: "Kubernetes 1.25+",
+			DocsURL:   "https://kubernetes.io/docs/concepts/workloads/pods/ephemeral-containers/",
+		}
+	}
+	return out
+}
+
+// explainMissingPermission builds the explainer for a feature whose permission is denied.
+// The fix depends on whose RBAC is missing: the signed-in user's, the chart-installed
+// ServiceAccount's, or the local kubeconfig user's.
+func explainMissingPermission(f rbacFeature, subject *ImpersonationSubject) CapabilityExplainer {
+	p := f.permission
+	resource := p.Resource
+	if p.Subresource != "" {
+		resource += "/" + p.Subresource
+	}
+	role := "radar-" + strings.ToLower(f.id)
+	e := CapabilityExplainer{
+		Permission: &p,
+		Manifest: fmt.Sprintf(`apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: %s
+rules:
+  - apiGroups: [""]
+    resources: ["%s"]
+    verbs: ["%s"]
+`, role, resource, p.Verb),
+		DocsURL: "https://kubernetes.io/docs/reference/access-authn-authz/rbac/",
+	}
+
+	switch {
+	case subject != nil:
+		e.Reason = fmt.Sprintf("%s needs permission to %s %s, which user %s doesn't have", f.name, p.Verb, resource, subject.User)
+		e.Fix = fmt.Sprintf("kubectl create clusterrole %s --verb=%s --resource=%s && kubectl create clusterrolebinding %s-%s --clusterrole=%s --user=%s",
+			role, p.Verb, resource, role, rbacNameSuffix(subject.User), role, subject.User)
+	case IsInCluster():
+		e.Reason = fmt.Sprintf("%s needs permission to %s %s, which Radar's ServiceAccount doesn't have", f.name, p.Verb, resource)
+		e.Fix = fmt.Sprintf("helm upgrade radar skyhook/radar -n radar --reuse-values --set %s=true", f.helmValue)
+		e.DocsURL = "https://github.com/skyhook-io/radar/blob/main/docs/in-cluster.md"
+	default:
+		e.Reason = fmt.Sprintf("%s needs permission to %s %s, which your kubeconfig user doesn't have", f.name, p.Verb, resource)
+		e.Fix = fmt.Sprintf("kubectl auth can-i %s %s  # ask a cluster admin to grant it", p.Verb, resource)
+	}
+	return e
+}
+
+// rbacNameSuffix makes a user name usable in an object name
+func rbacNameSuffix(user string) string {
+	return strings.Trim(strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' {
+			return r
+		}
+		if r >= 'A' && r <= 'Z' {
+			return r + ('a' - 'A')
+		}
+		return '-'
+	}, user), "-")
+}
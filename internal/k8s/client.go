@@ -13,7 +13,6 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
-	"k8s.io/client-go/util/homedir"
 )
 
 var (
@@ -23,8 +22,8 @@ var (
 	dynamicClient   dynamic.Interface
 	initOnce        sync.Once
 	initErr         error
-	kubeconfigPath  string
-	kubeconfigPaths []string // Multiple kubeconfig paths when using --kubeconfig-dir
+	kubeconfigPath  string   // The kubeconfig file, when there's exactly one
+	kubeconfigPaths []string // Every kubeconfig file loaded (from a path list or --kubeconfig-dir)
 	contextName     string
 	clusterName     string
 	// clientMu protects access to client variables during context switches.
@@ -34,8 +33,10 @@ var (
 
 // InitOptions configures the K8s client initialization
 type InitOptions struct {
-	KubeconfigPath string
+	KubeconfigPath string   // File, directory or path list like KUBECONFIG
 	KubeconfigDirs []string // Directories containing kubeconfig files
+	SkipInCluster  bool     // Ignore the in-cluster config (replay always uses its generated kubeconfig)
+	ContentType    string   // Overrides the request encoding (replay's API server only speaks JSON)
 }
 
 // Initialize initializes the K8s client with the given options
@@ -58,11 +59,11 @@ func doInit(opts InitOptions) error {
 	var err error
 
 	// Try in-cluster config first (for when running inside a pod)
-	config, err = rest.InClusterConfig()
-	if err != nil {
+	if !opts.SkipInCluster {
+		config, err = rest.InClusterConfig()
+	}
+	if opts.SkipInCluster || err != nil {
 		// Fall back to kubeconfig (for local development / CLI usage)
-		var loadingRules *clientcmd.ClientConfigLoadingRules
-
 		if len(opts.KubeconfigDirs) > 0 {
 			// Multi-kubeconfig mode: discover and merge configs from directories
 			configs, err := discoverKubeconfigs(opts.KubeconfigDirs)
@@ -74,24 +75,24 @@ func doInit(opts InitOptions) error {
 			}
 			log.Printf("Discovered %d kubeconfig files from %d directories", len(configs), len(opts.KubeconfigDirs))
 			kubeconfigPaths = configs
-			loadingRules = &clientcmd.ClientConfigLoadingRules{Precedence: configs}
 		} else {
-			// Single kubeconfig mode (existing behavior)
-			kubeconfig := opts.KubeconfigPath
-			if kubeconfig == "" {
-				kubeconfig = os.Getenv("KUBECONFIG")
-			}
-			if kubeconfig == "" {
-				if home := homedir.HomeDir(); home != "" {
-					kubeconfig = filepath.Join(home, ".kube", "config")
+			kubeconfigPaths = resolveKubeconfigPaths(opts.KubeconfigPath)
+			if len(kubeconfigPaths) == 0 {
+				source := opts.KubeconfigPath
+				if source == "" {
+					source = os.Getenv("KUBECONFIG")
 				}
+				return fmt.Errorf("no kubeconfig files found in %s", source)
 			}
-			kubeconfigPath = kubeconfig
-			loadingRules = &clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfig}
+		}
+		if len(kubeconfigPaths) == 1 {
+			kubeconfigPath = kubeconfigPaths[0]
 		}
 
-		configOverrides := &clientcmd.ConfigOverrides{}
-		kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, configOverrides)
+		kubeConfig, err := kubeconfigLoader(&clientcmd.ConfigOverrides{})
+		if err != nil {
+			return err
+		}
 
 		// Get raw config to extract context/cluster names
 		rawConfig, err := kubeConfig.RawConfig()
@@ -104,7 +105,7 @@ func doInit(opts InitOptions) error {
 
 		config, err = kubeConfig.ClientConfig()
 		if err != nil {
-			if len(kubeconfigPaths) > 0 {
+			if len(kubeconfigPaths) > 1 {
 				return fmt.Errorf("failed to build kubeconfig from %d files: %w", len(kubeconfigPaths), err)
 			}
 			return fmt.Errorf("failed to build kubeconfig from %s: %w", kubeconfigPath, err)
@@ -115,7 +116,11 @@ func doInit(opts InitOptions) error {
 		clusterName = "in-cluster"
 	}
 
+	if opts.ContentType != "" {
+		config.ContentType = opts.ContentType
+	}
 	k8sConfig = config
+	logProxy(config)
 
 	k8sClient, err = kubernetes.NewForConfig(config)
 	if err != nil {
@@ -206,7 +211,8 @@ func GetDynamicClient() dynamic.Interface {
 	return dynamicClient
 }
 
-// GetKubeconfigPath returns the path to the kubeconfig file used
+// GetKubeconfigPath returns the path to the kubeconfig file used ("" when several are
+// merged; see GetKubeconfigPaths)
 func GetKubeconfigPath() string {
 	clientMu.RLock()
 	defer clientMu.RUnlock()
@@ -229,7 +235,7 @@ func GetClusterName() string {
 
 // IsInCluster returns true if running inside a Kubernetes cluster
 func IsInCluster() bool {
-	return kubeconfigPath == "" && len(kubeconfigPaths) == 0
+	return len(kubeconfigPaths) == 0
 }
 
 // ContextInfo represents information about a kubeconfig context
@@ -239,6 +245,7 @@ type ContextInfo struct {
 	User      string `json:"user"`
 	Namespace string `json:"namespace"`
 	IsCurrent bool   `json:"isCurrent"`
+	File      string `json:"file,omitempty"` // Kubeconfig file defining it, when several are merged
 }
 
 // GetAvailableContexts returns all available contexts from the kubeconfig
@@ -256,22 +263,11 @@ func GetAvailableContexts() ([]ContextInfo, error) {
 		}, nil
 	}
 
-	var loadingRules *clientcmd.ClientConfigLoadingRules
-	if len(kubeconfigPaths) > 0 {
-		// Multi-kubeconfig mode
-		loadingRules = &clientcmd.ClientConfigLoadingRules{Precedence: kubeconfigPaths}
-	} else {
-		// Single kubeconfig mode
-		kubeconfig := kubeconfigPath
-		if kubeconfig == "" {
-			return nil, fmt.Errorf("kubeconfig path not set")
-		}
-		loadingRules = &clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfig}
+	kubeConfig, err := kubeconfigLoader(&clientcmd.ConfigOverrides{})
+	if err != nil {
+		return nil, err
 	}
 
-	configOverrides := &clientcmd.ConfigOverrides{}
-	kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, configOverrides)
-
 	rawConfig, err := kubeConfig.RawConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
@@ -287,13 +283,17 @@ func GetAvailableContexts() ([]ContextInfo, error) {
 
 	contexts := make([]ContextInfo, 0, len(rawConfig.Contexts))
 	for name, ctx := range rawConfig.Contexts {
-		contexts = append(contexts, ContextInfo{
+		info := ContextInfo{
 			Name:      name,
 			Cluster:   ctx.Cluster,
 			User:      ctx.AuthInfo,
 			Namespace: ctx.Namespace,
 			IsCurrent: name == currentCtx,
-		})
+		}
+		if len(kubeconfigPaths) > 1 {
+			info.File = ctx.LocationOfOrigin
+		}
+		contexts = append(contexts, info)
 	}
 
 	return contexts, nil
@@ -306,22 +306,11 @@ func SwitchContext(name string) error {
 		return fmt.Errorf("cannot switch context when running in-cluster")
 	}
 
-	var loadingRules *clientcmd.ClientConfigLoadingRules
-	if len(kubeconfigPaths) > 0 {
-		// Multi-kubeconfig mode
-		loadingRules = &clientcmd.ClientConfigLoadingRules{Precedence: kubeconfigPaths}
-	} else {
-		// Single kubeconfig mode
-		kubeconfig := kubeconfigPath
-		if kubeconfig == "" {
-			return fmt.Errorf("kubeconfig path not set")
-		}
-		loadingRules = &clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfig}
-	}
-
 	// Build config with the new context
-	configOverrides := &clientcmd.ConfigOverrides{CurrentContext: name}
-	kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, configOverrides)
+	kubeConfig, err := kubeconfigLoader(&clientcmd.ConfigOverrides{CurrentContext: name})
+	if err != nil {
+		return err
+	}
 
 	// Verify the context exists
 	rawConfig, err := kubeConfig.RawConfig()
@@ -340,6 +329,8 @@ func SwitchContext(name string) error {
 		return fmt.Errorf("failed to build config for context %q: %w", name, err)
 	}
 
+	logProxy(config)
+
 	// Create new clients
 	newK8sClient, err := kubernetes.NewForConfig(config)
 	if err != nil {
@@ -0,0 +1,453 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/skyhook-io/radar/internal/timeline"
+)
+
+// ConsistencyCheckInterval is how often cross-resource consistency checks run
+const ConsistencyCheckInterval = 30 * time.Second
+
+// ConsistencyFinding is a cross-resource reference that doesn't resolve
+// (e.g. a Service selecting no pods or an Ingress pointing at a missing Service)
+type ConsistencyFinding struct {
+	Kind      string    `json:"kind"`
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Check     string    `json:"check"`    // service-selector, ingress-backend, hpa-target, pdb-selector, replica-conflict
+	Severity  string    `json:"severity"` // error, warning
+	Reason    string    `json:"reason"`   // Stable short description (used for problem identity)
+	Message   string    `json:"message"`
+	FirstSeen time.Time `json:"firstSeen"`
+
+	// Events are the timeline events the finding was inferred from. Such findings are
+	// explained on the timeline by an analyzer event, whose ID is TimelineEventID.
+	Events          []string `json:"events,omitempty"`
+	TimelineEventID string   `json:"timelineEventId,omitempty"`
+}
+
+// ID identifies the finding across runs (the CorrelationID of its timeline event)
+func (f ConsistencyFinding) ID() string {
+	return "consistency/" + f.key()
+}
+
+func (f ConsistencyFinding) key() string {
+	return f.Kind + "/" + f.Namespace + "/" + f.Name + "/" + f.Check + "/" + f.Reason
+}
+
+// ConsistencyChecker periodically validates references between resources.
+// Findings persist across runs (keeping FirstSeen) and are dropped as soon as
+// a run no longer detects them.
+type ConsistencyChecker struct {
+	mu       sync.RWMutex
+	findings map[string]ConsistencyFinding
+	lastRun  time.Time
+	stopCh   chan struct{}
+	wg       sync.WaitGroup
+}
+
+var (
+	consistencyChecker     *ConsistencyChecker
+	consistencyCheckerOnce sync.Once
+)
+
+// InitConsistencyChecker starts the background consistency checker
+func InitConsistencyChecker() {
+	consistencyCheckerOnce.Do(func() {
+		consistencyChecker = &ConsistencyChecker{
+			findings: make(map[string]ConsistencyFinding),
+			stopCh:   make(chan struct{}),
+		}
+		consistencyChecker.wg.Add(1)
+		go consistencyChecker.runLoop()
+		log.Println("Consistency checker started")
+	})
+}
+
+// GetConsistencyChecker returns the consistency checker (nil if not started)
+func GetConsistencyChecker() *ConsistencyChecker {
+	return consistencyChecker
+}
+
+// StopConsistencyChecker stops the background checks
+func StopConsistencyChecker() {
+	if consistencyChecker != nil {
+		close(consistencyChecker.stopCh)
+		consistencyChecker.wg.Wait()
+		log.Println("Consistency checker stopped")
+	}
+}
+
+// clearConsistencyFindings drops findings from the previous cluster (on context switch)
+func clearConsistencyFindings() {
+	if c := consistencyChecker; c != nil {
+		c.mu.Lock()
+		c.findings = make(map[string]ConsistencyFinding)
+		c.lastRun = time.Time{}
+		c.mu.Unlock()
+	}
+}
+
+// Findings returns the current findings, optionally filtered by namespace
+func (c *ConsistencyChecker) Findings(namespace string) []ConsistencyFinding {
+	if c == nil {
+		return nil
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	result := make([]ConsistencyFinding, 0, len(c.findings))
+	for _, f := range c.findings {
+		if namespace != "" && f.Namespace != namespace {
+			continue
+		}
+		result = append(result, f)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].key() < result[j].key() })
+	return result
+}
+
+// LastRun returns when the checks last completed
+func (c *ConsistencyChecker) LastRun() time.Time {
+	if c == nil {
+		return time.Time{}
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.lastRun
+}
+
+func (c *ConsistencyChecker) runLoop() {
+	defer c.wg.Done()
+
+	// Initial run
+	c.Run()
+
+	ticker := time.NewTicker(ConsistencyCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stopCh:
+			return
+		case <-ticker.C:
+			c.Run()
+		}
+	}
+}
+
+// Run executes all checks once and reconciles the finding set
+func (c *ConsistencyChecker) Run() {
+	cache := GetResourceCache()
+	if cache == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var detected []ConsistencyFinding
+	detected = append(detected, checkServiceSelectors(cache)...)
+	detected = append(detected, checkIngressBackends(cache)...)
+	detected = append(detected, checkHPATargets(cache)...)
+	detected = append(detected, checkPDBSelectors(ctx, cache)...)
+	detected = append(detected, checkReplicaConflicts(ctx, cache)...)
+
+	now := time.Now()
+	next := make(map[string]ConsistencyFinding, len(detected))
+	var explain []timeline.TimelineEvent
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, f := range detected {
+		k := f.key()
+		if prev, ok := c.findings[k]; ok {
+			f.FirstSeen = prev.FirstSeen
+			f.TimelineEventID = prev.TimelineEventID
+		} else {
+			f.FirstSeen = now
+			if len(f.Events) > 0 {
+				event := timeline.NewAnalyzerEvent(f.Kind, f.Namespace, f.Name, f.Reason, f.Message, f.ID())
+				f.TimelineEventID = event.ID
+				explain = append(explain, event)
+			}
+			if DebugEvents {
+				log.Printf("[DEBUG] Consistency finding: %s %s/%s: %s", f.Kind, f.Namespace, f.Name, f.Message)
+			}
+		}
+		next[k] = f
+	}
+	if len(explain) > 0 {
+		if err := timeline.RecordEventsWithBroadcast(ctx, explain); err != nil {
+			log.Printf("Warning: failed to record consistency findings on timeline: %v", err)
+		}
+	}
+	if DebugEvents {
+		for k, f := range c.findings {
+			if _, ok := next[k]; !ok {
+				log.Printf("[DEBUG] Consistency finding resolved: %s %s/%s: %s", f.Kind, f.Namespace, f.Name, f.Reason)
+			}
+		}
+	}
+	c.findings = next
+	c.lastRun = now
+}
+
+// checkServiceSelectors finds Services whose selector matches no pods
+func checkServiceSelectors(cache *ResourceCache) []ConsistencyFinding {
+	services, err := cache.Services().List(labels.Everything())
+	if err != nil {
+		return nil
+	}
+
+	var findings []ConsistencyFinding
+	for _, svc := range services {
+		// Services without a selector are backed by manually managed endpoints
+		if len(svc.Spec.Selector) == 0 || svc.Spec.Type == corev1.ServiceTypeExternalName {
+			continue
+		}
+		if len(cache.PodsWithLabels(svc.Namespace, svc.Spec.Selector)) > 0 {
+			continue
+		}
+		findings = append(findings, ConsistencyFinding{
+			Kind:      "Service",
+			Namespace: svc.Namespace,
+			Name:      svc.Name,
+			Check:     "service-selector",
+			Severity:  "warning",
+			Reason:    "Selector matches no pods",
+			Message:   fmt.Sprintf("Selector %s matches no pods in namespace %s", labels.SelectorFromSet(svc.Spec.Selector), svc.Namespace),
+		})
+	}
+	return findings
+}
+
+// checkIngressBackends finds Ingress backends referencing missing Services or ports
+func checkIngressBackends(cache *ResourceCache) []ConsistencyFinding {
+	ingresses, err := cache.Ingresses().List(labels.Everything())
+	if err != nil {
+		return nil
+	}
+
+	var findings []ConsistencyFinding
+	for _, ing := range ingresses {
+		var backends []*networkingv1.IngressServiceBackend
+		if ing.Spec.DefaultBackend != nil && ing.Spec.DefaultBackend.Service != nil {
+			backends = append(backends, ing.Spec.DefaultBackend.Service)
+		}
+		for _, rule := range ing.Spec.Rules {
+			if rule.HTTP == nil {
+				continue
+			}
+			for _, path := range rule.HTTP.Paths {
+				if path.Backend.Service != nil {
+					backends = append(backends, path.Backend.Service)
+				}
+			}
+		}
+
+		seen := make(map[string]bool)
+		for _, b := range backends {
+			port := b.Port.Name
+			if port == "" {
+				port = fmt.Sprintf("%d", b.Port.Number)
+			}
+			ref := b.Name + ":" + port
+			if seen[ref] {
+				continue
+			}
+			seen[ref] = true
+
+			svc, err := cache.Services().Services(ing.Namespace).Get(b.Name)
+			if err != nil {
+				findings = append(findings, ConsistencyFinding{
+					Kind:      "Ingress",
+					Namespace: ing.Namespace,
+					Name:      ing.Name,
+					Check:     "ingress-backend",
+					Severity:  "error",
+					Reason:    "Backend service " + b.Name + " not found",
+					Message:   fmt.Sprintf("Backend references Service %s which does not exist", b.Name),
+				})
+				continue
+			}
+			if !serviceHasPort(svc, b.Port) {
+				findings = append(findings, ConsistencyFinding{
+					Kind:      "Ingress",
+					Namespace: ing.Namespace,
+					Name:      ing.Name,
+					Check:     "ingress-backend",
+					Severity:  "error",
+					Reason:    "Backend port " + ref + " not found",
+					Message:   fmt.Sprintf("Service %s has no port %s", b.Name, port),
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// serviceHasPort reports whether a Service exposes the given backend port
+func serviceHasPort(svc *corev1.Service, port networkingv1.ServiceBackendPort) bool {
+	for _, p := range svc.Spec.Ports {
+		if port.Name != "" && p.Name == port.Name {
+			return true
+		}
+		if port.Name == "" && p.Port == port.Number {
+			return true
+		}
+	}
+	return false
+}
+
+// checkHPATargets finds HPAs targeting missing workloads, or resource-based HPAs
+// targeting workloads whose containers have no requests for that resource
+func checkHPATargets(cache *ResourceCache) []ConsistencyFinding {
+	if !cache.HasTypedInformer("HorizontalPodAutoscaler") {
+		return nil
+	}
+	hpas, err := cache.HorizontalPodAutoscalers().List(labels.Everything())
+	if err != nil {
+		return nil
+	}
+
+	var findings []ConsistencyFinding
+	for _, hpa := range hpas {
+		ref := hpa.Spec.ScaleTargetRef
+		template, found, known := hpaTargetTemplate(cache, hpa.Namespace, ref)
+		if !known {
+			// Custom scalable resources (e.g. Argo Rollouts) aren't checked
+			continue
+		}
+		if !found {
+			findings = append(findings, ConsistencyFinding{
+				Kind:      "HorizontalPodAutoscaler",
+				Namespace: hpa.Namespace,
+				Name:      hpa.Name,
+				Check:     "hpa-target",
+				Severity:  "error",
+				Reason:    "Scale target not found",
+				Message:   fmt.Sprintf("Scale target %s/%s does not exist", ref.Kind, ref.Name),
+			})
+			continue
+		}
+
+		for _, resource := range hpaResourceMetrics(hpa) {
+			if missing := containersWithoutRequest(template, resource); len(missing) > 0 {
+				findings = append(findings, ConsistencyFinding{
+					Kind:      "HorizontalPodAutoscaler",
+					Namespace: hpa.Namespace,
+					Name:      hpa.Name,
+					Check:     "hpa-target",
+					Severity:  "warning",
+					Reason:    "Target has no " + string(resource) + " requests",
+					Message: fmt.Sprintf("%s/%s containers %v have no %s request; utilization cannot be computed",
+						ref.Kind, ref.Name, missing, resource),
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// hpaTargetTemplate resolves an HPA scale target to its pod template.
+// known is false for kinds the checker doesn't resolve.
+func hpaTargetTemplate(cache *ResourceCache, namespace string, ref autoscalingv2.CrossVersionObjectReference) (tmpl *corev1.PodTemplateSpec, found bool, known bool) {
+	switch ref.Kind {
+	case "Deployment":
+		if d, err := cache.Deployments().Deployments(namespace).Get(ref.Name); err == nil {
+			return &d.Spec.Template, true, true
+		}
+	case "StatefulSet":
+		if s, err := cache.StatefulSets().StatefulSets(namespace).Get(ref.Name); err == nil {
+			return &s.Spec.Template, true, true
+		}
+	case "ReplicaSet":
+		if rs, err := cache.ReplicaSets().ReplicaSets(namespace).Get(ref.Name); err == nil {
+			return &rs.Spec.Template, true, true
+		}
+	default:
+		return nil, false, false
+	}
+	return nil, false, true
+}
+
+// hpaResourceMetrics returns resources used with utilization targets (which require requests)
+func hpaResourceMetrics(hpa *autoscalingv2.HorizontalPodAutoscaler) []corev1.ResourceName {
+	var resources []corev1.ResourceName
+	for _, m := range hpa.Spec.Metrics {
+		switch {
+		case m.Type == autoscalingv2.ResourceMetricSourceType && m.Resource != nil &&
+			m.Resource.Target.Type == autoscalingv2.UtilizationMetricType:
+			resources = append(resources, m.Resource.Name)
+		case m.Type == autoscalingv2.ContainerResourceMetricSourceType && m.ContainerResource != nil &&
+			m.ContainerResource.Target.Type == autoscalingv2.UtilizationMetricType:
+			resources = append(resources, m.ContainerResource.Name)
+		}
+	}
+	return resources
+}
+
+// containersWithoutRequest lists containers missing a request for the resource
+func containersWithoutRequest(tmpl *corev1.PodTemplateSpec, resource corev1.ResourceName) []string {
+	var missing []string
+	for _, c := range tmpl.Spec.Containers {
+		if _, ok := c.Resources.Requests[resource]; !ok {
+			missing = append(missing, c.Name)
+		}
+	}
+	return missing
+}
+
+// checkPDBSelectors finds PodDisruptionBudgets that select no pods.
+// PDBs have no typed informer, so they're read through the dynamic cache.
+func checkPDBSelectors(ctx context.Context, cache *ResourceCache) []ConsistencyFinding {
+	items, err := cache.ListDynamic(ctx, "PodDisruptionBudget", "")
+	if err != nil {
+		return nil
+	}
+
+	var findings []ConsistencyFinding
+	for _, item := range items {
+		var pdb policyv1.PodDisruptionBudget
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &pdb); err != nil {
+			continue
+		}
+		if pdb.Spec.Selector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil || selector.Empty() {
+			continue
+		}
+		pods, err := cache.Pods().Pods(pdb.Namespace).List(selector)
+		if err != nil || len(pods) > 0 {
+			continue
+		}
+		findings = append(findings, ConsistencyFinding{
+			Kind:      "PodDisruptionBudget",
+			Namespace: pdb.Namespace,
+			Name:      pdb.Name,
+			Check:     "pdb-selector",
+			Severity:  "warning",
+			Reason:    "Selector matches no pods",
+			Message:   fmt.Sprintf("Selector %s matches no pods in namespace %s", selector, pdb.Namespace),
+		})
+	}
+	return findings
+}
@@ -155,6 +155,11 @@ func PerformContextSwitch(newContext string) error {
 
 	log.Println("Stopping resource discovery...")
 	ResetResourceDiscovery()
+	ResetFeatureDetection()
+	resetTemplateChanges()
+	clearConsistencyFindings()
+	clearUsageSamples()
+	clearPrinterColumns()
 
 	// Reset timeline store if registered
 	contextSwitchMu.RLock()
@@ -193,6 +198,9 @@ func PerformContextSwitch(newContext string) error {
 	// Invalidate capabilities cache - RBAC permissions may differ between clusters
 	InvalidateCapabilitiesCache()
 
+	// Metrics history is per cluster; load the new one's persisted samples, if any
+	resetMetricsHistory()
+
 	// Step 2.5: Test connectivity before proceeding with cache initialization
 	// This prevents hanging if the cluster is unreachable
 	reportProgress("Testing cluster connectivity...")
@@ -204,6 +212,11 @@ func PerformContextSwitch(newContext string) error {
 	}
 	log.Println("Cluster connectivity verified")
 
+	// Detect API versions before starting informers, which depend on them
+	if err := ReinitFeatureDetection(); err != nil {
+		log.Printf("Warning: feature detection failed: %v", err)
+	}
+
 	// Step 3: Reinitialize all caches with new client
 	// Order matters: typed cache first (provides change channel), then dynamic cache
 	reportProgress("Loading workloads...")
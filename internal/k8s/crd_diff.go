@@ -0,0 +1,331 @@
+package k8s
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// maxGenericSummaryParts caps how many changes a generic diff summary lists
+const maxGenericSummaryParts = 3
+
+// defaultDiffRules are the field paths summarized for common operator CRDs. Rules set
+// with SetDiffRules replace these per kind.
+var defaultDiffRules = map[string][]string{
+	// cert-manager
+	"Certificate": {".spec.dnsNames", ".spec.secretName", ".spec.issuerRef.name", ".status.conditions[Ready]", ".status.notAfter"},
+	"Issuer":      {".status.conditions[Ready]"},
+	// Istio
+	"VirtualService":  {".spec.hosts", ".spec.gateways", ".spec.http", ".spec.tcp", ".spec.tls"},
+	"DestinationRule": {".spec.host", ".spec.trafficPolicy", ".spec.subsets"},
+	// Argo
+	"Rollout":     {".spec.replicas", ".spec.template.spec.containers[*].image", ".spec.paused", ".status.phase", ".status.currentStepIndex"},
+	"Application": {".status.sync.status", ".status.sync.revision", ".status.health.status"},
+	// Flux
+	"Kustomization": {".spec.suspend", ".status.lastAppliedRevision", ".status.conditions[Ready]"},
+	"HelmRelease":   {".spec.suspend", ".spec.chart.spec.version", ".status.lastAppliedRevision", ".status.conditions[Ready]"},
+	// KEDA
+	"ScaledObject": {".spec.minReplicaCount", ".spec.maxReplicaCount", ".status.conditions[Ready]", ".status.conditions[Active]"},
+}
+
+var (
+	diffRulesMu sync.RWMutex
+	diffRules   = mustParseDiffRules(defaultDiffRules)
+)
+
+// SetDiffRules sets which field paths are compared when summarizing updates to custom
+// resources, keyed by kind. Paths look like .spec.replicas, .status.conditions[Ready]
+// (the list entry whose type or name is Ready) or .spec.containers[*].image. Kinds
+// without rules fall back to a generic spec and status comparison.
+func SetDiffRules(rules map[string][]string) error {
+	parsed, err := parseDiffRules(rules)
+	if err != nil {
+		return err
+	}
+	merged := mustParseDiffRules(defaultDiffRules)
+	for kind, paths := range parsed {
+		merged[kind] = paths
+	}
+	diffRulesMu.Lock()
+	diffRules = merged
+	diffRulesMu.Unlock()
+	return nil
+}
+
+// ValidateDiffRules checks rule paths without applying them
+func ValidateDiffRules(rules map[string][]string) error {
+	_, err := parseDiffRules(rules)
+	return err
+}
+
+func parseDiffRules(rules map[string][]string) (map[string][]fieldPath, error) {
+	parsed := make(map[string][]fieldPath, len(rules))
+	for kind, paths := range rules {
+		for _, p := range paths {
+			fp, err := parseFieldPath(p)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", kind, err)
+			}
+			parsed[kind] = append(parsed[kind], fp)
+		}
+	}
+	return parsed, nil
+}
+
+func mustParseDiffRules(rules map[string][]string) map[string][]fieldPath {
+	parsed, err := parseDiffRules(rules)
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+// pathStep is one step of a field path: a map field, a list index, a list entry selected
+// by its type/name, or every list entry
+type pathStep struct {
+	field    string
+	index    int
+	selector string
+	all      bool
+	isList   bool
+}
+
+// fieldPath is a parsed diff rule path
+type fieldPath struct {
+	steps []pathStep
+}
+
+// parseFieldPath parses a JSONPath-like field path (.a.b[0].c, .a[Name], .a[*].b)
+func parseFieldPath(s string) (fieldPath, error) {
+	var fp fieldPath
+	rest := strings.TrimPrefix(strings.TrimSpace(s), ".")
+	if rest == "" {
+		return fp, fmt.Errorf("empty field path")
+	}
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return fp, fmt.Errorf("unclosed [ in %q", s)
+			}
+			sel := strings.Trim(rest[1:end], `'"`)
+			rest = rest[end+1:]
+			if len(fp.steps) == 0 || sel == "" {
+				return fp, fmt.Errorf("invalid list selector in %q", s)
+			}
+			step := pathStep{isList: true}
+			if sel == "*" {
+				step.all = true
+			} else if i, err := strconv.Atoi(sel); err == nil {
+				step.index = i
+			} else {
+				step.selector = sel
+			}
+			fp.steps = append(fp.steps, step)
+		default:
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			fp.steps = append(fp.steps, pathStep{field: rest[:end]})
+			rest = rest[end:]
+		}
+	}
+	return fp, nil
+}
+
+// pathValue is a value found at a field path, with the concrete path it was found at
+type pathValue struct {
+	path  string // e.g. status.conditions[Ready]
+	label string // short name for summaries, e.g. Ready or image(app)
+	value any
+}
+
+// lookup returns the values at the path. [*] expands to one value per list entry,
+// keyed by the entry's type or name when it has one.
+func (fp fieldPath) lookup(obj map[string]any) map[string]pathValue {
+	out := make(map[string]pathValue)
+	var walk func(v any, steps []pathStep, path, label, parent string)
+	walk = func(v any, steps []pathStep, path, label, parent string) {
+		if len(steps) == 0 {
+			out[path] = pathValue{path: path, label: label, value: conditionStatus(v)}
+			return
+		}
+		step := steps[0]
+		if !step.isList {
+			m, ok := v.(map[string]any)
+			if !ok {
+				return
+			}
+			child, ok := m[step.field]
+			if !ok {
+				return
+			}
+			next := step.field
+			switch {
+			case label != "" && parent == "list":
+				next = fmt.Sprintf("%s(%s)", step.field, label) // image(app)
+			case step.field == "status" && label != "":
+				next = label + ".status" // sync.status, health.status
+			}
+			walk(child, steps[1:], joinPath(path, step.field), next, "field")
+			return
+		}
+		list, ok := v.([]any)
+		if !ok {
+			return
+		}
+		for i, item := range list {
+			key := entryKey(item)
+			switch {
+			case step.all:
+			case step.selector != "":
+				if key != step.selector {
+					continue
+				}
+			case step.index != i:
+				continue
+			}
+			if key == "" {
+				key = strconv.Itoa(i)
+			}
+			walk(item, steps[1:], fmt.Sprintf("%s[%s]", path, key), key, "list")
+		}
+	}
+	walk(obj, fp.steps, "", "", "")
+	return out
+}
+
+func joinPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+// entryKey identifies a list entry by its type (conditions) or name (containers, ports)
+func entryKey(item any) string {
+	m, ok := item.(map[string]any)
+	if !ok {
+		return ""
+	}
+	for _, k := range []string{"type", "name"} {
+		if s, ok := m[k].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// conditionStatus reduces a condition to its status, so timestamp and message churn on an
+// unchanged condition isn't reported. Other values are returned as is.
+func conditionStatus(v any) any {
+	if m, ok := v.(map[string]any); ok {
+		if _, isCondition := m["type"].(string); isCondition {
+			if status, ok := m["status"]; ok {
+				return status
+			}
+		}
+	}
+	return v
+}
+
+// diffUnstructured summarizes an update to a custom resource using the rules for its kind,
+// or a generic spec/status comparison when there are none
+func diffUnstructured(kind string, oldObj, newObj any) ([]FieldChange, []string) {
+	oldU, ok1 := oldObj.(*unstructured.Unstructured)
+	newU, ok2 := newObj.(*unstructured.Unstructured)
+	if !ok1 || !ok2 {
+		return nil, nil
+	}
+
+	diffRulesMu.RLock()
+	rules, ok := diffRules[kind]
+	diffRulesMu.RUnlock()
+	if !ok {
+		rules = genericDiffRules
+	}
+
+	var changes []FieldChange
+	var summary []string
+	for _, rule := range rules {
+		// A whole spec is compared field by field, like ComputeObjectDiff
+		if len(rule.steps) == 1 && rule.steps[0].field == "spec" {
+			var specChanges []FieldChange
+			diffValues("spec", oldU.Object["spec"], newU.Object["spec"], &specChanges)
+			for _, c := range specChanges {
+				changes = append(changes, c)
+				summary = append(summary, summarizeChange(c.Path[strings.LastIndex(c.Path, ".")+1:], c.OldValue, c.NewValue))
+			}
+			continue
+		}
+
+		oldVals, newVals := rule.lookup(oldU.Object), rule.lookup(newU.Object)
+		paths := make([]string, 0, len(oldVals)+len(newVals))
+		for p := range oldVals {
+			paths = append(paths, p)
+		}
+		for p := range newVals {
+			if _, ok := oldVals[p]; !ok {
+				paths = append(paths, p)
+			}
+		}
+		sort.Strings(paths)
+		for _, p := range paths {
+			o, n := oldVals[p], newVals[p]
+			if reflect.DeepEqual(o.value, n.value) {
+				continue
+			}
+			label := n.label
+			if label == "" {
+				label = o.label
+			}
+			changes = append(changes, FieldChange{Path: p, OldValue: o.value, NewValue: n.value})
+			summary = append(summary, summarizeChange(label, o.value, n.value))
+		}
+	}
+
+	if len(summary) > maxGenericSummaryParts {
+		more := len(summary) - maxGenericSummaryParts
+		summary = append(summary[:maxGenericSummaryParts], fmt.Sprintf("+%d more", more))
+	}
+	return changes, summary
+}
+
+// genericDiffRules apply to custom resources without rules: every spec field, the
+// status phase and each condition's status
+var genericDiffRules = mustParseDiffRules(map[string][]string{"": {
+	".spec", ".status.phase", ".status.conditions[*]",
+}})[""]
+
+// summarizeChange renders one change as "label: old→new", or "label changed" for
+// lists and objects
+func summarizeChange(label string, oldVal, newVal any) string {
+	o, okOld := summaryValue(oldVal)
+	n, okNew := summaryValue(newVal)
+	if !okOld || !okNew {
+		return label + " changed"
+	}
+	return fmt.Sprintf("%s: %s→%s", label, o, n)
+}
+
+func summaryValue(v any) (string, bool) {
+	switch val := v.(type) {
+	case nil:
+		return "none", true
+	case string:
+		return truncateImage(val), true
+	case bool, int64, float64, int32, int:
+		return fmt.Sprint(val), true
+	default:
+		return "", false
+	}
+}
@@ -3,6 +3,7 @@ package k8s
 import (
 	"fmt"
 	"log"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -25,7 +26,7 @@ type APIResource struct {
 type ResourceDiscovery struct {
 	resources   []APIResource
 	resourceMap map[string]APIResource // keyed by lowercase kind
-	gvrMap      map[string]schema.GroupVersionResource
+	kindGroups  map[string][]string    // lowercase kind -> groups defining it
 	lastRefresh time.Time
 	cacheTTL    time.Duration
 	mu          sync.RWMutex
@@ -64,7 +65,6 @@ func InitResourceDiscovery() error {
 	discoveryOnce.Do(func() {
 		resourceDiscovery = &ResourceDiscovery{
 			resourceMap: make(map[string]APIResource),
-			gvrMap:      make(map[string]schema.GroupVersionResource),
 			cacheTTL:    5 * time.Minute,
 		}
 		initErr = resourceDiscovery.refresh()
@@ -113,7 +113,7 @@ func (d *ResourceDiscovery) refresh() error {
 
 	d.resources = nil
 	d.resourceMap = make(map[string]APIResource)
-	d.gvrMap = make(map[string]schema.GroupVersionResource)
+	d.kindGroups = make(map[string][]string)
 
 	for _, apiList := range apiResourceLists {
 		if apiList == nil {
@@ -149,31 +149,31 @@ func (d *ResourceDiscovery) refresh() error {
 
 			// Store in map by lowercase kind for lookup
 			kindKey := strings.ToLower(apiRes.Kind)
-			// Prefer non-CRD resources if there's a conflict (unlikely)
+			if !slices.Contains(d.kindGroups[kindKey], gv.Group) {
+				d.kindGroups[kindKey] = append(d.kindGroups[kindKey], gv.Group)
+			}
+			// Prefer non-CRD resources if there's a conflict. Bare lookups of a kind two
+			// CRDs define return the first discovered; use Kind.group to pick another.
 			if existing, ok := d.resourceMap[kindKey]; !ok || (!isCRD && existing.IsCRD) {
 				d.resourceMap[kindKey] = resource
-				d.gvrMap[kindKey] = schema.GroupVersionResource{
-					Group:    gv.Group,
-					Version:  gv.Version,
-					Resource: apiRes.Name,
-				}
 			}
 
 			// Also store by plural name (lowercase)
 			nameKey := strings.ToLower(apiRes.Name)
 			if existing, ok := d.resourceMap[nameKey]; !ok || (!isCRD && existing.IsCRD) {
 				d.resourceMap[nameKey] = resource
-				d.gvrMap[nameKey] = schema.GroupVersionResource{
-					Group:    gv.Group,
-					Version:  gv.Version,
-					Resource: apiRes.Name,
-				}
 			}
 		}
 	}
 
 	d.lastRefresh = time.Now()
 	log.Printf("Discovered %d API resources (%d unique kinds)", len(d.resources), len(d.resourceMap)/2)
+	for kind, groups := range d.kindGroups {
+		if len(groups) > 1 {
+			res := d.resourceMap[kind]
+			log.Printf("Kind %s is defined by groups %s; %q refers to %s", res.Kind, strings.Join(groups, ", "), res.Kind, QualifiedKind(res.Kind, res.Group))
+		}
+	}
 
 	return nil
 }
@@ -203,17 +203,18 @@ func (d *ResourceDiscovery) GetAPIResources() ([]APIResource, error) {
 	return result, nil
 }
 
-// GetGVR returns the GroupVersionResource for a given kind or plural name
+// GetGVR returns the GroupVersionResource for a given kind or plural name, optionally
+// qualified with its group ("Application.argoproj.io", "applications.argoproj.io")
 func (d *ResourceDiscovery) GetGVR(kindOrName string) (schema.GroupVersionResource, bool) {
 	if d == nil {
 		return schema.GroupVersionResource{}, false
 	}
 
-	d.mu.RLock()
-	defer d.mu.RUnlock()
-
-	gvr, ok := d.gvrMap[strings.ToLower(kindOrName)]
-	return gvr, ok
+	res, ok := d.GetResource(kindOrName)
+	if !ok {
+		return schema.GroupVersionResource{}, false
+	}
+	return schema.GroupVersionResource{Group: res.Group, Version: res.Version, Resource: res.Name}, true
 }
 
 // GetGVRWithGroup returns the GroupVersionResource for a kind with a specific API group
@@ -232,22 +233,26 @@ func (d *ResourceDiscovery) GetGVRWithGroup(kindOrName string, group string) (sc
 	d.mu.RLock()
 	defer d.mu.RUnlock()
 
-	// Search through all resources for matching kind/name AND group
+	res, ok := d.findInGroup(kindOrName, group)
+	if !ok {
+		return schema.GroupVersionResource{}, false
+	}
+	return schema.GroupVersionResource{Group: res.Group, Version: res.Version, Resource: res.Name}, true
+}
+
+// findInGroup searches for a kind or plural name in one API group; callers hold d.mu
+func (d *ResourceDiscovery) findInGroup(kindOrName, group string) (APIResource, bool) {
 	kindLower := strings.ToLower(kindOrName)
 	for _, res := range d.resources {
 		if (strings.ToLower(res.Kind) == kindLower || strings.ToLower(res.Name) == kindLower) && res.Group == group {
-			return schema.GroupVersionResource{
-				Group:    res.Group,
-				Version:  res.Version,
-				Resource: res.Name,
-			}, true
+			return res, true
 		}
 	}
-
-	return schema.GroupVersionResource{}, false
+	return APIResource{}, false
 }
 
-// GetResource returns the APIResource for a given kind or plural name
+// GetResource returns the APIResource for a given kind or plural name, optionally
+// qualified with its group
 func (d *ResourceDiscovery) GetResource(kindOrName string) (APIResource, bool) {
 	if d == nil {
 		return APIResource{}, false
@@ -256,8 +261,24 @@ func (d *ResourceDiscovery) GetResource(kindOrName string) (APIResource, bool) {
 	d.mu.RLock()
 	defer d.mu.RUnlock()
 
-	res, ok := d.resourceMap[strings.ToLower(kindOrName)]
-	return res, ok
+	if res, ok := d.resourceMap[strings.ToLower(kindOrName)]; ok {
+		return res, true
+	}
+	if kind, group := SplitQualifiedKind(kindOrName); group != "" {
+		return d.findInGroup(kind, group)
+	}
+	return APIResource{}, false
+}
+
+// getResourceForGVR returns the APIResource for a GVR, matching the group so resources
+// with the same plural name in different groups aren't confused
+func (d *ResourceDiscovery) getResourceForGVR(gvr schema.GroupVersionResource) (APIResource, bool) {
+	if d == nil {
+		return APIResource{}, false
+	}
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.findInGroup(gvr.Resource, gvr.Group)
 }
 
 // IsKnownResource checks if a kind or plural name is a known resource
@@ -279,6 +300,10 @@ func (d *ResourceDiscovery) SupportsWatch(kindOrName string) bool {
 	if !ok {
 		return false
 	}
+	return supportsListWatch(res)
+}
+
+func supportsListWatch(res APIResource) bool {
 	hasList := false
 	hasWatch := false
 	for _, verb := range res.Verbs {
@@ -294,15 +319,14 @@ func (d *ResourceDiscovery) SupportsWatch(kindOrName string) bool {
 
 // SupportsWatchGVR checks if a GVR supports list and watch verbs
 func (d *ResourceDiscovery) SupportsWatchGVR(gvr schema.GroupVersionResource) bool {
-	// Look up by plural resource name
-	return d.SupportsWatch(gvr.Resource)
+	res, ok := d.getResourceForGVR(gvr)
+	return ok && supportsListWatch(res)
 }
 
 // GetKindForGVR returns the Kind name for a given GVR
 // e.g., for GVR{Resource: "rollouts"}, returns "Rollout"
 func (d *ResourceDiscovery) GetKindForGVR(gvr schema.GroupVersionResource) string {
-	res, ok := d.GetResource(gvr.Resource)
-	if ok {
+	if res, ok := d.getResourceForGVR(gvr); ok {
 		return res.Kind
 	}
 	return ""
@@ -15,6 +15,7 @@ import (
 	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/tools/cache"
 
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
 	"github.com/skyhook-io/radar/internal/timeline"
 )
 
@@ -128,11 +129,14 @@ func (d *DynamicResourceCache) EnsureWatching(gvr schema.GroupVersionResource) e
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
-		if !cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+		syncStart := time.Now()
+		synced := cache.WaitForCacheSync(ctx.Done(), informer.HasSynced)
+		if !synced {
 			log.Printf("Warning: cache sync timeout for %v", gvr)
 		} else {
 			log.Printf("Dynamic resource synced: %s.%s/%s", gvr.Resource, gvr.Group, gvr.Version)
 		}
+		recordInformerSync(kind, true, time.Since(syncStart), len(informer.GetStore().ListKeys()), !synced)
 
 		// Mark this informer as sync complete - now we can record ADD events for it
 		d.mu.Lock()
@@ -183,6 +187,7 @@ func (d *DynamicResourceCache) addDynamicChangeHandlers(inf cache.SharedIndexInf
 // enqueueDynamicChange records a change and sends notification for dynamic (unstructured) resources
 func (d *DynamicResourceCache) enqueueDynamicChange(kind string, gvr schema.GroupVersionResource, obj any, oldObj any, op string) {
 	u, ok := obj.(*unstructured.Unstructured)
+	isTombstone := false
 	if !ok {
 		// Handle tombstone for deleted objects
 		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
@@ -190,6 +195,7 @@ func (d *DynamicResourceCache) enqueueDynamicChange(kind string, gvr schema.Grou
 			if !ok {
 				return
 			}
+			isTombstone = true
 		} else {
 			return
 		}
@@ -202,6 +208,10 @@ func (d *DynamicResourceCache) enqueueDynamicChange(kind string, gvr schema.Grou
 	// Track event received
 	timeline.IncrementReceived(kind)
 
+	// Raw event for the watch inspector (nil unless someone is inspecting)
+	raw := newRawWatchEvent("dynamic", kind, namespace, name, uid, op, u.GetResourceVersion(), isTombstone)
+	defer publishRawWatchEvent(raw)
+
 	// Skip ADD events during initial sync - they represent existing resources, not new creations
 	if op == "add" {
 		d.mu.RLock()
@@ -213,6 +223,7 @@ func (d *DynamicResourceCache) enqueueDynamicChange(kind string, gvr schema.Grou
 				log.Printf("[DEBUG] Skipping dynamic initial sync add event: %s/%s/%s", kind, namespace, name)
 			}
 			timeline.RecordDrop(kind, namespace, name, timeline.DropReasonAlreadySeen, op)
+			raw.addDrop(timeline.DropReasonAlreadySeen)
 			return
 		}
 	}
@@ -225,25 +236,38 @@ func (d *DynamicResourceCache) enqueueDynamicChange(kind string, gvr schema.Grou
 
 	// Record to timeline store
 	recordToTimelineStore(kind, namespace, name, uid, op, oldObj, obj)
+	if raw != nil {
+		raw.SentToTimeline = true
+	}
 
 	// Send to change channel for SSE if configured
 	if d.changes != nil {
 		change := ResourceChange{
 			Kind:      kind,
+			Group:     CustomGroup(gvr.Group),
 			Namespace: namespace,
 			Name:      name,
 			UID:       uid,
 			Operation: op,
 			Diff:      diff,
+			Labels:    u.GetLabels(),
+			Health:    timeline.DetermineHealthState(kind, u),
+		}
+		if op == "update" && oldObj != nil {
+			change.PrevHealth = timeline.DetermineHealthState(kind, oldObj)
 		}
 
 		// Non-blocking send
 		select {
 		case d.changes <- change:
+			if raw != nil {
+				raw.SentToChannel = true
+			}
 		default:
 			// Channel full, drop event
 			timeline.RecordDrop(kind, namespace, name,
 				timeline.DropReasonChannelFull, op)
+			raw.addDrop(timeline.DropReasonChannelFull)
 			if DebugEvents {
 				log.Printf("[DEBUG] Dynamic change channel full, dropped: %s/%s/%s op=%s", kind, namespace, name, op)
 			}
@@ -429,6 +453,9 @@ func (d *DynamicResourceCache) Get(gvr schema.GroupVersionResource, namespace, n
 	}
 
 	if !exists {
+		if !informer.HasSynced() {
+			return nil, explorerErrors.CacheNotSynced(gvr.Resource)
+		}
 		return nil, fmt.Errorf("resource not found: %s", key)
 	}
 
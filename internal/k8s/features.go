@@ -0,0 +1,211 @@
+package k8s
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ClusterFeatures describes API and version-dependent capabilities of the connected cluster.
+// It is detected once at startup (and again on context switch) so callers can gate
+// behavior on it instead of making their own version assumptions.
+type ClusterFeatures struct {
+	GitVersion string    `json:"gitVersion"`
+	Major      int       `json:"major"`
+	Minor      int       `json:"minor"`
+	DetectedAt time.Time `json:"detectedAt"`
+
+	// Preferred API group/versions for kinds that moved between versions ("" = not served)
+	CronJobAPI string `json:"cronJobApi"` // batch/v1 (1.21+) or batch/v1beta1
+	HPAAPI     string `json:"hpaApi"`     // autoscaling/v2 (1.23+), autoscaling/v2beta2, or autoscaling/v1
+	IngressAPI string `json:"ingressApi"` // networking.k8s.io/v1 (1.19+) or networking.k8s.io/v1beta1
+	PDBAPI     string `json:"pdbApi"`     // policy/v1 (1.21+) or policy/v1beta1
+
+	// Optional API groups
+	GatewayAPI        bool   `json:"gatewayApi"`
+	GatewayAPIVersion string `json:"gatewayApiVersion,omitempty"`
+	MetricsAPI        bool   `json:"metricsApi"`
+	EndpointSlices    bool   `json:"endpointSlices"`
+
+	// Version-gated features
+	EphemeralContainers bool `json:"ephemeralContainers"` // GA in 1.25
+	SidecarContainers   bool `json:"sidecarContainers"`   // Native sidecars, beta in 1.29
+	WatchListSupported  bool `json:"watchListSupported"`  // Streaming list via watch, beta in 1.32
+
+	// Groups maps each served API group to its served versions (preferred first)
+	Groups map[string][]string `json:"groups"`
+}
+
+var (
+	clusterFeatures *ClusterFeatures
+	featuresOnce    sync.Once
+	featuresMu      sync.RWMutex
+)
+
+// InitFeatureDetection inspects the server version and API groups of the current cluster
+func InitFeatureDetection() error {
+	var initErr error
+	featuresOnce.Do(func() {
+		features, err := detectFeatures()
+		if err != nil {
+			initErr = err
+			return
+		}
+		featuresMu.Lock()
+		clusterFeatures = features
+		featuresMu.Unlock()
+		log.Printf("Detected Kubernetes %s (cronjob=%s, hpa=%s, gateway=%v)",
+			features.GitVersion, features.CronJobAPI, features.HPAAPI, features.GatewayAPI)
+	})
+	return initErr
+}
+
+// GetFeatures returns the detected cluster features, or nil if detection hasn't run
+func GetFeatures() *ClusterFeatures {
+	featuresMu.RLock()
+	defer featuresMu.RUnlock()
+	return clusterFeatures
+}
+
+// ResetFeatureDetection clears detected features
+// This must be called before ReinitFeatureDetection when switching contexts
+func ResetFeatureDetection() {
+	featuresMu.Lock()
+	defer featuresMu.Unlock()
+	clusterFeatures = nil
+	featuresOnce = sync.Once{}
+}
+
+// ReinitFeatureDetection re-runs feature detection after a context switch
+// Must call ResetFeatureDetection first
+func ReinitFeatureDetection() error {
+	return InitFeatureDetection()
+}
+
+// detectFeatures queries the discovery API for version and group information
+func detectFeatures() (*ClusterFeatures, error) {
+	client := GetDiscoveryClient()
+	if client == nil {
+		return nil, fmt.Errorf("discovery client not initialized")
+	}
+
+	version, err := client.ServerVersion()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server version: %w", err)
+	}
+
+	groupList, err := client.ServerGroups()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server groups: %w", err)
+	}
+
+	groups := make(map[string][]string, len(groupList.Groups))
+	for _, g := range groupList.Groups {
+		versions := make([]string, 0, len(g.Versions))
+		// Preferred version first so callers can pick versions[0]
+		if g.PreferredVersion.Version != "" {
+			versions = append(versions, g.PreferredVersion.Version)
+		}
+		for _, v := range g.Versions {
+			if v.Version != g.PreferredVersion.Version {
+				versions = append(versions, v.Version)
+			}
+		}
+		groups[g.Name] = versions
+	}
+
+	f := &ClusterFeatures{
+		GitVersion: version.GitVersion,
+		Major:      parseVersionNumber(version.Major),
+		Minor:      parseVersionNumber(version.Minor),
+		DetectedAt: time.Now(),
+		Groups:     groups,
+	}
+	f.resolve()
+	return f, nil
+}
+
+// resolve derives the feature flags from version and group information
+func (f *ClusterFeatures) resolve() {
+	f.CronJobAPI = f.firstServed("batch", "v1", "v1beta1")
+	f.HPAAPI = f.firstServed("autoscaling", "v2", "v2beta2", "v1")
+	f.IngressAPI = f.firstServed("networking.k8s.io", "v1", "v1beta1")
+	f.PDBAPI = f.firstServed("policy", "v1", "v1beta1")
+
+	if versions, ok := f.Groups["gateway.networking.k8s.io"]; ok && len(versions) > 0 {
+		f.GatewayAPI = true
+		f.GatewayAPIVersion = "gateway.networking.k8s.io/" + versions[0]
+	}
+	f.MetricsAPI = f.ServesGroup("metrics.k8s.io")
+	f.EndpointSlices = f.ServesGroupVersion("discovery.k8s.io", "v1")
+
+	f.EphemeralContainers = f.AtLeast(1, 25)
+	f.SidecarContainers = f.AtLeast(1, 29)
+	f.WatchListSupported = f.AtLeast(1, 32)
+}
+
+// firstServed returns "group/version" for the first of the candidate versions that is served
+func (f *ClusterFeatures) firstServed(group string, versions ...string) string {
+	for _, v := range versions {
+		if f.ServesGroupVersion(group, v) {
+			return group + "/" + v
+		}
+	}
+	return ""
+}
+
+// AtLeast reports whether the server version is at least major.minor
+func (f *ClusterFeatures) AtLeast(major, minor int) bool {
+	if f == nil {
+		return false
+	}
+	if f.Major != major {
+		return f.Major > major
+	}
+	return f.Minor >= minor
+}
+
+// ServesGroup reports whether any version of the API group is served
+func (f *ClusterFeatures) ServesGroup(group string) bool {
+	if f == nil {
+		return false
+	}
+	_, ok := f.Groups[group]
+	return ok
+}
+
+// ServesGroupVersion reports whether a specific group/version is served.
+// The core group is addressed as "".
+func (f *ClusterFeatures) ServesGroupVersion(group, version string) bool {
+	if f == nil {
+		return false
+	}
+	for _, v := range f.Groups[group] {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+// HasCronJobV1 reports whether batch/v1 CronJobs are served.
+// Unknown (nil) features assume a modern cluster.
+func (f *ClusterFeatures) HasCronJobV1() bool {
+	return f == nil || f.CronJobAPI == "batch/v1"
+}
+
+// HasHPAV2 reports whether autoscaling/v2 HPAs are served.
+// Unknown (nil) features assume a modern cluster.
+func (f *ClusterFeatures) HasHPAV2() bool {
+	return f == nil || f.HPAAPI == "autoscaling/v2"
+}
+
+// parseVersionNumber parses version components like "28" or "28+" (GKE/EKS append "+")
+func parseVersionNumber(s string) int {
+	s = strings.TrimRight(s, "+")
+	n, _ := strconv.Atoi(s)
+	return n
+}
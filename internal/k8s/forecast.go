@@ -0,0 +1,418 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	// ForecastSampleInterval is how often quota and PVC usage is sampled
+	ForecastSampleInterval = 5 * time.Minute
+	// ForecastHistorySize is the number of samples kept per series (7 days at 5m intervals)
+	ForecastHistorySize = 7 * 24 * 12
+	// ForecastWarningHorizon is how close exhaustion must be to be flagged on the dashboard
+	ForecastWarningHorizon = 14 * 24 * time.Hour
+
+	// Minimum data needed before a trend is trusted
+	forecastMinSamples = 6
+	forecastMinSpan    = 30 * time.Minute
+)
+
+// UsageForecast predicts when a namespace quota or PVC runs out based on its recent trend
+type UsageForecast struct {
+	Type          string     `json:"type"` // quota, pvc
+	Namespace     string     `json:"namespace"`
+	Name          string     `json:"name"`               // ResourceQuota or PVC name
+	Resource      string     `json:"resource,omitempty"` // Quota resource (e.g. requests.memory); empty for PVCs
+	Used          float64    `json:"used"`
+	Limit         float64    `json:"limit"`
+	UsedPercent   float64    `json:"usedPercent"`
+	GrowthPerDay  float64    `json:"growthPerDay"` // Same unit as Used (bytes, cores, count)
+	ExhaustsAt    *time.Time `json:"exhaustsAt,omitempty"`
+	DaysRemaining *float64   `json:"daysRemaining,omitempty"` // nil = not trending toward the limit
+	Samples       int        `json:"samples"`
+	Message       string     `json:"message"`
+}
+
+// usageSample is a single usage observation
+type usageSample struct {
+	at    time.Time
+	used  float64
+	limit float64
+}
+
+// usageSeries is the sample history of one quota resource or PVC
+type usageSeries struct {
+	kind      string
+	namespace string
+	name      string
+	resource  string
+	samples   []usageSample
+	lastSeen  time.Time
+}
+
+// UsageForecaster samples quota and PVC usage and projects exhaustion dates
+type UsageForecaster struct {
+	mu      sync.RWMutex
+	series  map[string]*usageSeries
+	volumes map[string]pvcVolumeUsage // Latest kubelet stats by namespace/name
+	stopCh  chan struct{}
+	wg      sync.WaitGroup
+}
+
+var (
+	usageForecaster     *UsageForecaster
+	usageForecasterOnce sync.Once
+)
+
+// InitUsageForecaster starts periodic usage sampling
+func InitUsageForecaster() {
+	usageForecasterOnce.Do(func() {
+		usageForecaster = &UsageForecaster{
+			series: make(map[string]*usageSeries),
+			stopCh: make(chan struct{}),
+		}
+		usageForecaster.wg.Add(1)
+		go usageForecaster.sampleLoop()
+		log.Println("Usage forecasting started")
+	})
+}
+
+// GetUsageForecaster returns the forecaster (nil if not started)
+func GetUsageForecaster() *UsageForecaster {
+	return usageForecaster
+}
+
+// StopUsageForecaster stops sampling
+func StopUsageForecaster() {
+	if usageForecaster != nil {
+		close(usageForecaster.stopCh)
+		usageForecaster.wg.Wait()
+	}
+}
+
+// clearUsageSamples drops history from the previous cluster (on context switch)
+func clearUsageSamples() {
+	if f := usageForecaster; f != nil {
+		f.mu.Lock()
+		f.series = make(map[string]*usageSeries)
+		f.volumes = nil
+		f.mu.Unlock()
+	}
+}
+
+func (f *UsageForecaster) sampleLoop() {
+	defer f.wg.Done()
+
+	f.sample()
+
+	ticker := time.NewTicker(ForecastSampleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-f.stopCh:
+			return
+		case <-ticker.C:
+			f.sample()
+		}
+	}
+}
+
+// sample records current quota and PVC usage
+func (f *UsageForecaster) sample() {
+	cache := GetResourceCache()
+	if cache == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	now := time.Now()
+
+	quotas, err := cache.ResourceQuotas().List(labels.Everything())
+	if err == nil {
+		for _, q := range quotas {
+			for res, hard := range q.Status.Hard {
+				used, ok := q.Status.Used[res]
+				if !ok || hard.IsZero() {
+					continue
+				}
+				f.add("quota", q.Namespace, q.Name, string(res), now, used.AsApproximateFloat64(), hard.AsApproximateFloat64())
+			}
+		}
+	} else if DebugEvents {
+		log.Printf("[DEBUG] Forecast: failed to list resource quotas: %v", err)
+	}
+
+	volumes := make(map[string]pvcVolumeUsage)
+	for _, vol := range f.pvcUsage(ctx) {
+		vol.sampledAt = now
+		volumes[vol.namespace+"/"+vol.name] = vol
+		f.add("pvc", vol.namespace, vol.name, "", now, vol.used, vol.capacity)
+	}
+	f.mu.Lock()
+	f.volumes = volumes
+	f.mu.Unlock()
+
+	f.prune(now)
+}
+
+func (f *UsageForecaster) add(kind, namespace, name, resource string, at time.Time, used, limit float64) {
+	key := kind + "/" + namespace + "/" + name + "/" + resource
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	s, ok := f.series[key]
+	if !ok {
+		s = &usageSeries{kind: kind, namespace: namespace, name: name, resource: resource}
+		f.series[key] = s
+	}
+	s.samples = append(s.samples, usageSample{at: at, used: used, limit: limit})
+	if len(s.samples) > ForecastHistorySize {
+		s.samples = s.samples[len(s.samples)-ForecastHistorySize:]
+	}
+	s.lastSeen = at
+}
+
+// prune drops series that weren't seen in the latest sample (deleted quotas/PVCs)
+func (f *UsageForecaster) prune(now time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for key, s := range f.series {
+		if now.Sub(s.lastSeen) > 3*ForecastSampleInterval {
+			delete(f.series, key)
+		}
+	}
+}
+
+// pvcVolumeUsage is PVC usage reported by the kubelet
+type pvcVolumeUsage struct {
+	namespace  string
+	name       string
+	used       float64
+	capacity   float64
+	inodesUsed float64
+	inodes     float64
+	abnormal   bool // CSI volume health monitoring reported the volume as abnormal
+	sampledAt  time.Time
+}
+
+// kubeletStatsSummary is the subset of the kubelet /stats/summary response we need
+type kubeletStatsSummary struct {
+	Pods []struct {
+		Volumes []struct {
+			UsedBytes     *uint64 `json:"usedBytes"`
+			CapacityBytes *uint64 `json:"capacityBytes"`
+			InodesUsed    *uint64 `json:"inodesUsed"`
+			Inodes        *uint64 `json:"inodes"`
+			PVCRef        *struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"pvcRef"`
+			// Only reported when the CSI driver supports volume health (CSIVolumeHealth gate)
+			VolumeHealthStats *struct {
+				Abnormal bool `json:"abnormal"`
+			} `json:"volumeHealthStats"`
+		} `json:"volume"`
+	} `json:"pods"`
+}
+
+// pvcUsage reads PVC filesystem usage from each ready node's kubelet summary (via the API server proxy)
+func (f *UsageForecaster) pvcUsage(ctx context.Context) []pvcVolumeUsage {
+	cache := GetResourceCache()
+	client := GetClient()
+	if cache == nil || client == nil {
+		return nil
+	}
+	nodes, err := cache.Nodes().List(labels.Everything())
+	if err != nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var result []pvcVolumeUsage
+	for _, node := range nodes {
+		if !isNodeReady(node) {
+			continue
+		}
+		raw, err := client.CoreV1().RESTClient().Get().
+			Resource("nodes").
+			Name(node.Name).
+			SubResource("proxy", "stats", "summary").
+			DoRaw(ctx)
+		if err != nil {
+			if DebugEvents {
+				log.Printf("[DEBUG] Forecast: stats summary for node %s failed: %v", node.Name, err)
+			}
+			continue
+		}
+		var summary kubeletStatsSummary
+		if err := json.Unmarshal(raw, &summary); err != nil {
+			continue
+		}
+		for _, pod := range summary.Pods {
+			for _, v := range pod.Volumes {
+				if v.PVCRef == nil || v.UsedBytes == nil || v.CapacityBytes == nil || *v.CapacityBytes == 0 {
+					continue
+				}
+				// RWX volumes are reported once per mounting pod
+				key := v.PVCRef.Namespace + "/" + v.PVCRef.Name
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				vol := pvcVolumeUsage{
+					namespace: v.PVCRef.Namespace,
+					name:      v.PVCRef.Name,
+					used:      float64(*v.UsedBytes),
+					capacity:  float64(*v.CapacityBytes),
+					abnormal:  v.VolumeHealthStats != nil && v.VolumeHealthStats.Abnormal,
+				}
+				if v.InodesUsed != nil && v.Inodes != nil {
+					vol.inodesUsed, vol.inodes = float64(*v.InodesUsed), float64(*v.Inodes)
+				}
+				result = append(result, vol)
+			}
+		}
+	}
+	return result
+}
+
+func isNodeReady(node *corev1.Node) bool {
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == corev1.NodeReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// Forecasts returns exhaustion forecasts, soonest first, optionally for one namespace
+func (f *UsageForecaster) Forecasts(namespace string) []UsageForecast {
+	if f == nil {
+		return nil
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	now := time.Now()
+	result := make([]UsageForecast, 0, len(f.series))
+	for _, s := range f.series {
+		if namespace != "" && s.namespace != namespace {
+			continue
+		}
+		if fc, ok := forecastSeries(s, now); ok {
+			result = append(result, fc)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		di, dj := result[i].DaysRemaining, result[j].DaysRemaining
+		if (di == nil) != (dj == nil) {
+			return di != nil
+		}
+		if di != nil && *di != *dj {
+			return *di < *dj
+		}
+		return result[i].UsedPercent > result[j].UsedPercent
+	})
+	return result
+}
+
+// forecastSeries fits a linear trend to a series and projects when it reaches its limit
+func forecastSeries(s *usageSeries, now time.Time) (UsageForecast, bool) {
+	if len(s.samples) == 0 {
+		return UsageForecast{}, false
+	}
+	last := s.samples[len(s.samples)-1]
+
+	fc := UsageForecast{
+		Type:      s.kind,
+		Namespace: s.namespace,
+		Name:      s.name,
+		Resource:  s.resource,
+		Used:      last.used,
+		Limit:     last.limit,
+		Samples:   len(s.samples),
+	}
+	if last.limit > 0 {
+		fc.UsedPercent = math.Round(last.used/last.limit*1000) / 10
+	}
+	subject := forecastSubject(s)
+
+	if last.used >= last.limit {
+		zero := 0.0
+		fc.DaysRemaining = &zero
+		fc.ExhaustsAt = &last.at
+		fc.Message = fmt.Sprintf("%s is at its limit", subject)
+		return fc, true
+	}
+
+	span := last.at.Sub(s.samples[0].at)
+	if len(s.samples) < forecastMinSamples || span < forecastMinSpan {
+		fc.Message = fmt.Sprintf("%s: collecting data (%d samples)", subject, len(s.samples))
+		return fc, true
+	}
+
+	slopePerHour := linearSlope(s.samples)
+	fc.GrowthPerDay = slopePerHour * 24
+	if slopePerHour <= 0 {
+		fc.Message = fmt.Sprintf("%s is stable or shrinking", subject)
+		return fc, true
+	}
+
+	hours := (last.limit - last.used) / slopePerHour
+	days := math.Round(hours/24*10) / 10
+	at := now.Add(time.Duration(hours * float64(time.Hour)))
+	fc.DaysRemaining = &days
+	fc.ExhaustsAt = &at
+	fc.Message = fmt.Sprintf("%s will hit its limit in ~%s", subject, formatForecastHorizon(hours))
+	return fc, true
+}
+
+// linearSlope returns the least-squares slope of used over time, in units per hour
+func linearSlope(samples []usageSample) float64 {
+	n := float64(len(samples))
+	origin := samples[0].at
+	var sumX, sumY, sumXY, sumXX float64
+	for _, s := range samples {
+		x := s.at.Sub(origin).Hours()
+		sumX += x
+		sumY += s.used
+		sumXY += x * s.used
+		sumXX += x * x
+	}
+	denom := n*sumXX - sumX*sumX
+	if denom == 0 {
+		return 0
+	}
+	return (n*sumXY - sumX*sumY) / denom
+}
+
+func forecastSubject(s *usageSeries) string {
+	if s.kind == "pvc" {
+		return fmt.Sprintf("PVC %s/%s", s.namespace, s.name)
+	}
+	return fmt.Sprintf("namespace %s %s quota", s.namespace, s.resource)
+}
+
+func formatForecastHorizon(hours float64) string {
+	switch {
+	case hours < 1:
+		return fmt.Sprintf("%d minutes", int(hours*60))
+	case hours < 48:
+		return fmt.Sprintf("%d hours", int(math.Round(hours)))
+	default:
+		return fmt.Sprintf("%d days", int(math.Round(hours/24)))
+	}
+}
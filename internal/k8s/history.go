@@ -3,6 +3,8 @@ package k8s
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/skyhook-io/radar/internal/timeline"
@@ -11,6 +13,8 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // Type aliases - these types are defined in the timeline package
@@ -18,7 +22,8 @@ type OwnerInfo = timeline.OwnerInfo
 type DiffInfo = timeline.DiffInfo
 type FieldChange = timeline.FieldChange
 
-// ComputeDiff computes the diff between old and new objects based on kind
+// ComputeDiff computes the diff between old and new objects based on kind.
+// Custom resources (unstructured objects) are compared with the diff rules for their kind.
 // Returns nil if no meaningful changes detected or kind not supported
 func ComputeDiff(kind string, oldObj, newObj any) *DiffInfo {
 	var changes []FieldChange
@@ -50,7 +55,7 @@ func ComputeDiff(kind string, oldObj, newObj any) *DiffInfo {
 	case "PersistentVolumeClaim":
 		changes, summaryParts = diffPVC(oldObj, newObj)
 	default:
-		return nil
+		changes, summaryParts = diffUnstructured(kind, oldObj, newObj)
 	}
 
 	if len(changes) == 0 {
@@ -73,6 +78,105 @@ func ComputeDiff(kind string, oldObj, newObj any) *DiffInfo {
 	}
 }
 
+// ComputeObjectDiff returns every field that differs between two unstructured objects,
+// for any kind. Server-maintained bookkeeping (resourceVersion, generation, managedFields)
+// and status are skipped since edits don't set them. Lists of equal length are compared
+// element by element; otherwise the whole list is reported.
+func ComputeObjectDiff(oldObj, newObj map[string]any) []FieldChange {
+	var changes []FieldChange
+	diffValues("", oldObj, newObj, &changes)
+	return changes
+}
+
+// objectDiffIgnored are paths ComputeObjectDiff doesn't descend into
+var objectDiffIgnored = map[string]bool{
+	"metadata.resourceVersion":   true,
+	"metadata.generation":        true,
+	"metadata.managedFields":     true,
+	"metadata.creationTimestamp": true,
+	"metadata.uid":               true,
+	"status":                     true,
+}
+
+func diffValues(path string, oldVal, newVal any, changes *[]FieldChange) {
+	if objectDiffIgnored[path] {
+		return
+	}
+	switch o := oldVal.(type) {
+	case map[string]any:
+		if n, ok := newVal.(map[string]any); ok {
+			keys := make(map[string]bool, len(o)+len(n))
+			for k := range o {
+				keys[k] = true
+			}
+			for k := range n {
+				keys[k] = true
+			}
+			sorted := make([]string, 0, len(keys))
+			for k := range keys {
+				sorted = append(sorted, k)
+			}
+			sort.Strings(sorted)
+			for _, k := range sorted {
+				child := k
+				if path != "" {
+					child = path + "." + k
+				}
+				diffValues(child, o[k], n[k], changes)
+			}
+			return
+		}
+	case []any:
+		if n, ok := newVal.([]any); ok && len(n) == len(o) {
+			for i := range o {
+				diffValues(fmt.Sprintf("%s[%d]", path, i), o[i], n[i], changes)
+			}
+			return
+		}
+	}
+	if !reflect.DeepEqual(oldVal, newVal) {
+		*changes = append(*changes, FieldChange{Path: path, OldValue: oldVal, NewValue: newVal})
+	}
+}
+
+// typedForDiff converts an unstructured object to the typed object ComputeDiff expects.
+// Other kinds are returned as is and summarized with the custom resource diff rules.
+func typedForDiff(u *unstructured.Unstructured) any {
+	var typed any
+	switch u.GetKind() {
+	case "Deployment":
+		typed = &appsv1.Deployment{}
+	case "Pod":
+		typed = &corev1.Pod{}
+	case "Service":
+		typed = &corev1.Service{}
+	case "ConfigMap":
+		typed = &corev1.ConfigMap{}
+	case "Ingress":
+		typed = &networkingv1.Ingress{}
+	case "ReplicaSet":
+		typed = &appsv1.ReplicaSet{}
+	case "DaemonSet":
+		typed = &appsv1.DaemonSet{}
+	case "StatefulSet":
+		typed = &appsv1.StatefulSet{}
+	case "HorizontalPodAutoscaler":
+		typed = &autoscalingv2.HorizontalPodAutoscaler{}
+	case "Job":
+		typed = &batchv1.Job{}
+	case "Node":
+		typed = &corev1.Node{}
+	case "PersistentVolumeClaim":
+		typed = &corev1.PersistentVolumeClaim{}
+	default:
+		return u
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, typed); err != nil {
+		return nil
+	}
+	return typed
+}
+
 // diffDeployment computes diff for Deployment resources
 func diffDeployment(oldObj, newObj any) ([]FieldChange, []string) {
 	oldDep, ok1 := oldObj.(*appsv1.Deployment)
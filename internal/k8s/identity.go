@@ -0,0 +1,48 @@
+package k8s
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// IsBuiltinGroup reports whether an API group ships with Kubernetes. Kinds in these
+// groups are unique, so they are identified by kind alone.
+func IsBuiltinGroup(group string) bool {
+	return coreAPIGroups[group]
+}
+
+// QualifiedKind identifies a kind across API groups: "Deployment" for built-in kinds and
+// "Application.argoproj.io" for custom resources, so an Argo CD Application and a
+// Crossplane Application don't collide. Parse it back with SplitQualifiedKind.
+func QualifiedKind(kind, group string) string {
+	if IsBuiltinGroup(group) {
+		return kind
+	}
+	return kind + "." + group
+}
+
+// SplitQualifiedKind splits "Kind.group" (or kubectl's "plural.group") into kind and
+// group. Kinds never contain dots, so a bare kind returns an empty group.
+func SplitQualifiedKind(s string) (kind, group string) {
+	kind, group, _ = strings.Cut(s, ".")
+	return kind, group
+}
+
+// CustomGroup returns group, or "" when it is a built-in group. Resource identity
+// fields (timeline events, changes, topology refs) only carry groups that disambiguate.
+func CustomGroup(group string) string {
+	if IsBuiltinGroup(group) {
+		return ""
+	}
+	return group
+}
+
+// GroupOf returns the API group of a custom resource, or "" for built-in kinds (typed
+// objects are always built-in)
+func GroupOf(obj any) string {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		return CustomGroup(u.GroupVersionKind().Group)
+	}
+	return ""
+}
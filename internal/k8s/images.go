@@ -0,0 +1,173 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+)
+
+// ImageUse is a container of a workload that runs an image
+type ImageUse struct {
+	Kind          string `json:"kind"`
+	Namespace     string `json:"namespace"`
+	Name          string `json:"name"`
+	Container     string `json:"container"`
+	InitContainer bool   `json:"initContainer,omitempty"`
+	Image         string `json:"image"`
+	// ManagedBy names the tool that owns the manifest (Helm, Argo CD or Flux), which will
+	// revert an image changed outside it
+	ManagedBy string `json:"managedBy,omitempty"`
+
+	path string // JSON pointer to the image field
+}
+
+// NormalizeImage expands Docker Hub shorthands ("nginx", "library/nginx") and an implicit
+// latest tag so equivalent references compare equal
+func NormalizeImage(image string) string {
+	image = strings.TrimSpace(image)
+	name, digest, hasDigest := strings.Cut(image, "@")
+	slash := strings.LastIndex(name, "/")
+	if colon := strings.LastIndex(name, ":"); colon <= slash && !hasDigest {
+		name += ":latest"
+	}
+	first, _, _ := strings.Cut(name, "/")
+	if !strings.Contains(name, "/") {
+		name = "docker.io/library/" + name
+	} else if !strings.ContainsAny(first, ".:") && first != "localhost" {
+		name = "docker.io/" + name
+	}
+	if hasDigest {
+		return name + "@" + digest
+	}
+	return name
+}
+
+// WorkloadsUsingImage lists the containers of Deployments, StatefulSets, DaemonSets and
+// CronJobs that run an image, in one namespace or all (namespace ""). Pods and ReplicaSets
+// created by those controllers are left out, since they follow their owner.
+func (c *ResourceCache) WorkloadsUsingImage(image, namespace string) []ImageUse {
+	if c == nil {
+		return nil
+	}
+	want := NormalizeImage(image)
+	uses := make([]ImageUse, 0)
+	add := func(kind string, obj metav1.Object, spec corev1.PodSpec, prefix string) {
+		managedBy := gitOpsManagerOf(obj.GetLabels(), obj.GetAnnotations())
+		if managedBy == "" && obj.GetLabels()["app.kubernetes.io/managed-by"] == "Helm" {
+			managedBy = "Helm"
+		}
+		for _, set := range []struct {
+			containers []corev1.Container
+			field      string
+		}{{spec.InitContainers, "initContainers"}, {spec.Containers, "containers"}} {
+			for i, ctr := range set.containers {
+				if NormalizeImage(ctr.Image) != want {
+					continue
+				}
+				uses = append(uses, ImageUse{
+					Kind:          kind,
+					Namespace:     obj.GetNamespace(),
+					Name:          obj.GetName(),
+					Container:     ctr.Name,
+					InitContainer: set.field == "initContainers",
+					Image:         ctr.Image,
+					ManagedBy:     managedBy,
+					path:          fmt.Sprintf("%s/%s/%d/image", prefix, set.field, i),
+				})
+			}
+		}
+	}
+
+	const template = "/spec/template/spec"
+	if deployments, err := c.Deployments().Deployments(namespace).List(labels.Everything()); err == nil {
+		for _, d := range deployments {
+			add("Deployment", d, d.Spec.Template.Spec, template)
+		}
+	}
+	if statefulSets, err := c.StatefulSets().StatefulSets(namespace).List(labels.Everything()); err == nil {
+		for _, sts := range statefulSets {
+			add("StatefulSet", sts, sts.Spec.Template.Spec, template)
+		}
+	}
+	if daemonSets, err := c.DaemonSets().DaemonSets(namespace).List(labels.Everything()); err == nil {
+		for _, ds := range daemonSets {
+			add("DaemonSet", ds, ds.Spec.Template.Spec, template)
+		}
+	}
+	if lister := c.CronJobs(); lister != nil {
+		if cronJobs, err := lister.CronJobs(namespace).List(labels.Everything()); err == nil {
+			for _, cj := range cronJobs {
+				add("CronJob", cj, cj.Spec.JobTemplate.Spec.Template.Spec, "/spec/jobTemplate/spec/template/spec")
+			}
+		}
+	}
+
+	sort.SliceStable(uses, func(i, j int) bool {
+		a, b := uses[i], uses[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+	return uses
+}
+
+// SetContainerImages points containers of one workload (uses from WorkloadsUsingImage,
+// all for the same workload) at a new image. The patch fails without changing anything if
+// a container no longer runs the image it was found with. Returns the new generation.
+func SetContainerImages(ctx context.Context, uses []ImageUse, image string) (int64, error) {
+	if len(uses) == 0 {
+		return 0, explorerErrors.ValidationError("no containers to update")
+	}
+	if strings.TrimSpace(image) == "" || strings.ContainsAny(image, " \t\n") {
+		return 0, explorerErrors.ValidationError(fmt.Sprintf("invalid image reference %q", image))
+	}
+	kind, namespace, name := uses[0].Kind, uses[0].Namespace, uses[0].Name
+
+	type op struct {
+		Op    string `json:"op"`
+		Path  string `json:"path"`
+		Value string `json:"value"`
+	}
+	var ops []op
+	for _, u := range uses {
+		if u.Kind != kind || u.Namespace != namespace || u.Name != name || u.path == "" {
+			return 0, explorerErrors.ValidationError("containers must come from one workload")
+		}
+		ops = append(ops, op{"test", u.path, u.Image}, op{"replace", u.path, image})
+	}
+	patch, err := json.Marshal(ops)
+	if err != nil {
+		return 0, err
+	}
+
+	dynamicClient, err := DynamicClientFor(ctx)
+	if err != nil {
+		return 0, err
+	}
+	discovery := GetResourceDiscovery()
+	if discovery == nil {
+		return 0, fmt.Errorf("resource discovery not initialized")
+	}
+	gvr, ok := discovery.GetGVR(kind)
+	if !ok {
+		return 0, explorerErrors.ValidationError(fmt.Sprintf("unknown resource kind: %s", kind))
+	}
+	updated, err := dynamicClient.Resource(gvr).Namespace(namespace).Patch(ctx, name, types.JSONPatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to update image of %s %s/%s: %w", kind, namespace, name, err)
+	}
+	return updated.GetGeneration(), nil
+}
@@ -0,0 +1,156 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+)
+
+// Impersonation: when enabled, operations that change the cluster on behalf of a user
+// (edits, deletes, exec, Helm, workload actions) send Impersonate-User/Impersonate-Group
+// headers, so the API server enforces that user's RBAC and records them in its audit log.
+// Radar's service account needs the impersonate verb on users and groups. Reads still
+// come from Radar's own caches.
+
+// maxImpersonatedClients bounds the per-user clients kept; the cache starts over past it
+const maxImpersonatedClients = 256
+
+var (
+	impersonationEnabled atomic.Bool
+
+	impersonatedMu      sync.Mutex
+	impersonatedClients = make(map[string]*impersonatedClient)
+)
+
+// impersonatedClient holds the clients for one subject, built from a base config
+type impersonatedClient struct {
+	base    *rest.Config // Radar's config the clients derive from; a context switch replaces it
+	config  *rest.Config
+	client  *kubernetes.Clientset
+	dynamic dynamic.Interface
+}
+
+type impersonationKey struct{}
+
+// SetImpersonation turns impersonation of request users on or off
+func SetImpersonation(enabled bool) {
+	impersonationEnabled.Store(enabled)
+}
+
+// ImpersonationEnabled reports whether mutating operations run as the request's user
+func ImpersonationEnabled() bool {
+	return impersonationEnabled.Load()
+}
+
+// WithImpersonation returns a context whose mutating operations act as subject, when
+// impersonation is enabled. A nil subject leaves ctx unchanged.
+func WithImpersonation(ctx context.Context, subject *ImpersonationSubject) context.Context {
+	if subject == nil || subject.User == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, impersonationKey{}, subject)
+}
+
+// ImpersonationFrom returns the subject operations in ctx act as, or nil to use Radar's
+// own identity
+func ImpersonationFrom(ctx context.Context) *ImpersonationSubject {
+	if ctx == nil || !ImpersonationEnabled() {
+		return nil
+	}
+	subject, _ := ctx.Value(impersonationKey{}).(*ImpersonationSubject)
+	return subject
+}
+
+// ConfigFor returns the rest config for operations in ctx: Radar's own, or a copy that
+// impersonates the request's user
+func ConfigFor(ctx context.Context) (*rest.Config, error) {
+	c, err := impersonatedFor(ctx)
+	if err != nil || c == nil {
+		return GetConfig(), err
+	}
+	return c.config, nil
+}
+
+// ClientFor returns the clientset for operations in ctx, like ConfigFor. It returns an
+// error rather than falling back to Radar's identity when the user's client can't be built.
+func ClientFor(ctx context.Context) (*kubernetes.Clientset, error) {
+	c, err := impersonatedFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		if client := GetClient(); client != nil {
+			return client, nil
+		}
+		return nil, explorerErrors.K8sClientNotInitialized()
+	}
+	return c.client, nil
+}
+
+// DynamicClientFor returns the dynamic client for operations in ctx, like ClientFor
+func DynamicClientFor(ctx context.Context) (dynamic.Interface, error) {
+	c, err := impersonatedFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		if client := GetDynamicClient(); client != nil {
+			return client, nil
+		}
+		return nil, explorerErrors.K8sClientNotInitialized()
+	}
+	return c.dynamic, nil
+}
+
+// impersonatedFor returns the clients for ctx's subject, or nil when ctx has none
+func impersonatedFor(ctx context.Context) (*impersonatedClient, error) {
+	subject := ImpersonationFrom(ctx)
+	if subject == nil {
+		return nil, nil
+	}
+	base := GetConfig()
+	if base == nil {
+		return nil, explorerErrors.K8sClientNotInitialized()
+	}
+
+	key := impersonationCacheKey(subject)
+	impersonatedMu.Lock()
+	defer impersonatedMu.Unlock()
+	if c, ok := impersonatedClients[key]; ok && c.base == base {
+		return c, nil
+	}
+
+	config := rest.CopyConfig(base)
+	config.Impersonate = rest.ImpersonationConfig{UserName: subject.User, Groups: subject.Groups}
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("creating client impersonating %s: %w", subject.User, err)
+	}
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("creating dynamic client impersonating %s: %w", subject.User, err)
+	}
+
+	if len(impersonatedClients) >= maxImpersonatedClients {
+		impersonatedClients = make(map[string]*impersonatedClient)
+	}
+	c := &impersonatedClient{base: base, config: config, client: client, dynamic: dynamicClient}
+	impersonatedClients[key] = c
+	return c, nil
+}
+
+// impersonationCacheKey identifies a subject regardless of group order
+func impersonationCacheKey(subject *ImpersonationSubject) string {
+	groups := slices.Clone(subject.Groups)
+	slices.Sort(groups)
+	return subject.User + "\x00" + strings.Join(groups, "\x00")
+}
@@ -0,0 +1,170 @@
+package k8s
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/client-go/util/homedir"
+)
+
+// resolveKubeconfigPaths expands --kubeconfig (or $KUBECONFIG, or ~/.kube/config) into
+// the kubeconfig files to load. Like KUBECONFIG, the value may be a path list; entries
+// that are directories contribute every kubeconfig in them. Missing files in a list are
+// skipped as kubectl does, but a single explicit path is kept so loading reports it.
+func resolveKubeconfigPaths(kubeconfig string) []string {
+	if kubeconfig == "" {
+		kubeconfig = os.Getenv("KUBECONFIG")
+	}
+	if kubeconfig == "" {
+		if home := homedir.HomeDir(); home != "" {
+			kubeconfig = filepath.Join(home, ".kube", "config")
+		}
+	}
+
+	entries := filepath.SplitList(kubeconfig)
+	var paths []string
+	seen := make(map[string]bool)
+	add := func(path string) {
+		if path = filepath.Clean(path); !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	for _, entry := range entries {
+		if entry == "" {
+			continue
+		}
+		info, err := os.Stat(entry)
+		switch {
+		case err == nil && info.IsDir():
+			configs, _ := discoverKubeconfigs([]string{entry})
+			for _, path := range configs {
+				add(path)
+			}
+		case err != nil && len(entries) > 1:
+			log.Printf("Skipping kubeconfig %s: %v", entry, err)
+		default:
+			add(entry)
+		}
+	}
+	return paths
+}
+
+// kubeconfigLoader returns a client config over the kubeconfig files in use. A single
+// file is loaded by client-go as usual; several are merged by mergeKubeconfigs.
+func kubeconfigLoader(overrides *clientcmd.ConfigOverrides) (clientcmd.ClientConfig, error) {
+	switch len(kubeconfigPaths) {
+	case 0:
+		return nil, fmt.Errorf("kubeconfig path not set")
+	case 1:
+		rules := &clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfigPaths[0]}
+		return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides), nil
+	}
+	merged, err := mergeKubeconfigs(kubeconfigPaths)
+	if err != nil {
+		return nil, err
+	}
+	return clientcmd.NewNonInteractiveClientConfig(*merged, "", overrides, nil), nil
+}
+
+// KubeconfigLoader returns a client config over the merged kubeconfig when Radar loaded
+// several files (nil with a single file or in-cluster), so consumers that otherwise read
+// a kubeconfig path (Helm) see the same contexts as Radar, file-qualified names included
+func KubeconfigLoader(overrides *clientcmd.ConfigOverrides) clientcmd.ClientConfig {
+	clientMu.RLock()
+	multi := len(kubeconfigPaths) > 1
+	clientMu.RUnlock()
+	if !multi {
+		return nil
+	}
+	loader, err := kubeconfigLoader(overrides)
+	if err != nil {
+		log.Printf("Warning: failed to load kubeconfigs: %v", err)
+		return nil
+	}
+	return loader
+}
+
+// GetKubeconfigPaths returns every kubeconfig file in use (empty in-cluster)
+func GetKubeconfigPaths() []string {
+	clientMu.RLock()
+	defer clientMu.RUnlock()
+	return kubeconfigPaths
+}
+
+// mergeKubeconfigs merges kubeconfig files the way kubectl merges a KUBECONFIG list: the
+// first file to set current-context wins, and so does the first definition of a name.
+// Unlike kubectl, a later context whose name is taken by a different definition isn't
+// dropped but added as "<name> (<file>)"; clusters and users it references that clash
+// are qualified the same way, so it keeps pointing at its own file's entries.
+func mergeKubeconfigs(paths []string) (*clientcmdapi.Config, error) {
+	merged := clientcmdapi.NewConfig()
+	for _, path := range paths {
+		cfg, err := clientcmd.LoadFromFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to load kubeconfig %s: %w", path, err)
+		}
+		if err := clientcmd.ResolveLocalPaths(cfg); err != nil {
+			return nil, fmt.Errorf("failed to resolve paths in kubeconfig %s: %w", path, err)
+		}
+		if merged.CurrentContext == "" {
+			merged.CurrentContext = cfg.CurrentContext
+		}
+
+		clusters := make(map[string]string, len(cfg.Clusters))
+		for name, cluster := range cfg.Clusters {
+			clusters[name] = mergeEntry(merged.Clusters, name, cluster, path)
+		}
+		users := make(map[string]string, len(cfg.AuthInfos))
+		for name, user := range cfg.AuthInfos {
+			users[name] = mergeEntry(merged.AuthInfos, name, user, path)
+		}
+		for name, ctx := range cfg.Contexts {
+			ctx = ctx.DeepCopy()
+			if renamed, ok := clusters[ctx.Cluster]; ok {
+				ctx.Cluster = renamed
+			}
+			if renamed, ok := users[ctx.AuthInfo]; ok {
+				ctx.AuthInfo = renamed
+			}
+			mergeEntry(merged.Contexts, name, ctx, path)
+		}
+	}
+	return merged, nil
+}
+
+// mergeEntry adds a named kubeconfig entry from file unless an identical one is already
+// there, and returns the name it's available under
+func mergeEntry[T any](entries map[string]T, name string, entry T, file string) string {
+	existing, ok := entries[name]
+	if !ok {
+		entries[name] = entry
+		return name
+	}
+	if sameKubeconfigEntry(existing, entry) {
+		return name
+	}
+	qualified := fmt.Sprintf("%s (%s)", name, filepath.Base(file))
+	if _, taken := entries[qualified]; taken {
+		qualified = fmt.Sprintf("%s (%s)", name, file)
+	}
+	entries[qualified] = entry
+	return qualified
+}
+
+// sameKubeconfigEntry compares two entries by their serialized form, which leaves out
+// the file they were loaded from
+func sameKubeconfigEntry(a, b any) bool {
+	aj, errA := json.Marshal(a)
+	bj, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(aj, bj)
+}
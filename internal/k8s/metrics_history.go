@@ -8,6 +8,8 @@ import (
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/skyhook-io/radar/internal/timeline"
 )
 
 const (
@@ -22,6 +24,9 @@ type MetricsDataPoint struct {
 	Timestamp time.Time `json:"timestamp"`
 	CPU       int64     `json:"cpu"`       // CPU in nanocores
 	Memory    int64     `json:"memory"`    // Memory in bytes
+	// Highest raw samples behind a rolled-up point (persisted history only)
+	CPUMax    int64     `json:"cpuMax,omitempty"`
+	MemoryMax int64     `json:"memoryMax,omitempty"`
 }
 
 // ContainerMetricsHistory holds historical metrics for a container
@@ -35,12 +40,16 @@ type PodMetricsHistory struct {
 	Namespace  string                    `json:"namespace"`
 	Name       string                    `json:"name"`
 	Containers []ContainerMetricsHistory `json:"containers"`
+	Resolution string                    `json:"resolution,omitempty"` // Rollup width when read from disk ("raw", "5m", "1h")
+	Persistent bool                      `json:"persistent,omitempty"` // Longer ranges can be requested
 }
 
 // NodeMetricsHistory holds historical metrics for a node
 type NodeMetricsHistory struct {
 	Name       string             `json:"name"`
 	DataPoints []MetricsDataPoint `json:"dataPoints"`
+	Resolution string             `json:"resolution,omitempty"`
+	Persistent bool               `json:"persistent,omitempty"`
 }
 
 // MetricsHistoryStore stores historical metrics data
@@ -53,6 +62,9 @@ type MetricsHistoryStore struct {
 	// Node metrics: key = node name
 	nodeMetrics map[string]*nodeMetricsBuffer
 
+	// persist also writes samples to disk, for history across restarts (nil = memory only)
+	persist *timeline.MetricsStore
+
 	// Control
 	stopCh chan struct{}
 	wg     sync.WaitGroup
@@ -118,14 +130,17 @@ var (
 	metricsHistoryOnce  sync.Once
 )
 
-// InitMetricsHistory initializes the metrics history store and starts polling
-func InitMetricsHistory() {
+// InitMetricsHistory initializes the metrics history store and starts polling. With a
+// persistent store, samples are also written to disk and the last hour is reloaded from it.
+func InitMetricsHistory(persist *timeline.MetricsStore) {
 	metricsHistoryOnce.Do(func() {
 		metricsHistoryStore = &MetricsHistoryStore{
 			podMetrics:  make(map[string]*podMetricsBuffer),
 			nodeMetrics: make(map[string]*nodeMetricsBuffer),
+			persist:     persist,
 			stopCh:      make(chan struct{}),
 		}
+		metricsHistoryStore.load()
 
 		// Start polling goroutine
 		metricsHistoryStore.wg.Add(1)
@@ -145,6 +160,11 @@ func StopMetricsHistory() {
 	if metricsHistoryStore != nil {
 		close(metricsHistoryStore.stopCh)
 		metricsHistoryStore.wg.Wait()
+		if metricsHistoryStore.persist != nil {
+			if err := metricsHistoryStore.persist.Close(); err != nil {
+				log.Printf("Warning: error closing metrics history database: %v", err)
+			}
+		}
 		log.Println("Metrics history collection stopped")
 	}
 }
@@ -177,43 +197,125 @@ func (s *MetricsHistoryStore) collectMetrics() {
 	now := time.Now()
 
 	// Collect pod metrics
-	s.collectPodMetrics(ctx, now)
+	samples := s.collectPodMetrics(ctx, now)
 
 	// Collect node metrics
-	s.collectNodeMetrics(ctx, now)
+	samples = append(samples, s.collectNodeMetrics(ctx, now)...)
+
+	if s.persist != nil {
+		if err := s.persist.Write(ctx, metricsScope(), samples); err != nil {
+			log.Printf("Warning: failed to persist metrics history: %v", err)
+		}
+	}
+}
+
+// metricsScope identifies the cluster persisted samples belong to
+func metricsScope() string {
+	return GetClusterName() + "/" + GetContextName()
+}
+
+// load refills the in-memory buffers from disk for the current cluster
+func (s *MetricsHistoryStore) load() {
+	if s.persist == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	samples, err := s.persist.Recent(ctx, metricsScope(), time.Now().Add(-MetricsHistorySize*MetricsPollInterval))
+	if err != nil {
+		log.Printf("Warning: failed to load metrics history: %v", err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, m := range samples {
+		point := MetricsDataPoint{Timestamp: m.Timestamp, CPU: m.CPU, Memory: m.Memory}
+		if m.Kind == "node" {
+			s.nodeBuffer(m.Name).buffer.Add(point)
+		} else {
+			s.containerBuffer(s.podBuffer(m.Namespace, m.Name), m.Container).Add(point)
+		}
+	}
+	if len(samples) > 0 {
+		log.Printf("Loaded %d metrics samples from disk", len(samples))
+	}
+}
+
+// resetMetricsHistory drops the previous cluster's samples after a context switch and
+// loads the new one's from disk
+func resetMetricsHistory() {
+	s := metricsHistoryStore
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.podMetrics = make(map[string]*podMetricsBuffer)
+	s.nodeMetrics = make(map[string]*nodeMetricsBuffer)
+	s.mu.Unlock()
+	s.load()
+}
+
+// podBuffer returns a pod's buffers, creating them if needed. Callers hold s.mu.
+func (s *MetricsHistoryStore) podBuffer(namespace, name string) *podMetricsBuffer {
+	key := namespace + "/" + name
+	podBuf, exists := s.podMetrics[key]
+	if !exists {
+		podBuf = &podMetricsBuffer{
+			namespace:  namespace,
+			name:       name,
+			containers: make(map[string]*ringBuffer),
+		}
+		s.podMetrics[key] = podBuf
+	}
+	return podBuf
+}
+
+func (s *MetricsHistoryStore) containerBuffer(podBuf *podMetricsBuffer, container string) *ringBuffer {
+	containerBuf, exists := podBuf.containers[container]
+	if !exists {
+		containerBuf = newRingBuffer(MetricsHistorySize)
+		podBuf.containers[container] = containerBuf
+	}
+	return containerBuf
+}
+
+// nodeBuffer returns a node's buffer, creating it if needed. Callers hold s.mu.
+func (s *MetricsHistoryStore) nodeBuffer(name string) *nodeMetricsBuffer {
+	nodeBuf, exists := s.nodeMetrics[name]
+	if !exists {
+		nodeBuf = &nodeMetricsBuffer{
+			name:   name,
+			buffer: newRingBuffer(MetricsHistorySize),
+		}
+		s.nodeMetrics[name] = nodeBuf
+	}
+	return nodeBuf
 }
 
-func (s *MetricsHistoryStore) collectPodMetrics(ctx context.Context, now time.Time) {
+func (s *MetricsHistoryStore) collectPodMetrics(ctx context.Context, now time.Time) []timeline.MetricSample {
 	client := GetDynamicClient()
 	if client == nil {
-		return
+		return nil
 	}
 
 	// List all pod metrics
 	result, err := client.Resource(podMetricsGVR).List(ctx, metav1.ListOptions{})
 	if err != nil {
 		// Metrics server might not be installed, don't spam logs
-		return
+		return nil
 	}
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	var samples []timeline.MetricSample
 	for _, item := range result.Items {
 		namespace := item.GetNamespace()
 		name := item.GetName()
-		key := namespace + "/" + name
 
 		// Get or create pod buffer
-		podBuf, exists := s.podMetrics[key]
-		if !exists {
-			podBuf = &podMetricsBuffer{
-				namespace:  namespace,
-				name:       name,
-				containers: make(map[string]*ringBuffer),
-			}
-			s.podMetrics[key] = podBuf
-		}
+		podBuf := s.podBuffer(namespace, name)
 
 		// Extract container metrics
 		containers, ok := item.Object["containers"].([]interface{})
@@ -243,49 +345,39 @@ func (s *MetricsHistoryStore) collectPodMetrics(ctx context.Context, now time.Ti
 			cpu := parseCPU(cpuStr)
 			mem := parseMemory(memStr)
 
-			// Get or create container buffer
-			containerBuf, exists := podBuf.containers[containerName]
-			if !exists {
-				containerBuf = newRingBuffer(MetricsHistorySize)
-				podBuf.containers[containerName] = containerBuf
-			}
-
-			containerBuf.Add(MetricsDataPoint{
+			s.containerBuffer(podBuf, containerName).Add(MetricsDataPoint{
 				Timestamp: now,
 				CPU:       cpu,
 				Memory:    mem,
 			})
+			samples = append(samples, timeline.MetricSample{Kind: "pod", Namespace: namespace, Name: name,
+				Container: containerName, Timestamp: now, CPU: cpu, Memory: mem})
 		}
 	}
+	return samples
 }
 
-func (s *MetricsHistoryStore) collectNodeMetrics(ctx context.Context, now time.Time) {
+func (s *MetricsHistoryStore) collectNodeMetrics(ctx context.Context, now time.Time) []timeline.MetricSample {
 	client := GetDynamicClient()
 	if client == nil {
-		return
+		return nil
 	}
 
 	// List all node metrics
 	result, err := client.Resource(nodeMetricsGVR).List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return
+		return nil
 	}
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	var samples []timeline.MetricSample
 	for _, item := range result.Items {
 		name := item.GetName()
 
 		// Get or create node buffer
-		nodeBuf, exists := s.nodeMetrics[name]
-		if !exists {
-			nodeBuf = &nodeMetricsBuffer{
-				name:   name,
-				buffer: newRingBuffer(MetricsHistorySize),
-			}
-			s.nodeMetrics[name] = nodeBuf
-		}
+		nodeBuf := s.nodeBuffer(name)
 
 		usage, ok := item.Object["usage"].(map[string]interface{})
 		if !ok {
@@ -303,7 +395,9 @@ func (s *MetricsHistoryStore) collectNodeMetrics(ctx context.Context, now time.T
 			CPU:       cpu,
 			Memory:    mem,
 		})
+		samples = append(samples, timeline.MetricSample{Kind: "node", Name: name, Timestamp: now, CPU: cpu, Memory: mem})
 	}
+	return samples
 }
 
 // GetPodMetricsHistory returns historical metrics for a specific pod
@@ -325,6 +419,7 @@ func (s *MetricsHistoryStore) GetPodMetricsHistory(namespace, name string) *PodM
 		Namespace:  namespace,
 		Name:       name,
 		Containers: make([]ContainerMetricsHistory, 0, len(podBuf.containers)),
+		Persistent: s.persist != nil,
 	}
 
 	for containerName, buf := range podBuf.containers {
@@ -354,6 +449,75 @@ func (s *MetricsHistoryStore) GetNodeMetricsHistory(name string) *NodeMetricsHis
 	return &NodeMetricsHistory{
 		Name:       name,
 		DataPoints: nodeBuf.buffer.GetAll(),
+		Persistent: s.persist != nil,
+	}
+}
+
+// Persistent reports whether history is kept on disk, so ranges beyond the in-memory hour
+// can be queried
+func (s *MetricsHistoryStore) Persistent() bool {
+	return s != nil && s.persist != nil
+}
+
+// QueryPodMetricsHistory reads a pod's history since the given time from disk, at the
+// finest resolution that covers it
+func (s *MetricsHistoryStore) QueryPodMetricsHistory(ctx context.Context, namespace, name string, since time.Time) (*PodMetricsHistory, error) {
+	resolution := s.persist.ResolutionFor(since, time.Now())
+	series, err := s.persist.Query(ctx, metricsScope(), "pod", namespace, name, resolution, since)
+	if err != nil {
+		return nil, err
+	}
+	history := &PodMetricsHistory{
+		Namespace:  namespace,
+		Name:       name,
+		Containers: make([]ContainerMetricsHistory, 0, len(series)),
+		Resolution: resolutionName(resolution),
+		Persistent: true,
+	}
+	for _, c := range series {
+		history.Containers = append(history.Containers, ContainerMetricsHistory{Name: c.Container, DataPoints: dataPoints(c.Points)})
+	}
+	return history, nil
+}
+
+// QueryNodeMetricsHistory reads a node's history since the given time from disk
+func (s *MetricsHistoryStore) QueryNodeMetricsHistory(ctx context.Context, name string, since time.Time) (*NodeMetricsHistory, error) {
+	resolution := s.persist.ResolutionFor(since, time.Now())
+	series, err := s.persist.Query(ctx, metricsScope(), "node", "", name, resolution, since)
+	if err != nil {
+		return nil, err
+	}
+	history := &NodeMetricsHistory{
+		Name:       name,
+		DataPoints: []MetricsDataPoint{},
+		Resolution: resolutionName(resolution),
+		Persistent: true,
+	}
+	if len(series) > 0 {
+		history.DataPoints = dataPoints(series[0].Points)
+	}
+	return history, nil
+}
+
+func dataPoints(points []timeline.MetricPoint) []MetricsDataPoint {
+	out := make([]MetricsDataPoint, len(points))
+	for i, p := range points {
+		out[i] = MetricsDataPoint{Timestamp: p.Timestamp, CPU: p.CPU, Memory: p.Memory}
+		if p.CPUMax != p.CPU || p.MemoryMax != p.Memory {
+			out[i].CPUMax, out[i].MemoryMax = p.CPUMax, p.MemoryMax
+		}
+	}
+	return out
+}
+
+func resolutionName(resolution time.Duration) string {
+	switch resolution {
+	case timeline.Metrics5m:
+		return "5m"
+	case timeline.Metrics1h:
+		return "1h"
+	default:
+		return "raw"
 	}
 }
 
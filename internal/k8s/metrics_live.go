@@ -0,0 +1,177 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Live pod metrics are polled per subscriber, only while a pod's detail view is open
+const (
+	LiveMetricsMinInterval     = 2 * time.Second
+	LiveMetricsMaxInterval     = 5 * time.Second
+	LiveMetricsDefaultInterval = 2 * time.Second
+)
+
+// Where a live sample came from
+const (
+	LiveMetricsSourceKubelet       = "kubelet"
+	LiveMetricsSourceMetricsServer = "metrics-server"
+)
+
+// LivePodMetrics is one high-resolution sample of a pod's usage
+type LivePodMetrics struct {
+	Timestamp  time.Time              `json:"timestamp"`
+	Source     string                 `json:"source"` // kubelet or metrics-server
+	Containers []LiveContainerMetrics `json:"containers"`
+}
+
+// LiveContainerMetrics is a container's usage in a live sample
+type LiveContainerMetrics struct {
+	Name   string `json:"name"`
+	CPU    int64  `json:"cpu"`    // CPU in nanocores
+	Memory int64  `json:"memory"` // Working set in bytes
+}
+
+// kubeletPodStats is the subset of the kubelet /stats/summary response live metrics need
+type kubeletPodStats struct {
+	Pods []struct {
+		PodRef struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"podRef"`
+		Containers []struct {
+			Name string `json:"name"`
+			CPU  *struct {
+				Time           time.Time `json:"time"`
+				UsageNanoCores *uint64   `json:"usageNanoCores"`
+			} `json:"cpu"`
+			Memory *struct {
+				Time            time.Time `json:"time"`
+				WorkingSetBytes *uint64   `json:"workingSetBytes"`
+			} `json:"memory"`
+		} `json:"containers"`
+	} `json:"pods"`
+}
+
+// PodMetricsPoller samples one pod's usage. It reads the kubelet summary of the pod's node
+// through the API server proxy, which refreshes more often than metrics-server's scrape
+// (15s by default), and falls back to metrics-server when the proxy isn't allowed.
+type PodMetricsPoller struct {
+	namespace string
+	name      string
+	kubelet   bool // Cleared when the node proxy fails (e.g. nodes/proxy not granted)
+	last      time.Time
+}
+
+// NewPodMetricsPoller creates a poller for a pod
+func NewPodMetricsPoller(namespace, name string) *PodMetricsPoller {
+	return &PodMetricsPoller{namespace: namespace, name: name, kubelet: true}
+}
+
+// ValidateLiveMetricsInterval returns an error for intervals outside the live range
+func ValidateLiveMetricsInterval(interval time.Duration) error {
+	if interval < LiveMetricsMinInterval || interval > LiveMetricsMaxInterval {
+		return fmt.Errorf("interval must be between %s and %s", LiveMetricsMinInterval, LiveMetricsMaxInterval)
+	}
+	return nil
+}
+
+// Poll returns the pod's latest sample, or nil when it's the same one as the last poll
+// (the source hasn't refreshed since)
+func (p *PodMetricsPoller) Poll(ctx context.Context) (*LivePodMetrics, error) {
+	var sample *LivePodMetrics
+	var err error
+	if p.kubelet {
+		sample, _ = p.pollKubelet(ctx)
+	}
+	if sample == nil {
+		if sample, err = p.pollMetricsServer(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if !sample.Timestamp.After(p.last) {
+		return nil, nil
+	}
+	p.last = sample.Timestamp
+	return sample, nil
+}
+
+func (p *PodMetricsPoller) pollKubelet(ctx context.Context) (*LivePodMetrics, error) {
+	cache := GetResourceCache()
+	client := GetClient()
+	if cache == nil || client == nil {
+		return nil, fmt.Errorf("client not initialized")
+	}
+	pod, err := cache.Pods().Pods(p.namespace).Get(p.name)
+	if err != nil {
+		return nil, err
+	}
+	if pod.Spec.NodeName == "" {
+		return nil, fmt.Errorf("pod %s/%s isn't scheduled", p.namespace, p.name)
+	}
+	raw, err := client.CoreV1().RESTClient().Get().
+		Resource("nodes").
+		Name(pod.Spec.NodeName).
+		SubResource("proxy", "stats", "summary").
+		Param("only_cpu_and_memory", "true").
+		DoRaw(ctx)
+	if err != nil {
+		p.kubelet = false
+		return nil, fmt.Errorf("failed to get kubelet stats summary: %w", err)
+	}
+	var summary kubeletPodStats
+	if err := json.Unmarshal(raw, &summary); err != nil {
+		return nil, fmt.Errorf("failed to decode kubelet stats summary: %w", err)
+	}
+
+	for _, ps := range summary.Pods {
+		if ps.PodRef.Namespace != p.namespace || ps.PodRef.Name != p.name {
+			continue
+		}
+		sample := &LivePodMetrics{Source: LiveMetricsSourceKubelet, Containers: []LiveContainerMetrics{}}
+		for _, c := range ps.Containers {
+			cm := LiveContainerMetrics{Name: c.Name}
+			if c.CPU != nil && c.CPU.UsageNanoCores != nil {
+				cm.CPU = int64(*c.CPU.UsageNanoCores)
+				sample.Timestamp = later(sample.Timestamp, c.CPU.Time)
+			}
+			if c.Memory != nil && c.Memory.WorkingSetBytes != nil {
+				cm.Memory = int64(*c.Memory.WorkingSetBytes)
+				sample.Timestamp = later(sample.Timestamp, c.Memory.Time)
+			}
+			sample.Containers = append(sample.Containers, cm)
+		}
+		return sample, nil
+	}
+	return nil, fmt.Errorf("pod %s/%s not in the kubelet stats summary", p.namespace, p.name)
+}
+
+func (p *PodMetricsPoller) pollMetricsServer(ctx context.Context) (*LivePodMetrics, error) {
+	metrics, err := GetPodMetrics(ctx, p.namespace, p.name)
+	if err != nil {
+		return nil, err
+	}
+	sample := &LivePodMetrics{Source: LiveMetricsSourceMetricsServer, Containers: []LiveContainerMetrics{}}
+	if ts, err := time.Parse(time.RFC3339, metrics.Timestamp); err == nil {
+		sample.Timestamp = ts
+	} else {
+		sample.Timestamp = time.Now()
+	}
+	for _, c := range metrics.Containers {
+		sample.Containers = append(sample.Containers, LiveContainerMetrics{
+			Name:   c.Name,
+			CPU:    parseCPU(c.Usage.CPU),
+			Memory: parseMemory(c.Usage.Memory),
+		})
+	}
+	return sample, nil
+}
+
+func later(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
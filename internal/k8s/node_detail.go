@@ -0,0 +1,258 @@
+package k8s
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// nodeRoleLabelPrefix marks node roles, e.g. node-role.kubernetes.io/control-plane
+const nodeRoleLabelPrefix = "node-role.kubernetes.io/"
+
+// NodeDetail is a node's health and capacity: pressure conditions, taints, what the
+// scheduler has committed to it (sum of pod requests) against what it can allocate, and
+// the versions it runs
+type NodeDetail struct {
+	Name          string          `json:"name"`
+	Roles         []string        `json:"roles,omitempty"`
+	Ready         bool            `json:"ready"`
+	Unschedulable bool            `json:"unschedulable,omitempty"`
+	Pressure      []string        `json:"pressure,omitempty"` // Conditions under pressure, e.g. MemoryPressure
+	Conditions    []NodeCondition `json:"conditions"`
+	Taints        []NodeTaint     `json:"taints,omitempty"`
+	CPU           NodeResource    `json:"cpu"`    // Millicores
+	Memory        NodeResource    `json:"memory"` // Bytes
+	Pods          NodePodCount    `json:"pods"`
+	Versions      NodeVersions    `json:"versions"`
+	CreatedAt     time.Time       `json:"createdAt"`
+
+	// PodList is the node's running and pending pods, set for a single node's detail
+	PodList []NodePod `json:"podList,omitempty"`
+}
+
+// NodeCondition is one node condition (Ready, MemoryPressure, DiskPressure, PIDPressure, ...)
+type NodeCondition struct {
+	Type               string    `json:"type"`
+	Status             string    `json:"status"`
+	Reason             string    `json:"reason,omitempty"`
+	Message            string    `json:"message,omitempty"`
+	LastTransitionTime time.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// NodeTaint is a taint that repels pods without a matching toleration
+type NodeTaint struct {
+	Key    string `json:"key"`
+	Value  string `json:"value,omitempty"`
+	Effect string `json:"effect"`
+}
+
+// NodeResource compares a node's allocatable amount of a resource with the requests and
+// limits of the pods scheduled to it
+type NodeResource struct {
+	Allocatable    int64 `json:"allocatable"`
+	Requests       int64 `json:"requests"`
+	Limits         int64 `json:"limits"`
+	RequestPercent int   `json:"requestPercent"`
+	LimitPercent   int   `json:"limitPercent"` // Over 100 means the node is overcommitted
+}
+
+// NodePodCount is the number of pods on a node against the kubelet's pod limit
+type NodePodCount struct {
+	Count    int   `json:"count"`
+	Capacity int64 `json:"capacity"`
+}
+
+// NodeVersions are the kubelet, container runtime and OS versions a node reports
+type NodeVersions struct {
+	Kubelet          string `json:"kubelet"`
+	ContainerRuntime string `json:"containerRuntime"`
+	Kernel           string `json:"kernel,omitempty"`
+	OSImage          string `json:"osImage,omitempty"`
+	Architecture     string `json:"architecture,omitempty"`
+}
+
+// NodePod is a pod on a node with what it requests
+type NodePod struct {
+	Namespace      string `json:"namespace"`
+	Name           string `json:"name"`
+	Phase          string `json:"phase"`
+	CPURequests    int64  `json:"cpuRequests"`    // Millicores
+	MemoryRequests int64  `json:"memoryRequests"` // Bytes
+}
+
+// podResources is what a pod reserves on its node, counted the way the scheduler does
+type podResources struct {
+	cpuRequests, cpuLimits int64 // Millicores
+	memRequests, memLimits int64 // Bytes
+}
+
+func (p *podResources) add(r corev1.ResourceRequirements) {
+	p.cpuRequests += r.Requests.Cpu().MilliValue()
+	p.cpuLimits += r.Limits.Cpu().MilliValue()
+	p.memRequests += r.Requests.Memory().Value()
+	p.memLimits += r.Limits.Memory().Value()
+}
+
+func (p *podResources) atLeast(o podResources) {
+	p.cpuRequests = max(p.cpuRequests, o.cpuRequests)
+	p.cpuLimits = max(p.cpuLimits, o.cpuLimits)
+	p.memRequests = max(p.memRequests, o.memRequests)
+	p.memLimits = max(p.memLimits, o.memLimits)
+}
+
+// effectivePodResources returns a pod's requests and limits: the larger of its app
+// containers (plus sidecars) and any single init container, plus pod overhead
+func effectivePodResources(pod *corev1.Pod) podResources {
+	var total, sidecars podResources
+	var inits []podResources
+	for _, c := range pod.Spec.InitContainers {
+		// Sidecars (restartable init containers) keep running alongside the app containers
+		if c.RestartPolicy != nil && *c.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+			sidecars.add(c.Resources)
+			continue
+		}
+		// A regular init container runs alone, next to the sidecars started before it
+		one := sidecars
+		one.add(c.Resources)
+		inits = append(inits, one)
+	}
+	total = sidecars
+	for _, c := range pod.Spec.Containers {
+		total.add(c.Resources)
+	}
+	for _, one := range inits {
+		total.atLeast(one)
+	}
+	if pod.Spec.Overhead != nil {
+		total.add(corev1.ResourceRequirements{Requests: pod.Spec.Overhead})
+	}
+	return total
+}
+
+// percentOf returns part as a percentage of whole, or 0 when whole is unknown
+func percentOf(part, whole int64) int {
+	if whole <= 0 {
+		return 0
+	}
+	return int(part * 100 / whole)
+}
+
+// podsByNode groups pods that hold node resources (scheduled and not finished) by node
+func podsByNode(pods []*corev1.Pod) map[string][]*corev1.Pod {
+	byNode := make(map[string][]*corev1.Pod)
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		byNode[pod.Spec.NodeName] = append(byNode[pod.Spec.NodeName], pod)
+	}
+	return byNode
+}
+
+// buildNodeDetail aggregates a node with the pods scheduled to it
+func buildNodeDetail(node *corev1.Node, pods []*corev1.Pod, withPods bool) NodeDetail {
+	d := NodeDetail{
+		Name:          node.Name,
+		Unschedulable: node.Spec.Unschedulable,
+		Conditions:    make([]NodeCondition, 0, len(node.Status.Conditions)),
+		CreatedAt:     node.CreationTimestamp.Time,
+		Versions: NodeVersions{
+			Kubelet:          node.Status.NodeInfo.KubeletVersion,
+			ContainerRuntime: node.Status.NodeInfo.ContainerRuntimeVersion,
+			Kernel:           node.Status.NodeInfo.KernelVersion,
+			OSImage:          node.Status.NodeInfo.OSImage,
+			Architecture:     node.Status.NodeInfo.Architecture,
+		},
+	}
+	for label := range node.Labels {
+		if role, ok := strings.CutPrefix(label, nodeRoleLabelPrefix); ok && role != "" {
+			d.Roles = append(d.Roles, role)
+		}
+	}
+	sort.Strings(d.Roles)
+
+	for _, c := range node.Status.Conditions {
+		d.Conditions = append(d.Conditions, NodeCondition{
+			Type:               string(c.Type),
+			Status:             string(c.Status),
+			Reason:             c.Reason,
+			Message:            c.Message,
+			LastTransitionTime: c.LastTransitionTime.Time,
+		})
+		switch c.Type {
+		case corev1.NodeReady:
+			d.Ready = c.Status == corev1.ConditionTrue
+		case corev1.NodeMemoryPressure, corev1.NodeDiskPressure, corev1.NodePIDPressure, corev1.NodeNetworkUnavailable:
+			if c.Status == corev1.ConditionTrue {
+				d.Pressure = append(d.Pressure, string(c.Type))
+			}
+		}
+	}
+	for _, t := range node.Spec.Taints {
+		d.Taints = append(d.Taints, NodeTaint{Key: t.Key, Value: t.Value, Effect: string(t.Effect)})
+	}
+
+	d.CPU.Allocatable = node.Status.Allocatable.Cpu().MilliValue()
+	d.Memory.Allocatable = node.Status.Allocatable.Memory().Value()
+	d.Pods = NodePodCount{Count: len(pods), Capacity: node.Status.Allocatable.Pods().Value()}
+	for _, pod := range pods {
+		r := effectivePodResources(pod)
+		d.CPU.Requests += r.cpuRequests
+		d.CPU.Limits += r.cpuLimits
+		d.Memory.Requests += r.memRequests
+		d.Memory.Limits += r.memLimits
+		if withPods {
+			d.PodList = append(d.PodList, NodePod{
+				Namespace:      pod.Namespace,
+				Name:           pod.Name,
+				Phase:          string(pod.Status.Phase),
+				CPURequests:    r.cpuRequests,
+				MemoryRequests: r.memRequests,
+			})
+		}
+	}
+	for _, res := range []*NodeResource{&d.CPU, &d.Memory} {
+		res.RequestPercent = percentOf(res.Requests, res.Allocatable)
+		res.LimitPercent = percentOf(res.Limits, res.Allocatable)
+	}
+	sort.Slice(d.PodList, func(i, j int) bool {
+		if d.PodList[i].Namespace != d.PodList[j].Namespace {
+			return d.PodList[i].Namespace < d.PodList[j].Namespace
+		}
+		return d.PodList[i].Name < d.PodList[j].Name
+	})
+	return d
+}
+
+// ListNodeDetails returns every cached node's detail, sorted by name
+func (c *ResourceCache) ListNodeDetails() ([]NodeDetail, error) {
+	nodes, err := c.Nodes().List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	pods, err := c.Pods().List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	byNode := podsByNode(pods)
+
+	details := make([]NodeDetail, 0, len(nodes))
+	for _, node := range nodes {
+		details = append(details, buildNodeDetail(node, byNode[node.Name], false))
+	}
+	sort.Slice(details, func(i, j int) bool { return details[i].Name < details[j].Name })
+	return details, nil
+}
+
+// GetNodeDetail returns one node's detail with its pods
+func (c *ResourceCache) GetNodeDetail(name string) (*NodeDetail, error) {
+	node, err := c.Nodes().Get(name)
+	if err != nil {
+		return nil, err
+	}
+	d := buildNodeDetail(node, c.PodsOnNode(name), true)
+	return &d, nil
+}
@@ -0,0 +1,225 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	authv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MaxPermissionChecks bounds a single preflight batch
+const MaxPermissionChecks = 100
+
+// ErrTooManyChecks is returned for a batch larger than MaxPermissionChecks
+var ErrTooManyChecks = errors.New("too many checks")
+
+// permissionCheckConcurrency limits parallel access reviews per batch
+const permissionCheckConcurrency = 8
+
+// PermissionCheck is a single "can I <verb> <resource>" question.
+// Either Kind (resolved via discovery) or Resource (+Group) must be set.
+type PermissionCheck struct {
+	ID          string `json:"id,omitempty"` // Caller-supplied key echoed in the result
+	Verb        string `json:"verb"`
+	Kind        string `json:"kind,omitempty"`
+	Group       string `json:"group,omitempty"`
+	Resource    string `json:"resource,omitempty"`
+	Subresource string `json:"subresource,omitempty"` // e.g. exec, log, scale
+	Namespace   string `json:"namespace,omitempty"`
+	Name        string `json:"name,omitempty"`
+}
+
+// PermissionResult is the answer to a PermissionCheck
+type PermissionResult struct {
+	PermissionCheck
+	Allowed bool   `json:"allowed"`
+	Denied  bool   `json:"denied,omitempty"` // Explicitly denied (vs. no rule allowing it)
+	Reason  string `json:"reason,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// ImpersonationSubject evaluates checks as another user via SubjectAccessReview
+// instead of SelfSubjectAccessReview (requires create on subjectaccessreviews)
+type ImpersonationSubject struct {
+	User   string   `json:"user"`
+	Groups []string `json:"groups,omitempty"`
+}
+
+var (
+	permissionCache   = make(map[string]permissionCacheEntry)
+	permissionCacheMu sync.Mutex
+)
+
+type permissionCacheEntry struct {
+	result  PermissionResult
+	expires time.Time
+}
+
+// CheckPermissions answers a batch of access questions in parallel.
+// Results are returned in request order and cached for the capabilities TTL.
+func CheckPermissions(ctx context.Context, checks []PermissionCheck, subject *ImpersonationSubject) ([]PermissionResult, error) {
+	if len(checks) > MaxPermissionChecks {
+		return nil, fmt.Errorf("%w: %d (max %d)", ErrTooManyChecks, len(checks), MaxPermissionChecks)
+	}
+	client := GetClient()
+	if client == nil {
+		return nil, fmt.Errorf("K8s client not initialized")
+	}
+
+	results := make([]PermissionResult, len(checks))
+	sem := make(chan struct{}, permissionCheckConcurrency)
+	var wg sync.WaitGroup
+
+	for i, check := range checks {
+		resolved, err := resolvePermissionCheck(check)
+		if err != nil {
+			results[i] = PermissionResult{PermissionCheck: check, Error: err.Error()}
+			continue
+		}
+
+		key := permissionCacheKey(resolved, subject)
+		if cached, ok := cachedPermission(key); ok {
+			cached.PermissionCheck = check
+			results[i] = cached
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, original, resolved PermissionCheck, key string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			res := reviewPermission(ctx, resolved, subject)
+			if res.Error == "" {
+				storePermission(key, res)
+			}
+			res.PermissionCheck = original
+			results[i] = res
+		}(i, check, resolved, key)
+	}
+
+	wg.Wait()
+	return results, nil
+}
+
+// resolvePermissionCheck fills in group/resource from Kind and normalizes the verb
+func resolvePermissionCheck(check PermissionCheck) (PermissionCheck, error) {
+	check.Verb = strings.ToLower(strings.TrimSpace(check.Verb))
+	if check.Verb == "" {
+		return check, fmt.Errorf("verb is required")
+	}
+	if check.Resource == "" {
+		if check.Kind == "" {
+			return check, fmt.Errorf("kind or resource is required")
+		}
+		discovery := GetResourceDiscovery()
+		if discovery == nil {
+			return check, fmt.Errorf("resource discovery not initialized")
+		}
+		gvr, ok := discovery.GetGVR(check.Kind)
+		if !ok {
+			return check, fmt.Errorf("unknown resource kind: %s", check.Kind)
+		}
+		check.Group = gvr.Group
+		check.Resource = gvr.Resource
+	}
+	return check, nil
+}
+
+// reviewPermission runs a single (Self)SubjectAccessReview
+func reviewPermission(ctx context.Context, check PermissionCheck, subject *ImpersonationSubject) PermissionResult {
+	attrs := &authv1.ResourceAttributes{
+		Namespace:   check.Namespace,
+		Verb:        check.Verb,
+		Group:       check.Group,
+		Resource:    check.Resource,
+		Subresource: check.Subresource,
+		Name:        check.Name,
+	}
+	result := PermissionResult{PermissionCheck: check}
+	client := GetClient()
+
+	var status authv1.SubjectAccessReviewStatus
+	if subject != nil && subject.User != "" {
+		review := &authv1.SubjectAccessReview{
+			Spec: authv1.SubjectAccessReviewSpec{
+				ResourceAttributes: attrs,
+				User:               subject.User,
+				Groups:             subject.Groups,
+			},
+		}
+		resp, err := client.AuthorizationV1().SubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+		if err != nil {
+			result.Error = fmt.Sprintf("SubjectAccessReview failed: %v", err)
+			return result
+		}
+		status = resp.Status
+	} else {
+		review := &authv1.SelfSubjectAccessReview{
+			Spec: authv1.SelfSubjectAccessReviewSpec{ResourceAttributes: attrs},
+		}
+		resp, err := client.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+		if err != nil {
+			result.Error = fmt.Sprintf("SelfSubjectAccessReview failed: %v", err)
+			return result
+		}
+		status = resp.Status
+	}
+
+	result.Allowed = status.Allowed
+	result.Denied = status.Denied
+	result.Reason = status.Reason
+	if status.EvaluationError != "" && result.Reason == "" {
+		result.Reason = status.EvaluationError
+	}
+	return result
+}
+
+func permissionCacheKey(check PermissionCheck, subject *ImpersonationSubject) string {
+	who := "self"
+	if subject != nil && subject.User != "" {
+		groups := append([]string(nil), subject.Groups...)
+		sort.Strings(groups)
+		who = "user:" + subject.User + "|" + strings.Join(groups, ",")
+	}
+	return strings.Join([]string{who, check.Verb, check.Group, check.Resource, check.Subresource, check.Namespace, check.Name}, "/")
+}
+
+func cachedPermission(key string) (PermissionResult, bool) {
+	permissionCacheMu.Lock()
+	defer permissionCacheMu.Unlock()
+	entry, ok := permissionCache[key]
+	if !ok || time.Now().After(entry.expires) {
+		return PermissionResult{}, false
+	}
+	return entry.result, true
+}
+
+func storePermission(key string, result PermissionResult) {
+	permissionCacheMu.Lock()
+	defer permissionCacheMu.Unlock()
+	now := time.Now()
+	// Opportunistically prune expired entries to keep the cache bounded
+	if len(permissionCache) > 5000 {
+		for k, e := range permissionCache {
+			if now.After(e.expires) {
+				delete(permissionCache, k)
+			}
+		}
+	}
+	permissionCache[key] = permissionCacheEntry{result: result, expires: now.Add(capabilitiesTTL)}
+}
+
+// invalidatePermissionCache drops cached preflight answers (RBAC differs per cluster)
+func invalidatePermissionCache() {
+	permissionCacheMu.Lock()
+	defer permissionCacheMu.Unlock()
+	permissionCache = make(map[string]permissionCacheEntry)
+}
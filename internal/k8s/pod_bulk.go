@@ -0,0 +1,141 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+)
+
+// PodFilter selects pods for bulk operations. Empty fields match everything.
+type PodFilter struct {
+	Namespace string
+	Selector  string // Label selector, e.g. "app=foo,tier!=db"
+	Node      string
+}
+
+// PodsMatching returns the cached pods matching filter, sorted by namespace and name
+func (c *ResourceCache) PodsMatching(filter PodFilter) ([]*corev1.Pod, error) {
+	if c == nil {
+		return nil, explorerErrors.CacheNotInitialized()
+	}
+	selector, err := labels.Parse(filter.Selector)
+	if err != nil {
+		return nil, explorerErrors.ValidationError(fmt.Sprintf("invalid label selector %q: %v", filter.Selector, err))
+	}
+	lister := c.Pods()
+
+	var pods []*corev1.Pod
+	switch {
+	case filter.Node != "":
+		pods = c.PodsOnNode(filter.Node) // Filtered by namespace and selector below
+	case filter.Namespace != "":
+		pods, err = lister.Pods(filter.Namespace).List(selector)
+	default:
+		pods, err = lister.List(selector)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	matched := make([]*corev1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if filter.Node != "" {
+			if filter.Namespace != "" && pod.Namespace != filter.Namespace {
+				continue
+			}
+			if !selector.Matches(labels.Set(pod.Labels)) {
+				continue
+			}
+		}
+		matched = append(matched, pod)
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		if matched[i].Namespace != matched[j].Namespace {
+			return matched[i].Namespace < matched[j].Namespace
+		}
+		return matched[i].Name < matched[j].Name
+	})
+	return matched, nil
+}
+
+// PodWorkload resolves a pod to the workload that manages it: a Deployment (or Rollout)
+// through its ReplicaSet, and a CronJob through its Job. Pods without a controller
+// return empty strings.
+func (c *ResourceCache) PodWorkload(pod *corev1.Pod) (kind, name string) {
+	ref := metav1.GetControllerOf(pod)
+	if ref == nil {
+		return "", ""
+	}
+	var parent *metav1.OwnerReference
+	switch ref.Kind {
+	case "ReplicaSet":
+		if rs, err := c.ReplicaSets().ReplicaSets(pod.Namespace).Get(ref.Name); err == nil {
+			parent = metav1.GetControllerOf(rs)
+		}
+	case "Job":
+		if job, err := c.Jobs().Jobs(pod.Namespace).Get(ref.Name); err == nil {
+			parent = metav1.GetControllerOf(job)
+		}
+	}
+	if parent != nil {
+		return parent.Kind, parent.Name
+	}
+	return ref.Kind, ref.Name
+}
+
+// PodDisruptionBudgets lists PDBs in namespace (empty = all) through the dynamic cache,
+// since there is no typed informer
+func (c *ResourceCache) PodDisruptionBudgets(ctx context.Context, namespace string) []policyv1.PodDisruptionBudget {
+	items, err := c.ListDynamic(ctx, "PodDisruptionBudget", namespace)
+	if err != nil {
+		return nil
+	}
+	pdbs := make([]policyv1.PodDisruptionBudget, 0, len(items))
+	for _, item := range items {
+		var pdb policyv1.PodDisruptionBudget
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &pdb); err == nil {
+			pdbs = append(pdbs, pdb)
+		}
+	}
+	return pdbs
+}
+
+// PDBSelectsPod reports whether the PDB covers the pod
+func PDBSelectsPod(pdb *policyv1.PodDisruptionBudget, pod *corev1.Pod) bool {
+	if pdb.Namespace != pod.Namespace || pdb.Spec.Selector == nil {
+		return false
+	}
+	selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+	return err == nil && !selector.Empty() && selector.Matches(labels.Set(pod.Labels))
+}
+
+// EvictPod evicts a pod through the Eviction API, which refuses when a PodDisruptionBudget
+// would be violated (see IsEvictionBlocked)
+func EvictPod(ctx context.Context, namespace, name string, gracePeriodSeconds *int64) error {
+	client, err := ClientFor(ctx)
+	if err != nil {
+		return err
+	}
+	eviction := &policyv1.Eviction{
+		ObjectMeta:    metav1.ObjectMeta{Name: name, Namespace: namespace},
+		DeleteOptions: &metav1.DeleteOptions{GracePeriodSeconds: gracePeriodSeconds},
+	}
+	if err := client.PolicyV1().Evictions(namespace).Evict(ctx, eviction); err != nil {
+		return fmt.Errorf("failed to evict pod %s/%s: %w", namespace, name, err)
+	}
+	return nil
+}
+
+// IsEvictionBlocked reports whether an eviction was refused to honor a PodDisruptionBudget
+func IsEvictionBlocked(err error) bool {
+	return apierrors.IsTooManyRequests(err)
+}
@@ -0,0 +1,252 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/jsonpath"
+
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+)
+
+// printerColumnsTTL is how long a CRD's columns are reused before it's fetched again
+const printerColumnsTTL = 5 * time.Minute
+
+var crdGVR = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+// PrinterColumn is a column kubectl get prints for a custom resource, from its CRD's
+// additionalPrinterColumns
+type PrinterColumn struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"` // integer, number, string, boolean or date
+	Format      string `json:"format,omitempty"`
+	Description string `json:"description,omitempty"`
+	Priority    int32  `json:"priority,omitempty"` // Above 0: only in kubectl get -o wide
+	JSONPath    string `json:"jsonPath"`
+}
+
+// ResourceTable lists custom resources with their CRD's columns
+type ResourceTable struct {
+	Kind    string          `json:"kind"`
+	Group   string          `json:"group"`
+	Version string          `json:"version"`
+	Columns []PrinterColumn `json:"columns"`
+	Rows    []TableRow      `json:"rows"`
+}
+
+// TableRow is one resource in a ResourceTable. Cells line up with the columns: integers,
+// numbers, booleans and strings as JSON values, dates as RFC 3339 timestamps (kubectl
+// shows them as ages), and null where the path matches nothing.
+type TableRow struct {
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Cells     []any  `json:"cells"`
+}
+
+// ageColumn is what the API server prints for CRDs that declare no columns
+var ageColumn = PrinterColumn{Name: "Age", Type: "date", JSONPath: ".metadata.creationTimestamp"}
+
+type printerColumnsEntry struct {
+	columns []PrinterColumn
+	fetched time.Time
+}
+
+var (
+	printerColumnsMu    sync.Mutex
+	printerColumnsCache = map[string]printerColumnsEntry{} // By CRD name and version
+)
+
+// clearPrinterColumns forgets cached CRD columns (on context switch)
+func clearPrinterColumns() {
+	printerColumnsMu.Lock()
+	defer printerColumnsMu.Unlock()
+	printerColumnsCache = map[string]printerColumnsEntry{}
+}
+
+// ResourceTable lists a custom resource kind from the dynamic cache with the columns its
+// CRD declares for kubectl get, in one namespace or all (namespace ""). group picks the
+// API group when the kind exists in several.
+func (c *ResourceCache) ResourceTable(ctx context.Context, kind, group, namespace string) (*ResourceTable, error) {
+	discovery := GetResourceDiscovery()
+	if discovery == nil {
+		return nil, fmt.Errorf("resource discovery not initialized")
+	}
+	var gvr schema.GroupVersionResource
+	var ok bool
+	if group != "" {
+		gvr, ok = discovery.GetGVRWithGroup(kind, group)
+	} else {
+		gvr, ok = discovery.GetGVR(kind)
+	}
+	if !ok {
+		return nil, explorerErrors.ValidationError(fmt.Sprintf("unknown resource kind: %s", kind))
+	}
+	res, _ := discovery.getResourceForGVR(gvr)
+	if !res.IsCRD {
+		return nil, explorerErrors.ValidationError(fmt.Sprintf("%s is not a custom resource", res.Kind))
+	}
+
+	columns, err := crdPrinterColumns(ctx, gvr)
+	if err != nil {
+		return nil, err
+	}
+	dynamicCache := GetDynamicResourceCache()
+	if dynamicCache == nil {
+		return nil, fmt.Errorf("dynamic resource cache not initialized")
+	}
+	items, err := dynamicCache.List(gvr, namespace)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := tableRows(columns, items)
+	if err != nil {
+		return nil, err
+	}
+	return &ResourceTable{Kind: res.Kind, Group: gvr.Group, Version: gvr.Version, Columns: columns, Rows: rows}, nil
+}
+
+// crdPrinterColumns returns the columns the CRD behind gvr declares for its version,
+// fetching the CRD at most every printerColumnsTTL
+func crdPrinterColumns(ctx context.Context, gvr schema.GroupVersionResource) ([]PrinterColumn, error) {
+	name := gvr.Resource + "." + gvr.Group
+	key := name + "/" + gvr.Version
+	printerColumnsMu.Lock()
+	entry, ok := printerColumnsCache[key]
+	printerColumnsMu.Unlock()
+	if ok && time.Since(entry.fetched) < printerColumnsTTL {
+		return entry.columns, nil
+	}
+
+	client := GetDynamicClient()
+	if client == nil {
+		return nil, fmt.Errorf("dynamic client not initialized")
+	}
+	obj, err := client.Resource(crdGVR).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("getting CRD %s: %w", name, err)
+	}
+	var crd apiextensionsv1.CustomResourceDefinition
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &crd); err != nil {
+		return nil, fmt.Errorf("decoding CRD %s: %w", name, err)
+	}
+
+	var columns []PrinterColumn
+	for _, v := range crd.Spec.Versions {
+		if v.Name != gvr.Version {
+			continue
+		}
+		for _, col := range v.AdditionalPrinterColumns {
+			columns = append(columns, PrinterColumn{
+				Name:        col.Name,
+				Type:        col.Type,
+				Format:      col.Format,
+				Description: col.Description,
+				Priority:    col.Priority,
+				JSONPath:    col.JSONPath,
+			})
+		}
+	}
+	if len(columns) == 0 {
+		columns = []PrinterColumn{ageColumn}
+	}
+
+	printerColumnsMu.Lock()
+	printerColumnsCache[key] = printerColumnsEntry{columns: columns, fetched: time.Now()}
+	printerColumnsMu.Unlock()
+	return columns, nil
+}
+
+// tableRows evaluates the columns' JSONPaths against each item, like the API server does
+// for kubectl get, sorted by namespace and name
+func tableRows(columns []PrinterColumn, items []*unstructured.Unstructured) ([]TableRow, error) {
+	paths := make([]*jsonpath.JSONPath, len(columns))
+	for i, col := range columns {
+		path := jsonpath.New(col.Name)
+		if err := path.Parse("{" + col.JSONPath + "}"); err != nil {
+			return nil, fmt.Errorf("column %q has an unsupported path %q: %w", col.Name, col.JSONPath, err)
+		}
+		path.AllowMissingKeys(true)
+		paths[i] = path
+	}
+
+	rows := make([]TableRow, 0, len(items))
+	var buf bytes.Buffer
+	for _, item := range items {
+		row := TableRow{Namespace: item.GetNamespace(), Name: item.GetName(), Cells: make([]any, len(columns))}
+		for i, path := range paths {
+			results, err := path.FindResults(item.UnstructuredContent())
+			if err != nil || len(results) == 0 || len(results[0]) == 0 {
+				continue
+			}
+			value := results[0][0].Interface()
+			if columns[i].Type == "string" {
+				// Non-string values (a list, a map) print the way kubectl shows them
+				if err := path.PrintResults(&buf, []reflect.Value{reflect.ValueOf(value)}); err == nil {
+					row.Cells[i] = buf.String()
+				}
+				buf.Reset()
+				continue
+			}
+			row.Cells[i] = cellValue(columns[i].Type, value)
+		}
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Namespace != rows[j].Namespace {
+			return rows[i].Namespace < rows[j].Namespace
+		}
+		return rows[i].Name < rows[j].Name
+	})
+	return rows, nil
+}
+
+// cellValue converts a value to its column's type, or nil when it doesn't fit
+func cellValue(columnType string, value any) any {
+	switch columnType {
+	case "integer":
+		switch v := value.(type) {
+		case int64:
+			return v
+		case float64:
+			return int64(v)
+		case json.Number:
+			if i, err := v.Int64(); err == nil {
+				return i
+			}
+		}
+	case "number":
+		switch v := value.(type) {
+		case int64:
+			return float64(v)
+		case float64:
+			return v
+		case json.Number:
+			if f, err := v.Float64(); err == nil {
+				return f
+			}
+		}
+	case "boolean":
+		if b, ok := value.(bool); ok {
+			return b
+		}
+	case "date":
+		if s, ok := value.(string); ok {
+			var t metav1.Time
+			if err := t.UnmarshalQueryParameter(s); err == nil {
+				return t.UTC().Format(time.RFC3339)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,241 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+)
+
+// Filesystem usage thresholds for PVCs (percent of capacity, bytes or inodes)
+const (
+	PVCUsageWarningPercent  = 80
+	PVCUsageCriticalPercent = 90
+)
+
+// PVC usage statuses
+const (
+	PVCUsageOK       = "ok"
+	PVCUsageWarning  = "warning"
+	PVCUsageCritical = "critical"
+	PVCUsageUnknown  = "unknown" // Not mounted, or the kubelet didn't report it
+)
+
+// PVCUsage is a PVC's filesystem usage as last reported by the kubelet, with what's
+// needed to decide whether it can be expanded
+type PVCUsage struct {
+	Namespace         string     `json:"namespace"`
+	Name              string     `json:"name"`
+	StorageClass      string     `json:"storageClass,omitempty"`
+	Phase             string     `json:"phase"`
+	Requested         string     `json:"requested,omitempty"` // spec.resources.requests.storage
+	Capacity          string     `json:"capacity,omitempty"`  // status.capacity.storage
+	UsedBytes         int64      `json:"usedBytes,omitempty"`
+	CapacityBytes     int64      `json:"capacityBytes,omitempty"` // Filesystem size seen by the kubelet
+	UsedPercent       *float64   `json:"usedPercent,omitempty"`
+	InodesUsedPercent *float64   `json:"inodesUsedPercent,omitempty"`
+	Status            string     `json:"status"`             // ok, warning, critical, unknown
+	Abnormal          bool       `json:"abnormal,omitempty"` // CSI volume health reported a problem
+	Expandable        bool       `json:"expandable"`         // The StorageClass sets allowVolumeExpansion
+	Resizing          string     `json:"resizing,omitempty"` // Resize condition in progress (Resizing, FileSystemResizePending)
+	Message           string     `json:"message,omitempty"`
+	SampledAt         *time.Time `json:"sampledAt,omitempty"`
+}
+
+// pvcUsageStatus classifies a usage percentage against the thresholds
+func pvcUsageStatus(percent float64) string {
+	switch {
+	case percent >= PVCUsageCriticalPercent:
+		return PVCUsageCritical
+	case percent >= PVCUsageWarningPercent:
+		return PVCUsageWarning
+	}
+	return PVCUsageOK
+}
+
+// PVCUsages returns the usage of every cached PVC, optionally in one namespace, fullest
+// first. PVCs that no running pod mounts have no kubelet stats and report status unknown.
+func (f *UsageForecaster) PVCUsages(ctx context.Context, namespace string) ([]PVCUsage, error) {
+	cache := GetResourceCache()
+	if cache == nil || cache.PersistentVolumeClaims() == nil {
+		return nil, explorerErrors.New(explorerErrors.ErrCacheNotInitialized, "resource cache not initialized")
+	}
+	var claims []*corev1.PersistentVolumeClaim
+	var err error
+	if namespace != "" {
+		claims, err = cache.PersistentVolumeClaims().PersistentVolumeClaims(namespace).List(labels.Everything())
+	} else {
+		claims, err = cache.PersistentVolumeClaims().List(labels.Everything())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list persistent volume claims: %w", err)
+	}
+
+	var volumes map[string]pvcVolumeUsage
+	if f != nil {
+		f.mu.RLock()
+		volumes = f.volumes
+		f.mu.RUnlock()
+	}
+
+	classes := make(map[string]*storagev1.StorageClass)
+	result := make([]PVCUsage, 0, len(claims))
+	for _, pvc := range claims {
+		u := PVCUsage{
+			Namespace: pvc.Namespace,
+			Name:      pvc.Name,
+			Phase:     string(pvc.Status.Phase),
+			Status:    PVCUsageUnknown,
+			Resizing:  pvcResizeCondition(pvc),
+		}
+		if q, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+			u.Requested = q.String()
+		}
+		if q, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+			u.Capacity = q.String()
+		}
+		if pvc.Spec.StorageClassName != nil && *pvc.Spec.StorageClassName != "" {
+			u.StorageClass = *pvc.Spec.StorageClassName
+			sc, seen := classes[u.StorageClass]
+			if !seen {
+				sc = getStorageClass(ctx, cache, u.StorageClass)
+				classes[u.StorageClass] = sc
+			}
+			u.Expandable = sc != nil && sc.AllowVolumeExpansion != nil && *sc.AllowVolumeExpansion
+		}
+
+		if vol, ok := volumes[pvc.Namespace+"/"+pvc.Name]; ok {
+			applyVolumeStats(&u, vol)
+		}
+		result = append(result, u)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		pi, pj := -1.0, -1.0
+		if result[i].UsedPercent != nil {
+			pi = *result[i].UsedPercent
+		}
+		if result[j].UsedPercent != nil {
+			pj = *result[j].UsedPercent
+		}
+		if pi != pj {
+			return pi > pj
+		}
+		if result[i].Namespace != result[j].Namespace {
+			return result[i].Namespace < result[j].Namespace
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// applyVolumeStats fills in usage from the kubelet's stats. Inodes count against the
+// same thresholds as bytes, since running out of either fails writes.
+func applyVolumeStats(u *PVCUsage, vol pvcVolumeUsage) {
+	sampledAt := vol.sampledAt
+	u.SampledAt = &sampledAt
+	u.UsedBytes = int64(vol.used)
+	u.CapacityBytes = int64(vol.capacity)
+	used := math.Round(vol.used/vol.capacity*1000) / 10
+	u.UsedPercent = &used
+	u.Status = pvcUsageStatus(used)
+	u.Message = fmt.Sprintf("%.1f%% of %s used", used, resource.NewQuantity(u.CapacityBytes, resource.BinarySI))
+
+	if vol.inodes > 0 {
+		inodes := math.Round(vol.inodesUsed/vol.inodes*1000) / 10
+		u.InodesUsedPercent = &inodes
+		if inodes > used && pvcUsageStatus(inodes) != PVCUsageOK {
+			u.Status = pvcUsageStatus(inodes)
+			u.Message = fmt.Sprintf("%.1f%% of inodes used", inodes)
+		}
+	}
+	if vol.abnormal {
+		u.Abnormal = true
+		u.Status = PVCUsageCritical
+		u.Message = "volume health check reported the volume as abnormal; " + u.Message
+	}
+}
+
+// pvcResizeCondition returns the resize condition the PVC is in, if any
+func pvcResizeCondition(pvc *corev1.PersistentVolumeClaim) string {
+	for _, cond := range pvc.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case corev1.PersistentVolumeClaimResizing, corev1.PersistentVolumeClaimFileSystemResizePending,
+			corev1.PersistentVolumeClaimControllerResizeError, corev1.PersistentVolumeClaimNodeResizeError:
+			return string(cond.Type)
+		}
+	}
+	return ""
+}
+
+// getStorageClass returns a StorageClass from the dynamic cache (nil if it can't be read)
+func getStorageClass(ctx context.Context, cache *ResourceCache, name string) *storagev1.StorageClass {
+	obj, err := cache.GetDynamic(ctx, "StorageClass", "", name)
+	if err != nil {
+		return nil
+	}
+	var sc storagev1.StorageClass
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &sc); err != nil {
+		return nil
+	}
+	return &sc
+}
+
+// ExpandPVC raises a bound PVC's storage request to size. The StorageClass must allow
+// volume expansion, and the new size must be larger than the current request since
+// volumes can't shrink. Returns the previous request.
+func ExpandPVC(ctx context.Context, namespace, name, size string) (string, error) {
+	want, err := resource.ParseQuantity(size)
+	if err != nil {
+		return "", explorerErrors.New(explorerErrors.ErrBadRequest, fmt.Sprintf("invalid size %q: %v", size, err))
+	}
+
+	client, err := ClientFor(ctx)
+	if err != nil {
+		return "", err
+	}
+	pvc, err := client.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get persistent volume claim: %w", err)
+	}
+	if pvc.Status.Phase != corev1.ClaimBound {
+		return "", explorerErrors.New(explorerErrors.ErrConflict, fmt.Sprintf("PVC %s is %s, only bound claims can be expanded", name, pvc.Status.Phase))
+	}
+	current := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	if want.Cmp(current) <= 0 {
+		return "", explorerErrors.New(explorerErrors.ErrBadRequest, fmt.Sprintf("size %s must be larger than the current request %s (volumes can't shrink)", want.String(), current.String()))
+	}
+
+	if pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName == "" {
+		return "", explorerErrors.New(explorerErrors.ErrConflict, fmt.Sprintf("PVC %s has no storage class, so it can't be expanded", name))
+	}
+	className := *pvc.Spec.StorageClassName
+	sc, err := client.StorageV1().StorageClasses().Get(ctx, className, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get storage class %s: %w", className, err)
+	}
+	if sc.AllowVolumeExpansion == nil || !*sc.AllowVolumeExpansion {
+		return "", explorerErrors.New(explorerErrors.ErrConflict, fmt.Sprintf("storage class %s doesn't allow volume expansion", className))
+	}
+
+	patch := fmt.Sprintf(`{"spec":{"resources":{"requests":{"storage":%q}}}}`, want.String())
+	_, err = client.CoreV1().PersistentVolumeClaims(namespace).Patch(ctx, name, types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to patch persistent volume claim: %w", err)
+	}
+	return current.String(), nil
+}
@@ -0,0 +1,193 @@
+package k8s
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// quotaDisplayResources are the quota resources shown as utilization, in display order.
+// "cpu" and "memory" are the legacy names for requests.cpu and requests.memory.
+var quotaDisplayResources = []corev1.ResourceName{
+	corev1.ResourcePods,
+	corev1.ResourceRequestsCPU, corev1.ResourceCPU,
+	corev1.ResourceRequestsMemory, corev1.ResourceMemory,
+	corev1.ResourceLimitsCPU, corev1.ResourceLimitsMemory,
+}
+
+// QuotaUtilization is how much of one ResourceQuota is used
+type QuotaUtilization struct {
+	Namespace string               `json:"namespace"`
+	Name      string               `json:"name"`
+	Resources []QuotaResourceUsage `json:"resources"`
+	// MaxPercent is the highest utilization of any resource, for sorting
+	MaxPercent int `json:"maxPercent"`
+}
+
+// QuotaResourceUsage is used vs hard for one quota resource
+type QuotaResourceUsage struct {
+	Resource string `json:"resource"` // e.g. pods, requests.cpu
+	Used     string `json:"used"`
+	Hard     string `json:"hard"`
+	Percent  int    `json:"percent"`
+}
+
+// GetQuotaUtilization returns pods, CPU and memory utilization of a quota
+func GetQuotaUtilization(q *corev1.ResourceQuota) QuotaUtilization {
+	u := QuotaUtilization{Namespace: q.Namespace, Name: q.Name, Resources: []QuotaResourceUsage{}}
+	for _, res := range quotaDisplayResources {
+		hard, ok := q.Status.Hard[res]
+		if !ok {
+			continue
+		}
+		used := q.Status.Used[res]
+		pct := 100
+		if !hard.IsZero() {
+			pct = int(used.AsApproximateFloat64() / hard.AsApproximateFloat64() * 100)
+		}
+		u.Resources = append(u.Resources, QuotaResourceUsage{Resource: string(res), Used: used.String(), Hard: hard.String(), Percent: pct})
+		u.MaxPercent = max(u.MaxPercent, pct)
+	}
+	return u
+}
+
+// PodQuotaUsage returns what one pod with this spec counts against a namespace's quotas:
+// one pod plus its effective requests and limits, with LimitRange container defaults
+// filled in the way admission does. Requests are also listed under the legacy cpu and
+// memory names. Resources the pod leaves unset (after defaults) are omitted.
+func PodQuotaUsage(spec corev1.PodSpec, limitRanges []*corev1.LimitRange) corev1.ResourceList {
+	var defaults []corev1.LimitRangeItem
+	for _, lr := range limitRanges {
+		for _, item := range lr.Spec.Limits {
+			if item.Type == corev1.LimitTypeContainer {
+				defaults = append(defaults, item)
+			}
+		}
+	}
+
+	// Effective pod resources: the sum over containers, or the largest init container if
+	// that's more. Every container must set a resource for the pod to count it.
+	usage := corev1.ResourceList{corev1.ResourcePods: resource.MustParse("1")}
+	for _, res := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		for _, limits := range []bool{false, true} {
+			name := "requests." + res
+			if limits {
+				name = "limits." + res
+			}
+			var total, initMax resource.Quantity
+			complete := len(spec.Containers) > 0
+			for _, c := range spec.Containers {
+				q, ok := containerResource(c, defaults, res, limits)
+				if !ok {
+					complete = false
+					break
+				}
+				total.Add(q)
+			}
+			for _, c := range spec.InitContainers {
+				if q, ok := containerResource(c, defaults, res, limits); ok && q.Cmp(initMax) > 0 {
+					initMax = q
+				}
+			}
+			if !complete {
+				continue
+			}
+			if initMax.Cmp(total) > 0 {
+				total = initMax
+			}
+			usage[name] = total
+			if !limits {
+				usage[res] = total
+			}
+		}
+	}
+	return usage
+}
+
+// containerResource returns a container's request (or limit) for res with LimitRange
+// defaults applied. An unset request takes the default request, then the limit, as
+// admission does.
+func containerResource(c corev1.Container, defaults []corev1.LimitRangeItem, res corev1.ResourceName, limits bool) (resource.Quantity, bool) {
+	limit, hasLimit := c.Resources.Limits[res]
+	for _, item := range defaults {
+		if q, ok := item.Default[res]; ok && !hasLimit {
+			limit, hasLimit = q, true
+		}
+	}
+	if limits {
+		return limit, hasLimit
+	}
+	if q, ok := c.Resources.Requests[res]; ok {
+		return q, true
+	}
+	for _, item := range defaults {
+		if q, ok := item.DefaultRequest[res]; ok {
+			return q, true
+		}
+	}
+	return limit, hasLimit
+}
+
+// QuotaShortfall explains why pods with the given per-pod usage wouldn't be admitted
+// under the namespace's quotas: a resource with less remaining than they need, or a
+// quota on requests or limits the pods don't set. Quotas with scopes are skipped since
+// whether they apply depends on the pod. Returns nil when the pods fit.
+func QuotaShortfall(quotas []*corev1.ResourceQuota, perPod corev1.ResourceList, pods int64) []string {
+	var issues []string
+	for _, q := range quotas {
+		if len(q.Spec.Scopes) > 0 || q.Spec.ScopeSelector != nil {
+			continue
+		}
+		for res, hard := range q.Status.Hard {
+			if !isComputeQuotaResource(res) {
+				continue
+			}
+			each, ok := perPod[res]
+			if !ok {
+				issues = append(issues, fmt.Sprintf("quota %s limits %s, which the pods don't set", q.Name, res))
+				continue
+			}
+			need := each.DeepCopy()
+			need.Mul(pods)
+			remaining := hard.DeepCopy()
+			remaining.Sub(q.Status.Used[res])
+			if need.Cmp(remaining) > 0 {
+				left := remaining.String()
+				if remaining.Sign() < 0 {
+					left = "0"
+				}
+				issues = append(issues, fmt.Sprintf("quota %s: %d more pod(s) need %s %s, %s of %s left", q.Name, pods, need.String(), res, left, hard.String()))
+			}
+		}
+	}
+	sort.Strings(issues)
+	return issues
+}
+
+// isComputeQuotaResource reports whether a quota resource is counted from pod specs
+func isComputeQuotaResource(res corev1.ResourceName) bool {
+	switch res {
+	case corev1.ResourcePods, corev1.ResourceCPU, corev1.ResourceMemory:
+		return true
+	}
+	name := string(res)
+	return name == "requests.cpu" || name == "requests.memory" || name == "limits.cpu" || name == "limits.memory" ||
+		strings.HasPrefix(name, "count/pods")
+}
+
+// isQuotaUsageUpdate reports whether a ResourceQuota update only changed its usage. The
+// quota controller rewrites status whenever pods come and go, which would flood the
+// timeline; changes to the hard limits are kept.
+func isQuotaUsageUpdate(kind string, oldObj, newObj any) bool {
+	if kind != "ResourceQuota" {
+		return false
+	}
+	o, ok1 := oldObj.(*corev1.ResourceQuota)
+	n, ok2 := newObj.(*corev1.ResourceQuota)
+	return ok1 && ok2 && equality.Semantic.DeepEqual(o.Spec, n.Spec) &&
+		equality.Semantic.DeepEqual(o.Status.Hard, n.Status.Hard)
+}
@@ -0,0 +1,252 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/skyhook-io/radar/internal/timeline"
+)
+
+const (
+	// replicaFlapWindow is how far back the timeline is searched for replica flapping
+	replicaFlapWindow = 30 * time.Minute
+	// maxFlapValues caps the replica sequence quoted in a finding
+	maxFlapValues = 8
+	// defaultScaleDownStabilization is the HPA scale-down stabilization window when unset
+	defaultScaleDownStabilization = 300
+)
+
+// replicaOwners is everything that sets the replica count of workloads in one pass
+type replicaOwners struct {
+	hpas     map[string][]*autoscalingv2.HorizontalPodAutoscaler // By target kind/namespace/name
+	rollouts map[string]string                                   // Deployment namespace/name -> Rollout referencing it via workloadRef
+}
+
+func (o replicaOwners) hpaNames(kind, namespace, name string) []string {
+	var names []string
+	for _, hpa := range o.hpas[kind+"/"+namespace+"/"+name] {
+		names = append(names, hpa.Name)
+	}
+	return names
+}
+
+// checkReplicaConflicts finds workloads whose replica count is set by controllers that
+// disagree: several HPAs, an HPA and an Argo Rollout, or an HPA and a GitOps controller
+// re-applying spec.replicas. Conflicts that only show up as behavior are detected from
+// spec.replicas flapping on the timeline.
+func checkReplicaConflicts(ctx context.Context, cache *ResourceCache) []ConsistencyFinding {
+	owners := replicaOwners{
+		hpas:     make(map[string][]*autoscalingv2.HorizontalPodAutoscaler),
+		rollouts: make(map[string]string),
+	}
+	if cache.HasTypedInformer("HorizontalPodAutoscaler") {
+		if hpas, err := cache.HorizontalPodAutoscalers().List(labels.Everything()); err == nil {
+			for _, hpa := range hpas {
+				ref := hpa.Spec.ScaleTargetRef
+				key := ref.Kind + "/" + hpa.Namespace + "/" + ref.Name
+				owners.hpas[key] = append(owners.hpas[key], hpa)
+			}
+		}
+	}
+	// Argo Rollouts with workloadRef scale the referenced Deployment to zero
+	if rollouts, err := cache.ListDynamic(ctx, "Rollout", ""); err == nil {
+		for _, ro := range rollouts {
+			kind, _, _ := unstructured.NestedString(ro.Object, "spec", "workloadRef", "kind")
+			name, _, _ := unstructured.NestedString(ro.Object, "spec", "workloadRef", "name")
+			if kind == "Deployment" && name != "" {
+				owners.rollouts[ro.GetNamespace()+"/"+name] = ro.GetName()
+			}
+		}
+	}
+
+	var findings []ConsistencyFinding
+	keys := make([]string, 0, len(owners.hpas))
+	for key := range owners.hpas {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, hpa := range owners.hpas[key] {
+			findings = append(findings, checkHPAReplicaConflicts(cache, owners, hpa)...)
+		}
+	}
+
+	flaps, err := timeline.QueryReplicaFlaps(ctx, time.Now().Add(-replicaFlapWindow))
+	if err != nil {
+		log.Printf("Replica conflict check: failed to query timeline: %v", err)
+	}
+	for _, flap := range flaps {
+		findings = append(findings, ConsistencyFinding{
+			Kind:      flap.Kind,
+			Namespace: flap.Namespace,
+			Name:      flap.Name,
+			Check:     "replica-conflict",
+			Severity:  "warning",
+			Reason:    "Replica count flapping",
+			Message: fmt.Sprintf("spec.replicas changed %d times in %s (%s): %s",
+				flap.Changes(), replicaFlapWindow, formatReplicaValues(flap.Values), explainFlap(cache, owners, flap)),
+			Events: flap.EventIDs,
+		})
+	}
+	return findings
+}
+
+// checkHPAReplicaConflicts checks one HPA against the other controllers of its target
+func checkHPAReplicaConflicts(cache *ResourceCache, owners replicaOwners, hpa *autoscalingv2.HorizontalPodAutoscaler) []ConsistencyFinding {
+	ref := hpa.Spec.ScaleTargetRef
+	finding := func(severity, reason, message string) ConsistencyFinding {
+		return ConsistencyFinding{
+			Kind:      "HorizontalPodAutoscaler",
+			Namespace: hpa.Namespace,
+			Name:      hpa.Name,
+			Check:     "replica-conflict",
+			Severity:  severity,
+			Reason:    reason,
+			Message:   message,
+		}
+	}
+
+	var findings []ConsistencyFinding
+	if names := owners.hpaNames(ref.Kind, hpa.Namespace, ref.Name); len(names) > 1 {
+		findings = append(findings, finding("error", "Scale target shared with other HPAs",
+			fmt.Sprintf("%s/%s is scaled by HPAs %s, which overwrite each other's replica count",
+				ref.Kind, ref.Name, strings.Join(names, ", "))))
+	}
+	if ref.Kind == "Deployment" {
+		if rollout, ok := owners.rollouts[hpa.Namespace+"/"+ref.Name]; ok {
+			findings = append(findings, finding("error", "Scale target managed by Rollout",
+				fmt.Sprintf("Deployment %s is the workloadRef of Rollout %s, which keeps it at 0 replicas; target the Rollout instead",
+					ref.Name, rollout)))
+		}
+	}
+
+	minReplicas := int32(1)
+	if hpa.Spec.MinReplicas != nil {
+		minReplicas = *hpa.Spec.MinReplicas
+	}
+	var replicas *int32
+	switch ref.Kind {
+	case "Deployment":
+		d, err := cache.Deployments().Deployments(hpa.Namespace).Get(ref.Name)
+		if err != nil {
+			return findings
+		}
+		replicas = d.Spec.Replicas
+		if unavailable := rolloutUnavailable(d, minReplicas); unavailable >= minReplicas {
+			findings = append(findings, finding("warning", "Rollout can take all pods down at minReplicas",
+				fmt.Sprintf("At minReplicas %d, maxUnavailable %s lets a rollout of Deployment %s stop %d of %d pods; lower maxUnavailable or raise minReplicas",
+					minReplicas, d.Spec.Strategy.RollingUpdate.MaxUnavailable.String(), ref.Name, unavailable, minReplicas)))
+		}
+	case "StatefulSet":
+		s, err := cache.StatefulSets().StatefulSets(hpa.Namespace).Get(ref.Name)
+		if err != nil {
+			return findings
+		}
+		replicas = s.Spec.Replicas
+	}
+	if replicas != nil && *replicas == 0 {
+		findings = append(findings, finding("warning", "Scaling disabled by zero replicas",
+			fmt.Sprintf("%s/%s is scaled to 0, so the HPA stops scaling it until its replicas are set above 0", ref.Kind, ref.Name)))
+	}
+	return findings
+}
+
+// rolloutUnavailable returns how many pods a RollingUpdate may take down at the given
+// replica count (percentages round down, as the Deployment controller does)
+func rolloutUnavailable(d *appsv1.Deployment, replicas int32) int32 {
+	if d.Spec.Strategy.Type != appsv1.RollingUpdateDeploymentStrategyType ||
+		d.Spec.Strategy.RollingUpdate == nil || d.Spec.Strategy.RollingUpdate.MaxUnavailable == nil {
+		return 0
+	}
+	n, err := intstr.GetScaledValueFromIntOrPercent(d.Spec.Strategy.RollingUpdate.MaxUnavailable, int(replicas), false)
+	if err != nil {
+		return 0
+	}
+	return int32(n)
+}
+
+// explainFlap names the most likely cause of a workload's replica flapping
+func explainFlap(cache *ResourceCache, owners replicaOwners, flap timeline.ReplicaFlap) string {
+	hpas := owners.hpas[flap.Kind+"/"+flap.Namespace+"/"+flap.Name]
+	names := owners.hpaNames(flap.Kind, flap.Namespace, flap.Name)
+	gitops := gitOpsManager(cache, flap.Kind, flap.Namespace, flap.Name)
+
+	switch {
+	case len(hpas) > 1:
+		return fmt.Sprintf("HPAs %s each set their own replica count", strings.Join(names, ", "))
+	case len(hpas) == 1 && flap.Kind == "Deployment" && owners.rollouts[flap.Namespace+"/"+flap.Name] != "":
+		return fmt.Sprintf("HPA %s scales it up while Rollout %s scales it back to 0",
+			names[0], owners.rollouts[flap.Namespace+"/"+flap.Name])
+	case len(hpas) == 1 && gitops != "":
+		return fmt.Sprintf("HPA %s scales it while %s re-applies spec.replicas from its manifest; remove replicas from the manifest or have %s ignore /spec/replicas",
+			names[0], gitops, gitops)
+	case len(hpas) == 1:
+		window := int32(defaultScaleDownStabilization)
+		if b := hpas[0].Spec.Behavior; b != nil && b.ScaleDown != nil && b.ScaleDown.StabilizationWindowSeconds != nil {
+			window = *b.ScaleDown.StabilizationWindowSeconds
+		}
+		return fmt.Sprintf("HPA %s is oscillating; raise behavior.scaleDown.stabilizationWindowSeconds (currently %ds) or widen the metric target",
+			names[0], window)
+	case gitops != "":
+		return fmt.Sprintf("spec.replicas is changed outside %s, which reverts it to the manifest", gitops)
+	}
+	return "spec.replicas is changed by something other than an HPA (e.g. kubectl scale, a CI job or an operator)"
+}
+
+// gitOpsManager returns the GitOps controller that syncs a workload, from the tracking
+// labels and annotations Argo CD and Flux set
+func gitOpsManager(cache *ResourceCache, kind, namespace, name string) string {
+	var objLabels, objAnnotations map[string]string
+	switch kind {
+	case "Deployment":
+		d, err := cache.Deployments().Deployments(namespace).Get(name)
+		if err != nil {
+			return ""
+		}
+		objLabels, objAnnotations = d.Labels, d.Annotations
+	case "StatefulSet":
+		s, err := cache.StatefulSets().StatefulSets(namespace).Get(name)
+		if err != nil {
+			return ""
+		}
+		objLabels, objAnnotations = s.Labels, s.Annotations
+	default:
+		return ""
+	}
+	return gitOpsManagerOf(objLabels, objAnnotations)
+}
+
+// gitOpsManagerOf returns "Argo CD" or "Flux" when an object carries their tracking
+// labels or annotations
+func gitOpsManagerOf(objLabels, objAnnotations map[string]string) string {
+	if objAnnotations["argocd.argoproj.io/tracking-id"] != "" || objLabels["argocd.argoproj.io/instance"] != "" {
+		return "Argo CD"
+	}
+	if objLabels["kustomize.toolkit.fluxcd.io/name"] != "" || objLabels["helm.toolkit.fluxcd.io/name"] != "" {
+		return "Flux"
+	}
+	return ""
+}
+
+// formatReplicaValues renders a replica sequence like "3→5→3→5", keeping the latest values
+func formatReplicaValues(values []int64) string {
+	prefix := ""
+	if len(values) > maxFlapValues {
+		values, prefix = values[len(values)-maxFlapValues:], "…→"
+	}
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = fmt.Sprintf("%d", v)
+	}
+	return prefix + strings.Join(parts, "→")
+}
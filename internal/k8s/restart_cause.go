@@ -0,0 +1,326 @@
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/skyhook-io/radar/internal/timeline"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	restartedAtAnnotation     = "kubectl.kubernetes.io/restartedAt"
+	deploymentRevisionAnnot   = "deployment.kubernetes.io/revision"
+	daemonSetTemplateGenAnnot = "deprecated.daemonset.template.generation"
+	daemonSetTemplateGenLabel = "pod-template-generation"
+	templateChangeTrackingTTL = 30 * time.Minute
+	maxTrackedTemplateChanges = 5000
+)
+
+// templateChange records the most recent pod template change of a workload
+type templateChange struct {
+	hash      string
+	cause     timeline.ReplacementCause // SpecChange or RolloutRestart
+	changedAt time.Time
+}
+
+var (
+	templateChanges   = make(map[string]templateChange)
+	templateChangesMu sync.Mutex
+)
+
+// PodTemplateHash returns a hash of a pod template, ignoring the rollout restart annotation
+// so that restarts and real spec changes can be told apart
+func PodTemplateHash(tmpl *corev1.PodTemplateSpec) string {
+	if tmpl == nil {
+		return ""
+	}
+	t := tmpl.DeepCopy()
+	if t.Annotations != nil {
+		delete(t.Annotations, restartedAtAnnotation)
+	}
+	data, err := json.Marshal(t)
+	if err != nil {
+		return ""
+	}
+	h := fnv.New64a()
+	h.Write(data)
+	return fmt.Sprintf("%x", h.Sum64())
+}
+
+// podTemplateOf returns the pod template of a workload object
+func podTemplateOf(obj any) *corev1.PodTemplateSpec {
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		return &o.Spec.Template
+	case *appsv1.StatefulSet:
+		return &o.Spec.Template
+	case *appsv1.DaemonSet:
+		return &o.Spec.Template
+	}
+	return nil
+}
+
+// trackTemplateChange compares old/new workload templates and remembers
+// whether a change was a real spec change or only a rollout restart
+func trackTemplateChange(kind, namespace, name string, oldObj, newObj any) {
+	oldTmpl, newTmpl := podTemplateOf(oldObj), podTemplateOf(newObj)
+	if oldTmpl == nil || newTmpl == nil {
+		return
+	}
+
+	oldHash, newHash := PodTemplateHash(oldTmpl), PodTemplateHash(newTmpl)
+	var cause timeline.ReplacementCause
+	switch {
+	case oldHash != newHash:
+		cause = timeline.CauseSpecChange
+	case oldTmpl.Annotations[restartedAtAnnotation] != newTmpl.Annotations[restartedAtAnnotation]:
+		cause = timeline.CauseRolloutRestart
+	default:
+		return
+	}
+
+	templateChangesMu.Lock()
+	defer templateChangesMu.Unlock()
+
+	// Prune expired entries when the map grows large
+	if len(templateChanges) >= maxTrackedTemplateChanges {
+		cutoff := time.Now().Add(-templateChangeTrackingTTL)
+		for k, v := range templateChanges {
+			if v.changedAt.Before(cutoff) {
+				delete(templateChanges, k)
+			}
+		}
+	}
+
+	templateChanges[kind+"/"+namespace+"/"+name] = templateChange{
+		hash:      newHash,
+		cause:     cause,
+		changedAt: time.Now(),
+	}
+}
+
+// recentTemplateChange returns the tracked template change for a workload if still fresh
+func recentTemplateChange(kind, namespace, name string) (templateChange, bool) {
+	templateChangesMu.Lock()
+	defer templateChangesMu.Unlock()
+	tc, ok := templateChanges[kind+"/"+namespace+"/"+name]
+	if !ok || time.Since(tc.changedAt) > templateChangeTrackingTTL {
+		return templateChange{}, false
+	}
+	return tc, true
+}
+
+// resetTemplateChanges clears tracked template changes (on context switch)
+func resetTemplateChanges() {
+	templateChangesMu.Lock()
+	defer templateChangesMu.Unlock()
+	templateChanges = make(map[string]templateChange)
+}
+
+// InferPodDeletionCause determines why a pod was removed, returning the cause and a short explanation
+func InferPodDeletionCause(pod *corev1.Pod) (timeline.ReplacementCause, string) {
+	// Explicit disruption reasons set by the control plane (1.26+)
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type != corev1.DisruptionTarget || cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Reason {
+		case "PreemptionByScheduler", "PreemptionByKubeScheduler":
+			return timeline.CausePreemption, cond.Message
+		case "DeletionByTaintManager":
+			return timeline.CauseNodeFailure, cond.Message
+		case "EvictionByEvictionAPI", "TerminationByKubelet":
+			return timeline.CauseEviction, cond.Message
+		}
+	}
+
+	if pod.Status.Reason == "Evicted" {
+		return timeline.CauseEviction, pod.Status.Message
+	}
+	if pod.Status.Reason == "NodeLost" || pod.Status.Reason == "NodeShutdown" {
+		return timeline.CauseNodeFailure, pod.Status.Message
+	}
+
+	if pod.Status.Phase == corev1.PodSucceeded {
+		return timeline.CauseCompleted, ""
+	}
+
+	// Node gone or not ready
+	if pod.Spec.NodeName != "" {
+		if cause, msg, ok := nodeFailureCause(pod.Spec.NodeName); ok {
+			return cause, msg
+		}
+	}
+
+	// Replaced by a rollout of the owning workload
+	if cause, msg, ok := rolloutCause(pod); ok {
+		return cause, msg
+	}
+
+	// Last container termination was an OOM kill
+	for _, cs := range pod.Status.ContainerStatuses {
+		if t := cs.State.Terminated; t != nil && t.Reason == "OOMKilled" {
+			return timeline.CauseOOM, fmt.Sprintf("container %s was OOMKilled", cs.Name)
+		}
+	}
+
+	if pod.Status.Phase == corev1.PodFailed {
+		return timeline.CauseCrash, pod.Status.Message
+	}
+
+	// Owning ReplicaSet wants fewer replicas than it has
+	if owner := controllerRef(pod); owner != nil && owner.Kind == "ReplicaSet" {
+		if c := GetResourceCache(); c != nil {
+			if rs, err := c.ReplicaSets().ReplicaSets(pod.Namespace).Get(owner.Name); err == nil {
+				if rs.Spec.Replicas != nil && *rs.Spec.Replicas < rs.Status.Replicas {
+					return timeline.CauseScaleDown, fmt.Sprintf("%s scaled to %d", rs.Name, *rs.Spec.Replicas)
+				}
+			}
+		}
+	}
+
+	return timeline.CauseUnknown, ""
+}
+
+// InferContainerRestartCause returns the cause when a pod update shows new container restarts
+func InferContainerRestartCause(oldPod, newPod *corev1.Pod) (timeline.ReplacementCause, string, bool) {
+	oldCounts := make(map[string]int32, len(oldPod.Status.ContainerStatuses))
+	for _, cs := range oldPod.Status.ContainerStatuses {
+		oldCounts[cs.Name] = cs.RestartCount
+	}
+	for _, cs := range newPod.Status.ContainerStatuses {
+		if cs.RestartCount <= oldCounts[cs.Name] {
+			continue
+		}
+		t := cs.LastTerminationState.Terminated
+		if t == nil {
+			return timeline.CauseUnknown, fmt.Sprintf("container %s restarted", cs.Name), true
+		}
+		if t.Reason == "OOMKilled" {
+			return timeline.CauseOOM, fmt.Sprintf("container %s was OOMKilled", cs.Name), true
+		}
+		if t.Reason == "Completed" && t.ExitCode == 0 {
+			return timeline.CauseCompleted, fmt.Sprintf("container %s exited", cs.Name), true
+		}
+		return timeline.CauseCrash, fmt.Sprintf("container %s exited with code %d (%s)", cs.Name, t.ExitCode, t.Reason), true
+	}
+	return "", "", false
+}
+
+// nodeFailureCause checks whether the pod's node is missing or not ready
+func nodeFailureCause(nodeName string) (timeline.ReplacementCause, string, bool) {
+	c := GetResourceCache()
+	if c == nil {
+		return "", "", false
+	}
+	node, err := c.Nodes().Get(nodeName)
+	if err != nil {
+		return timeline.CauseNodeFailure, fmt.Sprintf("node %s no longer exists", nodeName), true
+	}
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == corev1.NodeReady && cond.Status != corev1.ConditionTrue {
+			return timeline.CauseNodeFailure, fmt.Sprintf("node %s is NotReady", nodeName), true
+		}
+	}
+	return "", "", false
+}
+
+// rolloutCause checks whether the pod belongs to an outdated revision of its workload
+func rolloutCause(pod *corev1.Pod) (timeline.ReplacementCause, string, bool) {
+	c := GetResourceCache()
+	owner := controllerRef(pod)
+	if c == nil || owner == nil {
+		return "", "", false
+	}
+
+	var workloadKind, workloadName string
+	stale := false
+
+	switch owner.Kind {
+	case "ReplicaSet":
+		rs, err := c.ReplicaSets().ReplicaSets(pod.Namespace).Get(owner.Name)
+		if err != nil {
+			return "", "", false
+		}
+		rsOwner := metav1.GetControllerOfNoCopy(rs)
+		if rsOwner == nil || rsOwner.Kind != "Deployment" {
+			return "", "", false
+		}
+		dep, err := c.Deployments().Deployments(pod.Namespace).Get(rsOwner.Name)
+		if err != nil {
+			return "", "", false
+		}
+		workloadKind, workloadName = "Deployment", dep.Name
+		stale = rs.Annotations[deploymentRevisionAnnot] != "" &&
+			rs.Annotations[deploymentRevisionAnnot] != dep.Annotations[deploymentRevisionAnnot]
+	case "StatefulSet":
+		sts, err := c.StatefulSets().StatefulSets(pod.Namespace).Get(owner.Name)
+		if err != nil {
+			return "", "", false
+		}
+		workloadKind, workloadName = "StatefulSet", sts.Name
+		rev := pod.Labels[appsv1.ControllerRevisionHashLabelKey]
+		stale = rev != "" && sts.Status.UpdateRevision != "" && rev != sts.Status.UpdateRevision
+	case "DaemonSet":
+		ds, err := c.DaemonSets().DaemonSets(pod.Namespace).Get(owner.Name)
+		if err != nil {
+			return "", "", false
+		}
+		workloadKind, workloadName = "DaemonSet", ds.Name
+		gen := pod.Labels[daemonSetTemplateGenLabel]
+		stale = gen != "" && gen != ds.Annotations[daemonSetTemplateGenAnnot]
+	default:
+		return "", "", false
+	}
+
+	if !stale {
+		return "", "", false
+	}
+
+	cause := timeline.CauseSpecChange
+	if tc, ok := recentTemplateChange(workloadKind, pod.Namespace, workloadName); ok {
+		cause = tc.cause
+	}
+	if cause == timeline.CauseRolloutRestart {
+		return cause, fmt.Sprintf("replaced by rollout restart of %s/%s", workloadKind, workloadName), true
+	}
+	return cause, fmt.Sprintf("replaced by rollout of %s/%s after pod template change", workloadKind, workloadName), true
+}
+
+// annotatePodEvent labels a pod timeline event with the inferred replacement/restart cause
+func annotatePodEvent(event *timeline.TimelineEvent, op string, oldObj, newObj any) {
+	switch op {
+	case "delete":
+		pod, ok := newObj.(*corev1.Pod)
+		if !ok {
+			pod, ok = oldObj.(*corev1.Pod)
+		}
+		if !ok {
+			return
+		}
+		cause, msg := InferPodDeletionCause(pod)
+		event.Reason = string(cause)
+		event.Message = msg
+	case "update":
+		oldPod, ok1 := oldObj.(*corev1.Pod)
+		newPod, ok2 := newObj.(*corev1.Pod)
+		if !ok1 || !ok2 {
+			return
+		}
+		if cause, msg, ok := InferContainerRestartCause(oldPod, newPod); ok {
+			event.Reason = string(cause)
+			event.Message = msg
+		}
+	}
+}
+
+// controllerRef returns the controlling owner reference of a pod
+func controllerRef(pod *corev1.Pod) *metav1.OwnerReference {
+	return metav1.GetControllerOfNoCopy(pod)
+}
@@ -0,0 +1,88 @@
+package k8s
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RolloutProgress is how far a workload's rollout of a spec change has come
+type RolloutProgress struct {
+	Done    bool   // Every replica runs the new template and is available
+	Failed  bool   // The rollout can't finish (e.g. progress deadline exceeded)
+	Message string // Progress or failure detail, e.g. "2 of 3 updated replicas available"
+}
+
+// WorkloadRolloutProgress reports the rollout of a cached Deployment, StatefulSet or
+// DaemonSet towards the given generation, following kubectl rollout status. A newer
+// generation includes the change, so it's waited for instead. ok is false for other kinds.
+func WorkloadRolloutProgress(obj metav1.Object, generation int64) (progress RolloutProgress, ok bool) {
+	if obj.GetGeneration() > generation {
+		generation = obj.GetGeneration()
+	}
+	switch w := obj.(type) {
+	case *appsv1.Deployment:
+		return deploymentRollout(w, generation), true
+	case *appsv1.StatefulSet:
+		return statefulSetRollout(w, generation), true
+	case *appsv1.DaemonSet:
+		return daemonSetRollout(w, generation), true
+	}
+	return RolloutProgress{}, false
+}
+
+func deploymentRollout(d *appsv1.Deployment, generation int64) RolloutProgress {
+	if d.Status.ObservedGeneration < generation {
+		return RolloutProgress{Message: "waiting for the controller to observe the change"}
+	}
+	for _, c := range d.Status.Conditions {
+		if c.Type == appsv1.DeploymentProgressing && c.Reason == "ProgressDeadlineExceeded" {
+			return RolloutProgress{Failed: true, Message: "progress deadline exceeded: " + c.Message}
+		}
+	}
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+	switch {
+	case d.Status.UpdatedReplicas < replicas:
+		return RolloutProgress{Message: fmt.Sprintf("%d of %d replicas updated", d.Status.UpdatedReplicas, replicas)}
+	case d.Status.Replicas > d.Status.UpdatedReplicas:
+		return RolloutProgress{Message: fmt.Sprintf("%d old replicas pending termination", d.Status.Replicas-d.Status.UpdatedReplicas)}
+	case d.Status.AvailableReplicas < d.Status.UpdatedReplicas:
+		return RolloutProgress{Message: fmt.Sprintf("%d of %d updated replicas available", d.Status.AvailableReplicas, d.Status.UpdatedReplicas)}
+	}
+	return RolloutProgress{Done: true}
+}
+
+func statefulSetRollout(sts *appsv1.StatefulSet, generation int64) RolloutProgress {
+	if sts.Status.ObservedGeneration < generation {
+		return RolloutProgress{Message: "waiting for the controller to observe the change"}
+	}
+	replicas := int32(1)
+	if sts.Spec.Replicas != nil {
+		replicas = *sts.Spec.Replicas
+	}
+	switch {
+	case sts.Status.ReadyReplicas < replicas:
+		return RolloutProgress{Message: fmt.Sprintf("%d of %d replicas ready", sts.Status.ReadyReplicas, replicas)}
+	case sts.Spec.UpdateStrategy.Type == appsv1.RollingUpdateStatefulSetStrategyType && sts.Status.UpdateRevision != sts.Status.CurrentRevision:
+		return RolloutProgress{Message: fmt.Sprintf("%d of %d replicas updated", sts.Status.UpdatedReplicas, replicas)}
+	}
+	return RolloutProgress{Done: true}
+}
+
+func daemonSetRollout(ds *appsv1.DaemonSet, generation int64) RolloutProgress {
+	if ds.Status.ObservedGeneration < generation {
+		return RolloutProgress{Message: "waiting for the controller to observe the change"}
+	}
+	desired := ds.Status.DesiredNumberScheduled
+	switch {
+	case ds.Status.UpdatedNumberScheduled < desired:
+		return RolloutProgress{Message: fmt.Sprintf("%d of %d pods updated", ds.Status.UpdatedNumberScheduled, desired)}
+	case ds.Status.NumberAvailable < desired:
+		return RolloutProgress{Message: fmt.Sprintf("%d of %d updated pods available", ds.Status.NumberAvailable, desired)}
+	}
+	return RolloutProgress{Done: true}
+}
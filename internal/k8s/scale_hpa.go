@@ -0,0 +1,94 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+)
+
+// What scaling a workload does when an HPA manages its replica count
+const (
+	ScaleHPAPolicyWarn   = "warn"   // Scale, and warn that the HPA will override it
+	ScaleHPAPolicyRefuse = "refuse" // Refuse; updating the HPA's range instead still works
+)
+
+// ValidateScaleHPAPolicy returns an error for unknown scale HPA policies
+func ValidateScaleHPAPolicy(policy string) error {
+	switch policy {
+	case ScaleHPAPolicyWarn, ScaleHPAPolicyRefuse:
+		return nil
+	}
+	return fmt.Errorf("invalid scale HPA policy %q (expected warn or refuse)", policy)
+}
+
+// HPARange is an HPA's replica range before and after an update
+type HPARange struct {
+	Name                string `json:"name"`
+	PreviousMinReplicas int32  `json:"previousMinReplicas"`
+	PreviousMaxReplicas int32  `json:"previousMaxReplicas"`
+	MinReplicas         int32  `json:"minReplicas"`
+	MaxReplicas         int32  `json:"maxReplicas"`
+}
+
+// ScalingHPAs returns the HPAs whose scale target is the workload. kind may be a kind or
+// resource name ("deployments").
+func ScalingHPAs(kind, namespace, name string) []*autoscalingv2.HorizontalPodAutoscaler {
+	cache := GetResourceCache()
+	if cache == nil || !cache.HasTypedInformer("HorizontalPodAutoscaler") {
+		return nil
+	}
+	var group string
+	if discovery := GetResourceDiscovery(); discovery != nil {
+		if res, ok := discovery.GetResource(kind); ok {
+			kind, group = res.Kind, res.Group
+		}
+	}
+	hpas, err := cache.HorizontalPodAutoscalers().HorizontalPodAutoscalers(namespace).List(labels.Everything())
+	if err != nil {
+		return nil
+	}
+	var scaling []*autoscalingv2.HorizontalPodAutoscaler
+	for _, hpa := range hpas {
+		ref := hpa.Spec.ScaleTargetRef
+		if ref.Kind != kind || ref.Name != name {
+			continue
+		}
+		if gv, err := schema.ParseGroupVersion(ref.APIVersion); err == nil && ref.APIVersion != "" && gv.Group != group {
+			continue
+		}
+		scaling = append(scaling, hpa)
+	}
+	return scaling
+}
+
+// ScaleHPA moves an HPA's range to hold at least replicas: minReplicas becomes replicas,
+// and maxReplicas is raised to it when lower. The HPA then scales the workload itself.
+func ScaleHPA(ctx context.Context, hpa *autoscalingv2.HorizontalPodAutoscaler, replicas int32) (*HPARange, error) {
+	if replicas < 1 {
+		return nil, explorerErrors.ValidationError("an HPA's minReplicas must be at least 1")
+	}
+	client, err := ClientFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r := &HPARange{Name: hpa.Name, PreviousMinReplicas: 1, PreviousMaxReplicas: hpa.Spec.MaxReplicas}
+	if hpa.Spec.MinReplicas != nil {
+		r.PreviousMinReplicas = *hpa.Spec.MinReplicas
+	}
+	r.MinReplicas, r.MaxReplicas = replicas, max(r.PreviousMaxReplicas, replicas)
+
+	patch := fmt.Sprintf(`{"spec":{"minReplicas":%d,"maxReplicas":%d}}`, r.MinReplicas, r.MaxReplicas)
+	if _, err := client.AutoscalingV2().HorizontalPodAutoscalers(hpa.Namespace).Patch(
+		ctx, hpa.Name, types.MergePatchType, []byte(patch), metav1.PatchOptions{},
+	); err != nil {
+		return nil, fmt.Errorf("failed to update HPA %s: %w", hpa.Name, err)
+	}
+	return r, nil
+}
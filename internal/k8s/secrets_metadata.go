@@ -0,0 +1,86 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Secrets modes (set via --secrets)
+const (
+	SecretsModeAuto     = "auto"     // Full secrets when RBAC allows listing them, otherwise none
+	SecretsModeFull     = "full"     // Full secrets, including data
+	SecretsModeMetadata = "metadata" // Metadata only - secret data is never fetched or held in memory
+	SecretsModeOff      = "off"      // Don't watch secrets
+)
+
+// SecretsMode controls how the resource cache watches secrets (set via --secrets flag)
+var SecretsMode = SecretsModeAuto
+
+// ValidateSecretsMode returns an error for unknown secrets modes
+func ValidateSecretsMode(mode string) error {
+	switch mode {
+	case SecretsModeAuto, SecretsModeFull, SecretsModeMetadata, SecretsModeOff:
+		return nil
+	}
+	return fmt.Errorf("invalid secrets mode %q (expected auto, full, metadata or off)", mode)
+}
+
+// newSecretMetadataInformer watches secrets through the metadata API (PartialObjectMetadata),
+// so only names, labels, annotations and timestamps cross the wire. Objects are stored as
+// *corev1.Secret without data so the regular secret lister and change handlers work on them.
+func newSecretMetadataInformer(client metadata.Interface, namespace string) cache.SharedIndexInformer {
+	res := client.Resource(corev1.SchemeGroupVersion.WithResource("secrets")).Namespace(namespace)
+	lw := &cache.ListWatch{
+		ListWithContextFunc: func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return res.List(ctx, opts)
+		},
+		WatchFuncWithContext: func(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+			return res.Watch(ctx, opts)
+		},
+	}
+	inf := cache.NewSharedIndexInformer(lw, &metav1.PartialObjectMetadata{}, 0,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	inf.SetTransform(secretFromMetadata)
+	return inf
+}
+
+// secretFromMetadata converts a PartialObjectMetadata into a data-less Secret
+func secretFromMetadata(obj any) (any, error) {
+	m, ok := obj.(*metav1.PartialObjectMetadata)
+	if !ok {
+		return obj, nil
+	}
+	meta := *m.ObjectMeta.DeepCopy()
+	meta.ManagedFields = nil
+	// kubectl apply stores the full object, data included, in this annotation
+	delete(meta.Annotations, "kubectl.kubernetes.io/last-applied-configuration")
+	return &corev1.Secret{
+		TypeMeta:   metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+		ObjectMeta: meta,
+		Type:       inferSecretType(meta),
+	}, nil
+}
+
+// inferSecretType recognizes well-known secret types from metadata. The type field itself
+// isn't part of the metadata API, so other secrets are left untyped.
+func inferSecretType(meta metav1.ObjectMeta) corev1.SecretType {
+	switch {
+	case meta.Labels["owner"] == "helm" && strings.HasPrefix(meta.Name, "sh.helm.release.v1."):
+		return "helm.sh/release.v1"
+	case meta.Annotations[corev1.ServiceAccountNameKey] != "":
+		return corev1.SecretTypeServiceAccountToken
+	case meta.Namespace == metav1.NamespaceSystem && strings.HasPrefix(meta.Name, "bootstrap-token-"):
+		return corev1.SecretTypeBootstrapToken
+	case meta.Annotations["cert-manager.io/certificate-name"] != "":
+		return corev1.SecretTypeTLS
+	}
+	return ""
+}
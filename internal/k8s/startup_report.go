@@ -0,0 +1,198 @@
+package k8s
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Thresholds used to generate tuning hints in the startup report
+const (
+	slowInformerThreshold  = 5 * time.Second
+	largeInformerObjects   = 10000
+	slowDiscoveryThreshold = 5 * time.Second
+	slowHelmThreshold      = 5 * time.Second
+)
+
+// StartupPhase is the timing of one startup step
+type StartupPhase struct {
+	Name       string  `json:"name"`
+	OffsetMs   int64   `json:"offsetMs"` // Start time relative to process start
+	DurationMs int64   `json:"durationMs"`
+	Error      string  `json:"error,omitempty"`
+	Share      float64 `json:"share"` // Fraction of total startup time
+}
+
+// InformerSync is how long a single informer took to sync and how much it holds
+type InformerSync struct {
+	Kind       string `json:"kind"`
+	Dynamic    bool   `json:"dynamic,omitempty"` // CRD / dynamic informer
+	DurationMs int64  `json:"durationMs"`
+	Objects    int    `json:"objects"`
+	TimedOut   bool   `json:"timedOut,omitempty"`
+}
+
+// StartupReport breaks down where startup time went so slow starts on large
+// clusters can be attributed and tuned
+type StartupReport struct {
+	StartedAt  time.Time      `json:"startedAt"`
+	ReadyAt    *time.Time     `json:"readyAt,omitempty"`
+	TotalMs    int64          `json:"totalMs"`
+	Phases     []StartupPhase `json:"phases"`
+	Informers  []InformerSync `json:"informers"` // Slowest first
+	TotalItems int            `json:"totalObjects"`
+	ListMethod string         `json:"listMethod,omitempty"` // How informers loaded their initial state: watch-list or list
+	Hints      []string       `json:"hints,omitempty"`
+}
+
+var (
+	startupMu       sync.Mutex
+	startupBegan    = time.Now()
+	startupPhases   []StartupPhase
+	startupInformer = make(map[string]InformerSync)
+	startupReadyAt  time.Time
+	startupListMode string
+)
+
+// StartPhase begins timing a startup phase; call the returned func with the phase's error (or nil)
+func StartPhase(name string) func(error) {
+	start := time.Now()
+	return func(err error) {
+		phase := StartupPhase{
+			Name:       name,
+			OffsetMs:   start.Sub(startupBegan).Milliseconds(),
+			DurationMs: time.Since(start).Milliseconds(),
+		}
+		if err != nil {
+			phase.Error = err.Error()
+		}
+		startupMu.Lock()
+		startupPhases = append(startupPhases, phase)
+		startupMu.Unlock()
+	}
+}
+
+// recordInformerSync records an informer's initial sync (later syncs, e.g. after a
+// context switch, replace earlier entries for the same kind)
+func recordInformerSync(kind string, dynamic bool, took time.Duration, objects int, timedOut bool) {
+	startupMu.Lock()
+	defer startupMu.Unlock()
+	startupInformer[kind] = InformerSync{
+		Kind:       kind,
+		Dynamic:    dynamic,
+		DurationMs: took.Milliseconds(),
+		Objects:    objects,
+		TimedOut:   timedOut,
+	}
+}
+
+// recordListMethod records how the informers started next load their initial state
+func recordListMethod(method string) {
+	startupMu.Lock()
+	startupListMode = method
+	startupMu.Unlock()
+}
+
+// MarkStartupComplete records that the server is ready and logs the report
+func MarkStartupComplete() {
+	startupMu.Lock()
+	if startupReadyAt.IsZero() {
+		startupReadyAt = time.Now()
+	}
+	startupMu.Unlock()
+
+	report := GetStartupReport()
+	log.Printf("Startup report: ready in %s (%d objects cached, initial lists via %s)", time.Duration(report.TotalMs)*time.Millisecond, report.TotalItems, report.ListMethod)
+	for _, p := range report.Phases {
+		status := ""
+		if p.Error != "" {
+			status = " (error: " + p.Error + ")"
+		}
+		log.Printf("  %-22s %8s  %4.1f%%%s", p.Name, time.Duration(p.DurationMs)*time.Millisecond, p.Share*100, status)
+	}
+	for i, inf := range report.Informers {
+		if i == 5 {
+			log.Printf("  ... %d more informers (see /api/debug/startup)", len(report.Informers)-5)
+			break
+		}
+		log.Printf("  informer %-20s %8s  %d objects", inf.Kind, time.Duration(inf.DurationMs)*time.Millisecond, inf.Objects)
+	}
+	for _, hint := range report.Hints {
+		log.Printf("  hint: %s", hint)
+	}
+}
+
+// GetStartupReport returns the startup breakdown (partial if startup is still in progress)
+func GetStartupReport() StartupReport {
+	startupMu.Lock()
+	defer startupMu.Unlock()
+
+	end := time.Now()
+	report := StartupReport{StartedAt: startupBegan}
+	if !startupReadyAt.IsZero() {
+		ready := startupReadyAt
+		report.ReadyAt = &ready
+		end = ready
+	}
+	report.TotalMs = end.Sub(startupBegan).Milliseconds()
+	report.ListMethod = startupListMode
+
+	report.Phases = append([]StartupPhase(nil), startupPhases...)
+	sort.SliceStable(report.Phases, func(i, j int) bool { return report.Phases[i].OffsetMs < report.Phases[j].OffsetMs })
+	for i := range report.Phases {
+		if report.TotalMs > 0 {
+			report.Phases[i].Share = float64(report.Phases[i].DurationMs) / float64(report.TotalMs)
+		}
+	}
+
+	report.Informers = make([]InformerSync, 0, len(startupInformer))
+	for _, inf := range startupInformer {
+		report.Informers = append(report.Informers, inf)
+		report.TotalItems += inf.Objects
+	}
+	sort.Slice(report.Informers, func(i, j int) bool {
+		if report.Informers[i].DurationMs != report.Informers[j].DurationMs {
+			return report.Informers[i].DurationMs > report.Informers[j].DurationMs
+		}
+		return report.Informers[i].Kind < report.Informers[j].Kind
+	})
+
+	report.Hints = startupHints(report)
+	return report
+}
+
+// startupHints suggests tuning for the slowest parts of startup
+func startupHints(report StartupReport) []string {
+	var hints []string
+	var heavy []string
+	for _, inf := range report.Informers {
+		if inf.TimedOut {
+			hints = append(hints, fmt.Sprintf("%s informer did not sync in time - check API server load and RBAC for this kind", inf.Kind))
+			continue
+		}
+		if time.Duration(inf.DurationMs)*time.Millisecond >= slowInformerThreshold || inf.Objects >= largeInformerObjects {
+			heavy = append(heavy, fmt.Sprintf("%s (%d objects, %s)", inf.Kind, inf.Objects, time.Duration(inf.DurationMs)*time.Millisecond))
+		}
+	}
+	if len(heavy) > 0 {
+		hints = append(hints, "Large or slow informers: "+strings.Join(heavy, ", ")+
+			" - scoping Radar to the namespaces you need reduces list time and memory")
+		if report.ListMethod == ListMethodList {
+			hints = append(hints, "Informers used paged LIST - on API servers with WatchList enabled, streaming lists (--watch-list) "+
+				"load large kinds faster and with less API server memory")
+		}
+	}
+	for _, p := range report.Phases {
+		d := time.Duration(p.DurationMs) * time.Millisecond
+		switch {
+		case p.Name == "crd-discovery" && d >= slowDiscoveryThreshold:
+			hints = append(hints, fmt.Sprintf("API discovery took %s - clusters with many CRDs or unavailable aggregated APIs (check `kubectl get apiservices`) slow this down", d))
+		case p.Name == "helm-init" && d >= slowHelmThreshold:
+			hints = append(hints, fmt.Sprintf("Helm initialization took %s - many release secrets slow this down", d))
+		}
+	}
+	return hints
+}
@@ -0,0 +1,136 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// Streaming subresources (exec, attach, port-forward) upgrade the connection, which some
+// corporate proxies and load balancers refuse for SPDY. Both helpers try SPDY first and
+// retry over WebSockets when the upgrade is rejected. The rest.Config carries the
+// kubeconfig's proxy-url (HTTP, HTTPS or SOCKS5) and exec credential plugins, so both
+// protocols go through the same proxy and authentication as regular API calls.
+
+// NewStreamExecutor returns an executor for a pod exec/attach URL
+func NewStreamExecutor(config *rest.Config, u *url.URL) (remotecommand.Executor, error) {
+	spdyExec, err := remotecommand.NewSPDYExecutor(config, "POST", u)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SPDY executor: %w", err)
+	}
+	// WebSocket exec uses GET for the upgrade handshake
+	wsExec, err := remotecommand.NewWebSocketExecutor(config, "GET", u.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create WebSocket executor: %w", err)
+	}
+	return remotecommand.NewFallbackExecutor(spdyExec, wsExec, shouldFallbackToWebSocket)
+}
+
+// ExecStream runs a command in a container without a TTY, streaming stdin to it (when
+// not nil) and its stdout to stdout. A failing command's error includes its stderr.
+func ExecStream(ctx context.Context, namespace, pod, container string, command []string, stdin io.Reader, stdout io.Writer) error {
+	client, err := ClientFor(ctx)
+	if err != nil {
+		return err
+	}
+	config, err := ConfigFor(ctx)
+	if err != nil {
+		return err
+	}
+	req := client.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(pod).
+		Namespace(namespace).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdin:     stdin != nil,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+	exec, err := NewStreamExecutor(config, req.URL())
+	if err != nil {
+		return err
+	}
+
+	var stderr bytes.Buffer
+	err = exec.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdin:  stdin,
+		Stdout: stdout,
+		Stderr: &limitedBuffer{buf: &stderr, max: 4096},
+	})
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s: %w", msg, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// limitedBuffer keeps the first max bytes written and discards the rest
+type limitedBuffer struct {
+	buf *bytes.Buffer
+	max int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.max - b.buf.Len(); room > 0 {
+		b.buf.Write(p[:min(len(p), room)])
+	}
+	return len(p), nil
+}
+
+// NewPortForwardDialer returns a dialer for a pod port-forward URL
+func NewPortForwardDialer(config *rest.Config, u *url.URL) (httpstream.Dialer, error) {
+	transport, upgrader, err := spdy.RoundTripperFor(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create round tripper: %w", err)
+	}
+	spdyDialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", u)
+	wsDialer, err := portforward.NewSPDYOverWebsocketDialer(u, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create WebSocket dialer: %w", err)
+	}
+	return portforward.NewFallbackDialer(spdyDialer, wsDialer, shouldFallbackToWebSocket), nil
+}
+
+// shouldFallbackToWebSocket reports whether a SPDY failure came from an intermediary
+// rejecting the upgrade, rather than from the pod or the API server
+func shouldFallbackToWebSocket(err error) bool {
+	if httpstream.IsUpgradeFailure(err) || httpstream.IsHTTPSProxyError(err) {
+		log.Printf("[k8s] SPDY upgrade failed (%v), retrying over WebSocket", err)
+		return true
+	}
+	return false
+}
+
+// logProxy reports the proxy API requests use, so proxy-url problems are easy to spot
+func logProxy(config *rest.Config) {
+	if config == nil || config.Proxy == nil {
+		return
+	}
+	host, err := url.Parse(config.Host)
+	if err != nil {
+		return
+	}
+	proxyURL, err := config.Proxy(&http.Request{URL: host})
+	if err != nil || proxyURL == nil {
+		return
+	}
+	log.Printf("[k8s] Connecting to %s through proxy %s", config.Host, proxyURL.Redacted())
+}
@@ -3,12 +3,17 @@ package k8s
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
 	"sigs.k8s.io/yaml"
+
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+	"github.com/skyhook-io/radar/internal/yamldiff"
 )
 
 // UpdateResourceOptions contains options for updating a resource
@@ -21,64 +26,187 @@ type UpdateResourceOptions struct {
 
 // UpdateResource updates a Kubernetes resource from YAML
 func UpdateResource(ctx context.Context, opts UpdateResourceOptions) (*unstructured.Unstructured, error) {
+	client, obj, err := prepareUpdate(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := client.Update(ctx, obj, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update resource: %w", err)
+	}
+
+	RedactSecretData(result)
+	return result, nil
+}
+
+// UpdatePreview is the outcome of a server-side dry-run of an edit: the live object, the
+// object the API server would store (after defaulting, admission and validation), and
+// what changed between them
+type UpdatePreview struct {
+	Live     *unstructured.Unstructured `json:"live"`
+	Proposed *unstructured.Unstructured `json:"proposed"`
+	Diff     *DiffInfo                  `json:"diff"` // Empty fields when the edit changes nothing
+	Changes  *yamldiff.Result           `json:"changes"`
+}
+
+// PreviewResourceUpdate runs the same update as UpdateResource with dryRun=All, so
+// admission webhooks and validation run but nothing is persisted
+func PreviewResourceUpdate(ctx context.Context, opts UpdateResourceOptions) (*UpdatePreview, error) {
+	client, obj, err := prepareUpdate(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	live, err := client.Get(ctx, opts.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resource: %w", err)
+	}
+	proposed, err := client.Update(ctx, obj, metav1.UpdateOptions{DryRun: []string{metav1.DryRunAll}})
+	if err != nil {
+		return nil, fmt.Errorf("dry-run update failed: %w", err)
+	}
+
+	// Secret values stay out of the preview, so the diff shows changed keys but not values
+	for _, u := range []*unstructured.Unstructured{live, proposed} {
+		u.SetManagedFields(nil)
+		RedactSecretData(u)
+	}
+	diff := &DiffInfo{Fields: ComputeObjectDiff(live.Object, proposed.Object)}
+	if summary := ComputeDiff(live.GetKind(), typedForDiff(live), typedForDiff(proposed)); summary != nil {
+		diff.Summary = summary.Summary
+	}
+	if diff.Summary == "" {
+		diff.Summary = fmt.Sprintf("%d field(s) changed", len(diff.Fields))
+	}
+	if diff.Fields == nil {
+		diff.Fields = []FieldChange{}
+	}
+
+	return &UpdatePreview{
+		Live:     live,
+		Proposed: proposed,
+		Diff:     diff,
+		Changes:  yamldiff.CompareObjects("", live.Object, proposed.Object),
+	}, nil
+}
+
+// prepareUpdate parses the edited YAML, checks it targets the resource being edited,
+// and returns a client for that resource
+func prepareUpdate(ctx context.Context, opts UpdateResourceOptions) (dynamic.ResourceInterface, *unstructured.Unstructured, error) {
 	discovery := GetResourceDiscovery()
 	if discovery == nil {
-		return nil, fmt.Errorf("resource discovery not initialized")
+		return nil, nil, fmt.Errorf("resource discovery not initialized")
 	}
 
-	dynamicClient := GetDynamicClient()
-	if dynamicClient == nil {
-		return nil, fmt.Errorf("dynamic client not initialized")
+	dynamicClient, err := DynamicClientFor(ctx)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	// Parse YAML into unstructured
 	obj := &unstructured.Unstructured{}
 	if err := yaml.Unmarshal([]byte(opts.YAML), &obj.Object); err != nil {
-		return nil, fmt.Errorf("invalid YAML: %w", err)
+		return nil, nil, fmt.Errorf("invalid YAML: %w", err)
 	}
 
 	// Get GVR for this resource kind
 	gvr, ok := discovery.GetGVR(opts.Kind)
 	if !ok {
-		return nil, fmt.Errorf("unknown resource kind: %s", opts.Kind)
+		return nil, nil, fmt.Errorf("unknown resource kind: %s", opts.Kind)
 	}
 
 	// Validate that the resource matches what we're trying to update
 	objName := obj.GetName()
 	objNamespace := obj.GetNamespace()
 	if objName != opts.Name {
-		return nil, fmt.Errorf("resource name mismatch: expected %s, got %s", opts.Name, objName)
+		return nil, nil, fmt.Errorf("resource name mismatch: expected %s, got %s", opts.Name, objName)
 	}
 	if opts.Namespace != "" && objNamespace != opts.Namespace {
-		return nil, fmt.Errorf("resource namespace mismatch: expected %s, got %s", opts.Namespace, objNamespace)
+		return nil, nil, fmt.Errorf("resource namespace mismatch: expected %s, got %s", opts.Namespace, objNamespace)
 	}
 
-	// Update the resource
-	var result *unstructured.Unstructured
-	var err error
+	var client dynamic.ResourceInterface = dynamicClient.Resource(gvr)
 	if opts.Namespace != "" {
-		result, err = dynamicClient.Resource(gvr).Namespace(opts.Namespace).Update(ctx, obj, metav1.UpdateOptions{})
-	} else {
-		result, err = dynamicClient.Resource(gvr).Update(ctx, obj, metav1.UpdateOptions{})
+		client = dynamicClient.Resource(gvr).Namespace(opts.Namespace)
 	}
+	if isSecret(obj) {
+		live, err := client.Get(ctx, opts.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get resource: %w", err)
+		}
+		restoreSecretData(obj, live)
+	}
+	return client, obj, nil
+}
 
-	if err != nil {
-		return nil, fmt.Errorf("failed to update resource: %w", err)
+// lastAppliedAnnotation holds kubectl's copy of the applied object, Secret values included
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+func isSecret(u *unstructured.Unstructured) bool {
+	return u != nil && u.GetKind() == "Secret" && u.GetAPIVersion() == "v1"
+}
+
+// RedactSecretData blanks a Secret's values in place, keeping its keys, and drops
+// stringData and kubectl's last-applied copy. Values are only served one key at a time
+// through the audited reveal. Other objects are left alone.
+func RedactSecretData(u *unstructured.Unstructured) {
+	if !isSecret(u) {
+		return
+	}
+	if data, ok := u.Object["data"].(map[string]any); ok {
+		for k := range data {
+			data[k] = ""
+		}
+	}
+	delete(u.Object, "stringData")
+	if annotations := u.GetAnnotations(); annotations[lastAppliedAnnotation] != "" {
+		delete(annotations, lastAppliedAnnotation)
+		u.SetAnnotations(annotations)
 	}
+}
 
-	return result, nil
+// restoreSecretData keeps the live value of every key an edited Secret leaves blank, and
+// kubectl's last-applied copy, since Secrets are served redacted. A value can't be
+// cleared through an edit.
+func restoreSecretData(edited, live *unstructured.Unstructured) {
+	data, _ := edited.Object["data"].(map[string]any)
+	liveData, _ := live.Object["data"].(map[string]any)
+	for k, v := range data {
+		if v == nil || v == "" {
+			if liveValue, ok := liveData[k]; ok {
+				data[k] = liveValue
+			}
+		}
+	}
+	if applied := live.GetAnnotations()[lastAppliedAnnotation]; applied != "" {
+		annotations := edited.GetAnnotations()
+		if _, ok := annotations[lastAppliedAnnotation]; !ok {
+			if annotations == nil {
+				annotations = make(map[string]string)
+			}
+			annotations[lastAppliedAnnotation] = applied
+			edited.SetAnnotations(annotations)
+		}
+	}
 }
 
-// DeleteResource deletes a Kubernetes resource
-func DeleteResource(ctx context.Context, kind, namespace, name string) error {
+// DeleteResource deletes a Kubernetes resource. The resource must be in the cache; opts
+// sets the propagation policy for its dependents and the grace period.
+func DeleteResource(ctx context.Context, kind, namespace, name string, opts DeleteOptions) error {
+	deleteOpts, err := opts.meta()
+	if err != nil {
+		return err
+	}
+
 	discovery := GetResourceDiscovery()
 	if discovery == nil {
 		return fmt.Errorf("resource discovery not initialized")
 	}
 
-	dynamicClient := GetDynamicClient()
-	if dynamicClient == nil {
-		return fmt.Errorf("dynamic client not initialized")
+	dynamicClient, err := DynamicClientFor(ctx)
+	if err != nil {
+		return err
 	}
 
 	// Get GVR for this resource kind
@@ -87,12 +215,15 @@ func DeleteResource(ctx context.Context, kind, namespace, name string) error {
 		return fmt.Errorf("unknown resource kind: %s", kind)
 	}
 
+	if _, err := GetResourceCache().CachedObject(ctx, kind, namespace, name); err != nil {
+		return err
+	}
+
 	// Delete the resource
-	var err error
 	if namespace != "" {
-		err = dynamicClient.Resource(gvr).Namespace(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+		err = dynamicClient.Resource(gvr).Namespace(namespace).Delete(ctx, name, deleteOpts)
 	} else {
-		err = dynamicClient.Resource(gvr).Delete(ctx, name, metav1.DeleteOptions{})
+		err = dynamicClient.Resource(gvr).Delete(ctx, name, deleteOpts)
 	}
 
 	if err != nil {
@@ -104,9 +235,9 @@ func DeleteResource(ctx context.Context, kind, namespace, name string) error {
 
 // TriggerCronJob creates a Job from a CronJob
 func TriggerCronJob(ctx context.Context, namespace, name string) (*unstructured.Unstructured, error) {
-	dynamicClient := GetDynamicClient()
-	if dynamicClient == nil {
-		return nil, fmt.Errorf("dynamic client not initialized")
+	dynamicClient, err := DynamicClientFor(ctx)
+	if err != nil {
+		return nil, err
 	}
 
 	discovery := GetResourceDiscovery()
@@ -190,9 +321,9 @@ func TriggerCronJob(ctx context.Context, namespace, name string) (*unstructured.
 
 // SetCronJobSuspend sets the suspend field on a CronJob
 func SetCronJobSuspend(ctx context.Context, namespace, name string, suspend bool) error {
-	dynamicClient := GetDynamicClient()
-	if dynamicClient == nil {
-		return fmt.Errorf("dynamic client not initialized")
+	dynamicClient, err := DynamicClientFor(ctx)
+	if err != nil {
+		return err
 	}
 
 	discovery := GetResourceDiscovery()
@@ -208,7 +339,7 @@ func SetCronJobSuspend(ctx context.Context, namespace, name string, suspend bool
 
 	// Patch the CronJob to set suspend
 	patch := fmt.Sprintf(`{"spec":{"suspend":%t}}`, suspend)
-	_, err := dynamicClient.Resource(cronJobGVR).Namespace(namespace).Patch(
+	_, err = dynamicClient.Resource(cronJobGVR).Namespace(namespace).Patch(
 		ctx,
 		name,
 		types.MergePatchType,
@@ -222,29 +353,134 @@ func SetCronJobSuspend(ctx context.Context, namespace, name string, suspend bool
 	return nil
 }
 
-// RestartWorkload performs a rolling restart on a Deployment, StatefulSet, or DaemonSet
-func RestartWorkload(ctx context.Context, kind, namespace, name string) error {
-	dynamicClient := GetDynamicClient()
-	if dynamicClient == nil {
-		return fmt.Errorf("dynamic client not initialized")
+// RerunOfAnnotation names the Job a re-run was cloned from
+const RerunOfAnnotation = "radar.skyhook.io/rerun-of"
+
+// jobControllerLabels are set by the Job controller to tie pods to one Job, so a clone
+// must not carry them over
+var jobControllerLabels = []string{
+	"controller-uid", "job-name",
+	"batch.kubernetes.io/controller-uid", "batch.kubernetes.io/job-name",
+}
+
+// rerunSuffix matches the suffix of an earlier re-run, so re-running a re-run doesn't stack them
+var rerunSuffix = regexp.MustCompile(`-rerun-\d+$`)
+
+// RerunJob creates a copy of a finished Job under a new name. The generated selector and
+// controller labels are dropped so the API server assigns fresh ones; owner references
+// are kept, so a re-run of a CronJob's Job still shows under the CronJob.
+func RerunJob(ctx context.Context, namespace, name string) (*unstructured.Unstructured, error) {
+	dynamicClient, err := DynamicClientFor(ctx)
+	if err != nil {
+		return nil, err
 	}
 
 	discovery := GetResourceDiscovery()
 	if discovery == nil {
-		return fmt.Errorf("resource discovery not initialized")
+		return nil, fmt.Errorf("resource discovery not initialized")
+	}
+
+	jobGVR, ok := discovery.GetGVR("jobs")
+	if !ok {
+		return nil, fmt.Errorf("jobs resource not found")
+	}
+
+	job, err := dynamicClient.Resource(jobGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+	if !jobFinished(job) {
+		return nil, explorerErrors.New(explorerErrors.ErrConflict, fmt.Sprintf("job %s hasn't finished yet", name))
+	}
+
+	spec, found, err := unstructured.NestedMap(job.Object, "spec")
+	if err != nil || !found {
+		return nil, fmt.Errorf("failed to get job spec: %w", err)
+	}
+	delete(spec, "selector")
+	delete(spec, "manualSelector")
+	if podLabels, found, _ := unstructured.NestedStringMap(spec, "template", "metadata", "labels"); found {
+		for _, key := range jobControllerLabels {
+			delete(podLabels, key)
+		}
+		if err := unstructured.SetNestedStringMap(spec, podLabels, "template", "metadata", "labels"); err != nil {
+			return nil, fmt.Errorf("failed to set pod labels: %w", err)
+		}
+	}
+
+	suffix := fmt.Sprintf("-rerun-%d", time.Now().Unix())
+	base := rerunSuffix.ReplaceAllString(name, "")
+	// Job names end up in pod labels, so they're limited to 63 characters
+	if limit := 63 - len(suffix); len(base) > limit {
+		base = base[:limit]
+	}
+
+	rerun := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "batch/v1",
+		"kind":       "Job",
+		"spec":       spec,
+	}}
+	rerun.SetName(base + suffix)
+	rerun.SetNamespace(namespace)
+	if labels := job.GetLabels(); len(labels) > 0 {
+		for _, key := range jobControllerLabels {
+			delete(labels, key)
+		}
+		rerun.SetLabels(labels)
+	}
+	rerun.SetAnnotations(map[string]string{RerunOfAnnotation: name})
+	rerun.SetOwnerReferences(job.GetOwnerReferences())
+
+	result, err := dynamicClient.Resource(jobGVR).Namespace(namespace).Create(ctx, rerun, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create job: %w", err)
+	}
+	return result, nil
+}
+
+// jobFinished reports whether a Job has a true Complete or Failed condition
+func jobFinished(job *unstructured.Unstructured) bool {
+	conditions, _, _ := unstructured.NestedSlice(job.Object, "status", "conditions")
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if (cond["type"] == "Complete" || cond["type"] == "Failed") && cond["status"] == "True" {
+			return true
+		}
+	}
+	return false
+}
+
+// RestartWorkload performs a rolling restart on a Deployment, StatefulSet, or DaemonSet and
+// returns the workload generation carrying the restart, for following the rollout
+func RestartWorkload(ctx context.Context, kind, namespace, name string) (int64, error) {
+	dynamicClient, err := DynamicClientFor(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	discovery := GetResourceDiscovery()
+	if discovery == nil {
+		return 0, fmt.Errorf("resource discovery not initialized")
 	}
 
 	// Get the GVR for the workload kind
 	gvr, ok := discovery.GetGVR(kind)
 	if !ok {
-		return fmt.Errorf("unknown resource kind: %s", kind)
+		return 0, fmt.Errorf("unknown resource kind: %s", kind)
+	}
+
+	if _, err := GetResourceCache().CachedObject(ctx, kind, namespace, name); err != nil {
+		return 0, err
 	}
 
 	// Patch to trigger a rolling restart by updating an annotation
 	restartTime := time.Now().Format(time.RFC3339)
 	patch := fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{"kubectl.kubernetes.io/restartedAt":"%s"}}}}}`, restartTime)
 
-	_, err := dynamicClient.Resource(gvr).Namespace(namespace).Patch(
+	updated, err := dynamicClient.Resource(gvr).Namespace(namespace).Patch(
 		ctx,
 		name,
 		types.MergePatchType,
@@ -252,8 +488,8 @@ func RestartWorkload(ctx context.Context, kind, namespace, name string) error {
 		metav1.PatchOptions{},
 	)
 	if err != nil {
-		return fmt.Errorf("failed to restart workload: %w", err)
+		return 0, fmt.Errorf("failed to restart workload: %w", err)
 	}
 
-	return nil
+	return updated.GetGeneration(), nil
 }
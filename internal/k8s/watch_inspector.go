@@ -0,0 +1,134 @@
+package k8s
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RawWatchEvent is an informer event as received, annotated with what the
+// change pipeline did with it. Used to debug "why doesn't Radar show my change".
+type RawWatchEvent struct {
+	Time            time.Time `json:"time"`
+	Source          string    `json:"source"` // typed, dynamic
+	Kind            string    `json:"kind"`
+	Namespace       string    `json:"namespace,omitempty"`
+	Name            string    `json:"name"`
+	UID             string    `json:"uid,omitempty"`
+	Operation       string    `json:"operation"`
+	ResourceVersion string    `json:"resourceVersion,omitempty"`
+	Tombstone       bool      `json:"tombstone,omitempty"` // Delete delivered as DeletedFinalStateUnknown
+	SentToTimeline  bool      `json:"sentToTimeline"`      // Passed to the timeline store (which may still dedup it)
+	SentToChannel   bool      `json:"sentToChannel"`       // Delivered to the change channel (SSE, topology)
+	DropReasons     []string  `json:"dropReasons,omitempty"`
+}
+
+// RawWatchFilter selects which raw events a subscriber receives (empty fields match all)
+type RawWatchFilter struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+func (f RawWatchFilter) matches(ev *RawWatchEvent) bool {
+	if f.Kind != "" && !strings.EqualFold(f.Kind, ev.Kind) {
+		return false
+	}
+	if f.Namespace != "" && f.Namespace != ev.Namespace {
+		return false
+	}
+	if f.Name != "" && f.Name != ev.Name {
+		return false
+	}
+	return true
+}
+
+type rawWatchSubscriber struct {
+	filter  RawWatchFilter
+	ch      chan RawWatchEvent
+	dropped atomic.Int64
+}
+
+var (
+	rawWatchSubscribers   = make(map[*rawWatchSubscriber]struct{})
+	rawWatchSubscribersMu sync.RWMutex
+	// rawWatchActive lets the hot path skip all inspector work when nobody is listening
+	rawWatchActive atomic.Int32
+)
+
+// SubscribeRawWatchEvents registers a subscriber for raw watch events.
+// The returned func unsubscribes and closes the channel. The second return value
+// reports how many events were dropped because the subscriber was too slow.
+func SubscribeRawWatchEvents(filter RawWatchFilter, buffer int) (<-chan RawWatchEvent, func() int64, func()) {
+	if buffer <= 0 {
+		buffer = 256
+	}
+	sub := &rawWatchSubscriber{filter: filter, ch: make(chan RawWatchEvent, buffer)}
+
+	rawWatchSubscribersMu.Lock()
+	rawWatchSubscribers[sub] = struct{}{}
+	rawWatchActive.Add(1)
+	rawWatchSubscribersMu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			rawWatchSubscribersMu.Lock()
+			delete(rawWatchSubscribers, sub)
+			rawWatchActive.Add(-1)
+			rawWatchSubscribersMu.Unlock()
+			close(sub.ch)
+		})
+	}
+	return sub.ch, sub.dropped.Load, unsubscribe
+}
+
+// rawWatchInspecting reports whether any inspector is subscribed
+func rawWatchInspecting() bool {
+	return rawWatchActive.Load() > 0
+}
+
+// publishRawWatchEvent fans an event out to matching subscribers without blocking
+func publishRawWatchEvent(ev *RawWatchEvent) {
+	if ev == nil {
+		return
+	}
+	rawWatchSubscribersMu.RLock()
+	defer rawWatchSubscribersMu.RUnlock()
+	for sub := range rawWatchSubscribers {
+		if !sub.filter.matches(ev) {
+			continue
+		}
+		select {
+		case sub.ch <- *ev:
+		default:
+			sub.dropped.Add(1)
+		}
+	}
+}
+
+// newRawWatchEvent starts an inspector record, or returns nil when nobody is inspecting
+func newRawWatchEvent(source, kind, namespace, name, uid, op, resourceVersion string, tombstone bool) *RawWatchEvent {
+	if !rawWatchInspecting() {
+		return nil
+	}
+	return &RawWatchEvent{
+		Time:            time.Now(),
+		Source:          source,
+		Kind:            kind,
+		Namespace:       namespace,
+		Name:            name,
+		UID:             uid,
+		Operation:       op,
+		ResourceVersion: resourceVersion,
+		Tombstone:       tombstone,
+	}
+}
+
+// addDrop annotates the event with a drop reason (nil-safe)
+func (ev *RawWatchEvent) addDrop(reason string) {
+	if ev != nil {
+		ev.DropReasons = append(ev.DropReasons, reason)
+	}
+}
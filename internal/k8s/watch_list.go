@@ -0,0 +1,116 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientfeatures "k8s.io/client-go/features"
+)
+
+// Watch-list modes (set via --watch-list)
+const (
+	WatchListModeAuto = "auto" // Stream initial lists when the API server supports it, otherwise paged LIST
+	WatchListModeOn   = "on"   // Always try streaming lists (informers still fall back to LIST on error)
+	WatchListModeOff  = "off"  // Always use paged LIST
+)
+
+// WatchListMode controls how informers load their initial state (set via --watch-list flag)
+var WatchListMode = WatchListModeAuto
+
+// ValidateWatchListMode returns an error for unknown watch-list modes
+func ValidateWatchListMode(mode string) error {
+	switch mode {
+	case WatchListModeAuto, WatchListModeOn, WatchListModeOff:
+		return nil
+	}
+	return fmt.Errorf("invalid watch-list mode %q (expected auto, on or off)", mode)
+}
+
+// List methods reported in the startup report
+const (
+	ListMethodWatchList = "watch-list" // Streaming list: a watch with sendInitialEvents=true
+	ListMethodList      = "list"       // Paged LIST followed by a watch
+)
+
+// watchListGates overrides client-go's WatchListClient gate and defers every other
+// feature to the defaults. Installed once at init, before client-go reads any gate,
+// so switching modes on a context change doesn't replace the gates after first use.
+type watchListGates struct {
+	clientfeatures.Gates
+	override atomic.Pointer[bool] // nil = client-go's default
+}
+
+func (g *watchListGates) Enabled(key clientfeatures.Feature) bool {
+	if key == clientfeatures.WatchListClient {
+		if v := g.override.Load(); v != nil {
+			return *v
+		}
+	}
+	return g.Gates.Enabled(key)
+}
+
+var clientGates = &watchListGates{Gates: clientfeatures.FeatureGates()}
+
+func init() {
+	clientfeatures.ReplaceFeatureGates(clientGates)
+}
+
+// configureWatchList decides whether informers started next stream their initial list,
+// probing the API server in auto mode, and returns the list method in use
+func configureWatchList() string {
+	enabled := true
+	switch WatchListMode {
+	case WatchListModeOff:
+		enabled = false
+	case WatchListModeAuto:
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		err := probeWatchList(ctx)
+		cancel()
+		if err != nil {
+			log.Printf("Streaming lists not available, informers will use paged LIST: %v", err)
+			enabled = false
+		}
+	}
+	clientGates.override.Store(&enabled)
+
+	method := ListMethodList
+	if enabled {
+		method = ListMethodWatchList
+	}
+	recordListMethod(method)
+	return method
+}
+
+// probeWatchList opens a streaming-list watch on a small resource and closes it straight
+// away. API servers without WatchList reject sendInitialEvents as invalid. Other errors
+// (RBAC, timeouts) don't say anything about support, so they don't disable it - the
+// reflector still falls back to LIST per informer if streaming fails.
+func probeWatchList(ctx context.Context) error {
+	sendInitialEvents := true
+	opts := metav1.ListOptions{
+		SendInitialEvents:    &sendInitialEvents,
+		ResourceVersionMatch: metav1.ResourceVersionMatchNotOlderThan,
+		AllowWatchBookmarks:  true,
+	}
+	// Any object will do; select one that exists so the initial events are a single item
+	watchFn := k8sClient.CoreV1().Namespaces().Watch
+	opts.FieldSelector = "metadata.name=default"
+	if len(WatchNamespaces) > 0 {
+		watchFn = k8sClient.CoreV1().ConfigMaps(WatchNamespaces[0]).Watch
+		opts.FieldSelector = "metadata.name=kube-root-ca.crt"
+	}
+	w, err := watchFn(ctx, opts)
+	if err != nil {
+		if apierrors.IsInvalid(err) || apierrors.IsBadRequest(err) {
+			return err
+		}
+		return nil
+	}
+	w.Stop()
+	return nil
+}
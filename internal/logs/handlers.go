@@ -0,0 +1,234 @@
+package logs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/websocket"
+
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+const (
+	defaultTailLines = 100
+	maxFilterLength  = 1024
+	// rescanInterval is how often a followed workload is checked for new pods
+	rescanInterval = 5 * time.Second
+)
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool {
+		return true // Same policy as pod exec
+	},
+}
+
+// Handlers provides HTTP handlers for merged log streams
+type Handlers struct{}
+
+// NewHandlers creates a new Handlers instance
+func NewHandlers() *Handlers {
+	return &Handlers{}
+}
+
+// RegisterRoutes registers log streaming routes on the given router
+func (h *Handlers) RegisterRoutes(r chi.Router) {
+	r.Get("/logs/{kind}/{namespace}/{name}", h.handleStream)
+}
+
+// handleStream streams the merged logs of a pod's containers or a workload's pods, as SSE
+// or, when the request is a WebSocket upgrade, as {"event", "data"} JSON messages.
+//
+// Query: container, init, tailLines (default 100), sinceSeconds, follow (default true),
+// previous, filter (regex on the line content), invert.
+// Events: connected {sources, truncated}, log {timestamp, pod, container, content},
+// source_added, source_end {pod, container, reason, error}, end, error.
+func (h *Handlers) handleStream(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	target := Target{
+		Kind:      chi.URLParam(r, "kind"),
+		Namespace: chi.URLParam(r, "namespace"),
+		Name:      chi.URLParam(r, "name"),
+		Container: q.Get("container"),
+		Init:      q.Get("init") == "true",
+	}
+	opts := Options{
+		Namespace:    target.Namespace,
+		TailLines:    defaultTailLines,
+		Follow:       q.Get("follow") != "false",
+		Previous:     q.Get("previous") == "true",
+		InvertFilter: q.Get("invert") == "true",
+	}
+	if v := q.Get("tailLines"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			explorerErrors.Write(w, explorerErrors.ValidationError("tailLines must be a non-negative integer"))
+			return
+		}
+		opts.TailLines = n
+	}
+	if v := q.Get("sinceSeconds"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			explorerErrors.Write(w, explorerErrors.ValidationError("sinceSeconds must be a non-negative integer"))
+			return
+		}
+		opts.SinceSeconds = n
+	}
+	if f := q.Get("filter"); f != "" {
+		if len(f) > maxFilterLength {
+			explorerErrors.Write(w, explorerErrors.ValidationError(fmt.Sprintf("filter is longer than %d characters", maxFilterLength)))
+			return
+		}
+		re, err := regexp.Compile(f)
+		if err != nil {
+			explorerErrors.Write(w, explorerErrors.ValidationError(fmt.Sprintf("invalid filter: %v", err)).WithDetail("filter", f))
+			return
+		}
+		opts.Filter = re
+	}
+
+	client := k8s.GetClient()
+	if client == nil {
+		explorerErrors.Write(w, explorerErrors.K8sClientNotInitialized())
+		return
+	}
+	res, err := Resolve(target)
+	if err != nil {
+		explorerErrors.Write(w, err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	out, err := newSink(cancel, w, r)
+	if err != nil {
+		log.Printf("Log stream: %v", err)
+		return
+	}
+	defer out.close()
+
+	streamer := NewStreamer(KubeOpener(client), opts)
+	for _, src := range res.Sources {
+		streamer.Add(ctx, src)
+	}
+	if err := out.send("connected", map[string]any{
+		"kind":      target.Kind,
+		"namespace": target.Namespace,
+		"name":      target.Name,
+		"sources":   res.Sources,
+		"truncated": res.Truncated,
+		"follow":    opts.Follow,
+	}); err != nil {
+		return
+	}
+
+	// Pick up pods a followed workload starts (rollouts, scale-ups, restarts)
+	watchWorkload := opts.Follow && target.IsWorkload()
+	if watchWorkload {
+		go func() {
+			ticker := time.NewTicker(rescanInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					res, err := Resolve(target)
+					if err != nil {
+						continue
+					}
+					for _, src := range res.Sources {
+						if streamer.Add(ctx, src) {
+							out.send("source_added", src)
+						}
+					}
+				}
+			}
+		}()
+	}
+
+	err = streamer.Run(ctx, !watchWorkload, func(ev Event) error {
+		if ev.End != nil {
+			return out.send("source_end", ev.End)
+		}
+		return out.send("log", ev.Line)
+	})
+	if err == nil {
+		out.send("end", map[string]string{"reason": "all sources ended"})
+	}
+}
+
+// sink writes stream events over SSE or WebSocket. Safe for concurrent use.
+type sink struct {
+	mu      sync.Mutex
+	w       http.ResponseWriter
+	flusher http.Flusher
+	conn    *websocket.Conn
+}
+
+func newSink(cancel context.CancelFunc, w http.ResponseWriter, r *http.Request) (*sink, error) {
+	if websocket.IsWebSocketUpgrade(r) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return nil, fmt.Errorf("WebSocket upgrade failed: %w", err)
+		}
+		// The client only closes; reading is how the close is noticed
+		go func() {
+			defer cancel()
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+		return &sink{conn: conn}, nil
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		explorerErrors.WriteHTTP(w, http.StatusInternalServerError, "Streaming not supported")
+		return nil, fmt.Errorf("streaming not supported")
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	return &sink{w: w, flusher: flusher}, nil
+}
+
+func (s *sink) send(event string, data any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn != nil {
+		return s.conn.WriteJSON(map[string]any{"event": event, "data": data})
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
+
+func (s *sink) close() {
+	if s.conn == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	s.conn.Close()
+}
@@ -0,0 +1,168 @@
+package logs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// MaxPods caps how many pods of a workload are tailed at once
+const MaxPods = 20
+
+// Target is what to tail: a pod, or every pod of a workload
+type Target struct {
+	Kind      string // pods, deployments, statefulsets, daemonsets, replicasets or jobs
+	Namespace string
+	Name      string
+	Container string // Only this container (empty = all)
+	Init      bool   // Include init containers
+}
+
+// IsWorkload reports whether the target selects pods by label, so new pods can appear
+func (t Target) IsWorkload() bool {
+	return normalizeKind(t.Kind) != "pods"
+}
+
+// Resolution is the set of sources a target currently maps to
+type Resolution struct {
+	Sources   []Source `json:"sources"`
+	Truncated bool     `json:"truncated,omitempty"` // More than MaxPods pods matched
+}
+
+// Resolve lists the containers to tail for a target from the resource cache. Pods of a
+// workload that haven't started yet are skipped; callers following a workload resolve
+// again to pick them up.
+func Resolve(t Target) (Resolution, error) {
+	cache := k8s.GetResourceCache()
+	if cache == nil {
+		return Resolution{}, explorerErrors.CacheNotInitialized()
+	}
+
+	var pods []*corev1.Pod
+	kind := normalizeKind(t.Kind)
+	if kind == "pods" {
+		pod, err := cache.Pods().Pods(t.Namespace).Get(t.Name)
+		if err != nil {
+			return Resolution{}, explorerErrors.K8sResourceNotFound("Pod", t.Namespace, t.Name)
+		}
+		pods = []*corev1.Pod{pod}
+	} else {
+		selector, err := workloadSelector(kind, t.Namespace, t.Name)
+		if err != nil {
+			return Resolution{}, err
+		}
+		all, err := cache.Pods().Pods(t.Namespace).List(selector)
+		if err != nil {
+			return Resolution{}, explorerErrors.InternalError("failed to list pods", err)
+		}
+		for _, pod := range all {
+			if pod.Status.Phase != corev1.PodPending {
+				pods = append(pods, pod)
+			}
+		}
+	}
+
+	// Newest pods first, so a capped rollout shows the current ReplicaSet
+	sort.Slice(pods, func(i, j int) bool {
+		if !pods[i].CreationTimestamp.Equal(&pods[j].CreationTimestamp) {
+			return pods[j].CreationTimestamp.Before(&pods[i].CreationTimestamp)
+		}
+		return pods[i].Name < pods[j].Name
+	})
+
+	var res Resolution
+	if len(pods) > MaxPods {
+		pods, res.Truncated = pods[:MaxPods], true
+	}
+	for _, pod := range pods {
+		var containers []corev1.Container
+		if t.Init {
+			containers = append(containers, pod.Spec.InitContainers...)
+		}
+		containers = append(containers, pod.Spec.Containers...)
+		for _, c := range containers {
+			if t.Container == "" || c.Name == t.Container {
+				res.Sources = append(res.Sources, Source{Pod: pod.Name, Container: c.Name})
+			}
+		}
+	}
+	if len(res.Sources) == 0 && t.Container != "" && !t.IsWorkload() {
+		return res, explorerErrors.ValidationError(fmt.Sprintf("pod %s has no container %q", t.Name, t.Container))
+	}
+	return res, nil
+}
+
+// workloadSelector returns the pod selector of a workload in the cache
+func workloadSelector(kind, namespace, name string) (labels.Selector, error) {
+	cache := k8s.GetResourceCache()
+	var selector *metav1.LabelSelector
+	var kindName string
+	var err error
+
+	switch kind {
+	case "deployments":
+		kindName = "Deployment"
+		if d, getErr := cache.Deployments().Deployments(namespace).Get(name); getErr == nil {
+			selector = d.Spec.Selector
+		} else {
+			err = getErr
+		}
+	case "statefulsets":
+		kindName = "StatefulSet"
+		if s, getErr := cache.StatefulSets().StatefulSets(namespace).Get(name); getErr == nil {
+			selector = s.Spec.Selector
+		} else {
+			err = getErr
+		}
+	case "daemonsets":
+		kindName = "DaemonSet"
+		if d, getErr := cache.DaemonSets().DaemonSets(namespace).Get(name); getErr == nil {
+			selector = d.Spec.Selector
+		} else {
+			err = getErr
+		}
+	case "replicasets":
+		kindName = "ReplicaSet"
+		if rs, getErr := cache.ReplicaSets().ReplicaSets(namespace).Get(name); getErr == nil {
+			selector = rs.Spec.Selector
+		} else {
+			err = getErr
+		}
+	case "jobs":
+		kindName = "Job"
+		if j, getErr := cache.Jobs().Jobs(namespace).Get(name); getErr == nil {
+			selector = j.Spec.Selector
+		} else {
+			err = getErr
+		}
+	default:
+		return nil, explorerErrors.ValidationError(fmt.Sprintf("logs are not supported for kind %q (use pods, deployments, statefulsets, daemonsets, replicasets or jobs)", kind))
+	}
+	if err != nil {
+		return nil, explorerErrors.K8sResourceNotFound(kindName, namespace, name)
+	}
+	if selector == nil {
+		return nil, explorerErrors.ValidationError(fmt.Sprintf("%s %s/%s has no pod selector", kindName, namespace, name))
+	}
+	sel, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, explorerErrors.ValidationError(fmt.Sprintf("invalid selector on %s %s/%s: %v", kindName, namespace, name, err))
+	}
+	return sel, nil
+}
+
+// normalizeKind accepts singular and Kind forms ("deployment", "Deployment")
+func normalizeKind(kind string) string {
+	kind = strings.ToLower(kind)
+	if !strings.HasSuffix(kind, "s") {
+		kind += "s"
+	}
+	return kind
+}
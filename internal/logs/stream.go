@@ -0,0 +1,304 @@
+// Package logs tails container logs from one or more pods and merges them into a single
+// time-ordered stream, with each line labelled by pod and container and regex filters
+// applied server-side.
+package logs
+
+import (
+	"bufio"
+	"container/heap"
+	"context"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// mergeWindow is how long a line is held so slightly later lines from other
+	// containers with earlier timestamps can be sorted in front of it
+	mergeWindow = 250 * time.Millisecond
+	// initialWindow holds the first lines longer, while every source sends its tail
+	initialWindow = time.Second
+	// flushInterval is how often held lines are checked
+	flushInterval = 100 * time.Millisecond
+)
+
+// Source is one container of one pod
+type Source struct {
+	Pod       string `json:"pod"`
+	Container string `json:"container"`
+}
+
+// Line is a log line labelled with its source
+type Line struct {
+	Timestamp string `json:"timestamp,omitempty"` // RFC3339Nano, as sent by the kubelet
+	Pod       string `json:"pod"`
+	Container string `json:"container"`
+	Content   string `json:"content"`
+
+	time    time.Time // Parsed timestamp, or arrival time if the line had none
+	arrived time.Time
+	seq     uint64 // Arrival order; keeps lines with equal timestamps stable
+}
+
+// SourceEnd reports that a source stopped sending lines
+type SourceEnd struct {
+	Source
+	Reason string `json:"reason"` // "ended" or "error"
+	Error  string `json:"error,omitempty"`
+}
+
+// Event is either a log line or the end of a source
+type Event struct {
+	Line *Line
+	End  *SourceEnd
+}
+
+// Options control what is read from each source and which lines are kept
+type Options struct {
+	Namespace    string
+	TailLines    int64 // 0 = all
+	SinceSeconds int64 // 0 = no limit
+	Follow       bool
+	Previous     bool           // Logs of the previous container instance
+	Filter       *regexp.Regexp // Keep only matching lines (nil = all)
+	InvertFilter bool           // Keep only lines that don't match Filter
+}
+
+func (o *Options) keep(content string) bool {
+	if o.Filter == nil {
+		return true
+	}
+	return o.Filter.MatchString(content) != o.InvertFilter
+}
+
+// Opener opens a container's log stream, with timestamps on each line
+type Opener func(ctx context.Context, namespace string, src Source, opts *corev1.PodLogOptions) (io.ReadCloser, error)
+
+// KubeOpener opens log streams through the Kubernetes API
+func KubeOpener(client kubernetes.Interface) Opener {
+	return func(ctx context.Context, namespace string, src Source, opts *corev1.PodLogOptions) (io.ReadCloser, error) {
+		return client.CoreV1().Pods(namespace).GetLogs(src.Pod, opts).Stream(ctx)
+	}
+}
+
+// Streamer tails a set of sources and merges their lines. Sources can be added while it
+// runs, e.g. when a workload starts new pods.
+type Streamer struct {
+	opts   Options
+	open   Opener
+	events chan Event
+
+	mu     sync.Mutex
+	active map[Source]bool
+	seen   map[Source]bool
+}
+
+// NewStreamer creates a streamer reading logs with open
+func NewStreamer(open Opener, opts Options) *Streamer {
+	return &Streamer{
+		opts:   opts,
+		open:   open,
+		events: make(chan Event, 1024),
+		active: make(map[Source]bool),
+		seen:   make(map[Source]bool),
+	}
+}
+
+// Add starts tailing a source. Sources already added, even ones that have ended, are
+// ignored so a restarted workload scan doesn't replay them.
+func (s *Streamer) Add(ctx context.Context, src Source) bool {
+	s.mu.Lock()
+	if s.seen[src] {
+		s.mu.Unlock()
+		return false
+	}
+	s.seen[src] = true
+	s.active[src] = true
+	s.mu.Unlock()
+
+	go s.tail(ctx, src)
+	return true
+}
+
+// Active returns the number of sources still sending lines
+func (s *Streamer) Active() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.active)
+}
+
+// tail reads one source line by line until it ends or ctx is cancelled
+func (s *Streamer) tail(ctx context.Context, src Source) {
+	podOpts := &corev1.PodLogOptions{
+		Container:  src.Container,
+		Follow:     s.opts.Follow,
+		Previous:   s.opts.Previous,
+		Timestamps: true,
+	}
+	if s.opts.TailLines > 0 {
+		podOpts.TailLines = &s.opts.TailLines
+	}
+	if s.opts.SinceSeconds > 0 {
+		podOpts.SinceSeconds = &s.opts.SinceSeconds
+	}
+
+	end := &SourceEnd{Source: src, Reason: "ended"}
+	defer func() {
+		select {
+		case s.events <- Event{End: end}:
+		case <-ctx.Done():
+		}
+	}()
+
+	stream, err := s.open(ctx, s.opts.Namespace, src, podOpts)
+	if err != nil {
+		end.Reason, end.Error = "error", err.Error()
+		return
+	}
+	defer stream.Close()
+
+	reader := bufio.NewReader(stream)
+	for {
+		text, err := reader.ReadString('\n')
+		if text = strings.TrimRight(text, "\r\n"); text != "" {
+			timestamp, content := ParseLine(text)
+			line := &Line{Timestamp: timestamp, Pod: src.Pod, Container: src.Container, Content: content}
+			select {
+			case s.events <- Event{Line: line}:
+			case <-ctx.Done():
+				return
+			}
+		}
+		if err != nil {
+			if err != io.EOF && ctx.Err() == nil {
+				end.Reason, end.Error = "error", err.Error()
+			}
+			return
+		}
+	}
+}
+
+// Run merges lines from all sources in timestamp order and passes them, and source ends,
+// to emit. It returns when ctx is done, emit fails, or — if exitWhenIdle — every source
+// has ended.
+func (s *Streamer) Run(ctx context.Context, exitWhenIdle bool, emit func(Event) error) error {
+	start := time.Now()
+	var pending lineHeap
+	var ends []heldEnd
+	var seq uint64
+
+	// flush emits held lines that arrived before cutoff, oldest timestamp first, then the
+	// end notices of sources with no lines left to emit
+	flush := func(cutoff time.Time) error {
+		for pending.Len() > 0 && !pending[0].arrived.After(cutoff) {
+			if err := emit(Event{Line: heap.Pop(&pending).(*Line)}); err != nil {
+				return err
+			}
+		}
+		kept := ends[:0]
+		for _, end := range ends {
+			if end.arrived.After(cutoff) || pending.hasSource(end.Source) {
+				kept = append(kept, end)
+				continue
+			}
+			if err := emit(Event{End: end.SourceEnd}); err != nil {
+				return err
+			}
+		}
+		ends = kept
+		return nil
+	}
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		if exitWhenIdle && s.Active() == 0 && len(s.events) == 0 {
+			return flush(time.Now().Add(time.Hour))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case ev := <-s.events:
+			if ev.End != nil {
+				s.mu.Lock()
+				delete(s.active, ev.End.Source)
+				s.mu.Unlock()
+				ends = append(ends, heldEnd{SourceEnd: ev.End, arrived: time.Now()})
+				continue
+			}
+			line := ev.Line
+			if !s.opts.keep(line.Content) {
+				continue
+			}
+			seq++
+			line.arrived, line.seq = time.Now(), seq
+			line.time = line.arrived
+			if t, err := time.Parse(time.RFC3339Nano, line.Timestamp); err == nil {
+				line.time = t
+			}
+			heap.Push(&pending, line)
+
+		case now := <-ticker.C:
+			if now.Sub(start) < initialWindow {
+				continue
+			}
+			if err := flush(now.Add(-mergeWindow)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// heldEnd is a source end waiting for the source's held lines to be emitted
+type heldEnd struct {
+	*SourceEnd
+	arrived time.Time
+}
+
+// ParseLine splits a kubelet timestamp (RFC3339Nano, as added by timestamps=true) from
+// the log content
+func ParseLine(line string) (timestamp, content string) {
+	if len(line) > 30 && line[4] == '-' && line[7] == '-' && line[10] == 'T' {
+		spaceIdx := strings.Index(line, " ")
+		if spaceIdx > 20 && spaceIdx < 40 {
+			return line[:spaceIdx], line[spaceIdx+1:]
+		}
+	}
+	return "", line
+}
+
+// lineHeap orders held lines by timestamp, then arrival
+type lineHeap []*Line
+
+func (h lineHeap) Len() int { return len(h) }
+func (h lineHeap) Less(i, j int) bool {
+	if !h[i].time.Equal(h[j].time) {
+		return h[i].time.Before(h[j].time)
+	}
+	return h[i].seq < h[j].seq
+}
+func (h lineHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *lineHeap) Push(x any)   { *h = append(*h, x.(*Line)) }
+func (h lineHeap) hasSource(src Source) bool {
+	for _, line := range h {
+		if line.Pod == src.Pod && line.Container == src.Container {
+			return true
+		}
+	}
+	return false
+}
+func (h *lineHeap) Pop() any {
+	old := *h
+	n := len(old)
+	line := old[n-1]
+	*h = old[:n-1]
+	return line
+}
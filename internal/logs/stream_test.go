@@ -0,0 +1,137 @@
+package logs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// fakeOpener serves fixed log output per container
+func fakeOpener(logs map[string]string) Opener {
+	return func(ctx context.Context, namespace string, src Source, opts *corev1.PodLogOptions) (io.ReadCloser, error) {
+		text, ok := logs[src.Pod+"/"+src.Container]
+		if !ok {
+			return nil, errors.New("container not found")
+		}
+		return io.NopCloser(strings.NewReader(text)), nil
+	}
+}
+
+func run(t *testing.T, opts Options, logs map[string]string, sources ...Source) []Event {
+	t.Helper()
+	s := NewStreamer(fakeOpener(logs), opts)
+	for _, src := range sources {
+		s.Add(context.Background(), src)
+	}
+	var events []Event
+	err := s.Run(context.Background(), true, func(ev Event) error {
+		events = append(events, ev)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	return events
+}
+
+func TestStreamerMergesByTimestamp(t *testing.T) {
+	logs := map[string]string{
+		"web-1/app":   "2024-01-01T00:00:01.000000000Z a1\n2024-01-01T00:00:03.000000000Z a3\n",
+		"web-1/proxy": "2024-01-01T00:00:02.000000000Z p2\n2024-01-01T00:00:04.000000000Z p4\n",
+		"web-2/app":   "2024-01-01T00:00:00.500000000Z b0\n",
+	}
+	events := run(t, Options{}, logs,
+		Source{Pod: "web-1", Container: "app"},
+		Source{Pod: "web-1", Container: "proxy"},
+		Source{Pod: "web-2", Container: "app"},
+	)
+
+	var got []string
+	ended := map[Source]bool{}
+	for _, ev := range events {
+		if ev.End != nil {
+			ended[ev.End.Source] = true
+			continue
+		}
+		src := Source{Pod: ev.Line.Pod, Container: ev.Line.Container}
+		if ended[src] {
+			t.Errorf("line %q emitted after its source ended", ev.Line.Content)
+		}
+		got = append(got, ev.Line.Content)
+	}
+	if want := "b0 a1 p2 a3 p4"; strings.Join(got, " ") != want {
+		t.Errorf("merged order = %v, want %s", got, want)
+	}
+	if len(ended) != 3 {
+		t.Errorf("ended sources = %v, want all 3", ended)
+	}
+}
+
+func TestStreamerFilter(t *testing.T) {
+	logs := map[string]string{
+		"api/app": "2024-01-01T00:00:01.000000000Z GET /health\n2024-01-01T00:00:02.000000000Z ERROR boom\n2024-01-01T00:00:03.000000000Z GET /users\n",
+	}
+	src := Source{Pod: "api", Container: "app"}
+
+	tests := []struct {
+		name   string
+		invert bool
+		want   string
+	}{
+		{"match", false, "ERROR boom"},
+		{"invert", true, "GET /health|GET /users"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := Options{Filter: regexp.MustCompile(`^ERROR`), InvertFilter: tt.invert}
+			var got []string
+			for _, ev := range run(t, opts, logs, src) {
+				if ev.Line != nil {
+					got = append(got, ev.Line.Content)
+				}
+			}
+			if strings.Join(got, "|") != tt.want {
+				t.Errorf("lines = %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamerSourceError(t *testing.T) {
+	events := run(t, Options{}, map[string]string{}, Source{Pod: "gone", Container: "app"})
+	if len(events) != 1 || events[0].End == nil {
+		t.Fatalf("events = %+v, want a single end", events)
+	}
+	if end := events[0].End; end.Reason != "error" || end.Error == "" {
+		t.Errorf("end = %+v, want reason error with a message", end)
+	}
+}
+
+func TestStreamerAddDeduplicates(t *testing.T) {
+	s := NewStreamer(fakeOpener(map[string]string{"p/c": ""}), Options{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	src := Source{Pod: "p", Container: "c"}
+	if !s.Add(ctx, src) {
+		t.Fatal("first Add returned false")
+	}
+	if s.Add(ctx, src) {
+		t.Error("second Add of the same source returned true")
+	}
+}
+
+func TestParseLine(t *testing.T) {
+	ts, content := ParseLine("2024-01-01T12:34:56.123456789Z hello world")
+	if ts != "2024-01-01T12:34:56.123456789Z" || content != "hello world" {
+		t.Errorf("ParseLine = %q, %q", ts, content)
+	}
+	ts, content = ParseLine("no timestamp here")
+	if ts != "" || content != "no timestamp here" {
+		t.Errorf("ParseLine without timestamp = %q, %q", ts, content)
+	}
+}
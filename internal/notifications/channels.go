@@ -0,0 +1,227 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// pagerDutyEventsURL is the PagerDuty Events API v2 endpoint
+const pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// HTTP client for webhook deliveries
+var httpClient = &http.Client{
+	Timeout: 10 * time.Second,
+}
+
+// Channel delivers alerts to a single destination
+type Channel interface {
+	Name() string
+	Type() ChannelType
+	Info() ChannelInfo
+	// Send delivers the alert, returning the HTTP status code when applicable
+	Send(ctx context.Context, alert Alert) (int, error)
+}
+
+// newChannel builds a Channel from its configuration
+func newChannel(cfg ChannelConfig) (Channel, error) {
+	if cfg.Name == "" {
+		return nil, fmt.Errorf("channel name is required")
+	}
+	switch cfg.Type {
+	case ChannelSlack, ChannelWebhook:
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("channel %q: url is required", cfg.Name)
+		}
+		if _, err := url.ParseRequestURI(cfg.URL); err != nil {
+			return nil, fmt.Errorf("channel %q: invalid url: %w", cfg.Name, err)
+		}
+		return &httpChannel{cfg: cfg}, nil
+	case ChannelEmail:
+		if cfg.SMTPHost == "" || cfg.From == "" || len(cfg.To) == 0 {
+			return nil, fmt.Errorf("channel %q: smtpHost, from and to are required", cfg.Name)
+		}
+		return &emailChannel{cfg: cfg}, nil
+	case ChannelPagerDuty:
+		if cfg.RoutingKey == "" {
+			return nil, fmt.Errorf("channel %q: routingKey is required", cfg.Name)
+		}
+		if cfg.URL == "" {
+			cfg.URL = pagerDutyEventsURL
+		} else if _, err := url.ParseRequestURI(cfg.URL); err != nil {
+			return nil, fmt.Errorf("channel %q: invalid url: %w", cfg.Name, err)
+		}
+		return &httpChannel{cfg: cfg}, nil
+	default:
+		return nil, fmt.Errorf("channel %q: unknown type %q", cfg.Name, cfg.Type)
+	}
+}
+
+// httpChannel posts alerts to Slack incoming webhooks or generic webhook endpoints
+type httpChannel struct {
+	cfg ChannelConfig
+}
+
+func (c *httpChannel) Name() string      { return c.cfg.Name }
+func (c *httpChannel) Type() ChannelType { return c.cfg.Type }
+
+func (c *httpChannel) Info() ChannelInfo {
+	target := ""
+	if u, err := url.Parse(c.cfg.URL); err == nil {
+		target = u.Host
+	}
+	return ChannelInfo{Name: c.cfg.Name, Type: c.cfg.Type, Target: target}
+}
+
+func (c *httpChannel) Send(ctx context.Context, alert Alert) (int, error) {
+	var payload any = alert
+	switch c.cfg.Type {
+	case ChannelSlack:
+		payload = map[string]string{"text": formatText(alert)}
+	case ChannelPagerDuty:
+		payload = pagerDutyEvent(c.cfg.RoutingKey, alert)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range c.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// emailChannel sends alerts over SMTP
+type emailChannel struct {
+	cfg ChannelConfig
+}
+
+func (c *emailChannel) Name() string      { return c.cfg.Name }
+func (c *emailChannel) Type() ChannelType { return ChannelEmail }
+
+func (c *emailChannel) Info() ChannelInfo {
+	return ChannelInfo{Name: c.cfg.Name, Type: ChannelEmail, Target: strings.Join(c.cfg.To, ", ")}
+}
+
+func (c *emailChannel) Send(ctx context.Context, alert Alert) (int, error) {
+	port := c.cfg.SMTPPort
+	if port == 0 {
+		port = 587
+	}
+	addr := net.JoinHostPort(c.cfg.SMTPHost, strconv.Itoa(port))
+
+	var auth smtp.Auth
+	if c.cfg.Username != "" {
+		auth = smtp.PlainAuth("", c.cfg.Username, c.cfg.Password, c.cfg.SMTPHost)
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", c.cfg.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(c.cfg.To, ", "))
+	fmt.Fprintf(&msg, "Subject: [%s] %s\r\n", strings.ToUpper(string(alert.Severity)), alert.Title)
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(formatText(alert))
+	msg.WriteString("\r\n")
+
+	// smtp.SendMail has no context support, so run it in the background and honor cancellation
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- smtp.SendMail(addr, auth, c.cfg.From, c.cfg.To, []byte(msg.String()))
+	}()
+
+	select {
+	case err := <-errCh:
+		return 0, err
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// pagerDutyEvent builds an Events API v2 event. Health alerts and their resolutions share
+// a dedup key, so a recovery resolves the incident its alert opened.
+func pagerDutyEvent(routingKey string, alert Alert) map[string]any {
+	dedupKey := alert.DedupKey
+	if dedupKey == "" {
+		dedupKey = fmt.Sprintf("radar-%d", alert.Timestamp.UnixNano())
+	}
+	if alert.Resolved {
+		return map[string]any{"routing_key": routingKey, "event_action": "resolve", "dedup_key": dedupKey}
+	}
+
+	source := alert.Resource
+	if source == "" {
+		source = "radar"
+	}
+	if alert.Cluster != "" {
+		source = alert.Cluster + "/" + source
+	}
+	summary := alert.Title
+	if alert.Test {
+		summary = "[TEST] " + summary
+	}
+	details := map[string]any{"message": alert.Message}
+	for k, v := range alert.Labels {
+		details[k] = v
+	}
+	return map[string]any{
+		"routing_key":  routingKey,
+		"event_action": "trigger",
+		"dedup_key":    dedupKey,
+		"payload": map[string]any{
+			"summary":        summary,
+			"source":         source,
+			"severity":       string(alert.Severity), // info, warning and critical are PagerDuty severities too
+			"timestamp":      alert.Timestamp.Format(time.RFC3339),
+			"component":      alert.Resource,
+			"custom_details": details,
+		},
+	}
+}
+
+// formatText renders an alert as plain text for chat and email channels
+func formatText(alert Alert) string {
+	var b strings.Builder
+	if alert.Test {
+		b.WriteString("[TEST] ")
+	}
+	if alert.Resolved {
+		fmt.Fprintf(&b, "[RESOLVED] %s", alert.Title)
+	} else {
+		fmt.Fprintf(&b, "%s (%s)", alert.Title, alert.Severity)
+	}
+	if alert.Cluster != "" {
+		fmt.Fprintf(&b, " - cluster: %s", alert.Cluster)
+	}
+	if alert.Message != "" {
+		b.WriteString("\n")
+		b.WriteString(alert.Message)
+	}
+	return b.String()
+}
@@ -0,0 +1,191 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// captureServer records the last request body and headers posted to it
+func captureServer(t *testing.T, status int) (*httptest.Server, *map[string]any, *http.Header) {
+	t.Helper()
+	var body map[string]any
+	var header http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body = nil
+		if err := json.Unmarshal(data, &body); err != nil {
+			t.Errorf("body is not JSON: %s", data)
+		}
+		header = r.Header.Clone()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &body, &header
+}
+
+func send(t *testing.T, cfg ChannelConfig, alert Alert) (int, error) {
+	t.Helper()
+	ch, err := newChannel(cfg)
+	if err != nil {
+		t.Fatalf("newChannel: %v", err)
+	}
+	return ch.Send(context.Background(), alert)
+}
+
+var testAlert = Alert{
+	Title:     "Deployment/prod/api is unhealthy",
+	Message:   "CrashLoopBackOff: back-off restarting failed container",
+	Severity:  SeverityCritical,
+	Cluster:   "prod-us",
+	Timestamp: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	Resource:  "Deployment/prod/api",
+	Labels:    map[string]string{"team": "payments"},
+	DedupKey:  "radar/prod-us/pager/Deployment/prod/api",
+}
+
+func TestSlackPayload(t *testing.T) {
+	srv, body, _ := captureServer(t, http.StatusOK)
+	status, err := send(t, ChannelConfig{Name: "slack", Type: ChannelSlack, URL: srv.URL}, testAlert)
+	if err != nil || status != http.StatusOK {
+		t.Fatalf("Send = %d, %v", status, err)
+	}
+	want := "Deployment/prod/api is unhealthy (critical) - cluster: prod-us\nCrashLoopBackOff: back-off restarting failed container"
+	if got := (*body)["text"]; got != want {
+		t.Errorf("text = %q, want %q", got, want)
+	}
+	if len(*body) != 1 {
+		t.Errorf("unexpected fields in Slack payload: %v", *body)
+	}
+}
+
+func TestWebhookPayload(t *testing.T) {
+	srv, body, header := captureServer(t, http.StatusAccepted)
+	cfg := ChannelConfig{Name: "hook", Type: ChannelWebhook, URL: srv.URL, Headers: map[string]string{"Authorization": "Bearer abc"}}
+	if _, err := send(t, cfg, testAlert); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if got := header.Get("Authorization"); got != "Bearer abc" {
+		t.Errorf("Authorization = %q", got)
+	}
+	if got := header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	for field, want := range map[string]any{
+		"title":    testAlert.Title,
+		"severity": "critical",
+		"cluster":  "prod-us",
+		"resource": "Deployment/prod/api",
+		"dedupKey": testAlert.DedupKey,
+	} {
+		if got := (*body)[field]; got != want {
+			t.Errorf("%s = %v, want %v", field, got, want)
+		}
+	}
+	if _, ok := (*body)["resolved"]; ok {
+		t.Error("resolved should be omitted for a firing alert")
+	}
+}
+
+func TestPagerDutyPayload(t *testing.T) {
+	srv, body, _ := captureServer(t, http.StatusAccepted)
+	cfg := ChannelConfig{Name: "pager", Type: ChannelPagerDuty, URL: srv.URL, RoutingKey: "R0UT1NG"}
+
+	if _, err := send(t, cfg, testAlert); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if (*body)["routing_key"] != "R0UT1NG" || (*body)["event_action"] != "trigger" || (*body)["dedup_key"] != testAlert.DedupKey {
+		t.Errorf("unexpected envelope: %v", *body)
+	}
+	payload, _ := (*body)["payload"].(map[string]any)
+	for field, want := range map[string]any{
+		"summary":   testAlert.Title,
+		"source":    "prod-us/Deployment/prod/api",
+		"severity":  "critical",
+		"timestamp": "2026-01-02T03:04:05Z",
+		"component": "Deployment/prod/api",
+	} {
+		if got := payload[field]; got != want {
+			t.Errorf("payload.%s = %v, want %v", field, got, want)
+		}
+	}
+	details, _ := payload["custom_details"].(map[string]any)
+	if details["team"] != "payments" || details["message"] != testAlert.Message {
+		t.Errorf("custom_details = %v", details)
+	}
+
+	// The resolution reuses the dedup key and carries no payload
+	resolved := testAlert
+	resolved.Resolved = true
+	if _, err := send(t, cfg, resolved); err != nil {
+		t.Fatalf("Send resolved: %v", err)
+	}
+	if (*body)["event_action"] != "resolve" || (*body)["dedup_key"] != testAlert.DedupKey {
+		t.Errorf("unexpected resolve event: %v", *body)
+	}
+	if _, ok := (*body)["payload"]; ok {
+		t.Error("resolve event should not carry a payload")
+	}
+}
+
+func TestPagerDutyTestAlert(t *testing.T) {
+	event := pagerDutyEvent("key", Alert{Title: "Radar test notification", Severity: SeverityInfo, Test: true, Timestamp: testAlert.Timestamp})
+	payload := event["payload"].(map[string]any)
+	if payload["summary"] != "[TEST] Radar test notification" || payload["source"] != "radar" {
+		t.Errorf("payload = %v", payload)
+	}
+	if key, _ := event["dedup_key"].(string); !strings.HasPrefix(key, "radar-") {
+		t.Errorf("dedup_key = %q, want a generated radar- key", key)
+	}
+}
+
+func TestSendReportsStatus(t *testing.T) {
+	srv, _, _ := captureServer(t, http.StatusForbidden)
+	status, err := send(t, ChannelConfig{Name: "hook", Type: ChannelWebhook, URL: srv.URL}, testAlert)
+	if err == nil || status != http.StatusForbidden {
+		t.Errorf("Send = %d, %v; want 403 and an error", status, err)
+	}
+}
+
+func TestFormatTextResolved(t *testing.T) {
+	got := formatText(Alert{Title: "Deployment/prod/api recovered", Resolved: true, Test: true})
+	if want := "[TEST] [RESOLVED] Deployment/prod/api recovered"; got != want {
+		t.Errorf("formatText = %q, want %q", got, want)
+	}
+}
+
+func TestNewChannelValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  ChannelConfig
+		want string
+	}{
+		{"missing name", ChannelConfig{Type: ChannelSlack, URL: "https://hooks.example.com"}, "name is required"},
+		{"slack without url", ChannelConfig{Name: "s", Type: ChannelSlack}, "url is required"},
+		{"bad url", ChannelConfig{Name: "w", Type: ChannelWebhook, URL: "not a url"}, "invalid url"},
+		{"email without recipients", ChannelConfig{Name: "e", Type: ChannelEmail, SMTPHost: "smtp", From: "radar@example.com"}, "smtpHost, from and to are required"},
+		{"pagerduty without key", ChannelConfig{Name: "p", Type: ChannelPagerDuty}, "routingKey is required"},
+		{"unknown type", ChannelConfig{Name: "x", Type: "carrier-pigeon"}, "unknown type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newChannel(tt.cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("newChannel error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	ch, err := newChannel(ChannelConfig{Name: "p", Type: ChannelPagerDuty, RoutingKey: "key"})
+	if err != nil {
+		t.Fatalf("newChannel: %v", err)
+	}
+	if got := ch.Info().Target; got != "events.pagerduty.com" {
+		t.Errorf("PagerDuty target = %q, want the default Events API host", got)
+	}
+}
@@ -0,0 +1,157 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+)
+
+// Handlers provides HTTP handlers for notification endpoints
+type Handlers struct{}
+
+// NewHandlers creates a new Handlers instance
+func NewHandlers() *Handlers {
+	return &Handlers{}
+}
+
+// RegisterRoutes registers notification routes on the given router
+func (h *Handlers) RegisterRoutes(r chi.Router) {
+	r.Route("/notifications", func(r chi.Router) {
+		r.Get("/channels", h.handleListChannels)
+		r.Post("/test", h.handleTestFire)
+		r.Get("/triggers", h.handleListTriggers)
+		r.Post("/triggers/{name}/test", h.handleTestTrigger)
+		r.Get("/rules", h.handleListRules)
+		r.Get("/deliveries", h.handleListDeliveries)
+	})
+}
+
+// TestFireRequest selects which channels to test (empty = all)
+type TestFireRequest struct {
+	Channels []string `json:"channels,omitempty"`
+}
+
+// TestFireResponse reports per-channel delivery results
+type TestFireResponse struct {
+	Results   []DeliveryResult `json:"results"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+}
+
+// handleListChannels returns configured notification channels
+func (h *Handlers) handleListChannels(w http.ResponseWriter, r *http.Request) {
+	m := GetManager()
+	if m == nil {
+		writeJSON(w, []ChannelInfo{})
+		return
+	}
+	writeJSON(w, m.Channels())
+}
+
+// handleTestFire sends a synthetic alert through the selected channels
+func (h *Handlers) handleTestFire(w http.ResponseWriter, r *http.Request) {
+	m := GetManager()
+	if m == nil {
+		writeError(w, http.StatusServiceUnavailable, "Notifications not configured")
+		return
+	}
+
+	var req TestFireRequest
+	if r.Body != nil && r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+	}
+	if ch := r.URL.Query().Get("channel"); ch != "" {
+		req.Channels = append(req.Channels, ch)
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	results, err := m.TestFire(ctx, req.Channels...)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp := TestFireResponse{Results: results}
+	for _, res := range results {
+		if res.Success {
+			resp.Succeeded++
+		} else {
+			resp.Failed++
+		}
+	}
+	writeJSON(w, resp)
+}
+
+// handleListTriggers returns configured lifecycle triggers
+func (h *Handlers) handleListTriggers(w http.ResponseWriter, r *http.Request) {
+	m := GetManager()
+	if m == nil {
+		writeJSON(w, []TriggerInfo{})
+		return
+	}
+	writeJSON(w, m.Triggers())
+}
+
+// handleListRules returns configured health alert rules
+func (h *Handlers) handleListRules(w http.ResponseWriter, r *http.Request) {
+	m := GetManager()
+	if m == nil {
+		writeJSON(w, []RuleInfo{})
+		return
+	}
+	writeJSON(w, m.Rules())
+}
+
+// handleListDeliveries returns recent trigger deliveries and rule alerts, newest first
+func (h *Handlers) handleListDeliveries(w http.ResponseWriter, r *http.Request) {
+	m := GetManager()
+	if m == nil {
+		writeJSON(w, []TriggerDelivery{})
+		return
+	}
+	writeJSON(w, m.Deliveries())
+}
+
+// handleTestTrigger delivers a synthetic event through one trigger
+func (h *Handlers) handleTestTrigger(w http.ResponseWriter, r *http.Request) {
+	m := GetManager()
+	if m == nil {
+		writeError(w, http.StatusServiceUnavailable, "Notifications not configured")
+		return
+	}
+
+	// Allow for retries with backoff
+	ctx, cancel := context.WithTimeout(r.Context(), 2*time.Minute)
+	defer cancel()
+
+	result, err := m.TestTrigger(ctx, chi.URLParam(r, "name"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeJSON(w, result)
+}
+
+func writeJSON(w http.ResponseWriter, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(data)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	explorerErrors.WriteHTTP(w, status, message)
+}
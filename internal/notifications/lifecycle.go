@@ -0,0 +1,242 @@
+package notifications
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/skyhook-io/radar/internal/timeline"
+)
+
+const lifecycleCheckInterval = 15 * time.Second
+
+// unhealthyEpisode tracks a resource from the moment it turns unhealthy until it recovers
+type unhealthyEpisode struct {
+	event   LifecycleEvent  // Latest observed state
+	since   time.Time       // When the resource left the healthy state
+	fired   map[string]bool // Triggers that already received "unhealthy" for this episode
+	alerted map[string]bool // Rules that already sent an alert for this episode
+}
+
+// LifecycleWatcher turns informer events from the timeline into trigger deliveries
+// and tracks health transitions so "unhealthy" can wait for a trigger's or rule's For duration
+type LifecycleWatcher struct {
+	mu        sync.Mutex
+	episodes  map[string]*unhealthyEpisode // kind/namespace/name -> episode
+	cooldowns map[string]time.Time         // rule + resource -> no new alerts before
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+var (
+	lifecycleWatcher     *LifecycleWatcher
+	lifecycleWatcherOnce sync.Once
+)
+
+// StartLifecycleWatcher starts delivering lifecycle events to configured triggers and
+// health alerts to rules. It is a no-op if the manager is not initialized or has neither.
+func StartLifecycleWatcher() {
+	m := GetManager()
+	if m == nil || (!m.HasTriggers() && !m.HasRules()) {
+		return
+	}
+	lifecycleWatcherOnce.Do(func() {
+		w := &LifecycleWatcher{
+			episodes:  make(map[string]*unhealthyEpisode),
+			cooldowns: make(map[string]time.Time),
+			stopCh:    make(chan struct{}),
+		}
+		events, unsubscribe := timeline.Subscribe()
+		w.wg.Add(1)
+		go w.run(events, unsubscribe)
+		lifecycleWatcher = w
+		log.Printf("Lifecycle trigger watcher started")
+	})
+}
+
+// StopLifecycleWatcher stops the lifecycle watcher
+func StopLifecycleWatcher() {
+	if lifecycleWatcher != nil {
+		lifecycleWatcher.stopOnce.Do(func() {
+			close(lifecycleWatcher.stopCh)
+		})
+		lifecycleWatcher.wg.Wait()
+	}
+}
+
+// ResetLifecycleState forgets tracked health episodes (e.g. after a context switch,
+// when resources from the previous cluster will never report recovery)
+func ResetLifecycleState() {
+	if w := lifecycleWatcher; w != nil {
+		w.mu.Lock()
+		w.episodes = make(map[string]*unhealthyEpisode)
+		w.cooldowns = make(map[string]time.Time)
+		w.mu.Unlock()
+	}
+}
+
+func (w *LifecycleWatcher) run(events chan timeline.TimelineEvent, unsubscribe func()) {
+	defer w.wg.Done()
+	defer unsubscribe()
+
+	ticker := time.NewTicker(lifecycleCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stopCh:
+			return
+		case te, ok := <-events:
+			if !ok {
+				return
+			}
+			if m := GetManager(); m != nil {
+				w.handle(m, te)
+			}
+		case <-ticker.C:
+			if m := GetManager(); m != nil {
+				w.checkUnhealthy(m, time.Now())
+			}
+		}
+	}
+}
+
+// handle dispatches created/updated/deleted and updates health tracking
+func (w *LifecycleWatcher) handle(m *Manager, te timeline.TimelineEvent) {
+	if te.Source != timeline.SourceInformer {
+		return
+	}
+	ev := LifecycleEvent{
+		Kind:        te.Kind,
+		Namespace:   te.Namespace,
+		Name:        te.Name,
+		Timestamp:   te.Timestamp,
+		HealthState: string(te.HealthState),
+		Reason:      te.Reason,
+		Message:     te.Message,
+		Labels:      te.Labels,
+	}
+	switch te.EventType {
+	case timeline.EventTypeAdd:
+		ev.Type = EventCreated
+	case timeline.EventTypeUpdate:
+		ev.Type = EventUpdated
+	case timeline.EventTypeDelete:
+		ev.Type = EventDeleted
+	default:
+		return
+	}
+	ev.ID = newEventID()
+	m.Dispatch(ev)
+
+	key := te.Kind + "/" + te.Namespace + "/" + te.Name
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if ev.Type == EventDeleted {
+		if episode := w.episodes[key]; episode != nil {
+			delete(w.episodes, key)
+			w.resolve(m, key, episode, ev)
+		}
+		return
+	}
+
+	episode := w.episodes[key]
+	switch te.HealthState {
+	case timeline.HealthUnhealthy, timeline.HealthDegraded:
+		if episode == nil {
+			episode = &unhealthyEpisode{since: te.Timestamp, fired: make(map[string]bool), alerted: make(map[string]bool)}
+			w.episodes[key] = episode
+		}
+		episode.event = ev
+	case timeline.HealthHealthy:
+		if episode == nil {
+			return
+		}
+		delete(w.episodes, key)
+		since := episode.since
+		recovered := ev
+		recovered.ID = newEventID()
+		recovered.Type = EventRecovered
+		recovered.Since = &since
+		if len(episode.fired) > 0 {
+			m.Dispatch(recovered)
+		}
+		w.resolve(m, key, episode, recovered)
+	}
+}
+
+// resolve sends resolved alerts for the rules that alerted during an episode (ev is the
+// recovery or deletion) and starts their cooldowns. Called with w.mu held.
+func (w *LifecycleWatcher) resolve(m *Manager, key string, episode *unhealthyEpisode, ev LifecycleEvent) {
+	if len(episode.alerted) == 0 {
+		return
+	}
+	if ev.Since == nil {
+		since := episode.since
+		ev.Since = &since
+	}
+	m.notify(ev, func(r *rule) bool {
+		if !episode.alerted[r.cfg.Name] {
+			return false
+		}
+		if r.cooldown > 0 {
+			w.cooldowns[r.cfg.Name+"\x00"+key] = ev.Timestamp.Add(r.cooldown)
+		}
+		return true
+	})
+}
+
+// checkUnhealthy fires "unhealthy" for each trigger, and alerts for each rule, whose For
+// has elapsed (rules wait out their cooldown too)
+func (w *LifecycleWatcher) checkUnhealthy(m *Manager, now time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for key, until := range w.cooldowns {
+		if now.After(until) {
+			delete(w.cooldowns, key)
+		}
+	}
+
+	for key, episode := range w.episodes {
+		held := now.Sub(episode.since)
+		since := episode.since
+		ev := episode.event
+		ev.ID = newEventID()
+		ev.Type = EventUnhealthy
+		ev.Timestamp = now
+		ev.Since = &since
+
+		fired := m.dispatch(ev, func(t *trigger) bool {
+			return !episode.fired[t.cfg.Name] && held >= t.forDuration
+		})
+		for _, name := range fired {
+			episode.fired[name] = true
+		}
+
+		alerted := m.notify(ev, func(r *rule) bool {
+			if episode.alerted[r.cfg.Name] || held < r.forDuration {
+				return false
+			}
+			until, cooling := w.cooldowns[r.cfg.Name+"\x00"+key]
+			return !cooling || now.After(until)
+		})
+		for _, name := range alerted {
+			episode.alerted[name] = true
+		}
+	}
+}
+
+func newEventID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,246 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Manager holds the configured notification channels, lifecycle triggers and health alert rules
+type Manager struct {
+	mu         sync.RWMutex
+	channels   []Channel
+	triggers   []*trigger
+	rules      []*rule
+	dispatcher *dispatcher
+	cluster    string
+}
+
+var (
+	globalManager *Manager
+	managerMu     sync.Mutex
+)
+
+// LoadConfig reads a notifications config file (YAML or JSON)
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read notifications config: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("invalid notifications config: %w", err)
+	}
+	return cfg, nil
+}
+
+// Initialize sets up the global notification manager from config. Invalid channels,
+// triggers and rules are skipped so one bad entry doesn't disable the rest, and returned
+// joined in the error; the manager still runs with the valid entries.
+func Initialize(cfg Config, cluster string) error {
+	managerMu.Lock()
+	defer managerMu.Unlock()
+	if globalManager != nil {
+		return nil
+	}
+
+	m := &Manager{cluster: cluster, dispatcher: newDispatcher()}
+	var errs []error
+	for _, cc := range cfg.Channels {
+		ch, err := newChannel(cc)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("skipping notification channel: %w", err))
+			continue
+		}
+		m.channels = append(m.channels, ch)
+	}
+	for _, tc := range cfg.Triggers {
+		t, err := newTrigger(tc)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("skipping lifecycle trigger: %w", err))
+			continue
+		}
+		m.triggers = append(m.triggers, t)
+	}
+	for _, rc := range cfg.Rules {
+		r, err := newRule(rc)
+		if err == nil {
+			r.channels, err = m.resolveChannels(rc)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("skipping alert rule: %w", err))
+			continue
+		}
+		m.rules = append(m.rules, r)
+	}
+	globalManager = m
+	log.Printf("Notification manager initialized with %d channel(s), %d trigger(s) and %d alert rule(s)", len(m.channels), len(m.triggers), len(m.rules))
+	return errors.Join(errs...)
+}
+
+// resolveChannels looks up a rule's channels by name
+func (m *Manager) resolveChannels(rc RuleConfig) ([]Channel, error) {
+	channels := make([]Channel, 0, len(rc.Channels))
+	for _, name := range rc.Channels {
+		idx := slices.IndexFunc(m.channels, func(ch Channel) bool { return ch.Name() == name })
+		if idx < 0 {
+			return nil, fmt.Errorf("rule %q: channel not found: %s", rc.Name, name)
+		}
+		channels = append(channels, m.channels[idx])
+	}
+	return channels, nil
+}
+
+// GetManager returns the global notification manager (nil if not initialized)
+func GetManager() *Manager {
+	managerMu.Lock()
+	defer managerMu.Unlock()
+	return globalManager
+}
+
+// Reset clears the notification manager
+func Reset() {
+	managerMu.Lock()
+	defer managerMu.Unlock()
+	globalManager = nil
+}
+
+// SetCluster updates the cluster name attached to outgoing alerts (e.g. after a context switch)
+func (m *Manager) SetCluster(cluster string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cluster = cluster
+}
+
+// Channels returns the public info for all configured channels
+func (m *Manager) Channels() []ChannelInfo {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	infos := make([]ChannelInfo, 0, len(m.channels))
+	for _, ch := range m.channels {
+		infos = append(infos, ch.Info())
+	}
+	return infos
+}
+
+// Send delivers an alert through the named channels (all channels if names is empty).
+// Channels are sent to concurrently; results are returned in channel config order.
+func (m *Manager) Send(ctx context.Context, alert Alert, names ...string) ([]DeliveryResult, error) {
+	m.mu.RLock()
+	if alert.Cluster == "" {
+		alert.Cluster = m.cluster
+	}
+	targets := make([]Channel, 0, len(m.channels))
+	if len(names) == 0 {
+		targets = append(targets, m.channels...)
+	} else {
+		for _, name := range names {
+			found := false
+			for _, ch := range m.channels {
+				if ch.Name() == name {
+					targets = append(targets, ch)
+					found = true
+					break
+				}
+			}
+			if !found {
+				m.mu.RUnlock()
+				return nil, fmt.Errorf("notification channel not found: %s", name)
+			}
+		}
+	}
+	m.mu.RUnlock()
+
+	if alert.Timestamp.IsZero() {
+		alert.Timestamp = time.Now()
+	}
+
+	results := make([]DeliveryResult, len(targets))
+	var wg sync.WaitGroup
+	for i, ch := range targets {
+		wg.Add(1)
+		go func(i int, ch Channel) {
+			defer wg.Done()
+			start := time.Now()
+			status, err := ch.Send(ctx, alert)
+			result := DeliveryResult{
+				Channel:    ch.Name(),
+				Type:       ch.Type(),
+				Success:    err == nil,
+				StatusCode: status,
+				LatencyMs:  time.Since(start).Milliseconds(),
+			}
+			if err != nil {
+				result.Error = err.Error()
+			}
+			results[i] = result
+		}(i, ch)
+	}
+	wg.Wait()
+
+	return results, nil
+}
+
+// TestFire sends a synthetic alert through the named channels (all if empty)
+// so integrations can be validated without waiting for a real incident
+func (m *Manager) TestFire(ctx context.Context, names ...string) ([]DeliveryResult, error) {
+	alert := Alert{
+		Title:     "Radar test notification",
+		Message:   "This is a test alert sent from Radar to verify the notification channel is configured correctly.",
+		Severity:  SeverityInfo,
+		Timestamp: time.Now(),
+		Test:      true,
+	}
+	return m.Send(ctx, alert, names...)
+}
+
+// ValidateConfig checks every channel, trigger and rule without creating a manager, returning one error per problem
+func ValidateConfig(cfg Config) []error {
+	var errs []error
+	seen := make(map[string]bool)
+	for i, cc := range cfg.Channels {
+		if _, err := newChannel(cc); err != nil {
+			errs = append(errs, fmt.Errorf("channels[%d]: %w", i, err))
+			continue
+		}
+		if seen[cc.Name] {
+			errs = append(errs, fmt.Errorf("channels[%d]: duplicate channel name %q", i, cc.Name))
+		}
+		seen[cc.Name] = true
+	}
+	seenTriggers := make(map[string]bool)
+	for i, tc := range cfg.Triggers {
+		if _, err := newTrigger(tc); err != nil {
+			errs = append(errs, fmt.Errorf("triggers[%d]: %w", i, err))
+			continue
+		}
+		if seenTriggers[tc.Name] {
+			errs = append(errs, fmt.Errorf("triggers[%d]: duplicate trigger name %q", i, tc.Name))
+		}
+		seenTriggers[tc.Name] = true
+	}
+	seenRules := make(map[string]bool)
+	for i, rc := range cfg.Rules {
+		if _, err := newRule(rc); err != nil {
+			errs = append(errs, fmt.Errorf("rules[%d]: %w", i, err))
+			continue
+		}
+		for _, name := range rc.Channels {
+			if !seen[name] {
+				errs = append(errs, fmt.Errorf("rules[%d]: unknown channel %q", i, name))
+			}
+		}
+		if seenRules[rc.Name] {
+			errs = append(errs, fmt.Errorf("rules[%d]: duplicate rule name %q", i, rc.Name))
+		}
+		seenRules[rc.Name] = true
+	}
+	return errs
+}
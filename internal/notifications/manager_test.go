@@ -0,0 +1,50 @@
+package notifications
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInitializeReportsInvalidEntries(t *testing.T) {
+	Reset()
+	t.Cleanup(Reset)
+
+	cfg := Config{
+		Channels: []ChannelConfig{
+			{Name: "slack", Type: ChannelSlack, URL: "https://hooks.example.com/services/x"},
+			{Name: "pager", Type: ChannelPagerDuty},
+		},
+		Triggers: []TriggerConfig{{Name: "no-events", URL: "https://example.com/hook"}},
+		Rules: []RuleConfig{
+			{Name: "chat", Channels: []string{"slack"}},
+			{Name: "page", Channels: []string{"pager"}},
+		},
+	}
+	err := Initialize(cfg, "prod-us")
+	if err == nil {
+		t.Fatal("expected an error for the invalid channel, trigger and rule")
+	}
+	for _, want := range []string{`channel "pager": routingKey is required`, "skipping lifecycle trigger", `rule "page": channel not found: pager`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+
+	// The valid entries still run
+	m := GetManager()
+	if m == nil {
+		t.Fatal("manager should be initialized despite invalid entries")
+	}
+	if len(m.Channels()) != 1 || len(m.Triggers()) != 0 || len(m.Rules()) != 1 {
+		t.Errorf("got %d channel(s), %d trigger(s), %d rule(s); want 1, 0, 1", len(m.Channels()), len(m.Triggers()), len(m.Rules()))
+	}
+
+	// Later calls keep the first manager until Reset
+	if err := Initialize(Config{}, "other"); err != nil || GetManager() != m {
+		t.Errorf("second Initialize replaced the manager (err %v)", err)
+	}
+	Reset()
+	if err := Initialize(Config{}, "other"); err != nil || GetManager() == m {
+		t.Errorf("Initialize after Reset should build a new manager (err %v)", err)
+	}
+}
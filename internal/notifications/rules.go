@@ -0,0 +1,221 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"slices"
+	"time"
+)
+
+// rule is a validated RuleConfig with its channels resolved
+type rule struct {
+	cfg         RuleConfig
+	channels    []Channel
+	minSeverity Severity
+	forDuration time.Duration
+	cooldown    time.Duration
+	backoff     time.Duration
+}
+
+// newRule validates a rule config. Channels are resolved by the manager.
+func newRule(cfg RuleConfig) (*rule, error) {
+	if cfg.Name == "" {
+		return nil, fmt.Errorf("rule name is required")
+	}
+	if len(cfg.Channels) == 0 {
+		return nil, fmt.Errorf("rule %q: at least one channel is required", cfg.Name)
+	}
+	for _, pattern := range cfg.Namespaces {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("rule %q: invalid namespace pattern %q", cfg.Name, pattern)
+		}
+	}
+
+	r := &rule{cfg: cfg, minSeverity: SeverityWarning}
+	switch cfg.Severity {
+	case "", SeverityWarning:
+	case SeverityCritical:
+		r.minSeverity = SeverityCritical
+	default:
+		return nil, fmt.Errorf("rule %q: severity must be warning or critical", cfg.Name)
+	}
+	if cfg.For != "" {
+		d, err := time.ParseDuration(cfg.For)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("rule %q: invalid for duration %q", cfg.Name, cfg.For)
+		}
+		r.forDuration = d
+	}
+	if cfg.Cooldown != "" {
+		d, err := time.ParseDuration(cfg.Cooldown)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("rule %q: invalid cooldown duration %q", cfg.Name, cfg.Cooldown)
+		}
+		r.cooldown = d
+	}
+	backoff, err := parseRetry(cfg.Retry)
+	if err != nil {
+		return nil, fmt.Errorf("rule %q: %w", cfg.Name, err)
+	}
+	r.backoff = backoff
+	return r, nil
+}
+
+func (r *rule) info() RuleInfo {
+	return RuleInfo{
+		Name:       r.cfg.Name,
+		Channels:   r.cfg.Channels,
+		Kinds:      r.cfg.Kinds,
+		Namespaces: r.cfg.Namespaces,
+		Severity:   r.minSeverity,
+		For:        r.cfg.For,
+		Cooldown:   r.cfg.Cooldown,
+	}
+}
+
+// healthSeverity maps a health state to an alert severity: unhealthy is critical,
+// degraded a warning, anything else isn't alerted on
+func healthSeverity(state string) Severity {
+	switch state {
+	case "unhealthy":
+		return SeverityCritical
+	case "degraded":
+		return SeverityWarning
+	}
+	return ""
+}
+
+// matches reports whether the rule alerts on this resource in its current health
+func (r *rule) matches(ev LifecycleEvent) bool {
+	switch healthSeverity(ev.HealthState) {
+	case SeverityCritical:
+	case SeverityWarning:
+		if r.minSeverity == SeverityCritical {
+			return false
+		}
+	default:
+		return false
+	}
+	if len(r.cfg.Kinds) > 0 && !slices.Contains(r.cfg.Kinds, ev.Kind) {
+		return false
+	}
+	if len(r.cfg.Namespaces) > 0 {
+		for _, pattern := range r.cfg.Namespaces {
+			if ok, _ := path.Match(pattern, ev.Namespace); ok {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// alert builds the channel alert for an unhealthy event, or the resolved alert for a
+// recovered or deleted one
+func (r *rule) alert(ev LifecycleEvent) Alert {
+	resource := ev.Kind + "/" + ev.Name
+	if ev.Namespace != "" {
+		resource = ev.Kind + "/" + ev.Namespace + "/" + ev.Name
+	}
+	a := Alert{
+		Resource:  resource,
+		Cluster:   ev.Cluster,
+		Timestamp: ev.Timestamp,
+		Labels:    ev.Labels,
+		DedupKey:  fmt.Sprintf("radar/%s/%s/%s", ev.Cluster, r.cfg.Name, resource),
+		Test:      ev.Test,
+	}
+	switch ev.Type {
+	case EventRecovered:
+		a.Resolved = true
+		a.Severity = SeverityInfo
+		a.Title = resource + " recovered"
+		if ev.Since != nil {
+			a.Message = fmt.Sprintf("Healthy again after %s.", ev.Timestamp.Sub(*ev.Since).Round(time.Second))
+		}
+		return a
+	case EventDeleted:
+		a.Resolved = true
+		a.Severity = SeverityInfo
+		a.Title = resource + " was deleted"
+		return a
+	}
+	a.Severity = healthSeverity(ev.HealthState)
+	a.Title = fmt.Sprintf("%s is %s", resource, ev.HealthState)
+	a.Message = ev.Message
+	if ev.Reason != "" {
+		a.Message = ev.Reason + ": " + ev.Message
+	}
+	if ev.Since != nil {
+		a.Message += fmt.Sprintf(" (since %s)", ev.Since.UTC().Format(time.RFC3339))
+	}
+	return a
+}
+
+// deliver sends an alert through one channel, retrying with exponential backoff on
+// errors that aren't a 4xx response (other than 429)
+func (r *rule) deliver(ctx context.Context, ch Channel, ev LifecycleEvent, alert Alert) TriggerDelivery {
+	result := TriggerDelivery{
+		Rule:      r.cfg.Name,
+		Channel:   ch.Name(),
+		EventID:   ev.ID,
+		EventType: ev.Type,
+		Resource:  ev.Kind + "/" + ev.Namespace + "/" + ev.Name,
+		Time:      time.Now(),
+	}
+	withRetry(ctx, r.cfg.Retry, r.backoff, &result, func() (int, bool, error) {
+		status, err := ch.Send(ctx, alert)
+		retryable := status == 0 || status >= 500 || status == http.StatusTooManyRequests
+		return status, retryable, err
+	})
+	return result
+}
+
+// Rules returns the public info for all configured health alert rules
+func (m *Manager) Rules() []RuleInfo {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	infos := make([]RuleInfo, 0, len(m.rules))
+	for _, r := range m.rules {
+		infos = append(infos, r.info())
+	}
+	return infos
+}
+
+// HasRules reports whether any health alert rules are configured
+func (m *Manager) HasRules() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.rules) > 0
+}
+
+// notify queues the alert for an event on every channel of the rules accepted by filter,
+// and returns the names of those rules. Resolutions (recovered, deleted) skip matching,
+// since the resource is healthy or gone by then.
+func (m *Manager) notify(ev LifecycleEvent, filter func(*rule) bool) []string {
+	m.mu.RLock()
+	if ev.Cluster == "" {
+		ev.Cluster = m.cluster
+	}
+	var targets []*rule
+	for _, r := range m.rules {
+		resolved := ev.Type == EventRecovered || ev.Type == EventDeleted
+		if (resolved || r.matches(ev)) && filter(r) {
+			targets = append(targets, r)
+		}
+	}
+	d := m.dispatcher
+	m.mu.RUnlock()
+
+	names := make([]string, 0, len(targets))
+	for _, r := range targets {
+		alert := r.alert(ev)
+		for _, ch := range r.channels {
+			d.enqueueAlert(r, ch, ev, alert)
+		}
+		names = append(names, r.cfg.Name)
+	}
+	return names
+}
@@ -0,0 +1,123 @@
+package notifications
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func mustRule(t *testing.T, cfg RuleConfig) *rule {
+	t.Helper()
+	if cfg.Name == "" {
+		cfg.Name = "test"
+	}
+	if len(cfg.Channels) == 0 {
+		cfg.Channels = []string{"slack"}
+	}
+	r, err := newRule(cfg)
+	if err != nil {
+		t.Fatalf("newRule: %v", err)
+	}
+	return r
+}
+
+func TestRuleMatches(t *testing.T) {
+	unhealthy := LifecycleEvent{Type: EventUnhealthy, Kind: "Deployment", Namespace: "prod-api", Name: "api", HealthState: "unhealthy"}
+	degraded := unhealthy
+	degraded.HealthState = "degraded"
+	healthy := unhealthy
+	healthy.HealthState = "healthy"
+	job := unhealthy
+	job.Kind = "Job"
+	staging := unhealthy
+	staging.Namespace = "staging"
+
+	tests := []struct {
+		name string
+		cfg  RuleConfig
+		ev   LifecycleEvent
+		want bool
+	}{
+		{"default matches unhealthy", RuleConfig{}, unhealthy, true},
+		{"default matches degraded", RuleConfig{}, degraded, true},
+		{"healthy never matches", RuleConfig{}, healthy, false},
+		{"critical skips degraded", RuleConfig{Severity: SeverityCritical}, degraded, false},
+		{"critical matches unhealthy", RuleConfig{Severity: SeverityCritical}, unhealthy, true},
+		{"kind filter", RuleConfig{Kinds: []string{"Deployment"}}, job, false},
+		{"kind filter match", RuleConfig{Kinds: []string{"Deployment", "Job"}}, job, true},
+		{"namespace glob", RuleConfig{Namespaces: []string{"prod-*"}}, unhealthy, true},
+		{"namespace glob miss", RuleConfig{Namespaces: []string{"prod-*"}}, staging, false},
+		{"second namespace pattern", RuleConfig{Namespaces: []string{"prod-*", "staging"}}, staging, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mustRule(t, tt.cfg).matches(tt.ev); got != tt.want {
+				t.Errorf("matches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuleAlert(t *testing.T) {
+	r := mustRule(t, RuleConfig{Name: "pager"})
+	since := time.Date(2026, 1, 2, 3, 0, 0, 0, time.UTC)
+	ev := LifecycleEvent{
+		Type:        EventUnhealthy,
+		Kind:        "Deployment",
+		Namespace:   "prod",
+		Name:        "api",
+		Cluster:     "prod-us",
+		Timestamp:   since.Add(5 * time.Minute),
+		HealthState: "unhealthy",
+		Reason:      "CrashLoopBackOff",
+		Message:     "back-off restarting failed container",
+		Since:       &since,
+	}
+
+	firing := r.alert(ev)
+	if firing.Title != "Deployment/prod/api is unhealthy" || firing.Severity != SeverityCritical || firing.Resolved {
+		t.Errorf("unexpected firing alert: %+v", firing)
+	}
+	if want := "CrashLoopBackOff: back-off restarting failed container (since 2026-01-02T03:00:00Z)"; firing.Message != want {
+		t.Errorf("message = %q, want %q", firing.Message, want)
+	}
+
+	ev.Type = EventRecovered
+	ev.Timestamp = since.Add(90 * time.Second)
+	recovered := r.alert(ev)
+	if !recovered.Resolved || recovered.Severity != SeverityInfo || recovered.Message != "Healthy again after 1m30s." {
+		t.Errorf("unexpected recovered alert: %+v", recovered)
+	}
+	if recovered.DedupKey != firing.DedupKey {
+		t.Errorf("recovery dedup key %q should match the alert's %q", recovered.DedupKey, firing.DedupKey)
+	}
+
+	// Cluster-scoped resources leave the namespace out of the resource path
+	node := r.alert(LifecycleEvent{Type: EventUnhealthy, Kind: "Node", Name: "n1", HealthState: "degraded"})
+	if node.Resource != "Node/n1" || node.Severity != SeverityWarning {
+		t.Errorf("unexpected node alert: %+v", node)
+	}
+}
+
+func TestNewRuleValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  RuleConfig
+		want string
+	}{
+		{"missing name", RuleConfig{Channels: []string{"slack"}}, "name is required"},
+		{"no channels", RuleConfig{Name: "r"}, "at least one channel"},
+		{"bad pattern", RuleConfig{Name: "r", Channels: []string{"slack"}, Namespaces: []string{"["}}, "invalid namespace pattern"},
+		{"info severity", RuleConfig{Name: "r", Channels: []string{"slack"}, Severity: SeverityInfo}, "severity must be"},
+		{"bad for", RuleConfig{Name: "r", Channels: []string{"slack"}, For: "soon"}, "invalid for duration"},
+		{"negative cooldown", RuleConfig{Name: "r", Channels: []string{"slack"}, Cooldown: "-1m"}, "invalid cooldown duration"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newRule(tt.cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("newRule error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,422 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
+	"sync"
+	"text/template"
+	"time"
+)
+
+const (
+	defaultMaxAttempts = 3
+	defaultBackoff     = 2 * time.Second
+	maxDeliveryLog     = 200
+	deliveryQueueSize  = 1000
+	deliveryWorkers    = 4
+)
+
+// trigger is a validated TriggerConfig ready for matching and delivery
+type trigger struct {
+	cfg         TriggerConfig
+	forDuration time.Duration
+	backoff     time.Duration
+	tmpl        *template.Template
+}
+
+var templateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// newTrigger validates a trigger config
+func newTrigger(cfg TriggerConfig) (*trigger, error) {
+	if cfg.Name == "" {
+		return nil, fmt.Errorf("trigger name is required")
+	}
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("trigger %q: url is required", cfg.Name)
+	}
+	if _, err := url.ParseRequestURI(cfg.URL); err != nil {
+		return nil, fmt.Errorf("trigger %q: invalid url: %w", cfg.Name, err)
+	}
+	if len(cfg.Events) == 0 {
+		return nil, fmt.Errorf("trigger %q: at least one event is required", cfg.Name)
+	}
+	for _, e := range cfg.Events {
+		switch e {
+		case EventCreated, EventUpdated, EventDeleted, EventUnhealthy, EventRecovered:
+		default:
+			return nil, fmt.Errorf("trigger %q: unknown event %q", cfg.Name, e)
+		}
+	}
+	for _, pattern := range cfg.Namespaces {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("trigger %q: invalid namespace pattern %q", cfg.Name, pattern)
+		}
+	}
+
+	t := &trigger{cfg: cfg}
+	if cfg.For != "" {
+		d, err := time.ParseDuration(cfg.For)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("trigger %q: invalid for duration %q", cfg.Name, cfg.For)
+		}
+		t.forDuration = d
+	}
+	backoff, err := parseRetry(cfg.Retry)
+	if err != nil {
+		return nil, fmt.Errorf("trigger %q: %w", cfg.Name, err)
+	}
+	t.backoff = backoff
+	if cfg.Template != "" {
+		tmpl, err := template.New(cfg.Name).Funcs(templateFuncs).Option("missingkey=error").Parse(cfg.Template)
+		if err != nil {
+			return nil, fmt.Errorf("trigger %q: invalid template: %w", cfg.Name, err)
+		}
+		t.tmpl = tmpl
+	}
+	return t, nil
+}
+
+func (t *trigger) info() TriggerInfo {
+	target := ""
+	if u, err := url.Parse(t.cfg.URL); err == nil {
+		target = u.Host
+	}
+	return TriggerInfo{
+		Name:       t.cfg.Name,
+		Target:     target,
+		Events:     t.cfg.Events,
+		Kinds:      t.cfg.Kinds,
+		Namespaces: t.cfg.Namespaces,
+		For:        t.cfg.For,
+		Signed:     t.cfg.Secret != "",
+	}
+}
+
+// matches reports whether the trigger wants this event
+func (t *trigger) matches(ev LifecycleEvent) bool {
+	if !slices.Contains(t.cfg.Events, ev.Type) {
+		return false
+	}
+	if len(t.cfg.Kinds) > 0 && !slices.Contains(t.cfg.Kinds, ev.Kind) {
+		return false
+	}
+	if len(t.cfg.Namespaces) > 0 {
+		for _, pattern := range t.cfg.Namespaces {
+			if ok, _ := path.Match(pattern, ev.Namespace); ok {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// render builds the request body for an event
+func (t *trigger) render(ev LifecycleEvent) ([]byte, error) {
+	if t.tmpl == nil {
+		return json.Marshal(ev)
+	}
+	var buf bytes.Buffer
+	if err := t.tmpl.Execute(&buf, ev); err != nil {
+		return nil, fmt.Errorf("template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// deliver posts the event, retrying with exponential backoff on network errors and 5xx/429
+func (t *trigger) deliver(ctx context.Context, ev LifecycleEvent) TriggerDelivery {
+	result := TriggerDelivery{
+		Trigger:   t.cfg.Name,
+		EventID:   ev.ID,
+		EventType: ev.Type,
+		Resource:  ev.Kind + "/" + ev.Namespace + "/" + ev.Name,
+		Time:      time.Now(),
+	}
+	body, err := t.render(ev)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	withRetry(ctx, t.cfg.Retry, t.backoff, &result, func() (int, bool, error) {
+		return t.post(ctx, ev, body)
+	})
+	return result
+}
+
+// withRetry calls send until it succeeds, retrying with exponential backoff while it
+// reports the failure as retryable, and records the outcome in result
+func withRetry(ctx context.Context, policy RetryPolicy, backoff time.Duration, result *TriggerDelivery, send func() (status int, retryable bool, err error)) {
+	maxAttempts := policy.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+	delay := backoff
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		result.Attempts = attempt
+		status, retryable, err := send()
+		result.StatusCode = status
+		if err == nil {
+			result.Success = true
+			result.Error = ""
+			return
+		}
+		result.Error = err.Error()
+		if !retryable || attempt == maxAttempts {
+			return
+		}
+		select {
+		case <-time.After(delay):
+			delay *= 2
+		case <-ctx.Done():
+			result.Error = ctx.Err().Error()
+			return
+		}
+	}
+}
+
+// parseRetry validates a retry policy and returns its initial backoff
+func parseRetry(policy RetryPolicy) (time.Duration, error) {
+	backoff := defaultBackoff
+	if policy.Backoff != "" {
+		d, err := time.ParseDuration(policy.Backoff)
+		if err != nil || d <= 0 {
+			return 0, fmt.Errorf("invalid retry backoff %q", policy.Backoff)
+		}
+		backoff = d
+	}
+	if policy.MaxAttempts < 0 || policy.MaxAttempts > 10 {
+		return 0, fmt.Errorf("retry.maxAttempts must be between 1 and 10")
+	}
+	return backoff, nil
+}
+
+func (t *trigger) post(ctx context.Context, ev LifecycleEvent, body []byte) (status int, retryable bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to create request: %w", err)
+	}
+	contentType := t.cfg.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("X-Radar-Event", string(ev.Type))
+	req.Header.Set("X-Radar-Delivery", ev.ID)
+	if t.cfg.Secret != "" {
+		// Sign timestamp + body so receivers can reject replays
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set("X-Radar-Timestamp", ts)
+		req.Header.Set("X-Radar-Signature", "sha256="+signPayload(t.cfg.Secret, ts, body))
+	}
+	for k, v := range t.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, true, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+		return resp.StatusCode, retry, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, false, nil
+}
+
+// signPayload returns hex(HMAC-SHA256(secret, timestamp + "." + body))
+func signPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// dispatcher queues lifecycle events for asynchronous delivery and keeps a delivery log
+type dispatcher struct {
+	queue chan queuedDelivery
+
+	mu         sync.Mutex
+	deliveries []TriggerDelivery // Most recent last
+	dropped    int64
+}
+
+// queuedDelivery is either a trigger event or a rule alert for one channel
+type queuedDelivery struct {
+	trigger *trigger
+	event   LifecycleEvent
+
+	rule    *rule
+	channel Channel
+	alert   Alert
+}
+
+func newDispatcher() *dispatcher {
+	d := &dispatcher{queue: make(chan queuedDelivery, deliveryQueueSize)}
+	for i := 0; i < deliveryWorkers; i++ {
+		go d.worker()
+	}
+	return d
+}
+
+func (d *dispatcher) worker() {
+	for q := range d.queue {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		var result TriggerDelivery
+		if q.rule != nil {
+			result = q.rule.deliver(ctx, q.channel, q.event, q.alert)
+		} else {
+			result = q.trigger.deliver(ctx, q.event)
+		}
+		cancel()
+		if !result.Success {
+			name := "trigger " + result.Trigger
+			if q.rule != nil {
+				name = "rule " + result.Rule + " (channel " + result.Channel + ")"
+			}
+			log.Printf("Warning: %s failed for %s %s after %d attempt(s): %s",
+				name, result.EventType, result.Resource, result.Attempts, result.Error)
+		}
+		d.record(result)
+	}
+}
+
+func (d *dispatcher) enqueue(t *trigger, ev LifecycleEvent) {
+	d.push(queuedDelivery{trigger: t, event: ev}, ev)
+}
+
+func (d *dispatcher) enqueueAlert(r *rule, ch Channel, ev LifecycleEvent, alert Alert) {
+	d.push(queuedDelivery{rule: r, channel: ch, event: ev, alert: alert}, ev)
+}
+
+func (d *dispatcher) push(q queuedDelivery, ev LifecycleEvent) {
+	select {
+	case d.queue <- q:
+	default:
+		d.mu.Lock()
+		d.dropped++
+		d.mu.Unlock()
+		log.Printf("Warning: notification delivery queue full, dropping %s event for %s/%s", ev.Type, ev.Kind, ev.Name)
+	}
+}
+
+func (d *dispatcher) record(result TriggerDelivery) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.deliveries = append(d.deliveries, result)
+	if over := len(d.deliveries) - maxDeliveryLog; over > 0 {
+		d.deliveries = d.deliveries[over:]
+	}
+}
+
+// Triggers returns the public info for all configured lifecycle triggers
+func (m *Manager) Triggers() []TriggerInfo {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	infos := make([]TriggerInfo, 0, len(m.triggers))
+	for _, t := range m.triggers {
+		infos = append(infos, t.info())
+	}
+	return infos
+}
+
+// HasTriggers reports whether any lifecycle triggers are configured
+func (m *Manager) HasTriggers() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.triggers) > 0
+}
+
+// Dispatch queues an event for every matching trigger
+func (m *Manager) Dispatch(ev LifecycleEvent) {
+	m.dispatch(ev, nil)
+}
+
+// dispatch queues an event for matching triggers accepted by filter (nil = all)
+// and returns the names of the triggers it was queued for
+func (m *Manager) dispatch(ev LifecycleEvent, filter func(*trigger) bool) []string {
+	m.mu.RLock()
+	if ev.Cluster == "" {
+		ev.Cluster = m.cluster
+	}
+	var targets []*trigger
+	for _, t := range m.triggers {
+		if t.matches(ev) && (filter == nil || filter(t)) {
+			targets = append(targets, t)
+		}
+	}
+	d := m.dispatcher
+	m.mu.RUnlock()
+
+	names := make([]string, 0, len(targets))
+	for _, t := range targets {
+		d.enqueue(t, ev)
+		names = append(names, t.cfg.Name)
+	}
+	return names
+}
+
+// Deliveries returns recent trigger deliveries, newest first
+func (m *Manager) Deliveries() []TriggerDelivery {
+	d := m.dispatcher
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	out := make([]TriggerDelivery, len(d.deliveries))
+	for i, del := range d.deliveries {
+		out[len(out)-1-i] = del
+	}
+	return out
+}
+
+// TestTrigger synchronously delivers a synthetic event through the named trigger
+func (m *Manager) TestTrigger(ctx context.Context, name string) (TriggerDelivery, error) {
+	m.mu.RLock()
+	var target *trigger
+	for _, t := range m.triggers {
+		if t.cfg.Name == name {
+			target = t
+			break
+		}
+	}
+	cluster := m.cluster
+	m.mu.RUnlock()
+	if target == nil {
+		return TriggerDelivery{}, fmt.Errorf("trigger not found: %s", name)
+	}
+
+	ev := LifecycleEvent{
+		ID:        fmt.Sprintf("test-%d", time.Now().UnixNano()),
+		Type:      target.cfg.Events[0],
+		Kind:      "Deployment",
+		Namespace: "default",
+		Name:      "radar-test",
+		Cluster:   cluster,
+		Timestamp: time.Now(),
+		Message:   "Synthetic event sent from Radar to verify the trigger is configured correctly.",
+		Test:      true,
+	}
+	result := target.deliver(ctx, ev)
+	m.dispatcher.record(result)
+	return result, nil
+}
@@ -0,0 +1,194 @@
+package notifications
+
+import "time"
+
+// ChannelType identifies the delivery mechanism of a notification channel
+type ChannelType string
+
+const (
+	ChannelSlack     ChannelType = "slack"
+	ChannelWebhook   ChannelType = "webhook"
+	ChannelEmail     ChannelType = "email"
+	ChannelPagerDuty ChannelType = "pagerduty"
+)
+
+// Severity is the urgency of an alert
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// ChannelConfig describes a single configured notification channel
+type ChannelConfig struct {
+	Name    string            `json:"name"`
+	Type    ChannelType       `json:"type"`
+	URL     string            `json:"url,omitempty"`     // Slack incoming webhook or generic webhook URL (PagerDuty: Events API override)
+	Headers map[string]string `json:"headers,omitempty"` // Extra headers for generic webhooks
+
+	// PagerDuty settings
+	RoutingKey string `json:"routingKey,omitempty"` // Events API v2 integration key
+
+	// Email settings
+	SMTPHost string   `json:"smtpHost,omitempty"`
+	SMTPPort int      `json:"smtpPort,omitempty"`
+	Username string   `json:"username,omitempty"`
+	Password string   `json:"password,omitempty"`
+	From     string   `json:"from,omitempty"`
+	To       []string `json:"to,omitempty"`
+}
+
+// Config is the top-level notifications configuration
+type Config struct {
+	Channels []ChannelConfig `json:"channels"`
+	Triggers []TriggerConfig `json:"triggers,omitempty"`
+	Rules    []RuleConfig    `json:"rules,omitempty"`
+}
+
+// LifecycleEventType is an observed change a trigger can fire on
+type LifecycleEventType string
+
+const (
+	EventCreated   LifecycleEventType = "created"
+	EventUpdated   LifecycleEventType = "updated"
+	EventDeleted   LifecycleEventType = "deleted"
+	EventUnhealthy LifecycleEventType = "unhealthy" // Became unhealthy/degraded (after For, if set)
+	EventRecovered LifecycleEventType = "recovered" // Healthy again after an unhealthy trigger fired
+)
+
+// TriggerConfig is an outbound webhook fired on object lifecycle events
+type TriggerConfig struct {
+	Name    string            `json:"name"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	// Secret signs the body with HMAC-SHA256 (X-Radar-Signature: sha256=<hex>)
+	Secret string `json:"secret,omitempty"`
+
+	// Matching (empty Kinds/Namespaces = all)
+	Events     []LifecycleEventType `json:"events"`
+	Kinds      []string             `json:"kinds,omitempty"`
+	Namespaces []string             `json:"namespaces,omitempty"` // Glob patterns
+	// For delays "unhealthy" until the resource has stayed unhealthy this long (Go duration)
+	For string `json:"for,omitempty"`
+
+	// Template renders the request body with text/template over the LifecycleEvent
+	// (default: the event as JSON). The json function encodes a value.
+	Template    string      `json:"template,omitempty"`
+	ContentType string      `json:"contentType,omitempty"` // Default application/json
+	Retry       RetryPolicy `json:"retry"`
+}
+
+// RuleConfig sends an alert through channels when a resource's health turns bad, and a
+// resolved alert once it recovers
+type RuleConfig struct {
+	Name     string   `json:"name"`
+	Channels []string `json:"channels"`
+
+	// Matching (empty Kinds/Namespaces = all)
+	Kinds      []string `json:"kinds,omitempty"`
+	Namespaces []string `json:"namespaces,omitempty"` // Glob patterns
+	// Severity is the minimum to alert on: warning (degraded or unhealthy, the default)
+	// or critical (unhealthy only)
+	Severity Severity `json:"severity,omitempty"`
+
+	// For waits until the resource has stayed unhealthy this long (Go duration)
+	For string `json:"for,omitempty"`
+	// Cooldown suppresses new alerts for a resource this long after its last one
+	// resolved, so flapping resources don't page on every transition
+	Cooldown string      `json:"cooldown,omitempty"`
+	Retry    RetryPolicy `json:"retry"`
+}
+
+// RetryPolicy controls redelivery of failed webhook calls
+type RetryPolicy struct {
+	MaxAttempts int    `json:"maxAttempts,omitempty"` // Default 3
+	Backoff     string `json:"backoff,omitempty"`     // Initial delay, doubled per attempt (default 2s)
+}
+
+// LifecycleEvent is the observed change delivered to triggers
+type LifecycleEvent struct {
+	ID          string             `json:"id"`
+	Type        LifecycleEventType `json:"type"`
+	Kind        string             `json:"kind"`
+	Namespace   string             `json:"namespace,omitempty"`
+	Name        string             `json:"name"`
+	Cluster     string             `json:"cluster,omitempty"`
+	Timestamp   time.Time          `json:"timestamp"`
+	HealthState string             `json:"healthState,omitempty"`
+	Reason      string             `json:"reason,omitempty"`
+	Message     string             `json:"message,omitempty"`
+	Since       *time.Time         `json:"since,omitempty"` // When the current health state began
+	Labels      map[string]string  `json:"labels,omitempty"`
+	Test        bool               `json:"test,omitempty"`
+}
+
+// TriggerInfo is the public view of a trigger (secrets stripped)
+type TriggerInfo struct {
+	Name       string               `json:"name"`
+	Target     string               `json:"target"`
+	Events     []LifecycleEventType `json:"events"`
+	Kinds      []string             `json:"kinds,omitempty"`
+	Namespaces []string             `json:"namespaces,omitempty"`
+	For        string               `json:"for,omitempty"`
+	Signed     bool                 `json:"signed"`
+}
+
+// RuleInfo is the public view of a health alert rule
+type RuleInfo struct {
+	Name       string   `json:"name"`
+	Channels   []string `json:"channels"`
+	Kinds      []string `json:"kinds,omitempty"`
+	Namespaces []string `json:"namespaces,omitempty"`
+	Severity   Severity `json:"severity"`
+	For        string   `json:"for,omitempty"`
+	Cooldown   string   `json:"cooldown,omitempty"`
+}
+
+// TriggerDelivery records one webhook delivery or rule alert (including retries)
+type TriggerDelivery struct {
+	Trigger    string             `json:"trigger,omitempty"`
+	Rule       string             `json:"rule,omitempty"`
+	Channel    string             `json:"channel,omitempty"` // Channel a rule alert went through
+	EventID    string             `json:"eventId"`
+	EventType  LifecycleEventType `json:"eventType"`
+	Resource   string             `json:"resource"` // kind/namespace/name
+	Attempts   int                `json:"attempts"`
+	Success    bool               `json:"success"`
+	StatusCode int                `json:"statusCode,omitempty"`
+	Error      string             `json:"error,omitempty"`
+	Time       time.Time          `json:"time"`
+}
+
+// Alert is the payload delivered through a notification channel
+type Alert struct {
+	Title     string            `json:"title"`
+	Message   string            `json:"message"`
+	Severity  Severity          `json:"severity"`
+	Cluster   string            `json:"cluster,omitempty"`
+	Timestamp time.Time         `json:"timestamp"`
+	Test      bool              `json:"test,omitempty"` // True for synthetic test-fire alerts
+	Labels    map[string]string `json:"labels,omitempty"`
+	Resource  string            `json:"resource,omitempty"` // kind/namespace/name for health alerts
+	// DedupKey is shared by a health alert and its resolution (PagerDuty dedup_key)
+	DedupKey string `json:"dedupKey,omitempty"`
+	Resolved bool   `json:"resolved,omitempty"` // The resource recovered
+}
+
+// ChannelInfo is the public view of a channel (secrets stripped)
+type ChannelInfo struct {
+	Name   string      `json:"name"`
+	Type   ChannelType `json:"type"`
+	Target string      `json:"target"` // Redacted destination (host or recipients)
+}
+
+// DeliveryResult reports the outcome of sending an alert through one channel
+type DeliveryResult struct {
+	Channel    string      `json:"channel"`
+	Type       ChannelType `json:"type"`
+	Success    bool        `json:"success"`
+	StatusCode int         `json:"statusCode,omitempty"`
+	LatencyMs  int64       `json:"latencyMs"`
+	Error      string      `json:"error,omitempty"`
+}
@@ -0,0 +1,172 @@
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// azureAPIVersion is the Blob service version requested; bearer tokens need 2017-11-09+
+const azureAPIVersion = "2021-08-06"
+
+// azureStore writes blobs to an Azure Storage container. Credentials, in order:
+// AZURE_STORAGE_KEY (shared key), AZURE_STORAGE_SAS_TOKEN, or AKS Workload Identity
+// (AZURE_CLIENT_ID, AZURE_TENANT_ID and AZURE_FEDERATED_TOKEN_FILE).
+type azureStore struct {
+	account   string
+	container string
+	prefix    string
+	sharedKey []byte
+	sas       url.Values
+	token     *cachedToken
+}
+
+func newAzureStore(loc *location) (*azureStore, error) {
+	container, prefix, _ := strings.Cut(loc.prefix, "/")
+	s := &azureStore{account: loc.bucket, container: container, prefix: prefix}
+	if key := os.Getenv("AZURE_STORAGE_KEY"); key != "" {
+		decoded, err := base64.StdEncoding.DecodeString(key)
+		if err != nil {
+			return nil, fmt.Errorf("AZURE_STORAGE_KEY isn't base64: %w", err)
+		}
+		s.sharedKey = decoded
+	} else if sas := os.Getenv("AZURE_STORAGE_SAS_TOKEN"); sas != "" {
+		values, err := url.ParseQuery(strings.TrimPrefix(sas, "?"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid AZURE_STORAGE_SAS_TOKEN: %w", err)
+		}
+		s.sas = values
+	} else {
+		s.token = &cachedToken{fetch: fetchAzureToken}
+	}
+	return s, nil
+}
+
+func (s *azureStore) Name() string {
+	return "azblob://" + s.account + "/" + objectKey(s.container, s.prefix)
+}
+
+func (s *azureStore) Put(ctx context.Context, key string, data []byte, contentType string) (*Object, error) {
+	key = objectKey(s.prefix, key)
+	u := &url.URL{Scheme: "https", Host: s.account + ".blob.core.windows.net", Path: "/" + s.container + "/" + key}
+	u.RawPath = uriEncode(u.Path, true)
+	blobURL := u.String()
+	if s.sas != nil {
+		u.RawQuery = s.sas.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("X-Ms-Blob-Type", "BlockBlob")
+	req.Header.Set("X-Ms-Date", time.Now().UTC().Format(http.TimeFormat))
+	req.Header.Set("X-Ms-Version", azureAPIVersion)
+	switch {
+	case s.sharedKey != nil:
+		req.Header.Set("Authorization", "SharedKey "+s.account+":"+s.signSharedKey(req, len(data)))
+	case s.token != nil:
+		token, err := s.token.get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := do(req, "write "+blobURL)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return &Object{Key: s.container + "/" + key, URL: blobURL, Size: len(data)}, nil
+}
+
+// signSharedKey signs a Blob service request with the account key
+func (s *azureStore) signSharedKey(req *http.Request, contentLength int) string {
+	length := ""
+	if contentLength > 0 {
+		length = strconv.Itoa(contentLength)
+	}
+	var msHeaders []string
+	for name, values := range req.Header {
+		if name = strings.ToLower(name); strings.HasPrefix(name, "x-ms-") {
+			msHeaders = append(msHeaders, name+":"+strings.TrimSpace(strings.Join(values, ",")))
+		}
+	}
+	sort.Strings(msHeaders)
+
+	resource := "/" + s.account + req.URL.EscapedPath()
+	query := req.URL.Query()
+	params := make([]string, 0, len(query))
+	for name := range query {
+		values := query[name]
+		sort.Strings(values)
+		params = append(params, strings.ToLower(name)+":"+strings.Join(values, ","))
+	}
+	sort.Strings(params)
+	for _, p := range params {
+		resource += "\n" + p
+	}
+
+	stringToSign := strings.Join([]string{
+		req.Method,
+		req.Header.Get("Content-Encoding"),
+		req.Header.Get("Content-Language"),
+		length,
+		req.Header.Get("Content-Md5"),
+		req.Header.Get("Content-Type"),
+		"", // Date: x-ms-date is used instead
+		req.Header.Get("If-Modified-Since"),
+		req.Header.Get("If-Match"),
+		req.Header.Get("If-None-Match"),
+		req.Header.Get("If-Unmodified-Since"),
+		req.Header.Get("Range"),
+		strings.Join(msHeaders, "\n"),
+		resource,
+	}, "\n")
+	mac := hmac.New(sha256.New, s.sharedKey)
+	mac.Write([]byte(stringToSign))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// fetchAzureToken exchanges the pod's federated service account token for a Storage
+// access token (AKS Workload Identity)
+func fetchAzureToken(ctx context.Context) (string, time.Duration, error) {
+	clientID, tenantID, tokenFile := os.Getenv("AZURE_CLIENT_ID"), os.Getenv("AZURE_TENANT_ID"), os.Getenv("AZURE_FEDERATED_TOKEN_FILE")
+	if clientID == "" || tenantID == "" || tokenFile == "" {
+		return "", 0, fmt.Errorf("no Azure credentials: set AZURE_STORAGE_KEY or AZURE_STORAGE_SAS_TOKEN, or use Workload Identity")
+	}
+	assertion, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to read Azure federated token: %w", err)
+	}
+	authority := os.Getenv("AZURE_AUTHORITY_HOST")
+	if authority == "" {
+		authority = "https://login.microsoftonline.com/"
+	}
+	form := url.Values{
+		"grant_type":            {"client_credentials"},
+		"client_id":             {clientID},
+		"scope":                 {"https://storage.azure.com/.default"},
+		"client_assertion_type": {"urn:ietf:params:oauth:client-assertion-type:jwt-bearer"},
+		"client_assertion":      {strings.TrimSpace(string(assertion))},
+	}
+	tokenURL := strings.TrimSuffix(authority, "/") + "/" + url.PathEscape(tenantID) + "/oauth2/v2.0/token"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return requestToken(req, "get an Azure access token")
+}
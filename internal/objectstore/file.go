@@ -0,0 +1,33 @@
+package objectstore
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// fileStore writes objects as files under a directory, for local runs and mounted volumes
+type fileStore struct {
+	dir string
+}
+
+func newFileStore(dir string) (*fileStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create artifact directory: %w", err)
+	}
+	return &fileStore{dir: dir}, nil
+}
+
+func (s *fileStore) Name() string { return "file://" + s.dir }
+
+func (s *fileStore) Put(_ context.Context, key string, data []byte, _ string) (*Object, error) {
+	path := filepath.Join(s.dir, filepath.FromSlash(objectKey("", key)))
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create artifact directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return &Object{Key: objectKey("", key), URL: "file://" + path, Size: len(data)}, nil
+}
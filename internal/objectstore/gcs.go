@@ -0,0 +1,179 @@
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	gcsScope         = "https://www.googleapis.com/auth/devstorage.read_write"
+	gcpMetadataToken = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+	googleTokenURL   = "https://oauth2.googleapis.com/token"
+)
+
+// gcsStore writes objects to a Google Cloud Storage bucket
+type gcsStore struct {
+	bucket string
+	prefix string
+	token  *cachedToken
+}
+
+func newGCSStore(loc *location) *gcsStore {
+	return &gcsStore{bucket: loc.bucket, prefix: loc.prefix, token: &cachedToken{fetch: fetchGoogleToken}}
+}
+
+func (s *gcsStore) Name() string { return "gs://" + s.bucket + "/" + s.prefix }
+
+func (s *gcsStore) Put(ctx context.Context, key string, data []byte, contentType string) (*Object, error) {
+	key = objectKey(s.prefix, key)
+	token, err := s.token.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	u := fmt.Sprintf("https://storage.googleapis.com/upload/storage/v1/b/%s/o?uploadType=media&name=%s",
+		url.PathEscape(s.bucket), url.QueryEscape(key))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", contentType)
+	resp, err := do(req, "write gs://"+s.bucket+"/"+key)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return &Object{Key: key, URL: "gs://" + s.bucket + "/" + key, Size: len(data)}, nil
+}
+
+// googleCredentials is the subset of an application default credentials file used here:
+// a service account key, or a user's credentials from gcloud auth application-default login
+type googleCredentials struct {
+	Type         string `json:"type"` // service_account or authorized_user
+	ClientEmail  string `json:"client_email"`
+	PrivateKey   string `json:"private_key"`
+	TokenURI     string `json:"token_uri"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// fetchGoogleToken gets an access token from the GOOGLE_APPLICATION_CREDENTIALS file, or
+// from the metadata server (GKE Workload Identity, GCE service accounts) without one
+func fetchGoogleToken(ctx context.Context) (string, time.Duration, error) {
+	file := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
+	if file == "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, gcpMetadataToken, nil)
+		if err != nil {
+			return "", 0, err
+		}
+		req.Header.Set("Metadata-Flavor", "Google")
+		return requestToken(req, "get a token from the GCP metadata server (set GOOGLE_APPLICATION_CREDENTIALS outside GCP)")
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to read GOOGLE_APPLICATION_CREDENTIALS: %w", err)
+	}
+	var creds googleCredentials
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return "", 0, fmt.Errorf("failed to parse GOOGLE_APPLICATION_CREDENTIALS: %w", err)
+	}
+	var form url.Values
+	tokenURL := googleTokenURL
+	switch creds.Type {
+	case "service_account":
+		if creds.TokenURI != "" {
+			tokenURL = creds.TokenURI
+		}
+		assertion, err := serviceAccountAssertion(&creds, tokenURL, time.Now())
+		if err != nil {
+			return "", 0, err
+		}
+		form = url.Values{"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"}, "assertion": {assertion}}
+	case "authorized_user":
+		form = url.Values{
+			"grant_type":    {"refresh_token"},
+			"client_id":     {creds.ClientID},
+			"client_secret": {creds.ClientSecret},
+			"refresh_token": {creds.RefreshToken},
+		}
+	default:
+		return "", 0, fmt.Errorf("unsupported GOOGLE_APPLICATION_CREDENTIALS type %q (expected service_account or authorized_user)", creds.Type)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return requestToken(req, "get a Google access token")
+}
+
+// serviceAccountAssertion signs the JWT a service account key exchanges for an access token
+func serviceAccountAssertion(creds *googleCredentials, audience string, now time.Time) (string, error) {
+	block, _ := pem.Decode([]byte(creds.PrivateKey))
+	if block == nil {
+		return "", fmt.Errorf("service account key %s has no PEM private key", creds.ClientEmail)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		if parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
+			return "", fmt.Errorf("failed to parse service account key: %w", err)
+		}
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return "", fmt.Errorf("service account key %s isn't an RSA key", creds.ClientEmail)
+	}
+
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]any{
+		"iss":   creds.ClientEmail,
+		"scope": gcsScope,
+		"aud":   audience,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+	sum := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, sum[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign service account assertion: %w", err)
+	}
+	return unsigned + "." + enc.EncodeToString(sig), nil
+}
+
+// requestToken sends an OAuth token request and returns the access token and its lifetime
+func requestToken(req *http.Request, what string) (string, time.Duration, error) {
+	resp, err := do(req, what)
+	if err != nil {
+		return "", 0, err
+	}
+	defer resp.Body.Close()
+	var out struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", 0, fmt.Errorf("failed to %s: %w", what, err)
+	}
+	if out.AccessToken == "" {
+		return "", 0, fmt.Errorf("failed to %s: no access token in the response", what)
+	}
+	return out.AccessToken, time.Duration(out.ExpiresIn) * time.Second, nil
+}
@@ -0,0 +1,219 @@
+// Package objectstore writes artifacts (timeline exports, replay bundles, terminal session
+// recordings) to object storage so an in-cluster deployment doesn't keep them on the
+// container filesystem. Stores are URLs:
+//
+//	s3://bucket/prefix?region=eu-west-1      Amazon S3 (?endpoint= for S3-compatible stores)
+//	gs://bucket/prefix                       Google Cloud Storage
+//	azblob://account/container/prefix        Azure Blob Storage
+//	file:///var/lib/radar/artifacts          A local directory
+//
+// Credentials come from each provider's usual environment variables or the pod's workload
+// identity (IRSA or EKS Pod Identity, GKE Workload Identity, AKS Workload Identity), so
+// they can be mounted from a Secret or granted to the service account.
+package objectstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Store writes objects under a bucket and prefix
+type Store interface {
+	Name() string // The store URL, without credentials
+	// Put writes an object at key, relative to the store's prefix, replacing any existing one
+	Put(ctx context.Context, key string, data []byte, contentType string) (*Object, error)
+}
+
+// Object describes a written object
+type Object struct {
+	Key  string `json:"key"` // Including the store's prefix
+	URL  string `json:"url"` // s3://, gs://, https:// (Azure) or file:// URL
+	Size int    `json:"size"`
+}
+
+// httpClient uploads objects and fetches credentials; the timeout leaves room for large
+// replay bundles on slow links
+var httpClient = &http.Client{Timeout: 5 * time.Minute}
+
+var (
+	mu       sync.RWMutex
+	artifact Store
+)
+
+// Configure opens the artifact store used for exports, replacing any previous one. An
+// empty spec disables it.
+func Configure(spec string) error {
+	var store Store
+	if spec != "" {
+		var err error
+		if store, err = Open(spec); err != nil {
+			return err
+		}
+		log.Printf("Writing exported artifacts to %s", store.Name())
+	}
+	mu.Lock()
+	artifact = store
+	mu.Unlock()
+	return nil
+}
+
+// Artifacts returns the configured artifact store, or nil when there's none
+func Artifacts() Store {
+	mu.RLock()
+	defer mu.RUnlock()
+	return artifact
+}
+
+// IsURL reports whether spec names a store scheme, for callers that also accept other
+// kinds of targets
+func IsURL(spec string) bool {
+	scheme, _, ok := strings.Cut(spec, "://")
+	if !ok {
+		return false
+	}
+	switch scheme {
+	case "s3", "gs", "azblob", "file":
+		return true
+	}
+	return false
+}
+
+// Validate checks a store URL without opening it or loading credentials
+func Validate(spec string) error {
+	_, err := parse(spec)
+	return err
+}
+
+// Open builds a store from its URL. Credentials are loaded on the first write, so a store
+// can be opened before they're mounted.
+func Open(spec string) (Store, error) {
+	loc, err := parse(spec)
+	if err != nil {
+		return nil, fmt.Errorf("object store: %w", err)
+	}
+	switch loc.scheme {
+	case "s3":
+		return newS3Store(loc)
+	case "gs":
+		return newGCSStore(loc), nil
+	case "azblob":
+		return newAzureStore(loc)
+	default:
+		return newFileStore(loc.prefix)
+	}
+}
+
+// location is a parsed store URL
+type location struct {
+	scheme string
+	bucket string // S3 or GCS bucket, Azure storage account
+	prefix string // Object key prefix (Azure: container, then prefix); directory for file
+	query  url.Values
+}
+
+func parse(spec string) (*location, error) {
+	if !IsURL(spec) {
+		return nil, fmt.Errorf("expected an s3://, gs://, azblob:// or file:// URL, got %q", spec)
+	}
+	u, err := url.Parse(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %q: %w", spec, err)
+	}
+	loc := &location{scheme: u.Scheme, bucket: u.Host, prefix: strings.Trim(u.Path, "/"), query: u.Query()}
+	switch {
+	case u.User != nil:
+		return nil, fmt.Errorf("%q: pass credentials through the environment, not the URL", u.Redacted())
+	case loc.scheme == "file":
+		if u.Host != "" || u.Path == "" {
+			return nil, fmt.Errorf("expected file:///<dir>, got %q", spec)
+		}
+		loc.prefix = u.Path
+	case loc.bucket == "":
+		return nil, fmt.Errorf("%q has no bucket", spec)
+	case loc.scheme == "azblob" && loc.prefix == "":
+		return nil, fmt.Errorf("expected azblob://<account>/<container>[/prefix], got %q", spec)
+	}
+	for param := range loc.query {
+		if loc.scheme != "s3" || (param != "region" && param != "endpoint") {
+			return nil, fmt.Errorf("%q: unknown parameter %q", spec, param)
+		}
+	}
+	if ep := loc.query.Get("endpoint"); ep != "" {
+		if e, err := url.Parse(ep); err != nil || (e.Scheme != "http" && e.Scheme != "https") || e.Host == "" {
+			return nil, fmt.Errorf("invalid S3 endpoint %q", ep)
+		}
+	}
+	return loc, nil
+}
+
+// objectKey joins the store's prefix and a key
+func objectKey(prefix, key string) string {
+	return strings.TrimPrefix(path.Join(prefix, key), "/")
+}
+
+// uriEncode percent-encodes everything but unreserved characters (RFC 3986), optionally
+// keeping slashes, as S3 signatures and object URLs expect
+func uriEncode(s string, keepSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' || (keepSlash && c == '/') {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// do sends a request and returns an error with the provider's message for non-2xx responses
+func do(req *http.Request, what string) (*http.Response, error) {
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to %s: %w", what, err)
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		msg := strings.TrimSpace(string(body))
+		if msg == "" {
+			return nil, fmt.Errorf("failed to %s: %s", what, resp.Status)
+		}
+		return nil, fmt.Errorf("failed to %s: %s: %s", what, resp.Status, msg)
+	}
+	return resp, nil
+}
+
+// cachedToken caches a bearer token until shortly before it expires
+type cachedToken struct {
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+	fetch   func(ctx context.Context) (token string, ttl time.Duration, err error)
+}
+
+// tokenRefreshMargin renews tokens this long before they expire
+const tokenRefreshMargin = 5 * time.Minute
+
+func (c *cachedToken) get(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token != "" && time.Until(c.expires) > tokenRefreshMargin {
+		return c.token, nil
+	}
+	token, ttl, err := c.fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+	c.token, c.expires = token, time.Now().Add(ttl)
+	return token, nil
+}
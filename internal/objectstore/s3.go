@@ -0,0 +1,262 @@
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// s3Store writes objects to an S3 bucket, or a bucket of an S3-compatible store (MinIO,
+// Ceph, R2) with ?endpoint=
+type s3Store struct {
+	bucket   string
+	prefix   string
+	region   string
+	endpoint *url.URL // nil for AWS
+	creds    *awsCredentials
+}
+
+func newS3Store(loc *location) (*s3Store, error) {
+	s := &s3Store{bucket: loc.bucket, prefix: loc.prefix, region: loc.query.Get("region"), creds: &awsCredentials{}}
+	if s.region == "" {
+		s.region = os.Getenv("AWS_REGION")
+	}
+	if s.region == "" {
+		s.region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if s.region == "" {
+		s.region = "us-east-1"
+	}
+	if ep := loc.query.Get("endpoint"); ep != "" {
+		s.endpoint, _ = url.Parse(ep) // Checked by parse
+	}
+	return s, nil
+}
+
+func (s *s3Store) Name() string {
+	name := "s3://" + s.bucket + "/" + s.prefix
+	if s.endpoint != nil {
+		name += " (" + s.endpoint.Host + ")"
+	}
+	return name
+}
+
+func (s *s3Store) Put(ctx context.Context, key string, data []byte, contentType string) (*Object, error) {
+	key = objectKey(s.prefix, key)
+	// AWS buckets are addressed virtual-hosted style; other endpoints path style, which
+	// every S3-compatible store supports
+	u := &url.URL{Scheme: "https", Host: fmt.Sprintf("%s.s3.%s.amazonaws.com", s.bucket, s.region), Path: "/" + key}
+	if s.endpoint != nil {
+		u = &url.URL{Scheme: s.endpoint.Scheme, Host: s.endpoint.Host, Path: "/" + s.bucket + "/" + key}
+	}
+	u.RawPath = uriEncode(u.Path, true)
+
+	creds, err := s.creds.get(ctx, s.region)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	sum := sha256.Sum256(data)
+	signV4(req, hex.EncodeToString(sum[:]), creds, s.region, "s3", time.Now())
+
+	resp, err := do(req, "write s3://"+s.bucket+"/"+key)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return &Object{Key: key, URL: "s3://" + s.bucket + "/" + key, Size: len(data)}, nil
+}
+
+// awsKeys is a set of AWS credentials
+type awsKeys struct {
+	accessKey    string
+	secretKey    string
+	sessionToken string
+	expires      time.Time // Zero for static keys
+}
+
+// awsCredentials resolves credentials like the AWS SDKs do for pods: static keys from
+// AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY, EKS Pod Identity
+// (AWS_CONTAINER_CREDENTIALS_FULL_URI), or IRSA (AWS_ROLE_ARN and
+// AWS_WEB_IDENTITY_TOKEN_FILE). Temporary credentials are renewed before they expire.
+type awsCredentials struct {
+	mu   sync.Mutex
+	keys *awsKeys
+}
+
+func (c *awsCredentials) get(ctx context.Context, region string) (*awsKeys, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.keys != nil && (c.keys.expires.IsZero() || time.Until(c.keys.expires) > tokenRefreshMargin) {
+		return c.keys, nil
+	}
+	var keys *awsKeys
+	var err error
+	switch {
+	case os.Getenv("AWS_ACCESS_KEY_ID") != "" && os.Getenv("AWS_SECRET_ACCESS_KEY") != "":
+		keys = &awsKeys{
+			accessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+			secretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			sessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+		}
+	case os.Getenv("AWS_CONTAINER_CREDENTIALS_FULL_URI") != "":
+		keys, err = containerCredentials(ctx)
+	case os.Getenv("AWS_ROLE_ARN") != "" && os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE") != "":
+		keys, err = webIdentityCredentials(ctx, region)
+	default:
+		err = fmt.Errorf("no AWS credentials: set AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY, or use IRSA or EKS Pod Identity")
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.keys = keys
+	return keys, nil
+}
+
+// containerCredentials fetches credentials from the EKS Pod Identity agent
+func containerCredentials(ctx context.Context) (*awsKeys, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, os.Getenv("AWS_CONTAINER_CREDENTIALS_FULL_URI"), nil)
+	if err != nil {
+		return nil, err
+	}
+	if file := os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE"); file != "" {
+		token, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read AWS container authorization token: %w", err)
+		}
+		req.Header.Set("Authorization", strings.TrimSpace(string(token)))
+	}
+	resp, err := do(req, "get AWS container credentials")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var out struct {
+		AccessKeyID     string    `json:"AccessKeyId"`
+		SecretAccessKey string    `json:"SecretAccessKey"`
+		Token           string    `json:"Token"`
+		Expiration      time.Time `json:"Expiration"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("failed to decode AWS container credentials: %w", err)
+	}
+	return &awsKeys{accessKey: out.AccessKeyID, secretKey: out.SecretAccessKey, sessionToken: out.Token, expires: out.Expiration}, nil
+}
+
+// webIdentityCredentials exchanges the pod's projected service account token for a role's
+// credentials (IRSA)
+func webIdentityCredentials(ctx context.Context, region string) (*awsKeys, error) {
+	token, err := os.ReadFile(os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read AWS web identity token: %w", err)
+	}
+	session := os.Getenv("AWS_ROLE_SESSION_NAME")
+	if session == "" {
+		session = "radar"
+	}
+	form := url.Values{
+		"Action":           {"AssumeRoleWithWebIdentity"},
+		"Version":          {"2011-06-15"},
+		"RoleArn":          {os.Getenv("AWS_ROLE_ARN")},
+		"RoleSessionName":  {session},
+		"WebIdentityToken": {strings.TrimSpace(string(token))},
+	}
+	endpoint := fmt.Sprintf("https://sts.%s.amazonaws.com/", region)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := do(req, "assume role "+os.Getenv("AWS_ROLE_ARN"))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var out struct {
+		Credentials struct {
+			AccessKeyID     string    `xml:"AccessKeyId"`
+			SecretAccessKey string    `xml:"SecretAccessKey"`
+			SessionToken    string    `xml:"SessionToken"`
+			Expiration      time.Time `xml:"Expiration"`
+		} `xml:"AssumeRoleWithWebIdentityResult>Credentials"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("failed to decode STS response: %w", err)
+	}
+	c := out.Credentials
+	return &awsKeys{accessKey: c.AccessKeyID, secretKey: c.SecretAccessKey, sessionToken: c.SessionToken, expires: c.Expiration}, nil
+}
+
+// signV4 signs a request with AWS Signature Version 4, covering the host and every header
+// already set on the request
+func signV4(req *http.Request, payloadHash string, keys *awsKeys, region, service string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if keys.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", keys.sessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	query := req.URL.Query()
+	params := make([]string, 0, len(query))
+	for name, values := range query {
+		for _, v := range values {
+			params = append(params, uriEncode(name, false)+"="+uriEncode(v, false))
+		}
+	}
+	sort.Strings(params)
+
+	canonicalRequest := strings.Join([]string{
+		req.Method, req.URL.EscapedPath(), strings.Join(params, "&"),
+		canonicalHeaders.String(), signedHeaders, payloadHash,
+	}, "\n")
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+
+	key := []byte("AWS4" + keys.secretKey)
+	for _, part := range []string{date, region, service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		keys.accessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
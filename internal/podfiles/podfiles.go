@@ -0,0 +1,199 @@
+// Package podfiles lists, downloads and uploads container files over exec, the way
+// kubectl cp does: the container's own tools (sh, find, stat, cat, tar) do the work, and
+// directories and uploads travel as tar streams.
+package podfiles
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrTooLarge is returned when a transfer exceeds its size limit
+var ErrTooLarge = errors.New("transfer exceeds the size limit")
+
+// EntryType is the kind of a directory entry
+type EntryType string
+
+const (
+	TypeFile    EntryType = "file"
+	TypeDir     EntryType = "dir"
+	TypeSymlink EntryType = "symlink"
+	TypeOther   EntryType = "other" // Devices, sockets and pipes
+)
+
+// Entry is one file in a directory listing
+type Entry struct {
+	Name    string    `json:"name"`
+	Type    EntryType `json:"type"`
+	Size    int64     `json:"size"`
+	Mode    string    `json:"mode"` // e.g. "drwxr-xr-x"
+	ModTime time.Time `json:"modTime"`
+}
+
+// Unix file type bits of st_mode
+const (
+	modeTypeMask = 0o170000
+	modeDir      = 0o040000
+	modeFile     = 0o100000
+	modeSymlink  = 0o120000
+)
+
+// ListCommand lists a directory as "<mode in hex> <size> <mtime> <name>" lines. find and
+// stat -c are in both GNU coreutils and busybox.
+func ListCommand(dir string) []string {
+	return []string{"sh", "-c", `cd -- "$1" && find . -mindepth 1 -maxdepth 1 -exec stat -c '%f %s %Y %n' {} +`, "sh", dir}
+}
+
+// ParseListing parses ListCommand output, directories first and then by name
+func ParseListing(out string) ([]Entry, error) {
+	entries := []Entry{}
+	for _, line := range strings.Split(out, "\n") {
+		if line == "" {
+			continue
+		}
+		fields := strings.SplitN(line, " ", 4)
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("unexpected listing line %q", line)
+		}
+		mode, err1 := strconv.ParseUint(fields[0], 16, 32)
+		size, err2 := strconv.ParseInt(fields[1], 10, 64)
+		mtime, err3 := strconv.ParseInt(fields[2], 10, 64)
+		if err := errors.Join(err1, err2, err3); err != nil {
+			return nil, fmt.Errorf("unexpected listing line %q: %w", line, err)
+		}
+		entries = append(entries, Entry{
+			Name:    strings.TrimPrefix(fields[3], "./"),
+			Type:    entryType(uint32(mode)),
+			Size:    size,
+			Mode:    modeString(uint32(mode)),
+			ModTime: time.Unix(mtime, 0).UTC(),
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if (a.Type == TypeDir) != (b.Type == TypeDir) {
+			return a.Type == TypeDir
+		}
+		return a.Name < b.Name
+	})
+	return entries, nil
+}
+
+func entryType(mode uint32) EntryType {
+	switch mode & modeTypeMask {
+	case modeDir:
+		return TypeDir
+	case modeFile:
+		return TypeFile
+	case modeSymlink:
+		return TypeSymlink
+	}
+	return TypeOther
+}
+
+// modeString formats st_mode like ls -l
+func modeString(mode uint32) string {
+	b := []byte("?rwxrwxrwx")
+	switch entryType(mode) {
+	case TypeDir:
+		b[0] = 'd'
+	case TypeFile:
+		b[0] = '-'
+	case TypeSymlink:
+		b[0] = 'l'
+	}
+	for i := range 9 {
+		if mode&(1<<(8-i)) == 0 {
+			b[i+1] = '-'
+		}
+	}
+	return string(b)
+}
+
+// StatCommand prints "<mode in hex> <size>" for a path, followed by its disk usage in KiB
+// when it's a directory (stat follows symlinks, so a link to a directory is one)
+func StatCommand(p string) []string {
+	return []string{"sh", "-c", `stat -L -c '%f %s' -- "$1" && if [ -d "$1" ]; then du -sk -- "$1"; fi`, "sh", p}
+}
+
+// Target is what a download path refers to
+type Target struct {
+	Dir  bool
+	Size int64 // File size, or the directory's disk usage
+}
+
+// ParseStat parses StatCommand output
+func ParseStat(out string) (Target, error) {
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	fields := strings.Fields(lines[0])
+	if len(fields) != 2 {
+		return Target{}, fmt.Errorf("unexpected stat output %q", out)
+	}
+	mode, err1 := strconv.ParseUint(fields[0], 16, 32)
+	size, err2 := strconv.ParseInt(fields[1], 10, 64)
+	if err := errors.Join(err1, err2); err != nil {
+		return Target{}, fmt.Errorf("unexpected stat output %q: %w", out, err)
+	}
+	t := Target{Dir: entryType(uint32(mode)) == TypeDir, Size: size}
+	if t.Dir && len(lines) > 1 {
+		if kb, err := strconv.ParseInt(strings.Fields(lines[1])[0], 10, 64); err == nil {
+			t.Size = kb << 10
+		}
+	}
+	return t, nil
+}
+
+// ReadCommand writes a file to stdout
+func ReadCommand(p string) []string {
+	return []string{"cat", "--", p}
+}
+
+// TarCommand writes a directory to stdout as a tar archive whose entries are relative to
+// its parent, so it extracts into a directory of the same name
+func TarCommand(dir string) []string {
+	dir = path.Clean(dir)
+	if dir == "/" {
+		return []string{"tar", "cf", "-", "-C", "/", "."}
+	}
+	return []string{"tar", "cf", "-", "-C", path.Dir(dir), path.Base(dir)}
+}
+
+// ExtractCommand extracts a tar archive from stdin into a directory
+func ExtractCommand(dir string) []string {
+	return []string{"tar", "xf", "-", "-C", dir}
+}
+
+// ArchiveName is the file name a download is saved as
+func ArchiveName(p string, dir bool) string {
+	name := path.Base(path.Clean(p))
+	if name == "/" || name == "." {
+		name = "root"
+	}
+	if dir {
+		return name + ".tar.gz"
+	}
+	return name
+}
+
+// LimitWriter writes to W until N bytes have been written, then fails with ErrTooLarge.
+// N <= 0 means no limit.
+type LimitWriter struct {
+	W       io.Writer
+	N       int64
+	Written int64
+}
+
+func (l *LimitWriter) Write(p []byte) (int, error) {
+	if l.N > 0 && l.Written+int64(len(p)) > l.N {
+		return 0, ErrTooLarge
+	}
+	n, err := l.W.Write(p)
+	l.Written += int64(n)
+	return n, err
+}
@@ -0,0 +1,151 @@
+package podfiles
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseListing(t *testing.T) {
+	out := "81a4 120 1700000000 ./app.log\n" +
+		"41ed 4096 1700000100 ./conf.d\n" +
+		"a1ff 11 1700000200 ./current\n" +
+		"81a4 0 1700000300 ./with space.txt\n"
+	got, err := ParseListing(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Entry{
+		{Name: "conf.d", Type: TypeDir, Size: 4096, Mode: "drwxr-xr-x", ModTime: time.Unix(1700000100, 0).UTC()},
+		{Name: "app.log", Type: TypeFile, Size: 120, Mode: "-rw-r--r--", ModTime: time.Unix(1700000000, 0).UTC()},
+		{Name: "current", Type: TypeSymlink, Size: 11, Mode: "lrwxrwxrwx", ModTime: time.Unix(1700000200, 0).UTC()},
+		{Name: "with space.txt", Type: TypeFile, Size: 0, Mode: "-rw-r--r--", ModTime: time.Unix(1700000300, 0).UTC()},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseListing =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if _, err := ParseListing("garbage\n"); err == nil {
+		t.Error("expected an error for malformed output")
+	}
+}
+
+func TestParseStat(t *testing.T) {
+	if got, _ := ParseStat("81a4 2048\n"); got != (Target{Size: 2048}) {
+		t.Errorf("file: %+v", got)
+	}
+	if got, _ := ParseStat("41ed 4096\n12\t/var/log\n"); got != (Target{Dir: true, Size: 12 << 10}) {
+		t.Errorf("dir: %+v", got)
+	}
+}
+
+func TestTarCommand(t *testing.T) {
+	if got := TarCommand("/var/log/"); !reflect.DeepEqual(got, []string{"tar", "cf", "-", "-C", "/var", "log"}) {
+		t.Errorf("TarCommand = %v", got)
+	}
+	if got := ArchiveName("/var/log/", true); got != "log.tar.gz" {
+		t.Errorf("ArchiveName = %q", got)
+	}
+}
+
+func readTar(t *testing.T, r io.Reader) map[string]string {
+	t.Helper()
+	files := map[string]string{}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(tr)
+		files[hdr.Name] = string(data)
+	}
+}
+
+func writeTar(t *testing.T, files map[string]string, compress bool) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	var w io.Writer = &buf
+	var gz *gzip.Writer
+	if compress {
+		gz = gzip.NewWriter(&buf)
+		w = gz
+	}
+	tw := tar.NewWriter(w)
+	for name, data := range files {
+		tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), Typeflag: tar.TypeReg})
+		tw.Write([]byte(data))
+	}
+	tw.Close()
+	if gz != nil {
+		gz.Close()
+	}
+	return &buf
+}
+
+func TestWriteFileTar(t *testing.T) {
+	var buf bytes.Buffer
+	stats, err := WriteFileTar(&buf, "config.yaml", 5, strings.NewReader("hello"), 0)
+	if err != nil || stats != (UploadStats{Files: 1, Bytes: 5}) {
+		t.Fatalf("WriteFileTar = %+v, %v", stats, err)
+	}
+	if got := readTar(t, &buf); !reflect.DeepEqual(got, map[string]string{"config.yaml": "hello"}) {
+		t.Errorf("archive = %v", got)
+	}
+
+	if _, err := WriteFileTar(io.Discard, "big", 10, strings.NewReader("0123456789"), 5); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("over limit: %v", err)
+	}
+	if _, err := WriteFileTar(io.Discard, "../x", 1, strings.NewReader("x"), 0); err == nil {
+		t.Error("expected an error for a name outside the directory")
+	}
+	if _, err := WriteFileTar(io.Discard, "short", 10, strings.NewReader("abc"), 0); err == nil {
+		t.Error("expected an error for a truncated upload")
+	}
+}
+
+func TestCopyTar(t *testing.T) {
+	files := map[string]string{"a.txt": "aaa", "dir/b.txt": "bb"}
+	for _, compress := range []bool{false, true} {
+		var out bytes.Buffer
+		stats, err := CopyTar(&out, writeTar(t, files, compress), 0)
+		if err != nil || stats != (UploadStats{Files: 2, Bytes: 5}) {
+			t.Fatalf("compress=%v: CopyTar = %+v, %v", compress, stats, err)
+		}
+		if got := readTar(t, &out); !reflect.DeepEqual(got, files) {
+			t.Errorf("compress=%v: archive = %v", compress, got)
+		}
+	}
+
+	if _, err := CopyTar(io.Discard, writeTar(t, files, false), 4); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("over limit: %v", err)
+	}
+	for _, name := range []string{"/etc/passwd", "../escape", "dir/../../escape"} {
+		if _, err := CopyTar(io.Discard, writeTar(t, map[string]string{name: "x"}, false), 0); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestLimitWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := &LimitWriter{W: &buf, N: 4}
+	if _, err := w.Write([]byte("abc")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("de")); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("err = %v, want ErrTooLarge", err)
+	}
+	if buf.String() != "abc" || w.Written != 3 {
+		t.Errorf("wrote %q (%d)", buf.String(), w.Written)
+	}
+}
@@ -0,0 +1,104 @@
+package podfiles
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+// UploadStats summarizes what an upload wrote
+type UploadStats struct {
+	Files int   `json:"files"`
+	Bytes int64 `json:"bytes"`
+}
+
+// WriteFileTar writes one file of the given size as a tar archive, for ExtractCommand.
+// The reader must supply exactly size bytes.
+func WriteFileTar(w io.Writer, name string, size int64, r io.Reader, maxBytes int64) (UploadStats, error) {
+	if err := validName(name); err != nil || strings.Contains(name, "/") {
+		return UploadStats{}, fmt.Errorf("invalid file name %q", name)
+	}
+	if maxBytes > 0 && size > maxBytes {
+		return UploadStats{}, ErrTooLarge
+	}
+	tw := tar.NewWriter(w)
+	hdr := &tar.Header{Name: name, Mode: 0o644, Size: size, ModTime: time.Now(), Typeflag: tar.TypeReg}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return UploadStats{}, err
+	}
+	n, err := io.Copy(tw, io.LimitReader(r, size))
+	if err != nil {
+		return UploadStats{}, err
+	}
+	if n != size {
+		return UploadStats{}, fmt.Errorf("upload ended after %d of %d bytes", n, size)
+	}
+	return UploadStats{Files: 1, Bytes: n}, tw.Close()
+}
+
+// CopyTar copies a tar archive, gzip-compressed or not, to w uncompressed for
+// ExtractCommand. Entries that would land outside the target directory are rejected, and
+// so are archives with more than maxBytes of file content.
+func CopyTar(w io.Writer, r io.Reader, maxBytes int64) (UploadStats, error) {
+	var stats UploadStats
+	br := bufio.NewReader(r)
+	var src io.Reader = br
+	if magic, _ := br.Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return stats, err
+		}
+		defer gz.Close()
+		src = gz
+	}
+
+	tr := tar.NewReader(src)
+	tw := tar.NewWriter(w)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return stats, fmt.Errorf("invalid tar archive: %w", err)
+		}
+		if err := validName(hdr.Name); err != nil {
+			return stats, err
+		}
+		if hdr.Typeflag == tar.TypeLink {
+			if err := validName(hdr.Linkname); err != nil {
+				return stats, err
+			}
+		}
+		if hdr.Typeflag == tar.TypeReg {
+			stats.Files++
+			stats.Bytes += hdr.Size
+			if maxBytes > 0 && stats.Bytes > maxBytes {
+				return stats, ErrTooLarge
+			}
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return stats, err
+		}
+		if _, err := io.Copy(tw, tr); err != nil {
+			return stats, err
+		}
+	}
+	return stats, tw.Close()
+}
+
+// validName rejects archive paths that are absolute or climb out of the target directory
+func validName(name string) error {
+	clean := path.Clean(name)
+	if name == "" || path.IsAbs(name) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return fmt.Errorf("archive entry %q is outside the target directory", name)
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package policy
+
+import (
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// checkInterval is how often cached workloads are re-evaluated
+const checkInterval = time.Minute
+
+// Checker continuously evaluates cached workloads against the active policy
+type Checker struct {
+	mu         sync.RWMutex
+	violations []Violation
+	firstSeen  map[string]time.Time // violation key -> first time it was seen
+	lastRun    time.Time
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+var (
+	checker     *Checker
+	checkerOnce sync.Once
+)
+
+// InitChecker starts periodic policy evaluation
+func InitChecker() {
+	checkerOnce.Do(func() {
+		checker = &Checker{
+			firstSeen: make(map[string]time.Time),
+			stopCh:    make(chan struct{}),
+		}
+		checker.wg.Add(1)
+		go checker.loop()
+		log.Printf("Resource policy checker started (interval=%s)", checkInterval)
+	})
+}
+
+// GetChecker returns the policy checker (nil if not started)
+func GetChecker() *Checker {
+	return checker
+}
+
+// StopChecker stops periodic evaluation
+func StopChecker() {
+	if checker != nil {
+		close(checker.stopCh)
+		checker.wg.Wait()
+	}
+}
+
+func (c *Checker) loop() {
+	defer c.wg.Done()
+	c.Run()
+
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.Run()
+		case <-c.stopCh:
+			return
+		}
+	}
+}
+
+// Run evaluates all cached workloads now (also called after the policy changes)
+func (c *Checker) Run() {
+	if c == nil {
+		return
+	}
+	cache := k8s.GetResourceCache()
+	if cache == nil {
+		return
+	}
+	p := Current()
+
+	var violations []Violation
+	if len(p.Rules) > 0 {
+		nsLabels := make(map[string]map[string]string)
+		if nsLister := cache.Namespaces(); nsLister != nil {
+			if namespaces, err := nsLister.List(labels.Everything()); err == nil {
+				for _, ns := range namespaces {
+					nsLabels[ns.Name] = ns.Labels
+				}
+			}
+		}
+
+		var objects []runtime.Object
+		if l := cache.Deployments(); l != nil {
+			if items, err := l.List(labels.Everything()); err == nil {
+				for _, o := range items {
+					objects = append(objects, o)
+				}
+			}
+		}
+		if l := cache.StatefulSets(); l != nil {
+			if items, err := l.List(labels.Everything()); err == nil {
+				for _, o := range items {
+					objects = append(objects, o)
+				}
+			}
+		}
+		if l := cache.DaemonSets(); l != nil {
+			if items, err := l.List(labels.Everything()); err == nil {
+				for _, o := range items {
+					objects = append(objects, o)
+				}
+			}
+		}
+
+		for _, obj := range objects {
+			if w, ok := WorkloadFromObject(obj); ok {
+				violations = append(violations, Evaluate(p, w, nsLabels[w.Namespace])...)
+			}
+		}
+	}
+
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	seen := make(map[string]time.Time, len(violations))
+	for i := range violations {
+		key := violations[i].key()
+		first, ok := c.firstSeen[key]
+		if !ok {
+			first = now
+		}
+		seen[key] = first
+		violations[i].FirstSeen = first
+	}
+	sort.Slice(violations, func(i, j int) bool {
+		if violations[i].Namespace != violations[j].Namespace {
+			return violations[i].Namespace < violations[j].Namespace
+		}
+		return violations[i].key() < violations[j].key()
+	})
+	c.firstSeen = seen
+	c.violations = violations
+	c.lastRun = now
+}
+
+// Violations returns current violations, optionally filtered to one namespace.
+// Safe to call on a nil checker.
+func (c *Checker) Violations(namespace string) []Violation {
+	if c == nil {
+		return nil
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := make([]Violation, 0, len(c.violations))
+	for _, v := range c.violations {
+		if namespace == "" || v.Namespace == namespace {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// LastRun returns when violations were last evaluated
+func (c *Checker) LastRun() time.Time {
+	if c == nil {
+		return time.Time{}
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.lastRun
+}
+
+// Reset clears violations (e.g. on context switch); the next run repopulates them
+func (c *Checker) Reset() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.violations = nil
+	c.firstSeen = make(map[string]time.Time)
+}
+
+// NamespaceLabels returns a namespace's labels from the cache (nil if unknown)
+func NamespaceLabels(namespace string) map[string]string {
+	cache := k8s.GetResourceCache()
+	if cache == nil || cache.Namespaces() == nil {
+		return nil
+	}
+	ns, err := cache.Namespaces().Get(namespace)
+	if err != nil {
+		return nil
+	}
+	return ns.Labels
+}
@@ -0,0 +1,298 @@
+package policy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// Check identifies the constraint a violation is about
+type Check string
+
+const (
+	CheckRequests          Check = "requests"
+	CheckLimits            Check = "limits"
+	CheckCPURequest        Check = "cpu-request"
+	CheckMemoryRequest     Check = "memory-request"
+	CheckCPULimit          Check = "cpu-limit"
+	CheckMemoryLimit       Check = "memory-limit"
+	CheckLimitRequestRatio Check = "limit-request-ratio"
+	CheckLatestTag         Check = "latest-tag"
+	CheckMinReplicas       Check = "min-replicas"
+)
+
+// Violation is a single rule violation on a workload (or one of its containers)
+type Violation struct {
+	Rule      string    `json:"rule"`
+	Check     Check     `json:"check"`
+	Severity  string    `json:"severity"`
+	Kind      string    `json:"kind"`
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Container string    `json:"container,omitempty"`
+	Message   string    `json:"message"`
+	FirstSeen time.Time `json:"firstSeen,omitempty"`
+}
+
+// key identifies a violation across evaluation runs
+func (v Violation) key() string {
+	return strings.Join([]string{v.Rule, string(v.Check), v.Kind, v.Namespace, v.Name, v.Container}, "/")
+}
+
+// Workload is the policy-relevant view of a pod-template-owning resource
+type Workload struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Labels    map[string]string
+	Replicas  *int32 // nil for kinds without a replica count (DaemonSet)
+	PodSpec   corev1.PodSpec
+}
+
+// WorkloadFromObject extracts a Workload from a typed Deployment, StatefulSet or DaemonSet
+func WorkloadFromObject(obj runtime.Object) (Workload, bool) {
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		return Workload{"Deployment", o.Namespace, o.Name, o.Labels, replicasOrDefault(o.Spec.Replicas), o.Spec.Template.Spec}, true
+	case *appsv1.StatefulSet:
+		return Workload{"StatefulSet", o.Namespace, o.Name, o.Labels, replicasOrDefault(o.Spec.Replicas), o.Spec.Template.Spec}, true
+	case *appsv1.DaemonSet:
+		return Workload{"DaemonSet", o.Namespace, o.Name, o.Labels, nil, o.Spec.Template.Spec}, true
+	}
+	return Workload{}, false
+}
+
+// replicasOrDefault mirrors the API server default of 1 when replicas is unset
+func replicasOrDefault(r *int32) *int32 {
+	if r == nil {
+		one := int32(1)
+		return &one
+	}
+	return r
+}
+
+// Evaluate returns all violations of the policy's rules by a workload
+func Evaluate(p Policy, w Workload, namespaceLabels map[string]string) []Violation {
+	var out []Violation
+	for _, rule := range p.Rules {
+		if !rule.matches(w, namespaceLabels) {
+			continue
+		}
+		severity := rule.Severity
+		if severity == "" {
+			severity = "warning"
+		}
+		add := func(check Check, container, format string, args ...any) {
+			out = append(out, Violation{
+				Rule:      rule.Name,
+				Check:     check,
+				Severity:  severity,
+				Kind:      w.Kind,
+				Namespace: w.Namespace,
+				Name:      w.Name,
+				Container: container,
+				Message:   fmt.Sprintf(format, args...),
+			})
+		}
+
+		if rule.MinReplicas > 0 && w.Replicas != nil && *w.Replicas < rule.MinReplicas {
+			add(CheckMinReplicas, "", "%d replica(s), rule requires at least %d", *w.Replicas, rule.MinReplicas)
+		}
+
+		containers := append(append([]corev1.Container{}, w.PodSpec.InitContainers...), w.PodSpec.Containers...)
+		for _, c := range containers {
+			if rule.ForbidLatestTag && usesLatestTag(c.Image) {
+				add(CheckLatestTag, c.Name, "image %s uses the latest tag", c.Image)
+			}
+			req, lim := c.Resources.Requests, c.Resources.Limits
+			if rule.RequireRequests && (req.Cpu().IsZero() || req.Memory().IsZero()) {
+				add(CheckRequests, c.Name, "CPU and memory requests are required")
+			}
+			if rule.RequireLimits && lim.Memory().IsZero() {
+				add(CheckLimits, c.Name, "memory limit is required")
+			}
+			checkRange(rule.CPURequest, req, corev1.ResourceCPU, func(msg string) { add(CheckCPURequest, c.Name, "CPU request %s", msg) })
+			checkRange(rule.MemoryRequest, req, corev1.ResourceMemory, func(msg string) { add(CheckMemoryRequest, c.Name, "memory request %s", msg) })
+			checkRange(rule.CPULimit, lim, corev1.ResourceCPU, func(msg string) { add(CheckCPULimit, c.Name, "CPU limit %s", msg) })
+			checkRange(rule.MemoryLimit, lim, corev1.ResourceMemory, func(msg string) { add(CheckMemoryLimit, c.Name, "memory limit %s", msg) })
+			if rule.MaxLimitRequestRatio > 0 {
+				for _, res := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+					r, l := req[res], lim[res]
+					if r.IsZero() || l.IsZero() {
+						continue
+					}
+					if ratio := l.AsApproximateFloat64() / r.AsApproximateFloat64(); ratio > rule.MaxLimitRequestRatio {
+						add(CheckLimitRequestRatio, c.Name, "%s limit:request ratio %.1f exceeds %.1f (%s / %s)",
+							res, ratio, rule.MaxLimitRequestRatio, l.String(), r.String())
+					}
+				}
+			}
+		}
+	}
+	return out
+}
+
+// matches reports whether every selector set on the rule matches the workload
+func (r Rule) matches(w Workload, namespaceLabels map[string]string) bool {
+	if len(r.Namespaces) > 0 {
+		matched := false
+		for _, pattern := range r.Namespaces {
+			if ok, _ := path.Match(pattern, w.Namespace); ok {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return labelsMatch(r.NamespaceLabels, namespaceLabels) && labelsMatch(r.WorkloadLabels, w.Labels)
+}
+
+func labelsMatch(selector, labels map[string]string) bool {
+	for k, v := range selector {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// checkRange reports a quantity missing or outside the range via report
+func checkRange(qr *QuantityRange, list corev1.ResourceList, name corev1.ResourceName, report func(string)) {
+	if qr == nil {
+		return
+	}
+	min, max, err := qr.parse()
+	if err != nil {
+		return // Rejected by Validate before it gets here
+	}
+	v, ok := list[name]
+	if !ok || v.IsZero() {
+		report("is not set")
+		return
+	}
+	if min != nil && v.Cmp(*min) < 0 {
+		report(fmt.Sprintf("%s is below the minimum %s", v.String(), min.String()))
+	}
+	if max != nil && v.Cmp(*max) > 0 {
+		report(fmt.Sprintf("%s exceeds the maximum %s", v.String(), max.String()))
+	}
+}
+
+// usesLatestTag reports whether an image is untagged or tagged latest (digests are fine)
+func usesLatestTag(image string) bool {
+	if strings.Contains(image, "@") {
+		return false
+	}
+	// A tag follows the last colon after the last slash (registry ports come before it)
+	name := image[strings.LastIndex(image, "/")+1:]
+	i := strings.LastIndex(name, ":")
+	return i < 0 || name[i+1:] == "latest"
+}
+
+// ViolationError is returned when enforcement blocks a change
+type ViolationError struct {
+	Violations []Violation `json:"violations"`
+}
+
+func (e *ViolationError) Error() string {
+	msgs := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		target := fmt.Sprintf("%s %s/%s", v.Kind, v.Namespace, v.Name)
+		if v.Container != "" {
+			target += " container " + v.Container
+		}
+		msgs = append(msgs, fmt.Sprintf("%s: %s (rule %q)", target, v.Message, v.Rule))
+	}
+	return "blocked by resource policy: " + strings.Join(msgs, "; ")
+}
+
+// CheckObject enforces the policy on a workload Radar is about to write.
+// Returns a *ViolationError if enforcement is on and the object violates any rule.
+func CheckObject(obj *unstructured.Unstructured, namespaceLabels map[string]string) error {
+	p := Current()
+	if !p.Enforce || len(p.Rules) == 0 {
+		return nil
+	}
+	typed, err := toTyped(obj)
+	if err != nil || typed == nil {
+		return err
+	}
+	w, _ := WorkloadFromObject(typed)
+	if violations := Evaluate(p, w, namespaceLabels); len(violations) > 0 {
+		return &ViolationError{Violations: violations}
+	}
+	return nil
+}
+
+// CheckManifest enforces the policy on a multi-document YAML manifest (e.g. rendered
+// Helm templates). Objects without a namespace are evaluated in defaultNamespace.
+func CheckManifest(manifest, defaultNamespace string, namespaceLabels func(string) map[string]string) error {
+	p := Current()
+	if !p.Enforce || len(p.Rules) == 0 {
+		return nil
+	}
+	var all []Violation
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewBufferString(manifest), 4096)
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return fmt.Errorf("failed to parse manifest: %w", err)
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+		if obj.GetNamespace() == "" {
+			obj.SetNamespace(defaultNamespace)
+		}
+		typed, err := toTyped(obj)
+		if err != nil {
+			return err
+		}
+		if typed == nil {
+			continue
+		}
+		w, _ := WorkloadFromObject(typed)
+		all = append(all, Evaluate(p, w, namespaceLabels(w.Namespace))...)
+	}
+	if len(all) > 0 {
+		return &ViolationError{Violations: all}
+	}
+	return nil
+}
+
+// toTyped converts apps/v1 workloads to typed objects; other kinds return nil
+func toTyped(obj *unstructured.Unstructured) (runtime.Object, error) {
+	if obj.GroupVersionKind().GroupVersion() != appsv1.SchemeGroupVersion {
+		return nil, nil
+	}
+	var typed runtime.Object
+	switch obj.GetKind() {
+	case "Deployment":
+		typed = &appsv1.Deployment{}
+	case "StatefulSet":
+		typed = &appsv1.StatefulSet{}
+	case "DaemonSet":
+		typed = &appsv1.DaemonSet{}
+	default:
+		return nil, nil
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, typed); err != nil {
+		return nil, fmt.Errorf("failed to decode %s %s: %w", obj.GetKind(), obj.GetName(), err)
+	}
+	return typed, nil
+}
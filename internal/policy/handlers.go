@@ -0,0 +1,89 @@
+package policy
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+)
+
+// Handlers provides HTTP handlers for the resource policy endpoints
+type Handlers struct{}
+
+// NewHandlers creates a new Handlers instance
+func NewHandlers() *Handlers {
+	return &Handlers{}
+}
+
+// RegisterRoutes registers policy routes on the given router
+func (h *Handlers) RegisterRoutes(r chi.Router) {
+	r.Route("/policy", func(r chi.Router) {
+		r.Get("/", h.handleGetPolicy)
+		r.Put("/", h.handlePutPolicy)
+		r.Get("/violations", h.handleGetViolations)
+	})
+}
+
+// ViolationsResponse lists current violations
+type ViolationsResponse struct {
+	Enforce    bool        `json:"enforce"`
+	Violations []Violation `json:"violations"`
+	LastRun    *time.Time  `json:"lastRun,omitempty"`
+}
+
+// handleGetPolicy returns the active policy
+func (h *Handlers) handleGetPolicy(w http.ResponseWriter, r *http.Request) {
+	p := Current()
+	if p.Rules == nil {
+		p.Rules = []Rule{}
+	}
+	writeJSON(w, p)
+}
+
+// handlePutPolicy replaces the policy and re-evaluates immediately
+func (h *Handlers) handlePutPolicy(w http.ResponseWriter, r *http.Request) {
+	var p Policy
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := Validate(p); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := Save(p); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	GetChecker().Run()
+	writeJSON(w, p)
+}
+
+// handleGetViolations returns current violations, filtered by ?namespace=
+func (h *Handlers) handleGetViolations(w http.ResponseWriter, r *http.Request) {
+	c := GetChecker()
+	if c == nil {
+		writeError(w, http.StatusServiceUnavailable, "Policy checker not running")
+		return
+	}
+	resp := ViolationsResponse{
+		Enforce:    Current().Enforce,
+		Violations: c.Violations(r.URL.Query().Get("namespace")),
+	}
+	if last := c.LastRun(); !last.IsZero() {
+		resp.LastRun = &last
+	}
+	writeJSON(w, resp)
+}
+
+func writeJSON(w http.ResponseWriter, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(data)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	explorerErrors.WriteHTTP(w, status, message)
+}
@@ -0,0 +1,162 @@
+// Package policy evaluates workload resource settings against user-defined rules
+// (request/limit ranges, limit:request ratios, image tags, replica minimums) and can
+// block Radar-initiated changes that violate them.
+package policy
+
+import (
+	"fmt"
+	"path"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/skyhook-io/radar/internal/settings"
+)
+
+// settingsSection is the settings store section holding the policy
+const settingsSection = "resourcePolicy"
+
+// Policy is the full set of rules plus enforcement mode
+type Policy struct {
+	// Enforce blocks resource edits and Helm installs/upgrades made through Radar
+	// when the resulting workloads violate a rule (the cluster itself is never blocked)
+	Enforce bool   `json:"enforce"`
+	Rules   []Rule `json:"rules"`
+}
+
+// Rule constrains workloads matched by namespace and/or labels. A rule with no
+// selectors applies to every workload.
+type Rule struct {
+	Name     string `json:"name"`
+	Severity string `json:"severity,omitempty"` // warning (default) or error
+
+	// Selectors (all set selectors must match)
+	Namespaces      []string          `json:"namespaces,omitempty"`      // Glob patterns, e.g. "prod-*"
+	NamespaceLabels map[string]string `json:"namespaceLabels,omitempty"` // e.g. tier: production
+	WorkloadLabels  map[string]string `json:"workloadLabels,omitempty"`  // e.g. env: production
+
+	// Constraints
+	RequireRequests      bool           `json:"requireRequests,omitempty"`
+	RequireLimits        bool           `json:"requireLimits,omitempty"`
+	CPURequest           *QuantityRange `json:"cpuRequest,omitempty"`
+	MemoryRequest        *QuantityRange `json:"memoryRequest,omitempty"`
+	CPULimit             *QuantityRange `json:"cpuLimit,omitempty"`
+	MemoryLimit          *QuantityRange `json:"memoryLimit,omitempty"`
+	MaxLimitRequestRatio float64        `json:"maxLimitRequestRatio,omitempty"` // Applied to CPU and memory
+	ForbidLatestTag      bool           `json:"forbidLatestTag,omitempty"`
+	MinReplicas          int32          `json:"minReplicas,omitempty"`
+}
+
+// QuantityRange is an inclusive range of resource quantities (either bound optional)
+type QuantityRange struct {
+	Min string `json:"min,omitempty"`
+	Max string `json:"max,omitempty"`
+}
+
+var (
+	current   Policy
+	currentMu sync.RWMutex
+	loadOnce  sync.Once
+)
+
+// Current returns the active policy, loading it from the settings store on first use
+func Current() Policy {
+	loadOnce.Do(func() {
+		var p Policy
+		if _, err := settings.Get().Load(settingsSection, &p); err == nil {
+			currentMu.Lock()
+			current = p
+			currentMu.Unlock()
+		}
+	})
+	currentMu.RLock()
+	defer currentMu.RUnlock()
+	return current
+}
+
+// Save validates and persists a new policy, replacing the active one
+func Save(p Policy) error {
+	if err := Validate(p); err != nil {
+		return err
+	}
+	Current() // Ensure the initial load can't overwrite the saved policy later
+	if err := settings.Get().Save(settingsSection, p); err != nil {
+		return fmt.Errorf("failed to save policy: %w", err)
+	}
+	currentMu.Lock()
+	current = p
+	currentMu.Unlock()
+	return nil
+}
+
+// Validate checks rule names, severities, glob patterns and quantities
+func Validate(p Policy) error {
+	names := make(map[string]bool)
+	for i, r := range p.Rules {
+		field := fmt.Sprintf("rules[%d]", i)
+		if r.Name == "" {
+			return fmt.Errorf("%s: name is required", field)
+		}
+		if names[r.Name] {
+			return fmt.Errorf("%s: duplicate rule name %q", field, r.Name)
+		}
+		names[r.Name] = true
+		switch r.Severity {
+		case "", "warning", "error":
+		default:
+			return fmt.Errorf("%s: severity must be \"warning\" or \"error\"", field)
+		}
+		for _, pattern := range r.Namespaces {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("%s: invalid namespace pattern %q", field, pattern)
+			}
+		}
+		for name, qr := range map[string]*QuantityRange{
+			"cpuRequest": r.CPURequest, "memoryRequest": r.MemoryRequest,
+			"cpuLimit": r.CPULimit, "memoryLimit": r.MemoryLimit,
+		} {
+			if err := qr.validate(); err != nil {
+				return fmt.Errorf("%s.%s: %w", field, name, err)
+			}
+		}
+		if r.MaxLimitRequestRatio != 0 && r.MaxLimitRequestRatio < 1 {
+			return fmt.Errorf("%s: maxLimitRequestRatio must be at least 1", field)
+		}
+		if r.MinReplicas < 0 {
+			return fmt.Errorf("%s: minReplicas must not be negative", field)
+		}
+	}
+	return nil
+}
+
+func (q *QuantityRange) validate() error {
+	if q == nil {
+		return nil
+	}
+	min, max, err := q.parse()
+	if err != nil {
+		return err
+	}
+	if min != nil && max != nil && min.Cmp(*max) > 0 {
+		return fmt.Errorf("min %s is greater than max %s", q.Min, q.Max)
+	}
+	return nil
+}
+
+func (q *QuantityRange) parse() (min, max *resource.Quantity, err error) {
+	if q.Min != "" {
+		v, err := resource.ParseQuantity(q.Min)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid min %q: %w", q.Min, err)
+		}
+		min = &v
+	}
+	if q.Max != "" {
+		v, err := resource.ParseQuantity(q.Max)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid max %q: %w", q.Max, err)
+		}
+		max = &v
+	}
+	return min, max, nil
+}
@@ -0,0 +1,76 @@
+package policy
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func container(name, image string, req, lim corev1.ResourceList) corev1.Container {
+	return corev1.Container{Name: name, Image: image, Resources: corev1.ResourceRequirements{Requests: req, Limits: lim}}
+}
+
+func TestEvaluate(t *testing.T) {
+	one := int32(1)
+	w := Workload{
+		Kind:      "Deployment",
+		Namespace: "prod-payments",
+		Name:      "api",
+		Labels:    map[string]string{"env": "production"},
+		Replicas:  &one,
+		PodSpec: corev1.PodSpec{Containers: []corev1.Container{
+			container("app", "registry:5000/payments/api",
+				corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m"), corev1.ResourceMemory: resource.MustParse("64Mi")},
+				corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2"), corev1.ResourceMemory: resource.MustParse("8Gi")}),
+		}},
+	}
+	p := Policy{Rules: []Rule{{
+		Name:                 "prod",
+		Severity:             "error",
+		Namespaces:           []string{"prod-*"},
+		WorkloadLabels:       map[string]string{"env": "production"},
+		MemoryLimit:          &QuantityRange{Max: "4Gi"},
+		MaxLimitRequestRatio: 4,
+		ForbidLatestTag:      true,
+		MinReplicas:          2,
+	}}}
+	if err := Validate(p); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	got := make(map[Check]int)
+	for _, v := range Evaluate(p, w, nil) {
+		if v.Severity != "error" {
+			t.Errorf("severity = %q, want error", v.Severity)
+		}
+		got[v.Check]++
+	}
+	want := map[Check]int{CheckMinReplicas: 1, CheckLatestTag: 1, CheckMemoryLimit: 1, CheckLimitRequestRatio: 2}
+	for check, n := range want {
+		if got[check] != n {
+			t.Errorf("%s: got %d violations, want %d (all: %v)", check, got[check], n, got)
+		}
+	}
+
+	w.Namespace = "staging"
+	if v := Evaluate(p, w, nil); len(v) != 0 {
+		t.Errorf("rule should not match staging namespace, got %v", v)
+	}
+}
+
+func TestUsesLatestTag(t *testing.T) {
+	cases := map[string]bool{
+		"nginx":                       true,
+		"nginx:latest":                true,
+		"registry:5000/nginx":         true,
+		"registry:5000/nginx:1.25":    false,
+		"nginx@sha256:abc":            false,
+		"ghcr.io/org/app:latest-fips": false,
+	}
+	for image, want := range cases {
+		if got := usesLatestTag(image); got != want {
+			t.Errorf("usesLatestTag(%q) = %v, want %v", image, got, want)
+		}
+	}
+}
@@ -0,0 +1,446 @@
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const (
+	// ContextName is the kubeconfig context used while replaying
+	ContextName = "replay"
+
+	bundleResourceVersion = "1"
+	maxWatchDuration      = 30 * time.Minute
+)
+
+// builtinResources are always served (empty if not in the bundle) so Radar's typed
+// informers sync even when the bundle has no objects of that kind
+var builtinResources = []ResourceSet{
+	{Version: "v1", Resource: "namespaces", Kind: "Namespace"},
+	{Version: "v1", Resource: "nodes", Kind: "Node"},
+	{Version: "v1", Resource: "pods", Kind: "Pod", Namespaced: true},
+	{Version: "v1", Resource: "services", Kind: "Service", Namespaced: true},
+	{Version: "v1", Resource: "configmaps", Kind: "ConfigMap", Namespaced: true},
+	{Version: "v1", Resource: "secrets", Kind: "Secret", Namespaced: true},
+	{Version: "v1", Resource: "events", Kind: "Event", Namespaced: true},
+	{Version: "v1", Resource: "persistentvolumeclaims", Kind: "PersistentVolumeClaim", Namespaced: true},
+	{Version: "v1", Resource: "resourcequotas", Kind: "ResourceQuota", Namespaced: true},
+	{Version: "v1", Resource: "limitranges", Kind: "LimitRange", Namespaced: true},
+	{Group: "apps", Version: "v1", Resource: "deployments", Kind: "Deployment", Namespaced: true},
+	{Group: "apps", Version: "v1", Resource: "daemonsets", Kind: "DaemonSet", Namespaced: true},
+	{Group: "apps", Version: "v1", Resource: "statefulsets", Kind: "StatefulSet", Namespaced: true},
+	{Group: "apps", Version: "v1", Resource: "replicasets", Kind: "ReplicaSet", Namespaced: true},
+	{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses", Kind: "Ingress", Namespaced: true},
+	{Group: "batch", Version: "v1", Resource: "jobs", Kind: "Job", Namespaced: true},
+	{Group: "batch", Version: "v1", Resource: "cronjobs", Kind: "CronJob", Namespaced: true},
+	{Group: "autoscaling", Version: "v2", Resource: "horizontalpodautoscalers", Kind: "HorizontalPodAutoscaler", Namespaced: true},
+}
+
+// APIServer is a read-only Kubernetes API backed by a bundle. It implements just enough
+// of the API (discovery, get, list, watch, self access reviews) for client-go informers.
+type APIServer struct {
+	mu         sync.RWMutex
+	bundle     *Bundle
+	resources  map[string]*ResourceSet // "group/version/resource" -> set
+	listener   net.Listener
+	kubeconfig string
+}
+
+// StartAPIServer serves the bundle on a loopback port and writes a kubeconfig pointing
+// at it. Pass the returned path to the normal client initialization.
+func StartAPIServer(b *Bundle) (kubeconfigPath string, err error) {
+	s, err := ServeBundle(b)
+	if err != nil {
+		return "", err
+	}
+	return s.Kubeconfig(), nil
+}
+
+// ServeBundle is StartAPIServer returning the server, so the bundle can be swapped
+// (SetBundle) or the server closed
+func ServeBundle(b *Bundle) (s *APIServer, err error) {
+	s = &APIServer{}
+	s.SetBundle(b)
+
+	s.listener, err = net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to start replay API server: %w", err)
+	}
+	go func() {
+		if err := http.Serve(s.listener, s); err != nil {
+			log.Printf("Replay API server stopped: %v", err)
+		}
+	}()
+
+	server := "http://" + s.listener.Addr().String()
+	cluster := b.Cluster
+	if cluster == "" {
+		cluster = "replay"
+	}
+	cfg := clientcmdapi.NewConfig()
+	cfg.Clusters[cluster] = &clientcmdapi.Cluster{Server: server}
+	cfg.AuthInfos[ContextName] = &clientcmdapi.AuthInfo{}
+	cfg.Contexts[ContextName] = &clientcmdapi.Context{Cluster: cluster, AuthInfo: ContextName}
+	cfg.CurrentContext = ContextName
+
+	dir, err := os.MkdirTemp("", "radar-replay-")
+	if err != nil {
+		s.listener.Close()
+		return nil, fmt.Errorf("failed to create replay kubeconfig: %w", err)
+	}
+	s.kubeconfig = filepath.Join(dir, "kubeconfig")
+	if err := clientcmd.WriteToFile(*cfg, s.kubeconfig); err != nil {
+		s.listener.Close()
+		return nil, fmt.Errorf("failed to write replay kubeconfig: %w", err)
+	}
+	log.Printf("Replay API server for %q listening on %s (%d objects)", cluster, server, b.ObjectCount())
+	return s, nil
+}
+
+// Kubeconfig returns the path of the kubeconfig pointing at the server
+func (s *APIServer) Kubeconfig() string {
+	return s.kubeconfig
+}
+
+// SetBundle replaces the served objects. Open watches don't see the change; clients
+// must list again (restart their informers).
+func (s *APIServer) SetBundle(b *Bundle) {
+	resources := make(map[string]*ResourceSet)
+	for i := range builtinResources {
+		rs := builtinResources[i]
+		resources[resourceKey(rs.Group, rs.Version, rs.Resource)] = &rs
+	}
+	for i := range b.Resources {
+		rs := b.Resources[i]
+		resources[resourceKey(rs.Group, rs.Version, rs.Resource)] = &rs
+	}
+
+	s.mu.Lock()
+	s.bundle, s.resources = b, resources
+	s.mu.Unlock()
+}
+
+// Close stops the server and removes its kubeconfig
+func (s *APIServer) Close() error {
+	os.RemoveAll(filepath.Dir(s.kubeconfig))
+	return s.listener.Close()
+}
+
+// snapshot returns the bundle and resource sets being served. SetBundle replaces both
+// rather than modifying them, so they can be read without holding the lock.
+func (s *APIServer) snapshot() (*Bundle, map[string]*ResourceSet) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.bundle, s.resources
+}
+
+func resourceKey(group, version, resource string) string {
+	return group + "/" + version + "/" + resource
+}
+
+func (s *APIServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case r.URL.Path == "/version":
+		s.serveVersion(w)
+	case r.URL.Path == "/healthz" || r.URL.Path == "/readyz" || r.URL.Path == "/livez":
+		w.Write([]byte("ok"))
+	case r.URL.Path == "/api":
+		writeObject(w, http.StatusOK, map[string]any{"kind": "APIVersions", "versions": []string{"v1"}})
+	case r.URL.Path == "/apis":
+		s.serveGroups(w)
+	case len(parts) >= 2 && parts[0] == "api":
+		s.serveResource(w, r, "", parts[1], parts[2:])
+	case len(parts) >= 3 && parts[0] == "apis":
+		if parts[1] == "authorization.k8s.io" && len(parts) == 4 && parts[3] == "selfsubjectaccessreviews" {
+			s.serveAccessReview(w, r)
+			return
+		}
+		s.serveResource(w, r, parts[1], parts[2], parts[3:])
+	default:
+		writeStatus(w, http.StatusNotFound, "NotFound", "the server could not find the requested resource")
+	}
+}
+
+func (s *APIServer) serveVersion(w http.ResponseWriter) {
+	bundle, _ := s.snapshot()
+	gitVersion := bundle.ServerVersion
+	if gitVersion == "" {
+		gitVersion = "v1.30.0"
+	}
+	major, minor := "1", "30"
+	if v := strings.SplitN(strings.TrimPrefix(gitVersion, "v"), ".", 3); len(v) >= 2 {
+		major, minor = v[0], strings.TrimRight(v[1], "+")
+	}
+	writeObject(w, http.StatusOK, map[string]any{
+		"major":      major,
+		"minor":      minor,
+		"gitVersion": gitVersion,
+		"platform":   "replay",
+	})
+}
+
+// serveGroups returns the API group list for everything outside the core group
+func (s *APIServer) serveGroups(w http.ResponseWriter) {
+	_, sets := s.snapshot()
+	versions := map[string]map[string]bool{"authorization.k8s.io": {"v1": true}}
+	for _, rs := range sets {
+		if rs.Group == "" {
+			continue
+		}
+		if versions[rs.Group] == nil {
+			versions[rs.Group] = make(map[string]bool)
+		}
+		versions[rs.Group][rs.Version] = true
+	}
+
+	names := make([]string, 0, len(versions))
+	for g := range versions {
+		names = append(names, g)
+	}
+	sort.Strings(names)
+	groups := make([]map[string]any, 0, len(names))
+	for _, g := range names {
+		var vs []map[string]string
+		for v := range versions[g] {
+			vs = append(vs, map[string]string{"groupVersion": g + "/" + v, "version": v})
+		}
+		sort.Slice(vs, func(i, j int) bool { return vs[i]["version"] < vs[j]["version"] })
+		groups = append(groups, map[string]any{"name": g, "versions": vs, "preferredVersion": vs[len(vs)-1]})
+	}
+	writeObject(w, http.StatusOK, map[string]any{"kind": "APIGroupList", "apiVersion": "v1", "groups": groups})
+}
+
+// serveResourceList returns discovery for one group version
+func (s *APIServer) serveResourceList(w http.ResponseWriter, group, version string) {
+	gv := version
+	if group != "" {
+		gv = group + "/" + version
+	}
+	var resources []map[string]any
+	if group == "authorization.k8s.io" && version == "v1" {
+		resources = append(resources, map[string]any{
+			"name": "selfsubjectaccessreviews", "kind": "SelfSubjectAccessReview", "namespaced": false, "verbs": []string{"create"},
+		})
+	}
+	_, sets := s.snapshot()
+	for _, rs := range sets {
+		if rs.Group == group && rs.Version == version {
+			resources = append(resources, map[string]any{
+				"name": rs.Resource, "kind": rs.Kind, "namespaced": rs.Namespaced, "verbs": []string{"get", "list", "watch"},
+			})
+		}
+	}
+	if len(resources) == 0 {
+		writeStatus(w, http.StatusNotFound, "NotFound", "the server could not find the requested resource")
+		return
+	}
+	sort.Slice(resources, func(i, j int) bool { return resources[i]["name"].(string) < resources[j]["name"].(string) })
+	writeObject(w, http.StatusOK, map[string]any{"kind": "APIResourceList", "apiVersion": "v1", "groupVersion": gv, "resources": resources})
+}
+
+// serveResource handles [namespaces/{ns}/]{resource}[/{name}] under a group version
+func (s *APIServer) serveResource(w http.ResponseWriter, r *http.Request, group, version string, rest []string) {
+	if len(rest) == 0 {
+		s.serveResourceList(w, group, version)
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeStatus(w, http.StatusForbidden, "Forbidden", "replay clusters are read-only")
+		return
+	}
+
+	namespace := ""
+	if rest[0] == "namespaces" && len(rest) >= 3 {
+		namespace, rest = rest[1], rest[2:]
+	}
+	if len(rest) > 2 {
+		writeStatus(w, http.StatusNotFound, "NotFound", "subresources are not available in replay")
+		return
+	}
+	_, sets := s.snapshot()
+	rs, ok := sets[resourceKey(group, version, rest[0])]
+	if !ok {
+		writeStatus(w, http.StatusNotFound, "NotFound", fmt.Sprintf("the server could not find the requested resource (%s)", rest[0]))
+		return
+	}
+
+	if len(rest) == 2 {
+		for _, item := range rs.Items {
+			if itemNamespace(item) == namespace && itemName(item) == rest[1] {
+				writeObject(w, http.StatusOK, item)
+				return
+			}
+		}
+		writeStatus(w, http.StatusNotFound, "NotFound", fmt.Sprintf("%s %q not found", rs.Resource, rest[1]))
+		return
+	}
+
+	items, err := filterItems(rs.Items, namespace, r.URL.Query().Get("labelSelector"), r.URL.Query().Get("fieldSelector"))
+	if err != nil {
+		writeStatus(w, http.StatusBadRequest, "BadRequest", err.Error())
+		return
+	}
+	if watch := r.URL.Query().Get("watch"); watch == "true" || watch == "1" {
+		s.serveWatch(w, r, rs, items)
+		return
+	}
+	writeObject(w, http.StatusOK, map[string]any{
+		"apiVersion": rs.GroupVersion(),
+		"kind":       rs.Kind + "List",
+		"metadata":   map[string]any{"resourceVersion": bundleResourceVersion},
+		"items":      items,
+	})
+}
+
+// serveWatch sends the initial state if requested (watch-list) and then holds the
+// watch open; a replay has no live changes
+func (s *APIServer) serveWatch(w http.ResponseWriter, r *http.Request, rs *ResourceSet, items []map[string]any) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeStatus(w, http.StatusInternalServerError, "InternalError", "streaming not supported")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	enc := json.NewEncoder(w)
+	if r.URL.Query().Get("sendInitialEvents") == "true" {
+		for _, item := range items {
+			enc.Encode(map[string]any{"type": "ADDED", "object": item})
+		}
+		enc.Encode(map[string]any{"type": "BOOKMARK", "object": map[string]any{
+			"apiVersion": rs.GroupVersion(),
+			"kind":       rs.Kind,
+			"metadata": map[string]any{
+				"resourceVersion": bundleResourceVersion,
+				"annotations":     map[string]string{"k8s.io/initial-events-end": "true"},
+			},
+		}})
+	}
+	flusher.Flush()
+
+	timeout := maxWatchDuration
+	if secs, err := strconv.Atoi(r.URL.Query().Get("timeoutSeconds")); err == nil && secs > 0 {
+		timeout = time.Duration(secs) * time.Second
+	}
+	select {
+	case <-r.Context().Done():
+	case <-time.After(timeout):
+	}
+}
+
+// serveAccessReview allows read verbs and denies everything else
+func (s *APIServer) serveAccessReview(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeStatus(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "only create is supported")
+		return
+	}
+	var review map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&review); err != nil {
+		writeStatus(w, http.StatusBadRequest, "BadRequest", err.Error())
+		return
+	}
+	verb := ""
+	if spec, ok := review["spec"].(map[string]any); ok {
+		if attrs, ok := spec["resourceAttributes"].(map[string]any); ok {
+			verb, _ = attrs["verb"].(string)
+		}
+	}
+	allowed := verb == "get" || verb == "list" || verb == "watch"
+	status := map[string]any{"allowed": allowed}
+	if !allowed {
+		status["reason"] = "replay clusters are read-only"
+	}
+	review["status"] = status
+	writeObject(w, http.StatusCreated, review)
+}
+
+func filterItems(items []map[string]any, namespace, labelSelector, fieldSelector string) ([]map[string]any, error) {
+	ls, err := labels.Parse(labelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid labelSelector: %w", err)
+	}
+	fs, err := fields.ParseSelector(fieldSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fieldSelector: %w", err)
+	}
+	// Only metadata fields can be evaluated generically; other field selectors are ignored
+	metadataOnly := !fs.Empty()
+	for _, req := range fs.Requirements() {
+		if req.Field != "metadata.name" && req.Field != "metadata.namespace" {
+			metadataOnly = false
+		}
+	}
+
+	out := make([]map[string]any, 0, len(items))
+	for _, item := range items {
+		if namespace != "" && itemNamespace(item) != namespace {
+			continue
+		}
+		if !ls.Matches(labels.Set(itemLabels(item))) {
+			continue
+		}
+		if metadataOnly && !fs.Matches(fields.Set{"metadata.name": itemName(item), "metadata.namespace": itemNamespace(item)}) {
+			continue
+		}
+		out = append(out, item)
+	}
+	return out, nil
+}
+
+func itemMetadata(item map[string]any) map[string]any {
+	md, _ := item["metadata"].(map[string]any)
+	return md
+}
+
+func itemName(item map[string]any) string {
+	name, _ := itemMetadata(item)["name"].(string)
+	return name
+}
+
+func itemNamespace(item map[string]any) string {
+	ns, _ := itemMetadata(item)["namespace"].(string)
+	return ns
+}
+
+func itemLabels(item map[string]any) map[string]string {
+	raw, _ := itemMetadata(item)["labels"].(map[string]any)
+	out := make(map[string]string, len(raw))
+	for k, v := range raw {
+		out[k], _ = v.(string)
+	}
+	return out
+}
+
+func writeObject(w http.ResponseWriter, status int, obj any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(obj)
+}
+
+func writeStatus(w http.ResponseWriter, code int, reason, message string) {
+	writeObject(w, code, map[string]any{
+		"kind":       "Status",
+		"apiVersion": "v1",
+		"metadata":   map[string]any{},
+		"status":     "Failure",
+		"message":    message,
+		"reason":     reason,
+		"code":       code,
+	})
+}
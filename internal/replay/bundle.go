@@ -0,0 +1,101 @@
+// Package replay records a cluster's resources and timeline into a bundle and serves
+// a bundle back through the normal APIs without a live cluster. A replay runs a local
+// read-only Kubernetes API server backed by the bundle, so the caches, topology and
+// resource views work unchanged, and plays the recorded timeline back at a chosen speed.
+package replay
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/skyhook-io/radar/internal/timeline"
+)
+
+// BundleVersion is the bundle format version written by Capture
+const BundleVersion = 1
+
+// Bundle is a recorded cluster state: resources at capture time plus the timeline leading up to it
+type Bundle struct {
+	Version       int                      `json:"version"`
+	Cluster       string                   `json:"cluster,omitempty"`
+	Context       string                   `json:"context,omitempty"`
+	ServerVersion string                   `json:"serverVersion,omitempty"` // e.g. v1.30.2
+	CapturedAt    time.Time                `json:"capturedAt"`
+	Resources     []ResourceSet            `json:"resources"`
+	Events        []timeline.TimelineEvent `json:"events"`
+}
+
+// ResourceSet is every captured object of one resource type
+type ResourceSet struct {
+	Group      string           `json:"group,omitempty"`
+	Version    string           `json:"version"`
+	Resource   string           `json:"resource"` // Plural, e.g. "deployments"
+	Kind       string           `json:"kind"`
+	Namespaced bool             `json:"namespaced"`
+	Items      []map[string]any `json:"items"`
+}
+
+// GroupVersion returns the apiVersion string for the set
+func (s ResourceSet) GroupVersion() string {
+	if s.Group == "" {
+		return s.Version
+	}
+	return s.Group + "/" + s.Version
+}
+
+// ObjectCount returns the number of captured objects across all resource sets
+func (b *Bundle) ObjectCount() int {
+	n := 0
+	for _, rs := range b.Resources {
+		n += len(rs.Items)
+	}
+	return n
+}
+
+// LoadBundle reads a bundle file (plain or gzipped JSON)
+func LoadBundle(path string) (*Bundle, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open replay bundle: %w", err)
+	}
+	defer f.Close()
+	return ReadBundle(f)
+}
+
+// ReadBundle decodes a bundle, detecting gzip compression
+func ReadBundle(r io.Reader) (*Bundle, error) {
+	br := bufio.NewReader(r)
+	var src io.Reader = br
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip bundle: %w", err)
+		}
+		defer gz.Close()
+		src = gz
+	}
+
+	var b Bundle
+	if err := json.NewDecoder(src).Decode(&b); err != nil {
+		return nil, fmt.Errorf("invalid replay bundle: %w", err)
+	}
+	if b.Version == 0 || b.Version > BundleVersion {
+		return nil, fmt.Errorf("unsupported replay bundle version %d (supported: %d)", b.Version, BundleVersion)
+	}
+	return &b, nil
+}
+
+// WriteBundle encodes a bundle as gzipped JSON
+func WriteBundle(w io.Writer, b *Bundle) error {
+	gz := gzip.NewWriter(w)
+	if err := json.NewEncoder(gz).Encode(b); err != nil {
+		gz.Close()
+		return err
+	}
+	return gz.Close()
+}
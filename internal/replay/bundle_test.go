@@ -0,0 +1,78 @@
+package replay
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/skyhook-io/radar/internal/timeline"
+)
+
+func TestBundleRoundTrip(t *testing.T) {
+	b := &Bundle{
+		Version:    BundleVersion,
+		Cluster:    "demo",
+		CapturedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Resources: []ResourceSet{{
+			Group: "apps", Version: "v1", Resource: "deployments", Kind: "Deployment", Namespaced: true,
+			Items: []map[string]any{{"metadata": map[string]any{"name": "web", "namespace": "shop"}}},
+		}},
+		Events: []timeline.TimelineEvent{{ID: "e1", Kind: "Deployment", Name: "web"}},
+	}
+
+	var gz bytes.Buffer
+	if err := WriteBundle(&gz, b); err != nil {
+		t.Fatalf("WriteBundle: %v", err)
+	}
+	got, err := ReadBundle(&gz)
+	if err != nil {
+		t.Fatalf("ReadBundle(gzip): %v", err)
+	}
+	if got.Cluster != "demo" || got.ObjectCount() != 1 || len(got.Events) != 1 || got.Resources[0].GroupVersion() != "apps/v1" {
+		t.Errorf("unexpected bundle after round trip: %+v", got)
+	}
+
+	// Plain JSON bundles are accepted too
+	plain, _ := json.Marshal(b)
+	if _, err := ReadBundle(bytes.NewReader(plain)); err != nil {
+		t.Errorf("ReadBundle(plain): %v", err)
+	}
+
+	future, _ := json.Marshal(Bundle{Version: BundleVersion + 1})
+	if _, err := ReadBundle(bytes.NewReader(future)); err == nil {
+		t.Error("expected an error for a newer bundle version")
+	}
+}
+
+func TestFilterItems(t *testing.T) {
+	item := func(ns, name string, lbls map[string]any) map[string]any {
+		return map[string]any{"metadata": map[string]any{"name": name, "namespace": ns, "labels": lbls}}
+	}
+	items := []map[string]any{
+		item("shop", "web", map[string]any{"app": "web"}),
+		item("shop", "db", map[string]any{"app": "db"}),
+		item("kube-system", "dns", nil),
+	}
+
+	tests := []struct {
+		namespace, labelSelector, fieldSelector string
+		want                                    int
+	}{
+		{"", "", "", 3},
+		{"shop", "", "", 2},
+		{"", "app=web", "", 1},
+		{"", "", "metadata.name=dns", 1},
+		{"shop", "", "metadata.name=dns", 0},
+		{"", "", "status.phase=Running", 3}, // Non-metadata field selectors are ignored
+	}
+	for _, tt := range tests {
+		got, err := filterItems(items, tt.namespace, tt.labelSelector, tt.fieldSelector)
+		if err != nil {
+			t.Fatalf("filterItems(%q, %q, %q): %v", tt.namespace, tt.labelSelector, tt.fieldSelector, err)
+		}
+		if len(got) != tt.want {
+			t.Errorf("filterItems(%q, %q, %q) = %d items, want %d", tt.namespace, tt.labelSelector, tt.fieldSelector, len(got), tt.want)
+		}
+	}
+}
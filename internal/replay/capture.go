@@ -0,0 +1,167 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/timeline"
+)
+
+// maxCapturedEvents caps the timeline events written to a bundle
+const maxCapturedEvents = 10000
+
+// Capture records the cached resources and the timeline since the given time into a bundle.
+// Secret values are dropped (keys are kept) so bundles are safe to attach to bug reports.
+// Kubernetes Event objects are not captured as resources; they are part of the timeline.
+func Capture(ctx context.Context, since time.Time) (*Bundle, error) {
+	cache := k8s.GetResourceCache()
+	if cache == nil {
+		return nil, explorerErrors.CacheNotInitialized()
+	}
+
+	b := &Bundle{
+		Version:    BundleVersion,
+		Cluster:    k8s.GetClusterName(),
+		Context:    k8s.GetContextName(),
+		CapturedAt: time.Now(),
+	}
+	if features := k8s.GetFeatures(); features != nil {
+		b.ServerVersion = features.GitVersion
+	}
+
+	all := labels.Everything()
+	add := func(group, version, resource, kind string, namespaced bool, objs []runtime.Object) {
+		rs := ResourceSet{Group: group, Version: version, Resource: resource, Kind: kind, Namespaced: namespaced}
+		for _, obj := range objs {
+			item, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+			if err != nil {
+				log.Printf("Warning: replay capture skipping %s: %v", kind, err)
+				continue
+			}
+			// Informer objects don't carry TypeMeta
+			item["apiVersion"] = rs.GroupVersion()
+			item["kind"] = kind
+			rs.Items = append(rs.Items, item)
+		}
+		b.Resources = append(b.Resources, rs)
+	}
+
+	if objs, err := cache.Namespaces().List(all); err == nil {
+		add("", "v1", "namespaces", "Namespace", false, toObjects(objs))
+	}
+	if objs, err := cache.Nodes().List(all); err == nil {
+		add("", "v1", "nodes", "Node", false, toObjects(objs))
+	}
+	if objs, err := cache.Pods().List(all); err == nil {
+		add("", "v1", "pods", "Pod", true, toObjects(objs))
+	}
+	if objs, err := cache.Services().List(all); err == nil {
+		add("", "v1", "services", "Service", true, toObjects(objs))
+	}
+	if objs, err := cache.ConfigMaps().List(all); err == nil {
+		add("", "v1", "configmaps", "ConfigMap", true, toObjects(objs))
+	}
+	if objs, err := cache.PersistentVolumeClaims().List(all); err == nil {
+		add("", "v1", "persistentvolumeclaims", "PersistentVolumeClaim", true, toObjects(objs))
+	}
+	if objs, err := cache.ResourceQuotas().List(all); err == nil {
+		add("", "v1", "resourcequotas", "ResourceQuota", true, toObjects(objs))
+	}
+	if objs, err := cache.LimitRanges().List(all); err == nil {
+		add("", "v1", "limitranges", "LimitRange", true, toObjects(objs))
+	}
+	if cache.HasTypedInformer("secrets") {
+		if objs, err := cache.Secrets().List(all); err == nil {
+			add("", "v1", "secrets", "Secret", true, toObjects(objs))
+			redactSecrets(b.Resources[len(b.Resources)-1].Items)
+		}
+	}
+	if objs, err := cache.Deployments().List(all); err == nil {
+		add("apps", "v1", "deployments", "Deployment", true, toObjects(objs))
+	}
+	if objs, err := cache.DaemonSets().List(all); err == nil {
+		add("apps", "v1", "daemonsets", "DaemonSet", true, toObjects(objs))
+	}
+	if objs, err := cache.StatefulSets().List(all); err == nil {
+		add("apps", "v1", "statefulsets", "StatefulSet", true, toObjects(objs))
+	}
+	if objs, err := cache.ReplicaSets().List(all); err == nil {
+		add("apps", "v1", "replicasets", "ReplicaSet", true, toObjects(objs))
+	}
+	if objs, err := cache.Ingresses().List(all); err == nil {
+		add("networking.k8s.io", "v1", "ingresses", "Ingress", true, toObjects(objs))
+	}
+	if objs, err := cache.Jobs().List(all); err == nil {
+		add("batch", "v1", "jobs", "Job", true, toObjects(objs))
+	}
+	if cache.HasTypedInformer("cronjobs") {
+		if objs, err := cache.CronJobs().List(all); err == nil {
+			add("batch", "v1", "cronjobs", "CronJob", true, toObjects(objs))
+		}
+	}
+	if cache.HasTypedInformer("horizontalpodautoscalers") {
+		if objs, err := cache.HorizontalPodAutoscalers().List(all); err == nil {
+			add("autoscaling", "v2", "horizontalpodautoscalers", "HorizontalPodAutoscaler", true, toObjects(objs))
+		}
+	}
+
+	// CRDs and other kinds the dynamic cache is already watching
+	if dyn, disc := k8s.GetDynamicResourceCache(), k8s.GetResourceDiscovery(); dyn != nil && disc != nil {
+		for _, gvr := range dyn.GetWatchedResources() {
+			res, ok := disc.GetResource(gvr.Resource)
+			if !ok {
+				continue
+			}
+			items, err := dyn.List(gvr, "")
+			if err != nil {
+				continue
+			}
+			rs := ResourceSet{Group: gvr.Group, Version: gvr.Version, Resource: gvr.Resource, Kind: res.Kind, Namespaced: res.Namespaced}
+			for _, u := range items {
+				rs.Items = append(rs.Items, u.Object)
+			}
+			b.Resources = append(b.Resources, rs)
+		}
+	}
+
+	events, err := timeline.QueryEvents(ctx, timeline.QueryOptions{
+		Since:            since,
+		Limit:            maxCapturedEvents,
+		IncludeManaged:   true,
+		IncludeK8sEvents: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read timeline: %w", err)
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].Timestamp.Before(events[j].Timestamp) })
+	b.Events = events
+
+	return b, nil
+}
+
+// redactSecrets drops secret values, keeping the keys so references still resolve
+func redactSecrets(items []map[string]any) {
+	for _, item := range items {
+		data, _ := item["data"].(map[string]any)
+		for k := range data {
+			data[k] = ""
+		}
+		delete(item, "stringData")
+	}
+}
+
+func toObjects[T runtime.Object](objs []T) []runtime.Object {
+	out := make([]runtime.Object, len(objs))
+	for i, o := range objs {
+		out[i] = o
+	}
+	return out
+}
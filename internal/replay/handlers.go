@@ -0,0 +1,171 @@
+package replay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+	"github.com/skyhook-io/radar/internal/objectstore"
+)
+
+// Handlers provides HTTP handlers for replay endpoints
+type Handlers struct{}
+
+// NewHandlers creates a new Handlers instance
+func NewHandlers() *Handlers {
+	return &Handlers{}
+}
+
+// RegisterRoutes registers replay routes on the given router
+func (h *Handlers) RegisterRoutes(r chi.Router) {
+	r.Route("/replay", func(r chi.Router) {
+		r.Get("/", h.handleStatus)
+		r.Post("/control", h.handleControl)
+		r.Get("/export", h.handleExport)
+		r.Post("/export", h.handleExportStore)
+	})
+}
+
+// ControlRequest changes playback. Action is play, pause or seek; Speed (if set)
+// applies with any action.
+type ControlRequest struct {
+	Action      string  `json:"action,omitempty"`
+	Speed       float64 `json:"speed,omitempty"`
+	PositionSec float64 `json:"positionSec,omitempty"` // For seek
+}
+
+// handleStatus reports whether Radar is replaying a bundle and the playback position
+func (h *Handlers) handleStatus(w http.ResponseWriter, r *http.Request) {
+	p := GetPlayer()
+	if p == nil {
+		writeJSON(w, PlayerStatus{})
+		return
+	}
+	writeJSON(w, p.Status())
+}
+
+// handleControl plays, pauses, seeks or changes the speed of the replay
+func (h *Handlers) handleControl(w http.ResponseWriter, r *http.Request) {
+	p := GetPlayer()
+	if p == nil {
+		writeError(w, http.StatusConflict, "Radar is not running a replay (start with --replay)")
+		return
+	}
+	var req ControlRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	if req.Speed != 0 {
+		if err := p.SetSpeed(req.Speed); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	switch req.Action {
+	case "":
+	case "play":
+		p.Play()
+	case "pause":
+		p.Pause()
+	case "seek":
+		if err := p.Seek(time.Duration(req.PositionSec * float64(time.Second))); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown action %q (expected play, pause or seek)", req.Action))
+		return
+	}
+	writeJSON(w, p.Status())
+}
+
+// handleExport downloads a replay bundle of the current cluster state and timeline.
+// ?since= limits the timeline to a recent window (Go duration, default 24h).
+func (h *Handlers) handleExport(w http.ResponseWriter, r *http.Request) {
+	b, ok := captureForExport(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, bundleFilename(b)))
+	WriteBundle(w, b)
+}
+
+// handleExportStore writes the same bundle as handleExport to the artifact store
+// (--artifact-store) under replay/, so snapshots taken in the cluster don't have to pass
+// through a browser.
+func (h *Handlers) handleExportStore(w http.ResponseWriter, r *http.Request) {
+	artifacts := objectstore.Artifacts()
+	if artifacts == nil {
+		writeError(w, http.StatusServiceUnavailable, "No artifact store configured (start with --artifact-store)")
+		return
+	}
+	b, ok := captureForExport(w, r)
+	if !ok {
+		return
+	}
+	var buf bytes.Buffer
+	if err := WriteBundle(&buf, b); err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to encode bundle: "+err.Error())
+		return
+	}
+	obj, err := artifacts.Put(r.Context(), "replay/"+bundleFilename(b), buf.Bytes(), "application/gzip")
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	writeJSON(w, obj)
+}
+
+// captureForExport captures a bundle for the ?since= window, writing the error if it fails
+func captureForExport(w http.ResponseWriter, r *http.Request) (*Bundle, bool) {
+	window := 24 * time.Hour
+	if v := r.URL.Query().Get("since"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			writeError(w, http.StatusBadRequest, "invalid since duration: "+v)
+			return nil, false
+		}
+		window = d
+	}
+
+	b, err := Capture(r.Context(), time.Now().Add(-window))
+	if err != nil {
+		explorerErrors.Write(w, err)
+		return nil, false
+	}
+	return b, true
+}
+
+func bundleFilename(b *Bundle) string {
+	name := b.Context
+	if name == "" {
+		name = "cluster"
+	}
+	return fmt.Sprintf("radar-replay-%s-%s.json.gz", sanitizeFilename(name), b.CapturedAt.UTC().Format("20060102-150405"))
+}
+
+func sanitizeFilename(s string) string {
+	out := []byte(s)
+	for i, c := range out {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.') {
+			out[i] = '_'
+		}
+	}
+	return string(out)
+}
+
+func writeJSON(w http.ResponseWriter, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(data)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	explorerErrors.WriteHTTP(w, status, message)
+}
@@ -0,0 +1,211 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/skyhook-io/radar/internal/timeline"
+)
+
+const playbackTick = 250 * time.Millisecond
+
+// PlayerStatus describes the replay and playback position
+type PlayerStatus struct {
+	Active        bool      `json:"active"`
+	Cluster       string    `json:"cluster,omitempty"`
+	CapturedAt    time.Time `json:"capturedAt,omitempty"`
+	Objects       int       `json:"objects"`
+	Playing       bool      `json:"playing"`
+	Finished      bool      `json:"finished"`
+	Speed         float64   `json:"speed"`
+	PositionSec   float64   `json:"positionSec"` // Offset into the recording
+	DurationSec   float64   `json:"durationSec"`
+	EventsPlayed  int       `json:"eventsPlayed"`
+	EventsTotal   int       `json:"eventsTotal"`
+	RecordingFrom time.Time `json:"recordingFrom,omitempty"`
+}
+
+// Player feeds a bundle's timeline into the timeline store on a virtual clock.
+// Played events are re-stamped relative to now (keeping their spacing, compressed
+// by the speed) so they show up in the timeline as recent activity.
+type Player struct {
+	mu       sync.Mutex
+	bundle   *Bundle
+	events   []timeline.TimelineEvent // Oldest first
+	start    time.Time                // Timestamp of the first event
+	duration time.Duration
+	next     int
+	position time.Duration // Virtual time elapsed since start
+	speed    float64
+	playing  bool
+	lastTick time.Time
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+var (
+	player     *Player
+	playerOnce sync.Once
+)
+
+// StartPlayer begins playing the bundle's timeline. Speed is a multiplier of real
+// time; 0 plays the whole recording immediately.
+func StartPlayer(b *Bundle, speed float64) {
+	playerOnce.Do(func() {
+		events := append([]timeline.TimelineEvent(nil), b.Events...)
+		sort.SliceStable(events, func(i, j int) bool { return events[i].Timestamp.Before(events[j].Timestamp) })
+		p := &Player{
+			bundle:   b,
+			events:   events,
+			speed:    speed,
+			playing:  true,
+			lastTick: time.Now(),
+			stopCh:   make(chan struct{}),
+		}
+		if len(events) > 0 {
+			p.start = events[0].Timestamp
+			p.duration = events[len(events)-1].Timestamp.Sub(p.start)
+		}
+		if speed <= 0 {
+			p.speed = 1
+			p.position = p.duration
+		}
+		player = p
+
+		p.wg.Add(1)
+		go p.run()
+		log.Printf("Replaying %d timeline events spanning %s at %gx", len(events), p.duration.Round(time.Second), p.speed)
+	})
+}
+
+// GetPlayer returns the replay player, or nil when not replaying
+func GetPlayer() *Player {
+	return player
+}
+
+// StopPlayer stops playback
+func StopPlayer() {
+	if player != nil {
+		player.stopOnce.Do(func() { close(player.stopCh) })
+		player.wg.Wait()
+	}
+}
+
+func (p *Player) run() {
+	defer p.wg.Done()
+	ticker := time.NewTicker(playbackTick)
+	defer ticker.Stop()
+
+	p.advance()
+	for {
+		select {
+		case <-p.stopCh:
+			return
+		case <-ticker.C:
+			p.advance()
+		}
+	}
+}
+
+// advance moves the virtual clock forward and emits every event it has passed
+func (p *Player) advance() {
+	p.mu.Lock()
+	now := time.Now()
+	if p.playing {
+		p.position += time.Duration(float64(now.Sub(p.lastTick)) * p.speed)
+		if p.position >= p.duration {
+			p.position = p.duration
+		}
+	}
+	p.lastTick = now
+
+	// Shift so the current virtual position lands on now
+	shift := now.Sub(p.start.Add(p.position))
+	var due []timeline.TimelineEvent
+	for p.next < len(p.events) && p.events[p.next].Timestamp.Sub(p.start) <= p.position {
+		ev := p.events[p.next]
+		ev.ID = "replay-" + ev.ID
+		ev.Timestamp = ev.Timestamp.Add(shift)
+		due = append(due, ev)
+		p.next++
+	}
+	if p.next == len(p.events) {
+		p.playing = false
+	}
+	p.mu.Unlock()
+
+	for _, ev := range due {
+		if err := timeline.RecordEventWithBroadcast(context.Background(), ev); err != nil {
+			log.Printf("Warning: replay failed to record event: %v", err)
+			return
+		}
+	}
+}
+
+// Play resumes playback
+func (p *Player) Play() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.next < len(p.events) {
+		p.playing = true
+		p.lastTick = time.Now()
+	}
+}
+
+// Pause pauses playback
+func (p *Player) Pause() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.playing = false
+}
+
+// SetSpeed changes the playback speed multiplier
+func (p *Player) SetSpeed(speed float64) error {
+	if speed <= 0 || speed > 10000 {
+		return fmt.Errorf("speed must be between 0 and 10000")
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.speed = speed
+	return nil
+}
+
+// Seek jumps forward to an offset into the recording, emitting the skipped events at
+// once. Played events stay in the timeline, so seeking backwards is not supported.
+func (p *Player) Seek(offset time.Duration) error {
+	p.mu.Lock()
+	if offset < p.position {
+		p.mu.Unlock()
+		return fmt.Errorf("cannot seek backwards (at %s)", p.position.Round(time.Second))
+	}
+	p.position = min(offset, p.duration)
+	p.mu.Unlock()
+	p.advance()
+	return nil
+}
+
+// Status returns the playback state
+func (p *Player) Status() PlayerStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return PlayerStatus{
+		Active:        true,
+		Cluster:       p.bundle.Cluster,
+		CapturedAt:    p.bundle.CapturedAt,
+		Objects:       p.bundle.ObjectCount(),
+		Playing:       p.playing,
+		Finished:      p.next == len(p.events),
+		Speed:         p.speed,
+		PositionSec:   p.position.Seconds(),
+		DurationSec:   p.duration.Seconds(),
+		EventsPlayed:  p.next,
+		EventsTotal:   len(p.events),
+		RecordingFrom: p.start,
+	}
+}
@@ -0,0 +1,135 @@
+// Package restart orchestrates rolling restarts of related workloads, e.g. the services
+// reading a ConfigMap that just changed. Workloads restart in dependency order, declared
+// with the depends-on annotation; each step must roll out healthy before the next starts,
+// and the run halts at the first failure.
+package restart
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DependsOnAnnotation declares the workloads a workload depends on, as comma-separated
+// [namespace/]kind/name entries, e.g. "statefulset/postgres, deployment/auth". Entries
+// without a namespace are in the workload's own namespace.
+const DependsOnAnnotation = "radar.skyhook.io/depends-on"
+
+// Target is a workload to restart
+type Target struct {
+	Kind      string `json:"kind"` // Deployment, StatefulSet or DaemonSet
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+func (t Target) String() string {
+	return t.Kind + "/" + t.Namespace + "/" + t.Name
+}
+
+// NormalizeKind accepts singular, plural and Kind forms ("deployments", "Deployment") and
+// returns the Kind, or "" when the kind can't be restarted
+func NormalizeKind(kind string) string {
+	switch strings.ToLower(kind) {
+	case "deployment", "deployments", "deploy":
+		return "Deployment"
+	case "statefulset", "statefulsets", "sts":
+		return "StatefulSet"
+	case "daemonset", "daemonsets", "ds":
+		return "DaemonSet"
+	}
+	return ""
+}
+
+// ParseDependsOn parses a depends-on annotation value
+func ParseDependsOn(value, namespace string) ([]Target, error) {
+	var targets []Target
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, "/")
+		t := Target{Namespace: namespace}
+		switch len(parts) {
+		case 2:
+			t.Kind, t.Name = parts[0], parts[1]
+		case 3:
+			t.Namespace, t.Kind, t.Name = parts[0], parts[1], parts[2]
+		default:
+			return nil, fmt.Errorf("invalid dependency %q (use [namespace/]kind/name)", entry)
+		}
+		kind := NormalizeKind(t.Kind)
+		if kind == "" {
+			return nil, fmt.Errorf("invalid dependency %q: %s can't be restarted", entry, t.Kind)
+		}
+		t.Kind = kind
+		if t.Name == "" || t.Namespace == "" {
+			return nil, fmt.Errorf("invalid dependency %q (use [namespace/]kind/name)", entry)
+		}
+		targets = append(targets, t)
+	}
+	return targets, nil
+}
+
+// Plan orders targets into steps so that every target comes after the targets it depends
+// on. deps maps a target to its dependencies; dependencies that aren't being restarted
+// don't affect the order. Targets within a step don't depend on each other and restart
+// together. Returns an error naming the workloads involved if the dependencies form a cycle.
+func Plan(targets []Target, deps map[Target][]Target) ([][]Target, error) {
+	selected := make(map[Target]bool, len(targets))
+	for _, t := range targets {
+		selected[t] = true
+	}
+	remaining := make(map[Target]int, len(selected)) // Unrestarted dependencies
+	dependents := make(map[Target][]Target)
+	for t := range selected {
+		seen := make(map[Target]bool)
+		for _, d := range deps[t] {
+			if !selected[d] || d == t || seen[d] {
+				continue
+			}
+			seen[d] = true
+			remaining[t]++
+			dependents[d] = append(dependents[d], t)
+		}
+	}
+
+	var steps [][]Target
+	var ready []Target
+	for t := range selected {
+		if remaining[t] == 0 {
+			ready = append(ready, t)
+		}
+	}
+	planned := 0
+	for len(ready) > 0 {
+		sortTargets(ready)
+		steps = append(steps, ready)
+		planned += len(ready)
+		var next []Target
+		for _, t := range ready {
+			for _, d := range dependents[t] {
+				if remaining[d]--; remaining[d] == 0 {
+					next = append(next, d)
+				}
+			}
+		}
+		ready = next
+	}
+
+	if planned < len(selected) {
+		var cycle []string
+		for t := range selected {
+			if remaining[t] > 0 {
+				cycle = append(cycle, t.String())
+			}
+		}
+		sort.Strings(cycle)
+		return nil, fmt.Errorf("dependency cycle between %s", strings.Join(cycle, ", "))
+	}
+	return steps, nil
+}
+
+func sortTargets(targets []Target) {
+	sort.Slice(targets, func(i, j int) bool { return targets[i].String() < targets[j].String() })
+}
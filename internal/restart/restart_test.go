@@ -0,0 +1,134 @@
+package restart
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func dep(name string) Target { return Target{Kind: "Deployment", Namespace: "shop", Name: name} }
+
+func TestParseDependsOn(t *testing.T) {
+	got, err := ParseDependsOn("statefulset/postgres, deployments/auth,, infra/ds/node-agent", "shop")
+	if err != nil {
+		t.Fatalf("ParseDependsOn: %v", err)
+	}
+	want := []Target{
+		{Kind: "StatefulSet", Namespace: "shop", Name: "postgres"},
+		{Kind: "Deployment", Namespace: "shop", Name: "auth"},
+		{Kind: "DaemonSet", Namespace: "infra", Name: "node-agent"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseDependsOn = %v, want %v", got, want)
+	}
+	for _, bad := range []string{"postgres", "service/api", "a/b/c/d", "deployment/"} {
+		if _, err := ParseDependsOn(bad, "shop"); err == nil {
+			t.Errorf("ParseDependsOn(%q): expected error", bad)
+		}
+	}
+}
+
+func TestPlan(t *testing.T) {
+	targets := []Target{dep("web"), dep("api"), dep("auth"), dep("worker")}
+	deps := map[Target][]Target{
+		dep("web"):    {dep("api")},
+		dep("api"):    {dep("auth"), dep("db")}, // db isn't restarted, so it doesn't order anything
+		dep("worker"): {dep("auth")},
+	}
+	steps, err := Plan(targets, deps)
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	want := [][]Target{{dep("auth")}, {dep("api"), dep("worker")}, {dep("web")}}
+	if !reflect.DeepEqual(steps, want) {
+		t.Errorf("Plan = %v, want %v", steps, want)
+	}
+
+	deps[dep("auth")] = []Target{dep("web")}
+	if _, err := Plan(targets, deps); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("expected a cycle error, got %v", err)
+	}
+}
+
+// fakeWorkloads rolls a workload out after a number of status polls
+type fakeWorkloads struct {
+	polls     map[Target]int // Polls until healthy (-1 = fails)
+	restarted []Target
+}
+
+func (f *fakeWorkloads) Restart(ctx context.Context, t Target) (int64, error) {
+	if t.Name == "broken" {
+		return 0, errors.New("forbidden")
+	}
+	f.restarted = append(f.restarted, t)
+	return 2, nil
+}
+
+func (f *fakeWorkloads) Status(t Target, generation int64) (RolloutStatus, error) {
+	switch n := f.polls[t]; {
+	case n < 0:
+		return RolloutStatus{Failed: true, Message: "progress deadline exceeded"}, nil
+	case n == 0:
+		return RolloutStatus{Done: true}, nil
+	}
+	f.polls[t]--
+	return RolloutStatus{Message: "1 of 2 updated replicas available"}, nil
+}
+
+func collect(events *[]Event) func(Event) error {
+	return func(e Event) error {
+		*events = append(*events, e)
+		return nil
+	}
+}
+
+func TestRun(t *testing.T) {
+	opts := Options{PollInterval: time.Millisecond, StepTimeout: time.Second}
+	steps := [][]Target{{dep("auth")}, {dep("api"), dep("worker")}, {dep("web")}}
+
+	w := &fakeWorkloads{polls: map[Target]int{dep("auth"): 2, dep("api"): 1}}
+	var events []Event
+	if err := Run(context.Background(), w, steps, opts, collect(&events)); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if last := events[len(events)-1]; last.Type != EventDone || !last.Completed {
+		t.Errorf("last event = %+v, want completed", last)
+	}
+	if len(w.restarted) != 4 || w.restarted[0] != dep("auth") || w.restarted[3] != dep("web") {
+		t.Errorf("restart order = %v", w.restarted)
+	}
+
+	// A failed rollout halts before later steps
+	w = &fakeWorkloads{polls: map[Target]int{dep("api"): -1}}
+	events = nil
+	if err := Run(context.Background(), w, steps, opts, collect(&events)); err == nil {
+		t.Fatal("expected the run to halt")
+	}
+	for _, r := range w.restarted {
+		if r == dep("web") {
+			t.Error("step after the failed one was restarted")
+		}
+	}
+	if last := events[len(events)-1]; last.Type != EventDone || last.Completed {
+		t.Errorf("last event = %+v, want halted", last)
+	}
+
+	// So does a step that doesn't become healthy in time
+	w = &fakeWorkloads{polls: map[Target]int{dep("auth"): 1 << 30}}
+	opts.StepTimeout = 20 * time.Millisecond
+	if err := Run(context.Background(), w, steps, opts, collect(&events)); err == nil || !strings.Contains(err.Error(), "not healthy") {
+		t.Errorf("expected a timeout, got %v", err)
+	}
+
+	// The caller's deadline isn't reported as the step timing out
+	w = &fakeWorkloads{polls: map[Target]int{dep("auth"): 1 << 30}}
+	opts.StepTimeout = time.Hour
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := Run(ctx, w, steps, opts, collect(&events)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the caller's deadline, got %v", err)
+	}
+}
@@ -0,0 +1,150 @@
+package restart
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultStepTimeout is how long a step may take to roll out before the run halts
+	DefaultStepTimeout = 10 * time.Minute
+	// defaultPollInterval is how often rollout status is checked
+	defaultPollInterval = 2 * time.Second
+)
+
+// Event types streamed while a run progresses
+const (
+	EventPlan        = "plan"         // Steps: the full plan
+	EventStepStarted = "step_started" // Step: targets being restarted
+	EventRestarted   = "restarted"    // Target: restart requested
+	EventHealthy     = "healthy"      // Target: rolled out and healthy
+	EventFailed      = "failed"       // Target: restart or rollout failed (the run halts)
+	EventDone        = "done"         // Completed or halted
+)
+
+// Event reports run progress
+type Event struct {
+	Type      string     `json:"type"`
+	Step      int        `json:"step,omitempty"` // 1-based
+	Target    *Target    `json:"target,omitempty"`
+	Steps     [][]Target `json:"steps,omitempty"`
+	Message   string     `json:"message,omitempty"`
+	Completed bool       `json:"completed,omitempty"` // Done: every step succeeded
+}
+
+// RolloutStatus is the progress of a restarted workload
+type RolloutStatus struct {
+	Done    bool   // Every replica runs the restarted template and is available
+	Failed  bool   // The rollout can't finish (e.g. progress deadline exceeded)
+	Message string // Progress or failure detail, e.g. "2 of 3 updated replicas available"
+}
+
+// errStepTimedOut is the cause of a step's context ending at Options.StepTimeout
+var errStepTimedOut = errors.New("step timed out")
+
+// Workloads restarts workloads and reports their rollout status
+type Workloads interface {
+	// Restart triggers a rolling restart and returns the generation that carries it
+	Restart(ctx context.Context, t Target) (int64, error)
+	// Status reports whether the rollout of the given generation has finished
+	Status(t Target, generation int64) (RolloutStatus, error)
+}
+
+// Options tune a run
+type Options struct {
+	StepTimeout  time.Duration // Default DefaultStepTimeout
+	PollInterval time.Duration // Default 2s
+}
+
+// Run restarts the steps in order. A step's targets restart together, and the next step
+// starts once all of them rolled out healthy. The run halts at the first failure or
+// timeout, leaving later steps untouched. emit receives progress; an emit error (the
+// client went away) stops the run without restarting anything further.
+func Run(ctx context.Context, w Workloads, steps [][]Target, opts Options, emit func(Event) error) error {
+	if opts.StepTimeout <= 0 {
+		opts.StepTimeout = DefaultStepTimeout
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaultPollInterval
+	}
+	if err := emit(Event{Type: EventPlan, Steps: steps}); err != nil {
+		return err
+	}
+
+	for i, step := range steps {
+		n := i + 1
+		if err := emit(Event{Type: EventStepStarted, Step: n, Message: fmt.Sprintf("restarting %d workloads", len(step))}); err != nil {
+			return err
+		}
+		if err := runStep(ctx, w, n, step, opts, emit); err != nil {
+			emit(Event{Type: EventDone, Message: fmt.Sprintf("halted at step %d of %d: %v", n, len(steps), err)})
+			return err
+		}
+	}
+	return emit(Event{Type: EventDone, Completed: true, Message: fmt.Sprintf("%d steps completed", len(steps))})
+}
+
+// runStep restarts a step's targets and waits for all of them to roll out
+func runStep(ctx context.Context, w Workloads, step int, targets []Target, opts Options, emit func(Event) error) error {
+	generations := make(map[Target]int64, len(targets))
+	for _, t := range targets {
+		gen, err := w.Restart(ctx, t)
+		if err != nil {
+			emit(Event{Type: EventFailed, Step: step, Target: &t, Message: err.Error()})
+			return fmt.Errorf("restarting %s: %w", t, err)
+		}
+		generations[t] = gen
+		if err := emit(Event{Type: EventRestarted, Step: step, Target: &t}); err != nil {
+			return err
+		}
+	}
+
+	// The cause tells the step's own timeout from the caller's context ending
+	ctx, cancel := context.WithTimeoutCause(ctx, opts.StepTimeout, errStepTimedOut)
+	defer cancel()
+	ticker := time.NewTicker(opts.PollInterval)
+	defer ticker.Stop()
+
+	pending := append([]Target(nil), targets...)
+	last := make(map[Target]string) // Latest progress message, for timeouts
+	for {
+		var still []Target
+		for _, t := range pending {
+			status, err := w.Status(t, generations[t])
+			switch {
+			case err != nil:
+				emit(Event{Type: EventFailed, Step: step, Target: &t, Message: err.Error()})
+				return fmt.Errorf("%s: %w", t, err)
+			case status.Failed:
+				emit(Event{Type: EventFailed, Step: step, Target: &t, Message: status.Message})
+				return fmt.Errorf("%s: %s", t, status.Message)
+			case status.Done:
+				if err := emit(Event{Type: EventHealthy, Step: step, Target: &t}); err != nil {
+					return err
+				}
+			default:
+				last[t] = status.Message
+				still = append(still, t)
+			}
+		}
+		pending = still
+		if len(pending) == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if context.Cause(ctx) == errStepTimedOut {
+				for _, t := range pending {
+					emit(Event{Type: EventFailed, Step: step, Target: &t,
+						Message: fmt.Sprintf("not healthy after %s: %s", opts.StepTimeout, last[t])})
+				}
+				return fmt.Errorf("step %d not healthy after %s", step, opts.StepTimeout)
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package rightsizing
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	corev1 "k8s.io/api/core/v1"
+
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// Default lookback: the in-memory hour, or a week when history is kept on disk (the
+// VPA recommender looks back 8 days)
+const (
+	defaultMemoryWindow     = time.Hour
+	defaultPersistentWindow = 7 * 24 * time.Hour
+)
+
+// Handlers provides HTTP handlers for resource recommendations
+type Handlers struct{}
+
+// NewHandlers creates a new Handlers instance
+func NewHandlers() *Handlers {
+	return &Handlers{}
+}
+
+// RegisterRoutes registers rightsizing routes on the given router
+func (h *Handlers) RegisterRoutes(r chi.Router) {
+	r.Get("/rightsizing", h.handleGetRecommendations)
+}
+
+// handleGetRecommendations compares each workload's container usage with its requests
+// and limits and suggests requests. ?namespace= (comma-separated) limits workloads,
+// ?window= (Go duration) sets the lookback (beyond the in-memory hour only with
+// persistent metrics storage), and ?status= (comma-separated) keeps workloads with
+// those statuses.
+func (h *Handlers) handleGetRecommendations(w http.ResponseWriter, r *http.Request) {
+	store := k8s.GetMetricsHistory()
+	if store == nil {
+		writeError(w, http.StatusServiceUnavailable, "Metrics history not running")
+		return
+	}
+	cache := k8s.GetResourceCache()
+	if cache == nil {
+		explorerErrors.Write(w, explorerErrors.CacheNotInitialized())
+		return
+	}
+
+	window := defaultMemoryWindow
+	if store.Persistent() {
+		window = defaultPersistentWindow
+	}
+	if v := r.URL.Query().Get("window"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			writeError(w, http.StatusBadRequest, "window must be a positive Go duration (e.g. 24h)")
+			return
+		}
+		window = d
+	}
+	if !store.Persistent() && window > defaultMemoryWindow {
+		// Only the last hour is kept in memory
+		window = defaultMemoryWindow
+	}
+	statuses := map[Status]bool{}
+	for _, s := range strings.Split(r.URL.Query().Get("status"), ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		if _, ok := statusRank[Status(s)]; !ok {
+			writeError(w, http.StatusBadRequest, "status must be ok, over-provisioned, under-provisioned, no-request or insufficient-data")
+			return
+		}
+		statuses[Status(s)] = true
+	}
+
+	pods, err := cache.PodsMatching(k8s.PodFilter{})
+	if err != nil {
+		explorerErrors.Write(w, err)
+		return
+	}
+	if v := r.URL.Query().Get("namespace"); v != "" {
+		namespaces := map[string]bool{}
+		for _, ns := range strings.Split(v, ",") {
+			namespaces[strings.TrimSpace(ns)] = true
+		}
+		scoped := pods[:0]
+		for _, pod := range pods {
+			if namespaces[pod.Namespace] {
+				scoped = append(scoped, pod)
+			}
+		}
+		pods = scoped
+	}
+	now := time.Now()
+	history := store.GetPodMetricsHistory
+	if store.Persistent() && window > defaultMemoryWindow {
+		history = func(namespace, name string) *k8s.PodMetricsHistory {
+			h, err := store.QueryPodMetricsHistory(r.Context(), namespace, name, now.Add(-window))
+			if err != nil {
+				return store.GetPodMetricsHistory(namespace, name)
+			}
+			return h
+		}
+	}
+
+	report := Compute(Input{
+		Pods:    pods,
+		History: func(pod *corev1.Pod) *k8s.PodMetricsHistory { return history(pod.Namespace, pod.Name) },
+		Owner:   cache.PodWorkload,
+		Window:  window,
+		Now:     now,
+	})
+	if len(statuses) > 0 {
+		kept := make([]Workload, 0, len(report.Workloads))
+		for _, wl := range report.Workloads {
+			if statuses[wl.Status] {
+				kept = append(kept, wl)
+			}
+		}
+		report.Workloads = kept
+	}
+	writeJSON(w, report)
+}
+
+func writeJSON(w http.ResponseWriter, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(data)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	explorerErrors.WriteHTTP(w, status, message)
+}
@@ -0,0 +1,341 @@
+// Package rightsizing recommends container CPU and memory requests from the metrics
+// history, like the Vertical Pod Autoscaler's recommender but read-only: nothing is
+// changed, workloads whose requests are far from their usage are flagged.
+package rightsizing
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// Status is how a container's (or workload's) requests compare with its usage
+type Status string
+
+const (
+	StatusOK               Status = "ok"
+	StatusOverProvisioned  Status = "over-provisioned"  // Requests well above usage
+	StatusUnderProvisioned Status = "under-provisioned" // Usage above requests, or close to limits
+	StatusNoRequest        Status = "no-request"        // No request set, so nothing is reserved
+	StatusInsufficientData Status = "insufficient-data" // Too few samples to recommend
+)
+
+const (
+	// MinSamples is how many samples a container needs before it's assessed (5 minutes
+	// at the 30s polling interval)
+	MinSamples = 10
+
+	// Headroom added to observed usage: CPU is sized for its 95th percentile, memory for
+	// its peak since running out of memory kills the container
+	cpuMargin    = 0.15
+	memoryMargin = 0.15
+
+	// Smallest suggestions, and what they're rounded up to
+	minCPUMillis   = 10
+	cpuStepMillis  = 5
+	minMemoryBytes = 16 << 20
+	memoryStep     = 1 << 20
+
+	// Requests at least overFactor times the suggestion, and that much above it, are
+	// over-provisioned; the absolute slack keeps tiny containers from being flagged
+	overFactor        = 2
+	overSlackMillis   = 50
+	overSlackBytes    = 64 << 20
+	nearLimitFraction = 0.9
+)
+
+// Usage is one resource's observed usage next to its request and limit. CPU is in
+// millicores, memory in bytes.
+type Usage struct {
+	P50       int64  `json:"p50"`
+	P95       int64  `json:"p95"`
+	Max       int64  `json:"max"`
+	Request   *int64 `json:"request,omitempty"`
+	Limit     *int64 `json:"limit,omitempty"`
+	Suggested *int64 `json:"suggestedRequest,omitempty"`
+	// SuggestedQuantity is the suggestion as a Kubernetes quantity (e.g. 250m, 384Mi)
+	SuggestedQuantity string `json:"suggestedQuantity,omitempty"`
+	Status            Status `json:"status"`
+	Message           string `json:"message,omitempty"`
+}
+
+// Container is the recommendation for one container of a workload, across its pods
+type Container struct {
+	Name    string `json:"name"`
+	Samples int    `json:"samples"`
+	CPU     Usage  `json:"cpu"`
+	Memory  Usage  `json:"memory"`
+	Status  Status `json:"status"`
+}
+
+// Workload is the recommendation for one workload's containers
+type Workload struct {
+	Kind       string      `json:"kind"`
+	Namespace  string      `json:"namespace"`
+	Name       string      `json:"name"`
+	Pods       int         `json:"pods"`
+	Containers []Container `json:"containers"`
+	Status     Status      `json:"status"`
+	// Requested beyond the suggestions across all pods, for over-provisioned containers
+	ExcessCPUMillis   int64 `json:"excessCpuMillis,omitempty"`
+	ExcessMemoryBytes int64 `json:"excessMemoryBytes,omitempty"`
+}
+
+// Report lists recommendations per workload
+type Report struct {
+	GeneratedAt time.Time  `json:"generatedAt"`
+	Window      string     `json:"window"`               // How far back usage was read
+	Resolution  string     `json:"resolution,omitempty"` // Rollup width of the samples ("raw", "5m", "1h")
+	Workloads   []Workload `json:"workloads"`
+}
+
+// Input is what recommendations are computed from
+type Input struct {
+	Pods []*corev1.Pod
+	// History returns a pod's usage history (nil = no metrics)
+	History func(pod *corev1.Pod) *k8s.PodMetricsHistory
+	// Owner resolves a pod to its top-level workload (nil or empty = the pod's controller)
+	Owner  func(pod *corev1.Pod) (kind, name string)
+	Window time.Duration
+	Now    time.Time
+}
+
+// containerSeries collects one workload container's samples across its pods
+type containerSeries struct {
+	cpu, memory       []int64 // Millicores, bytes
+	cpuMax, memoryMax int64
+	spec              *corev1.Container // From the newest pod
+	specAt            time.Time
+}
+
+type workloadSeries struct {
+	kind, namespace, name string
+	pods                  int
+	containers            map[string]*containerSeries
+}
+
+// Compute aggregates each workload's container usage and compares it with the requests
+// and limits of its newest pod
+func Compute(in Input) *Report {
+	report := &Report{GeneratedAt: in.Now, Window: in.Window.String(), Workloads: []Workload{}}
+
+	workloads := make(map[string]*workloadSeries)
+	for _, pod := range in.Pods {
+		var history *k8s.PodMetricsHistory
+		if in.History != nil {
+			history = in.History(pod)
+		}
+		if history == nil || len(history.Containers) == 0 {
+			continue
+		}
+		if history.Resolution != "" {
+			report.Resolution = history.Resolution
+		}
+		kind, name := podOwner(pod, in.Owner)
+		key := pod.Namespace + "/" + kind + "/" + name
+		ws := workloads[key]
+		if ws == nil {
+			ws = &workloadSeries{kind: kind, namespace: pod.Namespace, name: name, containers: make(map[string]*containerSeries)}
+			workloads[key] = ws
+		}
+		ws.pods++
+		for _, ch := range history.Containers {
+			cs := ws.containers[ch.Name]
+			if cs == nil {
+				cs = &containerSeries{}
+				ws.containers[ch.Name] = cs
+			}
+			for _, dp := range ch.DataPoints {
+				cpu, mem := dp.CPU/1e6, dp.Memory // Nanocores to millicores
+				cs.cpu = append(cs.cpu, cpu)
+				cs.memory = append(cs.memory, mem)
+				cs.cpuMax = max(cs.cpuMax, cpu, dp.CPUMax/1e6)
+				cs.memoryMax = max(cs.memoryMax, mem, dp.MemoryMax)
+			}
+			if spec := containerSpec(pod, ch.Name); spec != nil && !pod.CreationTimestamp.Time.Before(cs.specAt) {
+				cs.spec, cs.specAt = spec, pod.CreationTimestamp.Time
+			}
+		}
+	}
+
+	for _, ws := range workloads {
+		w := Workload{Kind: ws.kind, Namespace: ws.namespace, Name: ws.name, Pods: ws.pods, Containers: make([]Container, 0, len(ws.containers))}
+		for name, cs := range ws.containers {
+			c := assessContainer(name, cs)
+			w.Containers = append(w.Containers, c)
+			if c.CPU.Status == StatusOverProvisioned {
+				w.ExcessCPUMillis += (*c.CPU.Request - *c.CPU.Suggested) * int64(ws.pods)
+			}
+			if c.Memory.Status == StatusOverProvisioned {
+				w.ExcessMemoryBytes += (*c.Memory.Request - *c.Memory.Suggested) * int64(ws.pods)
+			}
+		}
+		sort.Slice(w.Containers, func(i, j int) bool { return w.Containers[i].Name < w.Containers[j].Name })
+		statuses := make([]Status, len(w.Containers))
+		for i, c := range w.Containers {
+			statuses[i] = c.Status
+		}
+		w.Status = worst(statuses...)
+		report.Workloads = append(report.Workloads, w)
+	}
+	sort.Slice(report.Workloads, func(i, j int) bool {
+		a, b := report.Workloads[i], report.Workloads[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+	return report
+}
+
+func assessContainer(name string, cs *containerSeries) Container {
+	c := Container{Name: name, Samples: len(cs.cpu)}
+	var requests, limits corev1.ResourceList
+	if cs.spec != nil {
+		requests, limits = cs.spec.Resources.Requests, cs.spec.Resources.Limits
+	}
+	c.CPU = usageOf(cs.cpu, cs.cpuMax)
+	c.Memory = usageOf(cs.memory, cs.memoryMax)
+	c.CPU.Request, c.CPU.Limit = milliValue(requests, corev1.ResourceCPU), milliValue(limits, corev1.ResourceCPU)
+	c.Memory.Request, c.Memory.Limit = byteValue(requests, corev1.ResourceMemory), byteValue(limits, corev1.ResourceMemory)
+
+	if c.Samples < MinSamples {
+		c.CPU.Status, c.Memory.Status = StatusInsufficientData, StatusInsufficientData
+		c.Status = StatusInsufficientData
+		return c
+	}
+	cpuSuggested := roundUp(max(int64(math.Ceil(float64(c.CPU.P95)*(1+cpuMargin))), minCPUMillis), cpuStepMillis)
+	memSuggested := roundUp(max(int64(math.Ceil(float64(c.Memory.Max)*(1+memoryMargin))), minMemoryBytes), memoryStep)
+	c.CPU.Suggested, c.CPU.SuggestedQuantity = &cpuSuggested, resource.NewMilliQuantity(cpuSuggested, resource.DecimalSI).String()
+	c.Memory.Suggested, c.Memory.SuggestedQuantity = &memSuggested, resource.NewQuantity(memSuggested, resource.BinarySI).String()
+
+	assess(&c.CPU, c.CPU.P95, overSlackMillis, formatMillis, "95th percentile", "throttled")
+	assess(&c.Memory, c.Memory.Max, overSlackBytes, formatBytes, "peak", "OOMKilled")
+	c.Status = worst(c.CPU.Status, c.Memory.Status)
+	return c
+}
+
+// assess compares the usage that sizes a resource (p95 for CPU, the peak for memory)
+// with its request and limit
+func assess(u *Usage, sized, slack int64, format func(int64) string, what, limitRisk string) {
+	switch {
+	case u.Limit != nil && *u.Limit > 0 && float64(sized) >= nearLimitFraction*float64(*u.Limit):
+		u.Status = StatusUnderProvisioned
+		u.Message = fmt.Sprintf("%s usage %s is %.0f%% of the %s limit; at risk of being %s",
+			what, format(sized), 100*float64(sized)/float64(*u.Limit), format(*u.Limit), limitRisk)
+	case u.Request == nil || *u.Request == 0:
+		u.Status = StatusNoRequest
+		u.Message = fmt.Sprintf("no request set; request %s", u.SuggestedQuantity)
+	case sized > *u.Request:
+		u.Status = StatusUnderProvisioned
+		u.Message = fmt.Sprintf("%s usage %s exceeds the %s request; raise it to %s", what, format(sized), format(*u.Request), u.SuggestedQuantity)
+	case *u.Request >= overFactor**u.Suggested && *u.Request-*u.Suggested >= slack:
+		u.Status = StatusOverProvisioned
+		u.Message = fmt.Sprintf("%s usage %s is far below the %s request; lower it to %s", what, format(sized), format(*u.Request), u.SuggestedQuantity)
+	default:
+		u.Status = StatusOK
+	}
+}
+
+// usageOf summarizes samples; max also counts the peaks behind rolled-up samples
+func usageOf(samples []int64, peak int64) Usage {
+	if len(samples) == 0 {
+		return Usage{}
+	}
+	sorted := append([]int64(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return Usage{P50: percentile(sorted, 0.5), P95: percentile(sorted, 0.95), Max: max(sorted[len(sorted)-1], peak)}
+}
+
+// percentile returns the nearest-rank percentile of sorted samples
+func percentile(sorted []int64, p float64) int64 {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[max(rank, 0)]
+}
+
+// statusRank orders statuses from least to most in need of attention
+var statusRank = map[Status]int{
+	StatusInsufficientData: 0,
+	StatusOK:               1,
+	StatusOverProvisioned:  2,
+	StatusNoRequest:        3,
+	StatusUnderProvisioned: 4,
+}
+
+func worst(statuses ...Status) Status {
+	out := StatusInsufficientData
+	for _, s := range statuses {
+		if statusRank[s] > statusRank[out] {
+			out = s
+		}
+	}
+	return out
+}
+
+func containerSpec(pod *corev1.Pod, name string) *corev1.Container {
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == name {
+			return &pod.Spec.Containers[i]
+		}
+	}
+	// Sidecars run as restartable init containers
+	for i := range pod.Spec.InitContainers {
+		if pod.Spec.InitContainers[i].Name == name {
+			return &pod.Spec.InitContainers[i]
+		}
+	}
+	return nil
+}
+
+// podOwner returns the workload a pod's usage is attributed to
+func podOwner(pod *corev1.Pod, owner func(*corev1.Pod) (string, string)) (string, string) {
+	if owner != nil {
+		if kind, name := owner(pod); kind != "" {
+			return kind, name
+		}
+	}
+	if ref := metav1.GetControllerOf(pod); ref != nil {
+		return ref.Kind, ref.Name
+	}
+	return "Pod", pod.Name
+}
+
+func milliValue(list corev1.ResourceList, name corev1.ResourceName) *int64 {
+	q, ok := list[name]
+	if !ok {
+		return nil
+	}
+	v := q.MilliValue()
+	return &v
+}
+
+func byteValue(list corev1.ResourceList, name corev1.ResourceName) *int64 {
+	q, ok := list[name]
+	if !ok {
+		return nil
+	}
+	v := q.Value()
+	return &v
+}
+
+func roundUp(v, step int64) int64 {
+	return (v + step - 1) / step * step
+}
+
+func formatMillis(v int64) string {
+	return resource.NewMilliQuantity(v, resource.DecimalSI).String()
+}
+
+func formatBytes(v int64) string {
+	return fmt.Sprintf("%.0fMi", float64(v)/(1<<20))
+}
@@ -0,0 +1,167 @@
+package rightsizing
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+var now = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func pod(name, owner string, created time.Time, containers ...corev1.Container) *corev1.Pod {
+	p := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: name, CreationTimestamp: metav1.NewTime(created)},
+		Spec:       corev1.PodSpec{Containers: containers},
+	}
+	if owner != "" {
+		isController := true
+		p.OwnerReferences = []metav1.OwnerReference{{Kind: "ReplicaSet", Name: owner, Controller: &isController}}
+	}
+	return p
+}
+
+func container(name string, requests, limits map[corev1.ResourceName]string) corev1.Container {
+	c := corev1.Container{Name: name}
+	if requests != nil {
+		c.Resources.Requests = corev1.ResourceList{}
+		for k, v := range requests {
+			c.Resources.Requests[k] = resource.MustParse(v)
+		}
+	}
+	if limits != nil {
+		c.Resources.Limits = corev1.ResourceList{}
+		for k, v := range limits {
+			c.Resources.Limits[k] = resource.MustParse(v)
+		}
+	}
+	return c
+}
+
+// samples returns n points with CPU in millicores and memory in MiB from the given func
+func samples(n int, f func(i int) (cpuMillis, memMi int64)) []k8s.MetricsDataPoint {
+	points := make([]k8s.MetricsDataPoint, n)
+	for i := range points {
+		cpu, mem := f(i)
+		points[i] = k8s.MetricsDataPoint{Timestamp: now.Add(time.Duration(i-n) * 30 * time.Second), CPU: cpu * 1e6, Memory: mem << 20}
+	}
+	return points
+}
+
+func compute(pods []*corev1.Pod, histories map[string][]k8s.ContainerMetricsHistory) *Report {
+	return Compute(Input{
+		Pods: pods,
+		History: func(p *corev1.Pod) *k8s.PodMetricsHistory {
+			if c, ok := histories[p.Name]; ok {
+				return &k8s.PodMetricsHistory{Namespace: p.Namespace, Name: p.Name, Containers: c}
+			}
+			return nil
+		},
+		Window: time.Hour,
+		Now:    now,
+	})
+}
+
+func TestComputePercentilesAcrossPods(t *testing.T) {
+	spec := container("app", map[corev1.ResourceName]string{"cpu": "1", "memory": "1Gi"}, nil)
+	pods := []*corev1.Pod{pod("web-1", "web-abc", now.Add(-2*time.Hour), spec), pod("web-2", "web-abc", now.Add(-time.Hour), spec)}
+	// 1..100m across both pods, memory 100..199Mi
+	report := compute(pods, map[string][]k8s.ContainerMetricsHistory{
+		"web-1": {{Name: "app", DataPoints: samples(50, func(i int) (int64, int64) { return int64(i + 1), int64(100 + i) })}},
+		"web-2": {{Name: "app", DataPoints: samples(50, func(i int) (int64, int64) { return int64(i + 51), int64(150 + i) })}},
+	})
+
+	if len(report.Workloads) != 1 {
+		t.Fatalf("expected one workload, got %+v", report.Workloads)
+	}
+	w := report.Workloads[0]
+	if w.Kind != "ReplicaSet" || w.Name != "web-abc" || w.Pods != 2 || len(w.Containers) != 1 {
+		t.Fatalf("unexpected workload %+v", w)
+	}
+	c := w.Containers[0]
+	if c.Samples != 100 || c.CPU.P50 != 50 || c.CPU.P95 != 95 || c.CPU.Max != 100 {
+		t.Errorf("CPU = %+v with %d samples, want p50 50, p95 95, max 100 over 100", c.CPU, c.Samples)
+	}
+	if c.Memory.Max != 199<<20 {
+		t.Errorf("memory max = %d, want 199Mi", c.Memory.Max)
+	}
+	// p95 95m + 15% = 109.25m, rounded up to 110m; peak 199Mi + 15% rounded up to 229Mi
+	if *c.CPU.Suggested != 110 || c.CPU.SuggestedQuantity != "110m" {
+		t.Errorf("suggested CPU = %d (%s), want 110m", *c.CPU.Suggested, c.CPU.SuggestedQuantity)
+	}
+	if c.Memory.SuggestedQuantity != "229Mi" {
+		t.Errorf("suggested memory = %s, want 229Mi", c.Memory.SuggestedQuantity)
+	}
+	if c.CPU.Status != StatusOverProvisioned || c.Memory.Status != StatusOverProvisioned || w.Status != StatusOverProvisioned {
+		t.Errorf("statuses cpu=%s memory=%s workload=%s, want over-provisioned", c.CPU.Status, c.Memory.Status, w.Status)
+	}
+	if w.ExcessCPUMillis != 2*(1000-110) {
+		t.Errorf("excess CPU = %d, want %d", w.ExcessCPUMillis, 2*(1000-110))
+	}
+}
+
+func TestComputeFlagsUnderProvisioning(t *testing.T) {
+	pods := []*corev1.Pod{
+		// Usage above the CPU request
+		pod("api-1", "api-abc", now, container("app", map[corev1.ResourceName]string{"cpu": "100m", "memory": "512Mi"}, nil)),
+		// Memory peaking near its limit
+		pod("cache-1", "cache-abc", now, container("redis", map[corev1.ResourceName]string{"cpu": "200m", "memory": "256Mi"}, map[corev1.ResourceName]string{"memory": "256Mi"})),
+		// No requests at all
+		pod("bare", "", now, container("app", nil, nil)),
+		// Too new to judge
+		pod("fresh-1", "fresh-abc", now, container("app", map[corev1.ResourceName]string{"cpu": "100m"}, nil)),
+	}
+	report := compute(pods, map[string][]k8s.ContainerMetricsHistory{
+		"api-1":   {{Name: "app", DataPoints: samples(20, func(int) (int64, int64) { return 150, 300 })}},
+		"cache-1": {{Name: "redis", DataPoints: samples(20, func(i int) (int64, int64) { return 150, 200 + int64(i)*2 })}},
+		"bare":    {{Name: "app", DataPoints: samples(20, func(int) (int64, int64) { return 20, 40 })}},
+		"fresh-1": {{Name: "app", DataPoints: samples(3, func(int) (int64, int64) { return 20, 40 })}},
+	})
+
+	byName := map[string]Workload{}
+	for _, w := range report.Workloads {
+		byName[w.Name] = w
+	}
+	api := byName["api-abc"].Containers[0]
+	if api.CPU.Status != StatusUnderProvisioned || !strings.Contains(api.CPU.Message, "exceeds the 100m request") {
+		t.Errorf("api CPU = %s (%s), want under-provisioned", api.CPU.Status, api.CPU.Message)
+	}
+	if api.Memory.Status != StatusOK {
+		t.Errorf("api memory = %s (%s), want ok", api.Memory.Status, api.Memory.Message)
+	}
+	redis := byName["cache-abc"].Containers[0]
+	if redis.Memory.Status != StatusUnderProvisioned || !strings.Contains(redis.Memory.Message, "OOMKilled") {
+		t.Errorf("redis memory = %s (%s), want under-provisioned near the limit", redis.Memory.Status, redis.Memory.Message)
+	}
+	if bare := byName["bare"]; bare.Kind != "Pod" || bare.Status != StatusNoRequest {
+		t.Errorf("bare pod = %s/%s %s, want Pod no-request", bare.Kind, bare.Name, bare.Status)
+	}
+	fresh := byName["fresh-abc"]
+	if fresh.Status != StatusInsufficientData || fresh.Containers[0].CPU.Suggested != nil {
+		t.Errorf("fresh = %s with suggestion %v, want insufficient-data without one", fresh.Status, fresh.Containers[0].CPU.Suggested)
+	}
+}
+
+func TestComputeUsesNewestPodSpecAndRollupPeaks(t *testing.T) {
+	old := pod("web-1", "web", now.Add(-time.Hour), container("app", map[corev1.ResourceName]string{"memory": "2Gi"}, nil))
+	current := pod("web-2", "web", now, container("app", map[corev1.ResourceName]string{"memory": "256Mi"}, nil))
+	points := samples(12, func(int) (int64, int64) { return 10, 100 })
+	points[5].MemoryMax = 300 << 20 // A spike inside a 5m rollup
+	report := compute([]*corev1.Pod{current, old}, map[string][]k8s.ContainerMetricsHistory{
+		"web-1": {{Name: "app", DataPoints: points}},
+		"web-2": {{Name: "app", DataPoints: points}},
+	})
+
+	c := report.Workloads[0].Containers[0]
+	if *c.Memory.Request != 256<<20 {
+		t.Errorf("request = %d, want the newest pod's 256Mi", *c.Memory.Request)
+	}
+	if c.Memory.Max != 300<<20 || c.Memory.Status != StatusUnderProvisioned {
+		t.Errorf("memory = %+v, want the 300Mi rollup peak to exceed the request", c.Memory)
+	}
+}
@@ -0,0 +1,144 @@
+package scheduling
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// Handlers provides HTTP handlers for scheduling analysis
+type Handlers struct{}
+
+// NewHandlers creates a new Handlers instance
+func NewHandlers() *Handlers {
+	return &Handlers{}
+}
+
+// RegisterRoutes registers scheduling routes on the given router
+func (h *Handlers) RegisterRoutes(r chi.Router) {
+	r.Get("/pods/{namespace}/{name}/scheduling", h.handleAnalyzePod)
+}
+
+// handleAnalyzePod explains which nodes a pod can run on and, for the rest, why not
+func (h *Handlers) handleAnalyzePod(w http.ResponseWriter, r *http.Request) {
+	cache := k8s.GetResourceCache()
+	if cache == nil {
+		explorerErrors.Write(w, explorerErrors.CacheNotInitialized())
+		return
+	}
+	namespace, name := chi.URLParam(r, "namespace"), chi.URLParam(r, "name")
+	pod, err := cache.Pods().Pods(namespace).Get(name)
+	if err != nil {
+		explorerErrors.Write(w, err)
+		return
+	}
+	nodes, err := cache.Nodes().List(labels.Everything())
+	if err != nil {
+		explorerErrors.Write(w, err)
+		return
+	}
+	pods, err := cache.Pods().List(labels.Everything())
+	if err != nil {
+		explorerErrors.Write(w, err)
+		return
+	}
+
+	in := Input{
+		Pod:              pod,
+		Nodes:            nodes,
+		Pods:             pods,
+		PVCs:             make(map[string]*corev1.PersistentVolumeClaim),
+		PVs:              make(map[string]*corev1.PersistentVolume),
+		StorageClasses:   make(map[string]*storagev1.StorageClass),
+		SchedulerMessage: lastSchedulingFailure(cache, pod),
+	}
+	// Claims come from the typed cache; their volumes and classes through the dynamic one
+	for _, vol := range pod.Spec.Volumes {
+		claimName := ""
+		if vol.PersistentVolumeClaim != nil {
+			claimName = vol.PersistentVolumeClaim.ClaimName
+		} else if vol.Ephemeral != nil {
+			claimName = pod.Name + "-" + vol.Name
+		}
+		if claimName == "" {
+			continue
+		}
+		claim, err := cache.PersistentVolumeClaims().PersistentVolumeClaims(namespace).Get(claimName)
+		if err != nil {
+			continue
+		}
+		in.PVCs[claimName] = claim
+		if claim.Spec.VolumeName != "" {
+			var pv corev1.PersistentVolume
+			if getDynamic(r, cache, "PersistentVolume", claim.Spec.VolumeName, &pv) {
+				in.PVs[pv.Name] = &pv
+			}
+		}
+		if claim.Spec.StorageClassName != nil && *claim.Spec.StorageClassName != "" {
+			var sc storagev1.StorageClass
+			if getDynamic(r, cache, "StorageClass", *claim.Spec.StorageClassName, &sc) {
+				in.StorageClasses[sc.Name] = &sc
+			}
+		}
+	}
+
+	writeJSON(w, Analyze(in))
+}
+
+// getDynamic reads a cluster-scoped object into out, reporting whether it was found
+func getDynamic(r *http.Request, cache *k8s.ResourceCache, kind, name string, out any) bool {
+	obj, err := cache.GetDynamic(r.Context(), kind, "", name)
+	if err != nil {
+		return false
+	}
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, out) == nil
+}
+
+// lastSchedulingFailure returns the message of the pod's latest FailedScheduling event
+func lastSchedulingFailure(cache *k8s.ResourceCache, pod *corev1.Pod) string {
+	events, err := cache.Events().Events(pod.Namespace).List(labels.Everything())
+	if err != nil {
+		return ""
+	}
+	var latest *corev1.Event
+	for _, e := range events {
+		if e.Reason != "FailedScheduling" || e.InvolvedObject.Kind != "Pod" || e.InvolvedObject.Name != pod.Name {
+			continue
+		}
+		if e.InvolvedObject.UID != "" && e.InvolvedObject.UID != pod.UID {
+			continue
+		}
+		if latest == nil || eventTime(e).After(eventTime(latest)) {
+			latest = e
+		}
+	}
+	if latest == nil {
+		return ""
+	}
+	return latest.Message
+}
+
+// eventTime is when an event was last seen
+func eventTime(e *corev1.Event) time.Time {
+	if !e.LastTimestamp.IsZero() {
+		return e.LastTimestamp.Time
+	}
+	if !e.EventTime.IsZero() {
+		return e.EventTime.Time
+	}
+	return e.CreationTimestamp.Time
+}
+
+func writeJSON(w http.ResponseWriter, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,469 @@
+// Package scheduling explains why a pod can't be scheduled by replaying the scheduler's
+// main filters (taints, node selector and affinity, free resources, volumes and
+// topology spread) against the cached nodes.
+package scheduling
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	resourcehelper "k8s.io/component-helpers/resource"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/component-helpers/scheduling/corev1/nodeaffinity"
+	"k8s.io/klog/v2"
+)
+
+// Check names, in the order they're evaluated on each node
+const (
+	CheckVolumes        = "volumes"
+	CheckUnschedulable  = "unschedulable"
+	CheckTaints         = "taints"
+	CheckNodeSelector   = "nodeSelector"
+	CheckNodeAffinity   = "nodeAffinity"
+	CheckResources      = "resources"
+	CheckTopologySpread = "topologySpread"
+)
+
+// Reason is one cause keeping the pod off nodes. Pod-wide reasons (an unbound claim)
+// block every node; the rest count the nodes they rule out.
+type Reason struct {
+	Check   string   `json:"check"`
+	Message string   `json:"message"`
+	PodWide bool     `json:"podWide,omitempty"`
+	Nodes   int      `json:"nodes"`
+	Names   []string `json:"nodeNames,omitempty"` // Sorted, for node reasons
+}
+
+// Failure is why one node can't take the pod
+type Failure struct {
+	Check   string `json:"check"`
+	Message string `json:"message"`          // Shared across nodes, e.g. "Insufficient cpu"
+	Detail  string `json:"detail,omitempty"` // Node specific, e.g. "requests 2, 500m of 4 free"
+}
+
+// NodeFit is the verdict for one node
+type NodeFit struct {
+	Node     string    `json:"node"`
+	Fits     bool      `json:"fits"`
+	Failures []Failure `json:"failures,omitempty"`
+}
+
+// Report explains where a pod can and can't run
+type Report struct {
+	Namespace        string    `json:"namespace"`
+	Name             string    `json:"name"`
+	Phase            string    `json:"phase"`
+	Node             string    `json:"node,omitempty"` // Set once scheduled
+	Summary          string    `json:"summary"`
+	SchedulerMessage string    `json:"schedulerMessage,omitempty"` // Latest FailedScheduling event
+	FeasibleNodes    int       `json:"feasibleNodes"`
+	TotalNodes       int       `json:"totalNodes"`
+	Reasons          []Reason  `json:"reasons"` // Pod-wide first, then by nodes ruled out
+	Nodes            []NodeFit `json:"nodes"`   // Sorted by name
+}
+
+// Input is the pod to place and the cluster state to place it in
+type Input struct {
+	Pod              *corev1.Pod
+	Nodes            []*corev1.Node
+	Pods             []*corev1.Pod                            // Pods in any namespace, for used resources and spread counts
+	PVCs             map[string]*corev1.PersistentVolumeClaim // The pod's namespace, by name
+	PVs              map[string]*corev1.PersistentVolume      // By name
+	StorageClasses   map[string]*storagev1.StorageClass       // By name
+	SchedulerMessage string
+}
+
+// unschedulableTaint is the taint the node controller puts on cordoned nodes
+const unschedulableTaint = "node.kubernetes.io/unschedulable"
+
+// Analyze evaluates the pod against every node
+func Analyze(in Input) *Report {
+	pod := in.Pod
+	report := &Report{
+		Namespace:        pod.Namespace,
+		Name:             pod.Name,
+		Phase:            string(pod.Status.Phase),
+		Node:             pod.Spec.NodeName,
+		SchedulerMessage: in.SchedulerMessage,
+		TotalNodes:       len(in.Nodes),
+		Reasons:          []Reason{},
+		Nodes:            make([]NodeFit, 0, len(in.Nodes)),
+	}
+
+	podWide, volumeAffinity := checkVolumes(in)
+	report.Reasons = append(report.Reasons, podWide...)
+
+	usage := nodeUsage(in.Pods, pod)
+	requests := resourcehelper.PodRequests(pod, resourcehelper.PodResourcesOptions{})
+	spread := newSpreadChecks(pod, in.Nodes, in.Pods)
+
+	byReason := make(map[[2]string]*Reason)
+	var order [][2]string
+	for _, node := range in.Nodes {
+		var failures []Failure
+		for _, sel := range volumeAffinity {
+			if ok, _ := sel.selector.Match(node); !ok {
+				failures = append(failures, Failure{Check: CheckVolumes, Message: "volume node affinity conflict",
+					Detail: fmt.Sprintf("PersistentVolume %s can't be attached here", sel.pv)})
+			}
+		}
+		failures = append(failures, checkTaints(pod, node)...)
+		failures = append(failures, checkPlacement(pod, node)...)
+		failures = append(failures, checkResources(requests, node, usage[node.Name])...)
+		failures = append(failures, spread.check(node)...)
+
+		fit := NodeFit{Node: node.Name, Fits: len(failures) == 0 && len(podWide) == 0, Failures: failures}
+		if fit.Fits {
+			report.FeasibleNodes++
+		}
+		report.Nodes = append(report.Nodes, fit)
+		for _, f := range failures {
+			key := [2]string{f.Check, f.Message}
+			r, ok := byReason[key]
+			if !ok {
+				r = &Reason{Check: f.Check, Message: f.Message}
+				byReason[key] = r
+				order = append(order, key)
+			}
+			// A node counts once per reason, even with two conflicting volumes
+			if len(r.Names) == 0 || r.Names[len(r.Names)-1] != node.Name {
+				r.Nodes++
+				r.Names = append(r.Names, node.Name)
+			}
+		}
+	}
+
+	nodeReasons := make([]Reason, 0, len(order))
+	for _, key := range order {
+		r := byReason[key]
+		sort.Strings(r.Names)
+		nodeReasons = append(nodeReasons, *r)
+	}
+	sort.SliceStable(nodeReasons, func(i, j int) bool { return nodeReasons[i].Nodes > nodeReasons[j].Nodes })
+	report.Reasons = append(report.Reasons, nodeReasons...)
+	sort.Slice(report.Nodes, func(i, j int) bool { return report.Nodes[i].Node < report.Nodes[j].Node })
+	report.Summary = summarize(report)
+	return report
+}
+
+// summarize phrases the report like the scheduler's FailedScheduling message
+func summarize(r *Report) string {
+	if r.TotalNodes == 0 {
+		return "No nodes in the cache"
+	}
+	var parts []string
+	for _, reason := range r.Reasons {
+		if reason.PodWide {
+			parts = append(parts, reason.Message)
+		} else {
+			parts = append(parts, fmt.Sprintf("%d %s", reason.Nodes, reason.Message))
+		}
+	}
+	summary := fmt.Sprintf("%d/%d nodes can run the pod", r.FeasibleNodes, r.TotalNodes)
+	if len(parts) > 0 {
+		summary += ": " + strings.Join(parts, ", ")
+	}
+	return summary
+}
+
+// volumeSelector is a bound PersistentVolume's node affinity
+type volumeSelector struct {
+	pv       string
+	selector *nodeaffinity.LazyErrorNodeSelector
+}
+
+// checkVolumes returns pod-wide reasons for claims that can't be used, and the node
+// affinity of the volumes bound to the rest
+func checkVolumes(in Input) ([]Reason, []volumeSelector) {
+	var reasons []Reason
+	var selectors []volumeSelector
+	blocked := func(format string, args ...any) {
+		reasons = append(reasons, Reason{Check: CheckVolumes, Message: fmt.Sprintf(format, args...), PodWide: true, Nodes: len(in.Nodes)})
+	}
+	for _, vol := range in.Pod.Spec.Volumes {
+		var claimName string
+		switch {
+		case vol.PersistentVolumeClaim != nil:
+			claimName = vol.PersistentVolumeClaim.ClaimName
+		case vol.Ephemeral != nil:
+			claimName = in.Pod.Name + "-" + vol.Name
+		default:
+			continue
+		}
+		claim := in.PVCs[claimName]
+		if claim == nil {
+			if vol.Ephemeral == nil { // Ephemeral claims are created for the pod
+				blocked("PersistentVolumeClaim %q not found", claimName)
+			}
+			continue
+		}
+		if claim.DeletionTimestamp != nil {
+			blocked("PersistentVolumeClaim %q is being deleted", claimName)
+			continue
+		}
+		if claim.Status.Phase != corev1.ClaimBound {
+			className := ""
+			if claim.Spec.StorageClassName != nil {
+				className = *claim.Spec.StorageClassName
+			}
+			sc := in.StorageClasses[className]
+			if sc != nil && sc.VolumeBindingMode != nil && *sc.VolumeBindingMode == storagev1.VolumeBindingWaitForFirstConsumer {
+				continue // Bound once the pod has a node
+			}
+			if className == "" {
+				blocked("PersistentVolumeClaim %q is unbound and has no storage class", claimName)
+			} else {
+				blocked("PersistentVolumeClaim %q is unbound (storage class %q hasn't provisioned a volume)", claimName, className)
+			}
+			continue
+		}
+		pv := in.PVs[claim.Spec.VolumeName]
+		if pv != nil && pv.Spec.NodeAffinity != nil && pv.Spec.NodeAffinity.Required != nil {
+			selectors = append(selectors, volumeSelector{pv: pv.Name, selector: nodeaffinity.NewLazyErrorNodeSelector(pv.Spec.NodeAffinity.Required)})
+		}
+	}
+	return reasons, selectors
+}
+
+// checkTaints reports a cordoned node and taints the pod doesn't tolerate.
+// PreferNoSchedule taints only lower a node's score.
+func checkTaints(pod *corev1.Pod, node *corev1.Node) []Failure {
+	var failures []Failure
+	logger := klog.Background()
+	cordoned := false
+	if node.Spec.Unschedulable {
+		taint := &corev1.Taint{Key: unschedulableTaint, Effect: corev1.TaintEffectNoSchedule}
+		if !corev1helpers.TolerationsTolerateTaint(logger, pod.Spec.Tolerations, taint, false) {
+			failures = append(failures, Failure{Check: CheckUnschedulable, Message: "node is cordoned"})
+			cordoned = true
+		}
+	}
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule || (cordoned && taint.Key == unschedulableTaint) {
+			continue
+		}
+		if !corev1helpers.TolerationsTolerateTaint(logger, pod.Spec.Tolerations, taint, false) {
+			failures = append(failures, Failure{Check: CheckTaints, Message: "untolerated taint " + taint.ToString()})
+		}
+	}
+	return failures
+}
+
+// checkPlacement applies the pod's nodeSelector and required node affinity
+func checkPlacement(pod *corev1.Pod, node *corev1.Node) []Failure {
+	var failures []Failure
+	if len(pod.Spec.NodeSelector) > 0 {
+		var missing []string
+		for _, k := range sortedKeys(pod.Spec.NodeSelector) {
+			if v, ok := node.Labels[k]; !ok || v != pod.Spec.NodeSelector[k] {
+				missing = append(missing, k+"="+pod.Spec.NodeSelector[k])
+			}
+		}
+		if len(missing) > 0 {
+			failures = append(failures, Failure{Check: CheckNodeSelector, Message: "didn't match the pod's nodeSelector",
+				Detail: "missing " + strings.Join(missing, ", ")})
+		}
+	}
+	if required := requiredNodeAffinity(pod); required != nil {
+		if ok, err := nodeaffinity.NewLazyErrorNodeSelector(required).Match(node); !ok {
+			f := Failure{Check: CheckNodeAffinity, Message: "didn't match the pod's required node affinity"}
+			if err != nil {
+				f.Detail = err.Error()
+			}
+			failures = append(failures, f)
+		}
+	}
+	return failures
+}
+
+func requiredNodeAffinity(pod *corev1.Pod) *corev1.NodeSelector {
+	if a := pod.Spec.Affinity; a != nil && a.NodeAffinity != nil {
+		return a.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	}
+	return nil
+}
+
+// usage is what the pods already on a node request
+type usage struct {
+	requests corev1.ResourceList
+	pods     int64
+}
+
+// nodeUsage sums the requests of the pods holding node resources, leaving out the pod
+// being analyzed
+func nodeUsage(pods []*corev1.Pod, self *corev1.Pod) map[string]*usage {
+	byNode := make(map[string]*usage)
+	for _, p := range pods {
+		if p.Spec.NodeName == "" || p.UID == self.UID || p.Status.Phase == corev1.PodSucceeded || p.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		u := byNode[p.Spec.NodeName]
+		if u == nil {
+			u = &usage{requests: corev1.ResourceList{}}
+			byNode[p.Spec.NodeName] = u
+		}
+		u.pods++
+		for name, q := range resourcehelper.PodRequests(p, resourcehelper.PodResourcesOptions{}) {
+			total := u.requests[name]
+			total.Add(q)
+			u.requests[name] = total
+		}
+	}
+	return byNode
+}
+
+// checkResources compares the pod's requests with what's left of the node's allocatable
+func checkResources(requests corev1.ResourceList, node *corev1.Node, used *usage) []Failure {
+	if used == nil {
+		used = &usage{requests: corev1.ResourceList{}}
+	}
+	var failures []Failure
+	if podCap, ok := node.Status.Allocatable[corev1.ResourcePods]; ok && used.pods+1 > podCap.Value() {
+		failures = append(failures, Failure{Check: CheckResources, Message: "Too many pods",
+			Detail: fmt.Sprintf("%d of %d pods already", used.pods, podCap.Value())})
+	}
+	for _, name := range sortedResourceNames(requests) {
+		req := requests[name]
+		if req.IsZero() {
+			continue
+		}
+		free := node.Status.Allocatable[name]
+		inUse := used.requests[name]
+		free.Sub(inUse)
+		if req.Cmp(free) <= 0 {
+			continue
+		}
+		if free.Sign() < 0 {
+			free = resource.Quantity{}
+		}
+		alloc := node.Status.Allocatable[name]
+		failures = append(failures, Failure{Check: CheckResources, Message: "Insufficient " + string(name),
+			Detail: fmt.Sprintf("requests %s, %s of %s free", req.String(), free.String(), alloc.String())})
+	}
+	return failures
+}
+
+// spreadConstraint is one DoNotSchedule topology spread constraint with its domain counts
+type spreadConstraint struct {
+	c        corev1.TopologySpreadConstraint
+	counts   map[string]int // Matching pods per topology value
+	minCount int
+}
+
+type spreadChecks []spreadConstraint
+
+// newSpreadChecks counts matching pods per domain for the pod's hard spread constraints,
+// over the nodes the constraint considers (by default, those passing node affinity)
+func newSpreadChecks(pod *corev1.Pod, nodes []*corev1.Node, pods []*corev1.Pod) spreadChecks {
+	var checks spreadChecks
+	for _, c := range pod.Spec.TopologySpreadConstraints {
+		if c.WhenUnsatisfiable != corev1.DoNotSchedule {
+			continue
+		}
+		selector := spreadSelector(pod, c)
+		domainOf := make(map[string]string) // Node -> topology value, for eligible nodes
+		counts := make(map[string]int)
+		for _, node := range nodes {
+			value, ok := node.Labels[c.TopologyKey]
+			if !ok {
+				continue
+			}
+			if c.NodeAffinityPolicy == nil || *c.NodeAffinityPolicy == corev1.NodeInclusionPolicyHonor {
+				if len(checkPlacement(pod, node)) > 0 {
+					continue
+				}
+			}
+			if c.NodeTaintsPolicy != nil && *c.NodeTaintsPolicy == corev1.NodeInclusionPolicyHonor {
+				if len(checkTaints(pod, node)) > 0 {
+					continue
+				}
+			}
+			domainOf[node.Name] = value
+			counts[value] += 0
+		}
+		for _, p := range pods {
+			if p.Namespace != pod.Namespace || p.UID == pod.UID || p.DeletionTimestamp != nil ||
+				p.Status.Phase == corev1.PodSucceeded || p.Status.Phase == corev1.PodFailed {
+				continue
+			}
+			value, ok := domainOf[p.Spec.NodeName]
+			if ok && selector.Matches(labels.Set(p.Labels)) {
+				counts[value]++
+			}
+		}
+		minCount := 0
+		first := true
+		for _, n := range counts {
+			if first || n < minCount {
+				minCount, first = n, false
+			}
+		}
+		if c.MinDomains != nil && len(counts) < int(*c.MinDomains) {
+			minCount = 0 // Too few domains: the global minimum is treated as zero
+		}
+		checks = append(checks, spreadConstraint{c: c, counts: counts, minCount: minCount})
+	}
+	return checks
+}
+
+// spreadSelector is the constraint's selector plus its matchLabelKeys taken from the pod
+func spreadSelector(pod *corev1.Pod, c corev1.TopologySpreadConstraint) labels.Selector {
+	if c.LabelSelector == nil {
+		return labels.Nothing()
+	}
+	selector, err := metav1.LabelSelectorAsSelector(c.LabelSelector)
+	if err != nil {
+		return labels.Nothing()
+	}
+	for _, key := range c.MatchLabelKeys {
+		if value, ok := pod.Labels[key]; ok {
+			if req, err := labels.NewRequirement(key, selection.Equals, []string{value}); err == nil {
+				selector = selector.Add(*req)
+			}
+		}
+	}
+	return selector
+}
+
+func (s spreadChecks) check(node *corev1.Node) []Failure {
+	var failures []Failure
+	for _, sc := range s {
+		value, ok := node.Labels[sc.c.TopologyKey]
+		if !ok {
+			failures = append(failures, Failure{Check: CheckTopologySpread, Message: "didn't match pod topology spread constraints (missing required label)",
+				Detail: "no " + sc.c.TopologyKey + " label"})
+			continue
+		}
+		if skew := sc.counts[value] + 1 - sc.minCount; skew > int(sc.c.MaxSkew) {
+			failures = append(failures, Failure{Check: CheckTopologySpread, Message: "didn't match pod topology spread constraints",
+				Detail: fmt.Sprintf("%s=%s would have skew %d, max %d", sc.c.TopologyKey, value, skew, sc.c.MaxSkew)})
+		}
+	}
+	return failures
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedResourceNames(list corev1.ResourceList) []corev1.ResourceName {
+	names := make([]corev1.ResourceName, 0, len(list))
+	for name := range list {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
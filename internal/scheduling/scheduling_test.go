@@ -0,0 +1,201 @@
+package scheduling
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func testNode(name string, cpu, memory string, labels map[string]string, mutate ...func(*corev1.Node)) *corev1.Node {
+	n := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse(memory),
+			corev1.ResourcePods:   resource.MustParse("110"),
+		}},
+	}
+	for _, m := range mutate {
+		m(n)
+	}
+	return n
+}
+
+func testPod(name, node, cpu string, labels map[string]string, mutate ...func(*corev1.Pod)) *corev1.Pod {
+	p := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: name, UID: types.UID("uid-" + name), Labels: labels},
+		Spec: corev1.PodSpec{NodeName: node, Containers: []corev1.Container{{
+			Name:      "app",
+			Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)}},
+		}}},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	if node == "" {
+		p.Status.Phase = corev1.PodPending
+	}
+	for _, m := range mutate {
+		m(p)
+	}
+	return p
+}
+
+func fitOf(r *Report, node string) NodeFit {
+	for _, n := range r.Nodes {
+		if n.Node == node {
+			return n
+		}
+	}
+	return NodeFit{}
+}
+
+func TestAnalyzeRanksNodeReasons(t *testing.T) {
+	pending := testPod("api", "", "1500m", nil, func(p *corev1.Pod) {
+		p.Spec.NodeSelector = map[string]string{"pool": "general"}
+		p.Spec.Tolerations = []corev1.Toleration{{Key: "spot", Operator: corev1.TolerationOpExists}}
+	})
+	nodes := []*corev1.Node{
+		testNode("a", "2", "8Gi", map[string]string{"pool": "general"}),
+		testNode("b", "2", "8Gi", map[string]string{"pool": "general"}),
+		testNode("c", "4", "8Gi", map[string]string{"pool": "general"}, func(n *corev1.Node) {
+			n.Spec.Taints = []corev1.Taint{{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}}
+		}),
+		testNode("d", "4", "8Gi", map[string]string{"pool": "general"}, func(n *corev1.Node) {
+			n.Spec.Unschedulable = true
+			n.Spec.Taints = []corev1.Taint{{Key: unschedulableTaint, Effect: corev1.TaintEffectNoSchedule}}
+		}),
+		testNode("e", "4", "8Gi", map[string]string{"pool": "batch"}, func(n *corev1.Node) {
+			n.Spec.Taints = []corev1.Taint{{Key: "spot", Value: "true", Effect: corev1.TaintEffectNoSchedule}}
+		}),
+	}
+	pods := []*corev1.Pod{
+		testPod("web-1", "a", "1", nil),
+		testPod("web-2", "b", "1", nil),
+		testPod("done", "b", "1", nil, func(p *corev1.Pod) { p.Status.Phase = corev1.PodSucceeded }),
+	}
+
+	r := Analyze(Input{Pod: pending, Nodes: nodes, Pods: pods})
+	if r.FeasibleNodes != 0 || r.TotalNodes != 5 {
+		t.Fatalf("feasible %d/%d, want 0/5", r.FeasibleNodes, r.TotalNodes)
+	}
+	if len(r.Reasons) != 4 || r.Reasons[0].Message != "Insufficient cpu" || r.Reasons[0].Nodes != 2 {
+		t.Fatalf("reasons = %+v, want Insufficient cpu on 2 nodes first", r.Reasons)
+	}
+	if a := fitOf(r, "a"); a.Failures[0].Detail != "requests 1500m, 1 of 2 free" {
+		t.Errorf("node a detail = %q", a.Failures[0].Detail)
+	}
+	if c := fitOf(r, "c"); len(c.Failures) != 1 || c.Failures[0].Message != "untolerated taint dedicated=gpu:NoSchedule" {
+		t.Errorf("node c = %+v, want only the gpu taint", c)
+	}
+	// The cordon taint isn't reported twice
+	if d := fitOf(r, "d"); len(d.Failures) != 1 || d.Failures[0].Check != CheckUnschedulable {
+		t.Errorf("node d = %+v, want only cordoned", d)
+	}
+	// The spot taint is tolerated; the selector isn't matched
+	if e := fitOf(r, "e"); len(e.Failures) != 1 || e.Failures[0].Detail != "missing pool=general" {
+		t.Errorf("node e = %+v, want the nodeSelector miss", e)
+	}
+	if !strings.HasPrefix(r.Summary, "0/5 nodes can run the pod: 2 Insufficient cpu, ") {
+		t.Errorf("summary = %q", r.Summary)
+	}
+
+	// Freeing node b lets the pod fit there
+	r = Analyze(Input{Pod: pending, Nodes: nodes, Pods: pods[:1]})
+	if r.FeasibleNodes != 1 || !fitOf(r, "b").Fits {
+		t.Errorf("feasible = %d, want node b", r.FeasibleNodes)
+	}
+}
+
+func TestAnalyzeVolumes(t *testing.T) {
+	wait := storagev1.VolumeBindingWaitForFirstConsumer
+	immediate := storagev1.VolumeBindingImmediate
+	standard, fast := "standard", "fast"
+	claim := func(name string, class *string, phase corev1.PersistentVolumeClaimPhase, volume string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: name},
+			Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: class, VolumeName: volume},
+			Status:     corev1.PersistentVolumeClaimStatus{Phase: phase},
+		}
+	}
+	pod := testPod("db-0", "", "100m", nil, func(p *corev1.Pod) {
+		for _, name := range []string{"data", "logs", "cache", "missing"} {
+			p.Spec.Volumes = append(p.Spec.Volumes, corev1.Volume{Name: name, VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: name},
+			}})
+		}
+	})
+	zone := func(z string) map[string]string { return map[string]string{"topology.kubernetes.io/zone": z} }
+	in := Input{
+		Pod:   pod,
+		Nodes: []*corev1.Node{testNode("a", "4", "8Gi", zone("us-east-1a")), testNode("b", "4", "8Gi", zone("us-east-1b"))},
+		PVCs: map[string]*corev1.PersistentVolumeClaim{
+			"data":  claim("data", &standard, corev1.ClaimBound, "pv-data"),
+			"logs":  claim("logs", &standard, corev1.ClaimPending, ""),
+			"cache": claim("cache", &fast, corev1.ClaimPending, ""),
+		},
+		PVs: map[string]*corev1.PersistentVolume{"pv-data": {
+			ObjectMeta: metav1.ObjectMeta{Name: "pv-data"},
+			Spec: corev1.PersistentVolumeSpec{NodeAffinity: &corev1.VolumeNodeAffinity{Required: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{{
+					Key: "topology.kubernetes.io/zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"us-east-1a"},
+				}}}},
+			}}},
+		}},
+		StorageClasses: map[string]*storagev1.StorageClass{
+			"standard": {ObjectMeta: metav1.ObjectMeta{Name: "standard"}, VolumeBindingMode: &wait},
+			"fast":     {ObjectMeta: metav1.ObjectMeta{Name: "fast"}, VolumeBindingMode: &immediate},
+		},
+	}
+
+	r := Analyze(in)
+	// logs waits for a node; cache is stuck and missing doesn't exist
+	if len(r.Reasons) != 3 || !r.Reasons[0].PodWide || !strings.Contains(r.Reasons[0].Message, `"cache" is unbound`) ||
+		!strings.Contains(r.Reasons[1].Message, `"missing" not found`) {
+		t.Fatalf("reasons = %+v, want the cache and missing claims first", r.Reasons)
+	}
+	if r.Reasons[2].Message != "volume node affinity conflict" || r.Reasons[2].Names[0] != "b" {
+		t.Errorf("node reason = %+v, want the zonal volume to rule out b", r.Reasons[2])
+	}
+	if r.FeasibleNodes != 0 || len(fitOf(r, "a").Failures) != 0 {
+		t.Errorf("feasible = %d, node a = %+v; want a free of node failures but blocked pod-wide", r.FeasibleNodes, fitOf(r, "a"))
+	}
+}
+
+func TestAnalyzeTopologySpread(t *testing.T) {
+	app := map[string]string{"app": "web"}
+	zone := func(z string) map[string]string { return map[string]string{"zone": z} }
+	pending := testPod("web-4", "", "100m", app, func(p *corev1.Pod) {
+		p.Spec.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{{
+			MaxSkew: 1, TopologyKey: "zone", WhenUnsatisfiable: corev1.DoNotSchedule,
+			LabelSelector: &metav1.LabelSelector{MatchLabels: app},
+		}}
+	})
+	nodes := []*corev1.Node{
+		testNode("a", "4", "8Gi", zone("a")),
+		testNode("b", "4", "8Gi", zone("b")),
+		testNode("c", "4", "8Gi", zone("c")),
+		testNode("unlabeled", "4", "8Gi", nil),
+	}
+	pods := []*corev1.Pod{
+		testPod("web-1", "a", "100m", app),
+		testPod("web-2", "a", "100m", app),
+		testPod("web-3", "b", "100m", app),
+		testPod("other", "c", "100m", map[string]string{"app": "api"}),
+	}
+
+	r := Analyze(Input{Pod: pending, Nodes: nodes, Pods: pods})
+	// Zone c has none, so a (2) and b (1) would reach a skew of 3 and 2
+	if !fitOf(r, "c").Fits || fitOf(r, "a").Fits || fitOf(r, "b").Fits || r.FeasibleNodes != 1 {
+		t.Fatalf("fits a=%t b=%t c=%t, want only c", fitOf(r, "a").Fits, fitOf(r, "b").Fits, fitOf(r, "c").Fits)
+	}
+	if d := fitOf(r, "a").Failures[0].Detail; d != "zone=a would have skew 3, max 1" {
+		t.Errorf("node a detail = %q", d)
+	}
+	if f := fitOf(r, "unlabeled").Failures; len(f) != 1 || !strings.Contains(f[0].Message, "missing required label") {
+		t.Errorf("unlabeled node = %+v, want the missing label", f)
+	}
+}
@@ -0,0 +1,157 @@
+package search
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// lastAppliedAnnotation holds the whole applied manifest (for Secrets, their values), so
+// it's never searched
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// podSpecPaths are where custom resources commonly keep a pod template
+var podSpecPaths = [][]string{
+	{"spec", "template", "spec"},
+	{"spec", "jobTemplate", "spec", "template", "spec"},
+	{"spec"},
+}
+
+// Collect builds the documents to search from the typed cache and the custom resources
+// the dynamic cache is watching. Events aren't included.
+func Collect(cache *k8s.ResourceCache, dynamic *k8s.DynamicResourceCache) []Document {
+	var docs []Document
+	typed := make(map[schema.GroupResource]bool)
+	cache.ForEachTypedObject(func(kind string, gvr schema.GroupVersionResource, obj any) {
+		typed[gvr.GroupResource()] = true
+		if kind == "Event" {
+			return
+		}
+		if doc, ok := newDocument(kind, gvr, obj); ok {
+			doc.Images = typedImages(obj)
+			docs = append(docs, doc)
+		}
+	})
+
+	discovery := k8s.GetResourceDiscovery()
+	for _, gvr := range dynamic.GetWatchedResources() {
+		if typed[gvr.GroupResource()] || gvr.Resource == "events" {
+			continue
+		}
+		items, err := dynamic.List(gvr, "")
+		if err != nil {
+			continue
+		}
+		kind := ""
+		if discovery != nil {
+			kind = discovery.GetKindForGVR(gvr)
+		}
+		if kind == "" {
+			kind = gvr.Resource
+		}
+		for _, u := range items {
+			if doc, ok := newDocument(kind, gvr, u); ok {
+				doc.Images = unstructuredImages(u)
+				docs = append(docs, doc)
+			}
+		}
+	}
+	return docs
+}
+
+func newDocument(kind string, gvr schema.GroupVersionResource, obj any) (Document, bool) {
+	m, err := meta.Accessor(obj)
+	if err != nil {
+		return Document{}, false
+	}
+	annotations := m.GetAnnotations()
+	if _, ok := annotations[lastAppliedAnnotation]; ok {
+		annotations = make(map[string]string, len(annotations))
+		for k, v := range m.GetAnnotations() {
+			if k != lastAppliedAnnotation {
+				annotations[k] = v
+			}
+		}
+	}
+	return Document{
+		Kind:        kind,
+		Group:       gvr.Group,
+		Resource:    gvr.Resource,
+		Namespace:   m.GetNamespace(),
+		Name:        m.GetName(),
+		Labels:      m.GetLabels(),
+		Annotations: annotations,
+	}, true
+}
+
+// typedImages returns the container images of a pod or a workload's pod template
+func typedImages(obj any) []string {
+	var spec *corev1.PodSpec
+	switch o := obj.(type) {
+	case *corev1.Pod:
+		spec = &o.Spec
+	case *appsv1.Deployment:
+		spec = &o.Spec.Template.Spec
+	case *appsv1.StatefulSet:
+		spec = &o.Spec.Template.Spec
+	case *appsv1.DaemonSet:
+		spec = &o.Spec.Template.Spec
+	case *appsv1.ReplicaSet:
+		spec = &o.Spec.Template.Spec
+	case *batchv1.Job:
+		spec = &o.Spec.Template.Spec
+	case *batchv1.CronJob:
+		spec = &o.Spec.JobTemplate.Spec.Template.Spec
+	default:
+		return nil
+	}
+	var images []string
+	for _, containers := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+		for _, c := range containers {
+			images = appendUnique(images, c.Image)
+		}
+	}
+	for _, c := range spec.EphemeralContainers {
+		images = appendUnique(images, c.Image)
+	}
+	return images
+}
+
+// unstructuredImages returns the container images of a custom resource's pod template,
+// e.g. an Argo Rollout's
+func unstructuredImages(u *unstructured.Unstructured) []string {
+	var images []string
+	for _, path := range podSpecPaths {
+		for _, field := range []string{"initContainers", "containers"} {
+			containers, _, _ := unstructured.NestedSlice(u.Object, append(path, field)...)
+			for _, c := range containers {
+				if cm, ok := c.(map[string]any); ok {
+					if image, ok := cm["image"].(string); ok {
+						images = appendUnique(images, image)
+					}
+				}
+			}
+		}
+		if len(images) > 0 {
+			return images
+		}
+	}
+	return nil
+}
+
+func appendUnique(list []string, s string) []string {
+	if s == "" {
+		return list
+	}
+	for _, v := range list {
+		if v == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
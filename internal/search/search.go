@@ -0,0 +1,317 @@
+// Package search finds cached resources by name, label, annotation, container image and
+// kind. Queries are free text with optional field qualifiers; names and images match
+// exactly, by prefix, by substring or fuzzily (the query's characters in order), and
+// results are ranked by how well and where they matched.
+package search
+
+import (
+	"sort"
+	"strings"
+)
+
+// Document is one resource as search sees it
+type Document struct {
+	Kind        string
+	Group       string
+	Resource    string // Plural, e.g. "deployments"
+	Namespace   string
+	Name        string
+	Labels      map[string]string
+	Annotations map[string]string
+	Images      []string
+}
+
+// Field is what part of a resource a term matched
+type Field string
+
+const (
+	FieldName       Field = "name"
+	FieldKind       Field = "kind"
+	FieldNamespace  Field = "namespace"
+	FieldLabel      Field = "label"
+	FieldAnnotation Field = "annotation"
+	FieldImage      Field = "image"
+)
+
+// Term is one query word. Without a field it may match a name, label, image, annotation
+// or kind; with one (name:api, image:nginx, label:app=web) only that.
+type Term struct {
+	Field Field  `json:"field,omitempty"`
+	Value string `json:"value"`
+}
+
+// Query is a parsed search. Every term must match. Kinds and namespaces (kind:, ns:)
+// filter rather than score, and several of either are alternatives.
+type Query struct {
+	Terms      []Term   `json:"terms"`
+	Kinds      []string `json:"kinds,omitempty"`
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// qualifiers maps the accepted field prefixes to fields
+var qualifiers = map[string]Field{
+	"name":       FieldName,
+	"kind":       FieldKind,
+	"ns":         FieldNamespace,
+	"namespace":  FieldNamespace,
+	"label":      FieldLabel,
+	"l":          FieldLabel,
+	"annotation": FieldAnnotation,
+	"image":      FieldImage,
+	"img":        FieldImage,
+}
+
+// ParseQuery splits a query into terms. Matching is case-insensitive; unknown prefixes
+// are searched as plain text (so "app:web" still finds the label).
+func ParseQuery(s string) Query {
+	var q Query
+	for _, word := range strings.Fields(strings.ToLower(s)) {
+		field, value := Field(""), word
+		if prefix, rest, ok := strings.Cut(word, ":"); ok && rest != "" {
+			if f, known := qualifiers[prefix]; known {
+				field, value = f, rest
+			}
+		}
+		switch field {
+		case FieldKind:
+			q.Kinds = append(q.Kinds, strings.Split(value, ",")...)
+		case FieldNamespace:
+			q.Namespaces = append(q.Namespaces, strings.Split(value, ",")...)
+		default:
+			q.Terms = append(q.Terms, Term{Field: field, Value: value})
+		}
+	}
+	return q
+}
+
+// Match is where one term matched
+type Match struct {
+	Field Field  `json:"field"`
+	Value string `json:"value"` // The name, label (key=value), annotation key or image that matched
+}
+
+// Result is a matching resource, identified for deep-linking
+type Result struct {
+	Kind      string  `json:"kind"`
+	Group     string  `json:"group,omitempty"`
+	Resource  string  `json:"resource"`
+	Namespace string  `json:"namespace,omitempty"`
+	Name      string  `json:"name"`
+	Score     int     `json:"score"`
+	Matches   []Match `json:"matches,omitempty"`
+}
+
+// Search returns the documents matching q, best first
+func Search(docs []Document, q Query) []Result {
+	results := []Result{}
+	for i := range docs {
+		if r, ok := match(&docs[i], q); ok {
+			results = append(results, r)
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if len(a.Name) != len(b.Name) {
+			return len(a.Name) < len(b.Name) // Shorter names are closer to the query
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	return results
+}
+
+// match scores a document against every term
+func match(d *Document, q Query) (Result, bool) {
+	if len(q.Kinds) > 0 && !anyMatch(q.Kinds, func(k string) bool { return kindMatches(d, k) }) {
+		return Result{}, false
+	}
+	if len(q.Namespaces) > 0 && !anyMatch(q.Namespaces, func(ns string) bool { return strings.ToLower(d.Namespace) == ns }) {
+		return Result{}, false
+	}
+	r := Result{Kind: d.Kind, Group: d.Group, Resource: d.Resource, Namespace: d.Namespace, Name: d.Name}
+	for _, t := range q.Terms {
+		score, m := scoreTerm(d, t)
+		if score == 0 {
+			return Result{}, false
+		}
+		r.Score += score
+		r.Matches = append(r.Matches, m)
+	}
+	if len(q.Terms) == 0 {
+		r.Score = 1 // Filters only: everything that passes ranks the same
+	}
+	return r, true
+}
+
+func anyMatch(values []string, fn func(string) bool) bool {
+	for _, v := range values {
+		if fn(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// kindMatches accepts the kind, its plural resource or the resource with its group
+// (deployment, deployments, deployments.apps)
+func kindMatches(d *Document, k string) bool {
+	kind, resource := strings.ToLower(d.Kind), strings.ToLower(d.Resource)
+	return k == kind || k == resource || (d.Group != "" && k == resource+"."+strings.ToLower(d.Group))
+}
+
+// scoreTerm returns the best score of a term against the fields it may match (0 = none)
+func scoreTerm(d *Document, t Term) (int, Match) {
+	best, bestMatch := 0, Match{}
+	try := func(field Field, score int, value string) {
+		if score > best {
+			best, bestMatch = score, Match{Field: field, Value: value}
+		}
+	}
+	anyField := t.Field == ""
+
+	if anyField || t.Field == FieldName {
+		try(FieldName, scoreText(strings.ToLower(d.Name), t.Value, 100), d.Name)
+	}
+	if anyField || t.Field == FieldImage {
+		for _, img := range d.Images {
+			try(FieldImage, scoreImage(strings.ToLower(img), t.Value), img)
+		}
+	}
+	if anyField || t.Field == FieldLabel {
+		for k, v := range d.Labels {
+			try(FieldLabel, scorePair(strings.ToLower(k), strings.ToLower(v), t.Value, 60), k+"="+v)
+		}
+	}
+	if anyField || t.Field == FieldAnnotation {
+		for k, v := range d.Annotations {
+			try(FieldAnnotation, scorePair(strings.ToLower(k), strings.ToLower(v), t.Value, 30), k)
+		}
+	}
+	if anyField && kindMatches(d, t.Value) {
+		try(FieldKind, 40, d.Kind)
+	}
+	return best, bestMatch
+}
+
+// scoreText scores a query against a name: exact, prefix, word prefix (after - . / _),
+// substring, then fuzzy, scaled to top
+func scoreText(text, query string, top int) int {
+	switch {
+	case text == query:
+		return top
+	case strings.HasPrefix(text, query):
+		return top * 8 / 10
+	case hasWordPrefix(text, query):
+		return top * 7 / 10
+	case strings.Contains(text, query):
+		return top * 6 / 10
+	}
+	if f := fuzzy(text, query); f > 0 {
+		return max(1, top*f/100*4/10)
+	}
+	return 0
+}
+
+// scoreImage matches an image by its repository name (nginx in docker.io/library/nginx:1.25)
+// as well as the full reference
+func scoreImage(image, query string) int {
+	if image == query {
+		return 90
+	}
+	repo := image
+	if i := strings.LastIndex(repo, "@"); i >= 0 {
+		repo = repo[:i]
+	}
+	if i := strings.LastIndex(repo, ":"); i > strings.LastIndex(repo, "/") {
+		repo = repo[:i]
+	}
+	short := repo[strings.LastIndex(repo, "/")+1:]
+	switch {
+	case short == query || repo == query:
+		return 80
+	case strings.HasPrefix(short, query):
+		return 65
+	case strings.Contains(image, query):
+		return 50
+	}
+	if f := fuzzy(short, query); f > 0 {
+		return max(1, 20*f/100)
+	}
+	return 0
+}
+
+// scorePair matches key=value, a key or a value. Labels and annotations don't match
+// fuzzily: their values are too short and too many for that to be useful.
+func scorePair(key, value, query string, top int) int {
+	if k, v, ok := strings.Cut(query, "="); ok {
+		switch {
+		case k == key && v == value:
+			return top
+		case k == key && strings.HasPrefix(value, v):
+			return top * 8 / 10
+		}
+		return 0
+	}
+	switch {
+	case query == value || query == key:
+		return top * 8 / 10
+	case strings.HasPrefix(value, query) || strings.HasPrefix(key, query):
+		return top * 6 / 10
+	case strings.Contains(value, query):
+		return top * 4 / 10
+	}
+	return 0
+}
+
+// hasWordPrefix reports whether a word inside text (after a separator) starts with query
+func hasWordPrefix(text, query string) bool {
+	for i := 0; i < len(text); i++ {
+		if isSeparator(text[i]) && strings.HasPrefix(text[i+1:], query) {
+			return true
+		}
+	}
+	return false
+}
+
+func isSeparator(c byte) bool {
+	return c == '-' || c == '.' || c == '/' || c == '_' || c == ':'
+}
+
+// fuzzy scores query as a subsequence of text from 1 to 100, higher the more compact the
+// match (0 = not a subsequence). Queries shorter than 3 characters don't match fuzzily.
+func fuzzy(text, query string) int {
+	if len(query) < 3 || len(query) > len(text) {
+		return 0
+	}
+	// Compactness of the first occurrence found left to right, tightened by scanning
+	// back from its end
+	end, qi := -1, 0
+	for i := 0; i < len(text) && qi < len(query); i++ {
+		if text[i] == query[qi] {
+			qi++
+			if qi == len(query) {
+				end = i
+			}
+		}
+	}
+	if end < 0 {
+		return 0
+	}
+	start, qi := end, len(query)-1
+	for i := end; i >= 0 && qi >= 0; i-- {
+		if text[i] == query[qi] {
+			qi--
+			start = i
+		}
+	}
+	span := end - start + 1
+	return max(1, 100*len(query)/span)
+}
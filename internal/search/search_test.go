@@ -0,0 +1,105 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var docs = []Document{
+	{Kind: "Deployment", Group: "apps", Resource: "deployments", Namespace: "shop", Name: "checkout-api",
+		Labels: map[string]string{"app": "checkout", "team": "payments"}, Images: []string{"ghcr.io/acme/checkout:1.4.2"}},
+	{Kind: "Deployment", Group: "apps", Resource: "deployments", Namespace: "shop", Name: "cart",
+		Labels: map[string]string{"app": "cart"}, Images: []string{"nginx:1.25", "redis:7"}},
+	{Kind: "Service", Resource: "services", Namespace: "shop", Name: "checkout",
+		Annotations: map[string]string{"owner": "payments-oncall"}},
+	{Kind: "ConfigMap", Resource: "configmaps", Namespace: "kube-system", Name: "coredns"},
+	{Kind: "Rollout", Group: "argoproj.io", Resource: "rollouts", Namespace: "web", Name: "frontend",
+		Images: []string{"docker.io/library/nginx@sha256:abc"}},
+}
+
+func names(results []Result) []string {
+	out := []string{}
+	for _, r := range results {
+		out = append(out, r.Kind+"/"+r.Name)
+	}
+	return out
+}
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		// Exact name beats prefix, shorter names first on ties
+		{"checkout", []string{"Service/checkout", "Deployment/checkout-api"}},
+		// Word prefix after a separator
+		{"api", []string{"Deployment/checkout-api"}},
+		// Fuzzy: characters in order
+		{"chkapi", []string{"Deployment/checkout-api"}},
+		// Images by repository name, with or without registry, tag or digest
+		{"image:nginx", []string{"Deployment/cart", "Rollout/frontend"}},
+		{"redis", []string{"Deployment/cart"}},
+		// Labels by key=value, and annotations
+		{"label:team=payments", []string{"Deployment/checkout-api"}},
+		{"annotation:payments", []string{"Service/checkout"}},
+		// Kind and namespace filter; every term must match
+		{"kind:deployments nginx", []string{"Deployment/cart"}},
+		{"kind:rollouts.argoproj.io", []string{"Rollout/frontend"}},
+		{"ns:kube-system", []string{"ConfigMap/coredns"}},
+		{"ns:kube-system core", []string{"ConfigMap/coredns"}},
+		{"checkout nginx", []string{}},
+		// Too short to match fuzzily
+		{"cx", []string{}},
+	}
+	for _, tt := range tests {
+		q := ParseQuery(tt.query)
+		if got := names(Search(docs, q)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: got %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestSearchMatches(t *testing.T) {
+	results := Search(docs, ParseQuery("ghcr.io/acme CHECKOUT"))
+	if len(results) != 1 {
+		t.Fatalf("results = %+v", results)
+	}
+	want := []Match{{Field: FieldImage, Value: "ghcr.io/acme/checkout:1.4.2"}, {Field: FieldName, Value: "checkout-api"}}
+	if !reflect.DeepEqual(results[0].Matches, want) {
+		t.Errorf("matches = %+v, want %+v", results[0].Matches, want)
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	got := ParseQuery("kind:Pod,deployment ns:shop app:web image:nginx")
+	want := Query{
+		Terms:      []Term{{Value: "app:web"}, {Field: FieldImage, Value: "nginx"}},
+		Kinds:      []string{"pod", "deployment"},
+		Namespaces: []string{"shop"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseQuery = %+v, want %+v", got, want)
+	}
+}
+
+func TestImages(t *testing.T) {
+	deploy := &appsv1.Deployment{}
+	deploy.Spec.Template.Spec = corev1.PodSpec{
+		InitContainers: []corev1.Container{{Image: "busybox"}},
+		Containers:     []corev1.Container{{Image: "app:1"}, {Image: "busybox"}},
+	}
+	if got := typedImages(deploy); !reflect.DeepEqual(got, []string{"busybox", "app:1"}) {
+		t.Errorf("typedImages = %v", got)
+	}
+
+	rollout := &unstructured.Unstructured{Object: map[string]any{"spec": map[string]any{"template": map[string]any{
+		"spec": map[string]any{"containers": []any{map[string]any{"name": "web", "image": "nginx:1.25"}}},
+	}}}}
+	if got := unstructuredImages(rollout); !reflect.DeepEqual(got, []string{"nginx:1.25"}) {
+		t.Errorf("unstructuredImages = %v", got)
+	}
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/skyhook-io/radar/internal/auth"
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+	"github.com/skyhook-io/radar/internal/timeline"
+)
+
+// maxAnnotationBody caps the size of a posted annotation
+const maxAnnotationBody = 64 << 10
+
+// handleCreateAnnotation records a manual marker (deploy, incident note) on the timeline.
+// ?namespace= scopes it like the body's namespace does, so tokens limited to namespaces
+// can post; when both are set they must agree.
+func (s *Server) handleCreateAnnotation(w http.ResponseWriter, r *http.Request) {
+	var in timeline.AnnotationInput
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAnnotationBody)).Decode(&in); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	if ns := r.URL.Query().Get("namespace"); ns != "" {
+		if in.Namespace != "" && in.Namespace != ns {
+			s.writeError(w, http.StatusBadRequest, "body namespace "+in.Namespace+" doesn't match ?namespace="+ns)
+			return
+		}
+		in.Namespace = ns
+	}
+
+	event, err := timeline.NewAnnotationEvent(in, auth.Actor(r), time.Now())
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := timeline.RecordAnnotation(r.Context(), event); err != nil {
+		s.writeError(w, http.StatusServiceUnavailable, "failed to record annotation: "+err.Error())
+		return
+	}
+	s.writeJSON(w, event)
+}
+
+// handleListAnnotations returns annotations oldest first, for drawing markers on the
+// timeline and metrics charts. ?namespace= (comma-separated) keeps those namespaces'
+// markers plus cluster-wide ones; ?since=/?until= are RFC3339; ?limit= defaults to 500.
+func (s *Server) handleListAnnotations(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	var since, until time.Time
+	for param, dst := range map[string]*time.Time{"since": &since, "until": &until} {
+		if v := q.Get(param); v != "" {
+			ts, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				s.writeError(w, http.StatusBadRequest, param+" must be an RFC3339 timestamp")
+				return
+			}
+			*dst = ts
+		}
+	}
+	limit := 500
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			s.writeError(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = min(n, 5000)
+	}
+	var namespaces []string
+	for _, ns := range strings.Split(q.Get("namespace"), ",") {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			namespaces = append(namespaces, ns)
+		}
+	}
+
+	store := timeline.GetStore()
+	if store == nil {
+		s.writeExplorerError(w, explorerErrors.New(explorerErrors.ErrTimelineStoreNotInit, "timeline store not available"))
+		return
+	}
+	events, err := timeline.Annotations(r.Context(), store, namespaces, since, until, limit)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.writeJSON(w, events)
+}
@@ -0,0 +1,128 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/skyhook-io/radar/internal/auth"
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// handleSyncArgoApplication triggers an Argo CD sync, optionally to a specific revision
+// POST /api/argocd/applications/{namespace}/{name}/sync
+func (s *Server) handleSyncArgoApplication(w http.ResponseWriter, r *http.Request) {
+	namespace := chi.URLParam(r, "namespace")
+	name := chi.URLParam(r, "name")
+
+	var opts k8s.ArgoSyncOptions
+	if err := json.NewDecoder(r.Body).Decode(&opts); err != nil && !errors.Is(err, io.EOF) {
+		s.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	opts.InitiatedBy = "radar (" + auth.Actor(r) + ")"
+
+	if err := k8s.SyncArgoApplication(r.Context(), namespace, name, opts); err != nil {
+		s.writeExplorerError(w, err)
+		return
+	}
+
+	var detail []string
+	if opts.Revision != "" {
+		detail = append(detail, "revision "+opts.Revision)
+	}
+	if opts.Prune {
+		detail = append(detail, "prune")
+	}
+	if opts.DryRun {
+		detail = append(detail, "dry run")
+	}
+	auditActionDetail(r, "sync", "Application", namespace, name, strings.Join(detail, ", "))
+	s.writeJSON(w, map[string]string{"message": "Sync started"})
+}
+
+// handleRefreshArgoApplication asks Argo CD to re-compare an Application with its source
+// POST /api/argocd/applications/{namespace}/{name}/refresh?hard=true
+func (s *Server) handleRefreshArgoApplication(w http.ResponseWriter, r *http.Request) {
+	namespace := chi.URLParam(r, "namespace")
+	name := chi.URLParam(r, "name")
+	hard, _ := strconv.ParseBool(r.URL.Query().Get("hard"))
+
+	if err := k8s.RefreshArgoApplication(r.Context(), namespace, name, hard); err != nil {
+		s.writeExplorerError(w, err)
+		return
+	}
+
+	detail := ""
+	if hard {
+		detail = "hard"
+	}
+	auditActionDetail(r, "refresh", "Application", namespace, name, detail)
+	s.writeJSON(w, map[string]string{"message": "Refresh requested"})
+}
+
+// handleSetArgoAutoSync enables or disables an Application's automated sync
+// PUT /api/argocd/applications/{namespace}/{name}/auto-sync
+func (s *Server) handleSetArgoAutoSync(w http.ResponseWriter, r *http.Request) {
+	namespace := chi.URLParam(r, "namespace")
+	name := chi.URLParam(r, "name")
+
+	var policy k8s.ArgoAutoSync
+	if err := json.NewDecoder(r.Body).Decode(&policy); err != nil {
+		s.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if !policy.Enabled && (policy.Prune || policy.SelfHeal) {
+		s.writeError(w, http.StatusBadRequest, "prune and selfHeal require enabled")
+		return
+	}
+
+	if err := k8s.SetArgoAutoSync(r.Context(), namespace, name, policy); err != nil {
+		s.writeExplorerError(w, err)
+		return
+	}
+
+	action, message := "disable auto-sync", "Auto-sync disabled"
+	var detail []string
+	if policy.Enabled {
+		action, message = "enable auto-sync", "Auto-sync enabled"
+		if policy.Prune {
+			detail = append(detail, "prune")
+		}
+		if policy.SelfHeal {
+			detail = append(detail, "self-heal")
+		}
+	}
+	auditActionDetail(r, action, "Application", namespace, name, strings.Join(detail, ", "))
+	s.writeJSON(w, map[string]string{"message": message})
+}
+
+// rolloutActionMessages are the responses to Argo Rollouts actions
+var rolloutActionMessages = map[string]string{
+	k8s.RolloutActionPromote:     "Rollout promoted",
+	k8s.RolloutActionPromoteFull: "Full promotion started",
+	k8s.RolloutActionAbort:       "Rollout aborted",
+	k8s.RolloutActionRetry:       "Rollout retried",
+	k8s.RolloutActionPause:       "Rollout paused",
+}
+
+// handleRolloutAction promotes, fully promotes, aborts, retries or pauses an Argo Rollout
+// POST /api/rollouts/{namespace}/{name}/{promote|promote-full|abort|retry|pause}
+func (s *Server) handleRolloutAction(w http.ResponseWriter, r *http.Request) {
+	namespace := chi.URLParam(r, "namespace")
+	name := chi.URLParam(r, "name")
+	action := chi.URLParam(r, "action")
+
+	if err := k8s.RunRolloutAction(r.Context(), namespace, name, action); err != nil {
+		s.writeExplorerError(w, err)
+		return
+	}
+
+	auditActionDetail(r, action, "Rollout", namespace, name, "")
+	s.writeJSON(w, map[string]string{"message": rolloutActionMessages[action]})
+}
@@ -11,9 +11,11 @@ import (
 	"sync"
 	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
 
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
 	"github.com/skyhook-io/radar/internal/helm"
 	"github.com/skyhook-io/radar/internal/k8s"
 	"github.com/skyhook-io/radar/internal/timeline"
@@ -34,6 +36,8 @@ type DashboardResponse struct {
 	HelmReleases    DashboardHelmSummary     `json:"helmReleases"`
 	Metrics         *DashboardMetrics        `json:"metrics"`
 	TopCRDs         []DashboardCRDCount      `json:"topCRDs"`
+	Forecasts       []k8s.UsageForecast      `json:"forecasts"` // Quotas/PVCs forecast to run out soon
+	Quotas          []k8s.QuotaUtilization   `json:"quotas"`    // Most utilized ResourceQuotas first
 }
 
 type DashboardCluster struct {
@@ -191,50 +195,63 @@ type DashboardHelmRelease struct {
 }
 
 func (s *Server) handleDashboard(w http.ResponseWriter, r *http.Request) {
-	namespace := r.URL.Query().Get("namespace")
+	resp, err := s.buildDashboard(r.Context(), r.URL.Query().Get("namespace"))
+	if err != nil {
+		s.writeExplorerError(w, err)
+		return
+	}
+	s.writeJSON(w, resp)
+}
 
+// buildDashboard assembles the dashboard. Health, problems and resource counts come from
+// the state maintained from resource changes; the expensive summaries (Helm, traffic,
+// topology, CRDs, metrics) are reused for dashboardSummaryTTL.
+func (s *Server) buildDashboard(ctx context.Context, namespace string) (*DashboardResponse, error) {
 	cache := k8s.GetResourceCache()
 	if cache == nil {
-		s.writeError(w, http.StatusServiceUnavailable, "Resource cache not available")
-		return
+		return nil, explorerErrors.CacheNotInitialized()
 	}
+	state := s.broadcaster.dashboard
+	// Cached summaries outlive this request, so a cancelled request mustn't cut them short
+	summaryCtx := context.WithoutCancel(ctx)
 
-	resp := DashboardResponse{}
+	resp := &DashboardResponse{}
 
 	// Cluster info
-	resp.Cluster = s.getDashboardCluster(r.Context())
+	resp.Cluster = dashboardCachedSummary(state, "cluster", "", func() DashboardCluster { return s.getDashboardCluster(summaryCtx) })
 
-	// Pod health + workload problems
-	resp.Health, resp.Problems = s.getDashboardHealth(cache, namespace)
-
-	// Resource counts
-	resp.ResourceCounts = s.getDashboardResourceCounts(cache, namespace)
+	// Pod health, workload problems and resource counts (including warning events)
+	resp.Health, resp.Problems, resp.ResourceCounts = state.snapshot(namespace, time.Now())
 
 	// Recent warning events
 	resp.RecentEvents = s.getDashboardRecentEvents(cache, namespace)
 
-	// Count warning events for health banner
-	resp.Health.WarningEvents = s.countWarningEvents(cache, namespace)
-
 	// Recent changes from timeline
-	resp.RecentChanges = s.getDashboardRecentChanges(r.Context(), namespace)
+	resp.RecentChanges = s.getDashboardRecentChanges(ctx, namespace)
 
 	// Topology summary
-	resp.TopologySummary = s.getDashboardTopologySummary(namespace)
+	resp.TopologySummary = dashboardCachedSummary(state, "topology", namespace, func() DashboardTopologySummary { return s.getDashboardTopologySummary(namespace) })
 
 	// Traffic summary
-	resp.TrafficSummary = s.getDashboardTrafficSummary(r.Context(), namespace)
+	resp.TrafficSummary = dashboardCachedSummary(state, "traffic", namespace, func() *DashboardTrafficSummary { return s.getDashboardTrafficSummary(summaryCtx, namespace) })
 
 	// Helm releases summary
-	resp.HelmReleases = s.getDashboardHelmSummary(namespace)
+	resp.HelmReleases = dashboardCachedSummary(state, "helm", namespace, func() DashboardHelmSummary { return s.getDashboardHelmSummary(namespace) })
+	resp.ResourceCounts.HelmReleases = resp.HelmReleases.Total
 
 	// CRD counts
-	resp.TopCRDs = s.getDashboardCRDCounts(r.Context(), namespace)
+	resp.TopCRDs = dashboardCachedSummary(state, "crds", namespace, func() []DashboardCRDCount { return s.getDashboardCRDCounts(summaryCtx, namespace) })
+
+	// Quota/PVC exhaustion within the warning horizon
+	resp.Forecasts = forecastsWithin(k8s.GetUsageForecaster().Forecasts(namespace), k8s.ForecastWarningHorizon)
+
+	// ResourceQuota utilization
+	resp.Quotas = s.getDashboardQuotas(cache, namespace)
 
 	// Cluster metrics (best-effort, nil if metrics-server unavailable)
-	resp.Metrics = s.getDashboardMetrics(r.Context())
+	resp.Metrics = dashboardCachedSummary(state, "metrics", "", func() *DashboardMetrics { return s.getDashboardMetrics(summaryCtx) })
 
-	s.writeJSON(w, resp)
+	return resp, nil
 }
 
 func (s *Server) getDashboardCluster(ctx context.Context) DashboardCluster {
@@ -250,185 +267,104 @@ func (s *Server) getDashboardCluster(ctx context.Context) DashboardCluster {
 	}
 }
 
-func (s *Server) getDashboardHealth(cache *k8s.ResourceCache, namespace string) (DashboardHealth, []DashboardProblem) {
-	health := DashboardHealth{}
-	problems := make([]DashboardProblem, 0)
-
-	now := time.Now()
-
-	// Pod health
-	var pods []*corev1.Pod
-	var err error
-	if namespace != "" {
-		pods, err = cache.Pods().Pods(namespace).List(labels.Everything())
-	} else {
-		pods, err = cache.Pods().List(labels.Everything())
-	}
-	if err == nil {
-		for _, pod := range pods {
-			status := classifyPodHealth(pod, now)
-			switch status {
-			case "healthy":
-				health.Healthy++
-			case "warning":
-				health.Warning++
-				if len(problems) < 20 {
-					problems = append(problems, podToProblem(pod, "warning", now))
-				}
-			case "error":
-				health.Error++
-				if len(problems) < 20 {
-					problems = append([]DashboardProblem{podToProblem(pod, "error", now)}, problems...)
-				}
-			}
+// collectWorkloadProblems returns problems for Deployments, StatefulSets, DaemonSets, and Nodes
+func collectWorkloadProblems(cache *k8s.ResourceCache, namespace string, now time.Time) []DashboardProblem {
+	var problems []DashboardProblem
+	add := func(p *DashboardProblem) {
+		if p != nil {
+			problems = append(problems, *p)
 		}
 	}
 
-	// Deployment problems: unavailableReplicas > 0
+	var deps []*appsv1.Deployment
+	var ssets []*appsv1.StatefulSet
+	var dsets []*appsv1.DaemonSet
 	if namespace != "" {
-		deps, _ := cache.Deployments().Deployments(namespace).List(labels.Everything())
-		for _, d := range deps {
-			if d.Status.UnavailableReplicas > 0 {
-				ageDur := now.Sub(d.CreationTimestamp.Time)
-				problems = append(problems, DashboardProblem{
-					Kind:       "Deployment",
-					Namespace:  d.Namespace,
-					Name:       d.Name,
-					Status:     "error",
-					Reason:     fmt.Sprintf("%d/%d available", d.Status.AvailableReplicas, d.Status.Replicas),
-					Age:        formatAge(ageDur),
-					AgeSeconds: int64(ageDur.Seconds()),
-				})
-			}
-		}
+		deps, _ = cache.Deployments().Deployments(namespace).List(labels.Everything())
+		ssets, _ = cache.StatefulSets().StatefulSets(namespace).List(labels.Everything())
+		dsets, _ = cache.DaemonSets().DaemonSets(namespace).List(labels.Everything())
 	} else {
-		deps, _ := cache.Deployments().List(labels.Everything())
-		for _, d := range deps {
-			if d.Status.UnavailableReplicas > 0 {
-				ageDur := now.Sub(d.CreationTimestamp.Time)
-				problems = append(problems, DashboardProblem{
-					Kind:       "Deployment",
-					Namespace:  d.Namespace,
-					Name:       d.Name,
-					Status:     "error",
-					Reason:     fmt.Sprintf("%d/%d available", d.Status.AvailableReplicas, d.Status.Replicas),
-					Age:        formatAge(ageDur),
-					AgeSeconds: int64(ageDur.Seconds()),
-				})
-			}
-		}
+		deps, _ = cache.Deployments().List(labels.Everything())
+		ssets, _ = cache.StatefulSets().List(labels.Everything())
+		dsets, _ = cache.DaemonSets().List(labels.Everything())
 	}
-
-	// StatefulSet problems: readyReplicas < replicas
-	if namespace != "" {
-		ssets, _ := cache.StatefulSets().StatefulSets(namespace).List(labels.Everything())
-		for _, ss := range ssets {
-			if ss.Status.ReadyReplicas < ss.Status.Replicas {
-				ageDur := now.Sub(ss.CreationTimestamp.Time)
-				problems = append(problems, DashboardProblem{
-					Kind:       "StatefulSet",
-					Namespace:  ss.Namespace,
-					Name:       ss.Name,
-					Status:     "error",
-					Reason:     fmt.Sprintf("%d/%d ready", ss.Status.ReadyReplicas, ss.Status.Replicas),
-					Age:        formatAge(ageDur),
-					AgeSeconds: int64(ageDur.Seconds()),
-				})
-			}
-		}
-	} else {
-		ssets, _ := cache.StatefulSets().List(labels.Everything())
-		for _, ss := range ssets {
-			if ss.Status.ReadyReplicas < ss.Status.Replicas {
-				ageDur := now.Sub(ss.CreationTimestamp.Time)
-				problems = append(problems, DashboardProblem{
-					Kind:       "StatefulSet",
-					Namespace:  ss.Namespace,
-					Name:       ss.Name,
-					Status:     "error",
-					Reason:     fmt.Sprintf("%d/%d ready", ss.Status.ReadyReplicas, ss.Status.Replicas),
-					Age:        formatAge(ageDur),
-					AgeSeconds: int64(ageDur.Seconds()),
-				})
-			}
-		}
+	for _, d := range deps {
+		add(deploymentProblem(d, now))
 	}
-
-	// DaemonSet problems: numberUnavailable > 0
-	if namespace != "" {
-		dsets, _ := cache.DaemonSets().DaemonSets(namespace).List(labels.Everything())
-		for _, ds := range dsets {
-			if ds.Status.NumberUnavailable > 0 {
-				ageDur := now.Sub(ds.CreationTimestamp.Time)
-				problems = append(problems, DashboardProblem{
-					Kind:       "DaemonSet",
-					Namespace:  ds.Namespace,
-					Name:       ds.Name,
-					Status:     "error",
-					Reason:     fmt.Sprintf("%d unavailable", ds.Status.NumberUnavailable),
-					Age:        formatAge(ageDur),
-					AgeSeconds: int64(ageDur.Seconds()),
-				})
-			}
-		}
-	} else {
-		dsets, _ := cache.DaemonSets().List(labels.Everything())
-		for _, ds := range dsets {
-			if ds.Status.NumberUnavailable > 0 {
-				ageDur := now.Sub(ds.CreationTimestamp.Time)
-				problems = append(problems, DashboardProblem{
-					Kind:       "DaemonSet",
-					Namespace:  ds.Namespace,
-					Name:       ds.Name,
-					Status:     "error",
-					Reason:     fmt.Sprintf("%d unavailable", ds.Status.NumberUnavailable),
-					Age:        formatAge(ageDur),
-					AgeSeconds: int64(ageDur.Seconds()),
-				})
-			}
-		}
+	for _, ss := range ssets {
+		add(statefulSetProblem(ss, now))
+	}
+	for _, ds := range dsets {
+		add(daemonSetProblem(ds, now))
 	}
 
-	// Node problems: Ready=False
 	nodes, _ := cache.Nodes().List(labels.Everything())
 	for _, n := range nodes {
-		ready := false
-		for _, cond := range n.Status.Conditions {
-			if cond.Type == corev1.NodeReady && cond.Status == corev1.ConditionTrue {
-				ready = true
-				break
-			}
-		}
-		if !ready {
-			reason := "NotReady"
-			for _, cond := range n.Status.Conditions {
-				if cond.Type == corev1.NodeReady && cond.Message != "" {
-					reason = cond.Message
-					break
-				}
-			}
-			ageDur := now.Sub(n.CreationTimestamp.Time)
-			problems = append(problems, DashboardProblem{
-				Kind:       "Node",
-				Name:       n.Name,
-				Status:     "error",
-				Reason:     reason,
-				Age:        formatAge(ageDur),
-				AgeSeconds: int64(ageDur.Seconds()),
-			})
-		}
+		add(nodeProblem(n, now))
 	}
 
-	// Sort: errors first, then warnings; within each group sort by age (most recent first)
-	sort.SliceStable(problems, func(i, j int) bool {
-		if problems[i].Status != problems[j].Status {
-			return problems[i].Status == "error"
+	return problems
+}
+
+// deploymentProblem reports a Deployment with unavailable replicas
+func deploymentProblem(d *appsv1.Deployment, now time.Time) *DashboardProblem {
+	if d.Status.UnavailableReplicas == 0 {
+		return nil
+	}
+	return workloadProblem("Deployment", d.Namespace, d.Name, fmt.Sprintf("%d/%d available", d.Status.AvailableReplicas, d.Status.Replicas), d.CreationTimestamp.Time, now)
+}
+
+// statefulSetProblem reports a StatefulSet with fewer ready replicas than replicas
+func statefulSetProblem(ss *appsv1.StatefulSet, now time.Time) *DashboardProblem {
+	if ss.Status.ReadyReplicas >= ss.Status.Replicas {
+		return nil
+	}
+	return workloadProblem("StatefulSet", ss.Namespace, ss.Name, fmt.Sprintf("%d/%d ready", ss.Status.ReadyReplicas, ss.Status.Replicas), ss.CreationTimestamp.Time, now)
+}
+
+// daemonSetProblem reports a DaemonSet with unavailable pods
+func daemonSetProblem(ds *appsv1.DaemonSet, now time.Time) *DashboardProblem {
+	if ds.Status.NumberUnavailable == 0 {
+		return nil
+	}
+	return workloadProblem("DaemonSet", ds.Namespace, ds.Name, fmt.Sprintf("%d unavailable", ds.Status.NumberUnavailable), ds.CreationTimestamp.Time, now)
+}
+
+// nodeProblem reports a node that isn't Ready, with the Ready condition's message
+func nodeProblem(n *corev1.Node, now time.Time) *DashboardProblem {
+	if isNodeReady(n) {
+		return nil
+	}
+	reason := "NotReady"
+	for _, cond := range n.Status.Conditions {
+		if cond.Type == corev1.NodeReady && cond.Message != "" {
+			reason = cond.Message
+			break
 		}
-		// Within same status, sort by age (lower AgeSeconds = more recent = first)
-		return problems[i].AgeSeconds < problems[j].AgeSeconds
-	})
+	}
+	return workloadProblem("Node", "", n.Name, reason, n.CreationTimestamp.Time, now)
+}
+
+func workloadProblem(kind, namespace, name, reason string, created, now time.Time) *DashboardProblem {
+	ageDur := now.Sub(created)
+	return &DashboardProblem{
+		Kind:       kind,
+		Namespace:  namespace,
+		Name:       name,
+		Status:     "error",
+		Reason:     reason,
+		Age:        formatAge(ageDur),
+		AgeSeconds: int64(ageDur.Seconds()),
+	}
+}
 
-	return health, problems
+func isNodeReady(n *corev1.Node) bool {
+	for _, cond := range n.Status.Conditions {
+		if cond.Type == corev1.NodeReady && cond.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
 }
 
 // classifyPodHealth determines if a pod is healthy, warning, or error
@@ -545,256 +481,6 @@ func podToProblem(pod *corev1.Pod, severity string, now time.Time) DashboardProb
 	}
 }
 
-func (s *Server) getDashboardResourceCounts(cache *k8s.ResourceCache, namespace string) DashboardResourceCounts {
-	counts := DashboardResourceCounts{}
-
-	// Pods
-	var pods []*corev1.Pod
-	if namespace != "" {
-		pods, _ = cache.Pods().Pods(namespace).List(labels.Everything())
-	} else {
-		pods, _ = cache.Pods().List(labels.Everything())
-	}
-	counts.Pods.Total = len(pods)
-	for _, pod := range pods {
-		switch pod.Status.Phase {
-		case corev1.PodRunning:
-			counts.Pods.Running++
-		case corev1.PodPending:
-			counts.Pods.Pending++
-		case corev1.PodFailed:
-			counts.Pods.Failed++
-		case corev1.PodSucceeded:
-			counts.Pods.Succeeded++
-		}
-	}
-
-	// Deployments
-	if namespace != "" {
-		deps, _ := cache.Deployments().Deployments(namespace).List(labels.Everything())
-		counts.Deployments.Total = len(deps)
-		for _, d := range deps {
-			if d.Status.AvailableReplicas == d.Status.Replicas && d.Status.Replicas > 0 {
-				counts.Deployments.Available++
-			} else if d.Status.Replicas > 0 {
-				counts.Deployments.Unavailable++
-			}
-		}
-	} else {
-		deps, _ := cache.Deployments().List(labels.Everything())
-		counts.Deployments.Total = len(deps)
-		for _, d := range deps {
-			if d.Status.AvailableReplicas == d.Status.Replicas && d.Status.Replicas > 0 {
-				counts.Deployments.Available++
-			} else if d.Status.Replicas > 0 {
-				counts.Deployments.Unavailable++
-			}
-		}
-	}
-
-	// StatefulSets (only count those with replicas > 0)
-	if namespace != "" {
-		ssets, _ := cache.StatefulSets().StatefulSets(namespace).List(labels.Everything())
-		for _, ss := range ssets {
-			if ss.Status.Replicas == 0 {
-				continue
-			}
-			counts.StatefulSets.Total++
-			if ss.Status.ReadyReplicas == ss.Status.Replicas {
-				counts.StatefulSets.Ready++
-			} else {
-				counts.StatefulSets.Unready++
-			}
-		}
-	} else {
-		ssets, _ := cache.StatefulSets().List(labels.Everything())
-		for _, ss := range ssets {
-			if ss.Status.Replicas == 0 {
-				continue
-			}
-			counts.StatefulSets.Total++
-			if ss.Status.ReadyReplicas == ss.Status.Replicas {
-				counts.StatefulSets.Ready++
-			} else {
-				counts.StatefulSets.Unready++
-			}
-		}
-	}
-
-	// DaemonSets (only count those with desired > 0)
-	if namespace != "" {
-		dsets, _ := cache.DaemonSets().DaemonSets(namespace).List(labels.Everything())
-		for _, ds := range dsets {
-			if ds.Status.DesiredNumberScheduled == 0 {
-				continue
-			}
-			counts.DaemonSets.Total++
-			if ds.Status.NumberUnavailable == 0 {
-				counts.DaemonSets.Ready++
-			} else {
-				counts.DaemonSets.Unready++
-			}
-		}
-	} else {
-		dsets, _ := cache.DaemonSets().List(labels.Everything())
-		for _, ds := range dsets {
-			if ds.Status.DesiredNumberScheduled == 0 {
-				continue
-			}
-			counts.DaemonSets.Total++
-			if ds.Status.NumberUnavailable == 0 {
-				counts.DaemonSets.Ready++
-			} else {
-				counts.DaemonSets.Unready++
-			}
-		}
-	}
-
-	// Services
-	if namespace != "" {
-		svcs, _ := cache.Services().Services(namespace).List(labels.Everything())
-		counts.Services = len(svcs)
-	} else {
-		svcs, _ := cache.Services().List(labels.Everything())
-		counts.Services = len(svcs)
-	}
-
-	// Ingresses
-	if namespace != "" {
-		ings, _ := cache.Ingresses().Ingresses(namespace).List(labels.Everything())
-		counts.Ingresses = len(ings)
-	} else {
-		ings, _ := cache.Ingresses().List(labels.Everything())
-		counts.Ingresses = len(ings)
-	}
-
-	// Nodes (cluster-scoped, not filtered by namespace)
-	nodes, _ := cache.Nodes().List(labels.Everything())
-	counts.Nodes.Total = len(nodes)
-	for _, n := range nodes {
-		ready := false
-		for _, cond := range n.Status.Conditions {
-			if cond.Type == corev1.NodeReady && cond.Status == corev1.ConditionTrue {
-				ready = true
-				break
-			}
-		}
-		if ready {
-			counts.Nodes.Ready++
-		} else {
-			counts.Nodes.NotReady++
-		}
-	}
-
-	// Namespaces (cluster-scoped)
-	nss, _ := cache.Namespaces().List(labels.Everything())
-	counts.Namespaces = len(nss)
-
-	// Jobs
-	if namespace != "" {
-		jobs, _ := cache.Jobs().Jobs(namespace).List(labels.Everything())
-		counts.Jobs.Total = len(jobs)
-		for _, j := range jobs {
-			if j.Status.Active > 0 {
-				counts.Jobs.Active++
-			}
-			counts.Jobs.Succeeded += int(j.Status.Succeeded)
-			counts.Jobs.Failed += int(j.Status.Failed)
-		}
-	} else {
-		jobs, _ := cache.Jobs().List(labels.Everything())
-		counts.Jobs.Total = len(jobs)
-		for _, j := range jobs {
-			if j.Status.Active > 0 {
-				counts.Jobs.Active++
-			}
-			counts.Jobs.Succeeded += int(j.Status.Succeeded)
-			counts.Jobs.Failed += int(j.Status.Failed)
-		}
-	}
-
-	// CronJobs
-	if namespace != "" {
-		cronJobs, _ := cache.CronJobs().CronJobs(namespace).List(labels.Everything())
-		counts.CronJobs.Total = len(cronJobs)
-		for _, cj := range cronJobs {
-			if cj.Spec.Suspend != nil && *cj.Spec.Suspend {
-				counts.CronJobs.Suspended++
-			} else if len(cj.Status.Active) > 0 {
-				counts.CronJobs.Active++
-			}
-		}
-	} else {
-		cronJobs, _ := cache.CronJobs().List(labels.Everything())
-		counts.CronJobs.Total = len(cronJobs)
-		for _, cj := range cronJobs {
-			if cj.Spec.Suspend != nil && *cj.Spec.Suspend {
-				counts.CronJobs.Suspended++
-			} else if len(cj.Status.Active) > 0 {
-				counts.CronJobs.Active++
-			}
-		}
-	}
-
-	// ConfigMaps
-	if namespace != "" {
-		cms, _ := cache.ConfigMaps().ConfigMaps(namespace).List(labels.Everything())
-		counts.ConfigMaps = len(cms)
-	} else {
-		cms, _ := cache.ConfigMaps().List(labels.Everything())
-		counts.ConfigMaps = len(cms)
-	}
-
-	// Secrets
-	if namespace != "" {
-		secrets, _ := cache.Secrets().Secrets(namespace).List(labels.Everything())
-		counts.Secrets = len(secrets)
-	} else {
-		secrets, _ := cache.Secrets().List(labels.Everything())
-		counts.Secrets = len(secrets)
-	}
-
-	// PVCs
-	if namespace != "" {
-		pvcs, _ := cache.PersistentVolumeClaims().PersistentVolumeClaims(namespace).List(labels.Everything())
-		counts.PVCs.Total = len(pvcs)
-		for _, pvc := range pvcs {
-			switch pvc.Status.Phase {
-			case corev1.ClaimBound:
-				counts.PVCs.Bound++
-			case corev1.ClaimPending:
-				counts.PVCs.Pending++
-			default:
-				counts.PVCs.Unbound++
-			}
-		}
-	} else {
-		pvcs, _ := cache.PersistentVolumeClaims().List(labels.Everything())
-		counts.PVCs.Total = len(pvcs)
-		for _, pvc := range pvcs {
-			switch pvc.Status.Phase {
-			case corev1.ClaimBound:
-				counts.PVCs.Bound++
-			case corev1.ClaimPending:
-				counts.PVCs.Pending++
-			default:
-				counts.PVCs.Unbound++
-			}
-		}
-	}
-
-	// Helm releases count
-	helmClient := helm.GetClient()
-	if helmClient != nil {
-		releases, err := helmClient.ListReleases(namespace)
-		if err == nil {
-			counts.HelmReleases = len(releases)
-		}
-	}
-
-	return counts
-}
-
 func (s *Server) getDashboardRecentEvents(cache *k8s.ResourceCache, namespace string) []DashboardEvent {
 	var events []*corev1.Event
 	var err error
@@ -1014,20 +700,34 @@ func (s *Server) getDashboardHelmSummary(namespace string) DashboardHelmSummary
 	return result
 }
 
-func (s *Server) countWarningEvents(cache *k8s.ResourceCache, namespace string) int {
-	var events []*corev1.Event
+// dashboardMaxQuotas caps the quotas shown across all namespaces
+const dashboardMaxQuotas = 10
+
+// getDashboardQuotas returns quota utilization, most utilized first. All of a namespace's
+// quotas are shown when one is selected; cluster-wide, only the top few.
+func (s *Server) getDashboardQuotas(cache *k8s.ResourceCache, namespace string) []k8s.QuotaUtilization {
+	var quotas []*corev1.ResourceQuota
 	if namespace != "" {
-		events, _ = cache.Events().Events(namespace).List(labels.Everything())
+		quotas, _ = cache.ResourceQuotas().ResourceQuotas(namespace).List(labels.Everything())
 	} else {
-		events, _ = cache.Events().List(labels.Everything())
+		quotas, _ = cache.ResourceQuotas().List(labels.Everything())
 	}
-	count := 0
-	for _, e := range events {
-		if e.Type == "Warning" {
-			count++
+	result := make([]k8s.QuotaUtilization, 0, len(quotas))
+	for _, q := range quotas {
+		if u := k8s.GetQuotaUtilization(q); len(u.Resources) > 0 {
+			result = append(result, u)
 		}
 	}
-	return count
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].MaxPercent != result[j].MaxPercent {
+			return result[i].MaxPercent > result[j].MaxPercent
+		}
+		return result[i].Namespace+"/"+result[i].Name < result[j].Namespace+"/"+result[j].Name
+	})
+	if namespace == "" && len(result) > dashboardMaxQuotas {
+		result = result[:dashboardMaxQuotas]
+	}
+	return result
 }
 
 func (s *Server) getDashboardMetrics(ctx context.Context) *DashboardMetrics {
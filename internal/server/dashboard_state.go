@@ -0,0 +1,727 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+const (
+	// dashboardResync rebuilds the dashboard state from the cache, correcting drift from
+	// change notifications dropped under load and re-classifying pods that have been
+	// pending past the warning threshold without changing
+	dashboardResync = time.Minute
+	// dashboardSummaryTTL is how long Helm, traffic, topology, CRD and metrics summaries
+	// are reused before being recomputed
+	dashboardSummaryTTL = 30 * time.Second
+	// dashboardMaxPodProblems caps the pod problems listed, errors and recent ones first
+	dashboardMaxPodProblems = 20
+)
+
+// dashboardObject is one object's contribution to the dashboard's counters and problems.
+// It's comparable, so updates that change nothing the dashboard shows are ignored.
+type dashboardObject struct {
+	namespace  string // "" for cluster-scoped objects, which every namespace's view includes
+	counts     DashboardResourceCounts
+	health     DashboardHealth
+	problem    DashboardProblem // Without its age, which is computed when served
+	hasProblem bool
+	created    time.Time
+}
+
+// dashboardTotals sums the contributions of one namespace's objects
+type dashboardTotals struct {
+	counts   DashboardResourceCounts
+	health   DashboardHealth
+	problems map[string]*dashboardObject
+}
+
+// dashboardState maintains the dashboard's counters, pod health and problems from
+// resource changes, so requests don't re-classify every pod in the cluster
+type dashboardState struct {
+	mu          sync.RWMutex
+	ready       bool // Built from the cache; cleared on context switch
+	objects     map[string]*dashboardObject
+	byNamespace map[string]*dashboardTotals
+
+	summariesMu sync.Mutex
+	summaries   map[string]dashboardSummary
+
+	watchersMu sync.Mutex
+	watchers   map[chan struct{}]struct{}
+}
+
+// dashboardSummary is a cached dashboard section
+type dashboardSummary struct {
+	value   any
+	expires time.Time
+}
+
+func newDashboardState() *dashboardState {
+	return &dashboardState{
+		summaries: make(map[string]dashboardSummary),
+		watchers:  make(map[chan struct{}]struct{}),
+	}
+}
+
+// run rebuilds the state periodically until stopCh closes
+func (d *dashboardState) run(stopCh <-chan struct{}) {
+	ticker := time.NewTicker(dashboardResync)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			// Until a request builds it (again, after a reset), there's nothing to correct
+			d.mu.RLock()
+			ready := d.ready
+			d.mu.RUnlock()
+			if ready {
+				d.rebuild()
+				d.notify()
+			}
+		}
+	}
+}
+
+// reset drops the state and cached summaries, e.g. after a context switch
+func (d *dashboardState) reset() {
+	d.mu.Lock()
+	d.ready, d.objects, d.byNamespace = false, nil, nil
+	d.mu.Unlock()
+	d.summariesMu.Lock()
+	d.summaries = make(map[string]dashboardSummary)
+	d.summariesMu.Unlock()
+	d.notify()
+}
+
+// ensure builds the state on first use
+func (d *dashboardState) ensure() {
+	d.mu.RLock()
+	ready := d.ready
+	d.mu.RUnlock()
+	if !ready {
+		d.rebuild()
+	}
+}
+
+// rebuild recomputes every contribution from the cache. The write lock is held
+// throughout, so a change applied concurrently lands on the new state.
+func (d *dashboardState) rebuild() {
+	cache := k8s.GetResourceCache()
+	if cache == nil {
+		return
+	}
+	start := time.Now()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.objects = make(map[string]*dashboardObject)
+	d.byNamespace = make(map[string]*dashboardTotals)
+	for kind, k := range dashboardKinds {
+		items, err := k.list(cache)
+		if err != nil {
+			continue
+		}
+		for _, item := range items {
+			if obj := dashboardContribution(item, start); obj != nil {
+				d.set(dashboardKey(kind, obj.namespace, objectName(item)), obj)
+			}
+		}
+	}
+	d.ready = true
+	if elapsed := time.Since(start); elapsed > time.Second {
+		log.Printf("[dashboard] Rebuilt state from %d objects in %v", len(d.objects), elapsed)
+	}
+}
+
+// apply updates the contribution of a changed object. Kinds the dashboard doesn't count
+// are ignored.
+func (d *dashboardState) apply(change k8s.ResourceChange) {
+	k, ok := dashboardKinds[change.Kind]
+	if !ok || change.Group != "" {
+		return
+	}
+	cache := k8s.GetResourceCache()
+	if cache == nil {
+		return
+	}
+	var obj *dashboardObject
+	if change.Operation != "delete" {
+		if item, err := k.get(cache, change.Namespace, change.Name); err == nil {
+			obj = dashboardContribution(item, time.Now())
+		}
+	}
+
+	key := dashboardKey(change.Kind, change.Namespace, change.Name)
+	d.mu.Lock()
+	if !d.ready {
+		d.mu.Unlock()
+		return
+	}
+	old := d.objects[key]
+	changed := !sameDashboardObject(old, obj)
+	if changed {
+		d.remove(key)
+		if obj != nil {
+			d.set(key, obj)
+		}
+	}
+	d.mu.Unlock()
+
+	// Recent warning events change without changing the warning count
+	if changed || (change.Kind == "Event" && (obj != nil || old != nil)) {
+		d.notify()
+	}
+}
+
+func sameDashboardObject(a, b *dashboardObject) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// set adds a contribution; the caller holds the write lock and has removed any old one
+func (d *dashboardState) set(key string, obj *dashboardObject) {
+	d.objects[key] = obj
+	t := d.totals(obj.namespace)
+	t.counts.add(obj.counts, 1)
+	t.health.add(obj.health, 1)
+	if obj.hasProblem {
+		t.problems[key] = obj
+	}
+}
+
+// remove drops a contribution; the caller holds the write lock
+func (d *dashboardState) remove(key string) {
+	obj, ok := d.objects[key]
+	if !ok {
+		return
+	}
+	delete(d.objects, key)
+	t := d.totals(obj.namespace)
+	t.counts.add(obj.counts, -1)
+	t.health.add(obj.health, -1)
+	delete(t.problems, key)
+}
+
+func (d *dashboardState) totals(namespace string) *dashboardTotals {
+	t, ok := d.byNamespace[namespace]
+	if !ok {
+		t = &dashboardTotals{problems: make(map[string]*dashboardObject)}
+		d.byNamespace[namespace] = t
+	}
+	return t
+}
+
+// snapshot returns the health, problems and resource counts of one namespace (with
+// cluster-scoped objects such as nodes) or of the whole cluster
+func (d *dashboardState) snapshot(namespace string, now time.Time) (DashboardHealth, []DashboardProblem, DashboardResourceCounts) {
+	d.ensure()
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	var health DashboardHealth
+	var counts DashboardResourceCounts
+	var podProblems []DashboardProblem
+	problems := make([]DashboardProblem, 0)
+	for ns, t := range d.byNamespace {
+		if namespace != "" && ns != namespace && ns != "" {
+			continue
+		}
+		health.add(t.health, 1)
+		counts.add(t.counts, 1)
+		for _, obj := range t.problems {
+			p := obj.problem
+			ageDur := now.Sub(obj.created)
+			p.Age, p.AgeSeconds = formatAge(ageDur), int64(ageDur.Seconds())
+			if p.Kind == "Pod" {
+				podProblems = append(podProblems, p)
+			} else {
+				problems = append(problems, p)
+			}
+		}
+	}
+
+	sortDashboardProblems(podProblems)
+	if len(podProblems) > dashboardMaxPodProblems {
+		podProblems = podProblems[:dashboardMaxPodProblems]
+	}
+	problems = append(podProblems, problems...)
+	sortDashboardProblems(problems)
+	return health, problems, counts
+}
+
+// sortDashboardProblems orders errors before warnings, most recent first within each
+func sortDashboardProblems(problems []DashboardProblem) {
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].Status != problems[j].Status {
+			return problems[i].Status == "error"
+		}
+		if problems[i].AgeSeconds != problems[j].AgeSeconds {
+			return problems[i].AgeSeconds < problems[j].AgeSeconds
+		}
+		return problems[i].Namespace+"/"+problems[i].Name < problems[j].Namespace+"/"+problems[j].Name
+	})
+}
+
+// dashboardCachedSummary returns a cached dashboard section, computing it when missing or expired.
+// Concurrent misses may compute it more than once; the last result is kept.
+func dashboardCachedSummary[T any](d *dashboardState, section, namespace string, compute func() T) T {
+	key := section + "/" + namespace
+	d.summariesMu.Lock()
+	cached, ok := d.summaries[key]
+	d.summariesMu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.value.(T)
+	}
+	value := compute()
+	d.summariesMu.Lock()
+	d.summaries[key] = dashboardSummary{value: value, expires: time.Now().Add(dashboardSummaryTTL)}
+	d.summariesMu.Unlock()
+	return value
+}
+
+// watch registers for notifications that the dashboard changed. Returns nil if too many
+// streams are open.
+func (d *dashboardState) watch() chan struct{} {
+	d.watchersMu.Lock()
+	defer d.watchersMu.Unlock()
+	if len(d.watchers) >= MaxSSEClients {
+		return nil
+	}
+	ch := make(chan struct{}, 1)
+	d.watchers[ch] = struct{}{}
+	return ch
+}
+
+func (d *dashboardState) unwatch(ch chan struct{}) {
+	d.watchersMu.Lock()
+	delete(d.watchers, ch)
+	d.watchersMu.Unlock()
+}
+
+// notify wakes the dashboard streams. Notifications coalesce.
+func (d *dashboardState) notify() {
+	d.watchersMu.Lock()
+	defer d.watchersMu.Unlock()
+	for ch := range d.watchers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func dashboardKey(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
+
+func objectName(item any) string {
+	if obj, ok := item.(interface{ GetName() string }); ok {
+		return obj.GetName()
+	}
+	return ""
+}
+
+// dashboardKind lists and gets the cached objects of a kind the dashboard counts
+type dashboardKind struct {
+	list func(*k8s.ResourceCache) ([]any, error)
+	get  func(c *k8s.ResourceCache, namespace, name string) (any, error)
+}
+
+func anyList[T any](items []T, err error) ([]any, error) {
+	result := make([]any, len(items))
+	for i, item := range items {
+		result[i] = item
+	}
+	return result, err
+}
+
+// dashboardKinds are the kinds the dashboard counts, by Kind
+var dashboardKinds = map[string]dashboardKind{
+	"Pod": {
+		list: func(c *k8s.ResourceCache) ([]any, error) { return anyList(c.Pods().List(labels.Everything())) },
+		get:  func(c *k8s.ResourceCache, ns, name string) (any, error) { return c.Pods().Pods(ns).Get(name) },
+	},
+	"Deployment": {
+		list: func(c *k8s.ResourceCache) ([]any, error) { return anyList(c.Deployments().List(labels.Everything())) },
+		get: func(c *k8s.ResourceCache, ns, name string) (any, error) {
+			return c.Deployments().Deployments(ns).Get(name)
+		},
+	},
+	"StatefulSet": {
+		list: func(c *k8s.ResourceCache) ([]any, error) { return anyList(c.StatefulSets().List(labels.Everything())) },
+		get: func(c *k8s.ResourceCache, ns, name string) (any, error) {
+			return c.StatefulSets().StatefulSets(ns).Get(name)
+		},
+	},
+	"DaemonSet": {
+		list: func(c *k8s.ResourceCache) ([]any, error) { return anyList(c.DaemonSets().List(labels.Everything())) },
+		get: func(c *k8s.ResourceCache, ns, name string) (any, error) {
+			return c.DaemonSets().DaemonSets(ns).Get(name)
+		},
+	},
+	"Service": {
+		list: func(c *k8s.ResourceCache) ([]any, error) { return anyList(c.Services().List(labels.Everything())) },
+		get:  func(c *k8s.ResourceCache, ns, name string) (any, error) { return c.Services().Services(ns).Get(name) },
+	},
+	"Ingress": {
+		list: func(c *k8s.ResourceCache) ([]any, error) { return anyList(c.Ingresses().List(labels.Everything())) },
+		get:  func(c *k8s.ResourceCache, ns, name string) (any, error) { return c.Ingresses().Ingresses(ns).Get(name) },
+	},
+	"Node": {
+		list: func(c *k8s.ResourceCache) ([]any, error) { return anyList(c.Nodes().List(labels.Everything())) },
+		get:  func(c *k8s.ResourceCache, _, name string) (any, error) { return c.Nodes().Get(name) },
+	},
+	"Namespace": {
+		list: func(c *k8s.ResourceCache) ([]any, error) { return anyList(c.Namespaces().List(labels.Everything())) },
+		get:  func(c *k8s.ResourceCache, _, name string) (any, error) { return c.Namespaces().Get(name) },
+	},
+	"Job": {
+		list: func(c *k8s.ResourceCache) ([]any, error) { return anyList(c.Jobs().List(labels.Everything())) },
+		get:  func(c *k8s.ResourceCache, ns, name string) (any, error) { return c.Jobs().Jobs(ns).Get(name) },
+	},
+	"CronJob": {
+		list: func(c *k8s.ResourceCache) ([]any, error) { return anyList(c.CronJobs().List(labels.Everything())) },
+		get:  func(c *k8s.ResourceCache, ns, name string) (any, error) { return c.CronJobs().CronJobs(ns).Get(name) },
+	},
+	"ConfigMap": {
+		list: func(c *k8s.ResourceCache) ([]any, error) { return anyList(c.ConfigMaps().List(labels.Everything())) },
+		get: func(c *k8s.ResourceCache, ns, name string) (any, error) {
+			return c.ConfigMaps().ConfigMaps(ns).Get(name)
+		},
+	},
+	"Secret": {
+		list: func(c *k8s.ResourceCache) ([]any, error) {
+			if c.Secrets() == nil {
+				return nil, nil
+			}
+			return anyList(c.Secrets().List(labels.Everything()))
+		},
+		get: func(c *k8s.ResourceCache, ns, name string) (any, error) {
+			if c.Secrets() == nil {
+				return nil, errors.New("secrets aren't cached")
+			}
+			return c.Secrets().Secrets(ns).Get(name)
+		},
+	},
+	"PersistentVolumeClaim": {
+		list: func(c *k8s.ResourceCache) ([]any, error) {
+			return anyList(c.PersistentVolumeClaims().List(labels.Everything()))
+		},
+		get: func(c *k8s.ResourceCache, ns, name string) (any, error) {
+			return c.PersistentVolumeClaims().PersistentVolumeClaims(ns).Get(name)
+		},
+	},
+	"Event": {
+		list: func(c *k8s.ResourceCache) ([]any, error) { return anyList(c.Events().List(labels.Everything())) },
+		get:  func(c *k8s.ResourceCache, ns, name string) (any, error) { return c.Events().Events(ns).Get(name) },
+	},
+}
+
+// dashboardContribution returns what an object adds to the dashboard, or nil
+func dashboardContribution(item any, now time.Time) *dashboardObject {
+	obj := &dashboardObject{}
+	var problem *DashboardProblem
+	switch o := item.(type) {
+	case *corev1.Pod:
+		obj.namespace, obj.created = o.Namespace, o.CreationTimestamp.Time
+		obj.counts.Pods.Total = 1
+		switch o.Status.Phase {
+		case corev1.PodRunning:
+			obj.counts.Pods.Running = 1
+		case corev1.PodPending:
+			obj.counts.Pods.Pending = 1
+		case corev1.PodFailed:
+			obj.counts.Pods.Failed = 1
+		case corev1.PodSucceeded:
+			obj.counts.Pods.Succeeded = 1
+		}
+		switch status := classifyPodHealth(o, now); status {
+		case "healthy":
+			obj.health.Healthy = 1
+		case "warning", "error":
+			if status == "warning" {
+				obj.health.Warning = 1
+			} else {
+				obj.health.Error = 1
+			}
+			p := podToProblem(o, status, now)
+			problem = &p
+		}
+	case *appsv1.Deployment:
+		obj.namespace, obj.created = o.Namespace, o.CreationTimestamp.Time
+		obj.counts.Deployments.Total = 1
+		if o.Status.AvailableReplicas == o.Status.Replicas && o.Status.Replicas > 0 {
+			obj.counts.Deployments.Available = 1
+		} else if o.Status.Replicas > 0 {
+			obj.counts.Deployments.Unavailable = 1
+		}
+		problem = deploymentProblem(o, now)
+	case *appsv1.StatefulSet:
+		obj.namespace, obj.created = o.Namespace, o.CreationTimestamp.Time
+		if o.Status.Replicas > 0 { // Only count those with replicas
+			obj.counts.StatefulSets.Total = 1
+			if o.Status.ReadyReplicas == o.Status.Replicas {
+				obj.counts.StatefulSets.Ready = 1
+			} else {
+				obj.counts.StatefulSets.Unready = 1
+			}
+		}
+		problem = statefulSetProblem(o, now)
+	case *appsv1.DaemonSet:
+		obj.namespace, obj.created = o.Namespace, o.CreationTimestamp.Time
+		if o.Status.DesiredNumberScheduled > 0 { // Only count those with desired pods
+			obj.counts.DaemonSets.Total = 1
+			if o.Status.NumberUnavailable == 0 {
+				obj.counts.DaemonSets.Ready = 1
+			} else {
+				obj.counts.DaemonSets.Unready = 1
+			}
+		}
+		problem = daemonSetProblem(o, now)
+	case *corev1.Service:
+		obj.namespace = o.Namespace
+		obj.counts.Services = 1
+	case *networkingv1.Ingress:
+		obj.namespace = o.Namespace
+		obj.counts.Ingresses = 1
+	case *corev1.Node:
+		obj.created = o.CreationTimestamp.Time
+		obj.counts.Nodes.Total = 1
+		if isNodeReady(o) {
+			obj.counts.Nodes.Ready = 1
+		} else {
+			obj.counts.Nodes.NotReady = 1
+		}
+		problem = nodeProblem(o, now)
+	case *corev1.Namespace:
+		obj.counts.Namespaces = 1
+	case *batchv1.Job:
+		obj.namespace = o.Namespace
+		obj.counts.Jobs.Total = 1
+		if o.Status.Active > 0 {
+			obj.counts.Jobs.Active = 1
+		}
+		obj.counts.Jobs.Succeeded = int(o.Status.Succeeded)
+		obj.counts.Jobs.Failed = int(o.Status.Failed)
+	case *batchv1.CronJob:
+		obj.namespace = o.Namespace
+		obj.counts.CronJobs.Total = 1
+		if o.Spec.Suspend != nil && *o.Spec.Suspend {
+			obj.counts.CronJobs.Suspended = 1
+		} else if len(o.Status.Active) > 0 {
+			obj.counts.CronJobs.Active = 1
+		}
+	case *corev1.ConfigMap:
+		obj.namespace = o.Namespace
+		obj.counts.ConfigMaps = 1
+	case *corev1.Secret:
+		obj.namespace = o.Namespace
+		obj.counts.Secrets = 1
+	case *corev1.PersistentVolumeClaim:
+		obj.namespace = o.Namespace
+		obj.counts.PVCs.Total = 1
+		switch o.Status.Phase {
+		case corev1.ClaimBound:
+			obj.counts.PVCs.Bound = 1
+		case corev1.ClaimPending:
+			obj.counts.PVCs.Pending = 1
+		default:
+			obj.counts.PVCs.Unbound = 1
+		}
+	case *corev1.Event:
+		if o.Type != corev1.EventTypeWarning {
+			return nil
+		}
+		obj.namespace = o.Namespace
+		obj.health.WarningEvents = 1
+	default:
+		return nil
+	}
+	if problem != nil {
+		obj.problem, obj.hasProblem = *problem, true
+		obj.problem.Age, obj.problem.AgeSeconds = "", 0
+	}
+	return obj
+}
+
+// add adds (sign 1) or subtracts (sign -1) o's counts
+func (c *DashboardResourceCounts) add(o DashboardResourceCounts, sign int) {
+	c.Pods.Total += sign * o.Pods.Total
+	c.Pods.Running += sign * o.Pods.Running
+	c.Pods.Pending += sign * o.Pods.Pending
+	c.Pods.Failed += sign * o.Pods.Failed
+	c.Pods.Succeeded += sign * o.Pods.Succeeded
+	c.Deployments.Total += sign * o.Deployments.Total
+	c.Deployments.Available += sign * o.Deployments.Available
+	c.Deployments.Unavailable += sign * o.Deployments.Unavailable
+	c.StatefulSets.add(o.StatefulSets, sign)
+	c.DaemonSets.add(o.DaemonSets, sign)
+	c.Services += sign * o.Services
+	c.Ingresses += sign * o.Ingresses
+	c.Nodes.Total += sign * o.Nodes.Total
+	c.Nodes.Ready += sign * o.Nodes.Ready
+	c.Nodes.NotReady += sign * o.Nodes.NotReady
+	c.Namespaces += sign * o.Namespaces
+	c.Jobs.Total += sign * o.Jobs.Total
+	c.Jobs.Active += sign * o.Jobs.Active
+	c.Jobs.Succeeded += sign * o.Jobs.Succeeded
+	c.Jobs.Failed += sign * o.Jobs.Failed
+	c.CronJobs.Total += sign * o.CronJobs.Total
+	c.CronJobs.Active += sign * o.CronJobs.Active
+	c.CronJobs.Suspended += sign * o.CronJobs.Suspended
+	c.ConfigMaps += sign * o.ConfigMaps
+	c.Secrets += sign * o.Secrets
+	c.PVCs.Total += sign * o.PVCs.Total
+	c.PVCs.Bound += sign * o.PVCs.Bound
+	c.PVCs.Pending += sign * o.PVCs.Pending
+	c.PVCs.Unbound += sign * o.PVCs.Unbound
+}
+
+func (c *WorkloadCount) add(o WorkloadCount, sign int) {
+	c.Total += sign * o.Total
+	c.Ready += sign * o.Ready
+	c.Unready += sign * o.Unready
+}
+
+func (h *DashboardHealth) add(o DashboardHealth, sign int) {
+	h.Healthy += sign * o.Healthy
+	h.Warning += sign * o.Warning
+	h.Error += sign * o.Error
+	h.WarningEvents += sign * o.WarningEvents
+}
+
+// dashboardStreamRefresh re-sends what changed without a resource change: ages, recent
+// changes from the timeline, and summaries whose cache expired
+const dashboardStreamRefresh = 15 * time.Second
+
+// handleDashboardStream streams the dashboard as SSE: a "dashboard" event with the full
+// response, then "sections" events with only the top-level fields that changed (e.g.
+// {"health": ..., "problems": ...}) as resource changes update the maintained state.
+// GET /api/dashboard/stream?namespace=
+func (s *Server) handleDashboardStream(w http.ResponseWriter, r *http.Request) {
+	namespace := r.URL.Query().Get("namespace")
+	state := s.broadcaster.dashboard
+
+	resp, err := s.buildDashboard(r.Context(), namespace)
+	if err != nil {
+		s.writeExplorerError(w, err)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		s.writeError(w, http.StatusInternalServerError, "Streaming not supported")
+		return
+	}
+	watcher := state.watch()
+	if watcher == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "Too many dashboard streams")
+		return
+	}
+	defer state.unwatch(watcher)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	send := func(event string, data []byte) bool {
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data); err != nil {
+			return false
+		}
+		flusher.Flush()
+		return true
+	}
+
+	sent, err := dashboardSections(resp)
+	if err != nil {
+		return
+	}
+	full, err := json.Marshal(resp)
+	if err != nil || !send("dashboard", full) {
+		return
+	}
+
+	refresh := time.NewTicker(dashboardStreamRefresh)
+	defer refresh.Stop()
+	debounce := time.NewTimer(listSyncDebounce)
+	debounce.Stop()
+	pending := false
+
+	push := func() bool {
+		resp, err := s.buildDashboard(r.Context(), namespace)
+		if err != nil {
+			// Cache rebuilding (e.g. context switch); retry on the next change
+			return true
+		}
+		sections, err := dashboardSections(resp)
+		if err != nil {
+			return true
+		}
+		changed := make(map[string]json.RawMessage)
+		for name, data := range sections {
+			if !bytes.Equal(sent[name], data) {
+				changed[name] = data
+			}
+		}
+		sent = sections
+		if len(changed) == 0 {
+			// Keep proxies from closing an idle stream
+			_, err := w.Write([]byte(": heartbeat\n\n"))
+			flusher.Flush()
+			return err == nil
+		}
+		data, err := json.Marshal(changed)
+		return err == nil && send("sections", data)
+	}
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-watcher:
+			if !pending {
+				pending = true
+				debounce.Reset(listSyncDebounce)
+			}
+		case <-debounce.C:
+			pending = false
+			if !push() {
+				return
+			}
+		case <-refresh.C:
+			if !push() {
+				return
+			}
+		}
+	}
+}
+
+// dashboardSections splits a dashboard response into its top-level JSON fields
+func dashboardSections(resp *DashboardResponse) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return nil, err
+	}
+	var sections map[string]json.RawMessage
+	err = json.Unmarshal(data, &sections)
+	return sections, err
+}
@@ -1,12 +1,17 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"runtime/debug"
+	"slices"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/gorilla/websocket"
@@ -14,6 +19,8 @@ import (
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/remotecommand"
 
+	"github.com/skyhook-io/radar/internal/auth"
+	"github.com/skyhook-io/radar/internal/execaudit"
 	"github.com/skyhook-io/radar/internal/k8s"
 )
 
@@ -29,7 +36,12 @@ type ExecSession struct {
 	Namespace string `json:"namespace"`
 	Pod       string `json:"pod"`
 	Container string `json:"container"`
-	conn      *websocket.Conn
+	Shell     string `json:"shell,omitempty"` // For pod exec, the shell that was found
+
+	ownerToken string               // Proves ownership when managing share links and reattaching
+	hub        *terminalHub         // Fans output out to the owner, observers and co-drivers
+	recorder   *execaudit.Recorder  // nil when sessions aren't recorded
+	reattach   chan *websocket.Conn // The owner's new connection after a dropped one
 }
 
 // execSessionManager tracks active exec sessions
@@ -50,21 +62,25 @@ func GetExecSessionCount() int {
 	return len(execManager.sessions)
 }
 
-// StopAllExecSessions closes all active exec WebSocket connections
-func StopAllExecSessions() {
+// StopAllExecSessions closes all active exec WebSocket connections, telling the owner
+// and participants of each session why it ended
+func StopAllExecSessions(reason string) {
 	execManager.mu.Lock()
 	defer execManager.mu.Unlock()
 
 	for id, session := range execManager.sessions {
 		log.Printf("Closing exec session %s (%s/%s)", id, session.Namespace, session.Pod)
-		session.conn.Close()
+		session.recorder.Finish()
+		session.hub.owner.sendMessage(TerminalMessage{Type: "error", Data: reason})
+		session.hub.closeAll(reason)
+		session.hub.owner.close(websocket.CloseGoingAway, reason)
 		delete(execManager.sessions, id)
 	}
 }
 
 // TerminalMessage represents a message between client and server
 type TerminalMessage struct {
-	Type string `json:"type"` // "input", "resize", "output", "error"
+	Type string `json:"type"` // "input", "resize", "output", "error", "session", "shell", "participants", "role"
 	Data string `json:"data,omitempty"`
 	Rows uint16 `json:"rows,omitempty"`
 	Cols uint16 `json:"cols,omitempty"`
@@ -79,41 +95,51 @@ type wsWriter struct {
 func (w *wsWriter) Write(p []byte) (int, error) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
+	if err := writeTerminalMessage(w.conn, TerminalMessage{Type: "output", Data: string(p)}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
 
-	msg := TerminalMessage{Type: "output", Data: string(p)}
+func writeTerminalMessage(conn *websocket.Conn, msg TerminalMessage) error {
 	data, err := json.Marshal(msg)
 	if err != nil {
-		return 0, err
-	}
-	if err := w.conn.WriteMessage(websocket.TextMessage, data); err != nil {
-		return 0, err
+		return err
 	}
-	return len(p), nil
+	return conn.WriteMessage(websocket.TextMessage, data)
 }
 
 // terminalSizeQueue implements remotecommand.TerminalSizeQueue
 type terminalSizeQueue struct {
 	resizeChan chan remotecommand.TerminalSize
+	done       <-chan struct{} // Ends the queue once the session is over
 }
 
 func (t *terminalSizeQueue) Next() *remotecommand.TerminalSize {
-	size, ok := <-t.resizeChan
-	if !ok {
+	select {
+	case size := <-t.resizeChan:
+		return &size
+	case <-t.done:
 		return nil
 	}
-	return &size
 }
 
-// handlePodExec handles WebSocket connections for pod exec
+// defaultShells are tried in order when opening a pod terminal
+var defaultShells = []string{"/bin/bash", "/bin/sh", "/bin/ash"}
+
+// shellProbeTimeout bounds each check for whether a shell exists in the container
+const shellProbeTimeout = 10 * time.Second
+
+// handlePodExec handles WebSocket connections for pod exec. The shell is the first of
+// ?shell= (when given) and /bin/bash, /bin/sh, /bin/ash that the container has.
 func (s *Server) handlePodExec(w http.ResponseWriter, r *http.Request) {
 	namespace := chi.URLParam(r, "namespace")
 	podName := chi.URLParam(r, "name")
 	container := r.URL.Query().Get("container")
 
-	// Get shell - prefer bash, fall back to sh
-	shell := r.URL.Query().Get("shell")
-	if shell == "" {
-		shell = "/bin/sh"
+	shells := defaultShells
+	if shell := r.URL.Query().Get("shell"); shell != "" {
+		shells = append([]string{shell}, slices.DeleteFunc(slices.Clone(defaultShells), func(s string) bool { return s == shell })...)
 	}
 
 	// Upgrade to WebSocket
@@ -123,39 +149,100 @@ func (s *Server) handlePodExec(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	shell, err := negotiateShell(r.Context(), namespace, podName, container, shells)
+	if err != nil {
+		sendWSError(conn, err.Error())
+		conn.Close()
+		return
+	}
+
 	// Register the session
+	session := registerExecSession(namespace, podName, container, conn)
+	session.Shell = shell
+	log.Printf("Exec session %s started (%s/%s, %s)", session.ID, namespace, podName, shell)
+	auditAction(r, "exec", "Pod", namespace, podName)
+	session.startRecording(r, "exec", "", []string{shell})
+
+	// Ensure cleanup on exit
+	defer func() {
+		unregisterExecSession(session.ID)
+		session.hub.owner.detach()
+		log.Printf("Exec session %s ended (%s/%s)", session.ID, namespace, podName)
+	}()
+
+	if err := s.streamTerminal(r.Context(), session, []string{shell}); err != nil {
+		log.Printf("Exec finished with error: %v", err)
+	}
+}
+
+// registerExecSession tracks a terminal connection so it can be closed on context switch
+// and shared with other users
+func registerExecSession(namespace, podName, container string, conn *websocket.Conn) *ExecSession {
 	execManager.mu.Lock()
+	defer execManager.mu.Unlock()
 	execManager.nextID++
 	sessionID := fmt.Sprintf("exec-%d", execManager.nextID)
 	session := &ExecSession{
-		ID:        sessionID,
-		Namespace: namespace,
-		Pod:       podName,
-		Container: container,
-		conn:      conn,
+		ID:         sessionID,
+		Namespace:  namespace,
+		Pod:        podName,
+		Container:  container,
+		ownerToken: newShareToken(),
+		reattach:   make(chan *websocket.Conn),
 	}
+	session.hub = newTerminalHub(session, conn)
 	execManager.sessions[sessionID] = session
+	return session
+}
+
+func unregisterExecSession(sessionID string) {
+	execManager.mu.Lock()
+	session := execManager.sessions[sessionID]
+	delete(execManager.sessions, sessionID)
 	execManager.mu.Unlock()
-	log.Printf("Exec session %s started (%s/%s)", sessionID, namespace, podName)
+	if session != nil {
+		session.recorder.Finish()
+		session.hub.closeAll("Session ended by owner")
+	}
+}
 
-	// Ensure cleanup on exit
-	defer func() {
-		execManager.mu.Lock()
-		delete(execManager.sessions, sessionID)
-		execManager.mu.Unlock()
-		conn.Close()
-		log.Printf("Exec session %s ended (%s/%s)", sessionID, namespace, podName)
-	}()
+// startRecording records the session to the exec audit sinks, when configured, and
+// tells the owner it's being recorded
+func (session *ExecSession) startRecording(r *http.Request, kind, node string, command []string) {
+	session.recorder = execaudit.Start(execaudit.Session{
+		Type:      kind,
+		User:      auth.Actor(r),
+		Remote:    r.RemoteAddr,
+		Context:   k8s.GetContextName(),
+		Namespace: session.Namespace,
+		Pod:       session.Pod,
+		Container: session.Container,
+		Node:      node,
+		Command:   command,
+	})
+	if session.recorder != nil {
+		session.hub.owner.sendMessage(TerminalMessage{Type: "output", Data: "\x1b[2m[This session is recorded]\x1b[0m\r\n"})
+	}
+}
 
-	// Get K8s client and config
-	client := k8s.GetClient()
-	config := k8s.GetConfig()
-	if client == nil || config == nil {
-		sendWSError(conn, "K8s client not initialized")
-		return
+func getExecSession(sessionID string) *ExecSession {
+	execManager.mu.RLock()
+	defer execManager.mu.RUnlock()
+	return execManager.sessions[sessionID]
+}
+
+// newExecutor builds a stream executor for a command in a pod container, impersonating
+// the request's user when enabled
+func newExecutor(ctx context.Context, namespace, podName, container string, command []string, tty bool) (remotecommand.Executor, error) {
+	client, err := k8s.ClientFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	config, err := k8s.ConfigFor(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	// Build exec request
 	req := client.CoreV1().RESTClient().Post().
 		Resource("pods").
 		Name(podName).
@@ -163,18 +250,72 @@ func (s *Server) handlePodExec(w http.ResponseWriter, r *http.Request) {
 		SubResource("exec").
 		VersionedParams(&corev1.PodExecOptions{
 			Container: container,
-			Command:   []string{shell},
-			Stdin:     true,
+			Command:   command,
+			Stdin:     tty,
 			Stdout:    true,
-			Stderr:    true,
-			TTY:       true,
+			Stderr:    !tty, // A TTY merges stderr into stdout
+			TTY:       tty,
 		}, scheme.ParameterCodec)
 
-	// Create SPDY executor
-	exec, err := remotecommand.NewSPDYExecutor(config, "POST", req.URL())
+	// SPDY, falling back to WebSocket when a proxy rejects the upgrade
+	exec, err := k8s.NewStreamExecutor(config, req.URL())
 	if err != nil {
-		sendWSError(conn, fmt.Sprintf("Failed to create executor: %v", err))
-		return
+		return nil, fmt.Errorf("failed to create executor: %w", err)
+	}
+	return exec, nil
+}
+
+// negotiateShell returns the first shell that runs in the container, checking each with
+// a non-interactive "exit 0". If none do, the first failure is returned, since it's the
+// most telling when the cause isn't a missing shell (e.g. the container isn't running).
+func negotiateShell(ctx context.Context, namespace, podName, container string, shells []string) (string, error) {
+	var firstErr error
+	for _, shell := range shells {
+		exec, err := newExecutor(ctx, namespace, podName, container, []string{shell, "-c", "exit 0"}, false)
+		if err != nil {
+			return "", err
+		}
+		probeCtx, cancel := context.WithTimeout(ctx, shellProbeTimeout)
+		err = exec.StreamWithContext(probeCtx, remotecommand.StreamOptions{Stdout: io.Discard, Stderr: io.Discard})
+		cancel()
+		if err == nil {
+			return shell, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return "", fmt.Errorf("no shell found in the container (tried %s): %v", strings.Join(shells, ", "), firstErr)
+}
+
+// streamTerminal runs a TTY exec in the pod and bridges it to the owner's WebSocket
+// (and any share participants). It returns when the command exits, the owner closes the
+// terminal, or the owner's connection drops and they don't reattach within
+// reattachGracePeriod. Errors before streaming starts are sent to the owner. A panic
+// ends only this session. The exec runs on a context of its own, keeping ctx's values
+// (the user to impersonate) but not its cancellation, and ends at server shutdown.
+func (s *Server) streamTerminal(ctx context.Context, session *ExecSession, command []string) (err error) {
+	owner := session.hub.owner
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Exec session %s panicked: %v\n%s", session.ID, r, debug.Stack())
+			owner.sendMessage(TerminalMessage{Type: "error", Data: "Terminal session failed unexpectedly"})
+			err = fmt.Errorf("exec session panicked: %v", r)
+		}
+	}()
+
+	// The exec outlives the request's connection while the owner may reattach
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+	stop := context.AfterFunc(s.streamsCtx, cancel)
+	defer stop()
+
+	exec, err := newExecutor(ctx, session.Namespace, session.Pod, session.Container, command, true)
+	if err != nil {
+		if conn := owner.current(); conn != nil {
+			sendWSError(conn, err.Error())
+		}
+		return nil
 	}
 
 	// Set up pipes for stdin
@@ -184,35 +325,83 @@ func (s *Server) handlePodExec(w http.ResponseWriter, r *http.Request) {
 	// Set up terminal size queue
 	sizeQueue := &terminalSizeQueue{
 		resizeChan: make(chan remotecommand.TerminalSize, 1),
+		done:       ctx.Done(),
 	}
 
 	// Send initial size
 	sizeQueue.resizeChan <- remotecommand.TerminalSize{Width: 80, Height: 24}
 
-	// Set up stdout/stderr writer
-	wsOut := &wsWriter{conn: conn}
+	// Output goes to the owner and every share participant; co-drivers write to stdin
+	session.hub.start(stdinWriter)
+	session.hub.sendSessionInfo()
 
 	// Run exec in goroutine
 	execDone := make(chan error, 1)
 	go func() {
-		err := exec.StreamWithContext(r.Context(), remotecommand.StreamOptions{
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("Exec session %s stream panicked: %v\n%s", session.ID, r, debug.Stack())
+				execDone <- fmt.Errorf("exec stream panicked: %v", r)
+			}
+		}()
+		execDone <- exec.StreamWithContext(ctx, remotecommand.StreamOptions{
 			Stdin:             stdinReader,
-			Stdout:            wsOut,
-			Stderr:            wsOut,
+			Stdout:            session.hub,
+			Stderr:            session.hub,
 			Tty:               true,
 			TerminalSizeQueue: sizeQueue,
 		})
-		execDone <- err
 	}()
 
-	// Read messages from WebSocket
+	// Read the owner's messages, connection by connection
+	conn := owner.current()
+	for {
+		readDone := make(chan bool, 1)
+		go func(conn *websocket.Conn) {
+			readDone <- session.readOwner(conn, stdinWriter, sizeQueue)
+		}(conn)
+
+		select {
+		case err := <-execDone:
+			owner.close(websocket.CloseNormalClosure, "Session ended")
+			return err
+		case closed := <-readDone:
+			if closed {
+				stdinWriter.Close()
+				cancel()
+				return <-execDone
+			}
+		}
+
+		owner.detach()
+		log.Printf("Exec session %s: owner disconnected, waiting %s for a reattach", session.ID, reattachGracePeriod)
+		select {
+		case conn = <-session.reattach:
+			owner.attach(conn)
+			session.hub.sendSessionInfo()
+		case err := <-execDone:
+			return err
+		case <-time.After(reattachGracePeriod):
+			log.Printf("Exec session %s: owner didn't reattach", session.ID)
+			stdinWriter.Close()
+			cancel()
+			return <-execDone
+		}
+	}
+}
+
+// readOwner handles the owner's input and resize messages until their connection ends.
+// It returns true when the owner closed the terminal on purpose, rather than the
+// connection dropping.
+func (session *ExecSession) readOwner(conn *websocket.Conn, stdin io.Writer, sizeQueue *terminalSizeQueue) bool {
 	for {
 		_, message, err := conn.ReadMessage()
 		if err != nil {
-			if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
-				log.Printf("WebSocket read error: %v", err)
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				return true
 			}
-			break
+			log.Printf("WebSocket read error: %v", err)
+			return false
 		}
 
 		var msg TerminalMessage
@@ -223,27 +412,29 @@ func (s *Server) handlePodExec(w http.ResponseWriter, r *http.Request) {
 
 		switch msg.Type {
 		case "input":
-			stdinWriter.Write([]byte(msg.Data))
+			session.recorder.Input(msg.Data, "")
+			stdin.Write([]byte(msg.Data))
 		case "resize":
+			if msg.Cols == 0 || msg.Rows == 0 {
+				continue
+			}
+			session.recorder.Resize(msg.Cols, msg.Rows)
+			size := remotecommand.TerminalSize{Width: msg.Cols, Height: msg.Rows}
+			// Keep only the latest size if the previous one wasn't taken yet
 			select {
-			case sizeQueue.resizeChan <- remotecommand.TerminalSize{
-				Width:  msg.Cols,
-				Height: msg.Rows,
-			}:
+			case sizeQueue.resizeChan <- size:
 			default:
-				// Drop resize if channel full
+				select {
+				case <-sizeQueue.resizeChan:
+				default:
+				}
+				select {
+				case sizeQueue.resizeChan <- size:
+				default:
+				}
 			}
 		}
 	}
-
-	// Clean up
-	close(sizeQueue.resizeChan)
-	stdinWriter.Close()
-
-	// Wait for exec to finish
-	if err := <-execDone; err != nil {
-		log.Printf("Exec finished with error: %v", err)
-	}
 }
 
 func sendWSError(conn *websocket.Conn, msg string) {
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"crypto/subtle"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// reattachGracePeriod is how long a session outlives its owner's connection, waiting
+	// for the owner to reconnect after a network hiccup
+	reattachGracePeriod = 30 * time.Second
+	// reattachBufferBytes caps the output kept for a disconnected owner
+	reattachBufferBytes = 256 * 1024
+	// reattachHandoffTimeout bounds how long a reattach waits for the session to take it
+	reattachHandoffTimeout = 5 * time.Second
+)
+
+// ownerWriter is the session owner's connection. Output written while the owner is
+// disconnected is buffered for their return, so the exec stream never fails on it.
+type ownerWriter struct {
+	mu        sync.Mutex
+	conn      *websocket.Conn // nil while detached
+	pending   []byte
+	truncated bool // pending overflowed and lost its oldest output
+}
+
+func (w *ownerWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn != nil {
+		if err := writeTerminalMessage(w.conn, TerminalMessage{Type: "output", Data: string(p)}); err == nil {
+			return len(p), nil
+		}
+		// The read loop sees the closed connection and waits for a reattach
+		w.conn.Close()
+		w.conn = nil
+	}
+	w.pending = append(w.pending, p...)
+	if over := len(w.pending) - reattachBufferBytes; over > 0 {
+		w.pending = w.pending[over:]
+		w.truncated = true
+	}
+	return len(p), nil
+}
+
+// sendMessage sends a control message; it's dropped while the owner is away
+func (w *ownerWriter) sendMessage(msg TerminalMessage) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn != nil {
+		writeTerminalMessage(w.conn, msg)
+	}
+}
+
+// current returns the owner's connection, or nil while detached
+func (w *ownerWriter) current() *websocket.Conn {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.conn
+}
+
+// detach closes the owner's connection and buffers output until attach
+func (w *ownerWriter) detach() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn != nil {
+		w.conn.Close()
+		w.conn = nil
+	}
+}
+
+// attach switches to the owner's new connection, first replaying what they missed
+func (w *ownerWriter) attach(conn *websocket.Conn) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.truncated {
+		writeTerminalMessage(conn, TerminalMessage{Type: "output", Data: "\r\n\x1b[2m[Earlier output was dropped while disconnected]\x1b[0m\r\n"})
+	}
+	if len(w.pending) > 0 {
+		writeTerminalMessage(conn, TerminalMessage{Type: "output", Data: string(w.pending)})
+	}
+	w.pending, w.truncated = nil, false
+	w.conn = conn
+}
+
+// close ends the owner's connection with a close frame
+func (w *ownerWriter) close(code int, reason string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn == nil {
+		return
+	}
+	w.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(time.Second))
+	w.conn.Close()
+	w.conn = nil
+}
+
+// handleReattachExecSession reconnects a session's owner after a dropped connection. The
+// owner token comes as ?token= since browsers can't set headers on WebSockets. A session
+// whose old connection the server hasn't noticed is dead yet is taken over.
+func (s *Server) handleReattachExecSession(w http.ResponseWriter, r *http.Request) {
+	session := getExecSession(chi.URLParam(r, "id"))
+	if session == nil {
+		s.writeError(w, http.StatusNotFound, "Session not found or already ended")
+		return
+	}
+	token := r.URL.Query().Get("token")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(session.ownerToken)) != 1 {
+		s.writeError(w, http.StatusForbidden, "Only the session owner can reattach")
+		return
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("WebSocket upgrade error: %v", err)
+		return
+	}
+
+	session.hub.owner.detach()
+	select {
+	case session.reattach <- conn:
+		log.Printf("Exec session %s reattached (%s/%s)", session.ID, session.Namespace, session.Pod)
+	case <-time.After(reattachHandoffTimeout):
+		sendWSError(conn, "Session has ended")
+		conn.Close()
+	}
+}
@@ -0,0 +1,497 @@
+package server
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/websocket"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+const (
+	// shareScrollbackBytes is how much recent output late joiners receive
+	shareScrollbackBytes = 64 * 1024
+	// defaultShareTTL bounds how long an unused share link can be redeemed
+	defaultShareTTL = time.Hour
+	// maxShareTTL caps the requested link lifetime
+	maxShareTTL = 24 * time.Hour
+	// participantQueueSize is how many output chunks a participant may fall behind before
+	// it's disconnected, so a slow viewer can't stall the owner's terminal
+	participantQueueSize = 256
+)
+
+// sessionTokenHeader carries a session's owner token on share management requests
+const sessionTokenHeader = "X-Radar-Session-Token"
+
+// Share participant roles
+const (
+	RoleOwner    = "owner"
+	RoleObserver = "observer" // Read-only
+	RoleDriver   = "driver"   // Observer granted input by the owner
+)
+
+// ShareLink is a redeemable invitation to join a terminal session
+type ShareLink struct {
+	Token     string    `json:"token"`
+	SessionID string    `json:"sessionId"`
+	URL       string    `json:"url"` // WebSocket path to join with
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// ShareParticipant is a non-owner connected to a shared session
+type ShareParticipant struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	Role     string    `json:"role"`
+	Remote   string    `json:"remote"`
+	JoinedAt time.Time `json:"joinedAt"`
+
+	out *participantOutput
+}
+
+// participantOutput queues terminal output for one participant and sends it from its own
+// goroutine. A participant whose queue fills up is disconnected rather than sent a
+// terminal stream with gaps in it.
+type participantOutput struct {
+	*wsWriter
+	queue chan []byte
+	done  chan struct{}
+	once  sync.Once
+}
+
+func newParticipantOutput(conn *websocket.Conn) *participantOutput {
+	o := &participantOutput{
+		wsWriter: &wsWriter{conn: conn},
+		queue:    make(chan []byte, participantQueueSize),
+		done:     make(chan struct{}),
+	}
+	go o.run()
+	return o
+}
+
+func (o *participantOutput) run() {
+	for {
+		select {
+		case data := <-o.queue:
+			if _, err := o.Write(data); err != nil {
+				o.close()
+				return
+			}
+		case <-o.done:
+			return
+		}
+	}
+}
+
+// send queues output without blocking; p may be reused once it returns
+func (o *participantOutput) send(p []byte) {
+	select {
+	case o.queue <- append([]byte(nil), p...):
+	case <-o.done:
+	default:
+		log.Printf("Disconnecting a terminal share participant that fell %d writes behind", participantQueueSize)
+		o.close()
+	}
+}
+
+// close stops sending and closes the connection, which ends the participant's handler
+func (o *participantOutput) close() {
+	o.once.Do(func() {
+		close(o.done)
+		o.conn.Close()
+	})
+}
+
+// terminalHub fans terminal output out to the owner and every participant, and
+// accepts input from the owner and participants granted the driver role
+type terminalHub struct {
+	session *ExecSession
+	owner   *ownerWriter
+
+	mu           sync.Mutex
+	stdin        io.Writer
+	participants map[string]*ShareParticipant
+	links        map[string]*ShareLink
+	scrollback   []byte
+	nextID       int
+	closed       bool
+}
+
+func newTerminalHub(session *ExecSession, conn *websocket.Conn) *terminalHub {
+	return &terminalHub{
+		session:      session,
+		owner:        &ownerWriter{conn: conn},
+		participants: make(map[string]*ShareParticipant),
+		links:        make(map[string]*ShareLink),
+	}
+}
+
+// start records the exec stdin so co-drivers can type once streaming begins
+func (h *terminalHub) start(stdin io.Writer) {
+	h.mu.Lock()
+	h.stdin = stdin
+	h.mu.Unlock()
+}
+
+// Write broadcasts terminal output. Participants get it through their own queues, so a
+// slow or broken one is dropped, and output for an owner who is disconnected waits for
+// them to reattach, so neither affects the session.
+func (h *terminalHub) Write(p []byte) (int, error) {
+	h.session.recorder.Output(p)
+	h.mu.Lock()
+	h.scrollback = append(h.scrollback, p...)
+	if over := len(h.scrollback) - shareScrollbackBytes; over > 0 {
+		h.scrollback = h.scrollback[over:]
+	}
+	participants := make([]*ShareParticipant, 0, len(h.participants))
+	for _, p := range h.participants {
+		participants = append(participants, p)
+	}
+	h.mu.Unlock()
+
+	for _, participant := range participants {
+		participant.out.send(p)
+	}
+	return h.owner.Write(p)
+}
+
+// input writes participant keystrokes to the session if they hold the driver role
+func (h *terminalHub) input(participantID, data string) {
+	h.mu.Lock()
+	p, ok := h.participants[participantID]
+	stdin := h.stdin
+	allowed := ok && p.Role == RoleDriver && stdin != nil
+	h.mu.Unlock()
+	if allowed {
+		h.session.recorder.Input(data, p.Name)
+		stdin.Write([]byte(data))
+	}
+}
+
+// sendSessionInfo gives the owner the session ID and owner token needed to manage sharing
+// and to reattach, and the shell that was started
+func (h *terminalHub) sendSessionInfo() {
+	info, _ := json.Marshal(map[string]string{"sessionId": h.session.ID, "ownerToken": h.session.ownerToken, "shell": h.session.Shell})
+	h.owner.sendMessage(TerminalMessage{Type: "session", Data: string(info)})
+}
+
+// notifyParticipants tells the owner who is currently connected
+func (h *terminalHub) notifyParticipants() {
+	data, _ := json.Marshal(h.listParticipants())
+	h.owner.sendMessage(TerminalMessage{Type: "participants", Data: string(data)})
+}
+
+func (h *terminalHub) listParticipants() []ShareParticipant {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	out := make([]ShareParticipant, 0, len(h.participants))
+	for _, p := range h.participants {
+		out = append(out, *p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].JoinedAt.Before(out[j].JoinedAt) })
+	return out
+}
+
+// closeAll disconnects every participant and invalidates share links (session ended)
+func (h *terminalHub) closeAll(reason string) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return
+	}
+	h.closed = true
+	for _, p := range h.participants {
+		p.out.sendMessage(TerminalMessage{Type: "error", Data: reason})
+		p.out.conn.Close()
+	}
+	h.participants = make(map[string]*ShareParticipant)
+	h.links = make(map[string]*ShareLink)
+}
+
+func (w *wsWriter) sendMessage(msg TerminalMessage) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	data, _ := json.Marshal(msg)
+	w.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+func newShareToken() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("crypto/rand failed: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
+
+// auditExecShare logs share activity with the session target and participant
+func auditExecShare(action string, session *ExecSession, participant, role string, r *http.Request) {
+	log.Printf("[audit] exec-share %s session=%s pod=%s/%s container=%s participant=%q role=%s context=%s remote=%s",
+		action, session.ID, session.Namespace, session.Pod, session.Container, participant, role,
+		k8s.GetContextName(), r.RemoteAddr)
+}
+
+// ownedSession resolves {id} and checks the X-Radar-Session-Token header against the owner token
+func (s *Server) ownedSession(w http.ResponseWriter, r *http.Request) (*ExecSession, bool) {
+	session := getExecSession(chi.URLParam(r, "id"))
+	if session == nil {
+		s.writeError(w, http.StatusNotFound, "Session not found")
+		return nil, false
+	}
+	token := r.Header.Get(sessionTokenHeader)
+	if subtle.ConstantTimeCompare([]byte(token), []byte(session.ownerToken)) != 1 {
+		s.writeError(w, http.StatusForbidden, "Only the session owner can manage sharing")
+		return nil, false
+	}
+	return session, true
+}
+
+// handleCreateShareLink creates a link that lets others join a session read-only
+func (s *Server) handleCreateShareLink(w http.ResponseWriter, r *http.Request) {
+	session, ok := s.ownedSession(w, r)
+	if !ok {
+		return
+	}
+
+	ttl := defaultShareTTL
+	if v := r.URL.Query().Get("ttl"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			s.writeError(w, http.StatusBadRequest, "invalid ttl (examples: 15m, 2h)")
+			return
+		}
+		ttl = min(d, maxShareTTL)
+	}
+
+	now := time.Now()
+	link := &ShareLink{
+		Token:     newShareToken(),
+		SessionID: session.ID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+	link.URL = "/api/exec/shared/" + link.Token
+
+	h := session.hub
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		s.writeError(w, http.StatusGone, "Session has ended")
+		return
+	}
+	h.links[link.Token] = link
+	h.mu.Unlock()
+
+	auditExecShare("link-created", session, "", "", r)
+	s.writeJSON(w, link)
+}
+
+// handleRevokeShareLink invalidates a share link (connected participants stay connected)
+func (s *Server) handleRevokeShareLink(w http.ResponseWriter, r *http.Request) {
+	session, ok := s.ownedSession(w, r)
+	if !ok {
+		return
+	}
+	token := chi.URLParam(r, "token")
+
+	h := session.hub
+	h.mu.Lock()
+	_, found := h.links[token]
+	delete(h.links, token)
+	h.mu.Unlock()
+	if !found {
+		s.writeError(w, http.StatusNotFound, "Share link not found")
+		return
+	}
+
+	auditExecShare("link-revoked", session, "", "", r)
+	s.writeJSON(w, map[string]string{"status": "revoked"})
+}
+
+// handleListShareParticipants returns who is connected to a session
+func (s *Server) handleListShareParticipants(w http.ResponseWriter, r *http.Request) {
+	session, ok := s.ownedSession(w, r)
+	if !ok {
+		return
+	}
+	s.writeJSON(w, session.hub.listParticipants())
+}
+
+// ParticipantRoleRequest changes a participant's role
+type ParticipantRoleRequest struct {
+	Role string `json:"role"` // observer or driver
+}
+
+// handleSetParticipantRole grants or revokes co-driving for a participant, or removes them
+func (s *Server) handleSetParticipantRole(w http.ResponseWriter, r *http.Request) {
+	session, ok := s.ownedSession(w, r)
+	if !ok {
+		return
+	}
+	var req ParticipantRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Role != RoleObserver && req.Role != RoleDriver {
+		s.writeError(w, http.StatusBadRequest, "role must be observer or driver")
+		return
+	}
+
+	h := session.hub
+	h.mu.Lock()
+	p, found := h.participants[chi.URLParam(r, "participant")]
+	if found {
+		p.Role = req.Role
+	}
+	h.mu.Unlock()
+	if !found {
+		s.writeError(w, http.StatusNotFound, "Participant not found")
+		return
+	}
+
+	p.out.sendMessage(TerminalMessage{Type: "role", Data: req.Role})
+	auditExecShare("role-changed", session, p.Name, req.Role, r)
+	h.notifyParticipants()
+	s.writeJSON(w, p)
+}
+
+// handleKickParticipant disconnects a participant
+func (s *Server) handleKickParticipant(w http.ResponseWriter, r *http.Request) {
+	session, ok := s.ownedSession(w, r)
+	if !ok {
+		return
+	}
+	h := session.hub
+	h.mu.Lock()
+	p, found := h.participants[chi.URLParam(r, "participant")]
+	delete(h.participants, chi.URLParam(r, "participant"))
+	h.mu.Unlock()
+	if !found {
+		s.writeError(w, http.StatusNotFound, "Participant not found")
+		return
+	}
+
+	p.out.sendMessage(TerminalMessage{Type: "error", Data: "Removed from session by owner"})
+	p.out.conn.Close()
+	auditExecShare("participant-removed", session, p.Name, p.Role, r)
+	h.notifyParticipants()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleJoinSharedSession upgrades to a WebSocket and attaches the caller to a shared
+// session as an observer. ?name= identifies the participant to the owner and in the audit log.
+func (s *Server) handleJoinSharedSession(w http.ResponseWriter, r *http.Request) {
+	token := chi.URLParam(r, "token")
+	name := strings.TrimSpace(r.URL.Query().Get("name"))
+	if name == "" {
+		s.writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+
+	session, link := findShareLink(token)
+	if session == nil || time.Now().After(link.ExpiresAt) {
+		s.writeError(w, http.StatusNotFound, "Share link is invalid or has expired")
+		return
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("WebSocket upgrade error: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	h := session.hub
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		sendWSError(conn, "Session has ended")
+		return
+	}
+	h.nextID++
+	participant := &ShareParticipant{
+		ID:       fmt.Sprintf("p-%d", h.nextID),
+		Name:     name,
+		Role:     RoleObserver,
+		Remote:   r.RemoteAddr,
+		JoinedAt: time.Now(),
+		out:      newParticipantOutput(conn),
+	}
+	defer participant.out.close()
+	// Queue scrollback under the hub lock so no output is missed or duplicated
+	if len(h.scrollback) > 0 {
+		participant.out.send(h.scrollback)
+	}
+	h.participants[participant.ID] = participant
+	h.mu.Unlock()
+
+	participant.out.sendMessage(TerminalMessage{Type: "role", Data: RoleObserver})
+	auditExecShare("joined", session, name, RoleObserver, r)
+	session.recorder.Joined(name)
+	h.notifyParticipants()
+
+	defer func() {
+		h.mu.Lock()
+		_, stillJoined := h.participants[participant.ID]
+		delete(h.participants, participant.ID)
+		h.mu.Unlock()
+		if stillJoined {
+			auditExecShare("left", session, name, participant.Role, r)
+			h.notifyParticipants()
+		}
+	}()
+
+	// Only input is honored, and only while the participant holds the driver role
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var msg TerminalMessage
+		if err := json.Unmarshal(message, &msg); err != nil {
+			continue
+		}
+		if msg.Type == "input" {
+			h.input(participant.ID, msg.Data)
+		}
+	}
+}
+
+// findShareLink looks up an active share link across sessions
+func findShareLink(token string) (*ExecSession, *ShareLink) {
+	if token == "" {
+		return nil, nil
+	}
+	execManager.mu.RLock()
+	sessions := make([]*ExecSession, 0, len(execManager.sessions))
+	for _, session := range execManager.sessions {
+		sessions = append(sessions, session)
+	}
+	execManager.mu.RUnlock()
+
+	for _, session := range sessions {
+		session.hub.mu.Lock()
+		link, ok := session.hub.links[token]
+		session.hub.mu.Unlock()
+		if ok {
+			return session, link
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+	"github.com/skyhook-io/radar/internal/helm"
+	"github.com/skyhook-io/radar/internal/topology"
+)
+
+// HelmReleaseTopology is the topology subgraph of one Helm release
+type HelmReleaseTopology struct {
+	*topology.Topology
+	// Unmapped manifest resources have no topology node: kinds the topology doesn't draw
+	// (ServiceAccounts, RBAC, custom resources) or objects missing from the cluster
+	Unmapped []topology.ResourceRef `json:"unmapped"`
+}
+
+// handleHelmReleaseTopology returns the topology of everything a Helm release owns: the
+// resources in its manifest, what they manage (a Deployment's pods, a CronJob's Jobs) and
+// the edges between them, for a per-release map. It's served here rather than by the helm
+// package so the graph is filtered to what the user may see, like /api/topology.
+// GET /api/helm/releases/{namespace}/{name}/topology
+func (s *Server) handleHelmReleaseTopology(w http.ResponseWriter, r *http.Request) {
+	client := helm.GetClient()
+	if client == nil {
+		s.writeExplorerError(w, explorerErrors.HelmClientNotInitialized())
+		return
+	}
+	namespace := chi.URLParam(r, "namespace")
+	resources, err := client.GetReleaseResources(namespace, chi.URLParam(r, "name"))
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	roots := make([]topology.ResourceRef, len(resources))
+	opts := topology.DefaultBuildOptions()
+	opts.Namespace = namespace
+	for i, res := range resources {
+		roots[i] = topology.ResourceRef{Kind: res.Kind, Group: res.Group, Namespace: res.Namespace, Name: res.Name}
+		if res.Namespace != "" && res.Namespace != namespace {
+			opts.Namespace = "" // Charts may template resources into other namespaces
+		}
+	}
+	topo, err := topology.NewBuilder().Build(opts)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	sub, unmapped := topology.Subgraph(filterTopologyForUser(r.Context(), topo), roots)
+	s.writeJSON(w, HelmReleaseTopology{Topology: sub, Unmapped: unmapped})
+}
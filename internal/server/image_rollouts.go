@@ -0,0 +1,344 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/skyhook-io/radar/internal/auth"
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+const (
+	// maxImageRolloutWorkloads caps how many workloads one image rollout patches
+	maxImageRolloutWorkloads = 200
+	// imageRolloutTimeout is how long a rollout is tracked before unfinished workloads fail
+	imageRolloutTimeout = 15 * time.Minute
+	// imageRolloutPollInterval is how often rollout progress is read from the cache
+	imageRolloutPollInterval = 2 * time.Second
+	// maxImageRollouts is how many rollouts are kept for status queries
+	maxImageRollouts = 20
+)
+
+// Image rollout and per-workload states
+const (
+	imageRolloutPreview   = "preview"
+	imageRolloutRunning   = "running"
+	imageRolloutSucceeded = "succeeded"
+	imageRolloutFailed    = "failed"
+
+	imageWorkloadPending = "pending"
+	imageWorkloadRolling = "rolling"
+	imageWorkloadDone    = "done"
+	imageWorkloadFailed  = "failed"
+)
+
+// ImageRolloutRequest is the body for an image rollout: every workload running From (in
+// Namespace, or all namespaces) is moved to To
+type ImageRolloutRequest struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Namespace string `json:"namespace,omitempty"`
+	DryRun    bool   `json:"dryRun,omitempty"` // Return the change set without patching
+}
+
+// ImageRolloutWorkload is one workload of an image rollout
+type ImageRolloutWorkload struct {
+	Kind       string   `json:"kind"`
+	Namespace  string   `json:"namespace"`
+	Name       string   `json:"name"`
+	Containers []string `json:"containers"`
+	ManagedBy  string   `json:"managedBy,omitempty"`
+	State      string   `json:"state"`
+	Message    string   `json:"message,omitempty"`
+
+	uses       []k8s.ImageUse
+	generation int64
+}
+
+// ImageRollout is the change set of an image rollout and, once started, its progress
+type ImageRollout struct {
+	ID         string                 `json:"id,omitempty"`
+	From       string                 `json:"from"`
+	To         string                 `json:"to"`
+	Namespace  string                 `json:"namespace,omitempty"`
+	State      string                 `json:"state"`
+	StartedAt  *time.Time             `json:"startedAt,omitempty"`
+	FinishedAt *time.Time             `json:"finishedAt,omitempty"`
+	Workloads  []ImageRolloutWorkload `json:"workloads"`
+	Warnings   []string               `json:"warnings,omitempty"`
+}
+
+// imageRollouts holds started rollouts (in-memory, per process)
+var (
+	imageRollouts   = make(map[string]*ImageRollout)
+	imageRolloutsMu sync.Mutex
+)
+
+// handleImageRollout previews or starts moving every workload off an image, e.g. for an
+// emergency base-image bump. Started rollouts are tracked in the background.
+// POST /api/image-rollouts
+func (s *Server) handleImageRollout(w http.ResponseWriter, r *http.Request) {
+	var req ImageRolloutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	req.From, req.To = strings.TrimSpace(req.From), strings.TrimSpace(req.To)
+	if req.From == "" || req.To == "" {
+		s.writeError(w, http.StatusBadRequest, "from and to are required")
+		return
+	}
+	if k8s.NormalizeImage(req.From) == k8s.NormalizeImage(req.To) {
+		s.writeError(w, http.StatusBadRequest, "from and to are the same image")
+		return
+	}
+	// An empty namespace plans across every namespace
+	if err := auth.CheckNamespace(r.Context(), req.Namespace); err != nil {
+		s.writeError(w, http.StatusForbidden, err.Error())
+		return
+	}
+	cache := k8s.GetResourceCache()
+	if cache == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "Resource cache not available")
+		return
+	}
+
+	rollout := planImageRollout(cache.WorkloadsUsingImage(req.From, req.Namespace), req)
+	if req.DryRun {
+		s.writeJSON(w, rollout)
+		return
+	}
+	if len(rollout.Workloads) == 0 {
+		s.writeError(w, http.StatusBadRequest, fmt.Sprintf("no workloads use %s", req.From))
+		return
+	}
+	if len(rollout.Workloads) > maxImageRolloutWorkloads {
+		s.writeError(w, http.StatusBadRequest, fmt.Sprintf("too many workloads (%d, max %d): limit to a namespace", len(rollout.Workloads), maxImageRolloutWorkloads))
+		return
+	}
+	// Check every workload before patching any, so a rollout isn't left half-applied
+	for _, wl := range rollout.Workloads {
+		if err := checkUserAccess(r.Context(), k8s.PermissionCheck{Verb: "patch", Kind: wl.Kind, Namespace: wl.Namespace, Name: wl.Name}); err != nil {
+			s.writeError(w, http.StatusForbidden, err.Error())
+			return
+		}
+	}
+
+	now := time.Now()
+	rollout.ID = newImageRolloutID()
+	rollout.State = imageRolloutRunning
+	rollout.StartedAt = &now
+	for i := range rollout.Workloads {
+		wl := &rollout.Workloads[i]
+		generation, err := k8s.SetContainerImages(r.Context(), wl.uses, req.To)
+		if err != nil {
+			wl.State, wl.Message = imageWorkloadFailed, err.Error()
+			continue
+		}
+		auditActionDetail(r, "image-rollout", wl.Kind, wl.Namespace, wl.Name, req.From+" → "+req.To)
+		wl.generation = generation
+		wl.State = imageWorkloadRolling
+		if wl.Kind == "CronJob" {
+			wl.State, wl.Message = imageWorkloadDone, "Applies from the next scheduled Job"
+		}
+	}
+	log.Printf("[image-rollout] %s: %s → %s on %d workloads", rollout.ID, req.From, req.To, len(rollout.Workloads))
+
+	imageRolloutsMu.Lock()
+	finishImageRollout(rollout)
+	imageRollouts[rollout.ID] = rollout
+	pruneImageRollouts()
+	snapshot := rollout.snapshot()
+	imageRolloutsMu.Unlock()
+
+	if rollout.FinishedAt == nil {
+		go trackImageRollout(cache, rollout)
+	}
+	s.writeJSON(w, snapshot)
+}
+
+// handleListImageRollouts lists tracked image rollouts, newest first
+// GET /api/image-rollouts
+func (s *Server) handleListImageRollouts(w http.ResponseWriter, r *http.Request) {
+	imageRolloutsMu.Lock()
+	rollouts := make([]ImageRollout, 0, len(imageRollouts))
+	for _, rollout := range imageRollouts {
+		rollouts = append(rollouts, rollout.snapshot())
+	}
+	imageRolloutsMu.Unlock()
+
+	sort.Slice(rollouts, func(i, j int) bool { return rollouts[i].StartedAt.After(*rollouts[j].StartedAt) })
+	s.writeJSON(w, rollouts)
+}
+
+// handleGetImageRollout returns one image rollout's progress
+// GET /api/image-rollouts/{id}
+func (s *Server) handleGetImageRollout(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	imageRolloutsMu.Lock()
+	rollout, ok := imageRollouts[id]
+	var snapshot ImageRollout
+	if ok {
+		snapshot = rollout.snapshot()
+	}
+	imageRolloutsMu.Unlock()
+
+	if !ok {
+		s.writeError(w, http.StatusNotFound, fmt.Sprintf("image rollout %q not found", id))
+		return
+	}
+	s.writeJSON(w, snapshot)
+}
+
+// planImageRollout groups image uses by workload and warns about workloads whose manifests
+// are owned by Helm or GitOps, which will put the old image back on their next sync
+func planImageRollout(uses []k8s.ImageUse, req ImageRolloutRequest) *ImageRollout {
+	rollout := &ImageRollout{
+		From:      req.From,
+		To:        req.To,
+		Namespace: req.Namespace,
+		State:     imageRolloutPreview,
+		Workloads: make([]ImageRolloutWorkload, 0),
+	}
+	index := make(map[string]int)
+	managed := make(map[string]int)
+	for _, u := range uses {
+		key := u.Kind + "/" + u.Namespace + "/" + u.Name
+		i, ok := index[key]
+		if !ok {
+			i = len(rollout.Workloads)
+			index[key] = i
+			rollout.Workloads = append(rollout.Workloads, ImageRolloutWorkload{
+				Kind:      u.Kind,
+				Namespace: u.Namespace,
+				Name:      u.Name,
+				ManagedBy: u.ManagedBy,
+				State:     imageWorkloadPending,
+			})
+			if u.ManagedBy != "" {
+				managed[u.ManagedBy]++
+			}
+		}
+		wl := &rollout.Workloads[i]
+		wl.Containers = append(wl.Containers, u.Container)
+		wl.uses = append(wl.uses, u)
+	}
+
+	managers := make([]string, 0, len(managed))
+	for manager := range managed {
+		managers = append(managers, manager)
+	}
+	sort.Strings(managers)
+	for _, manager := range managers {
+		rollout.Warnings = append(rollout.Warnings, fmt.Sprintf(
+			"%d workload(s) are managed by %s, which will revert the image on its next sync unless the new image is also set at the source",
+			managed[manager], manager))
+	}
+	return rollout
+}
+
+// trackImageRollout polls the cache until every patched workload has rolled out or the
+// rollout times out
+func trackImageRollout(cache *k8s.ResourceCache, rollout *ImageRollout) {
+	ticker := time.NewTicker(imageRolloutPollInterval)
+	defer ticker.Stop()
+	deadline := time.Now().Add(imageRolloutTimeout)
+
+	for range ticker.C {
+		timedOut := time.Now().After(deadline)
+		imageRolloutsMu.Lock()
+		for i := range rollout.Workloads {
+			wl := &rollout.Workloads[i]
+			if wl.State != imageWorkloadRolling {
+				continue
+			}
+			obj, err := cache.CachedObject(context.Background(), wl.Kind, wl.Namespace, wl.Name)
+			if err != nil {
+				wl.State, wl.Message = imageWorkloadFailed, err.Error()
+				continue
+			}
+			if progress, ok := k8s.WorkloadRolloutProgress(obj, wl.generation); ok {
+				wl.Message = progress.Message
+				switch {
+				case progress.Done:
+					wl.State = imageWorkloadDone
+				case progress.Failed:
+					wl.State = imageWorkloadFailed
+				}
+			}
+			if timedOut && wl.State == imageWorkloadRolling {
+				wl.State = imageWorkloadFailed
+				wl.Message = fmt.Sprintf("Timed out after %s: %s", imageRolloutTimeout, wl.Message)
+			}
+		}
+		finished := finishImageRollout(rollout)
+		id, state := rollout.ID, rollout.State
+		imageRolloutsMu.Unlock()
+
+		if finished {
+			log.Printf("[image-rollout] %s finished: %s", id, state)
+			return
+		}
+	}
+}
+
+// finishImageRollout sets the final state once no workload is rolling. Callers hold
+// imageRolloutsMu.
+func finishImageRollout(rollout *ImageRollout) bool {
+	state := imageRolloutSucceeded
+	for _, wl := range rollout.Workloads {
+		switch wl.State {
+		case imageWorkloadRolling:
+			return false
+		case imageWorkloadFailed:
+			state = imageRolloutFailed
+		}
+	}
+	now := time.Now()
+	rollout.State = state
+	rollout.FinishedAt = &now
+	return true
+}
+
+// pruneImageRollouts drops the oldest finished rollouts beyond maxImageRollouts. Callers
+// hold imageRolloutsMu.
+func pruneImageRollouts() {
+	for len(imageRollouts) > maxImageRollouts {
+		var oldest *ImageRollout
+		for _, rollout := range imageRollouts {
+			if rollout.FinishedAt != nil && (oldest == nil || rollout.StartedAt.Before(*oldest.StartedAt)) {
+				oldest = rollout
+			}
+		}
+		if oldest == nil {
+			return
+		}
+		delete(imageRollouts, oldest.ID)
+	}
+}
+
+// snapshot copies the rollout for serving outside the lock
+func (r *ImageRollout) snapshot() ImageRollout {
+	c := *r
+	c.Workloads = append([]ImageRolloutWorkload(nil), r.Workloads...)
+	return c
+}
+
+func newImageRolloutID() string {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("crypto/rand failed: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,324 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/skyhook-io/radar/internal/auth"
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/timeline"
+)
+
+// ForecastsResponse lists quota and PVC exhaustion forecasts
+type ForecastsResponse struct {
+	Forecasts []k8s.UsageForecast `json:"forecasts"`
+	Horizon   string              `json:"horizon,omitempty"`
+}
+
+// handleInsightsForecasts returns quota/PVC exhaustion forecasts, soonest first.
+// ?horizon= (Go duration) limits results to those exhausting within that window.
+func (s *Server) handleInsightsForecasts(w http.ResponseWriter, r *http.Request) {
+	forecaster := k8s.GetUsageForecaster()
+	if forecaster == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "Usage forecasting not running")
+		return
+	}
+
+	namespace := r.URL.Query().Get("namespace")
+	forecasts := forecaster.Forecasts(namespace)
+
+	resp := ForecastsResponse{Forecasts: forecasts}
+	if v := r.URL.Query().Get("horizon"); v != "" {
+		horizon, err := time.ParseDuration(v)
+		if err != nil || horizon <= 0 {
+			s.writeError(w, http.StatusBadRequest, "horizon must be a positive Go duration (e.g. 168h)")
+			return
+		}
+		resp.Forecasts = forecastsWithin(forecasts, horizon)
+		resp.Horizon = horizon.String()
+	}
+
+	s.writeJSON(w, resp)
+}
+
+// forecastsWithin keeps forecasts that reach their limit within the horizon
+func forecastsWithin(forecasts []k8s.UsageForecast, horizon time.Duration) []k8s.UsageForecast {
+	result := make([]k8s.UsageForecast, 0)
+	maxDays := horizon.Hours() / 24
+	for _, fc := range forecasts {
+		if fc.DaysRemaining != nil && *fc.DaysRemaining <= maxDays {
+			result = append(result, fc)
+		}
+	}
+	return result
+}
+
+// defaultIncidentWindow is the report window when ?since= is omitted
+const defaultIncidentWindow = 30 * 24 * time.Hour
+
+// handleInsightsIncidents reports time to first action (MTTD) and time to recovery (MTTR)
+// for workload incidents, aggregated per workload, namespace and month.
+// ?since=/?until= (RFC3339) bound incident start times, ?namespace= filters, and
+// ?incidents=true includes the individual incidents.
+func (s *Server) handleInsightsIncidents(w http.ResponseWriter, r *http.Request) {
+	until := time.Now()
+	since := until.Add(-defaultIncidentWindow)
+	for param, dst := range map[string]*time.Time{"since": &since, "until": &until} {
+		if v := r.URL.Query().Get(param); v != "" {
+			ts, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				s.writeError(w, http.StatusBadRequest, param+" must be an RFC3339 timestamp")
+				return
+			}
+			*dst = ts
+		}
+	}
+	if !since.Before(until) {
+		s.writeError(w, http.StatusBadRequest, "since must be before until")
+		return
+	}
+
+	if timeline.GetStore() == nil {
+		s.writeExplorerError(w, explorerErrors.New(explorerErrors.ErrTimelineStoreNotInit, "timeline store not available"))
+		return
+	}
+	report, err := timeline.QueryIncidentReport(r.Context(), r.URL.Query().Get("namespace"), since, until)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if r.URL.Query().Get("incidents") != "true" {
+		report.Incidents = nil
+	}
+	s.writeJSON(w, report)
+}
+
+// defaultCorrelationRange is how far back correlated incidents are listed when ?since= is omitted
+const defaultCorrelationRange = 24 * time.Hour
+
+// parseCorrelationWindow reads ?window= (Go duration), the quiet period that closes an incident
+func (s *Server) parseCorrelationWindow(w http.ResponseWriter, r *http.Request) (time.Duration, bool) {
+	v := r.URL.Query().Get("window")
+	if v == "" {
+		return timeline.DefaultCorrelationWindow, true
+	}
+	window, err := time.ParseDuration(v)
+	if err != nil || window <= 0 {
+		s.writeError(w, http.StatusBadRequest, "window must be a positive Go duration (e.g. 10m)")
+		return 0, false
+	}
+	return window, true
+}
+
+// handleCorrelatedIncidents groups timeline events into incidents by top-level owner, e.g.
+// a Pod OOMKilled, its ReplicaSet replacing it and the Deployment going unavailable.
+// ?since=/?until= (RFC3339, default last 24h), ?namespace= filters and ?window= sets the
+// quiet period that closes an incident.
+func (s *Server) handleCorrelatedIncidents(w http.ResponseWriter, r *http.Request) {
+	until := time.Now()
+	since := until.Add(-defaultCorrelationRange)
+	for param, dst := range map[string]*time.Time{"since": &since, "until": &until} {
+		if v := r.URL.Query().Get(param); v != "" {
+			ts, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				s.writeError(w, http.StatusBadRequest, param+" must be an RFC3339 timestamp")
+				return
+			}
+			*dst = ts
+		}
+	}
+	if !since.Before(until) {
+		s.writeError(w, http.StatusBadRequest, "since must be before until")
+		return
+	}
+	window, ok := s.parseCorrelationWindow(w, r)
+	if !ok {
+		return
+	}
+
+	if timeline.GetStore() == nil {
+		s.writeExplorerError(w, explorerErrors.New(explorerErrors.ErrTimelineStoreNotInit, "timeline store not available"))
+		return
+	}
+	incidents, err := timeline.QueryCorrelatedIncidents(r.Context(), r.URL.Query().Get("namespace"), since, until, window)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if incidents == nil {
+		incidents = []timeline.CorrelatedIncident{}
+	}
+	s.writeJSON(w, incidents)
+}
+
+// handleCorrelatedIncident returns one correlated incident, identified by the event that
+// opened it, with its member events. ?window= must match the list request.
+func (s *Server) handleCorrelatedIncident(w http.ResponseWriter, r *http.Request) {
+	window, ok := s.parseCorrelationWindow(w, r)
+	if !ok {
+		return
+	}
+	if timeline.GetStore() == nil {
+		s.writeExplorerError(w, explorerErrors.New(explorerErrors.ErrTimelineStoreNotInit, "timeline store not available"))
+		return
+	}
+	incident, err := timeline.GetCorrelatedIncident(r.Context(), chi.URLParam(r, "id"), window)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if incident == nil {
+		s.writeExplorerError(w, explorerErrors.New(explorerErrors.ErrNotFound, "incident not found"))
+		return
+	}
+	s.writeJSON(w, incident)
+}
+
+const (
+	// defaultHeatmapWindow and defaultHeatmapBucket apply when ?since= and ?bucket= are omitted
+	defaultHeatmapWindow = 24 * time.Hour
+	defaultHeatmapBucket = time.Hour
+)
+
+// handleInsightsChanges returns a heatmap of resource changes per namespace, kind and time
+// bucket, with the resources changing faster than ?noisyPerHour= and a filter preset
+// excluding them. ?since=/?until= (RFC3339) set the window, ?bucket= (Go duration) the
+// resolution, ?namespace= and ?kinds= (comma-separated) filter, ?rows= caps the rows.
+func (s *Server) handleInsightsChanges(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	opts := timeline.HeatmapOptions{
+		Namespace: q.Get("namespace"),
+		Until:     time.Now(),
+		Bucket:    defaultHeatmapBucket,
+	}
+	opts.Since = opts.Until.Add(-defaultHeatmapWindow)
+	if err := parseBucketWindow(q, &opts.Since, &opts.Until, &opts.Bucket); err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	opts.Kinds = splitList(q.Get("kinds"))
+	if v := q.Get("rows"); v != "" {
+		rows, err := strconv.Atoi(v)
+		if err != nil || rows <= 0 {
+			s.writeError(w, http.StatusBadRequest, "rows must be a positive integer")
+			return
+		}
+		opts.Rows = rows
+	}
+	if v := q.Get("noisyPerHour"); v != "" {
+		rate, err := strconv.ParseFloat(v, 64)
+		if err != nil || rate <= 0 {
+			s.writeError(w, http.StatusBadRequest, "noisyPerHour must be a positive number")
+			return
+		}
+		opts.NoisyPerHour = rate
+	}
+	if err := opts.Validate(); err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if timeline.GetStore() == nil {
+		s.writeExplorerError(w, explorerErrors.New(explorerErrors.ErrTimelineStoreNotInit, "timeline store not available"))
+		return
+	}
+	heatmap, err := timeline.QueryChangeHeatmap(r.Context(), opts)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.writeJSON(w, heatmap)
+}
+
+// parseBucketWindow reads ?since=/?until= (RFC3339) and ?bucket= (Go duration, at least a
+// minute) into the defaults it's given
+func parseBucketWindow(q url.Values, since, until *time.Time, bucket *time.Duration) error {
+	for param, dst := range map[string]*time.Time{"since": since, "until": until} {
+		if v := q.Get(param); v != "" {
+			ts, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return fmt.Errorf("%s must be an RFC3339 timestamp", param)
+			}
+			*dst = ts
+		}
+	}
+	if v := q.Get("bucket"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < time.Minute {
+			return fmt.Errorf("bucket must be a Go duration of at least 1m (e.g. 1h)")
+		}
+		*bucket = d
+	}
+	return nil
+}
+
+// handleChangesHistogram counts timeline events per time bucket, grouped by ?groupBy= (any
+// of kind, namespace, health and owner, comma-separated; default kind), for "events per
+// hour" charts. Series are busiest first, capped by ?top=. The window and filters are as
+// for the heatmap, plus ?sources= (e.g. informer,k8s_event). SQL stores count in the
+// database, so long windows don't load every event.
+func (s *Server) handleChangesHistogram(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	opts := timeline.HistogramOptions{
+		Namespace: q.Get("namespace"),
+		Kinds:     splitList(q.Get("kinds")),
+		Until:     time.Now(),
+		Bucket:    defaultHeatmapBucket,
+	}
+	opts.Since = opts.Until.Add(-defaultHeatmapWindow)
+	if err := parseBucketWindow(q, &opts.Since, &opts.Until, &opts.Bucket); err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	for _, d := range splitList(q.Get("groupBy")) {
+		opts.GroupBy = append(opts.GroupBy, timeline.HistogramDimension(d))
+	}
+	for _, src := range splitList(q.Get("sources")) {
+		opts.Sources = append(opts.Sources, timeline.EventSource(src))
+	}
+	if v := q.Get("top"); v != "" {
+		top, err := strconv.Atoi(v)
+		if err != nil || top <= 0 {
+			s.writeError(w, http.StatusBadRequest, "top must be a positive integer")
+			return
+		}
+		opts.Top = top
+	}
+	if err := opts.Validate(); err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if timeline.GetStore() == nil {
+		s.writeExplorerError(w, explorerErrors.New(explorerErrors.ErrTimelineStoreNotInit, "timeline store not available"))
+		return
+	}
+	histogram, err := timeline.QueryEventHistogram(r.Context(), opts)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.writeJSON(w, histogram)
+}
+
+// auditAction records a user action for the audit log and incident reports. URL kinds
+// ("deployments") are resolved to Kind names so actions line up with timeline events.
+func auditAction(r *http.Request, action, kind, namespace, name string) {
+	auditActionDetail(r, action, kind, namespace, name, "")
+}
+
+// auditActionDetail is auditAction with a description of the action's parameters
+func auditActionDetail(r *http.Request, action, kind, namespace, name, detail string) {
+	if disc := k8s.GetResourceDiscovery(); disc != nil {
+		if res, ok := disc.GetResource(kind); ok {
+			kind = res.Kind
+		}
+	}
+	timeline.RecordActionDetail(r.Context(), action, kind, namespace, name, auth.Actor(r), detail)
+}
@@ -0,0 +1,251 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/skyhook-io/radar/internal/jsonpatch"
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// listSyncDebounce coalesces bursts of informer updates into one patch
+const listSyncDebounce = 500 * time.Millisecond
+
+// listKindAliases maps URL aliases the discovery index doesn't know to their Kind
+var listKindAliases = map[string]string{
+	"pvcs":   "PersistentVolumeClaim",
+	"hpas":   "HorizontalPodAutoscaler",
+	"quotas": "ResourceQuota",
+}
+
+// listWatcher is a list stream waiting for changes to one kind
+type listWatcher struct {
+	kind      string // Kind name, e.g. "Pod"
+	group     string // API group for custom resources ("" for built-in kinds)
+	urlKind   string // As requested, e.g. "pods" (fallback match when discovery can't resolve it)
+	namespace string
+	ch        chan bool // true = resend the full list (context switched)
+}
+
+func (lw *listWatcher) matches(change k8s.ResourceChange) bool {
+	if lw.namespace != "" && change.Namespace != lw.namespace {
+		return false
+	}
+	if lw.kind != "" {
+		return change.Kind == lw.kind && change.Group == lw.group
+	}
+	return strings.EqualFold(lw.urlKind, change.Kind) || strings.EqualFold(lw.urlKind, change.Kind+"s")
+}
+
+// ListSyncEvent is the data of a list stream's "list" and "patch" events. A "list" event
+// carries every item; a "patch" event carries RFC 6902 operations that turn the list at
+// version Base into the list at Version.
+type ListSyncEvent struct {
+	Version int                   `json:"version"`
+	Base    int                   `json:"base,omitempty"`
+	Items   []any                 `json:"items,omitempty"`
+	Ops     []jsonpatch.Operation `json:"ops,omitempty"`
+}
+
+// watchList registers a list stream for change notifications. Returns nil if too many
+// streams are open.
+func (b *SSEBroadcaster) watchList(urlKind, namespace string) *listWatcher {
+	kind, group := listKindAliases[strings.ToLower(urlKind)], ""
+	if kind == "" {
+		if res, ok := k8s.GetResourceDiscovery().GetResource(urlKind); ok {
+			kind, group = res.Kind, k8s.CustomGroup(res.Group)
+		}
+	}
+	lw := &listWatcher{kind: kind, group: group, urlKind: urlKind, namespace: namespace, ch: make(chan bool, 1)}
+
+	b.listWatchersMu.Lock()
+	defer b.listWatchersMu.Unlock()
+	if len(b.listWatchers) >= MaxSSEClients {
+		return nil
+	}
+	b.listWatchers[lw] = struct{}{}
+	return lw
+}
+
+func (b *SSEBroadcaster) unwatchList(lw *listWatcher) {
+	b.listWatchersMu.Lock()
+	delete(b.listWatchers, lw)
+	b.listWatchersMu.Unlock()
+}
+
+// notifyListWatchers wakes the list streams a change affects. Notifications coalesce:
+// a stream that hasn't caught up yet relists once.
+func (b *SSEBroadcaster) notifyListWatchers(change k8s.ResourceChange) {
+	b.listWatchersMu.Lock()
+	defer b.listWatchersMu.Unlock()
+	for lw := range b.listWatchers {
+		if lw.matches(change) {
+			select {
+			case lw.ch <- false:
+			default:
+			}
+		}
+	}
+}
+
+// resetListWatchers makes every list stream resend its full list
+func (b *SSEBroadcaster) resetListWatchers() {
+	b.listWatchersMu.Lock()
+	defer b.listWatchersMu.Unlock()
+	for lw := range b.listWatchers {
+		select {
+		case lw.ch <- true:
+		default:
+			// A pending change notification: replace it with a reset
+			select {
+			case <-lw.ch:
+			default:
+			}
+			lw.ch <- true
+		}
+	}
+}
+
+// handleResourceListStream streams a resource list as SSE: a "list" event with every
+// item, then "patch" events with JSON Patch operations as informers report changes.
+// Items are sorted by namespace and name. When a patch would be larger than the list,
+// or after a context switch, a new "list" event is sent instead.
+func (s *Server) handleResourceListStream(w http.ResponseWriter, r *http.Request) {
+	kind := chi.URLParam(r, "kind")
+	namespace := r.URL.Query().Get("namespace")
+
+	items, err := listItems(r, kind, namespace)
+	if err != nil {
+		s.writeExplorerError(w, err)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		s.writeError(w, http.StatusInternalServerError, "Streaming not supported")
+		return
+	}
+
+	watcher := s.broadcaster.watchList(kind, namespace)
+	if watcher == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "Too many list streams")
+		return
+	}
+	defer s.broadcaster.unwatchList(watcher)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	send := func(event string, data []byte) bool {
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data); err != nil {
+			return false
+		}
+		flusher.Flush()
+		return true
+	}
+
+	version := 1
+	full, err := json.Marshal(ListSyncEvent{Version: version, Items: items})
+	if err != nil || !send("list", full) {
+		return
+	}
+
+	heartbeat := time.NewTicker(30 * time.Second)
+	defer heartbeat.Stop()
+	debounce := time.NewTimer(listSyncDebounce)
+	debounce.Stop()
+	pending, reset := false, false
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+
+		case <-heartbeat.C:
+			if _, err := w.Write([]byte(": heartbeat\n\n")); err != nil {
+				return
+			}
+			flusher.Flush()
+
+		case resend := <-watcher.ch:
+			reset = reset || resend
+			if !pending {
+				pending = true
+				debounce.Reset(listSyncDebounce)
+			}
+
+		case <-debounce.C:
+			pending = false
+			next, err := listItems(r, kind, namespace)
+			if err != nil {
+				// Cache rebuilding (e.g. context switch); keep the old list and retry on the next change
+				log.Printf("List stream %s: %v", kind, err)
+				continue
+			}
+
+			var ops []jsonpatch.Operation
+			if !reset {
+				ops = jsonpatch.DiffList(items, next, listItemKey)
+				if len(ops) == 0 {
+					continue
+				}
+			}
+			version++
+			full, err := json.Marshal(ListSyncEvent{Version: version, Items: next})
+			if err != nil {
+				return
+			}
+			event, data := "list", full
+			if !reset {
+				patch, err := json.Marshal(ListSyncEvent{Version: version, Base: version - 1, Ops: ops})
+				if err == nil && len(patch) < len(full) {
+					event, data = "patch", patch
+				}
+			}
+			if !send(event, data) {
+				return
+			}
+			items, reset = next, false
+		}
+	}
+}
+
+// listItems lists a kind as decoded JSON, sorted by listItemKey so consecutive lists
+// can be diffed element by element
+func listItems(r *http.Request, kind, namespace string) ([]any, error) {
+	result, err := listResources(r.Context(), kind, namespace)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	var items []any
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+	if items == nil {
+		items = []any{}
+	}
+	sort.SliceStable(items, func(i, j int) bool { return listItemKey(items[i]) < listItemKey(items[j]) })
+	return items, nil
+}
+
+// listItemKey identifies a list item by namespace and name
+func listItemKey(item any) string {
+	obj, _ := item.(map[string]any)
+	meta, _ := obj["metadata"].(map[string]any)
+	ns, _ := meta["namespace"].(string)
+	name, _ := meta["name"].(string)
+	return ns + "/" + name
+}
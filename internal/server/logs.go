@@ -13,7 +13,9 @@ import (
 	"github.com/go-chi/chi/v5"
 	corev1 "k8s.io/api/core/v1"
 
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
 	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/logs"
 )
 
 // LogsResponse is the response for non-streaming logs
@@ -41,14 +43,14 @@ func (s *Server) handlePodLogs(w http.ResponseWriter, r *http.Request) {
 
 	client := k8s.GetClient()
 	if client == nil {
-		s.writeError(w, http.StatusServiceUnavailable, "Kubernetes client not available")
+		s.writeExplorerError(w, explorerErrors.K8sClientNotInitialized())
 		return
 	}
 
 	// Get pod to find containers
 	cache := k8s.GetResourceCache()
 	if cache == nil {
-		s.writeError(w, http.StatusServiceUnavailable, "Resource cache not available")
+		s.writeExplorerError(w, explorerErrors.CacheNotInitialized())
 		return
 	}
 
@@ -197,7 +199,7 @@ func (s *Server) handlePodLogsStream(w http.ResponseWriter, r *http.Request) {
 			}
 
 			// Parse timestamp and content
-			timestamp, content := parseLogLine(line)
+			timestamp, content := logs.ParseLine(line)
 
 			sendSSEEvent(w, flusher, "log", map[string]string{
 				"timestamp": timestamp,
@@ -237,19 +239,6 @@ func (s *Server) fetchContainerLogs(ctx context.Context, namespace, podName, con
 	return string(content), nil
 }
 
-// parseLogLine extracts timestamp from a log line (format: 2024-01-20T10:30:00.123456789Z content)
-func parseLogLine(line string) (timestamp, content string) {
-	// K8s timestamps are in RFC3339Nano format at the start of the line
-	if len(line) > 30 && line[4] == '-' && line[7] == '-' && line[10] == 'T' {
-		// Find the space after timestamp
-		spaceIdx := strings.Index(line, " ")
-		if spaceIdx > 20 && spaceIdx < 40 {
-			return line[:spaceIdx], line[spaceIdx+1:]
-		}
-	}
-	return "", line
-}
-
 // sendSSEEvent sends an SSE event
 func sendSSEEvent(w http.ResponseWriter, flusher http.Flusher, event string, data any) {
 	jsonData, _ := json.Marshal(data)
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// handlePodMetricsLive streams a pod's CPU and memory usage every few seconds (?interval=,
+// 2s-5s) while the client stays connected. Only new samples are sent: the kubelet and
+// metrics-server refresh on their own schedule, so a poll can return the previous sample.
+// The background collector keeps its 30s interval; this poller lives as long as the
+// request.
+// GET /api/metrics/pods/{namespace}/{name}/live
+func (s *Server) handlePodMetricsLive(w http.ResponseWriter, r *http.Request) {
+	namespace := chi.URLParam(r, "namespace")
+	name := chi.URLParam(r, "name")
+
+	interval := k8s.LiveMetricsDefaultInterval
+	if v := r.URL.Query().Get("interval"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid interval %q", v))
+			return
+		}
+		if err := k8s.ValidateLiveMetricsInterval(d); err != nil {
+			s.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		interval = d
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		s.writeError(w, http.StatusInternalServerError, "Streaming not supported")
+		return
+	}
+
+	emit := func(event string, v any) bool {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return false
+		}
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data); err != nil {
+			return false
+		}
+		flusher.Flush()
+		return true
+	}
+	if !emit("connected", map[string]string{"interval": interval.String()}) {
+		return
+	}
+
+	poller := k8s.NewPodMetricsPoller(namespace, name)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	heartbeat := time.NewTicker(15 * time.Second)
+	defer heartbeat.Stop()
+
+	var lastError string
+	poll := func() bool {
+		sample, err := poller.Poll(r.Context())
+		if err != nil {
+			// Report each distinct error once; the pod may not have started yet
+			if err.Error() == lastError || r.Context().Err() != nil {
+				return true
+			}
+			lastError = err.Error()
+			return emit("error", map[string]string{"error": lastError})
+		}
+		lastError = ""
+		if sample == nil {
+			return true
+		}
+		return emit("metrics", sample)
+	}
+	if !poll() {
+		return
+	}
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			if !poll() {
+				return
+			}
+		case <-heartbeat.C:
+			if !emit("heartbeat", struct{}{}) {
+				return
+			}
+		}
+	}
+}
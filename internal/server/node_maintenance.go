@@ -0,0 +1,175 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/skyhook-io/radar/internal/drain"
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// DrainRequest is the body for a node drain
+type DrainRequest struct {
+	Force              bool   `json:"force,omitempty"`              // Evict pods without a controller
+	DeleteEmptyDirData bool   `json:"deleteEmptyDirData,omitempty"` // Evict pods with emptyDir volumes
+	GracePeriodSeconds *int64 `json:"gracePeriodSeconds,omitempty"` // Overrides the pods' termination grace period
+	TimeoutSeconds     int    `json:"timeoutSeconds,omitempty"`     // Default 30 minutes
+	DryRun             bool   `json:"dryRun,omitempty"`             // Return the plan without cordoning or evicting
+}
+
+// handleCordonNode marks a node unschedulable
+// POST /api/nodes/{name}/cordon
+func (s *Server) handleCordonNode(w http.ResponseWriter, r *http.Request) {
+	s.setNodeUnschedulable(w, r, true)
+}
+
+// handleUncordonNode makes a node schedulable again
+// POST /api/nodes/{name}/uncordon
+func (s *Server) handleUncordonNode(w http.ResponseWriter, r *http.Request) {
+	s.setNodeUnschedulable(w, r, false)
+}
+
+func (s *Server) setNodeUnschedulable(w http.ResponseWriter, r *http.Request, unschedulable bool) {
+	name := chi.URLParam(r, "name")
+	if err := k8s.SetNodeUnschedulable(r.Context(), name, unschedulable); err != nil {
+		s.writeExplorerError(w, err)
+		return
+	}
+	if unschedulable {
+		auditAction(r, "cordon", "Node", "", name)
+		s.writeJSON(w, map[string]string{"message": "Node cordoned"})
+		return
+	}
+	auditAction(r, "uncordon", "Node", "", name)
+	s.writeJSON(w, map[string]string{"message": "Node uncordoned"})
+}
+
+// handleDrainNode cordons a node and evicts its pods, honoring PodDisruptionBudgets and
+// skipping DaemonSet and mirror pods. Progress streams as SSE events (one per drain.Event,
+// named by its type); dryRun returns the plan as JSON instead.
+// POST /api/nodes/{name}/drain
+func (s *Server) handleDrainNode(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	var req DrainRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		s.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.TimeoutSeconds < 0 {
+		s.writeError(w, http.StatusBadRequest, "timeoutSeconds must not be negative")
+		return
+	}
+	if req.GracePeriodSeconds != nil && *req.GracePeriodSeconds < 0 {
+		s.writeError(w, http.StatusBadRequest, "gracePeriodSeconds must not be negative")
+		return
+	}
+	cache := k8s.GetResourceCache()
+	if cache == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "Resource cache not available")
+		return
+	}
+	if _, err := cache.CachedObject(r.Context(), "nodes", "", name); err != nil {
+		s.writeExplorerError(w, err)
+		return
+	}
+
+	pods, err := cache.PodsMatching(k8s.PodFilter{Node: name})
+	if err != nil {
+		s.writeExplorerError(w, err)
+		return
+	}
+	plan := drain.NewPlan(name, pods, drain.PlanOptions{Force: req.Force, DeleteEmptyDirData: req.DeleteEmptyDirData})
+	if req.DryRun {
+		s.writeJSON(w, plan)
+		return
+	}
+	if len(plan.Problems) > 0 {
+		s.writeError(w, http.StatusBadRequest, "cannot drain: "+strings.Join(plan.Problems, "; "))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		s.writeError(w, http.StatusInternalServerError, "Streaming not supported")
+		return
+	}
+
+	emit := func(e drain.Event) error {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	}
+	cluster := newDrainCluster(r, cache, req.GracePeriodSeconds)
+	opts := drain.Options{Timeout: time.Duration(req.TimeoutSeconds) * time.Second}
+	log.Printf("[drain] Draining %s: %d pods to evict, %d skipped", name, len(plan.Evict), len(plan.Skipped))
+	if err := drain.Run(r.Context(), cluster, plan, opts, emit); err != nil {
+		log.Printf("[drain] Drain of %s halted: %v", name, err)
+		return
+	}
+	auditAction(r, "drain", "Node", "", name)
+}
+
+// newDrainCluster returns the drain.Cluster a drain runs against; tests replace it
+var newDrainCluster = func(r *http.Request, cache *k8s.ResourceCache, gracePeriod *int64) drain.Cluster {
+	return &drainCluster{r: r, cache: cache, gracePeriod: gracePeriod}
+}
+
+// drainCluster drains through the API and reads pod state from the cache
+type drainCluster struct {
+	r           *http.Request // For auditing
+	cache       *k8s.ResourceCache
+	gracePeriod *int64
+}
+
+func (dc *drainCluster) Cordon(ctx context.Context, node string) error {
+	if err := k8s.SetNodeUnschedulable(ctx, node, true); err != nil {
+		return err
+	}
+	auditActionDetail(dc.r, "cordon", "Node", "", node, "drain")
+	return nil
+}
+
+func (dc *drainCluster) Evict(ctx context.Context, p drain.Pod) error {
+	err := k8s.EvictPod(ctx, p.Namespace, p.Name, dc.gracePeriod)
+	switch {
+	case err == nil:
+		auditActionDetail(dc.r, "evict", "Pod", p.Namespace, p.Name, "drain")
+		return nil
+	case apierrors.IsNotFound(err):
+		return nil
+	case k8s.IsEvictionBlocked(err):
+		return fmt.Errorf("%w: %v", drain.ErrBlocked, err)
+	}
+	return err
+}
+
+func (dc *drainCluster) Exists(p drain.Pod) (bool, error) {
+	pod, err := dc.cache.Pods().Pods(p.Namespace).Get(p.Name)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return p.UID == "" || string(pod.UID) == p.UID, nil
+}
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/skyhook-io/radar/internal/drain"
+	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/testenv"
+)
+
+// slowDrainCluster takes longer to evict each pod than the request timeout allows
+type slowDrainCluster struct {
+	delay time.Duration
+}
+
+func (c *slowDrainCluster) Cordon(ctx context.Context, node string) error { return nil }
+
+func (c *slowDrainCluster) Evict(ctx context.Context, p drain.Pod) error {
+	select {
+	case <-time.After(c.delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *slowDrainCluster) Exists(p drain.Pod) (bool, error) { return false, nil }
+
+func TestDrainOutlivesRequestTimeout(t *testing.T) {
+	objs := append(testenv.App("shop", "web", testenv.OnNode("node-1")), testenv.Node("node-1", "4", "16Gi"))
+	testenv.Setup(t, objs...)
+
+	restoreTimeout, restoreCluster := apiRequestTimeout, newDrainCluster
+	apiRequestTimeout = 50 * time.Millisecond
+	newDrainCluster = func(*http.Request, *k8s.ResourceCache, *int64) drain.Cluster {
+		return &slowDrainCluster{delay: 100 * time.Millisecond}
+	}
+	t.Cleanup(func() { apiRequestTimeout, newDrainCluster = restoreTimeout, restoreCluster })
+
+	// Through the server's whole middleware chain, as the UI calls it
+	srv := httptest.NewServer(New(Config{}).router)
+	defer srv.Close()
+	resp, err := http.Post(srv.URL+"/api/nodes/node-1/drain", "application/json", strings.NewReader("{}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %s: %s", resp.Status, body)
+	}
+	if strings.Count(string(body), "event: evicted") != 2 || !strings.Contains(string(body), `"completed":true`) {
+		t.Errorf("drain stream = %s, want both pods evicted and the drain completed", body)
+	}
+}
@@ -0,0 +1,223 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/websocket"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+const (
+	nodeShellLabel         = "radar.skyhook.io/node-shell"
+	nodeShellContainer     = "shell"
+	nodeShellStartTimeout  = 90 * time.Second
+	nodeShellMaxLifetime   = 4 * time.Hour
+	defaultNodeShellImage  = "busybox:1.36"
+	defaultNodeShellNSName = "default"
+)
+
+// NodeShellConfig controls the node shell feature. It is disabled unless explicitly enabled,
+// since it runs a privileged pod with access to the host namespaces.
+type NodeShellConfig struct {
+	Enabled   bool
+	Image     string // Image for the debug pod (must provide nsenter)
+	Namespace string // Namespace the debug pods are created in
+}
+
+func (c NodeShellConfig) withDefaults() NodeShellConfig {
+	if c.Image == "" {
+		c.Image = defaultNodeShellImage
+	}
+	if c.Namespace == "" {
+		c.Namespace = defaultNodeShellNSName
+	}
+	return c
+}
+
+// handleNodeShell opens a host shell on a node via a privileged debug pod pinned to it.
+// The pod is deleted when the session ends.
+func (s *Server) handleNodeShell(w http.ResponseWriter, r *http.Request) {
+	nodeName := chi.URLParam(r, "name")
+
+	if !s.nodeShell.Enabled {
+		s.writeError(w, http.StatusForbidden, "node shell is disabled (start radar with --enable-node-shell)")
+		return
+	}
+
+	// Radar creates the privileged pod on the user's behalf, so exec rights aren't enough
+	ns := s.nodeShell.Namespace
+	if err := checkUserAccess(r.Context(), k8s.PermissionCheck{Verb: "create", Resource: "pods", Namespace: ns}); err != nil {
+		s.writeExplorerError(w, explorerErrors.New(explorerErrors.ErrForbidden, err.Error()))
+		return
+	}
+	// With --impersonate, the pod is created and deleted as the user
+	client, err := k8s.ClientFor(r.Context())
+	if err != nil {
+		s.writeExplorerError(w, err)
+		return
+	}
+	if _, err := client.CoreV1().Nodes().Get(r.Context(), nodeName, metav1.GetOptions{}); err != nil {
+		s.writeError(w, http.StatusNotFound, fmt.Sprintf("node %s not found: %v", nodeName, err))
+		return
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("WebSocket upgrade error: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	auditNodeShell("requested", nodeName, ns, "", r)
+
+	pod, err := client.CoreV1().Pods(ns).Create(r.Context(), buildNodeShellPod(nodeName, ns, s.nodeShell.Image), metav1.CreateOptions{})
+	if err != nil {
+		auditNodeShell("failed", nodeName, ns, "", r)
+		sendWSError(conn, fmt.Sprintf("Failed to create node shell pod: %v", err))
+		return
+	}
+	podName := pod.Name
+
+	// Always remove the debug pod, even if the client disconnects mid-startup
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		grace := int64(0)
+		if err := client.CoreV1().Pods(ns).Delete(ctx, podName, metav1.DeleteOptions{GracePeriodSeconds: &grace}); err != nil {
+			log.Printf("Warning: failed to delete node shell pod %s/%s: %v", ns, podName, err)
+		}
+		auditNodeShell("ended", nodeName, ns, podName, r)
+	}()
+
+	sendWSOutput(conn, fmt.Sprintf("Starting node shell pod %s/%s on %s...\r\n", ns, podName, nodeName))
+	if err := waitForPodRunning(r.Context(), ns, podName); err != nil {
+		sendWSError(conn, fmt.Sprintf("Node shell pod did not start: %v", err))
+		return
+	}
+	auditNodeShell("started", nodeName, ns, podName, r)
+
+	session := registerExecSession(ns, podName, nodeShellContainer, conn)
+	defer unregisterExecSession(session.ID)
+
+	// Enter all host namespaces of PID 1, preferring bash when the host has it
+	command := []string{
+		"nsenter", "--target", "1", "--mount", "--uts", "--ipc", "--net", "--pid", "--",
+		"sh", "-c", "if command -v bash >/dev/null 2>&1; then exec bash -l; else exec sh -l; fi",
+	}
+	session.startRecording(r, "node-shell", nodeName, command)
+	if err := s.streamTerminal(r.Context(), session, command); err != nil {
+		log.Printf("Node shell on %s finished with error: %v", nodeName, err)
+	}
+}
+
+// buildNodeShellPod returns a privileged pod pinned to the node, sharing host PID/network/IPC
+func buildNodeShellPod(nodeName, namespace, image string) *corev1.Pod {
+	privileged := true
+	grace := int64(0)
+	deadline := int64(nodeShellMaxLifetime.Seconds())
+
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nodeShellPodName(nodeName),
+			Namespace: namespace,
+			Labels: map[string]string{
+				nodeShellLabel:                 "true",
+				"app.kubernetes.io/managed-by": "radar",
+			},
+			Annotations: map[string]string{
+				nodeShellLabel + "-node": nodeName,
+			},
+		},
+		Spec: corev1.PodSpec{
+			NodeName:                      nodeName,
+			HostPID:                       true,
+			HostNetwork:                   true,
+			HostIPC:                       true,
+			RestartPolicy:                 corev1.RestartPolicyNever,
+			TerminationGracePeriodSeconds: &grace,
+			// Safety net in case cleanup never runs (e.g. radar is killed)
+			ActiveDeadlineSeconds: &deadline,
+			// Tolerate everything so the pod can land on tainted/cordoned nodes
+			Tolerations: []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+			Containers: []corev1.Container{{
+				Name:    nodeShellContainer,
+				Image:   image,
+				Command: []string{"sleep", fmt.Sprintf("%d", deadline)},
+				Stdin:   true,
+				TTY:     true,
+				SecurityContext: &corev1.SecurityContext{
+					Privileged: &privileged,
+				},
+			}},
+		},
+	}
+}
+
+// nodeShellPodName builds a unique, DNS-safe pod name for a node shell
+func nodeShellPodName(nodeName string) string {
+	suffix := make([]byte, 3)
+	_, _ = rand.Read(suffix)
+	base := strings.ToLower(nodeName)
+	if len(base) > 40 {
+		base = strings.TrimRight(base[:40], "-.")
+	}
+	return fmt.Sprintf("radar-node-shell-%s-%s", base, hex.EncodeToString(suffix))
+}
+
+// waitForPodRunning polls until the pod is running or fails to start
+func waitForPodRunning(ctx context.Context, namespace, name string) error {
+	client := k8s.GetClient()
+	ctx, cancel := context.WithTimeout(ctx, nodeShellStartTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		pod, err := client.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err == nil {
+			switch pod.Status.Phase {
+			case corev1.PodRunning:
+				return nil
+			case corev1.PodFailed, corev1.PodSucceeded:
+				return fmt.Errorf("pod exited with phase %s", pod.Status.Phase)
+			}
+			for _, cs := range pod.Status.ContainerStatuses {
+				if w := cs.State.Waiting; w != nil && (w.Reason == "ErrImagePull" || w.Reason == "ImagePullBackOff" || w.Reason == "CreateContainerConfigError") {
+					return fmt.Errorf("%s: %s", w.Reason, w.Message)
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out after %s", nodeShellStartTimeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+// auditNodeShell writes an audit log line for node shell lifecycle events
+func auditNodeShell(action, nodeName, namespace, podName string, r *http.Request) {
+	log.Printf("[audit] node-shell %s node=%s pod=%s/%s context=%s remote=%s user-agent=%q",
+		action, nodeName, namespace, podName, k8s.GetContextName(), r.RemoteAddr, r.UserAgent())
+}
+
+func sendWSOutput(conn *websocket.Conn, data string) {
+	msg := TerminalMessage{Type: "output", Data: data}
+	payload, _ := json.Marshal(msg)
+	conn.WriteMessage(websocket.TextMessage, payload)
+}
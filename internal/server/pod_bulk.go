@@ -0,0 +1,221 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/skyhook-io/radar/internal/auth"
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// maxBulkPods caps how many pods one bulk operation touches
+const maxBulkPods = 200
+
+// Bulk pod actions and per-pod states
+const (
+	bulkPodDelete = "delete"
+	bulkPodEvict  = "evict"
+
+	bulkPodPending = "pending" // Dry run: would be acted on
+	bulkPodSkipped = "skipped" // Already terminating
+	bulkPodDone    = "done"
+	bulkPodBlocked = "blocked" // Eviction refused by a PodDisruptionBudget
+	bulkPodFailed  = "failed"
+)
+
+// BulkPodRequest selects pods by namespace, label selector and node (at least a selector
+// or a node is required) and deletes or evicts them
+type BulkPodRequest struct {
+	Action             string `json:"action"` // delete or evict
+	Namespace          string `json:"namespace,omitempty"`
+	Selector           string `json:"selector,omitempty"`
+	Node               string `json:"node,omitempty"`
+	GracePeriodSeconds *int64 `json:"gracePeriodSeconds,omitempty"`
+	DryRun             bool   `json:"dryRun,omitempty"` // List the affected pods without acting
+}
+
+// BulkPod is one pod of a bulk operation
+type BulkPod struct {
+	Namespace string   `json:"namespace"`
+	Name      string   `json:"name"`
+	Node      string   `json:"node,omitempty"`
+	Owner     string   `json:"owner,omitempty"` // Kind/name of the controller; empty for bare pods
+	PDBs      []string `json:"pdbs,omitempty"`  // PodDisruptionBudgets covering the pod
+	State     string   `json:"state"`
+	Message   string   `json:"message,omitempty"`
+}
+
+// BulkPodResult lists the pods a bulk operation affected, or would affect on a dry run
+type BulkPodResult struct {
+	Action   string    `json:"action"`
+	DryRun   bool      `json:"dryRun,omitempty"`
+	Pods     []BulkPod `json:"pods"`
+	Warnings []string  `json:"warnings,omitempty"`
+}
+
+// handleBulkPods deletes or evicts every pod matching a selector, one at a time.
+// Evictions go through the Eviction API, so PodDisruptionBudgets are honored: pods whose
+// eviction would violate one are reported as blocked and left running.
+// POST /api/pods/bulk
+func (s *Server) handleBulkPods(w http.ResponseWriter, r *http.Request) {
+	var req BulkPodRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	req.Action = strings.ToLower(strings.TrimSpace(req.Action))
+	if req.Action != bulkPodDelete && req.Action != bulkPodEvict {
+		s.writeError(w, http.StatusBadRequest, "action must be \"delete\" or \"evict\"")
+		return
+	}
+	if strings.TrimSpace(req.Selector) == "" && req.Node == "" {
+		s.writeError(w, http.StatusBadRequest, "a selector or node is required")
+		return
+	}
+	if req.GracePeriodSeconds != nil && *req.GracePeriodSeconds < 0 {
+		s.writeError(w, http.StatusBadRequest, "gracePeriodSeconds must not be negative")
+		return
+	}
+	// An empty namespace matches pods cluster-wide, which the dry run lists too
+	if err := auth.CheckNamespace(r.Context(), req.Namespace); err != nil {
+		s.writeError(w, http.StatusForbidden, err.Error())
+		return
+	}
+	if err := checkUserAccess(r.Context(), k8s.PermissionCheck{Verb: "list", Resource: "pods", Namespace: req.Namespace}); err != nil {
+		s.writeError(w, http.StatusForbidden, err.Error())
+		return
+	}
+	cache := k8s.GetResourceCache()
+	if cache == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "Resource cache not available")
+		return
+	}
+
+	pods, err := cache.PodsMatching(k8s.PodFilter{Namespace: req.Namespace, Selector: req.Selector, Node: req.Node})
+	if err != nil {
+		s.writeExplorerError(w, err)
+		return
+	}
+	result := planBulkPods(pods, cache.PodDisruptionBudgets(r.Context(), req.Namespace), req.Action)
+	result.DryRun = req.DryRun
+	if req.DryRun {
+		s.writeJSON(w, result)
+		return
+	}
+	if len(result.Pods) == 0 {
+		s.writeError(w, http.StatusBadRequest, "no pods match")
+		return
+	}
+	if len(result.Pods) > maxBulkPods {
+		s.writeError(w, http.StatusBadRequest, fmt.Sprintf("too many pods (%d, max %d): narrow the selector", len(result.Pods), maxBulkPods))
+		return
+	}
+	// Check every pod before acting on any, so the operation isn't left half-applied
+	for _, p := range result.Pods {
+		check := k8s.PermissionCheck{Verb: "delete", Resource: "pods", Namespace: p.Namespace, Name: p.Name}
+		if req.Action == bulkPodEvict {
+			check = k8s.PermissionCheck{Verb: "create", Resource: "pods", Subresource: "eviction", Namespace: p.Namespace, Name: p.Name}
+		}
+		if err := checkUserAccess(r.Context(), check); err != nil {
+			s.writeError(w, http.StatusForbidden, err.Error())
+			return
+		}
+	}
+
+	counts := make(map[string]int)
+	for i := range result.Pods {
+		p := &result.Pods[i]
+		if p.State == bulkPodSkipped {
+			counts[p.State]++
+			continue
+		}
+		var err error
+		if req.Action == bulkPodEvict {
+			err = k8s.EvictPod(r.Context(), p.Namespace, p.Name, req.GracePeriodSeconds)
+		} else {
+			err = k8s.DeleteResource(r.Context(), "pods", p.Namespace, p.Name, k8s.DeleteOptions{GracePeriodSeconds: req.GracePeriodSeconds})
+		}
+		switch {
+		case err == nil:
+			p.State, p.Message = bulkPodDone, ""
+			auditActionDetail(r, req.Action, "Pod", p.Namespace, p.Name, bulkPodDetail(req))
+		case req.Action == bulkPodEvict && k8s.IsEvictionBlocked(err):
+			p.State, p.Message = bulkPodBlocked, "Eviction would violate a PodDisruptionBudget"
+		default:
+			p.State, p.Message = bulkPodFailed, err.Error()
+		}
+		counts[p.State]++
+	}
+	log.Printf("[bulk-pods] %s %q: %d done, %d blocked, %d failed, %d skipped", req.Action, bulkPodDetail(req),
+		counts[bulkPodDone], counts[bulkPodBlocked], counts[bulkPodFailed], counts[bulkPodSkipped])
+	s.writeJSON(w, result)
+}
+
+// planBulkPods lists the pods with the PDBs covering them. It warns about bare pods,
+// which nothing recreates, and, for evictions, PDBs that allow fewer disruptions than
+// the pods they cover.
+func planBulkPods(pods []*corev1.Pod, pdbs []policyv1.PodDisruptionBudget, action string) *BulkPodResult {
+	result := &BulkPodResult{Action: action, Pods: make([]BulkPod, 0, len(pods))}
+	covered := make(map[string]int) // PDB namespace/name -> selected pods it covers
+	bare := 0
+	for _, pod := range pods {
+		p := BulkPod{Namespace: pod.Namespace, Name: pod.Name, Node: pod.Spec.NodeName, State: bulkPodPending}
+		if owner := metav1.GetControllerOf(pod); owner != nil {
+			p.Owner = owner.Kind + "/" + owner.Name
+		} else {
+			bare++
+		}
+		for i := range pdbs {
+			if k8s.PDBSelectsPod(&pdbs[i], pod) {
+				p.PDBs = append(p.PDBs, pdbs[i].Name)
+				covered[pdbs[i].Namespace+"/"+pdbs[i].Name]++
+			}
+		}
+		if pod.DeletionTimestamp != nil {
+			p.State, p.Message = bulkPodSkipped, "Already terminating"
+		}
+		result.Pods = append(result.Pods, p)
+	}
+
+	if bare > 0 {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("%d pod(s) have no controller and won't be recreated", bare))
+	}
+	if action == bulkPodEvict {
+		var limited []string
+		for i := range pdbs {
+			pdb := &pdbs[i]
+			key := pdb.Namespace + "/" + pdb.Name
+			if n := covered[key]; n > int(pdb.Status.DisruptionsAllowed) {
+				limited = append(limited, fmt.Sprintf(
+					"PodDisruptionBudget %s allows %d disruption(s) now; evicting its %d selected pods will block some until replacements are ready",
+					key, pdb.Status.DisruptionsAllowed, n))
+			}
+		}
+		sort.Strings(limited)
+		result.Warnings = append(result.Warnings, limited...)
+	}
+	return result
+}
+
+// bulkPodDetail describes the selection for the audit log, e.g. "app=foo in shop on node-1"
+func bulkPodDetail(req BulkPodRequest) string {
+	var parts []string
+	if req.Selector != "" {
+		parts = append(parts, req.Selector)
+	}
+	if req.Namespace != "" {
+		parts = append(parts, "in "+req.Namespace)
+	}
+	if req.Node != "" {
+		parts = append(parts, "on "+req.Node)
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,202 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+const (
+	debugContainerPrefix = "radar-debug-"
+	debugStartTimeout    = 90 * time.Second
+	debugMaxLifetime     = 4 * time.Hour
+	// debugDoneMarker is created in the debug container's own filesystem when the session
+	// ends, which stops its keep-alive loop. Ephemeral containers can't be removed from a
+	// pod, only left to exit.
+	debugDoneMarker = "/tmp/.radar-debug-done"
+)
+
+// defaultDebugImages are offered when --debug-images isn't set: a small shell and a
+// network toolbox
+var defaultDebugImages = []string{"busybox:1.36", "nicolaka/netshoot:latest"}
+
+// DebugConfig controls debug containers
+type DebugConfig struct {
+	Images []string // Images users may choose from; the first is the default
+}
+
+func (c DebugConfig) withDefaults() DebugConfig {
+	if len(c.Images) == 0 {
+		c.Images = defaultDebugImages
+	}
+	return c
+}
+
+// handlePodDebug opens a terminal in an ephemeral debug container attached to a pod, for
+// images without a shell (kubectl debug -it --target). The container shares the target
+// container's process namespace where the runtime supports it, and exits when the
+// session ends.
+// GET /api/pods/{namespace}/{name}/debug?target=&image= (WebSocket)
+func (s *Server) handlePodDebug(w http.ResponseWriter, r *http.Request) {
+	namespace, podName := chi.URLParam(r, "namespace"), chi.URLParam(r, "name")
+	image := r.URL.Query().Get("image")
+	if image == "" {
+		image = s.debug.Images[0]
+	}
+	if !slices.Contains(s.debug.Images, image) {
+		s.writeError(w, http.StatusBadRequest, fmt.Sprintf("image %q is not allowed (allowed: %v; see --debug-images)", image, s.debug.Images))
+		return
+	}
+
+	client := k8s.GetClient()
+	if client == nil {
+		s.writeExplorerError(w, explorerErrors.K8sClientNotInitialized())
+		return
+	}
+	pod, err := client.CoreV1().Pods(namespace).Get(r.Context(), podName, metav1.GetOptions{})
+	if err != nil {
+		s.writeError(w, http.StatusNotFound, fmt.Sprintf("pod %s/%s not found: %v", namespace, podName, err))
+		return
+	}
+	if pod.Status.Phase != corev1.PodRunning {
+		s.writeError(w, http.StatusConflict, fmt.Sprintf("pod is %s; debug containers need a running pod", pod.Status.Phase))
+		return
+	}
+	target := r.URL.Query().Get("target")
+	if target == "" && len(pod.Spec.Containers) > 0 {
+		target = pod.Spec.Containers[0].Name
+	}
+	if !slices.ContainsFunc(pod.Spec.Containers, func(c corev1.Container) bool { return c.Name == target }) {
+		s.writeError(w, http.StatusBadRequest, fmt.Sprintf("pod has no container %q", target))
+		return
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("WebSocket upgrade error: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	name := debugContainerName()
+	auditActionDetail(r, "debug", "Pod", namespace, podName, fmt.Sprintf("%s targeting %s", image, target))
+	sendWSOutput(conn, fmt.Sprintf("Starting debug container %s (%s) targeting %s...\r\n", name, image, target))
+	if err := addDebugContainer(r.Context(), namespace, podName, buildDebugContainer(name, image, target)); err != nil {
+		sendWSError(conn, fmt.Sprintf("Failed to add debug container: %v", err))
+		return
+	}
+
+	// Stop the container however the session ends, including client disconnects mid-startup
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		if err := k8s.ExecStream(ctx, namespace, podName, name, []string{"touch", debugDoneMarker}, nil, io.Discard); err != nil {
+			log.Printf("Warning: failed to stop debug container %s in %s/%s (it exits within %s): %v", name, namespace, podName, debugMaxLifetime, err)
+		}
+	}()
+
+	if err := waitForEphemeralContainer(r.Context(), namespace, podName, name); err != nil {
+		sendWSError(conn, fmt.Sprintf("Debug container did not start: %v", err))
+		return
+	}
+
+	session := registerExecSession(namespace, podName, name, conn)
+	defer unregisterExecSession(session.ID)
+
+	command := []string{"sh", "-c", "if command -v bash >/dev/null 2>&1; then exec bash -l; else exec sh -l; fi"}
+	session.startRecording(r, "debug", "", command)
+	if err := s.streamTerminal(r.Context(), session, command); err != nil {
+		log.Printf("Debug session in %s/%s finished with error: %v", namespace, podName, err)
+	}
+}
+
+// buildDebugContainer returns an ephemeral container that idles until the session
+// creates debugDoneMarker, or for debugMaxLifetime if Radar never does (e.g. it's killed)
+func buildDebugContainer(name, image, target string) corev1.EphemeralContainer {
+	keepAlive := fmt.Sprintf(`i=0; while [ ! -e %s ] && [ "$i" -lt %d ]; do sleep 1; i=$((i+1)); done`,
+		debugDoneMarker, int(debugMaxLifetime.Seconds()))
+	return corev1.EphemeralContainer{
+		TargetContainerName: target,
+		EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+			Name:                     name,
+			Image:                    image,
+			ImagePullPolicy:          corev1.PullIfNotPresent,
+			Command:                  []string{"sh", "-c", keepAlive},
+			TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+		},
+	}
+}
+
+// addDebugContainer appends an ephemeral container through the pod's ephemeralcontainers
+// subresource, retrying if the pod changed in between
+func addDebugContainer(ctx context.Context, namespace, podName string, c corev1.EphemeralContainer) error {
+	client, err := k8s.ClientFor(ctx)
+	if err != nil {
+		return err
+	}
+	pods := client.CoreV1().Pods(namespace)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		pod, err := pods.Get(ctx, podName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		pod.Spec.EphemeralContainers = append(pod.Spec.EphemeralContainers, c)
+		_, err = pods.UpdateEphemeralContainers(ctx, podName, pod, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// waitForEphemeralContainer polls until the ephemeral container runs or fails to start
+func waitForEphemeralContainer(ctx context.Context, namespace, podName, name string) error {
+	ctx, cancel := context.WithTimeout(ctx, debugStartTimeout)
+	defer cancel()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		pod, err := k8s.GetClient().CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+		if err == nil {
+			for _, cs := range pod.Status.EphemeralContainerStatuses {
+				if cs.Name != name {
+					continue
+				}
+				switch {
+				case cs.State.Running != nil:
+					return nil
+				case cs.State.Terminated != nil:
+					return fmt.Errorf("exited: %s %s", cs.State.Terminated.Reason, cs.State.Terminated.Message)
+				case cs.State.Waiting != nil && (cs.State.Waiting.Reason == "ErrImagePull" || cs.State.Waiting.Reason == "ImagePullBackOff" ||
+					cs.State.Waiting.Reason == "CreateContainerConfigError" || cs.State.Waiting.Reason == "CreateContainerError"):
+					return fmt.Errorf("%s: %s", cs.State.Waiting.Reason, cs.State.Waiting.Message)
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out after %s", debugStartTimeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+// debugContainerName returns a unique ephemeral container name
+func debugContainerName() string {
+	suffix := make([]byte, 3)
+	_, _ = rand.Read(suffix)
+	return debugContainerPrefix + hex.EncodeToString(suffix)
+}
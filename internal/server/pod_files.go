@@ -0,0 +1,218 @@
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/podfiles"
+)
+
+const (
+	// maxListingBytes bounds a directory listing's output (~100k entries)
+	maxListingBytes = 8 << 20
+	// estimatedSizeHeader carries a download's uncompressed size for progress bars, since
+	// directory archives are streamed without a Content-Length
+	estimatedSizeHeader = "X-Estimated-Size"
+)
+
+// PodFilesResponse is a directory listing
+type PodFilesResponse struct {
+	Path    string           `json:"path"`
+	Entries []podfiles.Entry `json:"entries"`
+}
+
+// PodUploadResponse is the result of an upload
+type PodUploadResponse struct {
+	Path string `json:"path"` // Directory the upload was extracted into
+	podfiles.UploadStats
+}
+
+// podFilePath returns the ?path= parameter, which must be absolute
+func podFilePath(r *http.Request, def string) (string, error) {
+	p := r.URL.Query().Get("path")
+	if p == "" {
+		p = def
+	}
+	if !path.IsAbs(p) {
+		return "", fmt.Errorf("path must be absolute, got %q", p)
+	}
+	return path.Clean(p), nil
+}
+
+// podFileErrorStatus maps a failed command to a status: missing paths are the caller's
+// mistake, anything else is the container's
+func podFileErrorStatus(err error) int {
+	msg := err.Error()
+	if strings.Contains(msg, "No such file") || strings.Contains(msg, "Not a directory") ||
+		strings.Contains(msg, "can't cd") || strings.Contains(msg, "can't open") {
+		return http.StatusNotFound
+	}
+	if strings.Contains(msg, "Permission denied") {
+		return http.StatusForbidden
+	}
+	return http.StatusBadGateway
+}
+
+// handleListPodFiles lists a directory in a container
+// GET /api/pods/{namespace}/{name}/files?container=&path=
+func (s *Server) handleListPodFiles(w http.ResponseWriter, r *http.Request) {
+	namespace, podName := chi.URLParam(r, "namespace"), chi.URLParam(r, "name")
+	dir, err := podFilePath(r, "/")
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var out bytes.Buffer
+	err = k8s.ExecStream(r.Context(), namespace, podName, r.URL.Query().Get("container"),
+		podfiles.ListCommand(dir), nil, &podfiles.LimitWriter{W: &out, N: maxListingBytes})
+	if errors.Is(err, podfiles.ErrTooLarge) {
+		s.writeError(w, http.StatusRequestEntityTooLarge, "directory has too many entries to list")
+		return
+	}
+	if err != nil {
+		s.writeError(w, podFileErrorStatus(err), fmt.Sprintf("failed to list %s: %v", dir, err))
+		return
+	}
+	entries, err := podfiles.ParseListing(out.String())
+	if err != nil {
+		s.writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	s.writeJSON(w, PodFilesResponse{Path: dir, Entries: entries})
+}
+
+// handleDownloadPodFiles streams a file as is, or a directory as a tar.gz archive
+// GET /api/pods/{namespace}/{name}/files/download?container=&path=
+func (s *Server) handleDownloadPodFiles(w http.ResponseWriter, r *http.Request) {
+	namespace, podName := chi.URLParam(r, "namespace"), chi.URLParam(r, "name")
+	container := r.URL.Query().Get("container")
+	p, err := podFilePath(r, "")
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var statOut bytes.Buffer
+	if err := k8s.ExecStream(r.Context(), namespace, podName, container, podfiles.StatCommand(p), nil, &statOut); err != nil {
+		s.writeError(w, podFileErrorStatus(err), fmt.Sprintf("failed to read %s: %v", p, err))
+		return
+	}
+	target, err := podfiles.ParseStat(statOut.String())
+	if err != nil {
+		s.writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	if s.fileTransferMax > 0 && target.Size > s.fileTransferMax {
+		s.writeError(w, http.StatusRequestEntityTooLarge,
+			fmt.Sprintf("%s is %d MB, over the %d MB transfer limit", p, target.Size>>20, s.fileTransferMax>>20))
+		return
+	}
+
+	auditActionDetail(r, "download", "Pod", namespace, podName, p)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment",
+		map[string]string{"filename": podfiles.ArchiveName(p, target.Dir)}))
+	w.Header().Set(estimatedSizeHeader, strconv.FormatInt(target.Size, 10))
+
+	// Once the response has started, a failure can only abort the connection, which tells
+	// the browser the download is incomplete rather than saving a truncated file
+	sent := &podfiles.LimitWriter{W: w}
+	if !target.Dir {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Length", strconv.FormatInt(target.Size, 10))
+		err = k8s.ExecStream(r.Context(), namespace, podName, container, podfiles.ReadCommand(p), nil,
+			&podfiles.LimitWriter{W: sent, N: target.Size})
+	} else {
+		w.Header().Set("Content-Type", "application/gzip")
+		gz := gzip.NewWriter(sent)
+		err = k8s.ExecStream(r.Context(), namespace, podName, container, podfiles.TarCommand(p), nil,
+			&podfiles.LimitWriter{W: gz, N: s.fileTransferMax})
+		if err == nil {
+			err = gz.Close()
+		}
+	}
+	if err == nil {
+		return
+	}
+	log.Printf("Download of %s from %s/%s failed after %d bytes: %v", p, namespace, podName, sent.Written, err)
+	if sent.Written > 0 {
+		panic(http.ErrAbortHandler)
+	}
+	if errors.Is(err, podfiles.ErrTooLarge) {
+		s.writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("%s is over the %d MB transfer limit", p, s.fileTransferMax>>20))
+		return
+	}
+	s.writeError(w, podFileErrorStatus(err), fmt.Sprintf("failed to download %s: %v", p, err))
+}
+
+// handleUploadPodFiles extracts an upload into a container directory. The body is either
+// a tar archive (Content-Type application/x-tar or application/gzip), extracted as is,
+// or a single file named by ?name=.
+// POST /api/pods/{namespace}/{name}/files/upload?container=&path=&name=
+func (s *Server) handleUploadPodFiles(w http.ResponseWriter, r *http.Request) {
+	namespace, podName := chi.URLParam(r, "namespace"), chi.URLParam(r, "name")
+	dir, err := podFilePath(r, "")
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	archive := mediaType == "application/x-tar" || mediaType == "application/gzip" || mediaType == "application/x-gzip"
+	name := r.URL.Query().Get("name")
+	switch {
+	case !archive && name == "":
+		s.writeError(w, http.StatusBadRequest, "name is required unless the body is a tar archive")
+		return
+	case !archive && r.ContentLength < 0:
+		s.writeError(w, http.StatusLengthRequired, "Content-Length is required for file uploads")
+		return
+	case s.fileTransferMax > 0 && r.ContentLength > s.fileTransferMax && mediaType != "application/gzip" && mediaType != "application/x-gzip":
+		s.writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("upload is over the %d MB transfer limit", s.fileTransferMax>>20))
+		return
+	}
+
+	// The archive is validated (and a single file wrapped) while it streams to tar in the
+	// container. A rejected archive may leave the entries before the rejected one behind.
+	pr, pw := io.Pipe()
+	var stats podfiles.UploadStats
+	var produceErr error
+	produced := make(chan struct{})
+	go func() {
+		defer close(produced)
+		if archive {
+			stats, produceErr = podfiles.CopyTar(pw, r.Body, s.fileTransferMax)
+		} else {
+			stats, produceErr = podfiles.WriteFileTar(pw, name, r.ContentLength, r.Body, s.fileTransferMax)
+		}
+		pw.CloseWithError(produceErr)
+	}()
+	execErr := k8s.ExecStream(r.Context(), namespace, podName, r.URL.Query().Get("container"),
+		podfiles.ExtractCommand(dir), pr, io.Discard)
+	pr.CloseWithError(io.ErrClosedPipe) // Unblock the producer if tar exited early
+	<-produced
+
+	switch {
+	case errors.Is(produceErr, podfiles.ErrTooLarge):
+		s.writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("upload is over the %d MB transfer limit", s.fileTransferMax>>20))
+	case produceErr != nil && !errors.Is(produceErr, io.ErrClosedPipe):
+		s.writeError(w, http.StatusBadRequest, produceErr.Error())
+	case execErr != nil:
+		s.writeError(w, podFileErrorStatus(execErr), fmt.Sprintf("failed to extract into %s: %v", dir, execErr))
+	default:
+		detail := fmt.Sprintf("%d files (%d bytes) to %s", stats.Files, stats.Bytes, dir)
+		auditActionDetail(r, "upload", "Pod", namespace, podName, detail)
+		s.writeJSON(w, PodUploadResponse{Path: dir, UploadStats: stats})
+	}
+}
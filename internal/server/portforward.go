@@ -18,8 +18,9 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/portforward"
-	"k8s.io/client-go/transport/spdy"
 
+	"github.com/skyhook-io/radar/internal/auth"
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
 	"github.com/skyhook-io/radar/internal/k8s"
 )
 
@@ -31,6 +32,7 @@ type PortForwardSession struct {
 	PodPort     int       `json:"podPort"`
 	LocalPort   int       `json:"localPort"`
 	ServiceName string    `json:"serviceName,omitempty"` // If forwarding to a service
+	Profile     string    `json:"profile,omitempty"`     // Set when started from a named profile
 	StartedAt   time.Time `json:"startedAt"`
 	Status      string    `json:"status"` // "running", "stopped", "error"
 	Error       string    `json:"error,omitempty"`
@@ -102,38 +104,50 @@ func (s *Server) handleStartPortForward(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if req.Namespace == "" || req.PodPort == 0 {
-		s.writeError(w, http.StatusBadRequest, "namespace and podPort are required")
+	session, status, err := startPortForward(r.Context(), req, "")
+	if err != nil {
+		s.writeError(w, status, err.Error())
 		return
 	}
+	s.writeJSON(w, session)
+}
+
+// startPortForward validates the request and starts a session. On failure it returns
+// the HTTP status that best describes the error. profile tags sessions started from a profile.
+func startPortForward(ctx context.Context, req PortForwardRequest, profile string) (*PortForwardSession, int, error) {
+	if req.Namespace == "" || req.PodPort == 0 {
+		return nil, http.StatusBadRequest, fmt.Errorf("namespace and podPort are required")
+	}
 
 	if req.PodName == "" && req.ServiceName == "" {
-		s.writeError(w, http.StatusBadRequest, "either podName or serviceName is required")
-		return
+		return nil, http.StatusBadRequest, fmt.Errorf("either podName or serviceName is required")
 	}
 
 	client := k8s.GetClient()
 	config := k8s.GetConfig()
 	if client == nil || config == nil {
-		s.writeError(w, http.StatusServiceUnavailable, "K8s client not initialized")
-		return
+		return nil, http.StatusServiceUnavailable, fmt.Errorf("K8s client not initialized")
+	}
+	if err := auth.CheckNamespace(ctx, req.Namespace); err != nil {
+		return nil, http.StatusForbidden, err
+	}
+	if err := checkUserAccess(ctx, k8s.PermissionCheck{Verb: "create", Resource: "pods", Subresource: "portforward", Namespace: req.Namespace}); err != nil {
+		return nil, http.StatusForbidden, err
 	}
 
 	// If service name provided, find a pod backing it
 	podName := req.PodName
 	if req.ServiceName != "" && podName == "" {
-		foundPod, err := findPodForService(r.Context(), req.Namespace, req.ServiceName, req.PodPort)
+		foundPod, err := findPodForService(ctx, req.Namespace, req.ServiceName, req.PodPort)
 		if err != nil {
-			s.writeError(w, http.StatusNotFound, fmt.Sprintf("No pod found for service %s: %v", req.ServiceName, err))
-			return
+			return nil, http.StatusNotFound, fmt.Errorf("No pod found for service %s: %v", req.ServiceName, err)
 		}
 		podName = foundPod
 	}
 
 	// Validate that the pod actually exposes this port
-	if err := validatePodPort(r.Context(), req.Namespace, podName, req.PodPort); err != nil {
-		s.writeError(w, http.StatusBadRequest, err.Error())
-		return
+	if err := validatePodPort(ctx, req.Namespace, podName, req.PodPort); err != nil {
+		return nil, http.StatusBadRequest, err
 	}
 
 	// Find available local port if not specified
@@ -141,8 +155,7 @@ func (s *Server) handleStartPortForward(w http.ResponseWriter, r *http.Request)
 	if localPort == 0 {
 		port, err := findFreePort()
 		if err != nil {
-			s.writeError(w, http.StatusInternalServerError, "Failed to find free port")
-			return
+			return nil, http.StatusInternalServerError, fmt.Errorf("Failed to find free port")
 		}
 		localPort = port
 	}
@@ -152,7 +165,7 @@ func (s *Server) handleStartPortForward(w http.ResponseWriter, r *http.Request)
 	pfManager.nextID++
 	sessionID := fmt.Sprintf("pf-%d", pfManager.nextID)
 
-	ctx, cancel := context.WithCancel(context.Background())
+	pfCtx, cancel := context.WithCancel(context.Background())
 	stopCh := make(chan struct{})
 
 	session := &PortForwardSession{
@@ -162,6 +175,7 @@ func (s *Server) handleStartPortForward(w http.ResponseWriter, r *http.Request)
 		PodPort:     req.PodPort,
 		LocalPort:   localPort,
 		ServiceName: req.ServiceName,
+		Profile:     profile,
 		StartedAt:   time.Now(),
 		Status:      "starting",
 		cancel:      cancel,
@@ -172,7 +186,7 @@ func (s *Server) handleStartPortForward(w http.ResponseWriter, r *http.Request)
 
 	// Start port forward in goroutine
 	go func() {
-		err := runPortForward(ctx, session)
+		err := runPortForward(pfCtx, session)
 		pfManager.mu.Lock()
 		if err != nil {
 			session.Status = "error"
@@ -199,24 +213,33 @@ func (s *Server) handleStartPortForward(w http.ResponseWriter, r *http.Request)
 	pfManager.mu.RUnlock()
 
 	if session.Status == "error" {
-		s.writeError(w, http.StatusInternalServerError, session.Error)
-		return
+		return nil, http.StatusInternalServerError, fmt.Errorf("%s", session.Error)
 	}
 
 	session.Status = "running"
-	s.writeJSON(w, session)
+	return session, http.StatusOK, nil
 }
 
 // handleStopPortForward stops an active port forward session
 func (s *Server) handleStopPortForward(w http.ResponseWriter, r *http.Request) {
 	sessionID := chi.URLParam(r, "id")
 
+	if !stopPortForward(sessionID) {
+		s.writeError(w, http.StatusNotFound, "Session not found")
+		return
+	}
+
+	s.writeJSON(w, map[string]string{"status": "stopped"})
+}
+
+// stopPortForward stops and removes a session, returning false if it doesn't exist
+func stopPortForward(sessionID string) bool {
 	pfManager.mu.Lock()
+	defer pfManager.mu.Unlock()
+
 	session, ok := pfManager.sessions[sessionID]
 	if !ok {
-		pfManager.mu.Unlock()
-		s.writeError(w, http.StatusNotFound, "Session not found")
-		return
+		return false
 	}
 
 	// Signal stop
@@ -224,9 +247,7 @@ func (s *Server) handleStopPortForward(w http.ResponseWriter, r *http.Request) {
 	close(session.stopCh)
 	session.Status = "stopped"
 	delete(pfManager.sessions, sessionID)
-	pfManager.mu.Unlock()
-
-	s.writeJSON(w, map[string]string{"status": "stopped"})
+	return true
 }
 
 func runPortForward(ctx context.Context, session *PortForwardSession) error {
@@ -243,13 +264,11 @@ func runPortForward(ctx context.Context, session *PortForwardSession) error {
 			Ports: []int32{int32(session.PodPort)},
 		}, scheme.ParameterCodec)
 
-	transport, upgrader, err := spdy.RoundTripperFor(config)
+	dialer, err := k8s.NewPortForwardDialer(config, req.URL())
 	if err != nil {
-		return fmt.Errorf("failed to create round tripper: %w", err)
+		return err
 	}
 
-	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", req.URL())
-
 	ports := []string{fmt.Sprintf("%d:%d", session.LocalPort, session.PodPort)}
 	readyCh := make(chan struct{})
 
@@ -432,7 +451,7 @@ func (s *Server) handleGetAvailablePorts(w http.ResponseWriter, r *http.Request)
 
 	client := k8s.GetClient()
 	if client == nil {
-		s.writeError(w, http.StatusServiceUnavailable, "K8s client not initialized")
+		s.writeExplorerError(w, explorerErrors.K8sClientNotInitialized())
 		return
 	}
 
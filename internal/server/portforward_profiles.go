@@ -0,0 +1,268 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/skyhook-io/radar/internal/settings"
+)
+
+// portForwardProfilesSection is the settings store section holding profiles
+const portForwardProfilesSection = "portForwardProfiles"
+
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9 ._-]{0,62}$`)
+
+// PortForwardProfile is a named set of forwards started and stopped as a unit
+// (e.g. "payments local dev": svc/payments-api 8080 and svc/payments-db 5432)
+type PortForwardProfile struct {
+	Name        string               `json:"name"`
+	Description string               `json:"description,omitempty"`
+	Forwards    []PortForwardRequest `json:"forwards"`
+}
+
+// PortForwardProfileStatus is a profile plus the sessions currently running for it
+type PortForwardProfileStatus struct {
+	PortForwardProfile
+	Active   bool                  `json:"active"`
+	Sessions []*PortForwardSession `json:"sessions"`
+}
+
+// ProfileStartResult reports the outcome of starting a profile; forwards that
+// fail don't prevent the others from starting
+type ProfileStartResult struct {
+	Profile  string                `json:"profile"`
+	Sessions []*PortForwardSession `json:"sessions"`
+	Errors   []string              `json:"errors,omitempty"`
+}
+
+// pfProfilesMu serializes profile edits and start/stop so a profile isn't started twice
+var pfProfilesMu sync.Mutex
+
+func loadPortForwardProfiles() (map[string]PortForwardProfile, error) {
+	profiles := make(map[string]PortForwardProfile)
+	if _, err := settings.Get().Load(portForwardProfilesSection, &profiles); err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+func validatePortForwardProfile(p PortForwardProfile) error {
+	if !profileNamePattern.MatchString(p.Name) {
+		return fmt.Errorf("invalid profile name %q (letters, digits, space, '.', '_', '-'; max 63 chars)", p.Name)
+	}
+	if len(p.Forwards) == 0 {
+		return fmt.Errorf("profile must contain at least one forward")
+	}
+	localPorts := make(map[int]bool)
+	for i, f := range p.Forwards {
+		if f.Namespace == "" || f.PodPort == 0 {
+			return fmt.Errorf("forwards[%d]: namespace and podPort are required", i)
+		}
+		if f.PodName == "" && f.ServiceName == "" {
+			return fmt.Errorf("forwards[%d]: either podName or serviceName is required", i)
+		}
+		if f.LocalPort != 0 {
+			if localPorts[f.LocalPort] {
+				return fmt.Errorf("forwards[%d]: local port %d is used twice", i, f.LocalPort)
+			}
+			localPorts[f.LocalPort] = true
+		}
+	}
+	return nil
+}
+
+// profileSessions returns the running sessions started from a profile
+func profileSessions(name string) []*PortForwardSession {
+	pfManager.mu.RLock()
+	defer pfManager.mu.RUnlock()
+
+	sessions := make([]*PortForwardSession, 0)
+	for _, session := range pfManager.sessions {
+		if session.Profile == name {
+			sessions = append(sessions, session)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].StartedAt.Before(sessions[j].StartedAt) })
+	return sessions
+}
+
+// StartPortForwardProfile starts every forward in a profile. Already-active profiles
+// are left untouched and their sessions returned.
+func StartPortForwardProfile(ctx context.Context, name string) (*ProfileStartResult, error) {
+	pfProfilesMu.Lock()
+	defer pfProfilesMu.Unlock()
+
+	profiles, err := loadPortForwardProfiles()
+	if err != nil {
+		return nil, err
+	}
+	profile, ok := profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("profile %q not found", name)
+	}
+
+	result := &ProfileStartResult{Profile: name}
+	if existing := profileSessions(name); len(existing) > 0 {
+		result.Sessions = existing
+		return result, nil
+	}
+	for _, fwd := range profile.Forwards {
+		session, _, err := startPortForward(ctx, fwd, name)
+		if err != nil {
+			target := fwd.ServiceName
+			if target == "" {
+				target = "pod/" + fwd.PodName
+			} else {
+				target = "svc/" + target
+			}
+			result.Errors = append(result.Errors, fmt.Sprintf("%s/%s:%d: %v", fwd.Namespace, target, fwd.PodPort, err))
+			continue
+		}
+		result.Sessions = append(result.Sessions, session)
+	}
+	return result, nil
+}
+
+// StopPortForwardProfile stops all sessions started from a profile, returning how many were stopped
+func StopPortForwardProfile(name string) int {
+	pfProfilesMu.Lock()
+	defer pfProfilesMu.Unlock()
+
+	stopped := 0
+	for _, session := range profileSessions(name) {
+		if stopPortForward(session.ID) {
+			stopped++
+		}
+	}
+	return stopped
+}
+
+// AutoStartPortForwardProfiles starts the named profiles at launch, logging failures
+func AutoStartPortForwardProfiles(names []string) {
+	for _, name := range names {
+		result, err := StartPortForwardProfile(context.Background(), name)
+		if err != nil {
+			log.Printf("Warning: Failed to start port-forward profile %q: %v", name, err)
+			continue
+		}
+		for _, session := range result.Sessions {
+			log.Printf("Port-forward profile %q: localhost:%d -> %s/%s:%d",
+				name, session.LocalPort, session.Namespace, session.PodName, session.PodPort)
+		}
+		for _, msg := range result.Errors {
+			log.Printf("Warning: Port-forward profile %q: %s", name, msg)
+		}
+	}
+}
+
+// handleListPortForwardProfiles returns all saved profiles with their running sessions
+func (s *Server) handleListPortForwardProfiles(w http.ResponseWriter, r *http.Request) {
+	profiles, err := loadPortForwardProfiles()
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	result := make([]PortForwardProfileStatus, 0, len(profiles))
+	for _, p := range profiles {
+		sessions := profileSessions(p.Name)
+		result = append(result, PortForwardProfileStatus{
+			PortForwardProfile: p,
+			Active:             len(sessions) > 0,
+			Sessions:           sessions,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	s.writeJSON(w, result)
+}
+
+// handleSavePortForwardProfile creates or replaces a profile
+func (s *Server) handleSavePortForwardProfile(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	var profile PortForwardProfile
+	if err := json.NewDecoder(r.Body).Decode(&profile); err != nil {
+		s.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	profile.Name = strings.TrimSpace(name)
+	if err := validatePortForwardProfile(profile); err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	pfProfilesMu.Lock()
+	defer pfProfilesMu.Unlock()
+
+	profiles, err := loadPortForwardProfiles()
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	profiles[profile.Name] = profile
+	if err := settings.Get().Save(portForwardProfilesSection, profiles); err != nil {
+		s.writeError(w, http.StatusInternalServerError, "Failed to save profile: "+err.Error())
+		return
+	}
+	s.writeJSON(w, profile)
+}
+
+// handleDeletePortForwardProfile removes a profile (running sessions keep running)
+func (s *Server) handleDeletePortForwardProfile(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	pfProfilesMu.Lock()
+	defer pfProfilesMu.Unlock()
+
+	profiles, err := loadPortForwardProfiles()
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if _, ok := profiles[name]; !ok {
+		s.writeError(w, http.StatusNotFound, "Profile not found")
+		return
+	}
+	delete(profiles, name)
+	if err := settings.Get().Save(portForwardProfilesSection, profiles); err != nil {
+		s.writeError(w, http.StatusInternalServerError, "Failed to save profiles: "+err.Error())
+		return
+	}
+	s.writeJSON(w, map[string]string{"status": "deleted"})
+}
+
+// handleStartPortForwardProfile starts all forwards in a profile
+func (s *Server) handleStartPortForwardProfile(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	result, err := StartPortForwardProfile(r.Context(), name)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		}
+		s.writeError(w, status, err.Error())
+		return
+	}
+	if len(result.Sessions) == 0 && len(result.Errors) > 0 {
+		s.writeError(w, http.StatusBadGateway, "No forwards started: "+strings.Join(result.Errors, "; "))
+		return
+	}
+	s.writeJSON(w, result)
+}
+
+// handleStopPortForwardProfile stops all sessions started from a profile
+func (s *Server) handleStopPortForwardProfile(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	stopped := StopPortForwardProfile(name)
+	s.writeJSON(w, map[string]any{"status": "stopped", "stopped": stopped})
+}
@@ -0,0 +1,558 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/policy"
+	"github.com/skyhook-io/radar/internal/signatures"
+)
+
+// Problem is a single detected issue with a stable identity and priority score
+type Problem struct {
+	ID              string     `json:"id"` // Stable across refreshes for the same underlying issue
+	Kind            string     `json:"kind"`
+	Namespace       string     `json:"namespace,omitempty"`
+	Name            string     `json:"name"`
+	Severity        string     `json:"severity"` // critical, error, warning
+	Reason          string     `json:"reason"`
+	Message         string     `json:"message,omitempty"`
+	Impact          int        `json:"impact"` // Blast radius weight (kind × severity × affected replicas)
+	Score           float64    `json:"score"`  // impact × duration weight, higher = more urgent
+	Since           time.Time  `json:"since"`  // Best estimate of when the issue started
+	Duration        string     `json:"duration"`
+	DurationSeconds int64      `json:"durationSeconds"`
+	Snoozed         bool       `json:"snoozed,omitempty"`
+	SnoozedUntil    *time.Time `json:"snoozedUntil,omitempty"`
+	TimelineEventID string     `json:"timelineEventId,omitempty"` // Timeline event explaining the problem, if any
+	// Known failure signatures the problem or its Warning events match, most specific first
+	KnownCauses []signatures.KnownCause `json:"knownCauses,omitempty"`
+}
+
+// ProblemsResponse is a page of problems plus aggregate counts
+type ProblemsResponse struct {
+	Problems   []Problem      `json:"problems"`
+	Total      int            `json:"total"` // Total matching filters (before pagination)
+	Offset     int            `json:"offset"`
+	Limit      int            `json:"limit"`
+	HasMore    bool           `json:"hasMore"`
+	BySeverity map[string]int `json:"bySeverity"`
+	// Problems per primary known cause, so many pods failing the same way read as one issue
+	BySignature map[string]int `json:"bySignature,omitempty"`
+	Snoozed     int            `json:"snoozed"` // Problems hidden by snooze
+}
+
+// ProblemFilter narrows the problem list
+type ProblemFilter struct {
+	Namespace      string
+	Kinds          []string
+	Severities     []string
+	Signatures     []string // Primary known cause IDs
+	IncludeSnoozed bool
+}
+
+// severityWeight ranks severities for impact scoring
+var severityWeight = map[string]int{
+	"critical": 10,
+	"error":    5,
+	"warning":  1,
+}
+
+// kindWeight reflects how many things are usually affected by a problem on each kind
+var kindWeight = map[string]int{
+	"Node":        8,
+	"Deployment":  3,
+	"StatefulSet": 3,
+	"DaemonSet":   3,
+	"Service":     2,
+	"Ingress":     2,
+	"Pod":         1,
+}
+
+// problemSnoozes tracks snoozed problem IDs and their expiry (in-memory, per process)
+var (
+	problemSnoozes   = make(map[string]time.Time)
+	problemSnoozesMu sync.Mutex
+)
+
+// SnoozeProblem hides a problem until the given time
+func SnoozeProblem(id string, until time.Time) {
+	problemSnoozesMu.Lock()
+	defer problemSnoozesMu.Unlock()
+	problemSnoozes[id] = until
+}
+
+// UnsnoozeProblem removes a snooze
+func UnsnoozeProblem(id string) bool {
+	problemSnoozesMu.Lock()
+	defer problemSnoozesMu.Unlock()
+	_, ok := problemSnoozes[id]
+	delete(problemSnoozes, id)
+	return ok
+}
+
+// snoozedUntil returns the snooze expiry for an ID, pruning expired entries
+func snoozedUntil(id string, now time.Time) (time.Time, bool) {
+	problemSnoozesMu.Lock()
+	defer problemSnoozesMu.Unlock()
+	until, ok := problemSnoozes[id]
+	if !ok {
+		return time.Time{}, false
+	}
+	if now.After(until) {
+		delete(problemSnoozes, id)
+		return time.Time{}, false
+	}
+	return until, true
+}
+
+// ProblemID returns a stable identifier for an issue. Numbers are stripped from the reason
+// so that "1/3 available" and "2/3 available" keep the same identity.
+func ProblemID(kind, namespace, name, reason string) string {
+	normalized := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return -1
+		}
+		return r
+	}, reason)
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s/%s/%s/%s", kind, namespace, name, normalized)))
+	return fmt.Sprintf("prb-%x", hash[:6])
+}
+
+// CollectProblems gathers all current problems in priority order (highest score first)
+func CollectProblems(cache *k8s.ResourceCache, namespace string, now time.Time) []Problem {
+	var problems []Problem
+
+	// Pod problems
+	var pods []*corev1.Pod
+	var err error
+	if namespace != "" {
+		pods, err = cache.Pods().Pods(namespace).List(labels.Everything())
+	} else {
+		pods, err = cache.Pods().List(labels.Everything())
+	}
+	if err == nil {
+		for _, pod := range pods {
+			status := classifyPodHealth(pod, now)
+			if status == "healthy" {
+				continue
+			}
+			dp := podToProblem(pod, status, now)
+			problems = append(problems, newProblem(dp, 1, podProblemSince(pod), now))
+		}
+	}
+
+	// Workload and node problems
+	for _, dp := range collectWorkloadProblems(cache, namespace, now) {
+		problems = append(problems, newProblem(dp, workloadAffected(cache, dp), workloadProblemSince(cache, dp), now))
+	}
+
+	// Cross-resource consistency findings (resolved findings disappear on the next check run)
+	for _, f := range k8s.GetConsistencyChecker().Findings(namespace) {
+		dp := DashboardProblem{
+			Kind:      f.Kind,
+			Namespace: f.Namespace,
+			Name:      f.Name,
+			Status:    f.Severity,
+			Reason:    f.Reason,
+			Message:   f.Message,
+		}
+		p := newProblem(dp, 1, f.FirstSeen, now)
+		p.TimelineEventID = f.TimelineEventID
+		problems = append(problems, p)
+	}
+
+	for _, v := range policy.GetChecker().Violations(namespace) {
+		dp := DashboardProblem{
+			Kind:      v.Kind,
+			Namespace: v.Namespace,
+			Name:      v.Name,
+			Status:    v.Severity,
+			Reason:    fmt.Sprintf("PolicyViolation(%s/%s)", v.Rule, v.Check),
+			Message:   v.Message,
+		}
+		if v.Container != "" {
+			// Keep per-container violations distinct (the reason feeds the problem ID)
+			dp.Reason = fmt.Sprintf("PolicyViolation(%s/%s/%s)", v.Rule, v.Check, v.Container)
+			dp.Message = v.Container + ": " + v.Message
+		}
+		problems = append(problems, newProblem(dp, 1, v.FirstSeen, now))
+	}
+
+	// PVCs filling up (or reported abnormal by CSI volume health)
+	if usages, err := k8s.GetUsageForecaster().PVCUsages(context.Background(), namespace); err == nil {
+		for _, u := range usages {
+			if dp, ok := pvcUsageProblem(u); ok {
+				problems = append(problems, newProblem(dp, 1, time.Time{}, now))
+			}
+		}
+	}
+
+	attachKnownCauses(cache, namespace, problems)
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].Score != problems[j].Score {
+			return problems[i].Score > problems[j].Score
+		}
+		return problems[i].ID < problems[j].ID
+	})
+
+	return problems
+}
+
+// pvcUsageProblem reports a PVC over the usage thresholds. Reasons carry no numbers so
+// the problem keeps its ID as usage grows; the percentage is in the message.
+func pvcUsageProblem(u k8s.PVCUsage) (DashboardProblem, bool) {
+	dp := DashboardProblem{
+		Kind:      "PersistentVolumeClaim",
+		Namespace: u.Namespace,
+		Name:      u.Name,
+		Message:   u.Message,
+	}
+	switch {
+	case u.Abnormal:
+		dp.Status, dp.Reason = "error", "VolumeAbnormal"
+	case u.Status == k8s.PVCUsageCritical:
+		dp.Status, dp.Reason = "error", "VolumeAlmostFull"
+	case u.Status == k8s.PVCUsageWarning:
+		dp.Status, dp.Reason = "warning", "VolumeFillingUp"
+	default:
+		return dp, false
+	}
+	if u.Expandable && u.Resizing == "" {
+		dp.Message += " (storage class allows expansion)"
+	}
+	return dp, true
+}
+
+// newProblem converts a dashboard problem into a scored Problem
+func newProblem(dp DashboardProblem, affected int, since time.Time, now time.Time) Problem {
+	severity := dp.Status
+	// Node outages and fully unavailable workloads are escalated
+	if severity == "error" && (dp.Kind == "Node" || strings.HasPrefix(dp.Reason, "0/")) {
+		severity = "critical"
+	}
+	if affected < 1 {
+		affected = 1
+	}
+
+	kw := kindWeight[dp.Kind]
+	if kw == 0 {
+		kw = 1
+	}
+	impact := severityWeight[severity] * kw * affected
+
+	if since.IsZero() || since.After(now) {
+		since = now.Add(-time.Duration(dp.AgeSeconds) * time.Second)
+	}
+	duration := now.Sub(since)
+
+	p := Problem{
+		ID:              ProblemID(dp.Kind, dp.Namespace, dp.Name, dp.Reason),
+		Kind:            dp.Kind,
+		Namespace:       dp.Namespace,
+		Name:            dp.Name,
+		Severity:        severity,
+		Reason:          dp.Reason,
+		Message:         dp.Message,
+		Impact:          impact,
+		Score:           math.Round(float64(impact)*durationWeight(duration)*100) / 100,
+		Since:           since,
+		Duration:        formatAge(duration),
+		DurationSeconds: int64(duration.Seconds()),
+	}
+	if until, ok := snoozedUntil(p.ID, now); ok {
+		p.Snoozed = true
+		p.SnoozedUntil = &until
+	}
+	return p
+}
+
+// attachKnownCauses matches each problem, and the Warning events of its resource, against
+// the known failure signatures
+func attachKnownCauses(cache *k8s.ResourceCache, namespace string, problems []Problem) {
+	if len(problems) == 0 {
+		return
+	}
+	var events []*corev1.Event
+	if namespace != "" {
+		events, _ = cache.Events().Events(namespace).List(labels.Everything())
+	} else {
+		events, _ = cache.Events().List(labels.Everything())
+	}
+	// Latest first, so current failures are matched before old ones
+	sort.Slice(events, func(i, j int) bool { return eventTime(events[i]).After(eventTime(events[j])) })
+	evidence := make(map[string][]signatures.Evidence)
+	for _, e := range events {
+		if e.Type != corev1.EventTypeWarning {
+			continue
+		}
+		key := e.InvolvedObject.Kind + "/" + e.InvolvedObject.Namespace + "/" + e.InvolvedObject.Name
+		evidence[key] = append(evidence[key], signatures.Evidence{Reason: e.Reason, Message: e.Message})
+	}
+
+	for i := range problems {
+		p := &problems[i]
+		ev := append([]signatures.Evidence{{Reason: p.Reason, Message: p.Message}},
+			evidence[p.Kind+"/"+p.Namespace+"/"+p.Name]...)
+		p.KnownCauses = signatures.Match(p.Kind, ev)
+	}
+}
+
+// eventTime is when an event was last seen
+func eventTime(e *corev1.Event) time.Time {
+	if !e.LastTimestamp.IsZero() {
+		return e.LastTimestamp.Time
+	}
+	if !e.EventTime.IsZero() {
+		return e.EventTime.Time
+	}
+	return e.CreationTimestamp.Time
+}
+
+// primaryCause returns the ID of a problem's most specific known cause, if any
+func primaryCause(p Problem) string {
+	if len(p.KnownCauses) == 0 {
+		return ""
+	}
+	return p.KnownCauses[0].SignatureID
+}
+
+// durationWeight grows logarithmically so long-running issues rank higher
+// without letting week-old warnings drown out fresh outages
+func durationWeight(d time.Duration) float64 {
+	minutes := d.Minutes()
+	if minutes < 0 {
+		minutes = 0
+	}
+	return 1 + math.Log1p(minutes)
+}
+
+// podProblemSince estimates when a pod's issue began
+func podProblemSince(pod *corev1.Pod) time.Time {
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.LastTerminationState.Terminated != nil && !cs.LastTerminationState.Terminated.FinishedAt.IsZero() {
+			return cs.LastTerminationState.Terminated.FinishedAt.Time
+		}
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady && cond.Status != corev1.ConditionTrue && !cond.LastTransitionTime.IsZero() {
+			return cond.LastTransitionTime.Time
+		}
+	}
+	return pod.CreationTimestamp.Time
+}
+
+// workloadProblemSince estimates when a workload or node issue began using condition transitions
+func workloadProblemSince(cache *k8s.ResourceCache, dp DashboardProblem) time.Time {
+	switch dp.Kind {
+	case "Deployment":
+		if d, err := cache.Deployments().Deployments(dp.Namespace).Get(dp.Name); err == nil {
+			for _, cond := range d.Status.Conditions {
+				if cond.Type == "Available" && cond.Status != corev1.ConditionTrue {
+					return cond.LastTransitionTime.Time
+				}
+			}
+			for _, cond := range d.Status.Conditions {
+				if cond.Type == "Progressing" {
+					return cond.LastTransitionTime.Time
+				}
+			}
+		}
+	case "Node":
+		if n, err := cache.Nodes().Get(dp.Name); err == nil {
+			for _, cond := range n.Status.Conditions {
+				if cond.Type == corev1.NodeReady {
+					return cond.LastTransitionTime.Time
+				}
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// workloadAffected returns the number of affected replicas (or pods on a node)
+func workloadAffected(cache *k8s.ResourceCache, dp DashboardProblem) int {
+	switch dp.Kind {
+	case "Deployment":
+		if d, err := cache.Deployments().Deployments(dp.Namespace).Get(dp.Name); err == nil {
+			return int(d.Status.UnavailableReplicas)
+		}
+	case "StatefulSet":
+		if ss, err := cache.StatefulSets().StatefulSets(dp.Namespace).Get(dp.Name); err == nil {
+			return int(ss.Status.Replicas - ss.Status.ReadyReplicas)
+		}
+	case "DaemonSet":
+		if ds, err := cache.DaemonSets().DaemonSets(dp.Namespace).Get(dp.Name); err == nil {
+			return int(ds.Status.NumberUnavailable)
+		}
+	}
+	return 1
+}
+
+// FilterProblems applies the filter and splits out snoozed problems
+func FilterProblems(problems []Problem, f ProblemFilter) (matched []Problem, snoozed int) {
+	matched = make([]Problem, 0, len(problems))
+	for _, p := range problems {
+		if f.Namespace != "" && p.Namespace != f.Namespace {
+			continue
+		}
+		if len(f.Kinds) > 0 && !containsFold(f.Kinds, p.Kind) {
+			continue
+		}
+		if len(f.Severities) > 0 && !containsFold(f.Severities, p.Severity) {
+			continue
+		}
+		if len(f.Signatures) > 0 && !containsFold(f.Signatures, primaryCause(p)) {
+			continue
+		}
+		if p.Snoozed && !f.IncludeSnoozed {
+			snoozed++
+			continue
+		}
+		matched = append(matched, p)
+	}
+	return matched, snoozed
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitParam splits a comma-separated query parameter, dropping empty entries
+func splitParam(v string) []string {
+	if v == "" {
+		return nil
+	}
+	var out []string
+	for _, part := range strings.Split(v, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// handleProblems returns a filtered, paginated, priority-sorted problem list
+func (s *Server) handleProblems(w http.ResponseWriter, r *http.Request) {
+	cache := k8s.GetResourceCache()
+	if cache == nil {
+		s.writeExplorerError(w, explorerErrors.CacheNotInitialized())
+		return
+	}
+
+	q := r.URL.Query()
+	filter := ProblemFilter{
+		Namespace:      q.Get("namespace"),
+		Kinds:          splitParam(q.Get("kind")),
+		Severities:     splitParam(q.Get("severity")),
+		Signatures:     splitParam(q.Get("signature")),
+		IncludeSnoozed: q.Get("includeSnoozed") == "true",
+	}
+
+	limit := 50
+	if v := q.Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			limit = n
+		}
+	}
+	if limit > 500 {
+		limit = 500
+	}
+	offset := 0
+	if v := q.Get("offset"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			offset = n
+		}
+	}
+
+	all := CollectProblems(cache, filter.Namespace, time.Now())
+	matched, snoozed := FilterProblems(all, filter)
+
+	resp := ProblemsResponse{
+		Problems:   []Problem{},
+		Total:      len(matched),
+		Offset:     offset,
+		Limit:      limit,
+		BySeverity: make(map[string]int),
+		Snoozed:    snoozed,
+	}
+	for _, p := range matched {
+		resp.BySeverity[p.Severity]++
+		if id := primaryCause(p); id != "" {
+			if resp.BySignature == nil {
+				resp.BySignature = make(map[string]int)
+			}
+			resp.BySignature[id]++
+		}
+	}
+	if offset < len(matched) {
+		end := offset + limit
+		if end > len(matched) {
+			end = len(matched)
+		}
+		resp.Problems = matched[offset:end]
+		resp.HasMore = end < len(matched)
+	}
+
+	s.writeJSON(w, resp)
+}
+
+// SnoozeRequest is the body for snoozing a problem
+type SnoozeRequest struct {
+	Duration string `json:"duration"` // Go duration, e.g. "30m", "4h"
+}
+
+// handleSnoozeProblem hides a problem for a duration
+func (s *Server) handleSnoozeProblem(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req SnoozeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	d, err := time.ParseDuration(req.Duration)
+	if err != nil || d <= 0 {
+		s.writeError(w, http.StatusBadRequest, "duration must be a positive Go duration (e.g. 30m, 4h)")
+		return
+	}
+	if d > 7*24*time.Hour {
+		s.writeError(w, http.StatusBadRequest, "duration cannot exceed 168h")
+		return
+	}
+
+	until := time.Now().Add(d)
+	SnoozeProblem(id, until)
+	s.writeJSON(w, map[string]any{"id": id, "snoozedUntil": until})
+}
+
+// handleUnsnoozeProblem clears a snooze
+func (s *Server) handleUnsnoozeProblem(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if !UnsnoozeProblem(id) {
+		s.writeError(w, http.StatusNotFound, "problem is not snoozed")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
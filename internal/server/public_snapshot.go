@@ -0,0 +1,375 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+const (
+	defaultPublicSnapshotInterval = 30 * time.Second
+	minPublicSnapshotInterval     = 5 * time.Second
+	// maxPublicSnapshotProblems caps the problems listed in a snapshot
+	maxPublicSnapshotProblems = 20
+)
+
+// PublicSnapshotConfig controls the public snapshot: a sanitized, read-only summary of
+// cluster health for wallboards, rebuilt on an interval. It is disabled unless Serve or
+// File is set.
+type PublicSnapshotConfig struct {
+	Serve      bool          // Serve at /public/snapshot.json without an API token
+	File       string        // Write to this file (replaced atomically on each refresh)
+	Interval   time.Duration // How often the snapshot is rebuilt
+	Namespaces []string      // Only these namespaces (empty = all)
+	HideNames  bool          // Omit cluster, namespace and resource names
+}
+
+// Enabled reports whether a snapshot is published anywhere
+func (c PublicSnapshotConfig) Enabled() bool {
+	return c.Serve || c.File != ""
+}
+
+func (c PublicSnapshotConfig) withDefaults() PublicSnapshotConfig {
+	if c.Interval <= 0 {
+		c.Interval = defaultPublicSnapshotInterval
+	}
+	if c.Interval < minPublicSnapshotInterval {
+		c.Interval = minPublicSnapshotInterval
+	}
+	return c
+}
+
+// PublicSnapshot is the published summary. It carries counts and statuses only: no
+// messages, labels, images or other resource details.
+type PublicSnapshot struct {
+	GeneratedAt time.Time         `json:"generatedAt"`
+	Cluster     PublicCluster     `json:"cluster"`
+	Status      string            `json:"status"` // healthy, warning, error or unknown
+	Pods        PublicHealthCount `json:"pods"`
+	Workloads   WorkloadCount     `json:"workloads"` // Deployments, StatefulSets and DaemonSets
+	Nodes       NodeCount         `json:"nodes"`
+	Namespaces  []PublicNamespace `json:"namespaces"` // Worst status first
+	Problems    []PublicProblem   `json:"problems"`
+}
+
+// PublicCluster identifies the cluster (Name is omitted when names are hidden)
+type PublicCluster struct {
+	Name      string `json:"name,omitempty"`
+	Platform  string `json:"platform,omitempty"`
+	Version   string `json:"version,omitempty"`
+	Connected bool   `json:"connected"`
+}
+
+// PublicHealthCount counts pods by health
+type PublicHealthCount struct {
+	Healthy int `json:"healthy"`
+	Warning int `json:"warning"`
+	Error   int `json:"error"`
+}
+
+// PublicNamespace is the status of one namespace
+type PublicNamespace struct {
+	Name      string            `json:"name,omitempty"`
+	Status    string            `json:"status"`
+	Pods      PublicHealthCount `json:"pods"`
+	Workloads WorkloadCount     `json:"workloads"`
+	Problems  int               `json:"problems"`
+}
+
+// PublicProblem is a problem reduced to its kind, severity and reason
+type PublicProblem struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Severity  string `json:"severity"`
+	Reason    string `json:"reason"`
+}
+
+// publicSnapshotPublisher rebuilds the snapshot on an interval and publishes it
+type publicSnapshotPublisher struct {
+	cfg    PublicSnapshotConfig
+	mu     sync.RWMutex
+	data   []byte
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+func newPublicSnapshotPublisher(cfg PublicSnapshotConfig) *publicSnapshotPublisher {
+	return &publicSnapshotPublisher{cfg: cfg.withDefaults(), stopCh: make(chan struct{})}
+}
+
+func (p *publicSnapshotPublisher) start(s *Server) {
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		p.publish(s)
+
+		ticker := time.NewTicker(p.cfg.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.stopCh:
+				return
+			case <-ticker.C:
+				p.publish(s)
+			}
+		}
+	}()
+	log.Printf("Public snapshot enabled (every %s, serve=%t, file=%q, hideNames=%t)",
+		p.cfg.Interval, p.cfg.Serve, p.cfg.File, p.cfg.HideNames)
+}
+
+func (p *publicSnapshotPublisher) stop() {
+	close(p.stopCh)
+	p.wg.Wait()
+}
+
+func (p *publicSnapshotPublisher) publish(s *Server) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	data, err := json.MarshalIndent(s.buildPublicSnapshot(ctx, p.cfg), "", "  ")
+	if err != nil {
+		log.Printf("Public snapshot: %v", err)
+		return
+	}
+	p.mu.Lock()
+	p.data = data
+	p.mu.Unlock()
+
+	if p.cfg.File != "" {
+		if err := writeFileAtomic(p.cfg.File, data); err != nil {
+			log.Printf("Public snapshot: failed to write %s: %v", p.cfg.File, err)
+		}
+	}
+}
+
+// handlePublicSnapshot serves the latest snapshot. It sits outside /api, so it needs no
+// token, and allows any origin so wallboards hosted elsewhere can fetch it.
+func (s *Server) handlePublicSnapshot(w http.ResponseWriter, r *http.Request) {
+	s.publicSnapshot.mu.RLock()
+	data := s.publicSnapshot.data
+	s.publicSnapshot.mu.RUnlock()
+
+	if data == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "Snapshot not generated yet")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Write(data)
+}
+
+// buildPublicSnapshot summarizes pod and workload health per namespace from the cache
+func (s *Server) buildPublicSnapshot(ctx context.Context, cfg PublicSnapshotConfig) PublicSnapshot {
+	snap := PublicSnapshot{
+		GeneratedAt: time.Now().UTC(),
+		Status:      "unknown",
+		Namespaces:  make([]PublicNamespace, 0),
+		Problems:    make([]PublicProblem, 0),
+	}
+	if info, err := k8s.GetClusterInfo(ctx); err == nil {
+		snap.Cluster = PublicCluster{Platform: info.Platform, Version: info.KubernetesVersion, Connected: true}
+		if !cfg.HideNames {
+			snap.Cluster.Name = info.Cluster
+		}
+	}
+	cache := k8s.GetResourceCache()
+	if cache == nil {
+		return snap
+	}
+
+	included := func(string) bool { return true }
+	if len(cfg.Namespaces) > 0 {
+		selected := make(map[string]bool, len(cfg.Namespaces))
+		for _, ns := range cfg.Namespaces {
+			selected[ns] = true
+		}
+		included = func(ns string) bool { return selected[ns] }
+	}
+
+	namespaces := make(map[string]*PublicNamespace)
+	nsEntry := func(name string) *PublicNamespace {
+		if namespaces[name] == nil {
+			namespaces[name] = &PublicNamespace{Name: name}
+		}
+		return namespaces[name]
+	}
+	if nsList, err := cache.Namespaces().List(labels.Everything()); err == nil {
+		for _, ns := range nsList {
+			if included(ns.Name) {
+				nsEntry(ns.Name)
+			}
+		}
+	}
+
+	now := time.Now()
+	if pods, err := cache.Pods().List(labels.Everything()); err == nil {
+		for _, pod := range pods {
+			if !included(pod.Namespace) {
+				continue
+			}
+			entry := nsEntry(pod.Namespace)
+			switch classifyPodHealth(pod, now) {
+			case "healthy":
+				entry.Pods.Healthy++
+			case "warning":
+				entry.Pods.Warning++
+			case "error":
+				entry.Pods.Error++
+			}
+		}
+	}
+	countPublicWorkloads(cache, included, nsEntry)
+
+	var problems []DashboardProblem
+	for _, dp := range collectWorkloadProblems(cache, "", now) {
+		if dp.Namespace == "" || included(dp.Namespace) {
+			problems = append(problems, dp)
+		}
+		if dp.Namespace != "" && included(dp.Namespace) {
+			nsEntry(dp.Namespace).Problems++
+		}
+	}
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].Status != problems[j].Status {
+			return problems[i].Status == "error"
+		}
+		return problems[i].AgeSeconds < problems[j].AgeSeconds
+	})
+	for _, dp := range problems {
+		if len(snap.Problems) >= maxPublicSnapshotProblems {
+			break
+		}
+		p := PublicProblem{Kind: dp.Kind, Severity: dp.Status, Reason: dp.Reason}
+		if !cfg.HideNames {
+			p.Namespace, p.Name = dp.Namespace, dp.Name
+		}
+		snap.Problems = append(snap.Problems, p)
+	}
+
+	if nodes, err := cache.Nodes().List(labels.Everything()); err == nil {
+		for _, node := range nodes {
+			snap.Nodes.Total++
+			if nodeReady(node) {
+				snap.Nodes.Ready++
+			} else {
+				snap.Nodes.NotReady++
+			}
+		}
+	}
+
+	for _, entry := range namespaces {
+		entry.Status = publicStatus(entry.Pods, entry.Workloads.Unready > 0 || entry.Problems > 0)
+		snap.Pods.Healthy += entry.Pods.Healthy
+		snap.Pods.Warning += entry.Pods.Warning
+		snap.Pods.Error += entry.Pods.Error
+		snap.Workloads.Total += entry.Workloads.Total
+		snap.Workloads.Ready += entry.Workloads.Ready
+		snap.Workloads.Unready += entry.Workloads.Unready
+		snap.Namespaces = append(snap.Namespaces, *entry)
+	}
+	snap.Status = publicStatus(snap.Pods, snap.Workloads.Unready > 0 || snap.Nodes.NotReady > 0)
+	for _, p := range snap.Problems {
+		if p.Severity == "error" || p.Severity == "critical" {
+			snap.Status = "error"
+		}
+	}
+
+	rank := map[string]int{"error": 0, "warning": 1, "healthy": 2}
+	sort.SliceStable(snap.Namespaces, func(i, j int) bool {
+		a, b := snap.Namespaces[i], snap.Namespaces[j]
+		if a.Status != b.Status {
+			return rank[a.Status] < rank[b.Status]
+		}
+		return a.Name < b.Name
+	})
+	if cfg.HideNames {
+		for i := range snap.Namespaces {
+			snap.Namespaces[i].Name = ""
+		}
+	}
+	return snap
+}
+
+// countPublicWorkloads counts ready and unready Deployments, StatefulSets and DaemonSets
+// per namespace, skipping ones scaled to zero
+func countPublicWorkloads(cache *k8s.ResourceCache, included func(string) bool, nsEntry func(string) *PublicNamespace) {
+	add := func(namespace string, ready bool) {
+		if !included(namespace) {
+			return
+		}
+		w := &nsEntry(namespace).Workloads
+		w.Total++
+		if ready {
+			w.Ready++
+		} else {
+			w.Unready++
+		}
+	}
+	if deps, err := cache.Deployments().List(labels.Everything()); err == nil {
+		for _, d := range deps {
+			if d.Status.Replicas > 0 {
+				add(d.Namespace, d.Status.AvailableReplicas == d.Status.Replicas)
+			}
+		}
+	}
+	if ssets, err := cache.StatefulSets().List(labels.Everything()); err == nil {
+		for _, ss := range ssets {
+			if ss.Status.Replicas > 0 {
+				add(ss.Namespace, ss.Status.ReadyReplicas == ss.Status.Replicas)
+			}
+		}
+	}
+	if dsets, err := cache.DaemonSets().List(labels.Everything()); err == nil {
+		for _, ds := range dsets {
+			if ds.Status.DesiredNumberScheduled > 0 {
+				add(ds.Namespace, ds.Status.NumberUnavailable == 0)
+			}
+		}
+	}
+}
+
+// publicStatus is error if any pod errors, warning if any pod warns or degraded is set
+func publicStatus(pods PublicHealthCount, degraded bool) string {
+	switch {
+	case pods.Error > 0:
+		return "error"
+	case pods.Warning > 0 || degraded:
+		return "warning"
+	}
+	return "healthy"
+}
+
+func nodeReady(node *corev1.Node) bool {
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == corev1.NodeReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// writeFileAtomic writes data via a temp file so readers never see a partial snapshot
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
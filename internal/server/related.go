@@ -0,0 +1,33 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/topology"
+)
+
+// handleGetRelated returns a resource's related-resource graph: its owner chain, what it
+// owns transitively, the ConfigMaps, Secrets, PVCs and ServiceAccounts its pods use, the
+// Services, Ingresses, HPAs and PDBs targeting it, and recent events about all of it
+// GET /api/resources/{kind}/{namespace}/{name}/related
+func (s *Server) handleGetRelated(w http.ResponseWriter, r *http.Request) {
+	kind := normalizeKind(chi.URLParam(r, "kind"))
+	namespace := chi.URLParam(r, "namespace")
+	name := chi.URLParam(r, "name")
+	if group := r.URL.Query().Get("group"); group != "" {
+		kind = k8s.QualifiedKind(kind, group)
+	}
+	if namespace == "_" {
+		namespace = ""
+	}
+
+	rel, err := topology.NewBuilder().Related(r.Context(), kind, namespace, name)
+	if err != nil {
+		s.writeExplorerError(w, err)
+		return
+	}
+	s.writeJSON(w, filterRelatedForUser(r.Context(), rel))
+}
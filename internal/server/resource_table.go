@@ -0,0 +1,28 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// handleResourceTable lists custom resources with the columns their CRD declares for
+// kubectl get (additionalPrinterColumns), evaluated server-side
+// GET /api/resources/{kind}/table?namespace=&group=
+func (s *Server) handleResourceTable(w http.ResponseWriter, r *http.Request) {
+	cache := k8s.GetResourceCache()
+	if cache == nil {
+		s.writeExplorerError(w, explorerErrors.CacheNotInitialized())
+		return
+	}
+	q := r.URL.Query()
+	table, err := cache.ResourceTable(r.Context(), normalizeKind(chi.URLParam(r, "kind")), q.Get("group"), q.Get("namespace"))
+	if err != nil {
+		s.writeExplorerError(w, err)
+		return
+	}
+	s.writeJSON(w, table)
+}
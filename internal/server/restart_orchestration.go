@@ -0,0 +1,248 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/skyhook-io/radar/internal/auth"
+	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/restart"
+)
+
+// maxRestartTargets caps how many workloads one orchestrated restart touches
+const maxRestartTargets = 50
+
+// OrchestratedRestartRequest is the body for an orchestrated restart. Workloads are the
+// listed targets plus, when set, every workload whose pod template references the
+// ConfigMap or Secret (given as namespace/name).
+type OrchestratedRestartRequest struct {
+	Targets            []restart.Target `json:"targets"`
+	ConfigMap          string           `json:"configMap,omitempty"`
+	Secret             string           `json:"secret,omitempty"`
+	StepTimeoutSeconds int              `json:"stepTimeoutSeconds,omitempty"`
+	DryRun             bool             `json:"dryRun,omitempty"` // Return the plan without restarting
+}
+
+// OrchestratedRestartPlan is the dry-run response
+type OrchestratedRestartPlan struct {
+	Steps [][]restart.Target `json:"steps"`
+}
+
+// handleOrchestratedRestart restarts related workloads in dependency order, streaming
+// progress as SSE events (one per restart.Event, named by its type)
+// POST /api/workloads/restart
+func (s *Server) handleOrchestratedRestart(w http.ResponseWriter, r *http.Request) {
+	var req OrchestratedRestartRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.StepTimeoutSeconds < 0 {
+		s.writeError(w, http.StatusBadRequest, "stepTimeoutSeconds must not be negative")
+		return
+	}
+	cache := k8s.GetResourceCache()
+	if cache == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "Resource cache not available")
+		return
+	}
+
+	targets, err := restartTargets(cache, req)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(targets) == 0 {
+		s.writeError(w, http.StatusBadRequest, "no workloads to restart")
+		return
+	}
+	if len(targets) > maxRestartTargets {
+		s.writeError(w, http.StatusBadRequest, fmt.Sprintf("too many workloads (%d, max %d)", len(targets), maxRestartTargets))
+		return
+	}
+
+	deps := make(map[restart.Target][]restart.Target, len(targets))
+	for _, t := range targets {
+		if err := auth.CheckNamespace(r.Context(), t.Namespace); err != nil {
+			s.writeError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		obj, err := cache.CachedObject(r.Context(), t.Kind, t.Namespace, t.Name)
+		if err != nil {
+			s.writeExplorerError(w, err)
+			return
+		}
+		if value := obj.GetAnnotations()[restart.DependsOnAnnotation]; value != "" {
+			if deps[t], err = restart.ParseDependsOn(value, t.Namespace); err != nil {
+				s.writeError(w, http.StatusBadRequest, fmt.Sprintf("%s: %v", t, err))
+				return
+			}
+		}
+		if err := checkUserAccess(r.Context(), k8s.PermissionCheck{Verb: "patch", Kind: t.Kind, Namespace: t.Namespace, Name: t.Name}); err != nil {
+			s.writeError(w, http.StatusForbidden, err.Error())
+			return
+		}
+	}
+	steps, err := restart.Plan(targets, deps)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.DryRun {
+		s.writeJSON(w, OrchestratedRestartPlan{Steps: steps})
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		s.writeError(w, http.StatusInternalServerError, "Streaming not supported")
+		return
+	}
+
+	emit := func(e restart.Event) error {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	}
+	opts := restart.Options{StepTimeout: time.Duration(req.StepTimeoutSeconds) * time.Second}
+	if err := restart.Run(r.Context(), &restartWorkloads{r: r, cache: cache}, steps, opts, emit); err != nil {
+		log.Printf("[restart] Orchestrated restart of %d workloads halted: %v", len(targets), err)
+	}
+}
+
+// restartTargets resolves the request to a deduplicated list of targets
+func restartTargets(cache *k8s.ResourceCache, req OrchestratedRestartRequest) ([]restart.Target, error) {
+	var targets []restart.Target
+	seen := make(map[restart.Target]bool)
+	add := func(t restart.Target) {
+		if !seen[t] {
+			seen[t] = true
+			targets = append(targets, t)
+		}
+	}
+	for _, t := range req.Targets {
+		kind := restart.NormalizeKind(t.Kind)
+		if kind == "" {
+			return nil, fmt.Errorf("%s can't be restarted (use Deployment, StatefulSet or DaemonSet)", t.Kind)
+		}
+		if t.Namespace == "" || t.Name == "" {
+			return nil, fmt.Errorf("targets need a namespace and name")
+		}
+		add(restart.Target{Kind: kind, Namespace: t.Namespace, Name: t.Name})
+	}
+
+	for _, ref := range []struct{ kind, value string }{{"configMap", req.ConfigMap}, {"secret", req.Secret}} {
+		if ref.value == "" {
+			continue
+		}
+		namespace, name, ok := strings.Cut(ref.value, "/")
+		if !ok || namespace == "" || name == "" {
+			return nil, fmt.Errorf("%s must be namespace/name", ref.kind)
+		}
+		matches := func(spec corev1.PodSpec) bool { return referencesConfig(spec, ref.kind, name) }
+		if deployments, err := cache.Deployments().Deployments(namespace).List(labels.Everything()); err == nil {
+			for _, d := range deployments {
+				if matches(d.Spec.Template.Spec) {
+					add(restart.Target{Kind: "Deployment", Namespace: namespace, Name: d.Name})
+				}
+			}
+		}
+		if statefulSets, err := cache.StatefulSets().StatefulSets(namespace).List(labels.Everything()); err == nil {
+			for _, sts := range statefulSets {
+				if matches(sts.Spec.Template.Spec) {
+					add(restart.Target{Kind: "StatefulSet", Namespace: namespace, Name: sts.Name})
+				}
+			}
+		}
+		if daemonSets, err := cache.DaemonSets().DaemonSets(namespace).List(labels.Everything()); err == nil {
+			for _, ds := range daemonSets {
+				if matches(ds.Spec.Template.Spec) {
+					add(restart.Target{Kind: "DaemonSet", Namespace: namespace, Name: ds.Name})
+				}
+			}
+		}
+	}
+	return targets, nil
+}
+
+// referencesConfig reports whether a pod spec reads a ConfigMap or Secret ("configMap" or
+// "secret") through env, envFrom or a volume
+func referencesConfig(spec corev1.PodSpec, kind, name string) bool {
+	configMap, secret := kind == "configMap", kind == "secret"
+	for _, c := range append(append([]corev1.Container(nil), spec.InitContainers...), spec.Containers...) {
+		for _, env := range c.Env {
+			if from := env.ValueFrom; from != nil {
+				if configMap && from.ConfigMapKeyRef != nil && from.ConfigMapKeyRef.Name == name ||
+					secret && from.SecretKeyRef != nil && from.SecretKeyRef.Name == name {
+					return true
+				}
+			}
+		}
+		for _, from := range c.EnvFrom {
+			if configMap && from.ConfigMapRef != nil && from.ConfigMapRef.Name == name ||
+				secret && from.SecretRef != nil && from.SecretRef.Name == name {
+				return true
+			}
+		}
+	}
+	for _, v := range spec.Volumes {
+		if configMap && v.ConfigMap != nil && v.ConfigMap.Name == name ||
+			secret && v.Secret != nil && v.Secret.SecretName == name {
+			return true
+		}
+		if v.Projected != nil {
+			for _, src := range v.Projected.Sources {
+				if configMap && src.ConfigMap != nil && src.ConfigMap.Name == name ||
+					secret && src.Secret != nil && src.Secret.Name == name {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// restartWorkloads restarts workloads through the API and reads rollout status from the cache
+type restartWorkloads struct {
+	r     *http.Request // For auditing
+	cache *k8s.ResourceCache
+}
+
+func (rw *restartWorkloads) Restart(ctx context.Context, t restart.Target) (int64, error) {
+	generation, err := k8s.RestartWorkload(ctx, t.Kind, t.Namespace, t.Name)
+	if err != nil {
+		return 0, err
+	}
+	auditActionDetail(rw.r, "restart", t.Kind, t.Namespace, t.Name, "orchestrated restart")
+	return generation, nil
+}
+
+func (rw *restartWorkloads) Status(t restart.Target, generation int64) (restart.RolloutStatus, error) {
+	obj, err := rw.cache.CachedObject(context.Background(), t.Kind, t.Namespace, t.Name)
+	if err != nil {
+		return restart.RolloutStatus{}, err
+	}
+	progress, ok := k8s.WorkloadRolloutProgress(obj, generation)
+	if !ok {
+		return restart.RolloutStatus{}, fmt.Errorf("%s: unexpected object in cache", t)
+	}
+	return restart.RolloutStatus{Done: progress.Done, Failed: progress.Failed, Message: progress.Message}, nil
+}
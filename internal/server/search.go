@@ -0,0 +1,59 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/search"
+)
+
+// searchDefaultLimit and searchMaxLimit bound the results returned
+const (
+	searchDefaultLimit = 50
+	searchMaxLimit     = 500
+)
+
+// SearchResponse is the ranked result of a search
+type SearchResponse struct {
+	Query   search.Query    `json:"query"`
+	Results []search.Result `json:"results"`
+	Total   int             `json:"total"` // Matches before the limit
+}
+
+// handleSearch searches every cached resource by name, label, annotation, image and kind
+// GET /api/search?q=&namespace=&limit=
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	query := search.ParseQuery(r.URL.Query().Get("q"))
+	for _, ns := range strings.Split(r.URL.Query().Get("namespace"), ",") {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			query.Namespaces = append(query.Namespaces, strings.ToLower(ns))
+		}
+	}
+	if len(query.Terms) == 0 && len(query.Kinds) == 0 {
+		s.writeError(w, http.StatusBadRequest, "q must contain a search term or kind:")
+		return
+	}
+	limit := searchDefaultLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			limit = min(n, searchMaxLimit)
+		}
+	}
+
+	cache := k8s.GetResourceCache()
+	if cache == nil {
+		s.writeExplorerError(w, explorerErrors.CacheNotInitialized())
+		return
+	}
+	results := search.Search(search.Collect(cache, k8s.GetDynamicResourceCache()), query)
+	results = filterSearchResultsForUser(r.Context(), results)
+
+	resp := SearchResponse{Query: query, Results: results, Total: len(results)}
+	if len(results) > limit {
+		resp.Results = results[:limit]
+	}
+	s.writeJSON(w, resp)
+}
@@ -0,0 +1,160 @@
+package server
+
+import (
+	"encoding/base64"
+	"net/http"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-chi/chi/v5"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// SecretDetail describes a Secret without its values
+type SecretDetail struct {
+	Namespace       string      `json:"namespace"`
+	Name            string      `json:"name"`
+	Type            string      `json:"type"`
+	CreatedAt       time.Time   `json:"createdAt"`
+	Immutable       bool        `json:"immutable,omitempty"`
+	Keys            []SecretKey `json:"keys"`
+	ValuesAvailable bool        `json:"valuesAvailable"` // False in metadata-only mode: keys and values aren't loaded
+}
+
+// SecretKey is one key of a Secret and the size of its decoded value
+type SecretKey struct {
+	Key  string `json:"key"`
+	Size int    `json:"size"` // Bytes
+}
+
+// SecretValue is a single revealed Secret key. Values that aren't valid UTF-8 (keystores,
+// certificates in DER form) are returned base64-encoded.
+type SecretValue struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Encoding string `json:"encoding"` // "text" or "base64"
+}
+
+// getCachedSecret returns a Secret from the cache, or an error when Radar doesn't watch
+// secrets (RBAC or --secrets=off)
+func getCachedSecret(namespace, name string) (*corev1.Secret, error) {
+	cache := k8s.GetResourceCache()
+	if cache == nil {
+		return nil, explorerErrors.New(explorerErrors.ErrCacheNotInitialized, "resource cache not initialized")
+	}
+	lister := cache.Secrets()
+	if lister == nil {
+		return nil, explorerErrors.New(explorerErrors.ErrK8sForbidden, "secrets access not available (RBAC not granted)")
+	}
+	return lister.Secrets(namespace).Get(name)
+}
+
+// handleGetSecret returns a Secret's keys and the size of each value, never the values
+// GET /api/secrets/{namespace}/{name}
+func (s *Server) handleGetSecret(w http.ResponseWriter, r *http.Request) {
+	namespace := chi.URLParam(r, "namespace")
+	name := chi.URLParam(r, "name")
+
+	secret, err := getCachedSecret(namespace, name)
+	if err != nil {
+		s.writeExplorerError(w, err)
+		return
+	}
+	s.writeJSON(w, secretDetail(secret, !k8s.GetResourceCache().SecretsMetadataOnly()))
+}
+
+func secretDetail(secret *corev1.Secret, valuesAvailable bool) SecretDetail {
+	detail := SecretDetail{
+		Namespace:       secret.Namespace,
+		Name:            secret.Name,
+		Type:            string(secret.Type),
+		CreatedAt:       secret.CreationTimestamp.Time,
+		Immutable:       secret.Immutable != nil && *secret.Immutable,
+		Keys:            []SecretKey{},
+		ValuesAvailable: valuesAvailable,
+	}
+	for key, value := range secret.Data {
+		detail.Keys = append(detail.Keys, SecretKey{Key: key, Size: len(value)})
+	}
+	sort.Slice(detail.Keys, func(i, j int) bool { return detail.Keys[i].Key < detail.Keys[j].Key })
+	return detail
+}
+
+// handleRevealSecretKey returns the decoded value of one Secret key and records the reveal
+// on the timeline. It's a POST so read-only API tokens can't reveal values.
+// POST /api/secrets/{namespace}/{name}/keys/{key}/reveal
+func (s *Server) handleRevealSecretKey(w http.ResponseWriter, r *http.Request) {
+	namespace := chi.URLParam(r, "namespace")
+	name := chi.URLParam(r, "name")
+	key := chi.URLParam(r, "key")
+
+	if k8s.GetResourceCache().SecretsMetadataOnly() {
+		s.writeExplorerError(w, explorerErrors.New(explorerErrors.ErrForbidden,
+			"secret values aren't loaded: Radar watches secrets in metadata-only mode (--secrets=full)"))
+		return
+	}
+	secret, err := getCachedSecret(namespace, name)
+	if err != nil {
+		s.writeExplorerError(w, err)
+		return
+	}
+	value, ok := secret.Data[key]
+	if !ok {
+		s.writeExplorerError(w, explorerErrors.New(explorerErrors.ErrNotFound, "secret "+namespace+"/"+name+" has no key "+key))
+		return
+	}
+
+	auditActionDetail(r, "reveal", "Secret", namespace, name, "key "+key)
+	s.writeJSON(w, secretValue(key, value))
+}
+
+// redactSecrets blanks Secret values in a cached resource or list of them, keeping the
+// keys, so handleRevealSecretKey is the only way to read a value. The cache's objects are
+// copied, not modified; other resources are returned as they are.
+func redactSecrets(resource any) any {
+	switch r := resource.(type) {
+	case *corev1.Secret:
+		return redactSecret(r)
+	case []*corev1.Secret:
+		out := make([]*corev1.Secret, len(r))
+		for i, secret := range r {
+			out[i] = redactSecret(secret)
+		}
+		return out
+	case *unstructured.Unstructured:
+		if r.GetKind() == "Secret" && r.GetAPIVersion() == "v1" {
+			r = r.DeepCopy()
+			k8s.RedactSecretData(r)
+		}
+		return r
+	case []*unstructured.Unstructured:
+		out := make([]*unstructured.Unstructured, len(r))
+		for i, u := range r {
+			out[i] = redactSecrets(u).(*unstructured.Unstructured)
+		}
+		return out
+	}
+	return resource
+}
+
+func redactSecret(secret *corev1.Secret) *corev1.Secret {
+	secret = secret.DeepCopy()
+	for k := range secret.Data {
+		secret.Data[k] = []byte{}
+	}
+	secret.StringData = nil
+	delete(secret.Annotations, "kubectl.kubernetes.io/last-applied-configuration")
+	return secret
+}
+
+func secretValue(key string, value []byte) SecretValue {
+	if utf8.Valid(value) {
+		return SecretValue{Key: key, Value: string(value), Encoding: "text"}
+	}
+	return SecretValue{Key: key, Value: base64.StdEncoding.EncodeToString(value), Encoding: "base64"}
+}
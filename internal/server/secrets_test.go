@@ -0,0 +1,63 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/skyhook-io/radar/internal/testenv"
+)
+
+func TestResourceEndpointsRedactSecrets(t *testing.T) {
+	testenv.Setup(t, testenv.Namespace("shop"), &corev1.Secret{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "db", UID: testenv.UID("Secret", "shop", "db")},
+		Data:       map[string][]byte{"password": []byte("hunter2")},
+	})
+	srv := httptest.NewServer(New(Config{}).router)
+	defer srv.Close()
+
+	get := func(path string, out any) {
+		t.Helper()
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("GET %s: %s", path, resp.Status)
+		}
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+	}
+
+	var detail struct {
+		Resource corev1.Secret `json:"resource"`
+	}
+	get("/api/resources/secrets/shop/db", &detail)
+	if v, ok := detail.Resource.Data["password"]; !ok || len(v) != 0 {
+		t.Errorf("get: data = %q, want the key with a blank value", detail.Resource.Data)
+	}
+
+	var list []corev1.Secret
+	get("/api/resources/secrets?namespace=shop", &list)
+	if len(list) != 1 || len(list[0].Data["password"]) != 0 {
+		t.Errorf("list = %+v, want one Secret with blank values", list)
+	}
+
+	// The per-key reveal still reads the cached value
+	resp, err := http.Post(srv.URL+"/api/secrets/shop/db/keys/password/reveal", "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var value SecretValue
+	if err := json.NewDecoder(resp.Body).Decode(&value); err != nil || value.Value != "hunter2" {
+		t.Errorf("reveal = %+v, %v, want hunter2", value, err)
+	}
+}
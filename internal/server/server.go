@@ -1,6 +1,8 @@
 package server
 
 import (
+	"bytes"
+	"context"
 	"embed"
 	"encoding/json"
 	"errors"
@@ -10,7 +12,10 @@ import (
 	"log"
 	"net/http"
 	"net/url"
+	"slices"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -21,22 +26,49 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 
+	"github.com/skyhook-io/radar/internal/auth"
+	"github.com/skyhook-io/radar/internal/cost"
 	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+	"github.com/skyhook-io/radar/internal/execaudit"
 	"github.com/skyhook-io/radar/internal/helm"
+	"github.com/skyhook-io/radar/internal/hygiene"
+	"github.com/skyhook-io/radar/internal/images"
 	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/logs"
+	"github.com/skyhook-io/radar/internal/notifications"
+	"github.com/skyhook-io/radar/internal/objectstore"
+	"github.com/skyhook-io/radar/internal/policy"
+	"github.com/skyhook-io/radar/internal/replay"
+	"github.com/skyhook-io/radar/internal/rightsizing"
+	"github.com/skyhook-io/radar/internal/scheduling"
+	"github.com/skyhook-io/radar/internal/signatures"
 	"github.com/skyhook-io/radar/internal/timeline"
 	"github.com/skyhook-io/radar/internal/topology"
 )
 
 // Server is the Explorer HTTP server
 type Server struct {
-	router      *chi.Mux
-	broadcaster *SSEBroadcaster
-	port        int
-	devMode     bool
-	staticFS    fs.FS
+	router          *chi.Mux
+	broadcaster     *SSEBroadcaster
+	port            int
+	devMode         bool
+	staticFS        fs.FS
+	nodeShell       NodeShellConfig
+	debug           DebugConfig
+	requireAPIToken bool
+	authenticators  []auth.Authenticator
+	tls             TLSConfig
+	publicSnapshot  *publicSnapshotPublisher // nil when disabled
+	fileTransferMax int64
+	scaleHPAPolicy  string
+
+	httpServer  *http.Server
+	draining    atomic.Bool
+	streamsCtx  context.Context // Cancelled at shutdown to end SSE streams and terminals
+	stopStreams context.CancelFunc
 }
 
 // Config holds server configuration
@@ -45,15 +77,39 @@ type Config struct {
 	DevMode    bool     // Serve frontend from filesystem instead of embedded
 	StaticFS   embed.FS // Embedded frontend files
 	StaticRoot string   // Path within StaticFS
+	NodeShell  NodeShellConfig
+	Debug      DebugConfig
+	// RequireAPIToken rejects API requests without a token unless they come from loopback
+	RequireAPIToken bool
+	// Authenticators establish the user of requests without a token (e.g. proxy headers)
+	Authenticators []auth.Authenticator
+	TLS            TLSConfig
+	PublicSnapshot PublicSnapshotConfig
+	// FileTransferMaxBytes caps pod file downloads and uploads (0 = unlimited)
+	FileTransferMaxBytes int64
+	// ScaleHPAPolicy is k8s.ScaleHPAPolicyWarn or ScaleHPAPolicyRefuse (default warn)
+	ScaleHPAPolicy string
 }
 
 // New creates a new server instance
 func New(cfg Config) *Server {
 	s := &Server{
-		router:      chi.NewRouter(),
-		broadcaster: NewSSEBroadcaster(),
-		port:        cfg.Port,
-		devMode:     cfg.DevMode,
+		router:          chi.NewRouter(),
+		broadcaster:     NewSSEBroadcaster(),
+		port:            cfg.Port,
+		devMode:         cfg.DevMode,
+		nodeShell:       cfg.NodeShell.withDefaults(),
+		debug:           cfg.Debug.withDefaults(),
+		requireAPIToken: cfg.RequireAPIToken,
+		authenticators:  cfg.Authenticators,
+		tls:             cfg.TLS,
+		fileTransferMax: cfg.FileTransferMaxBytes,
+		scaleHPAPolicy:  cfg.ScaleHPAPolicy,
+	}
+	s.httpServer = &http.Server{Addr: fmt.Sprintf(":%d", cfg.Port), Handler: s.router}
+	s.streamsCtx, s.stopStreams = context.WithCancel(context.Background())
+	if cfg.PublicSnapshot.Enabled() {
+		s.publicSnapshot = newPublicSnapshotPublisher(cfg.PublicSnapshot)
 	}
 
 	// Set up static file system
@@ -68,51 +124,165 @@ func New(cfg Config) *Server {
 	return s
 }
 
+// apiRequestTimeout bounds ordinary requests (see requestTimeout)
+var apiRequestTimeout = 60 * time.Second
+
+// untimedRoutes are API routes that take as long as their work does, so requestTimeout
+// leaves them alone. Streams are recognized by their request (see isStreamRequest).
+var untimedRoutes = map[string]bool{
+	"/api/pods/{namespace}/{name}/files/download": true,
+	"/api/pods/{namespace}/{name}/files/upload":   true,
+	"/api/nodes/{name}/drain":                     true, // Streams progress for up to its own timeout
+	"/api/workloads/restart":                      true, // Waits up to the step timeout for each rollout
+	// Terminals: WebSockets that last as long as the session
+	"/api/pods/{namespace}/{name}/exec":  true,
+	"/api/pods/{namespace}/{name}/debug": true,
+	"/api/nodes/{name}/shell":            true,
+	"/api/exec/sessions/{id}/attach":     true,
+	"/api/exec/shared/{token}":           true,
+	// Followed logs, as SSE or WebSocket, also for clients that don't send those headers
+	"/api/logs/{kind}/{namespace}/{name}":      true,
+	"/api/pods/{namespace}/{name}/logs/stream": true,
+}
+
+// requestTimeout is middleware.Timeout, except for streams (WebSocket and SSE), which
+// last as long as the client stays connected, and untimedRoutes. routes resolves the route
+// pattern, which isn't known yet when the middleware runs.
+func requestTimeout(routes chi.Routes, d time.Duration) func(http.Handler) http.Handler {
+	timeout := middleware.Timeout(d)
+	return func(next http.Handler) http.Handler {
+		limited := timeout(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isStreamRequest(r) || untimedRoutes[routes.Find(chi.NewRouteContext(), r.Method, r.URL.Path)] {
+				next.ServeHTTP(w, r)
+				return
+			}
+			limited.ServeHTTP(w, r)
+		})
+	}
+}
+
+// isStreamRequest reports whether a request opens a WebSocket or an SSE stream
+func isStreamRequest(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket") ||
+		strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
 func (s *Server) setupRoutes() {
 	r := s.router
 
 	// Middleware
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
-	r.Use(middleware.Timeout(60 * time.Second))
+	r.Use(s.drainMiddleware)
+	r.Use(explorerErrors.CorrelationMiddleware)
+	r.Use(requestTimeout(s.router, apiRequestTimeout))
 
 	// CORS for development
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"http://localhost:*", "http://127.0.0.1:*"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Content-Type"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", explorerErrors.CorrelationHeader, sessionTokenHeader},
+		ExposedHeaders:   []string{explorerErrors.CorrelationHeader, "Content-Disposition", estimatedSizeHeader},
 		AllowCredentials: true,
 	}))
 
 	// API routes
 	r.Route("/api", func(r chi.Router) {
+		// Scoped API tokens (Authorization: Bearer) for scripts and CI
+		r.Use(auth.Middleware(s.router, s.requireAPIToken, s.authenticators...))
+		// Requests acting for a Kubernetes user are limited to that user's RBAC
+		r.Use(userAccessMiddleware(s.router))
+
 		r.Get("/health", s.handleHealth)
 		r.Get("/dashboard", s.handleDashboard)
+		r.Get("/dashboard/stream", s.handleDashboardStream)
+		r.Get("/problems", s.handleProblems)
+		r.Post("/problems/{id}/snooze", s.handleSnoozeProblem)
+		r.Delete("/problems/{id}/snooze", s.handleUnsnoozeProblem)
+		r.Get("/insights/forecasts", s.handleInsightsForecasts)
+		r.Get("/storage/pvcs", s.handleListPVCUsage)
+		r.Post("/storage/pvcs/{namespace}/{name}/expand", s.handleExpandPVC)
+		r.Get("/insights/incidents", s.handleInsightsIncidents)
+		r.Get("/insights/changes", s.handleInsightsChanges)
 		r.Get("/cluster-info", s.handleClusterInfo)
 		r.Get("/capabilities", s.handleCapabilities)
+		r.Post("/permissions/check", s.handleCheckPermissions)
+		r.Get("/watch-namespaces", s.handleGetWatchNamespaces)
+		r.Put("/watch-namespaces", s.handleSetWatchNamespaces)
 		r.Get("/topology", s.handleTopology)
 		r.Get("/namespaces", s.handleNamespaces)
 		r.Get("/api-resources", s.handleAPIResources)
 		r.Get("/resources/{kind}", s.handleListResources)
+		r.Get("/resources/{kind}/stream", s.handleResourceListStream)
+		r.Get("/resources/{kind}/table", s.handleResourceTable)
 		r.Get("/resources/{kind}/{namespace}/{name}", s.handleGetResource)
+		r.Get("/resources/{kind}/{namespace}/{name}/related", s.handleGetRelated)
 		r.Put("/resources/{kind}/{namespace}/{name}", s.handleUpdateResource)
+		r.Post("/resources/{kind}/{namespace}/{name}/dry-run", s.handlePreviewUpdateResource)
 		r.Delete("/resources/{kind}/{namespace}/{name}", s.handleDeleteResource)
+		r.Get("/search", s.handleSearch)
+		r.Get("/secrets/{namespace}/{name}", s.handleGetSecret)
+		r.Post("/secrets/{namespace}/{name}/keys/{key}/reveal", s.handleRevealSecretKey)
 		r.Get("/events", s.handleEvents)
 		r.Get("/events/stream", s.broadcaster.HandleSSE)
 		r.Get("/changes", s.handleChanges)
+		r.Get("/changes/export", s.handleChangesExport)
+		r.Post("/changes/export", s.handleChangesExportStore)
+		r.Get("/changes/histogram", s.handleChangesHistogram)
+		r.Get("/changes/annotations", s.handleListAnnotations)
+		r.Post("/changes/annotations", s.handleCreateAnnotation)
+		r.Get("/changes/incidents", s.handleCorrelatedIncidents)
+		r.Get("/changes/incidents/{id}", s.handleCorrelatedIncident)
 		r.Get("/changes/{kind}/{namespace}/{name}/children", s.handleChangeChildren)
 
 		// Pod logs
 		r.Get("/pods/{namespace}/{name}/logs", s.handlePodLogs)
 		r.Get("/pods/{namespace}/{name}/logs/stream", s.handlePodLogsStream)
 
+		// Merged log streams (all containers of a pod or all pods of a workload; SSE or WebSocket)
+		logHandlers := logs.NewHandlers()
+		logHandlers.RegisterRoutes(r)
+
 		// Pod exec (terminal)
 		r.Get("/pods/{namespace}/{name}/exec", s.handlePodExec)
+		// Debug terminal in an ephemeral container, for images without a shell
+		r.Get("/pods/{namespace}/{name}/debug", s.handlePodDebug)
+
+		// Pod file browser (list, download as file or tar.gz, upload file or tar)
+		r.Get("/pods/{namespace}/{name}/files", s.handleListPodFiles)
+		r.Get("/pods/{namespace}/{name}/files/download", s.handleDownloadPodFiles)
+		r.Post("/pods/{namespace}/{name}/files/upload", s.handleUploadPodFiles)
+		r.Post("/pods/bulk", s.handleBulkPods)
+
+		// Terminal owner reconnecting after a dropped connection
+		r.Get("/exec/sessions/{id}/attach", s.handleReattachExecSession)
+
+		// Terminal sharing (owner-managed links, observers and co-drivers)
+		r.Post("/exec/sessions/{id}/share", s.handleCreateShareLink)
+		r.Delete("/exec/sessions/{id}/share/{token}", s.handleRevokeShareLink)
+		r.Get("/exec/sessions/{id}/participants", s.handleListShareParticipants)
+		r.Put("/exec/sessions/{id}/participants/{participant}", s.handleSetParticipantRole)
+		r.Delete("/exec/sessions/{id}/participants/{participant}", s.handleKickParticipant)
+		r.Get("/exec/shared/{token}", s.handleJoinSharedSession)
+
+		// Node capacity and health
+		r.Get("/nodes", s.handleListNodeDetails)
+		r.Get("/nodes/{name}", s.handleGetNodeDetail)
+
+		// Node shell (privileged debug pod, requires --enable-node-shell)
+		r.Get("/nodes/{name}/shell", s.handleNodeShell)
+
+		// Node maintenance (drain streams SSE progress)
+		r.Post("/nodes/{name}/cordon", s.handleCordonNode)
+		r.Post("/nodes/{name}/uncordon", s.handleUncordonNode)
+		r.Post("/nodes/{name}/drain", s.handleDrainNode)
 
 		// Metrics (from metrics.k8s.io API)
 		r.Get("/metrics/pods/{namespace}/{name}", s.handlePodMetrics)
 		r.Get("/metrics/nodes/{name}", s.handleNodeMetrics)
 		r.Get("/metrics/pods/{namespace}/{name}/history", s.handlePodMetricsHistory)
+		r.Get("/metrics/pods/{namespace}/{name}/live", s.handlePodMetricsLive)
 		r.Get("/metrics/nodes/{name}/history", s.handleNodeMetricsHistory)
 
 		// Port forwarding
@@ -120,6 +290,11 @@ func (s *Server) setupRoutes() {
 		r.Post("/portforwards", s.handleStartPortForward)
 		r.Delete("/portforwards/{id}", s.handleStopPortForward)
 		r.Get("/portforwards/available/{type}/{namespace}/{name}", s.handleGetAvailablePorts)
+		r.Get("/portforward-profiles", s.handleListPortForwardProfiles)
+		r.Put("/portforward-profiles/{name}", s.handleSavePortForwardProfile)
+		r.Delete("/portforward-profiles/{name}", s.handleDeletePortForwardProfile)
+		r.Post("/portforward-profiles/{name}/start", s.handleStartPortForwardProfile)
+		r.Post("/portforward-profiles/{name}/stop", s.handleStopPortForwardProfile)
 
 		// Active sessions (for context switch confirmation)
 		r.Get("/sessions", s.handleGetSessions)
@@ -128,17 +303,77 @@ func (s *Server) setupRoutes() {
 		r.Post("/cronjobs/{namespace}/{name}/trigger", s.handleTriggerCronJob)
 		r.Post("/cronjobs/{namespace}/{name}/suspend", s.handleSuspendCronJob)
 		r.Post("/cronjobs/{namespace}/{name}/resume", s.handleResumeCronJob)
+		r.Post("/jobs/{namespace}/{name}/rerun", s.handleRerunJob)
 
 		// Workload restart
 		r.Post("/workloads/{kind}/{namespace}/{name}/restart", s.handleRestartWorkload)
+		r.Post("/workloads/restart", s.handleOrchestratedRestart)
+		r.Post("/workloads/{kind}/{namespace}/{name}/scale", s.handleScaleWorkload)
+
+		// Argo CD Application actions
+		r.Post("/argocd/applications/{namespace}/{name}/sync", s.handleSyncArgoApplication)
+		r.Post("/argocd/applications/{namespace}/{name}/refresh", s.handleRefreshArgoApplication)
+		r.Put("/argocd/applications/{namespace}/{name}/auto-sync", s.handleSetArgoAutoSync)
+
+		// Argo Rollouts actions
+		r.Post("/rollouts/{namespace}/{name}/{action}", s.handleRolloutAction)
+
+		// Image rollouts
+		r.Post("/image-rollouts", s.handleImageRollout)
+		r.Get("/image-rollouts", s.handleListImageRollouts)
+		r.Get("/image-rollouts/{id}", s.handleGetImageRollout)
 
 		// Helm routes
 		helmHandlers := helm.NewHandlers()
 		helmHandlers.RegisterRoutes(r)
+		r.Get("/helm/releases/{namespace}/{name}/topology", s.handleHelmReleaseTopology)
+
+		// Notification routes (channel listing, test-fire)
+		notificationHandlers := notifications.NewHandlers()
+		notificationHandlers.RegisterRoutes(r)
+
+		// Hygiene score routes (current score, trend history)
+		hygieneHandlers := hygiene.NewHandlers()
+		hygieneHandlers.RegisterRoutes(r)
+
+		// Resource policy routes (rules, violations)
+		policyHandlers := policy.NewHandlers()
+		policyHandlers.RegisterRoutes(r)
+
+		// Cost estimates (node pricing, namespace and workload attribution)
+		costHandlers := cost.NewHandlers()
+		costHandlers.RegisterRoutes(r)
+
+		// Resource recommendations (container requests vs metrics history)
+		rightsizingHandlers := rightsizing.NewHandlers()
+		rightsizingHandlers.RegisterRoutes(r)
+
+		// Image metadata (registry digests, build age, vulnerability counts)
+		imageHandlers := images.NewHandlers()
+		imageHandlers.RegisterRoutes(r)
+
+		// Scheduling analysis (why a pending pod fits no node)
+		schedulingHandlers := scheduling.NewHandlers()
+		schedulingHandlers.RegisterRoutes(r)
+
+		// Known problem signatures (built-in and user-defined root causes)
+		signatureHandlers := signatures.NewHandlers()
+		signatureHandlers.RegisterRoutes(r)
+
+		// Replay routes (playback control, bundle export)
+		replayHandlers := replay.NewHandlers()
+		replayHandlers.RegisterRoutes(r)
+
+		// API token management (browser session only; tokens can't manage tokens)
+		authHandlers := auth.NewHandlers()
+		authHandlers.RegisterRoutes(r)
 
 		// Debug routes (for event pipeline diagnostics)
 		r.Get("/debug/events", s.handleDebugEvents)
 		r.Get("/debug/events/diagnose", s.handleDebugEventsDiagnose)
+		r.Get("/debug/features", s.handleDebugFeatures)
+		r.Get("/debug/startup", s.handleDebugStartup)
+		r.Get("/debug/watch/stream", s.handleDebugWatchStream)
 
 		// Traffic routes
 		r.Get("/traffic/sources", s.handleGetTrafficSources)
@@ -154,6 +389,11 @@ func (s *Server) setupRoutes() {
 		r.Post("/contexts/{name}", s.handleSwitchContext)
 	})
 
+	// Sanitized read-only snapshot for wallboards (outside /api: no token required)
+	if s.publicSnapshot != nil && s.publicSnapshot.cfg.Serve {
+		r.Get("/public/snapshot.json", s.handlePublicSnapshot)
+	}
+
 	// Static files (frontend) - SPA fallback to index.html
 	if s.staticFS != nil {
 		r.Handle("/*", spaHandler(http.FS(s.staticFS)))
@@ -194,16 +434,36 @@ func spaHandler(fsys http.FileSystem) http.Handler {
 // Start starts the server
 func (s *Server) Start() error {
 	s.broadcaster.Start()
+	if s.publicSnapshot != nil {
+		s.publicSnapshot.start(s)
+	}
 
-	addr := fmt.Sprintf(":%d", s.port)
-	log.Printf("Starting Explorer server on http://localhost%s", addr)
+	if !s.tls.Enabled() {
+		log.Printf("Starting Explorer server on http://localhost%s", s.httpServer.Addr)
+		if err := s.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	}
 
-	return http.ListenAndServe(addr, s.router)
+	tlsConfig, err := s.tls.serverConfig()
+	if err != nil {
+		return err
+	}
+	s.httpServer.TLSConfig = tlsConfig
+	log.Printf("Starting Explorer server on https://localhost%s", s.httpServer.Addr)
+	if err := s.httpServer.ListenAndServeTLS(s.tls.CertFile, s.tls.KeyFile); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
 }
 
-// Stop gracefully stops the server
+// Stop stops background broadcasting; Shutdown also drains HTTP requests
 func (s *Server) Stop() {
 	s.broadcaster.Stop()
+	if s.publicSnapshot != nil {
+		s.publicSnapshot.stop()
+	}
 }
 
 // Handlers
@@ -243,14 +503,166 @@ func (s *Server) handleClusterInfo(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) handleCapabilities(w http.ResponseWriter, r *http.Request) {
-	caps, err := k8s.CheckCapabilities(r.Context())
+	var caps *k8s.Capabilities
+	var err error
+	subject := userSubject(r.Context())
+	if subject != nil {
+		caps, err = k8s.CheckCapabilitiesFor(r.Context(), subject)
+	} else {
+		caps, err = k8s.CheckCapabilities(r.Context())
+	}
 	if err != nil {
 		s.writeError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
+	// Node shell needs both the server-side opt-in and permission to exec into the debug pod
+	caps.NodeShell = s.nodeShell.Enabled && caps.Exec
+	// Debug containers are added via ephemeralcontainers, then exec'd into
+	caps.DebugContainers = caps.DebugContainers && caps.Exec
+	if f := k8s.GetFeatures(); f != nil && !f.EphemeralContainers {
+		caps.DebugContainers = false
+	}
+	if caps.DebugContainers {
+		caps.DebugImages = s.debug.Images
+	}
+	caps.SecretsMetadataOnly = k8s.GetResourceCache().SecretsMetadataOnly()
+	caps.ExecRecorded = execaudit.Enabled()
+
+	caps.Unavailable = k8s.ExplainCapabilities(caps, k8s.GetFeatures(), subject)
+	switch {
+	case !s.nodeShell.Enabled:
+		caps.Unavailable["nodeShell"] = k8s.CapabilityExplainer{
+			Reason: "Node shell is disabled on this Radar server",
+			Fix:    "radar --enable-node-shell",
+		}
+	case !caps.Exec:
+		explainer := caps.Unavailable["exec"]
+		explainer.Reason = "Node shell runs in a debug pod and needs the terminal's permission: " + explainer.Reason
+		caps.Unavailable["nodeShell"] = explainer
+	}
+	if caps.SecretsMetadataOnly {
+		caps.Unavailable["secretValues"] = k8s.CapabilityExplainer{
+			Reason: "Secret values aren't loaded: Radar watches secrets in metadata-only mode",
+			Fix:    "radar --secrets=full",
+		}
+	}
 	s.writeJSON(w, caps)
 }
 
+// WatchNamespaces describes which namespaces the informer cache watches
+type WatchNamespaces struct {
+	Scoped     bool     `json:"scoped"`               // False when the whole cluster is watched
+	Pinned     []string `json:"pinned,omitempty"`     // From --namespaces
+	Namespaces []string `json:"namespaces,omitempty"` // Currently watched
+}
+
+func (s *Server) handleGetWatchNamespaces(w http.ResponseWriter, r *http.Request) {
+	cache := k8s.GetResourceCache()
+	if cache == nil {
+		s.writeExplorerError(w, explorerErrors.CacheNotInitialized())
+		return
+	}
+	s.writeJSON(w, WatchNamespaces{
+		Scoped:     cache.IsNamespaceScoped(),
+		Pinned:     k8s.WatchNamespaces,
+		Namespaces: cache.WatchedNamespaces(),
+	})
+}
+
+// handleSetWatchNamespaces replaces the watched namespaces of a namespace-scoped cache,
+// starting informers for added namespaces and stopping removed ones
+func (s *Server) handleSetWatchNamespaces(w http.ResponseWriter, r *http.Request) {
+	cache := k8s.GetResourceCache()
+	if cache == nil {
+		s.writeExplorerError(w, explorerErrors.CacheNotInitialized())
+		return
+	}
+	var req WatchNamespaces
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	if err := cache.SetWatchedNamespaces(req.Namespaces); err != nil {
+		s.writeExplorerError(w, err)
+		return
+	}
+	// Removed namespaces produce no change events, so refresh topology subscribers explicitly
+	go s.broadcaster.broadcastTopologyUpdate()
+	s.handleGetWatchNamespaces(w, r)
+}
+
+// PermissionCheckRequest is a batch of access questions, evaluated for the caller or, with
+// impersonate rights, for another subject
+type PermissionCheckRequest struct {
+	Checks  []k8s.PermissionCheck     `json:"checks"`
+	Subject *k8s.ImpersonationSubject `json:"subject,omitempty"`
+}
+
+// handleCheckPermissions answers "can I do X" for a batch of verbs/resources so the UI
+// can hide or disable actions up front instead of failing after the click
+func (s *Server) handleCheckPermissions(w http.ResponseWriter, r *http.Request) {
+	var req PermissionCheckRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	if len(req.Checks) == 0 {
+		s.writeError(w, http.StatusBadRequest, "at least one check is required")
+		return
+	}
+
+	for _, check := range req.Checks {
+		if err := auth.CheckNamespace(r.Context(), check.Namespace); err != nil {
+			s.writeExplorerError(w, explorerErrors.New(explorerErrors.ErrForbidden, err.Error()))
+			return
+		}
+	}
+
+	// Callers evaluate their own RBAC; asking about anyone else takes impersonate rights
+	caller := userSubject(r.Context())
+	if req.Subject == nil {
+		req.Subject = caller
+	} else if !sameSubject(req.Subject, caller) {
+		if err := checkUserAccess(r.Context(), impersonateChecks(req.Subject)...); err != nil {
+			s.writeExplorerError(w, explorerErrors.New(explorerErrors.ErrForbidden, err.Error()))
+			return
+		}
+	}
+
+	results, err := k8s.CheckPermissions(r.Context(), req.Checks, req.Subject)
+	if err != nil {
+		if errors.Is(err, k8s.ErrTooManyChecks) {
+			s.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		s.writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	s.writeJSON(w, map[string]any{"results": results})
+}
+
+// sameSubject reports whether subject is the caller, with no groups the caller lacks
+func sameSubject(subject, caller *k8s.ImpersonationSubject) bool {
+	if caller == nil || subject.User != caller.User {
+		return false
+	}
+	for _, g := range subject.Groups {
+		if !slices.Contains(caller.Groups, g) {
+			return false
+		}
+	}
+	return true
+}
+
+// impersonateChecks are the RBAC checks for acting as subject
+func impersonateChecks(subject *k8s.ImpersonationSubject) []k8s.PermissionCheck {
+	checks := []k8s.PermissionCheck{{Verb: "impersonate", Resource: "users", Name: subject.User}}
+	for _, g := range subject.Groups {
+		checks = append(checks, k8s.PermissionCheck{Verb: "impersonate", Resource: "groups", Name: g})
+	}
+	return checks
+}
+
 func (s *Server) handleTopology(w http.ResponseWriter, r *http.Request) {
 	namespace := r.URL.Query().Get("namespace")
 	viewMode := r.URL.Query().Get("view")
@@ -260,6 +672,9 @@ func (s *Server) handleTopology(w http.ResponseWriter, r *http.Request) {
 	if viewMode == "traffic" {
 		opts.ViewMode = topology.ViewModeTraffic
 	}
+	if r.URL.Query().Get("networkPolicies") == "false" {
+		opts.IncludeNetworkPolicies = false
+	}
 
 	builder := topology.NewBuilder()
 	topo, err := builder.Build(opts)
@@ -268,13 +683,19 @@ func (s *Server) handleTopology(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	s.writeJSON(w, topo)
+	s.writeJSON(w, filterTopologyForUser(r.Context(), topo))
+}
+
+// NamespaceInfo is one entry of the namespace list
+type NamespaceInfo struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
 }
 
 func (s *Server) handleNamespaces(w http.ResponseWriter, r *http.Request) {
 	cache := k8s.GetResourceCache()
 	if cache == nil {
-		s.writeError(w, http.StatusServiceUnavailable, "Resource cache not available")
+		s.writeExplorerError(w, explorerErrors.CacheNotInitialized())
 		return
 	}
 
@@ -284,12 +705,9 @@ func (s *Server) handleNamespaces(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result := make([]map[string]any, 0, len(namespaces))
+	result := make([]NamespaceInfo, 0, len(namespaces))
 	for _, ns := range namespaces {
-		result = append(result, map[string]any{
-			"name":   ns.Name,
-			"status": string(ns.Status.Phase),
-		})
+		result = append(result, NamespaceInfo{Name: ns.Name, Status: string(ns.Status.Phase)})
 	}
 
 	s.writeJSON(w, result)
@@ -312,20 +730,33 @@ func (s *Server) handleAPIResources(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) handleListResources(w http.ResponseWriter, r *http.Request) {
-	kind := chi.URLParam(r, "kind")
-	namespace := r.URL.Query().Get("namespace")
+	result, err := listResources(r.Context(), chi.URLParam(r, "kind"), r.URL.Query().Get("namespace"))
+	if err != nil {
+		s.writeExplorerError(w, err)
+		return
+	}
+	s.writeJSON(w, result)
+}
 
+// listResources lists a kind from the typed cache, or the dynamic cache for CRDs and
+// kinds without a typed informer
+func listResources(ctx context.Context, kind, namespace string) (any, error) {
 	cache := k8s.GetResourceCache()
 	if cache == nil {
-		s.writeError(w, http.StatusServiceUnavailable, "Resource cache not available")
-		return
+		return nil, explorerErrors.CacheNotInitialized()
 	}
 
 	var result any
 	var err error
 
+	// Kinds without a running typed informer (API version not served) use the dynamic cache
+	typedKind := kind
+	if k8s.IsKnownKind(kind) && kind != "secrets" && !cache.HasTypedInformer(kind) {
+		typedKind = ""
+	}
+
 	// Try typed cache for known resource types first
-	switch kind {
+	switch typedKind {
 	case "pods":
 		if namespace != "" {
 			result, err = cache.Pods().Pods(namespace).List(labels.Everything())
@@ -396,6 +827,18 @@ func (s *Server) handleListResources(w http.ResponseWriter, r *http.Request) {
 		} else {
 			result, err = cache.PersistentVolumeClaims().List(labels.Everything())
 		}
+	case "resourcequotas", "quotas":
+		if namespace != "" {
+			result, err = cache.ResourceQuotas().ResourceQuotas(namespace).List(labels.Everything())
+		} else {
+			result, err = cache.ResourceQuotas().List(labels.Everything())
+		}
+	case "limitranges":
+		if namespace != "" {
+			result, err = cache.LimitRanges().LimitRanges(namespace).List(labels.Everything())
+		} else {
+			result, err = cache.LimitRanges().List(labels.Everything())
+		}
 	case "jobs":
 		if namespace != "" {
 			result, err = cache.Jobs().Jobs(namespace).List(labels.Everything())
@@ -420,24 +863,17 @@ func (s *Server) handleListResources(w http.ResponseWriter, r *http.Request) {
 		result, err = cache.Namespaces().List(labels.Everything())
 	default:
 		// Fall back to dynamic cache for CRDs and other unknown resources
-		result, err = cache.ListDynamic(r.Context(), kind, namespace)
-		if err != nil {
-			// Check if it's an unknown resource error
-			if strings.Contains(err.Error(), "unknown resource kind") {
-				s.writeError(w, http.StatusBadRequest, err.Error())
-				return
-			}
-			s.writeError(w, http.StatusInternalServerError, err.Error())
-			return
+		result, err = cache.ListDynamic(ctx, kind, namespace)
+		// Check if it's an unknown resource error
+		if err != nil && strings.Contains(err.Error(), "unknown resource kind") {
+			return nil, explorerErrors.New(explorerErrors.ErrBadRequest, err.Error())
 		}
 	}
 
 	if err != nil {
-		s.writeError(w, http.StatusInternalServerError, err.Error())
-		return
+		return nil, explorerErrors.New(explorerErrors.ErrInternalServer, err.Error())
 	}
-
-	s.writeJSON(w, result)
+	return redactSecrets(result), nil
 }
 
 // normalizeKind converts K8s kind names to lowercase for case-insensitive matching
@@ -471,6 +907,12 @@ func setTypeMeta(resource any) {
 	case *corev1.PersistentVolumeClaim:
 		r.APIVersion = "v1"
 		r.Kind = "PersistentVolumeClaim"
+	case *corev1.ResourceQuota:
+		r.APIVersion = "v1"
+		r.Kind = "ResourceQuota"
+	case *corev1.LimitRange:
+		r.APIVersion = "v1"
+		r.Kind = "LimitRange"
 	case *appsv1.Deployment:
 		r.APIVersion = "apps/v1"
 		r.Kind = "Deployment"
@@ -512,15 +954,21 @@ func (s *Server) handleGetResource(w http.ResponseWriter, r *http.Request) {
 
 	cache := k8s.GetResourceCache()
 	if cache == nil {
-		s.writeError(w, http.StatusServiceUnavailable, "Resource cache not available")
+		s.writeExplorerError(w, explorerErrors.CacheNotInitialized())
 		return
 	}
 
 	var resource any
 	var err error
 
+	// Kinds without a running typed informer (API version not served) use the dynamic cache
+	typedKind := kind
+	if k8s.IsKnownKind(kind) && kind != "secrets" && kind != "secret" && !cache.HasTypedInformer(kind) {
+		typedKind = ""
+	}
+
 	// Try typed cache for known resource types first
-	switch kind {
+	switch typedKind {
 	case "pods", "pod":
 		resource, err = cache.Pods().Pods(namespace).Get(name)
 	case "services", "service":
@@ -540,12 +988,16 @@ func (s *Server) handleGetResource(w http.ResponseWriter, r *http.Request) {
 	case "secrets", "secret":
 		lister := cache.Secrets()
 		if lister == nil {
-			s.writeError(w, http.StatusForbidden, "secrets access not available (RBAC not granted)")
+			s.writeExplorerError(w, explorerErrors.New(explorerErrors.ErrK8sForbidden, "secrets access not available (RBAC not granted)"))
 			return
 		}
 		resource, err = lister.Secrets(namespace).Get(name)
 	case "persistentvolumeclaims", "persistentvolumeclaim", "pvcs", "pvc":
 		resource, err = cache.PersistentVolumeClaims().PersistentVolumeClaims(namespace).Get(name)
+	case "resourcequotas", "resourcequota", "quotas", "quota":
+		resource, err = cache.ResourceQuotas().ResourceQuotas(namespace).Get(name)
+	case "limitranges", "limitrange":
+		resource, err = cache.LimitRanges().LimitRanges(namespace).Get(name)
 	case "hpas", "hpa", "horizontalpodautoscaler", "horizontalpodautoscalers":
 		resource, err = cache.HorizontalPodAutoscalers().HorizontalPodAutoscalers(namespace).Get(name)
 	case "jobs", "job":
@@ -565,38 +1017,46 @@ func (s *Server) handleGetResource(w http.ResponseWriter, r *http.Request) {
 				s.writeError(w, http.StatusBadRequest, err.Error())
 				return
 			}
-			if strings.Contains(err.Error(), "not found") {
-				s.writeError(w, http.StatusNotFound, err.Error())
-				return
-			}
-			s.writeError(w, http.StatusInternalServerError, err.Error())
+			s.writeExplorerError(w, err)
 			return
 		}
 	}
 
 	if err != nil {
-		s.writeError(w, http.StatusNotFound, err.Error())
+		s.writeExplorerError(w, err)
 		return
 	}
 
+	resource = redactSecrets(resource)
 	// Set APIVersion and Kind for typed resources (informers don't populate these)
 	setTypeMeta(resource)
 
 	// Get relationships from cached topology
 	var relationships *topology.Relationships
 	if cachedTopo := s.broadcaster.GetCachedTopology(); cachedTopo != nil {
-		relationships = topology.GetRelationships(kind, namespace, name, cachedTopo)
+		relationships = topology.GetRelationships(kind, group, namespace, name, cachedTopo)
 	}
 
 	// Return resource with relationships
-	response := topology.ResourceWithRelationships{
+	response := resourceDetail{ResourceWithRelationships: topology.ResourceWithRelationships{
 		Resource:      resource,
 		Relationships: relationships,
+	}}
+	if u, ok := resource.(*unstructured.Unstructured); ok && u.GetKind() == "Rollout" && strings.HasPrefix(u.GetAPIVersion(), "argoproj.io/") {
+		runsCheck := k8s.PermissionCheck{Verb: "list", Group: "argoproj.io", Resource: "analysisruns", Namespace: namespace}
+		response.Rollout = k8s.GetArgoRolloutStatus(u, checkUserAccess(r.Context(), runsCheck) == nil)
 	}
 
 	s.writeJSON(w, response)
 }
 
+// resourceDetail is a resource with its relationships and, for an Argo Rollout, its steps
+// and analysis runs
+type resourceDetail struct {
+	topology.ResourceWithRelationships
+	Rollout *k8s.ArgoRolloutStatus `json:"rollout,omitempty"`
+}
+
 // handlePodMetrics fetches metrics for a specific pod from the metrics.k8s.io API
 func (s *Server) handlePodMetrics(w http.ResponseWriter, r *http.Request) {
 	namespace := chi.URLParam(r, "namespace")
@@ -632,7 +1092,53 @@ func (s *Server) handleNodeMetrics(w http.ResponseWriter, r *http.Request) {
 	s.writeJSON(w, metrics)
 }
 
-// handlePodMetricsHistory returns historical metrics for a specific pod
+// handleListNodeDetails returns every node's conditions, taints, versions and allocatable
+// capacity against the requests of the pods scheduled to it
+func (s *Server) handleListNodeDetails(w http.ResponseWriter, r *http.Request) {
+	cache := k8s.GetResourceCache()
+	if cache == nil {
+		s.writeExplorerError(w, explorerErrors.New(explorerErrors.ErrCacheNotInitialized, "resource cache not initialized"))
+		return
+	}
+	details, err := cache.ListNodeDetails()
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.writeJSON(w, details)
+}
+
+// handleGetNodeDetail returns one node's detail with the pods scheduled to it
+func (s *Server) handleGetNodeDetail(w http.ResponseWriter, r *http.Request) {
+	cache := k8s.GetResourceCache()
+	if cache == nil {
+		s.writeExplorerError(w, explorerErrors.New(explorerErrors.ErrCacheNotInitialized, "resource cache not initialized"))
+		return
+	}
+	detail, err := cache.GetNodeDetail(chi.URLParam(r, "name"))
+	if err != nil {
+		s.writeExplorerError(w, err)
+		return
+	}
+	s.writeJSON(w, detail)
+}
+
+// metricsHistoryRange parses ?range= (a Go duration). It reports whether the range needs
+// the persistent store, i.e. reaches past the in-memory hour.
+func metricsHistoryRange(r *http.Request, store *k8s.MetricsHistoryStore) (time.Duration, bool, error) {
+	v := r.URL.Query().Get("range")
+	if v == "" {
+		return 0, false, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		return 0, false, fmt.Errorf("invalid range %q (examples: 1h, 24h, 168h)", v)
+	}
+	return d, d > k8s.MetricsHistorySize*k8s.MetricsPollInterval && store.Persistent(), nil
+}
+
+// handlePodMetricsHistory returns historical metrics for a specific pod: the last hour from
+// memory, or ?range= from disk when history is persisted
 func (s *Server) handlePodMetricsHistory(w http.ResponseWriter, r *http.Request) {
 	namespace := chi.URLParam(r, "namespace")
 	name := chi.URLParam(r, "name")
@@ -642,6 +1148,20 @@ func (s *Server) handlePodMetricsHistory(w http.ResponseWriter, r *http.Request)
 		s.writeError(w, http.StatusServiceUnavailable, "Metrics history not available")
 		return
 	}
+	span, fromDisk, err := metricsHistoryRange(r, store)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if fromDisk {
+		history, err := store.QueryPodMetricsHistory(r.Context(), namespace, name, time.Now().Add(-span))
+		if err != nil {
+			s.writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to read metrics history: %v", err))
+			return
+		}
+		s.writeJSON(w, history)
+		return
+	}
 
 	history := store.GetPodMetricsHistory(namespace, name)
 	if history == nil {
@@ -650,13 +1170,14 @@ func (s *Server) handlePodMetricsHistory(w http.ResponseWriter, r *http.Request)
 			Namespace:  namespace,
 			Name:       name,
 			Containers: []k8s.ContainerMetricsHistory{},
+			Persistent: store.Persistent(),
 		}
 	}
 
 	s.writeJSON(w, history)
 }
 
-// handleNodeMetricsHistory returns historical metrics for a specific node
+// handleNodeMetricsHistory returns historical metrics for a specific node (?range= as for pods)
 func (s *Server) handleNodeMetricsHistory(w http.ResponseWriter, r *http.Request) {
 	name := chi.URLParam(r, "name")
 
@@ -665,6 +1186,20 @@ func (s *Server) handleNodeMetricsHistory(w http.ResponseWriter, r *http.Request
 		s.writeError(w, http.StatusServiceUnavailable, "Metrics history not available")
 		return
 	}
+	span, fromDisk, err := metricsHistoryRange(r, store)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if fromDisk {
+		history, err := store.QueryNodeMetricsHistory(r.Context(), name, time.Now().Add(-span))
+		if err != nil {
+			s.writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to read metrics history: %v", err))
+			return
+		}
+		s.writeJSON(w, history)
+		return
+	}
 
 	history := store.GetNodeMetricsHistory(name)
 	if history == nil {
@@ -672,6 +1207,7 @@ func (s *Server) handleNodeMetricsHistory(w http.ResponseWriter, r *http.Request
 		history = &k8s.NodeMetricsHistory{
 			Name:       name,
 			DataPoints: []k8s.MetricsDataPoint{},
+			Persistent: store.Persistent(),
 		}
 	}
 
@@ -683,7 +1219,7 @@ func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
 
 	cache := k8s.GetResourceCache()
 	if cache == nil {
-		s.writeError(w, http.StatusServiceUnavailable, "Resource cache not available")
+		s.writeExplorerError(w, explorerErrors.CacheNotInitialized())
 		return
 	}
 
@@ -735,7 +1271,7 @@ func (s *Server) handleChanges(w http.ResponseWriter, r *http.Request) {
 
 	store := timeline.GetStore()
 	if store == nil {
-		s.writeError(w, http.StatusServiceUnavailable, "Timeline store not available")
+		s.writeExplorerError(w, explorerErrors.New(explorerErrors.ErrTimelineStoreNotInit, "timeline store not available"))
 		return
 	}
 
@@ -764,6 +1300,111 @@ func (s *Server) handleChanges(w http.ResponseWriter, r *http.Request) {
 	s.writeJSON(w, events)
 }
 
+// handleChangesExport streams all stored timeline events matching the filters as a download.
+// ?format=json|csv|ndjson (default json), ?kind= (comma-separated), ?namespace=,
+// ?since=/?until= (RFC3339), ?filter= preset (default "all") and ?include_k8s_events=false.
+func (s *Server) handleChangesExport(w http.ResponseWriter, r *http.Request) {
+	format, opts, err := parseChangesExport(r.URL.Query())
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	store := timeline.GetStore()
+	if store == nil {
+		s.writeExplorerError(w, explorerErrors.New(explorerErrors.ErrTimelineStoreNotInit, "timeline store not available"))
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", changesExportFilename(format)))
+	// Headers are sent with the first page, so later failures can only truncate the stream
+	if n, err := timeline.Export(r.Context(), store, w, format, opts); err != nil {
+		log.Printf("[timeline] Export failed after %d events: %v", n, err)
+	}
+}
+
+// handleChangesExportStore writes the same export as handleChangesExport to the artifact
+// store (--artifact-store) under timeline/, instead of downloading it. The export is built
+// in memory before it's uploaded.
+// POST /api/changes/export
+func (s *Server) handleChangesExportStore(w http.ResponseWriter, r *http.Request) {
+	artifacts := objectstore.Artifacts()
+	if artifacts == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "No artifact store configured (start with --artifact-store)")
+		return
+	}
+	format, opts, err := parseChangesExport(r.URL.Query())
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	store := timeline.GetStore()
+	if store == nil {
+		s.writeExplorerError(w, explorerErrors.New(explorerErrors.ErrTimelineStoreNotInit, "timeline store not available"))
+		return
+	}
+
+	var buf bytes.Buffer
+	n, err := timeline.Export(r.Context(), store, &buf, format, opts)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, fmt.Sprintf("export failed after %d events: %v", n, err))
+		return
+	}
+	obj, err := artifacts.Put(r.Context(), "timeline/"+changesExportFilename(format), buf.Bytes(), format.ContentType())
+	if err != nil {
+		s.writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	s.writeJSON(w, struct {
+		*objectstore.Object
+		Events int `json:"events"`
+	}{obj, n})
+}
+
+func changesExportFilename(format timeline.ExportFormat) string {
+	return fmt.Sprintf("radar-timeline-%s.%s", time.Now().UTC().Format("20060102-150405"), format)
+}
+
+// parseChangesExport reads the export format and filters from the query
+func parseChangesExport(q url.Values) (timeline.ExportFormat, timeline.QueryOptions, error) {
+	format, err := timeline.ParseExportFormat(q.Get("format"))
+	if err != nil {
+		return "", timeline.QueryOptions{}, err
+	}
+
+	opts := timeline.QueryOptions{
+		Namespace:        q.Get("namespace"),
+		IncludeManaged:   true,
+		IncludeK8sEvents: q.Get("include_k8s_events") != "false",
+		FilterPreset:     q.Get("filter"),
+	}
+	if opts.FilterPreset == "" {
+		opts.FilterPreset = "all"
+	}
+	if _, ok := timeline.DefaultFilterPresets()[opts.FilterPreset]; !ok {
+		return "", opts, fmt.Errorf("unknown filter preset %q", opts.FilterPreset)
+	}
+	for _, k := range strings.Split(q.Get("kind"), ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			opts.Kinds = append(opts.Kinds, k)
+		}
+	}
+	for param, dst := range map[string]*time.Time{"since": &opts.Since, "until": &opts.Until} {
+		if v := q.Get(param); v != "" {
+			ts, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return "", opts, fmt.Errorf("%s must be an RFC3339 timestamp", param)
+			}
+			*dst = ts
+		}
+	}
+	if !opts.Since.IsZero() && !opts.Until.IsZero() && !opts.Since.Before(opts.Until) {
+		return "", opts, fmt.Errorf("since must be before until")
+	}
+	return format, opts, nil
+}
+
 // handleChangeChildren returns child resource changes for a given parent workload
 func (s *Server) handleChangeChildren(w http.ResponseWriter, r *http.Request) {
 	ownerKind := chi.URLParam(r, "kind")
@@ -798,57 +1439,122 @@ func (s *Server) handleChangeChildren(w http.ResponseWriter, r *http.Request) {
 
 // handleUpdateResource updates a Kubernetes resource from YAML
 func (s *Server) handleUpdateResource(w http.ResponseWriter, r *http.Request) {
-	kind := chi.URLParam(r, "kind")
-	namespace := chi.URLParam(r, "namespace")
-	name := chi.URLParam(r, "name")
-
-	// Read request body (YAML content)
-	body, err := io.ReadAll(r.Body)
-	if err != nil {
-		s.writeError(w, http.StatusBadRequest, "failed to read request body")
+	opts, ok := s.readResourceEdit(w, r)
+	if !ok {
 		return
 	}
-	defer r.Body.Close()
 
 	// Update the resource
-	result, err := k8s.UpdateResource(r.Context(), k8s.UpdateResourceOptions{
-		Kind:      kind,
-		Namespace: namespace,
-		Name:      name,
-		YAML:      string(body),
-	})
+	result, err := k8s.UpdateResource(r.Context(), opts)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			s.writeError(w, http.StatusNotFound, err.Error())
-			return
-		}
-		if strings.Contains(err.Error(), "invalid YAML") || strings.Contains(err.Error(), "mismatch") {
-			s.writeError(w, http.StatusBadRequest, err.Error())
-			return
-		}
-		s.writeError(w, http.StatusInternalServerError, err.Error())
+		s.writeUpdateError(w, err)
 		return
 	}
 
+	auditAction(r, "edit", opts.Kind, opts.Namespace, opts.Name)
 	s.writeJSON(w, result)
 }
 
+// handlePreviewUpdateResource dry-runs an edit on the API server and returns the live and
+// proposed objects with the changes between them. The UI applies it with the PUT above.
+func (s *Server) handlePreviewUpdateResource(w http.ResponseWriter, r *http.Request) {
+	opts, ok := s.readResourceEdit(w, r)
+	if !ok {
+		return
+	}
+
+	preview, err := k8s.PreviewResourceUpdate(r.Context(), opts)
+	if err != nil {
+		s.writeUpdateError(w, err)
+		return
+	}
+
+	s.writeJSON(w, preview)
+}
+
+// readResourceEdit reads edited YAML from the request body, rejecting edits that violate
+// an enforced resource policy (parse errors are reported by the update itself)
+func (s *Server) readResourceEdit(w http.ResponseWriter, r *http.Request) (k8s.UpdateResourceOptions, bool) {
+	opts := k8s.UpdateResourceOptions{
+		Kind:      chi.URLParam(r, "kind"),
+		Namespace: chi.URLParam(r, "namespace"),
+		Name:      chi.URLParam(r, "name"),
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, "failed to read request body")
+		return opts, false
+	}
+	defer r.Body.Close()
+	opts.YAML = string(body)
+
+	var verr *policy.ViolationError
+	if err := policy.CheckManifest(opts.YAML, opts.Namespace, policy.NamespaceLabels); errors.As(err, &verr) {
+		s.writeExplorerError(w, explorerErrors.New(explorerErrors.ErrPolicyViolation, verr.Error()).
+			WithDetail("violations", verr.Violations))
+		return opts, false
+	}
+	return opts, true
+}
+
+func (s *Server) writeUpdateError(w http.ResponseWriter, err error) {
+	if strings.Contains(err.Error(), "invalid YAML") || strings.Contains(err.Error(), "mismatch") {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	s.writeExplorerError(w, err)
+}
+
 // handleDeleteResource deletes a Kubernetes resource
 func (s *Server) handleDeleteResource(w http.ResponseWriter, r *http.Request) {
 	kind := chi.URLParam(r, "kind")
 	namespace := chi.URLParam(r, "namespace")
 	name := chi.URLParam(r, "name")
+	if namespace == "_" {
+		namespace = ""
+	}
 
-	err := k8s.DeleteResource(r.Context(), kind, namespace, name)
-	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			s.writeError(w, http.StatusNotFound, err.Error())
+	q := r.URL.Query()
+	opts := k8s.DeleteOptions{Propagation: q.Get("propagation")}
+	if v := q.Get("gracePeriodSeconds"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			s.writeExplorerError(w, explorerErrors.ValidationError("gracePeriodSeconds must be an integer"))
 			return
 		}
-		s.writeError(w, http.StatusInternalServerError, err.Error())
+		opts.GracePeriodSeconds = &n
+	}
+	if q.Get("force") == "true" {
+		zero := int64(0)
+		opts.GracePeriodSeconds = &zero
+	}
+
+	// Dry run: report what would be deleted, for the confirmation dialog
+	if q.Get("dryRun") == "true" {
+		preview, err := k8s.PreviewDelete(r.Context(), kind, namespace, name, opts)
+		if err != nil {
+			s.writeExplorerError(w, err)
+			return
+		}
+		s.writeJSON(w, preview)
 		return
 	}
 
+	err := k8s.DeleteResource(r.Context(), kind, namespace, name, opts)
+	if err != nil {
+		s.writeExplorerError(w, err)
+		return
+	}
+	var details []string
+	if opts.Propagation != "" {
+		details = append(details, "propagation "+strings.ToLower(opts.Propagation))
+	}
+	if opts.GracePeriodSeconds != nil {
+		details = append(details, fmt.Sprintf("grace period %ds", *opts.GracePeriodSeconds))
+	}
+	auditActionDetail(r, "delete", kind, namespace, name, strings.Join(details, ", "))
+
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -859,14 +1565,11 @@ func (s *Server) handleTriggerCronJob(w http.ResponseWriter, r *http.Request) {
 
 	result, err := k8s.TriggerCronJob(r.Context(), namespace, name)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			s.writeError(w, http.StatusNotFound, err.Error())
-			return
-		}
-		s.writeError(w, http.StatusInternalServerError, err.Error())
+		s.writeExplorerError(w, err)
 		return
 	}
 
+	auditAction(r, "trigger", "CronJob", namespace, name)
 	s.writeJSON(w, map[string]interface{}{
 		"message": "Job created successfully",
 		"jobName": result.GetName(),
@@ -880,14 +1583,11 @@ func (s *Server) handleSuspendCronJob(w http.ResponseWriter, r *http.Request) {
 
 	err := k8s.SetCronJobSuspend(r.Context(), namespace, name, true)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			s.writeError(w, http.StatusNotFound, err.Error())
-			return
-		}
-		s.writeError(w, http.StatusInternalServerError, err.Error())
+		s.writeExplorerError(w, err)
 		return
 	}
 
+	auditAction(r, "suspend", "CronJob", namespace, name)
 	s.writeJSON(w, map[string]string{"message": "CronJob suspended"})
 }
 
@@ -898,17 +1598,32 @@ func (s *Server) handleResumeCronJob(w http.ResponseWriter, r *http.Request) {
 
 	err := k8s.SetCronJobSuspend(r.Context(), namespace, name, false)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			s.writeError(w, http.StatusNotFound, err.Error())
-			return
-		}
-		s.writeError(w, http.StatusInternalServerError, err.Error())
+		s.writeExplorerError(w, err)
 		return
 	}
 
+	auditAction(r, "resume", "CronJob", namespace, name)
 	s.writeJSON(w, map[string]string{"message": "CronJob resumed"})
 }
 
+// handleRerunJob re-runs a finished Job by creating a copy of it under a new name
+func (s *Server) handleRerunJob(w http.ResponseWriter, r *http.Request) {
+	namespace := chi.URLParam(r, "namespace")
+	name := chi.URLParam(r, "name")
+
+	result, err := k8s.RerunJob(r.Context(), namespace, name)
+	if err != nil {
+		s.writeExplorerError(w, err)
+		return
+	}
+
+	auditActionDetail(r, "rerun", "Job", namespace, name, "as "+result.GetName())
+	s.writeJSON(w, map[string]interface{}{
+		"message": "Job created successfully",
+		"jobName": result.GetName(),
+	})
+}
+
 // handleRestartWorkload performs a rolling restart on a Deployment, StatefulSet, or DaemonSet
 func (s *Server) handleRestartWorkload(w http.ResponseWriter, r *http.Request) {
 	kind := chi.URLParam(r, "kind")
@@ -927,17 +1642,108 @@ func (s *Server) handleRestartWorkload(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := k8s.RestartWorkload(r.Context(), kind, namespace, name)
-	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			s.writeError(w, http.StatusNotFound, err.Error())
+	if _, err := k8s.RestartWorkload(r.Context(), kind, namespace, name); err != nil {
+		s.writeExplorerError(w, err)
+		return
+	}
+
+	auditAction(r, "restart", kind, namespace, name)
+	s.writeJSON(w, map[string]string{"message": "Workload restart initiated"})
+}
+
+// ScaleRequest is the body for scaling a workload
+type ScaleRequest struct {
+	Replicas *int32 `json:"replicas"`
+	// UpdateHPA changes the range of the HPA managing the workload instead (minReplicas
+	// becomes replicas), so the HPA doesn't revert the scale
+	UpdateHPA bool `json:"updateHPA,omitempty"`
+}
+
+// ScaleResponse reports a completed scale
+type ScaleResponse struct {
+	Message          string `json:"message"`
+	PreviousReplicas int32  `json:"previousReplicas"`
+	Replicas         int32  `json:"replicas"`
+	// Warning is set when an HPA manages the workload and will override the replica count
+	Warning string        `json:"warning,omitempty"`
+	HPA     *k8s.HPARange `json:"hpa,omitempty"` // Set when updateHPA changed the HPA's range
+}
+
+// handleScaleWorkload sets the replica count of a Deployment, StatefulSet, ReplicaSet, or
+// Rollout. When an HPA manages it the scale is made with a warning, or refused with
+// --scale-hpa-policy=refuse; with updateHPA the HPA's range is moved instead.
+func (s *Server) handleScaleWorkload(w http.ResponseWriter, r *http.Request) {
+	kind := chi.URLParam(r, "kind")
+	namespace := chi.URLParam(r, "namespace")
+	name := chi.URLParam(r, "name")
+
+	var req ScaleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Replicas == nil {
+		s.writeError(w, http.StatusBadRequest, "body must be {\"replicas\": <count>}")
+		return
+	}
+
+	hpas := k8s.ScalingHPAs(kind, namespace, name)
+	hpaNames := make([]string, len(hpas))
+	for i, hpa := range hpas {
+		hpaNames[i] = hpa.Name
+	}
+	if req.UpdateHPA {
+		switch {
+		case len(hpas) == 0:
+			s.writeError(w, http.StatusBadRequest, "the workload isn't scaled by an HPA")
+			return
+		case len(hpas) > 1:
+			s.writeError(w, http.StatusConflict, fmt.Sprintf("the workload is scaled by several HPAs (%s); update them directly", strings.Join(hpaNames, ", ")))
 			return
 		}
-		s.writeError(w, http.StatusInternalServerError, err.Error())
+		if err := checkUserAccess(r.Context(), k8s.PermissionCheck{Verb: "patch", Group: "autoscaling", Resource: "horizontalpodautoscalers", Namespace: namespace, Name: hpas[0].Name}); err != nil {
+			s.writeError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		hpaRange, err := k8s.ScaleHPA(r.Context(), hpas[0], *req.Replicas)
+		if err != nil {
+			s.writeExplorerError(w, err)
+			return
+		}
+		auditActionDetail(r, "scale", "horizontalpodautoscalers", namespace, hpaRange.Name,
+			fmt.Sprintf("minReplicas %d → %d, maxReplicas %d → %d", hpaRange.PreviousMinReplicas, hpaRange.MinReplicas, hpaRange.PreviousMaxReplicas, hpaRange.MaxReplicas))
+		s.writeJSON(w, ScaleResponse{
+			Message:          "HPA updated",
+			PreviousReplicas: hpaRange.PreviousMinReplicas,
+			Replicas:         hpaRange.MinReplicas,
+			HPA:              hpaRange,
+		})
 		return
 	}
 
-	s.writeJSON(w, map[string]string{"message": "Workload restart initiated"})
+	var warning string
+	if len(hpas) > 0 {
+		if s.scaleHPAPolicy == k8s.ScaleHPAPolicyRefuse {
+			s.writeError(w, http.StatusConflict, fmt.Sprintf("the workload is scaled by HPA %s, which would revert the change; set updateHPA to change the HPA's minReplicas instead", strings.Join(hpaNames, ", ")))
+			return
+		}
+		hpa := hpas[0]
+		minReplicas := int32(1)
+		if hpa.Spec.MinReplicas != nil {
+			minReplicas = *hpa.Spec.MinReplicas
+		}
+		warning = fmt.Sprintf("HPA %s manages this workload and will move it back within %d–%d replicas; update the HPA to keep the new count", strings.Join(hpaNames, ", "), minReplicas, hpa.Spec.MaxReplicas)
+	}
+
+	previous, err := k8s.ScaleWorkload(r.Context(), kind, namespace, name, *req.Replicas)
+	if err != nil {
+		s.writeExplorerError(w, err)
+		return
+	}
+
+	auditActionDetail(r, "scale", kind, namespace, name, fmt.Sprintf("replicas %d → %d", previous, *req.Replicas))
+	s.writeJSON(w, ScaleResponse{
+		Message:          "Workload scaled",
+		PreviousReplicas: previous,
+		Replicas:         *req.Replicas,
+		Warning:          warning,
+	})
 }
 
 // Session management handlers
@@ -960,10 +1766,10 @@ func (s *Server) handleGetSessions(w http.ResponseWriter, r *http.Request) {
 }
 
 // StopAllSessions terminates all active port forwards and exec sessions
-func StopAllSessions() {
+func StopAllSessions(reason string) {
 	log.Println("Stopping all active sessions...")
 	StopAllPortForwards()
-	StopAllExecSessions()
+	StopAllExecSessions(reason)
 }
 
 // Context switching handlers
@@ -1000,7 +1806,7 @@ func (s *Server) handleSwitchContext(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Stop all active sessions before switching
-	StopAllSessions()
+	StopAllSessions("Session closed: switching cluster context")
 
 	// Perform the context switch
 	if err := k8s.PerformContextSwitch(name); err != nil {
@@ -1029,46 +1835,16 @@ func (s *Server) writeJSON(w http.ResponseWriter, data any) {
 	}
 }
 
+// writeError writes an error response with the generic code for status.
+// Prefer writeExplorerError when the error itself carries the cause.
 func (s *Server) writeError(w http.ResponseWriter, status int, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	if err := json.NewEncoder(w).Encode(map[string]string{"error": message}); err != nil {
-		log.Printf("Failed to encode error response: %v", err)
-	}
+	explorerErrors.WriteHTTP(w, status, message)
 }
 
-// writeExplorerError writes an ExplorerError as a structured JSON response.
-// It maps error codes to appropriate HTTP status codes.
+// writeExplorerError writes err as a structured JSON response. ExplorerErrors keep
+// their code; Kubernetes API errors are classified (RBAC denied, not found, conflict, ...).
 func (s *Server) writeExplorerError(w http.ResponseWriter, err error) {
-	var explorerErr *explorerErrors.ExplorerError
-	if !errors.As(err, &explorerErr) {
-		// Not an ExplorerError, use generic internal server error
-		s.writeError(w, http.StatusInternalServerError, err.Error())
-		return
-	}
-
-	// Map error codes to HTTP status
-	status := http.StatusInternalServerError
-	switch explorerErr.Code {
-	case explorerErrors.ErrBadRequest, explorerErrors.ErrValidation:
-		status = http.StatusBadRequest
-	case explorerErrors.ErrNotFound, explorerErrors.ErrK8sResourceNotFound, explorerErrors.ErrHelmReleaseNotFound:
-		status = http.StatusNotFound
-	case explorerErrors.ErrServiceUnavailable, explorerErrors.ErrCacheNotInitialized, explorerErrors.ErrK8sClientNotInitialized:
-		status = http.StatusServiceUnavailable
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-
-	response := map[string]any{
-		"error": explorerErr.Message,
-		"code":  explorerErr.Code.String(),
-	}
-	if explorerErr.Details != nil {
-		response["details"] = explorerErr.Details
-	}
-	json.NewEncoder(w).Encode(response)
+	explorerErrors.Write(w, err)
 }
 
 // Debug handlers for event pipeline diagnostics
@@ -1093,3 +1869,18 @@ func (s *Server) handleDebugEventsDiagnose(w http.ResponseWriter, r *http.Reques
 	response := timeline.GetDiagnosis(kind, namespace, name)
 	s.writeJSON(w, response)
 }
+
+// handleDebugStartup returns the startup timing breakdown (phases, per-informer sync, hints)
+func (s *Server) handleDebugStartup(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, k8s.GetStartupReport())
+}
+
+// handleDebugFeatures returns the detected Kubernetes version and API feature set
+func (s *Server) handleDebugFeatures(w http.ResponseWriter, r *http.Request) {
+	features := k8s.GetFeatures()
+	if features == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "Feature detection has not completed")
+		return
+	}
+	s.writeJSON(w, features)
+}
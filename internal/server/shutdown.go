@@ -0,0 +1,59 @@
+package server
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// shutdownMessage is shown to clients whose streams and terminals end at shutdown
+const shutdownMessage = "Radar is shutting down"
+
+// drainMiddleware rejects requests once shutdown starts and ties long-lived streams and
+// terminals to the server's lifetime, so they end instead of holding shutdown open
+func (s *Server) drainMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.draining.Load() {
+			w.Header().Set("Retry-After", "5")
+			s.writeError(w, http.StatusServiceUnavailable, shutdownMessage)
+			return
+		}
+		if isLongLived(r) {
+			ctx, cancel := context.WithCancel(r.Context())
+			defer cancel()
+			stop := context.AfterFunc(s.streamsCtx, cancel)
+			defer stop()
+			r = r.WithContext(ctx)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isLongLived reports whether a request streams until the client leaves: SSE streams and
+// WebSocket terminals. The main event stream is excluded; the broadcaster ends it after
+// sending its shutdown event.
+func isLongLived(r *http.Request) bool {
+	if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		return true
+	}
+	return strings.HasSuffix(r.URL.Path, "/stream") && r.URL.Path != "/api/events/stream"
+}
+
+// Shutdown drains the server: new requests get 503, SSE clients get a "shutdown" event,
+// terminal sessions are closed with a message, and in-flight requests (including running
+// Helm installs and orchestrated restarts) may finish until ctx expires.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.draining.Store(true)
+
+	s.broadcaster.Broadcast(SSEEvent{Event: "shutdown", Data: map[string]string{"reason": shutdownMessage}})
+	s.Stop()
+	StopAllExecSessions(shutdownMessage)
+	s.stopStreams()
+
+	if err := s.httpServer.Shutdown(ctx); err != nil {
+		log.Printf("Requests still running at shutdown timeout: %v", err)
+		return s.httpServer.Close()
+	}
+	return nil
+}
@@ -1,10 +1,12 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
+	"slices"
 	"sync"
 	"time"
 
@@ -26,18 +28,25 @@ type SSEBroadcaster struct {
 	// Cached topology for relationship lookups (updated on each topology rebuild)
 	cachedTopology   *topology.Topology
 	cachedTopologyMu sync.RWMutex
+
+	// Resource list streams waiting for changes (see list_sync.go)
+	listWatchers   map[*listWatcher]struct{}
+	listWatchersMu sync.Mutex
+
+	// Dashboard counters and problems, maintained from resource changes (see dashboard_state.go)
+	dashboard *dashboardState
 }
 
 // ClientInfo stores information about a connected client
 type ClientInfo struct {
-	Namespace string
-	ViewMode  string // "full" or "traffic"
+	Namespace    string
+	ViewMode     string        // "full" or "traffic"
+	Subscription *Subscription // Nil receives every change
 }
 
 type clientRegistration struct {
-	ch        chan SSEEvent
-	namespace string
-	viewMode  string
+	ch   chan SSEEvent
+	info ClientInfo
 }
 
 // SSEEvent represents an event to send to clients
@@ -65,6 +74,9 @@ func NewSSEBroadcaster() *SSEBroadcaster {
 		register:   make(chan clientRegistration),
 		unregister: make(chan chan SSEEvent),
 		stopCh:     make(chan struct{}),
+
+		listWatchers: make(map[*listWatcher]struct{}),
+		dashboard:    newDashboardState(),
 	}
 }
 
@@ -78,6 +90,7 @@ func (b *SSEBroadcaster) Start() {
 
 	go b.run()
 	go b.watchResourceChanges()
+	go b.dashboard.run(b.stopCh)
 	go b.heartbeat()
 }
 
@@ -111,6 +124,10 @@ func (b *SSEBroadcaster) registerContextSwitchCallback() {
 			},
 		})
 
+		// List streams resend their lists from the new cluster
+		b.resetListWatchers()
+		b.dashboard.reset()
+
 		// Broadcast the new topology so clients can complete the switch
 		// Run in goroutine to not block the context switch
 		go b.broadcastTopologyUpdate()
@@ -159,9 +176,9 @@ func (b *SSEBroadcaster) run() {
 				close(reg.ch) // Signal rejection by closing the channel
 				continue
 			}
-			b.clients[reg.ch] = ClientInfo{Namespace: reg.namespace, ViewMode: reg.viewMode}
+			b.clients[reg.ch] = reg.info
 			b.mu.Unlock()
-			log.Printf("SSE client connected (namespace=%s, view=%s), total clients: %d", reg.namespace, reg.viewMode, len(b.clients))
+			log.Printf("SSE client connected (namespace=%s, view=%s, filtered=%t), total clients: %d", reg.info.Namespace, reg.info.ViewMode, reg.info.Subscription != nil, len(b.clients))
 
 		case ch := <-b.unregister:
 			b.mu.Lock()
@@ -203,28 +220,14 @@ func (b *SSEBroadcaster) watchResourceChanges() {
 				return
 			}
 
+			b.notifyListWatchers(change)
+			b.dashboard.apply(change)
+
 			// Broadcast K8s event immediately for important events
-			if change.Kind == "Event" || change.Operation == "delete" ||
+			important := change.Kind == "Event" || change.Operation == "delete" ||
 				(change.Kind == "Pod" && change.Operation != "update") ||
-				change.Diff != nil { // Also broadcast updates with meaningful diffs
-				eventData := map[string]any{
-					"kind":      change.Kind,
-					"namespace": change.Namespace,
-					"name":      change.Name,
-					"operation": change.Operation,
-				}
-				// Include diff info if available
-				if change.Diff != nil {
-					eventData["diff"] = map[string]any{
-						"fields":  change.Diff.Fields,
-						"summary": change.Diff.Summary,
-					}
-				}
-				b.Broadcast(SSEEvent{
-					Event: "k8s_event",
-					Data:  eventData,
-				})
-			}
+				change.Diff != nil // Also broadcast updates with meaningful diffs
+			b.broadcastChange(change, important)
 
 			// Schedule debounced topology update (500ms to reduce UI thrashing)
 			if !pendingUpdate {
@@ -241,12 +244,65 @@ func (b *SSEBroadcaster) watchResourceChanges() {
 	}
 }
 
+// broadcastChange sends a resource change to the clients whose subscriptions match it.
+// Unimportant updates only go to clients subscribed to health transitions, and only when
+// they are one. The event is built once, and not at all if nobody receives it.
+func (b *SSEBroadcaster) broadcastChange(change k8s.ResourceChange, important bool) {
+	transition := isHealthTransition(change)
+	var event *SSEEvent
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for ch, info := range b.clients {
+		sub := info.Subscription
+		if !important && !(transition && sub != nil && sub.transitions) {
+			continue
+		}
+		if !sub.Matches(change) {
+			continue
+		}
+		if event == nil {
+			event = changeEvent(change, transition)
+		}
+		safeSend(ch, *event)
+	}
+}
+
+// changeEvent builds the k8s_event for a resource change
+func changeEvent(change k8s.ResourceChange, transition bool) *SSEEvent {
+	eventData := map[string]any{
+		"kind":      change.Kind,
+		"namespace": change.Namespace,
+		"name":      change.Name,
+		"operation": change.Operation,
+	}
+	if change.Group != "" {
+		eventData["group"] = change.Group
+	}
+	// Include diff info if available
+	if change.Diff != nil {
+		eventData["diff"] = map[string]any{
+			"fields":  change.Diff.Fields,
+			"summary": change.Diff.Summary,
+		}
+	}
+	if transition {
+		eventData["health"] = change.Health
+		if change.PrevHealth != "" {
+			eventData["previousHealth"] = change.PrevHealth
+		}
+	}
+	return &SSEEvent{Event: "k8s_event", Data: eventData}
+}
+
 // broadcastTopologyUpdate sends the current topology to all clients
 func (b *SSEBroadcaster) broadcastTopologyUpdate() {
 	b.mu.RLock()
 	clients := make(map[chan SSEEvent]ClientInfo, len(b.clients))
 	for ch, info := range b.clients {
-		clients[ch] = info
+		if info.Subscription.wantsTopology() {
+			clients[ch] = info
+		}
 	}
 	b.mu.RUnlock()
 
@@ -333,8 +389,9 @@ func (b *SSEBroadcaster) Broadcast(event SSEEvent) {
 	}
 }
 
-// Subscribe adds a new SSE client. Returns nil if max clients reached.
-func (b *SSEBroadcaster) Subscribe(namespace, viewMode string) chan SSEEvent {
+// Subscribe adds a new SSE client; a nil subscription receives every change. Returns nil
+// if max clients reached.
+func (b *SSEBroadcaster) Subscribe(namespace, viewMode string, sub *Subscription) chan SSEEvent {
 	// Check client count before creating the channel to fail fast
 	b.mu.RLock()
 	clientCount := len(b.clients)
@@ -346,7 +403,7 @@ func (b *SSEBroadcaster) Subscribe(namespace, viewMode string) chan SSEEvent {
 	}
 
 	ch := make(chan SSEEvent, 10)
-	b.register <- clientRegistration{ch: ch, namespace: namespace, viewMode: viewMode}
+	b.register <- clientRegistration{ch: ch, info: ClientInfo{Namespace: namespace, ViewMode: viewMode, Subscription: sub}}
 	return ch
 }
 
@@ -372,6 +429,12 @@ func (b *SSEBroadcaster) updateCachedTopology(topo *topology.Topology) {
 
 // HandleSSE is the HTTP handler for the SSE endpoint
 func (b *SSEBroadcaster) HandleSSE(w http.ResponseWriter, r *http.Request) {
+	sub, err := parseSubscription(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	// Set SSE headers
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
@@ -393,27 +456,42 @@ func (b *SSEBroadcaster) HandleSSE(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Subscribe to events
-	eventCh := b.Subscribe(namespace, viewMode)
+	eventCh := b.Subscribe(namespace, viewMode, sub)
 	if eventCh == nil {
 		http.Error(w, "Too many SSE connections", http.StatusServiceUnavailable)
 		return
 	}
 	defer b.Unsubscribe(eventCh)
 
-	// Send initial topology immediately
-	builder := topology.NewBuilder()
-	opts := topology.DefaultBuildOptions()
-	opts.Namespace = namespace
-	if viewMode == "traffic" {
-		opts.ViewMode = topology.ViewModeTraffic
+	// Confirm the negotiated filters so clients can tell they were applied
+	if sub != nil {
+		data, _ := json.Marshal(sub)
+		fmt.Fprintf(w, "event: subscription\ndata: %s\n\n", data)
+		flusher.Flush()
 	}
-	if topo, err := builder.Build(opts); err == nil {
-		data, marshalErr := json.Marshal(topo)
-		if marshalErr != nil {
-			log.Printf("SSE: failed to marshal initial topology: %v", marshalErr)
-		} else {
-			fmt.Fprintf(w, "event: topology\ndata: %s\n\n", data)
-			flusher.Flush()
+
+	// Deltas are computed per connection against the (user-filtered) graph it last
+	// received, so a dropped update is folded into the next delta
+	var sent *topology.Topology
+
+	// Send initial topology immediately
+	if sub.wantsTopology() {
+		builder := topology.NewBuilder()
+		opts := topology.DefaultBuildOptions()
+		opts.Namespace = namespace
+		if viewMode == "traffic" {
+			opts.ViewMode = topology.ViewModeTraffic
+		}
+		if topo, err := builder.Build(opts); err == nil {
+			topo = filterTopologyForUser(r.Context(), topo)
+			data, marshalErr := json.Marshal(topo)
+			if marshalErr != nil {
+				log.Printf("SSE: failed to marshal initial topology: %v", marshalErr)
+			} else {
+				fmt.Fprintf(w, "event: topology\ndata: %s\n\n", data)
+				flusher.Flush()
+				sent = topo
+			}
 		}
 	}
 
@@ -426,6 +504,29 @@ func (b *SSEBroadcaster) HandleSSE(w http.ResponseWriter, r *http.Request) {
 			if !ok {
 				return
 			}
+			event, ok = filterEventForUser(r.Context(), event)
+			if !ok {
+				continue
+			}
+			if sub.wantsDeltas() {
+				switch event.Event {
+				case "context_changed":
+					sent = nil // The next topology is from another cluster; send it whole
+				case "topology":
+					topo, _ := event.Data.(*topology.Topology)
+					if sent != nil && topo != nil {
+						delta := topology.Diff(sent, topo)
+						sameWarnings := slices.Equal(sent.Warnings, topo.Warnings)
+						sent = topo
+						if delta.Empty() && sameWarnings {
+							continue
+						}
+						event = SSEEvent{Event: "topology_delta", Data: delta}
+					} else {
+						sent = topo
+					}
+				}
+			}
 			data, err := json.Marshal(event.Data)
 			if err != nil {
 				// Log the error and notify client instead of silently dropping
@@ -443,3 +544,23 @@ func (b *SSEBroadcaster) HandleSSE(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 }
+
+// filterEventForUser limits an event to what the client's user may see: topology is
+// filtered per user and resource changes are dropped unless the user can list the kind.
+// Clients without a user get every event unchanged.
+func filterEventForUser(ctx context.Context, event SSEEvent) (SSEEvent, bool) {
+	if userSubject(ctx) == nil {
+		return event, true
+	}
+	switch data := event.Data.(type) {
+	case *topology.Topology:
+		event.Data = filterTopologyForUser(ctx, data)
+	case map[string]any:
+		if event.Event == "k8s_event" {
+			kind, _ := data["kind"].(string)
+			namespace, _ := data["namespace"].(string)
+			return event, userCanSeeChange(ctx, kind, namespace)
+		}
+	}
+	return event, true
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/timeline"
+)
+
+// Subscription narrows what one SSE connection receives. It's negotiated from the
+// stream's query parameters and echoed back as the connection's first event:
+//
+//	kinds=Pod,Deployment     Resource kinds, case-insensitive (Kind.group for custom resources)
+//	namespaces=shop,billing  Namespaces; cluster-scoped resources are dropped when set
+//	selector=app=web,tier    Label selector; K8s Events have no labels and are dropped
+//	health=transitions       Only changes that move a resource's health state
+//	topology=false           No topology snapshots, just resource changes
+//	topology=delta           One snapshot, then topology_delta events with what changed
+//
+// Filters apply to k8s_event changes before they're queued for the connection, so
+// events a client would discard are never serialized or sent.
+type Subscription struct {
+	Kinds       []string `json:"kinds,omitempty"`
+	Namespaces  []string `json:"namespaces,omitempty"`
+	Selector    string   `json:"selector,omitempty"`
+	Health      string   `json:"health,omitempty"` // "transitions" or empty
+	NoTopology  bool     `json:"noTopology,omitempty"`
+	Deltas      bool     `json:"topologyDeltas,omitempty"`
+	kinds       map[string]bool
+	namespaces  map[string]bool
+	selector    labels.Selector
+	transitions bool
+}
+
+// parseSubscription reads a subscription from the stream's query parameters. It returns
+// nil when the connection asks for everything.
+func parseSubscription(q url.Values) (*Subscription, error) {
+	sub := &Subscription{
+		Kinds:      splitList(q.Get("kinds")),
+		Namespaces: splitList(q.Get("namespaces")),
+		Selector:   strings.TrimSpace(q.Get("selector")),
+		Health:     q.Get("health"),
+	}
+	if len(sub.Kinds) > 0 {
+		sub.kinds = make(map[string]bool, len(sub.Kinds))
+		for _, kind := range sub.Kinds {
+			sub.kinds[strings.ToLower(kind)] = true
+		}
+	}
+	if len(sub.Namespaces) > 0 {
+		sub.namespaces = make(map[string]bool, len(sub.Namespaces))
+		for _, ns := range sub.Namespaces {
+			sub.namespaces[ns] = true
+		}
+	}
+	if sub.Selector != "" {
+		selector, err := labels.Parse(sub.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector: %w", err)
+		}
+		sub.selector = selector
+	}
+	switch sub.Health {
+	case "":
+	case "transitions":
+		sub.transitions = true
+	default:
+		return nil, fmt.Errorf("invalid health %q: only \"transitions\" is supported", sub.Health)
+	}
+	if v := q.Get("topology"); v == "delta" {
+		sub.Deltas = true
+	} else if v != "" {
+		topology, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid topology %q: must be true, false or delta", v)
+		}
+		sub.NoTopology = !topology
+	}
+
+	if sub.kinds == nil && sub.namespaces == nil && sub.selector == nil && !sub.transitions && !sub.NoTopology && !sub.Deltas {
+		return nil, nil
+	}
+	return sub, nil
+}
+
+// Matches reports whether a resource change passes the subscription's filters. A nil
+// subscription matches everything.
+func (s *Subscription) Matches(change k8s.ResourceChange) bool {
+	if s == nil {
+		return true
+	}
+	if s.kinds != nil && !s.kinds[strings.ToLower(change.Kind)] &&
+		!s.kinds[strings.ToLower(k8s.QualifiedKind(change.Kind, change.Group))] {
+		return false
+	}
+	if s.namespaces != nil && !s.namespaces[change.Namespace] {
+		return false
+	}
+	if s.selector != nil && (change.Kind == "Event" || !s.selector.Matches(labels.Set(change.Labels))) {
+		return false
+	}
+	if s.transitions && !isHealthTransition(change) {
+		return false
+	}
+	return true
+}
+
+// wantsTopology reports whether the connection receives topology snapshots
+func (s *Subscription) wantsTopology() bool {
+	return s == nil || !s.NoTopology
+}
+
+// wantsDeltas reports whether topology updates after the first snapshot are sent as
+// deltas against what the connection last received
+func (s *Subscription) wantsDeltas() bool {
+	return s != nil && s.Deltas
+}
+
+// isHealthTransition reports whether a change moved a resource's health: an update that
+// changed it, or a resource that appeared already degraded or unhealthy. Kinds without
+// health tracking never transition.
+func isHealthTransition(change k8s.ResourceChange) bool {
+	if change.Health == "" || change.Health == timeline.HealthUnknown {
+		return false
+	}
+	switch change.Operation {
+	case "update":
+		return change.PrevHealth != "" && change.PrevHealth != change.Health
+	case "add":
+		return change.Health != timeline.HealthHealthy
+	}
+	return false
+}
+
+// splitList splits a comma-separated query value, dropping empty entries
+func splitList(v string) []string {
+	var out []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// PVCUsageResponse lists PVC filesystem usage with the thresholds it was classified by
+type PVCUsageResponse struct {
+	PVCs            []k8s.PVCUsage `json:"pvcs"`
+	WarningPercent  int            `json:"warningPercent"`
+	CriticalPercent int            `json:"criticalPercent"`
+}
+
+// handleListPVCUsage returns per-PVC usage from the kubelet stats summaries, fullest first.
+// Stats are sampled with the usage forecasts, so they can be up to one interval old.
+func (s *Server) handleListPVCUsage(w http.ResponseWriter, r *http.Request) {
+	usages, err := k8s.GetUsageForecaster().PVCUsages(r.Context(), r.URL.Query().Get("namespace"))
+	if err != nil {
+		s.writeExplorerError(w, err)
+		return
+	}
+	s.writeJSON(w, PVCUsageResponse{
+		PVCs:            usages,
+		WarningPercent:  k8s.PVCUsageWarningPercent,
+		CriticalPercent: k8s.PVCUsageCriticalPercent,
+	})
+}
+
+// handleExpandPVC raises a PVC's storage request, e.g. {"size": "20Gi"}
+func (s *Server) handleExpandPVC(w http.ResponseWriter, r *http.Request) {
+	namespace := chi.URLParam(r, "namespace")
+	name := chi.URLParam(r, "name")
+
+	var req struct {
+		Size string `json:"size"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Size == "" {
+		s.writeError(w, http.StatusBadRequest, "request body must include a size (e.g. {\"size\": \"20Gi\"})")
+		return
+	}
+
+	previous, err := k8s.ExpandPVC(r.Context(), namespace, name, req.Size)
+	if err != nil {
+		s.writeExplorerError(w, err)
+		return
+	}
+
+	auditActionDetail(r, "expand", "PersistentVolumeClaim", namespace, name, previous+" -> "+req.Size)
+	s.writeJSON(w, map[string]interface{}{
+		"message":  "Volume expansion requested",
+		"previous": previous,
+		"size":     req.Size,
+	})
+}
@@ -0,0 +1,42 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// TLSConfig serves HTTPS instead of HTTP. With a client CA, clients may present a
+// certificate signed by it (mTLS); proxy auth can then require one from the proxy.
+type TLSConfig struct {
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string // PEM bundle that verifies client certificates
+}
+
+// Enabled reports whether the server serves HTTPS
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != "" && c.KeyFile != ""
+}
+
+// serverConfig builds the TLS config. Client certificates are verified when given but
+// not required, so browsers reaching Radar through other routes still connect; it's up to
+// authentication to insist on one.
+func (c TLSConfig) serverConfig() (*tls.Config, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if c.ClientCAFile == "" {
+		return cfg, nil
+	}
+	pem, err := os.ReadFile(c.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading TLS client CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("TLS client CA %s contains no PEM certificates", c.ClientCAFile)
+	}
+	cfg.ClientCAs = pool
+	cfg.ClientAuth = tls.VerifyClientCertIfGiven
+	return cfg, nil
+}
@@ -0,0 +1,446 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/skyhook-io/radar/internal/auth"
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/search"
+	"github.com/skyhook-io/radar/internal/topology"
+)
+
+// Per-user RBAC: when a request acts for a Kubernetes user (auth.UserFromContext), the
+// user's own permissions are checked with SubjectAccessReview before Radar does anything
+// on their behalf with its service account. Results share the permission check cache.
+// With --impersonate, the changes themselves are also made as the user.
+
+// userSubject returns the identity whose RBAC gates the request, or nil to use Radar's own
+func userSubject(ctx context.Context) *k8s.ImpersonationSubject {
+	u, ok := auth.UserFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	return &k8s.ImpersonationSubject{User: u.Name, Groups: u.Groups}
+}
+
+// checkUserAccess returns an error unless the request's user passes every check. Checks
+// that can't be evaluated deny access (fail closed). Requests without a user always pass.
+func checkUserAccess(ctx context.Context, checks ...k8s.PermissionCheck) error {
+	subject := userSubject(ctx)
+	if subject == nil || len(checks) == 0 {
+		return nil
+	}
+	results, err := k8s.CheckPermissions(ctx, checks, subject)
+	if err != nil {
+		return fmt.Errorf("checking permissions of %s: %w", subject.User, err)
+	}
+	for _, res := range results {
+		if res.Error != "" {
+			return fmt.Errorf("checking permissions of %s: %s", subject.User, res.Error)
+		}
+		if !res.Allowed {
+			return fmt.Errorf("%s can't %s", subject.User, describeCheck(res.PermissionCheck))
+		}
+	}
+	return nil
+}
+
+// describeCheck renders a check like "get pods/log in namespace shop"
+func describeCheck(c k8s.PermissionCheck) string {
+	if c == clusterAdminCheck {
+		return "change Radar's settings (requires cluster admin)"
+	}
+	resource := c.Resource
+	if resource == "" {
+		resource = c.Kind
+	}
+	if c.Subresource != "" {
+		resource += "/" + c.Subresource
+	}
+	if c.Name != "" {
+		resource += " " + c.Name
+	}
+	if c.Namespace == "" {
+		return c.Verb + " " + resource + " cluster-wide"
+	}
+	return c.Verb + " " + resource + " in namespace " + c.Namespace
+}
+
+// userAccessMiddleware rejects API requests whose user lacks the RBAC the route needs.
+// routes resolves the route pattern, which isn't known yet when the middleware runs.
+func userAccessMiddleware(routes chi.Routes) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			subject := userSubject(r.Context())
+			if subject == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			rctx := chi.NewRouteContext()
+			pattern := routes.Find(rctx, r.Method, r.URL.Path)
+			if err := checkUserAccess(r.Context(), routeChecks(pattern, rctx, r)...); err != nil {
+				explorerErrors.Write(w, explorerErrors.New(explorerErrors.ErrForbidden, err.Error()))
+				return
+			}
+			// With --impersonate, changes the handler makes run as the user (k8s.ClientFor)
+			next.ServeHTTP(w, r.WithContext(k8s.WithImpersonation(r.Context(), subject)))
+		})
+	}
+}
+
+// routeChecks maps an API route to the RBAC checks its caller must pass. Routes that only
+// expose Radar's own state, or filter their output per user, need none.
+func routeChecks(pattern string, rctx *chi.Context, r *http.Request) []k8s.PermissionCheck {
+	ns, name := rctx.URLParam("namespace"), rctx.URLParam("name")
+	kind := rctx.URLParam("kind")
+
+	switch pattern {
+	case "/api/resources/{kind}", "/api/resources/{kind}/stream", "/api/resources/{kind}/table":
+		return perNamespace(r, k8s.PermissionCheck{Verb: "list", Kind: kind})
+	case "/api/events":
+		return perNamespace(r, k8s.PermissionCheck{Verb: "list", Resource: "events"})
+	case "/api/resources/{kind}/{namespace}/{name}":
+		verb := map[string]string{http.MethodGet: "get", http.MethodPut: "update", http.MethodDelete: "delete"}[r.Method]
+		if ns == "_" {
+			ns = ""
+		}
+		return []k8s.PermissionCheck{{Verb: verb, Kind: kind, Namespace: ns, Name: name}}
+	case "/api/resources/{kind}/{namespace}/{name}/related":
+		if ns == "_" {
+			ns = ""
+		}
+		return []k8s.PermissionCheck{{Verb: "get", Kind: kind, Namespace: ns, Name: name}}
+	case "/api/resources/{kind}/{namespace}/{name}/dry-run":
+		return []k8s.PermissionCheck{{Verb: "update", Kind: kind, Namespace: ns, Name: name}}
+	case "/api/secrets/{namespace}/{name}", "/api/secrets/{namespace}/{name}/keys/{key}/reveal":
+		// Values come from Radar's cache, so the caller must be allowed to read them directly
+		return []k8s.PermissionCheck{{Verb: "get", Resource: "secrets", Namespace: ns, Name: name}}
+	case "/api/costs", "/api/costs/":
+		// Node prices are cluster-wide; the breakdown covers the pods in ?namespace=
+		return append([]k8s.PermissionCheck{{Verb: "list", Resource: "nodes"}},
+			perNamespace(r, k8s.PermissionCheck{Verb: "list", Resource: "pods"})...)
+	case "/api/storage/pvcs":
+		return perNamespace(r, k8s.PermissionCheck{Verb: "list", Resource: "persistentvolumeclaims"})
+	case "/api/storage/pvcs/{namespace}/{name}/expand":
+		return []k8s.PermissionCheck{{Verb: "patch", Resource: "persistentvolumeclaims", Namespace: ns, Name: name}}
+	case "/api/rightsizing", "/api/images", "/api/images/", "/api/images/workloads":
+		return perNamespace(r, k8s.PermissionCheck{Verb: "list", Resource: "pods"})
+	case "/api/pods/{namespace}/{name}/scheduling":
+		// The analysis reports on every node and counts the pods on them
+		return []k8s.PermissionCheck{
+			{Verb: "get", Resource: "pods", Namespace: ns, Name: name},
+			{Verb: "list", Resource: "nodes"},
+			{Verb: "list", Resource: "pods"},
+		}
+	case "/api/changes/annotations":
+		// Posting a marker is like creating an Event where it's scoped (cluster-wide without ?namespace=)
+		if r.Method == http.MethodPost {
+			return []k8s.PermissionCheck{{Verb: "create", Resource: "events", Namespace: r.URL.Query().Get("namespace")}}
+		}
+		return nil
+	case "/api/pods/{namespace}/{name}/logs", "/api/pods/{namespace}/{name}/logs/stream":
+		return []k8s.PermissionCheck{{Verb: "get", Resource: "pods", Subresource: "log", Namespace: ns, Name: name}}
+	case "/api/logs/{kind}/{namespace}/{name}":
+		// Workload logs fan out to pods that aren't known yet
+		return []k8s.PermissionCheck{{Verb: "get", Resource: "pods", Subresource: "log", Namespace: ns}}
+	case "/api/pods/{namespace}/{name}/exec", "/api/pods/{namespace}/{name}/files",
+		"/api/pods/{namespace}/{name}/files/download", "/api/pods/{namespace}/{name}/files/upload":
+		// File access runs commands in the container
+		return []k8s.PermissionCheck{{Verb: "create", Resource: "pods", Subresource: "exec", Namespace: ns, Name: name}}
+	case "/api/pods/{namespace}/{name}/debug":
+		return []k8s.PermissionCheck{
+			{Verb: "patch", Resource: "pods", Subresource: "ephemeralcontainers", Namespace: ns, Name: name},
+			{Verb: "create", Resource: "pods", Subresource: "exec", Namespace: ns, Name: name},
+		}
+	case "/api/nodes", "/api/nodes/{name}":
+		// Node detail includes the pods on each node, from every namespace
+		return []k8s.PermissionCheck{{Verb: "list", Resource: "nodes"}, {Verb: "list", Resource: "pods"}}
+	case "/api/nodes/{name}/cordon", "/api/nodes/{name}/uncordon":
+		return []k8s.PermissionCheck{{Verb: "patch", Resource: "nodes", Name: name}}
+	case "/api/nodes/{name}/drain":
+		// The node's pods can be in any namespace
+		return []k8s.PermissionCheck{
+			{Verb: "patch", Resource: "nodes", Name: name},
+			{Verb: "list", Resource: "pods"},
+			{Verb: "create", Resource: "pods", Subresource: "eviction"},
+		}
+	case "/api/nodes/{name}/shell":
+		// The shell runs in a privileged debug pod, so it needs exec anywhere (the handler
+		// also checks create pods in --node-shell-namespace)
+		return []k8s.PermissionCheck{{Verb: "create", Resource: "pods", Subresource: "exec"}}
+	case "/api/cronjobs/{namespace}/{name}/trigger", "/api/jobs/{namespace}/{name}/rerun":
+		return []k8s.PermissionCheck{{Verb: "create", Group: "batch", Resource: "jobs", Namespace: ns}}
+	case "/api/cronjobs/{namespace}/{name}/suspend", "/api/cronjobs/{namespace}/{name}/resume":
+		return []k8s.PermissionCheck{{Verb: "patch", Group: "batch", Resource: "cronjobs", Namespace: ns, Name: name}}
+	case "/api/workloads/{kind}/{namespace}/{name}/restart":
+		return []k8s.PermissionCheck{{Verb: "patch", Kind: kind, Namespace: ns, Name: name}}
+	case "/api/workloads/{kind}/{namespace}/{name}/scale":
+		return []k8s.PermissionCheck{{Verb: "patch", Kind: kind, Subresource: "scale", Namespace: ns, Name: name}}
+	case "/api/argocd/applications/{namespace}/{name}/sync", "/api/argocd/applications/{namespace}/{name}/refresh",
+		"/api/argocd/applications/{namespace}/{name}/auto-sync":
+		return []k8s.PermissionCheck{{Verb: "patch", Group: "argoproj.io", Resource: "applications", Namespace: ns, Name: name}}
+	case "/api/rollouts/{namespace}/{name}/{action}":
+		// Pause patches the spec, abort and retry the status, promotions either
+		spec := k8s.PermissionCheck{Verb: "patch", Group: "argoproj.io", Resource: "rollouts", Namespace: ns, Name: name}
+		status := spec
+		status.Subresource = "status"
+		switch rctx.URLParam("action") {
+		case k8s.RolloutActionPause:
+			return []k8s.PermissionCheck{spec}
+		case k8s.RolloutActionAbort, k8s.RolloutActionRetry:
+			return []k8s.PermissionCheck{status}
+		}
+		return []k8s.PermissionCheck{spec, status}
+	case "/api/watch-namespaces", "/api/contexts/{name}", "/api/policy", "/api/policy/", "/api/signatures", "/api/signatures/":
+		// Radar-wide settings apply to every user, so changing them takes cluster admin
+		if r.Method == http.MethodGet {
+			return nil
+		}
+		return []k8s.PermissionCheck{clusterAdminCheck}
+	case "/api/helm/registries/{namespace}/{name}/login":
+		// Radar reads the Secret's registry credentials on the caller's behalf
+		return []k8s.PermissionCheck{{Verb: "get", Resource: "secrets", Namespace: ns, Name: name}}
+	}
+
+	// Helm stores releases as Secrets in the release namespace
+	if strings.HasPrefix(pattern, "/api/helm/releases") {
+		if r.Method == http.MethodGet {
+			return perNamespace(r, k8s.PermissionCheck{Verb: "list", Resource: "secrets", Namespace: ns})
+		}
+		if ns == "" {
+			ns = r.URL.Query().Get("namespace")
+		}
+		return []k8s.PermissionCheck{{Verb: "create", Resource: "secrets", Namespace: ns}}
+	}
+	return nil
+}
+
+// clusterAdminCheck passes only for users allowed every verb on every resource
+var clusterAdminCheck = k8s.PermissionCheck{Verb: "*", Group: "*", Resource: "*"}
+
+// perNamespace repeats a check for each namespace in ?namespace= (cluster-wide if none).
+// A namespace already set on the check wins.
+func perNamespace(r *http.Request, check k8s.PermissionCheck) []k8s.PermissionCheck {
+	if check.Namespace != "" {
+		return []k8s.PermissionCheck{check}
+	}
+	var checks []k8s.PermissionCheck
+	for _, ns := range strings.Split(r.URL.Query().Get("namespace"), ",") {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			c := check
+			c.Namespace = ns
+			checks = append(checks, c)
+		}
+	}
+	if len(checks) == 0 {
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// topologyResources maps topology node kinds to the resources a user must be able to list
+// to see them. Kinds not listed (e.g. Internet) are always shown.
+var topologyResources = map[topology.NodeKind]k8s.PermissionCheck{
+	topology.KindIngress:       {Group: "networking.k8s.io", Resource: "ingresses"},
+	topology.KindService:       {Resource: "services"},
+	topology.KindDeployment:    {Group: "apps", Resource: "deployments"},
+	topology.KindRollout:       {Group: "argoproj.io", Resource: "rollouts"},
+	topology.KindDaemonSet:     {Group: "apps", Resource: "daemonsets"},
+	topology.KindStatefulSet:   {Group: "apps", Resource: "statefulsets"},
+	topology.KindReplicaSet:    {Group: "apps", Resource: "replicasets"},
+	topology.KindPod:           {Resource: "pods"},
+	topology.KindPodGroup:      {Resource: "pods"},
+	topology.KindConfigMap:     {Resource: "configmaps"},
+	topology.KindSecret:        {Resource: "secrets"},
+	topology.KindHPA:           {Group: "autoscaling", Resource: "horizontalpodautoscalers"},
+	topology.KindJob:           {Group: "batch", Resource: "jobs"},
+	topology.KindCronJob:       {Group: "batch", Resource: "cronjobs"},
+	topology.KindPVC:           {Resource: "persistentvolumeclaims"},
+	topology.KindNamespace:     {Resource: "namespaces"},
+	topology.KindNetworkPolicy: {Group: "networking.k8s.io", Resource: "networkpolicies"},
+}
+
+// filterTopologyForUser drops the nodes the request's user can't list, and the edges that
+// touch them. The input is shared between clients and isn't modified.
+func filterTopologyForUser(ctx context.Context, topo *topology.Topology) *topology.Topology {
+	subject := userSubject(ctx)
+	if subject == nil || topo == nil {
+		return topo
+	}
+
+	// One check per resource and namespace the graph contains
+	type access struct {
+		resource  string
+		namespace string
+	}
+	index := make(map[access]int)
+	var checks []k8s.PermissionCheck
+	keyOf := func(n topology.Node) (access, bool) {
+		check, ok := topologyResources[n.Kind]
+		if !ok {
+			return access{}, false
+		}
+		ns, _ := n.Data["namespace"].(string)
+		key := access{resource: check.Group + "/" + check.Resource, namespace: ns}
+		if _, seen := index[key]; !seen {
+			index[key] = len(checks)
+			check.Verb, check.Namespace = "list", ns
+			checks = append(checks, check)
+		}
+		return key, true
+	}
+	for _, n := range topo.Nodes {
+		keyOf(n)
+	}
+
+	allowed := checkBatched(ctx, checks, subject)
+
+	filtered := &topology.Topology{
+		Nodes:    make([]topology.Node, 0, len(topo.Nodes)),
+		Edges:    make([]topology.Edge, 0, len(topo.Edges)),
+		Warnings: topo.Warnings,
+	}
+	visible := make(map[string]bool, len(topo.Nodes))
+	for _, n := range topo.Nodes {
+		if key, ok := keyOf(n); ok && !allowed[index[key]] {
+			continue
+		}
+		visible[n.ID] = true
+		filtered.Nodes = append(filtered.Nodes, n)
+	}
+	for _, e := range topo.Edges {
+		if visible[e.Source] && visible[e.Target] {
+			filtered.Edges = append(filtered.Edges, e)
+		}
+	}
+	if hidden := len(topo.Nodes) - len(filtered.Nodes); hidden > 0 {
+		filtered.Warnings = append(append([]string(nil), topo.Warnings...),
+			fmt.Sprintf("%d resources hidden: %s lacks permission to list them", hidden, subject.User))
+	}
+	return filtered
+}
+
+// filterSearchResultsForUser drops the search results the request's user can't list
+func filterSearchResultsForUser(ctx context.Context, results []search.Result) []search.Result {
+	subject := userSubject(ctx)
+	if subject == nil {
+		return results
+	}
+	index := make(map[k8s.PermissionCheck]int)
+	var checks []k8s.PermissionCheck
+	keys := make([]int, len(results))
+	for i, res := range results {
+		check := k8s.PermissionCheck{Verb: "list", Group: res.Group, Resource: res.Resource, Namespace: res.Namespace}
+		if _, seen := index[check]; !seen {
+			index[check] = len(checks)
+			checks = append(checks, check)
+		}
+		keys[i] = index[check]
+	}
+	allowed := checkBatched(ctx, checks, subject)
+	filtered := make([]search.Result, 0, len(results))
+	for i, res := range results {
+		if allowed[keys[i]] {
+			filtered = append(filtered, res)
+		}
+	}
+	return filtered
+}
+
+// checkBatched evaluates checks for subject in batches of the API's maximum. Checks that
+// can't be evaluated are denied (fail closed).
+func checkBatched(ctx context.Context, checks []k8s.PermissionCheck, subject *k8s.ImpersonationSubject) []bool {
+	allowed := make([]bool, len(checks))
+	for start := 0; start < len(checks); start += k8s.MaxPermissionChecks {
+		end := min(start+k8s.MaxPermissionChecks, len(checks))
+		results, err := k8s.CheckPermissions(ctx, checks[start:end], subject)
+		if err != nil {
+			continue // Fail closed: leave the batch denied
+		}
+		for i, res := range results {
+			allowed[start+i] = res.Allowed
+		}
+	}
+	return allowed
+}
+
+// userCanSeeChange reports whether the request's user may list the kind of a resource
+// change event in its namespace
+func userCanSeeChange(ctx context.Context, kind, namespace string) bool {
+	return checkUserAccess(ctx, k8s.PermissionCheck{Verb: "list", Kind: kind, Namespace: namespace}) == nil
+}
+
+// filterRelatedForUser drops the related resources the request's user can't list, and the
+// events unless the user can list events too
+func filterRelatedForUser(ctx context.Context, rel *topology.RelatedResources) *topology.RelatedResources {
+	subject := userSubject(ctx)
+	if subject == nil || rel == nil {
+		return rel
+	}
+	index := make(map[k8s.PermissionCheck]int)
+	var checks []k8s.PermissionCheck
+	keyOf := func(ref topology.ResourceRef) int {
+		check := k8s.PermissionCheck{Verb: "list", Kind: k8s.QualifiedKind(ref.Kind, ref.Group), Namespace: ref.Namespace}
+		i, seen := index[check]
+		if !seen {
+			i = len(checks)
+			index[check] = i
+			checks = append(checks, check)
+		}
+		return i
+	}
+	eventsRef := topology.ResourceRef{Kind: "Event", Namespace: rel.Resource.Namespace}
+	keyOf(eventsRef)
+	forEachRelated(rel, func(ref topology.ResourceRef) { keyOf(ref) })
+	allowed := checkBatched(ctx, checks, subject)
+	visible := func(ref topology.ResourceRef) bool { return allowed[keyOf(ref)] }
+
+	filtered := *rel
+	filtered.Owners = keepVisible(rel.Owners, func(r topology.ResourceRef) topology.ResourceRef { return r }, visible)
+	filtered.Children = keepVisible(rel.Children, func(r topology.OwnedRef) topology.ResourceRef { return r.ResourceRef }, visible)
+	filtered.References = keepVisible(rel.References, func(r topology.Reference) topology.ResourceRef { return r.ResourceRef }, visible)
+	filtered.Services = keepVisible(rel.Services, func(r topology.ResourceRef) topology.ResourceRef { return r }, visible)
+	filtered.Ingresses = keepVisible(rel.Ingresses, func(r topology.ResourceRef) topology.ResourceRef { return r }, visible)
+	filtered.HPAs = keepVisible(rel.HPAs, func(r topology.ResourceRef) topology.ResourceRef { return r }, visible)
+	filtered.PDBs = keepVisible(rel.PDBs, func(r topology.ResourceRef) topology.ResourceRef { return r }, visible)
+	filtered.Events = nil
+	if visible(eventsRef) {
+		filtered.Events = keepVisible(rel.Events, func(e topology.RelatedEvent) topology.ResourceRef { return e.Object }, visible)
+	}
+	return &filtered
+}
+
+// forEachRelated calls fn with every resource a related graph lists
+func forEachRelated(rel *topology.RelatedResources, fn func(topology.ResourceRef)) {
+	for _, refs := range [][]topology.ResourceRef{rel.Owners, rel.Services, rel.Ingresses, rel.HPAs, rel.PDBs} {
+		for _, ref := range refs {
+			fn(ref)
+		}
+	}
+	for _, c := range rel.Children {
+		fn(c.ResourceRef)
+	}
+	for _, r := range rel.References {
+		fn(r.ResourceRef)
+	}
+	for _, e := range rel.Events {
+		fn(e.Object)
+	}
+}
+
+// keepVisible returns the items whose resource is visible
+func keepVisible[T any](items []T, ref func(T) topology.ResourceRef, visible func(topology.ResourceRef) bool) []T {
+	var kept []T
+	for _, item := range items {
+		if visible(ref(item)) {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// handleDebugWatchStream streams raw informer events (before filtering) with drop
+// annotations as SSE. Restricted to local clients since it exposes every change in the cluster.
+// Query: kind, namespace, name (all optional, but kind or namespace is recommended).
+func (s *Server) handleDebugWatchStream(w http.ResponseWriter, r *http.Request) {
+	if !isLocalRequest(r) {
+		s.writeError(w, http.StatusForbidden, "watch inspector is only available to local clients")
+		return
+	}
+
+	filter := k8s.RawWatchFilter{
+		Kind:      r.URL.Query().Get("kind"),
+		Namespace: r.URL.Query().Get("namespace"),
+		Name:      r.URL.Query().Get("name"),
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		s.writeError(w, http.StatusInternalServerError, "Streaming not supported")
+		return
+	}
+
+	events, droppedCount, unsubscribe := k8s.SubscribeRawWatchEvents(filter, 512)
+	defer unsubscribe()
+	log.Printf("Watch inspector attached (kind=%q namespace=%q name=%q remote=%s)",
+		filter.Kind, filter.Namespace, filter.Name, r.RemoteAddr)
+	defer log.Printf("Watch inspector detached (remote=%s)", r.RemoteAddr)
+
+	if _, err := w.Write([]byte("event: connected\ndata: {}\n\n")); err != nil {
+		return
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(15 * time.Second)
+	defer heartbeat.Stop()
+
+	var lastDropped int64
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(ev)
+			if err != nil {
+				continue
+			}
+			if _, err := w.Write([]byte("event: watch\ndata: " + string(data) + "\n\n")); err != nil {
+				return
+			}
+			flusher.Flush()
+
+		case <-heartbeat.C:
+			// Report events the inspector itself couldn't keep up with, so they aren't mistaken for pipeline drops
+			if dropped := droppedCount(); dropped != lastDropped {
+				lastDropped = dropped
+				if _, err := fmt.Fprintf(w, "event: inspector_lag\ndata: {\"dropped\":%d}\n\n", dropped); err != nil {
+					return
+				}
+			} else if _, err := w.Write([]byte(": heartbeat\n\n")); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// isLocalRequest reports whether the request comes from a loopback address
+func isLocalRequest(r *http.Request) bool {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
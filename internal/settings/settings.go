@@ -0,0 +1,111 @@
+// Package settings persists server-side user settings (port-forward profiles, etc.)
+// as a single JSON document with one section per feature.
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Store is a JSON file of named sections. Each feature owns its section and
+// decodes it into its own types, so the store has no knowledge of their shape.
+type Store struct {
+	path string
+
+	mu       sync.RWMutex
+	sections map[string]json.RawMessage
+}
+
+var (
+	store     *Store
+	storeOnce sync.Once
+)
+
+// Init opens the settings file at path (empty = in-memory only)
+func Init(path string) error {
+	var initErr error
+	storeOnce.Do(func() {
+		s := &Store{path: path, sections: make(map[string]json.RawMessage)}
+		if err := s.load(); err != nil {
+			initErr = err
+			return
+		}
+		store = s
+	})
+	return initErr
+}
+
+// Get returns the settings store, falling back to an in-memory store if Init wasn't called
+func Get() *Store {
+	storeOnce.Do(func() {
+		store = &Store{sections: make(map[string]json.RawMessage)}
+	})
+	return store
+}
+
+// Load decodes a section into v. Returns false if the section doesn't exist.
+func (s *Store) Load(section string, v any) (bool, error) {
+	s.mu.RLock()
+	raw, ok := s.sections[section]
+	s.mu.RUnlock()
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return true, fmt.Errorf("failed to parse %s settings: %w", section, err)
+	}
+	return true, nil
+}
+
+// Save replaces a section with v and writes the file
+func (s *Store) Save(section string, v any) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.sections[section] = raw
+	s.mu.Unlock()
+	return s.save()
+}
+
+func (s *Store) load() error {
+	if s.path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read settings: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.sections); err != nil {
+		return fmt.Errorf("failed to parse settings %s: %w", s.path, err)
+	}
+	return nil
+}
+
+func (s *Store) save() error {
+	if s.path == "" {
+		return nil
+	}
+	s.mu.RLock()
+	data, err := json.MarshalIndent(s.sections, "", "  ")
+	s.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	// Write atomically so a crash never leaves a truncated file
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
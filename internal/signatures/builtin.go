@@ -0,0 +1,137 @@
+package signatures
+
+// Reasons shared by several image pull signatures
+var imagePullReasons = []string{"ImagePullBackOff", "ErrImagePull", "Failed"}
+
+// builtin is the shipped signature database. More specific signatures come first, since
+// they are listed first when several match.
+var builtin = []Signature{
+	{
+		ID:          "ecr-token-expired",
+		Title:       "ECR pull credentials expired",
+		Reasons:     imagePullReasons,
+		Message:     `(?i)\.dkr\.ecr\.[^ ]*amazonaws\.com.*(401|403|unauthorized|no basic auth credentials|authorization token has expired)`,
+		Cause:       "ECR authorization tokens are only valid for 12 hours, and the pull secret holding one has gone stale.",
+		Remediation: "Let nodes pull with their instance role (ECR credential provider) instead of a static pull secret, or refresh the secret on a schedule shorter than 12 hours.",
+		Links:       []string{"https://docs.aws.amazon.com/AmazonECR/latest/userguide/registry_auth.html"},
+	},
+	{
+		ID:          "docker-hub-rate-limit",
+		Title:       "Docker Hub pull rate limit",
+		Reasons:     imagePullReasons,
+		Message:     `(?i)toomanyrequests|pull rate limit`,
+		Cause:       "Docker Hub limits anonymous and free-tier pulls per IP, and the nodes' address has hit the limit.",
+		Remediation: "Authenticate pulls with an imagePullSecret, mirror the image to your own registry, or use a pull-through cache.",
+		Links:       []string{"https://docs.docker.com/docker-hub/usage/"},
+	},
+	{
+		ID:          "image-not-found",
+		Title:       "Image or tag does not exist",
+		Reasons:     imagePullReasons,
+		Message:     `(?i)manifest unknown|repository does not exist|name unknown|failed to resolve reference.*not found`,
+		Cause:       "The image reference points at a repository or tag the registry doesn't have, usually a typo or a tag that was never pushed.",
+		Remediation: "Check the image name and tag, and that the CI job publishing it succeeded.",
+	},
+	{
+		ID:          "image-pull-unauthorized",
+		Title:       "Registry rejected pull credentials",
+		Reasons:     imagePullReasons,
+		Message:     `(?i)unauthorized|authentication required|access denied|requested access to the resource is denied|\b40[13]\b`,
+		Cause:       "The registry requires credentials the pod doesn't have, or the ones it has are wrong.",
+		Remediation: "Add an imagePullSecrets entry (or attach one to the service account) with credentials for the registry.",
+		Links:       []string{"https://kubernetes.io/docs/tasks/configure-pod-container/pull-image-private-registry/"},
+	},
+	{
+		ID:          "exec-format-error",
+		Title:       "Image built for another CPU architecture",
+		Message:     `(?i)exec format error`,
+		Cause:       "The container's binary doesn't match the node's CPU architecture (e.g. an arm64 image on amd64 nodes).",
+		Remediation: "Publish a multi-arch image, or schedule the pod on matching nodes with a kubernetes.io/arch nodeSelector.",
+	},
+	{
+		ID:      "invalid-image-name",
+		Title:   "Invalid image reference",
+		Reasons: []string{"InvalidImageName"},
+		Cause:   "The image field isn't a valid reference, often because of an unrendered template variable or uppercase characters.",
+	},
+	{
+		ID:          "missing-config-reference",
+		Title:       "Referenced Secret or ConfigMap missing",
+		Reasons:     []string{"CreateContainerConfigError", "FailedMount", "Failed"},
+		Message:     `(?i)(secret|configmap) "?[^" ]+"? not found`,
+		Cause:       "The pod references a Secret or ConfigMap that doesn't exist in its namespace.",
+		Remediation: "Create the object, fix the name, or mark the reference optional.",
+	},
+	{
+		ID:          "oom-killed",
+		Title:       "Container exceeded its memory limit",
+		Reasons:     []string{"OOMKilled"},
+		Cause:       "The container used more memory than its limit and was killed by the kernel.",
+		Remediation: "Raise the memory limit, or look for a leak if usage grows steadily between restarts.",
+		Links:       []string{"https://kubernetes.io/docs/tasks/configure-pod-container/assign-memory-resource/"},
+	},
+	{
+		ID:          "insufficient-resources",
+		Title:       "Not enough CPU or memory to schedule",
+		Reasons:     []string{"Pending", "FailedScheduling"},
+		Message:     `(?i)insufficient (cpu|memory|ephemeral-storage)`,
+		Cause:       "No node has enough unreserved capacity for the pod's requests.",
+		Remediation: "Lower the requests, add nodes, or check why the cluster autoscaler isn't scaling up.",
+	},
+	{
+		ID:          "unbound-pvc",
+		Title:       "PersistentVolumeClaim not bound",
+		Reasons:     []string{"Pending", "FailedScheduling"},
+		Message:     `(?i)unbound (immediate )?PersistentVolumeClaims`,
+		Cause:       "A volume the pod needs has no PersistentVolume, usually because the StorageClass doesn't exist or has no provisioner.",
+		Remediation: "Check the claim's events and its storageClassName.",
+	},
+	{
+		ID:          "scheduling-constraints",
+		Title:       "No node matches scheduling constraints",
+		Reasons:     []string{"Pending", "FailedScheduling"},
+		Message:     `(?i)didn't match (pod's )?node (affinity|selector)|untolerated taint|had taint`,
+		Cause:       "The pod's nodeSelector, affinity or missing tolerations rule out every node.",
+		Remediation: "Compare the pod's nodeSelector, affinity and tolerations with the node labels and taints.",
+		Links:       []string{"https://kubernetes.io/docs/concepts/scheduling-eviction/taint-and-toleration/"},
+	},
+	{
+		ID:          "volume-multi-attach",
+		Title:       "Volume attached to another node",
+		Reasons:     []string{"FailedAttachVolume"},
+		Message:     `(?i)multi-attach error`,
+		Cause:       "A ReadWriteOnce volume is still attached to the node of the previous pod, which typically happens during a RollingUpdate.",
+		Remediation: "Use the Recreate strategy for workloads with ReadWriteOnce volumes, or wait for the old pod to terminate.",
+	},
+	{
+		ID:          "liveness-probe-failing",
+		Title:       "Liveness probe restarting the container",
+		Reasons:     []string{"Unhealthy"},
+		Message:     `(?i)liveness probe failed`,
+		Cause:       "The liveness probe fails, so the kubelet keeps restarting a container that may just be slow.",
+		Remediation: "Check the probe path and port, and give slow starters a startupProbe or a longer initialDelaySeconds.",
+	},
+	{
+		ID:          "readiness-probe-failing",
+		Title:       "Readiness probe failing",
+		Reasons:     []string{"Unhealthy"},
+		Message:     `(?i)readiness probe failed`,
+		Cause:       "The readiness probe fails, so the pod receives no traffic from its Services.",
+		Remediation: "Check the probe path and port, and whether the app's dependencies are reachable.",
+	},
+	{
+		ID:          "node-unreachable",
+		Title:       "Kubelet stopped reporting",
+		Kinds:       []string{"Node"},
+		Message:     `(?i)kubelet stopped posting node status`,
+		Cause:       "The control plane lost contact with the node: the instance was stopped, the network is partitioned, or the kubelet crashed.",
+		Remediation: "Check the instance in the cloud console and the kubelet logs on the node.",
+	},
+	{
+		ID:          "crash-loop",
+		Title:       "Container exits on startup",
+		Reasons:     []string{"CrashLoopBackOff"},
+		Cause:       "The container keeps exiting shortly after starting, usually because of bad configuration or a dependency it can't reach.",
+		Remediation: "Read the logs of the previous run (previous=true) and the last exit code.",
+	},
+}
@@ -0,0 +1,80 @@
+package signatures
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+)
+
+// Handlers provides HTTP handlers for the problem signature endpoints
+type Handlers struct{}
+
+// NewHandlers creates a new Handlers instance
+func NewHandlers() *Handlers {
+	return &Handlers{}
+}
+
+// RegisterRoutes registers signature routes on the given router
+func (h *Handlers) RegisterRoutes(r chi.Router) {
+	r.Route("/signatures", func(r chi.Router) {
+		r.Get("/", h.handleList)
+		r.Put("/", h.handlePut)
+		r.Post("/match", h.handleMatch)
+	})
+}
+
+// MatchRequest is a problem to try the signatures against
+type MatchRequest struct {
+	Kind    string `json:"kind"`
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+}
+
+// handleList returns the active signatures (user signatures first, then built-ins)
+func (h *Handlers) handleList(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, All())
+}
+
+// handlePut replaces the user signatures
+func (h *Handlers) handlePut(w http.ResponseWriter, r *http.Request) {
+	var sigs []Signature
+	if err := json.NewDecoder(r.Body).Decode(&sigs); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := Validate(sigs); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := Save(sigs); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, Custom())
+}
+
+// handleMatch returns the signatures a problem matches, for testing new signatures
+func (h *Handlers) handleMatch(w http.ResponseWriter, r *http.Request) {
+	var req MatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	matches := Match(req.Kind, []Evidence{{Reason: req.Reason, Message: req.Message}})
+	if matches == nil {
+		matches = []KnownCause{}
+	}
+	writeJSON(w, matches)
+}
+
+func writeJSON(w http.ResponseWriter, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(data)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	explorerErrors.WriteHTTP(w, status, message)
+}
@@ -0,0 +1,216 @@
+// Package signatures matches detected problems against known failure patterns (reason,
+// message and resource kind) and attaches the likely root cause and remediation, e.g.
+// "this ImagePullBackOff pattern usually means the ECR token expired". Built-in
+// signatures can be extended or overridden with user signatures stored in settings.
+package signatures
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/skyhook-io/radar/internal/settings"
+)
+
+// settingsSection is the settings store section holding user signatures
+const settingsSection = "problemSignatures"
+
+// maxEvidence caps how many reason/message pairs of one problem are matched
+const maxEvidence = 10
+
+// Signature is a known failure pattern. It matches when the resource kind is one of Kinds,
+// and a piece of evidence has one of Reasons and a message matching Message. Unset fields
+// match anything, but a signature needs Reasons or Message.
+type Signature struct {
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Kinds       []string `json:"kinds,omitempty"`
+	Reasons     []string `json:"reasons,omitempty"` // Case-insensitive, e.g. ImagePullBackOff, FailedMount
+	Message     string   `json:"message,omitempty"` // Regular expression
+	Cause       string   `json:"cause"`
+	Remediation string   `json:"remediation,omitempty"`
+	Links       []string `json:"links,omitempty"`
+	Builtin     bool     `json:"builtin,omitempty"`
+}
+
+// Evidence is a reason and message observed for a problem: its own status or one of the
+// resource's Warning events
+type Evidence struct {
+	Reason  string
+	Message string
+}
+
+// KnownCause is a signature that matched a problem
+type KnownCause struct {
+	SignatureID string   `json:"signatureId"`
+	Title       string   `json:"title"`
+	Cause       string   `json:"cause"`
+	Remediation string   `json:"remediation,omitempty"`
+	Links       []string `json:"links,omitempty"`
+	Evidence    string   `json:"evidence"` // The reason and message that matched
+}
+
+// compiled is a signature with its message pattern compiled
+type compiled struct {
+	Signature
+	message *regexp.Regexp
+}
+
+var (
+	custom   []Signature
+	active   []compiled // Custom signatures first, then built-ins they don't override
+	activeMu sync.RWMutex
+	loadOnce sync.Once
+)
+
+// load reads user signatures from the settings store on first use
+func load() {
+	loadOnce.Do(func() {
+		var stored []Signature
+		if _, err := settings.Get().Load(settingsSection, &stored); err != nil || Validate(stored) != nil {
+			stored = nil
+		}
+		activeMu.Lock()
+		custom, active = stored, compile(stored)
+		activeMu.Unlock()
+	})
+}
+
+// Custom returns the user signatures
+func Custom() []Signature {
+	load()
+	activeMu.RLock()
+	defer activeMu.RUnlock()
+	return append([]Signature(nil), custom...)
+}
+
+// All returns the active signatures in match order
+func All() []Signature {
+	load()
+	activeMu.RLock()
+	defer activeMu.RUnlock()
+	out := make([]Signature, len(active))
+	for i, c := range active {
+		out[i] = c.Signature
+	}
+	return out
+}
+
+// Save validates and persists user signatures, replacing the current ones. A user
+// signature with a built-in's ID replaces that built-in.
+func Save(sigs []Signature) error {
+	if err := Validate(sigs); err != nil {
+		return err
+	}
+	load()
+	for i := range sigs {
+		sigs[i].Builtin = false
+	}
+	if err := settings.Get().Save(settingsSection, sigs); err != nil {
+		return fmt.Errorf("failed to save signatures: %w", err)
+	}
+	activeMu.Lock()
+	custom, active = sigs, compile(sigs)
+	activeMu.Unlock()
+	return nil
+}
+
+// Validate checks IDs, required fields and message patterns
+func Validate(sigs []Signature) error {
+	ids := make(map[string]bool)
+	for i, s := range sigs {
+		field := fmt.Sprintf("signatures[%d]", i)
+		if s.ID == "" {
+			return fmt.Errorf("%s: id is required", field)
+		}
+		if ids[s.ID] {
+			return fmt.Errorf("%s: duplicate id %q", field, s.ID)
+		}
+		ids[s.ID] = true
+		if s.Cause == "" {
+			return fmt.Errorf("%s: cause is required", field)
+		}
+		if len(s.Reasons) == 0 && s.Message == "" {
+			return fmt.Errorf("%s: reasons or message is required", field)
+		}
+		if _, err := regexp.Compile(s.Message); err != nil {
+			return fmt.Errorf("%s: invalid message pattern: %w", field, err)
+		}
+	}
+	return nil
+}
+
+// compile builds the match list from user signatures and the built-ins
+func compile(user []Signature) []compiled {
+	overridden := make(map[string]bool, len(user))
+	out := make([]compiled, 0, len(user)+len(builtin))
+	for _, s := range user {
+		overridden[s.ID] = true
+		out = append(out, compiled{Signature: s, message: regexp.MustCompile(s.Message)})
+	}
+	for _, s := range builtin {
+		if overridden[s.ID] {
+			continue
+		}
+		s.Builtin = true
+		out = append(out, compiled{Signature: s, message: regexp.MustCompile(s.Message)})
+	}
+	return out
+}
+
+// Match returns the signatures matching a problem on a resource of the given kind, in
+// match order. Each signature is reported once, with the first evidence it matched.
+func Match(kind string, evidence []Evidence) []KnownCause {
+	load()
+	if len(evidence) > maxEvidence {
+		evidence = evidence[:maxEvidence]
+	}
+	activeMu.RLock()
+	defer activeMu.RUnlock()
+
+	var matches []KnownCause
+	for _, sig := range active {
+		if len(sig.Kinds) > 0 && !containsFold(sig.Kinds, kind) {
+			continue
+		}
+		for _, ev := range evidence {
+			if !sig.matches(ev) {
+				continue
+			}
+			matches = append(matches, KnownCause{
+				SignatureID: sig.ID,
+				Title:       sig.Title,
+				Cause:       sig.Cause,
+				Remediation: sig.Remediation,
+				Links:       sig.Links,
+				Evidence:    describe(ev),
+			})
+			break
+		}
+	}
+	return matches
+}
+
+func (c compiled) matches(ev Evidence) bool {
+	if len(c.Reasons) > 0 && !containsFold(c.Reasons, ev.Reason) {
+		return false
+	}
+	return c.Message == "" || c.message.MatchString(ev.Message)
+}
+
+func describe(ev Evidence) string {
+	if ev.Message == "" {
+		return ev.Reason
+	}
+	return ev.Reason + ": " + ev.Message
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
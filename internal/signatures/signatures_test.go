@@ -0,0 +1,95 @@
+package signatures
+
+import (
+	"regexp"
+	"testing"
+)
+
+func causeIDs(causes []KnownCause) []string {
+	ids := make([]string, len(causes))
+	for i, c := range causes {
+		ids[i] = c.SignatureID
+	}
+	return ids
+}
+
+func TestBuiltinPatternsCompile(t *testing.T) {
+	if err := Validate(builtin); err != nil {
+		t.Fatalf("built-in signatures are invalid: %v", err)
+	}
+	for _, s := range builtin {
+		regexp.MustCompile(s.Message)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		kind     string
+		evidence []Evidence
+		first    string // Expected primary cause ("" = no match)
+	}{
+		{"ecr token", "Pod", []Evidence{{"ImagePullBackOff",
+			`Back-off pulling image "123456789012.dkr.ecr.us-east-1.amazonaws.com/api:v2": failed to authorize: 403 Forbidden`}}, "ecr-token-expired"},
+		{"generic unauthorized", "Pod", []Evidence{{"ErrImagePull",
+			`failed to pull "ghcr.io/acme/api:v2": 401 Unauthorized`}}, "image-pull-unauthorized"},
+		{"missing tag", "Pod", []Evidence{{"ErrImagePull",
+			`rpc error: failed to resolve reference "docker.io/acme/api:v9": docker.io/acme/api:v9: not found`}}, "image-not-found"},
+		{"missing secret via event", "Pod", []Evidence{
+			{"CreateContainerConfigError", ""},
+			{"Failed", `Error: secret "db-creds" not found`},
+		}, "missing-config-reference"},
+		{"oom", "Pod", []Evidence{{"OOMKilled", ""}}, "oom-killed"},
+		{"scheduling", "Pod", []Evidence{{"Pending",
+			"0/3 nodes are available: 3 Insufficient memory."}}, "insufficient-resources"},
+		{"kind filter", "Deployment", []Evidence{{"NotReady", "Kubelet stopped posting node status."}}, ""},
+		{"no match", "Pod", []Evidence{{"Completed", ""}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Match(tt.kind, tt.evidence)
+			if tt.first == "" {
+				if len(got) != 0 {
+					t.Errorf("Match = %v, want none", causeIDs(got))
+				}
+				return
+			}
+			if len(got) == 0 || got[0].SignatureID != tt.first {
+				t.Errorf("Match = %v, want %s first", causeIDs(got), tt.first)
+			}
+		})
+	}
+}
+
+func TestCustomSignatures(t *testing.T) {
+	if err := Save([]Signature{{ID: "bad", Cause: "x"}}); err == nil {
+		t.Error("expected a signature without reasons or message to be rejected")
+	}
+	if err := Save([]Signature{{ID: "bad", Cause: "x", Message: "("}}); err == nil {
+		t.Error("expected an invalid pattern to be rejected")
+	}
+
+	err := Save([]Signature{
+		{ID: "vault-agent", Title: "Vault agent can't log in", Reasons: []string{"CrashLoopBackOff"},
+			Message: `vault.*permission denied`, Cause: "The Vault role doesn't allow this service account."},
+		{ID: "oom-killed", Reasons: []string{"OOMKilled"}, Cause: "Our JVMs ignore the container limit; set -XX:MaxRAMPercentage."},
+	})
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	defer Save(nil)
+
+	got := Match("Pod", []Evidence{{"CrashLoopBackOff", "vault: permission denied"}})
+	if ids := causeIDs(got); len(ids) != 2 || ids[0] != "vault-agent" || ids[1] != "crash-loop" {
+		t.Errorf("Match = %v, want [vault-agent crash-loop]", ids)
+	}
+	got = Match("Pod", []Evidence{{"OOMKilled", ""}})
+	if len(got) != 1 || got[0].Cause != "Our JVMs ignore the container limit; set -XX:MaxRAMPercentage." {
+		t.Errorf("user signature should replace the built-in with the same ID, got %+v", got)
+	}
+	for _, s := range All() {
+		if s.ID == "oom-killed" && s.Builtin {
+			t.Error("overridden built-in still listed")
+		}
+	}
+}
@@ -0,0 +1,221 @@
+// Package testenv runs Radar's subsystems against a cluster in tests. Fixtures (see
+// App, CronJob and Generate) are served by the replay API server, so the k8s clients,
+// informers, resource cache and timeline pipeline run exactly as they do against a real
+// API server, without a cluster or API server binaries.
+//
+// Setting RADAR_TEST_KUBECONFIG runs the same tests against a real cluster instead, e.g.
+// a kind cluster in CI. Fixtures can't be loaded there, so tests that need them skip.
+package testenv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/replay"
+	"github.com/skyhook-io/radar/internal/timeline"
+)
+
+// LiveKubeconfigEnv names the variable pointing tests at a real cluster
+const LiveKubeconfigEnv = "RADAR_TEST_KUBECONFIG"
+
+// ErrLive is returned by Load against a real cluster
+var ErrLive = errors.New("fixtures can't be loaded into a live cluster")
+
+// Cluster is the cluster the k8s package is connected to
+type Cluster struct {
+	server     *replay.APIServer // nil for a live cluster
+	kubeconfig string
+	mu         sync.Mutex // Serializes Load
+}
+
+var (
+	startOnce sync.Once
+	started   *Cluster
+	startErr  error
+)
+
+// Start connects the k8s package to a fake cluster serving objs and starts the resource
+// cache, resource discovery and an in-memory timeline, as the server does at startup.
+//
+// The k8s clients are process-wide, so all tests in a package share one Cluster. Later
+// calls return it with objs loaded in place of the previous fixtures.
+func Start(objs ...runtime.Object) (*Cluster, error) {
+	startOnce.Do(func() {
+		started, startErr = start()
+	})
+	if startErr != nil {
+		return nil, startErr
+	}
+	if started.server == nil {
+		return started, nil
+	}
+	if err := started.Load(objs...); err != nil {
+		return nil, err
+	}
+	return started, nil
+}
+
+func start() (*Cluster, error) {
+	c := &Cluster{kubeconfig: os.Getenv(LiveKubeconfigEnv)}
+	opts := k8s.InitOptions{KubeconfigPath: c.kubeconfig, SkipInCluster: true}
+	if c.kubeconfig == "" {
+		server, err := replay.ServeBundle(&replay.Bundle{Version: replay.BundleVersion, Cluster: "testenv"})
+		if err != nil {
+			return nil, err
+		}
+		c.server, c.kubeconfig = server, server.Kubeconfig()
+		opts.KubeconfigPath = c.kubeconfig
+		opts.ContentType = "application/json" // The replay server doesn't decode protobuf
+	}
+
+	if err := k8s.Initialize(opts); err != nil {
+		return nil, fmt.Errorf("failed to connect to test cluster: %w", err)
+	}
+	if err := timeline.InitStore(timeline.StoreConfig{Type: timeline.StoreTypeMemory, Scope: "testenv"}); err != nil {
+		return nil, err
+	}
+	if c.server == nil {
+		return c, startCaches()
+	}
+	return c, nil
+}
+
+// startCaches starts what reads the cluster, in the order the server does
+func startCaches() error {
+	k8s.InitFeatureDetection()
+	if err := k8s.InitResourceCache(); err != nil {
+		return err
+	}
+	k8s.InitResourceDiscovery()
+	return k8s.InitDynamicResourceCache(k8s.GetResourceCache().ChangesRaw())
+}
+
+func stopCaches() {
+	k8s.ResetDynamicResourceCache()
+	k8s.ResetResourceDiscovery()
+	k8s.ResetResourceCache()
+	k8s.ResetFeatureDetection()
+}
+
+// Setup is Start for one test. It fails the test if the cluster can't start, and skips
+// it when objs are given but tests run against a live cluster.
+func Setup(t testing.TB, objs ...runtime.Object) *Cluster {
+	t.Helper()
+	if len(objs) > 0 && os.Getenv(LiveKubeconfigEnv) != "" {
+		t.Skipf("fixtures can't be loaded with %s set", LiveKubeconfigEnv)
+	}
+	c, err := Start(objs...)
+	if err != nil {
+		t.Fatalf("testenv: %v", err)
+	}
+	return c
+}
+
+// Live reports whether tests run against a real cluster
+func (c *Cluster) Live() bool {
+	return c.server == nil
+}
+
+// Kubeconfig returns the path of the kubeconfig for the cluster
+func (c *Cluster) Kubeconfig() string {
+	return c.kubeconfig
+}
+
+// Load replaces the fake cluster's objects with objs and restarts the caches so they
+// list them. The timeline is cleared, so it only holds what the new objects produce.
+func (c *Cluster) Load(objs ...runtime.Object) error {
+	if c.Live() {
+		return ErrLive
+	}
+	b, err := NewBundle(objs...)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stopCaches()
+	c.server.SetBundle(b)
+	timeline.ResetStore()
+	if err := timeline.InitStore(timeline.StoreConfig{Type: timeline.StoreTypeMemory, Scope: "testenv"}); err != nil {
+		return err
+	}
+	return startCaches()
+}
+
+// WaitForTimeline polls the timeline until match returns true for its events, for
+// assertions on the asynchronous recording pipeline
+func WaitForTimeline(ctx context.Context, opts timeline.QueryOptions, match func([]timeline.TimelineEvent) bool) ([]timeline.TimelineEvent, error) {
+	ticker := time.NewTicker(20 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		events, err := timeline.QueryEvents(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		if match(events) {
+			return events, nil
+		}
+		select {
+		case <-ctx.Done():
+			return events, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// NewBundle converts objects to a replay bundle, grouped by resource. Typed objects
+// must be registered in client-go's scheme; unstructured objects (custom resources)
+// need their apiVersion and kind set.
+func NewBundle(objs ...runtime.Object) (*replay.Bundle, error) {
+	sets := make(map[string]*replay.ResourceSet)
+	for _, obj := range objs {
+		gvk := obj.GetObjectKind().GroupVersionKind()
+		if gvk.Kind == "" {
+			kinds, _, err := scheme.Scheme.ObjectKinds(obj)
+			if err != nil {
+				return nil, fmt.Errorf("unknown fixture type %T: %w", obj, err)
+			}
+			gvk = kinds[0]
+		}
+		item, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %s fixture: %w", gvk.Kind, err)
+		}
+		u := &unstructured.Unstructured{Object: item}
+		u.SetGroupVersionKind(gvk)
+
+		plural, _ := meta.UnsafeGuessKindToResource(gvk)
+		key := plural.String()
+		rs, ok := sets[key]
+		if !ok {
+			rs = &replay.ResourceSet{Group: gvk.Group, Version: gvk.Version, Resource: plural.Resource, Kind: gvk.Kind}
+			sets[key] = rs
+		}
+		rs.Namespaced = rs.Namespaced || u.GetNamespace() != ""
+		rs.Items = append(rs.Items, u.Object)
+	}
+
+	b := &replay.Bundle{Version: replay.BundleVersion, Cluster: "testenv", CapturedAt: Epoch}
+	for _, rs := range sets {
+		b.Resources = append(b.Resources, *rs)
+	}
+	sort.Slice(b.Resources, func(i, j int) bool {
+		return strings.Compare(b.Resources[i].GroupVersion()+"/"+b.Resources[i].Resource,
+			b.Resources[j].GroupVersion()+"/"+b.Resources[j].Resource) < 0
+	})
+	return b, nil
+}
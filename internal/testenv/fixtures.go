@@ -0,0 +1,389 @@
+package testenv
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// Epoch is the creation time of every fixture, so generated objects (and anything
+// derived from them, like ages or timeline events) are the same on every run
+var Epoch = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// UID returns the UID fixtures give an object, for asserting on owner references
+func UID(kind, namespace, name string) types.UID {
+	return types.UID(fmt.Sprintf("%s-%s-%s", strings.ToLower(kind), namespace, name))
+}
+
+func objectMeta(kind, namespace, name string, labels map[string]string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:              name,
+		Namespace:         namespace,
+		UID:               UID(kind, namespace, name),
+		ResourceVersion:   "1",
+		CreationTimestamp: metav1.NewTime(Epoch),
+		Labels:            labels,
+	}
+}
+
+func controllerRef(kind, apiVersion, namespace, name string) []metav1.OwnerReference {
+	controller := true
+	return []metav1.OwnerReference{{
+		APIVersion: apiVersion,
+		Kind:       kind,
+		Name:       name,
+		UID:        UID(kind, namespace, name),
+		Controller: &controller,
+	}}
+}
+
+// templateHash stands in for the pod-template-hash a Deployment controller computes
+func templateHash(namespace, name, image string) string {
+	h := fnv.New32a()
+	h.Write([]byte(namespace + "/" + name + "/" + image))
+	return fmt.Sprintf("%08x", h.Sum32())[:8]
+}
+
+// Namespace returns an active namespace
+func Namespace(name string) *corev1.Namespace {
+	ns := &corev1.Namespace{ObjectMeta: objectMeta("Namespace", "", name, map[string]string{"kubernetes.io/metadata.name": name})}
+	ns.Status.Phase = corev1.NamespaceActive
+	return ns
+}
+
+// Node returns a Ready node with the given allocatable CPU and memory (e.g. "4", "16Gi")
+func Node(name, cpu, memory string) *corev1.Node {
+	node := &corev1.Node{ObjectMeta: objectMeta("Node", "", name, map[string]string{
+		"kubernetes.io/hostname":           name,
+		"kubernetes.io/os":                 "linux",
+		"node.kubernetes.io/instance-type": "m5.xlarge",
+	})}
+	capacity := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse(cpu),
+		corev1.ResourceMemory: resource.MustParse(memory),
+		corev1.ResourcePods:   resource.MustParse("110"),
+	}
+	node.Status = corev1.NodeStatus{
+		Capacity:    capacity,
+		Allocatable: capacity,
+		Conditions: []corev1.NodeCondition{
+			{Type: corev1.NodeReady, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(Epoch)},
+			{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionFalse},
+			{Type: corev1.NodeDiskPressure, Status: corev1.ConditionFalse},
+		},
+		NodeInfo: corev1.NodeSystemInfo{KubeletVersion: "v1.30.0", OperatingSystem: "linux", Architecture: "amd64"},
+	}
+	return node
+}
+
+type appConfig struct {
+	replicas  int32
+	image     string
+	port      int32
+	host      string
+	configMap bool
+	crashLoop bool
+	hpaMin    int32
+	hpaMax    int32
+	node      string
+}
+
+// AppOption customizes App
+type AppOption func(*appConfig)
+
+// WithReplicas sets the Deployment's replicas, and how many Pods are created
+func WithReplicas(n int32) AppOption { return func(c *appConfig) { c.replicas = n } }
+
+// WithImage sets the container image
+func WithImage(image string) AppOption { return func(c *appConfig) { c.image = image } }
+
+// WithIngress adds an Ingress routing host to the app's Service
+func WithIngress(host string) AppOption { return func(c *appConfig) { c.host = host } }
+
+// WithConfigMap adds a ConfigMap the pods load with envFrom
+func WithConfigMap() AppOption { return func(c *appConfig) { c.configMap = true } }
+
+// WithCrashLoop makes every Pod's container crash-loop
+func WithCrashLoop() AppOption { return func(c *appConfig) { c.crashLoop = true } }
+
+// WithHPA adds an HPA scaling the Deployment between min and max replicas
+func WithHPA(min, max int32) AppOption {
+	return func(c *appConfig) { c.hpaMin, c.hpaMax = min, max }
+}
+
+// OnNode schedules the app's Pods on a node
+func OnNode(node string) AppOption { return func(c *appConfig) { c.node = node } }
+
+// App returns a Deployment as the controllers would have left it: its ReplicaSet, the
+// Pods that ReplicaSet owns, and a Service selecting them, plus whatever the options add.
+// Objects are linked by labels, selectors and owner references like in a real cluster.
+func App(namespace, name string, opts ...AppOption) []runtime.Object {
+	cfg := appConfig{replicas: 2, image: "ghcr.io/example/" + name + ":1.0.0", port: 8080}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	selector := map[string]string{"app.kubernetes.io/name": name}
+	labels := map[string]string{"app.kubernetes.io/name": name, "app.kubernetes.io/version": "1.0.0"}
+	hash := templateHash(namespace, name, cfg.image)
+
+	container := corev1.Container{
+		Name:  name,
+		Image: cfg.image,
+		Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: cfg.port}},
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m"), corev1.ResourceMemory: resource.MustParse("128Mi")},
+			Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+		},
+	}
+	var objs []runtime.Object
+	if cfg.configMap {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: objectMeta("ConfigMap", namespace, name+"-config", labels),
+			Data:       map[string]string{"LOG_LEVEL": "info"},
+		}
+		container.EnvFrom = []corev1.EnvFromSource{{ConfigMapRef: &corev1.ConfigMapEnvSource{
+			LocalObjectReference: corev1.LocalObjectReference{Name: cm.Name},
+		}}}
+		objs = append(objs, cm)
+	}
+	template := corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: labels},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{container}},
+	}
+
+	deploy := &appsv1.Deployment{
+		ObjectMeta: objectMeta("Deployment", namespace, name, labels),
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &cfg.replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: selector},
+			Template: template,
+		},
+	}
+	deploy.Generation = 1
+	ready := cfg.replicas
+	if cfg.crashLoop {
+		ready = 0
+	}
+	deploy.Status = appsv1.DeploymentStatus{
+		ObservedGeneration:  1,
+		Replicas:            cfg.replicas,
+		UpdatedReplicas:     cfg.replicas,
+		ReadyReplicas:       ready,
+		AvailableReplicas:   ready,
+		UnavailableReplicas: cfg.replicas - ready,
+		Conditions: []appsv1.DeploymentCondition{{
+			Type: appsv1.DeploymentAvailable, Status: conditionStatus(ready == cfg.replicas), LastTransitionTime: metav1.NewTime(Epoch),
+		}},
+	}
+
+	rsName := name + "-" + hash
+	rsLabels := withLabel(labels, "pod-template-hash", hash)
+	rs := &appsv1.ReplicaSet{
+		ObjectMeta: objectMeta("ReplicaSet", namespace, rsName, rsLabels),
+		Spec: appsv1.ReplicaSetSpec{
+			Replicas: &cfg.replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: withLabel(selector, "pod-template-hash", hash)},
+			Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: rsLabels}, Spec: template.Spec},
+		},
+		Status: appsv1.ReplicaSetStatus{Replicas: cfg.replicas, ReadyReplicas: ready, AvailableReplicas: ready},
+	}
+	rs.OwnerReferences = controllerRef("Deployment", "apps/v1", namespace, name)
+	rs.Annotations = map[string]string{"deployment.kubernetes.io/revision": "1"}
+	objs = append(objs, deploy, rs)
+
+	for i := int32(0); i < cfg.replicas; i++ {
+		objs = append(objs, pod(namespace, fmt.Sprintf("%s-%c%d", rsName, 'a'+i%26, i), rsName, rsLabels, template.Spec, cfg))
+	}
+
+	objs = append(objs, &corev1.Service{
+		ObjectMeta: objectMeta("Service", namespace, name, labels),
+		Spec: corev1.ServiceSpec{
+			Type:      corev1.ServiceTypeClusterIP,
+			Selector:  selector,
+			ClusterIP: clusterIP(namespace, name),
+			Ports:     []corev1.ServicePort{{Name: "http", Port: 80, TargetPort: intstr.FromString("http"), Protocol: corev1.ProtocolTCP}},
+		},
+	})
+
+	if cfg.host != "" {
+		pathType := networkingv1.PathTypePrefix
+		objs = append(objs, &networkingv1.Ingress{
+			ObjectMeta: objectMeta("Ingress", namespace, name, labels),
+			Spec: networkingv1.IngressSpec{Rules: []networkingv1.IngressRule{{
+				Host: cfg.host,
+				IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+					Paths: []networkingv1.HTTPIngressPath{{
+						Path:     "/",
+						PathType: &pathType,
+						Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+							Name: name, Port: networkingv1.ServiceBackendPort{Name: "http"},
+						}},
+					}},
+				}},
+			}}},
+		})
+	}
+
+	if cfg.hpaMax > 0 {
+		objs = append(objs, &autoscalingv2.HorizontalPodAutoscaler{
+			ObjectMeta: objectMeta("HorizontalPodAutoscaler", namespace, name, labels),
+			Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+				ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: name},
+				MinReplicas:    &cfg.hpaMin,
+				MaxReplicas:    cfg.hpaMax,
+			},
+			Status: autoscalingv2.HorizontalPodAutoscalerStatus{CurrentReplicas: cfg.replicas, DesiredReplicas: cfg.replicas},
+		})
+	}
+	return objs
+}
+
+func pod(namespace, name, owner string, labels map[string]string, spec corev1.PodSpec, cfg appConfig) *corev1.Pod {
+	p := &corev1.Pod{ObjectMeta: objectMeta("Pod", namespace, name, labels), Spec: *spec.DeepCopy()}
+	p.OwnerReferences = controllerRef("ReplicaSet", "apps/v1", namespace, owner)
+	p.Spec.NodeName = cfg.node
+
+	started := metav1.NewTime(Epoch.Add(10 * time.Second))
+	status := corev1.ContainerStatus{Name: spec.Containers[0].Name, Image: spec.Containers[0].Image, Ready: true, Started: boolPtr(true)}
+	status.State.Running = &corev1.ContainerStateRunning{StartedAt: started}
+	if cfg.crashLoop {
+		status.Ready, status.Started = false, boolPtr(false)
+		status.RestartCount = 12
+		status.State = corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+			Reason:  "CrashLoopBackOff",
+			Message: "back-off 5m0s restarting failed container",
+		}}
+		status.LastTerminationState.Terminated = &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error", FinishedAt: started}
+	}
+	p.Status = corev1.PodStatus{
+		Phase:             corev1.PodRunning,
+		StartTime:         &started,
+		PodIP:             "10.244.0.10",
+		ContainerStatuses: []corev1.ContainerStatus{status},
+		Conditions: []corev1.PodCondition{
+			{Type: corev1.PodScheduled, Status: corev1.ConditionTrue},
+			{Type: corev1.PodReady, Status: conditionStatus(status.Ready)},
+		},
+	}
+	return p
+}
+
+// CronJob returns a CronJob with one completed Job and that Job's succeeded Pod
+func CronJob(namespace, name, schedule string) []runtime.Object {
+	labels := map[string]string{"app.kubernetes.io/name": name}
+	spec := corev1.PodSpec{
+		RestartPolicy: corev1.RestartPolicyOnFailure,
+		Containers:    []corev1.Container{{Name: name, Image: "ghcr.io/example/" + name + ":1.0.0"}},
+	}
+	cj := &batchv1.CronJob{
+		ObjectMeta: objectMeta("CronJob", namespace, name, labels),
+		Spec: batchv1.CronJobSpec{
+			Schedule: schedule,
+			JobTemplate: batchv1.JobTemplateSpec{Spec: batchv1.JobSpec{Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels}, Spec: spec,
+			}}},
+		},
+	}
+	lastRun := metav1.NewTime(Epoch.Add(time.Hour))
+	cj.Status.LastScheduleTime = &lastRun
+	cj.Status.LastSuccessfulTime = &lastRun
+
+	jobName := fmt.Sprintf("%s-%d", name, lastRun.Unix()/60)
+	job := &batchv1.Job{
+		ObjectMeta: objectMeta("Job", namespace, jobName, labels),
+		Spec:       batchv1.JobSpec{Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: labels}, Spec: spec}},
+		Status: batchv1.JobStatus{
+			Succeeded:      1,
+			StartTime:      &lastRun,
+			CompletionTime: &lastRun,
+			Conditions:     []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}},
+		},
+	}
+	job.OwnerReferences = controllerRef("CronJob", "batch/v1", namespace, name)
+
+	p := &corev1.Pod{ObjectMeta: objectMeta("Pod", namespace, jobName+"-x1", withLabel(labels, "job-name", jobName)), Spec: spec}
+	p.OwnerReferences = controllerRef("Job", "batch/v1", namespace, jobName)
+	p.Status = corev1.PodStatus{Phase: corev1.PodSucceeded, StartTime: &lastRun}
+	return []runtime.Object{cj, job, p}
+}
+
+// Scale sizes a generated cluster
+type Scale struct {
+	Namespaces       int
+	AppsPerNamespace int
+	Nodes            int
+	Replicas         int32 // Per app; 2 if zero
+}
+
+// Generate returns a cluster of nodes and namespaces full of apps, for tests that need
+// volume rather than specific shapes. Every tenth app crash-loops and every fifth has
+// an Ingress, so health and routing code has something to find.
+func Generate(s Scale) []runtime.Object {
+	replicas := s.Replicas
+	if replicas == 0 {
+		replicas = 2
+	}
+	var objs []runtime.Object
+	for i := 0; i < s.Nodes; i++ {
+		objs = append(objs, Node(fmt.Sprintf("node-%d", i), "8", "32Gi"))
+	}
+	n := 0
+	for i := 0; i < s.Namespaces; i++ {
+		ns := fmt.Sprintf("team-%d", i)
+		objs = append(objs, Namespace(ns))
+		for j := 0; j < s.AppsPerNamespace; j++ {
+			name := fmt.Sprintf("svc-%d", j)
+			opts := []AppOption{WithReplicas(replicas), WithConfigMap()}
+			if s.Nodes > 0 {
+				opts = append(opts, OnNode(fmt.Sprintf("node-%d", n%s.Nodes)))
+			}
+			if n%10 == 9 {
+				opts = append(opts, WithCrashLoop())
+			}
+			if n%5 == 0 {
+				opts = append(opts, WithIngress(name+"."+ns+".example.com"))
+			}
+			objs = append(objs, App(ns, name, opts...)...)
+			n++
+		}
+	}
+	return objs
+}
+
+// clusterIP returns a stable Service IP in 10.96.0.0/16
+func clusterIP(namespace, name string) string {
+	h := fnv.New32a()
+	h.Write([]byte(namespace + "/" + name))
+	sum := h.Sum32()
+	return fmt.Sprintf("10.96.%d.%d", byte(sum>>8), byte(sum)|1)
+}
+
+func withLabel(labels map[string]string, key, value string) map[string]string {
+	out := make(map[string]string, len(labels)+1)
+	for k, v := range labels {
+		out[k] = v
+	}
+	out[key] = value
+	return out
+}
+
+func conditionStatus(ok bool) corev1.ConditionStatus {
+	if ok {
+		return corev1.ConditionTrue
+	}
+	return corev1.ConditionFalse
+}
+
+func boolPtr(b bool) *bool { return &b }
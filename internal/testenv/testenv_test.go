@@ -0,0 +1,91 @@
+package testenv
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/timeline"
+)
+
+func TestNewBundle(t *testing.T) {
+	b, err := NewBundle(append(App("shop", "web", WithIngress("shop.example.com"), WithHPA(2, 5)), Namespace("shop"))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]int{}
+	for _, rs := range b.Resources {
+		got[rs.GroupVersion()+" "+rs.Resource] = len(rs.Items)
+		if rs.Resource == "namespaces" && rs.Namespaced {
+			t.Error("namespaces should be cluster-scoped")
+		}
+	}
+	want := map[string]int{
+		"apps/v1 deployments":                     1,
+		"apps/v1 replicasets":                     1,
+		"v1 pods":                                 2,
+		"v1 services":                             1,
+		"v1 namespaces":                           1,
+		"networking.k8s.io/v1 ingresses":          1,
+		"autoscaling/v2 horizontalpodautoscalers": 1,
+	}
+	for k, n := range want {
+		if got[k] != n {
+			t.Errorf("%s: got %d objects, want %d", k, got[k], n)
+		}
+	}
+}
+
+func TestClusterServesFixtures(t *testing.T) {
+	objs := append(App("shop", "web", WithReplicas(3), WithConfigMap()), CronJob("shop", "report", "0 * * * *")...)
+	Setup(t, objs...)
+
+	cache := k8s.GetResourceCache()
+	pods, err := cache.Pods().Pods("shop").List(labels.Everything())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pods) != 4 { // 3 replicas plus the CronJob's pod
+		t.Errorf("got %d pods, want 4", len(pods))
+	}
+	rs, err := cache.ReplicaSets().ReplicaSets("shop").List(labels.Everything())
+	if err != nil || len(rs) != 1 {
+		t.Fatalf("replicasets = %v, %v", rs, err)
+	}
+	if owner := rs[0].OwnerReferences[0]; owner.Kind != "Deployment" || owner.UID != UID("Deployment", "shop", "web") {
+		t.Errorf("replicaset owner = %+v", owner)
+	}
+
+	// Loading new fixtures replaces the old ones
+	Setup(t, App("shop", "api", WithReplicas(1))...)
+	cache = k8s.GetResourceCache()
+	deploys, err := cache.Deployments().List(labels.Everything())
+	if err != nil || len(deploys) != 1 || deploys[0].Name != "api" {
+		t.Errorf("deployments after reload = %v, %v", deploys, err)
+	}
+}
+
+func TestTimelineRecordsFixtures(t *testing.T) {
+	Setup(t, App("shop", "worker", WithReplicas(1))...)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	created := func(kind string, events []timeline.TimelineEvent) bool {
+		for _, e := range events {
+			if e.Kind == kind && e.Name == "worker" && e.Reason == "created" {
+				return true
+			}
+		}
+		return false
+	}
+	opts := timeline.QueryOptions{Namespace: "shop", Limit: 100, IncludeManaged: true}
+	events, err := WaitForTimeline(ctx, opts, func(events []timeline.TimelineEvent) bool {
+		return created("Deployment", events) && created("Service", events)
+	})
+	if err != nil {
+		t.Fatalf("timeline never recorded the fixtures: %v (%d events)", err, len(events))
+	}
+}
@@ -0,0 +1,198 @@
+package timeline
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// aggregateGap is how long an object and reason can go quiet before a recurrence
+	// starts a new timeline entry instead of extending the last one
+	aggregateGap = time.Hour
+	// maxOccurrences bounds the raw Events kept on an aggregate
+	maxOccurrences = 20
+	// aggregateSweepInterval is how often windows that went quiet are forgotten
+	aggregateSweepInterval = 10 * time.Minute
+)
+
+// EventAggregator folds K8s Events into one timeline entry per involved object, reason
+// and type. An Event's count increments and new Event objects for the same recurrence
+// update that entry in place, instead of each adding a row to the store.
+type EventAggregator struct {
+	mu        sync.Mutex
+	windows   map[string]*eventWindow
+	lastSweep time.Time
+}
+
+// eventWindow is the aggregate currently being extended for one object and reason
+type eventWindow struct {
+	id          string
+	firstSeen   time.Time
+	lastSeen    time.Time
+	counts      map[string]int32 // Event UID -> its latest count (bounded by the Events the informer holds)
+	total       int32
+	message     string
+	occurrences []EventOccurrence
+}
+
+// NewEventAggregator creates an empty aggregator
+func NewEventAggregator() *EventAggregator {
+	return &EventAggregator{windows: make(map[string]*eventWindow)}
+}
+
+var k8sEventAggregator = NewEventAggregator()
+
+// FoldK8sEvent returns the aggregated timeline event a corev1.Event belongs to, updated
+// with it, ready to be recorded over the previous version
+func FoldK8sEvent(event *corev1.Event, owner *OwnerInfo) TimelineEvent {
+	return k8sEventAggregator.Fold(event, owner, time.Now())
+}
+
+// resetK8sEventAggregator forgets every window, e.g. when switching clusters
+func resetK8sEventAggregator() {
+	k8sEventAggregator.Reset()
+}
+
+// Reset forgets every window
+func (a *EventAggregator) Reset() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.windows = make(map[string]*eventWindow)
+}
+
+// Fold adds an Event (new or with a higher count) to its window and returns the window
+// as a timeline event
+func (a *EventAggregator) Fold(event *corev1.Event, owner *OwnerInfo, now time.Time) TimelineEvent {
+	te := NewK8sEventTimelineEvent(event, owner)
+	first, last := eventSpan(event)
+	count := eventCount(event)
+	key := fmt.Sprintf("%s/%s/%s/%s/%s", event.Namespace, event.InvolvedObject.Kind, event.InvolvedObject.Name, event.Reason, event.Type)
+	uid := string(event.UID)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.sweep(now)
+
+	w := a.windows[key]
+	if _, known := w.tracks(uid); w == nil || (!known && first.Sub(w.lastSeen) > aggregateGap) {
+		w = &eventWindow{id: aggregateID(key, first), firstSeen: first, counts: make(map[string]int32)}
+		a.windows[key] = w
+	}
+
+	// Counts only grow; the window holds each Event's latest so increments add the difference
+	if prev, _ := w.tracks(uid); count > prev {
+		w.total += count - prev
+		w.counts[uid] = count
+	}
+	if first.Before(w.firstSeen) {
+		w.firstSeen = first
+	}
+	if !last.Before(w.lastSeen) {
+		w.lastSeen = last
+		w.message = event.Message
+	}
+	w.addOccurrence(EventOccurrence{Name: event.Name, Timestamp: last, Count: count, Message: event.Message})
+
+	te.ID = w.id
+	te.Timestamp = w.lastSeen
+	te.Message = w.message
+	te.Count = w.total
+	te.Aggregate = &EventAggregate{
+		FirstSeen:   w.firstSeen,
+		LastSeen:    w.lastSeen,
+		RatePerHour: aggregateRate(w.total, w.firstSeen, w.lastSeen),
+		Occurrences: append([]EventOccurrence(nil), w.occurrences...),
+	}
+	return te
+}
+
+// tracks returns the count last folded for an Event, if the window has seen it
+func (w *eventWindow) tracks(uid string) (int32, bool) {
+	if w == nil {
+		return 0, false
+	}
+	c, ok := w.counts[uid]
+	return c, ok
+}
+
+// addOccurrence records or updates an Event among the most recent occurrences
+func (w *eventWindow) addOccurrence(o EventOccurrence) {
+	for i := range w.occurrences {
+		if w.occurrences[i].Name == o.Name {
+			w.occurrences[i] = o
+			o.Name = "" // Updated in place
+			break
+		}
+	}
+	if o.Name != "" {
+		w.occurrences = append(w.occurrences, o)
+	}
+	sort.SliceStable(w.occurrences, func(i, j int) bool { return w.occurrences[i].Timestamp.Before(w.occurrences[j].Timestamp) })
+	if len(w.occurrences) > maxOccurrences {
+		w.occurrences = w.occurrences[len(w.occurrences)-maxOccurrences:]
+	}
+}
+
+// sweep forgets windows that went quiet, so a recurrence starts a new entry without
+// the window lingering in memory. Callers hold a.mu.
+func (a *EventAggregator) sweep(now time.Time) {
+	if now.Sub(a.lastSweep) < aggregateSweepInterval {
+		return
+	}
+	a.lastSweep = now
+	for key, w := range a.windows {
+		if now.Sub(w.lastSeen) > aggregateGap {
+			delete(a.windows, key)
+		}
+	}
+}
+
+// aggregateID is deterministic, so a restart rebuilding the window from the informer's
+// initial list updates the stored entry rather than adding another
+func aggregateID(key string, firstSeen time.Time) string {
+	sum := sha256.Sum256(fmt.Appendf(nil, "%s@%d", key, firstSeen.Truncate(time.Hour).Unix()))
+	return fmt.Sprintf("k8sevt-%x", sum[:12])
+}
+
+// aggregateRate is occurrences per hour, or 0 until they span at least a minute
+func aggregateRate(total int32, first, last time.Time) float64 {
+	span := last.Sub(first)
+	if span < time.Minute {
+		return 0
+	}
+	return float64(total) / span.Hours()
+}
+
+// eventSpan returns when an Event first and last occurred, falling back through the
+// fields different reporters fill in
+func eventSpan(event *corev1.Event) (first, last time.Time) {
+	last = event.LastTimestamp.Time
+	if event.Series != nil && !event.Series.LastObservedTime.IsZero() {
+		last = event.Series.LastObservedTime.Time
+	}
+	first = event.FirstTimestamp.Time
+	if first.IsZero() {
+		first = event.EventTime.Time
+	}
+	if first.IsZero() {
+		first = event.CreationTimestamp.Time
+	}
+	if last.IsZero() || last.Before(first) {
+		last = first
+	}
+	return first, last
+}
+
+// eventCount returns how many times an Event occurred, at least once
+func eventCount(event *corev1.Event) int32 {
+	count := event.Count
+	if event.Series != nil && event.Series.Count > count {
+		count = event.Series.Count
+	}
+	return max(count, 1)
+}
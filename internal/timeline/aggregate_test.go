@@ -0,0 +1,136 @@
+package timeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func backOffEvent(name string, count int32, first, last time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "shop", UID: types.UID("uid-" + name)},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "shop", Name: "api-0"},
+		Reason:         "BackOff",
+		Type:           corev1.EventTypeWarning,
+		Message:        "Back-off restarting failed container " + name,
+		Count:          count,
+		FirstTimestamp: metav1.NewTime(first),
+		LastTimestamp:  metav1.NewTime(last),
+	}
+}
+
+func TestEventAggregator_FoldsRecurrences(t *testing.T) {
+	a := NewEventAggregator()
+	base := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	first := a.Fold(backOffEvent("a", 1, base, base), nil, base)
+	if first.Count != 1 || first.Aggregate == nil || !first.Aggregate.FirstSeen.Equal(base) {
+		t.Fatalf("first fold = count %d, aggregate %+v", first.Count, first.Aggregate)
+	}
+
+	// The same Event's count increments, then a second Event object continues the recurrence
+	a.Fold(backOffEvent("a", 5, base, base.Add(20*time.Minute)), nil, base.Add(20*time.Minute))
+	got := a.Fold(backOffEvent("b", 3, base.Add(40*time.Minute), base.Add(time.Hour)), nil, base.Add(time.Hour))
+
+	if got.ID != first.ID {
+		t.Errorf("recurrence got a new ID %s, want %s", got.ID, first.ID)
+	}
+	if got.Count != 8 {
+		t.Errorf("Count = %d, want 8", got.Count)
+	}
+	if !got.Timestamp.Equal(base.Add(time.Hour)) || !got.Aggregate.LastSeen.Equal(base.Add(time.Hour)) {
+		t.Errorf("Timestamp = %s, LastSeen = %s, want the latest occurrence", got.Timestamp, got.Aggregate.LastSeen)
+	}
+	if got.Aggregate.RatePerHour != 8 {
+		t.Errorf("RatePerHour = %v, want 8", got.Aggregate.RatePerHour)
+	}
+	if len(got.Aggregate.Occurrences) != 2 || got.Aggregate.Occurrences[0].Count != 5 || got.Aggregate.Occurrences[1].Name != "b" {
+		t.Errorf("Occurrences = %+v, want a (x5) then b", got.Aggregate.Occurrences)
+	}
+	if got.Message != "Back-off restarting failed container b" {
+		t.Errorf("Message = %q, want the latest", got.Message)
+	}
+
+	// A stale update for an Event already folded must not count twice
+	if again := a.Fold(backOffEvent("a", 5, base, base.Add(20*time.Minute)), nil, base.Add(time.Hour)); again.Count != 8 {
+		t.Errorf("Count after replayed update = %d, want 8", again.Count)
+	}
+}
+
+func TestEventAggregator_GapStartsNewEntry(t *testing.T) {
+	a := NewEventAggregator()
+	base := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	first := a.Fold(backOffEvent("a", 2, base, base.Add(time.Minute)), nil, base.Add(time.Minute))
+	later := base.Add(3 * time.Hour)
+	second := a.Fold(backOffEvent("b", 1, later, later), nil, later)
+
+	if second.ID == first.ID {
+		t.Fatal("recurrence after a quiet hour reused the previous entry")
+	}
+	if second.Count != 1 || !second.Aggregate.FirstSeen.Equal(later) {
+		t.Errorf("new entry = count %d, firstSeen %s", second.Count, second.Aggregate.FirstSeen)
+	}
+
+	// Other reasons on the same object aggregate separately
+	other := backOffEvent("c", 1, later, later)
+	other.Reason = "Unhealthy"
+	if a.Fold(other, nil, later).ID == second.ID {
+		t.Error("different reasons share an entry")
+	}
+}
+
+func TestSQLiteStore_UpsertsK8sEventAggregates(t *testing.T) {
+	store, cleanup := createTestSQLiteStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	a := NewEventAggregator()
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+	for i := range 3 {
+		ts := base.Add(time.Duration(i) * 10 * time.Minute)
+		if err := store.Append(ctx, a.Fold(backOffEvent("a", int32(i+1), base, ts), nil, ts)); err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+	}
+
+	events, err := store.Query(ctx, QueryOptions{Limit: 10, IncludeManaged: true, IncludeK8sEvents: true})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("got %d rows, want 1 updated in place", len(events))
+	}
+	if events[0].Count != 3 || events[0].Aggregate == nil || !events[0].Aggregate.LastSeen.Equal(base.Add(20*time.Minute)) {
+		t.Errorf("stored event = count %d, aggregate %+v", events[0].Count, events[0].Aggregate)
+	}
+}
+
+func TestMemoryStore_UpsertsK8sEventAggregates(t *testing.T) {
+	store := NewMemoryStore(100)
+	ctx := context.Background()
+
+	a := NewEventAggregator()
+	base := time.Now().Add(-time.Hour)
+	for i := range 3 {
+		ts := base.Add(time.Duration(i) * 10 * time.Minute)
+		if err := store.Append(ctx, a.Fold(backOffEvent("a", int32(i+1), base, ts), nil, ts)); err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+	}
+
+	events, err := store.Query(ctx, QueryOptions{Limit: 10, IncludeManaged: true, IncludeK8sEvents: true})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1 updated in place", len(events))
+	}
+	if events[0].Count != 3 {
+		t.Errorf("Count = %d, want 3", events[0].Count)
+	}
+}
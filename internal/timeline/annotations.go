@@ -0,0 +1,155 @@
+package timeline
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Limits on posted annotations, so a misbehaving CI job can't fill the store with noise
+const (
+	maxAnnotationTitle  = 200
+	maxAnnotationText   = 4000
+	maxAnnotationLinks  = 10
+	maxAnnotationLabels = 20
+	// annotationClockSkew is how far in the future an annotation's timestamp may be
+	annotationClockSkew = 5 * time.Minute
+)
+
+// AnnotationKind is the kind of annotations that aren't about a particular resource
+const AnnotationKind = "Annotation"
+
+// DefaultAnnotationCategory is used when an annotation doesn't name one
+const DefaultAnnotationCategory = "note"
+
+var annotationCategory = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,30}[a-z0-9])?$`)
+
+// AnnotationInput is a manual marker posted to the timeline, e.g. "release 2.3 deployed".
+// Markers can be cluster-wide, scoped to a namespace, or about one resource.
+type AnnotationInput struct {
+	Title     string            `json:"title"`
+	Text      string            `json:"text,omitempty"`
+	Category  string            `json:"category,omitempty"` // e.g. deploy, incident, note (the default)
+	Namespace string            `json:"namespace,omitempty"`
+	Kind      string            `json:"kind,omitempty"` // Resource the marker is about, with Name
+	Name      string            `json:"name,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Links     []AnnotationLink  `json:"links,omitempty"`
+	Timestamp *time.Time        `json:"timestamp,omitempty"` // Defaults to now
+}
+
+// NewAnnotationEvent validates an annotation and turns it into a timeline event. The
+// category is stored in Reason and the title in Message; annotations that aren't about
+// a resource get kind Annotation and their category as name.
+func NewAnnotationEvent(in AnnotationInput, author string, now time.Time) (TimelineEvent, error) {
+	if in.Title == "" {
+		return TimelineEvent{}, fmt.Errorf("title is required")
+	}
+	if len(in.Title) > maxAnnotationTitle {
+		return TimelineEvent{}, fmt.Errorf("title is longer than %d characters", maxAnnotationTitle)
+	}
+	if len(in.Text) > maxAnnotationText {
+		return TimelineEvent{}, fmt.Errorf("text is longer than %d characters", maxAnnotationText)
+	}
+	if in.Category == "" {
+		in.Category = DefaultAnnotationCategory
+	}
+	if !annotationCategory.MatchString(in.Category) {
+		return TimelineEvent{}, fmt.Errorf("category %q must be lowercase letters, digits and dashes (at most 32)", in.Category)
+	}
+	if in.Namespace != "" {
+		if errs := validation.IsDNS1123Label(in.Namespace); len(errs) > 0 {
+			return TimelineEvent{}, fmt.Errorf("invalid namespace %q: %s", in.Namespace, errs[0])
+		}
+	}
+	if (in.Kind == "") != (in.Name == "") {
+		return TimelineEvent{}, fmt.Errorf("kind and name must be set together")
+	}
+	if len(in.Labels) > maxAnnotationLabels {
+		return TimelineEvent{}, fmt.Errorf("at most %d labels are allowed", maxAnnotationLabels)
+	}
+	for k, v := range in.Labels {
+		if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+			return TimelineEvent{}, fmt.Errorf("invalid label key %q: %s", k, errs[0])
+		}
+		if errs := validation.IsValidLabelValue(v); len(errs) > 0 {
+			return TimelineEvent{}, fmt.Errorf("invalid value for label %q: %s", k, errs[0])
+		}
+	}
+	if len(in.Links) > maxAnnotationLinks {
+		return TimelineEvent{}, fmt.Errorf("at most %d links are allowed", maxAnnotationLinks)
+	}
+	for _, l := range in.Links {
+		u, err := url.Parse(l.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return TimelineEvent{}, fmt.Errorf("link %q must be an absolute http(s) URL", l.URL)
+		}
+	}
+
+	ts := now
+	if in.Timestamp != nil && !in.Timestamp.IsZero() {
+		if in.Timestamp.After(now.Add(annotationClockSkew)) {
+			return TimelineEvent{}, fmt.Errorf("timestamp %s is in the future", in.Timestamp.Format(time.RFC3339))
+		}
+		ts = *in.Timestamp
+	}
+
+	kind, name := in.Kind, in.Name
+	if kind == "" {
+		kind, name = AnnotationKind, in.Category
+	}
+	return TimelineEvent{
+		ID:         uuid.New().String(),
+		Timestamp:  ts,
+		Source:     SourceAnnotation,
+		Kind:       kind,
+		Namespace:  in.Namespace,
+		Name:       name,
+		EventType:  EventTypeAnnotation,
+		Reason:     in.Category,
+		Message:    in.Title,
+		Labels:     in.Labels,
+		Annotation: &AnnotationInfo{Author: author, Text: in.Text, Links: in.Links},
+	}, nil
+}
+
+// RecordAnnotation writes an audit log line and records an annotation on the timeline
+func RecordAnnotation(ctx context.Context, e TimelineEvent) error {
+	author := ""
+	if e.Annotation != nil {
+		author = e.Annotation.Author
+	}
+	log.Printf("[audit] action=annotate target=%s/%s/%s actor=%s category=%s title=%q", e.Kind, e.Namespace, e.Name, author, e.Reason, e.Message)
+	return RecordEventWithBroadcast(ctx, e)
+}
+
+// Annotations returns the annotations between since and until (zero = unbounded), oldest
+// first. With namespaces, only annotations in them and cluster-wide ones are returned.
+func Annotations(ctx context.Context, store EventStore, namespaces []string, since, until time.Time, limit int) ([]TimelineEvent, error) {
+	events, err := store.Query(ctx, QueryOptions{
+		Since:          since,
+		Until:          until,
+		Sources:        []EventSource{SourceAnnotation},
+		Limit:          limit,
+		IncludeManaged: true, // Markers on Pods and ReplicaSets too
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := events[:0]
+	for _, e := range events {
+		if len(namespaces) == 0 || e.Namespace == "" || slices.Contains(namespaces, e.Namespace) {
+			out = append(out, e)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Timestamp.Before(out[j].Timestamp) })
+	return out, nil
+}
@@ -0,0 +1,101 @@
+package timeline
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewAnnotationEvent(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	e, err := NewAnnotationEvent(AnnotationInput{
+		Title:    "release 2.3 deployed",
+		Category: "deploy",
+		Labels:   map[string]string{"version": "2.3.0"},
+		Links:    []AnnotationLink{{Title: "changelog", URL: "https://example.com/releases/2.3"}},
+	}, "token:ci", now)
+	if err != nil {
+		t.Fatalf("NewAnnotationEvent: %v", err)
+	}
+	if e.Kind != AnnotationKind || e.Name != "deploy" || e.Reason != "deploy" || e.Message != "release 2.3 deployed" {
+		t.Errorf("event = %s %s reason %q message %q", e.Kind, e.Name, e.Reason, e.Message)
+	}
+	if e.Source != SourceAnnotation || e.EventType != EventTypeAnnotation || !e.Timestamp.Equal(now) {
+		t.Errorf("source %s, type %s, timestamp %s", e.Source, e.EventType, e.Timestamp)
+	}
+	if e.Annotation == nil || e.Annotation.Author != "token:ci" || len(e.Annotation.Links) != 1 {
+		t.Errorf("annotation = %+v", e.Annotation)
+	}
+
+	// A marker about a resource keeps its identity
+	e, err = NewAnnotationEvent(AnnotationInput{Title: "INC-123 started", Category: "incident", Namespace: "shop", Kind: "Deployment", Name: "web"}, "user:ana", now)
+	if err != nil || e.Kind != "Deployment" || e.Namespace != "shop" || e.Name != "web" {
+		t.Errorf("resource marker = %s/%s/%s, %v", e.Kind, e.Namespace, e.Name, err)
+	}
+
+	future := now.Add(time.Hour)
+	for _, tc := range []struct {
+		in   AnnotationInput
+		want string
+	}{
+		{AnnotationInput{}, "title is required"},
+		{AnnotationInput{Title: "x", Category: "Deploy!"}, "category"},
+		{AnnotationInput{Title: "x", Kind: "Deployment"}, "kind and name"},
+		{AnnotationInput{Title: "x", Namespace: "Shop"}, "invalid namespace"},
+		{AnnotationInput{Title: "x", Labels: map[string]string{"team": "a b"}}, "invalid value for label"},
+		{AnnotationInput{Title: "x", Links: []AnnotationLink{{URL: "javascript:alert(1)"}}}, "absolute http(s) URL"},
+		{AnnotationInput{Title: "x", Timestamp: &future}, "in the future"},
+		{AnnotationInput{Title: strings.Repeat("x", maxAnnotationTitle+1)}, "longer than"},
+	} {
+		if _, err := NewAnnotationEvent(tc.in, "me", now); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("NewAnnotationEvent(%+v) error = %v, want %q", tc.in, err, tc.want)
+		}
+	}
+}
+
+func TestAnnotations(t *testing.T) {
+	ctx := context.Background()
+	base := time.Now().Add(-time.Hour)
+	mark := func(ns, title string, at time.Duration) TimelineEvent {
+		ts := base.Add(at)
+		e, err := NewAnnotationEvent(AnnotationInput{Title: title, Namespace: ns, Timestamp: &ts}, "me", time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return e
+	}
+
+	for name, newStore := range map[string]func(t *testing.T) (EventStore, func()){
+		"memory": func(t *testing.T) (EventStore, func()) { return NewMemoryStore(100), func() {} },
+		"sqlite": func(t *testing.T) (EventStore, func()) { return createTestSQLiteStore(t) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			store, cleanup := newStore(t)
+			defer cleanup()
+			store.AppendBatch(ctx, []TimelineEvent{
+				mark("", "cluster upgrade", 3*time.Minute),
+				mark("shop", "shop deploy", time.Minute),
+				mark("billing", "billing deploy", 2*time.Minute),
+				NewInformerEvent("Deployment", "shop", "web", "", EventTypeUpdate, HealthHealthy, nil, nil, nil, nil),
+			})
+
+			got, err := Annotations(ctx, store, []string{"shop"}, time.Time{}, time.Time{}, 100)
+			if err != nil {
+				t.Fatalf("Annotations: %v", err)
+			}
+			if len(got) != 2 || got[0].Message != "shop deploy" || got[1].Message != "cluster upgrade" {
+				t.Fatalf("Annotations(shop) = %+v, want shop deploy then the cluster-wide upgrade", got)
+			}
+			if got[0].Annotation == nil || got[0].Annotation.Author != "me" {
+				t.Errorf("annotation info not stored: %+v", got[0].Annotation)
+			}
+
+			got, _ = Annotations(ctx, store, nil, base.Add(90*time.Second), time.Time{}, 100)
+			if len(got) != 2 || got[0].Message != "billing deploy" {
+				t.Errorf("Annotations(since) = %+v, want billing deploy and the upgrade", got)
+			}
+		})
+	}
+}
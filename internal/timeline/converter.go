@@ -9,6 +9,9 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/skyhook-io/radar/internal/health"
 )
 
 // NewInformerEvent creates a TimelineEvent from an informer callback
@@ -162,8 +165,12 @@ func ExtractLabels(obj any) map[string]string {
 	return relevant
 }
 
-// DetermineHealthState determines health state from an object
+// DetermineHealthState determines health state from an object. Custom resources are
+// assessed by the health rules for their GroupKind.
 func DetermineHealthState(kind string, obj any) HealthState {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		return HealthState(health.Assess(u))
+	}
 	switch kind {
 	case "Pod":
 		if pod, ok := obj.(*corev1.Pod); ok {
@@ -183,6 +190,27 @@ func DetermineHealthState(kind string, obj any) HealthState {
 				return HealthDegraded
 			}
 		}
+	case "Node":
+		// NotReady (or no longer reporting) is unhealthy; resource pressure degrades it
+		if node, ok := obj.(*corev1.Node); ok {
+			state := HealthUnknown
+			for _, c := range node.Status.Conditions {
+				switch c.Type {
+				case corev1.NodeReady:
+					if c.Status != corev1.ConditionTrue {
+						return HealthUnhealthy
+					}
+					if state == HealthUnknown {
+						state = HealthHealthy
+					}
+				case corev1.NodeMemoryPressure, corev1.NodeDiskPressure, corev1.NodePIDPressure:
+					if c.Status == corev1.ConditionTrue {
+						state = HealthDegraded
+					}
+				}
+			}
+			return state
+		}
 	case "Deployment":
 		if dep, ok := obj.(*appsv1.Deployment); ok {
 			desired := int32(1)
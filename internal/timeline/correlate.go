@@ -0,0 +1,277 @@
+package timeline
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultCorrelationWindow is how long an incident stays open after its last event
+	DefaultCorrelationWindow = 10 * time.Minute
+
+	// maxIncidentSpan caps how long one correlated incident can run, so a workload that
+	// keeps failing is split into several incidents instead of one unbounded one
+	maxIncidentSpan = 6 * time.Hour
+
+	// maxOwnerDepth bounds owner chain walks (Pod → ReplicaSet → Deployment → ...)
+	maxOwnerDepth = 8
+)
+
+// OwnerResolver returns the owner of a resource when the events being correlated don't
+// say (e.g. the Deployment of a ReplicaSet that recorded no events), or the workload an
+// HPA scales. It returns nil for top-level resources.
+type OwnerResolver func(kind, namespace, name string) *OwnerInfo
+
+var (
+	ownerResolverMu sync.RWMutex
+	ownerResolver   OwnerResolver
+)
+
+// SetOwnerResolver registers the lookup used for owners missing from the events. The k8s
+// package registers one backed by the informer cache.
+func SetOwnerResolver(r OwnerResolver) {
+	ownerResolverMu.Lock()
+	ownerResolver = r
+	ownerResolverMu.Unlock()
+}
+
+func getOwnerResolver() OwnerResolver {
+	ownerResolverMu.RLock()
+	defer ownerResolverMu.RUnlock()
+	return ownerResolver
+}
+
+// CorrelatedIncident is a burst of related events under one top-level owner, e.g. a Pod
+// OOMKilled, its ReplicaSet replacing it, the Deployment going unavailable and its HPA
+// scaling up. It opens on a problem signal and closes after DefaultCorrelationWindow
+// without events, or when the owner reports healthy again.
+type CorrelatedIncident struct {
+	ID         string      `json:"id"` // ID of the event that opened the incident
+	Root       OwnerInfo   `json:"root"`
+	Namespace  string      `json:"namespace"`
+	Start      time.Time   `json:"start"`
+	End        time.Time   `json:"end"`                // Last member event
+	Resolved   *time.Time  `json:"resolved,omitempty"` // Root reported healthy again
+	Worst      HealthState `json:"worst,omitempty"`
+	Reasons    []string    `json:"reasons,omitempty"` // Distinct problem reasons, in order seen
+	Kinds      []string    `json:"kinds"`             // Member kinds, in order seen
+	EventCount int         `json:"eventCount"`
+	EventIDs   []string    `json:"eventIds"`
+
+	// Events are the member events, set when drilling into one incident
+	Events []TimelineEvent `json:"events,omitempty"`
+}
+
+// isIncidentTrigger reports whether an event is a problem signal that opens an incident
+func isIncidentTrigger(e *TimelineEvent) bool {
+	if e.EventType == EventTypeWarning || e.HealthState == HealthDegraded || e.HealthState == HealthUnhealthy {
+		return true
+	}
+	if e.Kind == "Pod" && e.Source == SourceInformer {
+		switch ReplacementCause(e.Reason) {
+		case CauseOOM, CauseCrash, CauseEviction, CausePreemption, CauseNodeFailure:
+			return true
+		}
+	}
+	return false
+}
+
+// ownerGraph resolves resources to their top-level owner, from the owners the events
+// record and the registered OwnerResolver for the rest
+type ownerGraph struct {
+	owners   map[string]OwnerInfo // ResourceKey -> immediate owner
+	roots    map[string]OwnerInfo // ResourceKey -> memoized top-level owner
+	resolver OwnerResolver
+}
+
+func newOwnerGraph(events []TimelineEvent, resolver OwnerResolver) *ownerGraph {
+	g := &ownerGraph{owners: make(map[string]OwnerInfo), roots: make(map[string]OwnerInfo), resolver: resolver}
+	for _, e := range events {
+		if e.Owner != nil && e.Owner.Name != "" {
+			g.owners[ResourceKey(e.Kind, e.Namespace, e.Name)] = *e.Owner
+		}
+	}
+	return g
+}
+
+// root walks owner references up from a resource. Cycles and overly deep chains stop at
+// the last resource reached.
+func (g *ownerGraph) root(kind, namespace, name string) OwnerInfo {
+	start := ResourceKey(kind, namespace, name)
+	if r, ok := g.roots[start]; ok {
+		return r
+	}
+	cur := OwnerInfo{Kind: kind, Name: name}
+	visited := map[string]bool{start: true}
+	for depth := 0; depth < maxOwnerDepth; depth++ {
+		key := ResourceKey(cur.Kind, namespace, cur.Name)
+		if r, ok := g.roots[key]; ok && key != start {
+			cur = r
+			break
+		}
+		owner, ok := g.owners[key]
+		if !ok && g.resolver != nil {
+			if o := g.resolver(cur.Kind, namespace, cur.Name); o != nil {
+				owner, ok = *o, true
+				g.owners[key] = owner
+			}
+		}
+		next := ResourceKey(owner.Kind, namespace, owner.Name)
+		if !ok || visited[next] {
+			break
+		}
+		visited[next] = true
+		cur = owner
+	}
+	g.roots[start] = cur
+	return cur
+}
+
+// CorrelateIncidents groups events into incidents by top-level owner. An incident opens
+// on a problem signal (Warning event, degraded health, OOMKilled or crashed pod) and
+// collects every later event under the same owner until window passes without one.
+// Incidents are returned most recent first.
+func CorrelateIncidents(events []TimelineEvent, window time.Duration) []CorrelatedIncident {
+	if window <= 0 {
+		window = DefaultCorrelationWindow
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Timestamp.Before(events[j].Timestamp) })
+
+	graph := newOwnerGraph(events, getOwnerResolver())
+	open := make(map[string]*CorrelatedIncident)
+	var incidents []*CorrelatedIncident
+
+	for i := range events {
+		e := &events[i]
+		root := graph.root(e.Kind, e.Namespace, e.Name)
+		key := ResourceKey(root.Kind, e.Namespace, root.Name)
+
+		inc := open[key]
+		if inc != nil && (e.Timestamp.Sub(inc.End) > window || e.Timestamp.Sub(inc.Start) > maxIncidentSpan) {
+			delete(open, key)
+			inc = nil
+		}
+		if inc == nil {
+			if !isIncidentTrigger(e) {
+				continue
+			}
+			inc = &CorrelatedIncident{ID: e.ID, Root: root, Namespace: e.Namespace, Start: e.Timestamp}
+			open[key] = inc
+			incidents = append(incidents, inc)
+		}
+
+		inc.End = e.Timestamp
+		inc.EventCount++
+		inc.EventIDs = append(inc.EventIDs, e.ID)
+		inc.Kinds = appendUnique(inc.Kinds, e.Kind)
+		if e.HealthState == HealthDegraded || e.HealthState == HealthUnhealthy {
+			inc.Worst = worseHealth(inc.Worst, e.HealthState)
+		}
+		if isIncidentTrigger(e) && e.Reason != "" {
+			inc.Reasons = appendUnique(inc.Reasons, e.Reason)
+		}
+
+		// The owner itself recovering closes the incident
+		if e.Kind == root.Kind && e.Name == root.Name && e.HealthState == HealthHealthy && inc.Worst != "" {
+			ts := e.Timestamp
+			inc.Resolved = &ts
+			delete(open, key)
+		}
+	}
+
+	result := make([]CorrelatedIncident, len(incidents))
+	for i, inc := range incidents {
+		result[len(incidents)-1-i] = *inc
+	}
+	return result
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, v := range list {
+		if v == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
+
+// correlationQuery returns every event, including managed resources and K8s Events,
+// in the given range
+func correlationQuery(ctx context.Context, store EventStore, namespace string, since, until time.Time) ([]TimelineEvent, error) {
+	opts := QueryOptions{
+		Namespace:        namespace,
+		Since:            since,
+		Until:            until,
+		Limit:            reportPageSize,
+		IncludeManaged:   true,
+		IncludeK8sEvents: true,
+		FilterPreset:     "all",
+	}
+	var events []TimelineEvent
+	for page := 0; page < reportMaxPages; page++ {
+		opts.Offset = page * reportPageSize
+		batch, err := store.Query(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, batch...)
+		if len(batch) < reportPageSize {
+			break
+		}
+	}
+	return events, nil
+}
+
+// QueryCorrelatedIncidents correlates the events in [since, until] from the global store
+func QueryCorrelatedIncidents(ctx context.Context, namespace string, since, until time.Time, window time.Duration) ([]CorrelatedIncident, error) {
+	store := GetStore()
+	if store == nil {
+		return nil, fmt.Errorf("event store not initialized")
+	}
+	events, err := correlationQuery(ctx, store, namespace, since, until)
+	if err != nil {
+		return nil, err
+	}
+	return CorrelateIncidents(events, window), nil
+}
+
+// GetCorrelatedIncident rebuilds the incident opened by the event with the given ID,
+// with its member events. Returns nil when the event doesn't exist or opens no incident.
+func GetCorrelatedIncident(ctx context.Context, id string, window time.Duration) (*CorrelatedIncident, error) {
+	store := GetStore()
+	if store == nil {
+		return nil, fmt.Errorf("event store not initialized")
+	}
+	trigger, err := store.GetEvent(ctx, id)
+	if err != nil || trigger == nil {
+		return nil, err
+	}
+	if window <= 0 {
+		window = DefaultCorrelationWindow
+	}
+
+	// An incident never outlasts maxIncidentSpan, so this range holds all its members
+	start := trigger.Timestamp
+	events, err := correlationQuery(ctx, store, trigger.Namespace, start, start.Add(maxIncidentSpan+window))
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]TimelineEvent, len(events))
+	for _, e := range events {
+		byID[e.ID] = e
+	}
+	for _, inc := range CorrelateIncidents(events, window) {
+		if inc.ID != id {
+			continue
+		}
+		inc.Events = make([]TimelineEvent, 0, len(inc.EventIDs))
+		for _, eid := range inc.EventIDs {
+			inc.Events = append(inc.Events, byID[eid])
+		}
+		return &inc, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,97 @@
+package timeline
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCorrelateIncidents(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(min int) time.Time { return base.Add(time.Duration(min) * time.Minute) }
+	rs := &OwnerInfo{Kind: "ReplicaSet", Name: "web-abc"}
+	deploy := &OwnerInfo{Kind: "Deployment", Name: "web"}
+
+	events := []TimelineEvent{
+		// Before the first problem: not part of any incident
+		{ID: "rs-update", Timestamp: at(-5), Source: SourceInformer, Kind: "ReplicaSet", Namespace: "prod", Name: "web-abc", EventType: EventTypeUpdate, Owner: deploy},
+		{ID: "oom", Timestamp: at(0), Source: SourceInformer, Kind: "Pod", Namespace: "prod", Name: "web-abc-1", EventType: EventTypeDelete, Reason: string(CauseOOM), Owner: rs},
+		{ID: "rs-scale", Timestamp: at(1), Source: SourceK8sEvent, Kind: "ReplicaSet", Namespace: "prod", Name: "web-abc", EventType: EventTypeNormal, Reason: "SuccessfulCreate", Owner: deploy},
+		{ID: "unavailable", Timestamp: at(2), Source: SourceInformer, Kind: "Deployment", Namespace: "prod", Name: "web", EventType: EventTypeUpdate, HealthState: HealthDegraded},
+		// The HPA's target comes from the resolver
+		{ID: "hpa-scale", Timestamp: at(3), Source: SourceK8sEvent, Kind: "HorizontalPodAutoscaler", Namespace: "prod", Name: "web", EventType: EventTypeNormal, Reason: "SuccessfulRescale"},
+		// Another workload's problem is a separate incident
+		{ID: "db-warning", Timestamp: at(4), Source: SourceK8sEvent, Kind: "Pod", Namespace: "prod", Name: "db-0", EventType: EventTypeWarning, Reason: "BackOff", Owner: &OwnerInfo{Kind: "StatefulSet", Name: "db"}},
+		{ID: "recovered", Timestamp: at(6), Source: SourceInformer, Kind: "Deployment", Namespace: "prod", Name: "web", EventType: EventTypeUpdate, HealthState: HealthHealthy},
+		// After recovery, without a new problem: ignored
+		{ID: "later", Timestamp: at(8), Source: SourceInformer, Kind: "Deployment", Namespace: "prod", Name: "web", EventType: EventTypeUpdate, HealthState: HealthHealthy},
+	}
+
+	SetOwnerResolver(func(kind, namespace, name string) *OwnerInfo {
+		if kind == "HorizontalPodAutoscaler" && name == "web" {
+			return &OwnerInfo{Kind: "Deployment", Name: "web"}
+		}
+		return nil
+	})
+	t.Cleanup(func() { SetOwnerResolver(nil) })
+
+	incidents := CorrelateIncidents(events, 0)
+	if len(incidents) != 2 {
+		t.Fatalf("got %d incidents, want 2: %+v", len(incidents), incidents)
+	}
+
+	// Most recent first
+	db, web := incidents[0], incidents[1]
+	if db.Root != (OwnerInfo{Kind: "StatefulSet", Name: "db"}) || db.EventCount != 1 {
+		t.Errorf("db incident = %+v", db)
+	}
+
+	if web.ID != "oom" || web.Root != *deploy {
+		t.Errorf("web incident opened by %s under %+v, want oom under Deployment/web", web.ID, web.Root)
+	}
+	wantIDs := []string{"oom", "rs-scale", "unavailable", "hpa-scale", "recovered"}
+	if !reflect.DeepEqual(web.EventIDs, wantIDs) {
+		t.Errorf("event IDs = %v, want %v", web.EventIDs, wantIDs)
+	}
+	if web.Resolved == nil || !web.Resolved.Equal(at(6)) || web.Worst != HealthDegraded {
+		t.Errorf("resolved = %v, worst = %s", web.Resolved, web.Worst)
+	}
+	if want := []string{"Pod", "ReplicaSet", "Deployment", "HorizontalPodAutoscaler"}; !reflect.DeepEqual(web.Kinds, want) {
+		t.Errorf("kinds = %v, want %v", web.Kinds, want)
+	}
+	if want := []string{string(CauseOOM)}; !reflect.DeepEqual(web.Reasons, want) {
+		t.Errorf("reasons = %v, want %v", web.Reasons, want)
+	}
+}
+
+func TestCorrelateIncidentsWindow(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	warning := func(id string, offset time.Duration) TimelineEvent {
+		return TimelineEvent{ID: id, Timestamp: base.Add(offset), Source: SourceK8sEvent, Kind: "Pod", Namespace: "prod", Name: "api-1",
+			EventType: EventTypeWarning, Reason: "BackOff", Owner: &OwnerInfo{Kind: "ReplicaSet", Name: "api-abc"}}
+	}
+	events := []TimelineEvent{warning("a", 0), warning("b", 5*time.Minute), warning("c", 30*time.Minute)}
+
+	incidents := CorrelateIncidents(events, 10*time.Minute)
+	if len(incidents) != 2 {
+		t.Fatalf("got %d incidents, want 2", len(incidents))
+	}
+	if incidents[1].EventCount != 2 || incidents[0].ID != "c" {
+		t.Errorf("unexpected split: %+v", incidents)
+	}
+	// Without a resolver, the chain stops at the last recorded owner
+	if incidents[0].Root != (OwnerInfo{Kind: "ReplicaSet", Name: "api-abc"}) {
+		t.Errorf("root = %+v", incidents[0].Root)
+	}
+}
+
+func TestOwnerGraphCycle(t *testing.T) {
+	events := []TimelineEvent{
+		{Kind: "A", Namespace: "ns", Name: "a", Owner: &OwnerInfo{Kind: "B", Name: "b"}},
+		{Kind: "B", Namespace: "ns", Name: "b", Owner: &OwnerInfo{Kind: "A", Name: "a"}},
+	}
+	g := newOwnerGraph(events, nil)
+	if root := g.root("A", "ns", "a"); root != (OwnerInfo{Kind: "B", Name: "b"}) {
+		t.Errorf("root = %+v", root)
+	}
+}
@@ -0,0 +1,166 @@
+package timeline
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// ExportFormat is the encoding of an event export
+type ExportFormat string
+
+const (
+	ExportJSON   ExportFormat = "json"   // A single JSON array
+	ExportNDJSON ExportFormat = "ndjson" // One JSON event per line
+	ExportCSV    ExportFormat = "csv"    // Flattened columns, diff reduced to its summary
+)
+
+// exportPageSize is how many events are read from the store per query while exporting
+const exportPageSize = 1000
+
+// exportCSVHeader lists the CSV columns, in order
+var exportCSVHeader = []string{
+	"timestamp", "id", "source", "kind", "namespace", "name", "eventType", "reason", "message",
+	"healthState", "ownerKind", "ownerName", "count", "correlationId", "diffSummary",
+}
+
+// ParseExportFormat validates a format name; empty means JSON
+func ParseExportFormat(s string) (ExportFormat, error) {
+	switch f := ExportFormat(s); f {
+	case "":
+		return ExportJSON, nil
+	case ExportJSON, ExportNDJSON, ExportCSV:
+		return f, nil
+	}
+	return "", fmt.Errorf("unsupported export format %q (use json, csv or ndjson)", s)
+}
+
+// ContentType returns the MIME type for the format
+func (f ExportFormat) ContentType() string {
+	switch f {
+	case ExportNDJSON:
+		return "application/x-ndjson"
+	case ExportCSV:
+		return "text/csv"
+	default:
+		return "application/json"
+	}
+}
+
+// Export streams every event matching opts to w, newest first, paging through the store so
+// large histories aren't held in memory. Limit and Offset in opts are ignored. Events
+// recorded after the export starts are excluded so pages stay stable.
+func Export(ctx context.Context, store EventStore, w io.Writer, format ExportFormat, opts QueryOptions) (int, error) {
+	if opts.Until.IsZero() {
+		opts.Until = time.Now()
+	}
+	opts.Limit = exportPageSize
+
+	enc := newExportEncoder(w, format)
+	if err := enc.begin(); err != nil {
+		return 0, err
+	}
+	written := 0
+	for {
+		opts.Offset = written
+		batch, err := store.Query(ctx, opts)
+		if err != nil {
+			return written, err
+		}
+		for i := range batch {
+			if err := enc.write(&batch[i]); err != nil {
+				return written, err
+			}
+			written++
+		}
+		if len(batch) < exportPageSize {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return written, err
+		}
+	}
+	return written, enc.end()
+}
+
+// exportEncoder writes events in one format
+type exportEncoder struct {
+	w      io.Writer
+	format ExportFormat
+	csv    *csv.Writer
+	first  bool
+}
+
+func newExportEncoder(w io.Writer, format ExportFormat) *exportEncoder {
+	enc := &exportEncoder{w: w, format: format, first: true}
+	if format == ExportCSV {
+		enc.csv = csv.NewWriter(w)
+	}
+	return enc
+}
+
+func (e *exportEncoder) begin() error {
+	switch e.format {
+	case ExportCSV:
+		return e.csv.Write(exportCSVHeader)
+	case ExportJSON:
+		_, err := io.WriteString(e.w, "[")
+		return err
+	}
+	return nil
+}
+
+func (e *exportEncoder) write(event *TimelineEvent) error {
+	if e.format == ExportCSV {
+		return e.csv.Write(csvRecord(event))
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if e.format == ExportJSON && !e.first {
+		if _, err := io.WriteString(e.w, ","); err != nil {
+			return err
+		}
+	}
+	e.first = false
+	if e.format == ExportNDJSON {
+		data = append(data, '\n')
+	}
+	_, err = e.w.Write(data)
+	return err
+}
+
+func (e *exportEncoder) end() error {
+	switch e.format {
+	case ExportCSV:
+		e.csv.Flush()
+		return e.csv.Error()
+	case ExportJSON:
+		_, err := io.WriteString(e.w, "]\n")
+		return err
+	}
+	return nil
+}
+
+func csvRecord(e *TimelineEvent) []string {
+	var ownerKind, ownerName, diffSummary, count string
+	if e.Owner != nil {
+		ownerKind, ownerName = e.Owner.Kind, e.Owner.Name
+	}
+	if e.Diff != nil {
+		diffSummary = e.Diff.Summary
+	}
+	if e.Count > 0 {
+		count = strconv.Itoa(int(e.Count))
+	}
+	return []string{
+		e.Timestamp.UTC().Format(time.RFC3339Nano), e.ID, string(e.Source), e.Kind, e.Namespace, e.Name,
+		string(e.EventType), e.Reason, e.Message, string(e.HealthState), ownerKind, ownerName, count,
+		e.CorrelationID, diffSummary,
+	}
+}
@@ -0,0 +1,88 @@
+package timeline
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExport(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(5000)
+	base := time.Now().Add(-time.Hour)
+	// More than one page, across two namespaces
+	for i := 0; i < exportPageSize+500; i++ {
+		ns := "shop"
+		if i%2 == 1 {
+			ns = "ops"
+		}
+		if err := store.Append(ctx, TimelineEvent{
+			ID:        fmt.Sprintf("e-%d", i),
+			Timestamp: base.Add(time.Duration(i) * time.Second),
+			Source:    SourceInformer,
+			Kind:      "Deployment",
+			Namespace: ns,
+			Name:      "web",
+			EventType: EventTypeUpdate,
+			Message:   "changed, \"quoted\"",
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	opts := QueryOptions{Namespace: "shop", Kinds: []string{"Deployment"}, IncludeManaged: true, IncludeK8sEvents: true}
+
+	var buf bytes.Buffer
+	n, err := Export(ctx, store, &buf, ExportJSON, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var events []TimelineEvent
+	if err := json.Unmarshal(buf.Bytes(), &events); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if n != 750 || len(events) != 750 {
+		t.Errorf("JSON export: wrote %d, decoded %d, want 750", n, len(events))
+	}
+	seen := make(map[string]bool)
+	for _, e := range events {
+		if e.Namespace != "shop" || seen[e.ID] {
+			t.Fatalf("unexpected or duplicate event %s in %s", e.ID, e.Namespace)
+		}
+		seen[e.ID] = true
+	}
+
+	buf.Reset()
+	if _, err := Export(ctx, store, &buf, ExportNDJSON, opts); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != 750 {
+		t.Errorf("NDJSON export: %d lines, want 750", lines)
+	}
+
+	buf.Reset()
+	opts.Since = base.Add(1400 * time.Second)
+	if _, err := Export(ctx, store, &buf, ExportCSV, opts); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	if len(records) != 51 || records[0][0] != "timestamp" || records[1][8] != "changed, \"quoted\"" {
+		t.Errorf("CSV export: %d records, first row %q", len(records), records[1])
+	}
+}
+
+func TestParseExportFormat(t *testing.T) {
+	if f, err := ParseExportFormat(""); err != nil || f != ExportJSON {
+		t.Errorf("empty format = %q, %v", f, err)
+	}
+	if _, err := ParseExportFormat("xml"); err == nil {
+		t.Error("expected error for xml")
+	}
+}
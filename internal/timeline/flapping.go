@@ -0,0 +1,144 @@
+package timeline
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// MinFlapReversals is how many times the replica count must change direction within the
+// window for a workload to count as flapping (e.g. 3→5→3→5)
+const MinFlapReversals = 3
+
+// ReplicaFlap describes a workload whose spec.replicas kept moving up and down
+type ReplicaFlap struct {
+	Kind      string    `json:"kind"`
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Values    []int64   `json:"values"`    // Replica counts in order, starting with the first old value
+	Reversals int       `json:"reversals"` // Direction changes
+	First     time.Time `json:"first"`
+	Last      time.Time `json:"last"`
+	EventIDs  []string  `json:"eventIds"` // Timeline events of the replica changes
+}
+
+// Changes returns the number of replica changes
+func (f ReplicaFlap) Changes() int {
+	return len(f.EventIDs)
+}
+
+// NewAnalyzerEvent creates a warning TimelineEvent that explains a finding on a resource.
+// The finding's ID is stored in CorrelationID so the event and finding link to each other.
+func NewAnalyzerEvent(kind, namespace, name, reason, message, findingID string) TimelineEvent {
+	e := NewInformerEvent(kind, namespace, name, "", EventTypeWarning, "", nil, nil, nil, nil)
+	e.Source = SourceAnalyzer
+	e.Reason = reason
+	e.Message = message
+	e.CorrelationID = findingID
+	return e
+}
+
+// QueryReplicaFlaps finds Deployments and StatefulSets whose replica count flapped since
+// the given time
+func QueryReplicaFlaps(ctx context.Context, since time.Time) ([]ReplicaFlap, error) {
+	store := GetStore()
+	if store == nil {
+		return nil, nil
+	}
+	events, err := store.Query(ctx, QueryOptions{
+		Kinds:          []string{"Deployment", "StatefulSet"},
+		Since:          since,
+		Sources:        []EventSource{SourceInformer},
+		Limit:          10000,
+		IncludeManaged: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return DetectReplicaFlaps(events), nil
+}
+
+// DetectReplicaFlaps groups spec.replicas changes by workload and returns workloads whose
+// replica count reversed direction at least MinFlapReversals times, most reversals first
+func DetectReplicaFlaps(events []TimelineEvent) []ReplicaFlap {
+	sorted := make([]TimelineEvent, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Timestamp.Before(sorted[j].Timestamp) })
+
+	byWorkload := make(map[string]*ReplicaFlap)
+	var order []string
+	for _, e := range sorted {
+		if e.EventType != EventTypeUpdate || e.Diff == nil {
+			continue
+		}
+		for _, fc := range e.Diff.Fields {
+			if fc.Path != "spec.replicas" {
+				continue
+			}
+			oldVal, ok1 := toInt64(fc.OldValue)
+			newVal, ok2 := toInt64(fc.NewValue)
+			if !ok1 || !ok2 || oldVal == newVal {
+				continue
+			}
+			key := e.Kind + "/" + e.Namespace + "/" + e.Name
+			f := byWorkload[key]
+			if f == nil {
+				f = &ReplicaFlap{Kind: e.Kind, Namespace: e.Namespace, Name: e.Name, Values: []int64{oldVal}, First: e.Timestamp}
+				byWorkload[key] = f
+				order = append(order, key)
+			}
+			f.Values = append(f.Values, newVal)
+			f.Last = e.Timestamp
+			f.EventIDs = append(f.EventIDs, e.ID)
+		}
+	}
+
+	var flaps []ReplicaFlap
+	for _, key := range order {
+		f := byWorkload[key]
+		f.Reversals = countReversals(f.Values)
+		if f.Reversals >= MinFlapReversals {
+			flaps = append(flaps, *f)
+		}
+	}
+	sort.SliceStable(flaps, func(i, j int) bool { return flaps[i].Reversals > flaps[j].Reversals })
+	return flaps
+}
+
+// countReversals counts how often a sequence switches between rising and falling
+func countReversals(values []int64) int {
+	reversals, direction := 0, 0
+	for i := 1; i < len(values); i++ {
+		d := 0
+		switch {
+		case values[i] > values[i-1]:
+			d = 1
+		case values[i] < values[i-1]:
+			d = -1
+		}
+		if d == 0 {
+			continue
+		}
+		if direction != 0 && d != direction {
+			reversals++
+		}
+		direction = d
+	}
+	return reversals
+}
+
+// toInt64 reads a replica count from a diff value, which is an int32 when recorded and a
+// float64 once it has round-tripped through JSON storage
+func toInt64(v any) (int64, bool) {
+	switch n := v.(type) {
+	case int32:
+		return int64(n), true
+	case int:
+		return int64(n), true
+	case int64:
+		return n, true
+	case float64:
+		return int64(n), true
+	}
+	return 0, false
+}
@@ -0,0 +1,70 @@
+package timeline
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDetectReplicaFlaps(t *testing.T) {
+	t0 := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	n := 0
+	scale := func(min int, name string, from, to any) TimelineEvent {
+		n++
+		return TimelineEvent{
+			ID: name + "-" + string(rune('a'+n)), Timestamp: t0.Add(time.Duration(min) * time.Minute),
+			Source: SourceInformer, Kind: "Deployment", Namespace: "shop", Name: name, EventType: EventTypeUpdate,
+			Diff: &DiffInfo{Fields: []FieldChange{{Path: "spec.replicas", OldValue: from, NewValue: to}}},
+		}
+	}
+
+	events := []TimelineEvent{
+		// web: HPA scales up, GitOps resets, four times over (values as stored after JSON round-trip)
+		scale(0, "web", float64(3), float64(5)),
+		scale(3, "web", float64(5), float64(3)),
+		scale(6, "web", float64(3), float64(5)),
+		scale(9, "web", float64(5), float64(3)),
+		// api: a steady scale-up is not flapping
+		scale(1, "api", int32(2), int32(4)),
+		scale(4, "api", int32(4), int32(6)),
+		scale(7, "api", int32(6), int32(8)),
+		// Other fields and events are ignored
+		{ID: "img", Timestamp: t0, Kind: "Deployment", Namespace: "shop", Name: "web", EventType: EventTypeUpdate,
+			Diff: &DiffInfo{Fields: []FieldChange{{Path: "spec.template.spec.containers[app].image", OldValue: "a", NewValue: "b"}}}},
+		scale(12, "web", float64(3), float64(5)),
+	}
+	// Out-of-order input is sorted by time
+	events[0], events[3] = events[3], events[0]
+
+	flaps := DetectReplicaFlaps(events)
+	if len(flaps) != 1 {
+		t.Fatalf("flaps = %+v, want only web", flaps)
+	}
+	f := flaps[0]
+	if f.Name != "web" || f.Reversals != 4 || f.Changes() != 5 {
+		t.Errorf("flap = %+v, want web with 4 reversals and 5 changes", f)
+	}
+	if want := []int64{3, 5, 3, 5, 3, 5}; !reflect.DeepEqual(f.Values, want) {
+		t.Errorf("values = %v, want %v", f.Values, want)
+	}
+	if !f.First.Equal(t0) || !f.Last.Equal(t0.Add(12*time.Minute)) {
+		t.Errorf("first/last = %v/%v", f.First, f.Last)
+	}
+}
+
+func TestCountReversals(t *testing.T) {
+	tests := []struct {
+		values []int64
+		want   int
+	}{
+		{[]int64{1, 2, 3}, 0},
+		{[]int64{3, 5, 3}, 1},
+		{[]int64{3, 5, 5, 3, 5}, 2},
+		{[]int64{0, 4, 0, 4, 0}, 3},
+	}
+	for _, tt := range tests {
+		if got := countReversals(tt.values); got != tt.want {
+			t.Errorf("countReversals(%v) = %d, want %d", tt.values, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package timeline
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+)
+
+const (
+	// maxHeatmapBuckets bounds the time resolution of a heatmap
+	maxHeatmapBuckets = 500
+	// heatmapTopResources is how many of a row's busiest resources are listed
+	heatmapTopResources = 5
+	// DefaultHeatmapRows is how many namespace/kind rows a heatmap keeps by default
+	DefaultHeatmapRows = 50
+	// DefaultNoisyPerHour is the change rate above which a resource is reported as noisy
+	DefaultNoisyPerHour = 30.0
+)
+
+// HeatmapOptions configures a change heatmap
+type HeatmapOptions struct {
+	Namespace    string
+	Kinds        []string
+	Since        time.Time
+	Until        time.Time
+	Bucket       time.Duration
+	Rows         int     // Busiest namespace/kind rows kept (default DefaultHeatmapRows)
+	NoisyPerHour float64 // Resources changing faster are reported as noisy (default DefaultNoisyPerHour)
+}
+
+// ResourceChurn is how often one resource changed
+type ResourceChurn struct {
+	Kind           string  `json:"kind"`
+	Namespace      string  `json:"namespace"`
+	Name           string  `json:"name"`
+	Changes        int     `json:"changes"`
+	ChangesPerHour float64 `json:"changesPerHour"`
+}
+
+// HeatmapRow counts the changes of one kind in one namespace per time bucket
+type HeatmapRow struct {
+	Namespace    string          `json:"namespace"`
+	Kind         string          `json:"kind"`
+	Total        int             `json:"total"`
+	Counts       []int           `json:"counts"` // Aligned with ChangeHeatmap.Buckets
+	Resources    int             `json:"resources"`
+	TopResources []ResourceChurn `json:"topResources"`
+}
+
+// ChangeHeatmap is a namespace × kind × time grid of resource changes (adds, updates and
+// deletes), for spotting components that update far more often than expected
+type ChangeHeatmap struct {
+	Since         time.Time       `json:"since"`
+	Until         time.Time       `json:"until"`
+	BucketSeconds int64           `json:"bucketSeconds"`
+	Buckets       []time.Time     `json:"buckets"` // Bucket start times
+	Total         int             `json:"total"`
+	Rows          []HeatmapRow    `json:"rows"` // Busiest first
+	OmittedRows   int             `json:"omittedRows,omitempty"`
+	Noisy         []ResourceChurn `json:"noisy"` // Resources above the noisy rate, busiest first
+	// SuggestedFilter excludes the noisy resources, for a timeline filter preset
+	SuggestedFilter *FilterPreset `json:"suggestedFilter,omitempty"`
+	Truncated       bool          `json:"truncated,omitempty"` // The read limit was hit; counts are partial
+}
+
+// QueryChangeHeatmap builds a change heatmap from the global store
+func QueryChangeHeatmap(ctx context.Context, opts HeatmapOptions) (*ChangeHeatmap, error) {
+	store := GetStore()
+	if store == nil {
+		return nil, fmt.Errorf("event store not initialized")
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	q := QueryOptions{
+		Namespace:      opts.Namespace,
+		Kinds:          opts.Kinds,
+		Since:          opts.Since,
+		Until:          opts.Until,
+		Sources:        []EventSource{SourceInformer},
+		Limit:          reportPageSize,
+		IncludeManaged: true,
+	}
+	var events []TimelineEvent
+	truncated := true
+	for page := 0; page < reportMaxPages; page++ {
+		q.Offset = page * reportPageSize
+		batch, err := store.Query(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, batch...)
+		if len(batch) < reportPageSize {
+			truncated = false
+			break
+		}
+	}
+
+	heatmap := BuildChangeHeatmap(events, opts)
+	heatmap.Truncated = truncated
+	return heatmap, nil
+}
+
+// Validate checks the window and bucket size
+func (o HeatmapOptions) Validate() error {
+	if o.Since.IsZero() || o.Until.IsZero() || !o.Since.Before(o.Until) {
+		return fmt.Errorf("since must be before until")
+	}
+	if o.Bucket <= 0 {
+		return fmt.Errorf("bucket must be positive")
+	}
+	if n := (o.Until.Sub(o.Since) + o.Bucket - 1) / o.Bucket; n > maxHeatmapBuckets {
+		return fmt.Errorf("too many buckets (%d, max %d): use a larger bucket", n, maxHeatmapBuckets)
+	}
+	return nil
+}
+
+// BuildChangeHeatmap counts add, update and delete events in [Since, Until) per namespace,
+// kind and bucket. Other events (K8s Events, audit actions) are ignored.
+func BuildChangeHeatmap(events []TimelineEvent, opts HeatmapOptions) *ChangeHeatmap {
+	if opts.Rows <= 0 {
+		opts.Rows = DefaultHeatmapRows
+	}
+	if opts.NoisyPerHour <= 0 {
+		opts.NoisyPerHour = DefaultNoisyPerHour
+	}
+	n := int((opts.Until.Sub(opts.Since) + opts.Bucket - 1) / opts.Bucket)
+	heatmap := &ChangeHeatmap{
+		Since:         opts.Since,
+		Until:         opts.Until,
+		BucketSeconds: int64(opts.Bucket / time.Second),
+		Buckets:       make([]time.Time, n),
+		Rows:          make([]HeatmapRow, 0),
+		Noisy:         make([]ResourceChurn, 0),
+	}
+	for i := range heatmap.Buckets {
+		heatmap.Buckets[i] = opts.Since.Add(time.Duration(i) * opts.Bucket)
+	}
+
+	type rowKey struct{ namespace, kind string }
+	rows := make(map[rowKey]*HeatmapRow)
+	perResource := make(map[rowKey]map[string]int)
+	for _, e := range events {
+		switch e.EventType {
+		case EventTypeAdd, EventTypeUpdate, EventTypeDelete:
+		default:
+			continue
+		}
+		if e.Timestamp.Before(opts.Since) || !e.Timestamp.Before(opts.Until) {
+			continue
+		}
+		key := rowKey{e.Namespace, e.Kind}
+		row := rows[key]
+		if row == nil {
+			row = &HeatmapRow{Namespace: e.Namespace, Kind: e.Kind, Counts: make([]int, n)}
+			rows[key] = row
+			perResource[key] = make(map[string]int)
+		}
+		row.Counts[int(e.Timestamp.Sub(opts.Since)/opts.Bucket)]++
+		row.Total++
+		perResource[key][e.Name]++
+		heatmap.Total++
+	}
+
+	hours := opts.Until.Sub(opts.Since).Hours()
+	var noisy []ResourceChurn
+	for key, row := range rows {
+		churn := make([]ResourceChurn, 0, len(perResource[key]))
+		for name, changes := range perResource[key] {
+			c := ResourceChurn{Kind: row.Kind, Namespace: row.Namespace, Name: name, Changes: changes,
+				ChangesPerHour: float64(changes) / hours}
+			churn = append(churn, c)
+			if c.ChangesPerHour > opts.NoisyPerHour {
+				noisy = append(noisy, c)
+			}
+		}
+		sortChurn(churn)
+		row.Resources = len(churn)
+		row.TopResources = churn[:min(len(churn), heatmapTopResources)]
+		heatmap.Rows = append(heatmap.Rows, *row)
+	}
+
+	sort.Slice(heatmap.Rows, func(i, j int) bool {
+		a, b := heatmap.Rows[i], heatmap.Rows[j]
+		if a.Total != b.Total {
+			return a.Total > b.Total
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Kind < b.Kind
+	})
+	if len(heatmap.Rows) > opts.Rows {
+		heatmap.OmittedRows = len(heatmap.Rows) - opts.Rows
+		heatmap.Rows = heatmap.Rows[:opts.Rows]
+	}
+
+	if len(noisy) > 0 {
+		sortChurn(noisy)
+		heatmap.Noisy = noisy
+		heatmap.SuggestedFilter = suggestedFilter(noisy)
+	}
+	return heatmap
+}
+
+// suggestedFilter excludes noisy resources by exact name
+func suggestedFilter(noisy []ResourceChurn) *FilterPreset {
+	preset := &FilterPreset{Name: "suggested"}
+	seen := make(map[string]bool)
+	for _, c := range noisy {
+		pattern := "^" + regexp.QuoteMeta(c.Name) + "$"
+		if !seen[pattern] {
+			seen[pattern] = true
+			preset.ExcludeNamePatterns = append(preset.ExcludeNamePatterns, pattern)
+		}
+	}
+	return preset
+}
+
+func sortChurn(churn []ResourceChurn) {
+	sort.Slice(churn, func(i, j int) bool {
+		if churn[i].Changes != churn[j].Changes {
+			return churn[i].Changes > churn[j].Changes
+		}
+		return ResourceKey(churn[i].Kind, churn[i].Namespace, churn[i].Name) <
+			ResourceKey(churn[j].Kind, churn[j].Namespace, churn[j].Name)
+	})
+}
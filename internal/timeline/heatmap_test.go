@@ -0,0 +1,70 @@
+package timeline
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuildChangeHeatmap(t *testing.T) {
+	t0 := time.Date(2026, 5, 4, 0, 0, 0, 0, time.UTC)
+	change := func(min int, ns, kind, name string, et EventType) TimelineEvent {
+		return TimelineEvent{Timestamp: t0.Add(time.Duration(min) * time.Minute), Source: SourceInformer,
+			Kind: kind, Namespace: ns, Name: name, EventType: et}
+	}
+
+	var events []TimelineEvent
+	// An operator updating its CR every 30 seconds for two hours
+	for i := 0; i < 240; i++ {
+		events = append(events, TimelineEvent{Timestamp: t0.Add(time.Duration(i) * 30 * time.Second), Source: SourceInformer,
+			Kind: "Certificate", Namespace: "infra", Name: "wildcard", EventType: EventTypeUpdate})
+	}
+	events = append(events,
+		change(10, "shop", "Deployment", "web", EventTypeUpdate),
+		change(70, "shop", "Deployment", "web", EventTypeUpdate),
+		change(90, "shop", "Deployment", "api", EventTypeAdd),
+		change(100, "shop", "Deployment", "web", EventTypeWarning), // K8s Events aren't changes
+		change(130, "shop", "Deployment", "web", EventTypeUpdate),  // Outside the window
+	)
+
+	h := BuildChangeHeatmap(events, HeatmapOptions{Since: t0, Until: t0.Add(2 * time.Hour), Bucket: time.Hour})
+	if len(h.Buckets) != 2 || h.BucketSeconds != 3600 || h.Total != 243 {
+		t.Fatalf("buckets = %d, bucketSeconds = %d, total = %d", len(h.Buckets), h.BucketSeconds, h.Total)
+	}
+	if len(h.Rows) != 2 {
+		t.Fatalf("rows = %+v", h.Rows)
+	}
+	if r := h.Rows[0]; r.Kind != "Certificate" || r.Counts[0] != 120 || r.Counts[1] != 120 {
+		t.Errorf("first row = %+v, want the Certificate with 120 changes per hour", r)
+	}
+	shop := h.Rows[1]
+	if shop.Counts[0] != 1 || shop.Counts[1] != 2 || shop.Resources != 2 || shop.TopResources[0].Name != "web" {
+		t.Errorf("shop row = %+v", shop)
+	}
+
+	if len(h.Noisy) != 1 || h.Noisy[0].Name != "wildcard" || h.Noisy[0].ChangesPerHour != 120 {
+		t.Errorf("noisy = %+v", h.Noisy)
+	}
+	if f := h.SuggestedFilter; f == nil || len(f.ExcludeNamePatterns) != 1 || f.ExcludeNamePatterns[0] != "^wildcard$" {
+		t.Errorf("suggested filter = %+v", f)
+	}
+
+	if capped := BuildChangeHeatmap(events, HeatmapOptions{Since: t0, Until: t0.Add(2 * time.Hour), Bucket: time.Hour, Rows: 1}); len(capped.Rows) != 1 || capped.OmittedRows != 1 {
+		t.Errorf("capped rows = %d, omitted = %d", len(capped.Rows), capped.OmittedRows)
+	}
+}
+
+func TestHeatmapOptionsValidate(t *testing.T) {
+	t0 := time.Now()
+	for _, opts := range []HeatmapOptions{
+		{Since: t0, Until: t0, Bucket: time.Hour},
+		{Since: t0, Until: t0.Add(time.Hour)},
+		{Since: t0, Until: t0.Add(30 * 24 * time.Hour), Bucket: time.Minute},
+	} {
+		if opts.Validate() == nil {
+			t.Errorf("Validate(%+v): expected error", opts)
+		}
+	}
+	if err := (HeatmapOptions{Since: t0, Until: t0.Add(24 * time.Hour), Bucket: time.Hour}).Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+}
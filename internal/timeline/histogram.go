@@ -0,0 +1,352 @@
+package timeline
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// maxHistogramBuckets bounds the time resolution of a histogram
+	maxHistogramBuckets = 500
+	// DefaultHistogramSeries is how many of the busiest series a histogram keeps by default
+	DefaultHistogramSeries = 10
+)
+
+// HistogramDimension is an event attribute a histogram can group by
+type HistogramDimension string
+
+const (
+	// DimensionKind is the resource kind, qualified with its API group for custom
+	// resources ("Application.argoproj.io")
+	DimensionKind HistogramDimension = "kind"
+	// DimensionNamespace is the resource namespace ("" for cluster-scoped resources)
+	DimensionNamespace HistogramDimension = "namespace"
+	// DimensionHealth is the health state recorded with the event ("" when none was)
+	DimensionHealth HistogramDimension = "health"
+	// DimensionOwner is the resource's owner as Kind/namespace/name, or the resource
+	// itself when it has none, so a Deployment's pods and replica sets add up under it
+	DimensionOwner HistogramDimension = "owner"
+)
+
+// HistogramOptions configures an event histogram
+type HistogramOptions struct {
+	Namespace string
+	Kinds     []string
+	Sources   []EventSource // Empty = all sources, K8s Events and audit actions included
+	Since     time.Time
+	Until     time.Time
+	Bucket    time.Duration
+	GroupBy   []HistogramDimension // Default: kind
+	Top       int                  // Busiest series kept (default DefaultHistogramSeries)
+}
+
+// HistogramSeries counts the events of one group per time bucket
+type HistogramSeries struct {
+	Group  map[string]string `json:"group"` // Dimension -> value
+	Total  int               `json:"total"`
+	Counts []int             `json:"counts"` // Aligned with EventHistogram.Buckets
+}
+
+// EventHistogram counts timeline events per group and time bucket, for "events per hour"
+// charts and finding what generates the most events
+type EventHistogram struct {
+	Since         time.Time            `json:"since"`
+	Until         time.Time            `json:"until"`
+	BucketSeconds int64                `json:"bucketSeconds"`
+	Buckets       []time.Time          `json:"buckets"` // Bucket start times
+	GroupBy       []HistogramDimension `json:"groupBy"`
+	Total         int                  `json:"total"`
+	Series        []HistogramSeries    `json:"series"` // Busiest first
+	// OmittedSeries is how many quieter series were cut by Top; Other sums their counts
+	OmittedSeries int   `json:"omittedSeries,omitempty"`
+	Other         []int `json:"other,omitempty"`
+	Truncated     bool  `json:"truncated,omitempty"` // The read limit was hit; counts are partial
+}
+
+// histogramCount is the number of events of one group in one bucket
+type histogramCount struct {
+	bucket int
+	group  []string // Aligned with HistogramOptions.GroupBy
+	count  int
+}
+
+// histogramStore is implemented by stores that count events in the database instead of
+// returning every row
+type histogramStore interface {
+	histogramCounts(ctx context.Context, opts HistogramOptions) ([]histogramCount, error)
+}
+
+// QueryEventHistogram builds an event histogram from the global store. SQL stores count
+// in the database; others are read page by page. Since is rounded down to the second.
+func QueryEventHistogram(ctx context.Context, opts HistogramOptions) (*EventHistogram, error) {
+	store := GetStore()
+	if store == nil {
+		return nil, fmt.Errorf("event store not initialized")
+	}
+	if len(opts.GroupBy) == 0 {
+		opts.GroupBy = []HistogramDimension{DimensionKind}
+	}
+	opts.Since = opts.Since.Truncate(time.Second)
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	if hs, ok := store.(histogramStore); ok {
+		counts, err := hs.histogramCounts(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		return buildEventHistogram(counts, opts), nil
+	}
+
+	q := QueryOptions{
+		Namespace:        opts.Namespace,
+		Kinds:            opts.Kinds,
+		Since:            opts.Since,
+		Until:            opts.Until,
+		Sources:          opts.Sources,
+		Limit:            reportPageSize,
+		IncludeManaged:   true,
+		IncludeK8sEvents: true,
+	}
+	var events []TimelineEvent
+	truncated := true
+	for page := 0; page < reportMaxPages; page++ {
+		q.Offset = page * reportPageSize
+		batch, err := store.Query(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, batch...)
+		if len(batch) < reportPageSize {
+			truncated = false
+			break
+		}
+	}
+
+	histogram := BuildEventHistogram(events, opts)
+	histogram.Truncated = truncated
+	return histogram, nil
+}
+
+// Validate checks the window, bucket size and dimensions
+func (o HistogramOptions) Validate() error {
+	if o.Since.IsZero() || o.Until.IsZero() || !o.Since.Before(o.Until) {
+		return fmt.Errorf("since must be before until")
+	}
+	if o.Bucket < time.Second {
+		return fmt.Errorf("bucket must be at least 1s")
+	}
+	if n := (o.Until.Sub(o.Since) + o.Bucket - 1) / o.Bucket; n > maxHistogramBuckets {
+		return fmt.Errorf("too many buckets (%d, max %d): use a larger bucket", n, maxHistogramBuckets)
+	}
+	seen := make(map[HistogramDimension]bool, len(o.GroupBy))
+	for _, d := range o.GroupBy {
+		switch d {
+		case DimensionKind, DimensionNamespace, DimensionHealth, DimensionOwner:
+		default:
+			return fmt.Errorf("unknown group %q (use kind, namespace, health or owner)", d)
+		}
+		if seen[d] {
+			return fmt.Errorf("group %q is listed twice", d)
+		}
+		seen[d] = true
+	}
+	return nil
+}
+
+// BuildEventHistogram counts events in [Since, Until) per group and bucket. Events are
+// expected to be filtered by namespace, kind and source already.
+func BuildEventHistogram(events []TimelineEvent, opts HistogramOptions) *EventHistogram {
+	if len(opts.GroupBy) == 0 {
+		opts.GroupBy = []HistogramDimension{DimensionKind}
+	}
+	counts := make(map[string]*histogramCount)
+	for _, e := range events {
+		if e.Timestamp.Before(opts.Since) || !e.Timestamp.Before(opts.Until) {
+			continue
+		}
+		bucket := int(e.Timestamp.Sub(opts.Since) / opts.Bucket)
+		group := make([]string, len(opts.GroupBy))
+		for i, d := range opts.GroupBy {
+			group[i] = histogramValue(e, d)
+		}
+		key := fmt.Sprintf("%d\x00%s", bucket, strings.Join(group, "\x00"))
+		if c, ok := counts[key]; ok {
+			c.count += occurrences(e)
+			continue
+		}
+		counts[key] = &histogramCount{bucket: bucket, group: group, count: occurrences(e)}
+	}
+	final := make([]histogramCount, 0, len(counts))
+	for _, c := range counts {
+		final = append(final, *c)
+	}
+	return buildEventHistogram(final, opts)
+}
+
+// occurrences is how many events a row stands for: an aggregated K8s Event counts every
+// recurrence, matching histogramSQL
+func occurrences(e TimelineEvent) int {
+	return max(int(e.Count), 1)
+}
+
+// histogramValue is an event's value for a dimension, matching histogramColumnSQL
+func histogramValue(e TimelineEvent, d HistogramDimension) string {
+	switch d {
+	case DimensionKind:
+		if e.Group != "" {
+			return e.Kind + "." + e.Group
+		}
+		return e.Kind
+	case DimensionNamespace:
+		return e.Namespace
+	case DimensionHealth:
+		return string(e.HealthState)
+	case DimensionOwner:
+		if e.Owner != nil && e.Owner.Name != "" {
+			return ResourceKey(e.Owner.Kind, e.Namespace, e.Owner.Name)
+		}
+		return ResourceKey(e.Kind, e.Namespace, e.Name)
+	}
+	return ""
+}
+
+// buildEventHistogram assembles per-bucket group counts into series, busiest first
+func buildEventHistogram(counts []histogramCount, opts HistogramOptions) *EventHistogram {
+	if opts.Top <= 0 {
+		opts.Top = DefaultHistogramSeries
+	}
+	n := int((opts.Until.Sub(opts.Since) + opts.Bucket - 1) / opts.Bucket)
+	histogram := &EventHistogram{
+		Since:         opts.Since,
+		Until:         opts.Until,
+		BucketSeconds: int64(opts.Bucket / time.Second),
+		Buckets:       make([]time.Time, n),
+		GroupBy:       opts.GroupBy,
+		Series:        make([]HistogramSeries, 0),
+	}
+	for i := range histogram.Buckets {
+		histogram.Buckets[i] = opts.Since.Add(time.Duration(i) * opts.Bucket)
+	}
+
+	series := make(map[string]*HistogramSeries)
+	keys := make(map[*HistogramSeries]string)
+	for _, c := range counts {
+		if c.bucket < 0 || c.bucket >= n {
+			continue
+		}
+		key := strings.Join(c.group, "\x00")
+		s := series[key]
+		if s == nil {
+			s = &HistogramSeries{Group: make(map[string]string, len(opts.GroupBy)), Counts: make([]int, n)}
+			for i, d := range opts.GroupBy {
+				s.Group[string(d)] = c.group[i]
+			}
+			series[key] = s
+			keys[s] = key
+		}
+		s.Counts[c.bucket] += c.count
+		s.Total += c.count
+		histogram.Total += c.count
+	}
+
+	sorted := make([]*HistogramSeries, 0, len(series))
+	for _, s := range series {
+		sorted = append(sorted, s)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Total != sorted[j].Total {
+			return sorted[i].Total > sorted[j].Total
+		}
+		return keys[sorted[i]] < keys[sorted[j]]
+	})
+	for i, s := range sorted {
+		if i < opts.Top {
+			histogram.Series = append(histogram.Series, *s)
+			continue
+		}
+		if histogram.Other == nil {
+			histogram.Other = make([]int, n)
+		}
+		for b, count := range s.Counts {
+			histogram.Other[b] += count
+		}
+		histogram.OmittedSeries++
+	}
+	return histogram
+}
+
+// histogramColumnSQL is the SQL expression for a dimension, common to the SQLite and
+// PostgreSQL schemas (both name the columns alike)
+func histogramColumnSQL(d HistogramDimension) string {
+	switch d {
+	case DimensionKind:
+		return "kind || CASE WHEN COALESCE(api_group, '') <> '' THEN '.' || api_group ELSE '' END"
+	case DimensionNamespace:
+		return "COALESCE(namespace, '')"
+	case DimensionHealth:
+		return "COALESCE(health_state, '')"
+	case DimensionOwner:
+		return "CASE WHEN COALESCE(owner_name, '') <> '' " +
+			"THEN COALESCE(owner_kind, '') || '/' || COALESCE(namespace, '') || '/' || owner_name " +
+			"ELSE kind || '/' || COALESCE(namespace, '') || '/' || name END"
+	}
+	return "''"
+}
+
+// histogramSQL builds the per-bucket count query, summing the stored occurrence count so
+// an aggregated K8s Event weighs as much as its recurrences. bucketExpr computes a row's
+// bucket index; tsColumn, since and until bound the window in the store's timestamp format.
+func histogramSQL(opts HistogramOptions, table, tsColumn, bucketExpr string, since, until any, arg func(any) string) string {
+	query := strings.Builder{}
+	query.WriteString("SELECT " + bucketExpr)
+	for _, d := range opts.GroupBy {
+		query.WriteString(", " + histogramColumnSQL(d))
+	}
+	query.WriteString(", SUM(CASE WHEN count > 0 THEN count ELSE 1 END) FROM " + table)
+	query.WriteString(" WHERE " + tsColumn + " >= " + arg(since) + " AND " + tsColumn + " < " + arg(until))
+	if opts.Namespace != "" {
+		query.WriteString(" AND namespace = " + arg(opts.Namespace))
+	}
+	if len(opts.Kinds) > 0 {
+		query.WriteString(" AND " + kindFilterSQL(opts.Kinds, arg))
+	}
+	if len(opts.Sources) > 0 {
+		placeholders := make([]string, len(opts.Sources))
+		for i, src := range opts.Sources {
+			placeholders[i] = arg(string(src))
+		}
+		query.WriteString(" AND source IN (" + strings.Join(placeholders, ",") + ")")
+	}
+	query.WriteString(" GROUP BY 1")
+	for i := range opts.GroupBy {
+		query.WriteString(fmt.Sprintf(", %d", i+2))
+	}
+	return query.String()
+}
+
+// scanHistogramCounts reads rows of (bucket, group values..., count)
+func scanHistogramCounts(rows *sql.Rows, dims int) ([]histogramCount, error) {
+	defer rows.Close()
+	var counts []histogramCount
+	for rows.Next() {
+		var bucket, count int64
+		group := make([]string, dims)
+		dest := make([]any, 0, dims+2)
+		dest = append(dest, &bucket)
+		for i := range group {
+			dest = append(dest, &group[i])
+		}
+		dest = append(dest, &count)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		counts = append(counts, histogramCount{bucket: int(bucket), group: group, count: int(count)})
+	}
+	return counts, rows.Err()
+}
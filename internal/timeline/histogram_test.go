@@ -0,0 +1,146 @@
+package timeline
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func histogramFixture(t0 time.Time) []TimelineEvent {
+	var events []TimelineEvent
+	add := func(min int, kind, group, ns, name string, owner *OwnerInfo, health HealthState) {
+		events = append(events, TimelineEvent{
+			ID: fmt.Sprintf("e%d", len(events)), Timestamp: t0.Add(time.Duration(min) * time.Minute), Source: SourceInformer,
+			Kind: kind, Group: group, Namespace: ns, Name: name, EventType: EventTypeUpdate, Owner: owner, HealthState: health,
+		})
+	}
+	rs := &OwnerInfo{Kind: "ReplicaSet", Name: "web-7d4"}
+	// A crash-looping pod: 12 events an hour for two hours
+	for i := 0; i < 24; i++ {
+		add(i*5, "Pod", "", "shop", "web-7d4-x", rs, HealthUnhealthy)
+	}
+	add(30, "Pod", "", "shop", "web-7d4-y", rs, HealthHealthy)
+	add(30, "Deployment", "", "shop", "web", nil, HealthDegraded)
+	add(70, "Application", "argoproj.io", "argocd", "shop", nil, HealthHealthy)
+	add(100, "Node", "", "", "node-1", nil, "")
+	add(-5, "Pod", "", "shop", "early", nil, "")  // Before the window
+	add(120, "Pod", "", "shop", "late", nil, "")  // Until is exclusive
+	add(90, "Pod", "", "other", "skip", nil, "")  // Another namespace, filtered in SQL only
+	events[len(events)-1].Source = SourceK8sEvent // Sources aren't filtered below
+	return events
+}
+
+func TestBuildEventHistogram(t *testing.T) {
+	t0 := time.Date(2026, 5, 4, 0, 0, 0, 0, time.UTC)
+	opts := HistogramOptions{Since: t0, Until: t0.Add(2 * time.Hour), Bucket: time.Hour}
+
+	h := BuildEventHistogram(histogramFixture(t0), opts)
+	if len(h.Buckets) != 2 || h.BucketSeconds != 3600 || h.Total != 29 {
+		t.Fatalf("buckets = %d, bucketSeconds = %d, total = %d", len(h.Buckets), h.BucketSeconds, h.Total)
+	}
+	if len(h.Series) != 4 {
+		t.Fatalf("series = %+v", h.Series)
+	}
+	if s := h.Series[0]; s.Group["kind"] != "Pod" || s.Total != 26 || s.Counts[0] != 13 || s.Counts[1] != 13 {
+		t.Errorf("first series = %+v, want 26 Pod events", s)
+	}
+	if s := h.Series[1]; s.Group["kind"] != "Application.argoproj.io" || s.Counts[1] != 1 {
+		t.Errorf("second series = %+v, want the Application qualified with its group", s)
+	}
+
+	opts.GroupBy = []HistogramDimension{DimensionOwner}
+	opts.Top = 1
+	h = BuildEventHistogram(histogramFixture(t0), opts)
+	if len(h.Series) != 1 || h.Series[0].Group["owner"] != "ReplicaSet/shop/web-7d4" || h.Series[0].Total != 25 {
+		t.Errorf("owner series = %+v, want the ReplicaSet's 25 pod events", h.Series)
+	}
+	if h.OmittedSeries != 4 || !reflect.DeepEqual(h.Other, []int{1, 3}) {
+		t.Errorf("omitted = %d, other = %v", h.OmittedSeries, h.Other)
+	}
+}
+
+func TestSQLiteStore_HistogramMatchesBuild(t *testing.T) {
+	store, cleanup := createTestSQLiteStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	t0 := time.Date(2026, 5, 4, 0, 0, 0, 0, time.UTC)
+	events := histogramFixture(t0)
+	if err := store.AppendBatch(ctx, events); err != nil {
+		t.Fatalf("AppendBatch failed: %v", err)
+	}
+
+	for _, groupBy := range [][]HistogramDimension{
+		{DimensionKind},
+		{DimensionNamespace, DimensionHealth},
+		{DimensionOwner},
+	} {
+		opts := HistogramOptions{Since: t0, Until: t0.Add(2 * time.Hour), Bucket: 30 * time.Minute, GroupBy: groupBy, Top: 3}
+		counts, err := store.histogramCounts(ctx, opts)
+		if err != nil {
+			t.Fatalf("histogramCounts(%v) failed: %v", groupBy, err)
+		}
+		got := buildEventHistogram(counts, opts)
+		want := BuildEventHistogram(events, opts)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("group by %v:\nSQL    %+v\nmemory %+v", groupBy, got, want)
+		}
+	}
+
+	opts := HistogramOptions{Since: t0, Until: t0.Add(2 * time.Hour), Bucket: time.Hour, GroupBy: []HistogramDimension{DimensionKind},
+		Namespace: "shop", Kinds: []string{"Pod"}, Sources: []EventSource{SourceInformer}}
+	counts, err := store.histogramCounts(ctx, opts)
+	if err != nil {
+		t.Fatalf("filtered histogramCounts failed: %v", err)
+	}
+	if h := buildEventHistogram(counts, opts); h.Total != 25 || len(h.Series) != 1 {
+		t.Errorf("filtered histogram = %+v, want 25 shop Pod events", h)
+	}
+}
+
+func TestSQLiteStore_HistogramCountsOccurrences(t *testing.T) {
+	store, cleanup := createTestSQLiteStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	t0 := time.Date(2026, 5, 4, 0, 0, 0, 0, time.UTC)
+	events := []TimelineEvent{
+		{ID: "agg", Timestamp: t0.Add(10 * time.Minute), Source: SourceK8sEvent, Kind: "Pod", Namespace: "shop", Name: "web",
+			EventType: "Warning", Reason: "BackOff", Count: 40, Aggregate: &EventAggregate{FirstSeen: t0, LastSeen: t0.Add(10 * time.Minute)}},
+		{ID: "once", Timestamp: t0.Add(20 * time.Minute), Source: SourceInformer, Kind: "Pod", Namespace: "shop", Name: "web",
+			EventType: EventTypeUpdate},
+	}
+	if err := store.AppendBatch(ctx, events); err != nil {
+		t.Fatalf("AppendBatch failed: %v", err)
+	}
+
+	opts := HistogramOptions{Since: t0, Until: t0.Add(time.Hour), Bucket: time.Hour, GroupBy: []HistogramDimension{DimensionKind}}
+	counts, err := store.histogramCounts(ctx, opts)
+	if err != nil {
+		t.Fatalf("histogramCounts failed: %v", err)
+	}
+	got := buildEventHistogram(counts, opts)
+	if got.Total != 41 {
+		t.Errorf("SQL total = %d, want the aggregate's 40 occurrences plus 1", got.Total)
+	}
+	if want := BuildEventHistogram(events, opts); !reflect.DeepEqual(got, want) {
+		t.Errorf("SQL %+v\nmemory %+v", got, want)
+	}
+}
+
+func TestHistogramOptionsValidate(t *testing.T) {
+	t0 := time.Now()
+	for _, opts := range []HistogramOptions{
+		{Since: t0, Until: t0, Bucket: time.Hour},
+		{Since: t0, Until: t0.Add(time.Hour)},
+		{Since: t0, Until: t0.Add(1000 * time.Hour), Bucket: time.Hour},
+		{Since: t0, Until: t0.Add(time.Hour), Bucket: time.Minute, GroupBy: []HistogramDimension{"reason"}},
+		{Since: t0, Until: t0.Add(time.Hour), Bucket: time.Minute, GroupBy: []HistogramDimension{DimensionKind, DimensionKind}},
+	} {
+		if err := opts.Validate(); err == nil {
+			t.Errorf("Validate(%+v) = nil, want an error", opts)
+		}
+	}
+}
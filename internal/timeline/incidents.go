@@ -0,0 +1,297 @@
+package timeline
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+// NewAuditEvent creates a TimelineEvent for a user action on a resource.
+// The action is stored in Reason, the actor in Message.
+func NewAuditEvent(kind, namespace, name, action, actor string) TimelineEvent {
+	e := NewInformerEvent(kind, namespace, name, "", EventTypeAction, "", nil, nil, nil, nil)
+	e.Source = SourceAudit
+	e.Reason = action
+	e.Message = actor
+	return e
+}
+
+// RecordAction writes an audit log line and records a user action on the timeline, where
+// incident reports use it as the first human response to an unhealthy workload
+func RecordAction(ctx context.Context, action, kind, namespace, name, actor string) {
+	RecordActionDetail(ctx, action, kind, namespace, name, actor, "")
+}
+
+// RecordActionDetail is RecordAction with a short description of the action's parameters
+// (e.g. "replicas 3 → 5"), appended to the actor in the event message
+func RecordActionDetail(ctx context.Context, action, kind, namespace, name, actor, detail string) {
+	e := NewAuditEvent(kind, namespace, name, action, actor)
+	if detail != "" {
+		log.Printf("[audit] action=%s target=%s/%s/%s actor=%s detail=%q", action, kind, namespace, name, actor, detail)
+		e.Message = actor + ": " + detail
+	} else {
+		log.Printf("[audit] action=%s target=%s/%s/%s actor=%s", action, kind, namespace, name, actor)
+	}
+	if err := RecordEventWithBroadcast(ctx, e); err != nil {
+		log.Printf("Warning: failed to record %s action on timeline: %v", action, err)
+	}
+}
+
+// Incident is a period a workload spent degraded or unhealthy, from the first unhealthy
+// signal until it was healthy again
+type Incident struct {
+	Kind        string      `json:"kind"`
+	Namespace   string      `json:"namespace"`
+	Name        string      `json:"name"`
+	Start       time.Time   `json:"start"`
+	FirstAction *time.Time  `json:"firstAction,omitempty"`
+	Action      string      `json:"action,omitempty"` // First action taken, e.g. "restart"
+	Recovered   *time.Time  `json:"recovered,omitempty"`
+	Worst       HealthState `json:"worst"`
+
+	release string // app.kubernetes.io/instance, to attribute Helm actions
+}
+
+// IncidentStats aggregates incidents. Time to detect is measured from the first unhealthy
+// signal to the first human action, time to recover to the first healthy signal after it.
+type IncidentStats struct {
+	Incidents int `json:"incidents"`
+	Actioned  int `json:"actioned"` // Incidents with a user action before recovery
+	Resolved  int `json:"resolved"`
+
+	MTTDSeconds      *float64 `json:"mttdSeconds,omitempty"`
+	MedianTTDSeconds *float64 `json:"medianTtdSeconds,omitempty"`
+	MTTRSeconds      *float64 `json:"mttrSeconds,omitempty"`
+	MedianTTRSeconds *float64 `json:"medianTtrSeconds,omitempty"`
+}
+
+// IncidentGroup is IncidentStats for one workload, namespace or month
+type IncidentGroup struct {
+	Key       string `json:"key"`
+	Kind      string `json:"kind,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+	IncidentStats
+}
+
+// IncidentReport summarizes incidents that started in [Since, Until]
+type IncidentReport struct {
+	Since       time.Time       `json:"since"`
+	Until       time.Time       `json:"until"`
+	Summary     IncidentStats   `json:"summary"`
+	ByWorkload  []IncidentGroup `json:"byWorkload"`
+	ByNamespace []IncidentGroup `json:"byNamespace"`
+	ByMonth     []IncidentGroup `json:"byMonth"` // Keyed YYYY-MM (UTC) by incident start
+	Incidents   []Incident      `json:"incidents,omitempty"`
+}
+
+// incidentKinds are the workloads whose health transitions open incidents
+var incidentKinds = []string{"Deployment", "Rollout", "DaemonSet", "StatefulSet", "Job", "CronJob", "Workflow", "CronWorkflow"}
+
+const (
+	// reportPageSize and reportMaxPages bound how many events a report reads
+	reportPageSize = 10000
+	reportMaxPages = 50
+
+	// incidentLookback is read before the report window so incidents already open at its
+	// start are recognized (and excluded) instead of appearing to start mid-incident
+	incidentLookback = 24 * time.Hour
+)
+
+// QueryIncidentReport builds an incident report from the global store
+func QueryIncidentReport(ctx context.Context, namespace string, since, until time.Time) (*IncidentReport, error) {
+	store := GetStore()
+	if store == nil {
+		return nil, fmt.Errorf("event store not initialized")
+	}
+
+	// Health signals for workloads, plus all user actions (which may target pods or releases)
+	from := since
+	if !from.IsZero() {
+		from = from.Add(-incidentLookback)
+	}
+	var events []TimelineEvent
+	for _, opts := range []QueryOptions{
+		{Namespace: namespace, Kinds: incidentKinds, Sources: []EventSource{SourceInformer}},
+		{Namespace: namespace, Sources: []EventSource{SourceAudit}},
+	} {
+		opts.Since, opts.Until, opts.Limit, opts.IncludeManaged = from, until, reportPageSize, true
+		for page := 0; page < reportMaxPages; page++ {
+			opts.Offset = page * reportPageSize
+			batch, err := store.Query(ctx, opts)
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, batch...)
+			if len(batch) < reportPageSize {
+				break
+			}
+		}
+	}
+
+	return BuildIncidentReport(events, since, until), nil
+}
+
+// BuildIncidentReport reconstructs incidents from health transitions and user actions
+func BuildIncidentReport(events []TimelineEvent, since, until time.Time) *IncidentReport {
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Timestamp.Before(events[j].Timestamp) })
+
+	open := make(map[string]*Incident)
+	var incidents []*Incident
+
+	for _, e := range events {
+		if e.Source == SourceAudit {
+			if inc := actionTarget(open, e); inc != nil && inc.FirstAction == nil {
+				ts := e.Timestamp
+				inc.FirstAction = &ts
+				inc.Action = e.Reason
+			}
+			continue
+		}
+
+		key := ResourceKey(e.Kind, e.Namespace, e.Name)
+		inc := open[key]
+		switch e.HealthState {
+		case HealthDegraded, HealthUnhealthy:
+			if inc == nil {
+				inc = &Incident{Kind: e.Kind, Namespace: e.Namespace, Name: e.Name, Start: e.Timestamp,
+					Worst: e.HealthState, release: e.Labels["app.kubernetes.io/instance"]}
+				open[key] = inc
+				incidents = append(incidents, inc)
+			}
+			inc.Worst = worseHealth(inc.Worst, e.HealthState)
+		case HealthHealthy:
+			if inc != nil {
+				ts := e.Timestamp
+				inc.Recovered = &ts
+				delete(open, key)
+			}
+		}
+		if e.EventType == EventTypeDelete && inc != nil {
+			delete(open, key) // Deleted while unhealthy: unresolved
+		}
+	}
+
+	report := &IncidentReport{
+		Since:       since,
+		Until:       until,
+		ByWorkload:  make([]IncidentGroup, 0),
+		ByNamespace: make([]IncidentGroup, 0),
+		ByMonth:     make([]IncidentGroup, 0),
+	}
+	byWorkload := make(map[string][]*Incident)
+	byNamespace := make(map[string][]*Incident)
+	byMonth := make(map[string][]*Incident)
+	var all []*Incident
+	for _, inc := range incidents {
+		if (!since.IsZero() && inc.Start.Before(since)) || (!until.IsZero() && inc.Start.After(until)) {
+			continue
+		}
+		all = append(all, inc)
+		key := ResourceKey(inc.Kind, inc.Namespace, inc.Name)
+		byWorkload[key] = append(byWorkload[key], inc)
+		byNamespace[inc.Namespace] = append(byNamespace[inc.Namespace], inc)
+		month := inc.Start.UTC().Format("2006-01")
+		byMonth[month] = append(byMonth[month], inc)
+	}
+
+	report.Summary = incidentStats(all)
+	for key, list := range byWorkload {
+		g := IncidentGroup{Key: key, Kind: list[0].Kind, Namespace: list[0].Namespace, Name: list[0].Name, IncidentStats: incidentStats(list)}
+		report.ByWorkload = append(report.ByWorkload, g)
+	}
+	for ns, list := range byNamespace {
+		report.ByNamespace = append(report.ByNamespace, IncidentGroup{Key: ns, Namespace: ns, IncidentStats: incidentStats(list)})
+	}
+	for month, list := range byMonth {
+		report.ByMonth = append(report.ByMonth, IncidentGroup{Key: month, IncidentStats: incidentStats(list)})
+	}
+
+	// Most incidents first; months chronologically
+	byCount := func(groups []IncidentGroup) func(i, j int) bool {
+		return func(i, j int) bool {
+			if groups[i].Incidents != groups[j].Incidents {
+				return groups[i].Incidents > groups[j].Incidents
+			}
+			return groups[i].Key < groups[j].Key
+		}
+	}
+	sort.Slice(report.ByWorkload, byCount(report.ByWorkload))
+	sort.Slice(report.ByNamespace, byCount(report.ByNamespace))
+	sort.Slice(report.ByMonth, func(i, j int) bool { return report.ByMonth[i].Key < report.ByMonth[j].Key })
+
+	report.Incidents = make([]Incident, len(all))
+	for i, inc := range all {
+		report.Incidents[i] = *inc
+	}
+	return report
+}
+
+// actionTarget finds the open incident a user action responds to: the workload itself,
+// one of its pods or ReplicaSets (named <workload>-...), or the Helm release that installed it
+func actionTarget(open map[string]*Incident, action TimelineEvent) *Incident {
+	if inc := open[ResourceKey(action.Kind, action.Namespace, action.Name)]; inc != nil {
+		return inc
+	}
+	var match *Incident
+	for _, inc := range open {
+		if inc.Namespace != action.Namespace {
+			continue
+		}
+		switch action.Kind {
+		case "Pod", "ReplicaSet", "Job":
+			if !strings.HasPrefix(action.Name, inc.Name+"-") {
+				continue
+			}
+		case "HelmRelease":
+			if inc.release != action.Name {
+				continue
+			}
+		default:
+			continue
+		}
+		// Prefer the longest (most specific) workload name
+		if match == nil || len(inc.Name) > len(match.Name) {
+			match = inc
+		}
+	}
+	return match
+}
+
+func incidentStats(incidents []*Incident) IncidentStats {
+	stats := IncidentStats{Incidents: len(incidents)}
+	var ttd, ttr []float64
+	for _, inc := range incidents {
+		if inc.FirstAction != nil {
+			stats.Actioned++
+			ttd = append(ttd, inc.FirstAction.Sub(inc.Start).Seconds())
+		}
+		if inc.Recovered != nil {
+			stats.Resolved++
+			ttr = append(ttr, inc.Recovered.Sub(inc.Start).Seconds())
+		}
+	}
+	stats.MTTDSeconds, stats.MedianTTDSeconds = meanMedian(ttd)
+	stats.MTTRSeconds, stats.MedianTTRSeconds = meanMedian(ttr)
+	return stats
+}
+
+func meanMedian(values []float64) (mean, median *float64) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	sort.Float64s(values)
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	m := sum / float64(len(values))
+	med := values[len(values)/2]
+	if len(values)%2 == 0 {
+		med = (values[len(values)/2-1] + values[len(values)/2]) / 2
+	}
+	return &m, &med
+}
@@ -0,0 +1,62 @@
+package timeline
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuildIncidentReport(t *testing.T) {
+	t0 := time.Date(2026, 3, 31, 23, 50, 0, 0, time.UTC)
+	at := func(min int) time.Time { return t0.Add(time.Duration(min) * time.Minute) }
+	health := func(min int, kind, name string, h HealthState) TimelineEvent {
+		return TimelineEvent{Timestamp: at(min), Source: SourceInformer, Kind: kind, Namespace: "shop", Name: name,
+			EventType: EventTypeUpdate, HealthState: h, Labels: map[string]string{"app.kubernetes.io/instance": "shop"}}
+	}
+	action := func(min int, kind, name, what string) TimelineEvent {
+		e := NewAuditEvent(kind, "shop", name, what, "127.0.0.1")
+		e.Timestamp = at(min)
+		return e
+	}
+
+	events := []TimelineEvent{
+		// web: degraded at 0, pod deleted at 4, recovered at 10
+		health(0, "Deployment", "web", HealthDegraded),
+		health(2, "Deployment", "web", HealthUnhealthy),
+		action(4, "Pod", "web-7d9f-abcde", "delete"),
+		action(6, "Deployment", "web", "restart"),
+		health(10, "Deployment", "web", HealthHealthy),
+		// web again in April: Helm rollback at 22, recovered at 40
+		health(20, "Deployment", "web", HealthDegraded),
+		action(22, "HelmRelease", "shop", "helm-rollback"),
+		health(40, "Deployment", "web", HealthHealthy),
+		// db: unhealthy and never acted on or recovered
+		health(30, "StatefulSet", "db", HealthUnhealthy),
+		// An action with no open incident is ignored
+		action(50, "Deployment", "web", "edit"),
+	}
+
+	report := BuildIncidentReport(events, t0.Add(-time.Hour), at(60))
+
+	s := report.Summary
+	if s.Incidents != 3 || s.Actioned != 2 || s.Resolved != 2 {
+		t.Fatalf("summary = %+v, want 3 incidents, 2 actioned, 2 resolved", s)
+	}
+	if *s.MTTDSeconds != 180 || *s.MTTRSeconds != 900 {
+		t.Errorf("MTTD = %v, MTTR = %v, want 180s and 900s", *s.MTTDSeconds, *s.MTTRSeconds)
+	}
+	if first := report.Incidents[0]; first.Action != "delete" || first.Worst != HealthUnhealthy {
+		t.Errorf("first incident = %+v, want action delete and worst unhealthy", first)
+	}
+
+	if len(report.ByWorkload) != 2 || report.ByWorkload[0].Name != "web" || report.ByWorkload[0].Incidents != 2 {
+		t.Errorf("byWorkload = %+v", report.ByWorkload)
+	}
+	if len(report.ByMonth) != 2 || report.ByMonth[0].Key != "2026-03" || report.ByMonth[1].Incidents != 2 {
+		t.Errorf("byMonth = %+v", report.ByMonth)
+	}
+
+	// Incidents starting before the window are excluded even though their events are read
+	if late := BuildIncidentReport(events, at(15), at(60)); late.Summary.Incidents != 2 {
+		t.Errorf("windowed report has %d incidents, want 2", late.Summary.Incidents)
+	}
+}
@@ -2,24 +2,37 @@ package timeline
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // StoreType identifies the storage backend
 type StoreType string
 
 const (
-	StoreTypeMemory StoreType = "memory"
-	StoreTypeSQLite StoreType = "sqlite"
+	StoreTypeMemory   StoreType = "memory"
+	StoreTypeSQLite   StoreType = "sqlite"
+	StoreTypePostgres StoreType = "postgres"
 )
 
 // StoreConfig holds configuration for the event store
 type StoreConfig struct {
-	Type    StoreType
-	Path    string // For SQLite: database file path
-	MaxSize int    // For Memory: ring buffer size
+	Type     StoreType
+	Path     string        // For SQLite: database file path
+	MaxSize  int           // For Memory: ring buffer size. For Postgres: max events retained
+	DSN      string        // For Postgres: connection string (empty = PG* environment variables)
+	MaxConns int           // For Postgres: connection pool size
+	MaxAge   time.Duration // For SQLite and Postgres: delete events older than this (0 = no age limit)
+	Scope    string        // Cluster/context the seen-resource set belongs to (see EventStore.ResetSeen)
+
+	// For SQLite: further retention limits applied by background compaction (0 = off)
+	MaxRowsPerKind  int
+	MaxDBSize       int64         // Bytes
+	DownsampleAfter time.Duration // Collapse each resource's hourly updates once older than this
 }
 
 // DefaultStoreConfig returns sensible defaults
@@ -39,8 +52,46 @@ var (
 	// Event broadcast for SSE
 	subscribers   []chan TimelineEvent
 	subscribersMu sync.RWMutex
+
+	// Writes hold writeMu's read lock so Drain can wait for them before the store closes
+	writeMu  sync.RWMutex
+	draining atomic.Bool
 )
 
+// ErrDraining is returned for writes that arrive after Drain
+var ErrDraining = errors.New("timeline is shutting down")
+
+// beginWrite returns the store for a write, which the caller ends with writeMu.RUnlock
+func beginWrite() (EventStore, error) {
+	if draining.Load() {
+		return nil, ErrDraining
+	}
+	writeMu.RLock()
+	store := GetStore()
+	if store == nil {
+		writeMu.RUnlock()
+		return nil, fmt.Errorf("event store not initialized")
+	}
+	return store, nil
+}
+
+// Drain rejects new writes with ErrDraining and waits until in-flight writes have reached
+// the store, so closing it afterwards loses nothing already recorded
+func Drain(ctx context.Context) error {
+	draining.Store(true)
+	done := make(chan struct{})
+	go func() {
+		writeMu.Lock()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("timeline writes still in flight: %w", ctx.Err())
+	}
+}
+
 // InitStore initializes the global event store
 func InitStore(cfg StoreConfig) error {
 	var initErr error
@@ -58,8 +109,24 @@ func InitStore(cfg StoreConfig) error {
 				initErr = fmt.Errorf("failed to create SQLite store: %w", err)
 				return
 			}
+			policy := RetentionPolicy{
+				MaxAge:          cfg.MaxAge,
+				MaxRowsPerKind:  cfg.MaxRowsPerKind,
+				MaxDBSize:       cfg.MaxDBSize,
+				DownsampleAfter: cfg.DownsampleAfter,
+			}
+			store.StartRetention(policy)
 			globalStore = store
-			log.Printf("Initialized SQLite event store at %s", cfg.Path)
+			log.Printf("Initialized SQLite event store at %s (retention: %s)", cfg.Path, policy)
+
+		case StoreTypePostgres:
+			store, err := NewPostgresStore(cfg)
+			if err != nil {
+				initErr = fmt.Errorf("failed to create PostgreSQL store: %w", err)
+				return
+			}
+			globalStore = store
+			log.Printf("Initialized PostgreSQL event store (max %d events, max age %s)", cfg.MaxSize, cfg.MaxAge)
 
 		case StoreTypeMemory:
 			fallthrough
@@ -71,6 +138,10 @@ func InitStore(cfg StoreConfig) error {
 			globalStore = NewMemoryStore(maxSize)
 			log.Printf("Initialized in-memory event store (max %d events)", maxSize)
 		}
+
+		if err := globalStore.ResetSeen(cfg.Scope); err != nil {
+			log.Printf("Warning: failed to load seen resources for %q: %v", cfg.Scope, err)
+		}
 	})
 	return initErr
 }
@@ -93,9 +164,11 @@ func ResetStore() {
 		globalStore = nil
 	}
 	globalStoreOnce = sync.Once{}
+	resetK8sEventAggregator()
 }
 
-// ReinitStore reinitializes the event store after a context switch
+// ReinitStore reinitializes the event store after a context switch, with cfg.Scope set
+// to the new context so seen resources from the previous one don't suppress events.
 // Must call ResetStore first
 func ReinitStore(cfg StoreConfig) error {
 	return InitStore(cfg)
@@ -103,19 +176,21 @@ func ReinitStore(cfg StoreConfig) error {
 
 // RecordEvent is a convenience function to record an event to the global store
 func RecordEvent(ctx context.Context, event TimelineEvent) error {
-	store := GetStore()
-	if store == nil {
-		return fmt.Errorf("event store not initialized")
+	store, err := beginWrite()
+	if err != nil {
+		return err
 	}
+	defer writeMu.RUnlock()
 	return store.Append(ctx, event)
 }
 
 // RecordEvents is a convenience function to record multiple events to the global store
 func RecordEvents(ctx context.Context, events []TimelineEvent) error {
-	store := GetStore()
-	if store == nil {
-		return fmt.Errorf("event store not initialized")
+	store, err := beginWrite()
+	if err != nil {
+		return err
 	}
+	defer writeMu.RUnlock()
 	return store.AppendBatch(ctx, events)
 }
 
@@ -179,10 +254,11 @@ func broadcastEvent(event TimelineEvent) {
 
 // RecordEventWithBroadcast records an event and broadcasts it to subscribers
 func RecordEventWithBroadcast(ctx context.Context, event TimelineEvent) error {
-	store := GetStore()
-	if store == nil {
-		return fmt.Errorf("event store not initialized")
+	store, err := beginWrite()
+	if err != nil {
+		return err
 	}
+	defer writeMu.RUnlock()
 	if err := store.Append(ctx, event); err != nil {
 		return err
 	}
@@ -192,10 +268,11 @@ func RecordEventWithBroadcast(ctx context.Context, event TimelineEvent) error {
 
 // RecordEventsWithBroadcast records multiple events and broadcasts them to subscribers
 func RecordEventsWithBroadcast(ctx context.Context, events []TimelineEvent) error {
-	store := GetStore()
-	if store == nil {
-		return fmt.Errorf("event store not initialized")
+	store, err := beginWrite()
+	if err != nil {
+		return err
 	}
+	defer writeMu.RUnlock()
 	if err := store.AppendBatch(ctx, events); err != nil {
 		return err
 	}
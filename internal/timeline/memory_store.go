@@ -9,14 +9,15 @@ import (
 // MemoryStore is an in-memory implementation of EventStore using a ring buffer.
 // Suitable for local development and testing. Events are lost on restart.
 type MemoryStore struct {
-	records       []TimelineEvent
-	maxSize       int
-	head          int // next write position
-	count         int
-	mu            sync.RWMutex
-	seenResources map[string]bool
-	seenMu        sync.RWMutex
-	filterCache   map[string]*CompiledFilter
+	records     []TimelineEvent
+	maxSize     int
+	head        int // next write position
+	count       int
+	mu          sync.RWMutex
+	seen        *seenSet
+	filterCache map[string]*CompiledFilter
+	// k8sEvents maps K8s event IDs to their slots, so recurrences update in place
+	k8sEvents map[string]int
 }
 
 // NewMemoryStore creates a new in-memory event store
@@ -25,10 +26,11 @@ func NewMemoryStore(maxSize int) *MemoryStore {
 		maxSize = 1000
 	}
 	return &MemoryStore{
-		records:       make([]TimelineEvent, maxSize),
-		maxSize:       maxSize,
-		seenResources: make(map[string]bool),
-		filterCache:   make(map[string]*CompiledFilter),
+		records:     make([]TimelineEvent, maxSize),
+		maxSize:     maxSize,
+		seen:        newSeenSet(seenCapacity),
+		filterCache: make(map[string]*CompiledFilter),
+		k8sEvents:   make(map[string]int),
 	}
 }
 
@@ -37,11 +39,7 @@ func (m *MemoryStore) Append(ctx context.Context, event TimelineEvent) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	m.records[m.head] = event
-	m.head = (m.head + 1) % m.maxSize
-	if m.count < m.maxSize {
-		m.count++
-	}
+	m.appendLocked(event)
 	return nil
 }
 
@@ -51,15 +49,30 @@ func (m *MemoryStore) AppendBatch(ctx context.Context, events []TimelineEvent) e
 	defer m.mu.Unlock()
 
 	for _, event := range events {
-		m.records[m.head] = event
-		m.head = (m.head + 1) % m.maxSize
-		if m.count < m.maxSize {
-			m.count++
-		}
+		m.appendLocked(event)
 	}
 	return nil
 }
 
+// appendLocked writes an event at the head, or over the stored K8s event with the same ID
+func (m *MemoryStore) appendLocked(event TimelineEvent) {
+	if event.Source == SourceK8sEvent {
+		if idx, ok := m.k8sEvents[event.ID]; ok {
+			m.records[idx] = event
+			return
+		}
+		m.k8sEvents[event.ID] = m.head
+	}
+	if old := m.records[m.head]; old.Source == SourceK8sEvent && m.k8sEvents[old.ID] == m.head {
+		delete(m.k8sEvents, old.ID)
+	}
+	m.records[m.head] = event
+	m.head = (m.head + 1) % m.maxSize
+	if m.count < m.maxSize {
+		m.count++
+	}
+}
+
 // Query retrieves events matching the given options
 func (m *MemoryStore) Query(ctx context.Context, opts QueryOptions) ([]TimelineEvent, error) {
 	// Get filter preset BEFORE acquiring the read lock to avoid deadlock
@@ -225,32 +238,30 @@ func (m *MemoryStore) GetChangesForOwner(ctx context.Context, ownerKind, ownerNa
 }
 
 // MarkResourceSeen records that a resource has been seen
-func (m *MemoryStore) MarkResourceSeen(kind, namespace, name string) {
-	m.seenMu.Lock()
-	defer m.seenMu.Unlock()
-	m.seenResources[ResourceKey(kind, namespace, name)] = true
+func (m *MemoryStore) MarkResourceSeen(kind, namespace, name, uid string) {
+	m.seen.mark(ResourceKey(kind, namespace, name), uid)
 }
 
 // IsResourceSeen checks if a resource has been seen before
-func (m *MemoryStore) IsResourceSeen(kind, namespace, name string) bool {
-	m.seenMu.RLock()
-	defer m.seenMu.RUnlock()
-	return m.seenResources[ResourceKey(kind, namespace, name)]
+func (m *MemoryStore) IsResourceSeen(kind, namespace, name, uid string) bool {
+	return m.seen.has(ResourceKey(kind, namespace, name), uid)
 }
 
 // ClearResourceSeen removes a resource from the seen set
 func (m *MemoryStore) ClearResourceSeen(kind, namespace, name string) {
-	m.seenMu.Lock()
-	defer m.seenMu.Unlock()
-	delete(m.seenResources, ResourceKey(kind, namespace, name))
+	m.seen.remove(ResourceKey(kind, namespace, name))
+}
+
+// ResetSeen forgets every seen resource; the set isn't persisted, so scope is unused
+func (m *MemoryStore) ResetSeen(scope string) error {
+	m.seen.reset()
+	return nil
 }
 
 // Stats returns storage statistics
 func (m *MemoryStore) Stats() StoreStats {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
-	m.seenMu.RLock()
-	defer m.seenMu.RUnlock()
 
 	var oldest, newest time.Time
 	for i := 0; i < m.count; i++ {
@@ -271,7 +282,7 @@ func (m *MemoryStore) Stats() StoreStats {
 		TotalEvents:   int64(m.count),
 		OldestEvent:   oldest,
 		NewestEvent:   newest,
-		SeenResources: len(m.seenResources),
+		SeenResources: m.seen.len(),
 	}
 }
 
@@ -300,17 +311,8 @@ func (m *MemoryStore) matchesFilters(event *TimelineEvent, opts QueryOptions, cf
 		return false
 	}
 
-	if len(opts.Kinds) > 0 {
-		found := false
-		for _, k := range opts.Kinds {
-			if event.Kind == k {
-				found = true
-				break
-			}
-		}
-		if !found {
-			return false
-		}
+	if len(opts.Kinds) > 0 && !matchesKind(event, opts.Kinds) {
+		return false
 	}
 
 	if len(opts.Sources) > 0 {
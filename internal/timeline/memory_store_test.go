@@ -115,6 +115,31 @@ func TestMemoryStore_Query_Kinds(t *testing.T) {
 	}
 }
 
+func TestMemoryStore_Query_QualifiedKinds(t *testing.T) {
+	store := NewMemoryStore(100)
+	ctx := context.Background()
+
+	events := []TimelineEvent{
+		{ID: "app-1", Timestamp: time.Now(), Kind: "Application", Group: "argoproj.io", Namespace: "argocd", Name: "web", EventType: EventTypeAdd, Source: SourceInformer},
+		{ID: "app-2", Timestamp: time.Now(), Kind: "Application", Group: "app.k8s.io", Namespace: "argocd", Name: "web", EventType: EventTypeAdd, Source: SourceInformer},
+	}
+	_ = store.AppendBatch(ctx, events)
+
+	result, err := store.Query(ctx, QueryOptions{Kinds: []string{"Application.argoproj.io"}, Limit: 10, IncludeManaged: true})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(result) != 1 || result[0].ID != "app-1" {
+		t.Errorf("Expected only the argoproj.io Application, got %+v", result)
+	}
+
+	// A bare kind matches every group
+	result, _ = store.Query(ctx, QueryOptions{Kinds: []string{"Application"}, Limit: 10, IncludeManaged: true})
+	if len(result) != 2 {
+		t.Errorf("Expected 2 Application events, got %d", len(result))
+	}
+}
+
 func TestMemoryStore_Query_Since(t *testing.T) {
 	store := NewMemoryStore(100)
 	ctx := context.Background()
@@ -170,15 +195,15 @@ func TestMemoryStore_ResourceSeen(t *testing.T) {
 	store := NewMemoryStore(100)
 
 	// Initially not seen
-	if store.IsResourceSeen("Pod", "default", "test-pod") {
+	if store.IsResourceSeen("Pod", "default", "test-pod", "uid-1") {
 		t.Error("Resource should not be seen initially")
 	}
 
 	// Mark as seen
-	store.MarkResourceSeen("Pod", "default", "test-pod")
+	store.MarkResourceSeen("Pod", "default", "test-pod", "uid-1")
 
 	// Now should be seen
-	if !store.IsResourceSeen("Pod", "default", "test-pod") {
+	if !store.IsResourceSeen("Pod", "default", "test-pod", "uid-1") {
 		t.Error("Resource should be seen after marking")
 	}
 
@@ -186,7 +211,7 @@ func TestMemoryStore_ResourceSeen(t *testing.T) {
 	store.ClearResourceSeen("Pod", "default", "test-pod")
 
 	// Should not be seen again
-	if store.IsResourceSeen("Pod", "default", "test-pod") {
+	if store.IsResourceSeen("Pod", "default", "test-pod", "uid-1") {
 		t.Error("Resource should not be seen after clearing")
 	}
 }
@@ -0,0 +1,342 @@
+package timeline
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// Metrics resolutions: raw samples as polled, then 5 minute and 1 hour rollups
+const (
+	MetricsRaw    time.Duration = 0
+	Metrics5m                   = 5 * time.Minute
+	Metrics1h                   = time.Hour
+	metricsRollup               = 5 * time.Minute // How often rollups and retention run
+)
+
+// metricsTiers lists the resolutions finest first; each rollup is built from the one before
+var metricsTiers = []time.Duration{MetricsRaw, Metrics5m, Metrics1h}
+
+// MetricsRetention is how long each resolution is kept. Zero values use the defaults.
+type MetricsRetention struct {
+	Raw        time.Duration
+	FiveMinute time.Duration
+	Hour       time.Duration
+}
+
+// DefaultMetricsRetention keeps a day of raw samples, a week of 5m and 90 days of 1h rollups
+var DefaultMetricsRetention = MetricsRetention{Raw: 24 * time.Hour, FiveMinute: 7 * 24 * time.Hour, Hour: 90 * 24 * time.Hour}
+
+func (r MetricsRetention) withDefaults() MetricsRetention {
+	if r.Raw <= 0 {
+		r.Raw = DefaultMetricsRetention.Raw
+	}
+	if r.FiveMinute <= 0 {
+		r.FiveMinute = DefaultMetricsRetention.FiveMinute
+	}
+	if r.Hour <= 0 {
+		r.Hour = DefaultMetricsRetention.Hour
+	}
+	return r
+}
+
+// of returns the retention of a resolution
+func (r MetricsRetention) of(resolution time.Duration) time.Duration {
+	switch resolution {
+	case Metrics5m:
+		return r.FiveMinute
+	case Metrics1h:
+		return r.Hour
+	default:
+		return r.Raw
+	}
+}
+
+// String describes the retention for logs
+func (r MetricsRetention) String() string {
+	return fmt.Sprintf("raw %s, 5m %s, 1h %s", r.Raw, r.FiveMinute, r.Hour)
+}
+
+// MetricSample is one polled usage reading. Node samples have no namespace or container.
+type MetricSample struct {
+	Kind      string // "pod" or "node"
+	Namespace string
+	Name      string
+	Container string
+	Timestamp time.Time
+	CPU       int64 // Nanocores
+	Memory    int64 // Bytes
+}
+
+// MetricPoint is a stored reading. For rollups CPU and Memory are bucket averages and the
+// maxima are the highest raw samples in the bucket.
+type MetricPoint struct {
+	Timestamp time.Time
+	CPU       int64
+	Memory    int64
+	CPUMax    int64
+	MemoryMax int64
+}
+
+// MetricSeries is one container's (or a node's) points, oldest first
+type MetricSeries struct {
+	Container string
+	Points    []MetricPoint
+}
+
+// metricsMigrations version the metrics database; append new ones, never edit released ones
+var metricsMigrations = []Migration{
+	{
+		Version: 1,
+		Name:    "metric_samples and metric_rollups tables",
+		Up: `
+	CREATE TABLE IF NOT EXISTS metric_samples (
+		scope TEXT NOT NULL,
+		resolution INTEGER NOT NULL,
+		kind TEXT NOT NULL,
+		namespace TEXT NOT NULL,
+		name TEXT NOT NULL,
+		container TEXT NOT NULL,
+		ts INTEGER NOT NULL,
+		cpu INTEGER NOT NULL,
+		memory INTEGER NOT NULL,
+		cpu_max INTEGER NOT NULL,
+		memory_max INTEGER NOT NULL,
+		samples INTEGER NOT NULL,
+		PRIMARY KEY (scope, kind, namespace, name, resolution, ts, container)
+	);
+	CREATE INDEX IF NOT EXISTS idx_metric_samples_resolution_ts ON metric_samples(resolution, ts);
+
+	CREATE TABLE IF NOT EXISTS metric_rollups (
+		resolution INTEGER PRIMARY KEY,
+		through INTEGER NOT NULL
+	);
+	`,
+		Down: `DROP TABLE IF EXISTS metric_rollups; DROP TABLE IF EXISTS metric_samples;`,
+	},
+}
+
+// MetricsStore persists pod and node usage samples in SQLite, rolling them up into
+// coarser resolutions as they age so multi-day trends stay cheap to keep and query.
+// Samples are scoped by cluster, like seen resources.
+type MetricsStore struct {
+	db        *sql.DB
+	path      string
+	retention MetricsRetention
+	stopCh    chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewMetricsStore opens the metrics database at dbPath and starts rolling up samples
+func NewMetricsStore(dbPath string, retention MetricsRetention) (*MetricsStore, error) {
+	db, err := openSQLite(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	if err := sqliteQuickCheck(ctx, db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%s: %w (move the file aside to start over)", dbPath, err)
+	}
+	m := &migrator{db: db, dialect: sqliteDialect("metrics_schema_migrations"), migrations: metricsMigrations}
+	if _, _, err := m.up(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize schema: %w", err)
+	}
+
+	s := &MetricsStore{db: db, path: dbPath, retention: retention.withDefaults(), stopCh: make(chan struct{})}
+	s.wg.Add(1)
+	go s.maintainLoop()
+	return s, nil
+}
+
+// Retention returns the store's retention
+func (s *MetricsStore) Retention() MetricsRetention {
+	return s.retention
+}
+
+// Close stops background maintenance and closes the database
+func (s *MetricsStore) Close() error {
+	close(s.stopCh)
+	s.wg.Wait()
+	return s.db.Close()
+}
+
+func (s *MetricsStore) maintainLoop() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(metricsRollup)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			if err := s.Maintain(ctx, time.Now()); err != nil {
+				log.Printf("Warning: metrics history maintenance failed: %v", err)
+			}
+			cancel()
+		}
+	}
+}
+
+// Write stores raw samples for a cluster
+func (s *MetricsStore) Write(ctx context.Context, scope string, samples []MetricSample) error {
+	if len(samples) == 0 {
+		return nil
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	stmt, err := tx.PrepareContext(ctx, `INSERT OR REPLACE INTO metric_samples
+		(scope, resolution, kind, namespace, name, container, ts, cpu, memory, cpu_max, memory_max, samples)
+		VALUES (?, 0, ?, ?, ?, ?, ?, ?, ?, ?, ?, 1)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for _, m := range samples {
+		if _, err := stmt.ExecContext(ctx, scope, m.Kind, m.Namespace, m.Name, m.Container,
+			m.Timestamp.Unix(), m.CPU, m.Memory, m.CPU, m.Memory); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Maintain rolls completed buckets up into the coarser resolutions, then deletes points
+// past their resolution's retention
+func (s *MetricsStore) Maintain(ctx context.Context, now time.Time) error {
+	for i := 1; i < len(metricsTiers); i++ {
+		if err := s.rollup(ctx, metricsTiers[i-1], metricsTiers[i], now); err != nil {
+			return fmt.Errorf("%s rollup: %w", metricsTiers[i], err)
+		}
+	}
+	for _, res := range metricsTiers {
+		cutoff := now.Add(-s.retention.of(res)).Unix()
+		if _, err := s.db.ExecContext(ctx, "DELETE FROM metric_samples WHERE resolution = ? AND ts < ?",
+			int64(res.Seconds()), cutoff); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rollup aggregates the source resolution's points in buckets that ended since the last
+// rollup. Averages are weighted by the raw samples behind each point.
+func (s *MetricsStore) rollup(ctx context.Context, from, to time.Duration, now time.Time) error {
+	width := int64(to.Seconds())
+	end := now.Truncate(to).Unix()
+
+	var start int64
+	err := s.db.QueryRowContext(ctx, "SELECT through FROM metric_rollups WHERE resolution = ?", width).Scan(&start)
+	if err == sql.ErrNoRows {
+		var oldest sql.NullInt64
+		if err := s.db.QueryRowContext(ctx, "SELECT MIN(ts) FROM metric_samples WHERE resolution = ?",
+			int64(from.Seconds())).Scan(&oldest); err != nil {
+			return err
+		}
+		if !oldest.Valid {
+			return nil
+		}
+		start = oldest.Int64 / width * width
+	} else if err != nil {
+		return err
+	}
+	if end <= start {
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, `INSERT OR REPLACE INTO metric_samples
+		(scope, resolution, kind, namespace, name, container, ts, cpu, memory, cpu_max, memory_max, samples)
+		SELECT scope, ?, kind, namespace, name, container, ts / ? * ?,
+			SUM(cpu * samples) / SUM(samples), SUM(memory * samples) / SUM(samples),
+			MAX(cpu_max), MAX(memory_max), SUM(samples)
+		FROM metric_samples
+		WHERE resolution = ? AND ts >= ? AND ts < ?
+		GROUP BY scope, kind, namespace, name, container, ts / ?`,
+		width, width, width, int64(from.Seconds()), start, end, width); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT OR REPLACE INTO metric_rollups (resolution, through) VALUES (?, ?)", width, end); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ResolutionFor picks the finest resolution that still holds data from since and keeps
+// the series to roughly a thousand points
+func (s *MetricsStore) ResolutionFor(since, now time.Time) time.Duration {
+	age := now.Sub(since)
+	switch {
+	case age <= 6*time.Hour && age <= s.retention.Raw:
+		return MetricsRaw
+	case age <= 3*24*time.Hour && age <= s.retention.FiveMinute:
+		return Metrics5m
+	default:
+		return Metrics1h
+	}
+}
+
+// Query returns a pod's series per container (or a node's single series) at the given
+// resolution, from since onwards
+func (s *MetricsStore) Query(ctx context.Context, scope, kind, namespace, name string, resolution time.Duration, since time.Time) ([]MetricSeries, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT container, ts, cpu, memory, cpu_max, memory_max
+		FROM metric_samples
+		WHERE scope = ? AND kind = ? AND namespace = ? AND name = ? AND resolution = ? AND ts >= ?
+		ORDER BY container, ts`,
+		scope, kind, namespace, name, int64(resolution.Seconds()), since.Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var series []MetricSeries
+	for rows.Next() {
+		var container string
+		var ts int64
+		var p MetricPoint
+		if err := rows.Scan(&container, &ts, &p.CPU, &p.Memory, &p.CPUMax, &p.MemoryMax); err != nil {
+			return nil, err
+		}
+		p.Timestamp = time.Unix(ts, 0).UTC()
+		if len(series) == 0 || series[len(series)-1].Container != container {
+			series = append(series, MetricSeries{Container: container})
+		}
+		series[len(series)-1].Points = append(series[len(series)-1].Points, p)
+	}
+	return series, rows.Err()
+}
+
+// Recent returns a cluster's raw samples from since onwards, oldest first, for refilling
+// in-memory history after a restart
+func (s *MetricsStore) Recent(ctx context.Context, scope string, since time.Time) ([]MetricSample, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT kind, namespace, name, container, ts, cpu, memory
+		FROM metric_samples WHERE scope = ? AND resolution = 0 AND ts >= ? ORDER BY ts`, scope, since.Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var samples []MetricSample
+	for rows.Next() {
+		var m MetricSample
+		var ts int64
+		if err := rows.Scan(&m.Kind, &m.Namespace, &m.Name, &m.Container, &ts, &m.CPU, &m.Memory); err != nil {
+			return nil, err
+		}
+		m.Timestamp = time.Unix(ts, 0).UTC()
+		samples = append(samples, m)
+	}
+	return samples, rows.Err()
+}
@@ -0,0 +1,90 @@
+package timeline
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMetricsStore_RollupAndRetention(t *testing.T) {
+	store, err := NewMetricsStore(filepath.Join(t.TempDir(), "metrics.db"), MetricsRetention{Raw: 2 * time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+
+	// Two hours of 30s samples; CPU climbs by 1 per sample, memory is flat with one spike
+	base := time.Date(2026, 1, 10, 10, 0, 0, 0, time.UTC)
+	var samples []MetricSample
+	for i := range 240 {
+		mem := int64(100)
+		if i == 7 {
+			mem = 500
+		}
+		samples = append(samples,
+			MetricSample{Kind: "pod", Namespace: "shop", Name: "api", Container: "app", Timestamp: base.Add(time.Duration(i) * 30 * time.Second), CPU: int64(i), Memory: mem},
+			MetricSample{Kind: "node", Name: "node-1", Timestamp: base.Add(time.Duration(i) * 30 * time.Second), CPU: 1000, Memory: 1000})
+	}
+	if err := store.Write(ctx, "cluster/ctx", samples); err != nil {
+		t.Fatal(err)
+	}
+
+	now := base.Add(2*time.Hour + 10*time.Minute)
+	if err := store.Maintain(ctx, now); err != nil {
+		t.Fatal(err)
+	}
+
+	fiveMin, err := store.Query(ctx, "cluster/ctx", "pod", "shop", "api", Metrics5m, base)
+	if err != nil || len(fiveMin) != 1 || len(fiveMin[0].Points) != 24 {
+		t.Fatalf("5m series = %+v, %v", fiveMin, err)
+	}
+	first := fiveMin[0].Points[0]
+	// Samples 0..9: average CPU 4 (integer division of 4.5), memory (9*100+500)/10 = 140
+	if first.Timestamp != base || first.CPU != 4 || first.CPUMax != 9 || first.Memory != 140 || first.MemoryMax != 500 {
+		t.Errorf("first 5m point = %+v", first)
+	}
+
+	hour, _ := store.Query(ctx, "cluster/ctx", "pod", "shop", "api", Metrics1h, base)
+	if len(hour) != 1 || len(hour[0].Points) != 2 {
+		t.Fatalf("1h series = %+v", hour)
+	}
+	// Samples 0..119 average 59.5, weighted through the 5m rollups
+	if p := hour[0].Points[0]; p.CPU != 59 || p.CPUMax != 119 || p.MemoryMax != 500 {
+		t.Errorf("first 1h point = %+v", p)
+	}
+
+	// Raw samples older than 2h were deleted; rollups are kept
+	raw, _ := store.Query(ctx, "cluster/ctx", "pod", "shop", "api", MetricsRaw, base)
+	if len(raw) != 1 || len(raw[0].Points) != 220 {
+		t.Errorf("raw points after retention = %d, want 220", len(raw[0].Points))
+	}
+
+	// A second pass is idempotent
+	if err := store.Maintain(ctx, now); err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := store.Query(ctx, "cluster/ctx", "pod", "shop", "api", Metrics5m, base); len(again[0].Points) != 24 || again[0].Points[0] != first {
+		t.Errorf("5m series changed after a second pass: %+v", again[0].Points[0])
+	}
+
+	// Other clusters see nothing
+	if other, _ := store.Recent(ctx, "other/ctx", base); len(other) != 0 {
+		t.Errorf("Recent for another scope = %d samples", len(other))
+	}
+}
+
+func TestMetricsStore_ResolutionFor(t *testing.T) {
+	store := &MetricsStore{retention: MetricsRetention{}.withDefaults()}
+	now := time.Now()
+	for span, want := range map[time.Duration]time.Duration{
+		time.Hour:          MetricsRaw,
+		24 * time.Hour:     Metrics5m,
+		7 * 24 * time.Hour: Metrics1h,
+	} {
+		if got := store.ResolutionFor(now.Add(-span), now); got != want {
+			t.Errorf("ResolutionFor(%s) = %s, want %s", span, got, want)
+		}
+	}
+}
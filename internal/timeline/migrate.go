@@ -0,0 +1,324 @@
+package timeline
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Migration is one versioned schema change. Released migrations are never edited: each
+// one's checksum is recorded when it's applied and verified at every startup.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string // Reverts Up, for `radar migrate down`
+}
+
+func (m Migration) checksum() string {
+	sum := sha256.Sum256([]byte(m.Up))
+	return hex.EncodeToString(sum[:])
+}
+
+// AppliedMigration is a migration recorded in a database
+type AppliedMigration struct {
+	Version   int    `json:"version"`
+	Name      string `json:"name"`
+	AppliedAt string `json:"appliedAt"`
+	checksum  string
+}
+
+// SchemaStatus describes a timeline database's schema
+type SchemaStatus struct {
+	Version int                `json:"version"`
+	Latest  int                `json:"latest"` // Newest version this build knows
+	Applied []AppliedMigration `json:"applied"`
+	Pending []string           `json:"pending,omitempty"` // Names of migrations not yet applied
+}
+
+// dialect adapts the migrator to a database engine
+type dialect struct {
+	table       string
+	createTable string                                      // Creates (or upgrades) the migrations table
+	placeholder func(n int) string                          // nth query parameter
+	lock        func(ctx context.Context, tx *sql.Tx) error // Serializes migrations across processes; may be nil
+}
+
+// migrator applies and reverts an ordered list of migrations
+type migrator struct {
+	db         *sql.DB
+	dialect    dialect
+	migrations []Migration
+}
+
+// applied returns the recorded migrations, after checking they match this build's
+func (m *migrator) applied(ctx context.Context, tx *sql.Tx) ([]AppliedMigration, error) {
+	if _, err := tx.ExecContext(ctx, m.dialect.createTable); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", m.dialect.table, err)
+	}
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(
+		"SELECT version, COALESCE(name, ''), COALESCE(CAST(applied_at AS TEXT), ''), COALESCE(checksum, '') FROM %s ORDER BY version", m.dialect.table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var applied []AppliedMigration
+	for rows.Next() {
+		var a AppliedMigration
+		if err := rows.Scan(&a.Version, &a.Name, &a.AppliedAt, &a.checksum); err != nil {
+			return nil, err
+		}
+		applied = append(applied, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i, a := range applied {
+		if a.Version != i+1 {
+			return nil, fmt.Errorf("schema history is missing migration %d", i+1)
+		}
+		if a.Version > len(m.migrations) {
+			return nil, fmt.Errorf("database schema version %d is newer than this Radar build supports (%d); upgrade Radar, or run `radar migrate down %d` with the newer build first",
+				len(applied), len(m.migrations), len(m.migrations))
+		}
+		if want := m.migrations[i].checksum(); a.checksum != "" && a.checksum != want {
+			return nil, fmt.Errorf("migration %d (%s) differs from the one applied to this database", a.Version, m.migrations[i].Name)
+		}
+	}
+	return applied, nil
+}
+
+// up applies pending migrations in one transaction and returns the versions before and after
+func (m *migrator) up(ctx context.Context) (from, to int, err error) {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback()
+	if m.dialect.lock != nil {
+		if err := m.dialect.lock(ctx, tx); err != nil {
+			return 0, 0, fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+	}
+	applied, err := m.applied(ctx, tx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// Rows recorded before checksums were tracked adopt the current ones
+	p := m.dialect.placeholder
+	for _, a := range applied {
+		if a.checksum == "" {
+			mig := m.migrations[a.Version-1]
+			if _, err := tx.ExecContext(ctx, fmt.Sprintf("UPDATE %s SET name = %s, checksum = %s WHERE version = %s", m.dialect.table, p(1), p(2), p(3)),
+				mig.Name, mig.checksum(), a.Version); err != nil {
+				return 0, 0, err
+			}
+		}
+	}
+
+	from = len(applied)
+	for _, mig := range m.migrations[from:] {
+		if _, err := tx.ExecContext(ctx, mig.Up); err != nil {
+			return 0, 0, fmt.Errorf("migration %d (%s): %w", mig.Version, mig.Name, err)
+		}
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("INSERT INTO %s (version, name, checksum) VALUES (%s, %s, %s)", m.dialect.table, p(1), p(2), p(3)),
+			mig.Version, mig.Name, mig.checksum()); err != nil {
+			return 0, 0, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, 0, err
+	}
+	for _, mig := range m.migrations[from:] {
+		log.Printf("Applied timeline migration %d (%s)", mig.Version, mig.Name)
+	}
+	return from, len(m.migrations), nil
+}
+
+// down reverts migrations newer than target in one transaction
+func (m *migrator) down(ctx context.Context, target int) (from int, err error) {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	if m.dialect.lock != nil {
+		if err := m.dialect.lock(ctx, tx); err != nil {
+			return 0, fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+	}
+	applied, err := m.applied(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+	from = len(applied)
+	if target < 0 || target > from {
+		return 0, fmt.Errorf("target version must be between 0 and %d", from)
+	}
+	for v := from; v > target; v-- {
+		mig := m.migrations[v-1]
+		if mig.Down == "" {
+			return 0, fmt.Errorf("migration %d (%s) can't be reverted", v, mig.Name)
+		}
+		if _, err := tx.ExecContext(ctx, mig.Down); err != nil {
+			return 0, fmt.Errorf("reverting migration %d (%s): %w", v, mig.Name, err)
+		}
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE version = %s", m.dialect.table, m.dialect.placeholder(1)), v); err != nil {
+			return 0, err
+		}
+	}
+	return from, tx.Commit()
+}
+
+// status reports applied and pending migrations without changing the schema
+func (m *migrator) status(ctx context.Context) (*SchemaStatus, error) {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	applied, err := m.applied(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	st := &SchemaStatus{Version: len(applied), Latest: len(m.migrations), Applied: applied}
+	for _, mig := range m.migrations[len(applied):] {
+		st.Pending = append(st.Pending, mig.Name)
+	}
+	return st, nil
+}
+
+// sqliteQuickCheck fails if the database file is corrupt, so a damaged timeline is
+// reported instead of being migrated or overwritten
+func sqliteQuickCheck(ctx context.Context, db *sql.DB) error {
+	rows, err := db.QueryContext(ctx, "PRAGMA quick_check")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	var problems []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return err
+		}
+		if line != "ok" {
+			problems = append(problems, line)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(problems) > 0 {
+		if len(problems) > 3 {
+			problems = append(problems[:3], fmt.Sprintf("and %d more", len(problems)-3))
+		}
+		return fmt.Errorf("integrity check failed: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// sqliteBackupsKept is how many pre-migration backups are kept next to the database
+const sqliteBackupsKept = 3
+
+// backupSQLite copies the database to <path>.v<version>-<time>.bak before its schema
+// changes, keeping the newest sqliteBackupsKept backups. Empty databases aren't backed up.
+func backupSQLite(ctx context.Context, db *sql.DB, path string, version int) (string, error) {
+	var tables int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'events'").Scan(&tables); err != nil {
+		return "", err
+	}
+	if tables == 0 {
+		return "", nil
+	}
+
+	backup := fmt.Sprintf("%s.v%d-%s.bak", path, version, time.Now().Format("20060102-150405"))
+	if _, err := db.ExecContext(ctx, "VACUUM INTO '"+strings.ReplaceAll(backup, "'", "''")+"'"); err != nil {
+		return "", fmt.Errorf("failed to back up %s: %w", path, err)
+	}
+
+	old, _ := filepath.Glob(path + ".v*.bak")
+	sort.Slice(old, func(i, j int) bool {
+		a, _ := os.Stat(old[i])
+		b, _ := os.Stat(old[j])
+		return a != nil && b != nil && a.ModTime().After(b.ModTime())
+	})
+	for _, f := range old[min(len(old), sqliteBackupsKept):] {
+		os.Remove(f)
+	}
+	return backup, nil
+}
+
+// openForMigration opens a configured timeline database without starting a store
+func openForMigration(cfg StoreConfig) (*migrator, func() error, error) {
+	switch cfg.Type {
+	case StoreTypeSQLite:
+		if _, err := os.Stat(cfg.Path); err != nil {
+			return nil, nil, fmt.Errorf("no timeline database at %s: %w", cfg.Path, err)
+		}
+		db, err := sql.Open("sqlite", cfg.Path)
+		if err != nil {
+			return nil, nil, err
+		}
+		db.SetMaxOpenConns(1)
+		if _, err := db.Exec("PRAGMA busy_timeout=10000"); err != nil {
+			db.Close()
+			return nil, nil, err
+		}
+		return newSQLiteMigrator(db), db.Close, nil
+	case StoreTypePostgres:
+		db, err := sql.Open("postgres", cfg.DSN)
+		if err != nil {
+			return nil, nil, err
+		}
+		return newPostgresMigrator(db), db.Close, nil
+	default:
+		return nil, nil, fmt.Errorf("%q timeline storage has no schema", cfg.Type)
+	}
+}
+
+// GetSchemaStatus reports the schema version of a sqlite or postgres timeline database
+func GetSchemaStatus(ctx context.Context, cfg StoreConfig) (*SchemaStatus, error) {
+	m, closeDB, err := openForMigration(cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer closeDB()
+	if cfg.Type == StoreTypeSQLite {
+		if err := sqliteQuickCheck(ctx, m.db); err != nil {
+			return nil, err
+		}
+	}
+	return m.status(ctx)
+}
+
+// MigrateDown reverts a timeline database's schema to target, e.g. before downgrading
+// Radar. SQLite databases are backed up first; the backup path is returned.
+func MigrateDown(ctx context.Context, cfg StoreConfig, target int) (from int, backup string, err error) {
+	m, closeDB, err := openForMigration(cfg)
+	if err != nil {
+		return 0, "", err
+	}
+	defer closeDB()
+	if cfg.Type == StoreTypeSQLite {
+		st, err := m.status(ctx)
+		if err != nil {
+			return 0, "", err
+		}
+		if backup, err = backupSQLite(ctx, m.db, cfg.Path, st.Version); err != nil {
+			return 0, "", err
+		}
+	}
+	from, err = m.down(ctx, target)
+	return from, backup, err
+}
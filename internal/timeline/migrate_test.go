@@ -0,0 +1,126 @@
+package timeline
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMigrationsNumberedInOrder(t *testing.T) {
+	for name, migrations := range map[string][]Migration{"sqlite": sqliteMigrations, "postgres": postgresMigrations} {
+		for i, m := range migrations {
+			if m.Version != i+1 || m.Name == "" || m.Up == "" || m.Down == "" {
+				t.Errorf("%s migration %d is malformed: %+v", name, i+1, m)
+			}
+		}
+	}
+}
+
+func TestSQLiteAdoptsLegacySchemaWithBackup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "timeline.db")
+
+	// A database written by a build that created the schema without tracking it
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(sqliteMigrations[0].Up); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("INSERT INTO events (id, timestamp, source, kind, namespace, name, event_type) VALUES ('e1', ?, 'informer', 'Pod', 'default', 'web', 'add')",
+		time.Now().Format(time.RFC3339Nano)); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	store, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer store.Close()
+
+	var count int
+	if err := store.db.QueryRow("SELECT COUNT(*) FROM events WHERE id = 'e1'").Scan(&count); err != nil || count != 1 {
+		t.Fatalf("existing history lost: %d, %v", count, err)
+	}
+	backups, _ := filepath.Glob(path + ".v0-*.bak")
+	if len(backups) != 1 {
+		t.Fatalf("expected one pre-migration backup, got %v", backups)
+	}
+	st, err := newSQLiteMigrator(store.db).status(context.Background())
+	if err != nil || st.Version != len(sqliteMigrations) || len(st.Pending) != 0 {
+		t.Fatalf("status = %+v, %v", st, err)
+	}
+}
+
+func TestSQLiteNewDatabaseNotBackedUp(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "timeline.db")
+	store, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Close()
+	if backups, _ := filepath.Glob(path + ".v*.bak"); len(backups) != 0 {
+		t.Errorf("unexpected backups of an empty database: %v", backups)
+	}
+}
+
+func TestSQLiteRefusesNewerSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "timeline.db")
+	store, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.db.Exec("INSERT INTO schema_migrations (version, name, checksum) VALUES (?, 'from the future', 'x')", len(sqliteMigrations)+1); err != nil {
+		t.Fatal(err)
+	}
+	store.Close()
+
+	if _, err := NewSQLiteStore(path); err == nil || !strings.Contains(err.Error(), "newer than this Radar build") {
+		t.Fatalf("expected newer-schema error, got %v", err)
+	}
+}
+
+func TestSQLiteRefusesEditedMigration(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "timeline.db")
+	store, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.db.Exec("UPDATE schema_migrations SET checksum = 'edited' WHERE version = 1"); err != nil {
+		t.Fatal(err)
+	}
+	store.Close()
+
+	if _, err := NewSQLiteStore(path); err == nil || !strings.Contains(err.Error(), "differs") {
+		t.Fatalf("expected checksum error, got %v", err)
+	}
+}
+
+func TestSQLiteMigrateDownAndUp(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "timeline.db")
+	store, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Close()
+
+	cfg := StoreConfig{Type: StoreTypeSQLite, Path: path}
+	from, backup, err := MigrateDown(context.Background(), cfg, 0)
+	if err != nil || from != len(sqliteMigrations) || backup == "" {
+		t.Fatalf("MigrateDown = %d, %q, %v", from, backup, err)
+	}
+	st, err := GetSchemaStatus(context.Background(), cfg)
+	if err != nil || st.Version != 0 || len(st.Pending) != len(sqliteMigrations) {
+		t.Fatalf("status after down = %+v, %v", st, err)
+	}
+
+	store, err = NewSQLiteStore(path)
+	if err != nil {
+		t.Fatalf("re-applying migrations: %v", err)
+	}
+	store.Close()
+}
@@ -0,0 +1,712 @@
+package timeline
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	_ "github.com/lib/pq" // PostgreSQL driver
+)
+
+const (
+	// postgresLockKey is the advisory lock shared by replicas for migrations and pruning
+	postgresLockKey = 0x7261646172 // "radar"
+
+	postgresPruneInterval = 5 * time.Minute
+	postgresMaxConns      = 10
+)
+
+// postgresMigrations are applied in order; append new ones, never edit released ones
+var postgresMigrations = []Migration{
+	{
+		Version: 1,
+		Name:    "events table",
+		Up: `CREATE TABLE IF NOT EXISTS timeline_events (
+		id TEXT PRIMARY KEY,
+		dedup_key TEXT NOT NULL UNIQUE,
+		ts TIMESTAMPTZ NOT NULL,
+		source TEXT NOT NULL,
+		kind TEXT NOT NULL,
+		namespace TEXT NOT NULL DEFAULT '',
+		name TEXT NOT NULL,
+		uid TEXT,
+		event_type TEXT NOT NULL,
+		reason TEXT,
+		message TEXT,
+		diff JSONB,
+		health_state TEXT,
+		owner_kind TEXT,
+		owner_name TEXT,
+		labels JSONB,
+		count INTEGER NOT NULL DEFAULT 0,
+		correlation_id TEXT,
+		resource_created_at TIMESTAMPTZ,
+		recorded_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	);
+	CREATE INDEX IF NOT EXISTS idx_timeline_events_ts ON timeline_events (ts DESC);
+	CREATE INDEX IF NOT EXISTS idx_timeline_events_kind_ns_name ON timeline_events (kind, namespace, name);
+	CREATE INDEX IF NOT EXISTS idx_timeline_events_namespace_ts ON timeline_events (namespace, ts DESC);
+	CREATE INDEX IF NOT EXISTS idx_timeline_events_owner ON timeline_events (owner_kind, owner_name, namespace);`,
+		Down: `DROP TABLE IF EXISTS timeline_events;`,
+	},
+	{
+		Version: 2,
+		Name:    "events api_group column",
+		Up:      `ALTER TABLE timeline_events ADD COLUMN IF NOT EXISTS api_group TEXT;`,
+		Down:    `ALTER TABLE timeline_events DROP COLUMN IF EXISTS api_group;`,
+	},
+	{
+		Version: 3,
+		Name:    "events aggregate column",
+		Up:      `ALTER TABLE timeline_events ADD COLUMN IF NOT EXISTS aggregate JSONB;`,
+		Down:    `ALTER TABLE timeline_events DROP COLUMN IF EXISTS aggregate;`,
+	},
+	{
+		Version: 4,
+		Name:    "events annotation column",
+		Up:      `ALTER TABLE timeline_events ADD COLUMN IF NOT EXISTS annotation JSONB;`,
+		Down:    `ALTER TABLE timeline_events DROP COLUMN IF EXISTS annotation;`,
+	},
+}
+
+func newPostgresMigrator(db *sql.DB) *migrator {
+	return &migrator{
+		db: db,
+		dialect: dialect{
+			table: "timeline_schema_migrations",
+			createTable: `CREATE TABLE IF NOT EXISTS timeline_schema_migrations (
+				version INTEGER PRIMARY KEY,
+				applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+			);
+			ALTER TABLE timeline_schema_migrations ADD COLUMN IF NOT EXISTS name TEXT;
+			ALTER TABLE timeline_schema_migrations ADD COLUMN IF NOT EXISTS checksum TEXT;`,
+			placeholder: func(n int) string { return "$" + strconv.Itoa(n) },
+			// Replicas starting together wait on the advisory lock instead of racing
+			lock: func(ctx context.Context, tx *sql.Tx) error {
+				_, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", postgresLockKey)
+				return err
+			},
+		},
+		migrations: postgresMigrations,
+	}
+}
+
+// PostgresStore is a persistent implementation of EventStore backed by PostgreSQL.
+// Several Radar replicas can share one database: inserts are idempotent, and migrations
+// and retention pruning are serialized with an advisory lock. The seen-resource set stays
+// per process, since each replica runs its own informers.
+type PostgresStore struct {
+	db          *sql.DB
+	maxAge      time.Duration // Delete events older than this (0 = no age limit)
+	maxEvents   int           // Keep at most this many events (0 = no limit)
+	seen        *seenSet
+	filterCache map[string]*CompiledFilter
+	cacheMu     sync.RWMutex
+	stopCh      chan struct{}
+	wg          sync.WaitGroup
+}
+
+// NewPostgresStore connects to PostgreSQL, applies pending schema migrations and starts
+// retention pruning. An empty DSN uses the standard PG* environment variables.
+func NewPostgresStore(cfg StoreConfig) (*PostgresStore, error) {
+	db, err := sql.Open("postgres", cfg.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	maxConns := cfg.MaxConns
+	if maxConns <= 0 {
+		maxConns = postgresMaxConns
+	}
+	db.SetMaxOpenConns(maxConns)
+	db.SetMaxIdleConns(max(maxConns/2, 1))
+	db.SetConnMaxLifetime(30 * time.Minute)
+	db.SetConnMaxIdleTime(5 * time.Minute)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect: %w", err)
+	}
+
+	store := &PostgresStore{
+		db:          db,
+		maxAge:      cfg.MaxAge,
+		maxEvents:   cfg.MaxSize,
+		seen:        newSeenSet(seenCapacity),
+		filterCache: make(map[string]*CompiledFilter),
+		stopCh:      make(chan struct{}),
+	}
+
+	_, version, err := newPostgresMigrator(db).up(ctx)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate schema: %w", err)
+	}
+	log.Printf("Timeline PostgreSQL schema at version %d", version)
+
+	if store.maxAge > 0 || store.maxEvents > 0 {
+		store.wg.Add(1)
+		go store.pruneLoop()
+	}
+	return store, nil
+}
+
+// Append adds a single event to the store
+func (s *PostgresStore) Append(ctx context.Context, event TimelineEvent) error {
+	return s.AppendBatch(ctx, []TimelineEvent{event})
+}
+
+// AppendBatch adds multiple events atomically. Events another replica already recorded
+// are skipped.
+func (s *PostgresStore) AppendBatch(ctx context.Context, events []TimelineEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO timeline_events (
+			id, dedup_key, ts, source, kind, namespace, name, uid, event_type,
+			reason, message, diff, health_state, owner_kind, owner_name,
+			labels, count, correlation_id, resource_created_at, api_group, aggregate, annotation
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22)
+		ON CONFLICT (dedup_key) DO UPDATE SET
+			ts = EXCLUDED.ts, message = EXCLUDED.message, count = EXCLUDED.count,
+			owner_kind = EXCLUDED.owner_kind, owner_name = EXCLUDED.owner_name, aggregate = EXCLUDED.aggregate
+		WHERE timeline_events.source = 'k8s_event'
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, event := range events {
+		var diffJSON, labelsJSON, aggregateJSON, annotationJSON []byte
+		var ownerKind, ownerName sql.NullString
+
+		if event.Diff != nil {
+			if diffJSON, err = json.Marshal(event.Diff); err != nil {
+				log.Printf("Warning: failed to marshal diff for event %s: %v", event.ID, err)
+				diffJSON = nil
+			}
+		}
+		if event.Labels != nil {
+			if labelsJSON, err = json.Marshal(event.Labels); err != nil {
+				log.Printf("Warning: failed to marshal labels for event %s: %v", event.ID, err)
+				labelsJSON = nil
+			}
+		}
+		if event.Aggregate != nil {
+			if aggregateJSON, err = json.Marshal(event.Aggregate); err != nil {
+				aggregateJSON = nil
+			}
+		}
+		if event.Annotation != nil {
+			if annotationJSON, err = json.Marshal(event.Annotation); err != nil {
+				annotationJSON = nil
+			}
+		}
+		if event.Owner != nil {
+			ownerKind = sql.NullString{String: event.Owner.Kind, Valid: true}
+			ownerName = sql.NullString{String: event.Owner.Name, Valid: true}
+		}
+
+		_, err = stmt.ExecContext(ctx,
+			event.ID,
+			dedupKey(event, diffJSON),
+			event.Timestamp,
+			string(event.Source),
+			event.Kind,
+			event.Namespace,
+			event.Name,
+			event.UID,
+			string(event.EventType),
+			event.Reason,
+			event.Message,
+			nullJSON(diffJSON),
+			string(event.HealthState),
+			ownerKind,
+			ownerName,
+			nullJSON(labelsJSON),
+			event.Count,
+			event.CorrelationID,
+			event.CreatedAt,
+			nullString(event.Group),
+			nullJSON(aggregateJSON),
+			nullJSON(annotationJSON),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert event: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// dedupKey identifies an event across replicas. K8s Events and historical events already
+// have deterministic IDs; informer events get random IDs per replica, so they're keyed on
+// their content and the second they were observed.
+func dedupKey(event TimelineEvent, diffJSON []byte) string {
+	if event.Source != SourceInformer {
+		return event.ID
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "%s/%s/%s/%s/%s/%s/%d/", event.Kind, event.Namespace, event.Name, event.UID,
+		event.EventType, event.HealthState, event.Timestamp.Truncate(time.Second).Unix())
+	h.Write(diffJSON)
+	return fmt.Sprintf("inf-%x", h.Sum(nil)[:16])
+}
+
+func nullString(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func nullJSON(b []byte) any {
+	if len(b) == 0 {
+		return nil
+	}
+	return string(b)
+}
+
+const postgresEventColumns = `id, ts, source, kind, namespace, name, uid, event_type,
+	reason, message, diff, health_state, owner_kind, owner_name,
+	labels, count, correlation_id, resource_created_at, api_group, aggregate, annotation`
+
+// Query retrieves events matching the given options
+func (s *PostgresStore) Query(ctx context.Context, opts QueryOptions) ([]TimelineEvent, error) {
+	query := strings.Builder{}
+	query.WriteString("SELECT " + postgresEventColumns + " FROM timeline_events WHERE true")
+
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if opts.Namespace != "" {
+		query.WriteString(" AND namespace = " + arg(opts.Namespace))
+	}
+	if len(opts.Kinds) > 0 {
+		query.WriteString(" AND " + kindFilterSQL(opts.Kinds, arg))
+	}
+	if !opts.Since.IsZero() {
+		query.WriteString(" AND ts >= " + arg(opts.Since))
+	}
+	if !opts.Until.IsZero() {
+		query.WriteString(" AND ts <= " + arg(opts.Until))
+	}
+	if len(opts.Sources) > 0 {
+		placeholders := make([]string, len(opts.Sources))
+		for i, src := range opts.Sources {
+			placeholders[i] = arg(string(src))
+		}
+		query.WriteString(" AND source IN (" + strings.Join(placeholders, ",") + ")")
+	}
+
+	query.WriteString(" ORDER BY ts DESC")
+
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 200
+	}
+	if limit > 10000 {
+		limit = 10000
+	}
+	query.WriteString(fmt.Sprintf(" LIMIT %d", limit))
+	if opts.Offset > 0 {
+		query.WriteString(fmt.Sprintf(" OFFSET %d", opts.Offset))
+	}
+
+	rows, err := s.db.QueryContext(ctx, query.String(), args...)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var cf *CompiledFilter
+	if opts.FilterPreset != "" {
+		var filterErr error
+		cf, filterErr = s.getOrCompileFilter(opts.FilterPreset)
+		if filterErr != nil {
+			log.Printf("Warning: failed to compile filter preset %q: %v", opts.FilterPreset, filterErr)
+		}
+	}
+
+	var events []TimelineEvent
+	for rows.Next() {
+		event, err := scanPostgresEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		// Apply post-filters (same semantics as the SQLite store)
+		if cf != nil && !cf.Matches(&event) {
+			continue
+		}
+		if event.IsManaged() && !opts.IncludeManaged {
+			if cf == nil || (cf.preset != nil && !cf.preset.IncludeManaged) {
+				continue
+			}
+		}
+		if !opts.IncludeK8sEvents && event.Source == SourceK8sEvent {
+			continue
+		}
+
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// histogramCounts counts events per bucket and group in SQL
+func (s *PostgresStore) histogramCounts(ctx context.Context, opts HistogramOptions) ([]histogramCount, error) {
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+	bucket := "FLOOR((EXTRACT(EPOCH FROM ts) - " + arg(opts.Since.Unix()) + "::bigint) / " + arg(int64(opts.Bucket/time.Second)) + "::bigint)::bigint"
+	query := histogramSQL(opts, "timeline_events", "ts", bucket, opts.Since, opts.Until, arg)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("histogram query failed: %w", err)
+	}
+	return scanHistogramCounts(rows, len(opts.GroupBy))
+}
+
+// QueryGrouped retrieves events grouped according to the specified mode
+func (s *PostgresStore) QueryGrouped(ctx context.Context, opts QueryOptions) (*TimelineResponse, error) {
+	startTime := time.Now()
+
+	queryOpts := opts
+	queryOpts.Limit = opts.Limit * 10
+	if queryOpts.Limit > 5000 {
+		queryOpts.Limit = 5000
+	}
+
+	events, err := s.Query(ctx, queryOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.GroupBy == GroupByNone {
+		if len(events) > opts.Limit {
+			events = events[:opts.Limit]
+		}
+		return &TimelineResponse{
+			Ungrouped: events,
+			Meta: TimelineMeta{
+				TotalEvents: len(events),
+				QueryTimeMs: time.Since(startTime).Milliseconds(),
+				HasMore:     len(events) == opts.Limit,
+			},
+		}, nil
+	}
+
+	groups := groupEvents(events, opts.GroupBy)
+
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 200
+	}
+	hasMore := len(groups) > limit
+	if hasMore {
+		groups = groups[:limit]
+	}
+
+	return &TimelineResponse{
+		Groups: groups,
+		Meta: TimelineMeta{
+			TotalEvents: len(events),
+			GroupCount:  len(groups),
+			QueryTimeMs: time.Since(startTime).Milliseconds(),
+			HasMore:     hasMore,
+		},
+	}, nil
+}
+
+// GetEvent retrieves a single event by ID
+func (s *PostgresStore) GetEvent(ctx context.Context, id string) (*TimelineEvent, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT "+postgresEventColumns+" FROM timeline_events WHERE id = $1", id)
+	event, err := scanPostgresEvent(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// GetChangesForOwner retrieves changes for resources owned by the given owner
+func (s *PostgresStore) GetChangesForOwner(ctx context.Context, ownerKind, ownerNamespace, ownerName string, since time.Time, limit int) ([]TimelineEvent, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+
+	query := "SELECT " + postgresEventColumns + ` FROM timeline_events
+		WHERE owner_kind = $1 AND owner_name = $2 AND namespace = $3`
+	args := []any{ownerKind, ownerName, ownerNamespace}
+	if !since.IsZero() {
+		query += " AND ts >= $4"
+		args = append(args, since)
+	}
+	query += fmt.Sprintf(" ORDER BY ts DESC LIMIT %d", limit)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []TimelineEvent
+	for rows.Next() {
+		event, err := scanPostgresEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// MarkResourceSeen records that a resource has been seen
+func (s *PostgresStore) MarkResourceSeen(kind, namespace, name, uid string) {
+	s.seen.mark(ResourceKey(kind, namespace, name), uid)
+}
+
+// IsResourceSeen checks if a resource has been seen before
+func (s *PostgresStore) IsResourceSeen(kind, namespace, name, uid string) bool {
+	return s.seen.has(ResourceKey(kind, namespace, name), uid)
+}
+
+// ClearResourceSeen removes a resource from the seen set
+func (s *PostgresStore) ClearResourceSeen(kind, namespace, name string) {
+	s.seen.remove(ResourceKey(kind, namespace, name))
+}
+
+// ResetSeen forgets every seen resource. The set is per process (see PostgresStore), so
+// scope is unused.
+func (s *PostgresStore) ResetSeen(scope string) error {
+	s.seen.reset()
+	return nil
+}
+
+// Stats returns storage statistics
+func (s *PostgresStore) Stats() StoreStats {
+	var stats StoreStats
+	var oldest, newest sql.NullTime
+
+	row := s.db.QueryRow(`SELECT COUNT(*), MIN(ts), MAX(ts), pg_total_relation_size('timeline_events') FROM timeline_events`)
+	if err := row.Scan(&stats.TotalEvents, &oldest, &newest, &stats.StorageBytes); err != nil {
+		log.Printf("Warning: failed to read timeline stats: %v", err)
+	}
+	if oldest.Valid {
+		stats.OldestEvent = oldest.Time
+	}
+	if newest.Valid {
+		stats.NewestEvent = newest.Time
+	}
+
+	stats.SeenResources = s.seen.len()
+
+	return stats
+}
+
+// Close stops pruning and closes the connection pool
+func (s *PostgresStore) Close() error {
+	close(s.stopCh)
+	s.wg.Wait()
+	return s.db.Close()
+}
+
+// Cleanup removes events older than the given duration
+func (s *PostgresStore) Cleanup(ctx context.Context, maxAge time.Duration) (int64, error) {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM timeline_events WHERE ts < $1", time.Now().Add(-maxAge))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (s *PostgresStore) pruneLoop() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(postgresPruneInterval)
+	defer ticker.Stop()
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		if err := s.prune(ctx); err != nil {
+			log.Printf("Warning: timeline retention pruning failed: %v", err)
+		}
+		cancel()
+
+		select {
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// prune applies the retention policy. Only one replica prunes at a time; the others skip
+// the round when the advisory lock is held.
+func (s *PostgresStore) prune(ctx context.Context) error {
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", postgresLockKey).Scan(&locked); err != nil {
+		return err
+	}
+	if !locked {
+		return nil
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", postgresLockKey)
+
+	var deleted int64
+	if s.maxAge > 0 {
+		result, err := conn.ExecContext(ctx, "DELETE FROM timeline_events WHERE ts < $1", time.Now().Add(-s.maxAge))
+		if err != nil {
+			return fmt.Errorf("age cleanup: %w", err)
+		}
+		n, _ := result.RowsAffected()
+		deleted += n
+	}
+	if s.maxEvents > 0 {
+		result, err := conn.ExecContext(ctx, `DELETE FROM timeline_events WHERE ts < (
+			SELECT ts FROM timeline_events ORDER BY ts DESC OFFSET $1 LIMIT 1)`, s.maxEvents-1)
+		if err != nil {
+			return fmt.Errorf("size cleanup: %w", err)
+		}
+		n, _ := result.RowsAffected()
+		deleted += n
+	}
+	if deleted > 0 {
+		log.Printf("Timeline retention removed %d events", deleted)
+	}
+	return nil
+}
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanPostgresEvent(row rowScanner) (TimelineEvent, error) {
+	var event TimelineEvent
+	var source, eventType string
+	var uid, reason, message, diffJSON, healthState, labelsJSON sql.NullString
+	var ownerKind, ownerName, correlationID, group, aggregateJSON, annotationJSON sql.NullString
+	var createdAt sql.NullTime
+
+	err := row.Scan(
+		&event.ID,
+		&event.Timestamp,
+		&source,
+		&event.Kind,
+		&event.Namespace,
+		&event.Name,
+		&uid,
+		&eventType,
+		&reason,
+		&message,
+		&diffJSON,
+		&healthState,
+		&ownerKind,
+		&ownerName,
+		&labelsJSON,
+		&event.Count,
+		&correlationID,
+		&createdAt,
+		&group,
+		&aggregateJSON,
+		&annotationJSON,
+	)
+	if err != nil {
+		return event, err
+	}
+
+	event.Source = EventSource(source)
+	event.EventType = EventType(eventType)
+	event.HealthState = HealthState(healthState.String)
+	event.UID = uid.String
+	event.Reason = reason.String
+	event.Message = message.String
+	event.CorrelationID = correlationID.String
+	event.Group = group.String
+	if createdAt.Valid {
+		event.CreatedAt = &createdAt.Time
+	}
+	if aggregateJSON.Valid && aggregateJSON.String != "" {
+		var agg EventAggregate
+		if json.Unmarshal([]byte(aggregateJSON.String), &agg) == nil {
+			event.Aggregate = &agg
+		}
+	}
+	if annotationJSON.Valid && annotationJSON.String != "" {
+		var info AnnotationInfo
+		if json.Unmarshal([]byte(annotationJSON.String), &info) == nil {
+			event.Annotation = &info
+		}
+	}
+
+	if diffJSON.Valid && diffJSON.String != "" {
+		var diff DiffInfo
+		if json.Unmarshal([]byte(diffJSON.String), &diff) == nil {
+			event.Diff = &diff
+		}
+	}
+	if ownerKind.Valid && ownerKind.String != "" {
+		event.Owner = &OwnerInfo{Kind: ownerKind.String, Name: ownerName.String}
+	}
+	if labelsJSON.Valid && labelsJSON.String != "" {
+		json.Unmarshal([]byte(labelsJSON.String), &event.Labels)
+	}
+
+	return event, nil
+}
+
+// getOrCompileFilter returns a cached compiled filter or compiles a new one
+func (s *PostgresStore) getOrCompileFilter(presetName string) (*CompiledFilter, error) {
+	s.cacheMu.RLock()
+	if cf, ok := s.filterCache[presetName]; ok {
+		s.cacheMu.RUnlock()
+		return cf, nil
+	}
+	s.cacheMu.RUnlock()
+
+	preset, ok := DefaultFilterPresets()[presetName]
+	if !ok {
+		return nil, nil
+	}
+	cf, err := CompileFilter(&preset)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cacheMu.Lock()
+	s.filterCache[presetName] = cf
+	s.cacheMu.Unlock()
+	return cf, nil
+}
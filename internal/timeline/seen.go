@@ -0,0 +1,94 @@
+package timeline
+
+import (
+	"container/list"
+	"sync"
+)
+
+// seenCapacity bounds how many resources a store remembers as seen. Past it the least
+// recently seen are forgotten, which at worst re-records a "created" event for the
+// resource that has been quiet the longest.
+const seenCapacity = 100_000
+
+// seenSet is an LRU set of resources the timeline has recorded as created, used to skip
+// the "add" events informers replay when they (re)start. Each entry keeps the resource's
+// UID, so a resource deleted and recreated under the same name isn't mistaken for one
+// already recorded.
+type seenSet struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // Front is the most recently seen
+	items    map[string]*list.Element
+}
+
+type seenEntry struct {
+	key, uid string
+}
+
+func newSeenSet(capacity int) *seenSet {
+	if capacity <= 0 {
+		capacity = seenCapacity
+	}
+	return &seenSet{capacity: capacity, order: list.New(), items: make(map[string]*list.Element)}
+}
+
+// mark records key as seen with uid and returns the keys evicted to stay within capacity
+func (s *seenSet) mark(key, uid string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.items[key]; ok {
+		el.Value = seenEntry{key: key, uid: uid}
+		s.order.MoveToFront(el)
+		return nil
+	}
+	s.items[key] = s.order.PushFront(seenEntry{key: key, uid: uid})
+
+	var evicted []string
+	for s.order.Len() > s.capacity {
+		oldest := s.order.Back()
+		e := s.order.Remove(oldest).(seenEntry)
+		delete(s.items, e.key)
+		evicted = append(evicted, e.key)
+	}
+	return evicted
+}
+
+// has reports whether key was seen. When both sides know the UID they must match.
+func (s *seenSet) has(key, uid string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	el, ok := s.items[key]
+	if !ok {
+		return false
+	}
+	if e := el.Value.(seenEntry); uid != "" && e.uid != "" && e.uid != uid {
+		return false
+	}
+	s.order.MoveToFront(el)
+	return true
+}
+
+func (s *seenSet) remove(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if el, ok := s.items[key]; ok {
+		s.order.Remove(el)
+		delete(s.items, key)
+	}
+}
+
+// reset forgets every entry
+func (s *seenSet) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.order.Init()
+	s.items = make(map[string]*list.Element)
+}
+
+func (s *seenSet) len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
@@ -0,0 +1,20 @@
+package timeline
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSeenSetEvictsLeastRecentlySeen(t *testing.T) {
+	s := newSeenSet(2)
+	s.mark("a", "")
+	s.mark("b", "")
+	s.has("a", "") // a is now more recent than b
+
+	if evicted := s.mark("c", ""); !reflect.DeepEqual(evicted, []string{"b"}) {
+		t.Errorf("evicted = %v, want [b]", evicted)
+	}
+	if !s.has("a", "") || s.has("b", "") || !s.has("c", "") || s.len() != 2 {
+		t.Errorf("unexpected contents after eviction (len %d)", s.len())
+	}
+}
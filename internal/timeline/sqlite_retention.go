@@ -0,0 +1,364 @@
+package timeline
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+const (
+	// sqliteCompactInterval is how often the retention policy is applied
+	sqliteCompactInterval = 10 * time.Minute
+	// downsampleGroupBatch is how many resource-hours are collapsed per transaction
+	downsampleGroupBatch = 500
+	// sizeTrimTarget is the fraction of MaxDBSize trimming aims for, so the next few
+	// writes don't immediately trigger another round
+	sizeTrimTarget = 0.9
+	// ReasonDownsampled marks an update that stands for several collapsed ones
+	ReasonDownsampled = "Downsampled"
+)
+
+// RetentionPolicy bounds the history a SQLite store keeps. Zero values disable a limit.
+type RetentionPolicy struct {
+	MaxAge         time.Duration // Delete events older than this
+	MaxRowsPerKind int           // Keep at most this many events of each kind, newest first
+	MaxDBSize      int64         // Delete the oldest events until the database fits, in bytes
+	// DownsampleAfter collapses the informer updates of a resource within each hour into
+	// one event once they're older than this, keeping the last state and a count
+	DownsampleAfter time.Duration
+}
+
+// Enabled reports whether any limit is set
+func (p RetentionPolicy) Enabled() bool {
+	return p.MaxAge > 0 || p.MaxRowsPerKind > 0 || p.MaxDBSize > 0 || p.DownsampleAfter > 0
+}
+
+// String describes the policy for logs
+func (p RetentionPolicy) String() string {
+	var parts []string
+	if p.MaxAge > 0 {
+		parts = append(parts, "max age "+p.MaxAge.String())
+	}
+	if p.MaxRowsPerKind > 0 {
+		parts = append(parts, fmt.Sprintf("max %d events per kind", p.MaxRowsPerKind))
+	}
+	if p.MaxDBSize > 0 {
+		parts = append(parts, fmt.Sprintf("max %d MB", p.MaxDBSize>>20))
+	}
+	if p.DownsampleAfter > 0 {
+		parts = append(parts, "downsample after "+p.DownsampleAfter.String())
+	}
+	if len(parts) == 0 {
+		return "unbounded"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// CompactionResult is what one compaction round removed
+type CompactionResult struct {
+	Downsampled int64 `json:"downsampled"` // Updates folded into aggregated events
+	Expired     int64 `json:"expired"`     // Older than MaxAge
+	OverKind    int64 `json:"overKind"`    // Beyond MaxRowsPerKind
+	OverSize    int64 `json:"overSize"`    // Removed to fit MaxDBSize
+	Vacuumed    bool  `json:"vacuumed"`
+}
+
+// Removed is the total number of rows deleted
+func (r CompactionResult) Removed() int64 {
+	return r.Downsampled + r.Expired + r.OverKind + r.OverSize
+}
+
+// StartRetention applies the policy now and then every sqliteCompactInterval until the
+// store is closed. Call it at most once.
+func (s *SQLiteStore) StartRetention(p RetentionPolicy) {
+	if !p.Enabled() {
+		return
+	}
+	s.retention = p
+	s.stopCh = make(chan struct{})
+	s.wg.Add(1)
+	go s.compactLoop()
+}
+
+func (s *SQLiteStore) compactLoop() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(sqliteCompactInterval)
+	defer ticker.Stop()
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		res, err := s.Compact(ctx, time.Now())
+		cancel()
+		if err != nil {
+			log.Printf("Warning: timeline compaction failed: %v", err)
+		} else if res.Removed() > 0 {
+			log.Printf("Timeline compaction removed %d events (%d downsampled, %d expired, %d over per-kind limit, %d over size limit)",
+				res.Removed(), res.Downsampled, res.Expired, res.OverKind, res.OverSize)
+		}
+
+		select {
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Compact applies the retention policy as of now: downsampling first, so the row limits
+// count aggregated events, then age, per-kind and size limits
+func (s *SQLiteStore) Compact(ctx context.Context, now time.Time) (CompactionResult, error) {
+	var res CompactionResult
+	p := s.retention
+	var err error
+	if p.DownsampleAfter > 0 {
+		// Whole hours only, so an hour is collapsed once, after all its updates arrived
+		if res.Downsampled, err = s.downsample(ctx, now.Add(-p.DownsampleAfter).Truncate(time.Hour)); err != nil {
+			return res, fmt.Errorf("downsample: %w", err)
+		}
+	}
+	if p.MaxAge > 0 {
+		result, err := s.db.ExecContext(ctx, "DELETE FROM events WHERE timestamp < ?", now.Add(-p.MaxAge).Format(time.RFC3339Nano))
+		if err != nil {
+			return res, fmt.Errorf("age cleanup: %w", err)
+		}
+		res.Expired, _ = result.RowsAffected()
+	}
+	if p.MaxRowsPerKind > 0 {
+		result, err := s.db.ExecContext(ctx, `DELETE FROM events WHERE id IN (
+			SELECT id FROM (
+				SELECT id, ROW_NUMBER() OVER (PARTITION BY kind ORDER BY timestamp DESC) AS rn FROM events
+			) WHERE rn > ?)`, p.MaxRowsPerKind)
+		if err != nil {
+			return res, fmt.Errorf("per-kind cleanup: %w", err)
+		}
+		res.OverKind, _ = result.RowsAffected()
+	}
+	if p.MaxDBSize > 0 {
+		if res.OverSize, res.Vacuumed, err = s.trimToSize(ctx, p.MaxDBSize); err != nil {
+			return res, fmt.Errorf("size cleanup: %w", err)
+		}
+	}
+	return res, nil
+}
+
+// downsampleGroup is one resource's informer updates within one hour
+type downsampleGroup struct {
+	kind, group, namespace, name, hour string
+}
+
+// downsample collapses the informer updates before cutoff: all updates of a resource in
+// the same hour become its last update, with the count and a summary of the health
+// changes in between. Returns the number of rows removed.
+func (s *SQLiteStore) downsample(ctx context.Context, cutoff time.Time) (int64, error) {
+	var removed int64
+	for {
+		groups, err := s.downsampleGroups(ctx, cutoff)
+		if err != nil || len(groups) == 0 {
+			return removed, err
+		}
+		n, err := s.collapseGroups(ctx, groups)
+		removed += n
+		if err != nil {
+			return removed, err
+		}
+		if len(groups) < downsampleGroupBatch {
+			return removed, nil
+		}
+	}
+}
+
+// downsampleGroups returns resource-hours before cutoff with more than one update
+func (s *SQLiteStore) downsampleGroups(ctx context.Context, cutoff time.Time) ([]downsampleGroup, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT kind, COALESCE(api_group, ''), COALESCE(namespace, ''), name, substr(timestamp, 1, 13) AS hour
+		FROM events
+		WHERE source = ? AND event_type = ? AND timestamp < ?
+		GROUP BY kind, COALESCE(api_group, ''), COALESCE(namespace, ''), name, hour
+		HAVING COUNT(*) > 1
+		LIMIT ?`,
+		string(SourceInformer), string(EventTypeUpdate), cutoff.Format(time.RFC3339Nano), downsampleGroupBatch)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var groups []downsampleGroup
+	for rows.Next() {
+		var g downsampleGroup
+		if err := rows.Scan(&g.kind, &g.group, &g.namespace, &g.name, &g.hour); err != nil {
+			return nil, err
+		}
+		groups = append(groups, g)
+	}
+	return groups, rows.Err()
+}
+
+// collapseGroups folds each group into its last update in one transaction
+func (s *SQLiteStore) collapseGroups(ctx context.Context, groups []downsampleGroup) (int64, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var removed int64
+	for _, g := range groups {
+		n, err := collapseGroup(ctx, tx, g)
+		if err != nil {
+			return 0, err
+		}
+		removed += n
+	}
+	return removed, tx.Commit()
+}
+
+// downsampledUpdate is the part of an update row downsampling looks at
+type downsampledUpdate struct {
+	id        string
+	timestamp string
+	health    string
+	count     int
+}
+
+func collapseGroup(ctx context.Context, tx *sql.Tx, g downsampleGroup) (int64, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, timestamp, COALESCE(health_state, ''), COALESCE(count, 0)
+		FROM events
+		WHERE source = ? AND event_type = ? AND kind = ? AND COALESCE(api_group, '') = ?
+			AND COALESCE(namespace, '') = ? AND name = ? AND substr(timestamp, 1, 13) = ?
+		ORDER BY timestamp`,
+		string(SourceInformer), string(EventTypeUpdate), g.kind, g.group, g.namespace, g.name, g.hour)
+	if err != nil {
+		return 0, err
+	}
+	var updates []downsampledUpdate
+	for rows.Next() {
+		var u downsampledUpdate
+		if err := rows.Scan(&u.id, &u.timestamp, &u.health, &u.count); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		updates = append(updates, u)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	if len(updates) < 2 {
+		return 0, nil
+	}
+
+	last := updates[len(updates)-1]
+	_, err = tx.ExecContext(ctx, "UPDATE events SET reason = ?, message = ?, count = ? WHERE id = ?",
+		ReasonDownsampled, downsampleMessage(updates), downsampledCount(updates), last.id)
+	if err != nil {
+		return 0, err
+	}
+	args := make([]any, 0, len(updates)-1)
+	for _, u := range updates[:len(updates)-1] {
+		args = append(args, u.id)
+	}
+	result, err := tx.ExecContext(ctx, "DELETE FROM events WHERE id IN (?"+strings.Repeat(",?", len(args)-1)+")", args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// downsampledCount is how many updates an aggregated event stands for. Updates that are
+// already aggregates count for what they stand for.
+func downsampledCount(updates []downsampledUpdate) int {
+	n := 0
+	for _, u := range updates {
+		n += max(u.count, 1)
+	}
+	return n
+}
+
+// maxHealthPath caps how many health states a downsampled message lists
+const maxHealthPath = 8
+
+// downsampleMessage summarizes collapsed updates, e.g.
+// "12 updates 10:00:03–10:58:40; health changed 4 times: healthy → degraded → healthy → ..."
+func downsampleMessage(updates []downsampledUpdate) string {
+	msg := fmt.Sprintf("%d updates %s–%s", downsampledCount(updates),
+		clockOf(updates[0].timestamp), clockOf(updates[len(updates)-1].timestamp))
+
+	var path []string
+	changes := 0
+	for _, u := range updates {
+		if u.health == "" {
+			continue
+		}
+		if len(path) > 0 && path[len(path)-1] == u.health {
+			continue
+		}
+		if len(path) > 0 {
+			changes++
+		}
+		path = append(path, u.health)
+	}
+	if changes > 0 {
+		if len(path) > maxHealthPath {
+			path = append(path[:maxHealthPath], "...")
+		}
+		msg += fmt.Sprintf("; health changed %d times: %s", changes, strings.Join(path, " → "))
+	}
+	return msg
+}
+
+// clockOf returns the time of day of a stored timestamp
+func clockOf(ts string) string {
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return ts
+	}
+	return t.Format("15:04:05")
+}
+
+// trimToSize deletes the oldest events until the data fits maxBytes, then vacuums if the
+// file itself is still larger. Space freed by deletes is reused before the file grows, so
+// the data size is what's measured.
+func (s *SQLiteStore) trimToSize(ctx context.Context, maxBytes int64) (int64, bool, error) {
+	var pageSize, pageCount, freePages int64
+	if err := s.db.QueryRowContext(ctx, "PRAGMA page_size").Scan(&pageSize); err != nil {
+		return 0, false, err
+	}
+	if err := s.db.QueryRowContext(ctx, "PRAGMA page_count").Scan(&pageCount); err != nil {
+		return 0, false, err
+	}
+	if err := s.db.QueryRowContext(ctx, "PRAGMA freelist_count").Scan(&freePages); err != nil {
+		return 0, false, err
+	}
+	used := (pageCount - freePages) * pageSize
+
+	var deleted int64
+	if used > maxBytes {
+		var total int64
+		if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM events").Scan(&total); err != nil {
+			return 0, false, err
+		}
+		// Rows are roughly the same size, so remove the same share of them
+		keep := int64(float64(total) * float64(maxBytes) * sizeTrimTarget / float64(used))
+		if excess := total - keep; excess > 0 {
+			result, err := s.db.ExecContext(ctx,
+				"DELETE FROM events WHERE id IN (SELECT id FROM events ORDER BY timestamp ASC LIMIT ?)", excess)
+			if err != nil {
+				return 0, false, err
+			}
+			deleted, _ = result.RowsAffected()
+		}
+	}
+
+	if pageCount*pageSize <= maxBytes {
+		return deleted, false, nil
+	}
+	if _, err := s.db.ExecContext(ctx, "VACUUM"); err != nil {
+		return deleted, false, fmt.Errorf("vacuum: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
+		log.Printf("Warning: failed to checkpoint timeline database: %v", err)
+	}
+	return deleted, true, nil
+}
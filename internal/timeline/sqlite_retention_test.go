@@ -0,0 +1,153 @@
+package timeline
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestSQLiteStore_CompactDownsample(t *testing.T) {
+	store, cleanup := createTestSQLiteStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	base := time.Date(2026, 1, 10, 10, 0, 0, 0, time.UTC)
+	health := []HealthState{HealthHealthy, HealthDegraded, HealthHealthy, HealthDegraded}
+	var events []TimelineEvent
+	for i, h := range health {
+		events = append(events, TimelineEvent{
+			ID: fmt.Sprintf("flap-%d", i), Timestamp: base.Add(time.Duration(i) * 10 * time.Minute),
+			Source: SourceInformer, Kind: "Deployment", Namespace: "shop", Name: "api",
+			EventType: EventTypeUpdate, HealthState: h,
+		})
+	}
+	events = append(events,
+		// Next hour: collapsed separately
+		TimelineEvent{ID: "next-hour", Timestamp: base.Add(time.Hour), Source: SourceInformer, Kind: "Deployment",
+			Namespace: "shop", Name: "api", EventType: EventTypeUpdate, HealthState: HealthHealthy},
+		// Adds and K8s events are never collapsed
+		TimelineEvent{ID: "add", Timestamp: base.Add(time.Minute), Source: SourceInformer, Kind: "Deployment",
+			Namespace: "shop", Name: "api", EventType: EventTypeAdd},
+		TimelineEvent{ID: "warning", Timestamp: base.Add(2 * time.Minute), Source: SourceK8sEvent, Kind: "Deployment",
+			Namespace: "shop", Name: "api", EventType: EventTypeWarning, Reason: "Failed"},
+		// Another resource in the same hour
+		TimelineEvent{ID: "other", Timestamp: base.Add(5 * time.Minute), Source: SourceInformer, Kind: "Deployment",
+			Namespace: "shop", Name: "web", EventType: EventTypeUpdate},
+	)
+	if err := store.AppendBatch(ctx, events); err != nil {
+		t.Fatalf("AppendBatch failed: %v", err)
+	}
+
+	store.retention = RetentionPolicy{DownsampleAfter: time.Hour}
+	res, err := store.Compact(ctx, base.Add(2*time.Hour+30*time.Minute))
+	if err != nil {
+		t.Fatalf("Compact failed: %v", err)
+	}
+	if res.Downsampled != 3 {
+		t.Errorf("Downsampled = %d, want 3", res.Downsampled)
+	}
+	if stats := store.Stats(); stats.TotalEvents != 5 {
+		t.Errorf("TotalEvents = %d, want 5", stats.TotalEvents)
+	}
+
+	last, err := store.GetEvent(ctx, "flap-3")
+	if err != nil || last == nil {
+		t.Fatalf("GetEvent(flap-3) = %v, %v", last, err)
+	}
+	if last.Reason != ReasonDownsampled || last.Count != 4 || last.HealthState != HealthDegraded {
+		t.Errorf("aggregated event = %+v", last)
+	}
+	want := "4 updates 10:00:00–10:30:00; health changed 3 times: healthy → degraded → healthy → degraded"
+	if last.Message != want {
+		t.Errorf("Message = %q, want %q", last.Message, want)
+	}
+
+	// Compacting again leaves aggregated events alone
+	res, err = store.Compact(ctx, base.Add(2*time.Hour+30*time.Minute))
+	if err != nil || res.Removed() != 0 {
+		t.Errorf("second Compact = %+v, %v", res, err)
+	}
+}
+
+func TestSQLiteStore_CompactLimits(t *testing.T) {
+	store, cleanup := createTestSQLiteStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	now := time.Now()
+	var events []TimelineEvent
+	for i := range 5 {
+		ts := now.Add(-time.Duration(i) * time.Hour)
+		events = append(events,
+			TimelineEvent{ID: fmt.Sprintf("pod-%d", i), Timestamp: ts, Source: SourceInformer, Kind: "Pod",
+				Namespace: "default", Name: fmt.Sprintf("pod-%d", i), EventType: EventTypeAdd},
+			TimelineEvent{ID: fmt.Sprintf("svc-%d", i), Timestamp: ts, Source: SourceInformer, Kind: "Service",
+				Namespace: "default", Name: fmt.Sprintf("svc-%d", i), EventType: EventTypeAdd},
+		)
+	}
+	if err := store.AppendBatch(ctx, events); err != nil {
+		t.Fatalf("AppendBatch failed: %v", err)
+	}
+
+	// 3h30m drops the oldest of each kind; 3 per kind then drops one more of each
+	store.retention = RetentionPolicy{MaxAge: 3*time.Hour + 30*time.Minute, MaxRowsPerKind: 3}
+	res, err := store.Compact(ctx, now)
+	if err != nil {
+		t.Fatalf("Compact failed: %v", err)
+	}
+	if res.Expired != 2 || res.OverKind != 2 {
+		t.Errorf("result = %+v, want 2 expired and 2 over kind", res)
+	}
+	for _, id := range []string{"pod-0", "pod-2", "svc-2"} {
+		if e, _ := store.GetEvent(ctx, id); e == nil {
+			t.Errorf("%s was removed", id)
+		}
+	}
+	for _, id := range []string{"pod-3", "svc-4"} {
+		if e, _ := store.GetEvent(ctx, id); e != nil {
+			t.Errorf("%s was kept", id)
+		}
+	}
+}
+
+func TestSQLiteStore_CompactSize(t *testing.T) {
+	store, cleanup := createTestSQLiteStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	now := time.Now()
+	var events []TimelineEvent
+	for i := range 2000 {
+		events = append(events, TimelineEvent{
+			ID: fmt.Sprintf("e-%d", i), Timestamp: now.Add(-time.Duration(i) * time.Second),
+			Source: SourceInformer, Kind: "ConfigMap", Namespace: "default", Name: fmt.Sprintf("cm-%d", i),
+			EventType: EventTypeAdd, Message: fmt.Sprintf("%0500d", i),
+		})
+	}
+	if err := store.AppendBatch(ctx, events); err != nil {
+		t.Fatalf("AppendBatch failed: %v", err)
+	}
+
+	const maxSize = 512 << 10
+	store.retention = RetentionPolicy{MaxDBSize: maxSize}
+	res, err := store.Compact(ctx, now)
+	if err != nil {
+		t.Fatalf("Compact failed: %v", err)
+	}
+	if res.OverSize == 0 || !res.Vacuumed {
+		t.Fatalf("result = %+v, want rows removed and a vacuum", res)
+	}
+	if e, _ := store.GetEvent(ctx, "e-1999"); e != nil {
+		t.Error("oldest event was kept")
+	}
+	if e, _ := store.GetEvent(ctx, "e-0"); e == nil {
+		t.Error("newest event was removed")
+	}
+	var pages, pageSize int64
+	_ = store.db.QueryRow("PRAGMA page_count").Scan(&pages)
+	_ = store.db.QueryRow("PRAGMA page_size").Scan(&pageSize)
+	if pages*pageSize > maxSize {
+		t.Errorf("database is %d bytes after compaction, want at most %d", pages*pageSize, maxSize)
+	}
+}
@@ -19,16 +19,44 @@ import (
 // SQLiteStore is a persistent implementation of EventStore using SQLite.
 // Suitable for local development with persistence and in-cluster use with PVC.
 type SQLiteStore struct {
-	db            *sql.DB
-	seenResources map[string]bool
-	seenMu        sync.RWMutex
-	filterCache   map[string]*CompiledFilter
-	cacheMu       sync.RWMutex
-	path          string
+	db          *sql.DB
+	seen        *seenSet
+	seenScope   string // Cluster/context whose seen set is loaded and persisted
+	seenMu      sync.RWMutex
+	filterCache map[string]*CompiledFilter
+	cacheMu     sync.RWMutex
+	path        string
+
+	// Background compaction (see StartRetention)
+	retention RetentionPolicy
+	stopCh    chan struct{}
+	wg        sync.WaitGroup
 }
 
 // NewSQLiteStore creates a new SQLite-backed event store
 func NewSQLiteStore(dbPath string) (*SQLiteStore, error) {
+	db, err := openSQLite(dbPath)
+	if err != nil {
+		return nil, err
+	}
+
+	store := &SQLiteStore{
+		db:          db,
+		seen:        newSeenSet(seenCapacity),
+		filterCache: make(map[string]*CompiledFilter),
+		path:        dbPath,
+	}
+
+	if err := store.initSchema(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize schema: %w", err)
+	}
+
+	return store, nil
+}
+
+// openSQLite opens (creating if needed) a database file configured for a single writer
+func openSQLite(dbPath string) (*sql.DB, error) {
 	// Ensure directory exists
 	dir := filepath.Dir(dbPath)
 	if dir != "" && dir != "." {
@@ -59,31 +87,15 @@ func NewSQLiteStore(dbPath string) (*SQLiteStore, error) {
 			log.Printf("Warning: failed to set %s: %v", pragma, err)
 		}
 	}
-
-	store := &SQLiteStore{
-		db:            db,
-		seenResources: make(map[string]bool),
-		filterCache:   make(map[string]*CompiledFilter),
-		path:          dbPath,
-	}
-
-	if err := store.initSchema(); err != nil {
-		db.Close()
-		return nil, fmt.Errorf("failed to initialize schema: %w", err)
-	}
-
-	// Clear seen_resources on startup so historical events get re-extracted
-	// The events table will handle deduplication via INSERT OR IGNORE
-	if _, err := db.Exec("DELETE FROM seen_resources"); err != nil {
-		log.Printf("Warning: failed to clear seen resources: %v", err)
-	}
-
-	return store, nil
+	return db, nil
 }
 
-// initSchema creates the database tables if they don't exist
-func (s *SQLiteStore) initSchema() error {
-	schema := `
+// sqliteMigrations are applied in order; append new ones, never edit released ones
+var sqliteMigrations = []Migration{
+	{
+		Version: 1,
+		Name:    "events and seen_resources tables",
+		Up: `
 	CREATE TABLE IF NOT EXISTS events (
 		id TEXT PRIMARY KEY,
 		timestamp TEXT NOT NULL,
@@ -117,33 +129,132 @@ func (s *SQLiteStore) initSchema() error {
 		resource_key TEXT PRIMARY KEY,
 		seen_at TEXT DEFAULT (datetime('now'))
 	);
-	`
+	`,
+		Down: `DROP TABLE IF EXISTS seen_resources; DROP TABLE IF EXISTS events;`,
+	},
+	{
+		Version: 2,
+		Name:    "events api_group column",
+		Up:      `ALTER TABLE events ADD COLUMN api_group TEXT;`,
+		Down:    `ALTER TABLE events DROP COLUMN api_group;`,
+	},
+	{
+		// Earlier builds cleared seen_resources on every start, so nothing is lost
+		Version: 3,
+		Name:    "seen_resources scoped by cluster with uid",
+		Up: `
+	DROP TABLE IF EXISTS seen_resources;
+	CREATE TABLE seen_resources (
+		scope TEXT NOT NULL,
+		resource_key TEXT NOT NULL,
+		uid TEXT,
+		seen_at TEXT DEFAULT (datetime('now')),
+		PRIMARY KEY (scope, resource_key)
+	);
+	CREATE INDEX IF NOT EXISTS idx_seen_resources_seen_at ON seen_resources(scope, seen_at);
+	`,
+		Down: `
+	DROP TABLE IF EXISTS seen_resources;
+	CREATE TABLE seen_resources (
+		resource_key TEXT PRIMARY KEY,
+		seen_at TEXT DEFAULT (datetime('now'))
+	);
+	`,
+	}, {
+		Version: 4,
+		Name:    "events aggregate_json column",
+		Up:      `ALTER TABLE events ADD COLUMN aggregate_json TEXT;`,
+		Down:    `ALTER TABLE events DROP COLUMN aggregate_json;`,
+	}, {
+		Version: 5,
+		Name:    "events annotation_json column",
+		Up:      `ALTER TABLE events ADD COLUMN annotation_json TEXT;`,
+		Down:    `ALTER TABLE events DROP COLUMN annotation_json;`,
+	},
+}
 
-	_, err := s.db.Exec(schema)
-	return err
+func newSQLiteMigrator(db *sql.DB) *migrator {
+	return &migrator{db: db, dialect: sqliteDialect("schema_migrations"), migrations: sqliteMigrations}
+}
+
+// sqliteDialect records migrations in the given table, so databases holding more than
+// one schema can version each separately
+func sqliteDialect(table string) dialect {
+	return dialect{
+		table: table,
+		createTable: `CREATE TABLE IF NOT EXISTS ` + table + ` (
+			version INTEGER PRIMARY KEY,
+			name TEXT,
+			checksum TEXT,
+			applied_at TEXT DEFAULT (datetime('now'))
+		)`,
+		placeholder: func(int) string { return "?" },
+	}
 }
 
-// loadSeenResources loads the seen resources set from the database
-func (s *SQLiteStore) loadSeenResources() error {
-	rows, err := s.db.Query("SELECT resource_key FROM seen_resources")
+// initSchema checks the database's integrity and applies pending migrations, backing
+// up existing history first. Databases created before migrations were tracked adopt
+// version 1, whose statements are idempotent.
+func (s *SQLiteStore) initSchema() error {
+	ctx := context.Background()
+	if err := sqliteQuickCheck(ctx, s.db); err != nil {
+		return fmt.Errorf("%s: %w (restore a backup from %s.v*.bak or move the file aside)", s.path, err, s.path)
+	}
+
+	m := newSQLiteMigrator(s.db)
+	st, err := m.status(ctx)
 	if err != nil {
 		return err
 	}
-	defer rows.Close()
+	if len(st.Pending) > 0 {
+		backup, err := backupSQLite(ctx, s.db, s.path, st.Version)
+		if err != nil {
+			return err
+		}
+		if backup != "" {
+			log.Printf("Backed up timeline database to %s before migrating", backup)
+		}
+	}
+	_, _, err = m.up(ctx)
+	return err
+}
 
+// ResetSeen switches to scope's seen set: the most recently seen seenCapacity entries
+// are loaded, and older ones pruned from the database
+func (s *SQLiteStore) ResetSeen(scope string) error {
+	s.seen.reset()
 	s.seenMu.Lock()
-	defer s.seenMu.Unlock()
+	s.seenScope = scope
+	s.seenMu.Unlock()
+
+	if _, err := s.db.Exec(`DELETE FROM seen_resources WHERE scope = ? AND resource_key NOT IN (
+		SELECT resource_key FROM seen_resources WHERE scope = ? ORDER BY seen_at DESC LIMIT ?)`, scope, scope, seenCapacity); err != nil {
+		return err
+	}
+	rows, err := s.db.Query("SELECT resource_key, uid FROM seen_resources WHERE scope = ? ORDER BY seen_at ASC", scope)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
 
+	// Oldest first, so the most recently seen end up at the front of the LRU
 	for rows.Next() {
 		var key string
-		if err := rows.Scan(&key); err != nil {
+		var uid sql.NullString
+		if err := rows.Scan(&key, &uid); err != nil {
 			continue
 		}
-		s.seenResources[key] = true
+		s.seen.mark(key, uid.String)
 	}
 	return rows.Err()
 }
 
+func (s *SQLiteStore) currentSeenScope() string {
+	s.seenMu.RLock()
+	defer s.seenMu.RUnlock()
+	return s.seenScope
+}
+
 // Append adds a single event to the store
 func (s *SQLiteStore) Append(ctx context.Context, event TimelineEvent) error {
 	return s.AppendBatch(ctx, []TimelineEvent{event})
@@ -165,8 +276,12 @@ func (s *SQLiteStore) AppendBatch(ctx context.Context, events []TimelineEvent) e
 		INSERT OR IGNORE INTO events (
 			id, timestamp, source, kind, namespace, name, uid, event_type,
 			reason, message, diff_json, health_state, owner_kind, owner_name,
-			labels_json, count, correlation_id
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			labels_json, count, correlation_id, api_group, aggregate_json, annotation_json
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			timestamp = excluded.timestamp, message = excluded.message, count = excluded.count,
+			owner_kind = excluded.owner_kind, owner_name = excluded.owner_name, aggregate_json = excluded.aggregate_json
+		WHERE excluded.source = 'k8s_event'
 	`)
 	if err != nil {
 		return fmt.Errorf("failed to prepare statement: %w", err)
@@ -174,7 +289,7 @@ func (s *SQLiteStore) AppendBatch(ctx context.Context, events []TimelineEvent) e
 	defer stmt.Close()
 
 	for _, event := range events {
-		var diffJSON, labelsJSON []byte
+		var diffJSON, labelsJSON, aggregateJSON, annotationJSON []byte
 		var ownerKind, ownerName string
 		var err error
 
@@ -194,6 +309,16 @@ func (s *SQLiteStore) AppendBatch(ctx context.Context, events []TimelineEvent) e
 				labelsJSON = nil
 			}
 		}
+		if event.Aggregate != nil {
+			if aggregateJSON, err = json.Marshal(event.Aggregate); err != nil {
+				aggregateJSON = nil
+			}
+		}
+		if event.Annotation != nil {
+			if annotationJSON, err = json.Marshal(event.Annotation); err != nil {
+				annotationJSON = nil
+			}
+		}
 		if event.Owner != nil {
 			ownerKind = event.Owner.Kind
 			ownerName = event.Owner.Name
@@ -217,6 +342,9 @@ func (s *SQLiteStore) AppendBatch(ctx context.Context, events []TimelineEvent) e
 			string(labelsJSON),
 			event.Count,
 			event.CorrelationID,
+			event.Group,
+			string(aggregateJSON),
+			string(annotationJSON),
 		)
 		if err != nil {
 			return fmt.Errorf("failed to insert event: %w", err)
@@ -232,7 +360,7 @@ func (s *SQLiteStore) Query(ctx context.Context, opts QueryOptions) ([]TimelineE
 	query := strings.Builder{}
 	query.WriteString("SELECT id, timestamp, source, kind, namespace, name, uid, event_type, ")
 	query.WriteString("reason, message, diff_json, health_state, owner_kind, owner_name, ")
-	query.WriteString("labels_json, count, correlation_id FROM events WHERE 1=1")
+	query.WriteString("labels_json, count, correlation_id, api_group, aggregate_json, annotation_json FROM events WHERE 1=1")
 
 	var args []any
 
@@ -243,15 +371,10 @@ func (s *SQLiteStore) Query(ctx context.Context, opts QueryOptions) ([]TimelineE
 	}
 
 	if len(opts.Kinds) > 0 {
-		query.WriteString(" AND kind IN (")
-		for i, k := range opts.Kinds {
-			if i > 0 {
-				query.WriteString(",")
-			}
-			query.WriteString("?")
-			args = append(args, k)
-		}
-		query.WriteString(")")
+		query.WriteString(" AND " + kindFilterSQL(opts.Kinds, func(v any) string {
+			args = append(args, v)
+			return "?"
+		}))
 	}
 
 	if !opts.Since.IsZero() {
@@ -345,6 +468,25 @@ func (s *SQLiteStore) Query(ctx context.Context, opts QueryOptions) ([]TimelineE
 	return events, rows.Err()
 }
 
+// histogramCounts counts events per bucket and group in SQL. Timestamps are stored as
+// RFC3339 text, so buckets come from their Unix time (whole seconds).
+func (s *SQLiteStore) histogramCounts(ctx context.Context, opts HistogramOptions) ([]histogramCount, error) {
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return "?"
+	}
+	bucket := "(CAST(strftime('%s', timestamp) AS INTEGER) - " + arg(opts.Since.Unix()) + ") / " + arg(int64(opts.Bucket/time.Second))
+	query := histogramSQL(opts, "events", "timestamp", bucket,
+		opts.Since.Format(time.RFC3339Nano), opts.Until.Format(time.RFC3339Nano), arg)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("histogram query failed: %w", err)
+	}
+	return scanHistogramCounts(rows, len(opts.GroupBy))
+}
+
 // QueryGrouped retrieves events grouped according to the specified mode
 func (s *SQLiteStore) QueryGrouped(ctx context.Context, opts QueryOptions) (*TimelineResponse, error) {
 	startTime := time.Now()
@@ -402,7 +544,7 @@ func (s *SQLiteStore) QueryGrouped(ctx context.Context, opts QueryOptions) (*Tim
 func (s *SQLiteStore) GetEvent(ctx context.Context, id string) (*TimelineEvent, error) {
 	query := `SELECT id, timestamp, source, kind, namespace, name, uid, event_type,
 		reason, message, diff_json, health_state, owner_kind, owner_name,
-		labels_json, count, correlation_id FROM events WHERE id = ?`
+		labels_json, count, correlation_id, api_group, aggregate_json, annotation_json FROM events WHERE id = ?`
 
 	row := s.db.QueryRowContext(ctx, query, id)
 	event, err := s.scanEventRow(row)
@@ -423,7 +565,7 @@ func (s *SQLiteStore) GetChangesForOwner(ctx context.Context, ownerKind, ownerNa
 
 	query := `SELECT id, timestamp, source, kind, namespace, name, uid, event_type,
 		reason, message, diff_json, health_state, owner_kind, owner_name,
-		labels_json, count, correlation_id FROM events
+		labels_json, count, correlation_id, api_group, aggregate_json, annotation_json FROM events
 		WHERE owner_kind = ? AND owner_name = ? AND namespace = ?`
 
 	args := []any{ownerKind, ownerName, ownerNamespace}
@@ -453,35 +595,31 @@ func (s *SQLiteStore) GetChangesForOwner(ctx context.Context, ownerKind, ownerNa
 	return events, rows.Err()
 }
 
-// MarkResourceSeen records that a resource has been seen
-func (s *SQLiteStore) MarkResourceSeen(kind, namespace, name string) {
+// MarkResourceSeen records that a resource has been seen, persisting it (best effort) so
+// a restart doesn't record its creation again
+func (s *SQLiteStore) MarkResourceSeen(kind, namespace, name, uid string) {
 	key := ResourceKey(kind, namespace, name)
+	scope := s.currentSeenScope()
 
-	s.seenMu.Lock()
-	s.seenResources[key] = true
-	s.seenMu.Unlock()
-
-	// Persist to database (best effort)
-	_, _ = s.db.Exec("INSERT OR REPLACE INTO seen_resources (resource_key) VALUES (?)", key)
+	evicted := s.seen.mark(key, uid)
+	_, _ = s.db.Exec("INSERT OR REPLACE INTO seen_resources (scope, resource_key, uid, seen_at) VALUES (?, ?, ?, datetime('now'))", scope, key, uid)
+	for _, k := range evicted {
+		_, _ = s.db.Exec("DELETE FROM seen_resources WHERE scope = ? AND resource_key = ?", scope, k)
+	}
 }
 
 // IsResourceSeen checks if a resource has been seen before
-func (s *SQLiteStore) IsResourceSeen(kind, namespace, name string) bool {
-	s.seenMu.RLock()
-	defer s.seenMu.RUnlock()
-	return s.seenResources[ResourceKey(kind, namespace, name)]
+func (s *SQLiteStore) IsResourceSeen(kind, namespace, name, uid string) bool {
+	return s.seen.has(ResourceKey(kind, namespace, name), uid)
 }
 
 // ClearResourceSeen removes a resource from the seen set
 func (s *SQLiteStore) ClearResourceSeen(kind, namespace, name string) {
 	key := ResourceKey(kind, namespace, name)
-
-	s.seenMu.Lock()
-	delete(s.seenResources, key)
-	s.seenMu.Unlock()
+	s.seen.remove(key)
 
 	// Remove from database (best effort)
-	_, _ = s.db.Exec("DELETE FROM seen_resources WHERE resource_key = ?", key)
+	_, _ = s.db.Exec("DELETE FROM seen_resources WHERE scope = ? AND resource_key = ?", s.currentSeenScope(), key)
 }
 
 // Stats returns storage statistics
@@ -508,15 +646,21 @@ func (s *SQLiteStore) Stats() StoreStats {
 		stats.StorageBytes = info.Size()
 	}
 
-	s.seenMu.RLock()
-	stats.SeenResources = len(s.seenResources)
-	s.seenMu.RUnlock()
+	stats.SeenResources = s.seen.len()
 
 	return stats
 }
 
 // Close releases any resources held by the store
 func (s *SQLiteStore) Close() error {
+	if s.stopCh != nil {
+		close(s.stopCh)
+		s.wg.Wait()
+	}
+	// Fold the WAL into the database file so it's complete on its own (e.g. for backups)
+	if _, err := s.db.Exec("PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
+		log.Printf("Warning: failed to checkpoint timeline database: %v", err)
+	}
 	return s.db.Close()
 }
 
@@ -536,7 +680,7 @@ func (s *SQLiteStore) scanEvent(rows *sql.Rows) (TimelineEvent, error) {
 	var timestamp string
 	var source, eventType, healthState string
 	var uid, reason, message, diffJSON, labelsJSON sql.NullString
-	var ownerKind, ownerName, correlationID sql.NullString
+	var ownerKind, ownerName, correlationID, group, aggregateJSON, annotationJSON sql.NullString
 
 	err := rows.Scan(
 		&event.ID,
@@ -556,6 +700,9 @@ func (s *SQLiteStore) scanEvent(rows *sql.Rows) (TimelineEvent, error) {
 		&labelsJSON,
 		&event.Count,
 		&correlationID,
+		&group,
+		&aggregateJSON,
+		&annotationJSON,
 	)
 	if err != nil {
 		return event, err
@@ -578,6 +725,23 @@ func (s *SQLiteStore) scanEvent(rows *sql.Rows) (TimelineEvent, error) {
 	if correlationID.Valid {
 		event.CorrelationID = correlationID.String
 	}
+	if group.Valid {
+		event.Group = group.String
+	}
+
+	if aggregateJSON.Valid && aggregateJSON.String != "" {
+		var agg EventAggregate
+		if json.Unmarshal([]byte(aggregateJSON.String), &agg) == nil {
+			event.Aggregate = &agg
+		}
+	}
+
+	if annotationJSON.Valid && annotationJSON.String != "" {
+		var info AnnotationInfo
+		if json.Unmarshal([]byte(annotationJSON.String), &info) == nil {
+			event.Annotation = &info
+		}
+	}
 
 	if diffJSON.Valid && diffJSON.String != "" {
 		var diff DiffInfo
@@ -606,7 +770,7 @@ func (s *SQLiteStore) scanEventRow(row *sql.Row) (TimelineEvent, error) {
 	var timestamp string
 	var source, eventType, healthState string
 	var uid, reason, message, diffJSON, labelsJSON sql.NullString
-	var ownerKind, ownerName, correlationID sql.NullString
+	var ownerKind, ownerName, correlationID, group, aggregateJSON, annotationJSON sql.NullString
 
 	err := row.Scan(
 		&event.ID,
@@ -626,6 +790,9 @@ func (s *SQLiteStore) scanEventRow(row *sql.Row) (TimelineEvent, error) {
 		&labelsJSON,
 		&event.Count,
 		&correlationID,
+		&group,
+		&aggregateJSON,
+		&annotationJSON,
 	)
 	if err != nil {
 		return event, err
@@ -648,6 +815,23 @@ func (s *SQLiteStore) scanEventRow(row *sql.Row) (TimelineEvent, error) {
 	if correlationID.Valid {
 		event.CorrelationID = correlationID.String
 	}
+	if group.Valid {
+		event.Group = group.String
+	}
+
+	if aggregateJSON.Valid && aggregateJSON.String != "" {
+		var agg EventAggregate
+		if json.Unmarshal([]byte(aggregateJSON.String), &agg) == nil {
+			event.Aggregate = &agg
+		}
+	}
+
+	if annotationJSON.Valid && annotationJSON.String != "" {
+		var info AnnotationInfo
+		if json.Unmarshal([]byte(annotationJSON.String), &info) == nil {
+			event.Annotation = &info
+		}
+	}
 
 	if diffJSON.Valid && diffJSON.String != "" {
 		var diff DiffInfo
@@ -93,6 +93,37 @@ func TestSQLiteStore_AppendBatch(t *testing.T) {
 	}
 }
 
+func TestSQLiteStore_Query_QualifiedKinds(t *testing.T) {
+	store, cleanup := createTestSQLiteStore(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	events := []TimelineEvent{
+		{ID: "app-1", Timestamp: time.Now(), Kind: "Application", Group: "argoproj.io", Namespace: "argocd", Name: "web", EventType: EventTypeAdd, Source: SourceInformer},
+		{ID: "app-2", Timestamp: time.Now(), Kind: "Application", Group: "app.k8s.io", Namespace: "argocd", Name: "web", EventType: EventTypeAdd, Source: SourceInformer},
+		{ID: "deploy-1", Timestamp: time.Now(), Kind: "Deployment", Namespace: "argocd", Name: "web", EventType: EventTypeAdd, Source: SourceInformer},
+	}
+	if err := store.AppendBatch(ctx, events); err != nil {
+		t.Fatalf("AppendBatch failed: %v", err)
+	}
+
+	result, err := store.Query(ctx, QueryOptions{Kinds: []string{"Application.argoproj.io", "Deployment"}, Limit: 10, IncludeManaged: true})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(result) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(result))
+	}
+	for _, e := range result {
+		if e.ID == "app-2" {
+			t.Error("app.k8s.io Application should not match Application.argoproj.io")
+		}
+		if e.ID == "app-1" && e.Group != "argoproj.io" {
+			t.Errorf("Expected group argoproj.io, got %q", e.Group)
+		}
+	}
+}
+
 func TestSQLiteStore_Query_FilterPreset(t *testing.T) {
 	store, cleanup := createTestSQLiteStore(t)
 	defer cleanup()
@@ -256,15 +287,15 @@ func TestSQLiteStore_ResourceSeen(t *testing.T) {
 	defer cleanup()
 
 	// Initially not seen
-	if store.IsResourceSeen("Pod", "default", "test-pod") {
+	if store.IsResourceSeen("Pod", "default", "test-pod", "uid-1") {
 		t.Error("Resource should not be seen initially")
 	}
 
 	// Mark as seen
-	store.MarkResourceSeen("Pod", "default", "test-pod")
+	store.MarkResourceSeen("Pod", "default", "test-pod", "uid-1")
 
 	// Now should be seen
-	if !store.IsResourceSeen("Pod", "default", "test-pod") {
+	if !store.IsResourceSeen("Pod", "default", "test-pod", "uid-1") {
 		t.Error("Resource should be seen after marking")
 	}
 
@@ -272,11 +303,49 @@ func TestSQLiteStore_ResourceSeen(t *testing.T) {
 	store.ClearResourceSeen("Pod", "default", "test-pod")
 
 	// Should not be seen again
-	if store.IsResourceSeen("Pod", "default", "test-pod") {
+	if store.IsResourceSeen("Pod", "default", "test-pod", "uid-1") {
 		t.Error("Resource should not be seen after clearing")
 	}
 }
 
+func TestSQLiteStore_ResourceSeenPersistedPerScope(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "timeline.db")
+	store, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.ResetSeen("prod/ctx-a"); err != nil {
+		t.Fatal(err)
+	}
+	store.MarkResourceSeen("Pod", "default", "web", "uid-1")
+	store.Close()
+
+	// A restart in the same context remembers the resource
+	store, err = NewSQLiteStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	if err := store.ResetSeen("prod/ctx-a"); err != nil {
+		t.Fatal(err)
+	}
+	if !store.IsResourceSeen("Pod", "default", "web", "uid-1") {
+		t.Error("seen resource should survive a restart")
+	}
+	// Recreated under the same name while Radar was down
+	if store.IsResourceSeen("Pod", "default", "web", "uid-2") {
+		t.Error("a different UID should count as unseen")
+	}
+
+	// Another context starts empty
+	if err := store.ResetSeen("staging/ctx-b"); err != nil {
+		t.Fatal(err)
+	}
+	if store.IsResourceSeen("Pod", "default", "web", "uid-1") {
+		t.Error("seen resources should not leak across contexts")
+	}
+}
+
 func TestSQLiteStore_Stats(t *testing.T) {
 	store, cleanup := createTestSQLiteStore(t)
 	defer cleanup()
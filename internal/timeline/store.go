@@ -3,6 +3,7 @@ package timeline
 import (
 	"context"
 	"regexp"
+	"strings"
 	"time"
 )
 
@@ -27,15 +28,21 @@ type EventStore interface {
 	// GetChangesForOwner retrieves changes for resources owned by the given owner
 	GetChangesForOwner(ctx context.Context, ownerKind, ownerNamespace, ownerName string, since time.Time, limit int) ([]TimelineEvent, error)
 
-	// MarkResourceSeen records that a resource has been seen (for dedup on restart)
-	MarkResourceSeen(kind, namespace, name string)
+	// MarkResourceSeen records that a resource's creation has been recorded (for dedup on
+	// informer restarts). The set is bounded; the least recently seen are forgotten first.
+	MarkResourceSeen(kind, namespace, name, uid string)
 
-	// IsResourceSeen checks if a resource has been seen before
-	IsResourceSeen(kind, namespace, name string) bool
+	// IsResourceSeen checks if a resource has been seen before. A different UID means the
+	// resource was recreated, so it counts as unseen.
+	IsResourceSeen(kind, namespace, name, uid string) bool
 
 	// ClearResourceSeen removes a resource from the seen set (on delete)
 	ClearResourceSeen(kind, namespace, name string)
 
+	// ResetSeen forgets every seen resource and switches to the seen set of scope, the
+	// cluster and context being watched. Stores that persist the set load scope's entries.
+	ResetSeen(scope string) error
+
 	// Stats returns storage statistics
 	Stats() StoreStats
 
@@ -47,7 +54,7 @@ type EventStore interface {
 type QueryOptions struct {
 	// Filters
 	Namespace string        // Filter by namespace (empty = all)
-	Kinds     []string      // Filter by resource kinds (empty = all)
+	Kinds     []string      // Filter by resource kinds, bare or "Kind.group" (empty = all)
 	Since     time.Time     // Filter events after this time
 	Until     time.Time     // Filter events before this time
 	Sources   []EventSource // Filter by event source (empty = all)
@@ -173,6 +180,39 @@ func (cf *CompiledFilter) Matches(event *TimelineEvent) bool {
 	return true
 }
 
+// splitKindFilter splits a kind filter into kind and API group. "Application.argoproj.io"
+// matches only Argo CD Applications; a bare "Application" matches any group.
+func splitKindFilter(filter string) (kind, group string) {
+	kind, group, _ = strings.Cut(filter, ".")
+	return kind, group
+}
+
+// matchesKind reports whether an event is of one of the filter kinds
+func matchesKind(event *TimelineEvent, kinds []string) bool {
+	for _, k := range kinds {
+		kind, group := splitKindFilter(k)
+		if event.Kind == kind && (group == "" || event.Group == group) {
+			return true
+		}
+	}
+	return false
+}
+
+// kindFilterSQL builds a SQL condition for a kinds filter. arg adds a bind argument and
+// returns its placeholder.
+func kindFilterSQL(kinds []string, arg func(any) string) string {
+	conds := make([]string, len(kinds))
+	for i, k := range kinds {
+		kind, group := splitKindFilter(k)
+		if group == "" {
+			conds[i] = "kind = " + arg(kind)
+		} else {
+			conds[i] = "(kind = " + arg(kind) + " AND api_group = " + arg(group) + ")"
+		}
+	}
+	return "(" + strings.Join(conds, " OR ") + ")"
+}
+
 // ResourceKey generates a unique key for a resource
 func ResourceKey(kind, namespace, name string) string {
 	return kind + "/" + namespace + "/" + name
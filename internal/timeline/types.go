@@ -17,6 +17,12 @@ const (
 	SourceK8sEvent EventSource = "k8s_event"
 	// SourceHistorical means the event was reconstructed from resource metadata/status
 	SourceHistorical EventSource = "historical"
+	// SourceAudit means the event records an action a user took through Radar
+	SourceAudit EventSource = "audit"
+	// SourceAnalyzer means the event explains a pattern Radar detected across other events
+	SourceAnalyzer EventSource = "analyzer"
+	// SourceAnnotation means a user or CI job posted the event as a marker (deploy, incident note)
+	SourceAnnotation EventSource = "annotation"
 )
 
 // EventType categorizes what kind of event this is
@@ -33,6 +39,10 @@ const (
 	EventTypeNormal EventType = "Normal"
 	// EventTypeWarning is a Warning K8s event
 	EventTypeWarning EventType = "Warning"
+	// EventTypeAction is a user action taken through Radar (restart, edit, delete, rollback, ...)
+	EventTypeAction EventType = "action"
+	// EventTypeAnnotation is a manual marker posted through the annotations API
+	EventTypeAnnotation EventType = "annotation"
 )
 
 // HealthState represents the health of a resource
@@ -45,6 +55,33 @@ const (
 	HealthUnknown   HealthState = "unknown"
 )
 
+// ReplacementCause categorizes why a pod was replaced or a container restarted.
+// It is stored in TimelineEvent.Reason for informer events on Pods.
+type ReplacementCause string
+
+const (
+	// CauseSpecChange means a rollout replaced the pod after a pod template change
+	CauseSpecChange ReplacementCause = "SpecChange"
+	// CauseRolloutRestart means a rollout restart (restartedAt annotation) replaced the pod
+	CauseRolloutRestart ReplacementCause = "RolloutRestart"
+	// CauseEviction means the pod was evicted (API eviction or node pressure)
+	CauseEviction ReplacementCause = "Eviction"
+	// CausePreemption means the scheduler preempted the pod for a higher-priority pod
+	CausePreemption ReplacementCause = "Preemption"
+	// CauseOOM means a container was killed for exceeding its memory limit
+	CauseOOM ReplacementCause = "OOMKilled"
+	// CauseCrash means a container exited with an error
+	CauseCrash ReplacementCause = "ContainerCrash"
+	// CauseNodeFailure means the pod's node became unavailable
+	CauseNodeFailure ReplacementCause = "NodeFailure"
+	// CauseScaleDown means the owning controller reduced its replica count
+	CauseScaleDown ReplacementCause = "ScaleDown"
+	// CauseCompleted means the pod ran to completion (Jobs)
+	CauseCompleted ReplacementCause = "Completed"
+	// CauseUnknown means no cause could be inferred (e.g. manual deletion)
+	CauseUnknown ReplacementCause = "Unknown"
+)
+
 // GroupingMode determines how events are grouped in the timeline
 type GroupingMode string
 
@@ -70,6 +107,7 @@ type TimelineEvent struct {
 
 	// Resource identity
 	Kind      string `json:"kind"`
+	Group     string `json:"group,omitempty"` // API group, set for custom resources
 	Namespace string `json:"namespace"`
 	Name      string `json:"name"`
 	UID       string `json:"uid,omitempty"`
@@ -91,11 +129,47 @@ type TimelineEvent struct {
 
 	// K8s Event specific
 	Count int32 `json:"count,omitempty"`
+	// Aggregate is set on K8s events that stand for every recurrence of a reason on an
+	// object; Count is the total and Timestamp the last occurrence
+	Aggregate *EventAggregate `json:"aggregate,omitempty"`
+
+	// Annotation holds the author and links of a manual marker (SourceAnnotation)
+	Annotation *AnnotationInfo `json:"annotation,omitempty"`
 
 	// Correlation (for linking related events, e.g., rollout)
 	CorrelationID string `json:"correlationId,omitempty"`
 }
 
+// EventAggregate summarizes the recurrences behind an aggregated K8s event
+type EventAggregate struct {
+	FirstSeen   time.Time `json:"firstSeen"`
+	LastSeen    time.Time `json:"lastSeen"`
+	RatePerHour float64   `json:"ratePerHour"` // Occurrences per hour between first and last seen
+	// Occurrences are the most recent raw Events, oldest first
+	Occurrences []EventOccurrence `json:"occurrences,omitempty"`
+}
+
+// EventOccurrence is one corev1.Event folded into an aggregate
+type EventOccurrence struct {
+	Name      string    `json:"name"` // The Event object's name
+	Timestamp time.Time `json:"timestamp"`
+	Count     int32     `json:"count"`
+	Message   string    `json:"message,omitempty"`
+}
+
+// AnnotationInfo is the part of a manual marker that doesn't fit the resource event fields
+type AnnotationInfo struct {
+	Author string           `json:"author"`
+	Text   string           `json:"text,omitempty"` // Longer description under the title
+	Links  []AnnotationLink `json:"links,omitempty"`
+}
+
+// AnnotationLink points from an annotation to a release, incident ticket, dashboard, ...
+type AnnotationLink struct {
+	Title string `json:"title,omitempty"`
+	URL   string `json:"url"`
+}
+
 // OwnerInfo represents the owner/controller of a resource
 type OwnerInfo struct {
 	Kind string `json:"kind"`
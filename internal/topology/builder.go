@@ -12,6 +12,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 
+	"github.com/skyhook-io/radar/internal/health"
 	"github.com/skyhook-io/radar/internal/k8s"
 )
 
@@ -47,6 +48,10 @@ func (b *Builder) buildResourcesTopology(opts BuildOptions) (*Topology, error) {
 	edges := make([]Edge, 0)
 	warnings := make([]string, 0)
 
+	lists, listWarnings := b.gatherResources(opts)
+	warnings = append(warnings, listWarnings...)
+	quotas := newQuotaIndex(lists.quotas, lists.limitRanges)
+
 	// Track IDs for linking
 	deploymentIDs := make(map[string]string)
 	rolloutIDs := make(map[string]string) // Argo Rollouts
@@ -68,12 +73,7 @@ func (b *Builder) buildResourcesTopology(opts BuildOptions) (*Topology, error) {
 	workloadNamespaces := make(map[string]string) // workloadID -> namespace
 
 	// 1. Add Deployment nodes
-	deployments, err := b.cache.Deployments().List(labels.Everything())
-	if err != nil {
-		log.Printf("WARNING [topology] Failed to list Deployments: %v", err)
-		warnings = append(warnings, fmt.Sprintf("Failed to list Deployments: %v", err))
-	}
-	for _, deploy := range deployments {
+	for _, deploy := range lists.deployments {
 		if opts.Namespace != "" && deploy.Namespace != opts.Namespace {
 			continue
 		}
@@ -110,6 +110,8 @@ func (b *Builder) buildResourcesTopology(opts BuildOptions) (*Topology, error) {
 				"statusIssue":   statusIssue,
 			},
 		})
+		// Replicas the ReplicaSets couldn't create, e.g. because quota admission rejected them
+		setQuotaIssues(nodes[len(nodes)-1].Data, quotas.shortfall(deploy.Namespace, deploy.Spec.Template.Spec, total-deploy.Status.Replicas))
 
 		// Track ConfigMap/Secret/PVC references
 		refs := extractWorkloadReferences(deploy.Spec.Template.Spec)
@@ -128,85 +130,72 @@ func (b *Builder) buildResourcesTopology(opts BuildOptions) (*Topology, error) {
 	}
 
 	// 1b. Add Argo Rollout nodes (CRD - fetched via dynamic cache)
-	dynamicCache := k8s.GetDynamicResourceCache()
-	rolloutGVR, hasRollouts := k8s.GetResourceDiscovery().GetGVR("Rollout")
-	if hasRollouts && dynamicCache != nil {
-		rollouts, err := dynamicCache.List(rolloutGVR, opts.Namespace)
-		if err != nil {
-			log.Printf("WARNING [topology] Failed to list Rollouts: %v", err)
-			warnings = append(warnings, fmt.Sprintf("Failed to list Rollouts: %v", err))
-		}
-		for _, rollout := range rollouts {
-			ns := rollout.GetNamespace()
-			name := rollout.GetName()
-
-			rolloutID := fmt.Sprintf("rollout/%s/%s", ns, name)
-			rolloutIDs[ns+"/"+name] = rolloutID
-
-			// Extract status fields
-			status, _, _ := unstructured.NestedMap(rollout.Object, "status")
-			spec, _, _ := unstructured.NestedMap(rollout.Object, "spec")
-
-			var ready, total int64
-			if status != nil {
-				ready, _, _ = unstructured.NestedInt64(status, "readyReplicas")
-				total, _, _ = unstructured.NestedInt64(status, "replicas")
-			}
-			if total == 0 && spec != nil {
-				total, _, _ = unstructured.NestedInt64(spec, "replicas")
-			}
+	for _, rollout := range lists.rollouts {
+		ns := rollout.GetNamespace()
+		name := rollout.GetName()
 
-			// Get strategy type
-			strategy := "unknown"
-			if spec != nil {
-				if _, ok, _ := unstructured.NestedMap(spec, "strategy", "canary"); ok {
-					strategy = "Canary"
-				} else if _, ok, _ := unstructured.NestedMap(spec, "strategy", "blueGreen"); ok {
-					strategy = "BlueGreen"
-				}
+		rolloutID := nodeID("rollout", rolloutGroup, ns, name)
+		rolloutIDs[ns+"/"+name] = rolloutID
+
+		// Extract status fields
+		status, _, _ := unstructured.NestedMap(rollout.Object, "status")
+		spec, _, _ := unstructured.NestedMap(rollout.Object, "spec")
+
+		var ready, total int64
+		if status != nil {
+			ready, _, _ = unstructured.NestedInt64(status, "readyReplicas")
+			total, _, _ = unstructured.NestedInt64(status, "replicas")
+		}
+		if total == 0 && spec != nil {
+			total, _, _ = unstructured.NestedInt64(spec, "replicas")
+		}
+
+		// Get strategy type
+		strategy := "unknown"
+		if spec != nil {
+			if _, ok, _ := unstructured.NestedMap(spec, "strategy", "canary"); ok {
+				strategy = "Canary"
+			} else if _, ok, _ := unstructured.NestedMap(spec, "strategy", "blueGreen"); ok {
+				strategy = "BlueGreen"
 			}
+		}
 
-			nodes = append(nodes, Node{
-				ID:     rolloutID,
-				Kind:   "Rollout",
-				Name:   name,
-				Status: getDeploymentStatus(int32(ready), int32(total)),
-				Data: map[string]any{
-					"namespace":     ns,
-					"readyReplicas": ready,
-					"totalReplicas": total,
-					"strategy":      strategy,
-					"labels":        rollout.GetLabels(),
-				},
-			})
+		nodes = append(nodes, Node{
+			ID:     rolloutID,
+			Kind:   "Rollout",
+			Group:  rolloutGroup,
+			Name:   name,
+			Status: rolloutStatus(rollout, int32(ready), int32(total)),
+			Data: map[string]any{
+				"namespace":     ns,
+				"readyReplicas": ready,
+				"totalReplicas": total,
+				"strategy":      strategy,
+				"labels":        rollout.GetLabels(),
+			},
+		})
 
-			// Extract pod template spec for config references
-			template, _, _ := unstructured.NestedMap(spec, "template", "spec")
-			if template != nil {
-				refs := extractWorkloadReferencesFromMap(template)
-				if len(refs.configMaps) > 0 || len(refs.secrets) > 0 || len(refs.pvcs) > 0 {
-					workloadNamespaces[rolloutID] = ns
-				}
-				if len(refs.configMaps) > 0 {
-					workloadConfigMapRefs[rolloutID] = refs.configMaps
-				}
-				if len(refs.secrets) > 0 {
-					workloadSecretRefs[rolloutID] = refs.secrets
-				}
-				if len(refs.pvcs) > 0 {
-					workloadPVCRefs[rolloutID] = refs.pvcs
-				}
+		// Extract pod template spec for config references
+		template, _, _ := unstructured.NestedMap(spec, "template", "spec")
+		if template != nil {
+			refs := extractWorkloadReferencesFromMap(template)
+			if len(refs.configMaps) > 0 || len(refs.secrets) > 0 || len(refs.pvcs) > 0 {
+				workloadNamespaces[rolloutID] = ns
+			}
+			if len(refs.configMaps) > 0 {
+				workloadConfigMapRefs[rolloutID] = refs.configMaps
+			}
+			if len(refs.secrets) > 0 {
+				workloadSecretRefs[rolloutID] = refs.secrets
+			}
+			if len(refs.pvcs) > 0 {
+				workloadPVCRefs[rolloutID] = refs.pvcs
 			}
 		}
 	}
 
 	// 2. Add DaemonSet nodes
-	daemonsets, err := b.cache.DaemonSets().List(labels.Everything())
-	if err != nil {
-		log.Printf("WARNING [topology] Failed to list DaemonSets: %v", err)
-		warnings = append(warnings, fmt.Sprintf("Failed to list DaemonSets: %v", err))
-	}
-	for _, ds := range daemonsets {
+	for _, ds := range lists.daemonsets {
 		if opts.Namespace != "" && ds.Namespace != opts.Namespace {
 			continue
 		}
@@ -238,6 +227,7 @@ func (b *Builder) buildResourcesTopology(opts BuildOptions) (*Topology, error) {
 				"statusIssue":   statusIssue,
 			},
 		})
+		setQuotaIssues(nodes[len(nodes)-1].Data, quotas.shortfall(ds.Namespace, ds.Spec.Template.Spec, total-ds.Status.CurrentNumberScheduled))
 
 		refs := extractWorkloadReferences(ds.Spec.Template.Spec)
 		if len(refs.configMaps) > 0 || len(refs.secrets) > 0 || len(refs.pvcs) > 0 {
@@ -255,12 +245,7 @@ func (b *Builder) buildResourcesTopology(opts BuildOptions) (*Topology, error) {
 	}
 
 	// 3. Add StatefulSet nodes
-	statefulsets, err := b.cache.StatefulSets().List(labels.Everything())
-	if err != nil {
-		log.Printf("WARNING [topology] Failed to list StatefulSets: %v", err)
-		warnings = append(warnings, fmt.Sprintf("Failed to list StatefulSets: %v", err))
-	}
-	for _, sts := range statefulsets {
+	for _, sts := range lists.statefulsets {
 		if opts.Namespace != "" && sts.Namespace != opts.Namespace {
 			continue
 		}
@@ -296,6 +281,7 @@ func (b *Builder) buildResourcesTopology(opts BuildOptions) (*Topology, error) {
 				"statusIssue":   statusIssue,
 			},
 		})
+		setQuotaIssues(nodes[len(nodes)-1].Data, quotas.shortfall(sts.Namespace, sts.Spec.Template.Spec, total-sts.Status.Replicas))
 
 		refs := extractWorkloadReferences(sts.Spec.Template.Spec)
 		if len(refs.configMaps) > 0 || len(refs.secrets) > 0 || len(refs.pvcs) > 0 {
@@ -313,12 +299,7 @@ func (b *Builder) buildResourcesTopology(opts BuildOptions) (*Topology, error) {
 	}
 
 	// 4. Add CronJob nodes
-	cronjobs, err := b.cache.CronJobs().List(labels.Everything())
-	if err != nil {
-		log.Printf("WARNING [topology] Failed to list CronJobs: %v", err)
-		warnings = append(warnings, fmt.Sprintf("Failed to list CronJobs: %v", err))
-	}
-	for _, cj := range cronjobs {
+	for _, cj := range lists.cronjobs {
 		if opts.Namespace != "" && cj.Namespace != opts.Namespace {
 			continue
 		}
@@ -349,12 +330,7 @@ func (b *Builder) buildResourcesTopology(opts BuildOptions) (*Topology, error) {
 	}
 
 	// 5. Add Job nodes
-	jobs, err := b.cache.Jobs().List(labels.Everything())
-	if err != nil {
-		log.Printf("WARNING [topology] Failed to list Jobs: %v", err)
-		warnings = append(warnings, fmt.Sprintf("Failed to list Jobs: %v", err))
-	}
-	for _, job := range jobs {
+	for _, job := range lists.jobs {
 		if opts.Namespace != "" && job.Namespace != opts.Namespace {
 			continue
 		}
@@ -417,12 +393,7 @@ func (b *Builder) buildResourcesTopology(opts BuildOptions) (*Topology, error) {
 
 	// 6. Add ReplicaSet nodes (active ones) - if enabled
 	// Even if not shown, we still track them for shortcut edges
-	replicasets, err := b.cache.ReplicaSets().List(labels.Everything())
-	if err != nil {
-		log.Printf("WARNING [topology] Failed to list ReplicaSets: %v", err)
-		warnings = append(warnings, fmt.Sprintf("Failed to list ReplicaSets: %v", err))
-	}
-	for _, rs := range replicasets {
+	for _, rs := range lists.replicasets {
 		if opts.Namespace != "" && rs.Namespace != opts.Namespace {
 			continue
 		}
@@ -494,14 +465,9 @@ func (b *Builder) buildResourcesTopology(opts BuildOptions) (*Topology, error) {
 	}
 
 	// 5. Add Pod nodes - grouped by app label when there are multiple pods
-	pods, err := b.cache.Pods().List(labels.Everything())
-	if err != nil {
-		log.Printf("WARNING [topology] Failed to list Pods: %v", err)
-		warnings = append(warnings, fmt.Sprintf("Failed to list Pods: %v", err))
-	}
-	if len(pods) > 0 {
+	if len(lists.pods) > 0 {
 		// Group pods using shared grouping logic
-		groupingResult := GroupPods(pods, PodGroupingOptions{
+		groupingResult := GroupPods(lists.pods, PodGroupingOptions{
 			Namespace: opts.Namespace,
 		})
 
@@ -528,31 +494,14 @@ func (b *Builder) buildResourcesTopology(opts BuildOptions) (*Topology, error) {
 	}
 
 	// 8. Add Service nodes
-	services, err := b.cache.Services().List(labels.Everything())
-	if err != nil {
-		log.Printf("WARNING [topology] Failed to list Services: %v", err)
-		warnings = append(warnings, fmt.Sprintf("Failed to list Services: %v", err))
-	}
-
-	// Pre-index workloads by namespace for faster service-to-workload matching
-	// This avoids O(services × all_workloads) and instead does O(services × workloads_per_namespace)
-	deploymentsByNS := make(map[string][]*appsv1.Deployment)
-	for _, deploy := range deployments {
-		deploymentsByNS[deploy.Namespace] = append(deploymentsByNS[deploy.Namespace], deploy)
-	}
-	statefulsetsByNS := make(map[string][]*appsv1.StatefulSet)
-	for _, sts := range statefulsets {
-		statefulsetsByNS[sts.Namespace] = append(statefulsetsByNS[sts.Namespace], sts)
-	}
-	daemonsetsByNS := make(map[string][]*appsv1.DaemonSet)
-	for _, ds := range daemonsets {
-		daemonsetsByNS[ds.Namespace] = append(daemonsetsByNS[ds.Namespace], ds)
-	}
-
-	for _, svc := range services {
+	// Index workloads by namespace once, then match Services against them in parallel.
+	// This avoids O(services × all_workloads) and relisting Rollouts for every Service.
+	var nsServices []*corev1.Service
+	for _, svc := range lists.services {
 		if opts.Namespace != "" && svc.Namespace != opts.Namespace {
 			continue
 		}
+		nsServices = append(nsServices, svc)
 
 		svcID := fmt.Sprintf("service/%s/%s", svc.Namespace, svc.Name)
 		serviceIDs[svc.Namespace+"/"+svc.Name] = svcID
@@ -575,87 +524,11 @@ func (b *Builder) buildResourcesTopology(opts BuildOptions) (*Topology, error) {
 				"labels":    svc.Labels,
 			},
 		})
-
-		// Connect Service to Deployments via selector (using namespace-indexed lookup)
-		if svc.Spec.Selector != nil {
-			for _, deploy := range deploymentsByNS[svc.Namespace] {
-				if matchesSelector(deploy.Spec.Template.ObjectMeta.Labels, svc.Spec.Selector) {
-					deployID := deploymentIDs[deploy.Namespace+"/"+deploy.Name]
-					edges = append(edges, Edge{
-						ID:     fmt.Sprintf("%s-to-%s", svcID, deployID),
-						Source: svcID,
-						Target: deployID,
-						Type:   EdgeExposes,
-					})
-				}
-			}
-		}
-		// Check StatefulSets (using namespace-indexed lookup)
-		for _, sts := range statefulsetsByNS[svc.Namespace] {
-			if matchesSelector(sts.Spec.Template.ObjectMeta.Labels, svc.Spec.Selector) {
-				stsID := statefulSetIDs[sts.Namespace+"/"+sts.Name]
-				edges = append(edges, Edge{
-					ID:     fmt.Sprintf("%s-to-%s", svcID, stsID),
-					Source: svcID,
-					Target: stsID,
-					Type:   EdgeExposes,
-				})
-			}
-		}
-		// Check DaemonSets (using namespace-indexed lookup)
-		for _, ds := range daemonsetsByNS[svc.Namespace] {
-			if matchesSelector(ds.Spec.Template.ObjectMeta.Labels, svc.Spec.Selector) {
-				dsID := fmt.Sprintf("daemonset/%s/%s", ds.Namespace, ds.Name)
-				edges = append(edges, Edge{
-					ID:     fmt.Sprintf("%s-to-%s", svcID, dsID),
-					Source: svcID,
-					Target: dsID,
-					Type:   EdgeExposes,
-				})
-			}
-		}
-		// Check Rollouts (if we have any)
-		if hasRollouts && dynamicCache != nil {
-			svcRollouts, rolloutErr := dynamicCache.List(rolloutGVR, svc.Namespace)
-			if rolloutErr != nil {
-				log.Printf("WARNING [topology] Failed to list Rollouts for service %s/%s: %v", svc.Namespace, svc.Name, rolloutErr)
-				warnings = append(warnings, fmt.Sprintf("Failed to list Rollouts: %v", rolloutErr))
-			}
-			for _, rollout := range svcRollouts {
-				spec, _, _ := unstructured.NestedMap(rollout.Object, "spec", "template", "metadata")
-				if spec != nil {
-					if podLabels, ok := spec["labels"].(map[string]any); ok {
-						// Convert map[string]any to map[string]string for matching
-						strLabels := make(map[string]string)
-						for k, v := range podLabels {
-							if s, ok := v.(string); ok {
-								strLabels[k] = s
-							}
-						}
-						if matchesSelector(strLabels, svc.Spec.Selector) {
-							rolloutID := rolloutIDs[rollout.GetNamespace()+"/"+rollout.GetName()]
-							if rolloutID != "" {
-								edges = append(edges, Edge{
-									ID:     fmt.Sprintf("%s-to-%s", svcID, rolloutID),
-									Source: svcID,
-									Target: rolloutID,
-									Type:   EdgeExposes,
-								})
-							}
-						}
-					}
-				}
-			}
-		}
 	}
+	edges = append(edges, serviceExposeEdges(nsServices, indexSelectableWorkloads(lists, rolloutIDs), 0)...)
 
 	// 7. Add Ingress nodes
-	ingresses, err := b.cache.Ingresses().List(labels.Everything())
-	if err != nil {
-		log.Printf("WARNING [topology] Failed to list Ingresses: %v", err)
-		warnings = append(warnings, fmt.Sprintf("Failed to list Ingresses: %v", err))
-	}
-	for _, ing := range ingresses {
+	for _, ing := range lists.ingresses {
 		if opts.Namespace != "" && ing.Namespace != opts.Namespace {
 			continue
 		}
@@ -705,12 +578,7 @@ func (b *Builder) buildResourcesTopology(opts BuildOptions) (*Topology, error) {
 
 	// 8. Add ConfigMap nodes (if enabled)
 	if opts.IncludeConfigMaps {
-		configmaps, err := b.cache.ConfigMaps().List(labels.Everything())
-		if err != nil {
-			log.Printf("WARNING [topology] Failed to list ConfigMaps: %v", err)
-			warnings = append(warnings, fmt.Sprintf("Failed to list ConfigMaps: %v", err))
-		}
-		for _, cm := range configmaps {
+		for _, cm := range lists.configmaps {
 			if opts.Namespace != "" && cm.Namespace != opts.Namespace {
 				continue
 			}
@@ -753,67 +621,58 @@ func (b *Builder) buildResourcesTopology(opts BuildOptions) (*Topology, error) {
 
 	// 9. Add Secret nodes (if enabled and RBAC permits)
 	if opts.IncludeSecrets {
-		secretLister := b.cache.Secrets()
-		if secretLister == nil {
-			log.Printf("WARNING [topology] Secrets not available (RBAC not granted)")
-			warnings = append(warnings, "Secrets not available (RBAC not granted)")
-		} else {
-			secrets, err := secretLister.List(labels.Everything())
-			if err != nil {
-				log.Printf("WARNING [topology] Failed to list Secrets: %v", err)
-				warnings = append(warnings, fmt.Sprintf("Failed to list Secrets: %v", err))
+		for _, secret := range lists.secrets {
+			if opts.Namespace != "" && secret.Namespace != opts.Namespace {
+				continue
 			}
-			for _, secret := range secrets {
-				if opts.Namespace != "" && secret.Namespace != opts.Namespace {
-					continue
-				}
 
-				// Only include Secrets that are referenced by workloads in the same namespace
-				secretID := fmt.Sprintf("secret/%s/%s", secret.Namespace, secret.Name)
-				isReferenced := false
+			// Only include Secrets that are referenced by workloads in the same namespace
+			secretID := fmt.Sprintf("secret/%s/%s", secret.Namespace, secret.Name)
+			isReferenced := false
 
-				for workloadID, refs := range workloadSecretRefs {
-					// Only match if workload is in the same namespace as the Secret
-					if workloadNamespaces[workloadID] != secret.Namespace {
-						continue
-					}
-					if refs[secret.Name] {
-						isReferenced = true
-						edges = append(edges, Edge{
-							ID:     fmt.Sprintf("%s-to-%s", secretID, workloadID),
-							Source: secretID,
-							Target: workloadID,
-							Type:   EdgeConfigures,
-						})
-					}
+			for workloadID, refs := range workloadSecretRefs {
+				// Only match if workload is in the same namespace as the Secret
+				if workloadNamespaces[workloadID] != secret.Namespace {
+					continue
 				}
-
-				if isReferenced {
-					nodes = append(nodes, Node{
-						ID:     secretID,
-						Kind:   KindSecret,
-						Name:   secret.Name,
-						Status: StatusHealthy,
-						Data: map[string]any{
-							"namespace": secret.Namespace,
-							"type":      string(secret.Type),
-							"keys":      len(secret.Data),
-							"labels":    secret.Labels,
-						},
+				if refs[secret.Name] {
+					isReferenced = true
+					edges = append(edges, Edge{
+						ID:     fmt.Sprintf("%s-to-%s", secretID, workloadID),
+						Source: secretID,
+						Target: workloadID,
+						Type:   EdgeConfigures,
 					})
 				}
 			}
+
+			if isReferenced {
+				data := map[string]any{
+					"namespace": secret.Namespace,
+					"type":      string(secret.Type),
+					"keys":      len(secret.Data),
+					"labels":    secret.Labels,
+					"createdAt": secret.CreationTimestamp.Time,
+				}
+				// Metadata-only secrets have no data to count keys from
+				if b.cache.SecretsMetadataOnly() {
+					delete(data, "keys")
+					data["metadataOnly"] = true
+				}
+				nodes = append(nodes, Node{
+					ID:     secretID,
+					Kind:   KindSecret,
+					Name:   secret.Name,
+					Status: StatusHealthy,
+					Data:   data,
+				})
+			}
 		}
 	}
 
 	// 10. Add PVC nodes (if enabled)
 	if opts.IncludePVCs {
-		pvcs, err := b.cache.PersistentVolumeClaims().List(labels.Everything())
-		if err != nil {
-			log.Printf("WARNING [topology] Failed to list PersistentVolumeClaims: %v", err)
-			warnings = append(warnings, fmt.Sprintf("Failed to list PersistentVolumeClaims: %v", err))
-		}
-		for _, pvc := range pvcs {
+		for _, pvc := range lists.pvcs {
 			if opts.Namespace != "" && pvc.Namespace != opts.Namespace {
 				continue
 			}
@@ -871,12 +730,7 @@ func (b *Builder) buildResourcesTopology(opts BuildOptions) (*Topology, error) {
 	}
 
 	// 11. Add HPA nodes
-	hpas, err := b.cache.HorizontalPodAutoscalers().List(labels.Everything())
-	if err != nil {
-		log.Printf("WARNING [topology] Failed to list HorizontalPodAutoscalers: %v", err)
-		warnings = append(warnings, fmt.Sprintf("Failed to list HorizontalPodAutoscalers: %v", err))
-	}
-	for _, hpa := range hpas {
+	for _, hpa := range lists.hpas {
 		if opts.Namespace != "" && hpa.Namespace != opts.Namespace {
 			continue
 		}
@@ -924,6 +778,13 @@ func (b *Builder) buildResourcesTopology(opts BuildOptions) (*Topology, error) {
 		}
 	}
 
+	// 12. Add NetworkPolicy nodes and the traffic they allow or block
+	if opts.IncludeNetworkPolicies {
+		var npWarnings []string
+		nodes, edges, npWarnings = b.buildNetworkPolicies(opts, nodes, edges, lists)
+		warnings = append(warnings, npWarnings...)
+	}
+
 	return &Topology{Nodes: nodes, Edges: edges, Warnings: warnings}, nil
 }
 
@@ -951,11 +812,17 @@ func (b *Builder) buildTrafficTopology(opts BuildOptions) (*Topology, error) {
 		warnings = append(warnings, fmt.Sprintf("Failed to list Pods: %v", err))
 	}
 
-	// Pre-index pods by namespace to avoid O(services × all_pods) complexity
-	podsByNS := make(map[string][]*corev1.Pod)
-	for _, pod := range pods {
-		podsByNS[pod.Namespace] = append(podsByNS[pod.Namespace], pod)
+	virtualServices, err := listIstio("VirtualService", opts.Namespace)
+	if err != nil {
+		log.Printf("WARNING [topology/traffic] Failed to list VirtualServices: %v", err)
+		warnings = append(warnings, fmt.Sprintf("Failed to list VirtualServices: %v", err))
 	}
+	destinationRules, err := listIstio("DestinationRule", "")
+	if err != nil {
+		log.Printf("WARNING [topology/traffic] Failed to list DestinationRules: %v", err)
+		warnings = append(warnings, fmt.Sprintf("Failed to list DestinationRules: %v", err))
+	}
+	mesh := parseMeshRouting(virtualServices, destinationRules)
 
 	// Track which services and pods to include
 	servicesToInclude := make(map[string]*corev1.Service) // svcKey -> service
@@ -987,14 +854,8 @@ func (b *Builder) buildTrafficTopology(opts BuildOptions) (*Topology, error) {
 		}
 		svcKey := svc.Namespace + "/" + svc.Name
 
-		// Check if any pod matches this service's selector (using namespace-indexed pods)
-		hasPods := false
-		for _, pod := range podsByNS[svc.Namespace] {
-			if matchesSelector(pod.Labels, svc.Spec.Selector) {
-				hasPods = true
-				break
-			}
-		}
+		// Check if any pod matches this service's selector (using the cache's label index)
+		hasPods := len(b.cache.PodsWithLabels(svc.Namespace, svc.Spec.Selector)) > 0
 
 		// Include service if: referenced by ingress OR has matching pods
 		if servicesFromIngress[svcKey] || hasPods {
@@ -1110,12 +971,20 @@ func (b *Builder) buildTrafficTopology(opts BuildOptions) (*Topology, error) {
 	// Step 6: Aggregate pods by owner and create PodGroup nodes
 	// This prevents cluttering the graph with hundreds of individual pod nodes
 	// Uses shared grouping logic with service matching for traffic view
-	groupingResult := GroupPods(pods, PodGroupingOptions{
+	// Pods are split by the Istio subsets routed to, so routes can target each subset
+	groupingOpts := PodGroupingOptions{
 		Namespace:       opts.Namespace,
 		ServiceMatching: true,
 		ServicesByNS:    servicesByNS,
 		ServiceIDs:      serviceIDs,
-	})
+	}
+	if len(mesh.subsets) > 0 {
+		groupingOpts.Subset = func(pod *corev1.Pod) string { return mesh.subsetOf(pod, servicesByNS) }
+	}
+	groupingResult := GroupPods(pods, groupingOpts)
+
+	// Services with VirtualService routes get routed edges instead of their selector edges
+	routedEdges, meshRouted := meshEdges(mesh, serviceIDs, groupingResult.Groups)
 
 	// Create nodes and edges for each group
 	for _, group := range groupingResult.Groups {
@@ -1123,10 +992,17 @@ func (b *Builder) buildTrafficTopology(opts BuildOptions) (*Topology, error) {
 			// Single pod - show as individual node
 			pod := group.Pods[0]
 			podID := GetPodID(pod)
-			nodes = append(nodes, CreatePodNode(pod, b.cache, false)) // includeNodeName=false for traffic view
+			podNode := CreatePodNode(pod, b.cache, false) // includeNodeName=false for traffic view
+			if group.Subset != "" {
+				podNode.Data["subset"] = group.Subset
+			}
+			nodes = append(nodes, podNode)
 
 			// Add edges from services to pod (traffic view specific)
 			for svcID := range group.ServiceIDs {
+				if meshRouted[svcID] {
+					continue
+				}
 				edges = append(edges, Edge{
 					ID:     fmt.Sprintf("%s-to-%s", svcID, podID),
 					Source: svcID,
@@ -1141,6 +1017,9 @@ func (b *Builder) buildTrafficTopology(opts BuildOptions) (*Topology, error) {
 
 			// Add edges from services to pod group (traffic view specific)
 			for svcID := range group.ServiceIDs {
+				if meshRouted[svcID] {
+					continue
+				}
 				edges = append(edges, Edge{
 					ID:     fmt.Sprintf("%s-to-%s", svcID, podGroupID),
 					Source: svcID,
@@ -1151,7 +1030,11 @@ func (b *Builder) buildTrafficTopology(opts BuildOptions) (*Topology, error) {
 		}
 	}
 
-	return &Topology{Nodes: nodes, Edges: edges, Warnings: warnings}, nil
+	edges = append(edges, routedEdges...)
+
+	topo := &Topology{Nodes: nodes, Edges: edges, Warnings: warnings}
+	applyTrafficLoad(topo, currentTrafficLoad())
+	return topo, nil
 }
 
 // Helper functions
@@ -1269,6 +1152,15 @@ func getDeploymentStatus(ready, total int32) HealthStatus {
 	return StatusUnhealthy
 }
 
+// rolloutStatus uses the health rules for Rollouts, falling back to replica readiness
+// when they can't tell
+func rolloutStatus(rollout *unstructured.Unstructured, ready, total int32) HealthStatus {
+	if state := health.Assess(rollout); state != health.Unknown {
+		return HealthStatus(state)
+	}
+	return getDeploymentStatus(ready, total)
+}
+
 func getJobStatus(job *batchv1.Job) HealthStatus {
 	// Check completion conditions
 	for _, cond := range job.Status.Conditions {
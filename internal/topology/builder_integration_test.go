@@ -0,0 +1,46 @@
+package topology
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/skyhook-io/radar/internal/testenv"
+)
+
+func TestBuildAgainstCluster(t *testing.T) {
+	objs := testenv.App("shop", "web", testenv.WithIngress("shop.example.com"), testenv.WithConfigMap(), testenv.WithHPA(2, 4))
+	objs = append(objs, testenv.App("shop", "worker", testenv.WithCrashLoop())...)
+	testenv.Setup(t, objs...)
+
+	topo, err := NewBuilder().Build(DefaultBuildOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var edges []string
+	for _, e := range topo.Edges {
+		edges = append(edges, e.Source+" -"+string(e.Type)+"-> "+e.Target)
+	}
+	sort.Strings(edges)
+	want := []string{
+		"configmap/shop/web-config -configures-> deployment/shop/web",
+		"deployment/shop/web -manages-> podgroup-shop-app-web",
+		"deployment/shop/worker -manages-> podgroup-shop-app-worker",
+		"hpa/shop/web -uses-> deployment/shop/web",
+		"ingress/shop/web -routes-to-> service/shop/web",
+		"service/shop/web -exposes-> deployment/shop/web",
+		"service/shop/worker -exposes-> deployment/shop/worker",
+	}
+	if !reflect.DeepEqual(edges, want) {
+		t.Errorf("edges:\n got %v\nwant %v", edges, want)
+	}
+
+	status := map[string]HealthStatus{}
+	for _, n := range topo.Nodes {
+		status[n.ID] = n.Status
+	}
+	if status["deployment/shop/web"] != StatusHealthy || status["deployment/shop/worker"] != StatusUnhealthy {
+		t.Errorf("deployment status: web=%s worker=%s", status["deployment/shop/web"], status["deployment/shop/worker"])
+	}
+}
@@ -0,0 +1,116 @@
+package topology
+
+import "reflect"
+
+// Delta is the change between two topology graphs. Nodes and edges are matched by ID;
+// updated ones are sent whole. Warnings are the new graph's, replacing the old.
+type Delta struct {
+	AddedNodes   []Node   `json:"addedNodes,omitempty"`
+	UpdatedNodes []Node   `json:"updatedNodes,omitempty"`
+	RemovedNodes []string `json:"removedNodes,omitempty"`
+	AddedEdges   []Edge   `json:"addedEdges,omitempty"`
+	UpdatedEdges []Edge   `json:"updatedEdges,omitempty"`
+	RemovedEdges []string `json:"removedEdges,omitempty"`
+	Warnings     []string `json:"warnings,omitempty"`
+}
+
+// Diff returns the delta that turns prev into next
+func Diff(prev, next *Topology) *Delta {
+	d := &Delta{Warnings: next.Warnings}
+
+	prevNodes := make(map[string]*Node, len(prev.Nodes))
+	for i := range prev.Nodes {
+		prevNodes[prev.Nodes[i].ID] = &prev.Nodes[i]
+	}
+	for _, n := range next.Nodes {
+		old, ok := prevNodes[n.ID]
+		switch {
+		case !ok:
+			d.AddedNodes = append(d.AddedNodes, n)
+		case !reflect.DeepEqual(*old, n):
+			d.UpdatedNodes = append(d.UpdatedNodes, n)
+		}
+		delete(prevNodes, n.ID)
+	}
+	for _, n := range prev.Nodes {
+		if _, gone := prevNodes[n.ID]; gone {
+			d.RemovedNodes = append(d.RemovedNodes, n.ID)
+		}
+	}
+
+	prevEdges := make(map[string]*Edge, len(prev.Edges))
+	for i := range prev.Edges {
+		prevEdges[prev.Edges[i].ID] = &prev.Edges[i]
+	}
+	for _, e := range next.Edges {
+		old, ok := prevEdges[e.ID]
+		switch {
+		case !ok:
+			d.AddedEdges = append(d.AddedEdges, e)
+		case !reflect.DeepEqual(*old, e):
+			d.UpdatedEdges = append(d.UpdatedEdges, e)
+		}
+		delete(prevEdges, e.ID)
+	}
+	for _, e := range prev.Edges {
+		if _, gone := prevEdges[e.ID]; gone {
+			d.RemovedEdges = append(d.RemovedEdges, e.ID)
+		}
+	}
+	return d
+}
+
+// Empty reports whether the delta changes no nodes or edges
+func (d *Delta) Empty() bool {
+	return len(d.AddedNodes) == 0 && len(d.UpdatedNodes) == 0 && len(d.RemovedNodes) == 0 &&
+		len(d.AddedEdges) == 0 && len(d.UpdatedEdges) == 0 && len(d.RemovedEdges) == 0
+}
+
+// Apply returns topo with the delta applied, keeping the order of surviving nodes and
+// edges and appending added ones. topo is not modified.
+func (d *Delta) Apply(topo *Topology) *Topology {
+	out := &Topology{
+		Nodes:    make([]Node, 0, len(topo.Nodes)+len(d.AddedNodes)-len(d.RemovedNodes)),
+		Edges:    make([]Edge, 0, len(topo.Edges)+len(d.AddedEdges)-len(d.RemovedEdges)),
+		Warnings: d.Warnings,
+	}
+
+	removed := make(map[string]bool, len(d.RemovedNodes))
+	for _, id := range d.RemovedNodes {
+		removed[id] = true
+	}
+	updatedNodes := make(map[string]Node, len(d.UpdatedNodes))
+	for _, n := range d.UpdatedNodes {
+		updatedNodes[n.ID] = n
+	}
+	for _, n := range topo.Nodes {
+		if removed[n.ID] {
+			continue
+		}
+		if u, ok := updatedNodes[n.ID]; ok {
+			n = u
+		}
+		out.Nodes = append(out.Nodes, n)
+	}
+	out.Nodes = append(out.Nodes, d.AddedNodes...)
+
+	removed = make(map[string]bool, len(d.RemovedEdges))
+	for _, id := range d.RemovedEdges {
+		removed[id] = true
+	}
+	updatedEdges := make(map[string]Edge, len(d.UpdatedEdges))
+	for _, e := range d.UpdatedEdges {
+		updatedEdges[e.ID] = e
+	}
+	for _, e := range topo.Edges {
+		if removed[e.ID] {
+			continue
+		}
+		if u, ok := updatedEdges[e.ID]; ok {
+			e = u
+		}
+		out.Edges = append(out.Edges, e)
+	}
+	out.Edges = append(out.Edges, d.AddedEdges...)
+	return out
+}
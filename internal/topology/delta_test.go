@@ -0,0 +1,68 @@
+package topology
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffAndApply(t *testing.T) {
+	prev := &Topology{
+		Nodes: []Node{
+			{ID: "deployment/shop/web", Kind: KindDeployment, Name: "web", Status: StatusHealthy, Data: map[string]any{"readyReplicas": 2}},
+			{ID: "service/shop/web", Kind: KindService, Name: "web", Status: StatusHealthy, Data: map[string]any{}},
+			{ID: "pod/shop/web-1", Kind: KindPod, Name: "web-1", Status: StatusHealthy, Data: map[string]any{}},
+		},
+		Edges: []Edge{
+			{ID: "service/shop/web-to-deployment/shop/web", Source: "service/shop/web", Target: "deployment/shop/web", Type: EdgeExposes},
+			{ID: "deployment/shop/web-to-pod/shop/web-1", Source: "deployment/shop/web", Target: "pod/shop/web-1", Type: EdgeManages},
+		},
+	}
+	next := &Topology{
+		Nodes: []Node{
+			{ID: "deployment/shop/web", Kind: KindDeployment, Name: "web", Status: StatusDegraded, Data: map[string]any{"readyReplicas": 1}},
+			{ID: "service/shop/web", Kind: KindService, Name: "web", Status: StatusHealthy, Data: map[string]any{}},
+			{ID: "pod/shop/web-2", Kind: KindPod, Name: "web-2", Status: StatusUnhealthy, Data: map[string]any{}},
+		},
+		Edges: []Edge{
+			{ID: "service/shop/web-to-deployment/shop/web", Source: "service/shop/web", Target: "deployment/shop/web", Type: EdgeExposes, Label: "80"},
+			{ID: "deployment/shop/web-to-pod/shop/web-2", Source: "deployment/shop/web", Target: "pod/shop/web-2", Type: EdgeManages},
+		},
+		Warnings: []string{"Failed to list Ingresses"},
+	}
+
+	d := Diff(prev, next)
+	if len(d.AddedNodes) != 1 || d.AddedNodes[0].ID != "pod/shop/web-2" {
+		t.Errorf("added nodes = %+v, want web-2", d.AddedNodes)
+	}
+	if len(d.UpdatedNodes) != 1 || d.UpdatedNodes[0].ID != "deployment/shop/web" {
+		t.Errorf("updated nodes = %+v, want the deployment", d.UpdatedNodes)
+	}
+	if !reflect.DeepEqual(d.RemovedNodes, []string{"pod/shop/web-1"}) {
+		t.Errorf("removed nodes = %v, want web-1", d.RemovedNodes)
+	}
+	if len(d.AddedEdges) != 1 || len(d.UpdatedEdges) != 1 || len(d.RemovedEdges) != 1 {
+		t.Errorf("edges added=%d updated=%d removed=%d, want 1 each", len(d.AddedEdges), len(d.UpdatedEdges), len(d.RemovedEdges))
+	}
+
+	got := d.Apply(prev)
+	if !reflect.DeepEqual(got, next) {
+		t.Errorf("Apply(prev) =\n%+v\nwant\n%+v", got, next)
+	}
+	if prev.Nodes[0].Status != StatusHealthy {
+		t.Error("Apply modified its input")
+	}
+}
+
+func TestDiffUnchanged(t *testing.T) {
+	topo := &Topology{
+		Nodes: []Node{{ID: "service/shop/web", Kind: KindService, Data: map[string]any{"ports": []any{80}}}},
+		Edges: []Edge{{ID: "a-to-b", Source: "a", Target: "b"}},
+	}
+	same := &Topology{
+		Nodes: []Node{{ID: "service/shop/web", Kind: KindService, Data: map[string]any{"ports": []any{80}}}},
+		Edges: []Edge{{ID: "a-to-b", Source: "a", Target: "b"}},
+	}
+	if d := Diff(topo, same); !d.Empty() {
+		t.Errorf("Diff of equal graphs = %+v, want empty", d)
+	}
+}
@@ -0,0 +1,228 @@
+package topology
+
+import (
+	"fmt"
+	"log"
+	"runtime"
+	"sync"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// listConcurrency bounds how many resource kinds are listed at once
+const listConcurrency = 4
+
+// serviceShardSize is the number of services each worker matches against workloads at a time
+const serviceShardSize = 64
+
+// resourceLists holds every kind the resources view is built from, listed up front so
+// nothing is listed again inside per-resource loops
+type resourceLists struct {
+	deployments  []*appsv1.Deployment
+	rollouts     []*unstructured.Unstructured // Argo Rollouts (dynamic cache), nil if not installed
+	daemonsets   []*appsv1.DaemonSet
+	statefulsets []*appsv1.StatefulSet
+	cronjobs     []*batchv1.CronJob
+	jobs         []*batchv1.Job
+	replicasets  []*appsv1.ReplicaSet
+	pods         []*corev1.Pod
+	services     []*corev1.Service
+	ingresses    []*networkingv1.Ingress
+	configmaps   []*corev1.ConfigMap
+	secrets      []*corev1.Secret
+	pvcs         []*corev1.PersistentVolumeClaim
+	hpas         []*autoscalingv2.HorizontalPodAutoscaler
+	quotas       []*corev1.ResourceQuota
+	limitRanges  []*corev1.LimitRange
+}
+
+// listTask lists one kind into a resourceLists field
+type listTask struct {
+	kind string
+	list func() error
+}
+
+// gatherResources lists the kinds needed for opts concurrently. Warnings are returned in a
+// stable order regardless of which list finishes first.
+func (b *Builder) gatherResources(opts BuildOptions) (*resourceLists, []string) {
+	l := &resourceLists{}
+	tasks := []listTask{
+		{"Deployments", func() (err error) { l.deployments, err = b.cache.Deployments().List(labels.Everything()); return }},
+		{"Rollouts", func() (err error) { l.rollouts, err = listRollouts(opts.Namespace); return }},
+		{"DaemonSets", func() (err error) { l.daemonsets, err = b.cache.DaemonSets().List(labels.Everything()); return }},
+		{"StatefulSets", func() (err error) { l.statefulsets, err = b.cache.StatefulSets().List(labels.Everything()); return }},
+		{"CronJobs", func() (err error) { l.cronjobs, err = b.cache.CronJobs().List(labels.Everything()); return }},
+		{"Jobs", func() (err error) { l.jobs, err = b.cache.Jobs().List(labels.Everything()); return }},
+		{"ReplicaSets", func() (err error) { l.replicasets, err = b.cache.ReplicaSets().List(labels.Everything()); return }},
+		{"Pods", func() (err error) { l.pods, err = b.cache.Pods().List(labels.Everything()); return }},
+		{"Services", func() (err error) { l.services, err = b.cache.Services().List(labels.Everything()); return }},
+		{"Ingresses", func() (err error) { l.ingresses, err = b.cache.Ingresses().List(labels.Everything()); return }},
+		{"HorizontalPodAutoscalers", func() (err error) {
+			l.hpas, err = b.cache.HorizontalPodAutoscalers().List(labels.Everything())
+			return
+		}},
+		{"ResourceQuotas", func() (err error) { l.quotas, err = b.cache.ResourceQuotas().List(labels.Everything()); return }},
+		{"LimitRanges", func() (err error) { l.limitRanges, err = b.cache.LimitRanges().List(labels.Everything()); return }},
+	}
+	if opts.IncludeConfigMaps {
+		tasks = append(tasks, listTask{"ConfigMaps", func() (err error) { l.configmaps, err = b.cache.ConfigMaps().List(labels.Everything()); return }})
+	}
+	if opts.IncludePVCs {
+		tasks = append(tasks, listTask{"PersistentVolumeClaims", func() (err error) {
+			l.pvcs, err = b.cache.PersistentVolumeClaims().List(labels.Everything())
+			return
+		}})
+	}
+
+	var warnings []string
+	secretLister := b.cache.Secrets()
+	if opts.IncludeSecrets {
+		if secretLister == nil {
+			log.Printf("WARNING [topology] Secrets not available (RBAC not granted)")
+			warnings = append(warnings, "Secrets not available (RBAC not granted)")
+		} else {
+			tasks = append(tasks, listTask{"Secrets", func() (err error) { l.secrets, err = secretLister.List(labels.Everything()); return }})
+		}
+	}
+
+	errs := make([]error, len(tasks))
+	forEachBounded(len(tasks), listConcurrency, func(i int) {
+		errs[i] = tasks[i].list()
+	})
+	for i, err := range errs {
+		if err != nil {
+			log.Printf("WARNING [topology] Failed to list %s: %v", tasks[i].kind, err)
+			warnings = append(warnings, fmt.Sprintf("Failed to list %s: %v", tasks[i].kind, err))
+		}
+	}
+	return l, warnings
+}
+
+// listRollouts lists Argo Rollouts, or returns nil when the CRD isn't installed
+func listRollouts(namespace string) ([]*unstructured.Unstructured, error) {
+	dynamicCache := k8s.GetDynamicResourceCache()
+	gvr, ok := k8s.GetResourceDiscovery().GetGVRWithGroup("Rollout", rolloutGroup)
+	if !ok || dynamicCache == nil {
+		return nil, nil
+	}
+	return dynamicCache.List(gvr, namespace)
+}
+
+// forEachBounded calls fn for 0..n-1 on at most workers goroutines
+func forEachBounded(n, workers int, fn func(i int)) {
+	if workers > n {
+		workers = n
+	}
+	if workers <= 1 {
+		for i := 0; i < n; i++ {
+			fn(i)
+		}
+		return
+	}
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+}
+
+// selectableWorkload is a workload a Service can expose, with its pod template labels
+type selectableWorkload struct {
+	id     string
+	labels map[string]string
+}
+
+// indexSelectableWorkloads groups workloads by namespace for service matching, in the
+// order Deployments, StatefulSets, DaemonSets, Rollouts. Rollout labels are converted
+// once here rather than for every Service.
+func indexSelectableWorkloads(l *resourceLists, rolloutIDs map[string]string) map[string][]selectableWorkload {
+	byNS := make(map[string][]selectableWorkload)
+	for _, deploy := range l.deployments {
+		byNS[deploy.Namespace] = append(byNS[deploy.Namespace], selectableWorkload{
+			id:     fmt.Sprintf("deployment/%s/%s", deploy.Namespace, deploy.Name),
+			labels: deploy.Spec.Template.Labels,
+		})
+	}
+	for _, sts := range l.statefulsets {
+		byNS[sts.Namespace] = append(byNS[sts.Namespace], selectableWorkload{
+			id:     fmt.Sprintf("statefulset/%s/%s", sts.Namespace, sts.Name),
+			labels: sts.Spec.Template.Labels,
+		})
+	}
+	for _, ds := range l.daemonsets {
+		byNS[ds.Namespace] = append(byNS[ds.Namespace], selectableWorkload{
+			id:     fmt.Sprintf("daemonset/%s/%s", ds.Namespace, ds.Name),
+			labels: ds.Spec.Template.Labels,
+		})
+	}
+	for _, rollout := range l.rollouts {
+		ns := rollout.GetNamespace()
+		rolloutID := rolloutIDs[ns+"/"+rollout.GetName()]
+		if rolloutID == "" {
+			continue
+		}
+		podLabels, found, _ := unstructured.NestedStringMap(rollout.Object, "spec", "template", "metadata", "labels")
+		if !found {
+			continue
+		}
+		byNS[ns] = append(byNS[ns], selectableWorkload{id: rolloutID, labels: podLabels})
+	}
+	return byNS
+}
+
+// serviceExposeEdges matches each Service's selector against the workloads in its
+// namespace. Services are split into shards matched on up to workers goroutines; edges
+// come back in service order, the same as a sequential pass.
+func serviceExposeEdges(services []*corev1.Service, workloadsByNS map[string][]selectableWorkload, workers int) []Edge {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	shards := (len(services) + serviceShardSize - 1) / serviceShardSize
+	results := make([][]Edge, shards)
+	forEachBounded(shards, workers, func(shard int) {
+		end := min((shard+1)*serviceShardSize, len(services))
+		var edges []Edge
+		for _, svc := range services[shard*serviceShardSize : end] {
+			if len(svc.Spec.Selector) == 0 {
+				continue
+			}
+			svcID := fmt.Sprintf("service/%s/%s", svc.Namespace, svc.Name)
+			for _, w := range workloadsByNS[svc.Namespace] {
+				if matchesSelector(w.labels, svc.Spec.Selector) {
+					edges = append(edges, Edge{
+						ID:     fmt.Sprintf("%s-to-%s", svcID, w.id),
+						Source: svcID,
+						Target: w.id,
+						Type:   EdgeExposes,
+					})
+				}
+			}
+		}
+		results[shard] = edges
+	})
+
+	var edges []Edge
+	for _, r := range results {
+		edges = append(edges, r...)
+	}
+	return edges
+}
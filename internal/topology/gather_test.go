@@ -0,0 +1,160 @@
+package topology
+
+import (
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// syntheticCluster returns services, each selecting one Deployment and one Rollout,
+// spread over namespaces of servicesPerNS
+func syntheticCluster(services, servicesPerNS int) (*resourceLists, map[string]string) {
+	l := &resourceLists{}
+	rolloutIDs := make(map[string]string)
+	for i := 0; i < services; i++ {
+		ns := fmt.Sprintf("ns-%d", i/servicesPerNS)
+		app := fmt.Sprintf("app-%d", i)
+		podLabels := map[string]string{"app": app, "tier": "backend"}
+
+		l.services = append(l.services, &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: app, Namespace: ns},
+			Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": app}},
+		})
+		l.deployments = append(l.deployments, &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: app, Namespace: ns},
+			Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: podLabels},
+			}},
+		})
+
+		rolloutLabels := map[string]any{"app": app + "-canary", "tier": "backend"}
+		rollout := &unstructured.Unstructured{Object: map[string]any{
+			"spec": map[string]any{"template": map[string]any{"metadata": map[string]any{"labels": rolloutLabels}}},
+		}}
+		rollout.SetNamespace(ns)
+		rollout.SetName(app + "-canary")
+		l.rollouts = append(l.rollouts, rollout)
+		rolloutIDs[ns+"/"+app+"-canary"] = nodeID("rollout", rolloutGroup, ns, app+"-canary")
+	}
+	return l, rolloutIDs
+}
+
+func TestServiceExposeEdges(t *testing.T) {
+	l, rolloutIDs := syntheticCluster(3, 10)
+	// A service without a selector exposes nothing
+	l.services = append(l.services, &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "external", Namespace: "ns-0"}})
+	// A service selecting by tier matches every workload in its namespace
+	l.services = append(l.services, &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "backend", Namespace: "ns-0"},
+		Spec:       corev1.ServiceSpec{Selector: map[string]string{"tier": "backend"}},
+	})
+
+	edges := serviceExposeEdges(l.services, indexSelectableWorkloads(l, rolloutIDs), 1)
+	var got []string
+	for _, e := range edges {
+		got = append(got, e.Source+" -> "+e.Target)
+	}
+	want := []string{
+		"service/ns-0/app-0 -> deployment/ns-0/app-0",
+		"service/ns-0/app-1 -> deployment/ns-0/app-1",
+		"service/ns-0/app-2 -> deployment/ns-0/app-2",
+		"service/ns-0/backend -> deployment/ns-0/app-0",
+		"service/ns-0/backend -> deployment/ns-0/app-1",
+		"service/ns-0/backend -> deployment/ns-0/app-2",
+		"service/ns-0/backend -> rollout.argoproj.io/ns-0/app-0-canary",
+		"service/ns-0/backend -> rollout.argoproj.io/ns-0/app-1-canary",
+		"service/ns-0/backend -> rollout.argoproj.io/ns-0/app-2-canary",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("edges:\n got %v\nwant %v", got, want)
+	}
+}
+
+func TestServiceExposeEdgesParallelMatchesSequential(t *testing.T) {
+	l, rolloutIDs := syntheticCluster(1000, 25)
+	idx := indexSelectableWorkloads(l, rolloutIDs)
+
+	sequential := serviceExposeEdges(l.services, idx, 1)
+	parallel := serviceExposeEdges(l.services, idx, 8)
+	if len(sequential) != 1000 {
+		t.Fatalf("got %d edges, want 1000", len(sequential))
+	}
+	if !reflect.DeepEqual(sequential, parallel) {
+		t.Error("parallel matching returned different edges or order than sequential")
+	}
+}
+
+func TestForEachBounded(t *testing.T) {
+	for _, workers := range []int{0, 1, 3, 100} {
+		seen := make([]int32, 50)
+		var running, peak atomic.Int32
+		forEachBounded(len(seen), workers, func(i int) {
+			n := running.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			atomic.AddInt32(&seen[i], 1)
+			running.Add(-1)
+		})
+		for i, n := range seen {
+			if n != 1 {
+				t.Errorf("workers=%d: index %d called %d times", workers, i, n)
+			}
+		}
+		if limit := max(workers, 1); int(peak.Load()) > limit {
+			t.Errorf("workers=%d: %d calls ran at once", workers, peak.Load())
+		}
+	}
+}
+
+// perServiceRolloutEdges is the previous approach: Rollout pod labels converted from
+// unstructured again for every Service
+func perServiceRolloutEdges(l *resourceLists, rolloutIDs map[string]string) []Edge {
+	rolloutsByNS := make(map[string][]*unstructured.Unstructured)
+	for _, r := range l.rollouts {
+		rolloutsByNS[r.GetNamespace()] = append(rolloutsByNS[r.GetNamespace()], r)
+	}
+	var edges []Edge
+	for _, svc := range l.services {
+		svcID := fmt.Sprintf("service/%s/%s", svc.Namespace, svc.Name)
+		for _, rollout := range rolloutsByNS[svc.Namespace] {
+			podLabels, _, _ := unstructured.NestedStringMap(rollout.Object, "spec", "template", "metadata", "labels")
+			if matchesSelector(podLabels, svc.Spec.Selector) {
+				rolloutID := rolloutIDs[rollout.GetNamespace()+"/"+rollout.GetName()]
+				edges = append(edges, Edge{ID: svcID + "-to-" + rolloutID, Source: svcID, Target: rolloutID, Type: EdgeExposes})
+			}
+		}
+	}
+	return edges
+}
+
+func BenchmarkServiceExposeEdges(b *testing.B) {
+	for _, services := range []int{1000, 5000} {
+		l, rolloutIDs := syntheticCluster(services, 100)
+
+		b.Run(fmt.Sprintf("services=%d/per-service-rollouts", services), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				perServiceRolloutEdges(l, rolloutIDs)
+			}
+		})
+		b.Run(fmt.Sprintf("services=%d/indexed-sequential", services), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				serviceExposeEdges(l.services, indexSelectableWorkloads(l, rolloutIDs), 1)
+			}
+		})
+		b.Run(fmt.Sprintf("services=%d/indexed-parallel", services), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				serviceExposeEdges(l.services, indexSelectableWorkloads(l, rolloutIDs), 0)
+			}
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package topology
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+const istioNetworkingGroup = "networking.istio.io"
+
+// MeshRoute describes the Istio routing rule behind a traffic edge
+type MeshRoute struct {
+	VirtualService string `json:"virtualService"`   // namespace/name
+	Subset         string `json:"subset,omitempty"` // DestinationRule subset routed to
+	Weight         int64  `json:"weight,omitempty"` // Share of the route's traffic, 0 when unweighted
+}
+
+// meshSubset is a named DestinationRule subset of a Service's pods
+type meshSubset struct {
+	name   string
+	labels map[string]string
+}
+
+// meshRoute is one VirtualService route destination, between Services keyed namespace/name
+type meshRoute struct {
+	virtualService string
+	from           string // Service whose host the VirtualService routes
+	to             string // Destination Service
+	subset         string
+	weight         int64
+}
+
+// meshRouting is the Istio routing parsed from VirtualServices and DestinationRules
+type meshRouting struct {
+	routes  []meshRoute
+	subsets map[string][]meshSubset // Service key -> subsets routed to, in DestinationRule order
+}
+
+// listIstio lists an Istio networking kind, or returns nil when Istio isn't installed
+func listIstio(kind, namespace string) ([]*unstructured.Unstructured, error) {
+	dynamicCache := k8s.GetDynamicResourceCache()
+	gvr, ok := k8s.GetResourceDiscovery().GetGVRWithGroup(kind, istioNetworkingGroup)
+	if !ok || dynamicCache == nil {
+		return nil, nil
+	}
+	return dynamicCache.List(gvr, namespace)
+}
+
+// parseMeshRouting reads the route destinations of each VirtualService whose hosts name a
+// Service, and the DestinationRule subsets those routes use. Hosts that aren't Services
+// (external or gateway hostnames, wildcards) are ignored.
+func parseMeshRouting(virtualServices, destinationRules []*unstructured.Unstructured) *meshRouting {
+	m := &meshRouting{subsets: make(map[string][]meshSubset)}
+
+	drSubsets := make(map[string][]meshSubset)
+	for _, dr := range destinationRules {
+		host, _, _ := unstructured.NestedString(dr.Object, "spec", "host")
+		svcKey := meshServiceKey(host, dr.GetNamespace())
+		if svcKey == "" {
+			continue
+		}
+		subsets, _, _ := unstructured.NestedSlice(dr.Object, "spec", "subsets")
+		for _, s := range subsets {
+			sm, ok := s.(map[string]any)
+			if !ok {
+				continue
+			}
+			name, _, _ := unstructured.NestedString(sm, "name")
+			labels, _, _ := unstructured.NestedStringMap(sm, "labels")
+			if name != "" && len(labels) > 0 {
+				drSubsets[svcKey] = append(drSubsets[svcKey], meshSubset{name: name, labels: labels})
+			}
+		}
+	}
+
+	routedSubsets := make(map[string]map[string]bool)
+	for _, vs := range virtualServices {
+		ns := vs.GetNamespace()
+		vsKey := ns + "/" + vs.GetName()
+		hosts, _, _ := unstructured.NestedStringSlice(vs.Object, "spec", "hosts")
+		for _, host := range hosts {
+			from := meshServiceKey(host, ns)
+			if from == "" {
+				continue
+			}
+			for _, protocol := range []string{"http", "tcp", "tls"} {
+				rules, _, _ := unstructured.NestedSlice(vs.Object, "spec", protocol)
+				for _, rule := range rules {
+					rm, ok := rule.(map[string]any)
+					if !ok {
+						continue
+					}
+					destinations, _, _ := unstructured.NestedSlice(rm, "route")
+					for _, d := range destinations {
+						dm, ok := d.(map[string]any)
+						if !ok {
+							continue
+						}
+						destHost, _, _ := unstructured.NestedString(dm, "destination", "host")
+						to := meshServiceKey(destHost, ns)
+						if to == "" {
+							continue
+						}
+						subset, _, _ := unstructured.NestedString(dm, "destination", "subset")
+						weight, _, _ := unstructured.NestedInt64(dm, "weight")
+						m.routes = append(m.routes, meshRoute{virtualService: vsKey, from: from, to: to, subset: subset, weight: weight})
+						if subset != "" {
+							if routedSubsets[to] == nil {
+								routedSubsets[to] = make(map[string]bool)
+							}
+							routedSubsets[to][subset] = true
+						}
+					}
+				}
+			}
+		}
+	}
+
+	// Only subsets a route sends traffic to split pod groups
+	for svcKey, subsets := range drSubsets {
+		for _, s := range subsets {
+			if routedSubsets[svcKey][s.name] {
+				m.subsets[svcKey] = append(m.subsets[svcKey], s)
+			}
+		}
+	}
+	return m
+}
+
+// meshServiceKey resolves an Istio host to a Service key: a short name is in namespace,
+// "name.ns", "name.ns.svc" and "name.ns.svc.<cluster domain>" name a Service in ns
+func meshServiceKey(host, namespace string) string {
+	if host == "" || strings.Contains(host, "*") {
+		return ""
+	}
+	parts := strings.Split(host, ".")
+	switch {
+	case len(parts) == 1:
+		return namespace + "/" + parts[0]
+	case len(parts) == 2, parts[2] == "svc":
+		return parts[1] + "/" + parts[0]
+	}
+	return ""
+}
+
+// subsetOf returns the routed subset a pod belongs to among the Services selecting it,
+// or "" when none does
+func (m *meshRouting) subsetOf(pod *corev1.Pod, servicesByNS map[string]map[string]*corev1.Service) string {
+	if m == nil || len(m.subsets) == 0 {
+		return ""
+	}
+	svcKeys := make([]string, 0)
+	for svcKey, svc := range servicesByNS[pod.Namespace] {
+		if len(m.subsets[svcKey]) > 0 && matchesSelector(pod.Labels, svc.Spec.Selector) {
+			svcKeys = append(svcKeys, svcKey)
+		}
+	}
+	sort.Strings(svcKeys)
+	for _, svcKey := range svcKeys {
+		for _, s := range m.subsets[svcKey] {
+			if matchesSelector(pod.Labels, s.labels) {
+				return s.name
+			}
+		}
+	}
+	return ""
+}
+
+// meshEdges draws routes-to edges for each route destination: to the destination's pods
+// of the routed subset, to all its pods when a Service routes to itself, or else to the
+// destination Service. It returns the IDs of Services whose selector edges these replace.
+func meshEdges(m *meshRouting, serviceIDs map[string]string, groups map[string]*PodGroup) ([]Edge, map[string]bool) {
+	if m == nil || len(m.routes) == 0 {
+		return nil, nil
+	}
+
+	// Targets per destination Service ID and subset, in a stable order
+	groupKeys := make([]string, 0, len(groups))
+	for key := range groups {
+		groupKeys = append(groupKeys, key)
+	}
+	sort.Strings(groupKeys)
+
+	type edgeKey struct{ source, target string }
+	var order []edgeKey
+	routes := make(map[edgeKey][]MeshRoute)
+	add := func(source, target string, r meshRoute) {
+		k := edgeKey{source, target}
+		if _, ok := routes[k]; !ok {
+			order = append(order, k)
+		}
+		routes[k] = append(routes[k], MeshRoute{VirtualService: r.virtualService, Subset: r.subset, Weight: r.weight})
+	}
+
+	for _, r := range m.routes {
+		sourceID, ok := serviceIDs[r.from]
+		if !ok {
+			continue
+		}
+		destID, ok := serviceIDs[r.to]
+		if !ok {
+			continue
+		}
+		if r.subset == "" && r.from != r.to {
+			add(sourceID, destID, r)
+			continue
+		}
+		for _, key := range groupKeys {
+			g := groups[key]
+			if g.ServiceIDs[destID] && (r.subset == "" || g.Subset == r.subset) {
+				add(sourceID, podGroupNodeID(g), r)
+			}
+		}
+	}
+
+	edges := make([]Edge, 0, len(order))
+	routed := make(map[string]bool)
+	for _, k := range order {
+		rs := routes[k]
+		labels := make([]string, 0, len(rs))
+		for _, r := range rs {
+			if l := r.label(); l != "" {
+				labels = append(labels, l)
+			}
+		}
+		edges = append(edges, Edge{
+			ID:     fmt.Sprintf("%s-to-%s", k.source, k.target),
+			Source: k.source,
+			Target: k.target,
+			Type:   EdgeRoutesTo,
+			Label:  joinUnique(labels),
+			Mesh:   rs,
+		})
+		routed[k.source] = true
+	}
+	return edges, routed
+}
+
+// label renders the route for an edge, e.g. "v2 20%"
+func (r MeshRoute) label() string {
+	var parts []string
+	if r.Subset != "" {
+		parts = append(parts, r.Subset)
+	}
+	if r.Weight > 0 && r.Weight < 100 {
+		parts = append(parts, fmt.Sprintf("%d%%", r.Weight))
+	}
+	return strings.Join(parts, " ")
+}
+
+// podGroupNodeID is the traffic view node for a group: the pod itself when it's alone
+func podGroupNodeID(g *PodGroup) string {
+	if len(g.Pods) == 1 {
+		return GetPodID(g.Pods[0])
+	}
+	return GetPodGroupID(g)
+}
@@ -0,0 +1,105 @@
+package topology
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func istioObject(kind, ns, name string, spec map[string]any) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]any{"spec": spec}}
+	u.SetKind(kind)
+	u.SetNamespace(ns)
+	u.SetName(name)
+	return u
+}
+
+func TestMeshServiceKey(t *testing.T) {
+	for host, want := range map[string]string{
+		"reviews":                         "shop/reviews",
+		"reviews.books":                   "books/reviews",
+		"reviews.books.svc":               "books/reviews",
+		"reviews.books.svc.cluster.local": "books/reviews",
+		"reviews.books.svc.corp.example":  "books/reviews",
+		"api.example.com":                 "",
+		"*.shop.svc.cluster.local":        "",
+		"":                                "",
+	} {
+		if got := meshServiceKey(host, "shop"); got != want {
+			t.Errorf("meshServiceKey(%q) = %q, want %q", host, got, want)
+		}
+	}
+}
+
+func TestMeshEdges_CanarySplit(t *testing.T) {
+	vs := istioObject("VirtualService", "shop", "reviews", map[string]any{
+		"hosts": []any{"reviews", "reviews.example.com"},
+		"http": []any{map[string]any{"route": []any{
+			map[string]any{"destination": map[string]any{"host": "reviews", "subset": "v1"}, "weight": int64(90)},
+			map[string]any{"destination": map[string]any{"host": "reviews.shop.svc.cluster.local", "subset": "v2"}, "weight": int64(10)},
+		}}},
+	})
+	shadow := istioObject("VirtualService", "shop", "ratings", map[string]any{
+		"hosts": []any{"ratings"},
+		"tcp":   []any{map[string]any{"route": []any{map[string]any{"destination": map[string]any{"host": "ratings-v2"}}}}},
+	})
+	dr := istioObject("DestinationRule", "shop", "reviews", map[string]any{
+		"host": "reviews",
+		"subsets": []any{
+			map[string]any{"name": "v1", "labels": map[string]any{"version": "v1"}},
+			map[string]any{"name": "v2", "labels": map[string]any{"version": "v2"}},
+			map[string]any{"name": "v3", "labels": map[string]any{"version": "v3"}},
+		},
+	})
+	mesh := parseMeshRouting([]*unstructured.Unstructured{vs, shadow}, []*unstructured.Unstructured{dr})
+	if got := len(mesh.subsets["shop/reviews"]); got != 2 {
+		t.Fatalf("routed subsets = %d, want v1 and v2 only", got)
+	}
+
+	reviews := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "reviews", Namespace: "shop"},
+		Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": "reviews"}},
+	}
+	servicesByNS := map[string]map[string]*corev1.Service{"shop": {"shop/reviews": reviews}}
+	serviceIDs := map[string]string{
+		"shop/reviews":    "service/shop/reviews",
+		"shop/ratings":    "service/shop/ratings",
+		"shop/ratings-v2": "service/shop/ratings-v2",
+	}
+	var pods []*corev1.Pod
+	for _, p := range []struct{ name, version string }{{"reviews-v1-a", "v1"}, {"reviews-v1-b", "v1"}, {"reviews-v2-a", "v2"}, {"reviews-v3-a", "v3"}} {
+		pods = append(pods, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name: p.name, Namespace: "shop", Labels: map[string]string{"app": "reviews", "version": p.version},
+		}})
+	}
+
+	groups := GroupPods(pods, PodGroupingOptions{
+		ServiceMatching: true,
+		ServicesByNS:    servicesByNS,
+		ServiceIDs:      serviceIDs,
+		Subset:          func(pod *corev1.Pod) string { return mesh.subsetOf(pod, servicesByNS) },
+	}).Groups
+	if len(groups) != 3 {
+		t.Fatalf("got %d pod groups, want v1, v2 and unrouted v3 split apart", len(groups))
+	}
+
+	edges, routed := meshEdges(mesh, serviceIDs, groups)
+	got := make(map[string]string)
+	for _, e := range edges {
+		got[e.Source+" -> "+e.Target] = e.Label
+	}
+	want := map[string]string{
+		"service/shop/reviews -> podgroup-shop-app-reviews-v1": "v1 90%",
+		"service/shop/reviews -> pod/shop/reviews-v2-a":        "v2 10%",
+		"service/shop/ratings -> service/shop/ratings-v2":      "",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("edges:\n got %v\nwant %v", got, want)
+	}
+	if !routed["service/shop/reviews"] || !routed["service/shop/ratings"] {
+		t.Errorf("routed = %v, want reviews and ratings", routed)
+	}
+}
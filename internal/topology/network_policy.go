@@ -0,0 +1,391 @@
+package topology
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// maxBlockEdges caps "blocks" edges so a default-deny namespace doesn't flood the graph
+const maxBlockEdges = 200
+
+// policyWorkload is a workload whose pods NetworkPolicies may select
+type policyWorkload struct {
+	id        string
+	namespace string
+	labels    map[string]string // Pod template labels
+	exposed   bool              // Selected by a Service, so something is expected to connect to it
+}
+
+// buildNetworkPolicies adds NetworkPolicy nodes and the edges they imply to the
+// resources topology. Workloads already in the graph are matched by pod template labels.
+func (b *Builder) buildNetworkPolicies(opts BuildOptions, nodes []Node, edges []Edge, lists *resourceLists) ([]Node, []Edge, []string) {
+	var warnings []string
+
+	// NetworkPolicies have no typed informer - read them via the dynamic cache
+	dynamicCache := k8s.GetDynamicResourceCache()
+	gvr, ok := k8s.GetResourceDiscovery().GetGVRWithGroup("NetworkPolicy", "networking.k8s.io")
+	if !ok || dynamicCache == nil {
+		return nodes, edges, warnings
+	}
+	items, err := dynamicCache.List(gvr, opts.Namespace)
+	if err != nil {
+		log.Printf("WARNING [topology] Failed to list NetworkPolicies: %v", err)
+		return nodes, edges, append(warnings, fmt.Sprintf("Failed to list NetworkPolicies: %v", err))
+	}
+	if len(items) == 0 {
+		return nodes, edges, warnings
+	}
+
+	policies := make([]networkingv1.NetworkPolicy, 0, len(items))
+	for _, item := range items {
+		var np networkingv1.NetworkPolicy
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &np); err != nil {
+			log.Printf("WARNING [topology] Failed to convert NetworkPolicy %s/%s: %v", item.GetNamespace(), item.GetName(), err)
+			continue
+		}
+		policies = append(policies, np)
+	}
+
+	// Workloads in the graph, and which of them a Service exposes
+	exposed := make(map[string]bool)
+	for _, e := range edges {
+		if e.Type == EdgeExposes {
+			exposed[e.Target] = true
+		}
+	}
+	var workloads []policyWorkload
+	add := func(kind, group, namespace, name string, podLabels map[string]string) {
+		if opts.Namespace != "" && namespace != opts.Namespace {
+			return
+		}
+		id := nodeID(kind, group, namespace, name)
+		workloads = append(workloads, policyWorkload{id: id, namespace: namespace, labels: podLabels, exposed: exposed[id]})
+	}
+	for _, d := range lists.deployments {
+		add("deployment", "", d.Namespace, d.Name, d.Spec.Template.Labels)
+	}
+	for _, sts := range lists.statefulsets {
+		add("statefulset", "", sts.Namespace, sts.Name, sts.Spec.Template.Labels)
+	}
+	for _, ds := range lists.daemonsets {
+		add("daemonset", "", ds.Namespace, ds.Name, ds.Spec.Template.Labels)
+	}
+	for _, r := range lists.rollouts {
+		podLabels, found, _ := unstructured.NestedStringMap(r.Object, "spec", "template", "metadata", "labels")
+		if found {
+			add("rollout", rolloutGroup, r.GetNamespace(), r.GetName(), podLabels)
+		}
+	}
+
+	// Namespace labels for namespaceSelector peers
+	nsLabels := make(map[string]map[string]string)
+	if namespaces, err := b.cache.Namespaces().List(labels.Everything()); err == nil {
+		for _, ns := range namespaces {
+			nsLabels[ns.Name] = ns.Labels
+		}
+	}
+
+	selectEdges, flowEdges, truncated := networkPolicyEdges(policies, workloads, nsLabels)
+	selected := make(map[string]int)
+	for _, e := range selectEdges {
+		selected[e.Source]++
+	}
+
+	for i := range policies {
+		np := &policies[i]
+		id := networkPolicyID(np)
+		status := StatusHealthy
+		if selected[id] == 0 {
+			status = StatusUnknown // Selects no workload in the graph
+		}
+		nodes = append(nodes, Node{
+			ID:     id,
+			Kind:   KindNetworkPolicy,
+			Name:   np.Name,
+			Status: status,
+			Data: map[string]any{
+				"namespace":         np.Namespace,
+				"policyTypes":       policyTypeNames(np),
+				"podSelector":       metav1.FormatLabelSelector(&np.Spec.PodSelector),
+				"ingressRules":      len(np.Spec.Ingress),
+				"egressRules":       len(np.Spec.Egress),
+				"selectedWorkloads": selected[id],
+				"labels":            np.Labels,
+			},
+		})
+	}
+	edges = append(edges, selectEdges...)
+	edges = append(edges, flowEdges...)
+
+	if truncated {
+		log.Printf("WARNING [topology] NetworkPolicy blocked edges truncated at %d", maxBlockEdges)
+		warnings = append(warnings, fmt.Sprintf("Only the first %d blocked connections are shown", maxBlockEdges))
+	}
+	return nodes, edges, warnings
+}
+
+// policyEvaluator answers whether NetworkPolicies allow traffic between workloads
+type policyEvaluator struct {
+	policies []networkingv1.NetworkPolicy
+	nsLabels map[string]map[string]string
+}
+
+// networkPolicyEdges returns edges from each policy to the workloads it selects, and
+// "allows"/"blocks" edges between workloads where at least one side is isolated by a policy.
+// Traffic between two unisolated workloads is allowed by default and isn't drawn.
+func networkPolicyEdges(policies []networkingv1.NetworkPolicy, workloads []policyWorkload, nsLabels map[string]map[string]string) (selectEdges, flowEdges []Edge, truncated bool) {
+	ev := &policyEvaluator{policies: policies, nsLabels: nsLabels}
+
+	for i := range policies {
+		p := &policies[i]
+		policyID := networkPolicyID(p)
+		types := strings.Join(policyTypeNames(p), "+")
+		for _, w := range workloads {
+			if selectsWorkload(p, w) {
+				selectEdges = append(selectEdges, Edge{
+					ID:     fmt.Sprintf("%s-to-%s", policyID, w.id),
+					Source: policyID,
+					Target: w.id,
+					Type:   EdgeConfigures,
+					Label:  types,
+				})
+			}
+		}
+	}
+
+	blocks := 0
+	for _, src := range workloads {
+		for _, dst := range workloads {
+			if src.id == dst.id {
+				continue
+			}
+			ingressIsolated, egressIsolated := ev.isolated(dst, networkingv1.PolicyTypeIngress), ev.isolated(src, networkingv1.PolicyTypeEgress)
+			if !ingressIsolated && !egressIsolated {
+				continue
+			}
+
+			inAllowed, inReferenced, ports := ev.ingressAllows(dst, src)
+			outAllowed, outReferenced := ev.egressAllows(src, dst)
+			referenced := (ingressIsolated && inReferenced) || (egressIsolated && outReferenced)
+
+			if inAllowed && outAllowed {
+				// Only draw allowances a rule grants explicitly; "allow all" rules would connect everything
+				if referenced {
+					flowEdges = append(flowEdges, Edge{
+						ID:     fmt.Sprintf("%s-allows-%s", src.id, dst.id),
+						Source: src.id,
+						Target: dst.id,
+						Type:   EdgeAllows,
+						Label:  ports,
+					})
+				}
+				continue
+			}
+
+			if !referenced && !(src.namespace == dst.namespace && dst.exposed) {
+				continue
+			}
+			if blocks >= maxBlockEdges {
+				truncated = true
+				continue
+			}
+			blocks++
+			flowEdges = append(flowEdges, Edge{
+				ID:     fmt.Sprintf("%s-blocks-%s", src.id, dst.id),
+				Source: src.id,
+				Target: dst.id,
+				Type:   EdgeBlocks,
+				Label:  blockedDirection(inAllowed, outAllowed),
+			})
+		}
+	}
+	return selectEdges, flowEdges, truncated
+}
+
+// isolated reports whether any policy of the given type selects the workload
+func (ev *policyEvaluator) isolated(w policyWorkload, policyType networkingv1.PolicyType) bool {
+	for i := range ev.policies {
+		p := &ev.policies[i]
+		if hasPolicyType(p, policyType) && selectsWorkload(p, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// ingressAllows reports whether dst accepts connections from src. referenced is true when
+// a rule names src through a selector (as opposed to allowing every source), and ports
+// summarizes the allowed ports ("" = all).
+func (ev *policyEvaluator) ingressAllows(dst, src policyWorkload) (allowed, referenced bool, ports string) {
+	isolated := false
+	var portList []string
+	allPorts := false
+	for i := range ev.policies {
+		p := &ev.policies[i]
+		if !hasPolicyType(p, networkingv1.PolicyTypeIngress) || !selectsWorkload(p, dst) {
+			continue
+		}
+		isolated = true
+		for _, rule := range p.Spec.Ingress {
+			match, explicit := ev.peersMatch(rule.From, p.Namespace, src)
+			if !match {
+				continue
+			}
+			allowed = true
+			referenced = referenced || explicit
+			if len(rule.Ports) == 0 {
+				allPorts = true
+			}
+			portList = append(portList, formatPorts(rule.Ports)...)
+		}
+	}
+	if !isolated {
+		return true, false, ""
+	}
+	if allPorts {
+		return allowed, referenced, ""
+	}
+	return allowed, referenced, joinUnique(portList)
+}
+
+// egressAllows reports whether src may open connections to dst
+func (ev *policyEvaluator) egressAllows(src, dst policyWorkload) (allowed, referenced bool) {
+	isolated := false
+	for i := range ev.policies {
+		p := &ev.policies[i]
+		if !hasPolicyType(p, networkingv1.PolicyTypeEgress) || !selectsWorkload(p, src) {
+			continue
+		}
+		isolated = true
+		for _, rule := range p.Spec.Egress {
+			if match, explicit := ev.peersMatch(rule.To, p.Namespace, dst); match {
+				allowed = true
+				referenced = referenced || explicit
+			}
+		}
+	}
+	if !isolated {
+		return true, false
+	}
+	return allowed, referenced
+}
+
+// peersMatch reports whether a rule's peers include the workload. An empty peer list
+// matches everything (explicit=false). ipBlock peers are ignored: pod IPs aren't stable.
+func (ev *policyEvaluator) peersMatch(peers []networkingv1.NetworkPolicyPeer, policyNamespace string, w policyWorkload) (match, explicit bool) {
+	if len(peers) == 0 {
+		return true, false
+	}
+	for _, peer := range peers {
+		if peer.IPBlock != nil {
+			continue
+		}
+		if peer.NamespaceSelector == nil {
+			if w.namespace != policyNamespace {
+				continue
+			}
+		} else if !selectorMatches(peer.NamespaceSelector, ev.nsLabels[w.namespace]) {
+			continue
+		}
+		if peer.PodSelector != nil && !selectorMatches(peer.PodSelector, w.labels) {
+			continue
+		}
+		return true, true
+	}
+	return false, false
+}
+
+func selectsWorkload(p *networkingv1.NetworkPolicy, w policyWorkload) bool {
+	return p.Namespace == w.namespace && selectorMatches(&p.Spec.PodSelector, w.labels)
+}
+
+// selectorMatches treats an empty selector as matching everything, as NetworkPolicies do
+func selectorMatches(sel *metav1.LabelSelector, set map[string]string) bool {
+	selector, err := metav1.LabelSelectorAsSelector(sel)
+	if err != nil {
+		return false
+	}
+	return selector.Matches(labels.Set(set))
+}
+
+// policyTypeNames returns the policy's effective types. When policyTypes is unset,
+// Ingress always applies and Egress applies if egress rules are present.
+func policyTypeNames(p *networkingv1.NetworkPolicy) []string {
+	if len(p.Spec.PolicyTypes) > 0 {
+		names := make([]string, len(p.Spec.PolicyTypes))
+		for i, t := range p.Spec.PolicyTypes {
+			names[i] = string(t)
+		}
+		return names
+	}
+	if len(p.Spec.Egress) > 0 {
+		return []string{string(networkingv1.PolicyTypeIngress), string(networkingv1.PolicyTypeEgress)}
+	}
+	return []string{string(networkingv1.PolicyTypeIngress)}
+}
+
+func hasPolicyType(p *networkingv1.NetworkPolicy, t networkingv1.PolicyType) bool {
+	for _, name := range policyTypeNames(p) {
+		if name == string(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func formatPorts(ports []networkingv1.NetworkPolicyPort) []string {
+	out := make([]string, 0, len(ports))
+	for _, p := range ports {
+		proto := "TCP"
+		if p.Protocol != nil {
+			proto = string(*p.Protocol)
+		}
+		switch {
+		case p.Port == nil:
+			out = append(out, proto)
+		case p.EndPort != nil:
+			out = append(out, fmt.Sprintf("%s/%s-%d", proto, p.Port.String(), *p.EndPort))
+		default:
+			out = append(out, proto+"/"+p.Port.String())
+		}
+	}
+	return out
+}
+
+func joinUnique(values []string) string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	sort.Strings(out)
+	return strings.Join(out, ", ")
+}
+
+func blockedDirection(ingressAllowed, egressAllowed bool) string {
+	switch {
+	case !ingressAllowed && !egressAllowed:
+		return "ingress+egress"
+	case !ingressAllowed:
+		return "ingress"
+	default:
+		return "egress"
+	}
+}
+
+func networkPolicyID(p *networkingv1.NetworkPolicy) string {
+	return fmt.Sprintf("networkpolicy/%s/%s", p.Namespace, p.Name)
+}
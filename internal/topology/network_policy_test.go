@@ -0,0 +1,79 @@
+package topology
+
+import (
+	"testing"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNetworkPolicyEdges(t *testing.T) {
+	workloads := []policyWorkload{
+		{id: "deployment/shop/web", namespace: "shop", labels: map[string]string{"app": "web"}},
+		{id: "deployment/shop/api", namespace: "shop", labels: map[string]string{"app": "api"}, exposed: true},
+		{id: "deployment/shop/worker", namespace: "shop", labels: map[string]string{"app": "worker"}},
+		{id: "deployment/ops/prom", namespace: "ops", labels: map[string]string{"app": "prom"}},
+		{id: "deployment/ops/other", namespace: "ops", labels: map[string]string{"app": "other"}},
+	}
+	nsLabels := map[string]map[string]string{"shop": {"team": "shop"}, "ops": {"team": "ops"}}
+
+	// api accepts web (same namespace) and prom (namespace + pod selector); worker and other are blocked
+	policies := []networkingv1.NetworkPolicy{{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-ingress", Namespace: "shop"},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{{
+				From: []networkingv1.NetworkPolicyPeer{
+					{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}},
+					{
+						NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "ops"}},
+						PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"app": "prom"}},
+					},
+				},
+			}},
+		},
+	}}
+
+	selectEdges, flowEdges, truncated := networkPolicyEdges(policies, workloads, nsLabels)
+	if truncated {
+		t.Error("unexpected truncation")
+	}
+	if len(selectEdges) != 1 || selectEdges[0].Target != "deployment/shop/api" || selectEdges[0].Label != "Ingress" {
+		t.Errorf("select edges = %+v", selectEdges)
+	}
+
+	got := make(map[string]EdgeType)
+	for _, e := range flowEdges {
+		got[e.Source+" -> "+e.Target] = e.Type
+	}
+	want := map[string]EdgeType{
+		"deployment/shop/web -> deployment/shop/api":    EdgeAllows,
+		"deployment/ops/prom -> deployment/shop/api":    EdgeAllows,
+		"deployment/shop/worker -> deployment/shop/api": EdgeBlocks, // Same namespace, api is exposed
+	}
+	if len(got) != len(want) {
+		t.Errorf("flow edges = %v, want %v", got, want)
+	}
+	for pair, typ := range want {
+		if got[pair] != typ {
+			t.Errorf("%s: got %q, want %q", pair, got[pair], typ)
+		}
+	}
+
+	// An egress default-deny on web blocks the connection its ingress rule allows
+	policies = append(policies, networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-egress", Namespace: "shop"},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+		},
+	})
+	_, flowEdges, _ = networkPolicyEdges(policies, workloads, nsLabels)
+	for _, e := range flowEdges {
+		if e.Source == "deployment/shop/web" && e.Target == "deployment/shop/api" {
+			if e.Type != EdgeBlocks || e.Label != "egress" {
+				t.Errorf("web -> api = %s (%s), want blocks (egress)", e.Type, e.Label)
+			}
+		}
+	}
+}
@@ -17,6 +17,7 @@ type PodGroup struct {
 	Namespace  string          // Namespace of the pods
 	Pods       []*corev1.Pod   // Pods in this group
 	ServiceIDs map[string]bool // Service IDs that route to this group (for traffic view)
+	Subset     string          // Istio subset the pods belong to (traffic view with mesh routing)
 	Healthy    int             // Count of healthy pods
 	Degraded   int             // Count of degraded pods
 	Unhealthy  int             // Count of unhealthy pods
@@ -33,6 +34,7 @@ type PodGroupingOptions struct {
 	ServiceMatching bool                                  // Whether to match pods to services (for traffic view)
 	ServicesByNS    map[string]map[string]*corev1.Service // Namespace -> svcKey -> service
 	ServiceIDs      map[string]string                     // svcKey -> serviceID
+	Subset          func(pod *corev1.Pod) string          // Splits groups by mesh subset (optional)
 }
 
 // GroupPods groups pods by app label or owner reference
@@ -64,6 +66,12 @@ func GroupPods(pods []*corev1.Pod, opts PodGroupingOptions) *PodGroupingResult {
 
 		// Determine group key
 		groupKey, groupKind, groupName := determineGroupKey(pod)
+		var subset string
+		if opts.Subset != nil {
+			if subset = opts.Subset(pod); subset != "" {
+				groupKey += "/" + subset
+			}
+		}
 
 		if _, exists := result.Groups[groupKey]; !exists {
 			result.Groups[groupKey] = &PodGroup{
@@ -73,6 +81,7 @@ func GroupPods(pods []*corev1.Pod, opts PodGroupingOptions) *PodGroupingResult {
 				Namespace:  pod.Namespace,
 				Pods:       make([]*corev1.Pod, 0),
 				ServiceIDs: make(map[string]bool),
+				Subset:     subset,
 			}
 		}
 
@@ -229,7 +238,7 @@ func CreatePodGroupNode(group *PodGroup, cache *k8s.ResourceCache) Node {
 		})
 	}
 
-	return Node{
+	node := Node{
 		ID:     podGroupID,
 		Kind:   KindPodGroup,
 		Name:   groupName,
@@ -246,6 +255,10 @@ func CreatePodGroupNode(group *PodGroup, cache *k8s.ResourceCache) Node {
 			"statusIssue":   groupStatusIssue,
 		},
 	}
+	if group.Subset != "" {
+		node.Data["subset"] = group.Subset
+	}
+	return node
 }
 
 // GetPodGroupID returns the node ID for a pod group
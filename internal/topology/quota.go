@@ -0,0 +1,51 @@
+package topology
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// quotaIndex holds each namespace's ResourceQuotas and LimitRanges, for checking whether a
+// workload's missing pods would be admitted
+type quotaIndex struct {
+	quotas      map[string][]*corev1.ResourceQuota
+	limitRanges map[string][]*corev1.LimitRange
+}
+
+func newQuotaIndex(quotas []*corev1.ResourceQuota, limitRanges []*corev1.LimitRange) *quotaIndex {
+	idx := &quotaIndex{
+		quotas:      make(map[string][]*corev1.ResourceQuota),
+		limitRanges: make(map[string][]*corev1.LimitRange),
+	}
+	for _, q := range quotas {
+		idx.quotas[q.Namespace] = append(idx.quotas[q.Namespace], q)
+	}
+	for _, lr := range limitRanges {
+		idx.limitRanges[lr.Namespace] = append(idx.limitRanges[lr.Namespace], lr)
+	}
+	return idx
+}
+
+// shortfall explains why the pending pods of a workload (desired but not yet created)
+// would be rejected by the namespace's quotas. Quota admission rejects pod creation
+// outright, so these pods never show up as Pending.
+func (idx *quotaIndex) shortfall(namespace string, template corev1.PodSpec, pending int32) []string {
+	quotas := idx.quotas[namespace]
+	if pending <= 0 || len(quotas) == 0 {
+		return nil
+	}
+	return k8s.QuotaShortfall(quotas, k8s.PodQuotaUsage(template, idx.limitRanges[namespace]), int64(pending))
+}
+
+// setQuotaIssues records quota shortfalls on a workload node, making them its status issue
+// unless it already has one
+func setQuotaIssues(data map[string]any, issues []string) {
+	if len(issues) == 0 {
+		return
+	}
+	data["quotaIssues"] = issues
+	if issue, _ := data["statusIssue"].(string); issue == "" {
+		data["statusIssue"] = "QuotaExceeded"
+	}
+}
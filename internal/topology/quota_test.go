@@ -0,0 +1,67 @@
+package topology
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestQuotaShortfall(t *testing.T) {
+	quota := &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "compute", Namespace: "shop"},
+		Status: corev1.ResourceQuotaStatus{
+			Hard: corev1.ResourceList{"pods": resource.MustParse("10"), "requests.cpu": resource.MustParse("2"), "limits.memory": resource.MustParse("4Gi")},
+			Used: corev1.ResourceList{"pods": resource.MustParse("3"), "requests.cpu": resource.MustParse("1500m"), "limits.memory": resource.MustParse("1Gi")},
+		},
+	}
+	// Scoped quotas only apply to some pods and are skipped
+	scoped := &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "best-effort", Namespace: "shop"},
+		Spec:       corev1.ResourceQuotaSpec{Scopes: []corev1.ResourceQuotaScope{corev1.ResourceQuotaScopeBestEffort}},
+		Status:     corev1.ResourceQuotaStatus{Hard: corev1.ResourceList{"pods": resource.MustParse("0")}},
+	}
+	defaults := &corev1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{Name: "defaults", Namespace: "shop"},
+		Spec: corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{{
+			Type:    corev1.LimitTypeContainer,
+			Default: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+		}}},
+	}
+	idx := newQuotaIndex([]*corev1.ResourceQuota{quota, scoped}, nil)
+	withDefaults := newQuotaIndex([]*corev1.ResourceQuota{quota, scoped}, []*corev1.LimitRange{defaults})
+
+	spec := func(cpu string) corev1.PodSpec {
+		return corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+		}}}}
+	}
+
+	tests := []struct {
+		name    string
+		idx     *quotaIndex
+		ns      string
+		spec    corev1.PodSpec
+		pending int32
+		want    []string
+	}{
+		{"no pending pods", idx, "shop", spec("1"), 0, nil},
+		{"no quota in namespace", idx, "other", spec("1"), 3, nil},
+		{"unset limit", idx, "shop", spec("100m"), 2, []string{"quota compute limits limits.memory, which the pods don't set"}},
+		{"fits with default limit", withDefaults, "shop", spec("250m"), 2, nil},
+		{"over cpu", withDefaults, "shop", spec("300m"), 2, []string{"quota compute: 2 more pod(s) need 600m requests.cpu, 500m of 2 left"}},
+		{"init container dominates", withDefaults, "shop", corev1.PodSpec{
+			Containers: spec("100m").Containers,
+			InitContainers: []corev1.Container{{Name: "init", Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+			}}},
+		}, 1, []string{"quota compute: 1 more pod(s) need 1 requests.cpu, 500m of 2 left"}},
+	}
+	for _, tt := range tests {
+		if got := tt.idx.shortfall(tt.ns, tt.spec, tt.pending); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
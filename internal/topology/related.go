@@ -0,0 +1,456 @@
+package topology
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+const (
+	// maxOwnerDepth bounds the walk up owner references (owner cycles aren't valid, but
+	// nothing stops them being written)
+	maxOwnerDepth = 10
+	// maxRelatedEvents caps the events returned, most recent first
+	maxRelatedEvents = 50
+)
+
+// RelatedResources is everything connected to one resource: its owner chain, what it
+// owns (Deployment → ReplicaSets → Pods), what its pods reference, and what selects or
+// targets them
+type RelatedResources struct {
+	Resource   ResourceRef    `json:"resource"`
+	Owners     []ResourceRef  `json:"owners,omitempty"`     // Controller owners, nearest first
+	Children   []OwnedRef     `json:"children,omitempty"`   // Owned resources, transitively, breadth first
+	References []Reference    `json:"references,omitempty"` // ConfigMaps, Secrets, PVCs and ServiceAccounts its pods use
+	Services   []ResourceRef  `json:"services,omitempty"`   // Services selecting its pods
+	Ingresses  []ResourceRef  `json:"ingresses,omitempty"`  // Ingresses routing to those Services
+	HPAs       []ResourceRef  `json:"hpas,omitempty"`       // HPAs scaling it or one of its owners
+	PDBs       []ResourceRef  `json:"pdbs,omitempty"`       // PodDisruptionBudgets covering its pods
+	Events     []RelatedEvent `json:"events,omitempty"`     // K8s Events about it and its children, newest first
+}
+
+// OwnedRef is a resource owned, directly or through other children, by the resource
+type OwnedRef struct {
+	ResourceRef
+	Owner string `json:"owner"` // Kind/name of its direct owner
+}
+
+// Reference is a resource the pods' specs refer to
+type Reference struct {
+	ResourceRef
+	Missing bool `json:"missing,omitempty"` // Not in the cache: a typo, or not created yet
+}
+
+// RelatedEvent is a K8s Event about the resource or one of its children
+type RelatedEvent struct {
+	Object   ResourceRef `json:"object"`
+	Type     string      `json:"type"`
+	Reason   string      `json:"reason"`
+	Message  string      `json:"message"`
+	Count    int32       `json:"count,omitempty"`
+	LastSeen time.Time   `json:"lastSeen"`
+}
+
+// Related computes a resource's related-resource graph from the cache, using the same
+// ownership, reference and selector matching as the topology, scoped to one resource.
+// kind may be a kind or plural name, qualified with its group for custom resources.
+func (b *Builder) Related(ctx context.Context, kind, namespace, name string) (*RelatedResources, error) {
+	if b.cache == nil {
+		return nil, fmt.Errorf("resource cache not initialized")
+	}
+	obj, err := b.cache.CachedObject(ctx, kind, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	res, _ := k8s.GetResourceDiscovery().GetResource(kind)
+	rel := &RelatedResources{Resource: relatedRef(res.Kind, res.Group, namespace, name)}
+
+	b.relatedOwners(ctx, rel, obj)
+	children, pods := b.relatedChildren(obj)
+	for _, c := range children {
+		rel.Children = append(rel.Children, c.OwnedRef)
+	}
+
+	// The pods' specs and labels, or the template's when there are no pods yet
+	var specs []corev1.PodSpec
+	var podLabels []map[string]string
+	for _, pod := range pods {
+		specs = append(specs, pod.Spec)
+		podLabels = append(podLabels, pod.Labels)
+	}
+	templateRefs, templateLabels, serviceAccount := podTemplateOf(obj)
+	if len(podLabels) == 0 && templateLabels != nil {
+		podLabels = append(podLabels, templateLabels)
+	}
+
+	rel.References = b.relatedReferences(namespace, specs, templateRefs, serviceAccount)
+	rel.Services, rel.Ingresses = b.relatedServices(namespace, podLabels)
+	rel.HPAs = b.relatedHPAs(namespace, append([]ResourceRef{rel.Resource}, rel.Owners...))
+	rel.PDBs = b.relatedPDBs(ctx, namespace, podLabels)
+
+	uids := map[types.UID]ResourceRef{obj.GetUID(): rel.Resource}
+	for _, n := range pods {
+		uids[n.UID] = relatedRef("Pod", "", n.Namespace, n.Name)
+	}
+	for _, c := range children {
+		if c.uid != "" {
+			uids[c.uid] = c.ResourceRef
+		}
+	}
+	rel.Events = b.relatedEvents(namespace, uids)
+	return rel, nil
+}
+
+// relatedRef builds a ref, dropping the group of built-in kinds
+func relatedRef(kind, group, namespace, name string) ResourceRef {
+	if k8s.IsBuiltinGroup(group) {
+		group = ""
+	}
+	return ResourceRef{Kind: kind, Group: group, Namespace: namespace, Name: name}
+}
+
+// relatedOwners walks controller owner references up from obj. An owner missing from the
+// cache is still listed; the walk stops there.
+func (b *Builder) relatedOwners(ctx context.Context, rel *RelatedResources, obj metav1.Object) {
+	for range maxOwnerDepth {
+		var owner *metav1.OwnerReference
+		for i, ref := range obj.GetOwnerReferences() {
+			if ref.Controller != nil && *ref.Controller {
+				owner = &obj.GetOwnerReferences()[i]
+				break
+			}
+		}
+		if owner == nil {
+			return
+		}
+		gv, _ := schema.ParseGroupVersion(owner.APIVersion)
+		ref := relatedRef(owner.Kind, gv.Group, obj.GetNamespace(), owner.Name)
+		rel.Owners = append(rel.Owners, ref)
+
+		next, err := b.cache.CachedObject(ctx, k8s.QualifiedKind(owner.Kind, gv.Group), obj.GetNamespace(), owner.Name)
+		if err != nil || next.GetUID() != owner.UID {
+			return
+		}
+		obj = next
+	}
+}
+
+// ownedChild is a child with the UID its own children point at
+type ownedChild struct {
+	OwnedRef
+	uid types.UID
+}
+
+// relatedChildren walks owner references down from obj through the kinds workload
+// controllers create, returning every child and the pods among them
+func (b *Builder) relatedChildren(obj metav1.Object) ([]ownedChild, []*corev1.Pod) {
+	var children []ownedChild
+	var pods []*corev1.Pod
+	if pod, ok := obj.(*corev1.Pod); ok {
+		pods = append(pods, pod)
+	}
+	if obj.GetNamespace() == "" {
+		return children, pods // Workload controllers only create namespaced children
+	}
+	seen := map[types.UID]bool{obj.GetUID(): true}
+	queue := []types.UID{obj.GetUID()}
+	for len(queue) > 0 {
+		uid := queue[0]
+		queue = queue[1:]
+		var level []ownedChild
+		podsByUID := make(map[types.UID]*corev1.Pod)
+		for kind, objs := range b.cache.OwnedBy(uid) {
+			for _, child := range objs {
+				for _, ref := range child.GetOwnerReferences() {
+					if ref.UID != uid {
+						continue
+					}
+					level = append(level, ownedChild{
+						OwnedRef: OwnedRef{ResourceRef: relatedRef(kind, "", child.GetNamespace(), child.GetName()), Owner: ref.Kind + "/" + ref.Name},
+						uid:      child.GetUID(),
+					})
+				}
+				if pod, ok := child.(*corev1.Pod); ok {
+					podsByUID[pod.UID] = pod
+				}
+			}
+		}
+		sort.Slice(level, func(i, j int) bool {
+			if level[i].Name != level[j].Name {
+				return level[i].Name < level[j].Name
+			}
+			return level[i].Kind < level[j].Kind
+		})
+		for _, child := range level {
+			if seen[child.uid] {
+				continue
+			}
+			seen[child.uid] = true
+			children = append(children, child)
+			if pod := podsByUID[child.uid]; pod != nil {
+				pods = append(pods, pod)
+			}
+			queue = append(queue, child.uid)
+		}
+	}
+	return children, pods
+}
+
+// podTemplateOf returns the references, labels and service account of a workload's pod
+// template (or a pod's own spec), or nils for kinds without one
+func podTemplateOf(obj metav1.Object) (*workloadRefs, map[string]string, string) {
+	var tmpl *corev1.PodTemplateSpec
+	switch o := obj.(type) {
+	case *corev1.Pod:
+		refs := extractWorkloadReferences(o.Spec)
+		return &refs, o.Labels, o.Spec.ServiceAccountName
+	case *appsv1.Deployment:
+		tmpl = &o.Spec.Template
+	case *appsv1.StatefulSet:
+		tmpl = &o.Spec.Template
+	case *appsv1.DaemonSet:
+		tmpl = &o.Spec.Template
+	case *appsv1.ReplicaSet:
+		tmpl = &o.Spec.Template
+	case *batchv1.Job:
+		tmpl = &o.Spec.Template
+	case *batchv1.CronJob:
+		tmpl = &o.Spec.JobTemplate.Spec.Template
+	case *unstructured.Unstructured:
+		// Custom workloads with a pod template, like Argo Rollouts
+		spec, ok, _ := unstructured.NestedMap(o.Object, "spec", "template", "spec")
+		if !ok {
+			return nil, nil, ""
+		}
+		refs := extractWorkloadReferencesFromMap(spec)
+		podLabels, _, _ := unstructured.NestedStringMap(o.Object, "spec", "template", "metadata", "labels")
+		serviceAccount, _, _ := unstructured.NestedString(spec, "serviceAccountName")
+		return &refs, podLabels, serviceAccount
+	default:
+		return nil, nil, ""
+	}
+	refs := extractWorkloadReferences(tmpl.Spec)
+	return &refs, tmpl.Labels, tmpl.Spec.ServiceAccountName
+}
+
+// relatedReferences lists the ConfigMaps, Secrets, PVCs and ServiceAccounts the pods and
+// the template refer to, marking those missing from the cache. Secrets are only checked
+// when they're cached.
+func (b *Builder) relatedReferences(namespace string, specs []corev1.PodSpec, template *workloadRefs, serviceAccount string) []Reference {
+	configMaps, secrets, pvcs := map[string]bool{}, map[string]bool{}, map[string]bool{}
+	serviceAccounts := map[string]bool{}
+	merge := func(refs workloadRefs) {
+		for n := range refs.configMaps {
+			configMaps[n] = true
+		}
+		for n := range refs.secrets {
+			secrets[n] = true
+		}
+		for n := range refs.pvcs {
+			pvcs[n] = true
+		}
+	}
+	if template != nil {
+		merge(*template)
+		serviceAccounts[defaultServiceAccount(serviceAccount)] = true
+	}
+	for _, spec := range specs {
+		merge(extractWorkloadReferences(spec))
+		serviceAccounts[defaultServiceAccount(spec.ServiceAccountName)] = true
+	}
+
+	var refs []Reference
+	for _, name := range sortedKeys(configMaps) {
+		_, err := b.cache.ConfigMaps().ConfigMaps(namespace).Get(name)
+		refs = append(refs, Reference{ResourceRef: relatedRef("ConfigMap", "", namespace, name), Missing: err != nil})
+	}
+	secretLister := b.cache.Secrets()
+	for _, name := range sortedKeys(secrets) {
+		missing := false
+		if secretLister != nil {
+			_, err := secretLister.Secrets(namespace).Get(name)
+			missing = err != nil
+		}
+		refs = append(refs, Reference{ResourceRef: relatedRef("Secret", "", namespace, name), Missing: missing})
+	}
+	for _, name := range sortedKeys(pvcs) {
+		_, err := b.cache.PersistentVolumeClaims().PersistentVolumeClaims(namespace).Get(name)
+		refs = append(refs, Reference{ResourceRef: relatedRef("PersistentVolumeClaim", "", namespace, name), Missing: err != nil})
+	}
+	for _, name := range sortedKeys(serviceAccounts) {
+		refs = append(refs, Reference{ResourceRef: relatedRef("ServiceAccount", "", namespace, name)})
+	}
+	return refs
+}
+
+// defaultServiceAccount is the account pods run as when their spec names none
+func defaultServiceAccount(name string) string {
+	if name == "" {
+		return "default"
+	}
+	return name
+}
+
+// relatedServices finds the Services selecting any of the label sets, and the Ingresses
+// routing to those Services
+func (b *Builder) relatedServices(namespace string, podLabels []map[string]string) ([]ResourceRef, []ResourceRef) {
+	if len(podLabels) == 0 {
+		return nil, nil
+	}
+	services, err := b.cache.Services().Services(namespace).List(labels.Everything())
+	if err != nil {
+		return nil, nil
+	}
+	selected := make(map[string]bool)
+	var refs []ResourceRef
+	for _, svc := range services {
+		for _, l := range podLabels {
+			if matchesSelector(l, svc.Spec.Selector) {
+				selected[svc.Name] = true
+				refs = append(refs, relatedRef("Service", "", namespace, svc.Name))
+				break
+			}
+		}
+	}
+	sortRefs(refs)
+	if len(selected) == 0 {
+		return refs, nil
+	}
+
+	ingresses, err := b.cache.Ingresses().Ingresses(namespace).List(labels.Everything())
+	if err != nil {
+		return refs, nil
+	}
+	var ingRefs []ResourceRef
+	for _, ing := range ingresses {
+		routes := ing.Spec.DefaultBackend != nil && ing.Spec.DefaultBackend.Service != nil && selected[ing.Spec.DefaultBackend.Service.Name]
+		for _, rule := range ing.Spec.Rules {
+			if routes || rule.HTTP == nil {
+				continue
+			}
+			for _, path := range rule.HTTP.Paths {
+				if path.Backend.Service != nil && selected[path.Backend.Service.Name] {
+					routes = true
+					break
+				}
+			}
+		}
+		if routes {
+			ingRefs = append(ingRefs, relatedRef("Ingress", "", namespace, ing.Name))
+		}
+	}
+	sortRefs(ingRefs)
+	return refs, ingRefs
+}
+
+// relatedHPAs finds the HPAs whose scale target is one of targets
+func (b *Builder) relatedHPAs(namespace string, targets []ResourceRef) []ResourceRef {
+	if !b.cache.HasTypedInformer("HorizontalPodAutoscaler") {
+		return nil
+	}
+	hpas, err := b.cache.HorizontalPodAutoscalers().HorizontalPodAutoscalers(namespace).List(labels.Everything())
+	if err != nil {
+		return nil
+	}
+	var refs []ResourceRef
+	for _, hpa := range hpas {
+		target := hpa.Spec.ScaleTargetRef
+		for _, t := range targets {
+			if t.Kind == target.Kind && t.Name == target.Name {
+				refs = append(refs, relatedRef("HorizontalPodAutoscaler", "", namespace, hpa.Name))
+				break
+			}
+		}
+	}
+	sortRefs(refs)
+	return refs
+}
+
+// relatedPDBs finds the PodDisruptionBudgets selecting any of the label sets
+func (b *Builder) relatedPDBs(ctx context.Context, namespace string, podLabels []map[string]string) []ResourceRef {
+	if len(podLabels) == 0 {
+		return nil
+	}
+	var refs []ResourceRef
+	for _, pdb := range b.cache.PodDisruptionBudgets(ctx, namespace) {
+		if pdb.Spec.Selector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil || selector.Empty() {
+			continue
+		}
+		for _, l := range podLabels {
+			if selector.Matches(labels.Set(l)) {
+				refs = append(refs, relatedRef("PodDisruptionBudget", "", namespace, pdb.Name))
+				break
+			}
+		}
+	}
+	sortRefs(refs)
+	return refs
+}
+
+// relatedEvents returns the most recent K8s Events about the objects with the given UIDs
+func (b *Builder) relatedEvents(namespace string, objects map[types.UID]ResourceRef) []RelatedEvent {
+	events, err := b.cache.Events().Events(namespace).List(labels.Everything())
+	if err != nil {
+		return nil
+	}
+	var related []RelatedEvent
+	for _, e := range events {
+		ref, ok := objects[e.InvolvedObject.UID]
+		if !ok {
+			continue
+		}
+		related = append(related, RelatedEvent{
+			Object:   ref,
+			Type:     e.Type,
+			Reason:   e.Reason,
+			Message:  e.Message,
+			Count:    e.Count,
+			LastSeen: eventLastSeen(e),
+		})
+	}
+	sort.Slice(related, func(i, j int) bool { return related[i].LastSeen.After(related[j].LastSeen) })
+	if len(related) > maxRelatedEvents {
+		related = related[:maxRelatedEvents]
+	}
+	return related
+}
+
+// eventLastSeen is when an event was last seen
+func eventLastSeen(e *corev1.Event) time.Time {
+	if !e.LastTimestamp.IsZero() {
+		return e.LastTimestamp.Time
+	}
+	if !e.EventTime.IsZero() {
+		return e.EventTime.Time
+	}
+	return e.CreationTimestamp.Time
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortRefs(refs []ResourceRef) {
+	sort.Slice(refs, func(i, j int) bool { return refs[i].Name < refs[j].Name })
+}
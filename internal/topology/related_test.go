@@ -0,0 +1,62 @@
+package topology
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/skyhook-io/radar/internal/testenv"
+)
+
+func TestRelatedAgainstCluster(t *testing.T) {
+	objs := testenv.App("shop", "web", testenv.WithIngress("shop.example.com"), testenv.WithConfigMap(), testenv.WithHPA(2, 4))
+	objs = append(objs, testenv.App("shop", "worker")...)
+	testenv.Setup(t, objs...)
+	b := NewBuilder()
+
+	rel, err := b.Related(context.Background(), "deployments", "shop", "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rel.Resource.Kind != "Deployment" || len(rel.Owners) != 0 {
+		t.Errorf("resource = %+v, owners = %+v", rel.Resource, rel.Owners)
+	}
+	var children []string
+	for _, c := range rel.Children {
+		children = append(children, c.Kind+"/"+c.Name+"<-"+c.Owner)
+	}
+	if len(children) != 3 || !strings.HasPrefix(children[0], "ReplicaSet/web-") ||
+		rel.Children[1].Kind != "Pod" || rel.Children[1].Owner != "ReplicaSet/"+rel.Children[0].Name {
+		t.Errorf("children = %v, want the ReplicaSet then its two Pods", children)
+	}
+	refs := map[string]bool{}
+	for _, r := range rel.References {
+		refs[r.Kind+"/"+r.Name] = !r.Missing
+	}
+	if !refs["ConfigMap/web-config"] || !refs["ServiceAccount/default"] || len(refs) != 2 {
+		t.Errorf("references = %+v", rel.References)
+	}
+	if len(rel.Services) != 1 || rel.Services[0].Name != "web" {
+		t.Errorf("services = %+v, want web only", rel.Services)
+	}
+	if len(rel.Ingresses) != 1 || len(rel.HPAs) != 1 {
+		t.Errorf("ingresses = %+v, hpas = %+v", rel.Ingresses, rel.HPAs)
+	}
+
+	// From a pod, the owner chain leads up to the Deployment and its HPA
+	pod := rel.Children[1]
+	rel, err = b.Related(context.Background(), "pods", "shop", pod.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rel.Owners) != 2 || rel.Owners[0].Kind != "ReplicaSet" || rel.Owners[1].Kind != "Deployment" || rel.Owners[1].Name != "web" {
+		t.Errorf("owners = %+v, want the ReplicaSet then the Deployment", rel.Owners)
+	}
+	if len(rel.Children) != 0 || len(rel.Services) != 1 || len(rel.HPAs) != 1 {
+		t.Errorf("pod graph = %+v", rel)
+	}
+
+	if _, err := b.Related(context.Background(), "deployments", "shop", "missing"); err == nil {
+		t.Error("expected an error for a missing resource")
+	}
+}
@@ -7,13 +7,15 @@ import (
 // GetRelationships computes relationships for a specific resource
 // by finding all edges in the topology that involve this resource.
 // The topology should be pre-built and cached for performance.
-func GetRelationships(kind, namespace, name string, topo *Topology) *Relationships {
+// group disambiguates custom resource kinds; it may be empty for the kinds the
+// topology knows about.
+func GetRelationships(kind, group, namespace, name string, topo *Topology) *Relationships {
 	if topo == nil {
 		return nil
 	}
 
 	// Build the node ID for this resource (matches format used in builder.go)
-	nodeID := buildNodeID(kind, namespace, name)
+	nodeID := buildNodeID(kind, group, namespace, name)
 
 	rel := &Relationships{}
 
@@ -92,12 +94,33 @@ func GetRelationships(kind, namespace, name string, topo *Topology) *Relationshi
 	return rel
 }
 
-// buildNodeID constructs a node ID from kind, namespace, and name
-// This must match the format used in builder.go
-// Format: kind/namespace/name (using / since it's not allowed in K8s names)
-func buildNodeID(kind, namespace, name string) string {
+// rolloutGroup is the API group of Argo Rollouts
+const rolloutGroup = "argoproj.io"
+
+// customKindGroups maps the custom resource kinds in the topology to their API group, so
+// lookups by bare kind (older clients) still resolve
+var customKindGroups = map[string]string{
+	"rollout": rolloutGroup,
+}
+
+// nodeID formats a node ID: kind/namespace/name for built-in kinds and
+// kind.group/namespace/name for custom resources (kinds contain neither . nor /)
+func nodeID(kind, group, namespace, name string) string {
+	if group != "" {
+		kind += "." + group
+	}
+	return kind + "/" + namespace + "/" + name
+}
+
+// buildNodeID constructs a node ID from kind, group, namespace, and name.
+// This must match the format used in builder.go. kind may be plural or qualified
+// ("Rollout.argoproj.io"); a missing group is filled in for known custom resources.
+func buildNodeID(kind, group, namespace, name string) string {
 	// Normalize kind to match topology builder format
 	k := strings.ToLower(kind)
+	if base, g, ok := strings.Cut(k, "."); ok {
+		k, group = base, g
+	}
 
 	// Handle plural to singular conversion for common types
 	kindMap := map[string]string{
@@ -114,28 +137,39 @@ func buildNodeID(kind, namespace, name string) string {
 		"hpas":         "hpa",
 		"jobs":         "job",
 		"cronjobs":     "cronjob",
+
+		"horizontalpodautoscaler":  "hpa",
+		"horizontalpodautoscalers": "hpa",
+		"persistentvolumeclaim":    "pvc",
+		"persistentvolumeclaims":   "pvc",
 	}
 
 	if singular, ok := kindMap[k]; ok {
 		k = singular
 	}
+	if group == "" {
+		group = customKindGroups[k]
+	} else if customKindGroups[k] != group {
+		group = "" // built-in kinds are identified without their group
+	}
 
-	return k + "/" + namespace + "/" + name
+	return nodeID(k, group, namespace, name)
 }
 
 // parseNodeID extracts kind, namespace, and name from a node ID
 // Returns nil for PodGroup since it's a UI-only concept, not a real K8s resource
-// Format: kind/namespace/name (using / since it's not allowed in K8s names)
+// Format: kind/namespace/name or kind.group/namespace/name (using / since it's not allowed in K8s names)
 func parseNodeID(nodeID string) *ResourceRef {
 	// Node IDs are formatted as: kind/namespace/name
-	// e.g., "deployment/default/my-app" or "pod/kube-system/coredns-abc123"
+	// e.g., "deployment/default/my-app", "pod/kube-system/coredns-abc123" or
+	// "rollout.argoproj.io/default/my-app". IDs without a group are still accepted.
 
 	parts := strings.SplitN(nodeID, "/", 3)
 	if len(parts) < 3 {
 		return nil
 	}
 
-	kind := parts[0]
+	kind, group, _ := strings.Cut(parts[0], ".")
 	namespace := parts[1]
 	name := parts[2]
 
@@ -146,6 +180,7 @@ func parseNodeID(nodeID string) *ResourceRef {
 
 	return &ResourceRef{
 		Kind:      normalizeKind(kind),
+		Group:     group,
 		Namespace: namespace,
 		Name:      name,
 	}
@@ -190,7 +225,7 @@ func GetRelationshipsForAll(resources []ResourceRef, topo *Topology) map[string]
 
 	// Build set of node IDs we care about
 	for _, r := range resources {
-		nodeID := buildNodeID(r.Kind, r.Namespace, r.Name)
+		nodeID := buildNodeID(r.Kind, r.Group, r.Namespace, r.Name)
 		nodeIDs[nodeID] = true
 		result[nodeID] = &Relationships{}
 	}
@@ -0,0 +1,61 @@
+package topology
+
+import "testing"
+
+func TestBuildNodeID(t *testing.T) {
+	for _, tc := range []struct {
+		kind, group, want string
+	}{
+		{"Deployment", "", "deployment/default/web"},
+		{"deployments", "apps", "deployment/default/web"},
+		{"Rollout", "", "rollout.argoproj.io/default/web"},
+		{"rollouts", "argoproj.io", "rollout.argoproj.io/default/web"},
+		{"Rollout.argoproj.io", "", "rollout.argoproj.io/default/web"},
+		{"HorizontalPodAutoscaler", "autoscaling", "hpa/default/web"},
+		{"PersistentVolumeClaim", "", "pvc/default/web"},
+	} {
+		if got := buildNodeID(tc.kind, tc.group, "default", "web"); got != tc.want {
+			t.Errorf("buildNodeID(%q, %q) = %q, want %q", tc.kind, tc.group, got, tc.want)
+		}
+	}
+}
+
+func TestParseNodeID(t *testing.T) {
+	for _, tc := range []struct {
+		id   string
+		want ResourceRef
+	}{
+		{"deployment/default/web", ResourceRef{Kind: "Deployment", Namespace: "default", Name: "web"}},
+		{"rollout.argoproj.io/default/web", ResourceRef{Kind: "Rollout", Group: "argoproj.io", Namespace: "default", Name: "web"}},
+		// IDs from before groups were added
+		{"rollout/default/web", ResourceRef{Kind: "Rollout", Namespace: "default", Name: "web"}},
+	} {
+		got := parseNodeID(tc.id)
+		if got == nil || *got != tc.want {
+			t.Errorf("parseNodeID(%q) = %+v, want %+v", tc.id, got, tc.want)
+		}
+	}
+	if parseNodeID("podgroup/default/web") != nil {
+		t.Error("PodGroup IDs should not parse to a resource")
+	}
+}
+
+func TestGetRelationshipsDistinguishesGroups(t *testing.T) {
+	topo := &Topology{Edges: []Edge{
+		{Source: "service/default/web", Target: "rollout.argoproj.io/default/web", Type: EdgeExposes},
+		{Source: "service/default/web", Target: "deployment/default/web", Type: EdgeExposes},
+	}}
+
+	rel := GetRelationships("rollouts", "argoproj.io", "default", "web", topo)
+	if rel == nil || len(rel.Services) != 1 || rel.Services[0].Name != "web" {
+		t.Fatalf("expected the Rollout to be exposed by one Service, got %+v", rel)
+	}
+
+	rel = GetRelationships("services", "", "default", "web", topo)
+	if rel == nil || len(rel.Pods) != 2 {
+		t.Fatalf("expected two exposed workloads, got %+v", rel)
+	}
+	if rel.Pods[0].Group != "argoproj.io" || rel.Pods[1].Group != "" {
+		t.Errorf("expected groups to be carried on refs, got %+v", rel.Pods)
+	}
+}
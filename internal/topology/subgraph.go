@@ -0,0 +1,57 @@
+package topology
+
+// Subgraph returns the part of topo made of the root resources and everything they manage,
+// transitively (a Deployment's pods, a CronJob's Jobs and their pods), with the edges
+// between those nodes. Roots without a node (kinds the topology doesn't draw, or objects
+// not in the cache) are returned as unmapped.
+func Subgraph(topo *Topology, roots []ResourceRef) (*Topology, []ResourceRef) {
+	sub := &Topology{Nodes: []Node{}, Edges: []Edge{}, Warnings: topo.Warnings}
+	unmapped := []ResourceRef{}
+
+	nodes := make(map[string]bool, len(topo.Nodes))
+	for _, n := range topo.Nodes {
+		nodes[n.ID] = true
+	}
+	manages := make(map[string][]string)
+	for _, e := range topo.Edges {
+		if e.Type == EdgeManages {
+			manages[e.Source] = append(manages[e.Source], e.Target)
+		}
+	}
+
+	keep := make(map[string]bool)
+	var queue []string
+	for _, r := range roots {
+		id := buildNodeID(r.Kind, r.Group, r.Namespace, r.Name)
+		if !nodes[id] {
+			unmapped = append(unmapped, r)
+			continue
+		}
+		if !keep[id] {
+			keep[id] = true
+			queue = append(queue, id)
+		}
+	}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, child := range manages[id] {
+			if nodes[child] && !keep[child] {
+				keep[child] = true
+				queue = append(queue, child)
+			}
+		}
+	}
+
+	for _, n := range topo.Nodes {
+		if keep[n.ID] {
+			sub.Nodes = append(sub.Nodes, n)
+		}
+	}
+	for _, e := range topo.Edges {
+		if keep[e.Source] && keep[e.Target] {
+			sub.Edges = append(sub.Edges, e)
+		}
+	}
+	return sub, unmapped
+}
@@ -0,0 +1,64 @@
+package topology
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestSubgraph(t *testing.T) {
+	node := func(id string) Node { return Node{ID: id} }
+	edge := func(source, target string, typ EdgeType) Edge {
+		return Edge{ID: source + "-to-" + target, Source: source, Target: target, Type: typ}
+	}
+	topo := &Topology{
+		Nodes: []Node{
+			node("ingress/shop/web"), node("service/shop/web"), node("deployment/shop/web"),
+			node("pod/shop/web-1"), node("podgroup/shop/web-workers"), node("hpa/shop/web"),
+			node("configmap/shop/web"), node("cronjob/shop/report"), node("job/shop/report-1"), node("pod/shop/report-1-x"),
+			// Another release's workload sharing the ConfigMap
+			node("deployment/shop/api"), node("pod/shop/api-1"),
+		},
+		Edges: []Edge{
+			edge("ingress/shop/web", "service/shop/web", EdgeRoutesTo),
+			edge("service/shop/web", "deployment/shop/web", EdgeExposes),
+			edge("deployment/shop/web", "pod/shop/web-1", EdgeManages),
+			edge("deployment/shop/web", "podgroup/shop/web-workers", EdgeManages),
+			edge("hpa/shop/web", "deployment/shop/web", EdgeUses),
+			edge("configmap/shop/web", "deployment/shop/web", EdgeConfigures),
+			edge("configmap/shop/web", "deployment/shop/api", EdgeConfigures),
+			edge("deployment/shop/api", "pod/shop/api-1", EdgeManages),
+			edge("cronjob/shop/report", "job/shop/report-1", EdgeManages),
+			edge("job/shop/report-1", "pod/shop/report-1-x", EdgeManages),
+		},
+	}
+	roots := []ResourceRef{
+		{Kind: "Ingress", Namespace: "shop", Name: "web"},
+		{Kind: "Service", Namespace: "shop", Name: "web"},
+		{Kind: "Deployment", Namespace: "shop", Name: "web"},
+		{Kind: "HorizontalPodAutoscaler", Group: "autoscaling", Namespace: "shop", Name: "web"},
+		{Kind: "ConfigMap", Namespace: "shop", Name: "web"},
+		{Kind: "CronJob", Group: "batch", Namespace: "shop", Name: "report"},
+		{Kind: "ServiceAccount", Namespace: "shop", Name: "web"},
+	}
+
+	sub, unmapped := Subgraph(topo, roots)
+	var ids []string
+	for _, n := range sub.Nodes {
+		ids = append(ids, n.ID)
+	}
+	sort.Strings(ids)
+	want := []string{
+		"configmap/shop/web", "cronjob/shop/report", "deployment/shop/web", "hpa/shop/web", "ingress/shop/web",
+		"job/shop/report-1", "pod/shop/report-1-x", "pod/shop/web-1", "podgroup/shop/web-workers", "service/shop/web",
+	}
+	if !reflect.DeepEqual(ids, want) {
+		t.Errorf("nodes = %v\nwant %v", ids, want)
+	}
+	if len(sub.Edges) != 8 {
+		t.Errorf("edges = %d, want the 8 between the release's nodes", len(sub.Edges))
+	}
+	if len(unmapped) != 1 || unmapped[0].Kind != "ServiceAccount" {
+		t.Errorf("unmapped = %+v, want the ServiceAccount", unmapped)
+	}
+}
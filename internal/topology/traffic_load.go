@@ -0,0 +1,104 @@
+package topology
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EdgeLoad is the observed request load on a traffic edge
+type EdgeLoad struct {
+	RPS          float64  `json:"rps"`
+	ErrorPercent float64  `json:"errorPercent"`    // Share of requests failing with 5xx
+	P99Ms        *float64 `json:"p99Ms,omitempty"` // Unset when no latency histogram exists
+}
+
+// TrafficLoad is the request load observed per Service and Ingress
+type TrafficLoad struct {
+	Services  map[string]EdgeLoad // Keyed namespace/name
+	Ingresses map[string]EdgeLoad // Keyed namespace/name
+	Routes    map[string]EdgeLoad // Ingress to Service, keyed namespace/ingress/service
+	UpdatedAt time.Time
+	Error     string // Why the latest collection failed, surfaced as a topology warning
+}
+
+// TrafficLoadSource supplies the latest traffic load. It must not block, since it's read
+// on every traffic topology build.
+type TrafficLoadSource interface {
+	TrafficLoad() *TrafficLoad
+}
+
+var (
+	trafficLoadSource   TrafficLoadSource
+	trafficLoadSourceMu sync.RWMutex
+)
+
+// SetTrafficLoadSource sets the source annotating traffic topology edges (nil disables)
+func SetTrafficLoadSource(src TrafficLoadSource) {
+	trafficLoadSourceMu.Lock()
+	defer trafficLoadSourceMu.Unlock()
+	trafficLoadSource = src
+}
+
+func currentTrafficLoad() *TrafficLoad {
+	trafficLoadSourceMu.RLock()
+	defer trafficLoadSourceMu.RUnlock()
+	if trafficLoadSource == nil {
+		return nil
+	}
+	return trafficLoadSource.TrafficLoad()
+}
+
+// applyTrafficLoad annotates traffic edges with observed load: Internet to Ingress edges
+// with the Ingress's load, Ingress to Service edges with that route's load, and Service
+// to pod edges with the Service's load
+func applyTrafficLoad(topo *Topology, load *TrafficLoad) {
+	if load == nil {
+		return
+	}
+	if load.Error != "" {
+		topo.Warnings = append(topo.Warnings, "Traffic metrics unavailable: "+load.Error)
+	}
+	for i := range topo.Edges {
+		e := &topo.Edges[i]
+		src, dst := strings.Split(e.Source, "/"), strings.Split(e.Target, "/")
+		var l EdgeLoad
+		var ok bool
+		switch {
+		case e.Source == "internet" && len(dst) == 3 && dst[0] == "ingress":
+			l, ok = load.Ingresses[dst[1]+"/"+dst[2]]
+		case len(src) == 3 && src[0] == "ingress" && len(dst) == 3 && dst[0] == "service":
+			l, ok = load.Routes[src[1]+"/"+src[2]+"/"+dst[2]]
+		case len(src) == 3 && src[0] == "service":
+			l, ok = load.Services[src[1]+"/"+src[2]]
+		}
+		if ok {
+			e.Load = &l
+			if e.Label != "" {
+				e.Label += " · " + l.String() // Keep the mesh route, e.g. "v2 20% · 3 rps"
+			} else {
+				e.Label = l.String()
+			}
+		}
+	}
+}
+
+// String renders the load as an edge label, e.g. "12.5 rps · 0.4% errors · p99 84ms"
+func (l EdgeLoad) String() string {
+	parts := []string{formatRate(l.RPS) + " rps"}
+	if l.ErrorPercent > 0 {
+		parts = append(parts, fmt.Sprintf("%.1f%% errors", l.ErrorPercent))
+	}
+	if l.P99Ms != nil {
+		parts = append(parts, fmt.Sprintf("p99 %.0fms", *l.P99Ms))
+	}
+	return strings.Join(parts, " · ")
+}
+
+func formatRate(rps float64) string {
+	if rps >= 10 {
+		return fmt.Sprintf("%.0f", rps)
+	}
+	return fmt.Sprintf("%.2g", rps)
+}
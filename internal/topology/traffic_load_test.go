@@ -0,0 +1,36 @@
+package topology
+
+import "testing"
+
+func TestApplyTrafficLoad(t *testing.T) {
+	p99 := 84.0
+	topo := &Topology{Edges: []Edge{
+		{ID: "a", Source: "internet", Target: "ingress/shop/web"},
+		{ID: "b", Source: "ingress/shop/web", Target: "service/shop/frontend"},
+		{ID: "c", Source: "service/shop/frontend", Target: "podgroup-shop-frontend"},
+		{ID: "d", Source: "service/shop/cart", Target: "pod/shop/cart-0"},
+	}}
+	applyTrafficLoad(topo, &TrafficLoad{
+		Ingresses: map[string]EdgeLoad{"shop/web": {RPS: 120}},
+		Routes:    map[string]EdgeLoad{"shop/web/frontend": {RPS: 120, ErrorPercent: 0.5, P99Ms: &p99}},
+		Services:  map[string]EdgeLoad{"shop/frontend": {RPS: 0.25}},
+	})
+
+	if l := topo.Edges[0].Load; l == nil || l.RPS != 120 {
+		t.Errorf("internet edge load = %+v", l)
+	}
+	if got := topo.Edges[1].Label; got != "120 rps · 0.5% errors · p99 84ms" {
+		t.Errorf("route label = %q", got)
+	}
+	if got := topo.Edges[2].Label; got != "0.25 rps" {
+		t.Errorf("service edge label = %q", got)
+	}
+	if topo.Edges[3].Load != nil {
+		t.Error("edge without metrics was annotated")
+	}
+
+	applyTrafficLoad(topo, &TrafficLoad{Error: "no Prometheus service found"})
+	if len(topo.Warnings) != 1 {
+		t.Errorf("warnings = %v, want the collection error", topo.Warnings)
+	}
+}
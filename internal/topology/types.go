@@ -21,6 +21,8 @@ const (
 	KindCronJob     NodeKind = "CronJob"
 	KindPVC         NodeKind = "PVC"
 	KindNamespace   NodeKind = "Namespace"
+
+	KindNetworkPolicy NodeKind = "NetworkPolicy"
 )
 
 // HealthStatus represents the health status of a node
@@ -42,12 +44,15 @@ const (
 	EdgeManages    EdgeType = "manages"
 	EdgeUses       EdgeType = "uses"
 	EdgeConfigures EdgeType = "configures"
+	EdgeAllows     EdgeType = "allows" // NetworkPolicies permit traffic between workloads
+	EdgeBlocks     EdgeType = "blocks" // NetworkPolicies deny traffic between workloads
 )
 
 // Node represents a node in the topology graph
 type Node struct {
 	ID     string         `json:"id"`
 	Kind   NodeKind       `json:"kind"`
+	Group  string         `json:"group,omitempty"` // API group, set for custom resources
 	Name   string         `json:"name"`
 	Status HealthStatus   `json:"status"`
 	Data   map[string]any `json:"data"`
@@ -55,12 +60,14 @@ type Node struct {
 
 // Edge represents a connection between two nodes
 type Edge struct {
-	ID                string   `json:"id"`
-	Source            string   `json:"source"`
-	Target            string   `json:"target"`
-	Type              EdgeType `json:"type"`
-	Label             string   `json:"label,omitempty"`
-	SkipIfKindVisible string   `json:"skipIfKindVisible,omitempty"` // Hide this edge if this kind is visible (for shortcut edges)
+	ID                string      `json:"id"`
+	Source            string      `json:"source"`
+	Target            string      `json:"target"`
+	Type              EdgeType    `json:"type"`
+	Label             string      `json:"label,omitempty"`
+	SkipIfKindVisible string      `json:"skipIfKindVisible,omitempty"` // Hide this edge if this kind is visible (for shortcut edges)
+	Load              *EdgeLoad   `json:"load,omitempty"`              // Observed request load (traffic view with traffic metrics)
+	Mesh              []MeshRoute `json:"mesh,omitempty"`              // Istio routes behind this edge (traffic view)
 }
 
 // Topology represents the complete graph
@@ -87,6 +94,8 @@ type BuildOptions struct {
 	IncludeConfigMaps  bool     // Include ConfigMap nodes
 	IncludePVCs        bool     // Include PersistentVolumeClaim nodes
 	IncludeReplicaSets bool     // Include ReplicaSet nodes (noisy intermediate objects)
+
+	IncludeNetworkPolicies bool // Include NetworkPolicy nodes and allows/blocks edges
 }
 
 // DefaultBuildOptions returns sensible defaults
@@ -99,12 +108,15 @@ func DefaultBuildOptions() BuildOptions {
 		IncludeConfigMaps:  true,
 		IncludePVCs:        true,
 		IncludeReplicaSets: false, // Hidden by default - noisy intermediate between Deployment and Pod
+
+		IncludeNetworkPolicies: true,
 	}
 }
 
 // ResourceRef is a reference to a related K8s resource
 type ResourceRef struct {
 	Kind      string `json:"kind"`
+	Group     string `json:"group,omitempty"` // API group, set for custom resources
 	Namespace string `json:"namespace"`
 	Name      string `json:"name"`
 }
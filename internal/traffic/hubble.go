@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -466,6 +467,9 @@ func convertHubbleFlow(pbFlow *flowpb.Flow) Flow {
 		Verdict:     strings.ToLower(pbFlow.GetVerdict().String()),
 		Connections: 1,
 	}
+	if pbFlow.GetVerdict() == flowpb.Verdict_DROPPED {
+		flow.DropReason = hubbleDropReason(pbFlow.GetDropReasonDesc())
+	}
 
 	// Extract L4 info
 	l4 := pbFlow.GetL4()
@@ -486,16 +490,37 @@ func convertHubbleFlow(pbFlow *flowpb.Flow) Flow {
 		}
 	}
 
-	// Extract L7 info if available
+	// Extract L7 info if available (requires an L7 visibility policy or annotation)
 	l7 := pbFlow.GetL7()
 	if l7 != nil {
+		if t := l7.GetType(); t != flowpb.L7FlowType_UNKNOWN_L7_TYPE {
+			flow.L7Type = strings.ToLower(t.String())
+		}
+		flow.LatencyMs = float64(l7.GetLatencyNs()) / float64(time.Millisecond)
 		if http := l7.GetHttp(); http != nil {
 			flow.L7Protocol = "HTTP"
 			flow.HTTPMethod = http.GetMethod()
 			flow.HTTPPath = http.GetUrl()
 			flow.HTTPStatus = int(http.GetCode())
+			// gRPC is HTTP/2 with a gRPC content type; its status travels in a header
+			for _, header := range http.GetHeaders() {
+				switch strings.ToLower(header.GetKey()) {
+				case "content-type":
+					if strings.HasPrefix(header.GetValue(), "application/grpc") {
+						flow.L7Protocol = "gRPC"
+					}
+				case "grpc-status":
+					flow.GRPCStatus, _ = strconv.Atoi(header.GetValue())
+				}
+			}
 		} else if dns := l7.GetDns(); dns != nil {
 			flow.L7Protocol = "DNS"
+			flow.DNSQuery = strings.TrimSuffix(dns.GetQuery(), ".")
+			if rcode := dns.GetRcode(); rcode != 0 {
+				flow.DNSError = dnsRCodeName(rcode)
+			}
+		} else if l7.GetKafka() != nil {
+			flow.L7Protocol = "Kafka"
 		}
 	}
 
@@ -509,6 +534,32 @@ func convertHubbleFlow(pbFlow *flowpb.Flow) Flow {
 	return flow
 }
 
+// hubbleDropReason renders a drop reason for display, e.g. POLICY_DENIED as "policy denied"
+func hubbleDropReason(reason flowpb.DropReason) string {
+	if reason == flowpb.DropReason_DROP_REASON_UNKNOWN {
+		return ""
+	}
+	return strings.ReplaceAll(strings.ToLower(reason.String()), "_", " ")
+}
+
+// dnsRCodeName returns the mnemonic of a DNS response code (RFC 1035, RFC 6895)
+func dnsRCodeName(rcode uint32) string {
+	switch rcode {
+	case 1:
+		return "FORMERR"
+	case 2:
+		return "SERVFAIL"
+	case 3:
+		return "NXDOMAIN"
+	case 4:
+		return "NOTIMP"
+	case 5:
+		return "REFUSED"
+	default:
+		return fmt.Sprintf("RCODE%d", rcode)
+	}
+}
+
 // convertEndpoint converts a Hubble Endpoint to our internal Endpoint type
 func convertEndpoint(ep *flowpb.Endpoint, ip string) Endpoint {
 	if ep == nil {
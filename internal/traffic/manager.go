@@ -407,6 +407,8 @@ func (m *Manager) GetActiveSourceName() string {
 func AggregateFlows(flows []Flow) []AggregatedFlow {
 	// Key: source-ns/source-name|dest-ns/dest-name|port
 	aggregated := make(map[string]*AggregatedFlow)
+	latencySum := make(map[string]float64)
+	latencyCount := make(map[string]int64)
 
 	for _, f := range flows {
 		key := fmt.Sprintf("%s/%s|%s/%s|%d",
@@ -414,7 +416,8 @@ func AggregateFlows(flows []Flow) []AggregatedFlow {
 			f.Destination.Namespace, f.Destination.Name,
 			f.Port)
 
-		if agg, ok := aggregated[key]; ok {
+		agg, ok := aggregated[key]
+		if ok {
 			agg.FlowCount++
 			agg.BytesSent += f.BytesSent
 			agg.BytesRecv += f.BytesRecv
@@ -423,7 +426,7 @@ func AggregateFlows(flows []Flow) []AggregatedFlow {
 				agg.LastSeen = f.LastSeen
 			}
 		} else {
-			aggregated[key] = &AggregatedFlow{
+			agg = &AggregatedFlow{
 				Source:      f.Source,
 				Destination: f.Destination,
 				Protocol:    f.Protocol,
@@ -434,11 +437,35 @@ func AggregateFlows(flows []Flow) []AggregatedFlow {
 				Connections: f.Connections,
 				LastSeen:    f.LastSeen,
 			}
+			aggregated[key] = agg
+		}
+
+		if f.Verdict == "dropped" {
+			agg.DroppedCount++
+			if f.DropReason != "" {
+				if agg.DropReasons == nil {
+					agg.DropReasons = make(map[string]int64)
+				}
+				agg.DropReasons[f.DropReason]++
+			}
+		}
+		if f.L7Type == "response" {
+			agg.RequestCount++
+			if f.HTTPStatus >= 500 || f.GRPCStatus != 0 || f.DNSError != "" {
+				agg.ErrorCount++
+			}
+			if f.LatencyMs > 0 {
+				latencySum[key] += f.LatencyMs
+				latencyCount[key]++
+			}
 		}
 	}
 
 	result := make([]AggregatedFlow, 0, len(aggregated))
-	for _, agg := range aggregated {
+	for key, agg := range aggregated {
+		if n := latencyCount[key]; n > 0 {
+			agg.AvgLatencyMs = latencySum[key] / float64(n)
+		}
 		result = append(result, *agg)
 	}
 	return result
@@ -6,7 +6,6 @@ import (
 	"io"
 	"log"
 	"net"
-	"net/http"
 	"sync"
 	"time"
 
@@ -16,7 +15,8 @@ import (
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/portforward"
-	"k8s.io/client-go/transport/spdy"
+
+	"github.com/skyhook-io/radar/internal/k8s"
 )
 
 // MetricsPortForward manages port-forwarding to metrics services for traffic data
@@ -267,13 +267,11 @@ func runMetricsPortForward(ctx context.Context, client kubernetes.Interface, con
 			Ports: []int32{int32(targetPort)},
 		}, scheme.ParameterCodec)
 
-	transport, upgrader, err := spdy.RoundTripperFor(config)
+	dialer, err := k8s.NewPortForwardDialer(config, req.URL())
 	if err != nil {
-		return fmt.Errorf("failed to create round tripper: %w", err)
+		return err
 	}
 
-	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", req.URL())
-
 	ports := []string{fmt.Sprintf("%d:%d", localPort, targetPort)}
 
 	pf, err := portforward.New(dialer, ports, stopCh, readyCh, io.Discard, io.Discard)
@@ -0,0 +1,442 @@
+package traffic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/rest"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/topology"
+)
+
+// Service annotations overriding the built-in queries with PromQL for apps that export
+// their own request metrics. Each query should return a single value (results are summed).
+const (
+	RPSQueryAnnotation    = "radar.skyhook.io/rps-query"    // Requests per second
+	ErrorsQueryAnnotation = "radar.skyhook.io/errors-query" // Failing requests per second
+	P99QueryAnnotation    = "radar.skyhook.io/p99-query"    // p99 latency in milliseconds
+)
+
+const (
+	// DefaultRateInterval is how often traffic rates are refreshed
+	DefaultRateInterval = 30 * time.Second
+	// rateWindow is the PromQL rate() window
+	rateWindow = "5m"
+	// maxAnnotatedServices caps how many Services are queried through annotations per refresh
+	maxAnnotatedServices = 100
+)
+
+// rateQuery is a PromQL query whose result is keyed by some of its labels
+type rateQuery struct {
+	query  string
+	labels []string // Joined with "/" to form the key
+}
+
+// Istio request metrics, reported by the destination sidecar
+var (
+	istioRPS = rateQuery{
+		query:  `sum by (destination_service_namespace, destination_service_name) (rate(istio_requests_total{reporter="destination"}[` + rateWindow + `]))`,
+		labels: []string{"destination_service_namespace", "destination_service_name"},
+	}
+	istioErrors = rateQuery{
+		query:  `sum by (destination_service_namespace, destination_service_name) (rate(istio_requests_total{reporter="destination",response_code=~"5.."}[` + rateWindow + `]))`,
+		labels: istioRPS.labels,
+	}
+	istioP99 = rateQuery{
+		query:  `histogram_quantile(0.99, sum by (destination_service_namespace, destination_service_name, le) (rate(istio_request_duration_milliseconds_bucket{reporter="destination"}[` + rateWindow + `])))`,
+		labels: istioRPS.labels,
+	}
+)
+
+// ingress-nginx request metrics, per Ingress and per backend Service
+var (
+	nginxRPS = rateQuery{
+		query:  `sum by (namespace, ingress, service) (rate(nginx_ingress_controller_requests[` + rateWindow + `]))`,
+		labels: []string{"namespace", "ingress", "service"},
+	}
+	nginxErrors = rateQuery{
+		query:  `sum by (namespace, ingress, service) (rate(nginx_ingress_controller_requests{status=~"5.."}[` + rateWindow + `]))`,
+		labels: nginxRPS.labels,
+	}
+	nginxP99 = rateQuery{
+		query:  `histogram_quantile(0.99, sum by (namespace, ingress, service, le) (rate(nginx_ingress_controller_request_duration_seconds_bucket[` + rateWindow + `]))) * 1000`,
+		labels: nginxRPS.labels,
+	}
+)
+
+// Prometheus services checked, in order, when no URL is configured
+var prometheusServiceLocations = []struct {
+	namespace string
+	name      string
+}{
+	{"monitoring", "prometheus-operated"},
+	{"monitoring", "prometheus-k8s"},
+	{"monitoring", "prometheus-server"},
+	{"monitoring", "kube-prometheus-stack-prometheus"},
+	{"prometheus", "prometheus-server"},
+	{"istio-system", "prometheus"},
+	{"kube-system", "prometheus"},
+	{"default", "prometheus"},
+}
+
+// RateCollector periodically queries Prometheus for request rate, error rate and p99
+// latency per Service and Ingress, and serves the latest result to traffic topologies
+type RateCollector struct {
+	url        string // Configured Prometheus URL (empty = discover in the cluster)
+	interval   time.Duration
+	httpClient *http.Client
+
+	mu      sync.RWMutex
+	load    *topology.TrafficLoad
+	addr    string       // Resolved Prometheus address
+	client  *http.Client // Client for addr (authenticated when going through the API server)
+	context string       // Cluster context addr belongs to
+}
+
+// NewRateCollector creates a collector for the given Prometheus URL, or one discovered in
+// the cluster when empty
+func NewRateCollector(prometheusURL string, interval time.Duration) *RateCollector {
+	if interval <= 0 {
+		interval = DefaultRateInterval
+	}
+	return &RateCollector{
+		url:        strings.TrimSuffix(prometheusURL, "/"),
+		interval:   interval,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// TrafficLoad returns the latest collected load (nil before the first collection)
+func (c *RateCollector) TrafficLoad() *topology.TrafficLoad {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.load
+}
+
+// Start collects until ctx is done
+func (c *RateCollector) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+		for {
+			c.refresh(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// refresh collects the load and replaces the served one
+func (c *RateCollector) refresh(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, c.interval)
+	defer cancel()
+
+	load, err := c.collect(ctx)
+	if err != nil {
+		log.Printf("[traffic] Failed to collect traffic rates: %v", err)
+		load = &topology.TrafficLoad{UpdatedAt: time.Now(), Error: err.Error()}
+	}
+	c.mu.Lock()
+	c.load = load
+	c.mu.Unlock()
+}
+
+func (c *RateCollector) collect(ctx context.Context) (*topology.TrafficLoad, error) {
+	addr, client, err := c.address(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p := prometheus{addr: addr, client: client}
+	load := &topology.TrafficLoad{
+		Services:  make(map[string]topology.EdgeLoad),
+		Ingresses: make(map[string]topology.EdgeLoad),
+		Routes:    make(map[string]topology.EdgeLoad),
+		UpdatedAt: time.Now(),
+	}
+
+	istio, err := p.loads(ctx, istioRPS, istioErrors, istioP99)
+	if err != nil {
+		// Prometheus itself failed; drop the address so it's rediscovered
+		c.mu.Lock()
+		c.addr = ""
+		c.mu.Unlock()
+		return nil, err
+	}
+	for key, l := range istio {
+		load.Services[key] = l
+	}
+
+	// ingress-nginx: per route, summed per Ingress, and per Service when no mesh reports it
+	nginx, err := p.loads(ctx, nginxRPS, nginxErrors, nginxP99)
+	if err != nil {
+		return nil, err
+	}
+	ingressTotals := make(map[string][]topology.EdgeLoad)
+	serviceTotals := make(map[string][]topology.EdgeLoad)
+	for key, l := range nginx {
+		parts := strings.SplitN(key, "/", 3)
+		if len(parts) != 3 || parts[1] == "" {
+			continue
+		}
+		ingressTotals[parts[0]+"/"+parts[1]] = append(ingressTotals[parts[0]+"/"+parts[1]], l)
+		if parts[2] != "" {
+			load.Routes[key] = l
+			serviceTotals[parts[0]+"/"+parts[2]] = append(serviceTotals[parts[0]+"/"+parts[2]], l)
+		}
+	}
+	for key, loads := range ingressTotals {
+		load.Ingresses[key] = combine(loads)
+	}
+	for key, loads := range serviceTotals {
+		if _, ok := load.Services[key]; !ok {
+			load.Services[key] = combine(loads)
+		}
+	}
+
+	for key, l := range p.annotatedLoads(ctx) {
+		load.Services[key] = l
+	}
+	return load, nil
+}
+
+// loads runs request, error and latency queries and joins them by key
+func (p prometheus) loads(ctx context.Context, rps, failing, p99 rateQuery) (map[string]topology.EdgeLoad, error) {
+	requests, err := p.query(ctx, rps)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]topology.EdgeLoad, len(requests))
+	if len(requests) == 0 {
+		return out, nil
+	}
+	failures, err := p.query(ctx, failing)
+	if err != nil {
+		return nil, err
+	}
+	latencies, err := p.query(ctx, p99)
+	if err != nil {
+		return nil, err
+	}
+	for key, r := range requests {
+		out[key] = edgeLoad(r, failures[key], latencies, key)
+	}
+	return out, nil
+}
+
+// annotatedLoads queries the Services that carry their own PromQL in annotations
+func (p prometheus) annotatedLoads(ctx context.Context) map[string]topology.EdgeLoad {
+	out := make(map[string]topology.EdgeLoad)
+	cache := k8s.GetResourceCache()
+	if cache == nil {
+		return out
+	}
+	services, err := cache.Services().List(labels.Everything())
+	if err != nil {
+		return out
+	}
+	queried := 0
+	for _, svc := range services {
+		rpsQuery := svc.Annotations[RPSQueryAnnotation]
+		if rpsQuery == "" {
+			continue
+		}
+		if queried++; queried > maxAnnotatedServices {
+			log.Printf("[traffic] More than %d Services have %s, skipping the rest", maxAnnotatedServices, RPSQueryAnnotation)
+			break
+		}
+		key := svc.Namespace + "/" + svc.Name
+		scalar := func(q string) (float64, bool) {
+			if q == "" {
+				return 0, false
+			}
+			values, err := p.query(ctx, rateQuery{query: q})
+			if err != nil {
+				log.Printf("[traffic] Query from %s on Service %s failed: %v", RPSQueryAnnotation, key, err)
+				return 0, false
+			}
+			v, ok := values[""]
+			return v, ok
+		}
+		rps, ok := scalar(rpsQuery)
+		if !ok {
+			continue
+		}
+		failing, _ := scalar(svc.Annotations[ErrorsQueryAnnotation])
+		latencies := map[string]float64{}
+		if p99, ok := scalar(svc.Annotations[P99QueryAnnotation]); ok {
+			latencies[key] = p99
+		}
+		out[key] = edgeLoad(rps, failing, latencies, key)
+	}
+	return out
+}
+
+func edgeLoad(rps, failing float64, latencies map[string]float64, key string) topology.EdgeLoad {
+	l := topology.EdgeLoad{RPS: round(rps, 2)}
+	if rps > 0 {
+		l.ErrorPercent = round(math.Min(failing/rps, 1)*100, 2)
+	}
+	if p99, ok := latencies[key]; ok && !math.IsNaN(p99) && !math.IsInf(p99, 0) {
+		p99 = round(p99, 1)
+		l.P99Ms = &p99
+	}
+	return l
+}
+
+// combine sums request and error rates; the p99 is the worst of the parts
+func combine(loads []topology.EdgeLoad) topology.EdgeLoad {
+	var rps, failing float64
+	var p99 *float64
+	for _, l := range loads {
+		rps += l.RPS
+		failing += l.RPS * l.ErrorPercent / 100
+		if l.P99Ms != nil && (p99 == nil || *l.P99Ms > *p99) {
+			v := *l.P99Ms
+			p99 = &v
+		}
+	}
+	out := topology.EdgeLoad{RPS: round(rps, 2), P99Ms: p99}
+	if rps > 0 {
+		out.ErrorPercent = round(failing/rps*100, 2)
+	}
+	return out
+}
+
+func round(v float64, places int) float64 {
+	p := math.Pow(10, float64(places))
+	return math.Round(v*p) / p
+}
+
+// prometheus is a resolved Prometheus endpoint
+type prometheus struct {
+	addr   string
+	client *http.Client
+}
+
+// query runs an instant query and sums the samples per key
+func (p prometheus) query(ctx context.Context, q rateQuery) (map[string]float64, error) {
+	queryURL := fmt.Sprintf("%s/api/v1/query?query=%s", p.addr, url.QueryEscape(q.query))
+	req, err := http.NewRequestWithContext(ctx, "GET", queryURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("querying prometheus: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("prometheus returned status %d", resp.StatusCode)
+	}
+
+	var promResp prometheusResponse
+	if err := json.NewDecoder(resp.Body).Decode(&promResp); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+	if promResp.Status != "success" {
+		return nil, fmt.Errorf("prometheus query failed: %s", promResp.Status)
+	}
+
+	out := make(map[string]float64, len(promResp.Data.Result))
+	for _, result := range promResp.Data.Result {
+		if len(result.Value) < 2 {
+			continue
+		}
+		valStr, ok := result.Value[1].(string)
+		if !ok {
+			continue
+		}
+		val, err := strconv.ParseFloat(valStr, 64)
+		if err != nil || math.IsNaN(val) {
+			continue
+		}
+		parts := make([]string, len(q.labels))
+		for i, label := range q.labels {
+			parts[i] = result.Metric[label]
+		}
+		out[strings.Join(parts, "/")] += val
+	}
+	return out, nil
+}
+
+// address returns the configured Prometheus URL, or discovers one in the current cluster:
+// the Service's in-cluster address when reachable, else the API server's proxy to it
+func (c *RateCollector) address(ctx context.Context) (string, *http.Client, error) {
+	if c.url != "" {
+		return c.url, c.httpClient, nil
+	}
+	contextName := k8s.GetContextName()
+	c.mu.RLock()
+	addr, client, addrContext := c.addr, c.client, c.context
+	c.mu.RUnlock()
+	if addr != "" && addrContext == contextName {
+		return addr, client, nil
+	}
+
+	k8sClient, config := k8s.GetClient(), k8s.GetConfig()
+	if k8sClient == nil || config == nil {
+		return "", nil, fmt.Errorf("kubernetes client not initialized")
+	}
+	for _, loc := range prometheusServiceLocations {
+		svc, err := k8sClient.CoreV1().Services(loc.namespace).Get(ctx, loc.name, metav1.GetOptions{})
+		if err != nil {
+			continue
+		}
+		port := 9090
+		if len(svc.Spec.Ports) > 0 {
+			port = int(svc.Spec.Ports[0].Port)
+		}
+		addr, client = fmt.Sprintf("http://%s.%s.svc.cluster.local:%d", svc.Name, svc.Namespace, port), c.httpClient
+		if !reachable(ctx, client, addr) {
+			// Running outside the cluster: go through the API server's service proxy
+			proxyClient, err := rest.HTTPClientFor(config)
+			if err != nil {
+				return "", nil, fmt.Errorf("creating API server client: %w", err)
+			}
+			proxyClient.Timeout = c.httpClient.Timeout
+			addr = fmt.Sprintf("%s/api/v1/namespaces/%s/services/%s:%d/proxy",
+				strings.TrimSuffix(config.Host, "/"), svc.Namespace, svc.Name, port)
+			client = proxyClient
+			if !reachable(ctx, client, addr) {
+				log.Printf("[traffic] Prometheus %s/%s found but not reachable", svc.Namespace, svc.Name)
+				continue
+			}
+		}
+		log.Printf("[traffic] Collecting traffic rates from Prometheus %s/%s", svc.Namespace, svc.Name)
+		c.mu.Lock()
+		c.addr, c.client, c.context = addr, client, contextName
+		c.mu.Unlock()
+		return addr, client, nil
+	}
+	return "", nil, fmt.Errorf("no Prometheus service found (set --prometheus-url)")
+}
+
+func reachable(ctx context.Context, client *http.Client, addr string) bool {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", addr+"/api/v1/query?query=up", nil)
+	if err != nil {
+		return false
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode == http.StatusOK
+}
@@ -46,14 +46,20 @@ type Flow struct {
 	Destination Endpoint  `json:"destination"`
 	Protocol    string    `json:"protocol"` // tcp, udp, http, grpc
 	Port        int       `json:"port"`
-	L7Protocol  string    `json:"l7Protocol,omitempty"` // HTTP, gRPC, DNS (if L7 visibility)
+	L7Protocol  string    `json:"l7Protocol,omitempty"` // HTTP, gRPC, DNS, Kafka (if L7 visibility)
+	L7Type      string    `json:"l7Type,omitempty"`     // request, response, sample
 	HTTPMethod  string    `json:"httpMethod,omitempty"`
 	HTTPPath    string    `json:"httpPath,omitempty"`
 	HTTPStatus  int       `json:"httpStatus,omitempty"`
+	GRPCStatus  int       `json:"grpcStatus,omitempty"` // Non-zero is an error
+	DNSQuery    string    `json:"dnsQuery,omitempty"`
+	DNSError    string    `json:"dnsError,omitempty"`  // Response code other than NOERROR, e.g. NXDOMAIN
+	LatencyMs   float64   `json:"latencyMs,omitempty"` // L7 response latency
 	BytesSent   int64     `json:"bytesSent"`
 	BytesRecv   int64     `json:"bytesRecv"`
 	Connections int64     `json:"connections"`
-	Verdict     string    `json:"verdict"` // forwarded, dropped, error
+	Verdict     string    `json:"verdict"`              // forwarded, dropped, error
+	DropReason  string    `json:"dropReason,omitempty"` // Why a dropped flow was dropped, e.g. "policy denied"
 	LastSeen    time.Time `json:"lastSeen"`
 }
 
@@ -87,10 +93,13 @@ type AggregatedFlow struct {
 	BytesRecv   int64     `json:"bytesRecv"`
 	Connections int64     `json:"connections"`
 	LastSeen    time.Time `json:"lastSeen"`
-	// L7 stats (if available)
+	// L7 stats (if available), counted from responses
 	RequestCount int64   `json:"requestCount,omitempty"`
 	ErrorCount   int64   `json:"errorCount,omitempty"`
 	AvgLatencyMs float64 `json:"avgLatencyMs,omitempty"`
+	// Dropped flows (sources that report verdicts) and how often each drop reason occurred
+	DroppedCount int64            `json:"droppedCount,omitempty"`
+	DropReasons  map[string]int64 `json:"dropReasons,omitempty"`
 }
 
 // ClusterInfo contains cluster platform and CNI information
@@ -0,0 +1,408 @@
+// Package yamldiff compares Kubernetes manifests and values semantically. Documents are
+// matched by resource identity and compared field by field, so reordered keys or moved
+// documents don't show up as changes, and each changed document carries line hunks of
+// its normalized YAML for text views. Renderers turn a Result into unified, side-by-side
+// or JSON output.
+package yamldiff
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// ChangeType is how a document or field changed
+type ChangeType string
+
+const (
+	ChangeAdded    ChangeType = "added"
+	ChangeRemoved  ChangeType = "removed"
+	ChangeModified ChangeType = "modified"
+)
+
+// Result is the difference between two sets of YAML documents. Unchanged documents are
+// left out.
+type Result struct {
+	Documents []DocumentDiff `json:"documents"`
+	Added     int            `json:"added"`
+	Removed   int            `json:"removed"`
+	Modified  int            `json:"modified"`
+}
+
+// Empty reports whether nothing changed
+func (r *Result) Empty() bool {
+	return r == nil || len(r.Documents) == 0
+}
+
+// DocumentDiff is one document that was added, removed or modified
+type DocumentDiff struct {
+	Key       string        `json:"key"` // Kind/namespace/name, or the document's position when it has no identity
+	Kind      string        `json:"kind,omitempty"`
+	Namespace string        `json:"namespace,omitempty"`
+	Name      string        `json:"name,omitempty"`
+	Source    string        `json:"source,omitempty"` // Helm template path from the "# Source:" comment
+	Change    ChangeType    `json:"change"`
+	Fields    []FieldChange `json:"fields,omitempty"` // Only for modified documents that parsed
+	Hunks     []Hunk        `json:"hunks"`
+}
+
+// FieldChange is one changed leaf (or added/removed subtree) of a document
+type FieldChange struct {
+	Path   string     `json:"path"` // e.g. spec.template.spec.containers[name=app].image
+	Change ChangeType `json:"change"`
+	Old    any        `json:"old,omitempty"`
+	New    any        `json:"new,omitempty"`
+}
+
+// document is one parsed YAML document
+type document struct {
+	key, kind, namespace, name, source string
+	obj                                map[string]any // nil when the document didn't parse as a mapping
+	lines                              []string       // Normalized YAML, or the raw text when it didn't parse
+}
+
+// Compare diffs two multi-document YAML streams, such as Helm manifests. Documents are
+// paired by Kind/namespace/name; the result lists them in the order of newYAML, followed
+// by removed documents.
+func Compare(oldYAML, newYAML string) *Result {
+	return compareDocuments(splitDocuments(oldYAML), splitDocuments(newYAML))
+}
+
+// CompareObjects diffs two decoded objects, e.g. a live resource and its dry-run update,
+// or two sets of Helm values. Either may be nil for an added or removed object. An empty
+// key is derived from the object's kind, namespace and name.
+func CompareObjects(key string, oldObj, newObj map[string]any) *Result {
+	var olds, news []*document
+	if oldObj != nil {
+		olds = append(olds, objectDocument(key, oldObj))
+	}
+	if newObj != nil {
+		news = append(news, objectDocument(key, newObj))
+	}
+	return compareDocuments(olds, news)
+}
+
+func objectDocument(key string, obj map[string]any) *document {
+	doc := &document{obj: obj}
+	doc.identify(0)
+	if key != "" {
+		doc.key = key
+	}
+	doc.lines = marshalLines(obj)
+	return doc
+}
+
+func compareDocuments(olds, news []*document) *Result {
+	oldByKey := make(map[string]*document, len(olds))
+	for _, d := range olds {
+		oldByKey[d.key] = d
+	}
+
+	result := &Result{Documents: []DocumentDiff{}}
+	matched := make(map[string]bool, len(news))
+	for _, n := range news {
+		o := oldByKey[n.key]
+		if o == nil {
+			result.add(n.diffDocument(ChangeAdded, nil, diffLines(nil, n.lines)))
+			continue
+		}
+		matched[n.key] = true
+
+		var fields []FieldChange
+		if o.obj != nil && n.obj != nil {
+			diffValues("", o.obj, n.obj, &fields)
+			if len(fields) == 0 {
+				continue
+			}
+		}
+		lines := diffLines(o.lines, n.lines)
+		if len(fields) == 0 && !hasChanges(lines) {
+			continue
+		}
+		result.add(n.diffDocument(ChangeModified, fields, lines))
+	}
+	for _, o := range olds {
+		if !matched[o.key] {
+			result.add(o.diffDocument(ChangeRemoved, nil, diffLines(o.lines, nil)))
+		}
+	}
+	return result
+}
+
+func (r *Result) add(d DocumentDiff) {
+	r.Documents = append(r.Documents, d)
+	switch d.Change {
+	case ChangeAdded:
+		r.Added++
+	case ChangeRemoved:
+		r.Removed++
+	case ChangeModified:
+		r.Modified++
+	}
+}
+
+func (d *document) diffDocument(change ChangeType, fields []FieldChange, lines []Line) DocumentDiff {
+	return DocumentDiff{
+		Key:       d.key,
+		Kind:      d.kind,
+		Namespace: d.namespace,
+		Name:      d.name,
+		Source:    d.source,
+		Change:    change,
+		Fields:    fields,
+		Hunks:     buildHunks(lines, contextLines),
+	}
+}
+
+// splitDocuments splits a YAML stream on "---" separators and parses each document.
+// Documents holding only comments or whitespace are skipped.
+func splitDocuments(s string) []*document {
+	var docs []*document
+	seen := make(map[string]int)
+	var cur []string
+
+	flush := func() {
+		defer func() { cur = nil }()
+		text := strings.Join(cur, "\n")
+		if strings.TrimSpace(stripComments(text)) == "" {
+			return
+		}
+		doc := &document{source: sourceComment(cur)}
+		var obj map[string]any
+		if err := yaml.Unmarshal([]byte(text), &obj); err == nil && obj != nil {
+			doc.obj = obj
+			doc.lines = marshalLines(obj)
+		} else {
+			doc.lines = trimBlankLines(cur)
+		}
+		doc.identify(len(docs))
+
+		// Keep keys unique so a duplicated resource still pairs up in order
+		if n := seen[doc.key]; n > 0 {
+			doc.key = fmt.Sprintf("%s#%d", doc.key, n+1)
+		}
+		seen[doc.key]++
+		docs = append(docs, doc)
+	}
+
+	for _, line := range strings.Split(s, "\n") {
+		if trimmed := strings.TrimRight(line, " \t\r"); trimmed == "---" || strings.HasPrefix(trimmed, "--- ") {
+			flush()
+			continue
+		}
+		cur = append(cur, line)
+	}
+	flush()
+	return docs
+}
+
+// identify sets a document's key from its kind, namespace and name, falling back to its
+// Helm source path or its position in the stream
+func (d *document) identify(index int) {
+	if d.obj != nil {
+		d.kind, _ = d.obj["kind"].(string)
+		if meta, ok := d.obj["metadata"].(map[string]any); ok {
+			d.namespace, _ = meta["namespace"].(string)
+			d.name, _ = meta["name"].(string)
+		}
+	}
+	switch {
+	case d.kind != "" && d.name != "" && d.namespace != "":
+		d.key = d.kind + "/" + d.namespace + "/" + d.name
+	case d.kind != "" && d.name != "":
+		d.key = d.kind + "/" + d.name
+	case d.source != "":
+		d.key = d.source
+	default:
+		d.key = fmt.Sprintf("document %d", index+1)
+	}
+}
+
+// sourceComment returns the template path Helm records above each rendered document
+func sourceComment(lines []string) string {
+	for _, line := range lines {
+		if src, ok := strings.CutPrefix(strings.TrimSpace(line), "# Source:"); ok {
+			return strings.TrimSpace(src)
+		}
+	}
+	return ""
+}
+
+func stripComments(s string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(s, "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), "#") {
+			b.WriteString(line)
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+func trimBlankLines(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// marshalLines renders an object as YAML with sorted keys, so line hunks reflect content
+// rather than the key order of the source
+func marshalLines(obj map[string]any) []string {
+	out, err := yaml.Marshal(obj)
+	if err != nil {
+		return []string{fmt.Sprintf("%v", obj)}
+	}
+	return strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+}
+
+// diffValues appends the changes between two decoded YAML values under path
+func diffValues(path string, oldVal, newVal any, out *[]FieldChange) {
+	switch o := oldVal.(type) {
+	case map[string]any:
+		if n, ok := newVal.(map[string]any); ok {
+			diffMaps(path, o, n, out)
+			return
+		}
+	case []any:
+		if n, ok := newVal.([]any); ok {
+			diffLists(path, o, n, out)
+			return
+		}
+	}
+	if !reflect.DeepEqual(oldVal, newVal) {
+		*out = append(*out, FieldChange{Path: rootPath(path), Change: ChangeModified, Old: oldVal, New: newVal})
+	}
+}
+
+func diffMaps(path string, o, n map[string]any, out *[]FieldChange) {
+	keys := make([]string, 0, len(o)+len(n))
+	for k := range o {
+		keys = append(keys, k)
+	}
+	for k := range n {
+		if _, ok := o[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		p := joinKey(path, k)
+		ov, inOld := o[k]
+		nv, inNew := n[k]
+		switch {
+		case !inNew:
+			*out = append(*out, FieldChange{Path: p, Change: ChangeRemoved, Old: ov})
+		case !inOld:
+			*out = append(*out, FieldChange{Path: p, Change: ChangeAdded, New: nv})
+		default:
+			diffValues(p, ov, nv, out)
+		}
+	}
+}
+
+// diffLists pairs list items by their "name" field when every item has a unique one
+// (containers, env vars, ports, volumes), so inserting an item doesn't mark every later
+// one as changed. Other lists are compared by index.
+func diffLists(path string, o, n []any, out *[]FieldChange) {
+	oldNames, okOld := namedItems(o)
+	newNames, okNew := namedItems(n)
+	if okOld && okNew {
+		for i, item := range o {
+			name := itemName(item)
+			p := fmt.Sprintf("%s[name=%s]", path, name)
+			if j, ok := newNames[name]; ok {
+				diffValues(p, item, n[j], out)
+			} else {
+				*out = append(*out, FieldChange{Path: p, Change: ChangeRemoved, Old: o[i]})
+			}
+		}
+		for _, item := range n {
+			name := itemName(item)
+			if _, ok := oldNames[name]; !ok {
+				*out = append(*out, FieldChange{Path: fmt.Sprintf("%s[name=%s]", path, name), Change: ChangeAdded, New: item})
+			}
+		}
+		return
+	}
+
+	for i := 0; i < max(len(o), len(n)); i++ {
+		p := fmt.Sprintf("%s[%d]", path, i)
+		switch {
+		case i >= len(n):
+			*out = append(*out, FieldChange{Path: p, Change: ChangeRemoved, Old: o[i]})
+		case i >= len(o):
+			*out = append(*out, FieldChange{Path: p, Change: ChangeAdded, New: n[i]})
+		default:
+			diffValues(p, o[i], n[i], out)
+		}
+	}
+}
+
+// namedItems indexes list items by name, reporting false unless every item is a mapping
+// with a unique, non-empty name
+func namedItems(items []any) (map[string]int, bool) {
+	if len(items) == 0 {
+		return nil, true
+	}
+	byName := make(map[string]int, len(items))
+	for i, item := range items {
+		name := itemName(item)
+		if name == "" {
+			return nil, false
+		}
+		if _, dup := byName[name]; dup {
+			return nil, false
+		}
+		byName[name] = i
+	}
+	return byName, true
+}
+
+func itemName(item any) string {
+	m, ok := item.(map[string]any)
+	if !ok {
+		return ""
+	}
+	name, _ := m["name"].(string)
+	return name
+}
+
+// joinKey appends a map key to a path, quoting keys that aren't plain identifiers
+// (annotations and labels like app.kubernetes.io/name)
+func joinKey(path, key string) string {
+	if !isPlainKey(key) {
+		return fmt.Sprintf("%s[%q]", path, key)
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func isPlainKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, r := range key {
+		if !(r == '_' || r == '-' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// rootPath names the document root, for when a whole document changes type
+func rootPath(path string) string {
+	if path == "" {
+		return "."
+	}
+	return path
+}
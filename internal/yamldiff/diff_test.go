@@ -0,0 +1,221 @@
+package yamldiff
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const oldManifest = `---
+# Source: web/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: prod
+spec:
+  ports:
+  - port: 80
+---
+# Source: web/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: prod
+  annotations:
+    app.kubernetes.io/version: "1.0"
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+      - name: app
+        image: web:1.0
+      - name: proxy
+        image: envoy:1.30
+---
+# Source: web/templates/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web-config
+  namespace: prod
+data:
+  mode: old
+`
+
+// Same Service with keys reordered, Deployment changed, ConfigMap replaced by a Secret
+const newManifest = `---
+# Source: web/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  namespace: prod
+  name: web
+  annotations:
+    app.kubernetes.io/version: "1.1"
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+      - name: init-proxy
+        image: busybox
+      - name: app
+        image: web:1.1
+      - name: proxy
+        image: envoy:1.30
+---
+# Source: web/templates/service.yaml
+kind: Service
+apiVersion: v1
+metadata:
+  namespace: prod
+  name: web
+spec:
+  ports:
+  - port: 80
+---
+# Source: web/templates/secret.yaml
+apiVersion: v1
+kind: Secret
+metadata:
+  name: web-secret
+  namespace: prod
+`
+
+func TestCompare(t *testing.T) {
+	r := Compare(oldManifest, newManifest)
+
+	var keys []string
+	for _, d := range r.Documents {
+		keys = append(keys, string(d.Change)+" "+d.Key)
+	}
+	want := []string{"modified Deployment/prod/web", "added Secret/prod/web-secret", "removed ConfigMap/prod/web-config"}
+	if !reflect.DeepEqual(keys, want) {
+		t.Fatalf("documents = %v, want %v", keys, want)
+	}
+	if r.Added != 1 || r.Removed != 1 || r.Modified != 1 {
+		t.Errorf("stats = +%d -%d ~%d", r.Added, r.Removed, r.Modified)
+	}
+
+	deploy := r.Documents[0]
+	if deploy.Source != "web/templates/deployment.yaml" {
+		t.Errorf("source = %q", deploy.Source)
+	}
+	var paths []string
+	for _, f := range deploy.Fields {
+		paths = append(paths, string(f.Change)+" "+f.Path)
+	}
+	// Containers pair up by name, so inserting init-proxy doesn't shift app and proxy
+	wantPaths := []string{
+		`modified metadata.annotations["app.kubernetes.io/version"]`,
+		"modified spec.template.spec.containers[name=app].image",
+		"added spec.template.spec.containers[name=init-proxy]",
+	}
+	if !reflect.DeepEqual(paths, wantPaths) {
+		t.Errorf("fields = %v, want %v", paths, wantPaths)
+	}
+	if f := deploy.Fields[1]; f.Old != "web:1.0" || f.New != "web:1.1" {
+		t.Errorf("image change = %v -> %v", f.Old, f.New)
+	}
+}
+
+func TestCompareUnparseable(t *testing.T) {
+	r := Compare("a: [1\n", "a: [2\n")
+	if len(r.Documents) != 1 || r.Documents[0].Change != ChangeModified || r.Documents[0].Fields != nil {
+		t.Fatalf("expected a text-only modification, got %+v", r.Documents)
+	}
+	if r.Documents[0].Key != "document 1" {
+		t.Errorf("key = %q", r.Documents[0].Key)
+	}
+}
+
+func TestCompareObjects(t *testing.T) {
+	oldValues := map[string]any{"replicaCount": 1.0, "image": map[string]any{"tag": "1.0"}}
+	newValues := map[string]any{"replicaCount": 3.0, "image": map[string]any{"tag": "1.0"}}
+
+	r := CompareObjects("values.yaml", oldValues, newValues)
+	if len(r.Documents) != 1 || r.Documents[0].Key != "values.yaml" {
+		t.Fatalf("documents = %+v", r.Documents)
+	}
+	want := []FieldChange{{Path: "replicaCount", Change: ChangeModified, Old: 1.0, New: 3.0}}
+	if !reflect.DeepEqual(r.Documents[0].Fields, want) {
+		t.Errorf("fields = %+v, want %+v", r.Documents[0].Fields, want)
+	}
+
+	if r := CompareObjects("", oldValues, oldValues); !r.Empty() {
+		t.Errorf("identical objects should not differ: %+v", r.Documents)
+	}
+}
+
+func TestDiffLinesHunks(t *testing.T) {
+	var a, b []string
+	for i := 0; i < 20; i++ {
+		a = append(a, "line")
+	}
+	b = append(b, a...)
+	a[2], b[2] = "old-2", "new-2"
+	a[15] = "old-15"
+	b = append(b[:15], b[16:]...)
+
+	hunks := buildHunks(diffLines(a, b), contextLines)
+	if len(hunks) != 2 {
+		t.Fatalf("got %d hunks, want 2", len(hunks))
+	}
+	h := hunks[0]
+	if h.OldStart != 1 || h.OldLines != 6 || h.NewStart != 1 || h.NewLines != 6 {
+		t.Errorf("first hunk = -%d,%d +%d,%d", h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+	}
+	h = hunks[1]
+	if h.OldStart != 13 || h.OldLines != 7 || h.NewStart != 13 || h.NewLines != 6 {
+		t.Errorf("second hunk = -%d,%d +%d,%d", h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+	}
+}
+
+func TestRender(t *testing.T) {
+	r := Compare("kind: ConfigMap\nmetadata:\n  name: a\ndata:\n  k: v1\n", "kind: ConfigMap\nmetadata:\n  name: a\ndata:\n  k: v2\n")
+
+	unified, err := RenderString(FormatUnified, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"--- ConfigMap/a\n+++ ConfigMap/a\n", "-  k: v1\n", "+  k: v2\n"} {
+		if !strings.Contains(unified, want) {
+			t.Errorf("unified output missing %q:\n%s", want, unified)
+		}
+	}
+
+	sideBySide, err := RenderString(FormatSideBySide, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(sideBySide, "  k: v1"+strings.Repeat(" ", sideBySideWidth-7)+" |   k: v2\n") {
+		t.Errorf("side-by-side output:\n%s", sideBySide)
+	}
+
+	out, err := RenderString(FormatJSON, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Result
+	if err := json.Unmarshal([]byte(out), &decoded); err != nil || decoded.Modified != 1 {
+		t.Errorf("json output = %s (%v)", out, err)
+	}
+
+	if _, err := ParseFormat("html"); err == nil {
+		t.Error("expected unknown format to be rejected")
+	}
+	RegisterRenderer("keys", func(w io.Writer, r *Result) error { return nil })
+	t.Cleanup(func() {
+		renderersMu.Lock()
+		delete(renderers, "keys")
+		renderersMu.Unlock()
+	})
+	if f, err := ParseFormat("keys"); err != nil || f != "keys" {
+		t.Errorf("registered format not accepted: %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+package yamldiff
+
+// contextLines is how many unchanged lines surround each change in a hunk
+const contextLines = 3
+
+// LineType marks a line of a hunk as unchanged, added or removed
+type LineType string
+
+const (
+	LineContext LineType = "context"
+	LineAdded   LineType = "added"
+	LineRemoved LineType = "removed"
+)
+
+// Line is one line of a hunk. OldLine and NewLine are 1-based line numbers in the old and
+// new document; each is 0 when the line doesn't exist on that side.
+type Line struct {
+	Type    LineType `json:"type"`
+	Text    string   `json:"text"`
+	OldLine int      `json:"oldLine,omitempty"`
+	NewLine int      `json:"newLine,omitempty"`
+}
+
+// Hunk is a run of changed lines with surrounding context, as in a unified diff
+type Hunk struct {
+	OldStart int    `json:"oldStart"`
+	OldLines int    `json:"oldLines"`
+	NewStart int    `json:"newStart"`
+	NewLines int    `json:"newLines"`
+	Lines    []Line `json:"lines"`
+}
+
+// diffLines aligns two line slices into context, removed and added lines using their
+// longest common subsequence. The common prefix and suffix are matched directly so the
+// quadratic table only covers the region that changed.
+func diffLines(a, b []string) []Line {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]Line, 0, len(a)+len(b))
+	for i := 0; i < prefix; i++ {
+		lines = append(lines, Line{Type: LineContext, Text: a[i], OldLine: i + 1, NewLine: i + 1})
+	}
+
+	ma, mb := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	m, n := len(ma), len(mb)
+	// lcs[i][j] is the LCS length of ma[i:] and mb[j:]
+	lcs := make([][]int, m+1)
+	for i := range lcs {
+		lcs[i] = make([]int, n+1)
+	}
+	for i := m - 1; i >= 0; i-- {
+		for j := n - 1; j >= 0; j-- {
+			if ma[i] == mb[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	i, j := 0, 0
+	for i < m || j < n {
+		switch {
+		case i < m && j < n && ma[i] == mb[j]:
+			lines = append(lines, Line{Type: LineContext, Text: ma[i], OldLine: prefix + i + 1, NewLine: prefix + j + 1})
+			i++
+			j++
+		case j >= n || (i < m && lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, Line{Type: LineRemoved, Text: ma[i], OldLine: prefix + i + 1})
+			i++
+		default:
+			lines = append(lines, Line{Type: LineAdded, Text: mb[j], NewLine: prefix + j + 1})
+			j++
+		}
+	}
+
+	for k := 0; k < suffix; k++ {
+		lines = append(lines, Line{Type: LineContext, Text: a[len(a)-suffix+k], OldLine: len(a) - suffix + k + 1, NewLine: len(b) - suffix + k + 1})
+	}
+	return lines
+}
+
+func hasChanges(lines []Line) bool {
+	for _, l := range lines {
+		if l.Type != LineContext {
+			return true
+		}
+	}
+	return false
+}
+
+// buildHunks groups changed lines with up to context unchanged lines around them.
+// Changes separated by at most 2*context unchanged lines share a hunk.
+func buildHunks(lines []Line, context int) []Hunk {
+	hunks := []Hunk{}
+	for i := 0; i < len(lines); {
+		if lines[i].Type == LineContext {
+			i++
+			continue
+		}
+		start := max(0, i-context)
+		end := i
+		for end < len(lines) {
+			if lines[end].Type != LineContext {
+				end++
+				continue
+			}
+			// Look ahead: keep going if another change follows within 2*context lines
+			next := end
+			for next < len(lines) && lines[next].Type == LineContext && next-end < 2*context {
+				next++
+			}
+			if next < len(lines) && lines[next].Type != LineContext {
+				end = next
+				continue
+			}
+			end = min(len(lines), end+context)
+			break
+		}
+		hunks = append(hunks, newHunk(lines[start:end]))
+		i = end
+	}
+	return hunks
+}
+
+func newHunk(lines []Line) Hunk {
+	h := Hunk{Lines: lines}
+	for _, l := range lines {
+		if l.Type != LineAdded {
+			h.OldLines++
+			if h.OldStart == 0 {
+				h.OldStart = l.OldLine
+			}
+		}
+		if l.Type != LineRemoved {
+			h.NewLines++
+			if h.NewStart == 0 {
+				h.NewStart = l.NewLine
+			}
+		}
+	}
+	// An empty side starts at the line before the hunk, as in unified diff
+	if h.OldStart == 0 && len(lines) > 0 {
+		h.OldStart = max(0, lines[0].NewLine-1)
+	}
+	if h.NewStart == 0 && len(lines) > 0 {
+		h.NewStart = max(0, lines[0].OldLine-1)
+	}
+	return h
+}
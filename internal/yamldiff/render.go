@@ -0,0 +1,182 @@
+package yamldiff
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Format names a diff renderer
+type Format string
+
+const (
+	FormatUnified    Format = "unified"      // Unified diff text, one file header per document
+	FormatSideBySide Format = "side-by-side" // Two columns, like diff -y
+	FormatJSON       Format = "json"         // The structured Result
+)
+
+// sideBySideWidth is the width of each column in side-by-side output
+const sideBySideWidth = 60
+
+// Renderer writes a Result in one format
+type Renderer func(w io.Writer, r *Result) error
+
+var (
+	renderersMu sync.RWMutex
+	renderers   = map[Format]Renderer{
+		FormatUnified:    renderUnified,
+		FormatSideBySide: renderSideBySide,
+		FormatJSON:       renderJSON,
+	}
+)
+
+// RegisterRenderer adds or replaces the renderer for a format
+func RegisterRenderer(format Format, r Renderer) {
+	renderersMu.Lock()
+	renderers[format] = r
+	renderersMu.Unlock()
+}
+
+// ParseFormat validates a format name; empty means unified
+func ParseFormat(s string) (Format, error) {
+	if s == "" {
+		return FormatUnified, nil
+	}
+	renderersMu.RLock()
+	defer renderersMu.RUnlock()
+	if _, ok := renderers[Format(s)]; ok {
+		return Format(s), nil
+	}
+	names := make([]string, 0, len(renderers))
+	for f := range renderers {
+		names = append(names, string(f))
+	}
+	sort.Strings(names)
+	return "", fmt.Errorf("unsupported diff format %q (use %s)", s, strings.Join(names, ", "))
+}
+
+// Render writes r to w in the given format
+func Render(w io.Writer, format Format, r *Result) error {
+	renderersMu.RLock()
+	render, ok := renderers[format]
+	renderersMu.RUnlock()
+	if !ok {
+		return fmt.Errorf("unsupported diff format %q", format)
+	}
+	if r == nil {
+		r = &Result{Documents: []DocumentDiff{}}
+	}
+	return render(w, r)
+}
+
+// RenderString renders r to a string
+func RenderString(format Format, r *Result) (string, error) {
+	var buf bytes.Buffer
+	if err := Render(&buf, format, r); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func renderJSON(w io.Writer, r *Result) error {
+	return json.NewEncoder(w).Encode(r)
+}
+
+// renderUnified writes each document as a file in a unified diff, so standard diff
+// viewers color and fold it
+func renderUnified(w io.Writer, r *Result) error {
+	bw := bufio.NewWriter(w)
+	for _, d := range r.Documents {
+		oldName, newName := d.Key, d.Key
+		switch d.Change {
+		case ChangeAdded:
+			oldName = "/dev/null"
+		case ChangeRemoved:
+			newName = "/dev/null"
+		}
+		fmt.Fprintf(bw, "--- %s\n+++ %s\n", oldName, newName)
+		for _, h := range d.Hunks {
+			fmt.Fprintf(bw, "@@ -%d,%d +%d,%d @@\n", h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+			for _, l := range h.Lines {
+				bw.WriteString(linePrefix(l.Type))
+				bw.WriteString(l.Text)
+				bw.WriteByte('\n')
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+func linePrefix(t LineType) string {
+	switch t {
+	case LineAdded:
+		return "+"
+	case LineRemoved:
+		return "-"
+	default:
+		return " "
+	}
+}
+
+// renderSideBySide writes old and new lines in two columns. The gutter marks changed
+// lines with |, removed lines with < and added lines with >.
+func renderSideBySide(w io.Writer, r *Result) error {
+	bw := bufio.NewWriter(w)
+	row := func(left, gutter, right string) {
+		left = fitColumn(left)
+		left += strings.Repeat(" ", sideBySideWidth-utf8.RuneCountInString(left))
+		bw.WriteString(strings.TrimRight(left+" "+gutter+" "+fitColumn(right), " "))
+		bw.WriteByte('\n')
+	}
+	for _, d := range r.Documents {
+		fmt.Fprintf(bw, "=== %s (%s)\n", d.Key, d.Change)
+		for _, h := range d.Hunks {
+			fmt.Fprintf(bw, "@@ -%d,%d +%d,%d @@\n", h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+			// Pair each run of removed lines with the added lines that follow it
+			var removed, added []string
+			flush := func() {
+				for i := 0; i < max(len(removed), len(added)); i++ {
+					switch {
+					case i >= len(added):
+						row(removed[i], "<", "")
+					case i >= len(removed):
+						row("", ">", added[i])
+					default:
+						row(removed[i], "|", added[i])
+					}
+				}
+				removed, added = nil, nil
+			}
+			for _, l := range h.Lines {
+				switch l.Type {
+				case LineRemoved:
+					if len(added) > 0 {
+						flush()
+					}
+					removed = append(removed, l.Text)
+				case LineAdded:
+					added = append(added, l.Text)
+				default:
+					flush()
+					row(l.Text, " ", l.Text)
+				}
+			}
+			flush()
+		}
+	}
+	return bw.Flush()
+}
+
+// fitColumn truncates a line to the side-by-side column width
+func fitColumn(s string) string {
+	if utf8.RuneCountInString(s) <= sideBySideWidth {
+		return s
+	}
+	return string([]rune(s)[:sideBySideWidth-1]) + "…"
+}
@@ -0,0 +1,393 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// TopologyOptions selects a topology view
+type TopologyOptions struct {
+	Namespace         string
+	Traffic           bool // Traffic view instead of the full resource graph
+	NoNetworkPolicies bool
+}
+
+// Topology returns the resource graph
+func (c *Client) Topology(ctx context.Context, opts TopologyOptions) (*Topology, error) {
+	q := url.Values{}
+	setIf(q, "namespace", opts.Namespace)
+	if opts.Traffic {
+		q.Set("view", "traffic")
+	}
+	if opts.NoNetworkPolicies {
+		q.Set("networkPolicies", "false")
+	}
+	var topo Topology
+	if err := c.do(ctx, http.MethodGet, "/topology", q, nil, &topo); err != nil {
+		return nil, err
+	}
+	return &topo, nil
+}
+
+// Namespaces lists the cluster's namespaces
+func (c *Client) Namespaces(ctx context.Context) ([]NamespaceInfo, error) {
+	var namespaces []NamespaceInfo
+	return namespaces, c.do(ctx, http.MethodGet, "/namespaces", nil, nil, &namespaces)
+}
+
+// Capabilities returns the features available to the caller, with explanations for
+// unavailable ones
+func (c *Client) Capabilities(ctx context.Context) (*Capabilities, error) {
+	var caps Capabilities
+	if err := c.do(ctx, http.MethodGet, "/capabilities", nil, nil, &caps); err != nil {
+		return nil, err
+	}
+	return &caps, nil
+}
+
+// Resource is a resource with its topology relationships
+type Resource struct {
+	Object        *unstructured.Unstructured `json:"resource"`
+	Relationships *Relationships             `json:"relationships,omitempty"`
+}
+
+// ListResources lists a kind (plural, e.g. "deployments", or a CRD's plural) from Radar's
+// cache, in one namespace or all (namespace "")
+func (c *Client) ListResources(ctx context.Context, kind, namespace string) ([]unstructured.Unstructured, error) {
+	q := url.Values{}
+	setIf(q, "namespace", namespace)
+	var items []map[string]any
+	if err := c.do(ctx, http.MethodGet, "/resources/"+url.PathEscape(kind), q, nil, &items); err != nil {
+		return nil, err
+	}
+	objects := make([]unstructured.Unstructured, len(items))
+	for i, item := range items {
+		objects[i].Object = item
+	}
+	return objects, nil
+}
+
+// ResourceTable lists a custom resource kind with the columns its CRD declares for
+// kubectl get (namespace "" for all). group disambiguates kinds in several API groups.
+func (c *Client) ResourceTable(ctx context.Context, kind, group, namespace string) (*ResourceTable, error) {
+	q := url.Values{}
+	setIf(q, "namespace", namespace)
+	setIf(q, "group", group)
+	var table ResourceTable
+	if err := c.do(ctx, http.MethodGet, "/resources/"+url.PathEscape(kind)+"/table", q, nil, &table); err != nil {
+		return nil, err
+	}
+	return &table, nil
+}
+
+// GetResource returns one resource (namespace "" for cluster-scoped kinds). group
+// disambiguates CRDs whose plural exists in several API groups; it may be empty.
+func (c *Client) GetResource(ctx context.Context, kind, group, namespace, name string) (*Resource, error) {
+	q := url.Values{}
+	setIf(q, "group", group)
+	var res Resource
+	path := "/resources/" + url.PathEscape(kind) + "/" + namespacePath(namespace) + "/" + url.PathEscape(name)
+	if err := c.do(ctx, http.MethodGet, path, q, nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// RelatedResources returns a resource's related-resource graph: owners, transitive
+// children, the config and storage its pods use, what targets it, and recent events
+func (c *Client) RelatedResources(ctx context.Context, kind, group, namespace, name string) (*RelatedResources, error) {
+	q := url.Values{}
+	setIf(q, "group", group)
+	var rel RelatedResources
+	path := "/resources/" + url.PathEscape(kind) + "/" + namespacePath(namespace) + "/" + url.PathEscape(name) + "/related"
+	if err := c.do(ctx, http.MethodGet, path, q, nil, &rel); err != nil {
+		return nil, err
+	}
+	return &rel, nil
+}
+
+// DeleteResource deletes a resource (namespace "" for cluster-scoped kinds)
+func (c *Client) DeleteResource(ctx context.Context, kind, namespace, name string) error {
+	path := "/resources/" + url.PathEscape(kind) + "/" + namespacePath(namespace) + "/" + url.PathEscape(name)
+	return c.do(ctx, http.MethodDelete, path, nil, nil, nil)
+}
+
+// RestartWorkload starts a rolling restart of a Deployment, StatefulSet, DaemonSet or Rollout
+func (c *Client) RestartWorkload(ctx context.Context, kind, namespace, name string) error {
+	return c.do(ctx, http.MethodPost, workloadPath(kind, namespace, name)+"/restart", nil, nil, nil)
+}
+
+// ScaleWorkload sets a workload's replica count
+func (c *Client) ScaleWorkload(ctx context.Context, kind, namespace, name string, replicas int32) (*ScaleResponse, error) {
+	var resp ScaleResponse
+	if err := c.do(ctx, http.MethodPost, workloadPath(kind, namespace, name)+"/scale", nil, ScaleRequest{Replicas: &replicas}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// StartImageRollout previews (req.DryRun) or starts moving workloads to a new image
+func (c *Client) StartImageRollout(ctx context.Context, req ImageRolloutRequest) (*ImageRollout, error) {
+	var rollout ImageRollout
+	if err := c.do(ctx, http.MethodPost, "/image-rollouts", nil, req, &rollout); err != nil {
+		return nil, err
+	}
+	return &rollout, nil
+}
+
+// ImageRollout returns an image rollout's progress
+func (c *Client) ImageRollout(ctx context.Context, id string) (*ImageRollout, error) {
+	var rollout ImageRollout
+	if err := c.do(ctx, http.MethodGet, "/image-rollouts/"+url.PathEscape(id), nil, nil, &rollout); err != nil {
+		return nil, err
+	}
+	return &rollout, nil
+}
+
+// BulkPods previews (req.DryRun) or deletes or evicts the pods matching a selector
+func (c *Client) BulkPods(ctx context.Context, req BulkPodRequest) (*BulkPodResult, error) {
+	var result BulkPodResult
+	if err := c.do(ctx, http.MethodPost, "/pods/bulk", nil, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ChangesOptions filters timeline changes
+type ChangesOptions struct {
+	Namespace        string
+	Kind             string
+	Since            time.Time
+	Limit            int    // Server default 200, max 10000
+	Filter           string // Filter preset name (server default "default")
+	ExcludeK8sEvents bool
+	IncludeManaged   bool // Include changes to resources owned by other resources
+}
+
+// Changes returns timeline events, newest first
+func (c *Client) Changes(ctx context.Context, opts ChangesOptions) ([]TimelineEvent, error) {
+	q := url.Values{}
+	setIf(q, "namespace", opts.Namespace)
+	setIf(q, "kind", opts.Kind)
+	setIf(q, "filter", opts.Filter)
+	if !opts.Since.IsZero() {
+		q.Set("since", opts.Since.Format(time.RFC3339))
+	}
+	if opts.Limit > 0 {
+		q.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.ExcludeK8sEvents {
+		q.Set("include_k8s_events", "false")
+	}
+	if opts.IncludeManaged {
+		q.Set("include_managed", "true")
+	}
+	var events []TimelineEvent
+	return events, c.do(ctx, http.MethodGet, "/changes", q, nil, &events)
+}
+
+// CreateAnnotation posts a marker (deploy, incident note) to the timeline and returns the
+// recorded event. Tokens limited to namespaces must set the annotation's namespace.
+func (c *Client) CreateAnnotation(ctx context.Context, in AnnotationInput) (*TimelineEvent, error) {
+	q := url.Values{}
+	setIf(q, "namespace", in.Namespace)
+	var event TimelineEvent
+	if err := c.do(ctx, http.MethodPost, "/changes/annotations", q, in, &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// Annotations returns timeline markers in [since, until) (zero = unbounded), oldest first.
+// With a namespace, cluster-wide markers are included too.
+func (c *Client) Annotations(ctx context.Context, namespace string, since, until time.Time) ([]TimelineEvent, error) {
+	q := url.Values{}
+	setIf(q, "namespace", namespace)
+	setTime(q, "since", since)
+	setTime(q, "until", until)
+	var events []TimelineEvent
+	return events, c.do(ctx, http.MethodGet, "/changes/annotations", q, nil, &events)
+}
+
+// HeatmapQuery selects a change heatmap. Zero values use the server defaults (the last
+// 24 hours in 1-hour buckets).
+type HeatmapQuery struct {
+	Namespace    string
+	Kinds        []string
+	Since        time.Time
+	Until        time.Time
+	Bucket       time.Duration
+	Rows         int
+	NoisyPerHour float64
+}
+
+// ChangeHeatmap returns resource changes per namespace, kind and time bucket
+func (c *Client) ChangeHeatmap(ctx context.Context, query HeatmapQuery) (*ChangeHeatmap, error) {
+	q := url.Values{}
+	setIf(q, "namespace", query.Namespace)
+	setIf(q, "kinds", strings.Join(query.Kinds, ","))
+	setTime(q, "since", query.Since)
+	setTime(q, "until", query.Until)
+	if query.Bucket > 0 {
+		q.Set("bucket", query.Bucket.String())
+	}
+	if query.Rows > 0 {
+		q.Set("rows", strconv.Itoa(query.Rows))
+	}
+	if query.NoisyPerHour > 0 {
+		q.Set("noisyPerHour", strconv.FormatFloat(query.NoisyPerHour, 'f', -1, 64))
+	}
+	var heatmap ChangeHeatmap
+	if err := c.do(ctx, http.MethodGet, "/insights/changes", q, nil, &heatmap); err != nil {
+		return nil, err
+	}
+	return &heatmap, nil
+}
+
+// IncidentReport returns MTTD/MTTR for incidents that started in [since, until) (zero
+// values use the last 30 days), with the individual incidents when withIncidents is set
+func (c *Client) IncidentReport(ctx context.Context, namespace string, since, until time.Time, withIncidents bool) (*IncidentReport, error) {
+	q := url.Values{}
+	setIf(q, "namespace", namespace)
+	setTime(q, "since", since)
+	setTime(q, "until", until)
+	if withIncidents {
+		q.Set("incidents", "true")
+	}
+	var report IncidentReport
+	if err := c.do(ctx, http.MethodGet, "/insights/incidents", q, nil, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// Forecasts returns quota and PVC exhaustion forecasts, limited to those reaching their
+// limit within horizon when it's non-zero
+func (c *Client) Forecasts(ctx context.Context, namespace string, horizon time.Duration) ([]UsageForecast, error) {
+	q := url.Values{}
+	setIf(q, "namespace", namespace)
+	if horizon > 0 {
+		q.Set("horizon", horizon.String())
+	}
+	var resp ForecastsResponse
+	return resp.Forecasts, c.do(ctx, http.MethodGet, "/insights/forecasts", q, nil, &resp)
+}
+
+// Rightsizing compares workloads' container usage over window (0 = the server's default)
+// with their requests and limits, and suggests requests
+func (c *Client) Rightsizing(ctx context.Context, namespace string, window time.Duration) (*RightsizingReport, error) {
+	q := url.Values{}
+	setIf(q, "namespace", namespace)
+	if window > 0 {
+		q.Set("window", window.String())
+	}
+	var report RightsizingReport
+	if err := c.do(ctx, http.MethodGet, "/rightsizing", q, nil, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// PodScheduling explains which nodes a pod can run on and why the others are ruled out
+func (c *Client) PodScheduling(ctx context.Context, namespace, name string) (*SchedulingReport, error) {
+	var report SchedulingReport
+	path := "/pods/" + url.PathEscape(namespace) + "/" + url.PathEscape(name) + "/scheduling"
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// HelmReleases lists Helm releases in one namespace or all (namespace "")
+func (c *Client) HelmReleases(ctx context.Context, namespace string) ([]HelmRelease, error) {
+	q := url.Values{}
+	setIf(q, "namespace", namespace)
+	var releases []HelmRelease
+	return releases, c.do(ctx, http.MethodGet, "/helm/releases", q, nil, &releases)
+}
+
+// HelmRelease returns a release with its history, hooks and owned resources
+func (c *Client) HelmRelease(ctx context.Context, namespace, name string) (*HelmReleaseDetail, error) {
+	var release HelmReleaseDetail
+	if err := c.do(ctx, http.MethodGet, helmReleasePath(namespace, name), nil, nil, &release); err != nil {
+		return nil, err
+	}
+	return &release, nil
+}
+
+// HelmManifest returns a release's rendered manifest, at revision when it's non-zero
+func (c *Client) HelmManifest(ctx context.Context, namespace, name string, revision int) (string, error) {
+	q := url.Values{}
+	if revision > 0 {
+		q.Set("revision", strconv.Itoa(revision))
+	}
+	var manifest string
+	return manifest, c.do(ctx, http.MethodGet, helmReleasePath(namespace, name)+"/manifest", q, nil, &manifest)
+}
+
+// HelmValues returns a release's user-supplied values, plus computed values when all is set
+func (c *Client) HelmValues(ctx context.Context, namespace, name string, all bool) (*HelmValues, error) {
+	q := url.Values{}
+	if all {
+		q.Set("all", "true")
+	}
+	var values HelmValues
+	if err := c.do(ctx, http.MethodGet, helmReleasePath(namespace, name)+"/values", q, nil, &values); err != nil {
+		return nil, err
+	}
+	return &values, nil
+}
+
+// HelmDiff returns the manifest diff between two revisions of a release
+func (c *Client) HelmDiff(ctx context.Context, namespace, name string, revision1, revision2 int) (*ManifestDiff, error) {
+	q := url.Values{}
+	q.Set("revision1", strconv.Itoa(revision1))
+	q.Set("revision2", strconv.Itoa(revision2))
+	var diff ManifestDiff
+	if err := c.do(ctx, http.MethodGet, helmReleasePath(namespace, name)+"/diff", q, nil, &diff); err != nil {
+		return nil, err
+	}
+	return &diff, nil
+}
+
+// HelmUpgradeInfo reports whether a newer chart version is available for a release
+func (c *Client) HelmUpgradeInfo(ctx context.Context, namespace, name string) (*UpgradeInfo, error) {
+	var info UpgradeInfo
+	if err := c.do(ctx, http.MethodGet, helmReleasePath(namespace, name)+"/upgrade-info", nil, nil, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// HelmRollback rolls a release back to a revision
+func (c *Client) HelmRollback(ctx context.Context, namespace, name string, revision int) error {
+	q := url.Values{}
+	q.Set("revision", strconv.Itoa(revision))
+	return c.do(ctx, http.MethodPost, helmReleasePath(namespace, name)+"/rollback", q, nil, nil)
+}
+
+func workloadPath(kind, namespace, name string) string {
+	return "/workloads/" + url.PathEscape(kind) + "/" + url.PathEscape(namespace) + "/" + url.PathEscape(name)
+}
+
+func helmReleasePath(namespace, name string) string {
+	return "/helm/releases/" + url.PathEscape(namespace) + "/" + url.PathEscape(name)
+}
+
+func setIf(q url.Values, key, value string) {
+	if value != "" {
+		q.Set(key, value)
+	}
+}
+
+func setTime(q url.Values, key string, t time.Time) {
+	if !t.IsZero() {
+		q.Set(key, t.Format(time.RFC3339))
+	}
+}
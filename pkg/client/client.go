@@ -0,0 +1,156 @@
+// Package client is a typed Go client for Radar's HTTP API, for tools and tests that
+// program against a running Radar server. Request and response types are the ones the
+// server serializes, re-exported in types.go.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	explorerErrors "github.com/skyhook-io/radar/internal/errors"
+)
+
+// DefaultBaseURL is where a local Radar server listens by default
+const DefaultBaseURL = "http://localhost:9280"
+
+// Client calls a Radar server's API
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithToken authenticates requests with an API token (Authorization: Bearer)
+func WithToken(token string) Option {
+	return func(c *Client) { c.token = token }
+}
+
+// WithHTTPClient replaces the default HTTP client. Streams ignore its Timeout, since
+// they end when their context is cancelled.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// New returns a client for the Radar server at baseURL (e.g. DefaultBaseURL)
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Error is a non-2xx API response
+type Error struct {
+	StatusCode    int
+	Message       string
+	Code          string // Radar error code, e.g. "K8S_RESOURCE_NOT_FOUND"
+	Hint          string
+	CorrelationID string
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("radar: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("radar: %d: %s", e.StatusCode, e.Message)
+}
+
+// IsNotFound reports whether err is a 404 from the API
+func IsNotFound(err error) bool {
+	e, ok := err.(*Error)
+	return ok && e.StatusCode == http.StatusNotFound
+}
+
+// newRequest builds a request for an API path (relative to /api) with query parameters
+func (c *Client) newRequest(ctx context.Context, method, path string, query url.Values, body any) (*http.Request, error) {
+	endpoint := c.baseURL + "/api" + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	return req, nil
+}
+
+// do sends a request and decodes a JSON response into out (skipped when out is nil)
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	req, err := c.newRequest(ctx, method, path, query, body)
+	if err != nil {
+		return err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(resp); err != nil {
+		return err
+	}
+	if out == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if s, ok := out.(*string); ok {
+		data, err := io.ReadAll(resp.Body)
+		*s = string(data)
+		return err
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("radar: decoding %s %s: %w", method, path, err)
+	}
+	return nil
+}
+
+// checkResponse turns an error status into an *Error, reading Radar's error body
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode < 300 {
+		return nil
+	}
+	apiErr := &Error{StatusCode: resp.StatusCode}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	var body explorerErrors.APIError
+	if json.Unmarshal(data, &body) == nil && body.Error != "" {
+		apiErr.Message, apiErr.Code, apiErr.Hint, apiErr.CorrelationID = body.Error, body.Code, body.Hint, body.CorrelationID
+	} else {
+		apiErr.Message = strings.TrimSpace(string(data))
+	}
+	return apiErr
+}
+
+// namespacePath returns the path segment for a namespace, "_" for cluster-scoped resources
+func namespacePath(namespace string) string {
+	if namespace == "" {
+		return "_"
+	}
+	return url.PathEscape(namespace)
+}
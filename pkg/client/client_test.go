@@ -0,0 +1,147 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDecodesResponsesAndSendsToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization = %q", got)
+		}
+		switch r.URL.Path {
+		case "/api/namespaces":
+			json.NewEncoder(w).Encode([]NamespaceInfo{{Name: "default", Status: "Active"}})
+		case "/api/workloads/deployments/shop/api/scale":
+			var req ScaleRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Replicas == nil || *req.Replicas != 3 {
+				t.Errorf("scale body = %+v, %v", req, err)
+			}
+			json.NewEncoder(w).Encode(ScaleResponse{Message: "scaled", PreviousReplicas: 1, Replicas: 3})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	c := New(srv.URL, WithToken("secret"))
+
+	namespaces, err := c.Namespaces(context.Background())
+	if err != nil || len(namespaces) != 1 || namespaces[0].Name != "default" {
+		t.Fatalf("Namespaces = %+v, %v", namespaces, err)
+	}
+	scaled, err := c.ScaleWorkload(context.Background(), "deployments", "shop", "api", 3)
+	if err != nil || scaled.PreviousReplicas != 1 || scaled.Replicas != 3 {
+		t.Fatalf("ScaleWorkload = %+v, %v", scaled, err)
+	}
+}
+
+func TestAPIErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/helm/releases" {
+			http.Error(w, "helm unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":"deployment not found","code":"K8S_RESOURCE_NOT_FOUND","hint":"check the namespace","status":404}`))
+	}))
+	defer srv.Close()
+	c := New(srv.URL)
+
+	_, err := c.GetResource(context.Background(), "deployments", "", "shop", "missing")
+	if !IsNotFound(err) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	apiErr := err.(*Error)
+	if apiErr.Message != "deployment not found" || apiErr.Code != "K8S_RESOURCE_NOT_FOUND" || apiErr.Hint != "check the namespace" {
+		t.Errorf("error = %+v", apiErr)
+	}
+
+	_, err = c.HelmReleases(context.Background(), "")
+	if apiErr, ok := err.(*Error); !ok || apiErr.StatusCode != http.StatusServiceUnavailable || apiErr.Message != "helm unavailable" {
+		t.Errorf("plain-text error = %#v", err)
+	}
+}
+
+func TestClusterScopedPath(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/resources/nodes/_/node-1" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"resource":{"kind":"Node","metadata":{"name":"node-1"}}}`))
+	}))
+	defer srv.Close()
+
+	res, err := New(srv.URL).GetResource(context.Background(), "nodes", "", "", "node-1")
+	if err != nil || res.Object.GetName() != "node-1" {
+		t.Fatalf("GetResource = %+v, %v", res, err)
+	}
+}
+
+func TestStreamPodLogs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("container") != "app" || r.URL.Query().Get("tailLines") != "10" {
+			t.Errorf("query = %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("event: connected\ndata: {}\n\n" +
+			": keepalive\n\n" +
+			"event: log\ndata: {\"timestamp\":\"t1\",\"content\":\"hello\",\"container\":\"app\"}\n\n" +
+			"event: log\ndata: {\"timestamp\":\"t2\",\"content\":\"world\",\"container\":\"app\"}\n\n" +
+			"event: end\ndata: {\"reason\":\"stream ended\"}\n\n" +
+			"event: log\ndata: {\"content\":\"after end\"}\n\n"))
+	}))
+	defer srv.Close()
+
+	var lines []string
+	err := New(srv.URL).StreamPodLogs(context.Background(), "shop", "api-0", LogStreamOptions{Container: "app", TailLines: 10}, func(l LogLine) error {
+		lines = append(lines, l.Content)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 2 || lines[0] != "hello" || lines[1] != "world" {
+		t.Errorf("lines = %v", lines)
+	}
+}
+
+func TestStreamStopAndServerError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/events/stream" {
+			if q := r.URL.Query(); q.Has("kinds") &&
+				(q.Get("kinds") != "Pod,Deployment" || q.Get("selector") != "app=web" || q.Get("health") != "transitions" || q.Get("topology") != "false") {
+				t.Errorf("filter query = %s", r.URL.RawQuery)
+			}
+			w.Write([]byte("event: heartbeat\ndata: {}\n\nevent: topology\ndata: {\"nodes\":[]}\n\n"))
+			return
+		}
+		w.Write([]byte("event: error\ndata: {\"error\":\"Failed to open log stream\"}\n\n"))
+	}))
+	defer srv.Close()
+	c := New(srv.URL)
+
+	seen := 0
+	err := c.WatchEvents(context.Background(), "", "", func(e StreamEvent) error {
+		seen++
+		return ErrStop
+	})
+	if err != nil || seen != 1 {
+		t.Errorf("WatchEvents stopped after %d events, err %v", seen, err)
+	}
+
+	filter := EventFilter{Kinds: []string{"Pod", "Deployment"}, Selector: "app=web", HealthTransitions: true, NoTopology: true}
+	err = c.WatchFilteredEvents(context.Background(), "", "", filter, func(e StreamEvent) error { return ErrStop })
+	if err != nil {
+		t.Errorf("WatchFilteredEvents: %v", err)
+	}
+
+	err = c.StreamPodLogs(context.Background(), "shop", "api-0", LogStreamOptions{}, func(LogLine) error { return nil })
+	if apiErr, ok := err.(*Error); !ok || apiErr.Message != "Failed to open log stream" {
+		t.Errorf("StreamPodLogs error = %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// StreamEvent is one server-sent event
+type StreamEvent struct {
+	Type string // SSE event name, e.g. "topology", "k8s_event", "log"
+	Data []byte // JSON payload
+}
+
+// Decode unmarshals the event's payload into out
+func (e StreamEvent) Decode(out any) error {
+	if err := json.Unmarshal(e.Data, out); err != nil {
+		return fmt.Errorf("radar: decoding %s event: %w", e.Type, err)
+	}
+	return nil
+}
+
+// ErrStop can be returned by a stream handler to end the stream without an error
+var ErrStop = errors.New("radar: stop stream")
+
+// stream opens an SSE endpoint and calls fn for each event until the context is
+// cancelled, the server closes the stream, or fn returns an error
+func (c *Client) stream(ctx context.Context, path string, query url.Values, fn func(StreamEvent) error) error {
+	req, err := c.newRequest(ctx, http.MethodGet, path, query, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	// Streams are long-lived; only the context bounds them
+	hc := *c.httpClient
+	hc.Timeout = 0
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(resp); err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 16<<20) // topology snapshots can be large
+	var event StreamEvent
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if len(data) > 0 {
+				event.Data = []byte(strings.Join(data, "\n"))
+				if event.Type == "" {
+					event.Type = "message"
+				}
+				if err := fn(event); err != nil {
+					if errors.Is(err, ErrStop) {
+						return nil
+					}
+					return err
+				}
+			}
+			event, data = StreamEvent{}, nil
+		case strings.HasPrefix(line, ":"):
+			// Comment / keepalive
+		case strings.HasPrefix(line, "event:"):
+			event.Type = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		return err
+	}
+	return nil
+}
+
+// WatchEvents streams topology snapshots and resource events for a namespace ("" for
+// all) and view ("", "traffic"). Event types are "topology", "k8s_event", "heartbeat",
+// "context_switch_progress", "context_changed" and "shutdown".
+func (c *Client) WatchEvents(ctx context.Context, namespace, view string, fn func(StreamEvent) error) error {
+	q := url.Values{}
+	setIf(q, "namespace", namespace)
+	setIf(q, "view", view)
+	return c.stream(ctx, "/events/stream", q, fn)
+}
+
+// EventFilter narrows an event stream on the server, so filtered-out changes never
+// reach the client. Kinds, namespaces and the selector apply to k8s_event changes.
+type EventFilter struct {
+	Kinds             []string // Resource kinds, Kind.group for custom resources
+	Namespaces        []string
+	Selector          string // Label selector; drops K8s Events, which have no labels
+	HealthTransitions bool   // Only changes that move a resource's health state
+	NoTopology        bool   // Skip topology snapshots
+	TopologyDeltas    bool   // After the first snapshot, send "topology_delta" events (TopologyDelta)
+}
+
+// WatchFilteredEvents is WatchEvents with server-side filters. The first event is a
+// "subscription" echoing the filter the server applied.
+func (c *Client) WatchFilteredEvents(ctx context.Context, namespace, view string, filter EventFilter, fn func(StreamEvent) error) error {
+	q := url.Values{}
+	setIf(q, "namespace", namespace)
+	setIf(q, "view", view)
+	setIf(q, "kinds", strings.Join(filter.Kinds, ","))
+	setIf(q, "namespaces", strings.Join(filter.Namespaces, ","))
+	setIf(q, "selector", filter.Selector)
+	if filter.HealthTransitions {
+		q.Set("health", "transitions")
+	}
+	if filter.NoTopology {
+		q.Set("topology", "false")
+	} else if filter.TopologyDeltas {
+		q.Set("topology", "delta")
+	}
+	return c.stream(ctx, "/events/stream", q, fn)
+}
+
+// LogLine is one line from a pod log stream
+type LogLine struct {
+	Timestamp string `json:"timestamp"`
+	Content   string `json:"content"`
+	Container string `json:"container"`
+}
+
+// LogStreamOptions selects a pod log stream
+type LogStreamOptions struct {
+	Container string
+	TailLines int64 // Lines of history before following; server default when 0
+	Previous  bool  // Logs of the previous container instance
+}
+
+// StreamPodLogs follows a pod's logs, calling fn for each line until the stream ends or
+// fn returns an error. An "error" event from the server is returned as an *Error.
+func (c *Client) StreamPodLogs(ctx context.Context, namespace, name string, opts LogStreamOptions, fn func(LogLine) error) error {
+	q := url.Values{}
+	setIf(q, "container", opts.Container)
+	if opts.TailLines > 0 {
+		q.Set("tailLines", strconv.FormatInt(opts.TailLines, 10))
+	}
+	if opts.Previous {
+		q.Set("previous", "true")
+	}
+	path := "/pods/" + url.PathEscape(namespace) + "/" + url.PathEscape(name) + "/logs/stream"
+	return c.stream(ctx, path, q, func(e StreamEvent) error {
+		switch e.Type {
+		case "log":
+			var line LogLine
+			if err := e.Decode(&line); err != nil {
+				return err
+			}
+			return fn(line)
+		case "error":
+			var body struct {
+				Error string `json:"error"`
+			}
+			_ = e.Decode(&body)
+			return &Error{StatusCode: http.StatusBadGateway, Message: body.Error}
+		case "end":
+			return ErrStop
+		}
+		return nil
+	})
+}
@@ -0,0 +1,61 @@
+package client
+
+import (
+	"github.com/skyhook-io/radar/internal/helm"
+	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/rightsizing"
+	"github.com/skyhook-io/radar/internal/scheduling"
+	"github.com/skyhook-io/radar/internal/server"
+	"github.com/skyhook-io/radar/internal/timeline"
+	"github.com/skyhook-io/radar/internal/topology"
+)
+
+// Types shared with the server: each is the struct the server encodes or decodes, so the
+// client can't drift from the API
+
+// Topology
+type (
+	Topology      = topology.Topology
+	Node          = topology.Node
+	Edge          = topology.Edge
+	Relationships = topology.Relationships
+	TopologyDelta = topology.Delta
+
+	RelatedResources = topology.RelatedResources
+	ResourceTable    = k8s.ResourceTable
+)
+
+// Timeline and insights
+type (
+	TimelineEvent   = timeline.TimelineEvent
+	ChangeHeatmap   = timeline.ChangeHeatmap
+	IncidentReport  = timeline.IncidentReport
+	UsageForecast   = k8s.UsageForecast
+	AnnotationInput = timeline.AnnotationInput
+	AnnotationLink  = timeline.AnnotationLink
+
+	RightsizingReport = rightsizing.Report
+	SchedulingReport  = scheduling.Report
+)
+
+// Helm
+type (
+	HelmRelease       = helm.HelmRelease
+	HelmReleaseDetail = helm.HelmReleaseDetail
+	HelmValues        = helm.HelmValues
+	ManifestDiff      = helm.ManifestDiff
+	UpgradeInfo       = helm.UpgradeInfo
+)
+
+// Cluster and workloads
+type (
+	Capabilities        = k8s.Capabilities
+	NamespaceInfo       = server.NamespaceInfo
+	ForecastsResponse   = server.ForecastsResponse
+	ScaleRequest        = server.ScaleRequest
+	ScaleResponse       = server.ScaleResponse
+	ImageRollout        = server.ImageRollout
+	ImageRolloutRequest = server.ImageRolloutRequest
+	BulkPodRequest      = server.BulkPodRequest
+	BulkPodResult       = server.BulkPodResult
+)
@@ -19,7 +19,7 @@ import { ContextSwitchProvider, useContextSwitch } from './context/ContextSwitch
 import { CapabilitiesProvider } from './contexts/CapabilitiesContext'
 import { ErrorBoundary } from './components/ui/ErrorBoundary'
 import { useEventSource } from './hooks/useEventSource'
-import { useNamespaces } from './api/client'
+import { useNamespaces, useWatchNamespaces, useSetWatchNamespaces } from './api/client'
 import { Loader2 } from 'lucide-react'
 import { ChevronDown, RefreshCw, FolderTree, Network, List, Clock, Package, Sun, Moon, Activity, Home } from 'lucide-react'
 import { useTheme } from './context/ThemeContext'
@@ -28,13 +28,13 @@ import type { TopologyNode, GroupingMode, MainView, SelectedResource, SelectedHe
 // All possible node kinds
 const ALL_NODE_KINDS: NodeKind[] = [
   'Internet', 'Ingress', 'Service', 'Deployment', 'DaemonSet', 'StatefulSet',
-  'ReplicaSet', 'Pod', 'PodGroup', 'ConfigMap', 'Secret', 'HPA', 'Job', 'CronJob', 'PVC', 'Namespace'
+  'ReplicaSet', 'Pod', 'PodGroup', 'ConfigMap', 'Secret', 'HPA', 'Job', 'CronJob', 'PVC', 'Namespace', 'NetworkPolicy'
 ]
 
 // Default visible kinds (ReplicaSet hidden by default - noisy intermediate object)
 const DEFAULT_VISIBLE_KINDS: NodeKind[] = [
   'Internet', 'Ingress', 'Service', 'Deployment', 'Rollout', 'DaemonSet', 'StatefulSet',
-  'Pod', 'PodGroup', 'ConfigMap', 'Secret', 'HPA', 'Job', 'CronJob', 'PVC', 'Namespace'
+  'Pod', 'PodGroup', 'ConfigMap', 'Secret', 'HPA', 'Job', 'CronJob', 'PVC', 'Namespace', 'NetworkPolicy'
 ]
 
 // Convert node kind to plural API resource name
@@ -56,6 +56,7 @@ function kindToApiResource(kind: NodeKind): string {
     'CronJob': 'cronjobs',
     'PVC': 'persistentvolumeclaims',
     'Namespace': 'namespaces',
+    'NetworkPolicy': 'networkpolicies',
   }
   return kindMap[kind] || kind.toLowerCase() + 's'
 }
@@ -210,6 +211,19 @@ function AppInner() {
   // Fetch cluster info and namespaces
   const { data: namespaces } = useNamespaces()
 
+  // With --namespaces the server only watches some namespaces. Follow the filter: watch the
+  // selected namespace in addition to the pinned ones, and stop watching it when deselected.
+  const { data: watchScope } = useWatchNamespaces()
+  const setWatchNamespaces = useSetWatchNamespaces()
+  useEffect(() => {
+    if (!watchScope?.scoped || setWatchNamespaces.isPending) return
+    const want = new Set(watchScope.pinned ?? [])
+    if (namespace) want.add(namespace)
+    const current = watchScope.namespaces ?? []
+    if (want.size === 0 || (want.size === current.length && current.every((ns) => want.has(ns)))) return
+    setWatchNamespaces.mutate([...want])
+  }, [namespace, watchScope])
+
   // Context switch state
   const { isSwitching, targetContext, progressMessage, updateProgress, endSwitch } = useContextSwitch()
 
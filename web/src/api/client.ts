@@ -10,9 +10,13 @@ import type {
   ResourceWithRelationships,
   HelmRelease,
   HelmReleaseDetail,
+  HelmReleaseTopology,
   HelmValues,
+  HelmValueError,
   ManifestDiff,
   UpgradeInfo,
+  UpgradePreview,
+  RegistryLoginResult,
   BatchUpgradeInfo,
   ValuesPreviewResponse,
   HelmRepository,
@@ -21,15 +25,55 @@ import type {
   InstallChartRequest,
   ArtifactHubSearchResult,
   ArtifactHubChartDetail,
+  UpdatePreview,
+  SecretDetail,
+  SecretValue,
 } from '../types'
 
 const API_BASE = '/api'
 
+// Error body returned by every API endpoint. Branch on `code`, which is stable;
+// `error` is a display message.
+export interface ApiErrorBody {
+  error?: string
+  code?: string
+  status?: number
+  hint?: string
+  correlationId?: string
+  details?: Record<string, unknown>
+}
+
+export class ApiError extends Error {
+  status: number
+  code?: string
+  hint?: string
+  correlationId?: string
+  details?: Record<string, unknown>
+
+  constructor(status: number, body: ApiErrorBody) {
+    super(body.error || `HTTP ${status}`)
+    this.name = 'ApiError'
+    this.status = status
+    this.code = body.code
+    this.hint = body.hint
+    this.correlationId = body.correlationId
+    this.details = body.details
+  }
+}
+
+// Field-level values schema violations from a Helm install, upgrade or values change
+export function getHelmValueErrors(err: unknown): HelmValueError[] {
+  if (err instanceof ApiError && Array.isArray(err.details?.fields)) {
+    return err.details.fields as HelmValueError[]
+  }
+  return []
+}
+
 async function fetchJSON<T>(path: string): Promise<T> {
   const response = await fetch(`${API_BASE}${path}`)
   if (!response.ok) {
     const error = await response.json().catch(() => ({ error: 'Unknown error' }))
-    throw new Error(error.error || `HTTP ${response.status}`)
+    throw new ApiError(response.status, error)
   }
   return response.json()
 }
@@ -156,6 +200,20 @@ export interface DashboardCRDCount {
   count: number
 }
 
+export interface QuotaResourceUsage {
+  resource: string // e.g. pods, requests.cpu
+  used: string
+  hard: string
+  percent: number
+}
+
+export interface QuotaUtilization {
+  namespace: string
+  name: string
+  resources: QuotaResourceUsage[]
+  maxPercent: number
+}
+
 export interface DashboardResponse {
   cluster: DashboardCluster
   health: DashboardHealth
@@ -168,6 +226,7 @@ export interface DashboardResponse {
   helmReleases: DashboardHelmSummary
   metrics: DashboardMetrics | null
   topCRDs: DashboardCRDCount[]
+  quotas: QuotaUtilization[] // Most utilized first
 }
 
 export function useDashboard(namespace?: string) {
@@ -176,7 +235,92 @@ export function useDashboard(namespace?: string) {
     queryKey: ['dashboard', namespace],
     queryFn: () => fetchJSON(`/dashboard${params}`),
     staleTime: 15000, // 15 seconds
-    refetchInterval: 30000, // Refresh every 30 seconds
+    refetchInterval: 120000, // useDashboardStream pushes changes; this is a fallback
+  })
+}
+
+// Cost estimates (GET /api/costs)
+export interface CostAmount {
+  hourly: number
+  daily: number
+  monthly: number
+}
+
+export interface NodeCost {
+  name: string
+  instanceType?: string
+  region?: string
+  spot?: boolean
+  pricedBy: 'instance' | 'resources'
+  cpuCores: number
+  memoryBytes: number
+  cost: CostAmount
+  idle: CostAmount
+}
+
+export interface CostAllocation {
+  namespace: string
+  kind?: string // Workload allocations only
+  name?: string
+  pods: number
+  cpuCores: number
+  memoryBytes: number
+  cost: CostAmount
+}
+
+export type CostBasis = 'requests' | 'usage' | 'max'
+
+export interface CostReport {
+  currency: string
+  basis: CostBasis
+  pricingSource: string
+  generatedAt: string
+  total: CostAmount
+  idle: CostAmount
+  nodes: NodeCost[]
+  namespaces: CostAllocation[]
+  workloads?: CostAllocation[] // When a namespace is selected
+}
+
+export function useCosts(namespace?: string, basis: CostBasis = 'requests') {
+  const params = new URLSearchParams({ basis })
+  if (namespace) params.set('namespace', namespace)
+  return useQuery<CostReport>({
+    queryKey: ['costs', namespace, basis],
+    queryFn: () => fetchJSON(`/costs?${params}`),
+    staleTime: 60000, // 1 minute
+  })
+}
+
+export interface SearchMatch {
+  field: 'name' | 'kind' | 'label' | 'annotation' | 'image'
+  value: string
+}
+
+export interface SearchResult {
+  kind: string
+  group?: string
+  resource: string // Plural, e.g. "deployments"
+  namespace?: string
+  name: string
+  score: number
+  matches?: SearchMatch[]
+}
+
+export interface SearchResponse {
+  results: SearchResult[]
+  total: number // Matches before the limit
+}
+
+// Global search across cached resources; q supports name:, label:, annotation:, image:, kind: and ns:
+export function useSearch(q: string, namespace?: string, limit = 50) {
+  const params = new URLSearchParams({ q, limit: String(limit) })
+  if (namespace) params.set('namespace', namespace)
+  return useQuery<SearchResponse>({
+    queryKey: ['search', q, namespace, limit],
+    queryFn: () => fetchJSON(`/search?${params}`),
+    enabled: q.trim().length > 0,
+    staleTime: 10000, // 10 seconds
   })
 }
 
@@ -198,6 +342,49 @@ export function useCapabilities() {
   })
 }
 
+// Informer watch scope (--namespaces)
+export interface WatchNamespaces {
+  scoped: boolean
+  pinned?: string[]     // From --namespaces, always watched
+  namespaces?: string[] // Currently watched
+}
+
+export function useWatchNamespaces() {
+  return useQuery<WatchNamespaces>({
+    queryKey: ['watch-namespaces'],
+    queryFn: () => fetchJSON('/watch-namespaces'),
+    staleTime: 60000,
+  })
+}
+
+// Replace the watched namespaces; informers for added namespaces sync before this resolves
+export function useSetWatchNamespaces() {
+  const queryClient = useQueryClient()
+
+  return useMutation({
+    mutationFn: async (namespaces: string[]) => {
+      const response = await fetch(`${API_BASE}/watch-namespaces`, {
+        method: 'PUT',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ namespaces }),
+      })
+      if (!response.ok) {
+        const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+        throw new ApiError(response.status, error)
+      }
+      return response.json() as Promise<WatchNamespaces>
+    },
+    meta: {
+      errorMessage: 'Failed to change watched namespaces',
+    },
+    onSuccess: (data) => {
+      queryClient.setQueryData(['watch-namespaces'], data)
+      queryClient.invalidateQueries({ queryKey: ['resources'] })
+      queryClient.invalidateQueries({ queryKey: ['topology'] })
+    },
+  })
+}
+
 // Namespaces
 export function useNamespaces() {
   return useQuery<Namespace[]>({
@@ -272,6 +459,39 @@ export function useResources<T>(kind: string, namespace?: string, group?: string
   })
 }
 
+// Custom resources with the columns their CRD declares for kubectl get
+export interface PrinterColumn {
+  name: string
+  type: 'integer' | 'number' | 'string' | 'boolean' | 'date'
+  format?: string
+  description?: string
+  priority?: number // Above 0: only in kubectl get -o wide
+  jsonPath: string
+}
+
+export interface ResourceTable {
+  kind: string
+  group: string
+  version: string
+  columns: PrinterColumn[]
+  rows: { namespace?: string; name: string; cells: unknown[] }[]
+}
+
+export function useResourceTable(kind: string, namespace?: string, group?: string, enabled = true) {
+  const params = new URLSearchParams()
+  if (namespace) params.set('namespace', namespace)
+  if (group) params.set('group', group)
+  const queryString = params.toString()
+
+  return useQuery<ResourceTable>({
+    queryKey: ['resource-table', kind, group, namespace],
+    queryFn: () => fetchJSON(`/resources/${kind}/table${queryString ? `?${queryString}` : ''}`),
+    enabled: enabled && Boolean(kind),
+    staleTime: 30000,
+    refetchInterval: 30000,
+  })
+}
+
 // Timeline changes (unified view of changes + K8s events)
 export interface UseChangesOptions {
   namespace?: string
@@ -328,6 +548,21 @@ export function useChanges(options: UseChangesOptions = {}) {
   })
 }
 
+// Timeline markers (deploys, incident notes) posted to /api/changes/annotations, oldest
+// first. With a namespace, cluster-wide markers are included too.
+export function useAnnotations(namespace?: string) {
+  const params = new URLSearchParams()
+  if (namespace) params.set('namespace', namespace)
+  const queryString = params.toString()
+
+  return useQuery<TimelineEvent[]>({
+    queryKey: ['annotations', namespace],
+    queryFn: () => fetchJSON(`/changes/annotations${queryString ? `?${queryString}` : ''}`),
+    staleTime: 30000,
+    refetchInterval: 60000,
+  })
+}
+
 // Children changes for a parent workload (e.g., ReplicaSets and Pods under a Deployment)
 export function useResourceChildren(kind: string, namespace: string, name: string, timeRange: TimeRange = '1h') {
   const sinceDate = getTimeRangeDate(timeRange)
@@ -425,6 +660,135 @@ export function useNodeMetrics(nodeName: string) {
   })
 }
 
+// ============================================================================
+// Node Detail (capacity and health, from the informer cache)
+// ============================================================================
+
+export interface NodeResource {
+  allocatable: number
+  requests: number
+  limits: number
+  requestPercent: number
+  limitPercent: number // over 100 means overcommitted
+}
+
+export interface NodeDetail {
+  name: string
+  roles?: string[]
+  ready: boolean
+  unschedulable?: boolean
+  pressure?: string[] // e.g. MemoryPressure, DiskPressure, PIDPressure
+  conditions: { type: string; status: string; reason?: string; message?: string; lastTransitionTime?: string }[]
+  taints?: { key: string; value?: string; effect: string }[]
+  cpu: NodeResource    // millicores
+  memory: NodeResource // bytes
+  pods: { count: number; capacity: number }
+  versions: { kubelet: string; containerRuntime: string; kernel?: string; osImage?: string; architecture?: string }
+  createdAt: string
+  podList?: { namespace: string; name: string; phase: string; cpuRequests: number; memoryRequests: number }[]
+}
+
+// Fetch capacity and health for every node
+export function useNodeDetails() {
+  return useQuery<NodeDetail[]>({
+    queryKey: ['node-details'],
+    queryFn: () => fetchJSON('/nodes'),
+    staleTime: 15000,
+    refetchInterval: 30000,
+  })
+}
+
+// Fetch one node's capacity and health with its pods
+export function useNodeDetail(nodeName: string) {
+  return useQuery<NodeDetail>({
+    queryKey: ['node-detail', nodeName],
+    queryFn: () => fetchJSON(`/nodes/${nodeName}`),
+    enabled: Boolean(nodeName),
+    staleTime: 15000,
+    refetchInterval: 30000,
+  })
+}
+
+// Cordon (unschedulable: true) or uncordon a node
+export function useSetNodeSchedulable() {
+  const queryClient = useQueryClient()
+
+  return useMutation({
+    mutationFn: async ({ name, unschedulable }: { name: string; unschedulable: boolean }) => {
+      const response = await fetch(`${API_BASE}/nodes/${name}/${unschedulable ? 'cordon' : 'uncordon'}`, {
+        method: 'POST',
+      })
+      if (!response.ok) {
+        const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+        throw new ApiError(response.status, error)
+      }
+      return response.json()
+    },
+    meta: {
+      errorMessage: 'Failed to update node',
+    },
+    onSuccess: (_, variables) => {
+      queryClient.invalidateQueries({ queryKey: ['resource', 'nodes'] })
+      queryClient.invalidateQueries({ queryKey: ['node-detail', variables.name] })
+      queryClient.invalidateQueries({ queryKey: ['node-details'] })
+    },
+  })
+}
+
+export interface DrainRequest {
+  force?: boolean
+  deleteEmptyDirData?: boolean
+  gracePeriodSeconds?: number
+  timeoutSeconds?: number
+}
+
+export interface DrainPod {
+  namespace: string
+  name: string
+  owner?: string
+}
+
+export interface DrainPlan {
+  node: string
+  evict: DrainPod[]
+  skipped?: (DrainPod & { reason: string })[]
+  problems?: string[]
+}
+
+export interface DrainEvent {
+  type: 'plan' | 'cordoned' | 'evicted' | 'blocked' | 'deleted' | 'stuck' | 'failed' | 'done'
+  pod?: DrainPod
+  plan?: DrainPlan
+  message?: string
+  remaining?: number
+  completed?: boolean
+}
+
+// Preview which pods a drain would evict and skip
+export async function previewDrain(node: string, req: DrainRequest): Promise<DrainPlan> {
+  const response = await fetch(`${API_BASE}/nodes/${node}/drain`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ ...req, dryRun: true }),
+  })
+  if (!response.ok) {
+    const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+    throw new ApiError(response.status, error)
+  }
+  return response.json()
+}
+
+// Drain a node, calling onEvent for each progress event until the drain ends
+export async function runDrain(node: string, req: DrainRequest, onEvent: (e: DrainEvent) => void, signal?: AbortSignal): Promise<void> {
+  const response = await fetch(`${API_BASE}/nodes/${node}/drain`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify(req),
+    signal,
+  })
+  await readEventStream(response, onEvent)
+}
+
 // ============================================================================
 // Metrics History (local collection)
 // ============================================================================
@@ -433,6 +797,8 @@ export interface MetricsDataPoint {
   timestamp: string
   cpu: number      // CPU in nanocores
   memory: number   // Memory in bytes
+  cpuMax?: number    // Highest sample behind a rolled-up point
+  memoryMax?: number
 }
 
 export interface ContainerMetricsHistory {
@@ -444,29 +810,38 @@ export interface PodMetricsHistory {
   namespace: string
   name: string
   containers: ContainerMetricsHistory[]
+  resolution?: 'raw' | '5m' | '1h' // Set when read from persisted history
+  persistent?: boolean              // Longer ranges can be requested
 }
 
 export interface NodeMetricsHistory {
   name: string
   dataPoints: MetricsDataPoint[]
+  resolution?: 'raw' | '5m' | '1h'
+  persistent?: boolean
+}
+
+function metricsHistoryQuery(range?: string): string {
+  return range ? `?range=${encodeURIComponent(range)}` : ''
 }
 
-// Fetch historical metrics for a pod (last ~1 hour)
-export function usePodMetricsHistory(namespace: string, podName: string) {
+// Fetch historical metrics for a pod (last ~1 hour, or range as a Go duration when
+// history is persisted)
+export function usePodMetricsHistory(namespace: string, podName: string, range?: string) {
   return useQuery<PodMetricsHistory>({
-    queryKey: ['pod-metrics-history', namespace, podName],
-    queryFn: () => fetchJSON(`/metrics/pods/${namespace}/${podName}/history`),
+    queryKey: ['pod-metrics-history', namespace, podName, range],
+    queryFn: () => fetchJSON(`/metrics/pods/${namespace}/${podName}/history${metricsHistoryQuery(range)}`),
     enabled: Boolean(namespace && podName),
     staleTime: 25000, // Slightly less than poll interval
     refetchInterval: 30000, // Match the backend poll interval
   })
 }
 
-// Fetch historical metrics for a node (last ~1 hour)
-export function useNodeMetricsHistory(nodeName: string) {
+// Fetch historical metrics for a node (see usePodMetricsHistory)
+export function useNodeMetricsHistory(nodeName: string, range?: string) {
   return useQuery<NodeMetricsHistory>({
-    queryKey: ['node-metrics-history', nodeName],
-    queryFn: () => fetchJSON(`/metrics/nodes/${nodeName}/history`),
+    queryKey: ['node-metrics-history', nodeName, range],
+    queryFn: () => fetchJSON(`/metrics/nodes/${nodeName}/history${metricsHistoryQuery(range)}`),
     enabled: Boolean(nodeName),
     staleTime: 25000,
     refetchInterval: 30000,
@@ -537,6 +912,44 @@ export function createLogStream(
   return new EventSource(`${API_BASE}/pods/${namespace}/${podName}/logs/stream${queryString ? `?${queryString}` : ''}`)
 }
 
+// A line of a merged log stream, labelled with its source
+export interface MergedLogLine {
+  timestamp?: string
+  pod: string
+  container: string
+  content: string
+}
+
+// Create SSE connection for the merged, time-ordered logs of every container of a pod, or
+// every pod of a workload (deployments, statefulsets, daemonsets, replicasets, jobs).
+// Events: connected, log (MergedLogLine), source_added, source_end, end.
+export function createMergedLogStream(
+  kind: string,
+  namespace: string,
+  name: string,
+  options?: {
+    container?: string
+    tailLines?: number
+    sinceSeconds?: number
+    follow?: boolean
+    previous?: boolean
+    filter?: string
+    invert?: boolean
+  }
+): EventSource {
+  const params = new URLSearchParams()
+  if (options?.container) params.set('container', options.container)
+  if (options?.tailLines) params.set('tailLines', String(options.tailLines))
+  if (options?.sinceSeconds) params.set('sinceSeconds', String(options.sinceSeconds))
+  if (options?.follow === false) params.set('follow', 'false')
+  if (options?.previous) params.set('previous', 'true')
+  if (options?.filter) params.set('filter', options.filter)
+  if (options?.invert) params.set('invert', 'true')
+  const queryString = params.toString()
+
+  return new EventSource(`${API_BASE}/logs/${kind}/${namespace}/${name}${queryString ? `?${queryString}` : ''}`)
+}
+
 // ============================================================================
 // Port Forwarding
 // ============================================================================
@@ -574,7 +987,7 @@ export function useUpdateResource() {
       })
       if (!response.ok) {
         const error = await response.json().catch(() => ({ error: 'Unknown error' }))
-        throw new Error(error.error || `HTTP ${response.status}`)
+        throw new ApiError(response.status, error)
       }
       return response.json()
     },
@@ -590,18 +1003,69 @@ export function useUpdateResource() {
   })
 }
 
+// Dry-run an edit on the API server and return the diff against the live object
+export function usePreviewResourceUpdate() {
+  return useMutation({
+    mutationFn: async ({ kind, namespace, name, yaml }: { kind: string; namespace: string; name: string; yaml: string }): Promise<UpdatePreview> => {
+      const response = await fetch(`${API_BASE}/resources/${kind}/${namespace}/${name}/dry-run`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'text/plain' },
+        body: yaml,
+      })
+      if (!response.ok) {
+        const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+        throw new ApiError(response.status, error)
+      }
+      return response.json()
+    },
+  })
+}
+
+export type DeletePropagation = 'background' | 'foreground' | 'orphan'
+
+export interface DeletePreview {
+  kind: string
+  namespace?: string
+  name: string
+  propagation: DeletePropagation
+  dependents: Array<{ kind: string; namespace?: string; name: string }>
+  orphaned?: boolean
+}
+
+// Preview a delete (dependents affected by the propagation policy) for a confirmation dialog
+export async function previewDeleteResource(kind: string, namespace: string, name: string, propagation?: DeletePropagation): Promise<DeletePreview> {
+  const params = new URLSearchParams({ dryRun: 'true' })
+  if (propagation) params.set('propagation', propagation)
+  const response = await fetch(`${API_BASE}/resources/${kind}/${namespace}/${name}?${params}`, { method: 'DELETE' })
+  if (!response.ok) {
+    const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+    throw new ApiError(response.status, error)
+  }
+  return response.json()
+}
+
 // Delete a resource
 export function useDeleteResource() {
   const queryClient = useQueryClient()
 
   return useMutation({
-    mutationFn: async ({ kind, namespace, name }: { kind: string; namespace: string; name: string }) => {
-      const response = await fetch(`${API_BASE}/resources/${kind}/${namespace}/${name}`, {
+    mutationFn: async ({ kind, namespace, name, propagation, force }: {
+      kind: string
+      namespace: string
+      name: string
+      propagation?: DeletePropagation
+      force?: boolean
+    }) => {
+      const params = new URLSearchParams()
+      if (propagation) params.set('propagation', propagation)
+      if (force) params.set('force', 'true')
+      const query = params.toString() ? `?${params}` : ''
+      const response = await fetch(`${API_BASE}/resources/${kind}/${namespace}/${name}${query}`, {
         method: 'DELETE',
       })
       if (!response.ok) {
         const error = await response.json().catch(() => ({ error: 'Unknown error' }))
-        throw new Error(error.error || `HTTP ${response.status}`)
+        throw new ApiError(response.status, error)
       }
       // DELETE returns 204 No Content, no body to parse
       return { success: true }
@@ -632,7 +1096,7 @@ export function useTriggerCronJob() {
       })
       if (!response.ok) {
         const error = await response.json().catch(() => ({ error: 'Unknown error' }))
-        throw new Error(error.error || `HTTP ${response.status}`)
+        throw new ApiError(response.status, error)
       }
       return response.json()
     },
@@ -648,6 +1112,248 @@ export function useTriggerCronJob() {
   })
 }
 
+// Re-run a finished Job (creates a copy of it under a new name)
+export function useRerunJob() {
+  const queryClient = useQueryClient()
+
+  return useMutation({
+    mutationFn: async ({ namespace, name }: { namespace: string; name: string }) => {
+      const response = await fetch(`${API_BASE}/jobs/${namespace}/${name}/rerun`, {
+        method: 'POST',
+      })
+      if (!response.ok) {
+        const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+        throw new ApiError(response.status, error)
+      }
+      return response.json()
+    },
+    meta: {
+      errorMessage: 'Failed to re-run Job',
+      successMessage: 'Job re-run started',
+    },
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: ['resources', 'jobs'] })
+      queryClient.invalidateQueries({ queryKey: ['topology'] })
+    },
+  })
+}
+
+// PVC filesystem usage from the kubelet stats summaries
+export interface PVCUsage {
+  namespace: string
+  name: string
+  storageClass?: string
+  phase: string
+  requested?: string
+  capacity?: string
+  usedBytes?: number
+  capacityBytes?: number
+  usedPercent?: number
+  inodesUsedPercent?: number
+  status: 'ok' | 'warning' | 'critical' | 'unknown'
+  abnormal?: boolean
+  expandable: boolean
+  resizing?: string
+  message?: string
+  sampledAt?: string
+}
+
+export interface PVCUsageResponse {
+  pvcs: PVCUsage[]
+  warningPercent: number
+  criticalPercent: number
+}
+
+export function usePVCUsage(namespace?: string, enabled = true) {
+  const params = new URLSearchParams()
+  if (namespace) params.set('namespace', namespace)
+  return useQuery<PVCUsageResponse>({
+    queryKey: ['pvc-usage', namespace],
+    queryFn: () => fetchJSON(`/storage/pvcs?${params}`),
+    enabled,
+    staleTime: 60000, // Stats are sampled every 5 minutes
+  })
+}
+
+export function useExpandPVC() {
+  const queryClient = useQueryClient()
+
+  return useMutation({
+    mutationFn: async ({ namespace, name, size }: { namespace: string; name: string; size: string }) => {
+      const response = await fetch(`${API_BASE}/storage/pvcs/${namespace}/${name}/expand`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ size }),
+      })
+      if (!response.ok) {
+        const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+        throw new ApiError(response.status, error)
+      }
+      return response.json()
+    },
+    meta: {
+      errorMessage: 'Failed to expand volume',
+      successMessage: 'Volume expansion requested',
+    },
+    onSuccess: (_, variables) => {
+      queryClient.invalidateQueries({ queryKey: ['pvc-usage'] })
+      queryClient.invalidateQueries({ queryKey: ['resource', 'persistentvolumeclaims', variables.namespace, variables.name] })
+      queryClient.invalidateQueries({ queryKey: ['resources', 'persistentvolumeclaims'] })
+    },
+  })
+}
+
+// Image metadata from registries (--image-inspection)
+export interface VulnerabilitySummary {
+  critical: number
+  high: number
+  medium: number
+  low: number
+  unknown: number
+  source: 'harbor' | 'trivy'
+  scannedAt?: string
+}
+
+export type ImageFreshness = 'fresh' | 'aging' | 'old' | 'unknown'
+
+export interface WorkloadImages {
+  kind: string
+  namespace: string
+  name: string
+  containers: { container: string; image: string; runningDigest?: string; outdated?: boolean }[]
+  freshness: ImageFreshness
+  outdated?: boolean
+  vulnerabilities?: VulnerabilitySummary
+}
+
+// Per-workload image badges; fails with 503 when image inspection is off
+export function useWorkloadImages(namespace?: string, enabled = true) {
+  const params = new URLSearchParams()
+  if (namespace) params.set('namespace', namespace)
+  return useQuery<{ workloads: WorkloadImages[] }>({
+    queryKey: ['image-workloads', namespace],
+    queryFn: () => fetchJSON(`/images/workloads?${params}`),
+    enabled,
+    retry: false,
+    staleTime: 60000,
+  })
+}
+
+// Secret keys and value sizes (values are revealed one key at a time)
+export function useSecretDetail(namespace: string, name: string, enabled = true) {
+  return useQuery<SecretDetail>({
+    queryKey: ['secret', namespace, name],
+    queryFn: () => fetchJSON(`/secrets/${namespace}/${name}`),
+    enabled: enabled && Boolean(namespace && name),
+  })
+}
+
+// Reveal one decoded Secret key; each reveal is recorded on the timeline
+export function useRevealSecretKey() {
+  return useMutation<SecretValue, Error, { namespace: string; name: string; key: string }>({
+    mutationFn: async ({ namespace, name, key }) => {
+      const response = await fetch(`${API_BASE}/secrets/${namespace}/${name}/keys/${encodeURIComponent(key)}/reveal`, {
+        method: 'POST',
+      })
+      if (!response.ok) {
+        const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+        throw new ApiError(response.status, error)
+      }
+      return response.json()
+    },
+    meta: {
+      errorMessage: 'Failed to reveal secret value',
+    },
+  })
+}
+
+// ============================================================================
+// Pod files
+// ============================================================================
+
+export interface PodFileEntry {
+  name: string
+  type: 'file' | 'dir' | 'symlink' | 'other'
+  size: number
+  mode: string // e.g. "drwxr-xr-x"
+  modTime: string
+}
+
+export interface PodFilesResponse {
+  path: string
+  entries: PodFileEntry[]
+}
+
+export interface PodUploadResponse {
+  path: string
+  files: number
+  bytes: number
+}
+
+// List a directory in a container (runs find/stat over exec)
+export function usePodFiles(namespace: string, podName: string, container: string, path: string, enabled = true) {
+  const params = new URLSearchParams({ path })
+  if (container) params.set('container', container)
+  return useQuery<PodFilesResponse>({
+    queryKey: ['pod-files', namespace, podName, container, path],
+    queryFn: () => fetchJSON(`/pods/${namespace}/${podName}/files?${params}`),
+    enabled: enabled && Boolean(namespace && podName),
+    retry: false,
+  })
+}
+
+// URL that downloads a file, or a directory as .tar.gz; navigating to it lets the
+// browser's download manager show progress
+export function podFileDownloadUrl(namespace: string, podName: string, container: string, path: string): string {
+  const params = new URLSearchParams({ path })
+  if (container) params.set('container', container)
+  return `${API_BASE}/pods/${namespace}/${podName}/files/download?${params}`
+}
+
+// Upload a file into a container directory. With extract, .tar/.tar.gz/.tgz files are
+// unpacked there instead of copied. XHR rather than fetch for upload progress.
+export function uploadPodFile(
+  namespace: string,
+  podName: string,
+  container: string,
+  dir: string,
+  file: File,
+  options: { extract?: boolean; onProgress?: (loaded: number, total: number) => void } = {},
+): Promise<PodUploadResponse> {
+  const params = new URLSearchParams({ path: dir })
+  if (container) params.set('container', container)
+  let contentType = 'application/octet-stream'
+  if (options.extract && /\.(tar\.gz|tgz)$/i.test(file.name)) {
+    contentType = 'application/gzip'
+  } else if (options.extract && /\.tar$/i.test(file.name)) {
+    contentType = 'application/x-tar'
+  } else {
+    params.set('name', file.name)
+  }
+
+  return new Promise((resolve, reject) => {
+    const xhr = new XMLHttpRequest()
+    xhr.open('POST', `${API_BASE}/pods/${namespace}/${podName}/files/upload?${params}`)
+    xhr.setRequestHeader('Content-Type', contentType)
+    xhr.upload.onprogress = (e) => options.onProgress?.(e.loaded, e.lengthComputable ? e.total : file.size)
+    xhr.onload = () => {
+      let body: unknown
+      try {
+        body = JSON.parse(xhr.responseText)
+      } catch {
+        body = { error: xhr.statusText || 'Unknown error' }
+      }
+      if (xhr.status >= 200 && xhr.status < 300) {
+        resolve(body as PodUploadResponse)
+      } else {
+        reject(new ApiError(xhr.status, body as ApiErrorBody))
+      }
+    }
+    xhr.onerror = () => reject(new Error('Upload failed: network error'))
+    xhr.send(file)
+  })
+}
+
 // Suspend a CronJob
 export function useSuspendCronJob() {
   const queryClient = useQueryClient()
@@ -659,7 +1365,7 @@ export function useSuspendCronJob() {
       })
       if (!response.ok) {
         const error = await response.json().catch(() => ({ error: 'Unknown error' }))
-        throw new Error(error.error || `HTTP ${response.status}`)
+        throw new ApiError(response.status, error)
       }
       return response.json()
     },
@@ -685,7 +1391,7 @@ export function useResumeCronJob() {
       })
       if (!response.ok) {
         const error = await response.json().catch(() => ({ error: 'Unknown error' }))
-        throw new Error(error.error || `HTTP ${response.status}`)
+        throw new ApiError(response.status, error)
       }
       return response.json()
     },
@@ -700,6 +1406,109 @@ export function useResumeCronJob() {
   })
 }
 
+// ============================================================================
+// Argo CD Application actions
+// ============================================================================
+
+export interface ArgoSyncOptions {
+  revision?: string // Sync to this revision instead of the app's targetRevision
+  prune?: boolean
+  dryRun?: boolean
+}
+
+async function argoApplicationAction(path: string, method: string, body?: unknown) {
+  const response = await fetch(`${API_BASE}/argocd/applications/${path}`, {
+    method,
+    headers: body ? { 'Content-Type': 'application/json' } : undefined,
+    body: body ? JSON.stringify(body) : undefined,
+  })
+  if (!response.ok) {
+    const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+    throw new ApiError(response.status, error)
+  }
+  return response.json()
+}
+
+// Trigger an Argo CD sync
+export function useSyncArgoApplication() {
+  const queryClient = useQueryClient()
+
+  return useMutation({
+    mutationFn: ({ namespace, name, ...opts }: { namespace: string; name: string } & ArgoSyncOptions) =>
+      argoApplicationAction(`${namespace}/${name}/sync`, 'POST', opts),
+    meta: {
+      errorMessage: 'Failed to sync application',
+      successMessage: 'Sync started',
+    },
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: ['resources', 'applications'] })
+    },
+  })
+}
+
+// Ask Argo CD to re-compare an Application with its source
+export function useRefreshArgoApplication() {
+  const queryClient = useQueryClient()
+
+  return useMutation({
+    mutationFn: ({ namespace, name, hard }: { namespace: string; name: string; hard?: boolean }) =>
+      argoApplicationAction(`${namespace}/${name}/refresh${hard ? '?hard=true' : ''}`, 'POST'),
+    meta: {
+      errorMessage: 'Failed to refresh application',
+      successMessage: 'Refresh requested',
+    },
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: ['resources', 'applications'] })
+    },
+  })
+}
+
+// Enable or disable an Application's automated sync
+export function useSetArgoAutoSync() {
+  const queryClient = useQueryClient()
+
+  return useMutation({
+    mutationFn: ({ namespace, name, ...policy }: { namespace: string; name: string; enabled: boolean; prune?: boolean; selfHeal?: boolean }) =>
+      argoApplicationAction(`${namespace}/${name}/auto-sync`, 'PUT', policy),
+    meta: {
+      errorMessage: 'Failed to update auto-sync',
+      successMessage: 'Auto-sync updated',
+    },
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: ['resources', 'applications'] })
+    },
+  })
+}
+
+// ============================================================================
+// Argo Rollouts actions
+// ============================================================================
+
+export type RolloutAction = 'promote' | 'promote-full' | 'abort' | 'retry' | 'pause'
+
+// Promote, fully promote, abort, retry or pause an Argo Rollout
+export function useRolloutAction() {
+  const queryClient = useQueryClient()
+
+  return useMutation({
+    mutationFn: async ({ namespace, name, action }: { namespace: string; name: string; action: RolloutAction }) => {
+      const response = await fetch(`${API_BASE}/rollouts/${namespace}/${name}/${action}`, { method: 'POST' })
+      if (!response.ok) {
+        const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+        throw new ApiError(response.status, error)
+      }
+      return response.json() as Promise<{ message: string }>
+    },
+    meta: {
+      errorMessage: 'Rollout action failed',
+    },
+    onSuccess: (_, { namespace, name }) => {
+      queryClient.invalidateQueries({ queryKey: ['resources', 'rollouts'] })
+      queryClient.invalidateQueries({ queryKey: ['resource', 'rollouts', namespace, name] })
+    },
+  })
+}
+
 // ============================================================================
 // Workload operations
 // ============================================================================
@@ -715,7 +1524,7 @@ export function useRestartWorkload() {
       })
       if (!response.ok) {
         const error = await response.json().catch(() => ({ error: 'Unknown error' }))
-        throw new Error(error.error || `HTTP ${response.status}`)
+        throw new ApiError(response.status, error)
       }
       return response.json()
     },
@@ -730,6 +1539,164 @@ export function useRestartWorkload() {
   })
 }
 
+export interface RestartTarget {
+  kind: string
+  namespace: string
+  name: string
+}
+
+export interface OrchestratedRestartRequest {
+  targets?: RestartTarget[]
+  configMap?: string // namespace/name
+  secret?: string // namespace/name
+  stepTimeoutSeconds?: number
+}
+
+export interface RestartEvent {
+  type: 'plan' | 'step_started' | 'restarted' | 'healthy' | 'failed' | 'done'
+  step?: number
+  target?: RestartTarget
+  steps?: RestartTarget[][]
+  message?: string
+  completed?: boolean
+}
+
+// Preview the dependency-ordered steps of an orchestrated restart
+export async function previewOrchestratedRestart(req: OrchestratedRestartRequest): Promise<{ steps: RestartTarget[][] }> {
+  const response = await fetch(`${API_BASE}/workloads/restart`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ ...req, dryRun: true }),
+  })
+  if (!response.ok) {
+    const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+    throw new ApiError(response.status, error)
+  }
+  return response.json()
+}
+
+// Run an orchestrated restart, calling onEvent for each progress event until the run ends
+export async function runOrchestratedRestart(req: OrchestratedRestartRequest, onEvent: (e: RestartEvent) => void, signal?: AbortSignal): Promise<void> {
+  const response = await fetch(`${API_BASE}/workloads/restart`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify(req),
+    signal,
+  })
+  await readEventStream(response, onEvent)
+}
+
+// Read a POST response streamed as SSE, calling onEvent with each event's data
+async function readEventStream<T>(response: Response, onEvent: (e: T) => void): Promise<void> {
+  if (!response.ok || !response.body) {
+    const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+    throw new ApiError(response.status, error)
+  }
+  const reader = response.body.pipeThrough(new TextDecoderStream()).getReader()
+  let buffer = ''
+  for (;;) {
+    const { value, done } = await reader.read()
+    if (done) return
+    buffer += value
+    let end
+    while ((end = buffer.indexOf('\n\n')) >= 0) {
+      const data = buffer.slice(0, end).split('\n').find((line) => line.startsWith('data: '))
+      buffer = buffer.slice(end + 2)
+      if (data) onEvent(JSON.parse(data.slice(6)))
+    }
+  }
+}
+
+export interface ImageRolloutRequest {
+  from: string
+  to: string
+  namespace?: string
+  dryRun?: boolean
+}
+
+export interface ImageRolloutWorkload {
+  kind: string
+  namespace: string
+  name: string
+  containers: string[]
+  managedBy?: string
+  state: 'pending' | 'rolling' | 'done' | 'failed'
+  message?: string
+}
+
+export interface ImageRollout {
+  id?: string
+  from: string
+  to: string
+  namespace?: string
+  state: 'preview' | 'running' | 'succeeded' | 'failed'
+  startedAt?: string
+  finishedAt?: string
+  workloads: ImageRolloutWorkload[]
+  warnings?: string[]
+}
+
+// Preview (dryRun) or start moving every workload from one image to another
+export async function startImageRollout(req: ImageRolloutRequest): Promise<ImageRollout> {
+  const response = await fetch(`${API_BASE}/image-rollouts`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify(req),
+  })
+  if (!response.ok) {
+    const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+    throw new ApiError(response.status, error)
+  }
+  return response.json()
+}
+
+// Track an image rollout, polling until it finishes
+export function useImageRollout(id: string | undefined) {
+  return useQuery<ImageRollout>({
+    queryKey: ['image-rollout', id],
+    queryFn: () => fetchJSON(`/image-rollouts/${id}`),
+    enabled: Boolean(id),
+    refetchInterval: (query) => (query.state.data?.state === 'running' ? 2000 : false),
+  })
+}
+
+export interface ScaleResponse {
+  message: string
+  previousReplicas: number
+  replicas: number
+  warning?: string // An HPA manages the workload and will override the count
+  hpa?: { name: string; previousMinReplicas: number; previousMaxReplicas: number; minReplicas: number; maxReplicas: number }
+}
+
+// Scale a workload (Deployment, StatefulSet, ReplicaSet, Rollout). With updateHPA, the
+// range of the HPA managing it is moved instead.
+export function useScaleWorkload() {
+  const queryClient = useQueryClient()
+
+  return useMutation({
+    mutationFn: async ({ kind, namespace, name, replicas, updateHPA }: { kind: string; namespace: string; name: string; replicas: number; updateHPA?: boolean }): Promise<ScaleResponse> => {
+      const response = await fetch(`${API_BASE}/workloads/${kind}/${namespace}/${name}/scale`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ replicas, updateHPA }),
+      })
+      if (!response.ok) {
+        const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+        throw new ApiError(response.status, error)
+      }
+      return response.json()
+    },
+    meta: {
+      errorMessage: 'Failed to scale workload',
+      successMessage: 'Workload scaled',
+    },
+    onSuccess: (_, variables) => {
+      queryClient.invalidateQueries({ queryKey: ['resources', variables.kind] })
+      queryClient.invalidateQueries({ queryKey: ['topology'] })
+    },
+  })
+}
+
 // ============================================================================
 // Helm API hooks
 // ============================================================================
@@ -754,6 +1721,16 @@ export function useHelmRelease(namespace: string, name: string) {
   })
 }
 
+// Get the topology subgraph of everything a Helm release owns
+export function useHelmReleaseTopology(namespace: string, name: string, enabled = true) {
+  return useQuery<HelmReleaseTopology>({
+    queryKey: ['helm-release-topology', namespace, name],
+    queryFn: () => fetchJSON(`/helm/releases/${namespace}/${name}/topology`),
+    enabled: enabled && Boolean(namespace && name),
+    staleTime: 30000,
+  })
+}
+
 // Get manifest for a Helm release (optionally at a specific revision)
 export function useHelmManifest(namespace: string, name: string, revision?: number) {
   const params = revision ? `?revision=${revision}` : ''
@@ -763,7 +1740,7 @@ export function useHelmManifest(namespace: string, name: string, revision?: numb
       const response = await fetch(`${API_BASE}/helm/releases/${namespace}/${name}/manifest${params}`)
       if (!response.ok) {
         const error = await response.json().catch(() => ({ error: 'Unknown error' }))
-        throw new Error(error.error || `HTTP ${response.status}`)
+        throw new ApiError(response.status, error)
       }
       return response.text()
     },
@@ -810,6 +1787,18 @@ export function useHelmUpgradeInfo(namespace: string, name: string, enabled = tr
   })
 }
 
+// Diff the release against a chart version before upgrading (latest when version is empty)
+export function useHelmUpgradePreview(namespace: string, name: string, version: string, enabled = true) {
+  const params = version ? `?version=${encodeURIComponent(version)}` : ''
+  return useQuery<UpgradePreview>({
+    queryKey: ['helm-upgrade-preview', namespace, name, version],
+    queryFn: () => fetchJSON(`/helm/releases/${namespace}/${name}/upgrade-preview${params}`),
+    enabled: Boolean(namespace && name && enabled),
+    staleTime: 60000,
+    retry: false,
+  })
+}
+
 // Batch check for upgrade availability (for list view)
 export function useHelmBatchUpgradeInfo(namespace?: string, enabled = true) {
   const params = namespace ? `?namespace=${namespace}` : ''
@@ -837,7 +1826,7 @@ export function useHelmRollback() {
       })
       if (!response.ok) {
         const error = await response.json().catch(() => ({ error: 'Unknown error' }))
-        throw new Error(error.error || `HTTP ${response.status}`)
+        throw new ApiError(response.status, error)
       }
       return response.json()
     },
@@ -863,7 +1852,7 @@ export function useHelmUninstall() {
       })
       if (!response.ok) {
         const error = await response.json().catch(() => ({ error: 'Unknown error' }))
-        throw new Error(error.error || `HTTP ${response.status}`)
+        throw new ApiError(response.status, error)
       }
       return response.json()
     },
@@ -883,13 +1872,15 @@ export function useHelmUpgrade() {
   const queryClient = useQueryClient()
 
   return useMutation({
-    mutationFn: async ({ namespace, name, version }: { namespace: string; name: string; version: string }) => {
-      const response = await fetch(`${API_BASE}/helm/releases/${namespace}/${name}/upgrade?version=${encodeURIComponent(version)}`, {
+    mutationFn: async ({ namespace, name, version, repository }: { namespace: string; name: string; version: string; repository?: string }) => {
+      const params = new URLSearchParams({ version })
+      if (repository) params.set('repository', repository) // oci:// charts aren't in any configured repository
+      const response = await fetch(`${API_BASE}/helm/releases/${namespace}/${name}/upgrade?${params.toString()}`, {
         method: 'POST',
       })
       if (!response.ok) {
         const error = await response.json().catch(() => ({ error: 'Unknown error' }))
-        throw new Error(error.error || `HTTP ${response.status}`)
+        throw new ApiError(response.status, error)
       }
       return response.json()
     },
@@ -917,7 +1908,7 @@ export function useHelmPreviewValues() {
       })
       if (!response.ok) {
         const error = await response.json().catch(() => ({ error: 'Unknown error' }))
-        throw new Error(error.error || `HTTP ${response.status}`)
+        throw new ApiError(response.status, error)
       }
       return response.json()
     },
@@ -937,7 +1928,7 @@ export function useHelmApplyValues() {
       })
       if (!response.ok) {
         const error = await response.json().catch(() => ({ error: 'Unknown error' }))
-        throw new Error(error.error || `HTTP ${response.status}`)
+        throw new ApiError(response.status, error)
       }
       return response.json()
     },
@@ -976,7 +1967,7 @@ export function useUpdateRepository() {
       })
       if (!response.ok) {
         const error = await response.json().catch(() => ({ error: 'Unknown error' }))
-        throw new Error(error.error || `HTTP ${response.status}`)
+        throw new ApiError(response.status, error)
       }
       return response.json()
     },
@@ -991,7 +1982,29 @@ export function useUpdateRepository() {
   })
 }
 
-// Search charts across all repositories
+// Log in to the OCI registries listed in a Secret (docker config or username/password)
+export function useHelmRegistryLogin() {
+  return useMutation<RegistryLoginResult, Error, { namespace: string; secret: string; registry?: string; insecure?: boolean }>({
+    mutationFn: async ({ namespace, secret, registry, insecure }) => {
+      const response = await fetch(`${API_BASE}/helm/registries/${namespace}/${secret}/login`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ registry, insecure }),
+      })
+      if (!response.ok) {
+        const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+        throw new ApiError(response.status, error)
+      }
+      return response.json()
+    },
+    meta: {
+      errorMessage: 'Registry login failed',
+      successMessage: 'Logged in to registry',
+    },
+  })
+}
+
+// Search charts across all repositories (an oci:// query lists that chart's versions)
 export function useSearchCharts(query: string, allVersions = false, enabled = true) {
   return useQuery<ChartSearchResult>({
     queryKey: ['helm-charts', query, allVersions],
@@ -1010,6 +2023,12 @@ export function useChartDetail(repo: string, chart: string, version?: string, en
   return useQuery<ChartDetail>({
     queryKey: ['helm-chart-detail', repo, chart, version],
     queryFn: () => {
+      // OCI repositories are oci:// references, which can't be path segments
+      if (repo.startsWith('oci://')) {
+        const params = new URLSearchParams({ ref: `${repo.replace(/\/$/, '')}/${chart}` })
+        if (version) params.set('version', version)
+        return fetchJSON(`/helm/oci/chart?${params.toString()}`)
+      }
       const path = version
         ? `/helm/charts/${repo}/${chart}/${version}`
         : `/helm/charts/${repo}/${chart}`
@@ -1032,7 +2051,7 @@ export function useInstallChart() {
       })
       if (!response.ok) {
         const error = await response.json().catch(() => ({ error: 'Unknown error' }))
-        throw new Error(error.error || `HTTP ${response.status}`)
+        throw new ApiError(response.status, error)
       }
       return response.json() as Promise<HelmRelease>
     },
@@ -1053,6 +2072,7 @@ export interface InstallProgressEvent {
   message?: string
   detail?: string
   release?: HelmRelease
+  fields?: HelmValueError[] // Values schema violations, on error
 }
 
 // Install a chart with progress streaming via SSE
@@ -1102,7 +2122,9 @@ export function installChartWithProgress(
                 if (data.type === 'complete' && data.release) {
                   resolve(data.release)
                 } else if (data.type === 'error') {
-                  reject(new Error(data.message || 'Install failed'))
+                  reject(data.fields
+                    ? new ApiError(400, { error: data.message, code: 'VALIDATION_ERROR', details: { fields: data.fields } })
+                    : new Error(data.message || 'Install failed'))
                 }
               } catch {
                 // Ignore parse errors
@@ -1204,7 +2226,7 @@ export function useSwitchContext() {
 
         if (!response.ok) {
           const error = await response.json().catch(() => ({ error: 'Unknown error' }))
-          throw new Error(error.error || `HTTP ${response.status}`)
+          throw new ApiError(response.status, error)
         }
         return response.json()
       } catch (error) {
@@ -320,6 +320,7 @@ export function ContextSwitcher({ className = '' }: ContextSwitcherProps) {
                       key={item.context.name}
                       onClick={() => handleContextSwitch(item)}
                       disabled={item.context.isCurrent || switchContext.isPending}
+                      title={item.context.file ? `${item.context.name} (from ${item.context.file})` : undefined}
                       className={`
                         w-full flex items-center gap-2 px-3 py-2 text-left
                         transition-colors
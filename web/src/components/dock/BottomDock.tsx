@@ -170,6 +170,7 @@ function TabContent({ tab, isActive }: { tab: DockTab; isActive: boolean }) {
         podName={tab.podName!}
         containerName={tab.containerName!}
         containers={tab.containers!}
+        debugImage={tab.debugImage}
         isActive={isActive}
       />
     )
@@ -11,6 +11,7 @@ export interface DockTab {
   podName?: string
   containerName?: string
   containers?: string[]
+  debugImage?: string // Open the terminal in an ephemeral debug container with this image
   // Logs props
   // (namespace, podName, containers already covered)
 }
@@ -42,7 +43,8 @@ export function DockProvider({ children }: { children: ReactNode }) {
       t.type === tabData.type &&
       t.namespace === tabData.namespace &&
       t.podName === tabData.podName &&
-      t.containerName === tabData.containerName
+      t.containerName === tabData.containerName &&
+      t.debugImage === tabData.debugImage
     )
 
     if (existingTab) {
@@ -127,14 +129,16 @@ export function useOpenTerminal() {
     podName: string
     containerName: string
     containers: string[]
+    debugImage?: string
   }) => {
     addTab({
       type: 'terminal',
-      title: `${opts.podName}/${opts.containerName}`,
+      title: `${opts.podName}/${opts.containerName}${opts.debugImage ? ' (debug)' : ''}`,
       namespace: opts.namespace,
       podName: opts.podName,
       containerName: opts.containerName,
       containers: opts.containers,
+      debugImage: opts.debugImage,
     })
   }
 
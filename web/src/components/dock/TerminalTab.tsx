@@ -12,45 +12,58 @@ interface TerminalTabProps {
   podName: string
   containerName: string
   containers: string[]
+  debugImage?: string // Connect to a new ephemeral debug container targeting the container
   isActive?: boolean
 }
 
 interface TerminalMessage {
-  type: 'input' | 'resize' | 'output' | 'error'
+  type: 'input' | 'resize' | 'output' | 'error' | 'session'
   data?: string
   rows?: number
   cols?: number
 }
 
+// The server keeps a session alive this long after its connection drops
+const REATTACH_WINDOW_MS = 30000
+const REATTACH_RETRY_MS = 2000
+
 export function TerminalTab({
   namespace,
   podName,
   containerName,
   containers,
+  debugImage,
   isActive = true,
 }: TerminalTabProps) {
   const terminalRef = useRef<HTMLDivElement>(null)
   const xtermRef = useRef<XTerm | null>(null)
   const fitAddonRef = useRef<FitAddon | null>(null)
   const wsRef = useRef<WebSocket | null>(null)
+  const closingRef = useRef(false) // Set when we close the socket on purpose
   const [isConnected, setIsConnected] = useState(false)
   const [isConnecting, setIsConnecting] = useState(true)
+  const [isReattaching, setIsReattaching] = useState(false)
   const [error, setError] = useState<string | null>(null)
+  const [shell, setShell] = useState<string | null>(null)
   const [selectedContainer, setSelectedContainer] = useState(containerName)
 
   const connect = useCallback(() => {
     if (!terminalRef.current) return
 
     setIsConnecting(true)
+    setIsReattaching(false)
     setError(null)
+    setShell(null)
 
     // Clean up existing terminal
     if (xtermRef.current) {
       xtermRef.current.dispose()
     }
     if (wsRef.current) {
-      wsRef.current.close()
+      closingRef.current = true
+      wsRef.current.close(1000)
     }
+    closingRef.current = false
 
     // Create terminal
     const xterm = new XTerm({
@@ -110,17 +123,16 @@ export function TerminalTab({
 
     // Connect WebSocket
     const protocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:'
-    const wsUrl = `${protocol}//${window.location.host}/api/pods/${namespace}/${podName}/exec?container=${selectedContainer}`
-
-    const ws = new WebSocket(wsUrl)
-    wsRef.current = ws
+    const wsUrl = debugImage
+      ? `${protocol}//${window.location.host}/api/pods/${namespace}/${podName}/debug?target=${encodeURIComponent(selectedContainer)}&image=${encodeURIComponent(debugImage)}`
+      : `${protocol}//${window.location.host}/api/pods/${namespace}/${podName}/exec?container=${selectedContainer}`
 
-    ws.onopen = () => {
-      setIsConnected(true)
-      setIsConnecting(false)
-      xterm.focus()
+    // Session ID and owner token, for reattaching after a dropped connection
+    let session: { sessionId: string; ownerToken: string } | null = null
+    let reattachDeadline = 0
+    let reattachTimer: ReturnType<typeof setTimeout> | null = null
 
-      // Send initial size
+    const sendSize = (ws: WebSocket) => {
       const msg: TerminalMessage = {
         type: 'resize',
         rows: xterm.rows,
@@ -129,36 +141,76 @@ export function TerminalTab({
       ws.send(JSON.stringify(msg))
     }
 
-    ws.onmessage = (event) => {
-      try {
-        const msg: TerminalMessage = JSON.parse(event.data)
-        if (msg.type === 'output' && msg.data) {
-          xterm.write(msg.data)
-        } else if (msg.type === 'error' && msg.data) {
-          setError(msg.data)
+    const open = (url: string) => {
+      const ws = new WebSocket(url)
+      wsRef.current = ws
+      let sessionEnded = false
+
+      ws.onopen = () => {
+        reattachDeadline = 0
+        setIsConnected(true)
+        setIsConnecting(false)
+        setIsReattaching(false)
+        xterm.focus()
+        sendSize(ws)
+      }
+
+      ws.onmessage = (event) => {
+        try {
+          const msg: TerminalMessage = JSON.parse(event.data)
+          if (msg.type === 'output' && msg.data) {
+            xterm.write(msg.data)
+          } else if (msg.type === 'session' && msg.data) {
+            const info = JSON.parse(msg.data) as { sessionId: string; ownerToken: string; shell?: string }
+            session = { sessionId: info.sessionId, ownerToken: info.ownerToken }
+            if (info.shell) setShell(info.shell)
+          } else if (msg.type === 'error' && msg.data) {
+            sessionEnded = true
+            setError(msg.data)
+            setIsConnected(false)
+          }
+        } catch {
+          // Raw data fallback
+          xterm.write(event.data)
+        }
+      }
+
+      ws.onerror = () => {
+        // Before a session exists there's nothing to reattach to
+        if (!session) {
+          setError('Connection error')
           setIsConnected(false)
+          setIsConnecting(false)
         }
-      } catch {
-        // Raw data fallback
-        xterm.write(event.data)
       }
-    }
 
-    ws.onerror = () => {
-      setError('Connection error')
-      setIsConnected(false)
-      setIsConnecting(false)
+      ws.onclose = (event) => {
+        if (wsRef.current !== ws) return
+        setIsConnected(false)
+        // The server keeps the session for a while after a dropped connection; get back to it
+        const dropped = !closingRef.current && !sessionEnded && event.code !== 1000 && event.code !== 1001
+        if (dropped && session && (reattachDeadline === 0 || Date.now() < reattachDeadline)) {
+          if (reattachDeadline === 0) {
+            reattachDeadline = Date.now() + REATTACH_WINDOW_MS
+            xterm.write('\r\n\x1b[33mConnection lost, reconnecting...\x1b[0m\r\n')
+          }
+          setIsReattaching(true)
+          const attachUrl = `${protocol}//${window.location.host}/api/exec/sessions/${session.sessionId}/attach?token=${encodeURIComponent(session.ownerToken)}`
+          reattachTimer = setTimeout(() => open(attachUrl), REATTACH_RETRY_MS)
+          return
+        }
+        setIsReattaching(false)
+        setIsConnecting(false)
+        xterm.write('\r\n\x1b[31mConnection closed\x1b[0m\r\n')
+      }
     }
 
-    ws.onclose = () => {
-      setIsConnected(false)
-      setIsConnecting(false)
-      xterm.write('\r\n\x1b[31mConnection closed\x1b[0m\r\n')
-    }
+    open(wsUrl)
 
     // Handle input
     xterm.onData((data) => {
-      if (ws.readyState === WebSocket.OPEN) {
+      const ws = wsRef.current
+      if (ws && ws.readyState === WebSocket.OPEN) {
         const msg: TerminalMessage = { type: 'input', data }
         ws.send(JSON.stringify(msg))
       }
@@ -185,13 +237,9 @@ export function TerminalTab({
           if (dims) {
             xtermRef.current.resize(dims.cols, dims.rows)
           }
-          if (ws.readyState === WebSocket.OPEN) {
-            const msg: TerminalMessage = {
-              type: 'resize',
-              rows: xtermRef.current.rows,
-              cols: xtermRef.current.cols,
-            }
-            ws.send(JSON.stringify(msg))
+          const ws = wsRef.current
+          if (ws && ws.readyState === WebSocket.OPEN) {
+            sendSize(ws)
           }
         }
       }, 100)
@@ -200,15 +248,17 @@ export function TerminalTab({
 
     return () => {
       resizeObserver.disconnect()
+      if (reattachTimer) clearTimeout(reattachTimer)
     }
-  }, [namespace, podName, selectedContainer])
+  }, [namespace, podName, selectedContainer, debugImage])
 
   // Connect on mount and when container changes
   useEffect(() => {
     const cleanup = connect()
     return () => {
       cleanup?.()
-      wsRef.current?.close()
+      closingRef.current = true
+      wsRef.current?.close(1000)
       xtermRef.current?.dispose()
     }
   }, [connect])
@@ -234,19 +284,27 @@ export function TerminalTab({
       {/* Mini toolbar */}
       <div className="h-8 flex items-center gap-2 px-2 bg-slate-800/50 border-b border-slate-700/50">
         <Tooltip
-          content={isConnected ? 'Connected to pod' : isConnecting ? 'Connecting...' : 'Disconnected - click Reconnect'}
+          content={isConnected ? 'Connected to pod' : isConnecting ? 'Connecting...' : isReattaching ? 'Connection lost, reconnecting to the session...' : 'Disconnected - click Reconnect'}
           position="bottom"
         >
           <span
             className={clsx(
               'w-2 h-2 rounded-full cursor-help',
-              isConnected ? 'bg-green-500' : isConnecting ? 'bg-yellow-500 animate-pulse' : 'bg-red-500'
+              isConnected ? 'bg-green-500' : isConnecting || isReattaching ? 'bg-yellow-500 animate-pulse' : 'bg-red-500'
             )}
           />
         </Tooltip>
         <span className="text-xs text-slate-400">
           {podName}
         </span>
+        {shell && (
+          <span className="text-xs text-slate-500">{shell}</span>
+        )}
+        {debugImage && (
+          <Tooltip content="Ephemeral debug container; it exits when this session ends" position="bottom">
+            <span className="text-xs text-amber-400 cursor-help">debug: {debugImage}</span>
+          </Tooltip>
+        )}
 
         {containers.length > 1 && (
           <div className="relative">
@@ -265,7 +323,7 @@ export function TerminalTab({
           </div>
         )}
 
-        {!isConnected && !isConnecting && (
+        {!isConnected && !isConnecting && !isReattaching && (
           <button
             onClick={connect}
             className="flex items-center gap-1 px-2 py-0.5 text-xs text-slate-400 hover:text-white hover:bg-slate-700 rounded"
@@ -1,6 +1,6 @@
 import { useState, useCallback, useEffect, useRef } from 'react'
 import { useRefreshAnimation } from '../../hooks/useRefreshAnimation'
-import { X, Copy, Check, RefreshCw, Package, Code, History, FileText, Settings, Link2, Anchor, GitFork, BookOpen, ArrowUpCircle, Trash2 } from 'lucide-react'
+import { X, Copy, Check, RefreshCw, Package, Code, History, FileText, Settings, Link2, Anchor, GitFork, BookOpen, ArrowUpCircle, Trash2, Network } from 'lucide-react'
 import { clsx } from 'clsx'
 import { useHelmRelease, useHelmManifest, useHelmValues, useHelmManifestDiff, useHelmUpgradeInfo, useHelmRollback, useHelmUninstall, useHelmUpgrade } from '../../api/client'
 import { ConfirmDialog } from '../ui/ConfirmDialog'
@@ -12,6 +12,7 @@ import { RevisionHistory } from './RevisionHistory'
 import { ManifestViewer } from './ManifestViewer'
 import { ValuesViewer } from './ValuesViewer'
 import { OwnedResources } from './OwnedResources'
+import { ReleaseMap } from './ReleaseMap'
 import { ManifestDiffViewer } from './ManifestDiffViewer'
 
 interface HelmReleaseDrawerProps {
@@ -20,7 +21,7 @@ interface HelmReleaseDrawerProps {
   onNavigateToResource?: (kind: string, namespace: string, name: string) => void
 }
 
-type TabId = 'overview' | 'history' | 'manifest' | 'values' | 'resources' | 'hooks' | 'diff'
+type TabId = 'overview' | 'history' | 'manifest' | 'values' | 'resources' | 'map' | 'hooks' | 'diff'
 
 const MIN_WIDTH = 500
 const MAX_WIDTH_PERCENT = 0.8
@@ -194,6 +195,7 @@ export function HelmReleaseDrawer({ release, onClose, onNavigateToResource }: He
     { id: 'manifest', label: 'Manifest', icon: Code },
     { id: 'values', label: 'Values', icon: Settings },
     { id: 'resources', label: 'Resources', icon: Link2 },
+    { id: 'map', label: 'Map', icon: Network },
     { id: 'hooks', label: 'Hooks', icon: Anchor },
   ]
 
@@ -352,6 +354,14 @@ export function HelmReleaseDrawer({ release, onClose, onNavigateToResource }: He
             {activeTab === 'resources' && (
               <OwnedResources
                 resources={releaseDetail.resources}
+                drift={releaseDetail.drift}
+                onNavigate={onNavigateToResource}
+              />
+            )}
+            {activeTab === 'map' && (
+              <ReleaseMap
+                namespace={release.namespace}
+                name={release.name}
                 onNavigate={onNavigateToResource}
               />
             )}
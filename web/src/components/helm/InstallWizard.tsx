@@ -4,11 +4,12 @@ import { clsx } from 'clsx'
 import yaml from 'yaml'
 import { createPatch } from 'diff'
 import { useQueryClient } from '@tanstack/react-query'
-import { useChartDetail, useNamespaces, useArtifactHubChart, installChartWithProgress, type InstallProgressEvent } from '../../api/client'
-import type { ChartSource, ChartDetail, ArtifactHubChartDetail } from '../../types'
+import { useChartDetail, useNamespaces, useArtifactHubChart, installChartWithProgress, getHelmValueErrors, type InstallProgressEvent } from '../../api/client'
+import type { ChartSource, ChartDetail, ArtifactHubChartDetail, HelmValueError } from '../../types'
 import { YamlEditor } from '../ui/YamlEditor'
 import { Tooltip } from '../ui/Tooltip'
 import { Markdown } from '../ui/Markdown'
+import { ValueErrorsList } from './ValueErrorsList'
 
 interface InstallWizardProps {
   repo: string
@@ -42,6 +43,7 @@ export function InstallWizard({ repo, chartName, version, source, repoUrl, onClo
   // Install progress state
   const [progressLogs, setProgressLogs] = useState<ProgressEntry[]>([])
   const [installError, setInstallError] = useState<string | null>(null)
+  const [valueErrors, setValueErrors] = useState<HelmValueError[]>([])
   const [isInstalling, setIsInstalling] = useState(false)
   const progressEndRef = useRef<HTMLDivElement>(null)
 
@@ -99,6 +101,7 @@ export function InstallWizard({ repo, chartName, version, source, repoUrl, onClo
     setStep('installing')
     setIsInstalling(true)
     setInstallError(null)
+    setValueErrors([])
     setProgressLogs([])
 
     try {
@@ -140,6 +143,7 @@ export function InstallWizard({ repo, chartName, version, source, repoUrl, onClo
       }, 1500)
     } catch (err) {
       setInstallError(err instanceof Error ? err.message : 'Install failed')
+      setValueErrors(getHelmValueErrors(err))
       setProgressLogs(prev => [...prev, {
         phase: 'error',
         message: err instanceof Error ? err.message : 'Install failed',
@@ -305,6 +309,7 @@ export function InstallWizard({ repo, chartName, version, source, repoUrl, onClo
                   progressLogs={progressLogs}
                   isInstalling={isInstalling}
                   installError={installError}
+                  valueErrors={valueErrors}
                   progressEndRef={progressEndRef}
                 />
               )}
@@ -915,6 +920,7 @@ interface InstallingStepProps {
   progressLogs: ProgressEntry[]
   isInstalling: boolean
   installError: string | null
+  valueErrors: HelmValueError[]
   progressEndRef: React.RefObject<HTMLDivElement | null>
 }
 
@@ -925,6 +931,7 @@ function InstallingStep({
   progressLogs,
   isInstalling,
   installError,
+  valueErrors,
   progressEndRef,
 }: InstallingStepProps) {
   const isComplete = progressLogs.some(l => l.phase === 'complete')
@@ -1017,9 +1024,16 @@ function InstallingStep({
       {installError && (
         <div className="bg-red-500/10 border border-red-500/30 rounded-lg p-4">
           <p className="text-sm font-medium text-red-400 mb-2">Error Details</p>
-          <pre className="text-xs text-red-300 whitespace-pre-wrap font-mono">
-            {installError}
-          </pre>
+          {valueErrors.length > 0 ? (
+            <div className="text-xs text-red-300">
+              Values do not match the chart's schema. Go back and fix:
+              <ValueErrorsList errors={valueErrors} />
+            </div>
+          ) : (
+            <pre className="text-xs text-red-300 whitespace-pre-wrap font-mono">
+              {installError}
+            </pre>
+          )}
         </div>
       )}
     </div>
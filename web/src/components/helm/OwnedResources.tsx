@@ -1,8 +1,8 @@
 import { useState, useCallback } from 'react'
-import { Link2, ExternalLink, AlertCircle, Terminal, FileText, Plug, X, Loader2 } from 'lucide-react'
+import { Link2, ExternalLink, AlertCircle, AlertTriangle, Terminal, FileText, Plug, X, Loader2 } from 'lucide-react'
 import { getResourceIcon } from '../../utils/resource-icons'
 import { clsx } from 'clsx'
-import type { HelmOwnedResource } from '../../types'
+import type { HelmOwnedResource, HelmReleaseDrift } from '../../types'
 import { kindToPlural } from './helm-utils'
 import { getResourceStatusColor, SEVERITY_BADGE } from '../../utils/badge-colors'
 import { useQueryClient } from '@tanstack/react-query'
@@ -13,6 +13,7 @@ import { useCanExec, useCanViewLogs, useCanPortForward } from '../../contexts/Ca
 
 interface OwnedResourcesProps {
   resources: HelmOwnedResource[]
+  drift?: HelmReleaseDrift
   onNavigate?: (kind: string, namespace: string, name: string) => void
 }
 
@@ -62,14 +63,17 @@ function computeHealthSummary(resources: HelmOwnedResource[]) {
   return { healthy, warning, error, unknown, total: resources.length }
 }
 
-export function OwnedResources({ resources, onNavigate }: OwnedResourcesProps) {
+export function OwnedResources({ resources, drift, onNavigate }: OwnedResourcesProps) {
   const [healthFilter, setHealthFilter] = useState<HealthFilter>('all')
 
   if (!resources || resources.length === 0) {
     return (
-      <div className="flex flex-col items-center justify-center h-32 text-theme-text-tertiary gap-2">
-        <Link2 className="w-8 h-8 text-theme-text-disabled" />
-        <span>No owned resources</span>
+      <div className="p-4 space-y-4">
+        {drift && <DriftPanel drift={drift} onNavigate={onNavigate} />}
+        <div className="flex flex-col items-center justify-center h-32 text-theme-text-tertiary gap-2">
+          <Link2 className="w-8 h-8 text-theme-text-disabled" />
+          <span>No owned resources</span>
+        </div>
       </div>
     )
   }
@@ -89,6 +93,8 @@ export function OwnedResources({ resources, onNavigate }: OwnedResourcesProps) {
 
   return (
     <div className="p-4 space-y-4">
+      {drift && <DriftPanel drift={drift} onNavigate={onNavigate} />}
+
       {/* Health summary - clickable badges */}
       <div className="flex items-center justify-between">
         <div className="text-sm text-theme-text-secondary">
@@ -186,6 +192,70 @@ export function OwnedResources({ resources, onNavigate }: OwnedResourcesProps) {
   )
 }
 
+interface DriftPanelProps {
+  drift: HelmReleaseDrift
+  onNavigate?: (kind: string, namespace: string, name: string) => void
+}
+
+// Resources left behind by upgrades, and manifest entries missing from the cluster
+function DriftPanel({ drift, onNavigate }: DriftPanelProps) {
+  if (drift.orphaned.length === 0 && drift.missing.length === 0) return null
+
+  return (
+    <div className="rounded-lg border border-amber-500/30 bg-amber-500/5 p-3 space-y-3">
+      <div className="flex items-center gap-2 text-sm font-medium text-amber-400">
+        <AlertTriangle className="w-4 h-4" />
+        Drift from the release manifest
+      </div>
+      {drift.orphaned.length > 0 && (
+        <div>
+          <div className="text-xs text-theme-text-secondary mb-1">
+            {drift.orphaned.length} orphaned (in the cluster, not in the manifest)
+          </div>
+          <div className="space-y-0.5">
+            {drift.orphaned.map(r => (
+              <button
+                key={`${r.group}/${r.kind}/${r.namespace}/${r.name}`}
+                onClick={() => onNavigate?.(kindToPlural(r.kind), r.namespace || '', r.name)}
+                disabled={!onNavigate}
+                className="flex items-center gap-2 w-full text-left text-xs px-2 py-1 rounded hover:bg-theme-elevated disabled:hover:bg-transparent"
+              >
+                <span className="text-theme-text-tertiary">{r.kind}</span>
+                <span className="text-theme-text-primary truncate">{r.name}</span>
+                {r.lastRevision ? (
+                  <span className="ml-auto text-theme-text-tertiary shrink-0">last in revision {r.lastRevision}</span>
+                ) : (
+                  <span className="ml-auto text-theme-text-tertiary shrink-0">labeled only</span>
+                )}
+              </button>
+            ))}
+          </div>
+        </div>
+      )}
+      {drift.missing.length > 0 && (
+        <div>
+          <div className="text-xs text-theme-text-secondary mb-1">
+            {drift.missing.length} missing (in the manifest, not in the cluster)
+          </div>
+          <div className="space-y-0.5">
+            {drift.missing.map(r => (
+              <div key={`${r.group}/${r.kind}/${r.namespace}/${r.name}`} className="flex items-center gap-2 text-xs px-2 py-1">
+                <span className="text-theme-text-tertiary">{r.kind}</span>
+                <span className="text-theme-text-primary truncate">{r.name}</span>
+              </div>
+            ))}
+          </div>
+        </div>
+      )}
+      {drift.unchecked && drift.unchecked.length > 0 && (
+        <div className="text-xs text-theme-text-tertiary">
+          Not checked: {drift.unchecked.join(', ')}
+        </div>
+      )}
+    </div>
+  )
+}
+
 interface ResourceItemProps {
   resource: HelmOwnedResource
   onNavigate?: (kind: string, namespace: string, name: string) => void
@@ -0,0 +1,59 @@
+import { useHelmReleaseTopology } from '../../api/client'
+import type { TopologyNode } from '../../types'
+import { TopologyGraph } from '../topology/TopologyGraph'
+import { kindToPlural } from './helm-utils'
+
+interface ReleaseMapProps {
+  namespace: string
+  name: string
+  onNavigate?: (kind: string, namespace: string, name: string) => void
+}
+
+// Topology node kinds that are abbreviations of the Kubernetes kind
+const nodeKindToKind: Record<string, string> = {
+  HPA: 'HorizontalPodAutoscaler',
+  PVC: 'PersistentVolumeClaim',
+}
+
+// Mini topology of everything the release owns, pods included
+export function ReleaseMap({ namespace, name, onNavigate }: ReleaseMapProps) {
+  const { data: topology, isLoading, error } = useHelmReleaseTopology(namespace, name)
+
+  if (isLoading) {
+    return <div className="flex items-center justify-center h-32 text-theme-text-tertiary">Loading...</div>
+  }
+  if (error || !topology) {
+    return <div className="flex items-center justify-center h-32 text-theme-text-tertiary">Failed to load the release map</div>
+  }
+
+  const handleNodeClick = (node: TopologyNode) => {
+    if (!onNavigate || node.kind === 'PodGroup' || node.kind === 'Internet') return
+    const kind = nodeKindToKind[node.kind] || node.kind
+    onNavigate(kindToPlural(kind), (node.data.namespace as string) || '', node.name)
+  }
+
+  return (
+    <div className="flex flex-col h-full">
+      {topology.nodes.length === 0 ? (
+        <div className="flex items-center justify-center h-32 text-theme-text-tertiary">
+          None of this release's resources appear in the topology
+        </div>
+      ) : (
+        <div className="flex-1 min-h-[480px] relative">
+          <TopologyGraph
+            topology={topology}
+            viewMode="resources"
+            groupingMode="none"
+            hideGroupHeader
+            onNodeClick={handleNodeClick}
+          />
+        </div>
+      )}
+      {topology.unmapped.length > 0 && (
+        <div className="px-4 py-2 border-t border-theme-border text-xs text-theme-text-tertiary">
+          Not shown: {topology.unmapped.map(r => `${r.kind}/${r.name}`).join(', ')}
+        </div>
+      )}
+    </div>
+  )
+}
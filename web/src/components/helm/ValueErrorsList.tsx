@@ -0,0 +1,20 @@
+import type { HelmValueError } from '../../types'
+
+interface ValueErrorsListProps {
+  errors: HelmValueError[]
+}
+
+// Lists values.schema.json violations by field path
+export function ValueErrorsList({ errors }: ValueErrorsListProps) {
+  return (
+    <ul className="mt-2 space-y-1">
+      {errors.map((e, i) => (
+        <li key={`${e.chart}-${e.path}-${i}`} className="flex gap-2 font-mono">
+          <span className="text-red-300 shrink-0">{e.path || '(root)'}</span>
+          <span className="text-red-400/80">{e.message}</span>
+          {e.chart && <span className="text-theme-text-tertiary">({e.chart})</span>}
+        </li>
+      ))}
+    </ul>
+  )
+}
@@ -36,8 +36,7 @@ export function ValuesDiffPreview({
     }
   }, [])
 
-  const hasChanges = previewData.manifestDiff.trim().length > 0 &&
-    previewData.manifestDiff.split('\n').some(line => line.startsWith('+') || line.startsWith('-'))
+  const hasChanges = previewData.manifestChanges.documents.length > 0
 
   return (
     <div className="fixed inset-0 z-50 flex items-center justify-center">
@@ -5,8 +5,9 @@ import yaml from 'yaml'
 import type { HelmValues, ValuesPreviewResponse } from '../../types'
 import { CodeViewer } from '../ui/CodeViewer'
 import { YamlEditor } from '../ui/YamlEditor'
-import { useHelmPreviewValues, useHelmApplyValues } from '../../api/client'
+import { useHelmPreviewValues, useHelmApplyValues, getHelmValueErrors } from '../../api/client'
 import { ValuesDiffPreview } from './ValuesDiffPreview'
+import { ValueErrorsList } from './ValueErrorsList'
 
 interface ValuesViewerProps {
   values?: HelmValues
@@ -40,6 +41,7 @@ export function ValuesViewer({
 
   const previewMutation = useHelmPreviewValues()
   const applyMutation = useHelmApplyValues()
+  const valueErrors = getHelmValueErrors(previewMutation.error || applyMutation.error)
 
   const canEdit = Boolean(namespace && name)
 
@@ -255,7 +257,14 @@ export function ValuesViewer({
       {/* Mutation error */}
       {(previewMutation.error || applyMutation.error) && (
         <div className="mb-3 px-3 py-2 text-xs text-red-400 bg-red-500/10 border border-red-500/30 rounded">
-          {previewMutation.error?.message || applyMutation.error?.message}
+          {valueErrors.length > 0 ? (
+            <>
+              Values do not match the chart's schema:
+              <ValueErrorsList errors={valueErrors} />
+            </>
+          ) : (
+            previewMutation.error?.message || applyMutation.error?.message
+          )}
         </div>
       )}
 
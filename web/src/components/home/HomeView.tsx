@@ -1,11 +1,13 @@
 import { useDashboard } from '../../api/client'
 import type { DashboardResponse } from '../../api/client'
+import { useDashboardStream } from '../../hooks/useDashboardStream'
 import type { ExtendedMainView, Topology, SelectedResource } from '../../types'
 import { TopologyPreview } from './TopologyPreview'
 import { HelmSummary } from './HelmSummary'
 import { ActivitySummary } from './ActivitySummary'
 import { TrafficSummary } from './TrafficSummary'
 import { ClusterHealthCard } from './ClusterHealthCard'
+import { QuotaSummary } from './QuotaSummary'
 import { AlertTriangle, Loader2 } from 'lucide-react'
 import { clsx } from 'clsx'
 
@@ -19,6 +21,7 @@ interface HomeViewProps {
 
 export function HomeView({ namespace, topology, onNavigateToView, onNavigateToResourceKind, onNavigateToResource }: HomeViewProps) {
   const { data, isLoading, error } = useDashboard(namespace || undefined)
+  useDashboardStream(namespace || undefined)
 
   if (isLoading) {
     return (
@@ -80,6 +83,12 @@ export function HomeView({ namespace, topology, onNavigateToView, onNavigateToRe
               data={data.trafficSummary}
               onNavigate={() => onNavigateToView('traffic')}
             />
+            {data.quotas && data.quotas.length > 0 && (
+              <QuotaSummary
+                quotas={data.quotas}
+                onNavigate={() => onNavigateToResourceKind('resourcequotas')}
+              />
+            )}
           </div>
 
           {/* Right column: problems panel */}
@@ -0,0 +1,58 @@
+import type { QuotaUtilization } from '../../api/client'
+import { Gauge } from 'lucide-react'
+import { clsx } from 'clsx'
+
+interface QuotaSummaryProps {
+  quotas: QuotaUtilization[]
+  onNavigate: () => void
+}
+
+function getBarColor(percent: number): string {
+  if (percent >= 90) return 'bg-red-500'
+  if (percent >= 75) return 'bg-yellow-500'
+  return 'bg-green-500'
+}
+
+export function QuotaSummary({ quotas, onNavigate }: QuotaSummaryProps) {
+  return (
+    <button
+      onClick={onNavigate}
+      className="group flex flex-col h-[260px] rounded-lg border-[3px] border-amber-500/30 bg-theme-surface/50 hover:-translate-y-1 hover:shadow-[0_12px_24px_rgba(0,0,0,0.12)] hover:border-amber-500/60 transition-all duration-200 text-left cursor-pointer"
+    >
+      <div className="flex items-center justify-between px-4 py-2 border-b border-theme-border">
+        <div className="flex items-center gap-2">
+          <Gauge className="w-4 h-4 text-amber-500" />
+          <span className="text-sm font-semibold text-amber-500">Resource Quotas</span>
+          <span className="text-[11px] bg-amber-500/10 px-1.5 py-0.5 rounded text-amber-500">
+            {quotas.length}
+          </span>
+        </div>
+      </div>
+
+      <div className="flex-1 min-h-0 overflow-y-auto divide-y divide-theme-border">
+        {quotas.map((q) => (
+          <div key={`${q.namespace}/${q.name}`} className="px-3 py-1.5 space-y-1">
+            <div className="flex items-center gap-2 min-w-0">
+              <span className="text-xs text-theme-text-primary truncate">{q.name}</span>
+              <span className="text-[10px] text-theme-text-tertiary">{q.namespace}</span>
+            </div>
+            {q.resources.map((r) => (
+              <div key={r.resource} className="flex items-center gap-2 text-[10px]">
+                <span className="w-24 shrink-0 text-theme-text-secondary truncate">{r.resource}</span>
+                <div className="flex-1 h-1.5 rounded-full bg-theme-elevated overflow-hidden">
+                  <div
+                    className={clsx('h-full rounded-full', getBarColor(r.percent))}
+                    style={{ width: `${Math.min(r.percent, 100)}%` }}
+                  />
+                </div>
+                <span className="w-24 shrink-0 text-right text-theme-text-tertiary">
+                  {r.used} / {r.hard}
+                </span>
+              </div>
+            ))}
+          </div>
+        ))}
+      </div>
+    </button>
+  )
+}
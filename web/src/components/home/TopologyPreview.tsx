@@ -128,6 +128,7 @@ const kindDotColors: Record<string, string> = {
   Job: 'bg-purple-400', CronJob: 'bg-purple-400',
   ConfigMap: 'bg-amber-400', Secret: 'bg-red-400',
   ReplicaSet: 'bg-green-400', HPA: 'bg-pink-500', PVC: 'bg-cyan-400',
+  NetworkPolicy: 'bg-sky-400',
 }
 
 export function TopologyPreview({ topology, summary, onNavigate }: TopologyPreviewProps) {
@@ -15,12 +15,15 @@ import {
   Save,
   XCircle,
   AlertTriangle,
+  ArrowLeft,
+  GitCompare,
 } from 'lucide-react'
 import { clsx } from 'clsx'
 import { stringify as yamlStringify } from 'yaml'
-import { useResource, useResourceEvents, useUpdateResource, useDeleteResource, useTriggerCronJob, useSuspendCronJob, useResumeCronJob, useRestartWorkload } from '../../api/client'
+import { useResource, useResourceEvents, useUpdateResource, usePreviewResourceUpdate, useDeleteResource, useTriggerCronJob, useSuspendCronJob, useResumeCronJob, useRerunJob, useRestartWorkload } from '../../api/client'
 import { ConfirmDialog } from '../ui/ConfirmDialog'
-import type { SelectedResource, Relationships, ResourceRef } from '../../types'
+import { DiffViewer } from '../timeline/DiffViewer'
+import type { SelectedResource, Relationships, ResourceRef, UpdatePreview } from '../../types'
 import {
   getPodStatus,
   getWorkloadStatus,
@@ -53,6 +56,7 @@ import {
 import {
   PodRenderer,
   WorkloadRenderer,
+  WorkloadImagesSection,
   ReplicaSetRenderer,
   ServiceRenderer,
   IngressRenderer,
@@ -106,12 +110,14 @@ export function ResourceDetailDrawer({ resource, onClose, onNavigate }: Resource
   const [editedYaml, setEditedYaml] = useState('')
   const [yamlErrors, setYamlErrors] = useState<string[]>([])
   const [saveSuccess, setSaveSuccess] = useState(false)
+  const [preview, setPreview] = useState<UpdatePreview | null>(null)
   const [drawerWidth, setDrawerWidth] = useState(DEFAULT_WIDTH)
   const [isResizing, setIsResizing] = useState(false)
   const resizeStartX = useRef(0)
   const resizeStartWidth = useRef(DEFAULT_WIDTH)
 
   const updateResource = useUpdateResource()
+  const previewUpdate = usePreviewResourceUpdate()
 
   const { data: resourceData, relationships, isLoading, refetch: refetchResource } = useResource<any>(
     resource.kind,
@@ -203,17 +209,45 @@ export function ResourceDetailDrawer({ resource, onClose, onNavigate }: Resource
   const handleStartEdit = useCallback(() => {
     setEditedYaml(convertToYaml(resourceData))
     setYamlErrors([])
+    setPreview(null)
+    previewUpdate.reset()
+    updateResource.reset()
     setIsEditing(true)
-  }, [resourceData, convertToYaml])
+  }, [resourceData, convertToYaml, previewUpdate, updateResource])
 
   // Cancel editing
   const handleCancelEdit = useCallback(() => {
     setIsEditing(false)
     setEditedYaml('')
     setYamlErrors([])
+    setPreview(null)
   }, [])
 
-  // Save changes
+  // Dry-run the edit on the server and show what would change before applying
+  const handlePreviewEdit = useCallback(async () => {
+    if (yamlErrors.length > 0) return
+
+    updateResource.reset()
+    try {
+      const result = await previewUpdate.mutateAsync({
+        kind: resource.kind,
+        namespace: resource.namespace,
+        name: resource.name,
+        yaml: editedYaml,
+      })
+      setPreview(result)
+    } catch (error) {
+      // Error is shown in the editor
+    }
+  }, [previewUpdate, updateResource, resource, editedYaml, yamlErrors])
+
+  // Return from the diff to the editor, keeping the edits
+  const handleBackToEdit = useCallback(() => {
+    setPreview(null)
+    updateResource.reset()
+  }, [updateResource])
+
+  // Apply the previewed changes
   const handleSaveEdit = useCallback(async () => {
     if (yamlErrors.length > 0) return
 
@@ -226,6 +260,7 @@ export function ResourceDetailDrawer({ resource, onClose, onNavigate }: Resource
       })
       setIsEditing(false)
       setEditedYaml('')
+      setPreview(null)
       setSaveSuccess(true)
       // Small delay to allow K8s to process the update before refreshing
       setTimeout(() => {
@@ -294,9 +329,13 @@ export function ResourceDetailDrawer({ resource, onClose, onNavigate }: Resource
             onValidate={handleYamlValidate}
             yamlErrors={yamlErrors}
             isSaving={updateResource.isPending}
-            saveError={updateResource.error?.message}
+            isPreviewing={previewUpdate.isPending}
+            saveError={(updateResource.error ?? previewUpdate.error)?.message}
+            preview={preview}
             onStartEdit={handleStartEdit}
             onCancelEdit={handleCancelEdit}
+            onPreviewEdit={handlePreviewEdit}
+            onBackToEdit={handleBackToEdit}
             onSaveEdit={handleSaveEdit}
           />
         ) : (
@@ -422,6 +461,10 @@ function ActionsBar({ resource, data, onClose }: ActionsBarProps) {
   const triggerCronJobMutation = useTriggerCronJob()
   const suspendCronJobMutation = useSuspendCronJob()
   const resumeCronJobMutation = useResumeCronJob()
+  const rerunJobMutation = useRerunJob()
+  const jobFinished = (data?.status?.conditions || []).some(
+    (c: { type: string; status: string }) => (c.type === 'Complete' || c.type === 'Failed') && c.status === 'True'
+  )
 
   // Workload restart mutation
   const restartWorkloadMutation = useRestartWorkload()
@@ -564,6 +607,21 @@ function ActionsBar({ resource, data, onClose }: ActionsBarProps) {
         </>
       )}
 
+      {/* Re-run a finished Job */}
+      {kind === 'jobs' && jobFinished && (
+        <button
+          onClick={() => rerunJobMutation.mutate({
+            namespace: resource.namespace,
+            name: resource.name,
+          })}
+          disabled={rerunJobMutation.isPending}
+          className="flex items-center gap-1.5 px-3 py-1.5 text-xs font-medium text-white bg-blue-600 hover:bg-blue-700 rounded-lg transition-colors disabled:opacity-50"
+        >
+          <Play className={`w-3.5 h-3.5 ${rerunJobMutation.isPending ? 'animate-pulse' : ''}`} />
+          {rerunJobMutation.isPending ? 'Re-running...' : 'Re-run'}
+        </button>
+      )}
+
       {/* Job logs */}
       {kind === 'jobs' && (
         <button
@@ -636,9 +694,13 @@ interface YamlViewProps {
   onValidate: (isValid: boolean, errors: string[]) => void
   yamlErrors: string[]
   isSaving: boolean
+  isPreviewing: boolean
   saveError?: string
+  preview: UpdatePreview | null
   onStartEdit: () => void
   onCancelEdit: () => void
+  onPreviewEdit: () => void
+  onBackToEdit: () => void
   onSaveEdit: () => void
 }
 
@@ -712,9 +774,13 @@ function YamlView({
   onValidate,
   yamlErrors,
   isSaving,
+  isPreviewing,
   saveError,
+  preview,
   onStartEdit,
   onCancelEdit,
+  onPreviewEdit,
+  onBackToEdit,
   onSaveEdit,
 }: YamlViewProps) {
   const [showErrorDetails, setShowErrorDetails] = useState(false)
@@ -731,31 +797,62 @@ function YamlView({
         {/* Edit mode header */}
         <div className="flex items-center justify-between px-4 py-2 border-b border-theme-border bg-theme-elevated/50">
           <div className="flex items-center gap-2">
-            <Pencil className="w-4 h-4 text-blue-400" />
-            <span className="text-sm font-medium text-theme-text-primary">Editing Resource</span>
-          </div>
-          <div className="flex items-center gap-2">
-            <button
-              onClick={onCancelEdit}
-              disabled={isSaving}
-              className="flex items-center gap-1 px-3 py-1.5 text-xs text-theme-text-secondary hover:text-theme-text-primary hover:bg-theme-surface rounded border border-theme-border disabled:opacity-50"
-            >
-              <XCircle className="w-3.5 h-3.5" />
-              Cancel
-            </button>
-            <button
-              onClick={onSaveEdit}
-              disabled={isSaving || yamlErrors.length > 0}
-              className="flex items-center gap-1 px-3 py-1.5 text-xs text-white bg-blue-600 hover:bg-blue-700 rounded disabled:opacity-50 disabled:cursor-not-allowed"
-            >
-              {isSaving ? (
-                <RefreshCw className="w-3.5 h-3.5 animate-spin" />
-              ) : (
-                <Save className="w-3.5 h-3.5" />
-              )}
-              {isSaving ? 'Saving...' : 'Save'}
-            </button>
+            {preview ? (
+              <GitCompare className="w-4 h-4 text-blue-400" />
+            ) : (
+              <Pencil className="w-4 h-4 text-blue-400" />
+            )}
+            <span className="text-sm font-medium text-theme-text-primary">
+              {preview ? 'Review Changes' : 'Editing Resource'}
+            </span>
           </div>
+          {preview ? (
+            <div className="flex items-center gap-2">
+              <button
+                onClick={onBackToEdit}
+                disabled={isSaving}
+                className="flex items-center gap-1 px-3 py-1.5 text-xs text-theme-text-secondary hover:text-theme-text-primary hover:bg-theme-surface rounded border border-theme-border disabled:opacity-50"
+              >
+                <ArrowLeft className="w-3.5 h-3.5" />
+                Back to editing
+              </button>
+              <button
+                onClick={onSaveEdit}
+                disabled={isSaving || preview.diff.fields.length === 0}
+                className="flex items-center gap-1 px-3 py-1.5 text-xs text-white bg-blue-600 hover:bg-blue-700 rounded disabled:opacity-50 disabled:cursor-not-allowed"
+              >
+                {isSaving ? (
+                  <RefreshCw className="w-3.5 h-3.5 animate-spin" />
+                ) : (
+                  <Save className="w-3.5 h-3.5" />
+                )}
+                {isSaving ? 'Applying...' : 'Apply'}
+              </button>
+            </div>
+          ) : (
+            <div className="flex items-center gap-2">
+              <button
+                onClick={onCancelEdit}
+                disabled={isPreviewing}
+                className="flex items-center gap-1 px-3 py-1.5 text-xs text-theme-text-secondary hover:text-theme-text-primary hover:bg-theme-surface rounded border border-theme-border disabled:opacity-50"
+              >
+                <XCircle className="w-3.5 h-3.5" />
+                Cancel
+              </button>
+              <button
+                onClick={onPreviewEdit}
+                disabled={isPreviewing || yamlErrors.length > 0}
+                className="flex items-center gap-1 px-3 py-1.5 text-xs text-white bg-blue-600 hover:bg-blue-700 rounded disabled:opacity-50 disabled:cursor-not-allowed"
+              >
+                {isPreviewing ? (
+                  <RefreshCw className="w-3.5 h-3.5 animate-spin" />
+                ) : (
+                  <GitCompare className="w-3.5 h-3.5" />
+                )}
+                {isPreviewing ? 'Checking...' : 'Review'}
+              </button>
+            </div>
+          )}
         </div>
 
         {/* Resource-specific warning */}
@@ -801,7 +898,7 @@ function YamlView({
             <div className="flex items-start gap-2">
               <AlertTriangle className="w-4 h-4 text-red-600 dark:text-red-300 mt-0.5 shrink-0" />
               <div className="text-xs text-red-600 dark:text-red-300 flex-1">
-                <div className="font-medium">Save failed</div>
+                <div className="font-medium">{preview ? 'Save failed' : 'Dry run failed'}</div>
                 <div className="mt-1">{formattedError.summary}</div>
                 {formattedError.details && (
                   <button
@@ -821,16 +918,31 @@ function YamlView({
           </div>
         )}
 
-        {/* Editor */}
-        <div className="flex-1 min-h-0">
-          <YamlEditor
-            value={editedYaml}
-            onChange={onEditedYamlChange}
-            onValidate={onValidate}
-            height="100%"
-            kind={kind}
-          />
-        </div>
+        {/* Dry-run diff, or the editor */}
+        {preview ? (
+          <div className="flex-1 min-h-0 overflow-y-auto p-4">
+            {preview.diff.fields.length === 0 ? (
+              <div className="text-sm text-theme-text-tertiary">No changes — the edited YAML matches the live resource.</div>
+            ) : (
+              <>
+                <div className="mb-3 text-xs text-theme-text-tertiary">
+                  The API server accepted these changes in a dry run. Apply to save them.
+                </div>
+                <DiffViewer diff={preview.diff} />
+              </>
+            )}
+          </div>
+        ) : (
+          <div className="flex-1 min-h-0">
+            <YamlEditor
+              value={editedYaml}
+              onChange={onEditedYamlChange}
+              onValidate={onValidate}
+              height="100%"
+              kind={kind}
+            />
+          </div>
+        )}
       </div>
     )
   }
@@ -916,6 +1028,7 @@ function ResourceContent({ resource, data, relationships, onCopy, copied, onNavi
       {kind === 'secrets' && <SecretRenderer data={data} />}
       {kind === 'jobs' && <JobRenderer data={data} />}
       {kind === 'cronjobs' && <CronJobRenderer data={data} />}
+      <WorkloadImagesSection kind={kind} namespace={resource.namespace} name={resource.name} />
       {(kind === 'hpas' || kind === 'horizontalpodautoscalers') && <HPARenderer data={data} />}
       {kind === 'nodes' && <NodeRenderer data={data} />}
       {kind === 'persistentvolumeclaims' && <PVCRenderer data={data} />}
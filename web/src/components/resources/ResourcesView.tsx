@@ -1,5 +1,6 @@
 import { useState, useMemo, useEffect, useCallback, useRef, forwardRef } from 'react'
 import { useRefreshAnimation } from '../../hooks/useRefreshAnimation'
+import { useResourceListSync } from '../../hooks/useResourceListSync'
 import { useLocation } from 'react-router-dom'
 import { useQueries } from '@tanstack/react-query'
 import {
@@ -21,6 +22,7 @@ import {
 import { clsx } from 'clsx'
 import type { SelectedResource, APIResource } from '../../types'
 import { useAPIResources, categorizeResources, CORE_RESOURCES } from '../../api/apiResources'
+import { useResourceTable, type ResourceTable } from '../../api/client'
 import {
   getPodStatus,
   getPodReadiness,
@@ -475,6 +477,28 @@ function getColumnsForKind(kind: string): Column[] {
   return KNOWN_COLUMNS[kind.toLowerCase()] || DEFAULT_COLUMNS
 }
 
+// Printer column cells are keyed "printer:<index>" into the CRD's column list
+const PRINTER_COLUMN_PREFIX = 'printer:'
+
+// Columns for a custom resource from its CRD's additionalPrinterColumns (the ones
+// kubectl get shows by default, not -o wide)
+function getPrinterColumns(table: ResourceTable): Column[] {
+  const columns: Column[] = [
+    { key: 'name', label: 'Name' },
+    { key: 'namespace', label: 'Namespace', width: 'w-48' },
+  ]
+  table.columns.forEach((col, i) => {
+    if (col.priority) return
+    columns.push({
+      key: `${PRINTER_COLUMN_PREFIX}${i}`,
+      label: col.name,
+      width: col.type === 'date' ? 'w-24' : 'w-36',
+      tooltip: col.description,
+    })
+  })
+  return columns
+}
+
 interface ResourcesViewProps {
   namespace: string
   selectedResource?: SelectedResource | null
@@ -773,6 +797,9 @@ export function ResourcesView({ namespace, selectedResource, onResourceClick, on
     )
   }, [resourcesToCount, selectedKind.name, selectedKind.group])
 
+  // Live updates for the list on screen (initial list, then JSON Patch deltas)
+  useResourceListSync(selectedKind.name, selectedKind.group, namespace)
+
   const selectedQuery = resourceQueries[selectedQueryIndex]
   const resources = selectedQuery?.data
   const isLoading = selectedQuery?.isLoading ?? true
@@ -1195,7 +1222,19 @@ export function ResourcesView({ namespace, selectedResource, onResourceClick, on
     })
   }
 
-  const columns = getColumnsForKind(selectedKind.name)
+  // CRDs without hand-written columns use the columns their CRD declares
+  const selectedIsCrd = !KNOWN_COLUMNS[selectedKind.name.toLowerCase()] &&
+    (apiResources?.some(r => r.name === selectedKind.name && r.group === selectedKind.group && r.isCrd) ?? false)
+  const { data: printerTable } = useResourceTable(selectedKind.name, namespace, selectedKind.group, selectedIsCrd)
+  const printerCells = useMemo(() => {
+    if (!selectedIsCrd || !printerTable) return undefined
+    const cells = new Map<string, unknown[]>()
+    for (const row of printerTable.rows) {
+      cells.set(`${row.namespace || ''}/${row.name}`, row.cells)
+    }
+    return cells
+  }, [selectedIsCrd, printerTable])
+  const columns = selectedIsCrd && printerTable ? getPrinterColumns(printerTable) : getColumnsForKind(selectedKind.name)
 
   // Calculate filter options with counts based on current resources (before filtering)
   const filterOptions = useMemo(() => {
@@ -1747,6 +1786,8 @@ export function ResourcesView({ namespace, selectedResource, onResourceClick, on
                       resource={resource}
                       kind={selectedKind.name}
                       columns={columns}
+                      printerTable={printerTable}
+                      printerCells={printerCells?.get(`${resource.metadata?.namespace || ''}/${resource.metadata?.name}`)}
                       isSelected={isSelected}
                       onClick={() => onResourceClick?.(selectedKind.name, resource.metadata?.namespace || '', resource.metadata?.name, selectedKind.group)}
                     />
@@ -1804,12 +1845,14 @@ interface ResourceRowProps {
   resource: any
   kind: string
   columns: Column[]
+  printerTable?: ResourceTable
+  printerCells?: unknown[]
   isSelected?: boolean
   onClick?: () => void
 }
 
 const ResourceRow = forwardRef<HTMLTableRowElement, ResourceRowProps>(
-  function ResourceRow({ resource, kind, columns, isSelected, onClick }, ref) {
+  function ResourceRow({ resource, kind, columns, printerTable, printerCells, isSelected, onClick }, ref) {
     return (
       <tr
         ref={ref}
@@ -1830,7 +1873,14 @@ const ResourceRow = forwardRef<HTMLTableRowElement, ResourceRowProps>(
             col.hideOnMobile && 'hidden xl:table-cell'
           )}
         >
-          <CellContent resource={resource} kind={kind} column={col.key} />
+          {col.key.startsWith(PRINTER_COLUMN_PREFIX) && printerTable ? (
+            <PrinterCell
+              column={printerTable.columns[Number(col.key.slice(PRINTER_COLUMN_PREFIX.length))]}
+              value={printerCells?.[Number(col.key.slice(PRINTER_COLUMN_PREFIX.length))]}
+            />
+          ) : (
+            <CellContent resource={resource} kind={kind} column={col.key} />
+          )}
         </td>
       ))}
       </tr>
@@ -1941,6 +1991,19 @@ function CellContent({ resource, kind, column }: CellContentProps) {
   }
 }
 
+// Cell for a CRD printer column; dates show as ages like kubectl does
+function PrinterCell({ column, value }: { column?: ResourceTable['columns'][number]; value: unknown }) {
+  if (value === null || value === undefined || value === '') {
+    return <span className="text-sm text-theme-text-tertiary">-</span>
+  }
+  const text = column?.type === 'date' ? formatAge(String(value)) : String(value)
+  return (
+    <Tooltip content={String(value)}>
+      <span className="text-sm text-theme-text-secondary truncate block">{text}</span>
+    </Tooltip>
+  )
+}
+
 // Generic cell renderer for CRDs and unknown resources
 function GenericCell({ resource, column }: { resource: any; column: string }) {
   switch (column) {
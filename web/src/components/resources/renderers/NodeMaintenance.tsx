@@ -0,0 +1,206 @@
+import { useEffect, useRef, useState } from 'react'
+import { Wrench, Loader2, CheckCircle2, XCircle, AlertTriangle, Clock } from 'lucide-react'
+import { clsx } from 'clsx'
+import { Section } from '../drawer-components'
+import { ConfirmDialog } from '../../ui/ConfirmDialog'
+import { useSetNodeSchedulable, previewDrain, runDrain, type DrainEvent, type DrainPlan } from '../../../api/client'
+
+interface NodeMaintenanceProps {
+  nodeName: string
+  unschedulable: boolean
+}
+
+// Per-pod drain state, from the latest event about the pod
+type PodDrainState = 'pending' | 'evicted' | 'blocked' | 'stuck' | 'deleted' | 'failed'
+
+const stateStyles: Record<PodDrainState, string> = {
+  pending: 'text-theme-text-tertiary',
+  evicted: 'text-blue-400',
+  blocked: 'text-yellow-400',
+  stuck: 'text-orange-400',
+  deleted: 'text-green-400',
+  failed: 'text-red-400',
+}
+
+// Cordon/uncordon and drain a node, with the drain's progress streamed from the server
+export function NodeMaintenance({ nodeName, unschedulable }: NodeMaintenanceProps) {
+  const schedulable = useSetNodeSchedulable()
+  const [force, setForce] = useState(false)
+  const [deleteEmptyDirData, setDeleteEmptyDirData] = useState(false)
+  const [plan, setPlan] = useState<DrainPlan | null>(null)
+  const [error, setError] = useState<string | null>(null)
+  const [confirming, setConfirming] = useState(false)
+  const [running, setRunning] = useState(false)
+  const [podStates, setPodStates] = useState<Record<string, { state: PodDrainState; message?: string }>>({})
+  const [done, setDone] = useState<DrainEvent | null>(null)
+  const abortRef = useRef<AbortController | null>(null)
+
+  // Stop streaming (and the drain) when the drawer closes
+  useEffect(() => () => abortRef.current?.abort(), [])
+
+  const options = { force, deleteEmptyDirData }
+
+  const handlePreview = async () => {
+    setError(null)
+    setDone(null)
+    setPodStates({})
+    try {
+      setPlan(await previewDrain(nodeName, options))
+    } catch (e) {
+      setError(e instanceof Error ? e.message : String(e))
+    }
+  }
+
+  const handleDrain = async () => {
+    setConfirming(false)
+    setRunning(true)
+    setError(null)
+    setDone(null)
+    setPodStates({})
+    const controller = new AbortController()
+    abortRef.current = controller
+    try {
+      await runDrain(nodeName, options, (e) => {
+        if (e.type === 'plan' && e.plan) {
+          setPlan(e.plan)
+        } else if (e.type === 'done') {
+          setDone(e)
+        } else if (e.pod && e.type !== 'cordoned') {
+          const key = `${e.pod.namespace}/${e.pod.name}`
+          setPodStates((prev) => ({ ...prev, [key]: { state: e.type as PodDrainState, message: e.message } }))
+        }
+      }, controller.signal)
+    } catch (e) {
+      if (!controller.signal.aborted) {
+        setError(e instanceof Error ? e.message : String(e))
+      }
+    } finally {
+      setRunning(false)
+      abortRef.current = null
+    }
+  }
+
+  const problems = plan?.problems ?? []
+
+  return (
+    <Section title="Maintenance" icon={Wrench} defaultExpanded={false}>
+      <div className="space-y-3">
+        <div className="flex flex-wrap items-center gap-2">
+          <button
+            onClick={() => schedulable.mutate({ name: nodeName, unschedulable: !unschedulable })}
+            disabled={schedulable.isPending || running}
+            className="px-2.5 py-1 text-xs rounded bg-theme-elevated hover:bg-theme-hover text-theme-text-primary disabled:opacity-50"
+          >
+            {unschedulable ? 'Uncordon' : 'Cordon'}
+          </button>
+          <button
+            onClick={handlePreview}
+            disabled={running}
+            className="px-2.5 py-1 text-xs rounded bg-theme-elevated hover:bg-theme-hover text-theme-text-primary disabled:opacity-50"
+          >
+            Preview drain
+          </button>
+          {running ? (
+            <button
+              onClick={() => abortRef.current?.abort()}
+              className="px-2.5 py-1 text-xs rounded bg-red-500/20 hover:bg-red-500/30 text-red-400"
+            >
+              Stop drain
+            </button>
+          ) : (
+            <button
+              onClick={() => setConfirming(true)}
+              disabled={!plan || problems.length > 0}
+              className="px-2.5 py-1 text-xs rounded bg-red-500/20 hover:bg-red-500/30 text-red-400 disabled:opacity-50"
+            >
+              Drain
+            </button>
+          )}
+        </div>
+
+        <div className="flex flex-wrap gap-4 text-xs text-theme-text-secondary">
+          <label className="flex items-center gap-1.5">
+            <input type="checkbox" checked={force} onChange={(e) => setForce(e.target.checked)} disabled={running} />
+            Evict pods without a controller
+          </label>
+          <label className="flex items-center gap-1.5">
+            <input type="checkbox" checked={deleteEmptyDirData} onChange={(e) => setDeleteEmptyDirData(e.target.checked)} disabled={running} />
+            Delete emptyDir data
+          </label>
+        </div>
+
+        {error && <div className="text-xs text-red-400">{error}</div>}
+
+        {problems.length > 0 && (
+          <ul className="text-xs text-yellow-400 space-y-1">
+            {problems.map((p) => (
+              <li key={p} className="flex items-start gap-1.5">
+                <AlertTriangle className="w-3.5 h-3.5 mt-0.5 shrink-0" />
+                <span>{p}</span>
+              </li>
+            ))}
+          </ul>
+        )}
+
+        {plan && (
+          <div className="space-y-1 text-xs">
+            <div className="text-theme-text-tertiary">
+              {plan.evict.length} pod{plan.evict.length === 1 ? '' : 's'} to evict
+              {plan.skipped?.length ? `, ${plan.skipped.length} skipped` : ''}
+            </div>
+            {plan.evict.map((pod) => {
+              const key = `${pod.namespace}/${pod.name}`
+              const status = podStates[key] ?? { state: 'pending' as PodDrainState }
+              return (
+                <div key={key} className="flex items-center gap-2">
+                  <PodStateIcon state={status.state} />
+                  <span className="text-theme-text-primary truncate">{key}</span>
+                  <span className={clsx('shrink-0', stateStyles[status.state])}>{status.state}</span>
+                  {status.message && <span className="text-theme-text-tertiary truncate" title={status.message}>{status.message}</span>}
+                </div>
+              )
+            })}
+            {plan.skipped?.map((pod) => (
+              <div key={`${pod.namespace}/${pod.name}`} className="flex items-center gap-2 text-theme-text-tertiary">
+                <span className="w-3.5" />
+                <span className="truncate">{pod.namespace}/{pod.name}</span>
+                <span className="shrink-0">skipped: {pod.reason}</span>
+              </div>
+            ))}
+          </div>
+        )}
+
+        {done && (
+          <div className={clsx('text-xs', done.completed ? 'text-green-400' : 'text-red-400')}>{done.message}</div>
+        )}
+      </div>
+
+      <ConfirmDialog
+        open={confirming}
+        onClose={() => setConfirming(false)}
+        onConfirm={handleDrain}
+        title="Drain Node"
+        message={`Cordon "${nodeName}" and evict its pods?`}
+        details="Evictions honor PodDisruptionBudgets: blocked pods are retried until their budget allows them to go. The node stays cordoned afterwards."
+        confirmLabel="Drain"
+        variant="warning"
+      />
+    </Section>
+  )
+}
+
+function PodStateIcon({ state }: { state: PodDrainState }) {
+  switch (state) {
+    case 'deleted':
+      return <CheckCircle2 className="w-3.5 h-3.5 text-green-400 shrink-0" />
+    case 'failed':
+      return <XCircle className="w-3.5 h-3.5 text-red-400 shrink-0" />
+    case 'blocked':
+    case 'stuck':
+      return <AlertTriangle className={clsx('w-3.5 h-3.5 shrink-0', stateStyles[state])} />
+    case 'evicted':
+      return <Loader2 className="w-3.5 h-3.5 text-blue-400 shrink-0 animate-spin" />
+    default:
+      return <Clock className="w-3.5 h-3.5 text-theme-text-tertiary shrink-0" />
+  }
+}
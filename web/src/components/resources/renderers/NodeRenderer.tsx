@@ -1,9 +1,11 @@
+import { useState } from 'react'
 import { Server, HardDrive, Globe, AlertTriangle, Tag, Activity } from 'lucide-react'
 import { clsx } from 'clsx'
 import { Section, PropertyList, Property, ConditionsSection } from '../drawer-components'
-import { useNodeMetrics, useNodeMetricsHistory } from '../../../api/client'
-import { MetricsChart } from '../../ui/MetricsChart'
+import { useNodeMetrics, useNodeMetricsHistory, useAnnotations } from '../../../api/client'
+import { MetricsChart, MetricsRangePicker } from '../../ui/MetricsChart'
 import { formatMemoryString } from '../../../utils/format'
+import { NodeMaintenance } from './NodeMaintenance'
 
 interface NodeRendererProps {
   data: any
@@ -75,7 +77,9 @@ export function NodeRenderer({ data }: NodeRendererProps) {
   // Fetch node metrics (current and historical)
   const nodeName = metadata.name
   const { data: metrics } = useNodeMetrics(nodeName)
-  const { data: metricsHistory } = useNodeMetricsHistory(nodeName)
+  const [metricsRange, setMetricsRange] = useState('')
+  const { data: metricsHistory } = useNodeMetricsHistory(nodeName, metricsRange)
+  const { data: annotations } = useAnnotations()
 
   // Extract platform info from labels
   const instanceType = labels['node.kubernetes.io/instance-type']
@@ -153,6 +157,11 @@ export function NodeRenderer({ data }: NodeRendererProps) {
       {/* Resource Usage (from metrics-server) */}
       {(metrics?.usage || metricsHistory?.dataPoints?.length) && (
         <Section title="Resource Usage" icon={Activity} defaultExpanded>
+          {metricsHistory?.persistent && (
+            <div className="flex justify-end mb-2">
+              <MetricsRangePicker value={metricsRange} onChange={setMetricsRange} />
+            </div>
+          )}
           {metricsHistory?.dataPoints && metricsHistory.dataPoints.length > 0 ? (
             <div className="space-y-4">
               {/* CPU Usage with Chart */}
@@ -165,6 +174,7 @@ export function NodeRenderer({ data }: NodeRendererProps) {
                 </div>
                 <MetricsChart
                   dataPoints={metricsHistory.dataPoints}
+                  markers={annotations}
                   type="cpu"
                   height={60}
                   showAxis={true}
@@ -181,6 +191,7 @@ export function NodeRenderer({ data }: NodeRendererProps) {
                 </div>
                 <MetricsChart
                   dataPoints={metricsHistory.dataPoints}
+                  markers={annotations}
                   type="memory"
                   height={60}
                   showAxis={true}
@@ -270,6 +281,9 @@ export function NodeRenderer({ data }: NodeRendererProps) {
 
       {/* Conditions */}
       <ConditionsSection conditions={status.conditions} />
+
+      {/* Cordon, uncordon and drain */}
+      <NodeMaintenance nodeName={nodeName} unschedulable={Boolean(spec.unschedulable)} />
     </>
   )
 }
@@ -1,6 +1,8 @@
-import { HardDrive, AlertTriangle } from 'lucide-react'
+import { useState } from 'react'
+import { HardDrive, AlertTriangle, Maximize2 } from 'lucide-react'
 import { clsx } from 'clsx'
 import { Section, PropertyList, Property, ConditionsSection } from '../drawer-components'
+import { usePVCUsage, useExpandPVC } from '../../../api/client'
 
 interface PVCRendererProps {
   data: any
@@ -18,11 +20,29 @@ function formatAccessModes(modes: string[] | undefined): string | undefined {
   return modes.map(m => accessModeShorthand[m] || m).join(', ')
 }
 
+function formatBytes(bytes: number): string {
+  const units = ['B', 'KiB', 'MiB', 'GiB', 'TiB']
+  let value = bytes
+  let i = 0
+  while (value >= 1024 && i < units.length - 1) {
+    value /= 1024
+    i++
+  }
+  return `${value.toFixed(i === 0 ? 0 : 1)} ${units[i]}`
+}
+
 export function PVCRenderer({ data }: PVCRendererProps) {
   const status = data.status || {}
   const spec = data.spec || {}
   const annotations = data.metadata?.annotations || {}
   const phase = status.phase
+  const namespace = data.metadata?.namespace
+  const name = data.metadata?.name
+
+  const { data: usageData } = usePVCUsage(namespace, phase === 'Bound')
+  const usage = usageData?.pvcs.find(u => u.name === name)
+  const expandMutation = useExpandPVC()
+  const [newSize, setNewSize] = useState('')
 
   // Problem detection
   const isLost = phase === 'Lost'
@@ -95,6 +115,59 @@ export function PVCRenderer({ data }: PVCRendererProps) {
         </PropertyList>
       </Section>
 
+      {usage && (
+        <Section title="Usage">
+          {usage.usedPercent !== undefined && (
+            <div className="mb-3">
+              <div className="flex justify-between text-xs text-theme-text-secondary mb-1">
+                <span>{formatBytes(usage.usedBytes || 0)} of {formatBytes(usage.capacityBytes || 0)}</span>
+                <span>{usage.usedPercent.toFixed(1)}%</span>
+              </div>
+              <div className="h-2 bg-theme-elevated rounded-full overflow-hidden">
+                <div
+                  className={clsx(
+                    'h-full rounded-full',
+                    usage.status === 'critical' ? 'bg-red-500' : usage.status === 'warning' ? 'bg-yellow-500' : 'bg-green-500',
+                  )}
+                  style={{ width: `${Math.min(usage.usedPercent, 100)}%` }}
+                />
+              </div>
+            </div>
+          )}
+          <PropertyList>
+            <Property label="Inodes Used" value={usage.inodesUsedPercent !== undefined ? `${usage.inodesUsedPercent.toFixed(1)}%` : undefined} />
+            <Property label="Status" value={usage.status === 'unknown' ? 'Not mounted (no kubelet stats)' : usage.message} />
+            <Property label="Resizing" value={usage.resizing} />
+            <Property label="Expandable" value={usage.expandable ? 'Yes' : 'No (storage class)'} />
+          </PropertyList>
+          {usage.expandable && !usage.resizing && (
+            <form
+              className="mt-3 flex items-center gap-2"
+              onSubmit={(e) => {
+                e.preventDefault()
+                if (!newSize) return
+                expandMutation.mutate({ namespace, name, size: newSize }, { onSuccess: () => setNewSize('') })
+              }}
+            >
+              <input
+                value={newSize}
+                onChange={(e) => setNewSize(e.target.value)}
+                placeholder={`New size (now ${spec.resources?.requests?.storage || '?'})`}
+                className="flex-1 px-2 py-1.5 text-xs bg-theme-base border border-theme-border rounded-lg text-theme-text-primary"
+              />
+              <button
+                type="submit"
+                disabled={!newSize || expandMutation.isPending}
+                className="flex items-center gap-1.5 px-3 py-1.5 text-xs font-medium text-white bg-blue-600 hover:bg-blue-700 rounded-lg transition-colors disabled:opacity-50"
+              >
+                <Maximize2 className="w-3.5 h-3.5" />
+                {expandMutation.isPending ? 'Expanding...' : 'Expand'}
+              </button>
+            </form>
+          )}
+        </Section>
+      )}
+
       {hasProvisionerInfo && (
         <Section title="Provisioner Info">
           <PropertyList>
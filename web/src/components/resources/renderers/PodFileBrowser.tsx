@@ -0,0 +1,180 @@
+import { useRef, useState } from 'react'
+import { Folder, File, Link2, Download, Upload, ChevronRight, RefreshCw, Loader2 } from 'lucide-react'
+import { useQueryClient } from '@tanstack/react-query'
+import { usePodFiles, podFileDownloadUrl, uploadPodFile, type PodFileEntry } from '../../../api/client'
+import { formatBytes } from '../resource-utils'
+import { showApiError, showApiSuccess } from '../../ui/Toast'
+
+interface PodFileBrowserProps {
+  namespace: string
+  podName: string
+  containers: string[]
+}
+
+interface UploadProgress {
+  name: string
+  loaded: number
+  total: number
+}
+
+function joinPath(dir: string, name: string): string {
+  return dir === '/' ? `/${name}` : `${dir}/${name}`
+}
+
+// Browse a running container's filesystem: download files (directories as .tar.gz) and
+// upload files or tar archives into the current directory
+export function PodFileBrowser({ namespace, podName, containers }: PodFileBrowserProps) {
+  const [container, setContainer] = useState(containers[0] || '')
+  const [path, setPath] = useState('/')
+  const [extract, setExtract] = useState(false)
+  const [upload, setUpload] = useState<UploadProgress | null>(null)
+  const inputRef = useRef<HTMLInputElement>(null)
+  const queryClient = useQueryClient()
+  const { data, isLoading, error, refetch, isFetching } = usePodFiles(namespace, podName, container, path)
+
+  const segments = path.split('/').filter(Boolean)
+
+  const download = (entry: PodFileEntry) => {
+    const a = document.createElement('a')
+    a.href = podFileDownloadUrl(namespace, podName, container, joinPath(path, entry.name))
+    a.download = ''
+    a.click()
+  }
+
+  const handleUpload = async (files: FileList | null) => {
+    if (!files?.length) return
+    try {
+      for (const file of Array.from(files)) {
+        setUpload({ name: file.name, loaded: 0, total: file.size })
+        const result = await uploadPodFile(namespace, podName, container, path, file, {
+          extract,
+          onProgress: (loaded, total) => setUpload({ name: file.name, loaded, total }),
+        })
+        showApiSuccess(`Uploaded ${file.name}`, `${result.files} file${result.files === 1 ? '' : 's'} (${formatBytes(result.bytes)}) to ${result.path}`)
+      }
+    } catch (err) {
+      showApiError('Upload failed', err instanceof Error ? err.message : String(err))
+    } finally {
+      setUpload(null)
+      if (inputRef.current) inputRef.current.value = ''
+      queryClient.invalidateQueries({ queryKey: ['pod-files', namespace, podName, container, path] })
+    }
+  }
+
+  return (
+    <div className="space-y-2">
+      <div className="flex items-center gap-2">
+        {containers.length > 1 && (
+          <select
+            value={container}
+            onChange={(e) => setContainer(e.target.value)}
+            className="text-xs bg-theme-elevated border border-theme-border rounded px-2 py-1 text-theme-text-primary"
+          >
+            {containers.map((c) => (
+              <option key={c} value={c}>{c}</option>
+            ))}
+          </select>
+        )}
+        <div className="flex items-center gap-0.5 text-xs font-mono min-w-0 flex-1 overflow-x-auto">
+          <button onClick={() => setPath('/')} className="text-blue-400 hover:underline">/</button>
+          {segments.map((seg, i) => (
+            <span key={i} className="flex items-center gap-0.5">
+              {i > 0 && <ChevronRight className="w-3 h-3 text-theme-text-tertiary" />}
+              <button
+                onClick={() => setPath('/' + segments.slice(0, i + 1).join('/'))}
+                className="text-blue-400 hover:underline whitespace-nowrap"
+              >
+                {seg}
+              </button>
+            </span>
+          ))}
+        </div>
+        <button
+          onClick={() => refetch()}
+          className="p-1 text-slate-400 hover:text-blue-400 rounded"
+          title="Refresh"
+        >
+          <RefreshCw className={`w-3.5 h-3.5 ${isFetching ? 'animate-spin' : ''}`} />
+        </button>
+        <button
+          onClick={() => inputRef.current?.click()}
+          disabled={!!upload}
+          className="flex items-center gap-1 px-2 py-1 text-xs rounded bg-blue-500/20 text-blue-400 hover:bg-blue-500/30 disabled:opacity-50"
+          title={`Upload into ${path}`}
+        >
+          <Upload className="w-3.5 h-3.5" />
+          Upload
+        </button>
+        <input ref={inputRef} type="file" multiple className="hidden" onChange={(e) => handleUpload(e.target.files)} />
+      </div>
+
+      <label className="flex items-center gap-1.5 text-xs text-theme-text-tertiary">
+        <input type="checkbox" checked={extract} onChange={(e) => setExtract(e.target.checked)} />
+        Extract uploaded .tar / .tar.gz archives
+      </label>
+
+      {upload && (
+        <div className="text-xs text-theme-text-secondary">
+          <div className="flex justify-between mb-1">
+            <span className="truncate">Uploading {upload.name}</span>
+            <span>{formatBytes(upload.loaded)} / {formatBytes(upload.total)}</span>
+          </div>
+          <div className="h-1.5 bg-theme-elevated rounded overflow-hidden">
+            <div
+              className="h-full bg-blue-500 transition-all"
+              style={{ width: `${upload.total ? Math.min(100, (upload.loaded / upload.total) * 100) : 0}%` }}
+            />
+          </div>
+        </div>
+      )}
+
+      {isLoading ? (
+        <div className="flex items-center gap-2 text-xs text-theme-text-tertiary py-2">
+          <Loader2 className="w-3.5 h-3.5 animate-spin" /> Loading...
+        </div>
+      ) : error ? (
+        <div className="text-xs text-red-400 py-2">{error.message}</div>
+      ) : (
+        <div className="max-h-80 overflow-y-auto rounded border border-theme-border divide-y divide-theme-border">
+          {path !== '/' && (
+            <button
+              onClick={() => setPath(path.substring(0, path.lastIndexOf('/')) || '/')}
+              className="w-full text-left px-2 py-1 text-xs font-mono text-theme-text-secondary hover:bg-theme-elevated/50"
+            >
+              ..
+            </button>
+          )}
+          {data?.entries.length === 0 && (
+            <div className="px-2 py-1 text-xs text-theme-text-tertiary">Empty directory</div>
+          )}
+          {data?.entries.map((entry) => {
+            const Icon = entry.type === 'dir' ? Folder : entry.type === 'symlink' ? Link2 : File
+            return (
+              <div key={entry.name} className="flex items-center gap-2 px-2 py-1 text-xs hover:bg-theme-elevated/50 group">
+                <Icon className={`w-3.5 h-3.5 shrink-0 ${entry.type === 'dir' ? 'text-blue-400' : 'text-theme-text-tertiary'}`} />
+                {entry.type === 'dir' ? (
+                  <button onClick={() => setPath(joinPath(path, entry.name))} className="font-mono text-theme-text-primary hover:underline truncate text-left flex-1">
+                    {entry.name}
+                  </button>
+                ) : (
+                  <span className="font-mono text-theme-text-primary truncate flex-1">{entry.name}</span>
+                )}
+                <span className="font-mono text-theme-text-tertiary hidden sm:inline">{entry.mode}</span>
+                <span className="text-theme-text-tertiary w-16 text-right">{entry.type === 'dir' ? '' : formatBytes(entry.size)}</span>
+                {entry.type !== 'other' && (
+                  <button
+                    onClick={() => download(entry)}
+                    className="p-0.5 text-slate-400 hover:text-blue-400 opacity-0 group-hover:opacity-100"
+                    title={entry.type === 'dir' ? 'Download as .tar.gz' : 'Download'}
+                  >
+                    <Download className="w-3.5 h-3.5" />
+                  </button>
+                )}
+              </div>
+            )
+          })}
+        </div>
+      )}
+    </div>
+  )
+}
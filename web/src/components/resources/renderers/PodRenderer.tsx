@@ -1,13 +1,16 @@
-import { Server, HardDrive, Terminal as TerminalIcon, FileText, AlertTriangle, Activity } from 'lucide-react'
+import { useState } from 'react'
+import { Server, HardDrive, Terminal as TerminalIcon, FileText, AlertTriangle, Activity, FolderOpen, Bug } from 'lucide-react'
 import { clsx } from 'clsx'
 import { Section, PropertyList, Property, ConditionsSection, CopyHandler } from '../drawer-components'
 import { formatResources } from '../resource-utils'
 import { PortForwardInlineButton } from '../../portforward/PortForwardButton'
 import { useOpenTerminal, useOpenLogs } from '../../dock'
 import { Tooltip } from '../../ui/Tooltip'
-import { useCanExec, useCanViewLogs, useCanPortForward } from '../../../contexts/CapabilitiesContext'
-import { usePodMetrics, usePodMetricsHistory } from '../../../api/client'
-import { MetricsChart } from '../../ui/MetricsChart'
+import { useCanExec, useCanViewLogs, useCanPortForward, useDebugImages } from '../../../contexts/CapabilitiesContext'
+import { usePodMetrics, usePodMetricsHistory, useAnnotations } from '../../../api/client'
+import { MetricsChart, MetricsRangePicker } from '../../ui/MetricsChart'
+import { useLivePodMetrics } from '../../../hooks/useLivePodMetrics'
+import { PodFileBrowser } from './PodFileBrowser'
 
 interface PodRendererProps {
   data: any
@@ -94,10 +97,16 @@ export function PodRenderer({ data, onCopy, copied }: PodRendererProps) {
   const canExec = useCanExec()
   const canViewLogs = useCanViewLogs()
   const canPortForward = useCanPortForward()
+  const debugImages = useDebugImages()
+  const [debugMenuFor, setDebugMenuFor] = useState<string | null>(null)
 
   // Fetch pod metrics (current and historical)
   const { data: metrics } = usePodMetrics(namespace, podName)
-  const { data: metricsHistory } = usePodMetricsHistory(namespace, podName)
+  const [metricsRange, setMetricsRange] = useState('')
+  const { data: metricsHistory } = usePodMetricsHistory(namespace, podName, metricsRange)
+  const [liveMetrics, setLiveMetrics] = useState(false)
+  const live = useLivePodMetrics(namespace, podName, liveMetrics)
+  const { data: annotations } = useAnnotations(namespace)
 
   // Check for problems
   const problems = getPodProblems(data)
@@ -115,6 +124,20 @@ export function PodRenderer({ data, onCopy, copied }: PodRendererProps) {
     }
   }
 
+  // Debug terminal in an ephemeral container, for images without a shell
+  const handleOpenDebug = (containerName: string, image: string) => {
+    setDebugMenuFor(null)
+    if (namespace && podName) {
+      openTerminal({
+        namespace,
+        podName,
+        containerName,
+        containers: containers.map((c: { name: string }) => c.name),
+        debugImage: image,
+      })
+    }
+  }
+
   const handleOpenLogs = (containerName?: string) => {
     if (namespace && podName) {
       openLogs({
@@ -205,6 +228,32 @@ export function PodRenderer({ data, onCopy, copied }: PodRendererProps) {
                         <TerminalIcon className="w-4 h-4" />
                       </button>
                     )}
+                    {isRunning && debugImages.length > 0 && (
+                      <div className="relative">
+                        <button
+                          onClick={() => debugImages.length === 1
+                            ? handleOpenDebug(container.name, debugImages[0])
+                            : setDebugMenuFor(debugMenuFor === container.name ? null : container.name)}
+                          className="p-1 text-slate-400 hover:text-amber-400 hover:bg-slate-600/50 rounded transition-colors"
+                          title={`Debug ${container.name} in an ephemeral container (for images without a shell)`}
+                        >
+                          <Bug className="w-4 h-4" />
+                        </button>
+                        {debugMenuFor === container.name && (
+                          <div className="absolute right-0 top-full mt-1 z-10 min-w-48 py-1 bg-theme-elevated border border-theme-border rounded shadow-lg">
+                            {debugImages.map((image) => (
+                              <button
+                                key={image}
+                                onClick={() => handleOpenDebug(container.name, image)}
+                                className="block w-full text-left px-3 py-1 text-xs font-mono text-theme-text-primary hover:bg-theme-hover"
+                              >
+                                {image}
+                              </button>
+                            ))}
+                          </div>
+                        )}
+                      </div>
+                    )}
                     {canViewLogs && (
                       <button
                         onClick={() => handleOpenLogs(container.name)}
@@ -307,11 +356,39 @@ export function PodRenderer({ data, onCopy, copied }: PodRendererProps) {
         </div>
       </Section>
 
+      {/* File browser (exec into a running container) */}
+      {isRunning && canExec && namespace && podName && (
+        <Section title="Files" icon={FolderOpen} defaultExpanded={false}>
+          <PodFileBrowser
+            namespace={namespace}
+            podName={podName}
+            containers={containers.map((c: { name: string }) => c.name)}
+          />
+        </Section>
+      )}
+
       {/* Resource Usage (from metrics-server) */}
       {(metrics?.containers?.length || metricsHistory?.containers?.length) && (
         <Section title="Resource Usage" icon={Activity} defaultExpanded>
           <div className="space-y-4">
-            {(metricsHistory?.containers || metrics?.containers || []).map((historyContainer) => {
+            <div className="flex items-center justify-end gap-2">
+              {liveMetrics && (live.error || live.source) && (
+                <span className="text-xs text-theme-text-tertiary">{live.error || `via ${live.source}`}</span>
+              )}
+              <button
+                onClick={() => setLiveMetrics(!liveMetrics)}
+                className={clsx(
+                  'px-1.5 py-0.5 rounded text-xs',
+                  liveMetrics ? 'bg-green-500/20 text-green-400' : 'text-theme-text-tertiary hover:text-theme-text-primary'
+                )}
+              >
+                Live
+              </button>
+              {metricsHistory?.persistent && !liveMetrics && (
+                <MetricsRangePicker value={metricsRange} onChange={setMetricsRange} />
+              )}
+            </div>
+            {((liveMetrics && live.containers.length > 0 ? live.containers : metricsHistory?.containers) || metrics?.containers || []).map((historyContainer) => {
               // Find current metrics for this container
               const currentMetrics = metrics?.containers?.find(c => c.name === historyContainer.name)
               // Find the container spec to compare against limits
@@ -334,6 +411,7 @@ export function PodRenderer({ data, onCopy, copied }: PodRendererProps) {
                         <div className="text-xs text-theme-text-tertiary mb-2">CPU</div>
                         <MetricsChart
                           dataPoints={dataPoints}
+                          markers={annotations}
                           type="cpu"
                           height={80}
                           showAxis={true}
@@ -345,6 +423,7 @@ export function PodRenderer({ data, onCopy, copied }: PodRendererProps) {
                         <div className="text-xs text-theme-text-tertiary mb-2">Memory</div>
                         <MetricsChart
                           dataPoints={dataPoints}
+                          markers={annotations}
                           type="memory"
                           height={80}
                           showAxis={true}
@@ -1,6 +1,7 @@
 import { useState } from 'react'
 import { AlertTriangle } from 'lucide-react'
 import { Section, PropertyList, Property } from '../drawer-components'
+import { useCapabilitiesContext } from '../../../contexts/CapabilitiesContext'
 
 interface SecretRendererProps {
   data: any
@@ -8,6 +9,7 @@ interface SecretRendererProps {
 
 export function SecretRenderer({ data }: SecretRendererProps) {
   const [revealed, setRevealed] = useState<Set<string>>(new Set())
+  const { secretsMetadataOnly } = useCapabilitiesContext()
   const dataKeys = Object.keys(data.data || {})
 
   const toggleReveal = (key: string) => {
@@ -31,8 +33,8 @@ export function SecretRenderer({ data }: SecretRendererProps) {
     <>
       <Section title="Secret">
         <PropertyList>
-          <Property label="Type" value={data.type || 'Opaque'} />
-          <Property label="Keys" value={String(dataKeys.length)} />
+          <Property label="Type" value={data.type || (secretsMetadataOnly ? 'Unknown' : 'Opaque')} />
+          {!secretsMetadataOnly && <Property label="Keys" value={String(dataKeys.length)} />}
           {data.immutable && <Property label="Immutable" value="Yes" />}
         </PropertyList>
       </Section>
@@ -58,7 +60,9 @@ export function SecretRenderer({ data }: SecretRendererProps) {
             </div>
           ))}
           {dataKeys.length === 0 && (
-            <div className="text-sm text-theme-text-tertiary">No data</div>
+            <div className="text-sm text-theme-text-tertiary">
+              {secretsMetadataOnly ? 'Values are not loaded (Radar is watching secret metadata only)' : 'No data'}
+            </div>
           )}
         </div>
       </Section>
@@ -0,0 +1,74 @@
+import { Package, AlertTriangle } from 'lucide-react'
+import { clsx } from 'clsx'
+import { Section } from '../drawer-components'
+import { useWorkloadImages, type ImageFreshness } from '../../../api/client'
+
+// Drawer kinds and the workload kind image badges are reported under
+const workloadKinds: Record<string, string> = {
+  deployments: 'Deployment',
+  statefulsets: 'StatefulSet',
+  daemonsets: 'DaemonSet',
+  cronjobs: 'CronJob',
+  jobs: 'Job',
+  rollouts: 'Rollout',
+}
+
+const freshnessStyles: Record<ImageFreshness, string> = {
+  fresh: 'bg-green-500/10 text-green-400 border-green-500/30',
+  aging: 'bg-yellow-500/10 text-yellow-400 border-yellow-500/30',
+  old: 'bg-red-500/10 text-red-400 border-red-500/30',
+  unknown: 'bg-theme-elevated text-theme-text-secondary border-theme-border',
+}
+
+interface WorkloadImagesSectionProps {
+  kind: string
+  namespace: string
+  name: string
+}
+
+// Image freshness and CVE badges for a workload; hidden when image inspection is off
+export function WorkloadImagesSection({ kind, namespace, name }: WorkloadImagesSectionProps) {
+  const workloadKind = workloadKinds[kind]
+  const { data, isError } = useWorkloadImages(namespace, Boolean(workloadKind))
+  const workload = data?.workloads.find(w => w.kind === workloadKind && w.name === name)
+  if (!workloadKind || isError || !workload) return null
+
+  const vulns = workload.vulnerabilities
+  return (
+    <Section title="Images" icon={Package}>
+      <div className="flex flex-wrap items-center gap-2 mb-3">
+        <span className={clsx('px-2 py-0.5 text-xs rounded border', freshnessStyles[workload.freshness])}>
+          {workload.freshness === 'unknown' ? 'Build age unknown' : `Images ${workload.freshness}`}
+        </span>
+        {workload.outdated && (
+          <span className="flex items-center gap-1 px-2 py-0.5 text-xs rounded border bg-yellow-500/10 text-yellow-400 border-yellow-500/30">
+            <AlertTriangle className="w-3 h-3" />
+            Tag moved since pods started
+          </span>
+        )}
+        {vulns && (
+          <span
+            className={clsx(
+              'px-2 py-0.5 text-xs rounded border',
+              vulns.critical > 0 ? 'bg-red-500/10 text-red-400 border-red-500/30'
+                : vulns.high > 0 ? 'bg-orange-500/10 text-orange-400 border-orange-500/30'
+                : 'bg-green-500/10 text-green-400 border-green-500/30',
+            )}
+            title={`${vulns.medium} medium, ${vulns.low} low, ${vulns.unknown} unknown (${vulns.source})`}
+          >
+            {vulns.critical} critical, {vulns.high} high CVEs
+          </span>
+        )}
+      </div>
+      <ul className="space-y-1 text-xs">
+        {workload.containers.map(c => (
+          <li key={`${c.container}/${c.image}`} className="flex items-center gap-2 min-w-0">
+            <span className="text-theme-text-secondary shrink-0">{c.container}</span>
+            <span className="truncate text-theme-text-primary" title={c.runningDigest || c.image}>{c.image}</span>
+            {c.outdated && <span className="text-yellow-400 shrink-0">outdated</span>}
+          </li>
+        ))}
+      </ul>
+    </Section>
+  )
+}
@@ -1,5 +1,6 @@
 export { PodRenderer } from './PodRenderer'
 export { WorkloadRenderer } from './WorkloadRenderer'
+export { WorkloadImagesSection } from './WorkloadImages'
 export { ReplicaSetRenderer } from './ReplicaSetRenderer'
 export { ServiceRenderer } from './ServiceRenderer'
 export { IngressRenderer } from './IngressRenderer'
@@ -514,7 +514,8 @@ function ActivityCard({ item, expanded, onToggle, onResourceClick }: ActivityCar
   const time = formatTime(item.timestamp)
 
   // Only expandable if there's a diff to show
-  const hasExpandableContent = isChange && !!item.diff
+  const occurrences = item.aggregate?.occurrences ?? []
+  const hasExpandableContent = (isChange && !!item.diff) || occurrences.length > 1
 
   // Determine card styling based on type
   const getCardStyle = () => {
@@ -626,6 +627,23 @@ function ActivityCard({ item, expanded, onToggle, onResourceClick }: ActivityCar
                   <span className="text-sm text-theme-text-secondary">
                     {item.message}
                   </span>
+                  {item.annotation && (
+                    <span className="text-xs text-theme-text-tertiary" title={item.annotation.text}>
+                      by {item.annotation.author}
+                    </span>
+                  )}
+                  {item.annotation?.links?.map((link) => (
+                    <a
+                      key={link.url}
+                      href={link.url}
+                      target="_blank"
+                      rel="noopener noreferrer"
+                      onClick={(e) => e.stopPropagation()}
+                      className="text-xs text-blue-400 hover:underline"
+                    >
+                      {link.title || link.url}
+                    </a>
+                  ))}
                 </>
               )}
             </div>
@@ -637,6 +655,14 @@ function ActivityCard({ item, expanded, onToggle, onResourceClick }: ActivityCar
             {item.count && item.count > 1 && (
               <div className="text-xs text-theme-text-disabled mt-1">x{item.count}</div>
             )}
+            {item.aggregate && item.count && item.count > 1 && (
+              <div
+                className="text-xs text-theme-text-disabled"
+                title={`First seen ${new Date(item.aggregate.firstSeen).toLocaleString()}\nLast seen ${new Date(item.aggregate.lastSeen).toLocaleString()}`}
+              >
+                {item.aggregate.ratePerHour ? `${formatRate(item.aggregate.ratePerHour)}/h · ` : ''}first {formatTime(item.aggregate.firstSeen)}
+              </div>
+            )}
           </div>
 
           {/* Expand indicator - only show if there's content to expand */}
@@ -654,6 +680,22 @@ function ActivityCard({ item, expanded, onToggle, onResourceClick }: ActivityCar
             <DiffViewer diff={item.diff} />
           </div>
         )}
+
+        {/* Expanded occurrences - the raw Events folded into an aggregate */}
+        {expanded && occurrences.length > 1 && (
+          <div className="mt-3 pt-3 border-t-subtle">
+            <div className="text-xs text-theme-text-tertiary mb-2">Occurrences:</div>
+            <div className="space-y-1">
+              {[...occurrences].reverse().map((o) => (
+                <div key={o.name} className="flex items-start gap-2 text-xs">
+                  <span className="text-theme-text-tertiary shrink-0 w-16">{formatTime(o.timestamp)}</span>
+                  {o.count > 1 && <span className="text-theme-text-disabled shrink-0">x{o.count}</span>}
+                  <span className="text-theme-text-secondary truncate" title={o.message}>{o.message}</span>
+                </div>
+              ))}
+            </div>
+          </div>
+        )}
       </div>
     </div>
   )
@@ -785,6 +827,10 @@ function AggregatedActivityCard({ first, last, count, reason, expanded, onToggle
   )
 }
 
+function formatRate(perHour: number): string {
+  return perHour >= 10 ? Math.round(perHour).toString() : perHour.toFixed(1)
+}
+
 function formatTime(timestamp: string): string {
   if (!timestamp) return '-'
   const date = new Date(timestamp)
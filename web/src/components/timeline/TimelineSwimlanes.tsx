@@ -23,7 +23,7 @@ import {
   RotateCcw,
 } from 'lucide-react'
 import type { TimelineEvent, Topology } from '../../types'
-import { isChangeEvent, isHistoricalEvent, isOperation } from '../../types'
+import { isAnnotationEvent, isChangeEvent, isHistoricalEvent, isOperation } from '../../types'
 import { DiffViewer } from './DiffViewer'
 import { getOperationColor, getHealthBadgeColor, getEventTypeColor } from '../../utils/badge-colors'
 import { Tooltip } from '../ui/Tooltip'
@@ -44,6 +44,7 @@ interface TimelineSwimlanesProps {
   onViewModeChange?: (mode: 'list' | 'swimlane') => void
   topology?: Topology
   namespace?: string
+  annotations?: TimelineEvent[] // Markers drawn across all lanes; defaults to the annotation events in events
 }
 
 interface ResourceLane extends BaseResourceLane {
@@ -161,7 +162,7 @@ function calculateInterestingnessWithBreakdown(lane: ResourceLane): ScoreBreakdo
   return breakdown
 }
 
-export function TimelineSwimlanes({ events, isLoading, onResourceClick, viewMode, onViewModeChange, topology, namespace }: TimelineSwimlanesProps) {
+export function TimelineSwimlanes({ events, isLoading, onResourceClick, viewMode, onViewModeChange, topology, namespace, annotations }: TimelineSwimlanesProps) {
   const containerRef = useRef<HTMLDivElement>(null)
   const searchInputRef = useRef<HTMLInputElement>(null)
   const [zoom, setZoom] = useState(1)
@@ -237,12 +238,16 @@ export function TimelineSwimlanes({ events, isLoading, onResourceClick, viewMode
     return () => window.removeEventListener('keydown', handleKeyDown)
   }, [selectedEvent])
 
+  // Annotations are markers across the lanes, not lanes of their own
+  const markers = useMemo(() => annotations ?? events.filter(isAnnotationEvent), [annotations, events])
+
   // Filter events by search term
   const filteredEvents = useMemo(() => {
-    if (!searchTerm) return events
+    const resourceEvents = events.filter(e => !isAnnotationEvent(e))
+    if (!searchTerm) return resourceEvents
 
     const term = searchTerm.toLowerCase()
-    return events.filter(e =>
+    return resourceEvents.filter(e =>
       e.name.toLowerCase().includes(term) ||
       e.kind.toLowerCase().includes(term) ||
       e.namespace?.toLowerCase().includes(term) ||
@@ -594,6 +599,21 @@ export function TimelineSwimlanes({ events, isLoading, onResourceClick, viewMode
                     </div>
                   )
                 })}
+                {/* Annotation flags in header */}
+                {markers.map((marker) => {
+                  const x = timeToX(new Date(marker.timestamp).getTime())
+                  if (x < 0 || x > 100) return null
+                  return (
+                    <div
+                      key={marker.id}
+                      className="absolute top-0 bottom-0 flex flex-col items-center z-10"
+                      style={{ left: `${x}%` }}
+                      title={`${marker.reason ?? 'note'}: ${marker.message ?? ''}${marker.annotation?.author ? ` (${marker.annotation.author})` : ''}`}
+                    >
+                      <div className="h-full w-0.5 bg-amber-500" />
+                    </div>
+                  )
+                })}
                 {/* "Now" marker in header */}
                 {(() => {
                   const nowX = timeToX(visibleTimeRange.now)
@@ -644,6 +664,18 @@ export function TimelineSwimlanes({ events, isLoading, onResourceClick, viewMode
                 />
               )
             })()}
+            {/* Annotation lines through swimlanes */}
+            {markers.map((marker) => {
+              const x = timeToX(new Date(marker.timestamp).getTime())
+              if (x < 0 || x > 100) return null
+              return (
+                <div
+                  key={marker.id}
+                  className="absolute top-0 bottom-0 w-px bg-amber-500/50 z-10 pointer-events-none"
+                  style={{ left: `calc(320px + (100% - 320px - 32px) * ${x / 100})` }}
+                />
+              )
+            })}
             {visibleLanes.map((lane) => {
               const isExpanded = expandedLanes.has(lane.id)
               const hasChildren = lane.children && lane.children.length > 0
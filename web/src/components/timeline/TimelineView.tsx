@@ -1,7 +1,7 @@
 import { useState, useMemo, useRef } from 'react'
 import { TimelineList } from './TimelineList'
 import { TimelineSwimlanes } from './TimelineSwimlanes'
-import { useChanges, useTopology } from '../../api/client'
+import { useAnnotations, useChanges, useTopology } from '../../api/client'
 import type { Topology } from '../../types'
 
 // Stable empty array to avoid creating new references on every render
@@ -44,6 +44,9 @@ export function TimelineView({ namespace, onResourceClick, initialViewMode, init
     limit: 10000, // Fetch all available events
   })
 
+  // Markers include cluster-wide annotations, which a namespace's changes leave out
+  const { data: annotations } = useAnnotations(namespace || undefined)
+
   // Fetch topology for service stack grouping
   const { data: rawTopology } = useTopology(namespace, 'resources')
 
@@ -71,6 +74,7 @@ export function TimelineView({ namespace, onResourceClick, initialViewMode, init
         onViewModeChange={setViewMode}
         topology={stableTopology}
         namespace={namespace}
+        annotations={annotations}
       />
     )
   }
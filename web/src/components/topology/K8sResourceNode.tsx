@@ -11,7 +11,7 @@ import { healthToSeverity, SEVERITY_DOT } from '../../utils/badge-colors'
 import { Tooltip } from '../ui/Tooltip'
 
 // Get actionable tooltip content for health issues
-function getIssueTooltip(issue: string | undefined): React.ReactNode {
+function getIssueTooltip(issue: string | undefined, quotaIssues?: string[]): React.ReactNode {
   if (!issue) return null
 
   const issueDetails: Record<string, { title: string; description: string; action: string }> = {
@@ -50,6 +50,11 @@ function getIssueTooltip(issue: string | undefined): React.ReactNode {
       description: 'No suitable node found for this pod.',
       action: 'Check node resources, taints, tolerations, and affinity rules.',
     },
+    QuotaExceeded: {
+      title: 'Resource Quota Exceeded',
+      description: 'Pods cannot be created: their requests exceed what the namespace quota has left.',
+      action: 'Raise the ResourceQuota, free up quota, or lower the pod requests.',
+    },
     Evicted: {
       title: 'Pod Evicted',
       description: 'Pod was evicted from the node (usually due to resource pressure).',
@@ -71,6 +76,9 @@ function getIssueTooltip(issue: string | undefined): React.ReactNode {
     <div className="max-w-xs">
       <div className="font-medium text-red-400">{details.title}</div>
       <div className="text-theme-text-secondary text-[10px] mt-1">{details.description}</div>
+      {quotaIssues?.map((q) => (
+        <div key={q} className="text-theme-text-secondary text-[10px] mt-1">• {q}</div>
+      ))}
       <div className="text-blue-400 text-[10px] mt-1.5 border-t border-theme-border pt-1.5">
         💡 {details.action}
       </div>
@@ -97,6 +105,7 @@ export const NODE_DIMENSIONS: Record<NodeKind, { width: number; height: number }
   CronJob: { width: 200, height: 56 },
   PVC: { width: 200, height: 48 },
   Namespace: { width: 180, height: 48 },
+  NetworkPolicy: { width: 200, height: 48 },
 }
 
 // Icon mapping for node kinds
@@ -165,6 +174,8 @@ function getIconColor(kind: NodeKind): string {
       return 'text-purple-400'
     case 'PVC':
       return 'text-cyan-400'
+    case 'NetworkPolicy':
+      return 'text-sky-400'
     default:
       return 'text-theme-text-secondary'
   }
@@ -205,6 +216,7 @@ function getSubtitle(kind: NodeKind, nodeData: Record<string, unknown>): string
     case 'ConfigMap':
       return `${nodeData.keys ?? 0} keys`
     case 'Secret':
+      if (nodeData.metadataOnly) return (nodeData.type as string) || 'Secret'
       return `${nodeData.keys ?? 0} keys`
     case 'PVC': {
       const storage = (nodeData.storage as string) || ''
@@ -215,10 +227,16 @@ function getSubtitle(kind: NodeKind, nodeData: Record<string, unknown>): string
       const count = (nodeData.podCount as number) || 0
       const healthy = (nodeData.healthy as number) || 0
       const unhealthy = (nodeData.unhealthy as number) || 0
+      const subset = nodeData.subset ? ` · ${nodeData.subset as string}` : ''
       if (unhealthy > 0) {
-        return `${count} pods (${unhealthy} unhealthy)`
+        return `${count} pods (${unhealthy} unhealthy)${subset}`
       }
-      return `${count} pods (${healthy} healthy)`
+      return `${count} pods (${healthy} healthy)${subset}`
+    }
+    case 'NetworkPolicy': {
+      const types = (nodeData.policyTypes as string[] | undefined) || []
+      const selected = (nodeData.selectedWorkloads as number) || 0
+      return `${types.join('+') || 'Ingress'} · ${selected} selected`
     }
     case 'Internet':
       return ''
@@ -254,7 +272,7 @@ export const K8sResourceNode = memo(function K8sResourceNode({
   const canExpand = isPodGroup && onExpand && !isExpanded
   const canCollapse = isPodGroup && onCollapse && isExpanded
   const statusIssue = nodeData.statusIssue as string | undefined
-  const issueTooltip = getIssueTooltip(statusIssue)
+  const issueTooltip = getIssueTooltip(statusIssue, nodeData.quotaIssues as string[] | undefined)
 
   // Special styling for Internet node
   const InternetIcon = getTopologyIcon('Internet')
@@ -21,6 +21,7 @@ const RESOURCE_KINDS: {
   // Networking
   { kind: 'Ingress', label: 'Ingress', icon: getTopologyIcon('Ingress'), color: 'text-purple-400', category: 'networking' },
   { kind: 'Service', label: 'Service', icon: getTopologyIcon('Service'), color: 'text-blue-400', category: 'networking' },
+  { kind: 'NetworkPolicy', label: 'NetworkPolicy', icon: getTopologyIcon('NetworkPolicy'), color: 'text-sky-400', category: 'networking' },
 
   // Workloads
   { kind: 'Deployment', label: 'Deployment', icon: getTopologyIcon('Deployment'), color: 'text-emerald-400', category: 'workloads' },
@@ -33,6 +33,8 @@ const EDGE_COLORS = {
   'manages': '#64748b',    // Gray for management relationships
   'configures': '#f59e0b', // Amber for config
   'uses': '#ec4899',       // Pink for HPA
+  'allows': '#14b8a6',     // Teal for NetworkPolicy-allowed traffic
+  'blocks': '#ef4444',     // Red for NetworkPolicy-blocked traffic
 } as const
 
 function getEdgeColor(type: string, isTrafficView: boolean): string {
@@ -68,6 +70,8 @@ function getNodeColor(kind: string): string {
       return '#ef4444'
     case 'HPA':
       return '#ec4899'
+    case 'NetworkPolicy':
+      return '#0ea5e9'
     case 'group':
       return '#4f46e5'
     default:
@@ -141,8 +145,12 @@ function buildEdges(
       style: {
         stroke: edgeColor,
         strokeWidth: isTrafficView ? 2 : 1.5,
-        strokeDasharray: isTrafficView && isTrafficEdge ? '5 5' : undefined,
+        strokeDasharray: (isTrafficView && isTrafficEdge) || edge.type === 'blocks' ? '5 5' : undefined,
       },
+      // Istio route (subset, canary weight) and observed load from Prometheus (rps, error rate, p99)
+      label: isTrafficView && (edge.load || edge.mesh) ? edge.label : undefined,
+      labelStyle: { fontSize: 10, fill: edge.load && edge.load.errorPercent >= 5 ? '#ef4444' : undefined },
+      labelBgStyle: { fillOpacity: 0.8 },
     })
   }
 
@@ -225,6 +225,7 @@ function pickGroupName(nodes: TopologyNode[]): string {
     'Secret': 10,
     'PVC': 10,
     'HPA': 10,
+    'NetworkPolicy': 10,
   }
 
   // Sort by priority and pick the first
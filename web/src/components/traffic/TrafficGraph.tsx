@@ -627,8 +627,34 @@ function DetailsPanel({
                     </div>
                   </div>
                 )}
+                {edgeData.flow?.requestCount ? (
+                  <div className="p-2 rounded bg-theme-elevated">
+                    <div className="text-theme-text-tertiary">Requests</div>
+                    <div className="text-theme-text-primary font-medium">
+                      {edgeData.flow.requestCount.toLocaleString()}
+                      {edgeData.flow.errorCount ? <span className="text-red-400"> · {edgeData.flow.errorCount.toLocaleString()} failed</span> : null}
+                    </div>
+                  </div>
+                ) : null}
+                {edgeData.flow?.avgLatencyMs ? (
+                  <div className="p-2 rounded bg-theme-elevated">
+                    <div className="text-theme-text-tertiary">Avg latency</div>
+                    <div className="text-theme-text-primary font-medium">{edgeData.flow.avgLatencyMs.toFixed(1)} ms</div>
+                  </div>
+                ) : null}
               </div>
 
+              {edgeData.flow?.droppedCount ? (
+                <div className="text-xs text-red-400">
+                  {edgeData.flow.droppedCount.toLocaleString()} dropped
+                  {edgeData.flow.dropReasons && (
+                    <span className="text-theme-text-tertiary">
+                      {' '}({Object.entries(edgeData.flow.dropReasons).map(([reason, n]) => `${reason}: ${n}`).join(', ')})
+                    </span>
+                  )}
+                </div>
+              ) : null}
+
               {edgeData.flow && (
                 <div className="space-y-1 pt-2 border-t border-theme-border">
                   <div className="text-xs text-theme-text-secondary">
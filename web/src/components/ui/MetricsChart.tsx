@@ -1,6 +1,7 @@
 import { useMemo } from 'react'
 import { clsx } from 'clsx'
 import type { MetricsDataPoint } from '../../api/client'
+import type { TimelineEvent } from '../../types'
 import { formatCPUNanocores, formatMemoryBytes, parseCPUToNanocores, parseMemoryToBytes } from '../../utils/format'
 
 /**
@@ -29,6 +30,39 @@ function niceRoundUp(value: number): number {
   return nice * magnitude
 }
 
+// Ranges offered when metrics history is persisted; '' is the in-memory last hour
+const METRICS_RANGES = [
+  { value: '', label: '1h' },
+  { value: '24h', label: '24h' },
+  { value: '168h', label: '7d' },
+  { value: '720h', label: '30d' },
+]
+
+interface MetricsRangePickerProps {
+  value: string
+  onChange: (range: string) => void
+}
+
+// Range buttons for persisted metrics history
+export function MetricsRangePicker({ value, onChange }: MetricsRangePickerProps) {
+  return (
+    <div className="flex items-center gap-0.5 text-xs">
+      {METRICS_RANGES.map((r) => (
+        <button
+          key={r.label}
+          onClick={() => onChange(r.value)}
+          className={clsx(
+            'px-1.5 py-0.5 rounded',
+            value === r.value ? 'bg-blue-500/20 text-blue-400' : 'text-theme-text-tertiary hover:text-theme-text-primary'
+          )}
+        >
+          {r.label}
+        </button>
+      ))}
+    </div>
+  )
+}
+
 interface MetricsChartProps {
   dataPoints: MetricsDataPoint[]
   type: 'cpu' | 'memory'
@@ -39,6 +73,8 @@ interface MetricsChartProps {
   limit?: string
   /** K8s resource request string (e.g., "100m", "256Mi") */
   request?: string
+  /** Timeline annotations (deploys, incidents) drawn as vertical markers */
+  markers?: TimelineEvent[]
 }
 
 export function MetricsChart({
@@ -49,15 +85,16 @@ export function MetricsChart({
   showAxis = true,
   limit,
   request,
+  markers,
 }: MetricsChartProps) {
   // Parse limit and request to the same unit as data (nanocores or bytes)
   const parseValue = type === 'cpu' ? parseCPUToNanocores : parseMemoryToBytes
   const limitValue = limit ? parseValue(limit) : undefined
   const requestValue = request ? parseValue(request) : undefined
 
-  const { values, chartMax, dataMax, current, timeRange } = useMemo(() => {
+  const { values, chartMax, dataMax, current, timeRange, multiDay } = useMemo(() => {
     if (!dataPoints || dataPoints.length === 0) {
-      return { values: [], chartMax: 0, dataMax: 0, current: 0, timeRange: '' }
+      return { values: [], chartMax: 0, dataMax: 0, current: 0, timeRange: '', multiDay: false }
     }
 
     const vals = dataPoints.map(d => type === 'cpu' ? d.cpu : d.memory)
@@ -76,7 +113,8 @@ export function MetricsChart({
     const firstTime = new Date(dataPoints[0].timestamp)
     const lastTime = new Date(dataPoints[dataPoints.length - 1].timestamp)
     const diffMinutes = Math.round((lastTime.getTime() - firstTime.getTime()) / 60000)
-    const range = diffMinutes > 60 ? `${Math.round(diffMinutes / 60)}h` : `${diffMinutes}m`
+    const range = diffMinutes > 2880 ? `${Math.round(diffMinutes / 1440)}d`
+      : diffMinutes > 60 ? `${Math.round(diffMinutes / 60)}h` : `${diffMinutes}m`
 
     return {
       values: vals,
@@ -84,6 +122,7 @@ export function MetricsChart({
       dataMax: maxVal,
       current: currentVal,
       timeRange: range,
+      multiDay: diffMinutes > 1440,
     }
   }, [dataPoints, type, limitValue])
 
@@ -102,6 +141,15 @@ export function MetricsChart({
   const limitPercent = limitValue && chartMax > 0 ? (limitValue / chartMax) * 100 : undefined
   const requestPercent = requestValue && chartMax > 0 ? (requestValue / chartMax) * 100 : undefined
 
+  // Markers within the charted window, as a percentage across it
+  const firstMs = new Date(dataPoints[0].timestamp).getTime()
+  const spanMs = new Date(dataPoints[dataPoints.length - 1].timestamp).getTime() - firstMs
+  const markerPositions = spanMs > 0
+    ? (markers || [])
+      .map(m => ({ event: m, x: ((new Date(m.timestamp).getTime() - firstMs) / spanMs) * 100 }))
+      .filter(m => m.x >= 0 && m.x <= 100)
+    : []
+
   // Y-axis values
   const yAxisMax = chartMax
   const yAxisMid = chartMax / 2
@@ -128,7 +176,7 @@ export function MetricsChart({
                 <div
                   key={i}
                   className="flex-1 px-px flex items-end"
-                  title={`${format(value)} at ${new Date(dataPoints[i].timestamp).toLocaleTimeString()}`}
+                  title={`${format(value)} at ${multiDay ? new Date(dataPoints[i].timestamp).toLocaleString() : new Date(dataPoints[i].timestamp).toLocaleTimeString()}`}
                 >
                   <div
                     className={clsx(color, 'w-full rounded-t-sm opacity-70 hover:opacity-100 transition-opacity')}
@@ -157,6 +205,16 @@ export function MetricsChart({
             />
           )}
 
+          {/* Annotation markers */}
+          {markerPositions.map(({ event, x }) => (
+            <div
+              key={event.id}
+              className="absolute top-0 bottom-0 border-l-2 border-amber-500/80"
+              style={{ left: `${x}%` }}
+              title={`${event.reason ?? 'note'}: ${event.message ?? ''} (${new Date(event.timestamp).toLocaleString()})`}
+            />
+          ))}
+
           {/* Grid lines */}
           <div className="absolute inset-0 flex flex-col justify-between pointer-events-none">
             <div className="border-b border-theme-border/20" />
@@ -1,6 +1,6 @@
 import { createContext, useContext, ReactNode } from 'react'
 import { useCapabilities } from '../api/client'
-import type { Capabilities } from '../types'
+import type { Capabilities, CapabilityExplainer } from '../types'
 
 // Default capabilities for local development (when running locally, all features work)
 const defaultCapabilities: Capabilities = {
@@ -66,3 +66,15 @@ export function useCanPortForward(): boolean {
 export function useCanViewSecrets(): boolean {
   return useContext(CapabilitiesContext).secrets
 }
+
+// Images offered for debug containers, empty when they're unavailable
+export function useDebugImages(): string[] {
+  const caps = useContext(CapabilitiesContext)
+  return caps.debugContainers ? caps.debugImages ?? [] : []
+}
+
+// Why a feature is unavailable (keyed like Capabilities, plus metrics, gatewayApi,
+// argoRollouts and debugContainers), or undefined when it's available
+export function useUnavailableReason(feature: string): CapabilityExplainer | undefined {
+  return useContext(CapabilitiesContext).unavailable?.[feature]
+}
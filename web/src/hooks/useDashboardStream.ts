@@ -0,0 +1,37 @@
+import { useEffect } from 'react'
+import { useQueryClient } from '@tanstack/react-query'
+import type { DashboardResponse } from '../api/client'
+
+/**
+ * Keeps the ['dashboard', namespace] query up to date from the server's dashboard stream:
+ * the full dashboard on connect, then only the sections that changed (health, problems,
+ * resource counts, ...) as the cluster changes. The query's polling stays as a fallback.
+ */
+export function useDashboardStream(namespace?: string) {
+  const queryClient = useQueryClient()
+
+  useEffect(() => {
+    const queryKey = ['dashboard', namespace]
+    const params = namespace ? `?namespace=${encodeURIComponent(namespace)}` : ''
+    const es = new EventSource(`/api/dashboard/stream${params}`)
+
+    es.addEventListener('dashboard', (event) => {
+      try {
+        queryClient.setQueryData(queryKey, JSON.parse((event as MessageEvent).data) as DashboardResponse)
+      } catch (e) {
+        console.error('Dashboard stream: failed to parse dashboard', e)
+      }
+    })
+
+    es.addEventListener('sections', (event) => {
+      try {
+        const sections = JSON.parse((event as MessageEvent).data) as Partial<DashboardResponse>
+        queryClient.setQueryData<DashboardResponse>(queryKey, (prev) => (prev ? { ...prev, ...sections } : prev))
+      } catch (e) {
+        console.error('Dashboard stream: failed to parse sections', e)
+      }
+    })
+
+    return () => es.close()
+  }, [namespace, queryClient])
+}
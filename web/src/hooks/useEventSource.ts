@@ -1,5 +1,5 @@
 import { useState, useEffect, useCallback, useRef } from 'react'
-import type { Topology, K8sEvent, ViewMode } from '../types'
+import type { Topology, TopologyDelta, K8sEvent, ViewMode } from '../types'
 
 interface UseEventSourceReturn {
   topology: Topology | null
@@ -16,6 +16,26 @@ interface UseEventSourceOptions {
 
 const MAX_EVENTS = 100 // Keep last 100 events
 
+// applyDelta patches a topology with a topology_delta, keeping surviving nodes and edges
+// in place and appending added ones
+function applyDelta(topology: Topology, delta: TopologyDelta): Topology {
+  const removedNodes = new Set(delta.removedNodes)
+  const updatedNodes = new Map((delta.updatedNodes ?? []).map((n) => [n.id, n]))
+  const removedEdges = new Set(delta.removedEdges)
+  const updatedEdges = new Map((delta.updatedEdges ?? []).map((e) => [e.id, e]))
+  return {
+    nodes: [
+      ...topology.nodes.filter((n) => !removedNodes.has(n.id)).map((n) => updatedNodes.get(n.id) ?? n),
+      ...(delta.addedNodes ?? []),
+    ],
+    edges: [
+      ...topology.edges.filter((e) => !removedEdges.has(e.id)).map((e) => updatedEdges.get(e.id) ?? e),
+      ...(delta.addedEdges ?? []),
+    ],
+    warnings: delta.warnings,
+  }
+}
+
 export function useEventSource(
   namespace: string,
   viewMode: ViewMode = 'resources',
@@ -49,6 +69,8 @@ export function useEventSource(
     if (viewMode && viewMode !== 'resources') {
       params.set('view', viewMode)
     }
+    // After the first snapshot, only what changed is sent
+    params.set('topology', 'delta')
     const url = `/api/events/stream${params.toString() ? `?${params}` : ''}`
 
     // Create new EventSource
@@ -87,6 +109,16 @@ export function useEventSource(
       }
     })
 
+    // Handle incremental topology updates
+    es.addEventListener('topology_delta', (event) => {
+      try {
+        const delta = JSON.parse(event.data) as TopologyDelta
+        setTopology((prev) => (prev ? applyDelta(prev, delta) : prev))
+      } catch (e) {
+        console.error('Failed to parse topology delta:', e)
+      }
+    })
+
     // Handle K8s events
     es.addEventListener('k8s_event', (event) => {
       try {
@@ -103,6 +135,12 @@ export function useEventSource(
       // Connection is alive
     })
 
+    // Server is shutting down; the stream closes next and onerror schedules the reconnect
+    es.addEventListener('shutdown', () => {
+      console.log('SSE server shutting down')
+      setConnected(false)
+    })
+
     // Handle context switch progress events
     es.addEventListener('context_switch_progress', (event) => {
       try {
@@ -0,0 +1,60 @@
+import { useEffect, useState } from 'react'
+import type { ContainerMetricsHistory } from '../api/client'
+
+interface LivePodMetricsEvent {
+  timestamp: string
+  source: 'kubelet' | 'metrics-server'
+  containers: { name: string; cpu: number; memory: number }[]
+}
+
+// Points kept per container: 5 minutes at the fastest interval
+const MAX_POINTS = 150
+
+/**
+ * Streams a pod's usage every few seconds while enabled, for live charts. The server polls
+ * only while this stream is open; closing it (disabling, unmounting) stops the polling.
+ * EventSource reconnects by itself, and the points collected so far are kept.
+ */
+export function useLivePodMetrics(namespace: string, podName: string, enabled: boolean) {
+  const [containers, setContainers] = useState<ContainerMetricsHistory[]>([])
+  const [source, setSource] = useState<LivePodMetricsEvent['source']>()
+  const [error, setError] = useState<string>()
+
+  useEffect(() => {
+    setContainers([])
+    setSource(undefined)
+    setError(undefined)
+    if (!enabled || !namespace || !podName) return
+
+    const es = new EventSource(`/api/metrics/pods/${namespace}/${podName}/live`)
+
+    es.addEventListener('metrics', (event) => {
+      try {
+        const sample = JSON.parse((event as MessageEvent).data) as LivePodMetricsEvent
+        setSource(sample.source)
+        setError(undefined)
+        setContainers((prev) => sample.containers.map((c) => {
+          const points = prev.find((p) => p.name === c.name)?.dataPoints ?? []
+          const point = { timestamp: sample.timestamp, cpu: c.cpu, memory: c.memory }
+          return { name: c.name, dataPoints: [...points, point].slice(-MAX_POINTS) }
+        }))
+      } catch (e) {
+        console.error('Live metrics: failed to parse sample', e)
+      }
+    })
+
+    es.addEventListener('error', (event) => {
+      const data = (event as MessageEvent).data
+      if (!data) return // Connection error; EventSource reconnects
+      try {
+        setError((JSON.parse(data) as { error: string }).error)
+      } catch {
+        // Ignore malformed errors
+      }
+    })
+
+    return () => es.close()
+  }, [namespace, podName, enabled])
+
+  return { containers, source, error }
+}
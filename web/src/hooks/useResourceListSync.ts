@@ -0,0 +1,89 @@
+import { useEffect } from 'react'
+import { useQueryClient } from '@tanstack/react-query'
+import { applyJsonPatch, type JsonPatchOp } from '../utils/json-patch'
+
+interface ListSyncEvent {
+  version: number
+  base?: number
+  items?: unknown[]
+  ops?: JsonPatchOp[]
+}
+
+const RECONNECT_DELAY = 3000
+
+/**
+ * Keeps the ['resources', kind, group, namespace] query up to date from the server's list
+ * stream: a full list on connect, then JSON Patch deltas as the cluster changes. Large,
+ * busy lists (pods in big namespaces) update without refetching everything.
+ */
+export function useResourceListSync(kind: string | undefined, group: string, namespace: string, enabled = true) {
+  const queryClient = useQueryClient()
+
+  useEffect(() => {
+    if (!kind || !enabled) return
+
+    const queryKey = ['resources', kind, group, namespace]
+    const params = new URLSearchParams()
+    if (namespace) params.set('namespace', namespace)
+    const url = `/api/resources/${kind}/stream${params.toString() ? `?${params}` : ''}`
+
+    let es: EventSource | null = null
+    // The stream's own copy: patches apply to it, not to the query data, which a
+    // regular refetch may replace in a different order
+    let items: unknown[] | null = null
+    let version = 0
+    let reconnectTimer: number | null = null
+    let closed = false
+
+    const connect = () => {
+      es = new EventSource(url)
+      items = null
+
+      es.addEventListener('list', (event) => {
+        try {
+          const data = JSON.parse((event as MessageEvent).data) as ListSyncEvent
+          version = data.version
+          items = data.items ?? []
+          queryClient.setQueryData(queryKey, items)
+        } catch (e) {
+          console.error('List stream: failed to parse list', e)
+        }
+      })
+
+      es.addEventListener('patch', (event) => {
+        try {
+          const data = JSON.parse((event as MessageEvent).data) as ListSyncEvent
+          if (data.base !== version || !items) {
+            // Missed an update; reconnect for a fresh list
+            reconnect(0)
+            return
+          }
+          items = applyJsonPatch(items, data.ops ?? [])
+          version = data.version
+          queryClient.setQueryData(queryKey, items)
+        } catch (e) {
+          console.error('List stream: failed to apply patch', e)
+          reconnect(0)
+        }
+      })
+
+      es.onerror = () => reconnect(RECONNECT_DELAY)
+    }
+
+    const reconnect = (delay: number) => {
+      es?.close()
+      if (closed || reconnectTimer !== null) return
+      reconnectTimer = window.setTimeout(() => {
+        reconnectTimer = null
+        if (!closed) connect()
+      }, delay)
+    }
+
+    connect()
+    return () => {
+      closed = true
+      es?.close()
+      if (reconnectTimer !== null) clearTimeout(reconnectTimer)
+    }
+  }, [kind, group, namespace, enabled, queryClient])
+}
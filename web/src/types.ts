@@ -6,6 +6,21 @@ export interface Capabilities {
   logs: boolean        // Log viewer (pods/log)
   portForward: boolean // Port forwarding (pods/portforward)
   secrets: boolean     // List secrets
+  debugContainers?: boolean // Ephemeral debug containers (pods/ephemeralcontainers + exec)
+  debugImages?: string[] // Images offered for debug containers; the first is the default
+  secretsMetadataOnly?: boolean // Secrets are cached without values (--secrets=metadata)
+  execRecorded?: boolean // Terminal sessions are recorded (--exec-audit)
+  unavailable?: Record<string, CapabilityExplainer> // Why each unavailable feature is off, and the fix
+}
+
+// Why a feature is unavailable and how to enable it
+export interface CapabilityExplainer {
+  reason: string
+  permission?: { verb: string; group?: string; resource?: string; subresource?: string }
+  component?: string // Missing cluster component or API group
+  fix?: string // Command that enables the feature
+  manifest?: string // Manifest to apply instead of fix
+  docsUrl?: string
 }
 
 export type NodeKind =
@@ -26,14 +41,16 @@ export type NodeKind =
   | 'CronJob'
   | 'PVC'
   | 'Namespace'
+  | 'NetworkPolicy'
 
 export type HealthStatus = 'healthy' | 'degraded' | 'unhealthy' | 'unknown'
 
-export type EdgeType = 'routes-to' | 'exposes' | 'manages' | 'uses' | 'configures'
+export type EdgeType = 'routes-to' | 'exposes' | 'manages' | 'uses' | 'configures' | 'allows' | 'blocks'
 
 export interface TopologyNode {
   id: string
   kind: NodeKind
+  group?: string // API group, set for custom resources
   name: string
   status: HealthStatus
   data: Record<string, unknown>
@@ -46,6 +63,20 @@ export interface TopologyEdge {
   type: EdgeType
   label?: string
   skipIfKindVisible?: string // Hide this edge if this kind is visible (for shortcut edges)
+  load?: EdgeLoad // Observed request load (traffic view with --traffic-metrics)
+  mesh?: MeshRoute[] // Istio VirtualService routes behind this edge (traffic view)
+}
+
+export interface MeshRoute {
+  virtualService: string // namespace/name
+  subset?: string // DestinationRule subset routed to
+  weight?: number // Share of the route's traffic (canary splits)
+}
+
+export interface EdgeLoad {
+  rps: number
+  errorPercent: number
+  p99Ms?: number
 }
 
 export interface Topology {
@@ -54,6 +85,17 @@ export interface Topology {
   warnings?: string[] // Warnings about resources that failed to load
 }
 
+// Change since the last topology on the SSE stream (topology_delta event)
+export interface TopologyDelta {
+  addedNodes?: TopologyNode[]
+  updatedNodes?: TopologyNode[]
+  removedNodes?: string[]
+  addedEdges?: TopologyEdge[]
+  updatedEdges?: TopologyEdge[]
+  removedEdges?: string[]
+  warnings?: string[]
+}
+
 // K8s Event (from SSE stream)
 export interface K8sEvent {
   kind: string
@@ -76,6 +118,50 @@ export interface FieldChange {
   newValue: unknown
 }
 
+// Server-side dry-run of a resource edit, returned before the edit is applied
+export interface UpdatePreview {
+  live: Record<string, unknown>
+  proposed: Record<string, unknown>
+  diff: DiffInfo
+  changes: StructuredDiff
+}
+
+// Semantic YAML diff: documents paired by Kind/namespace/name, compared field by field
+export type DiffChange = 'added' | 'removed' | 'modified'
+
+export interface DiffLine {
+  type: 'context' | 'added' | 'removed'
+  text: string
+  oldLine?: number
+  newLine?: number
+}
+
+export interface DiffHunk {
+  oldStart: number
+  oldLines: number
+  newStart: number
+  newLines: number
+  lines: DiffLine[]
+}
+
+export interface DocumentDiff {
+  key: string
+  kind?: string
+  namespace?: string
+  name?: string
+  source?: string
+  change: DiffChange
+  fields?: { path: string; change: DiffChange; old?: unknown; new?: unknown }[]
+  hunks: DiffHunk[]
+}
+
+export interface StructuredDiff {
+  documents: DocumentDiff[]
+  added: number
+  removed: number
+  modified: number
+}
+
 // Owner information for managed resources
 export interface OwnerInfo {
   kind: string
@@ -83,10 +169,10 @@ export interface OwnerInfo {
 }
 
 // Event source types for the new timeline API
-export type EventSource = 'informer' | 'k8s_event' | 'historical'
+export type EventSource = 'informer' | 'k8s_event' | 'historical' | 'audit' | 'annotation' // audit: a user action taken through Radar; annotation: a posted marker
 
 // Event types for the new timeline API
-export type EventType = 'add' | 'update' | 'delete' | 'Normal' | 'Warning'
+export type EventType = 'add' | 'update' | 'delete' | 'Normal' | 'Warning' | 'action' | 'annotation'
 
 // Unified timeline event (from /api/changes and /api/timeline)
 // Uses the canonical format from timeline.TimelineEvent in the backend
@@ -97,6 +183,7 @@ export interface TimelineEvent {
 
   // Resource identity
   kind: string
+  group?: string // API group, set for custom resources
   namespace: string
   name: string
   uid?: string
@@ -118,11 +205,47 @@ export interface TimelineEvent {
 
   // K8s Event specific
   count?: number
+  aggregate?: EventAggregate // Set when recurring K8s Events are folded into this entry
+
+  // Manual markers (source 'annotation'): category in reason, title in message
+  annotation?: AnnotationInfo
 
   // Correlation
   correlationId?: string
 }
 
+// Author and links of a marker posted to /api/changes/annotations
+export interface AnnotationInfo {
+  author: string
+  text?: string
+  links?: AnnotationLink[]
+}
+
+export interface AnnotationLink {
+  title?: string
+  url: string
+}
+
+// Helper to check if event is a manual marker (deploy, incident note)
+export function isAnnotationEvent(event: TimelineEvent): boolean {
+  return event.source === 'annotation'
+}
+
+// Recurrences of a K8s Event folded into one timeline entry, per involved object and reason
+export interface EventAggregate {
+  firstSeen: string // ISO date string
+  lastSeen: string // ISO date string
+  ratePerHour?: number
+  occurrences?: EventOccurrence[] // Most recent raw Events, oldest first
+}
+
+export interface EventOccurrence {
+  name: string // K8s Event object name
+  timestamp: string // ISO date string
+  count: number
+  message?: string
+}
+
 // Helper to check if event is a change (vs K8s event)
 export function isChangeEvent(event: TimelineEvent): boolean {
   return event.source === 'informer' || event.source === 'historical'
@@ -187,6 +310,7 @@ export interface ContextInfo {
   user: string
   namespace: string
   isCurrent: boolean
+  file?: string // Kubeconfig file defining it, when several are merged
 }
 
 // Namespace
@@ -227,6 +351,7 @@ export interface SelectedResource {
 // Resource reference (for relationships)
 export interface ResourceRef {
   kind: string
+  group?: string // API group, set for custom resources
   namespace: string
   name: string
 }
@@ -247,6 +372,47 @@ export interface Relationships {
 export interface ResourceWithRelationships<T = unknown> {
   resource: T
   relationships?: Relationships
+  rollout?: ArgoRolloutStatus // Argo Rollouts only
+}
+
+// An Argo Rollout's canary steps and analysis runs
+export interface ArgoRolloutStatus {
+  strategy: 'canary' | 'blueGreen'
+  phase?: string
+  message?: string
+  paused: boolean
+  pauseReasons?: string[]
+  aborted: boolean
+  promoteFull: boolean
+  currentStepIndex?: number // Equals steps.length when all steps are done
+  steps?: ArgoRolloutStep[]
+  analysisRuns: ArgoAnalysisRun[]
+}
+
+export interface ArgoRolloutStep {
+  index: number
+  type: string // setWeight, pause, analysis, experiment, setCanaryScale...
+  value?: unknown
+  status: 'completed' | 'current' | 'pending'
+}
+
+export interface ArgoAnalysisRun {
+  name: string
+  type?: string // Step, Background, PrePromotion or PostPromotion
+  revision?: string
+  phase?: string
+  message?: string
+  createdAt: string
+  metrics?: {
+    name: string
+    phase?: string
+    message?: string
+    count: number
+    successful: number
+    failed: number
+    inconclusive: number
+    error: number
+  }[]
 }
 
 // API Resource (from discovery endpoint)
@@ -260,6 +426,24 @@ export interface APIResource {
   verbs: string[]
 }
 
+// Secret keys and sizes, without values (GET /api/secrets/{ns}/{name})
+export interface SecretDetail {
+  namespace: string
+  name: string
+  type: string
+  createdAt: string
+  immutable?: boolean
+  keys: { key: string; size: number }[]
+  valuesAvailable: boolean // False when Radar watches secrets in metadata-only mode
+}
+
+// One revealed Secret key; binary values are base64-encoded
+export interface SecretValue {
+  key: string
+  value: string
+  encoding: 'text' | 'base64'
+}
+
 // Helm release types
 export interface HelmRelease {
   name: string
@@ -285,6 +469,11 @@ export interface HelmRevision {
   updated: string // ISO date string
 }
 
+// Topology subgraph of one release: its manifest resources and what they manage
+export interface HelmReleaseTopology extends Topology {
+  unmapped: ResourceRef[] // Manifest resources the topology doesn't draw, or missing ones
+}
+
 export interface HelmReleaseDetail {
   name: string
   namespace: string
@@ -301,11 +490,29 @@ export interface HelmReleaseDetail {
   hooks?: HelmHook[]
   readme?: string
   dependencies?: ChartDependency[]
+  drift?: HelmReleaseDrift  // Absent when the resource cache isn't ready
+}
+
+// Manifest vs. cluster comparison for a release
+export interface HelmReleaseDrift {
+  orphaned: HelmDriftResource[]  // Belong to the release but not in its manifest
+  missing: HelmDriftResource[]   // In the manifest but not in the cluster
+  unchecked?: string[]           // Kinds that couldn't be listed
+}
+
+export interface HelmDriftResource {
+  kind: string
+  group?: string
+  name: string
+  namespace?: string
+  lastRevision?: number  // Newest revision that rendered an orphan
 }
 
 export interface HelmHook {
   name: string
   kind: string
+  group?: string      // API group, custom resources only
+  namespace?: string
   events: string[]
   weight: number
   status?: string
@@ -321,8 +528,10 @@ export interface ChartDependency {
 
 export interface HelmOwnedResource {
   kind: string
+  group?: string      // API group, custom resources only
+  apiVersion?: string
   name: string
-  namespace: string
+  namespace: string   // Empty for cluster-scoped resources
   status?: string   // Running, Pending, Failed, Active, etc.
   ready?: string    // e.g., "3/3" for deployments
   message?: string  // Status message or reason
@@ -338,7 +547,9 @@ export interface HelmValues {
 export interface ManifestDiff {
   revision1: number
   revision2: number
+  format: 'unified' | 'side-by-side' | 'json'
   diff: string
+  changes: StructuredDiff
 }
 
 // Selected Helm release (for drawer state)
@@ -356,6 +567,15 @@ export interface UpgradeInfo {
   error?: string
 }
 
+// What upgrading to another chart version would change in the rendered manifest
+export interface UpgradePreview {
+  currentVersion: string
+  targetVersion: string
+  format: 'unified' | 'side-by-side' | 'json'
+  diff: string
+  changes: StructuredDiff
+}
+
 // Batch upgrade info (map of "namespace/name" to UpgradeInfo)
 export interface BatchUpgradeInfo {
   releases: Record<string, UpgradeInfo>
@@ -366,11 +586,20 @@ export interface ApplyValuesRequest {
   values: Record<string, unknown>
 }
 
+// A user-supplied value that violates the chart's values.schema.json
+export interface HelmValueError {
+  path: string    // e.g. "image.tag" or "hosts[0]"; "" for the root
+  message: string
+  chart?: string  // Parent chart or subchart whose schema was violated
+}
+
 // Response for previewing values changes
 export interface ValuesPreviewResponse {
   currentValues: Record<string, unknown>
   newValues: Record<string, unknown>
+  valuesChanges: StructuredDiff
   manifestDiff: string
+  manifestChanges: StructuredDiff
 }
 
 // ============================================================================
@@ -419,6 +648,11 @@ export interface ChartSearchResult {
   total: number
 }
 
+// Registries logged in to from a Secret's credentials
+export interface RegistryLoginResult {
+  registries: string[]
+}
+
 // Request body for installing a new chart
 export interface InstallChartRequest {
   releaseName: string
@@ -533,14 +767,20 @@ export interface TrafficFlow {
   destination: TrafficEndpoint
   protocol: string // tcp, udp, http, grpc
   port: number
-  l7Protocol?: string // HTTP, gRPC, DNS
+  l7Protocol?: string // HTTP, gRPC, DNS, Kafka
+  l7Type?: string // request, response, sample
   httpMethod?: string
   httpPath?: string
   httpStatus?: number
+  grpcStatus?: number
+  dnsQuery?: string
+  dnsError?: string // e.g. NXDOMAIN
+  latencyMs?: number
   bytesSent: number
   bytesRecv: number
   connections: number
   verdict: string // forwarded, dropped, error
+  dropReason?: string
   lastSeen: string // ISO date string
 }
 
@@ -558,6 +798,8 @@ export interface AggregatedFlow {
   requestCount?: number
   errorCount?: number
   avgLatencyMs?: number
+  droppedCount?: number
+  dropReasons?: Record<string, number>
 }
 
 // Cluster info for traffic detection
@@ -0,0 +1,54 @@
+// Minimal RFC 6902 JSON Patch application for the add/remove/replace operations
+// the server sends on resource list streams.
+
+export interface JsonPatchOp {
+  op: 'add' | 'remove' | 'replace'
+  path: string
+  value?: unknown
+}
+
+function parsePointer(path: string): string[] {
+  if (path === '') return []
+  return path
+    .slice(1)
+    .split('/')
+    .map(token => token.replace(/~1/g, '/').replace(/~0/g, '~'))
+}
+
+// Copy containers along each patched path so unchanged items keep their identity
+// (cheap re-renders) while the input document is left untouched.
+export function applyJsonPatch<T>(doc: T, ops: JsonPatchOp[]): T {
+  let root: unknown = doc
+  for (const op of ops) {
+    const tokens = parsePointer(op.path)
+    if (tokens.length === 0) {
+      root = op.value
+      continue
+    }
+    root = Array.isArray(root) ? [...root] : { ...(root as Record<string, unknown>) }
+    let parent = root as Record<string, unknown> | unknown[]
+    for (const token of tokens.slice(0, -1)) {
+      const child = Array.isArray(parent) ? parent[Number(token)] : parent[token]
+      if (child === null || typeof child !== 'object') {
+        throw new Error(`json patch: missing parent for ${op.path}`)
+      }
+      const copy = Array.isArray(child) ? [...child] : { ...(child as Record<string, unknown>) }
+      if (Array.isArray(parent)) parent[Number(token)] = copy
+      else parent[token] = copy
+      parent = copy
+    }
+
+    const last = tokens[tokens.length - 1]
+    if (Array.isArray(parent)) {
+      const index = last === '-' ? parent.length : Number(last)
+      if (op.op === 'add') parent.splice(index, 0, op.value)
+      else if (op.op === 'remove') parent.splice(index, 1)
+      else parent[index] = op.value
+    } else if (op.op === 'remove') {
+      delete parent[last]
+    } else {
+      parent[last] = op.value
+    }
+  }
+  return root as T
+}
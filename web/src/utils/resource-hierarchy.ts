@@ -92,13 +92,14 @@ export function isProblematicEvent(event: TimelineEvent): boolean {
 
 /**
  * Convert topology node ID to lane ID format.
- * Node IDs are formatted as: kind/namespace/name (e.g., "pod/default/nginx-abc123")
+ * Node IDs are formatted as: kind/namespace/name (e.g., "pod/default/nginx-abc123"),
+ * or kind.group/namespace/name for custom resources (e.g., "rollout.argoproj.io/default/web")
  * Lane IDs are formatted as: Kind/namespace/name (e.g., "Pod/default/nginx-abc123")
  */
 function nodeIdToLaneId(nodeId: string): string | null {
   const parts = nodeId.split('/')
   if (parts.length < 3) return null
-  const kind = parts[0]
+  const kind = parts[0].split('.')[0]
   const namespace = parts[1]
   const name = parts[2]
   const kindMap: Record<string, string> = {